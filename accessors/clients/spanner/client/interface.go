@@ -12,6 +12,7 @@ type SpannerClient interface {
 	DatabaseName() string
 	Refresh(ctx context.Context, dbURI string) error
 	Apply(ctx context.Context, ms []*spanner.Mutation, opts ...spanner.ApplyOption) (commitTimestamp time.Time, err error)
+	PartitionedUpdate(ctx context.Context, stmt spanner.Statement) (count int64, err error)
 }
 
 type ReadOnlyTransaction interface {
@@ -58,6 +59,10 @@ func (c *SpannerClientImpl) Apply(ctx context.Context, ms []*spanner.Mutation, o
 	return c.spannerClient.Apply(ctx, ms, opts...)
 }
 
+func (c *SpannerClientImpl) PartitionedUpdate(ctx context.Context, stmt spanner.Statement) (count int64, err error) {
+	return c.spannerClient.PartitionedUpdate(ctx, stmt)
+}
+
 type ReadOnlyTransactionImpl struct {
 	rotxn *spanner.ReadOnlyTransaction
 }
@@ -8,10 +8,11 @@ import (
 )
 
 type SpannerClientMock struct {
-	SingleMock       func() ReadOnlyTransaction
-	DatabaseNameMock func() string
-	RefreshMock      func(ctx context.Context, dbURI string) error
-	ApplyMock        func(ctx context.Context, ms []*spanner.Mutation, opts ...spanner.ApplyOption) (commitTimestamp time.Time, err error)
+	SingleMock            func() ReadOnlyTransaction
+	DatabaseNameMock      func() string
+	RefreshMock           func(ctx context.Context, dbURI string) error
+	ApplyMock             func(ctx context.Context, ms []*spanner.Mutation, opts ...spanner.ApplyOption) (commitTimestamp time.Time, err error)
+	PartitionedUpdateMock func(ctx context.Context, stmt spanner.Statement) (count int64, err error)
 }
 
 func (scm SpannerClientMock) Refresh(ctx context.Context, dbURI string) error {
@@ -39,6 +40,10 @@ func (scm SpannerClientMock) Apply(ctx context.Context, ms []*spanner.Mutation,
 	return scm.ApplyMock(ctx, ms, opts...)
 }
 
+func (scm SpannerClientMock) PartitionedUpdate(ctx context.Context, stmt spanner.Statement) (count int64, err error) {
+	return scm.PartitionedUpdateMock(ctx, stmt)
+}
+
 func (rom ReadOnlyTransactionMock) Query(ctx context.Context, stmt spanner.Statement) RowIterator {
 	return rom.QueryMock(ctx, stmt)
 }
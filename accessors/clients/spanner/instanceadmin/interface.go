@@ -25,6 +25,8 @@ import (
 type InstanceAdminClient interface {
 	GetInstance(ctx context.Context, req *instancepb.GetInstanceRequest, opts ...gax.CallOption) (*instancepb.Instance, error)
 	GetInstanceConfig(ctx context.Context, req *instancepb.GetInstanceConfigRequest, opts ...gax.CallOption) (*instancepb.InstanceConfig, error)
+	// CreateInstance creates an instance and waits until it is ready.
+	CreateInstance(ctx context.Context, req *instancepb.CreateInstanceRequest, opts ...gax.CallOption) (*instancepb.Instance, error)
 }
 
 // This implements the InstanceAdminClient interface. This is the primary implementation that should be used in all places other than tests.
@@ -47,3 +49,11 @@ func (c *InstanceAdminClientImpl) GetInstance(ctx context.Context, req *instance
 func (c *InstanceAdminClientImpl) GetInstanceConfig(ctx context.Context, req *instancepb.GetInstanceConfigRequest, opts ...gax.CallOption) (*instancepb.InstanceConfig, error) {
 	return c.client.GetInstanceConfig(ctx, req, opts...)
 }
+
+func (c *InstanceAdminClientImpl) CreateInstance(ctx context.Context, req *instancepb.CreateInstanceRequest, opts ...gax.CallOption) (*instancepb.Instance, error) {
+	op, err := c.client.CreateInstance(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return op.Wait(ctx)
+}
@@ -25,6 +25,7 @@ import (
 type InstanceAdminClientMock struct {
 	GetInstanceMock       func(ctx context.Context, req *instancepb.GetInstanceRequest, opts ...gax.CallOption) (*instancepb.Instance, error)
 	GetInstanceConfigMock func(ctx context.Context, req *instancepb.GetInstanceConfigRequest, opts ...gax.CallOption) (*instancepb.InstanceConfig, error)
+	CreateInstanceMock    func(ctx context.Context, req *instancepb.CreateInstanceRequest, opts ...gax.CallOption) (*instancepb.Instance, error)
 }
 
 func (iac *InstanceAdminClientMock) GetInstance(ctx context.Context, req *instancepb.GetInstanceRequest, opts ...gax.CallOption) (*instancepb.Instance, error) {
@@ -34,3 +35,7 @@ func (iac *InstanceAdminClientMock) GetInstance(ctx context.Context, req *instan
 func (iac *InstanceAdminClientMock) GetInstanceConfig(ctx context.Context, req *instancepb.GetInstanceConfigRequest, opts ...gax.CallOption) (*instancepb.InstanceConfig, error) {
 	return iac.GetInstanceConfigMock(ctx, req, opts...)
 }
+
+func (iac *InstanceAdminClientMock) CreateInstance(ctx context.Context, req *instancepb.CreateInstanceRequest, opts ...gax.CallOption) (*instancepb.Instance, error) {
+	return iac.CreateInstanceMock(ctx, req, opts...)
+}
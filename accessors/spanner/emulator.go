@@ -0,0 +1,252 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package spanneraccessor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	database "cloud.google.com/go/spanner/admin/database/apiv1"
+	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
+	instance "cloud.google.com/go/spanner/admin/instance/apiv1"
+	"cloud.google.com/go/spanner/admin/instance/apiv1/instancepb"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"go.uber.org/zap"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	emulatorProject  = "smt-dry-run"
+	emulatorInstance = "smt-dry-run"
+	emulatorImage    = "gcr.io/cloud-spanner-emulator/emulator"
+	// Time to wait for an emulator to start and create the dry run database.
+	emulatorStartTimeout = 2 * time.Minute
+)
+
+// dryRunStatement is a DDL statement applied during an emulator dry run.
+type dryRunStatement struct {
+	tableId     string   // Table the statement belongs to, "" for e.g. sequences.
+	stmt        string   // DDL statement.
+	createTable bool     // Whether the statement creates table tableId.
+	requires    []string // Tables which must exist for the statement to succeed.
+}
+
+// EmulatorDryRun applies the DDL generated for conv to a new database of the
+// Spanner emulator, and records the statements rejected by the emulator in
+// conv (see Conv.AddDdlRejection). Unlike the expression verifier, this
+// validates the whole schema, e.g. interleaving, index and foreign key
+// definitions. Statements are applied one at a time so that every rejected
+// statement is reported.
+//
+// EmulatorDryRun connects to the emulator at emulatorHost, e.g.
+// "localhost:9010", or SPANNER_EMULATOR_HOST if emulatorHost is empty. If
+// neither is set, it starts an emulator with docker for the duration of the
+// dry run.
+func EmulatorDryRun(ctx context.Context, conv *internal.Conv, driver, emulatorHost string) error {
+	if emulatorHost == "" {
+		emulatorHost = os.Getenv("SPANNER_EMULATOR_HOST")
+	}
+	if emulatorHost == "" {
+		host, stop, err := startEmulator(ctx)
+		if err != nil {
+			return err
+		}
+		defer stop()
+		emulatorHost = host
+	}
+	opts := []option.ClientOption{
+		option.WithEndpoint(emulatorHost),
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		// The emulator may still be starting.
+		option.WithGRPCDialOption(grpc.WithDefaultCallOptions(grpc.WaitForReady(true))),
+	}
+	instanceClient, err := instance.NewInstanceAdminClient(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("can't connect to the Spanner emulator at %s: %v", emulatorHost, err)
+	}
+	defer instanceClient.Close()
+	adminClient, err := database.NewDatabaseAdminClient(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("can't connect to the Spanner emulator at %s: %v", emulatorHost, err)
+	}
+	defer adminClient.Close()
+
+	dbURI, err := createEmulatorDatabase(ctx, instanceClient, adminClient, conv.SpDialect)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := adminClient.DropDatabase(ctx, &databasepb.DropDatabaseRequest{Database: dbURI}); err != nil {
+			logger.Log.Warn("Can't drop the Spanner emulator dry run database", zap.String("database", dbURI), zap.Error(err))
+		}
+	}()
+	applyDryRunStatements(conv, dryRunStatements(conv, driver), func(stmt string) error {
+		op, err := adminClient.UpdateDatabaseDdl(ctx, &databasepb.UpdateDatabaseDdlRequest{Database: dbURI, Statements: []string{stmt}})
+		if err != nil {
+			return err
+		}
+		return op.Wait(ctx)
+	})
+	return nil
+}
+
+// startEmulator starts a Spanner emulator container and returns its address,
+// and a function stopping it.
+func startEmulator(ctx context.Context) (string, func(), error) {
+	name := fmt.Sprintf("smt-spanner-emulator-%d", time.Now().Unix())
+	// Let docker pick a free port of the host.
+	out, err := exec.CommandContext(ctx, "docker", "run", "-d", "--rm", "--name", name, "-p", "127.0.0.1::9010", emulatorImage).CombinedOutput()
+	if err != nil {
+		return "", nil, fmt.Errorf("can't start the Spanner emulator with docker, use --emulator-host to connect to a running emulator: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	stop := func() {
+		if out, err := exec.Command("docker", "stop", name).CombinedOutput(); err != nil {
+			logger.Log.Warn("Can't stop the Spanner emulator container", zap.String("container", name), zap.String("output", string(out)), zap.Error(err))
+		}
+	}
+	out, err = exec.CommandContext(ctx, "docker", "port", name, "9010/tcp").Output()
+	if err != nil {
+		stop()
+		return "", nil, fmt.Errorf("can't get the port of the Spanner emulator container: %v", err)
+	}
+	host := strings.TrimSpace(strings.Split(string(out), "\n")[0])
+	for deadline := time.Now().Add(emulatorStartTimeout); ; time.Sleep(500 * time.Millisecond) {
+		conn, err := net.DialTimeout("tcp", host, time.Second)
+		if err == nil {
+			conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			stop()
+			return "", nil, fmt.Errorf("the Spanner emulator didn't start at %s: %v", host, err)
+		}
+	}
+	logger.Log.Info("Started the Spanner emulator", zap.String("container", name), zap.String("host", host))
+	return host, stop, nil
+}
+
+// createEmulatorDatabase creates an empty database of the given dialect in
+// the emulator, and the emulator instance if needed.
+func createEmulatorDatabase(ctx context.Context, instanceClient *instance.InstanceAdminClient, adminClient *database.DatabaseAdminClient, dialect string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, emulatorStartTimeout)
+	defer cancel()
+	instanceOp, err := instanceClient.CreateInstance(ctx, &instancepb.CreateInstanceRequest{
+		Parent:     "projects/" + emulatorProject,
+		InstanceId: emulatorInstance,
+		Instance: &instancepb.Instance{
+			Config:      fmt.Sprintf("projects/%s/instanceConfigs/emulator-config", emulatorProject),
+			DisplayName: "Spanner migration tool dry run",
+			NodeCount:   1,
+		},
+	})
+	if err == nil {
+		_, err = instanceOp.Wait(ctx)
+	}
+	if err != nil && status.Code(err) != codes.AlreadyExists {
+		return "", fmt.Errorf("can't create the Spanner emulator instance: %v", err)
+	}
+	dbName := fmt.Sprintf("dry-run-%d", time.Now().Unix())
+	req := &databasepb.CreateDatabaseRequest{
+		Parent:          fmt.Sprintf("projects/%s/instances/%s", emulatorProject, emulatorInstance),
+		CreateStatement: fetchCreateDatabaseStatement(dialect, dbName),
+	}
+	if dialect == constants.DIALECT_POSTGRESQL {
+		req.DatabaseDialect = databasepb.DatabaseDialect_POSTGRESQL
+	}
+	dbOp, err := adminClient.CreateDatabase(ctx, req)
+	if err == nil {
+		_, err = dbOp.Wait(ctx)
+	}
+	if err != nil {
+		return "", fmt.Errorf("can't create the Spanner emulator database: %v", err)
+	}
+	return fmt.Sprintf("%s/databases/%s", req.Parent, dbName), nil
+}
+
+// dryRunStatements returns the DDL statements of the Spanner schema of conv,
+// in the order in which they are applied to a new database.
+func dryRunStatements(conv *internal.Conv, driver string) []dryRunStatement {
+	c := ddl.Config{Comments: false, ProtectIds: true, SpDialect: conv.SpDialect, Source: driver}
+	var stmts []dryRunStatement
+	// Database options and sequences.
+	for _, s := range ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions) {
+		stmts = append(stmts, dryRunStatement{stmt: s})
+	}
+	tableIds := ddl.GetSortedTableIdsBySpName(conv.SpSchema)
+	for _, tableId := range tableIds {
+		ct := conv.SpSchema[tableId]
+		s := dryRunStatement{tableId: tableId, stmt: ct.PrintCreateTable(conv.SpSchema, c), createTable: true}
+		if ct.ParentTable.Id != "" {
+			s.requires = []string{ct.ParentTable.Id}
+		}
+		stmts = append(stmts, s)
+		for _, index := range ct.Indexes {
+			stmts = append(stmts, dryRunStatement{tableId: tableId, stmt: index.PrintCreateIndex(ct, c)})
+		}
+		for _, index := range ct.VectorIndexes {
+			stmts = append(stmts, dryRunStatement{tableId: tableId, stmt: index.PrintVectorIndex(ct, c)})
+		}
+	}
+	// Foreign keys are created once all tables exist, like in GetDDL.
+	for _, tableId := range tableIds {
+		for _, fk := range conv.SpSchema[tableId].ForeignKeys {
+			stmts = append(stmts, dryRunStatement{tableId: tableId, stmt: fk.PrintForeignKeyAlterTable(conv.SpSchema, c, tableId), requires: []string{fk.ReferTableId}})
+		}
+	}
+	for _, s := range ddl.GetViewDDL(c, conv.SpViews) {
+		stmts = append(stmts, dryRunStatement{stmt: s})
+	}
+	for _, s := range ddl.GetAccessControlDDL(c, conv.SpSchema, conv.SpRoles, conv.SpGrants) {
+		stmts = append(stmts, dryRunStatement{stmt: s})
+	}
+	return stmts
+}
+
+// applyDryRunStatements applies stmts with apply and records the rejected
+// statements in conv. Statements of tables which couldn't be created, or
+// which require such tables, are skipped since they would fail as well.
+func applyDryRunStatements(conv *internal.Conv, stmts []dryRunStatement, apply func(stmt string) error) {
+	failed := make(map[string]bool)
+	for _, s := range stmts {
+		skip := failed[s.tableId]
+		for _, t := range s.requires {
+			skip = skip || failed[t]
+		}
+		if skip {
+			if s.createTable {
+				failed[s.tableId] = true
+			}
+			continue
+		}
+		if err := apply(s.stmt); err != nil {
+			conv.AddDdlRejection(s.tableId, s.stmt, err)
+			if s.createTable {
+				failed[s.tableId] = true
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package spanneraccessor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyDryRunStatements(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			Name:   "users",
+			Id:     "t1",
+			ColIds: []string{"c1", "c5"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+				"c5": {Name: "embedding", Id: "c5", T: ddl.Type{Name: ddl.Float32, IsArray: true, VectorLength: 3}},
+			},
+			PrimaryKeys:   []ddl.IndexKey{{ColId: "c1", Order: 1}},
+			Indexes:       []ddl.CreateIndex{{Name: "users_idx", TableId: "t1", Id: "i1", Keys: []ddl.IndexKey{{ColId: "c1", Order: 1}}}},
+			VectorIndexes: []ddl.VectorIndex{{Name: "users_embedding", Id: "i2", ColId: "c5", DistanceType: ddl.CosineDistance}},
+		},
+		"t2": {
+			Name:        "orders",
+			Id:          "t2",
+			ColIds:      []string{"c2", "c3"},
+			ColDefs:     map[string]ddl.ColumnDef{"c2": {Name: "user_id", Id: "c2", T: ddl.Type{Name: ddl.Int64}}, "c3": {Name: "id", Id: "c3", T: ddl.Type{Name: ddl.Int64}}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c2", Order: 1}, {ColId: "c3", Order: 2}},
+			ParentTable: ddl.InterleavedParent{Id: "t1", OnDelete: constants.FK_CASCADE, InterleaveType: "IN PARENT"},
+		},
+		"t3": {
+			Name:        "reviews",
+			Id:          "t3",
+			ColIds:      []string{"c4"},
+			ColDefs:     map[string]ddl.ColumnDef{"c4": {Name: "user_id", Id: "c4", T: ddl.Type{Name: ddl.Int64}}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c4", Order: 1}},
+			ForeignKeys: []ddl.Foreignkey{{Name: "fk_users", ColIds: []string{"c4"}, ReferTableId: "t1", ReferColumnIds: []string{"c1"}, Id: "f1"}},
+		},
+	}
+	stmts := dryRunStatements(conv, "mysql")
+	// Tables are created before foreign keys, and interleaved tables after
+	// their parent.
+	var kinds []string
+	for _, s := range stmts {
+		kinds = append(kinds, s.tableId+":"+strings.Fields(s.stmt)[0]+" "+strings.Fields(s.stmt)[1])
+	}
+	assert.Equal(t, []string{"t3:CREATE TABLE", "t1:CREATE TABLE", "t1:CREATE INDEX", "t1:CREATE VECTOR", "t2:CREATE TABLE", "t3:ALTER TABLE"}, kinds)
+
+	// Statements of the tables requiring a rejected table are skipped.
+	var applied []string
+	applyDryRunStatements(conv, []dryRunStatement{
+		{tableId: "t1", stmt: "CREATE TABLE users", createTable: true},
+		{tableId: "t2", stmt: "CREATE TABLE orders", createTable: true, requires: []string{"t1"}},
+		{tableId: "t3", stmt: "CREATE TABLE reviews", createTable: true},
+		{tableId: "t3", stmt: "CREATE INDEX reviews_idx"},
+		{tableId: "t1", stmt: "CREATE INDEX users_idx"},
+		{tableId: "t3", stmt: "ALTER TABLE reviews ADD FOREIGN KEY", requires: []string{"t1"}},
+	}, func(stmt string) error {
+		applied = append(applied, stmt)
+		if stmt == "CREATE TABLE users" || stmt == "CREATE INDEX reviews_idx" {
+			return fmt.Errorf("rejected")
+		}
+		return nil
+	})
+	assert.Equal(t, []string{"CREATE TABLE users", "CREATE TABLE reviews", "CREATE INDEX reviews_idx"}, applied)
+	assert.Equal(t, []internal.DdlRejection{{Statement: "CREATE TABLE users", Error: "rejected"}}, conv.DdlRejections["t1"])
+	assert.Equal(t, []internal.DdlRejection{{Statement: "CREATE INDEX reviews_idx", Error: "rejected"}}, conv.DdlRejections["t3"])
+	assert.Nil(t, conv.DdlRejections["t2"])
+	assert.Equal(t, []internal.SchemaIssue{internal.DdlRejected}, conv.SchemaIssues["t3"].TableLevelIssues)
+}
@@ -25,28 +25,33 @@ import (
 // Mock that implements the SpannerAccessor interface.
 // Pass in unit tests where SpannerAccessor is an input parameter.
 type SpannerAccessorMock struct {
-	GetDatabaseDialectMock          func(ctx context.Context, dbURI string) (string, error)
-	CheckExistingDbMock             func(ctx context.Context, dbURI string) (bool, error)
-	CreateEmptyDatabaseMock         func(ctx context.Context, dbURI, dialect string) error
-	GetSpannerLeaderLocationMock    func(ctx context.Context, instanceURI string) (string, error)
-	CheckIfChangeStreamExistsMock   func(ctx context.Context, changeStreamName, dbURI string) (bool, error)
-	ValidateChangeStreamOptionsMock func(ctx context.Context, changeStreamName, dbURI string) error
-	CreateChangeStreamMock          func(ctx context.Context, changeStreamName, dbURI string) error
-	CreateDatabaseMock              func(ctx context.Context, dbURI string, conv *internal.Conv, driver string, migrationType string) error
-	UpdateDatabaseMock              func(ctx context.Context, dbURI string, conv *internal.Conv, driver string) error
-	CreateOrUpdateDatabaseMock      func(ctx context.Context, dbURI, driver string, conv *internal.Conv, migrationType string, tablesExistingOnSpanner []string) error
-	VerifyDbMock                    func(ctx context.Context, dbURI string, conv *internal.Conv, tablesExistingOnSpanner []string) (dbExists bool, err error)
-	VerifyCreateTableDDLMock        func(ctx context.Context, dbURI string, conv *internal.Conv, tableId string, driver string) error
-	ValidateDDLMock                 func(ctx context.Context, conv *internal.Conv, tablesExistingOnSpanner []string) error
-	UpdateDDLForeignKeysMock        func(ctx context.Context, dbURI string, conv *internal.Conv, driver string, migrationType string)
-	DropDatabaseMock                func(ctx context.Context, dbURI string) error
-	ValidateDMLMock                 func(ctx context.Context, query string) (bool, error)
-	TableExistsMock                 func(ctx context.Context, tableName string) (bool, error)
-	GetDatabaseNameMock             func() string
-	RefreshMock                     func(ctx context.Context, dbURI string)
-	SetSpannerClientMock            func(spannerClient spannerclient.SpannerClient)
-	GetSpannerClientMock            func() spannerclient.SpannerClient
-	GetSpannerAdminClientMock       func() spanneradmin.AdminClient
+	GetDatabaseDialectMock             func(ctx context.Context, dbURI string) (string, error)
+	CheckExistingDbMock                func(ctx context.Context, dbURI string) (bool, error)
+	CreateEmptyDatabaseMock            func(ctx context.Context, dbURI, dialect string) error
+	CreateEmptyDatabaseWithOptionsMock func(ctx context.Context, dbURI, dialect string, opts DatabaseOptions) error
+	GetSpannerLeaderLocationMock       func(ctx context.Context, instanceURI string) (string, error)
+	CreateInstanceIfNotExistsMock      func(ctx context.Context, project, instanceId, instanceConfig string, processingUnits int32) (bool, error)
+	CheckIfChangeStreamExistsMock      func(ctx context.Context, changeStreamName, dbURI string) (bool, error)
+	ValidateChangeStreamOptionsMock    func(ctx context.Context, changeStreamName, dbURI string) error
+	CreateChangeStreamMock             func(ctx context.Context, changeStreamName, dbURI string) error
+	CreateDatabaseMock                 func(ctx context.Context, dbURI string, conv *internal.Conv, driver string, migrationType string) error
+	UpdateDatabaseMock                 func(ctx context.Context, dbURI string, conv *internal.Conv, driver string) error
+	UpdateDatabaseSkippingTablesMock   func(ctx context.Context, dbURI string, conv *internal.Conv, driver string, existingTableIds []string, extraStatements []string) error
+	CreateOrUpdateDatabaseMock         func(ctx context.Context, dbURI, driver string, conv *internal.Conv, migrationType string, tablesExistingOnSpanner []string) error
+	VerifyDbMock                       func(ctx context.Context, dbURI string, conv *internal.Conv, tablesExistingOnSpanner []string) (dbExists bool, err error)
+	VerifyCreateTableDDLMock           func(ctx context.Context, dbURI string, conv *internal.Conv, tableId string, driver string) error
+	ValidateDDLMock                    func(ctx context.Context, conv *internal.Conv, tablesExistingOnSpanner []string) error
+	UpdateDDLForeignKeysMock           func(ctx context.Context, dbURI string, conv *internal.Conv, driver string, migrationType string)
+	DropDatabaseMock                   func(ctx context.Context, dbURI string) error
+	CreateForeignKeysMock              func(ctx context.Context, dbURI string, conv *internal.Conv, fkStmts []string)
+	ReloadTablesMock                   func(ctx context.Context, dbURI string, conv *internal.Conv, tableIds []string, mode, driver string) ([]string, error)
+	ValidateDMLMock                    func(ctx context.Context, query string) (bool, error)
+	TableExistsMock                    func(ctx context.Context, tableName string) (bool, error)
+	GetDatabaseNameMock                func() string
+	RefreshMock                        func(ctx context.Context, dbURI string)
+	SetSpannerClientMock               func(spannerClient spannerclient.SpannerClient)
+	GetSpannerClientMock               func() spannerclient.SpannerClient
+	GetSpannerAdminClientMock          func() spanneradmin.AdminClient
 }
 
 func (sam *SpannerAccessorMock) GetDatabaseDialect(ctx context.Context, dbURI string) (string, error) {
@@ -61,10 +66,18 @@ func (sam *SpannerAccessorMock) CreateEmptyDatabase(ctx context.Context, dbURI,
 	return sam.CreateEmptyDatabaseMock(ctx, dbURI, dialect)
 }
 
+func (sam *SpannerAccessorMock) CreateEmptyDatabaseWithOptions(ctx context.Context, dbURI, dialect string, opts DatabaseOptions) error {
+	return sam.CreateEmptyDatabaseWithOptionsMock(ctx, dbURI, dialect, opts)
+}
+
 func (sam *SpannerAccessorMock) GetSpannerLeaderLocation(ctx context.Context, instanceURI string) (string, error) {
 	return sam.GetSpannerLeaderLocationMock(ctx, instanceURI)
 }
 
+func (sam *SpannerAccessorMock) CreateInstanceIfNotExists(ctx context.Context, project, instanceId, instanceConfig string, processingUnits int32) (bool, error) {
+	return sam.CreateInstanceIfNotExistsMock(ctx, project, instanceId, instanceConfig, processingUnits)
+}
+
 func (sam *SpannerAccessorMock) CheckIfChangeStreamExists(ctx context.Context, changeStreamName, dbURI string) (bool, error) {
 	return sam.CheckIfChangeStreamExistsMock(ctx, changeStreamName, dbURI)
 }
@@ -83,6 +96,10 @@ func (sam *SpannerAccessorMock) CreateDatabase(ctx context.Context, dbURI string
 func (sam *SpannerAccessorMock) UpdateDatabase(ctx context.Context, dbURI string, conv *internal.Conv, driver string) error {
 	return sam.UpdateDatabaseMock(ctx, dbURI, conv, driver)
 }
+
+func (sam *SpannerAccessorMock) UpdateDatabaseSkippingTables(ctx context.Context, dbURI string, conv *internal.Conv, driver string, existingTableIds []string, extraStatements []string) error {
+	return sam.UpdateDatabaseSkippingTablesMock(ctx, dbURI, conv, driver, existingTableIds, extraStatements)
+}
 func (sam *SpannerAccessorMock) CreateOrUpdateDatabase(ctx context.Context, dbURI, driver string, conv *internal.Conv, migrationType string, tablesExistingOnSpanner []string) error {
 	return sam.CreateOrUpdateDatabaseMock(ctx, dbURI, driver, conv, migrationType, tablesExistingOnSpanner)
 }
@@ -100,6 +117,14 @@ func (sam *SpannerAccessorMock) DropDatabase(ctx context.Context, dbURI string)
 	return sam.DropDatabaseMock(ctx, dbURI)
 }
 
+func (sam *SpannerAccessorMock) CreateForeignKeys(ctx context.Context, dbURI string, conv *internal.Conv, fkStmts []string) {
+	sam.CreateForeignKeysMock(ctx, dbURI, conv, fkStmts)
+}
+
+func (sam *SpannerAccessorMock) ReloadTables(ctx context.Context, dbURI string, conv *internal.Conv, tableIds []string, mode, driver string) ([]string, error) {
+	return sam.ReloadTablesMock(ctx, dbURI, conv, tableIds, mode, driver)
+}
+
 // ValidateDML implements SpannerAccessor.
 func (sam *SpannerAccessorMock) ValidateDML(ctx context.Context, query string) (bool, error) {
 	return sam.ValidateDMLMock(ctx, query)
@@ -16,6 +16,8 @@ package spanneraccessor
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -36,6 +38,8 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/api/iterator"
 	adminpb "google.golang.org/genproto/googleapis/spanner/admin/database/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -46,6 +50,18 @@ var (
 	MaxWorkers = 50
 )
 
+// DatabaseOptions are the options of a database set when it is created.
+type DatabaseOptions struct {
+	// KmsKeyName is the Cloud KMS key used to encrypt the database (CMEK), in
+	// the form projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>.
+	KmsKeyName string
+	// VersionRetentionPeriod is the period for which older versions of data
+	// are kept, e.g. 7d.
+	VersionRetentionPeriod string
+	// DefaultLeader is the region of the leader replicas of the database.
+	DefaultLeader string
+}
+
 // The SpannerAccessor provides methods that internally use a spanner client (can be adminClient/databaseclient/instanceclient etc).
 // Methods should only contain generic logic here that can be used by multiple workflows.
 type SpannerAccessor interface {
@@ -56,8 +72,12 @@ type SpannerAccessor interface {
 	CheckExistingDb(ctx context.Context, dbURI string) (bool, error)
 	// Create a database with no schema.
 	CreateEmptyDatabase(ctx context.Context, dbURI, dialect string) error
+	// Create a database with no schema and the given encryption, version retention and leader options.
+	CreateEmptyDatabaseWithOptions(ctx context.Context, dbURI, dialect string, opts DatabaseOptions) error
 	// Fetch the leader of the Spanner instance.
 	GetSpannerLeaderLocation(ctx context.Context, instanceURI string) (string, error)
+	// Create an instance with the given compute capacity unless it already exists. Returns whether it was created.
+	CreateInstanceIfNotExists(ctx context.Context, project, instanceId, instanceConfig string, processingUnits int32) (bool, error)
 	// Check if a change stream already exists.
 	CheckIfChangeStreamExists(ctx context.Context, changeStreamName, dbURI string) (bool, error)
 	// Validate that change stream option 'VALUE_CAPTURE_TYPE' is 'NEW_ROW'.
@@ -68,6 +88,8 @@ type SpannerAccessor interface {
 	CreateDatabase(ctx context.Context, dbURI string, conv *internal.Conv, driver string, migrationType string) error
 	// Update Database using conv
 	UpdateDatabase(ctx context.Context, dbURI string, conv *internal.Conv, driver string) error
+	// Update Database using conv, except for the tables which already exist in it, followed by extraStatements
+	UpdateDatabaseSkippingTables(ctx context.Context, dbURI string, conv *internal.Conv, driver string, existingTableIds []string, extraStatements []string) error
 	// Updates an existing Spanner database or create a new one if one does not exist using Conv
 	CreateOrUpdateDatabase(ctx context.Context, dbURI, driver string, conv *internal.Conv, migrationType string, tablesExistingOnSpanner []string) error
 	// Check whether the db exists and if it does, verify if the schema is what we currently support.
@@ -80,6 +102,11 @@ type SpannerAccessor interface {
 	UpdateDDLForeignKeys(ctx context.Context, dbURI string, conv *internal.Conv, driver string, migrationType string)
 	// Deletes a database.
 	DropDatabase(ctx context.Context, dbURI string) error
+	// CreateForeignKeys runs the given foreign key statements, e.g. the ones returned by ReloadTables.
+	CreateForeignKeys(ctx context.Context, dbURI string, conv *internal.Conv, fkStmts []string)
+	// Delete the rows of the tables of conv with the given ids, with partitioned DML or by dropping and recreating the tables.
+	// Returns the statements recreating the foreign keys dropped with the tables, to run once they are reloaded.
+	ReloadTables(ctx context.Context, dbURI string, conv *internal.Conv, tableIds []string, mode, driver string) ([]string, error)
 	//Runs a query against the provided spanner database and returns if the executed DML is validate or not
 	ValidateDML(ctx context.Context, query string) (bool, error)
 
@@ -163,6 +190,10 @@ func (sp *SpannerAccessorImpl) CheckExistingDb(ctx context.Context, dbURI string
 }
 
 func (sp *SpannerAccessorImpl) CreateEmptyDatabase(ctx context.Context, dbURI, dialect string) error {
+	return sp.CreateEmptyDatabaseWithOptions(ctx, dbURI, dialect, DatabaseOptions{})
+}
+
+func (sp *SpannerAccessorImpl) CreateEmptyDatabaseWithOptions(ctx context.Context, dbURI, dialect string, opts DatabaseOptions) error {
 	project, instance, dbName := parse.ParseDbURI(dbURI)
 
 	dbDialect := databasepb.DatabaseDialect_GOOGLE_STANDARD_SQL
@@ -176,6 +207,13 @@ func (sp *SpannerAccessorImpl) CreateEmptyDatabase(ctx context.Context, dbURI, d
 		CreateStatement: fetchCreateDatabaseStatement(dialect, dbName),
 		DatabaseDialect: dbDialect,
 	}
+	if opts.KmsKeyName != "" {
+		req.EncryptionConfig = &databasepb.EncryptionConfig{KmsKeyName: opts.KmsKeyName}
+	}
+	optionsStmts := fetchAlterDatabaseOptionsStatements(dialect, dbName, opts)
+	if dialect != constants.DIALECT_POSTGRESQL {
+		req.ExtraStatements = optionsStmts
+	}
 	op, err := sp.AdminClient.CreateDatabase(ctx, req)
 	if err != nil {
 		return fmt.Errorf("can't build CreateDatabaseRequest: %w", parse.AnalyzeError(err, dbURI))
@@ -183,6 +221,20 @@ func (sp *SpannerAccessorImpl) CreateEmptyDatabase(ctx context.Context, dbURI, d
 	if _, err := op.Wait(ctx); err != nil {
 		return fmt.Errorf("createDatabase call failed: %w", parse.AnalyzeError(err, dbURI))
 	}
+	if len(optionsStmts) > 0 && dialect == constants.DIALECT_POSTGRESQL {
+		// PostgreSQL dialect doesn't support DDL statements as part of a
+		// CreateDatabase operation, so the options are set afterwards.
+		op, err := sp.AdminClient.UpdateDatabaseDdl(ctx, &databasepb.UpdateDatabaseDdlRequest{
+			Database:   dbURI,
+			Statements: optionsStmts,
+		})
+		if err != nil {
+			return fmt.Errorf("can't build UpdateDatabaseDdlRequest: %w", parse.AnalyzeError(err, dbURI))
+		}
+		if err := op.Wait(ctx); err != nil {
+			return fmt.Errorf("setting database options failed: %w", parse.AnalyzeError(err, dbURI))
+		}
+	}
 	return nil
 }
 
@@ -204,6 +256,33 @@ func (sp *SpannerAccessorImpl) GetSpannerLeaderLocation(ctx context.Context, ins
 	return "", fmt.Errorf("no leader found for spanner instance %s while trying fetch location", instanceURI)
 }
 
+func (sp *SpannerAccessorImpl) CreateInstanceIfNotExists(ctx context.Context, project, instanceId, instanceConfig string, processingUnits int32) (bool, error) {
+	instanceURI := fmt.Sprintf("projects/%s/instances/%s", project, instanceId)
+	_, err := sp.InstanceClient.GetInstance(ctx, &instancepb.GetInstanceRequest{Name: instanceURI})
+	if err == nil {
+		return false, nil
+	}
+	if status.Code(err) != codes.NotFound {
+		return false, fmt.Errorf("can't check whether instance %s exists: %w", instanceURI, err)
+	}
+	if !strings.HasPrefix(instanceConfig, "projects/") {
+		instanceConfig = fmt.Sprintf("projects/%s/instanceConfigs/%s", project, instanceConfig)
+	}
+	_, err = sp.InstanceClient.CreateInstance(ctx, &instancepb.CreateInstanceRequest{
+		Parent:     "projects/" + project,
+		InstanceId: instanceId,
+		Instance: &instancepb.Instance{
+			Config:          instanceConfig,
+			DisplayName:     instanceId,
+			ProcessingUnits: processingUnits,
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("can't create instance %s: %w", instanceURI, err)
+	}
+	return true, nil
+}
+
 // Consider using a CreateChangestream operation and check for alreadyExists error. That uses adminClient which can be unit tested.
 func (sp *SpannerAccessorImpl) CheckIfChangeStreamExists(ctx context.Context, changeStreamName, dbURI string) (bool, error) {
 	spClient, err := spannerclient.GetOrCreateClient(ctx, dbURI)
@@ -312,6 +391,8 @@ func (sp *SpannerAccessorImpl) CreateDatabase(ctx context.Context, dbURI string,
 		} else {
 			req.ExtraStatements = ddl.GetDDL(ddl.Config{Comments: false, ProtectIds: true, Tables: true, ForeignKeys: false, SpDialect: conv.SpDialect, Source: driver}, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions)
 		}
+		req.ExtraStatements = append(req.ExtraStatements, ddl.GetViewDDL(ddl.Config{ProtectIds: true, SpDialect: conv.SpDialect}, conv.SpViews)...)
+		req.ExtraStatements = append(req.ExtraStatements, ddl.GetAccessControlDDL(ddl.Config{ProtectIds: true, SpDialect: conv.SpDialect, Source: driver}, conv.SpSchema, conv.SpRoles, conv.SpGrants)...)
 
 	}
 
@@ -371,11 +452,62 @@ func (sp *SpannerAccessorImpl) VerifyCreateTableDDL(ctx context.Context, dbURI s
 
 // UpdateDatabase updates an existing spanner database.
 func (sp *SpannerAccessorImpl) UpdateDatabase(ctx context.Context, dbURI string, conv *internal.Conv, driver string) error {
+	return sp.UpdateDatabaseSkippingTables(ctx, dbURI, conv, driver, nil, nil)
+}
+
+// UpdateDatabaseSkippingTables updates an existing Spanner database with the
+// schema of conv, except for the tables with ids in existingTableIds which
+// already exist in the database, followed by extraStatements. When tables
+// are skipped, the sequences, views and roles which already exist in the
+// database are skipped too.
+func (sp *SpannerAccessorImpl) UpdateDatabaseSkippingTables(ctx context.Context, dbURI string, conv *internal.Conv, driver string, existingTableIds []string, extraStatements []string) error {
 	// The schema we send to Spanner excludes comments (since Cloud
 	// Spanner DDL doesn't accept them), and protects table and col names
 	// using backticks (to avoid any issues with Spanner reserved words).
 	// Foreign Keys are set to false since we create them post data migration.
-	schema := ddl.GetDDL(ddl.Config{Comments: false, ProtectIds: true, Tables: true, ForeignKeys: false, SpDialect: conv.SpDialect, Source: driver}, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions)
+	config := ddl.Config{Comments: false, ProtectIds: true, Tables: true, ForeignKeys: false, SpDialect: conv.SpDialect, Source: driver}
+	sequences, views, roles := conv.SpSequences, conv.SpViews, conv.SpRoles
+	if len(existingTableIds) > 0 {
+		for _, tableId := range ddl.GetSortedTableIdsBySpName(conv.SpSchema) {
+			if !slices.Contains(existingTableIds, tableId) {
+				config.TableIds = append(config.TableIds, tableId)
+			}
+		}
+		config.Tables = len(config.TableIds) > 0
+		existing, err := sp.getExistingSchemaObjects(ctx, dbURI)
+		if err != nil {
+			return err
+		}
+		sequences = make(map[string]ddl.Sequence)
+		for id, seq := range conv.SpSequences {
+			if !existing["SEQUENCE "+strings.ToLower(seq.Name)] {
+				sequences[id] = seq
+			}
+		}
+		views = make(map[string]ddl.CreateView)
+		for id, v := range conv.SpViews {
+			if !existing["VIEW "+strings.ToLower(v.Name)] {
+				views[id] = v
+			}
+		}
+		roles = make(map[string]ddl.CreateRole)
+		for id, r := range conv.SpRoles {
+			if !existing["ROLE "+strings.ToLower(r.Name)] {
+				roles[id] = r
+			}
+		}
+	}
+	aclConfig := ddl.Config{ProtectIds: true, SpDialect: conv.SpDialect, Source: driver}
+	schema := ddl.GetDDL(config, conv.SpSchema, sequences, conv.DatabaseOptions)
+	schema = append(schema, ddl.GetViewDDL(ddl.Config{ProtectIds: true, SpDialect: conv.SpDialect}, views)...)
+	schema = append(schema, ddl.GetAccessControlDDL(aclConfig, conv.SpSchema, roles, nil)...)
+	// Grants to existing roles may be new, and refer to roles by id.
+	for _, g := range conv.SpGrants {
+		if stmt := g.PrintGrant(conv.SpSchema, conv.SpRoles, aclConfig); stmt != "" {
+			schema = append(schema, stmt)
+		}
+	}
+	schema = append(schema, extraStatements...)
 	if len(schema) == 0 {
 		return nil
 	}
@@ -397,6 +529,26 @@ func (sp *SpannerAccessorImpl) UpdateDatabase(ctx context.Context, dbURI string,
 	return nil
 }
 
+// existingSchemaObjectRe matches the statements creating sequences, views and
+// roles returned by GetDatabaseDdl, in both dialects.
+var existingSchemaObjectRe = regexp.MustCompile("(?i)^\\s*CREATE\\s+(?:OR\\s+REPLACE\\s+)?(SEQUENCE|VIEW|ROLE)\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?[`\"]?([^\\s`\"(]+)")
+
+// getExistingSchemaObjects returns the sequences, views and roles of the
+// database, keyed by their kind and lower case name, e.g. "VIEW v1".
+func (sp *SpannerAccessorImpl) getExistingSchemaObjects(ctx context.Context, dbURI string) (map[string]bool, error) {
+	resp, err := sp.AdminClient.GetDatabaseDdl(ctx, &databasepb.GetDatabaseDdlRequest{Database: dbURI})
+	if err != nil {
+		return nil, fmt.Errorf("can't read the schema of the database: %w", parse.AnalyzeError(err, dbURI))
+	}
+	existing := make(map[string]bool)
+	for _, stmt := range resp.GetStatements() {
+		if m := existingSchemaObjectRe.FindStringSubmatch(stmt); m != nil {
+			existing[strings.ToUpper(m[1])+" "+strings.ToLower(m[2])] = true
+		}
+	}
+	return existing, nil
+}
+
 // CreatesOrUpdatesDatabase updates an existing Spanner database or creates a new one if one does not exist.
 func (sp *SpannerAccessorImpl) CreateOrUpdateDatabase(ctx context.Context, dbURI, driver string, conv *internal.Conv, migrationType string, tablesExistingOnSpanner []string) error {
 	dbExists, err := sp.VerifyDb(ctx, dbURI, conv, tablesExistingOnSpanner)
@@ -508,6 +660,13 @@ func (sp *SpannerAccessorImpl) UpdateDDLForeignKeys(ctx context.Context, dbURI s
 	// Sequences will not be passed as they have already been created.
 	// Database options will not be passed since they have also already been set.
 	fkStmts := ddl.GetDDL(ddl.Config{Comments: false, ProtectIds: true, Tables: false, ForeignKeys: true, SpDialect: conv.SpDialect, Source: driver}, conv.SpSchema, make(map[string]ddl.Sequence), ddl.DatabaseOptions{})
+	sp.CreateForeignKeys(ctx, dbURI, conv, fkStmts)
+}
+
+// CreateForeignKeys updates the Spanner database with the foreign key
+// statements fkStmts, in parallel. Foreign keys which can't be created are
+// reported as unexpected conditions of conv.
+func (sp *SpannerAccessorImpl) CreateForeignKeys(ctx context.Context, dbURI string, conv *internal.Conv, fkStmts []string) {
 	if len(fkStmts) == 0 {
 		return
 	}
@@ -577,6 +736,108 @@ func (sp *SpannerAccessorImpl) UpdateDDLForeignKeys(ctx context.Context, dbURI s
 	conv.Audit.Progress.Done()
 }
 
+// ReloadTables empties the tables of conv with the given ids, so that their
+// data can be migrated again. In delete mode, their rows are deleted with
+// partitioned DML, which keeps the tables online but takes longer for large
+// tables. In recreate mode, the tables and their indexes are dropped and
+// created again. Tables are emptied before the tables they are interleaved in
+// or reference.
+func (sp *SpannerAccessorImpl) ReloadTables(ctx context.Context, dbURI string, conv *internal.Conv, tableIds []string, mode, driver string) ([]string, error) {
+	tableIds = reloadOrder(conv.SpSchema, tableIds)
+	c := ddl.Config{ProtectIds: true, SpDialect: conv.SpDialect, Source: driver}
+	if mode == constants.RELOAD_RECREATE {
+		// The foreign keys referencing the tables from the other tables
+		// are dropped first, and they are recreated along with the foreign
+		// keys of the tables once their rows are reloaded.
+		var stmts, fkStmts []string
+		for _, tableId := range ddl.GetSortedTableIdsBySpName(conv.SpSchema) {
+			if slices.Contains(tableIds, tableId) {
+				continue
+			}
+			ct := conv.SpSchema[tableId]
+			for _, fk := range ct.ForeignKeys {
+				if fk.Name != "" && slices.Contains(tableIds, fk.ReferTableId) {
+					stmts = append(stmts, fk.PrintDropForeignKey(ct, c))
+					fkStmts = append(fkStmts, fk.PrintForeignKeyAlterTable(conv.SpSchema, c, tableId))
+				}
+			}
+		}
+		for _, tableId := range tableIds {
+			stmts = append(stmts, conv.SpSchema[tableId].PrintDropTable(c)...)
+			for _, fk := range conv.SpSchema[tableId].ForeignKeys {
+				fkStmts = append(fkStmts, fk.PrintForeignKeyAlterTable(conv.SpSchema, c, tableId))
+			}
+		}
+		// Tables are created in the reverse order, parents first, along
+		// with their indexes and vector indexes.
+		createIds := slices.Clone(tableIds)
+		slices.Reverse(createIds)
+		c.Tables = true
+		c.TableIds = createIds
+		stmts = append(stmts, ddl.GetDDL(c, conv.SpSchema, nil, ddl.DatabaseOptions{})...)
+		logger.Log.Info(fmt.Sprintf("Recreating %d tables before reloading them", len(tableIds)))
+		op, err := sp.AdminClient.UpdateDatabaseDdl(ctx, &adminpb.UpdateDatabaseDdlRequest{Database: dbURI, Statements: stmts})
+		if err != nil {
+			return nil, fmt.Errorf("can't build UpdateDatabaseDdlRequest: %w", parse.AnalyzeError(err, dbURI))
+		}
+		if err := op.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("can't recreate tables: %w", parse.AnalyzeError(err, dbURI))
+		}
+		return fkStmts, nil
+	}
+	for _, tableId := range tableIds {
+		count, err := sp.SpannerClient.PartitionedUpdate(ctx, spanner.Statement{SQL: conv.SpSchema[tableId].PrintDeleteAll(c)})
+		if err != nil {
+			return nil, fmt.Errorf("can't delete the rows of table %s: %v", conv.SpSchema[tableId].Name, err)
+		}
+		logger.Log.Info(fmt.Sprintf("Deleted %d rows of table %s before reloading it", count, conv.SpSchema[tableId].Name))
+	}
+	return nil, nil
+}
+
+// reloadOrder sorts tableIds so that each table comes before the tables it
+// is interleaved in or references with a foreign key. Tables in cycles of
+// foreign keys are kept in name order.
+func reloadOrder(schema ddl.Schema, tableIds []string) []string {
+	remaining := slices.Clone(tableIds)
+	slices.SortFunc(remaining, func(a, b string) int { return strings.Compare(schema[a].Name, schema[b].Name) })
+	// dependsOn reports whether table a must be emptied before table b.
+	dependsOn := func(a, b string) bool {
+		if schema[a].ParentTable.Id == b {
+			return true
+		}
+		for _, fk := range schema[a].ForeignKeys {
+			if fk.ReferTableId == b && a != b {
+				return true
+			}
+		}
+		return false
+	}
+	var ordered []string
+	for len(remaining) > 0 {
+		next := -1
+		for i, b := range remaining {
+			blocked := false
+			for _, a := range remaining {
+				if dependsOn(a, b) {
+					blocked = true
+					break
+				}
+			}
+			if !blocked {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			next = 0
+		}
+		ordered = append(ordered, remaining[next])
+		remaining = slices.Delete(remaining, next, next+1)
+	}
+	return ordered
+}
+
 func (sp *SpannerAccessorImpl) DropDatabase(ctx context.Context, dbURI string) error {
 
 	err := sp.AdminClient.DropDatabase(ctx, &adminpb.DropDatabaseRequest{Database: dbURI})
@@ -621,6 +882,33 @@ func (sp *SpannerAccessorImpl) GetSpannerAdminClient() spanneradmin.AdminClient
 	return sp.AdminClient
 }
 
+// fetchAlterDatabaseOptionsStatements returns the statements setting the
+// version retention period and default leader of a database.
+func fetchAlterDatabaseOptionsStatements(dialect string, databaseName string, opts DatabaseOptions) []string {
+	var options []string
+	if dialect == constants.DIALECT_POSTGRESQL {
+		// PostgreSQL dialect sets one option per statement.
+		var stmts []string
+		if opts.VersionRetentionPeriod != "" {
+			stmts = append(stmts, fmt.Sprintf("ALTER DATABASE \"%s\" SET spanner.version_retention_period = '%s'", databaseName, opts.VersionRetentionPeriod))
+		}
+		if opts.DefaultLeader != "" {
+			stmts = append(stmts, fmt.Sprintf("ALTER DATABASE \"%s\" SET spanner.default_leader = '%s'", databaseName, opts.DefaultLeader))
+		}
+		return stmts
+	}
+	if opts.VersionRetentionPeriod != "" {
+		options = append(options, fmt.Sprintf("version_retention_period = '%s'", opts.VersionRetentionPeriod))
+	}
+	if opts.DefaultLeader != "" {
+		options = append(options, fmt.Sprintf("default_leader = '%s'", opts.DefaultLeader))
+	}
+	if len(options) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("ALTER DATABASE `%s` SET OPTIONS (%s)", databaseName, strings.Join(options, ", "))}
+}
+
 func fetchCreateDatabaseStatement(dialect string, databaseName string) string {
 
 	statementPattern := "CREATE DATABASE `%s`"
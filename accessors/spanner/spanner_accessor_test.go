@@ -35,6 +35,8 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/exp/rand"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const TablePerDbError = "can't create/update database: can't create database: can't build CreateDatabaseRequest: rpc error: code = FailedPrecondition desc = Cannot add table table_999: too many tables (limit 5000)."
@@ -325,6 +327,55 @@ func TestSpannerAccessorImpl_CreateEmptyDatabase(t *testing.T) {
 	}
 }
 
+func TestSpannerAccessorImpl_CreateEmptyDatabaseWithOptions(t *testing.T) {
+	opts := DatabaseOptions{KmsKeyName: "projects/p/locations/l/keyRings/r/cryptoKeys/k", VersionRetentionPeriod: "7d", DefaultLeader: "us-central1"}
+	testCases := []struct {
+		name            string
+		dialect         string
+		wantExtra       []string
+		wantUpdateStmts []string
+	}{
+		{
+			name:      "GoogleSQL",
+			dialect:   constants.DIALECT_GOOGLESQL,
+			wantExtra: []string{"ALTER DATABASE `mydb` SET OPTIONS (version_retention_period = '7d', default_leader = 'us-central1')"},
+		},
+		{
+			name:    "PostgreSQL",
+			dialect: constants.DIALECT_POSTGRESQL,
+			wantUpdateStmts: []string{
+				"ALTER DATABASE \"mydb\" SET spanner.version_retention_period = '7d'",
+				"ALTER DATABASE \"mydb\" SET spanner.default_leader = 'us-central1'",
+			},
+		},
+	}
+	ctx := context.Background()
+	for _, tc := range testCases {
+		var createReq *databasepb.CreateDatabaseRequest
+		var updateStmts []string
+		acm := spanneradmin.AdminClientMock{
+			CreateDatabaseMock: func(ctx context.Context, req *databasepb.CreateDatabaseRequest, opts ...gax.CallOption) (spanneradmin.CreateDatabaseOperation, error) {
+				createReq = req
+				return &spanneradmin.CreateDatabaseOperationMock{
+					WaitMock: func(ctx context.Context, opts ...gax.CallOption) (*databasepb.Database, error) { return nil, nil },
+				}, nil
+			},
+			UpdateDatabaseDdlMock: func(ctx context.Context, req *databasepb.UpdateDatabaseDdlRequest, opts ...gax.CallOption) (spanneradmin.UpdateDatabaseDdlOperation, error) {
+				updateStmts = req.Statements
+				return &spanneradmin.UpdateDatabaseDdlOperationMock{
+					WaitMock: func(ctx context.Context, opts ...gax.CallOption) error { return nil },
+				}, nil
+			},
+		}
+		spA := SpannerAccessorImpl{AdminClient: &acm}
+		err := spA.CreateEmptyDatabaseWithOptions(ctx, "projects/test-project/instances/test-instance/databases/mydb", tc.dialect, opts)
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, opts.KmsKeyName, createReq.EncryptionConfig.GetKmsKeyName(), tc.name)
+		assert.Equal(t, tc.wantExtra, createReq.ExtraStatements, tc.name)
+		assert.Equal(t, tc.wantUpdateStmts, updateStmts, tc.name)
+	}
+}
+
 func TestSpannerAccessorImpl_CreateChangeStream(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -469,6 +520,43 @@ func TestSpannerAccessorImpl_GetSpannerLeaderLocation(t *testing.T) {
 	}
 }
 
+func TestSpannerAccessorImpl_CreateInstanceIfNotExists(t *testing.T) {
+	var created *instancepb.CreateInstanceRequest
+	iac := spinstanceadmin.InstanceAdminClientMock{
+		GetInstanceMock: func(ctx context.Context, req *instancepb.GetInstanceRequest, opts ...gax.CallOption) (*instancepb.Instance, error) {
+			switch req.Name {
+			case "projects/test-project/instances/existing":
+				return &instancepb.Instance{Name: req.Name}, nil
+			case "projects/test-project/instances/new":
+				return nil, status.Error(codes.NotFound, "instance not found")
+			}
+			return nil, status.Error(codes.PermissionDenied, "permission denied")
+		},
+		CreateInstanceMock: func(ctx context.Context, req *instancepb.CreateInstanceRequest, opts ...gax.CallOption) (*instancepb.Instance, error) {
+			created = req
+			return req.Instance, nil
+		},
+	}
+	ctx := context.Background()
+	spA := SpannerAccessorImpl{InstanceClient: &iac}
+
+	ok, err := spA.CreateInstanceIfNotExists(ctx, "test-project", "existing", "regional-us-central1", 1000)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Nil(t, created)
+
+	ok, err = spA.CreateInstanceIfNotExists(ctx, "test-project", "new", "regional-us-central1", 2000)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "projects/test-project", created.Parent)
+	assert.Equal(t, "new", created.InstanceId)
+	assert.Equal(t, "projects/test-project/instanceConfigs/regional-us-central1", created.Instance.Config)
+	assert.Equal(t, int32(2000), created.Instance.ProcessingUnits)
+
+	_, err = spA.CreateInstanceIfNotExists(ctx, "test-project", "forbidden", "regional-us-central1", 1000)
+	assert.Error(t, err)
+}
+
 func TestSpannerAccessorImpl_CreateDatabase(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -1079,6 +1167,114 @@ func TestSpannerAccessorImpl_UpdateDatabase(t *testing.T) {
 	}
 }
 
+func TestSpannerAccessorImpl_UpdateDatabaseSkippingTables(t *testing.T) {
+	var statements []string
+	acm := spanneradmin.AdminClientMock{
+		UpdateDatabaseDdlMock: func(ctx context.Context, req *databasepb.UpdateDatabaseDdlRequest, opts ...gax.CallOption) (spanneradmin.UpdateDatabaseDdlOperation, error) {
+			statements = req.Statements
+			return &spanneradmin.UpdateDatabaseDdlOperationMock{
+				WaitMock: func(ctx context.Context, opts ...gax.CallOption) error { return nil },
+			}, nil
+		},
+		GetDatabaseDdlMock: func(ctx context.Context, req *databasepb.GetDatabaseDdlRequest, opts ...gax.CallOption) (*databasepb.GetDatabaseDdlResponse, error) {
+			return &databasepb.GetDatabaseDdlResponse{Statements: []string{
+				"CREATE TABLE table_t1 (col1 INT64) PRIMARY KEY(col1)",
+				"CREATE SEQUENCE `Seq1` OPTIONS (sequence_kind = 'bit_reversed_positive')",
+				"CREATE VIEW v1 SQL SECURITY INVOKER AS SELECT 1",
+				"CREATE ROLE reader",
+			}}, nil
+		},
+	}
+	conv := internal.MakeConv()
+	for _, id := range []string{"t1", "t2"} {
+		conv.SpSchema[id] = ddl.CreateTable{
+			Name:        "table_" + id,
+			ColIds:      []string{"c1"},
+			ColDefs:     map[string]ddl.ColumnDef{"c1": {Name: "col1", T: ddl.Type{Name: ddl.Int64}}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1"}},
+			Id:          id,
+		}
+	}
+	spA := SpannerAccessorImpl{AdminClient: &acm}
+	dbURI := "projects/project-id/instances/instance-id/databases/database-id"
+	err := spA.UpdateDatabaseSkippingTables(context.Background(), dbURI, conv, "", []string{"t1"}, []string{"ALTER TABLE `table_t1` ADD COLUMN `col2` INT64"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(statements))
+	assert.Contains(t, statements[0], "CREATE TABLE `table_t2`")
+	assert.Equal(t, "ALTER TABLE `table_t1` ADD COLUMN `col2` INT64", statements[1])
+
+	// Existing sequences, views and roles aren't created again, but the
+	// grants to existing roles are.
+	conv.SpSequences = map[string]ddl.Sequence{"s1": {Id: "s1", Name: "seq1", SequenceKind: "BIT REVERSED POSITIVE"}, "s2": {Id: "s2", Name: "seq2", SequenceKind: "BIT REVERSED POSITIVE"}}
+	conv.SpViews = map[string]ddl.CreateView{"v1": {Id: "v1", Name: "v1", Query: "SELECT 1"}, "v2": {Id: "v2", Name: "v2", Query: "SELECT 2"}}
+	conv.SpRoles = map[string]ddl.CreateRole{"r1": {Id: "r1", Name: "reader"}, "r2": {Id: "r2", Name: "writer"}}
+	conv.SpGrants = []ddl.Grant{{Privileges: []string{"SELECT"}, TableId: "t1", GranteeIds: []string{"r1"}}}
+	statements = nil
+	err = spA.UpdateDatabaseSkippingTables(context.Background(), dbURI, conv, "", []string{"t1", "t2"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(statements), statements)
+	assert.Contains(t, statements[0], "CREATE SEQUENCE `seq2`")
+	assert.Contains(t, statements[1], "CREATE VIEW `v2`")
+	assert.Equal(t, "CREATE ROLE writer", statements[2])
+	assert.Contains(t, statements[3], "GRANT SELECT ON TABLE `table_t1` TO ROLE reader")
+	conv.SpSequences, conv.SpViews, conv.SpRoles, conv.SpGrants = map[string]ddl.Sequence{}, map[string]ddl.CreateView{}, map[string]ddl.CreateRole{}, nil
+
+	// No table is created when they all exist.
+	statements = nil
+	err = spA.UpdateDatabaseSkippingTables(context.Background(), dbURI, conv, "", []string{"t1", "t2"}, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, statements)
+}
+
+func reloadTablesConv() *internal.Conv {
+	conv := internal.MakeConv()
+	conv.SpSchema = ddl.Schema{
+		"t1": {Name: "users", Id: "t1", ColIds: []string{"c1"}, ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "id", T: ddl.Type{Name: ddl.Int64}}}, PrimaryKeys: []ddl.IndexKey{{ColId: "c1"}}},
+		"t2": {Name: "orders", Id: "t2", ColIds: []string{"c2"}, ColDefs: map[string]ddl.ColumnDef{"c2": {Name: "id", T: ddl.Type{Name: ddl.Int64}}}, PrimaryKeys: []ddl.IndexKey{{ColId: "c2"}},
+			ParentTable: ddl.InterleavedParent{Id: "t1", OnDelete: constants.FK_CASCADE}},
+		"t3": {Name: "audit", Id: "t3", ColIds: []string{"c3"}, ColDefs: map[string]ddl.ColumnDef{"c3": {Name: "id", T: ddl.Type{Name: ddl.Int64}}}, PrimaryKeys: []ddl.IndexKey{{ColId: "c3"}},
+			ForeignKeys: []ddl.Foreignkey{{Name: "fk_orders", ColIds: []string{"c3"}, ReferTableId: "t2", ReferColumnIds: []string{"c2"}}}},
+	}
+	return conv
+}
+
+func TestSpannerAccessorImpl_ReloadTables(t *testing.T) {
+	ctx := context.Background()
+	dbURI := "projects/project-id/instances/instance-id/databases/database-id"
+	var dml []string
+	spA := SpannerAccessorImpl{SpannerClient: spannerclient.SpannerClientMock{
+		PartitionedUpdateMock: func(ctx context.Context, stmt spanner.Statement) (int64, error) {
+			dml = append(dml, stmt.SQL)
+			return 1, nil
+		},
+	}}
+	fkStmts, err := spA.ReloadTables(ctx, dbURI, reloadTablesConv(), []string{"t1", "t2", "t3"}, constants.RELOAD_DELETE, "")
+	assert.NoError(t, err)
+	assert.Empty(t, fkStmts)
+	// Tables are emptied before the tables they are interleaved in or reference.
+	assert.Equal(t, []string{"DELETE FROM `audit` WHERE true", "DELETE FROM `orders` WHERE true", "DELETE FROM `users` WHERE true"}, dml)
+
+	var ddlStmts []string
+	spA = SpannerAccessorImpl{AdminClient: &spanneradmin.AdminClientMock{
+		UpdateDatabaseDdlMock: func(ctx context.Context, req *databasepb.UpdateDatabaseDdlRequest, opts ...gax.CallOption) (spanneradmin.UpdateDatabaseDdlOperation, error) {
+			ddlStmts = req.Statements
+			return &spanneradmin.UpdateDatabaseDdlOperationMock{
+				WaitMock: func(ctx context.Context, opts ...gax.CallOption) error { return nil },
+			}, nil
+		},
+	}}
+	fkStmts, err = spA.ReloadTables(ctx, dbURI, reloadTablesConv(), []string{"t1", "t2"}, constants.RELOAD_RECREATE, "")
+	assert.NoError(t, err)
+	// The foreign keys referencing the reloaded tables are dropped first,
+	// and returned to be created again after the reload.
+	assert.Equal(t, 5, len(ddlStmts), ddlStmts)
+	assert.Equal(t, []string{"ALTER TABLE `audit` DROP CONSTRAINT `fk_orders`", "DROP TABLE `orders`", "DROP TABLE `users`"}, ddlStmts[:3])
+	assert.Contains(t, ddlStmts[3], "CREATE TABLE `users`")
+	assert.Contains(t, ddlStmts[4], "CREATE TABLE `orders`")
+	assert.Equal(t, 1, len(fkStmts))
+	assert.Contains(t, fkStmts[0], "ALTER TABLE `audit` ADD CONSTRAINT `fk_orders` FOREIGN KEY (`id`) REFERENCES `orders` (`id`)")
+}
+
 func TestSpannerAccessorImpl_UpdateDDLForeignKey(t *testing.T) {
 	schemaWithStatements := map[string]ddl.CreateTable{
 		"table_id": {
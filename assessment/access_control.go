@@ -0,0 +1,389 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assessment
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+)
+
+// IAM roles recommended for source principals, by increasing level of access.
+const (
+	iamFineGrainedAccessUser = "roles/spanner.fineGrainedAccessUser"
+	iamDatabaseReader        = "roles/spanner.databaseReader"
+	iamDatabaseUser          = "roles/spanner.databaseUser"
+	iamDatabaseAdmin         = "roles/spanner.databaseAdmin"
+)
+
+// Levels of access granted by database or instance wide privileges.
+const (
+	accessNone = iota
+	accessRead
+	accessWrite
+	accessAdmin
+)
+
+var iamRoleForAccess = map[int]string{
+	accessRead:  iamDatabaseReader,
+	accessWrite: iamDatabaseUser,
+	accessAdmin: iamDatabaseAdmin,
+}
+
+// Source privileges which map to administrative access on Spanner.
+var adminPrivileges = map[string]bool{
+	"ALL PRIVILEGES": true,
+	"SUPER":          true,
+	"CREATE USER":    true,
+	"CREATE ROLE":    true,
+	"DROP ROLE":      true,
+	"GRANT OPTION":   true,
+	"ROLE_ADMIN":     true,
+	"SYSTEM_USER":    true,
+}
+
+// Source privileges which map to read-write access, including schema updates, on Spanner.
+var writePrivileges = map[string]bool{
+	"INSERT":      true,
+	"UPDATE":      true,
+	"DELETE":      true,
+	"CREATE":      true,
+	"ALTER":       true,
+	"DROP":        true,
+	"INDEX":       true,
+	"REFERENCES":  true,
+	"CREATE VIEW": true,
+	"TRIGGER":     true,
+}
+
+// Privileges which can be granted to Spanner database roles on tables, and on columns.
+var fgacTablePrivileges = map[string]bool{"SELECT": true, "INSERT": true, "UPDATE": true, "DELETE": true}
+var fgacColumnPrivileges = map[string]bool{"SELECT": true, "INSERT": true, "UPDATE": true}
+
+var invalidRoleNameChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// principal groups the source data of a user or role.
+type principal struct {
+	user   utils.UserAssessmentInfo
+	grants []utils.GrantAssessmentInfo
+}
+
+func (p principal) key() string {
+	return p.user.Name + "@" + p.user.Host
+}
+
+func performAccessControlAssessment(collectors assessmentCollectors, conv *internal.Conv, generateDdl bool) *utils.AccessControlAssessmentOutput {
+	if collectors.accessControlCollector == nil || collectors.accessControlCollector.IsEmpty() {
+		logger.Log.Info("not proceeding with access control assessment as access control collector was not initialized")
+		return nil
+	}
+	logger.Log.Info("starting access control assessment...")
+	out := assessAccessControl(collectors.accessControlCollector.Users, collectors.accessControlCollector.Grants, conv, generateDdl)
+	logger.Log.Info("access control assessment completed successfully.")
+	return out
+}
+
+// assessAccessControl maps source users, roles and grants to recommended IAM
+// roles and Spanner database roles. When generateDdl is true, it also emits
+// the CREATE ROLE and GRANT statements for fine-grained access control.
+func assessAccessControl(users []utils.UserAssessmentInfo, grants []utils.GrantAssessmentInfo, conv *internal.Conv, generateDdl bool) *utils.AccessControlAssessmentOutput {
+	var principals []*principal
+	byKey := make(map[string]*principal)
+	for _, u := range users {
+		p := &principal{user: u}
+		principals = append(principals, p)
+		byKey[p.key()] = p
+	}
+	for _, g := range grants {
+		p, ok := byKey[g.Grantee+"@"+g.GranteeHost]
+		if !ok {
+			// Grantee isn't visible in the user list, e.g. due to missing privileges on mysql.user.
+			p = &principal{user: utils.UserAssessmentInfo{Name: g.Grantee, Host: g.GranteeHost, Db: g.Db}}
+			principals = append(principals, p)
+			byKey[p.key()] = p
+		}
+		p.grants = append(p.grants, g)
+	}
+	// Roles are referred to by name only.
+	roles := make(map[string]*principal)
+	for _, p := range principals {
+		if p.user.IsRole {
+			roles[p.user.Name] = p
+		}
+	}
+
+	// Assign Spanner database roles to source roles, and to users with
+	// privileges which can only be mapped using fine-grained access control.
+	spannerRoles := make(map[string]string)
+	usedNames := make(map[string]bool)
+	for _, p := range principals {
+		if effectiveAccess(p, roles, map[string]bool{}) >= accessWrite {
+			continue
+		}
+		if p.user.IsRole || hasFineGrainedGrants(p) {
+			spannerRoles[p.key()] = spannerRoleName(p.user.Name, usedNames)
+		}
+	}
+
+	out := &utils.AccessControlAssessmentOutput{}
+	for _, p := range principals {
+		pa := utils.PrincipalAssessment{
+			Name:        p.user.Name,
+			Host:        p.user.Host,
+			IsRole:      p.user.IsRole,
+			SourceRoles: p.user.Roles,
+			SpannerRole: spannerRoles[p.key()],
+		}
+		var unmapped []string
+		hasGlobal := false
+		for _, g := range p.grants {
+			pa.SourcePrivileges = append(pa.SourcePrivileges, describeGrant(g))
+			if !adminPrivileges[g.Privilege] && !writePrivileges[g.Privilege] && g.Privilege != "SELECT" {
+				unmapped = append(unmapped, g.Privilege)
+			}
+			hasGlobal = hasGlobal || g.Level == utils.GrantLevelGlobal
+		}
+		var dbRoles []string
+		if pa.SpannerRole != "" {
+			dbRoles = append(dbRoles, pa.SpannerRole)
+		}
+		for _, r := range p.user.Roles {
+			if rp, ok := roles[r]; ok && spannerRoles[rp.key()] != "" {
+				dbRoles = append(dbRoles, spannerRoles[rp.key()])
+			}
+		}
+		access := effectiveAccess(p, roles, map[string]bool{})
+		switch {
+		case access >= accessWrite:
+			pa.RecommendedIAMRole = iamRoleForAccess[access]
+		case len(dbRoles) > 0:
+			pa.RecommendedIAMRole = iamFineGrainedAccessUser
+			if !p.user.IsRole {
+				pa.Notes = append(pa.Notes, fmt.Sprintf("Grant roles/spanner.databaseRoleUser for database role(s) %s", strings.Join(dbRoles, ", ")))
+			}
+		case access == accessRead:
+			pa.RecommendedIAMRole = iamDatabaseReader
+		default:
+			pa.Notes = append(pa.Notes, "No privileges on the migrated database")
+		}
+		if hasGlobal {
+			pa.Notes = append(pa.Notes, "Global privileges apply to every database on the source server, but IAM roles on Spanner can be scoped to this database")
+		}
+		if len(unmapped) > 0 {
+			pa.Notes = append(pa.Notes, fmt.Sprintf("Privileges without a Spanner equivalent: %s", strings.Join(unmapped, ", ")))
+		}
+		if p.user.Host != "" && p.user.Host != "%" {
+			pa.Notes = append(pa.Notes, fmt.Sprintf("Access is restricted to host %s at source, consider IAM conditions or VPC Service Controls on Spanner", p.user.Host))
+		}
+		out.Principals = append(out.Principals, pa)
+	}
+
+	if generateDdl {
+		for _, p := range principals {
+			if spannerRoles[p.key()] != "" {
+				out.FGACStatements = append(out.FGACStatements, fgacStatements(p, spannerRoles, roles, conv)...)
+			}
+		}
+	}
+	return out
+}
+
+// effectiveAccess returns the database wide access level of p, including
+// the access inherited from its roles.
+func effectiveAccess(p *principal, roles map[string]*principal, visited map[string]bool) int {
+	visited[p.key()] = true
+	access := accessNone
+	for _, g := range p.grants {
+		if g.Level != utils.GrantLevelGlobal && g.Level != utils.GrantLevelSchema {
+			continue
+		}
+		switch {
+		case adminPrivileges[g.Privilege] || g.IsGrantable:
+			access = max(access, accessAdmin)
+		case writePrivileges[g.Privilege]:
+			access = max(access, accessWrite)
+		case g.Privilege == "SELECT":
+			access = max(access, accessRead)
+		}
+	}
+	for _, r := range p.user.Roles {
+		if rp, ok := roles[r]; ok && !visited[rp.key()] {
+			access = max(access, effectiveAccess(rp, roles, visited))
+		}
+	}
+	return access
+}
+
+func hasFineGrainedGrants(p *principal) bool {
+	for _, g := range p.grants {
+		if (g.Level == utils.GrantLevelTable && fgacTablePrivileges[g.Privilege]) || (g.Level == utils.GrantLevelColumn && fgacColumnPrivileges[g.Privilege]) {
+			return true
+		}
+	}
+	return false
+}
+
+// spannerRoleName returns a valid and unique Spanner database role name for
+// the source principal name.
+func spannerRoleName(name string, usedNames map[string]bool) string {
+	roleName := invalidRoleNameChars.ReplaceAllString(name, "_")
+	if roleName == "" || !isLetter(roleName[0]) || strings.EqualFold(roleName, "public") || strings.HasPrefix(strings.ToLower(roleName), "spanner_") {
+		roleName = "r_" + roleName
+	}
+	candidate := roleName
+	for i := 2; usedNames[strings.ToLower(candidate)]; i++ {
+		candidate = fmt.Sprintf("%s_%d", roleName, i)
+	}
+	usedNames[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func describeGrant(g utils.GrantAssessmentInfo) string {
+	var s string
+	switch g.Level {
+	case utils.GrantLevelGlobal:
+		s = fmt.Sprintf("%s ON *.*", g.Privilege)
+	case utils.GrantLevelSchema:
+		s = fmt.Sprintf("%s ON %s.*", g.Privilege, g.Db.DatabaseName)
+	case utils.GrantLevelTable:
+		s = fmt.Sprintf("%s ON %s", g.Privilege, g.TableName)
+	case utils.GrantLevelColumn:
+		s = fmt.Sprintf("%s (%s) ON %s", g.Privilege, g.ColumnName, g.TableName)
+	}
+	if g.IsGrantable {
+		s += " WITH GRANT OPTION"
+	}
+	return s
+}
+
+// fgacStatements returns the CREATE ROLE and GRANT statements of the Spanner
+// database role mapped to p. Database wide SELECT is granted on every table.
+func fgacStatements(p *principal, spannerRoles map[string]string, roles map[string]*principal, conv *internal.Conv) []string {
+	pg := conv.SpDialect == constants.DIALECT_POSTGRESQL
+	roleName := spannerRoles[p.key()]
+	grantee := "ROLE " + roleName
+	if pg {
+		grantee = roleName
+	}
+	stmts := []string{"CREATE ROLE " + roleName}
+
+	tablePrivs := make(map[string]map[string]bool)
+	colPrivs := make(map[string]map[string][]string)
+	for _, g := range p.grants {
+		switch g.Level {
+		case utils.GrantLevelGlobal, utils.GrantLevelSchema:
+			if g.Privilege != "SELECT" {
+				continue
+			}
+			for _, t := range conv.SpSchema {
+				if tablePrivs[t.Name] == nil {
+					tablePrivs[t.Name] = make(map[string]bool)
+				}
+				tablePrivs[t.Name]["SELECT"] = true
+			}
+		case utils.GrantLevelTable:
+			spTable, _, ok := spannerNames(conv, g.TableName, "")
+			if !ok || !fgacTablePrivileges[g.Privilege] {
+				continue
+			}
+			if tablePrivs[spTable] == nil {
+				tablePrivs[spTable] = make(map[string]bool)
+			}
+			tablePrivs[spTable][g.Privilege] = true
+		case utils.GrantLevelColumn:
+			spTable, spCol, ok := spannerNames(conv, g.TableName, g.ColumnName)
+			if !ok || !fgacColumnPrivileges[g.Privilege] {
+				continue
+			}
+			if colPrivs[spTable] == nil {
+				colPrivs[spTable] = make(map[string][]string)
+			}
+			colPrivs[spTable][g.Privilege] = append(colPrivs[spTable][g.Privilege], spCol)
+		}
+	}
+	for _, t := range sortedKeys(tablePrivs) {
+		var privs []string
+		for _, priv := range []string{"SELECT", "INSERT", "UPDATE", "DELETE"} {
+			if tablePrivs[t][priv] {
+				privs = append(privs, priv)
+			}
+		}
+		stmts = append(stmts, fmt.Sprintf("GRANT %s ON TABLE %s TO %s", strings.Join(privs, ", "), t, grantee))
+	}
+	for _, t := range sortedKeys(colPrivs) {
+		for _, priv := range []string{"SELECT", "INSERT", "UPDATE"} {
+			if cols := colPrivs[t][priv]; len(cols) > 0 {
+				sort.Strings(cols)
+				stmts = append(stmts, fmt.Sprintf("GRANT %s(%s) ON TABLE %s TO %s", priv, strings.Join(cols, ", "), t, grantee))
+			}
+		}
+	}
+	for _, r := range p.user.Roles {
+		rp, ok := roles[r]
+		if !ok || spannerRoles[rp.key()] == "" {
+			continue
+		}
+		if pg {
+			stmts = append(stmts, fmt.Sprintf("GRANT %s TO %s", spannerRoles[rp.key()], roleName))
+		} else {
+			stmts = append(stmts, fmt.Sprintf("GRANT ROLE %s TO ROLE %s", spannerRoles[rp.key()], roleName))
+		}
+	}
+	return stmts
+}
+
+// spannerNames returns the Spanner names of a source table and (optionally)
+// column, and false if they were not migrated.
+func spannerNames(conv *internal.Conv, srcTable, srcCol string) (string, string, bool) {
+	tableId, err := internal.GetTableIdFromSrcName(conv.SrcSchema, srcTable)
+	if err != nil {
+		return "", "", false
+	}
+	spTable, ok := conv.SpSchema[tableId]
+	if !ok {
+		return "", "", false
+	}
+	if srcCol == "" {
+		return spTable.Name, "", true
+	}
+	colId, err := internal.GetColIdFromSrcName(conv.SrcSchema[tableId].ColDefs, srcCol)
+	if err != nil {
+		return "", "", false
+	}
+	spCol, ok := spTable.ColDefs[colId]
+	if !ok {
+		return "", "", false
+	}
+	return spTable.Name, spCol.Name, true
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assessment
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func accessControlTestConv() *internal.Conv {
+	conv := internal.MakeConv()
+	conv.SrcSchema = map[string]schema.Table{
+		"t1": {Name: "orders", Id: "t1", ColDefs: map[string]schema.Column{"c1": {Name: "id", Id: "c1"}, "c2": {Name: "status", Id: "c2"}}},
+		"t2": {Name: "customers", Id: "t2", ColDefs: map[string]schema.Column{"c3": {Name: "id", Id: "c3"}}},
+	}
+	conv.SpSchema = ddl.Schema{
+		"t1": {Name: "Orders", Id: "t1", ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "id", Id: "c1"}, "c2": {Name: "order_status", Id: "c2"}}},
+		"t2": {Name: "customers", Id: "t2", ColDefs: map[string]ddl.ColumnDef{"c3": {Name: "id", Id: "c3"}}},
+	}
+	return conv
+}
+
+func TestAssessAccessControl(t *testing.T) {
+	users := []utils.UserAssessmentInfo{
+		{Name: "admin", Host: "localhost"},
+		{Name: "app", Host: "%", Roles: []string{"reporting"}},
+		{Name: "etl", Host: "%"},
+		{Name: "reporting", Host: "%", IsRole: true},
+		{Name: "nobody", Host: "%"},
+	}
+	grants := []utils.GrantAssessmentInfo{
+		{Grantee: "admin", GranteeHost: "localhost", Privilege: "SUPER", Level: utils.GrantLevelGlobal},
+		{Grantee: "app", GranteeHost: "%", Privilege: "UPDATE", Level: utils.GrantLevelColumn, TableName: "orders", ColumnName: "status"},
+		{Grantee: "app", GranteeHost: "%", Privilege: "EXECUTE", Level: utils.GrantLevelSchema},
+		{Grantee: "etl", GranteeHost: "%", Privilege: "INSERT", Level: utils.GrantLevelSchema},
+		{Grantee: "reporting", GranteeHost: "%", Privilege: "SELECT", Level: utils.GrantLevelTable, TableName: "orders"},
+	}
+	out := assessAccessControl(users, grants, accessControlTestConv(), true)
+
+	byName := make(map[string]utils.PrincipalAssessment)
+	for _, p := range out.Principals {
+		byName[p.Name] = p
+	}
+	assert.Equal(t, 5, len(out.Principals))
+	assert.Equal(t, iamDatabaseAdmin, byName["admin"].RecommendedIAMRole)
+	assert.Equal(t, "", byName["admin"].SpannerRole)
+	assert.Contains(t, byName["admin"].Notes, "Access is restricted to host localhost at source, consider IAM conditions or VPC Service Controls on Spanner")
+	assert.Equal(t, iamDatabaseUser, byName["etl"].RecommendedIAMRole)
+	assert.Equal(t, iamFineGrainedAccessUser, byName["reporting"].RecommendedIAMRole)
+	assert.Equal(t, "reporting", byName["reporting"].SpannerRole)
+	assert.Equal(t, iamFineGrainedAccessUser, byName["app"].RecommendedIAMRole)
+	assert.Equal(t, "app", byName["app"].SpannerRole)
+	assert.Equal(t, []string{
+		"Grant roles/spanner.databaseRoleUser for database role(s) app, reporting",
+		"Privileges without a Spanner equivalent: EXECUTE",
+	}, byName["app"].Notes)
+	assert.Equal(t, "", byName["nobody"].RecommendedIAMRole)
+
+	assert.Equal(t, []string{
+		"CREATE ROLE app",
+		"GRANT UPDATE(order_status) ON TABLE Orders TO ROLE app",
+		"GRANT ROLE reporting TO ROLE app",
+		"CREATE ROLE reporting",
+		"GRANT SELECT ON TABLE Orders TO ROLE reporting",
+	}, out.FGACStatements)
+}
+
+func TestAssessAccessControlPGAndSchemaSelect(t *testing.T) {
+	conv := accessControlTestConv()
+	conv.SpDialect = constants.DIALECT_POSTGRESQL
+	users := []utils.UserAssessmentInfo{{Name: "read-only", Host: "%", IsRole: true}}
+	grants := []utils.GrantAssessmentInfo{{Grantee: "read-only", GranteeHost: "%", Privilege: "SELECT", Level: utils.GrantLevelSchema}}
+	out := assessAccessControl(users, grants, conv, true)
+	assert.Equal(t, "read_only", out.Principals[0].SpannerRole)
+	assert.Equal(t, []string{
+		"CREATE ROLE read_only",
+		"GRANT SELECT ON TABLE Orders TO read_only",
+		"GRANT SELECT ON TABLE customers TO read_only",
+	}, out.FGACStatements)
+
+	out = assessAccessControl(users, grants, conv, false)
+	assert.Nil(t, out.FGACStatements)
+}
+
+func TestSpannerRoleName(t *testing.T) {
+	used := map[string]bool{}
+	assert.Equal(t, "app_user", spannerRoleName("app.user", used))
+	assert.Equal(t, "app_user_2", spannerRoleName("app-user", used))
+	assert.Equal(t, "r_1reader", spannerRoleName("1reader", used))
+	assert.Equal(t, "r_public", spannerRoleName("public", used))
+	assert.Equal(t, "r_spanner_admin", spannerRoleName("spanner_admin", used))
+}
@@ -0,0 +1,212 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package assessment
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"go.uber.org/zap"
+)
+
+// appSchemaIssueFinder maps the queries and code snippets of the assessment
+// to the tables and columns of the conversion.
+type appSchemaIssueFinder struct {
+	conv     *internal.Conv
+	tableIds map[string]string // Maps the lower case source table names to the table ids.
+	findings map[string][]internal.AppFinding
+	seen     map[string]map[internal.AppFinding]bool
+	paths    map[string]map[jsonPathKey]int // Counts the queries using the JSON paths of the tables.
+}
+
+// jsonPathKey is a path of a JSON column of a table.
+type jsonPathKey struct {
+	colId string
+	path  string
+}
+
+// performAppSchemaIssueAssessment adds the schema related findings of the
+// application assessment to the schema issues of conv: queries ordering by
+// or reading the latest value of auto-increment columns, lookups on columns
+// with a case-insensitive collation, and the schema changes of the code
+// snippets. The JSON paths the queries filter or order rows by are added to
+// conv as suggestions of generated columns and indexes.
+func performAppSchemaIssueAssessment(conv *internal.Conv, queries []utils.QueryTranslationResult, appCodeAssessment *utils.AppCodeAssessmentOutput) {
+	logger.Log.Info("starting app schema issue assessment...")
+	var snippets []utils.Snippet
+	if appCodeAssessment != nil && appCodeAssessment.CodeSnippets != nil {
+		snippets = *appCodeAssessment.CodeSnippets
+	}
+	findings, suggestions := findAppSchemaIssues(conv, queries, snippets)
+	conv.SetAppFindings(findings)
+	conv.SetJSONPathSuggestions(suggestions)
+	count, paths := 0, 0
+	for _, l := range conv.AppFindings {
+		count += len(l)
+	}
+	for _, l := range conv.JSONPathSuggestions {
+		paths += len(l)
+	}
+	logger.Log.Info("app schema issue assessment completed successfully.", zap.Int("findings", count), zap.Int("jsonPaths", paths))
+}
+
+// findAppSchemaIssues returns the findings of the queries and snippets about
+// the tables of conv, and the JSON paths used by the queries, by table id.
+func findAppSchemaIssues(conv *internal.Conv, queries []utils.QueryTranslationResult, snippets []utils.Snippet) (map[string][]internal.AppFinding, map[string][]internal.JSONPathSuggestion) {
+	f := &appSchemaIssueFinder{
+		conv:     conv,
+		tableIds: make(map[string]string),
+		findings: make(map[string][]internal.AppFinding),
+		seen:     make(map[string]map[internal.AppFinding]bool),
+		paths:    make(map[string]map[jsonPathKey]int),
+	}
+	for tableId, t := range conv.SrcSchema {
+		f.tableIds[strings.ToLower(t.Name)] = tableId
+	}
+	files := make(map[string]string)
+	for _, s := range snippets {
+		files[s.Id] = s.RelativeFilePath
+	}
+	for _, q := range queries {
+		source := files[q.SnippetId]
+		if source == "" {
+			source = q.AssessmentSource
+		}
+		f.addQuery(q.OriginalQuery, source)
+	}
+	for _, s := range snippets {
+		f.addSnippet(s)
+	}
+	suggestions := make(map[string][]internal.JSONPathSuggestion)
+	for tableId, counts := range f.paths {
+		for k, count := range counts {
+			suggestions[tableId] = append(suggestions[tableId], internal.JSONPathSuggestion{ColId: k.colId, Path: k.path, Count: count})
+		}
+	}
+	return f.findings, suggestions
+}
+
+func (f *appSchemaIssueFinder) add(tableId string, finding internal.AppFinding) {
+	if f.seen[tableId] == nil {
+		f.seen[tableId] = make(map[internal.AppFinding]bool)
+	}
+	if f.seen[tableId][finding] {
+		return
+	}
+	f.seen[tableId][finding] = true
+	f.findings[tableId] = append(f.findings[tableId], finding)
+}
+
+// column returns the table and column ids of c. Unqualified columns of
+// queries reading several tables are looked up in these tables.
+func (f *appSchemaIssueFinder) column(c utils.QueryColumn, tables []string) (string, string, bool) {
+	candidates := tables
+	if c.Table != "" {
+		candidates = []string{c.Table}
+	}
+	var tableId, colId string
+	for _, table := range candidates {
+		id, ok := f.tableIds[strings.ToLower(table)]
+		if !ok {
+			continue
+		}
+		for cid, col := range f.conv.SrcSchema[id].ColDefs {
+			if strings.EqualFold(col.Name, c.Column) {
+				if tableId != "" {
+					// Ambiguous column.
+					return "", "", false
+				}
+				tableId, colId = id, cid
+			}
+		}
+	}
+	return tableId, colId, tableId != ""
+}
+
+func (f *appSchemaIssueFinder) isAutoIncrement(tableId, colId string) bool {
+	col := f.conv.SrcSchema[tableId].ColDefs[colId]
+	return col.AutoGen.GenerationType == constants.AUTO_INCREMENT || col.Ignored.AutoIncrement
+}
+
+func (f *appSchemaIssueFinder) addQuery(query, source string) {
+	usage, err := utils.AnalyzeSourceQueryColumns(f.conv.Source, query)
+	if err != nil {
+		logger.Log.Debug("skipping the schema issues of a query", zap.String("query", query), zap.Error(err))
+		return
+	}
+	for _, c := range append(usage.OrderBy, usage.Max...) {
+		if tableId, colId, ok := f.column(c, usage.Tables); ok && f.isAutoIncrement(tableId, colId) {
+			f.add(tableId, internal.AppFinding{ColId: colId, Issue: internal.AppAutoIncrementOrdering, Source: source, Detail: query})
+		}
+	}
+	if usage.LastInsertId {
+		for _, table := range usage.Tables {
+			tableId, ok := f.tableIds[strings.ToLower(table)]
+			if !ok {
+				continue
+			}
+			for _, colId := range f.conv.SrcSchema[tableId].ColIds {
+				if f.isAutoIncrement(tableId, colId) {
+					f.add(tableId, internal.AppFinding{ColId: colId, Issue: internal.AppAutoIncrementOrdering, Source: source, Detail: query})
+				}
+			}
+		}
+	}
+	for _, p := range usage.JSONPaths {
+		if tableId, colId, ok := f.column(p.QueryColumn, usage.Tables); ok {
+			if f.paths[tableId] == nil {
+				f.paths[tableId] = make(map[jsonPathKey]int)
+			}
+			f.paths[tableId][jsonPathKey{colId: colId, path: p.Path}]++
+		}
+	}
+	for _, c := range usage.Lookups {
+		tableId, colId, ok := f.column(c, usage.Tables)
+		if !ok || !f.conv.SrcSchema[tableId].ColDefs[colId].Collation.CaseInsensitive {
+			continue
+		}
+		if spCol, ok := f.conv.SpSchema[tableId].ColDefs[colId]; ok && spCol.T.Name == ddl.String {
+			f.add(tableId, internal.AppFinding{ColId: colId, Issue: internal.AppCaseInsensitiveLookup, Source: source, Detail: query})
+		}
+	}
+}
+
+// addSnippet adds the schema change of the snippet, if any, to its table, or
+// to its column if it names one.
+func (f *appSchemaIssueFinder) addSnippet(s utils.Snippet) {
+	if s.TableName == "" {
+		return
+	}
+	tableId, ok := f.tableIds[strings.ToLower(s.TableName)]
+	if !ok {
+		return
+	}
+	colId := ""
+	if s.ColumnName != "" {
+		if _, id, ok := f.column(utils.QueryColumn{Table: s.TableName, Column: s.ColumnName}, nil); ok {
+			colId = id
+		}
+	}
+	detail := s.SchemaChange
+	if detail == "" {
+		detail = s.Explanation
+	}
+	f.add(tableId, internal.AppFinding{ColId: colId, Issue: internal.AppSchemaChange, Source: fmt.Sprintf("%s (%s)", s.RelativeFilePath, s.Id), Detail: detail})
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assessment
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAppSchemaIssues(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SrcSchema = map[string]schema.Table{
+		"t1": {
+			Name:   "users",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2"},
+			ColDefs: map[string]schema.Column{
+				"c1": {Name: "id", Id: "c1", AutoGen: ddl.AutoGenCol{Name: constants.AUTO_INCREMENT, GenerationType: constants.AUTO_INCREMENT}},
+				"c2": {Name: "email", Id: "c2", Collation: schema.Collation{Name: "utf8mb4_general_ci", CaseInsensitive: true}},
+			},
+		},
+		"t2": {
+			Name:    "orders",
+			Id:      "t2",
+			ColIds:  []string{"c3", "c4", "c5"},
+			ColDefs: map[string]schema.Column{"c3": {Name: "id", Id: "c3"}, "c4": {Name: "user_id", Id: "c4"}, "c5": {Name: "doc", Id: "c5"}},
+		},
+	}
+	conv.SpSchema = ddl.Schema{
+		"t1": {Name: "users", Id: "t1", ColIds: []string{"c1", "c2"}, ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+			"c2": {Name: "email", Id: "c2", T: ddl.Type{Name: ddl.String, Len: 255}},
+		}},
+		"t2": {Name: "orders", Id: "t2", ColIds: []string{"c3", "c4", "c5"}, ColDefs: map[string]ddl.ColumnDef{
+			"c3": {Name: "id", Id: "c3", T: ddl.Type{Name: ddl.Int64}},
+			"c4": {Name: "user_id", Id: "c4", T: ddl.Type{Name: ddl.Int64}},
+			"c5": {Name: "doc", Id: "c5", T: ddl.Type{Name: ddl.JSON}},
+		}},
+	}
+	queries := []utils.QueryTranslationResult{
+		{OriginalQuery: "SELECT * FROM users WHERE email = ? ORDER BY id", AssessmentSource: "app_code", SnippetId: "s1"},
+		{OriginalQuery: "SELECT o.id FROM orders o JOIN users u ON o.user_id = u.id WHERE u.email = ?", AssessmentSource: "performance_schema"},
+		{OriginalQuery: "SELECT * FROM orders ORDER BY id", AssessmentSource: "performance_schema"},
+		{OriginalQuery: "SELECT id FROM orders WHERE doc->>'$.status' = ? ORDER BY doc->'$.created'", AssessmentSource: "performance_schema"},
+		{OriginalQuery: "SELECT o.id FROM orders o JOIN users u ON o.user_id = u.id WHERE o.doc->>'$.status' = 'paid'", AssessmentSource: "performance_schema"},
+	}
+	snippets := []utils.Snippet{
+		{Id: "s1", RelativeFilePath: "/dao/UserDao.java"},
+		{Id: "s2", RelativeFilePath: "/dao/UserDao.java", TableName: "users", ColumnName: "id", SchemaChange: "ids are generated by a bit-reversed sequence"},
+	}
+
+	findings, suggestions := findAppSchemaIssues(conv, queries, snippets)
+	assert.Equal(t, map[string][]internal.AppFinding{
+		"t1": {
+			{ColId: "c1", Issue: internal.AppAutoIncrementOrdering, Source: "/dao/UserDao.java", Detail: "SELECT * FROM users WHERE email = ? ORDER BY id"},
+			{ColId: "c2", Issue: internal.AppCaseInsensitiveLookup, Source: "/dao/UserDao.java", Detail: "SELECT * FROM users WHERE email = ? ORDER BY id"},
+			{ColId: "c2", Issue: internal.AppCaseInsensitiveLookup, Source: "performance_schema", Detail: "SELECT o.id FROM orders o JOIN users u ON o.user_id = u.id WHERE u.email = ?"},
+			{ColId: "c1", Issue: internal.AppSchemaChange, Source: "/dao/UserDao.java (s2)", Detail: "ids are generated by a bit-reversed sequence"},
+		},
+	}, findings)
+	assert.ElementsMatch(t, []internal.JSONPathSuggestion{
+		{ColId: "c5", Path: "$.status", Count: 2},
+		{ColId: "c5", Path: "$.created", Count: 1},
+	}, suggestions["t2"])
+	assert.Len(t, suggestions, 1)
+}
@@ -16,7 +16,9 @@ package assessment
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 	"sync"
 
@@ -24,6 +26,7 @@ import (
 	assessment "github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/collectors"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/sources/mysql"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/task"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
@@ -34,10 +37,13 @@ import (
 )
 
 type assessmentCollectors struct {
-	sampleCollector            *assessment.SampleCollector
-	infoSchemaCollector        *assessment.InfoSchemaCollector
-	appAssessmentCollector     assessment.AppCodeAssessor
-	performanceSchemaCollector *assessment.PerformanceSchemaCollector
+	sampleCollector              *assessment.SampleCollector
+	infoSchemaCollector          *assessment.InfoSchemaCollector
+	appAssessmentCollector       assessment.AppCodeAssessor
+	performanceSchemaCollector   *assessment.PerformanceSchemaCollector
+	accessControlCollector       *assessment.AccessControlCollector
+	performanceSnapshotCollector *assessment.PerformanceSnapshotCollector
+	extensionCollector           *assessment.ExtensionCollector
 }
 
 type assessmentTaskInput struct {
@@ -107,10 +113,19 @@ func PerformAssessment(conv *internal.Conv, sourceProfile profiles.SourceProfile
 		}
 	}
 
+	output.AccessControlAssessment = performAccessControlAssessment(c, conv, assessmentConfig["generateFgacDdl"] == "true")
+	output.ExtensionAssessment = performExtensionAssessment(c)
+	output.PerformanceAssessment = performPerformanceAssessment(c)
+	output.CapacityAssessment = performCapacityAssessment(c, assessmentConfig, output.PerformanceAssessment)
+
 	combinedQueries := combineAndDeduplicateQueries(c.performanceSchemaCollector.Queries, output.AppCodeAssessment)
 	logger.Log.Info("Combined deduplicated queries", zap.Int("count", len(combinedQueries)))
+	// The feature matrix only reads the source queries: build it from all the
+	// collected queries, even if their translation fails.
+	output.FeatureMatrix = performFeatureMatrixAssessment(combinedQueries, output.AppCodeAssessment)
 	translatedQueries, err := performQueryAssessment(ctx, c, combinedQueries, projectId, assessmentConfig, conv)
 	output.QueryAssessment = utils.QueryAssessmentOutput{QueryTranslationResult: &translatedQueries}
+	performAppSchemaIssueAssessment(conv, translatedQueries, output.AppCodeAssessment)
 	if err != nil {
 		logger.Log.Error("error translating queries", zap.Error(err))
 		return output, err
@@ -119,6 +134,10 @@ func PerformAssessment(conv *internal.Conv, sourceProfile profiles.SourceProfile
 	return output, nil
 }
 
+// queryTranslationRules is the queryTranslation of the assessment profile
+// translating queries with rules instead of Vertex AI.
+const queryTranslationRules = "rules"
+
 type AIClientService struct {
 	NewClientFunc        func(ctx context.Context, projectID, location string, opts ...option.ClientOption) (*genai.Client, error)
 	TranslateQueriesFunc func(ctx context.Context, queries []utils.QueryTranslationInput, aiClient *genai.Client, mysqlSchema, spannerSchema string) ([]utils.QueryTranslationResult, error)
@@ -143,18 +162,40 @@ func performQueryAssessment(ctx context.Context, collectors assessmentCollectors
 			conv.DatabaseOptions),
 		"\n")
 
+	useRules := assessmentConfig["queryTranslation"] == queryTranslationRules
+	llmFallback := assessmentConfig["llmFallback"] == "true"
+	if backend := strings.ToLower(assessmentConfig["llmBackend"]); backend != "" && backend != utils.LLMBackendVertexAI {
+		// Queries are only translated with Vertex AI.
+		logger.Log.Info("translating queries with rules as the LLM backend is not Vertex AI", zap.String("backend", backend))
+		useRules, llmFallback = true, false
+	}
 	for _, query := range queries {
 		if query.AssessmentSource == "performance_schema" {
+			if useRules {
+				translated, ok := translateQueryWithRules(conv, query)
+				if ok || !llmFallback {
+					translationResult = append(translationResult, translated)
+					continue
+				}
+			}
 			performanceSchemaQueries = append(performanceSchemaQueries, utils.QueryTranslationInput{
 				Query: query.NormalizedQuery,
 				Count: query.ExecutionCount,
 			})
 		} else {
+			if useRules && query.SpannerQuery == "" {
+				translated := utils.TranslateQueryWithRules(query.OriginalQuery, conv.SpDialect)
+				query.SpannerQuery, query.Explanation, query.Complexity = translated.SpannerQuery, translated.Explanation, translated.Complexity
+			}
 			query.SpannerTablesAffected, query.TranslationError = fetchSpannerTableNames(conv, query.SourceTablesAffected)
 
 			translationResult = append(translationResult, query)
 		}
 	}
+	if useRules && len(performanceSchemaQueries) == 0 {
+		logger.Log.Info("query assessment completed successfully with translation rules.")
+		return translationResult, nil
+	}
 	aiClient, err := aiClientService.NewClientFunc(ctx, projectId, assessmentConfig["location"])
 	if err != nil {
 		return translationResult, fmt.Errorf("Error creating ai client")
@@ -173,6 +214,21 @@ func performQueryAssessment(ctx context.Context, collectors assessmentCollectors
 	return translationResult, nil
 }
 
+// translateQueryWithRules translates a performance schema query with the
+// translation rules, without Vertex AI, and returns whether the rules could
+// translate it.
+func translateQueryWithRules(conv *internal.Conv, query utils.QueryTranslationResult) (utils.QueryTranslationResult, bool) {
+	translated := utils.TranslateQueryWithRules(query.NormalizedQuery, conv.SpDialect)
+	translated.NormalizedQuery = query.NormalizedQuery
+	translated.AssessmentSource = query.AssessmentSource
+	translated.ExecutionCount = query.ExecutionCount
+	if translated.TranslationError != "" {
+		return translated, false
+	}
+	translated.SpannerTablesAffected, translated.TranslationError = fetchSpannerTableNames(conv, translated.SourceTablesAffected)
+	return translated, true
+}
+
 func fetchSpannerTableNames(conv *internal.Conv, tableNames []string) ([]string, string) {
 	spannerTableNames := make([]string, 0, len(tableNames))
 	for _, tableName := range tableNames {
@@ -224,12 +280,41 @@ func initializeCollectors(conv *internal.Conv, sourceProfile profiles.SourceProf
 		logger.Log.Debug("mysqlSchema", zap.String("schema", mysqlSchema))
 		logger.Log.Debug("spannerSchema", zap.String("schema", spannerSchema))
 
+		llmClient, err := utils.NewLLMClient(ctx, assessmentConfig, projectId, assessmentConfig["location"])
+		if err != nil {
+			logger.Log.Error("error initiating the LLM client", zap.Error(err))
+			return c, err
+		}
+		llmBudget, err := utils.NewLLMBudget(assessmentConfig, llmClient.Backend())
+		if err != nil {
+			logger.Log.Error("invalid LLM budget", zap.Error(err))
+			return c, err
+		}
 		summarizer, err := assessment.NewMigrationCodeSummarizer(
-			ctx, nil, projectId, assessmentConfig["location"], mysqlSchema, spannerSchema, codeDirectory, language, sourceFramework, targetFramework)
+			ctx, nil, llmClient, llmBudget, projectId, assessmentConfig["location"], mysqlSchema, spannerSchema, codeDirectory, language, sourceFramework, targetFramework)
 		if err != nil {
 			logger.Log.Error("error initiating migration summarizer")
 			return c, err
 		}
+		if assessmentConfig["llmCache"] != "false" && llmClient.Backend() != utils.LLMBackendNone {
+			cache, err := newLLMResponseCache(assessmentConfig)
+			if err != nil {
+				logger.Log.Warn("LLM analyses will not be cached", zap.Error(err))
+			} else {
+				summarizer.EnableCache(cache)
+			}
+		}
+		if diffRange, ok := assessmentConfig["gitDiffRange"]; ok {
+			incremental := assessment.IncrementalAnalysis{GitDiffRange: diffRange}
+			if previousAssessment, ok := assessmentConfig["previousAssessment"]; ok {
+				incremental.Previous, err = readAppCodeAssessment(previousAssessment)
+				if err != nil {
+					logger.Log.Error("error reading the previous app assessment", zap.Error(err))
+					return c, err
+				}
+			}
+			summarizer.EnableIncrementalAnalysis(incremental)
+		}
 		c.appAssessmentCollector = summarizer
 		logger.Log.Info("initialized app collector")
 	} else {
@@ -247,6 +332,38 @@ func initializeCollectors(conv *internal.Conv, sourceProfile profiles.SourceProf
 		logger.Log.Info("initialized performance schema collector")
 	}
 
+	// Initialize Access Control Collector
+	accessControlCollector, acErr := assessment.GetDefaultAccessControlCollector(sourceProfile)
+	if acErr != nil {
+		logger.Log.Warn("failed to initialize access control collector", zap.Error(acErr))
+		logger.Log.Info("access control assessment will be skipped")
+	} else {
+		c.accessControlCollector = &accessControlCollector
+		logger.Log.Info("initialized access control collector")
+	}
+
+	// Initialize Performance Snapshot Collector
+	performanceSnapshotCollector, psErr := assessment.GetDefaultPerformanceSnapshotCollector(sourceProfile)
+	if psErr != nil {
+		logger.Log.Warn("failed to initialize performance snapshot collector", zap.Error(psErr))
+		logger.Log.Info("performance assessment will be skipped")
+	} else {
+		c.performanceSnapshotCollector = &performanceSnapshotCollector
+		logger.Log.Info("initialized performance snapshot collector")
+	}
+
+	// Initialize Extension Collector, only PostgreSQL has extensions
+	if sourceProfile.Driver == constants.POSTGRES {
+		extensionCollector, extErr := assessment.GetDefaultExtensionCollector(sourceProfile)
+		if extErr != nil {
+			logger.Log.Warn("failed to initialize extension collector", zap.Error(extErr))
+			logger.Log.Info("extension assessment will be skipped")
+		} else {
+			c.extensionCollector = &extensionCollector
+			logger.Log.Info("initialized extension collector")
+		}
+	}
+
 	return c, err
 }
 
@@ -360,6 +477,34 @@ func performSchemaAssessment(ctx context.Context, collectors assessmentCollector
 	return schemaOut, nil
 }
 
+// newLLMResponseCache returns the cache of the LLM analyses in llmCacheDir, or
+// in the user cache directory by default.
+func newLLMResponseCache(assessmentConfig map[string]string) (*utils.LLMResponseCache, error) {
+	cacheDir := assessmentConfig["llmCacheDir"]
+	if cacheDir == "" {
+		var err error
+		cacheDir, err = utils.DefaultLLMCacheDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return utils.NewLLMResponseCache(cacheDir)
+}
+
+// readAppCodeAssessment reads the app code assessment written by
+// GenerateReport to file.
+func readAppCodeAssessment(file string) (*utils.AppCodeAssessmentOutput, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var appAssessment utils.AppCodeAssessmentOutput
+	if err := json.Unmarshal(data, &appAssessment); err != nil {
+		return nil, fmt.Errorf("could not parse the app assessment %s: %w", file, err)
+	}
+	return &appAssessment, nil
+}
+
 func performAppAssessment(ctx context.Context, collectors assessmentCollectors) (*utils.AppCodeAssessmentOutput, error) {
 
 	if collectors.appAssessmentCollector == nil {
@@ -385,6 +530,7 @@ func performAppAssessment(ctx context.Context, collectors assessmentCollectors)
 		TotalFiles:             codeAssessment.TotalFiles,
 		CodeSnippets:           codeAssessment.Snippets,
 		QueryTranslationResult: &queryResults,
+		LLMUsage:               codeAssessment.LLMUsage,
 	}, nil
 }
 
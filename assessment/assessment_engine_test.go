@@ -256,6 +256,51 @@ func TestPerformQueryAssessment(t *testing.T) {
 		assert.Len(t, result, 1)
 		assert.Equal(t, "INSERT INTO products", result[0].OriginalQuery)
 	})
+
+	t.Run("translation rules", func(t *testing.T) {
+		aiClientService.NewClientFunc = func(ctx context.Context, projectID, location string, opts ...option.ClientOption) (*genai.Client, error) {
+			return nil, errors.New("vertex ai must not be used")
+		}
+
+		queries := []utils.QueryTranslationResult{
+			{OriginalQuery: "SELECT * FROM users", NormalizedQuery: "SELECT IFNULL(name, ?) FROM users LIMIT ?, ?", AssessmentSource: "performance_schema", ExecutionCount: 100},
+			{OriginalQuery: "SELECT FIND_IN_SET(?, tags) FROM users", NormalizedQuery: "SELECT FIND_IN_SET(?, tags) FROM users", AssessmentSource: "performance_schema"},
+		}
+
+		result, err := performQueryAssessment(ctx, collectors, queries, projectId, map[string]string{"queryTranslation": "rules"}, conv)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "SELECT COALESCE(name, ?) FROM users LIMIT ? OFFSET ?", result[0].SpannerQuery)
+		assert.Equal(t, 100, result[0].ExecutionCount)
+		assert.Equal(t, "performance_schema", result[0].AssessmentSource)
+		assert.Equal(t, "no rule to translate the functions FIND_IN_SET", result[1].TranslationError)
+	})
+
+	t.Run("translation rules with llm fallback", func(t *testing.T) {
+		aiClientService.NewClientFunc = func(ctx context.Context, projectID, location string, opts ...option.ClientOption) (*genai.Client, error) {
+			return &genai.Client{}, nil
+		}
+
+		aiClientService.TranslateQueriesFunc = func(ctx context.Context, queries []utils.QueryTranslationInput, aiClient *genai.Client, mysqlSchema, spannerSchema string) ([]utils.QueryTranslationResult, error) {
+			assert.Len(t, queries, 1) // Only the query the rules can't translate.
+			return []utils.QueryTranslationResult{
+				{OriginalQuery: queries[0].Query, SpannerQuery: "SELECT ? IN UNNEST(SPLIT(tags, ',')) FROM users", AssessmentSource: "performance_schema"},
+			}, nil
+		}
+
+		queries := []utils.QueryTranslationResult{
+			{OriginalQuery: "SELECT * FROM users", NormalizedQuery: "SELECT * FROM users WHERE id = ?", AssessmentSource: "performance_schema"},
+			{OriginalQuery: "SELECT FIND_IN_SET(?, tags) FROM users", NormalizedQuery: "SELECT FIND_IN_SET(?, tags) FROM users", AssessmentSource: "performance_schema"},
+		}
+
+		result, err := performQueryAssessment(ctx, collectors, queries, projectId, map[string]string{"queryTranslation": "rules", "llmFallback": "true"}, conv)
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "SELECT * FROM users WHERE id = ?", result[0].SpannerQuery)
+		assert.Equal(t, "SELECT ? IN UNNEST(SPLIT(tags, ',')) FROM users", result[1].SpannerQuery)
+	})
 }
 
 func TestFetchSpannerTableNames(t *testing.T) {
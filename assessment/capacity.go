@@ -0,0 +1,112 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package assessment
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+)
+
+const (
+	processingUnitsPerNode = 1000
+	minProcessingUnits     = 100
+	// Storage limit of a node. The steady state capacity keeps the data
+	// within storageUtilization of it, leaving room for growth.
+	storageBytesPerNode = 10 << 40
+	storageUtilization  = 0.7
+	// Throughput of a node for the batched writes of a bulk load.
+	bulkLoadBytesPerSecondPerNode = 10 << 20
+	bulkLoadRowsPerSecondPerNode  = 10000
+	defaultBulkLoadHours          = 24
+	// Throughput of a node for the reads and writes of the application, with
+	// the CPU utilization kept below the recommended maximum.
+	readsPerSecondPerNode  = 10000
+	writesPerSecondPerNode = 2000
+	cpuUtilization         = 0.65
+)
+
+func performCapacityAssessment(collectors assessmentCollectors, assessmentConfig map[string]string, performance *utils.PerformanceAssessmentOutput) *utils.CapacityAssessmentOutput {
+	if collectors.infoSchemaCollector == nil || collectors.infoSchemaCollector.IsEmpty() {
+		logger.Log.Info("not proceeding with capacity assessment as infoschema collector was not initialized")
+		return nil
+	}
+	bulkLoadHours := defaultBulkLoadHours
+	if value, ok := assessmentConfig["bulkLoadHours"]; ok {
+		hours, err := strconv.Atoi(value)
+		if err != nil || hours <= 0 {
+			logger.Log.Warn(fmt.Sprintf("invalid bulkLoadHours %s in assessment profile, defaulting to %d", value, defaultBulkLoadHours))
+		} else {
+			bulkLoadHours = hours
+		}
+	}
+	logger.Log.Info("starting capacity assessment...")
+	out := estimateCapacity(collectors.infoSchemaCollector.ListTableSizes(), bulkLoadHours)
+	if performance != nil {
+		addThroughputCapacity(out, performance.ReadsPerSecond, performance.WritesPerSecond)
+	}
+	logger.Log.Info("capacity assessment completed successfully.")
+	return out
+}
+
+// estimateCapacity recommends the compute capacity of the Spanner instance
+// for the bulk load of the tables within bulkLoadHours, and to store them
+// once loaded.
+func estimateCapacity(tables []utils.TableSize, bulkLoadHours int) *utils.CapacityAssessmentOutput {
+	out := &utils.CapacityAssessmentOutput{Tables: tables, BulkLoadHours: bulkLoadHours}
+	for _, t := range tables {
+		out.TotalRows += t.RowCount
+		out.TotalBytes += t.SizeBytes
+	}
+	out.SteadyStateProcessingUnits = roundProcessingUnits(float64(out.TotalBytes) / (storageBytesPerNode * storageUtilization))
+	seconds := float64(bulkLoadHours) * 3600
+	loadNodes := math.Max(float64(out.TotalBytes)/(bulkLoadBytesPerSecondPerNode*seconds), float64(out.TotalRows)/(bulkLoadRowsPerSecondPerNode*seconds))
+	out.BulkLoadProcessingUnits = roundProcessingUnits(loadNodes)
+	if out.BulkLoadProcessingUnits < out.SteadyStateProcessingUnits {
+		out.BulkLoadProcessingUnits = out.SteadyStateProcessingUnits
+	}
+	return out
+}
+
+// addThroughputCapacity raises the steady state capacity of out to serve the
+// reads and writes per second of the source, and the bulk load capacity to
+// at least the steady state one.
+func addThroughputCapacity(out *utils.CapacityAssessmentOutput, readsPerSecond, writesPerSecond float64) {
+	nodes := (readsPerSecond/readsPerSecondPerNode + writesPerSecond/writesPerSecondPerNode) / cpuUtilization
+	out.ThroughputProcessingUnits = roundProcessingUnits(nodes)
+	if out.SteadyStateProcessingUnits < out.ThroughputProcessingUnits {
+		out.SteadyStateProcessingUnits = out.ThroughputProcessingUnits
+	}
+	if out.BulkLoadProcessingUnits < out.SteadyStateProcessingUnits {
+		out.BulkLoadProcessingUnits = out.SteadyStateProcessingUnits
+	}
+}
+
+// roundProcessingUnits rounds a number of nodes up to the granularity of
+// Spanner compute capacity: multiples of 100 processing units below a node
+// and whole nodes above.
+func roundProcessingUnits(nodes float64) int32 {
+	units := nodes * processingUnitsPerNode
+	if units <= minProcessingUnits {
+		return minProcessingUnits
+	}
+	if units <= processingUnitsPerNode {
+		return int32(math.Ceil(units/minProcessingUnits) * minProcessingUnits)
+	}
+	return int32(math.Ceil(units/processingUnitsPerNode) * processingUnitsPerNode)
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assessment
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateCapacity(t *testing.T) {
+	testCases := []struct {
+		name        string
+		tables      []utils.TableSize
+		hours       int
+		bulkLoad    int32
+		steadyState int32
+	}{
+		{
+			name:        "empty database",
+			hours:       24,
+			bulkLoad:    100,
+			steadyState: 100,
+		},
+		{
+			name:        "small database",
+			tables:      []utils.TableSize{{Name: "orders", RowCount: 1000000, SizeBytes: 1 << 30}},
+			hours:       24,
+			bulkLoad:    100,
+			steadyState: 100,
+		},
+		{
+			// The number of rows drives the bulk load capacity.
+			name:        "many rows",
+			tables:      []utils.TableSize{{Name: "events", RowCount: 10000000000, SizeBytes: 1 << 40}, {Name: "users", RowCount: 0, SizeBytes: 1 << 40}},
+			hours:       24,
+			bulkLoad:    12000,
+			steadyState: 300,
+		},
+		{
+			// The size of the data drives both capacities.
+			name:        "large database",
+			tables:      []utils.TableSize{{Name: "blobs", RowCount: 1000000, SizeBytes: 30 << 40}},
+			hours:       24,
+			bulkLoad:    37000,
+			steadyState: 5000,
+		},
+		{
+			name:        "large database with a longer bulk load",
+			tables:      []utils.TableSize{{Name: "blobs", RowCount: 1000000, SizeBytes: 30 << 40}},
+			hours:       240,
+			bulkLoad:    5000,
+			steadyState: 5000,
+		},
+	}
+	for _, tc := range testCases {
+		out := estimateCapacity(tc.tables, tc.hours)
+		assert.Equal(t, tc.bulkLoad, out.BulkLoadProcessingUnits, tc.name)
+		assert.Equal(t, tc.steadyState, out.SteadyStateProcessingUnits, tc.name)
+	}
+	out := estimateCapacity([]utils.TableSize{{Name: "a", RowCount: 2, SizeBytes: 10}, {Name: "b", RowCount: 3, SizeBytes: 20}}, 24)
+	assert.Equal(t, int64(5), out.TotalRows)
+	assert.Equal(t, int64(30), out.TotalBytes)
+}
+
+func TestAddThroughputCapacity(t *testing.T) {
+	testCases := []struct {
+		name          string
+		reads, writes float64
+		throughput    int32
+		bulkLoad      int32
+		steadyState   int32
+	}{
+		{
+			name:        "idle source",
+			throughput:  100,
+			bulkLoad:    1000,
+			steadyState: 300,
+		},
+		{
+			// 1.5 nodes of load within the CPU utilization.
+			name:        "busy source",
+			reads:       6500,
+			writes:      650,
+			throughput:  2000,
+			bulkLoad:    2000,
+			steadyState: 2000,
+		},
+	}
+	for _, tc := range testCases {
+		out := &utils.CapacityAssessmentOutput{BulkLoadProcessingUnits: 1000, SteadyStateProcessingUnits: 300}
+		addThroughputCapacity(out, tc.reads, tc.writes)
+		assert.Equal(t, tc.throughput, out.ThroughputProcessingUnits, tc.name)
+		assert.Equal(t, tc.bulkLoad, out.BulkLoadProcessingUnits, tc.name)
+		assert.Equal(t, tc.steadyState, out.SteadyStateProcessingUnits, tc.name)
+	}
+}
+
+func TestRoundProcessingUnits(t *testing.T) {
+	for nodes, units := range map[float64]int32{0: 100, 0.1: 100, 0.25: 300, 1: 1000, 1.2: 2000, 7: 7000} {
+		assert.Equal(t, units, roundProcessingUnits(nodes), nodes)
+	}
+}
+
+func TestGenerateCapacityReport(t *testing.T) {
+	records := generateCapacityReport(&utils.CapacityAssessmentOutput{
+		Tables:                     []utils.TableSize{{Name: "orders", RowCount: 10, SizeBytes: 4096}},
+		TotalRows:                  10,
+		TotalBytes:                 4096,
+		BulkLoadHours:              24,
+		BulkLoadProcessingUnits:    200,
+		SteadyStateProcessingUnits: 100,
+	})
+	assert.Len(t, records, 5)
+	assert.Equal(t, []string{"orders", "10", "4096", "", ""}, records[1])
+	assert.Equal(t, "200", records[3][3])
+	assert.Contains(t, records[3][4], "within 24 hours")
+	assert.Contains(t, records[3][4], "--processing-units=200")
+	assert.Equal(t, "100", records[4][3])
+
+	records = generateCapacityReport(&utils.CapacityAssessmentOutput{SteadyStateProcessingUnits: 2000, ThroughputProcessingUnits: 2000})
+	assert.Len(t, records, 5)
+	assert.Equal(t, []string{"Throughput", "", "", "2000"}, records[4][:4])
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assessment
+
+import (
+	"database/sql"
+	"fmt"
+
+	collectorCommon "github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/collectors/common"
+	sourcesCommon "github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/sources"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/sources/mysql"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"go.uber.org/zap"
+)
+
+// AccessControlCollector collects users, roles and grants from source databases
+type AccessControlCollector struct {
+	Users  []utils.UserAssessmentInfo
+	Grants []utils.GrantAssessmentInfo
+}
+
+// IsEmpty checks if the collector has any data
+func (c AccessControlCollector) IsEmpty() bool {
+	return len(c.Users) == 0 && len(c.Grants) == 0
+}
+
+// GetDefaultAccessControlCollector creates a new AccessControlCollector with default settings
+func GetDefaultAccessControlCollector(sourceProfile profiles.SourceProfile) (AccessControlCollector, error) {
+	return GetAccessControlCollector(sourceProfile, collectorCommon.SQLDBConnector{}, collectorCommon.DefaultConnectionConfigProvider{}, DefaultAccessControlSchemaProvider{})
+}
+
+// GetAccessControlCollector creates a new AccessControlCollector with custom dependencies
+func GetAccessControlCollector(sourceProfile profiles.SourceProfile, dbConnector collectorCommon.DBConnector, configProvider collectorCommon.ConnectionConfigProvider, accessControlSchemaProvider AccessControlSchemaProvider) (AccessControlCollector, error) {
+	logger.Log.Info("initializing access control collector")
+
+	connectionConfig, err := configProvider.GetConnectionConfig(sourceProfile)
+	if err != nil {
+		return AccessControlCollector{}, fmt.Errorf("failed to get connection config: %w", err)
+	}
+
+	db, err := dbConnector.Connect(sourceProfile.Driver, connectionConfig)
+	if err != nil {
+		return AccessControlCollector{}, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	accessControlSchema, err := accessControlSchemaProvider.getAccessControlSchema(db, sourceProfile)
+	if err != nil {
+		return AccessControlCollector{}, fmt.Errorf("failed to get access control schema: %w", err)
+	}
+
+	users, err := accessControlSchema.GetUserInfo()
+	if err != nil {
+		return AccessControlCollector{}, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	grants, err := accessControlSchema.GetGrantInfo()
+	if err != nil {
+		return AccessControlCollector{}, fmt.Errorf("failed to get grants: %w", err)
+	}
+
+	logger.Log.Info("access control collector initialized successfully",
+		zap.Int("user_count", len(users)), zap.Int("grant_count", len(grants)))
+
+	return AccessControlCollector{
+		Users:  users,
+		Grants: grants,
+	}, nil
+}
+
+// AccessControlSchemaProvider interface for reading users, roles and grants
+type AccessControlSchemaProvider interface {
+	getAccessControlSchema(db *sql.DB, sourceProfile profiles.SourceProfile) (sourcesCommon.AccessControlSchema, error)
+}
+
+// DefaultAccessControlSchemaProvider provides the access control schema of supported sources
+type DefaultAccessControlSchemaProvider struct{}
+
+// getAccessControlSchema creates an access control schema implementation based on the database driver
+func (d DefaultAccessControlSchemaProvider) getAccessControlSchema(db *sql.DB, sourceProfile profiles.SourceProfile) (sourcesCommon.AccessControlSchema, error) {
+	driver := sourceProfile.Driver
+	switch driver {
+	case constants.MYSQL:
+		return mysql.AccessControlSchemaImpl{
+			Db:     db,
+			DbName: sourceProfile.Conn.Mysql.Db,
+		}, nil
+	default:
+		return nil, fmt.Errorf("driver %s not supported for access control schema", driver)
+	}
+}
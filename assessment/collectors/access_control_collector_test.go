@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assessment
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sourcesCommon "github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/sources"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/sources/mysql"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAccessControlSchema struct {
+	mock.Mock
+}
+
+func (m *MockAccessControlSchema) GetUserInfo() ([]utils.UserAssessmentInfo, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]utils.UserAssessmentInfo), args.Error(1)
+}
+
+func (m *MockAccessControlSchema) GetGrantInfo() ([]utils.GrantAssessmentInfo, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]utils.GrantAssessmentInfo), args.Error(1)
+}
+
+type MockAccessControlSchemaProvider struct {
+	mock.Mock
+}
+
+func (m *MockAccessControlSchemaProvider) getAccessControlSchema(db *sql.DB, sourceProfile profiles.SourceProfile) (sourcesCommon.AccessControlSchema, error) {
+	args := m.Called(db, sourceProfile)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(sourcesCommon.AccessControlSchema), args.Error(1)
+}
+
+func TestDefaultAccessControlSchemaProvider_getAccessControlSchema(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	provider := DefaultAccessControlSchemaProvider{}
+	acs, err := provider.getAccessControlSchema(db, profiles.SourceProfile{
+		Driver: constants.MYSQL,
+		Conn: profiles.SourceProfileConnection{
+			Mysql: profiles.SourceProfileConnectionMySQL{Db: "test_mysql_db"},
+		},
+	})
+	assert.NoError(t, err)
+	mysqlACS, ok := acs.(mysql.AccessControlSchemaImpl)
+	assert.True(t, ok, "Expected mysql.AccessControlSchemaImpl type")
+	assert.Equal(t, "test_mysql_db", mysqlACS.DbName)
+
+	acs, err = provider.getAccessControlSchema(db, profiles.SourceProfile{Driver: "unsupported_db"})
+	assert.Nil(t, acs)
+	assert.EqualError(t, err, "driver unsupported_db not supported for access control schema")
+}
+
+func TestGetAccessControlCollector(t *testing.T) {
+	dummyDb, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating dummy sqlmock DB: %v", err)
+	}
+	defer dummyDb.Close()
+
+	sourceProfile := profiles.SourceProfile{
+		Driver: constants.MYSQL,
+		Conn: profiles.SourceProfileConnection{
+			Mysql: profiles.SourceProfileConnectionMySQL{Db: "test_db"},
+		},
+	}
+	users := []utils.UserAssessmentInfo{{Name: "app", Host: "%", Roles: []string{"reader"}}, {Name: "reader", Host: "%", IsRole: true}}
+	grants := []utils.GrantAssessmentInfo{{Grantee: "reader", Privilege: "SELECT", Level: utils.GrantLevelSchema}}
+
+	mockCfgProvider := new(MockConnectionConfigProvider)
+	mockDbConnector := new(MockDBConnector)
+	mockProvider := new(MockAccessControlSchemaProvider)
+	mockACS := new(MockAccessControlSchema)
+	mockCfgProvider.On("GetConnectionConfig", sourceProfile).Return("mock_conn_string", nil)
+	mockDbConnector.On("Connect", sourceProfile.Driver, "mock_conn_string").Return(dummyDb, nil)
+	mockProvider.On("getAccessControlSchema", dummyDb, sourceProfile).Return(mockACS, nil)
+	mockACS.On("GetUserInfo").Return(users, nil).Once()
+	mockACS.On("GetGrantInfo").Return(grants, nil).Once()
+
+	collector, err := GetAccessControlCollector(sourceProfile, mockDbConnector, mockCfgProvider, mockProvider)
+	assert.NoError(t, err)
+	assert.False(t, collector.IsEmpty())
+	assert.Equal(t, users, collector.Users)
+	assert.Equal(t, grants, collector.Grants)
+
+	mockACS.On("GetUserInfo").Return(nil, errors.New("access denied")).Once()
+	collector, err = GetAccessControlCollector(sourceProfile, mockDbConnector, mockCfgProvider, mockProvider)
+	assert.EqualError(t, err, "failed to get users: access denied")
+	assert.True(t, collector.IsEmpty())
+}
@@ -47,22 +47,6 @@ var daoMigrationPromptTemplate string
 //go:embed prompts/non-dao-migration-prompt.txt
 var nonDAOMigrationPromptTemplate string
 
-// generativeModel defines an interface for interacting with a generative AI model.
-type generativeModel interface {
-	GenerateContent(ctx context.Context, parts ...genai.Part) (*genai.GenerateContentResponse, error)
-	SetResponseMIMEType(string)
-}
-
-// genaiModelWrapper wraps the actual genai.GenerativeModel to implement the generativeModel interface.
-type genaiModelWrapper struct {
-	*genai.GenerativeModel
-}
-
-// SetResponseMIMEType sets the response MIME type for the underlying model.
-func (w *genaiModelWrapper) SetResponseMIMEType(mimeType string) {
-	w.GenerativeModel.ResponseMIMEType = mimeType
-}
-
 // AppCodeAssessor defines the interface for any component that can analyze application code.
 type AppCodeAssessor interface {
 	AnalyzeProject(ctx context.Context) (*utils.CodeAssessment, []utils.QueryTranslationResult, error)
@@ -72,9 +56,7 @@ type AppCodeAssessor interface {
 type MigrationCodeSummarizer struct {
 	gcpProjectID               string
 	gcpLocation                string
-	aiClient                   *genai.Client
-	geminiProModel             generativeModel
-	geminiFlashModel           generativeModel
+	llm                        *utils.BudgetedLLMClient
 	codeSampleDatabase         *assessment.MysqlConceptDb
 	querySampleDatabase        *assessment.MysqlConceptDb
 	sourceDatabaseFramework    string
@@ -86,6 +68,14 @@ type MigrationCodeSummarizer struct {
 	projectRootPath            string
 	dependencyGraph            map[string]map[string]struct{}
 	fileDependencyAnalysis     map[string]FileDependencyInfo
+	incremental                *IncrementalAnalysis
+	cache                      *utils.LLMResponseCache
+}
+
+// fileAnalysisCacheEntry is the cached LLM analysis of a file.
+type fileAnalysisCacheEntry struct {
+	Response string `json:"response"`
+	IsDAO    bool   `json:"is_dao"`
 }
 
 // FileDependencyInfo stores dependency analysis data for a single file.
@@ -137,11 +127,17 @@ var SupportedFrameworkCombinations = map[FrameworkPair]bool{
 	// Add more allowed combinations here
 }
 
-// NewMigrationCodeSummarizer initializes a new MigrationCodeSummarizer.
+// NewMigrationCodeSummarizer initializes a new MigrationCodeSummarizer which
+// analyzes the code with llmClient, or with Vertex AI if llmClient is nil,
+// within budget.
+// The code and query samples guiding the analyses need Vertex AI embeddings,
+// so they are only used with the Vertex AI backend.
 // ToDo:Add Unit Tests
 func NewMigrationCodeSummarizer(
 	ctx context.Context,
 	googleGenerativeAIAPIKey *string,
+	llmClient utils.LLMClient,
+	budget utils.LLMBudget,
 	projectID, location, sourceSchema, targetSchema, projectPath, language, sourceFramework, targetFramework string,
 ) (*MigrationCodeSummarizer, error) {
 
@@ -176,27 +172,34 @@ func NewMigrationCodeSummarizer(
 		os.Setenv("GOOGLE_API_KEY", *googleGenerativeAIAPIKey)
 	}
 
-	client, err := genai.NewClient(ctx, projectID, location)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Vertex AI client: %w", err)
+	if llmClient == nil {
+		client, err := genai.NewClient(ctx, projectID, location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Vertex AI client: %w", err)
+		}
+		llmClient = utils.NewVertexAILLMClient(client)
 	}
 
-	codeSampleDB, err := assessment.NewMysqlToSpannerCodeDb(projectID, location, strings.ToLower(sourceFramework)+"_"+strings.ToLower(targetFramework))
-	if err != nil {
-		return nil, fmt.Errorf("failed to load code sample DB: %w", err)
-	}
+	var codeSampleDB, querySampleDB *assessment.MysqlConceptDb
+	if llmClient.Backend() == utils.LLMBackendVertexAI {
+		var err error
+		codeSampleDB, err = assessment.NewMysqlToSpannerCodeDb(projectID, location, strings.ToLower(sourceFramework)+"_"+strings.ToLower(targetFramework))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load code sample DB: %w", err)
+		}
 
-	querySampleDB, err := assessment.NewMysqlToSpannerQueryDb(projectID, location)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load MySQL query sample DB: %w", err)
+		querySampleDB, err = assessment.NewMysqlToSpannerQueryDb(projectID, location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load MySQL query sample DB: %w", err)
+		}
+	} else {
+		logger.Log.Info("code and query samples are only used with the Vertex AI LLM backend", zap.String("backend", llmClient.Backend()))
 	}
 
 	summarizer := &MigrationCodeSummarizer{
 		gcpProjectID:               projectID,
 		gcpLocation:                location,
-		aiClient:                   client,
-		geminiProModel:             &genaiModelWrapper{client.GenerativeModel(utils.GEMINI_PRO_MODEL)},
-		geminiFlashModel:           &genaiModelWrapper{client.GenerativeModel(utils.GEMINI_FLASH_MODEL)},
+		llm:                        utils.NewBudgetedLLMClient(llmClient, budget),
 		codeSampleDatabase:         codeSampleDB,
 		projectDependencyAnalyzer:  projectDependencyAnalyzer,
 		sourceDatabaseSchema:       sourceSchema,
@@ -209,9 +212,6 @@ func NewMigrationCodeSummarizer(
 		dependencyGraph:            make(map[string]map[string]struct{}),
 		fileDependencyAnalysis:     make(map[string]FileDependencyInfo),
 	}
-	summarizer.geminiFlashModel.SetResponseMIMEType("application/json")
-	summarizer.geminiProModel.SetResponseMIMEType("application/json")
-
 	return summarizer, nil
 }
 
@@ -227,24 +227,12 @@ func (m *MigrationCodeSummarizer) InvokeCodeConversion(
 	prompt = strings.ReplaceAll(prompt, "{{OLDER_SCHEMA}}", olderSchema)
 	prompt = strings.ReplaceAll(prompt, "{{NEW_SCHEMA}}", newSchema)
 
-	retryClient := utils.DefaultLLMRetryClient{}
-	response, err := retryClient.GenerateContentWithRetry(ctx, m.geminiFlashModel.(*genaiModelWrapper).GenerativeModel, genai.Text(prompt), 5, logger.Log)
+	llmResponse, _, err := m.llm.GenerateContent(ctx, utils.LLMFlashModel, prompt)
 	if err != nil {
 		return "", err
 	}
-	logger.Log.Debug("LLM Token Usage (Initial Conversion): ",
-		zap.Int32("Prompt Tokens", response.UsageMetadata.PromptTokenCount),
-		zap.Int32("Candidate Tokens", response.UsageMetadata.CandidatesTokenCount),
-		zap.Int32("Total Tokens", response.UsageMetadata.TotalTokenCount))
 
-	var llmResponse string
-	if response.Candidates != nil && len(response.Candidates) > 0 && len(response.Candidates[0].Content.Parts) > 0 {
-		if part, ok := response.Candidates[0].Content.Parts[0].(genai.Text); ok {
-			llmResponse = string(part)
-		}
-	}
-
-	llmResponse = m.parseJSONWithRetries(m.geminiFlashModel, prompt, llmResponse, identifier)
+	llmResponse = m.parseJSONWithRetries(utils.LLMFlashModel, prompt, llmResponse, identifier)
 
 	var questionOutput LLMQuestionOutput
 	err = json.Unmarshal([]byte(llmResponse), &questionOutput) // Convert JSON string to struct
@@ -253,7 +241,7 @@ func (m *MigrationCodeSummarizer) InvokeCodeConversion(
 	}
 
 	finalPrompt := originalPrompt
-	if len(questionOutput.Questions) > 0 {
+	if len(questionOutput.Questions) > 0 && m.codeSampleDatabase != nil && m.querySampleDatabase != nil {
 		codeSearchResults := make([][]string, len(questionOutput.Questions))
 		querySearchResults := make([][]string, len(questionOutput.Questions))
 		answersPresent := false
@@ -292,25 +280,15 @@ func (m *MigrationCodeSummarizer) InvokeCodeConversion(
 		}
 	}
 
-	finalResponse, err := retryClient.GenerateContentWithRetry(ctx, m.geminiProModel.(*genaiModelWrapper).GenerativeModel, genai.Text(finalPrompt), 5, logger.Log)
+	llmResponse, _, err = m.llm.GenerateContent(ctx, utils.LLMProModel, finalPrompt)
 	if err != nil {
 		logger.Log.Error("Error generating final content:", zap.Error(err))
 		return "", err
 	}
-	logger.Log.Debug("LLM Token Usage (Final Conversion): ",
-		zap.Int32("Prompt Tokens", finalResponse.UsageMetadata.PromptTokenCount),
-		zap.Int32("Candidate Tokens", finalResponse.UsageMetadata.CandidatesTokenCount),
-		zap.Int32("Total Tokens", finalResponse.UsageMetadata.TotalTokenCount))
-
-	if len(finalResponse.Candidates) > 0 && len(finalResponse.Candidates[0].Content.Parts) > 0 {
-		if part, ok := finalResponse.Candidates[0].Content.Parts[0].(genai.Text); ok {
-			llmResponse = string(part)
-		}
-	}
 
 	logger.Log.Debug("Final LLM Response: ", zap.String("response", llmResponse))
 
-	llmResponse = m.parseJSONWithRetries(m.geminiProModel, finalPrompt, llmResponse, identifier)
+	llmResponse = m.parseJSONWithRetries(utils.LLMProModel, finalPrompt, llmResponse, identifier)
 
 	return llmResponse, nil
 }
@@ -333,7 +311,7 @@ func formatQuestionsAndSearchResults(questions []string, codeSearchResults [][]s
 	return formattedString
 }
 
-func (m *MigrationCodeSummarizer) parseJSONWithRetries(model generativeModel, originalPrompt string, originalResponse string, identifier string) string {
+func (m *MigrationCodeSummarizer) parseJSONWithRetries(tier utils.LLMModelTier, originalPrompt string, originalResponse string, identifier string) string {
 	jsonFixPromptTemplate := `
         You are a JSON parser expert tasked with fixing parsing errors in JSON string. Golang's json.Unmarshal library is
         being used for parsing the json string. The following JSON string is currently failing with error message: %s.
@@ -371,20 +349,12 @@ func (m *MigrationCodeSummarizer) parseJSONWithRetries(model generativeModel, or
 
 		logger.Log.Debug("JSON Parsing Retry Prompt: ", zap.String("prompt", newPrompt))
 
-		retryClient := utils.DefaultLLMRetryClient{}
-		resp, err := retryClient.GenerateContentWithRetry(context.Background(), model.(*genaiModelWrapper).GenerativeModel, genai.Text(newPrompt), 5, logger.Log)
+		resp, _, err := m.llm.GenerateContent(context.Background(), tier, newPrompt)
 		if err != nil {
 			logger.Log.Warn("Failed to get response from LLM for JSON parsing retry: ", zap.Error(err))
+			continue
 		}
-		logger.Log.Debug("LLM Token Usage (JSON Parsing Retry): ",
-			zap.Int32("Prompt Tokens", resp.UsageMetadata.PromptTokenCount),
-			zap.Int32("Candidate Tokens", resp.UsageMetadata.CandidatesTokenCount),
-			zap.Int32("Total Tokens", resp.UsageMetadata.TotalTokenCount))
-		if len(resp.Candidates) > 0 && len(resp.Candidates[0].Content.Parts) > 0 {
-			if part, ok := resp.Candidates[0].Content.Parts[0].(genai.Text); ok {
-				originalResponse = string(part)
-			}
-		}
+		originalResponse = resp
 	}
 	logger.Log.Warn("Failed to parse JSON after multiple retries for identifier: ", zap.String("identifier", identifier), zap.String("originalResponse", originalResponse))
 	return ""
@@ -412,6 +382,23 @@ func (m *MigrationCodeSummarizer) AnalyzeFileTask(analyzeFileInput *FileAnalysis
 	return task.TaskResult[*FileAnalysisResponse]{Result: analyzeFileResponse, Err: nil}
 }
 
+// EnableCache caches the LLM analyses of the files in cache.
+func (m *MigrationCodeSummarizer) EnableCache(cache *utils.LLMResponseCache) {
+	m.cache = cache
+}
+
+// fileAnalysisCacheKey returns the key of the cache of the analysis of the
+// file filePath of content. Analyses are redone when the file, the signatures
+// of the methods it depends on, the schemas, the frameworks, the LLM backend
+// or the prompts change.
+func (m *MigrationCodeSummarizer) fileAnalysisCacheKey(projectPath, filePath, methodChanges, content string) string {
+	return utils.LLMCacheKey(
+		analyzeCodePromptTemplate, daoMigrationPromptTemplate, nonDAOMigrationPromptTemplate,
+		m.sourceDatabaseSchema, m.targetDatabaseSchema,
+		m.sourceDatabaseFramework, m.targetDatabaseFramework, m.llm.Backend(),
+		parser.GetRelativeFilePath(projectPath, filePath), methodChanges, content)
+}
+
 // AnalyzeFile analyzes a single file to identify potential migration issues.
 func (m *MigrationCodeSummarizer) AnalyzeFile(ctx context.Context, projectPath, filepath, methodChanges, content string, fileIndex int) *FileAnalysisResponse {
 	emptySnippets := make([]utils.Snippet, 0)
@@ -426,7 +413,20 @@ func (m *MigrationCodeSummarizer) AnalyzeFile(ctx context.Context, projectPath,
 	extractedMethodSignatures := make([]any, 0)
 	var queryResults []utils.QueryTranslationResult
 
-	if m.projectDependencyAnalyzer.IsDAO(filepath, content) {
+	cacheKey := m.fileAnalysisCacheKey(projectPath, filepath, methodChanges, content)
+	var cached fileAnalysisCacheEntry
+	isCached := m.cache.Get(cacheKey, &cached)
+	if isCached {
+		logger.Log.Debug("Using cached analysis of file: ", zap.String("filepath", filepath))
+		llmResponse = cached.Response
+		isDataAccessObject = cached.IsDAO
+		publicMethods, err := m.extractPublicMethodSignatures(llmResponse)
+		if err != nil {
+			logger.Log.Error("Error extracting public method signatures from cached analysis: ", zap.Error(err))
+		} else {
+			extractedMethodSignatures = publicMethods
+		}
+	} else if m.projectDependencyAnalyzer.IsDAO(filepath, content) {
 		logger.Log.Debug("Analyzing DAO File: ", zap.String("filepath", filepath))
 		var err error
 		prompt := m.getPromptForDAOClass(content, filepath, &methodChanges, &m.sourceDatabaseSchema, &m.targetDatabaseSchema)
@@ -449,24 +449,13 @@ func (m *MigrationCodeSummarizer) AnalyzeFile(ctx context.Context, projectPath,
 	} else {
 		logger.Log.Debug("Analyzing Non-DAO File: ", zap.String("filepath", filepath))
 		prompt := m.getPromptForNonDAOClass(content, filepath, &methodChanges)
-		retryClient := utils.DefaultLLMRetryClient{}
-		response, err := retryClient.GenerateContentWithRetry(ctx, m.geminiFlashModel.(*genaiModelWrapper).GenerativeModel, genai.Text(prompt), 5, logger.Log)
-
+		var err error
+		llmResponse, _, err = m.llm.GenerateContent(ctx, utils.LLMFlashModel, prompt)
 		if err != nil {
 			return &FileAnalysisResponse{codeAssessment, extractedMethodSignatures, projectPath, filepath, queryResults}
 		}
-		logger.Log.Debug("LLM Token Usage (Non-DAO Analysis): ",
-			zap.Int32("Prompt Tokens", response.UsageMetadata.PromptTokenCount),
-			zap.Int32("Candidate Tokens", response.UsageMetadata.CandidatesTokenCount),
-			zap.Int32("Total Tokens", response.UsageMetadata.TotalTokenCount))
-
-		if response.Candidates != nil && len(response.Candidates) > 0 && len(response.Candidates[0].Content.Parts) > 0 {
-			if part, ok := response.Candidates[0].Content.Parts[0].(genai.Text); ok {
-				llmResponse = string(part)
-			}
-		}
 
-		llmResponse = m.parseJSONWithRetries(m.geminiFlashModel, prompt, llmResponse, "analyze-non-dao-class-"+filepath)
+		llmResponse = m.parseJSONWithRetries(utils.LLMFlashModel, prompt, llmResponse, "analyze-non-dao-class-"+filepath)
 		isDataAccessObject = false
 
 		if llmResponse != "" {
@@ -486,6 +475,11 @@ func (m *MigrationCodeSummarizer) AnalyzeFile(ctx context.Context, projectPath,
 	if err != nil {
 		return &FileAnalysisResponse{emptyAssessment, extractedMethodSignatures, projectPath, filepath, queryResults}
 	}
+	if llmResponse != "" && !isCached {
+		if err := m.cache.Put(cacheKey, fileAnalysisCacheEntry{Response: llmResponse, IsDAO: isDataAccessObject}); err != nil {
+			logger.Log.Warn("Error caching file analysis: ", zap.String("filepath", filepath), zap.Error(err))
+		}
+	}
 
 	return &FileAnalysisResponse{codeAssessment, extractedMethodSignatures, projectPath, filepath, queryResults}
 }
@@ -556,6 +550,55 @@ func (m *MigrationCodeSummarizer) analyzeFileDependencies(filePath, fileContent
 	return false, ""
 }
 
+// estimatePromptTokens returns the estimated tokens of the prompts analyzing
+// the file filePath of content. DAO files take a prompt for clarifying
+// questions and the prompt of the analysis, both with the schemas.
+func (m *MigrationCodeSummarizer) estimatePromptTokens(filePath, content string) int {
+	if m.projectDependencyAnalyzer.IsDAO(filePath, content) {
+		schemas := utils.EstimateTokens(m.sourceDatabaseSchema) + utils.EstimateTokens(m.targetDatabaseSchema)
+		return utils.EstimateTokens(analyzeCodePromptTemplate) + utils.EstimateTokens(daoMigrationPromptTemplate) + 2*(utils.EstimateTokens(content)+schemas)
+	}
+	return utils.EstimateTokens(nonDAOMigrationPromptTemplate) + utils.EstimateTokens(content)
+}
+
+// filesWithinBudget returns the estimated prompt tokens of the files of the
+// project to analyze, all of them if analyzed is nil, and the files to skip so
+// that the prompts of the others fit in the budget, largest first. Estimates
+// count every file, including the ones which turn out not to depend on DAOs,
+// so they are upper bounds.
+func (m *MigrationCodeSummarizer) filesWithinBudget(processingOrder [][]string, analyzed map[string]bool) (int, map[string]bool) {
+	estimates := make(map[string]int)
+	var files []string
+	total := 0
+	for _, fileBatch := range processingOrder {
+		for _, filePath := range fileBatch {
+			if analyzed != nil && !analyzed[filePath] {
+				continue
+			}
+			content, err := m.fetchFileContent(filePath)
+			if err != nil {
+				continue
+			}
+			estimates[filePath] = m.estimatePromptTokens(filePath, content)
+			files = append(files, filePath)
+			total += estimates[filePath]
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return estimates[files[i]] > estimates[files[j]]
+	})
+	skipped := make(map[string]bool)
+	remaining := total
+	for _, filePath := range files {
+		if m.llm.Budget.FitsPrompts(remaining) {
+			break
+		}
+		skipped[filePath] = true
+		remaining -= estimates[filePath]
+	}
+	return total, skipped
+}
+
 // AnalyzeProject orchestrates the analysis of the entire project.
 // ToDo:Add Unit Tests
 func (m *MigrationCodeSummarizer) AnalyzeProject(ctx context.Context) (*utils.CodeAssessment, []utils.QueryTranslationResult, error) {
@@ -566,6 +609,20 @@ func (m *MigrationCodeSummarizer) AnalyzeProject(ctx context.Context) (*utils.Co
 
 	m.dependencyGraph = dependencyGraph
 
+	// In incremental analyses, only the changed files and their dependents
+	// are analyzed.
+	var analyzed, changed map[string]bool
+	if m.incremental != nil {
+		var err error
+		changed, err = gitChangedFiles(ctx, m.projectRootPath, m.incremental.GitDiffRange)
+		if err != nil {
+			return nil, nil, err
+		}
+		analyzed = affectedFiles(m.projectRootPath, dependencyGraph, changed)
+		logger.Log.Info(fmt.Sprintf("%d files changed in %s, analyzing %d files including their dependents",
+			len(changed), m.incremental.GitDiffRange, len(analyzed)))
+	}
+
 	var allSnippets []utils.Snippet
 	projectCodeAssessment := &utils.CodeAssessment{
 		ProjectPath:     m.projectRootPath,
@@ -579,8 +636,15 @@ func (m *MigrationCodeSummarizer) AnalyzeProject(ctx context.Context) (*utils.Co
 	projectProgrammingLanguage := m.projectProgrammingLanguage
 	detectedFramework := m.sourceDatabaseFramework
 
+	estimatedTokens, skippedFiles := m.filesWithinBudget(processingOrder, analyzed)
+	logger.Log.Info(fmt.Sprintf("estimated %d prompt tokens to analyze the project", estimatedTokens))
+	if len(skippedFiles) > 0 {
+		logger.Log.Warn(fmt.Sprintf("skipping the analysis of the %d largest files to stay within the LLM budget", len(skippedFiles)))
+	}
+
 	logger.Log.Info("initiating file scanning and analysis. this may take a few minutes.")
 	var allQueryResults []utils.QueryTranslationResult
+	projectFiles := make(map[string]bool)
 	for _, fileBatch := range processingOrder {
 		analysisInputs := make([]*FileAnalysisInput, 0, len(fileBatch))
 		for _, filePath := range fileBatch {
@@ -591,9 +655,18 @@ func (m *MigrationCodeSummarizer) AnalyzeProject(ctx context.Context) (*utils.Co
 				continue
 			}
 			totalLinesOfCode += strings.Count(fileContent, "\n")
+			projectFiles[relativeProjectPath(m.projectRootPath, filePath)] = true
 
 			isDependentOnDAO, methodChanges := m.analyzeFileDependencies(filePath, fileContent)
-			if !isDependentOnDAO {
+			if analyzed != nil && !analyzed[filePath] {
+				// The method signatures of the files which aren't analyzed are
+				// unknown, but their dependents still depend on DAOs.
+				if isDependentOnDAO {
+					m.fileDependencyAnalysis[filePath] = FileDependencyInfo{IsDAODependent: true}
+				}
+				continue
+			}
+			if !isDependentOnDAO || skippedFiles[filePath] {
 				continue
 			}
 			analysisInputs = append(analysisInputs, &FileAnalysisInput{
@@ -632,6 +705,50 @@ func (m *MigrationCodeSummarizer) AnalyzeProject(ctx context.Context) (*utils.Co
 		}
 	}
 
+	if m.incremental != nil && m.incremental.Previous != nil {
+		reanalyzed := make(map[string]bool)
+		for filePath := range changed {
+			reanalyzed[filePath] = true
+		}
+		for filePath := range analyzed {
+			reanalyzed[relativeProjectPath(m.projectRootPath, filePath)] = true
+		}
+		*projectCodeAssessment.Snippets, allQueryResults = mergeIncrementalAnalysis(m.incremental.Previous, reanalyzed, projectFiles, *projectCodeAssessment.Snippets, allQueryResults)
+		logger.Log.Info("merged the incremental analysis into the previous assessment")
+	}
+
+	if m.cache != nil {
+		hits, misses := m.cache.Stats()
+		logger.Log.Info(fmt.Sprintf("LLM analysis cache: %d hits, %d misses", hits, misses), zap.String("dir", m.cache.Dir))
+	}
+
+	usage, cost, rejected := m.llm.Usage()
+	projectCodeAssessment.LLMUsage = &utils.LLMUsageOutput{
+		Backend:               m.llm.Backend(),
+		EstimatedPromptTokens: estimatedTokens,
+		PromptTokens:          usage.PromptTokens,
+		ResponseTokens:        usage.ResponseTokens,
+		EstimatedCost:         cost,
+		TokenBudget:           m.llm.Budget.MaxTokens,
+		CostBudget:            m.llm.Budget.MaxCost,
+		RejectedPrompts:       rejected,
+	}
+	for filePath := range skippedFiles {
+		relativePath, err := filepath.Rel(m.projectRootPath, filePath)
+		if err != nil {
+			relativePath = filePath
+		}
+		projectCodeAssessment.LLMUsage.SkippedFiles = append(projectCodeAssessment.LLMUsage.SkippedFiles, relativePath)
+	}
+	sort.Strings(projectCodeAssessment.LLMUsage.SkippedFiles)
+	if len(skippedFiles) > 0 || rejected > 0 {
+		projectCodeAssessment.GeneralWarnings = append(projectCodeAssessment.GeneralWarnings, fmt.Sprintf(
+			"the LLM budget of the assessment was exceeded: %d files were not analyzed and %d prompts were not sent", len(skippedFiles), rejected))
+	}
+	if m.llm.Backend() == utils.LLMBackendNone {
+		projectCodeAssessment.GeneralWarnings = append(projectCodeAssessment.GeneralWarnings,
+			"the code changes were not analyzed as no LLM backend is configured, only the size, language and framework of the project are assessed")
+	}
 	projectCodeAssessment.Language = projectProgrammingLanguage
 	projectCodeAssessment.Framework = detectedFramework
 	projectCodeAssessment.TotalLoc = totalLinesOfCode
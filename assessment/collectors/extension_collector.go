@@ -0,0 +1,96 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package assessment
+
+import (
+	"database/sql"
+	"fmt"
+
+	collectorCommon "github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/collectors/common"
+	sourcesCommon "github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/sources"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/sources/postgres"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"go.uber.org/zap"
+)
+
+// ExtensionCollector collects the extensions installed on source databases
+type ExtensionCollector struct {
+	Extensions []utils.ExtensionAssessmentInfo
+}
+
+// IsEmpty checks if the collector has any data
+func (c ExtensionCollector) IsEmpty() bool {
+	return len(c.Extensions) == 0
+}
+
+// GetDefaultExtensionCollector creates a new ExtensionCollector with default settings
+func GetDefaultExtensionCollector(sourceProfile profiles.SourceProfile) (ExtensionCollector, error) {
+	return GetExtensionCollector(sourceProfile, collectorCommon.SQLDBConnector{}, collectorCommon.DefaultConnectionConfigProvider{}, DefaultExtensionSchemaProvider{})
+}
+
+// GetExtensionCollector creates a new ExtensionCollector with custom dependencies
+func GetExtensionCollector(sourceProfile profiles.SourceProfile, dbConnector collectorCommon.DBConnector, configProvider collectorCommon.ConnectionConfigProvider, extensionSchemaProvider ExtensionSchemaProvider) (ExtensionCollector, error) {
+	logger.Log.Info("initializing extension collector")
+
+	connectionConfig, err := configProvider.GetConnectionConfig(sourceProfile)
+	if err != nil {
+		return ExtensionCollector{}, fmt.Errorf("failed to get connection config: %w", err)
+	}
+
+	db, err := dbConnector.Connect(sourceProfile.Driver, connectionConfig)
+	if err != nil {
+		return ExtensionCollector{}, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	extensionSchema, err := extensionSchemaProvider.getExtensionSchema(db, sourceProfile)
+	if err != nil {
+		return ExtensionCollector{}, fmt.Errorf("failed to get extension schema: %w", err)
+	}
+
+	extensions, err := extensionSchema.GetExtensions()
+	if err != nil {
+		return ExtensionCollector{}, fmt.Errorf("failed to get extensions: %w", err)
+	}
+
+	logger.Log.Info("extension collector initialized successfully", zap.Int("extension_count", len(extensions)))
+
+	return ExtensionCollector{
+		Extensions: extensions,
+	}, nil
+}
+
+// ExtensionSchemaProvider interface for reading the installed extensions
+type ExtensionSchemaProvider interface {
+	getExtensionSchema(db *sql.DB, sourceProfile profiles.SourceProfile) (sourcesCommon.ExtensionSchema, error)
+}
+
+// DefaultExtensionSchemaProvider provides the extension schema of supported sources
+type DefaultExtensionSchemaProvider struct{}
+
+// getExtensionSchema creates an extension schema implementation based on the database driver
+func (d DefaultExtensionSchemaProvider) getExtensionSchema(db *sql.DB, sourceProfile profiles.SourceProfile) (sourcesCommon.ExtensionSchema, error) {
+	driver := sourceProfile.Driver
+	switch driver {
+	case constants.POSTGRES:
+		return postgres.ExtensionSchemaImpl{
+			Db:     db,
+			DbName: sourceProfile.Conn.Pg.Db,
+		}, nil
+	default:
+		return nil, fmt.Errorf("driver %s not supported for extension schema", driver)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assessment
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sourcesCommon "github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/sources"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/sources/postgres"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockExtensionSchema struct {
+	mock.Mock
+}
+
+func (m *MockExtensionSchema) GetExtensions() ([]utils.ExtensionAssessmentInfo, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]utils.ExtensionAssessmentInfo), args.Error(1)
+}
+
+type MockExtensionSchemaProvider struct {
+	mock.Mock
+}
+
+func (m *MockExtensionSchemaProvider) getExtensionSchema(db *sql.DB, sourceProfile profiles.SourceProfile) (sourcesCommon.ExtensionSchema, error) {
+	args := m.Called(db, sourceProfile)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(sourcesCommon.ExtensionSchema), args.Error(1)
+}
+
+func TestDefaultExtensionSchemaProvider_getExtensionSchema(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	provider := DefaultExtensionSchemaProvider{}
+	es, err := provider.getExtensionSchema(db, profiles.SourceProfile{
+		Driver: constants.POSTGRES,
+		Conn: profiles.SourceProfileConnection{
+			Pg: profiles.SourceProfileConnectionPostgreSQL{Db: "test_pg_db"},
+		},
+	})
+	assert.NoError(t, err)
+	pgES, ok := es.(postgres.ExtensionSchemaImpl)
+	assert.True(t, ok, "Expected postgres.ExtensionSchemaImpl type")
+	assert.Equal(t, "test_pg_db", pgES.DbName)
+
+	es, err = provider.getExtensionSchema(db, profiles.SourceProfile{Driver: constants.MYSQL})
+	assert.Nil(t, es)
+	assert.EqualError(t, err, "driver mysql not supported for extension schema")
+}
+
+func TestGetExtensionCollector(t *testing.T) {
+	dummyDb, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating dummy sqlmock DB: %v", err)
+	}
+	defer dummyDb.Close()
+
+	sourceProfile := profiles.SourceProfile{
+		Driver: constants.POSTGRES,
+		Conn: profiles.SourceProfileConnection{
+			Pg: profiles.SourceProfileConnectionPostgreSQL{Db: "test_db"},
+		},
+	}
+	extensions := []utils.ExtensionAssessmentInfo{{Name: "postgis", Version: "3.4.0", Columns: []string{"stores.location"}}}
+
+	mockCfgProvider := new(MockConnectionConfigProvider)
+	mockDbConnector := new(MockDBConnector)
+	mockProvider := new(MockExtensionSchemaProvider)
+	mockES := new(MockExtensionSchema)
+	mockCfgProvider.On("GetConnectionConfig", sourceProfile).Return("mock_conn_string", nil)
+	mockDbConnector.On("Connect", sourceProfile.Driver, "mock_conn_string").Return(dummyDb, nil)
+	mockProvider.On("getExtensionSchema", dummyDb, sourceProfile).Return(mockES, nil)
+	mockES.On("GetExtensions").Return(extensions, nil).Once()
+
+	collector, err := GetExtensionCollector(sourceProfile, mockDbConnector, mockCfgProvider, mockProvider)
+	assert.NoError(t, err)
+	assert.False(t, collector.IsEmpty())
+	assert.Equal(t, extensions, collector.Extensions)
+
+	mockES.On("GetExtensions").Return(nil, errors.New("permission denied")).Once()
+	collector, err = GetExtensionCollector(sourceProfile, mockDbConnector, mockCfgProvider, mockProvider)
+	assert.EqualError(t, err, "failed to get extensions: permission denied")
+	assert.True(t, collector.IsEmpty())
+}
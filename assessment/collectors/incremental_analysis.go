@@ -0,0 +1,159 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package assessment
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	utils "github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+)
+
+// IncrementalAnalysis restricts the code assessment to the files changed in a
+// git ref range, e.g. the commits of a pull request, and to the files which
+// depend on them.
+type IncrementalAnalysis struct {
+	// GitDiffRange is the range passed to git diff, e.g. origin/main...HEAD.
+	GitDiffRange string
+	// Previous is the assessment of the project the results are merged into,
+	// if any. Its results for the files which weren't analyzed are kept.
+	Previous *utils.AppCodeAssessmentOutput
+}
+
+// EnableIncrementalAnalysis makes AnalyzeProject only analyze the files of
+// incremental.
+func (m *MigrationCodeSummarizer) EnableIncrementalAnalysis(incremental IncrementalAnalysis) {
+	m.incremental = &incremental
+}
+
+// gitChangedFiles returns the paths, relative to projectPath, of the files of
+// projectPath changed in diffRange.
+func gitChangedFiles(ctx context.Context, projectPath, diffRange string) (map[string]bool, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", projectPath, "diff", "--name-only", "--relative", diffRange)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("could not list the files changed in %s: %w: %s", diffRange, err, strings.TrimSpace(stderr.String()))
+	}
+	changed := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			changed[filepath.Clean(filepath.FromSlash(line))] = true
+		}
+	}
+	return changed, scanner.Err()
+}
+
+// relativeProjectPath returns filePath relative to projectPath, in the form
+// reported by git.
+func relativeProjectPath(projectPath, filePath string) string {
+	relativePath, err := filepath.Rel(projectPath, filePath)
+	if err != nil {
+		return filepath.Clean(filePath)
+	}
+	return relativePath
+}
+
+// affectedFiles returns the files of dependencyGraph, which maps the files to
+// the files they depend on, which are changed or depend transitively on a
+// changed file.
+func affectedFiles(projectPath string, dependencyGraph map[string]map[string]struct{}, changed map[string]bool) map[string]bool {
+	dependents := make(map[string][]string)
+	for file, dependencies := range dependencyGraph {
+		for dependency := range dependencies {
+			dependents[dependency] = append(dependents[dependency], file)
+		}
+	}
+	affected := make(map[string]bool)
+	var queue []string
+	for file := range dependencyGraph {
+		if changed[relativeProjectPath(projectPath, file)] {
+			affected[file] = true
+			queue = append(queue, file)
+		}
+	}
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
+		for _, dependent := range dependents[file] {
+			if !affected[dependent] {
+				affected[dependent] = true
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	return affected
+}
+
+// snippetProjectPath returns the path relative to the project of the file of
+// snippet, independent of the directory the project was checked out in.
+func snippetProjectPath(snippet utils.Snippet) string {
+	return filepath.Clean(strings.TrimPrefix(filepath.FromSlash(snippet.RelativeFilePath), string(filepath.Separator)))
+}
+
+// mergeIncrementalAnalysis merges the snippets and queries of the analyzed
+// files into the ones of previous for the other files of the project.
+// Snippets of the previous assessment whose ids collide with new ones are
+// kept under their id, and the new ones are renamed.
+func mergeIncrementalAnalysis(previous *utils.AppCodeAssessmentOutput, reanalyzed, projectFiles map[string]bool, snippets []utils.Snippet, queries []utils.QueryTranslationResult) ([]utils.Snippet, []utils.QueryTranslationResult) {
+	var mergedSnippets []utils.Snippet
+	keptIds := make(map[string]bool)
+	if previous.CodeSnippets != nil {
+		for _, snippet := range *previous.CodeSnippets {
+			path := snippetProjectPath(snippet)
+			if reanalyzed[path] || !projectFiles[path] {
+				continue
+			}
+			mergedSnippets = append(mergedSnippets, snippet)
+			keptIds[snippet.Id] = true
+		}
+	}
+	var mergedQueries []utils.QueryTranslationResult
+	if previous.QueryTranslationResult != nil {
+		for _, query := range *previous.QueryTranslationResult {
+			if keptIds[query.SnippetId] {
+				mergedQueries = append(mergedQueries, query)
+			}
+		}
+	}
+
+	renamed := make(map[string]string)
+	for _, snippet := range snippets {
+		if keptIds[snippet.Id] {
+			id := snippet.Id
+			for i := 1; keptIds[id]; i++ {
+				id = fmt.Sprintf("%s_%d", snippet.Id, i)
+			}
+			renamed[snippet.Id] = id
+			snippet.Id = id
+		}
+		keptIds[snippet.Id] = true
+		mergedSnippets = append(mergedSnippets, snippet)
+	}
+	for _, query := range queries {
+		if id, ok := renamed[query.SnippetId]; ok {
+			query.SnippetId = id
+		}
+		mergedQueries = append(mergedQueries, query)
+	}
+	return mergedSnippets, mergedQueries
+}
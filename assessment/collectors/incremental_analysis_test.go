@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assessment
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitChangedFiles(t *testing.T) {
+	repo := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(out))
+	}
+	project := filepath.Join(repo, "app")
+	assert.NoError(t, os.MkdirAll(filepath.Join(project, "dao"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(project, "dao", "UserDao.java"), []byte("class UserDao {}"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(project, "Main.java"), []byte("class Main {}"), 0644))
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-qm", "base")
+	assert.NoError(t, os.WriteFile(filepath.Join(project, "dao", "UserDao.java"), []byte("class UserDao { int id; }"), 0644))
+	git("commit", "-qam", "change")
+
+	changed, err := gitChangedFiles(context.Background(), project, "HEAD~1..HEAD")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{filepath.Join("dao", "UserDao.java"): true}, changed)
+
+	_, err = gitChangedFiles(context.Background(), project, "unknown..HEAD")
+	assert.ErrorContains(t, err, "could not list the files changed in unknown..HEAD")
+}
+
+func TestAffectedFiles(t *testing.T) {
+	dependencyGraph := map[string]map[string]struct{}{
+		"/app/dao/UserDao.java":         {},
+		"/app/service/UserService.java": {"/app/dao/UserDao.java": {}},
+		"/app/web/UserController.java":  {"/app/service/UserService.java": {}},
+		"/app/dao/OrderDao.java":        {},
+	}
+	affected := affectedFiles("/app", dependencyGraph, map[string]bool{"dao/UserDao.java": true, "README.md": true})
+	assert.Equal(t, map[string]bool{
+		"/app/dao/UserDao.java":         true,
+		"/app/service/UserService.java": true,
+		"/app/web/UserController.java":  true,
+	}, affected)
+}
+
+func TestMergeIncrementalAnalysis(t *testing.T) {
+	previous := &utils.AppCodeAssessmentOutput{
+		CodeSnippets: &[]utils.Snippet{
+			{Id: "snippet_1_0", RelativeFilePath: "/dao/UserDao.java"},
+			{Id: "snippet_2_0", RelativeFilePath: "/dao/OrderDao.java"},
+			{Id: "snippet_3_0", RelativeFilePath: "/dao/DeletedDao.java"},
+		},
+		QueryTranslationResult: &[]utils.QueryTranslationResult{
+			{OriginalQuery: "SELECT * FROM users", SnippetId: "snippet_1_0"},
+			{OriginalQuery: "SELECT * FROM orders", SnippetId: "snippet_2_0"},
+		},
+	}
+	reanalyzed := map[string]bool{filepath.Join("dao", "UserDao.java"): true}
+	projectFiles := map[string]bool{filepath.Join("dao", "UserDao.java"): true, filepath.Join("dao", "OrderDao.java"): true}
+	snippets, queries := mergeIncrementalAnalysis(previous, reanalyzed, projectFiles,
+		[]utils.Snippet{{Id: "snippet_2_0", RelativeFilePath: "/dao/UserDao.java"}},
+		[]utils.QueryTranslationResult{{OriginalQuery: "SELECT id FROM users", SnippetId: "snippet_2_0"}})
+
+	assert.Equal(t, []utils.Snippet{
+		{Id: "snippet_2_0", RelativeFilePath: "/dao/OrderDao.java"},
+		{Id: "snippet_2_0_1", RelativeFilePath: "/dao/UserDao.java"},
+	}, snippets)
+	assert.Equal(t, []utils.QueryTranslationResult{
+		{OriginalQuery: "SELECT * FROM orders", SnippetId: "snippet_2_0"},
+		{OriginalQuery: "SELECT id FROM users", SnippetId: "snippet_2_0_1"},
+	}, queries)
+}
@@ -205,6 +205,18 @@ func (c InfoSchemaCollector) ListTables() (map[string]utils.SrcTableDetails, map
 	return srcTable, spTable
 }
 
+// ListTableSizes returns the estimated size of the source tables, by name.
+func (c InfoSchemaCollector) ListTableSizes() []utils.TableSize {
+	var sizes []utils.TableSize
+	for _, table := range c.tables {
+		sizes = append(sizes, utils.TableSize{Name: table.Name, RowCount: table.RowCount, SizeBytes: table.SizeBytes})
+	}
+	sort.Slice(sizes, func(i, j int) bool {
+		return sizes[i].Name < sizes[j].Name
+	})
+	return sizes
+}
+
 // TODO: move this method to assessment_engine
 func canInterleaveWithFK(tableSchemaMap map[string]ddl.CreateTable, childTableId string, parentTableId string, fk *ddl.Foreignkey) bool {
 	// Check if both tables exist
@@ -389,6 +401,7 @@ func (c InfoSchemaCollector) ListColumnDefinitions() (map[string]utils.SrcColumn
 				GeneratedColumn:        generatedColumn,
 				IsOnUpdateTimestampSet: isOnUpdateTimestampSet,
 				IsOnInsertTimestampSet: onInsertTimestampSet,
+				Collation:              column.Collation,
 			}
 		}
 	}
@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package assessment
+
+import (
+	"database/sql"
+	"fmt"
+
+	collectorCommon "github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/collectors/common"
+	sourcesCommon "github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/sources"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/sources/mysql"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"go.uber.org/zap"
+)
+
+// PerformanceSnapshotCollector collects the load counters of source databases
+type PerformanceSnapshotCollector struct {
+	Snapshot utils.PerformanceSnapshot
+}
+
+// IsEmpty checks if the collector has any data
+func (c PerformanceSnapshotCollector) IsEmpty() bool {
+	return c.Snapshot.UptimeSeconds == 0
+}
+
+// GetDefaultPerformanceSnapshotCollector creates a new PerformanceSnapshotCollector with default settings
+func GetDefaultPerformanceSnapshotCollector(sourceProfile profiles.SourceProfile) (PerformanceSnapshotCollector, error) {
+	return GetPerformanceSnapshotCollector(sourceProfile, collectorCommon.SQLDBConnector{}, collectorCommon.DefaultConnectionConfigProvider{}, DefaultPerformanceSnapshotSchemaProvider{})
+}
+
+// GetPerformanceSnapshotCollector creates a new PerformanceSnapshotCollector with custom dependencies
+func GetPerformanceSnapshotCollector(sourceProfile profiles.SourceProfile, dbConnector collectorCommon.DBConnector, configProvider collectorCommon.ConnectionConfigProvider, performanceSnapshotSchemaProvider PerformanceSnapshotSchemaProvider) (PerformanceSnapshotCollector, error) {
+	logger.Log.Info("initializing performance snapshot collector")
+
+	connectionConfig, err := configProvider.GetConnectionConfig(sourceProfile)
+	if err != nil {
+		return PerformanceSnapshotCollector{}, fmt.Errorf("failed to get connection config: %w", err)
+	}
+
+	db, err := dbConnector.Connect(sourceProfile.Driver, connectionConfig)
+	if err != nil {
+		return PerformanceSnapshotCollector{}, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	performanceSnapshotSchema, err := performanceSnapshotSchemaProvider.getPerformanceSnapshotSchema(db, sourceProfile)
+	if err != nil {
+		return PerformanceSnapshotCollector{}, fmt.Errorf("failed to get performance snapshot schema: %w", err)
+	}
+
+	snapshot, err := performanceSnapshotSchema.GetPerformanceSnapshot()
+	if err != nil {
+		return PerformanceSnapshotCollector{}, fmt.Errorf("failed to get performance snapshot: %w", err)
+	}
+
+	logger.Log.Info("performance snapshot collector initialized successfully",
+		zap.Int64("uptime_seconds", snapshot.UptimeSeconds), zap.Int64("queries", snapshot.Queries))
+
+	return PerformanceSnapshotCollector{
+		Snapshot: snapshot,
+	}, nil
+}
+
+// PerformanceSnapshotSchemaProvider interface for reading the load counters
+type PerformanceSnapshotSchemaProvider interface {
+	getPerformanceSnapshotSchema(db *sql.DB, sourceProfile profiles.SourceProfile) (sourcesCommon.PerformanceSnapshotSchema, error)
+}
+
+// DefaultPerformanceSnapshotSchemaProvider provides the performance snapshot schema of supported sources
+type DefaultPerformanceSnapshotSchemaProvider struct{}
+
+// getPerformanceSnapshotSchema creates a performance snapshot schema implementation based on the database driver
+func (d DefaultPerformanceSnapshotSchemaProvider) getPerformanceSnapshotSchema(db *sql.DB, sourceProfile profiles.SourceProfile) (sourcesCommon.PerformanceSnapshotSchema, error) {
+	driver := sourceProfile.Driver
+	switch driver {
+	case constants.MYSQL:
+		return mysql.PerformanceSnapshotImpl{
+			Db:     db,
+			DbName: sourceProfile.Conn.Mysql.Db,
+		}, nil
+	default:
+		return nil, fmt.Errorf("driver %s not supported for performance snapshot schema", driver)
+	}
+}
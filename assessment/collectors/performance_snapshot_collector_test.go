@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assessment
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sourcesCommon "github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/sources"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/sources/mysql"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPerformanceSnapshotSchema struct {
+	mock.Mock
+}
+
+func (m *MockPerformanceSnapshotSchema) GetPerformanceSnapshot() (utils.PerformanceSnapshot, error) {
+	args := m.Called()
+	return args.Get(0).(utils.PerformanceSnapshot), args.Error(1)
+}
+
+type MockPerformanceSnapshotSchemaProvider struct {
+	mock.Mock
+}
+
+func (m *MockPerformanceSnapshotSchemaProvider) getPerformanceSnapshotSchema(db *sql.DB, sourceProfile profiles.SourceProfile) (sourcesCommon.PerformanceSnapshotSchema, error) {
+	args := m.Called(db, sourceProfile)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(sourcesCommon.PerformanceSnapshotSchema), args.Error(1)
+}
+
+func TestDefaultPerformanceSnapshotSchemaProvider_getPerformanceSnapshotSchema(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	provider := DefaultPerformanceSnapshotSchemaProvider{}
+	pss, err := provider.getPerformanceSnapshotSchema(db, profiles.SourceProfile{
+		Driver: constants.MYSQL,
+		Conn: profiles.SourceProfileConnection{
+			Mysql: profiles.SourceProfileConnectionMySQL{Db: "test_mysql_db"},
+		},
+	})
+	assert.NoError(t, err)
+	mysqlPSS, ok := pss.(mysql.PerformanceSnapshotImpl)
+	assert.True(t, ok, "Expected mysql.PerformanceSnapshotImpl type")
+	assert.Equal(t, "test_mysql_db", mysqlPSS.DbName)
+
+	pss, err = provider.getPerformanceSnapshotSchema(db, profiles.SourceProfile{Driver: "unsupported_db"})
+	assert.Nil(t, pss)
+	assert.EqualError(t, err, "driver unsupported_db not supported for performance snapshot schema")
+}
+
+func TestGetPerformanceSnapshotCollector(t *testing.T) {
+	dummyDb, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating dummy sqlmock DB: %v", err)
+	}
+	defer dummyDb.Close()
+
+	sourceProfile := profiles.SourceProfile{
+		Driver: constants.MYSQL,
+		Conn: profiles.SourceProfileConnection{
+			Mysql: profiles.SourceProfileConnectionMySQL{Db: "test_db"},
+		},
+	}
+	snapshot := utils.PerformanceSnapshot{UptimeSeconds: 100, Queries: 1000, Reads: 800, Writes: 100}
+
+	mockCfgProvider := new(MockConnectionConfigProvider)
+	mockDbConnector := new(MockDBConnector)
+	mockProvider := new(MockPerformanceSnapshotSchemaProvider)
+	mockPSS := new(MockPerformanceSnapshotSchema)
+	mockCfgProvider.On("GetConnectionConfig", sourceProfile).Return("mock_conn_string", nil)
+	mockDbConnector.On("Connect", sourceProfile.Driver, "mock_conn_string").Return(dummyDb, nil)
+	mockProvider.On("getPerformanceSnapshotSchema", dummyDb, sourceProfile).Return(mockPSS, nil)
+	mockPSS.On("GetPerformanceSnapshot").Return(snapshot, nil).Once()
+
+	collector, err := GetPerformanceSnapshotCollector(sourceProfile, mockDbConnector, mockCfgProvider, mockProvider)
+	assert.NoError(t, err)
+	assert.False(t, collector.IsEmpty())
+	assert.Equal(t, snapshot, collector.Snapshot)
+
+	mockPSS.On("GetPerformanceSnapshot").Return(utils.PerformanceSnapshot{}, errors.New("access denied")).Once()
+	collector, err = GetPerformanceSnapshotCollector(sourceProfile, mockDbConnector, mockCfgProvider, mockProvider)
+	assert.EqualError(t, err, "failed to get performance snapshot: access denied")
+	assert.True(t, collector.IsEmpty())
+}
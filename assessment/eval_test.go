@@ -27,6 +27,7 @@ import (
 	"testing"
 
 	assessment "github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/collectors"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
 	"go.uber.org/zap"
 )
@@ -115,7 +116,7 @@ func TestAccuracy(t *testing.T) {
 		if strings.HasSuffix(tc.FilePath, "java") {
 			language = "java"
 		}
-		summarizer, err := assessment.NewMigrationCodeSummarizer(ctx, nil, projectID, location, tc.SourceSchema, tc.TargetSchema, tc.FilePath, language, "go-sql-mysql", "go-sql-spanner")
+		summarizer, err := assessment.NewMigrationCodeSummarizer(ctx, nil, nil, utils.LLMBudget{}, projectID, location, tc.SourceSchema, tc.TargetSchema, tc.FilePath, language, "go-sql-mysql", "go-sql-spanner")
 
 		if err != nil {
 			t.Fatal("Failed to initialize migration summarizer: ", err)
@@ -0,0 +1,144 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package assessment
+
+import (
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+)
+
+const (
+	extensionNoImpact = "No impact"
+	extensionUnknown  = "Unknown"
+)
+
+// pgExtension is how to migrate the usages of a PostgreSQL extension to
+// Spanner.
+type pgExtension struct {
+	support           string
+	spannerEquivalent string
+	guidance          string
+}
+
+// pgExtensions is the catalog of the extensions of the extension assessment,
+// by name.
+var pgExtensions = map[string]pgExtension{
+	"postgis": {
+		support:           featureUnsupported,
+		spannerEquivalent: "None",
+		guidance:          "Spanner has no geometry or geography types nor spatial functions: store the shapes as WKB in BYTES or GeoJSON in STRING, index points with S2 cell ids and run spatial predicates in the application or in BigQuery GIS through federated queries.",
+	},
+	"pgcrypto": {
+		support:           featureBehaviorChange,
+		spannerEquivalent: "GENERATE_UUID(), SHA256(), SHA512(), MD5()",
+		guidance:          "Replace gen_random_uuid() with GENERATE_UUID() and digest() with the hash functions of Spanner. crypt(), pgp_sym_encrypt() and the other encryption functions have no equivalent: encrypt in the application, Spanner encrypts data at rest, with customer-managed keys if required.",
+	},
+	"uuid-ossp": {
+		support:           featureBehaviorChange,
+		spannerEquivalent: "GENERATE_UUID()",
+		guidance:          "Replace uuid_generate_v4() with GENERATE_UUID() and store UUIDs in STRING(36) columns. Generate version 1, 3 and 5 UUIDs in the application; version 1 UUIDs, like other monotonic keys, cause hotspots in primary keys.",
+	},
+	"hstore": {
+		support:           featureUnsupported,
+		spannerEquivalent: "JSON",
+		guidance:          "Convert hstore columns to JSON columns and rewrite the ->, ? and @> operators with the JSON functions of Spanner.",
+	},
+	"vector": {
+		support:           featureBehaviorChange,
+		spannerEquivalent: "ARRAY<FLOAT32>, COSINE_DISTANCE(), EUCLIDEAN_DISTANCE(), DOT_PRODUCT()",
+		guidance:          "vector(n) columns are converted to ARRAY<FLOAT32>(vector_length=>n) columns. Rewrite the <=>, <-> and <#> operators with the distance functions of Spanner and replace HNSW or IVFFlat indexes with vector indexes, queried with APPROX_COSINE_DISTANCE() or APPROX_EUCLIDEAN_DISTANCE(): their recall depends on the options of the index and of the query rather than on ef_search or probes.",
+	},
+	"citext": {
+		support:           featureBehaviorChange,
+		spannerEquivalent: "STRING with LOWER()",
+		guidance:          "Spanner compares strings case-sensitively: store a lower case copy of the column in a generated column, index it and compare with LOWER() of the looked up value.",
+	},
+	"pg_trgm": {
+		support:           featureUnsupported,
+		spannerEquivalent: "Full-text search with TOKENIZE_NGRAMS() and SEARCH_NGRAMS()",
+		guidance:          "Replace similarity() and trigram indexes with ngram tokens in search indexes queried with SEARCH_NGRAMS() and SCORE_NGRAMS().",
+	},
+	"fuzzystrmatch": {
+		support:           featureUnsupported,
+		spannerEquivalent: "None",
+		guidance:          "Compute soundex(), metaphone() and levenshtein() in the application, or store their results in columns on write.",
+	},
+	"unaccent": {
+		support:           featureUnsupported,
+		spannerEquivalent: "None",
+		guidance:          "Store unaccented copies of the searched columns on write, or rely on the normalization of full-text search tokenizers.",
+	},
+	"ltree": {
+		support:           featureUnsupported,
+		spannerEquivalent: "STRING paths or interleaved tables",
+		guidance:          "Store the paths in STRING columns and query subtrees with STARTS_WITH(), or model the hierarchy with interleaved tables.",
+	},
+	"postgres_fdw": {
+		support:           featureUnsupported,
+		spannerEquivalent: "None",
+		guidance:          "Spanner can't query other databases: move the foreign tables into Spanner or join the data in BigQuery with federated queries.",
+	},
+	"dblink": {
+		support:           featureUnsupported,
+		spannerEquivalent: "None",
+		guidance:          "Spanner can't query other databases: move the queried tables into Spanner or join the data in BigQuery with federated queries.",
+	},
+	"pg_partman": {
+		support:           extensionNoImpact,
+		spannerEquivalent: "Automatic splits, TTL row deletion policies",
+		guidance:          "Spanner splits tables by key ranges automatically: drop the partition maintenance and replace partition drops of old data with row deletion policies.",
+	},
+	"pg_stat_statements": {
+		support:           extensionNoImpact,
+		spannerEquivalent: "SPANNER_SYS query statistics",
+		guidance:          "Monitor queries with the SPANNER_SYS.QUERY_STATS tables and Query insights.",
+	},
+}
+
+func performExtensionAssessment(collectors assessmentCollectors) *utils.ExtensionAssessmentOutput {
+	if collectors.extensionCollector == nil || collectors.extensionCollector.IsEmpty() {
+		logger.Log.Info("not proceeding with extension assessment as extension collector was not initialized")
+		return nil
+	}
+	logger.Log.Info("starting extension assessment...")
+	out := assessExtensions(collectors.extensionCollector.Extensions)
+	logger.Log.Info("extension assessment completed successfully.")
+	return out
+}
+
+// assessExtensions maps the extensions to the catalog. Extensions missing
+// from the catalog are reported for a manual review.
+func assessExtensions(extensions []utils.ExtensionAssessmentInfo) *utils.ExtensionAssessmentOutput {
+	out := &utils.ExtensionAssessmentOutput{}
+	for _, e := range extensions {
+		ext, ok := pgExtensions[e.Name]
+		if !ok {
+			ext = pgExtension{
+				support:           extensionUnknown,
+				spannerEquivalent: "None",
+				guidance:          "Spanner doesn't support extensions: review the functions, types and operators of the extension used by the schema and the application.",
+			}
+		}
+		out.Extensions = append(out.Extensions, utils.ExtensionAssessment{
+			Name:              e.Name,
+			Version:           e.Version,
+			Columns:           e.Columns,
+			Support:           ext.support,
+			SpannerEquivalent: ext.spannerEquivalent,
+			Guidance:          ext.guidance,
+		})
+	}
+	return out
+}
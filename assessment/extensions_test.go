@@ -0,0 +1,55 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assessment
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssessExtensions(t *testing.T) {
+	out := assessExtensions([]utils.ExtensionAssessmentInfo{
+		{Name: "postgis", Version: "3.4.0", Columns: []string{"stores.location"}},
+		{Name: "uuid-ossp", Version: "1.1"},
+		{Name: "my_extension", Version: "0.1"},
+	})
+	assert.Len(t, out.Extensions, 3)
+
+	assert.Equal(t, "postgis", out.Extensions[0].Name)
+	assert.Equal(t, featureUnsupported, out.Extensions[0].Support)
+	assert.Equal(t, []string{"stores.location"}, out.Extensions[0].Columns)
+
+	assert.Equal(t, featureBehaviorChange, out.Extensions[1].Support)
+	assert.Equal(t, "GENERATE_UUID()", out.Extensions[1].SpannerEquivalent)
+
+	// Extensions missing from the catalog are left for a manual review.
+	assert.Equal(t, extensionUnknown, out.Extensions[2].Support)
+	assert.NotEmpty(t, out.Extensions[2].Guidance)
+}
+
+func TestGenerateExtensionReport(t *testing.T) {
+	records := generateExtensionReport(&utils.ExtensionAssessmentOutput{Extensions: []utils.ExtensionAssessment{{
+		Name:              "hstore",
+		Version:           "1.8",
+		Columns:           []string{"products.attributes", "orders.tags"},
+		Support:           featureUnsupported,
+		SpannerEquivalent: "JSON",
+		Guidance:          "Convert hstore columns to JSON columns.",
+	}}})
+	assert.Len(t, records, 2)
+	assert.Equal(t, []string{"hstore", "1.8", featureUnsupported, "JSON", "products.attributes, orders.tags", "Convert hstore columns to JSON columns."}, records[1])
+}
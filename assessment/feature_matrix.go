@@ -0,0 +1,210 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package assessment
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+)
+
+const (
+	featureUnsupported    = "Unsupported"
+	featureBehaviorChange = "Behavior change"
+)
+
+// sqlFeature is a source SQL feature which Spanner doesn't support or
+// supports with a different behavior.
+type sqlFeature struct {
+	name     string
+	support  string
+	behavior string
+	pattern  *regexp.Regexp
+	// queryOnly features are only detected in queries, as their patterns
+	// would match unrelated code.
+	queryOnly bool
+	// matchQuery detects the feature in a query besides the pattern, if set.
+	matchQuery func(q utils.QueryTranslationResult) bool
+}
+
+var (
+	selectRe  = regexp.MustCompile(`(?i)^\s*\(?\s*SELECT\b`)
+	inListRe  = regexp.MustCompile(`(?i)\bIN\s*\(`)
+	groupByRe = regexp.MustCompile(`(?i)\bGROUP\s+BY\b`)
+	orderByRe = regexp.MustCompile(`(?i)\bORDER\s+BY\b`)
+)
+
+// sqlFeatures is the catalog of the features of the feature matrix.
+var sqlFeatures = []sqlFeature{
+	{
+		name:     "AUTO_INCREMENT",
+		support:  featureBehaviorChange,
+		behavior: "Spanner generates keys with sequences or IDENTITY columns, whose values are unique but not monotonic: don't order rows by them and read generated keys with THEN RETURN instead of LAST_INSERT_ID().",
+		pattern:  regexp.MustCompile(`(?i)\bAUTO_INCREMENT\b|\bLAST_INSERT_ID\s*\(|GenerationType\.IDENTITY\b|\buseGeneratedKeys\b|\bRETURN_GENERATED_KEYS\b`),
+	},
+	{
+		name:     "Zero dates",
+		support:  featureUnsupported,
+		behavior: "Spanner rejects the date 0000-00-00: store NULL or a sentinel date instead and update the queries comparing with zero dates.",
+		pattern:  regexp.MustCompile(`0000-00-00`),
+	},
+	{
+		name:      "Implicit ordering",
+		support:   featureBehaviorChange,
+		behavior:  "Spanner returns rows in no particular order without ORDER BY, e.g. in the order of an IN list, of the primary key or of GROUP BY: add an ORDER BY where the application depends on the order.",
+		queryOnly: true,
+		matchQuery: func(q utils.QueryTranslationResult) bool {
+			query := q.OriginalQuery
+			return selectRe.MatchString(query) && (inListRe.MatchString(query) || groupByRe.MatchString(query)) && !orderByRe.MatchString(query)
+		},
+	},
+	{
+		name:      "SELECT ... FOR UPDATE",
+		support:   featureBehaviorChange,
+		behavior:  "Spanner locks the rows read by read-write transactions, FOR UPDATE only takes exclusive locks early and is ignored in read-only transactions and partitioned DML.",
+		pattern:   regexp.MustCompile(`(?i)\bFOR\s+UPDATE\b`),
+		queryOnly: true,
+		matchQuery: func(q utils.QueryTranslationResult) bool {
+			return q.SelectForUpdate
+		},
+	},
+	{
+		name:      "Locking read options",
+		support:   featureUnsupported,
+		behavior:  "Spanner doesn't support FOR SHARE, LOCK IN SHARE MODE, NOWAIT and SKIP LOCKED: rely on the locks of read-write transactions and retry aborted transactions.",
+		pattern:   regexp.MustCompile(`(?i)\bFOR\s+SHARE\b|\bLOCK\s+IN\s+SHARE\s+MODE\b|\bNOWAIT\b|\bSKIP\s+LOCKED\b`),
+		queryOnly: true,
+	},
+	{
+		name:     "LOCK TABLES",
+		support:  featureUnsupported,
+		behavior: "Spanner has no table locks: use read-write transactions to serialize the updates.",
+		pattern:  regexp.MustCompile(`(?i)\bLOCK\s+TABLES?\b|\bUNLOCK\s+TABLES\b`),
+	},
+	{
+		name:     "INSERT ... ON DUPLICATE KEY UPDATE",
+		support:  featureUnsupported,
+		behavior: "Use INSERT OR UPDATE, which replaces the values of the inserted columns, or an insert_or_update mutation.",
+		pattern:  regexp.MustCompile(`(?i)\bON\s+DUPLICATE\s+KEY\s+UPDATE\b`),
+	},
+	{
+		name:     "INSERT IGNORE",
+		support:  featureUnsupported,
+		behavior: "Use INSERT OR IGNORE, which only ignores duplicate keys and still fails on other errors.",
+		pattern:  regexp.MustCompile(`(?i)\bINSERT\s+IGNORE\b`),
+	},
+	{
+		name:     "REPLACE INTO",
+		support:  featureUnsupported,
+		behavior: "Use a replace mutation, or INSERT OR UPDATE which keeps the values of the columns which aren't inserted.",
+		pattern:  regexp.MustCompile(`(?i)\bREPLACE\s+INTO\b`),
+	},
+	{
+		name:      "LIMIT offset, count",
+		support:   featureUnsupported,
+		behavior:  "Use LIMIT count OFFSET offset.",
+		pattern:   regexp.MustCompile(`(?i)\bLIMIT\s+(\d+|\?)\s*,\s*(\d+|\?)`),
+		queryOnly: true,
+	},
+	{
+		name:     "SQL_CALC_FOUND_ROWS",
+		support:  featureUnsupported,
+		behavior: "Run a separate SELECT COUNT(*) query with the same filters.",
+		pattern:  regexp.MustCompile(`(?i)\bSQL_CALC_FOUND_ROWS\b|\bFOUND_ROWS\s*\(`),
+	},
+	{
+		name:      "User variables",
+		support:   featureUnsupported,
+		behavior:  "Spanner has no session variables: use query parameters, or compute the values in the application.",
+		pattern:   regexp.MustCompile(`@\w+\s*:=`),
+		queryOnly: true,
+	},
+}
+
+func (f sqlFeature) matchesQuery(q utils.QueryTranslationResult) bool {
+	if f.matchQuery != nil && f.matchQuery(q) {
+		return true
+	}
+	return f.pattern != nil && f.pattern.MatchString(q.OriginalQuery)
+}
+
+func (f sqlFeature) matchesSnippet(s utils.Snippet) bool {
+	return !f.queryOnly && f.pattern != nil && f.pattern.MatchString(strings.Join(s.SourceCodeSnippet, "\n"))
+}
+
+func performFeatureMatrixAssessment(queries []utils.QueryTranslationResult, appCodeAssessment *utils.AppCodeAssessmentOutput) *utils.FeatureMatrixOutput {
+	logger.Log.Info("starting feature matrix assessment...")
+	var snippets []utils.Snippet
+	if appCodeAssessment != nil && appCodeAssessment.CodeSnippets != nil {
+		snippets = *appCodeAssessment.CodeSnippets
+	}
+	out := buildFeatureMatrix(queries, snippets)
+	logger.Log.Info("feature matrix assessment completed successfully.")
+	return out
+}
+
+// buildFeatureMatrix detects the features of the catalog in the queries and
+// in the code snippets found by the collectors. Queries are located in the
+// file of their snippet, and snippets holding a query are only matched
+// through the query. Occurrences are ordered by file and query.
+func buildFeatureMatrix(queries []utils.QueryTranslationResult, snippets []utils.Snippet) *utils.FeatureMatrixOutput {
+	files := make(map[string]string)
+	for _, s := range snippets {
+		files[s.Id] = s.RelativeFilePath
+	}
+	querySnippets := make(map[string]bool)
+	for _, q := range queries {
+		if q.SnippetId != "" {
+			querySnippets[q.SnippetId] = true
+		}
+	}
+	out := &utils.FeatureMatrixOutput{}
+	for _, f := range sqlFeatures {
+		usage := utils.FeatureUsage{Feature: f.name, Support: f.support, SpannerBehavior: f.behavior}
+		for _, q := range queries {
+			if f.matchesQuery(q) {
+				usage.Occurrences = append(usage.Occurrences, utils.FeatureOccurrence{
+					Source:    q.AssessmentSource,
+					FilePath:  files[q.SnippetId],
+					SnippetId: q.SnippetId,
+					Query:     q.OriginalQuery,
+				})
+			}
+		}
+		for _, s := range snippets {
+			if !querySnippets[s.Id] && f.matchesSnippet(s) {
+				usage.Occurrences = append(usage.Occurrences, utils.FeatureOccurrence{
+					Source:    "app_code",
+					FilePath:  s.RelativeFilePath,
+					SnippetId: s.Id,
+				})
+			}
+		}
+		sort.SliceStable(usage.Occurrences, func(i, j int) bool {
+			a, b := usage.Occurrences[i], usage.Occurrences[j]
+			if a.FilePath != b.FilePath {
+				return a.FilePath < b.FilePath
+			}
+			return a.Query < b.Query
+		})
+		if len(usage.Occurrences) > 0 {
+			out.Features = append(out.Features, usage)
+		}
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assessment
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildFeatureMatrix(t *testing.T) {
+	queries := []utils.QueryTranslationResult{
+		{OriginalQuery: "SELECT * FROM orders WHERE id IN (1, 2, 3)", AssessmentSource: "app_code", SnippetId: "s1"},
+		{OriginalQuery: "SELECT * FROM orders WHERE id = ? FOR UPDATE", AssessmentSource: "performance_schema", SelectForUpdate: true},
+		{OriginalQuery: "INSERT INTO orders (id, created) VALUES (1, '0000-00-00') ON DUPLICATE KEY UPDATE created = VALUES(created)", AssessmentSource: "app_code", SnippetId: "s2"},
+		{OriginalQuery: "SELECT id FROM orders ORDER BY id LIMIT 10, 20", AssessmentSource: "app_code, performance_schema", SnippetId: "s1"},
+	}
+	snippets := []utils.Snippet{
+		{Id: "s1", RelativeFilePath: "dao/orders.go", SourceCodeSnippet: []string{`db.Query("SELECT * FROM orders WHERE id IN (1, 2, 3)")`}},
+		{Id: "s2", RelativeFilePath: "dao/orders.go", SourceCodeSnippet: []string{`db.Exec("INSERT INTO orders ...")`}},
+		{Id: "s3", RelativeFilePath: "model/order.java", SourceCodeSnippet: []string{"@GeneratedValue(strategy = GenerationType.IDENTITY)", "private Long id;"}},
+		{Id: "s4", RelativeFilePath: "model/order.java", SourceCodeSnippet: []string{"// FOR UPDATE is only used in comments here"}},
+	}
+	out := buildFeatureMatrix(queries, snippets)
+
+	var features []string
+	for _, f := range out.Features {
+		features = append(features, f.Feature)
+	}
+	assert.Equal(t, []string{"AUTO_INCREMENT", "Zero dates", "Implicit ordering", "SELECT ... FOR UPDATE", "INSERT ... ON DUPLICATE KEY UPDATE", "LIMIT offset, count"}, features)
+	assert.Equal(t, []utils.FeatureOccurrence{{Source: "app_code", FilePath: "model/order.java", SnippetId: "s3"}}, out.Features[0].Occurrences)
+	assert.Equal(t, []utils.FeatureOccurrence{{Source: "app_code", FilePath: "dao/orders.go", SnippetId: "s2", Query: queries[2].OriginalQuery}}, out.Features[1].Occurrences)
+	assert.Equal(t, []utils.FeatureOccurrence{{Source: "app_code", FilePath: "dao/orders.go", SnippetId: "s1", Query: queries[0].OriginalQuery}}, out.Features[2].Occurrences)
+	// Queries only seen in the performance schema have no file.
+	assert.Equal(t, []utils.FeatureOccurrence{{Source: "performance_schema", Query: queries[1].OriginalQuery}}, out.Features[3].Occurrences)
+	assert.Equal(t, "dao/orders.go", out.Features[5].Occurrences[0].FilePath)
+
+	records := generateFeatureMatrixReport(out)
+	assert.Equal(t, []string{"Feature", "Support", "Spanner Behavior", "Source", "File", "Snippet Id", "Query"}, records[0])
+	assert.Len(t, records, 7)
+}
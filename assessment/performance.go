@@ -0,0 +1,76 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package assessment
+
+import (
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+)
+
+func performPerformanceAssessment(collectors assessmentCollectors) *utils.PerformanceAssessmentOutput {
+	if collectors.performanceSnapshotCollector == nil || collectors.performanceSnapshotCollector.IsEmpty() {
+		logger.Log.Info("not proceeding with performance assessment as performance snapshot collector was not initialized")
+		return nil
+	}
+	logger.Log.Info("starting performance assessment...")
+	out := assessPerformance(collectors.performanceSnapshotCollector.Snapshot)
+	logger.Log.Info("performance assessment completed successfully.")
+	return out
+}
+
+// assessPerformance averages the load counters of the snapshot over the
+// uptime of the source database.
+func assessPerformance(snapshot utils.PerformanceSnapshot) *utils.PerformanceAssessmentOutput {
+	out := &utils.PerformanceAssessmentOutput{
+		UptimeSeconds:   snapshot.UptimeSeconds,
+		BufferPoolBytes: snapshot.BufferPoolBytes,
+		WorkingSetBytes: snapshot.BufferPoolDataBytes,
+		P95LatencyMs:    latencyPercentile(snapshot.LatencyHistogram, 0.95),
+		P99LatencyMs:    latencyPercentile(snapshot.LatencyHistogram, 0.99),
+	}
+	if snapshot.UptimeSeconds > 0 {
+		uptime := float64(snapshot.UptimeSeconds)
+		out.QueriesPerSecond = float64(snapshot.Queries) / uptime
+		out.ReadsPerSecond = float64(snapshot.Reads) / uptime
+		out.WritesPerSecond = float64(snapshot.Writes) / uptime
+	}
+	if snapshot.Writes > 0 {
+		out.ReadWriteRatio = float64(snapshot.Reads) / float64(snapshot.Writes)
+	}
+	if snapshot.BufferPoolReadRequests > 0 {
+		out.BufferPoolHitRatio = 1 - float64(snapshot.BufferPoolDiskReads)/float64(snapshot.BufferPoolReadRequests)
+	}
+	return out
+}
+
+// latencyPercentile returns the upper bound of the histogram bucket holding
+// the percentile p of the statements, 0 for an empty histogram.
+func latencyPercentile(histogram []utils.LatencyBucket, p float64) float64 {
+	var total int64
+	for _, b := range histogram {
+		total += b.Count
+	}
+	if total == 0 {
+		return 0
+	}
+	var count int64
+	for _, b := range histogram {
+		count += b.Count
+		if float64(count) >= p*float64(total) {
+			return b.UpperBoundMs
+		}
+	}
+	return histogram[len(histogram)-1].UpperBoundMs
+}
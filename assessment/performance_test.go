@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assessment
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssessPerformance(t *testing.T) {
+	out := assessPerformance(utils.PerformanceSnapshot{
+		UptimeSeconds:          100,
+		Queries:                5000,
+		Reads:                  4000,
+		Writes:                 500,
+		LatencyHistogram:       []utils.LatencyBucket{{UpperBoundMs: 1, Count: 90}, {UpperBoundMs: 10, Count: 8}, {UpperBoundMs: 100, Count: 2}},
+		BufferPoolBytes:        1 << 30,
+		BufferPoolDataBytes:    1 << 20,
+		BufferPoolReadRequests: 1000,
+		BufferPoolDiskReads:    10,
+	})
+	assert.Equal(t, &utils.PerformanceAssessmentOutput{
+		UptimeSeconds:      100,
+		QueriesPerSecond:   50,
+		ReadsPerSecond:     40,
+		WritesPerSecond:    5,
+		ReadWriteRatio:     8,
+		P95LatencyMs:       10,
+		P99LatencyMs:       100,
+		BufferPoolBytes:    1 << 30,
+		WorkingSetBytes:    1 << 20,
+		BufferPoolHitRatio: 0.99,
+	}, out)
+
+	// Counters of a server which just started, without histogram.
+	assert.Equal(t, &utils.PerformanceAssessmentOutput{}, assessPerformance(utils.PerformanceSnapshot{}))
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	histogram := []utils.LatencyBucket{{UpperBoundMs: 0.5, Count: 50}, {UpperBoundMs: 2, Count: 45}, {UpperBoundMs: 20, Count: 5}}
+	assert.Equal(t, 0.5, latencyPercentile(histogram, 0.5))
+	assert.Equal(t, 2.0, latencyPercentile(histogram, 0.95))
+	assert.Equal(t, 20.0, latencyPercentile(histogram, 0.99))
+	assert.Equal(t, 0.0, latencyPercentile(nil, 0.99))
+}
+
+func TestGeneratePerformanceReport(t *testing.T) {
+	records := generatePerformanceReport(&utils.PerformanceAssessmentOutput{
+		UptimeSeconds:    100,
+		QueriesPerSecond: 50,
+		ReadsPerSecond:   40,
+		WritesPerSecond:  5,
+		ReadWriteRatio:   8,
+		P95LatencyMs:     10,
+		P99LatencyMs:     100,
+	})
+	assert.Equal(t, []string{"Metric", "Value"}, records[0])
+	assert.Contains(t, records, []string{"Queries per second", "50.00"})
+	assert.Contains(t, records, []string{"p95 statement latency (ms)", "10.00"})
+
+	// Latencies are left out when the source doesn't provide them.
+	records = generatePerformanceReport(&utils.PerformanceAssessmentOutput{UptimeSeconds: 100})
+	for _, r := range records {
+		assert.NotContains(t, r[0], "latency")
+	}
+}
@@ -94,6 +94,22 @@ func writeRawSnippets(assessmentsFolder string, snippets []utils.Snippet) {
 	logger.Log.Info("completed publishing raw snippets")
 }
 
+// writeAppCodeAssessment writes the app code assessment as JSON, to be merged
+// with later incremental assessments set by previousAssessment.
+func writeAppCodeAssessment(assessmentsFolder string, appAssessment *utils.AppCodeAssessmentOutput) {
+	file := assessmentsFolder + "app_code_assessment.json"
+	f, err := os.Create(file)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Can't create app code assessment file %s: %v", file, err))
+		return
+	}
+	defer f.Close()
+
+	jsonWriter := json.NewEncoder(f)
+	jsonWriter.Encode(appAssessment)
+	logger.Log.Info("completed publishing app code assessment: " + file)
+}
+
 func generateCodeSummary(appAssessment *utils.AppCodeAssessmentOutput) [][]string {
 	//Add codebase details
 	if appAssessment == nil {
@@ -216,6 +232,7 @@ func GenerateReport(dbName string, assessmentOutput utils.AssessmentOutput) {
 			writeRawSnippets(folderPath, *assessmentOutput.AppCodeAssessment.CodeSnippets)
 			logger.Log.Info("completed publishing code changes report")
 		}
+		writeAppCodeAssessment(folderPath, assessmentOutput.AppCodeAssessment)
 	} else {
 		logger.Log.Info("not performing application assessment as code is not detected")
 	}
@@ -230,9 +247,184 @@ func GenerateReport(dbName string, assessmentOutput utils.AssessmentOutput) {
 			logger.Log.Info("completed publishing query assessment report: " + queryFile)
 		}
 	}
+
+	if assessmentOutput.AccessControlAssessment != nil {
+		accessControlFile := folderPath + "access_control.csv"
+		dumpCsvReport(accessControlFile, generateAccessControlReport(assessmentOutput.AccessControlAssessment))
+		logger.Log.Info("completed publishing access control report: " + accessControlFile)
+		if len(assessmentOutput.AccessControlAssessment.FGACStatements) > 0 {
+			fgacFile := folderPath + "fgac_ddl.sql"
+			err := os.WriteFile(fgacFile, []byte(strings.Join(assessmentOutput.AccessControlAssessment.FGACStatements, ";\n")+";\n"), 0644)
+			if err != nil {
+				logger.Log.Error("failed to write fine-grained access control DDL", zap.Error(err))
+			} else {
+				logger.Log.Info("completed publishing fine-grained access control DDL: " + fgacFile)
+			}
+		}
+	}
+	if assessmentOutput.ExtensionAssessment != nil {
+		extensionFile := folderPath + "extensions.csv"
+		dumpCsvReport(extensionFile, generateExtensionReport(assessmentOutput.ExtensionAssessment))
+		logger.Log.Info("completed publishing extension report: " + extensionFile)
+	}
+	if assessmentOutput.PerformanceAssessment != nil {
+		performanceFile := folderPath + "performance_snapshot.csv"
+		dumpCsvReport(performanceFile, generatePerformanceReport(assessmentOutput.PerformanceAssessment))
+		logger.Log.Info("completed publishing performance snapshot report: " + performanceFile)
+	}
+	if assessmentOutput.CapacityAssessment != nil {
+		capacityFile := folderPath + "capacity.csv"
+		dumpCsvReport(capacityFile, generateCapacityReport(assessmentOutput.CapacityAssessment))
+		logger.Log.Info("completed publishing capacity report: " + capacityFile)
+	}
+	if assessmentOutput.FeatureMatrix != nil && len(assessmentOutput.FeatureMatrix.Features) > 0 {
+		featureMatrixFile := folderPath + "feature_matrix.csv"
+		dumpCsvReport(featureMatrixFile, generateFeatureMatrixReport(assessmentOutput.FeatureMatrix))
+		logger.Log.Info("completed publishing feature matrix report: " + featureMatrixFile)
+	}
+	if assessmentOutput.AppCodeAssessment != nil && assessmentOutput.AppCodeAssessment.LLMUsage != nil {
+		llmUsageFile := folderPath + "llm_usage.csv"
+		dumpCsvReport(llmUsageFile, generateLLMUsageReport(assessmentOutput.AppCodeAssessment.LLMUsage))
+		logger.Log.Info("completed publishing LLM usage report: " + llmUsageFile)
+	}
 	logger.Log.Info("assessment complete!")
 }
 
+func generateAccessControlReport(accessControl *utils.AccessControlAssessmentOutput) [][]string {
+	records := [][]string{{
+		"Principal",
+		"Host",
+		"Type",
+		"Source Roles",
+		"Source Privileges",
+		"Recommended IAM Role",
+		"Spanner Database Role",
+		"Notes",
+	}}
+	for _, p := range accessControl.Principals {
+		principalType := "User"
+		if p.IsRole {
+			principalType = "Role"
+		}
+		records = append(records, []string{
+			p.Name,
+			p.Host,
+			principalType,
+			strings.Join(p.SourceRoles, ", "),
+			strings.Join(p.SourcePrivileges, ", "),
+			p.RecommendedIAMRole,
+			p.SpannerRole,
+			strings.Join(p.Notes, "; "),
+		})
+	}
+	return records
+}
+
+func generateCapacityReport(capacity *utils.CapacityAssessmentOutput) [][]string {
+	records := [][]string{{
+		"Element",
+		"Estimated Rows",
+		"Estimated Size (bytes)",
+		"Processing Units",
+		"Recommendation",
+	}}
+	for _, t := range capacity.Tables {
+		records = append(records, []string{t.Name, strconv.FormatInt(t.RowCount, 10), strconv.FormatInt(t.SizeBytes, 10), "", ""})
+	}
+	records = append(records,
+		[]string{"Total", strconv.FormatInt(capacity.TotalRows, 10), strconv.FormatInt(capacity.TotalBytes, 10), "", ""},
+		[]string{"Bulk load", "", "", strconv.Itoa(int(capacity.BulkLoadProcessingUnits)), fmt.Sprintf(
+			"Compute capacity to load the data within %d hours, e.g. gcloud spanner instances create INSTANCE --config=CONFIG --description=INSTANCE --processing-units=%d. "+
+				"Scale down to the steady state capacity once the data migration is complete.", capacity.BulkLoadHours, capacity.BulkLoadProcessingUnits)},
+		[]string{"Steady state", "", "", strconv.Itoa(int(capacity.SteadyStateProcessingUnits)),
+			"Compute capacity to store the data with room for growth and serve the average load of the source. With autoscaling, use it as the minimum capacity and raise the maximum to follow the load of the application."},
+	)
+	if capacity.ThroughputProcessingUnits > 0 {
+		records = append(records, []string{"Throughput", "", "", strconv.Itoa(int(capacity.ThroughputProcessingUnits)),
+			"Compute capacity to serve the average reads and writes per second of the source. Size for the peaks of the load if they are much higher than the average."})
+	}
+	return records
+}
+
+func generateExtensionReport(extensions *utils.ExtensionAssessmentOutput) [][]string {
+	records := [][]string{{
+		"Extension",
+		"Version",
+		"Support",
+		"Spanner Equivalent",
+		"Columns",
+		"Guidance",
+	}}
+	for _, e := range extensions.Extensions {
+		records = append(records, []string{
+			e.Name,
+			e.Version,
+			e.Support,
+			e.SpannerEquivalent,
+			strings.Join(e.Columns, ", "),
+			e.Guidance,
+		})
+	}
+	return records
+}
+
+func generatePerformanceReport(performance *utils.PerformanceAssessmentOutput) [][]string {
+	formatFloat := func(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) }
+	records := [][]string{
+		{"Metric", "Value"},
+		{"Uptime (seconds)", strconv.FormatInt(performance.UptimeSeconds, 10)},
+		{"Queries per second", formatFloat(performance.QueriesPerSecond)},
+		{"Reads per second", formatFloat(performance.ReadsPerSecond)},
+		{"Writes per second", formatFloat(performance.WritesPerSecond)},
+		{"Read/write ratio", formatFloat(performance.ReadWriteRatio)},
+	}
+	if performance.P95LatencyMs > 0 {
+		records = append(records,
+			[]string{"p95 statement latency (ms)", formatFloat(performance.P95LatencyMs)},
+			[]string{"p99 statement latency (ms)", formatFloat(performance.P99LatencyMs)},
+		)
+	}
+	records = append(records,
+		[]string{"Buffer pool size (bytes)", strconv.FormatInt(performance.BufferPoolBytes, 10)},
+		[]string{"Working set (bytes)", strconv.FormatInt(performance.WorkingSetBytes, 10)},
+		[]string{"Buffer pool hit ratio", formatFloat(performance.BufferPoolHitRatio)},
+	)
+	return records
+}
+
+func generateFeatureMatrixReport(featureMatrix *utils.FeatureMatrixOutput) [][]string {
+	records := [][]string{{
+		"Feature",
+		"Support",
+		"Spanner Behavior",
+		"Source",
+		"File",
+		"Snippet Id",
+		"Query",
+	}}
+	for _, f := range featureMatrix.Features {
+		for _, o := range f.Occurrences {
+			records = append(records, []string{f.Feature, f.Support, f.SpannerBehavior, o.Source, o.FilePath, o.SnippetId, o.Query})
+		}
+	}
+	return records
+}
+
+func generateLLMUsageReport(usage *utils.LLMUsageOutput) [][]string {
+	return [][]string{
+		{"Metric", "Value"},
+		{"LLM Backend", usage.Backend},
+		{"Estimated Prompt Tokens", strconv.Itoa(usage.EstimatedPromptTokens)},
+		{"Prompt Tokens", strconv.Itoa(usage.PromptTokens)},
+		{"Response Tokens", strconv.Itoa(usage.ResponseTokens)},
+		{"Estimated Cost (USD)", strconv.FormatFloat(usage.EstimatedCost, 'f', 4, 64)},
+		{"Token Budget", strconv.Itoa(usage.TokenBudget)},
+		{"Cost Budget (USD)", strconv.FormatFloat(usage.CostBudget, 'f', 2, 64)},
+		{"Rejected Prompts", strconv.Itoa(usage.RejectedPrompts)},
+		{"Skipped Files", strings.Join(usage.SkippedFiles, ", ")},
+	}
+}
+
 func generateSchemaReport(assessmentOutput utils.AssessmentOutput) [][]string {
 	var records [][]string
 
@@ -583,6 +775,9 @@ func sourceColumnDefinitionToString(columnDefinition utils.SrcColumnDetails) str
 	if columnDefinition.IsUnsigned {
 		s += " UNSIGNED"
 	}
+	if columnDefinition.Collation.Name != "" {
+		s += " COLLATE " + columnDefinition.Collation.Name
+	}
 	if columnDefinition.GeneratedColumn.IsPresent {
 		s += " GENERATED ALWAYS AS " + columnDefinition.GeneratedColumn.Statement
 		if columnDefinition.GeneratedColumn.IsVirtual {
@@ -680,6 +875,17 @@ func calculateColumnDbChangesAndImpact(columnAssessment utils.ColumnAssessment)
 		actionItems = append(actionItems, "Update schema to add generated column")
 	}
 
+	// Spanner compares strings byte by byte, so comparisons on columns with
+	// case-insensitive collations become case-sensitive.
+	if srcCol != nil && srcCol.Collation.CaseInsensitive {
+		changesMap["collation"] = true
+		impact = append(impact, "case-sensitive comparisons")
+		if changeEffort == "Automatic" {
+			changeEffort = "Small"
+		}
+		actionItems = append(actionItems, "Update queries to compare LOWER() values, or look up the generated normalized column (see collationShadowColumns)")
+	}
+
 	//TODO add check for not null to null scenarios
 
 	changes := []string{}
@@ -150,6 +150,15 @@ func TestSourceColumnDefinitionToString(t *testing.T) {
 			},
 			want: "INT GENERATED ALWAYS AS (c * d) STORED",
 		},
+		{
+			name: "Collation",
+			input: utils.SrcColumnDetails{
+				Datatype:  "VARCHAR",
+				Mods:      []int64{100},
+				Collation: schema.Collation{Name: "utf8mb4_0900_ai_ci", CaseInsensitive: true},
+			},
+			want: "VARCHAR(100) COLLATE utf8mb4_0900_ai_ci",
+		},
 		{
 			name:  "Empty struct",
 			input: utils.SrcColumnDetails{},
@@ -281,6 +290,20 @@ func TestCalculateColumnDbChangesAndImpact(t *testing.T) {
 			wantEffort:      "Automatic",
 			wantActionItems: &[]string{},
 		},
+		{
+			name: "Case-insensitive collation",
+			input: utils.ColumnAssessment{
+				SourceColDef: &utils.SrcColumnDetails{
+					Collation: schema.Collation{Name: "utf8mb4_0900_ai_ci", CaseInsensitive: true},
+				},
+				SpannerColDef:      &utils.SpColumnDetails{},
+				CompatibleDataType: true,
+			},
+			wantChanges:     "collation",
+			wantImpact:      "case-sensitive comparisons",
+			wantEffort:      "Small",
+			wantActionItems: &[]string{"Update queries to compare LOWER() values, or look up the generated normalized column (see collationShadowColumns)"},
+		},
 		{
 			name:            "Zero-value struct",
 			input:           utils.ColumnAssessment{},
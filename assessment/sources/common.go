@@ -38,6 +38,20 @@ type PerformanceSchema interface {
 
 type PerformanceSchemaImpl struct{}
 
+// Users, roles and grants defined in the source database
+type AccessControlSchema interface {
+	GetUserInfo() ([]utils.UserAssessmentInfo, error)
+	GetGrantInfo() ([]utils.GrantAssessmentInfo, error)
+}
+
+type ExtensionSchema interface {
+	GetExtensions() ([]utils.ExtensionAssessmentInfo, error)
+}
+
+type PerformanceSnapshotSchema interface {
+	GetPerformanceSnapshot() (utils.PerformanceSnapshot, error)
+}
+
 type SourceSpecificComparison interface {
 	IsDataTypeCodeCompatible(srcColumnDef utils.SrcColumnDetails, spColumnDef utils.SpColumnDetails) bool
 }
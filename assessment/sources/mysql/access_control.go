@@ -0,0 +1,140 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+)
+
+// AccessControlSchemaImpl reads users, roles (MySQL 8) and grants of the
+// source database.
+type AccessControlSchemaImpl struct {
+	Db     *sql.DB
+	DbName string
+}
+
+// GetUserInfo returns all user accounts except MySQL's internal ones, along
+// with the roles granted to them.
+func (aci AccessControlSchemaImpl) GetUserInfo() ([]utils.UserAssessmentInfo, error) {
+	q := `SELECT u.User, u.Host,
+		EXISTS (SELECT 1 FROM mysql.role_edges r WHERE r.FROM_USER = u.User AND r.FROM_HOST = u.Host) AS IS_ROLE
+	FROM mysql.user u
+	WHERE u.User NOT IN ('mysql.sys', 'mysql.session', 'mysql.infoschema')
+	ORDER BY u.User, u.Host;`
+	rows, err := aci.Db.Query(q)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read users : %s", err)
+	}
+	defer rows.Close()
+	var name, host, errString string
+	var isRole bool
+	var users []utils.UserAssessmentInfo
+	for rows.Next() {
+		if err := rows.Scan(&name, &host, &isRole); err != nil {
+			errString = errString + fmt.Sprintf("Can't scan: %v", err)
+			continue
+		}
+		users = append(users, utils.UserAssessmentInfo{
+			Name:   name,
+			Host:   host,
+			IsRole: isRole,
+			Db: utils.DbIdentifier{
+				DatabaseName: aci.DbName,
+			},
+		})
+	}
+
+	q = `SELECT FROM_USER, TO_USER, TO_HOST FROM mysql.role_edges ORDER BY TO_USER, FROM_USER;`
+	edges, err := aci.Db.Query(q)
+	if err != nil {
+		return users, fmt.Errorf("couldn't read role grants : %s", err)
+	}
+	defer edges.Close()
+	var role string
+	for edges.Next() {
+		if err := edges.Scan(&role, &name, &host); err != nil {
+			errString = errString + fmt.Sprintf("Can't scan: %v", err)
+			continue
+		}
+		for i := range users {
+			if users[i].Name == name && users[i].Host == host {
+				users[i].Roles = append(users[i].Roles, role)
+			}
+		}
+	}
+	if errString != "" {
+		return users, fmt.Errorf("%s", errString)
+	}
+	return users, nil
+}
+
+// GetGrantInfo returns the global privileges of all accounts and the schema,
+// table and column privileges on the source database.
+func (aci AccessControlSchemaImpl) GetGrantInfo() ([]utils.GrantAssessmentInfo, error) {
+	q := `SELECT GRANTEE, 'GLOBAL', '', '', PRIVILEGE_TYPE, IS_GRANTABLE
+	FROM INFORMATION_SCHEMA.USER_PRIVILEGES WHERE PRIVILEGE_TYPE <> 'USAGE'
+	UNION ALL
+	SELECT GRANTEE, 'SCHEMA', '', '', PRIVILEGE_TYPE, IS_GRANTABLE
+	FROM INFORMATION_SCHEMA.SCHEMA_PRIVILEGES WHERE TABLE_SCHEMA = ?
+	UNION ALL
+	SELECT GRANTEE, 'TABLE', TABLE_NAME, '', PRIVILEGE_TYPE, IS_GRANTABLE
+	FROM INFORMATION_SCHEMA.TABLE_PRIVILEGES WHERE TABLE_SCHEMA = ?
+	UNION ALL
+	SELECT GRANTEE, 'COLUMN', TABLE_NAME, COLUMN_NAME, PRIVILEGE_TYPE, IS_GRANTABLE
+	FROM INFORMATION_SCHEMA.COLUMN_PRIVILEGES WHERE TABLE_SCHEMA = ?;`
+	rows, err := aci.Db.Query(q, aci.DbName, aci.DbName, aci.DbName)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read privileges : %s", err)
+	}
+	defer rows.Close()
+	var grantee, level, table, column, privilege, isGrantable, errString string
+	var grants []utils.GrantAssessmentInfo
+	for rows.Next() {
+		if err := rows.Scan(&grantee, &level, &table, &column, &privilege, &isGrantable); err != nil {
+			errString = errString + fmt.Sprintf("Can't scan: %v", err)
+			continue
+		}
+		name, host := splitGrantee(grantee)
+		grants = append(grants, utils.GrantAssessmentInfo{
+			Grantee:     name,
+			GranteeHost: host,
+			Privilege:   privilege,
+			Level:       level,
+			TableName:   table,
+			ColumnName:  column,
+			IsGrantable: isGrantable == "YES",
+			Db: utils.DbIdentifier{
+				DatabaseName: aci.DbName,
+			},
+		})
+	}
+	if errString != "" {
+		return grants, fmt.Errorf("%s", errString)
+	}
+	return grants, nil
+}
+
+// splitGrantee splits a grantee of the form 'user'@'host' into user and host.
+func splitGrantee(grantee string) (string, string) {
+	i := strings.LastIndex(grantee, "@")
+	if i == -1 {
+		return strings.Trim(grantee, "'"), ""
+	}
+	return strings.Trim(grantee[:i], "'"), strings.Trim(grantee[i+1:], "'")
+}
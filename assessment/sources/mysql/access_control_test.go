@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserInfo(t *testing.T) {
+	db := mkMockDB(t, []mockSpec{
+		{
+			query: "SELECT u.User, u.Host,",
+			cols:  []string{"User", "Host", "IS_ROLE"},
+			rows: [][]driver.Value{
+				{"app", "%", false},
+				{"reader", "%", true},
+			},
+		},
+		{
+			query: "SELECT FROM_USER, TO_USER, TO_HOST FROM mysql.role_edges",
+			cols:  []string{"FROM_USER", "TO_USER", "TO_HOST"},
+			rows:  [][]driver.Value{{"reader", "app", "%"}},
+		},
+	})
+	aci := AccessControlSchemaImpl{Db: db, DbName: "test_db"}
+	users, err := aci.GetUserInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, []utils.UserAssessmentInfo{
+		{Name: "app", Host: "%", Roles: []string{"reader"}, Db: utils.DbIdentifier{DatabaseName: "test_db"}},
+		{Name: "reader", Host: "%", IsRole: true, Db: utils.DbIdentifier{DatabaseName: "test_db"}},
+	}, users)
+}
+
+func TestGetUserInfo_QueryError(t *testing.T) {
+	db := mkMockDB(t, []mockSpec{
+		{query: "SELECT u.User, u.Host,", err: errors.New("access denied")},
+	})
+	aci := AccessControlSchemaImpl{Db: db, DbName: "test_db"}
+	users, err := aci.GetUserInfo()
+	assert.Nil(t, users)
+	assert.ErrorContains(t, err, "access denied")
+}
+
+func TestGetGrantInfo(t *testing.T) {
+	db := mkMockDB(t, []mockSpec{
+		{
+			query: "SELECT GRANTEE, 'GLOBAL', '', '', PRIVILEGE_TYPE, IS_GRANTABLE",
+			args:  []driver.Value{"test_db", "test_db", "test_db"},
+			cols:  []string{"GRANTEE", "LEVEL", "TABLE_NAME", "COLUMN_NAME", "PRIVILEGE_TYPE", "IS_GRANTABLE"},
+			rows: [][]driver.Value{
+				{"'admin'@'localhost'", "GLOBAL", "", "", "SUPER", "YES"},
+				{"'reader'@'%'", "TABLE", "orders", "", "SELECT", "NO"},
+				{"'app'@'10.0.0.1'", "COLUMN", "orders", "status", "UPDATE", "NO"},
+			},
+		},
+	})
+	aci := AccessControlSchemaImpl{Db: db, DbName: "test_db"}
+	grants, err := aci.GetGrantInfo()
+	assert.Nil(t, err)
+	dbId := utils.DbIdentifier{DatabaseName: "test_db"}
+	assert.Equal(t, []utils.GrantAssessmentInfo{
+		{Grantee: "admin", GranteeHost: "localhost", Privilege: "SUPER", Level: utils.GrantLevelGlobal, IsGrantable: true, Db: dbId},
+		{Grantee: "reader", GranteeHost: "%", Privilege: "SELECT", Level: utils.GrantLevelTable, TableName: "orders", Db: dbId},
+		{Grantee: "app", GranteeHost: "10.0.0.1", Privilege: "UPDATE", Level: utils.GrantLevelColumn, TableName: "orders", ColumnName: "status", Db: dbId},
+	}, grants)
+}
@@ -41,10 +41,12 @@ func (isi InfoSchemaImpl) GetTableInfo(conv *internal.Conv) (map[string]utils.Ta
 	for _, table := range conv.SrcSchema {
 		columnAssessments := make(map[string]utils.ColumnAssessmentInfo[any])
 		var collation, charset string
-		q := `SELECT TABLE_COLLATION, SUBSTRING_INDEX(TABLE_COLLATION, '_', 1) as CHARACTER_SET
+		var rowCount, sizeBytes int64
+		q := `SELECT TABLE_COLLATION, SUBSTRING_INDEX(TABLE_COLLATION, '_', 1) as CHARACTER_SET,
+		COALESCE(TABLE_ROWS, 0), COALESCE(DATA_LENGTH, 0) + COALESCE(INDEX_LENGTH, 0)
 		FROM INFORMATION_SCHEMA.TABLES
 		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?;`
-		err := isi.Db.QueryRow(q, isi.DbName, table.Name).Scan(&collation, &charset)
+		err := isi.Db.QueryRow(q, isi.DbName, table.Name).Scan(&collation, &charset, &rowCount, &sizeBytes)
 		if err != nil {
 			errString = errString + fmt.Sprintf("couldn't get schema for table %s: %s", table.Name, err)
 		}
@@ -88,7 +90,7 @@ func (isi InfoSchemaImpl) GetTableInfo(conv *internal.Conv) (map[string]utils.Ta
 				GeneratedColumn:        generatedColumn,
 			}
 		}
-		tb[table.Id] = utils.TableAssessmentInfo{Name: table.Name, TableDef: table, ColumnAssessmentInfos: columnAssessments, Db: dbIdentifier, Charset: charset, Collation: collation}
+		tb[table.Id] = utils.TableAssessmentInfo{Name: table.Name, TableDef: table, ColumnAssessmentInfos: columnAssessments, Db: dbIdentifier, Charset: charset, Collation: collation, RowCount: rowCount, SizeBytes: sizeBytes}
 	}
 	if errString != "" {
 		return tb, fmt.Errorf("%s", errString)
@@ -36,7 +36,7 @@ func newTestInfoSchemaImpl(t *testing.T) (InfoSchemaImpl, sqlmock.Sqlmock) {
 }
 
 func TestInfoSchemaImpl_GetTableInfo(t *testing.T) {
-	tableQueryRegex := `SELECT TABLE_COLLATION, SUBSTRING_INDEX\(TABLE_COLLATION, '_', 1\) as CHARACTER_SET FROM INFORMATION_SCHEMA\.TABLES WHERE TABLE_SCHEMA = \? AND TABLE_NAME = \?`
+	tableQueryRegex := `SELECT TABLE_COLLATION, SUBSTRING_INDEX\(TABLE_COLLATION, '_', 1\) as CHARACTER_SET,\s+COALESCE\(TABLE_ROWS, 0\), COALESCE\(DATA_LENGTH, 0\) \+ COALESCE\(INDEX_LENGTH, 0\)\s+FROM INFORMATION_SCHEMA\.TABLES WHERE TABLE_SCHEMA = \? AND TABLE_NAME = \?`
 	columnQueryRegex := `SELECT c\.column_type, c\.extra, c\.generation_expression\s+FROM information_schema\.COLUMNS c\s+where table_schema = \? and table_name = \? and column_name = \?\s+ORDER BY c\.ordinal_position;`
 
 	type testCase struct {
@@ -60,7 +60,7 @@ func TestInfoSchemaImpl_GetTableInfo(t *testing.T) {
 			mockTableSetup: func(mock sqlmock.Sqlmock, tableName string, dbName string) {
 				mock.ExpectQuery(tableQueryRegex).
 					WithArgs(dbName, tableName).
-					WillReturnRows(sqlmock.NewRows([]string{"TABLE_COLLATION", "CHARACTER_SET"}).AddRow("utf8mb4_general_ci", "utf8mb4"))
+					WillReturnRows(sqlmock.NewRows([]string{"TABLE_COLLATION", "CHARACTER_SET", "TABLE_ROWS", "SIZE"}).AddRow("utf8mb4_general_ci", "utf8mb4", 1000, 65536))
 			},
 			mockColumnSetup: func(mock sqlmock.Sqlmock, tableName, colName, dbName string) {
 				mock.ExpectQuery(columnQueryRegex).
@@ -73,6 +73,8 @@ func TestInfoSchemaImpl_GetTableInfo(t *testing.T) {
 				assert.True(t, ok)
 				assert.Equal(t, "table1", tableInfo.Name)
 				assert.Equal(t, "utf8mb4", tableInfo.Charset)
+				assert.Equal(t, int64(1000), tableInfo.RowCount)
+				assert.Equal(t, int64(65536), tableInfo.SizeBytes)
 				assert.Len(t, tableInfo.ColumnAssessmentInfos, 1)
 
 				colInfo, ok := tableInfo.ColumnAssessmentInfos[colID]
@@ -95,7 +97,7 @@ func TestInfoSchemaImpl_GetTableInfo(t *testing.T) {
 			mockTableSetup: func(mock sqlmock.Sqlmock, tableName string, dbName string) {
 				mock.ExpectQuery(tableQueryRegex).
 					WithArgs(dbName, tableName).
-					WillReturnRows(sqlmock.NewRows([]string{"TABLE_COLLATION", "CHARACTER_SET"}).AddRow("latin1_swedish_ci", "latin1"))
+					WillReturnRows(sqlmock.NewRows([]string{"TABLE_COLLATION", "CHARACTER_SET", "TABLE_ROWS", "SIZE"}).AddRow("latin1_swedish_ci", "latin1", 1000, 65536))
 			},
 			mockColumnSetup: func(mock sqlmock.Sqlmock, tableName, colName, dbName string) {
 				mock.ExpectQuery(columnQueryRegex).
@@ -125,7 +127,7 @@ func TestInfoSchemaImpl_GetTableInfo(t *testing.T) {
 			mockTableSetup: func(mock sqlmock.Sqlmock, tableName string, dbName string) {
 				mock.ExpectQuery(tableQueryRegex).
 					WithArgs(dbName, tableName).
-					WillReturnRows(sqlmock.NewRows([]string{"TABLE_COLLATION", "CHARACTER_SET"}).AddRow("utf8_general_ci", "utf8"))
+					WillReturnRows(sqlmock.NewRows([]string{"TABLE_COLLATION", "CHARACTER_SET", "TABLE_ROWS", "SIZE"}).AddRow("utf8_general_ci", "utf8", 1000, 65536))
 			},
 			mockColumnSetup: func(mock sqlmock.Sqlmock, tableName, colName, dbName string) {
 				mock.ExpectQuery(columnQueryRegex).
@@ -149,7 +151,7 @@ func TestInfoSchemaImpl_GetTableInfo(t *testing.T) {
 			mockTableSetup: func(mock sqlmock.Sqlmock, tableName string, dbName string) {
 				mock.ExpectQuery(tableQueryRegex).
 					WithArgs(dbName, tableName).
-					WillReturnRows(sqlmock.NewRows([]string{"TABLE_COLLATION", "CHARACTER_SET"}).AddRow("utf8mb4_bin", "utf8mb4"))
+					WillReturnRows(sqlmock.NewRows([]string{"TABLE_COLLATION", "CHARACTER_SET", "TABLE_ROWS", "SIZE"}).AddRow("utf8mb4_bin", "utf8mb4", 1000, 65536))
 			},
 			mockColumnSetup: func(mock sqlmock.Sqlmock, tableName, colName, dbName string) {
 				mock.ExpectQuery(columnQueryRegex).
@@ -208,7 +210,7 @@ func getFirstColumn(tbl schema.Table) (schema.Column, string) {
 }
 
 func TestInfoSchemaImpl_GetTableInfoErrorCases(t *testing.T) {
-	tableQueryRegex := `SELECT TABLE_COLLATION, SUBSTRING_INDEX\(TABLE_COLLATION, '_', 1\) as CHARACTER_SET FROM INFORMATION_SCHEMA\.TABLES WHERE TABLE_SCHEMA = \? AND TABLE_NAME = \?`
+	tableQueryRegex := `SELECT TABLE_COLLATION, SUBSTRING_INDEX\(TABLE_COLLATION, '_', 1\) as CHARACTER_SET,\s+COALESCE\(TABLE_ROWS, 0\), COALESCE\(DATA_LENGTH, 0\) \+ COALESCE\(INDEX_LENGTH, 0\)\s+FROM INFORMATION_SCHEMA\.TABLES WHERE TABLE_SCHEMA = \? AND TABLE_NAME = \?`
 	columnQueryRegex := `SELECT c\.column_type, c\.extra, c\.generation_expression\s+FROM information_schema\.COLUMNS c\s+where table_schema = \? and table_name = \? and column_name = \?\s+ORDER BY c\.ordinal_position;`
 
 	type testCase struct {
@@ -244,7 +246,7 @@ func TestInfoSchemaImpl_GetTableInfoErrorCases(t *testing.T) {
 			mockTableSetup: func(mock sqlmock.Sqlmock, tableName string, dbName string) {
 				mock.ExpectQuery(tableQueryRegex).
 					WithArgs(dbName, tableName).
-					WillReturnRows(sqlmock.NewRows([]string{"TABLE_COLLATION", "CHARACTER_SET"}).AddRow("utf8mb4_general_ci", "utf8mb4"))
+					WillReturnRows(sqlmock.NewRows([]string{"TABLE_COLLATION", "CHARACTER_SET", "TABLE_ROWS", "SIZE"}).AddRow("utf8mb4_general_ci", "utf8mb4", 1000, 65536))
 			},
 			mockColumnSetup: func(mock sqlmock.Sqlmock, tableName, colName, dbName string) {
 				mock.ExpectQuery(columnQueryRegex).
@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+)
+
+// PerformanceSnapshotImpl reads the load counters of the source database from
+// performance_schema.
+type PerformanceSnapshotImpl struct {
+	Db     *sql.DB
+	DbName string
+}
+
+// writeStatements are the status counters of the statements modifying data.
+var writeStatements = map[string]bool{
+	"Com_insert":         true,
+	"Com_insert_select":  true,
+	"Com_update":         true,
+	"Com_update_multi":   true,
+	"Com_delete":         true,
+	"Com_delete_multi":   true,
+	"Com_replace":        true,
+	"Com_replace_select": true,
+}
+
+// GetPerformanceSnapshot returns the counters of the server since its start:
+// statements, buffer pool usage and, from MySQL 8.0, statement latencies.
+func (psi PerformanceSnapshotImpl) GetPerformanceSnapshot() (utils.PerformanceSnapshot, error) {
+	q := `SELECT VARIABLE_NAME, VARIABLE_VALUE FROM performance_schema.global_status
+	WHERE VARIABLE_NAME IN ('Uptime', 'Questions', 'Com_select', 'Com_insert', 'Com_insert_select', 'Com_update', 'Com_update_multi',
+		'Com_delete', 'Com_delete_multi', 'Com_replace', 'Com_replace_select', 'Innodb_buffer_pool_pages_data', 'Innodb_page_size',
+		'Innodb_buffer_pool_read_requests', 'Innodb_buffer_pool_reads')
+	UNION ALL
+	SELECT VARIABLE_NAME, VARIABLE_VALUE FROM performance_schema.global_variables WHERE VARIABLE_NAME = 'innodb_buffer_pool_size';`
+	rows, err := psi.Db.Query(q)
+	if err != nil {
+		return utils.PerformanceSnapshot{}, fmt.Errorf("couldn't read global status : %s", err)
+	}
+	defer rows.Close()
+	var name, value, errString string
+	var snapshot utils.PerformanceSnapshot
+	var pagesData, pageSize int64
+	for rows.Next() {
+		if err := rows.Scan(&name, &value); err != nil {
+			errString = errString + fmt.Sprintf("Can't scan: %v", err)
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			errString = errString + fmt.Sprintf("Can't parse %s: %v", name, err)
+			continue
+		}
+		switch {
+		case name == "Uptime":
+			snapshot.UptimeSeconds = n
+		case name == "Questions":
+			snapshot.Queries = n
+		case name == "Com_select":
+			snapshot.Reads = n
+		case writeStatements[name]:
+			snapshot.Writes += n
+		case name == "Innodb_buffer_pool_pages_data":
+			pagesData = n
+		case name == "Innodb_page_size":
+			pageSize = n
+		case name == "Innodb_buffer_pool_read_requests":
+			snapshot.BufferPoolReadRequests = n
+		case name == "Innodb_buffer_pool_reads":
+			snapshot.BufferPoolDiskReads = n
+		case name == "innodb_buffer_pool_size":
+			snapshot.BufferPoolBytes = n
+		}
+	}
+	snapshot.BufferPoolDataBytes = pagesData * pageSize
+	if errString != "" {
+		return snapshot, fmt.Errorf("%s", errString)
+	}
+	// The statement histogram is only available from MySQL 8.0: leave the
+	// latencies out on older versions rather than failing.
+	q = `SELECT BUCKET_TIMER_HIGH, COUNT_BUCKET FROM performance_schema.events_statements_histogram_global
+	WHERE COUNT_BUCKET > 0 ORDER BY BUCKET_NUMBER;`
+	buckets, err := psi.Db.Query(q)
+	if err != nil {
+		return snapshot, nil
+	}
+	defer buckets.Close()
+	var timerHigh, count int64
+	for buckets.Next() {
+		if err := buckets.Scan(&timerHigh, &count); err != nil {
+			errString = errString + fmt.Sprintf("Can't scan: %v", err)
+			continue
+		}
+		// Timers are in picoseconds.
+		snapshot.LatencyHistogram = append(snapshot.LatencyHistogram, utils.LatencyBucket{UpperBoundMs: float64(timerHigh) / 1e9, Count: count})
+	}
+	if errString != "" {
+		return snapshot, fmt.Errorf("%s", errString)
+	}
+	return snapshot, nil
+}
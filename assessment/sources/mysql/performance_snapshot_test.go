@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPerformanceSnapshot(t *testing.T) {
+	db := mkMockDB(t, []mockSpec{
+		{
+			query: "SELECT VARIABLE_NAME, VARIABLE_VALUE FROM performance_schema.global_status",
+			cols:  []string{"VARIABLE_NAME", "VARIABLE_VALUE"},
+			rows: [][]driver.Value{
+				{"Uptime", "1000"},
+				{"Questions", "50000"},
+				{"Com_select", "30000"},
+				{"Com_insert", "4000"},
+				{"Com_update", "1000"},
+				{"Com_delete_multi", "10"},
+				{"Innodb_buffer_pool_pages_data", "100"},
+				{"Innodb_page_size", "16384"},
+				{"Innodb_buffer_pool_read_requests", "9000"},
+				{"Innodb_buffer_pool_reads", "90"},
+				{"innodb_buffer_pool_size", "134217728"},
+			},
+		},
+		{
+			query: "SELECT BUCKET_TIMER_HIGH, COUNT_BUCKET FROM performance_schema.events_statements_histogram_global",
+			cols:  []string{"BUCKET_TIMER_HIGH", "COUNT_BUCKET"},
+			rows:  [][]driver.Value{{int64(1000000000), int64(90)}, {int64(5000000000), int64(10)}},
+		},
+	})
+	psi := PerformanceSnapshotImpl{Db: db, DbName: "test_db"}
+	snapshot, err := psi.GetPerformanceSnapshot()
+	assert.Nil(t, err)
+	assert.Equal(t, utils.PerformanceSnapshot{
+		UptimeSeconds:          1000,
+		Queries:                50000,
+		Reads:                  30000,
+		Writes:                 5010,
+		LatencyHistogram:       []utils.LatencyBucket{{UpperBoundMs: 1, Count: 90}, {UpperBoundMs: 5, Count: 10}},
+		BufferPoolBytes:        134217728,
+		BufferPoolDataBytes:    1638400,
+		BufferPoolReadRequests: 9000,
+		BufferPoolDiskReads:    90,
+	}, snapshot)
+}
+
+func TestGetPerformanceSnapshot_NoHistogram(t *testing.T) {
+	db := mkMockDB(t, []mockSpec{
+		{
+			query: "SELECT VARIABLE_NAME, VARIABLE_VALUE FROM performance_schema.global_status",
+			cols:  []string{"VARIABLE_NAME", "VARIABLE_VALUE"},
+			rows:  [][]driver.Value{{"Uptime", "10"}, {"Questions", "20"}},
+		},
+		{
+			query: "SELECT BUCKET_TIMER_HIGH, COUNT_BUCKET FROM performance_schema.events_statements_histogram_global",
+			err:   errors.New("table doesn't exist"),
+		},
+	})
+	psi := PerformanceSnapshotImpl{Db: db, DbName: "test_db"}
+	snapshot, err := psi.GetPerformanceSnapshot()
+	assert.Nil(t, err)
+	assert.Equal(t, utils.PerformanceSnapshot{UptimeSeconds: 10, Queries: 20}, snapshot)
+}
+
+func TestGetPerformanceSnapshot_QueryError(t *testing.T) {
+	db := mkMockDB(t, []mockSpec{
+		{query: "SELECT VARIABLE_NAME, VARIABLE_VALUE FROM performance_schema.global_status", err: errors.New("access denied")},
+	})
+	psi := PerformanceSnapshotImpl{Db: db, DbName: "test_db"}
+	_, err := psi.GetPerformanceSnapshot()
+	assert.ErrorContains(t, err, "access denied")
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	_ "github.com/lib/pq" // we will use database/sql package instead of using this package directly
+)
+
+// ExtensionSchemaImpl reads the extensions installed on the source database.
+type ExtensionSchemaImpl struct {
+	Db     *sql.DB
+	DbName string
+}
+
+// GetExtensions returns the installed extensions, except plpgsql which is
+// always installed, along with the user columns of the types they define.
+func (esi ExtensionSchemaImpl) GetExtensions() ([]utils.ExtensionAssessmentInfo, error) {
+	q := `SELECT e.extname, e.extversion, n.nspname
+	FROM pg_extension e JOIN pg_namespace n ON n.oid = e.extnamespace
+	WHERE e.extname <> 'plpgsql'
+	ORDER BY e.extname;`
+	rows, err := esi.Db.Query(q)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read extensions : %s", err)
+	}
+	defer rows.Close()
+	var name, version, schema, errString string
+	var extensions []utils.ExtensionAssessmentInfo
+	for rows.Next() {
+		if err := rows.Scan(&name, &version, &schema); err != nil {
+			errString = errString + fmt.Sprintf("Can't scan: %v", err)
+			continue
+		}
+		extensions = append(extensions, utils.ExtensionAssessmentInfo{
+			Name:    name,
+			Version: version,
+			Schema:  schema,
+			Db: utils.DbIdentifier{
+				DatabaseName: esi.DbName,
+			},
+		})
+	}
+
+	// Types, and arrays of types, which are members of an extension.
+	q = `SELECT e.extname, tn.nspname, c.relname, a.attname
+	FROM pg_extension e
+	JOIN pg_depend d ON d.refclassid = 'pg_extension'::regclass AND d.refobjid = e.oid
+		AND d.classid = 'pg_type'::regclass AND d.deptype = 'e'
+	JOIN pg_type t ON t.oid = d.objid OR t.typelem = d.objid
+	JOIN pg_attribute a ON a.atttypid = t.oid AND a.attnum > 0 AND NOT a.attisdropped
+	JOIN pg_class c ON c.oid = a.attrelid AND c.relkind IN ('r', 'p')
+	JOIN pg_namespace tn ON tn.oid = c.relnamespace
+	WHERE tn.nspname NOT IN ('pg_catalog', 'information_schema')
+	ORDER BY e.extname, tn.nspname, c.relname, a.attnum;`
+	columns, err := esi.Db.Query(q)
+	if err != nil {
+		return extensions, fmt.Errorf("couldn't read extension columns : %s", err)
+	}
+	defer columns.Close()
+	var table, column string
+	for columns.Next() {
+		if err := columns.Scan(&name, &schema, &table, &column); err != nil {
+			errString = errString + fmt.Sprintf("Can't scan: %v", err)
+			continue
+		}
+		if schema != "public" {
+			table = schema + "." + table
+		}
+		for i := range extensions {
+			if extensions[i].Name == name {
+				extensions[i].Columns = append(extensions[i].Columns, table+"."+column)
+			}
+		}
+	}
+	if errString != "" {
+		return extensions, fmt.Errorf("%s", errString)
+	}
+	return extensions, nil
+}
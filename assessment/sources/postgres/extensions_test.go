@@ -0,0 +1,76 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	extensionsQuery       = "SELECT e.extname, e.extversion, n.nspname"
+	extensionColumnsQuery = "SELECT e.extname, tn.nspname, c.relname, a.attname"
+)
+
+func TestGetExtensions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	mock.ExpectQuery(regexp.QuoteMeta(extensionsQuery)).WillReturnRows(
+		sqlmock.NewRows([]string{"extname", "extversion", "nspname"}).
+			AddRow("hstore", "1.8", "public").
+			AddRow("postgis", "3.4.0", "public"))
+	mock.ExpectQuery(regexp.QuoteMeta(extensionColumnsQuery)).WillReturnRows(
+		sqlmock.NewRows([]string{"extname", "nspname", "relname", "attname"}).
+			AddRow("postgis", "public", "stores", "location").
+			AddRow("postgis", "geo", "regions", "area"))
+
+	esi := ExtensionSchemaImpl{Db: db, DbName: "test_db"}
+	extensions, err := esi.GetExtensions()
+	assert.Nil(t, err)
+	assert.Equal(t, []utils.ExtensionAssessmentInfo{
+		{Name: "hstore", Version: "1.8", Schema: "public", Db: utils.DbIdentifier{DatabaseName: "test_db"}},
+		{Name: "postgis", Version: "3.4.0", Schema: "public", Columns: []string{"stores.location", "geo.regions.area"}, Db: utils.DbIdentifier{DatabaseName: "test_db"}},
+	}, extensions)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExtensions_QueryError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	mock.ExpectQuery(regexp.QuoteMeta(extensionsQuery)).WillReturnError(errors.New("permission denied"))
+
+	esi := ExtensionSchemaImpl{Db: db, DbName: "test_db"}
+	extensions, err := esi.GetExtensions()
+	assert.Nil(t, extensions)
+	assert.ErrorContains(t, err, "permission denied")
+}
+
+func TestGetExtensions_ColumnsError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	mock.ExpectQuery(regexp.QuoteMeta(extensionsQuery)).WillReturnRows(
+		sqlmock.NewRows([]string{"extname", "extversion", "nspname"}).AddRow("pgcrypto", "1.3", "public"))
+	mock.ExpectQuery(regexp.QuoteMeta(extensionColumnsQuery)).WillReturnError(errors.New("permission denied"))
+
+	esi := ExtensionSchemaImpl{Db: db, DbName: "test_db"}
+	extensions, err := esi.GetExtensions()
+	assert.Len(t, extensions, 1)
+	assert.ErrorContains(t, err, "permission denied")
+}
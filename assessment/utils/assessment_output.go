@@ -22,11 +22,15 @@ import (
 )
 
 type AssessmentOutput struct {
-	CostAssessment        CostAssessmentOutput
-	SchemaAssessment      *SchemaAssessmentOutput
-	AppCodeAssessment     *AppCodeAssessmentOutput
-	QueryAssessment       QueryAssessmentOutput
-	PerformanceAssessment PerformanceAssessmentOutput
+	CostAssessment          CostAssessmentOutput
+	SchemaAssessment        *SchemaAssessmentOutput
+	AppCodeAssessment       *AppCodeAssessmentOutput
+	QueryAssessment         QueryAssessmentOutput
+	PerformanceAssessment   *PerformanceAssessmentOutput
+	AccessControlAssessment *AccessControlAssessmentOutput
+	CapacityAssessment      *CapacityAssessmentOutput
+	FeatureMatrix           *FeatureMatrixOutput
+	ExtensionAssessment     *ExtensionAssessmentOutput
 }
 
 type CostAssessmentOutput struct {
@@ -158,6 +162,7 @@ type SrcColumnDetails struct {
 	IsOnInsertTimestampSet bool
 	IsUnsigned             bool
 	MaxColumnSize          int64
+	Collation              schema.Collation
 }
 
 type SpColumnDetails struct {
@@ -182,14 +187,133 @@ type AppCodeAssessmentOutput struct {
 	TotalFiles             int
 	CodeSnippets           *[]Snippet // Affected code snippets
 	QueryTranslationResult *[]QueryTranslationResult
+	LLMUsage               *LLMUsageOutput
+}
+
+// LLMUsageOutput is the usage of the LLM by the code assessment.
+type LLMUsageOutput struct {
+	Backend               string
+	EstimatedPromptTokens int // Pre-flight estimate of the prompt tokens of the files to analyze
+	PromptTokens          int
+	ResponseTokens        int
+	EstimatedCost         float64  // USD, from the prices of the models
+	TokenBudget           int      // 0 if unlimited
+	CostBudget            float64  // USD, 0 if unlimited
+	SkippedFiles          []string // Largest files left out of the analysis to stay within budget
+	RejectedPrompts       int      // Prompts not sent as the budget was exhausted
 }
 
 type QueryAssessmentOutput struct {
 	QueryTranslationResult *[]QueryTranslationResult
 }
 
+// PerformanceAssessmentOutput is the baseline load of the source database,
+// averaged since its start, the Spanner compute capacity is sized for.
 type PerformanceAssessmentOutput struct {
-	//TBD
+	UptimeSeconds      int64 // Period the averages are computed over
+	QueriesPerSecond   float64
+	ReadsPerSecond     float64
+	WritesPerSecond    float64
+	ReadWriteRatio     float64 // Reads per write, 0 without writes
+	P95LatencyMs       float64 // Statement latency percentiles, 0 if the latencies are not available
+	P99LatencyMs       float64
+	BufferPoolBytes    int64   // Configured size of the buffer pool
+	WorkingSetBytes    int64   // Data cached in the buffer pool
+	BufferPoolHitRatio float64 // Share of the page reads served by the buffer pool
+}
+
+// PerformanceSnapshot holds the load counters of the source database,
+// accumulated since its start.
+type PerformanceSnapshot struct {
+	UptimeSeconds          int64
+	Queries                int64           // Statements sent by the clients
+	Reads                  int64           // SELECT statements
+	Writes                 int64           // INSERT, UPDATE, DELETE and REPLACE statements
+	LatencyHistogram       []LatencyBucket // Statement latencies by increasing bound, empty if not available, e.g. before MySQL 8.0
+	BufferPoolBytes        int64
+	BufferPoolDataBytes    int64
+	BufferPoolReadRequests int64 // Logical page reads
+	BufferPoolDiskReads    int64 // Page reads not served by the buffer pool
+}
+
+// LatencyBucket is the number of statements run within a latency bound.
+type LatencyBucket struct {
+	UpperBoundMs float64
+	Count        int64
+}
+
+type AccessControlAssessmentOutput struct {
+	Principals     []PrincipalAssessment // Entry per source user and role
+	FGACStatements []string              // Spanner CREATE ROLE and GRANT statements, populated only when requested
+}
+
+// CapacityAssessmentOutput recommends the compute capacity of the Spanner
+// instance, estimated from the size of the source tables.
+type CapacityAssessmentOutput struct {
+	Tables                     []TableSize // Entry per source table, by name
+	TotalRows                  int64
+	TotalBytes                 int64
+	BulkLoadHours              int   // Duration of the bulk load the capacity is sized for
+	BulkLoadProcessingUnits    int32 // Compute capacity during the bulk load
+	SteadyStateProcessingUnits int32 // Compute capacity once the data is loaded
+	ThroughputProcessingUnits  int32 // Compute capacity to serve the reads and writes of the source, 0 without performance snapshot
+}
+
+// ExtensionAssessmentOutput maps the extensions installed on the source
+// database to their Spanner equivalents or gaps.
+type ExtensionAssessmentOutput struct {
+	Extensions []ExtensionAssessment // Entry per installed extension, by name
+}
+
+// ExtensionAssessment is an extension of the source database and how to
+// migrate its usages.
+type ExtensionAssessment struct {
+	Name              string
+	Version           string
+	Columns           []string // Columns whose type is defined by the extension
+	Support           string   // "Supported", "Behavior change", "Unsupported", "No impact" or "Unknown"
+	SpannerEquivalent string
+	Guidance          string
+}
+
+// FeatureMatrixOutput lists the source SQL features used by the application
+// which Spanner doesn't support or supports with a different behavior.
+type FeatureMatrixOutput struct {
+	Features []FeatureUsage // Entry per detected feature, in the order of the feature catalog
+}
+
+// FeatureUsage is a source SQL feature and the places it is used.
+type FeatureUsage struct {
+	Feature         string
+	Support         string // "Unsupported" or "Behavior change"
+	SpannerBehavior string // How Spanner differs and how to migrate the usages
+	Occurrences     []FeatureOccurrence
+}
+
+// FeatureOccurrence is a query or code snippet using a feature.
+type FeatureOccurrence struct {
+	Source    string // "app_code" or "performance_schema" or "app_code, performance_schema"
+	FilePath  string // Relative path of the file, empty for queries only seen in the performance schema
+	SnippetId string
+	Query     string // Query using the feature, empty for code snippets
+}
+
+// TableSize is the size of a source table, estimated from its statistics.
+type TableSize struct {
+	Name      string
+	RowCount  int64
+	SizeBytes int64
+}
+
+type PrincipalAssessment struct {
+	Name               string
+	Host               string
+	IsRole             bool
+	SourceRoles        []string // Roles granted to the principal at source
+	SourcePrivileges   []string // Privileges granted at source, e.g. "SELECT ON orders"
+	RecommendedIAMRole string   // IAM role to grant on the Spanner database
+	SpannerRole        string   // Spanner database role (fine-grained access control) mapped to the principal, if any
+	Notes              []string
 }
 
 type QueryTranslationResult struct {
@@ -35,6 +35,8 @@ type TableAssessmentInfo struct {
 	Charset               string
 	Collation             string
 	ColumnAssessmentInfos map[string]ColumnAssessmentInfo[any]
+	RowCount              int64 // Estimated number of rows, from the statistics of the source.
+	SizeBytes             int64 // Estimated size of the data and indexes, from the statistics of the source.
 }
 
 // Information relevant to assessment of columns
@@ -118,6 +120,44 @@ type QueryAssessmentInfo struct {
 	Count          int
 }
 
+// Privilege levels of a source grant.
+const (
+	GrantLevelGlobal = "GLOBAL"
+	GrantLevelSchema = "SCHEMA"
+	GrantLevelTable  = "TABLE"
+	GrantLevelColumn = "COLUMN"
+)
+
+// Information relevant to assessment of users and roles
+type UserAssessmentInfo struct {
+	Db     DbIdentifier
+	Name   string
+	Host   string
+	IsRole bool     // Whether the account is granted to other accounts as a role.
+	Roles  []string // Names of the roles granted to the account.
+}
+
+// Information relevant to assessment of privileges granted to users and roles
+type GrantAssessmentInfo struct {
+	Db          DbIdentifier
+	Grantee     string // Name of the user or role the privilege is granted to.
+	GranteeHost string
+	Privilege   string // Privilege type, e.g. SELECT, INSERT or CREATE USER.
+	Level       string // One of GLOBAL, SCHEMA, TABLE or COLUMN.
+	TableName   string // Set for TABLE and COLUMN level grants.
+	ColumnName  string // Set for COLUMN level grants.
+	IsGrantable bool
+}
+
+// Information relevant to assessment of the extensions installed on the source database
+type ExtensionAssessmentInfo struct {
+	Db      DbIdentifier
+	Name    string
+	Version string
+	Schema  string   // Schema the objects of the extension are created in.
+	Columns []string // Columns whose type is defined by the extension, as table.column.
+}
+
 type Snippet struct {
 	Id                       string // generated id
 	TableName                string // will be empty if snippet is not a schema update
@@ -144,4 +184,5 @@ type CodeAssessment struct {
 	TotalFiles      int
 	Snippets        *[]Snippet
 	GeneralWarnings []string
+	LLMUsage        *LLMUsageOutput
 }
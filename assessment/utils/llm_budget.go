@@ -0,0 +1,228 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// charsPerToken is the average number of characters of a token, used to
+// estimate the tokens of prompts before sending them.
+const charsPerToken = 4
+
+// ErrLLMBudgetExceeded is returned for the prompts which would exceed the
+// token or cost budget of the assessment.
+var ErrLLMBudgetExceeded = errors.New("the LLM budget of the assessment is exhausted")
+
+// LLMUsage is the number of tokens of prompts and of their responses.
+type LLMUsage struct {
+	PromptTokens   int
+	ResponseTokens int
+}
+
+func (u LLMUsage) total() int {
+	return u.PromptTokens + u.ResponseTokens
+}
+
+func (u *LLMUsage) add(o LLMUsage) {
+	u.PromptTokens += o.PromptTokens
+	u.ResponseTokens += o.ResponseTokens
+}
+
+// LLMPrice is the price in USD of a million tokens of a model.
+type LLMPrice struct {
+	PromptPerMillion   float64
+	ResponsePerMillion float64
+}
+
+// defaultLLMPrices are the list prices of the Vertex AI models of the
+// assessment. Prices of the other backends are set in the assessment profile.
+var defaultLLMPrices = map[string]map[LLMModelTier]LLMPrice{
+	LLMBackendVertexAI: {
+		LLMProModel:   {PromptPerMillion: 1.25, ResponsePerMillion: 10},
+		LLMFlashModel: {PromptPerMillion: 0.10, ResponsePerMillion: 0.40},
+	},
+}
+
+// LLMBudget limits the tokens and the cost of the prompts of an assessment.
+// Zero limits are unlimited.
+type LLMBudget struct {
+	MaxTokens int
+	MaxCost   float64 // USD
+	Prices    map[LLMModelTier]LLMPrice
+}
+
+// EstimateTokens returns the estimated number of tokens of text.
+func EstimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// NewLLMBudget returns the budget set in the assessment config by
+// llmTokenBudget and llmCostBudget, with the prices of the backend or the
+// ones set by llmPromptPricePerMillion and llmResponsePricePerMillion.
+func NewLLMBudget(assessmentConfig map[string]string, backend string) (LLMBudget, error) {
+	budget := LLMBudget{Prices: map[LLMModelTier]LLMPrice{}}
+	for tier, price := range defaultLLMPrices[backend] {
+		budget.Prices[tier] = price
+	}
+	if v, ok := assessmentConfig["llmTokenBudget"]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return budget, fmt.Errorf("invalid llmTokenBudget %s, it must be a number of tokens", v)
+		}
+		budget.MaxTokens = n
+	}
+	parseFloat := func(key string) (float64, bool, error) {
+		v, ok := assessmentConfig[key]
+		if !ok {
+			return 0, false, nil
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 {
+			return 0, false, fmt.Errorf("invalid %s %s, it must be an amount in USD", key, v)
+		}
+		return f, true, nil
+	}
+	maxCost, ok, err := parseFloat("llmCostBudget")
+	if err != nil {
+		return budget, err
+	}
+	if ok {
+		budget.MaxCost = maxCost
+	}
+	promptPrice, promptSet, err := parseFloat("llmPromptPricePerMillion")
+	if err != nil {
+		return budget, err
+	}
+	responsePrice, responseSet, err := parseFloat("llmResponsePricePerMillion")
+	if err != nil {
+		return budget, err
+	}
+	for _, tier := range []LLMModelTier{LLMProModel, LLMFlashModel} {
+		price := budget.Prices[tier]
+		if promptSet {
+			price.PromptPerMillion = promptPrice
+		}
+		if responseSet {
+			price.ResponsePerMillion = responsePrice
+		}
+		budget.Prices[tier] = price
+	}
+	if budget.MaxCost > 0 && budget.Prices[LLMProModel] == (LLMPrice{}) {
+		return budget, fmt.Errorf("llmCostBudget requires llmPromptPricePerMillion and llmResponsePricePerMillion for the %s LLM backend", backend)
+	}
+	return budget, nil
+}
+
+// FitsPrompts returns whether prompts of promptTokens tokens fit in the budget,
+// pricing them as prompts of the pro model. Responses aren't estimated.
+func (b LLMBudget) FitsPrompts(promptTokens int) bool {
+	return (b.MaxTokens == 0 || promptTokens <= b.MaxTokens) && (b.MaxCost == 0 || b.Cost(LLMProModel, LLMUsage{PromptTokens: promptTokens}) <= b.MaxCost)
+}
+
+// Cost returns the cost in USD of usage with the model of tier.
+func (b LLMBudget) Cost(tier LLMModelTier, usage LLMUsage) float64 {
+	price := b.Prices[tier]
+	return (float64(usage.PromptTokens)*price.PromptPerMillion + float64(usage.ResponseTokens)*price.ResponsePerMillion) / 1e6
+}
+
+// BudgetedLLMClient is an LLMClient which records the tokens used and rejects
+// the prompts which would exceed its budget with ErrLLMBudgetExceeded.
+type BudgetedLLMClient struct {
+	LLMClient
+	Budget LLMBudget
+
+	mu       sync.Mutex
+	usage    map[LLMModelTier]LLMUsage
+	pending  map[LLMModelTier]LLMUsage // Estimated usage of the prompts in flight.
+	rejected int
+}
+
+// NewBudgetedLLMClient returns a client sending the prompts within budget to
+// client.
+func NewBudgetedLLMClient(client LLMClient, budget LLMBudget) *BudgetedLLMClient {
+	return &BudgetedLLMClient{
+		LLMClient: client,
+		Budget:    budget,
+		usage:     map[LLMModelTier]LLMUsage{},
+		pending:   map[LLMModelTier]LLMUsage{},
+	}
+}
+
+// fits returns whether the prompts in flight and a prompt of estimated usage
+// with the model of tier stay within budget. c.mu must be held.
+func (c *BudgetedLLMClient) fits(tier LLMModelTier, estimated LLMUsage) bool {
+	var tokens int
+	var cost float64
+	for _, m := range []map[LLMModelTier]LLMUsage{c.usage, c.pending} {
+		for t, u := range m {
+			tokens += u.total()
+			cost += c.Budget.Cost(t, u)
+		}
+	}
+	tokens += estimated.total()
+	cost += c.Budget.Cost(tier, estimated)
+	return (c.Budget.MaxTokens == 0 || tokens <= c.Budget.MaxTokens) && (c.Budget.MaxCost == 0 || cost <= c.Budget.MaxCost)
+}
+
+func (c *BudgetedLLMClient) GenerateContent(ctx context.Context, tier LLMModelTier, prompt string) (string, LLMUsage, error) {
+	// Responses aren't known before the prompt is sent, only its tokens are
+	// checked against the budget.
+	estimated := LLMUsage{PromptTokens: EstimateTokens(prompt)}
+	c.mu.Lock()
+	if !c.fits(tier, estimated) {
+		c.rejected++
+		c.mu.Unlock()
+		return "", LLMUsage{}, ErrLLMBudgetExceeded
+	}
+	pending := c.pending[tier]
+	pending.add(estimated)
+	c.pending[tier] = pending
+	c.mu.Unlock()
+
+	response, usage, err := c.LLMClient.GenerateContent(ctx, tier, prompt)
+	if err == nil && usage == (LLMUsage{}) {
+		// The backend didn't report the usage.
+		usage = LLMUsage{PromptTokens: estimated.PromptTokens, ResponseTokens: EstimateTokens(response)}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending = c.pending[tier]
+	pending.PromptTokens -= estimated.PromptTokens
+	c.pending[tier] = pending
+	used := c.usage[tier]
+	used.add(usage)
+	c.usage[tier] = used
+	return response, usage, err
+}
+
+// Usage returns the tokens used, their cost in USD and the number of prompts
+// rejected as they would have exceeded the budget.
+func (c *BudgetedLLMClient) Usage() (LLMUsage, float64, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var total LLMUsage
+	var cost float64
+	for tier, u := range c.usage {
+		total.add(u)
+		cost += c.Budget.Cost(tier, u)
+	}
+	return total, cost, c.rejected
+}
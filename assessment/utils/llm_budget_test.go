@@ -0,0 +1,83 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package utils
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLLMClient struct {
+	usage LLMUsage
+}
+
+func (c *fakeLLMClient) Backend() string {
+	return LLMBackendOpenAI
+}
+
+func (c *fakeLLMClient) GenerateContent(ctx context.Context, tier LLMModelTier, prompt string) (string, LLMUsage, error) {
+	return "{}", c.usage, nil
+}
+
+func TestNewLLMBudget(t *testing.T) {
+	budget, err := NewLLMBudget(map[string]string{}, LLMBackendVertexAI)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, budget.MaxTokens)
+	assert.Equal(t, LLMPrice{PromptPerMillion: 1.25, ResponsePerMillion: 10}, budget.Prices[LLMProModel])
+	assert.True(t, budget.FitsPrompts(1<<30))
+
+	budget, err = NewLLMBudget(map[string]string{"llmTokenBudget": "1000", "llmCostBudget": "5", "llmPromptPricePerMillion": "2", "llmResponsePricePerMillion": "4"}, LLMBackendOpenAI)
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, budget.MaxTokens)
+	assert.Equal(t, 5.0, budget.MaxCost)
+	assert.Equal(t, LLMPrice{PromptPerMillion: 2, ResponsePerMillion: 4}, budget.Prices[LLMFlashModel])
+	assert.True(t, budget.FitsPrompts(1000))
+	assert.False(t, budget.FitsPrompts(1001))
+	assert.InDelta(t, 0.006, budget.Cost(LLMProModel, LLMUsage{PromptTokens: 1000, ResponseTokens: 1000}), 1e-9)
+
+	_, err = NewLLMBudget(map[string]string{"llmTokenBudget": "lots"}, LLMBackendVertexAI)
+	assert.EqualError(t, err, "invalid llmTokenBudget lots, it must be a number of tokens")
+
+	_, err = NewLLMBudget(map[string]string{"llmCostBudget": "10"}, LLMBackendOpenAI)
+	assert.EqualError(t, err, "llmCostBudget requires llmPromptPricePerMillion and llmResponsePricePerMillion for the openai LLM backend")
+}
+
+func TestBudgetedLLMClient(t *testing.T) {
+	fake := &fakeLLMClient{usage: LLMUsage{PromptTokens: 40, ResponseTokens: 20}}
+	client := NewBudgetedLLMClient(fake, LLMBudget{MaxTokens: 100})
+	assert.Equal(t, LLMBackendOpenAI, client.Backend())
+
+	_, usage, err := client.GenerateContent(context.Background(), LLMProModel, "short prompt")
+	assert.NoError(t, err)
+	assert.Equal(t, fake.usage, usage)
+
+	// 60 tokens are used, a prompt of 50 tokens would exceed the budget.
+	_, _, err = client.GenerateContent(context.Background(), LLMProModel, strings.Repeat("x", 200))
+	assert.ErrorIs(t, err, ErrLLMBudgetExceeded)
+
+	// The usage of backends which don't report it is estimated.
+	fake.usage = LLMUsage{}
+	_, usage, err = client.GenerateContent(context.Background(), LLMFlashModel, strings.Repeat("x", 40))
+	assert.NoError(t, err)
+	assert.Equal(t, LLMUsage{PromptTokens: 10, ResponseTokens: 1}, usage)
+
+	total, cost, rejected := client.Usage()
+	assert.Equal(t, LLMUsage{PromptTokens: 50, ResponseTokens: 21}, total)
+	assert.Equal(t, 0.0, cost)
+	assert.Equal(t, 1, rejected)
+}
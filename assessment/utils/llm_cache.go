@@ -0,0 +1,120 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+// LLMResponseCache caches the results of LLM analyses on disk, one file per
+// key, so that re-running an assessment only sends the prompts whose inputs
+// changed. It is safe for concurrent use, also by several assessments sharing
+// the directory. A nil cache caches nothing.
+type LLMResponseCache struct {
+	Dir    string
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// DefaultLLMCacheDir returns the directory of the cache of the LLM analyses
+// in the user cache directory.
+func DefaultLLMCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "spanner-migration-tool", "assessment-llm-cache"), nil
+}
+
+// NewLLMResponseCache returns a cache in dir, creating it if needed.
+func NewLLMResponseCache(dir string) (*LLMResponseCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create the LLM cache directory %s: %w", dir, err)
+	}
+	return &LLMResponseCache{Dir: dir}, nil
+}
+
+// LLMCacheKey returns the key of the cache of the analysis of parts, e.g. the
+// content of a file, the schemas and the prompt templates.
+func LLMCacheKey(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		// Length prefixes keep the boundaries of the parts in the hash.
+		binary.Write(h, binary.BigEndian, uint64(len(part)))
+		h.Write([]byte(part))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *LLMResponseCache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
+
+// Get reads the value cached for key into value, and returns whether it was
+// cached.
+func (c *LLMResponseCache) Get(key string, value any) bool {
+	if c == nil {
+		return false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil || json.Unmarshal(data, value) != nil {
+		c.misses.Add(1)
+		return false
+	}
+	c.hits.Add(1)
+	return true
+}
+
+// Put caches value for key. The file is renamed into place so that readers
+// never see partial values.
+func (c *LLMResponseCache) Put(key string, value any) error {
+	if c == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(c.Dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// Stats returns the number of hits and misses of the cache.
+func (c *LLMResponseCache) Stats() (int64, int64) {
+	if c == nil {
+		return 0, 0
+	}
+	return c.hits.Load(), c.misses.Load()
+}
@@ -0,0 +1,67 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type cachedAnalysis struct {
+	Response string
+}
+
+func TestLLMResponseCache(t *testing.T) {
+	cache, err := NewLLMResponseCache(t.TempDir())
+	assert.NoError(t, err)
+
+	key := LLMCacheKey("prompt", "class UserDao {}")
+	assert.NotEqual(t, key, LLMCacheKey("promptclass", " UserDao {}"))
+
+	var value cachedAnalysis
+	assert.False(t, cache.Get(key, &value))
+	assert.NoError(t, cache.Put(key, cachedAnalysis{Response: `{"code_changes": []}`}))
+	assert.True(t, cache.Get(key, &value))
+	assert.Equal(t, `{"code_changes": []}`, value.Response)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := LLMCacheKey(fmt.Sprint(i % 2))
+			assert.NoError(t, cache.Put(key, cachedAnalysis{Response: "response"}))
+			var value cachedAnalysis
+			assert.True(t, cache.Get(key, &value))
+		}(i)
+	}
+	wg.Wait()
+	hits, misses := cache.Stats()
+	assert.Equal(t, int64(11), hits)
+	assert.Equal(t, int64(1), misses)
+
+	// Only the values remain, not the temporary files.
+	entries, err := os.ReadDir(cache.Dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	var nilCache *LLMResponseCache
+	assert.False(t, nilCache.Get(key, &value))
+	assert.NoError(t, nilCache.Put(key, value))
+}
@@ -0,0 +1,262 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/vertexai/genai"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"go.uber.org/zap"
+)
+
+// LLM backends of the assessment, selected with llmBackend in the assessment
+// profile.
+const (
+	LLMBackendVertexAI = "vertexai"
+	LLMBackendOpenAI   = "openai" // OpenAI compatible chat completions endpoint, e.g. a local model server.
+	LLMBackendNone     = "none"   // No LLM: the assessment skips the steps which need one.
+)
+
+// LLMModelTier selects the model of a backend used for a prompt.
+type LLMModelTier int
+
+const (
+	// LLMProModel is the most capable model, used for the code analyses.
+	LLMProModel LLMModelTier = iota
+	// LLMFlashModel is the fast model, used for auxiliary prompts.
+	LLMFlashModel
+)
+
+// ErrLLMDisabled is returned by the LLM client of the none backend.
+var ErrLLMDisabled = errors.New("no LLM backend is configured")
+
+// LLMClient generates the responses of a large language model to prompts.
+type LLMClient interface {
+	// GenerateContent returns the text of the response of the model of tier
+	// to prompt, and the tokens used if reported by the backend. Prompts ask
+	// for JSON responses.
+	GenerateContent(ctx context.Context, tier LLMModelTier, prompt string) (string, LLMUsage, error)
+	// Backend returns the LLM backend of the client.
+	Backend() string
+}
+
+// NewLLMClient returns the client of the LLM backend selected by llmBackend in
+// the assessment config, Vertex AI by default. The openai backend requires
+// llmEndpoint and llmModel, llmFlashModel defaults to llmModel and the API key
+// is read from llmApiKey or the OPENAI_API_KEY environment variable.
+func NewLLMClient(ctx context.Context, assessmentConfig map[string]string, projectID, location string) (LLMClient, error) {
+	switch backend := strings.ToLower(assessmentConfig["llmBackend"]); backend {
+	case "", LLMBackendVertexAI:
+		client, err := genai.NewClient(ctx, projectID, location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Vertex AI client: %w", err)
+		}
+		return NewVertexAILLMClient(client), nil
+	case LLMBackendOpenAI:
+		endpoint, model := assessmentConfig["llmEndpoint"], assessmentConfig["llmModel"]
+		if endpoint == "" || model == "" {
+			return nil, fmt.Errorf("llmEndpoint and llmModel are required for the %s LLM backend", LLMBackendOpenAI)
+		}
+		flashModel := assessmentConfig["llmFlashModel"]
+		if flashModel == "" {
+			flashModel = model
+		}
+		apiKey := assessmentConfig["llmApiKey"]
+		if apiKey == "" {
+			apiKey = os.Getenv("OPENAI_API_KEY")
+		}
+		return &OpenAILLMClient{
+			Endpoint:   strings.TrimSuffix(endpoint, "/"),
+			APIKey:     apiKey,
+			Models:     map[LLMModelTier]string{LLMProModel: model, LLMFlashModel: flashModel},
+			HTTPClient: &http.Client{Timeout: 10 * time.Minute},
+		}, nil
+	case LLMBackendNone:
+		return NoopLLMClient{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM backend %q, supported backends are %s, %s and %s", backend, LLMBackendVertexAI, LLMBackendOpenAI, LLMBackendNone)
+	}
+}
+
+// VertexAILLMClient generates responses with the Gemini models of Vertex AI.
+type VertexAILLMClient struct {
+	Client      *genai.Client
+	RetryClient LLMRetryClient
+}
+
+// NewVertexAILLMClient returns the LLM client of a Vertex AI client.
+func NewVertexAILLMClient(client *genai.Client) *VertexAILLMClient {
+	return &VertexAILLMClient{Client: client, RetryClient: &DefaultLLMRetryClient{}}
+}
+
+func (c *VertexAILLMClient) Backend() string {
+	return LLMBackendVertexAI
+}
+
+func (c *VertexAILLMClient) GenerateContent(ctx context.Context, tier LLMModelTier, prompt string) (string, LLMUsage, error) {
+	modelName := GEMINI_PRO_MODEL
+	if tier == LLMFlashModel {
+		modelName = GEMINI_FLASH_MODEL
+	}
+	model := c.Client.GenerativeModel(modelName)
+	model.ResponseMIMEType = "application/json"
+	response, err := c.RetryClient.GenerateContentWithRetry(ctx, model, genai.Text(prompt), 5, logger.Log)
+	if err != nil {
+		return "", LLMUsage{}, err
+	}
+	var usage LLMUsage
+	if response.UsageMetadata != nil {
+		logger.Log.Debug("LLM Token Usage: ",
+			zap.String("Model", modelName),
+			zap.Int32("Prompt Tokens", response.UsageMetadata.PromptTokenCount),
+			zap.Int32("Candidate Tokens", response.UsageMetadata.CandidatesTokenCount),
+			zap.Int32("Total Tokens", response.UsageMetadata.TotalTokenCount))
+		usage = LLMUsage{PromptTokens: int(response.UsageMetadata.PromptTokenCount), ResponseTokens: int(response.UsageMetadata.CandidatesTokenCount)}
+	}
+	if len(response.Candidates) > 0 && response.Candidates[0].Content != nil && len(response.Candidates[0].Content.Parts) > 0 {
+		if part, ok := response.Candidates[0].Content.Parts[0].(genai.Text); ok {
+			return string(part), usage, nil
+		}
+	}
+	return "", usage, nil
+}
+
+// OpenAILLMClient generates responses with an OpenAI compatible chat
+// completions endpoint, e.g. a model served locally for air-gapped
+// assessments.
+type OpenAILLMClient struct {
+	Endpoint   string // Base URL of the API, e.g. http://localhost:8000/v1
+	APIKey     string // Sent as a bearer token, if set
+	Models     map[LLMModelTier]string
+	HTTPClient *http.Client
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatRequest struct {
+	Model          string            `json:"model"`
+	Messages       []openAIMessage   `json:"messages"`
+	ResponseFormat map[string]string `json:"response_format,omitempty"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+func (c *OpenAILLMClient) Backend() string {
+	return LLMBackendOpenAI
+}
+
+func (c *OpenAILLMClient) GenerateContent(ctx context.Context, tier LLMModelTier, prompt string) (string, LLMUsage, error) {
+	body, err := json.Marshal(openAIChatRequest{
+		Model:          c.Models[tier],
+		Messages:       []openAIMessage{{Role: "user", Content: prompt}},
+		ResponseFormat: map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return "", LLMUsage{}, err
+	}
+	const maxRetries = 5
+	for i := 0; ; i++ {
+		response, status, err := c.post(ctx, body)
+		if err == nil {
+			logger.Log.Debug("LLM Token Usage: ",
+				zap.String("Model", c.Models[tier]),
+				zap.Int("Prompt Tokens", response.Usage.PromptTokens),
+				zap.Int("Candidate Tokens", response.Usage.CompletionTokens),
+				zap.Int("Total Tokens", response.Usage.TotalTokens))
+			usage := LLMUsage{PromptTokens: response.Usage.PromptTokens, ResponseTokens: response.Usage.CompletionTokens}
+			if len(response.Choices) == 0 {
+				return "", usage, nil
+			}
+			return response.Choices[0].Message.Content, usage, nil
+		}
+		// Retry when rate limited or when the server is unavailable.
+		if i+1 == maxRetries || (status != http.StatusTooManyRequests && status != http.StatusBadGateway && status != http.StatusServiceUnavailable) {
+			return "", LLMUsage{}, err
+		}
+		backoff := time.Duration(math.Pow(2, float64(i))) * time.Second
+		logger.Log.Warn("LLM endpoint unavailable, backing off", zap.Int("attempt", i+1), zap.Duration("backoff", backoff), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return "", LLMUsage{}, ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// post sends a chat completions request and returns the response, and the
+// HTTP status of the failed requests.
+func (c *OpenAILLMClient) post(ctx context.Context, body []byte) (*openAIChatResponse, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("LLM endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	var response openAIChatResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("could not parse the response of the LLM endpoint: %w", err)
+	}
+	return &response, resp.StatusCode, nil
+}
+
+// NoopLLMClient is the client of the none backend, which fails every prompt
+// with ErrLLMDisabled so that the assessment runs without an LLM.
+type NoopLLMClient struct{}
+
+func (NoopLLMClient) Backend() string {
+	return LLMBackendNone
+}
+
+func (NoopLLMClient) GenerateContent(ctx context.Context, tier LLMModelTier, prompt string) (string, LLMUsage, error) {
+	logger.Log.Debug("skipping prompt as no LLM backend is configured", zap.Int("Prompt Length", len(prompt)))
+	return "", LLMUsage{}, ErrLLMDisabled
+}
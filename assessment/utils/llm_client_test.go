@@ -0,0 +1,83 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLLMClient(t *testing.T) {
+	ctx := context.Background()
+
+	client, err := NewLLMClient(ctx, map[string]string{"llmBackend": "none"}, "project", "us-central1")
+	assert.NoError(t, err)
+	assert.Equal(t, LLMBackendNone, client.Backend())
+	_, _, err = client.GenerateContent(ctx, LLMProModel, "prompt")
+	assert.ErrorIs(t, err, ErrLLMDisabled)
+
+	client, err = NewLLMClient(ctx, map[string]string{"llmBackend": "openai", "llmEndpoint": "http://localhost:8000/v1/", "llmModel": "llama", "llmApiKey": "key"}, "project", "us-central1")
+	assert.NoError(t, err)
+	openAIClient := client.(*OpenAILLMClient)
+	assert.Equal(t, "http://localhost:8000/v1", openAIClient.Endpoint)
+	assert.Equal(t, "key", openAIClient.APIKey)
+	assert.Equal(t, map[LLMModelTier]string{LLMProModel: "llama", LLMFlashModel: "llama"}, openAIClient.Models)
+
+	_, err = NewLLMClient(ctx, map[string]string{"llmBackend": "openai", "llmModel": "llama"}, "project", "us-central1")
+	assert.EqualError(t, err, "llmEndpoint and llmModel are required for the openai LLM backend")
+
+	_, err = NewLLMClient(ctx, map[string]string{"llmBackend": "bard"}, "project", "us-central1")
+	assert.EqualError(t, err, `unsupported LLM backend "bard", supported backends are vertexai, openai and none`)
+}
+
+func TestOpenAILLMClient(t *testing.T) {
+	var requests []openAIChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		var req openAIChatRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		if req.Model == "broken" {
+			http.Error(w, "model not found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"questions\": []}"}}], "usage": {"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15}}`))
+	}))
+	defer server.Close()
+
+	client := &OpenAILLMClient{
+		Endpoint:   server.URL + "/v1",
+		APIKey:     "key",
+		Models:     map[LLMModelTier]string{LLMProModel: "large", LLMFlashModel: "broken"},
+		HTTPClient: server.Client(),
+	}
+	response, usage, err := client.GenerateContent(context.Background(), LLMProModel, "analyze this")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"questions": []}`, response)
+	assert.Equal(t, LLMUsage{PromptTokens: 10, ResponseTokens: 5}, usage)
+	assert.Equal(t, []openAIMessage{{Role: "user", Content: "analyze this"}}, requests[0].Messages)
+	assert.Equal(t, "json_object", requests[0].ResponseFormat["type"])
+
+	// Errors other than rate limits aren't retried.
+	_, _, err = client.GenerateContent(context.Background(), LLMFlashModel, "analyze this")
+	assert.ErrorContains(t, err, "LLM endpoint returned 404 Not Found: model not found")
+	assert.Len(t, requests, 2)
+}
@@ -0,0 +1,218 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/pingcap/tidb/pkg/parser/opcode"
+)
+
+// QueryColumn is a column referenced by a query.
+type QueryColumn struct {
+	Table  string // Table of the column, empty if it isn't qualified and the query reads several tables.
+	Column string
+}
+
+// QueryJSONPath is a path of a JSON column referenced by a query.
+type QueryJSONPath struct {
+	QueryColumn
+	Path string // e.g. $.address.city
+}
+
+// QueryColumnUsage is how a query uses the columns of its tables.
+type QueryColumnUsage struct {
+	Tables       []string        // Tables of the query.
+	OrderBy      []QueryColumn   // Columns the rows are ordered by.
+	Lookups      []QueryColumn   // Columns compared to values with =, <>, IN or LIKE.
+	Max          []QueryColumn   // Columns whose maximum is read.
+	LastInsertId bool            // Whether the query reads the last auto-increment value generated.
+	JSONPaths    []QueryJSONPath // JSON paths the rows are ordered by or compared to values, e.g. doc->>'$.status' = ?.
+}
+
+// jsonPathRef is a path of a JSON column in a query.
+type jsonPathRef struct {
+	column *ast.ColumnName
+	path   string
+}
+
+// queryColumnVisitor collects the column usage of a query.
+type queryColumnVisitor struct {
+	aliases map[string]string // Maps the aliases and names of the tables to the tables.
+	tables  map[string]bool
+	usage   QueryColumnUsage
+	orderBy []*ast.ColumnName
+	lookups []*ast.ColumnName
+	max     []*ast.ColumnName
+	paths   []jsonPathRef
+}
+
+func columnName(expr ast.ExprNode) *ast.ColumnName {
+	if c, ok := expr.(*ast.ColumnNameExpr); ok {
+		return c.Name
+	}
+	return nil
+}
+
+// jsonPath returns the path of the JSON column extracted by expr, i.e.
+// col->'$.path', col->>'$.path' or JSON_EXTRACT(col, '$.path') with
+// or without JSON_UNQUOTE.
+func jsonPath(expr ast.ExprNode) (jsonPathRef, bool) {
+	f, ok := expr.(*ast.FuncCallExpr)
+	if ok && f.FnName.L == ast.JSONUnquote && len(f.Args) == 1 {
+		f, ok = f.Args[0].(*ast.FuncCallExpr)
+	}
+	if !ok || f.FnName.L != ast.JSONExtract || len(f.Args) != 2 {
+		return jsonPathRef{}, false
+	}
+	c := columnName(f.Args[0])
+	v, ok := f.Args[1].(ast.ValueExpr)
+	if c == nil || !ok {
+		return jsonPathRef{}, false
+	}
+	path, ok := v.GetValue().(string)
+	return jsonPathRef{column: c, path: path}, ok
+}
+
+// addLookup records expr if it is a column or a path of a JSON column
+// compared to values.
+func (v *queryColumnVisitor) addLookup(expr ast.ExprNode) {
+	if c := columnName(expr); c != nil {
+		v.lookups = append(v.lookups, c)
+	} else if p, ok := jsonPath(expr); ok {
+		v.paths = append(v.paths, p)
+	}
+}
+
+func (v *queryColumnVisitor) Enter(in ast.Node) (ast.Node, bool) {
+	switch n := in.(type) {
+	case *ast.TableSource:
+		if t, ok := n.Source.(*ast.TableName); ok && n.AsName.L != "" {
+			v.aliases[n.AsName.L] = t.Name.O
+		}
+	case *ast.TableName:
+		v.aliases[n.Name.L] = n.Name.O
+		v.tables[n.Name.O] = true
+	case *ast.OrderByClause:
+		for _, item := range n.Items {
+			if c := columnName(item.Expr); c != nil {
+				v.orderBy = append(v.orderBy, c)
+			} else if p, ok := jsonPath(item.Expr); ok {
+				v.paths = append(v.paths, p)
+			}
+		}
+	case *ast.BinaryOperationExpr:
+		if n.Op == opcode.EQ || n.Op == opcode.NE || n.Op == opcode.NullEQ {
+			for _, expr := range []ast.ExprNode{n.L, n.R} {
+				v.addLookup(expr)
+			}
+		}
+	case *ast.PatternInExpr:
+		v.addLookup(n.Expr)
+	case *ast.PatternLikeOrIlikeExpr:
+		v.addLookup(n.Expr)
+	case *ast.AggregateFuncExpr:
+		if strings.EqualFold(n.F, ast.AggFuncMax) && len(n.Args) == 1 {
+			if c := columnName(n.Args[0]); c != nil {
+				v.max = append(v.max, c)
+			}
+		}
+	case *ast.FuncCallExpr:
+		if n.FnName.L == ast.LastInsertId {
+			v.usage.LastInsertId = true
+		}
+	}
+	return in, false
+}
+
+func (v *queryColumnVisitor) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// resolve returns the columns of names, with the tables their qualifiers
+// refer to. Unqualified columns are columns of the table of the query if it
+// reads a single one.
+func (v *queryColumnVisitor) resolve(names []*ast.ColumnName) []QueryColumn {
+	var columns []QueryColumn
+	seen := make(map[QueryColumn]bool)
+	for _, name := range names {
+		c := v.resolveColumn(name)
+		if !seen[c] {
+			seen[c] = true
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+func (v *queryColumnVisitor) resolveColumn(name *ast.ColumnName) QueryColumn {
+	c := QueryColumn{Column: name.Name.O}
+	switch {
+	case name.Table.L != "":
+		c.Table = v.aliases[name.Table.L]
+	case len(v.usage.Tables) == 1:
+		c.Table = v.usage.Tables[0]
+	}
+	return c
+}
+
+// resolvePaths returns the JSON paths of refs, with their columns resolved
+// like resolve.
+func (v *queryColumnVisitor) resolvePaths(refs []jsonPathRef) []QueryJSONPath {
+	var paths []QueryJSONPath
+	seen := make(map[QueryJSONPath]bool)
+	for _, ref := range refs {
+		p := QueryJSONPath{QueryColumn: v.resolveColumn(ref.column), Path: ref.path}
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// AnalyzeSourceQueryColumns returns how the query of the source database
+// driver uses the columns of its tables. Queries of other sources than
+// PostgreSQL and MongoDB are parsed as MySQL queries.
+func AnalyzeSourceQueryColumns(driver, query string) (QueryColumnUsage, error) {
+	switch driver {
+	case constants.POSTGRES, constants.PGDUMP:
+		return AnalyzePostgresQueryColumns(query)
+	case constants.MONGODB:
+		return AnalyzeMongoQueryColumns(query)
+	}
+	return AnalyzeQueryColumns(query)
+}
+
+// AnalyzeQueryColumns returns how the MySQL query uses the columns of its
+// tables.
+func AnalyzeQueryColumns(query string) (QueryColumnUsage, error) {
+	stmt, err := parser.New().ParseOneStmt(query, "", "")
+	if err != nil {
+		return QueryColumnUsage{}, fmt.Errorf("could not parse the query: %w", err)
+	}
+	v := &queryColumnVisitor{aliases: map[string]string{}, tables: map[string]bool{}}
+	stmt.Accept(v)
+	v.usage.Tables = sortedKeys(v.tables)
+	v.usage.OrderBy = v.resolve(v.orderBy)
+	v.usage.Lookups = v.resolve(v.lookups)
+	v.usage.Max = v.resolve(v.max)
+	v.usage.JSONPaths = v.resolvePaths(v.paths)
+	return v.usage, nil
+}
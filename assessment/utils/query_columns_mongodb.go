@@ -0,0 +1,57 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// mongoCollectionRegexp matches the reads of a collection in the mongo shell
+// syntax, e.g. db.orders.find( or db.getCollection("orders").find(.
+var mongoCollectionRegexp = regexp.MustCompile(`\bdb\s*(?:\.\s*getCollection\(\s*["']([\w.]+)["']\s*\)|\.\s*(\w+))\s*\.\s*(?:find|findOne|countDocuments|count|distinct|aggregate)\s*\(`)
+
+// mongoDottedKeyRegexp matches the keys of embedded document fields, e.g.
+// "address.city": in filters and sorts.
+var mongoDottedKeyRegexp = regexp.MustCompile(`["']([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)+)["']\s*:`)
+
+// AnalyzeMongoQueryColumns returns how the MongoDB query, in the mongo shell
+// syntax, uses the fields of its collection. Only the collection and the
+// paths of the embedded documents which the query filters or sorts documents
+// by are analyzed: embedded documents are migrated to JSON columns.
+func AnalyzeMongoQueryColumns(query string) (QueryColumnUsage, error) {
+	m := mongoCollectionRegexp.FindStringSubmatchIndex(query)
+	if m == nil {
+		return QueryColumnUsage{}, fmt.Errorf("no read of a collection found")
+	}
+	var collection string
+	if m[2] >= 0 {
+		collection = query[m[2]:m[3]]
+	} else {
+		collection = query[m[4]:m[5]]
+	}
+	usage := QueryColumnUsage{Tables: []string{collection}}
+	seen := make(map[QueryJSONPath]bool)
+	for _, k := range mongoDottedKeyRegexp.FindAllStringSubmatch(query[m[1]:], -1) {
+		keys := strings.Split(k[1], ".")
+		p := QueryJSONPath{QueryColumn: QueryColumn{Table: collection, Column: keys[0]}, Path: "$." + strings.Join(keys[1:], ".")}
+		if !seen[p] {
+			seen[p] = true
+			usage.JSONPaths = append(usage.JSONPaths, p)
+		}
+	}
+	return usage, nil
+}
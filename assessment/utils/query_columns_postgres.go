@@ -0,0 +1,175 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package utils
+
+import (
+	"strings"
+
+	pg_query "github.com/pganalyze/pg_query_go/v6"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// pgComparisonOps are the operators comparing expressions to values.
+var pgComparisonOps = map[string]bool{"=": true, "<>": true, "!=": true, "<": true, ">": true, "<=": true, ">=": true}
+
+// pgJSONPathRef is a path of a JSONB column in a PostgreSQL query.
+type pgJSONPathRef struct {
+	table  string // Table or alias qualifying the column, if any.
+	column string
+	path   string
+}
+
+// pgQueryColumnVisitor collects the JSON paths of a PostgreSQL query.
+type pgQueryColumnVisitor struct {
+	usage   QueryColumnUsage
+	aliases map[string]string // Maps the lower case aliases and names of the tables to the table names.
+	paths   []pgJSONPathRef
+}
+
+// walk visits the nodes of the parse tree m.
+func (v *pgQueryColumnVisitor) walk(m protoreflect.Message) {
+	if n, ok := m.Interface().(*pg_query.Node); ok {
+		v.visit(n)
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, val protoreflect.Value) bool {
+		switch {
+		case fd.Kind() != protoreflect.MessageKind || fd.IsMap():
+		case fd.IsList():
+			l := val.List()
+			for i := 0; i < l.Len(); i++ {
+				v.walk(l.Get(i).Message())
+			}
+		default:
+			v.walk(val.Message())
+		}
+		return true
+	})
+}
+
+func (v *pgQueryColumnVisitor) visit(n *pg_query.Node) {
+	if r := n.GetRangeVar(); r != nil {
+		if _, ok := v.aliases[strings.ToLower(r.Relname)]; !ok {
+			v.usage.Tables = append(v.usage.Tables, r.Relname)
+		}
+		v.aliases[strings.ToLower(r.Relname)] = r.Relname
+		if r.GetAlias() != nil {
+			v.aliases[strings.ToLower(r.GetAlias().Aliasname)] = r.Relname
+		}
+	}
+	if s := n.GetSortBy(); s != nil {
+		v.addPath(s.Node)
+	}
+	if e := n.GetAExpr(); e != nil {
+		switch e.Kind {
+		case pg_query.A_Expr_Kind_AEXPR_OP:
+			if pgComparisonOps[pgOperator(e)] {
+				v.addPath(e.Lexpr)
+				v.addPath(e.Rexpr)
+			}
+		case pg_query.A_Expr_Kind_AEXPR_IN, pg_query.A_Expr_Kind_AEXPR_LIKE, pg_query.A_Expr_Kind_AEXPR_ILIKE:
+			v.addPath(e.Lexpr)
+		}
+	}
+}
+
+// addPath records n if it extracts a path of a JSONB column.
+func (v *pgQueryColumnVisitor) addPath(n *pg_query.Node) {
+	if p, ok := pgJSONPath(n); ok {
+		v.paths = append(v.paths, p)
+	}
+}
+
+func pgOperator(e *pg_query.A_Expr) string {
+	if len(e.Name) == 0 {
+		return ""
+	}
+	return e.Name[len(e.Name)-1].GetString_().GetSval()
+}
+
+// pgJSONPath returns the path of the JSONB column extracted by n, i.e.
+// col->'a'->>'b' or col#>>'{a,b}', possibly cast to another type.
+func pgJSONPath(n *pg_query.Node) (pgJSONPathRef, bool) {
+	if c := n.GetTypeCast(); c != nil {
+		n = c.Arg
+	}
+	e := n.GetAExpr()
+	if e == nil || e.Kind != pg_query.A_Expr_Kind_AEXPR_OP || e.Rexpr.GetAConst().GetSval() == nil {
+		return pgJSONPathRef{}, false
+	}
+	key := e.Rexpr.GetAConst().GetSval().GetSval()
+	switch pgOperator(e) {
+	case "->", "->>":
+		if ref, ok := pgColumnRef(e.Lexpr); ok {
+			ref.path = "$." + key
+			return ref, true
+		}
+		inner := e.Lexpr.GetAExpr()
+		if inner == nil || pgOperator(inner) != "->" {
+			return pgJSONPathRef{}, false
+		}
+		ref, ok := pgJSONPath(e.Lexpr)
+		ref.path += "." + key
+		return ref, ok
+	case "#>", "#>>":
+		ref, ok := pgColumnRef(e.Lexpr)
+		keys := strings.Split(strings.Trim(key, "{}"), ",")
+		for i := range keys {
+			keys[i] = strings.TrimSpace(keys[i])
+		}
+		ref.path = "$." + strings.Join(keys, ".")
+		return ref, ok
+	}
+	return pgJSONPathRef{}, false
+}
+
+// pgColumnRef returns the column referenced by n, if any.
+func pgColumnRef(n *pg_query.Node) (pgJSONPathRef, bool) {
+	c := n.GetColumnRef()
+	if c == nil || len(c.Fields) == 0 || c.Fields[len(c.Fields)-1].GetString_() == nil {
+		return pgJSONPathRef{}, false
+	}
+	ref := pgJSONPathRef{column: c.Fields[len(c.Fields)-1].GetString_().GetSval()}
+	if len(c.Fields) > 1 {
+		ref.table = c.Fields[len(c.Fields)-2].GetString_().GetSval()
+	}
+	return ref, true
+}
+
+// AnalyzePostgresQueryColumns returns how the PostgreSQL query uses the
+// columns of its tables. Only the tables and the JSON paths of JSONB columns
+// are analyzed.
+func AnalyzePostgresQueryColumns(query string) (QueryColumnUsage, error) {
+	tree, err := pg_query.Parse(query)
+	if err != nil {
+		return QueryColumnUsage{}, err
+	}
+	v := &pgQueryColumnVisitor{aliases: make(map[string]string)}
+	v.walk(tree.ProtoReflect())
+	seen := make(map[QueryJSONPath]bool)
+	for _, ref := range v.paths {
+		p := QueryJSONPath{QueryColumn: QueryColumn{Column: ref.column}, Path: ref.path}
+		switch {
+		case ref.table != "":
+			p.Table = v.aliases[strings.ToLower(ref.table)]
+		case len(v.usage.Tables) == 1:
+			p.Table = v.usage.Tables[0]
+		}
+		if !seen[p] {
+			seen[p] = true
+			v.usage.JSONPaths = append(v.usage.JSONPaths, p)
+		}
+	}
+	return v.usage, nil
+}
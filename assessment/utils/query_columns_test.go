@@ -0,0 +1,89 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeQueryColumns(t *testing.T) {
+	usage, err := AnalyzeQueryColumns("SELECT o.id, name FROM orders o JOIN users u ON o.user_id = u.id WHERE u.email = ? AND status IN ('new', 'paid') AND u.name LIKE ? ORDER BY o.id DESC")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"orders", "users"}, usage.Tables)
+	assert.Equal(t, []QueryColumn{{Table: "orders", Column: "id"}}, usage.OrderBy)
+	assert.Equal(t, []QueryColumn{
+		{Table: "orders", Column: "user_id"},
+		{Table: "users", Column: "id"},
+		{Table: "users", Column: "email"},
+		{Column: "status"},
+		{Table: "users", Column: "name"},
+	}, usage.Lookups)
+	assert.False(t, usage.LastInsertId)
+
+	usage, err = AnalyzeQueryColumns("SELECT MAX(id), LAST_INSERT_ID() FROM users")
+	assert.NoError(t, err)
+	assert.Equal(t, []QueryColumn{{Table: "users", Column: "id"}}, usage.Max)
+	assert.True(t, usage.LastInsertId)
+
+	usage, err = AnalyzeQueryColumns("SELECT id FROM orders o WHERE o.doc->>'$.status' = 'paid' AND JSON_EXTRACT(doc, '$.customer.city') IN (?, ?) AND doc->'$.status' <> 'new' ORDER BY doc->'$.created'")
+	assert.NoError(t, err)
+	assert.Equal(t, []QueryJSONPath{
+		{QueryColumn: QueryColumn{Table: "orders", Column: "doc"}, Path: "$.status"},
+		{QueryColumn: QueryColumn{Table: "orders", Column: "doc"}, Path: "$.customer.city"},
+		{QueryColumn: QueryColumn{Table: "orders", Column: "doc"}, Path: "$.created"},
+	}, usage.JSONPaths)
+	assert.Empty(t, usage.Lookups)
+
+	_, err = AnalyzeQueryColumns("SELEC id FROM users")
+	assert.ErrorContains(t, err, "could not parse the query")
+}
+
+func TestAnalyzePostgresQueryColumns(t *testing.T) {
+	usage, err := AnalyzePostgresQueryColumns("SELECT id FROM orders o JOIN users u ON o.user_id = u.id WHERE o.doc->>'status' = $1 AND (doc->'customer'->>'city') IN ('Paris', 'Lyon') AND (o.doc#>>'{shipping, carrier}')::text LIKE $2 ORDER BY o.doc->'created'")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"orders", "users"}, usage.Tables)
+	assert.Equal(t, []QueryJSONPath{
+		{QueryColumn: QueryColumn{Table: "orders", Column: "doc"}, Path: "$.status"},
+		{QueryColumn: QueryColumn{Column: "doc"}, Path: "$.customer.city"},
+		{QueryColumn: QueryColumn{Table: "orders", Column: "doc"}, Path: "$.shipping.carrier"},
+		{QueryColumn: QueryColumn{Table: "orders", Column: "doc"}, Path: "$.created"},
+	}, usage.JSONPaths)
+
+	usage, err = AnalyzeSourceQueryColumns("postgres", "SELECT id FROM orders WHERE doc->>'status' = 'paid'")
+	assert.NoError(t, err)
+	assert.Equal(t, []QueryJSONPath{{QueryColumn: QueryColumn{Table: "orders", Column: "doc"}, Path: "$.status"}}, usage.JSONPaths)
+
+	_, err = AnalyzePostgresQueryColumns("SELEC id FROM users")
+	assert.Error(t, err)
+}
+
+func TestAnalyzeMongoQueryColumns(t *testing.T) {
+	usage, err := AnalyzeSourceQueryColumns("mongodb", `db.orders.find({"customer.city": "Paris", status: "paid", 'shipping.carrier.name': {$in: ["UPS"]}}).sort({"customer.city": 1})`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"orders"}, usage.Tables)
+	assert.Equal(t, []QueryJSONPath{
+		{QueryColumn: QueryColumn{Table: "orders", Column: "customer"}, Path: "$.city"},
+		{QueryColumn: QueryColumn{Table: "orders", Column: "shipping"}, Path: "$.carrier.name"},
+	}, usage.JSONPaths)
+
+	usage, err = AnalyzeMongoQueryColumns(`db.getCollection("users").countDocuments({"address.zip": zip})`)
+	assert.NoError(t, err)
+	assert.Equal(t, []QueryJSONPath{{QueryColumn: QueryColumn{Table: "users", Column: "address"}, Path: "$.zip"}}, usage.JSONPaths)
+
+	_, err = AnalyzeMongoQueryColumns(`db.users.insertOne({"address.zip": zip})`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,429 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/pingcap/tidb/pkg/parser"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	_ "github.com/pingcap/tidb/pkg/types/parser_driver"
+)
+
+// Complexity of the queries translated by rules, matching the values of the
+// LLM translation.
+const (
+	complexitySimple   = "SIMPLE"
+	complexityModerate = "MODERATE"
+	complexityComplex  = "COMPLEX"
+)
+
+// renamedFunctions maps MySQL functions to the Spanner functions taking the
+// same arguments, by dialect.
+var renamedFunctions = map[string]map[string]string{
+	constants.DIALECT_GOOGLESQL: {
+		"IFNULL":           "COALESCE",
+		"NOW":              "CURRENT_TIMESTAMP",
+		"LOCALTIMESTAMP":   "CURRENT_TIMESTAMP",
+		"SYSDATE":          "CURRENT_TIMESTAMP",
+		"UTC_TIMESTAMP":    "CURRENT_TIMESTAMP",
+		"CURDATE":          "CURRENT_DATE",
+		"UTC_DATE":         "CURRENT_DATE",
+		"SUBSTRING":        "SUBSTR",
+		"MID":              "SUBSTR",
+		"LCASE":            "LOWER",
+		"UCASE":            "UPPER",
+		"CHARACTER_LENGTH": "CHAR_LENGTH",
+		"POW":              "POWER",
+		"GROUP_CONCAT":     "STRING_AGG",
+	},
+	constants.DIALECT_POSTGRESQL: {
+		"IFNULL":  "COALESCE",
+		"CURDATE": "CURRENT_DATE",
+		"LCASE":   "LOWER",
+		"UCASE":   "UPPER",
+		"RAND":    "RANDOM",
+	},
+}
+
+// mysqlToSpannerDateFormat maps the specifiers of MySQL DATE_FORMAT to the
+// ones of Spanner FORMAT_TIMESTAMP. Specifiers missing from the map are the
+// same in both, except those of untranslatedDateFormats.
+var mysqlToSpannerDateFormat = map[byte]string{
+	'i': "%M",
+	's': "%S",
+	'M': "%B",
+	'W': "%A",
+	'c': "%m",
+	'h': "%I",
+	'r': "%r",
+	'T': "%T",
+}
+
+// untranslatedDateFormats are the specifiers of MySQL DATE_FORMAT without a
+// FORMAT_TIMESTAMP equivalent: %f formats the microseconds alone, while %E6S
+// also formats the seconds.
+var untranslatedDateFormats = map[byte]bool{
+	'f': true,
+}
+
+// sqlToken is a token of a query. Whitespace and comments are kept as tokens
+// so that the parts of the query which aren't rewritten are left untouched.
+type sqlToken struct {
+	text  string
+	space bool
+}
+
+// tokenizeSQL splits a MySQL query into identifiers, quoted identifiers and
+// strings, numbers, whitespace and punctuation.
+func tokenizeSQL(query string) []sqlToken {
+	var tokens []sqlToken
+	for i := 0; i < len(query); {
+		c := query[i]
+		j := i + 1
+		space := false
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			for j < len(query) {
+				if query[j] == '\\' && c != '`' {
+					j += 2
+					continue
+				}
+				if query[j] == c {
+					if j+1 < len(query) && query[j+1] == c {
+						j += 2
+						continue
+					}
+					j++
+					break
+				}
+				j++
+			}
+		case c == '-' && strings.HasPrefix(query[i:], "-- "), c == '#':
+			for j < len(query) && query[j] != '\n' {
+				j++
+			}
+			space = true
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+				j = i + 2 + end + 2
+			} else {
+				j = len(query)
+			}
+			space = true
+		case unicode.IsSpace(rune(c)):
+			for j < len(query) && unicode.IsSpace(rune(query[j])) {
+				j++
+			}
+			space = true
+		case isWordByte(c):
+			for j < len(query) && isWordByte(query[j]) {
+				j++
+			}
+		case strings.ContainsRune("<>!=:|", rune(c)):
+			for j < len(query) && j < i+3 && strings.ContainsRune("<>!=:|", rune(query[j])) {
+				j++
+			}
+		}
+		if j > len(query) {
+			j = len(query)
+		}
+		tokens = append(tokens, sqlToken{text: query[i:j], space: space})
+		i = j
+	}
+	return tokens
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c == '.' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+// ruleTranslator rewrites the tokens of a MySQL query for a Spanner dialect
+// and records the rules it applied, and the calls it couldn't rewrite.
+type ruleTranslator struct {
+	dialect      string
+	applied      map[string]bool
+	untranslated map[string]bool
+}
+
+func (rt *ruleTranslator) apply(rule string) {
+	rt.applied[rule] = true
+}
+
+// nextToken returns the index of the first token after i which isn't
+// whitespace, or len(tokens).
+func nextToken(tokens []sqlToken, i int) int {
+	for i++; i < len(tokens) && tokens[i].space; i++ {
+	}
+	return i
+}
+
+// splitArgs returns the arguments of the function call whose opening
+// parenthesis is tokens[open], and the index of the closing parenthesis, or
+// -1 if it isn't closed.
+func splitArgs(tokens []sqlToken, open int) ([][]sqlToken, int) {
+	var args [][]sqlToken
+	depth := 0
+	start := open + 1
+	for i := open; i < len(tokens); i++ {
+		switch tokens[i].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				if i > start || len(args) > 0 {
+					args = append(args, tokens[start:i])
+				}
+				return args, i
+			}
+		case ",":
+			if depth == 1 {
+				args = append(args, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return nil, -1
+}
+
+// translate returns the query made of tokens rewritten for the dialect.
+func (rt *ruleTranslator) translate(tokens []sqlToken) string {
+	var sb strings.Builder
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case strings.HasPrefix(t.text, "`"):
+			if rt.dialect == constants.DIALECT_POSTGRESQL {
+				rt.apply("backtick quoted identifiers to double quoted identifiers")
+				sb.WriteString(`"` + strings.ReplaceAll(strings.Trim(t.text, "`"), `"`, `""`) + `"`)
+				continue
+			}
+		case strings.HasPrefix(t.text, `"`):
+			if rt.dialect == constants.DIALECT_POSTGRESQL {
+				rt.apply("double quoted strings to single quoted strings")
+				sb.WriteString("'" + strings.ReplaceAll(strings.Trim(t.text, `"`), "'", "''") + "'")
+				continue
+			}
+		case strings.EqualFold(t.text, "LIMIT"):
+			// LIMIT offset, count.
+			o := nextToken(tokens, i)
+			comma := nextToken(tokens, o)
+			c := nextToken(tokens, comma)
+			if c < len(tokens) && tokens[comma].text == "," {
+				rt.apply("LIMIT offset, count to LIMIT count OFFSET offset")
+				sb.WriteString(t.text + " " + tokens[c].text + " OFFSET " + tokens[o].text)
+				i = c
+				continue
+			}
+		case !t.space && isWordByte(t.text[0]):
+			open := nextToken(tokens, i)
+			if open >= len(tokens) || tokens[open].text != "(" {
+				break
+			}
+			fn := strings.ToUpper(t.text)
+			if call, end, ok := rt.translateCall(fn, tokens, open); ok {
+				sb.WriteString(call)
+				i = end
+				continue
+			}
+			if to, ok := renamedFunctions[rt.dialect][fn]; ok {
+				rt.apply(fmt.Sprintf("%s to %s", fn, to))
+				sb.WriteString(to)
+				continue
+			}
+		}
+		sb.WriteString(t.text)
+	}
+	return sb.String()
+}
+
+// translateCall rewrites the call of function fn whose arguments start at
+// tokens[open] when the arguments of the Spanner function differ. It returns
+// the rewritten call, the index of its closing parenthesis and whether it was
+// rewritten.
+func (rt *ruleTranslator) translateCall(fn string, tokens []sqlToken, open int) (string, int, bool) {
+	if rt.dialect != constants.DIALECT_GOOGLESQL || !translatedFunctions[fn] {
+		return "", 0, false
+	}
+	args, end := splitArgs(tokens, open)
+	if end < 0 {
+		return "", 0, false
+	}
+	translated := make([]string, len(args))
+	for i, arg := range args {
+		translated[i] = strings.TrimSpace(rt.translate(arg))
+	}
+	call := func(fn string, args ...string) string {
+		return fn + "(" + strings.Join(args, ", ") + ")"
+	}
+	switch {
+	case fn == "DATE_FORMAT" && len(args) == 2:
+		format, ok := convertDateFormat(translated[1])
+		if !ok {
+			rt.untranslated[fmt.Sprintf("DATE_FORMAT format %s", translated[1])] = true
+			return "", 0, false
+		}
+		rt.apply("DATE_FORMAT to FORMAT_TIMESTAMP")
+		return call("FORMAT_TIMESTAMP", format, translated[0]), end, true
+	case fn == "DATEDIFF" && len(args) == 2:
+		rt.apply("DATEDIFF to DATE_DIFF")
+		return call("DATE_DIFF", translated[0], translated[1], "DAY"), end, true
+	case fn == "UNIX_TIMESTAMP" && len(args) == 0:
+		rt.apply("UNIX_TIMESTAMP to UNIX_SECONDS")
+		return call("UNIX_SECONDS", "CURRENT_TIMESTAMP()"), end, true
+	case fn == "UNIX_TIMESTAMP" && len(args) == 1:
+		rt.apply("UNIX_TIMESTAMP to UNIX_SECONDS")
+		return call("UNIX_SECONDS", translated[0]), end, true
+	case fn == "FROM_UNIXTIME" && len(args) == 1:
+		rt.apply("FROM_UNIXTIME to TIMESTAMP_SECONDS")
+		return call("TIMESTAMP_SECONDS", translated[0]), end, true
+	}
+	return "", 0, false
+}
+
+// convertDateFormat converts the MySQL DATE_FORMAT format string literal to
+// the format of FORMAT_TIMESTAMP. Formats which aren't literals are kept. It
+// returns false if the format has a specifier without an equivalent.
+func convertDateFormat(format string) (string, bool) {
+	if len(format) < 2 || (format[0] != '\'' && format[0] != '"') {
+		return format, true
+	}
+	var sb strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] == '%' && i+1 < len(format) {
+			if untranslatedDateFormats[format[i+1]] {
+				return "", false
+			}
+			if to, ok := mysqlToSpannerDateFormat[format[i+1]]; ok {
+				sb.WriteString(to)
+			} else {
+				sb.WriteString(format[i : i+2])
+			}
+			i++
+			continue
+		}
+		sb.WriteByte(format[i])
+	}
+	return sb.String(), true
+}
+
+// queryInfoVisitor collects the tables, databases and functions referenced by
+// a query.
+type queryInfoVisitor struct {
+	tables          map[string]bool
+	databases       map[string]bool
+	functions       map[string]bool
+	selectForUpdate bool
+}
+
+func (v *queryInfoVisitor) Enter(in ast.Node) (ast.Node, bool) {
+	switch n := in.(type) {
+	case *ast.TableName:
+		v.tables[n.Name.O] = true
+		if n.Schema.O != "" {
+			v.databases[n.Schema.O] = true
+		}
+	case *ast.FuncCallExpr:
+		v.functions[strings.ToUpper(n.FnName.O)] = true
+	case *ast.AggregateFuncExpr:
+		v.functions[strings.ToUpper(n.F)] = true
+	case *ast.SelectStmt:
+		if n.LockInfo != nil && n.LockInfo.LockType != ast.SelectLockNone {
+			v.selectForUpdate = true
+		}
+	}
+	return in, false
+}
+
+func (v *queryInfoVisitor) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// TranslateQueryWithRules translates a MySQL query to the Spanner dialect
+// with deterministic rewrite rules, without Vertex AI: IFNULL to COALESCE,
+// LIMIT offset, count to LIMIT count OFFSET offset, the date and time
+// functions, and for the PostgreSQL dialect the quoting of identifiers and
+// strings. The query is parsed to check it and to find the tables and
+// functions it references. Queries which can't be parsed, or which call
+// functions without a rule or a Spanner equivalent, are returned with a
+// TranslationError so that they can be translated otherwise.
+func TranslateQueryWithRules(query, dialect string) QueryTranslationResult {
+	result := QueryTranslationResult{
+		OriginalQuery: query,
+		QueryType:     GetQueryType(query),
+		Complexity:    complexityComplex,
+	}
+	if dialect == "" {
+		dialect = constants.DIALECT_GOOGLESQL
+	}
+	stmt, err := parser.New().ParseOneStmt(query, "", "")
+	if err != nil {
+		result.TranslationError = fmt.Sprintf("could not parse the query: %v", err)
+		return result
+	}
+	v := &queryInfoVisitor{tables: map[string]bool{}, databases: map[string]bool{}, functions: map[string]bool{}}
+	stmt.Accept(v)
+	result.SourceTablesAffected = sortedKeys(v.tables)
+	result.DatabasesReferenced = sortedKeys(v.databases)
+	result.FunctionsUsed = sortedKeys(v.functions)
+	result.SelectForUpdate = v.selectForUpdate
+
+	var untranslated []string
+	for _, fn := range result.FunctionsUsed {
+		_, renamed := renamedFunctions[dialect][fn]
+		if !renamed && !SupportedFunctions[fn] && !(dialect == constants.DIALECT_GOOGLESQL && translatedFunctions[fn]) {
+			untranslated = append(untranslated, fn)
+		}
+	}
+	rt := &ruleTranslator{dialect: dialect, applied: map[string]bool{}, untranslated: map[string]bool{}}
+	result.SpannerQuery = rt.translate(tokenizeSQL(query))
+	rules := sortedKeys(rt.applied)
+	switch {
+	case len(untranslated) > 0:
+		result.TranslationError = fmt.Sprintf("no rule to translate the functions %s", strings.Join(untranslated, ", "))
+	case len(rt.untranslated) > 0:
+		result.TranslationError = fmt.Sprintf("no rule to translate the calls %s", strings.Join(sortedKeys(rt.untranslated), ", "))
+	case len(rules) == 0:
+		result.Complexity = complexitySimple
+		result.Explanation = "The query is compatible with Spanner."
+	default:
+		result.Complexity = complexityModerate
+		result.Explanation = fmt.Sprintf("Translated with rules: %s.", strings.Join(rules, "; "))
+	}
+	return result
+}
+
+// translatedFunctions are the functions whose arguments are rewritten by
+// translateCall for the GoogleSQL dialect.
+var translatedFunctions = map[string]bool{
+	"DATE_FORMAT":    true,
+	"DATEDIFF":       true,
+	"UNIX_TIMESTAMP": true,
+	"FROM_UNIXTIME":  true,
+}
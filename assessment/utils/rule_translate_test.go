@@ -0,0 +1,97 @@
+/* Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateQueryWithRules(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		dialect      string
+		spannerQuery string
+		complexity   string
+		err          string
+	}{
+		{
+			name:         "compatible query",
+			query:        "SELECT * FROM `users` WHERE id = ?",
+			dialect:      constants.DIALECT_GOOGLESQL,
+			spannerQuery: "SELECT * FROM `users` WHERE id = ?",
+			complexity:   "SIMPLE",
+		},
+		{
+			name:         "functions and limit",
+			query:        "SELECT IFNULL(name, 'n/a'), ucase(city) FROM users WHERE created > NOW() LIMIT 10, 20",
+			dialect:      constants.DIALECT_GOOGLESQL,
+			spannerQuery: "SELECT COALESCE(name, 'n/a'), UPPER(city) FROM users WHERE created > CURRENT_TIMESTAMP() LIMIT 20 OFFSET 10",
+			complexity:   "MODERATE",
+		},
+		{
+			name:         "date functions",
+			query:        "SELECT DATE_FORMAT(created, '%Y-%m-%d %H:%i:%s'), DATEDIFF(NOW(), created), UNIX_TIMESTAMP() FROM orders",
+			dialect:      constants.DIALECT_GOOGLESQL,
+			spannerQuery: "SELECT FORMAT_TIMESTAMP('%Y-%m-%d %H:%M:%S', created), DATE_DIFF(CURRENT_TIMESTAMP(), created, DAY), UNIX_SECONDS(CURRENT_TIMESTAMP()) FROM orders",
+			complexity:   "MODERATE",
+		},
+		{
+			name:         "date format with microseconds",
+			query:        "SELECT DATE_FORMAT(created, '%s.%f') FROM orders",
+			dialect:      constants.DIALECT_GOOGLESQL,
+			spannerQuery: "SELECT DATE_FORMAT(created, '%s.%f') FROM orders",
+			complexity:   "COMPLEX",
+			err:          "no rule to translate the calls DATE_FORMAT format '%s.%f'",
+		},
+		{
+			name:         "postgresql quoting",
+			query:        "SELECT `name` FROM `users` WHERE city = \"Paris\" AND IFNULL(age, 0) > 18",
+			dialect:      constants.DIALECT_POSTGRESQL,
+			spannerQuery: "SELECT \"name\" FROM \"users\" WHERE city = 'Paris' AND COALESCE(age, 0) > 18",
+			complexity:   "MODERATE",
+		},
+		{
+			name:         "function without rule",
+			query:        "SELECT FIND_IN_SET('a', tags) FROM posts",
+			dialect:      constants.DIALECT_GOOGLESQL,
+			spannerQuery: "SELECT FIND_IN_SET('a', tags) FROM posts",
+			complexity:   "COMPLEX",
+			err:          "no rule to translate the functions FIND_IN_SET",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := TranslateQueryWithRules(tc.query, tc.dialect)
+			assert.Equal(t, tc.spannerQuery, result.SpannerQuery)
+			assert.Equal(t, tc.complexity, result.Complexity)
+			assert.Equal(t, tc.err, result.TranslationError)
+			assert.Equal(t, tc.query, result.OriginalQuery)
+		})
+	}
+
+	result := TranslateQueryWithRules("SELECT id FROM shop.orders o JOIN items i ON o.id = i.order_id FOR UPDATE", constants.DIALECT_GOOGLESQL)
+	assert.Equal(t, []string{"items", "orders"}, result.SourceTablesAffected)
+	assert.Equal(t, []string{"shop"}, result.DatabasesReferenced)
+	assert.True(t, result.SelectForUpdate)
+	assert.Equal(t, "SELECT", result.QueryType)
+
+	result = TranslateQueryWithRules("SELEC id FROM orders", constants.DIALECT_GOOGLESQL)
+	assert.Contains(t, result.TranslationError, "could not parse the query")
+	assert.Empty(t, result.SpannerQuery)
+}
@@ -23,7 +23,9 @@ import (
 	"path/filepath"
 	"time"
 
+	spanneraccessor "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/spanner"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/assessment"
+	assessmentutils "github.com/GoogleCloudPlatform/spanner-migration-tool/assessment/utils"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/conversion"
@@ -66,7 +68,37 @@ func (cmd *AssessmentCmd) Usage() string {
 
 Run an assessment on the existing source db and create a report on the complexity of 
 performing a migration to Spanner. The configuration of the assessment collectors is
-provided in the assessment-profile
+provided in the assessment-profile. Set generateFgacDdl=true in the assessment-profile
+to also emit the source users, roles and grants as Spanner fine-grained access control DDL.
+The capacity report recommends the compute capacity of the Spanner instance for a bulk
+load within bulkLoadHours (default 24) and once the data is loaded. Set createInstance=true
+and instanceConfig in the assessment-profile to create the instance of the target-profile
+with the recommended bulk load capacity, if it doesn't exist.
+Set queryTranslation=rules in the assessment-profile to translate the queries with
+deterministic rewrite rules instead of Vertex AI, and llmFallback=true to translate
+the queries the rules can't handle with Vertex AI.
+The code is analyzed with the LLM backend set by llmBackend: vertexai (default), openai
+for an OpenAI compatible endpoint set by llmEndpoint and llmModel (e.g. a local model
+server, with the API key in llmApiKey or OPENAI_API_KEY), or none to skip the analysis
+of the code changes. Queries are translated with rules unless the backend is vertexai.
+Set llmTokenBudget (tokens) or llmCostBudget (USD) to bound the LLM usage: the largest
+files are skipped when the estimated prompts exceed the budget. llmPromptPricePerMillion
+and llmResponsePricePerMillion set the prices of the backend, required for a cost budget
+unless the backend is vertexai. The usage and cost are reported in llm_usage.csv.
+Set gitDiffRange (e.g. origin/main...HEAD) to only analyze the code files changed in
+the range and the files depending on them, e.g. on every pull request in CI, and
+previousAssessment to the app_code_assessment.json of an earlier assessment to merge
+its results for the other files.
+The LLM analyses of the files are cached in llmCacheDir (default: the user cache
+directory) and only redone when a file, its dependencies, the schemas or the prompts
+change. Set llmCache=false to disable the cache.
+Schema risks found in the application, e.g. lookups on case-insensitive columns, are
+added to the schema issues of the session file written next to the reports, with the
+JSON paths the queries filter or order rows by (MySQL JSON and PostgreSQL JSONB columns,
+MongoDB embedded documents). Loading the session in the web UI, generated columns and
+indexes can be added for these paths in bulk with ADD JSON PATH INDEXES.
+For PostgreSQL sources, the installed extensions and the Spanner equivalents of their
+features are reported in extensions.csv.
 The assessment flags are:
 `, path.Base(os.Args[0]))
 }
@@ -109,7 +141,7 @@ func (cmd *AssessmentCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...int
 		return subcommands.ExitSuccess
 	}
 
-	conv, sourceProfile, exitStatus := generateConv(cmd)
+	conv, sourceProfile, targetProfile, exitStatus := generateConv(cmd)
 	if conv == nil {
 		return exitStatus
 	}
@@ -134,6 +166,16 @@ func (cmd *AssessmentCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...int
 	}
 
 	assessment.GenerateReport(dbName, assessmentOutput)
+	// The session holds the schema issues found by the assessment of the
+	// application, to review them in the web UI.
+	conversion.WriteSessionFile(conv, "assessment_"+dbName+"/"+dbName+".session.json", os.Stdout)
+
+	if assessmentConfigMap["createInstance"] == "true" && !cmd.dryRun {
+		if err := createRecommendedInstance(ctx, targetProfile, assessmentConfigMap["instanceConfig"], assessmentOutput.CapacityAssessment); err != nil {
+			logger.Log.Error("could not create the Spanner instance", zap.Error(err))
+			return subcommands.ExitFailure
+		}
+	}
 
 	// Follow up if required - save assessment report
 	// Cleanup smt tmp data directory.
@@ -141,11 +183,11 @@ func (cmd *AssessmentCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...int
 	return subcommands.ExitSuccess
 }
 
-func generateConv(cmd *AssessmentCmd) (*internal.Conv, profiles.SourceProfile, subcommands.ExitStatus) {
+func generateConv(cmd *AssessmentCmd) (*internal.Conv, profiles.SourceProfile, profiles.TargetProfile, subcommands.ExitStatus) {
 	sourceProfile, targetProfile, ioHelper, _, err := PrepareMigrationPrerequisites(cmd.sourceProfile, cmd.targetProfile, cmd.source, cmd.dryRun)
 	if err != nil {
 		err = fmt.Errorf("error while preparing prerequisites for migration: %v", err)
-		return nil, profiles.SourceProfile{}, subcommands.ExitUsageError
+		return nil, profiles.SourceProfile{}, profiles.TargetProfile{}, subcommands.ExitUsageError
 	}
 
 	var conv *internal.Conv
@@ -156,7 +198,7 @@ func generateConv(cmd *AssessmentCmd) (*internal.Conv, profiles.SourceProfile, s
 		conv = internal.MakeConv()
 		err = conversion.ReadSessionFile(conv, cmd.sessionJSON)
 		if err != nil {
-			return nil, profiles.SourceProfile{}, subcommands.ExitFailure
+			return nil, profiles.SourceProfile{}, profiles.TargetProfile{}, subcommands.ExitFailure
 		}
 		expressionVerificationAccessor, _ := expressions_api.NewExpressionVerificationAccessorImpl(context.Background(), targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance)
 		schemaToSpanner := common.SchemaToSpannerImpl{
@@ -165,28 +207,58 @@ func generateConv(cmd *AssessmentCmd) (*internal.Conv, profiles.SourceProfile, s
 		err := schemaToSpanner.VerifyExpressions(conv)
 
 		if err != nil {
-			return nil, profiles.SourceProfile{}, subcommands.ExitFailure
+			return nil, profiles.SourceProfile{}, profiles.TargetProfile{}, subcommands.ExitFailure
 		}
 	} else {
 		ctx := context.Background()
 		ddlVerifier, err := expressions_api.NewDDLVerifierImpl(ctx, "", "")
 		if err != nil {
 			logger.Log.Error(fmt.Sprintf("error trying create ddl verifier: %v", err))
-			return nil, profiles.SourceProfile{}, subcommands.ExitFailure
+			return nil, profiles.SourceProfile{}, profiles.TargetProfile{}, subcommands.ExitFailure
 		}
 		sfs := &conversion.SchemaFromSourceImpl{
 			DdlVerifier: ddlVerifier,
 		}
 		conv, err = convImpl.SchemaConv(cmd.project, sourceProfile, targetProfile, &ioHelper, sfs)
 		if err != nil {
-			return nil, profiles.SourceProfile{}, subcommands.ExitFailure
+			return nil, profiles.SourceProfile{}, profiles.TargetProfile{}, subcommands.ExitFailure
 		}
 	}
 	if conv == nil {
 		logger.Log.Error("Could not initialize conversion context")
-		return nil, profiles.SourceProfile{}, subcommands.ExitFailure
+		return nil, profiles.SourceProfile{}, profiles.TargetProfile{}, subcommands.ExitFailure
 	}
 
 	logger.Log.Info("completed creation on source and spanner schema")
-	return conv, sourceProfile, 0
+	return conv, sourceProfile, targetProfile, 0
+}
+
+// createRecommendedInstance creates the instance of the target profile with the
+// compute capacity recommended for the bulk load, unless it already exists.
+func createRecommendedInstance(ctx context.Context, targetProfile profiles.TargetProfile, instanceConfig string, capacity *assessmentutils.CapacityAssessmentOutput) error {
+	if capacity == nil {
+		return fmt.Errorf("no compute capacity was recommended for the source database")
+	}
+	if instanceConfig == "" {
+		return fmt.Errorf("specify the instanceConfig of the instance to create in the assessment-profile, e.g. instanceConfig=regional-us-central1")
+	}
+	project, instance := targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance
+	if project == "" || instance == "" {
+		return fmt.Errorf("specify the project and instance to create in the target-profile")
+	}
+	spA, err := spanneraccessor.NewSpannerAccessorClientImpl(ctx)
+	if err != nil {
+		return err
+	}
+	created, err := spA.CreateInstanceIfNotExists(ctx, project, instance, instanceConfig, capacity.BulkLoadProcessingUnits)
+	if err != nil {
+		return err
+	}
+	if created {
+		logger.Log.Info(fmt.Sprintf("created Spanner instance %s with %d processing units for the bulk load, scale it down to %d processing units once the data migration is complete",
+			instance, capacity.BulkLoadProcessingUnits, capacity.SteadyStateProcessingUnits))
+	} else {
+		logger.Log.Info(fmt.Sprintf("Spanner instance %s already exists, its compute capacity is left unchanged", instance))
+	}
+	return nil
 }
@@ -0,0 +1,239 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/conversion"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/streaming"
+	"github.com/google/subcommands"
+	"go.uber.org/zap"
+)
+
+// CutoverCmd is the command for cutting over a minimal downtime migration once
+// replication has caught up and the validation queries agree.
+type CutoverCmd struct {
+	jobId                string
+	dataShardIds         string
+	source               string
+	sourceProfile        string
+	targetProfile        string
+	maxLagSeconds        int64
+	validationQueries    string
+	pauseWritesStatement string
+	flagFile             string
+	reportFile           string
+	dryRun               bool
+	logLevel             string
+	validate             bool
+}
+
+// Name returns the name of operation.
+func (cmd *CutoverCmd) Name() string {
+	return "cutover"
+}
+
+// Synopsis returns summary of operation.
+func (cmd *CutoverCmd) Synopsis() string {
+	return "cutover checks replication lag and validation queries for a jobId before pausing source writes"
+}
+
+// Usage returns usage info of the command.
+func (cmd *CutoverCmd) Usage() string {
+	return fmt.Sprintf(`%v cutover --jobId=[jobId] --target-profile=... --max-lag-seconds=30 --validation-queries=[file] ...
+
+Checks that the Datastream lag of every stream of a minimal downtime migration job
+is within --max-lag-seconds and that each validation query returns the same value
+on the source and on Spanner, numbers being compared by value and timestamps by
+instant. Only when every gate passes are source writes paused
+with --pause-writes-statement and the --flag-file written. Every attempt is recorded
+in a JSON cutover report.
+
+The validation queries file is a JSON array such as:
+  [{"name": "orders", "sourceQuery": "SELECT COUNT(*) FROM orders", "targetQuery": "SELECT COUNT(*) FROM orders"}]
+`, path.Base(os.Args[0]))
+}
+
+// SetFlags sets the flags.
+func (cmd *CutoverCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&cmd.jobId, "jobId", "", "Flag for specifying the migration jobId")
+	f.StringVar(&cmd.dataShardIds, "dataShardIds", "", "Flag for specifying a comma separated list of dataShardIds to check. Defaults to ALL shards. Optional flag, and only valid for a sharded migration.")
+	f.StringVar(&cmd.source, "source", "", "Flag for specifying source DB, (e.g., `PostgreSQL`, `MySQL`). Required with --validation-queries or --pause-writes-statement, which support MySQL, PostgreSQL, SQL Server and Oracle sources.")
+	f.StringVar(&cmd.sourceProfile, "source-profile", "", "Flag for specifying connection profile for source database e.g., \"host=localhost,port=3306,user=root,password=pwd,dbName=db\"")
+	f.StringVar(&cmd.targetProfile, "target-profile", "", "Flag for specifying project, instance and dbName of Spanner e.g., \"project=XYZ,instance=ABC,dbName=DB\"")
+	f.Int64Var(&cmd.maxLagSeconds, "max-lag-seconds", 30, "Maximum Datastream lag in seconds allowed for the cutover to proceed, defaults to 30")
+	f.StringVar(&cmd.validationQueries, "validation-queries", "", "Path to a JSON file of validation queries to run against the source and Spanner")
+	f.StringVar(&cmd.pauseWritesStatement, "pause-writes-statement", "", "Statement run on the source to pause writes once all gates pass e.g., \"SET GLOBAL read_only = ON\"")
+	f.StringVar(&cmd.flagFile, "flag-file", "", "File written once all gates pass, for applications to switch over to Spanner")
+	f.StringVar(&cmd.reportFile, "report-file", "", "Path of the cutover report, defaults to cutover_<jobId>.json")
+	f.BoolVar(&cmd.dryRun, "dry-run", false, "Flag for checking the gates without pausing writes or writing the flag file")
+	f.StringVar(&cmd.logLevel, "log-level", "DEBUG", "Configure the logging level for the command (INFO, DEBUG), defaults to DEBUG")
+	f.BoolVar(&cmd.validate, "validate", false, "Flag for validating if all the required input parameters are present")
+}
+
+func (cmd *CutoverCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	err := logger.InitializeLogger(cmd.logLevel)
+	if err != nil {
+		logger.Log.Info(fmt.Sprint("Error initialising logger, did you specify a valid log-level? [DEBUG, INFO, WARN, ERROR, FATAL]", err))
+		return subcommands.ExitFailure
+	}
+	if cmd.jobId == "" {
+		logger.Log.Error("jobId must be specified for cutover\n")
+		return subcommands.ExitUsageError
+	}
+	targetProfile, err := profiles.NewTargetProfile(cmd.targetProfile, false)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Target profile is not properly configured, this is needed for SMT to lookup job details in the metadata database: %v\n", err))
+		return subcommands.ExitFailure
+	}
+	project, instance, err := streaming.GetInstanceDetails(ctx, targetProfile)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("can't get resource ids: %v\n", err))
+		return subcommands.ExitFailure
+	}
+	dataShardIds, err := profiles.ParseList(cmd.dataShardIds)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Could not parse datashardIds: %v\n", err))
+		return subcommands.ExitFailure
+	}
+	var queries []streaming.CutoverValidationQuery
+	if cmd.validationQueries != "" {
+		queries, err = streaming.ReadCutoverValidationQueries(cmd.validationQueries)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("%v\n", err))
+			return subcommands.ExitFailure
+		}
+		if targetProfile.Conn.Sp.Dbname == "" {
+			logger.Log.Error("dbName must be specified in the target profile to run validation queries\n")
+			return subcommands.ExitUsageError
+		}
+	}
+	needsSource := len(queries) > 0 || cmd.pauseWritesStatement != ""
+	var sourceProfile profiles.SourceProfile
+	if needsSource {
+		sourceProfile, err = profiles.NewSourceProfile(cmd.sourceProfile, cmd.source, &profiles.NewSourceProfileImpl{})
+		if err == nil {
+			sourceProfile.Driver, err = sourceProfile.ToLegacyDriver(cmd.source)
+		}
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("Source profile is not properly configured, this is needed to run validation queries and pause writes: %v\n", err))
+			return subcommands.ExitFailure
+		}
+		switch sourceProfile.Driver {
+		case constants.MYSQL, constants.POSTGRES, constants.SQLSERVER, constants.ORACLE:
+		default:
+			logger.Log.Error(fmt.Sprintf("--validation-queries and --pause-writes-statement are only supported for SQL sources, not %s\n", cmd.source))
+			return subcommands.ExitUsageError
+		}
+	}
+	// all input parameters have been validated
+	if cmd.validate {
+		logger.Log.Info("All required parameters are present, validated that the command is syntactically correct.\n")
+		return subcommands.ExitSuccess
+	}
+
+	getInfo := &utils.GetUtilInfoImpl{}
+	migrationProjectId, err := getInfo.GetProject()
+	if err != nil {
+		logger.Log.Error("Could not get project id from gcloud environment. Inferring migration project id from target profile.", zap.Error(err))
+		migrationProjectId = project
+	}
+	streams, err := streaming.FetchDatastreamResources(ctx, cmd.jobId, dataShardIds, project, instance)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Unable to fetch datastream resources for jobId: %s: %v\n", cmd.jobId, err))
+		return subcommands.ExitFailure
+	}
+	deps := streaming.CutoverDeps{
+		StreamLag: func(ctx context.Context, stream internal.DatastreamResources) (time.Duration, error) {
+			return streaming.GetDatastreamLag(ctx, migrationProjectId, stream)
+		},
+	}
+	if needsSource {
+		connectionConfig, err := conversion.ConnectionConfig(sourceProfile)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("can't get source connection config: %v\n", err))
+			return subcommands.ExitFailure
+		}
+		connectionStr, ok := connectionConfig.(string)
+		if !ok {
+			logger.Log.Error(fmt.Sprintf("can't connect to source %s with SQL\n", cmd.source))
+			return subcommands.ExitFailure
+		}
+		db, err := sql.Open(sourceProfile.Driver, connectionStr)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("failed to connect to source database: %v\n", err))
+			return subcommands.ExitFailure
+		}
+		defer db.Close()
+		deps.SourceQuery = func(ctx context.Context, query string) (interface{}, error) {
+			return streaming.QuerySourceValue(ctx, db, query)
+		}
+		deps.SourceExec = func(ctx context.Context, stmt string) error {
+			_, err := db.ExecContext(ctx, stmt)
+			return err
+		}
+	}
+	if len(queries) > 0 {
+		dbURI := fmt.Sprintf("projects/%s/instances/%s/databases/%s", project, instance, targetProfile.Conn.Sp.Dbname)
+		client, err := utils.GetClient(ctx, dbURI)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("can't create client for db %s: %v\n", dbURI, err))
+			return subcommands.ExitFailure
+		}
+		defer client.Close()
+		deps.TargetQuery = func(ctx context.Context, query string) (interface{}, error) {
+			return streaming.QuerySpannerValue(ctx, client, query)
+		}
+	}
+
+	logger.Log.Info(fmt.Sprintf("Initiating cutover for jobId: %v \n", cmd.jobId))
+	opts := streaming.CutoverOptions{
+		MaxLag:               time.Duration(cmd.maxLagSeconds) * time.Second,
+		ValidationQueries:    queries,
+		PauseWritesStatement: cmd.pauseWritesStatement,
+		FlagFile:             cmd.flagFile,
+		DryRun:               cmd.dryRun,
+	}
+	report := streaming.RunCutover(ctx, cmd.jobId, streams, opts, deps)
+	reportFile := cmd.reportFile
+	if reportFile == "" {
+		reportFile = fmt.Sprintf("cutover_%s.json", cmd.jobId)
+	}
+	if err := streaming.WriteCutoverReport(report, reportFile); err != nil {
+		logger.Log.Error(fmt.Sprintf("%v\n", err))
+		return subcommands.ExitFailure
+	}
+	logger.Log.Info(fmt.Sprintf("Wrote cutover report to %s\n", reportFile))
+	if report.Error != "" {
+		logger.Log.Error(fmt.Sprintf("Cutover for jobId %s did not complete: %s\n", cmd.jobId, report.Error))
+		return subcommands.ExitFailure
+	}
+	if report.CutoverPerformed {
+		logger.Log.Info(fmt.Sprintf("Cutover for jobId %s completed\n", cmd.jobId))
+	}
+	return subcommands.ExitSuccess
+}
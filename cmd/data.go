@@ -28,6 +28,8 @@ import (
 	database "cloud.google.com/go/spanner/admin/database/apiv1"
 	spanneraccessor "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/spanner"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/export"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/status"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/conversion"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
@@ -41,19 +43,34 @@ import (
 
 // DataCmd struct with flags.
 type DataCmd struct {
-	source           string
-	sourceProfile    string
-	target           string
-	targetProfile    string
-	sessionJSON      string
-	filePrefix       string // TODO: move filePrefix to global flags
-	project          string
-	WriteLimit       int64
-	dryRun           bool
-	logLevel         string
-	SkipForeignKeys  bool
-	validate         bool
-	dataflowTemplate string
+	source               string
+	sourceProfile        string
+	target               string
+	targetProfile        string
+	sessionJSON          string
+	filePrefix           string // TODO: move filePrefix to global flags
+	project              string
+	WriteLimit           int64
+	dryRun               bool
+	logLevel             string
+	SkipForeignKeys      bool
+	validate             bool
+	dataflowTemplate     string
+	deadLetter           string
+	tableReadParallelism int
+	includeTables        string
+	excludeTables        string
+	tableOrder           string
+	recordRun            bool
+	config               string
+	reloadTables         string
+	reloadMode           string
+	exportURI            string
+	exportFormat         string
+	statusPort           int
+	statusAddress        string
+	maskingProfile       string
+	sample               string
 }
 
 // Name returns the name of operation.
@@ -92,9 +109,29 @@ func (cmd *DataCmd) SetFlags(f *flag.FlagSet) {
 	f.BoolVar(&cmd.SkipForeignKeys, "skip-foreign-keys", false, "Skip creating foreign keys after data migration is complete (ddl statements for foreign keys can still be found in the downloaded schema.ddl.txt file and the same can be applied separately)")
 	f.BoolVar(&cmd.validate, "validate", false, "Flag for validating if all the required input parameters are present")
 	f.StringVar(&cmd.dataflowTemplate, "dataflow-template", constants.DEFAULT_TEMPLATE_PATH, "GCS path of the Dataflow template")
+	f.StringVar(&cmd.deadLetter, "dead-letter", "", "Optional. Writes every bad row to a local file, a GCS object (gs://bucket/object) or a BigQuery table (bq://project.dataset.table)")
+	f.StringVar(&cmd.includeTables, "include-tables", "", "Optional. Regular expression matching the whole names of the source tables to migrate, e.g. \"orders|order_items\"; other tables are skipped")
+	f.StringVar(&cmd.excludeTables, "exclude-tables", "", "Optional. Regular expression matching the whole names of the source tables to skip, e.g. \"tmp_.*\"")
+	f.StringVar(&cmd.tableOrder, "table-order", "", "Optional. Comma separated priority classes of tables whose data is migrated first, each a regular expression matching whole source table names, e.g. \"countries|currencies,customers\"; other tables are migrated last")
+	f.BoolVar(&cmd.recordRun, "record-run", false, "Optional. Records the migration run, with its source, target, duration, row counts and session file, in the SMT_MIGRATION_RUN table of the spannermigrationtool_metadata database")
+	f.IntVar(&cmd.tableReadParallelism, "table-read-parallelism", 1, "Number of workers reading each large MySQL or PostgreSQL table by primary key range, or the data files of a mydumper export, defaults to 1")
+	f.StringVar(&cmd.config, "config", "", "Optional. YAML profile file with the source, target and orchestration settings of the migration, overridden by the flags passed")
+	f.StringVar(&cmd.reloadTables, "reload-tables", "", "Optional. Comma separated names of the Spanner tables emptied before their data is migrated again, or \"*\" for all the tables of the session. The tables interleaved in them are reloaded as well")
+	f.StringVar(&cmd.exportURI, "export-uri", "", "Optional. Writes the converted rows to files under a GCS path (gs://bucket/path) or a local directory, in a directory per table, or to BigQuery staging tables of a dataset (bq://project.dataset), instead of writing them to Spanner")
+	f.StringVar(&cmd.exportFormat, "export-format", export.FormatCSV, fmt.Sprintf("Optional. Format of the files of --export-uri: %s or %s, defaults to %s", export.FormatCSV, export.FormatAvro, export.FormatCSV))
+	f.IntVar(&cmd.statusPort, "status-port", 0, "Optional. Port of an HTTP server reporting the progress of the migration as JSON on /status, e.g. the rows written and errors of each table and the estimated time left")
+	f.StringVar(&cmd.statusAddress, "status-address", status.DefaultAddress, "Optional. Address the server of --status-port listens on, e.g. 0.0.0.0 to let other hosts read the progress")
+	f.StringVar(&cmd.maskingProfile, "masking-profile", "", "Optional. JSON file with the strategies masking columns during data migration (hash, shuffle, synthetic or nullify), e.g. to populate a staging database without personal data. The key of the hashes is read from the "+internal.MaskingKeyEnv+" environment variable, a random key is used by default")
+	f.StringVar(&cmd.sample, "sample", "", "Optional. Migrates a sample of the data to quickly produce a small but referentially consistent database, e.g. for application testing: a percentage of the rows, e.g. 1%, or a number of rows per table, e.g. 10k. Rows referencing other rows, through foreign keys or interleaving, are migrated only if the rows they reference are")
+	f.StringVar(&cmd.reloadMode, "reload-mode", constants.RELOAD_DELETE, fmt.Sprintf("Optional. How the tables of --reload-tables are emptied: %s deletes their rows with partitioned DML, %s drops and recreates them, defaults to %s", constants.RELOAD_DELETE, constants.RELOAD_RECREATE, constants.RELOAD_DELETE))
 }
 
 func (cmd *DataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	// Flags which aren't passed are set from the profile file, if any.
+	if err := applyProfileFile(f, cmd.config); err != nil {
+		logger.Log.Error(fmt.Sprintf("Error applying the profile file: %v", err))
+		return subcommands.ExitUsageError
+	}
 	// Cleanup smt tmp data directory in case residuals remain from prev runs.
 	os.RemoveAll(filepath.Join(os.TempDir(), constants.SMT_TMP_DIR))
 	var err error
@@ -118,6 +155,46 @@ func (cmd *DataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface
 		err = fmt.Errorf("error while preparing prerequisites for migration: %v", err)
 		return subcommands.ExitUsageError
 	}
+	sourceProfile.IncludeTables, sourceProfile.ExcludeTables = cmd.includeTables, cmd.excludeTables
+	tableFilter, err := internal.NewTableFilter(cmd.includeTables, cmd.excludeTables)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	tableOrder, err := internal.NewTableOrder(cmd.tableOrder)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	sample, err := internal.ParseDataSample(cmd.sample)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	if cmd.reloadMode != constants.RELOAD_DELETE && cmd.reloadMode != constants.RELOAD_RECREATE {
+		err = fmt.Errorf("invalid value for --reload-mode: %s, expected %s or %s", cmd.reloadMode, constants.RELOAD_DELETE, constants.RELOAD_RECREATE)
+		return subcommands.ExitUsageError
+	}
+	if cmd.reloadTables != "" && sourceProfile.UseTargetSchema() {
+		err = fmt.Errorf("--reload-tables requires a session file")
+		return subcommands.ExitUsageError
+	}
+	if cmd.exportURI != "" {
+		if !export.IsValidFormat(cmd.exportFormat) {
+			err = fmt.Errorf("invalid value for --export-format: %s, expected %s or %s", cmd.exportFormat, export.FormatCSV, export.FormatAvro)
+			return subcommands.ExitUsageError
+		}
+		if sourceProfile.Driver == constants.CSV || sourceProfile.Ty == profiles.SourceProfileTypeConfig || sourceProfile.UseTargetSchema() {
+			err = fmt.Errorf("--export-uri requires a session file and a dump file or a direct connection to the source database")
+			return subcommands.ExitUsageError
+		}
+		if cmd.reloadTables != "" {
+			err = fmt.Errorf("--export-uri and --reload-tables can't be used together")
+			return subcommands.ExitUsageError
+		}
+	}
+	if sourceProfile.IsSeparateMultiDatabase() {
+		// Each source database has its own session file, so data must be migrated one database at a time.
+		err = fmt.Errorf("the data subcommand migrates a single database, specify one dbName or use schema-and-data to migrate several databases")
+		return subcommands.ExitUsageError
+	}
 	if cmd.project == "" {
 		getInfo := &utils.GetUtilInfoImpl{}
 		cmd.project, err = getInfo.GetProject()
@@ -156,10 +233,59 @@ func (cmd *DataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface
 		}
 	}
 
+	if err = applyMaskingProfile(conv, cmd.maskingProfile); err != nil {
+		return subcommands.ExitUsageError
+	}
+	if conv.HasColumnMasks() && migratedByDataflow(sourceProfile) {
+		err = fmt.Errorf("column masks only apply to bulk migrations, remove them from the session file and --masking-profile to migrate with Dataflow")
+		return subcommands.ExitUsageError
+	}
+	if sample.Enabled() && migratedByDataflow(sourceProfile) {
+		err = fmt.Errorf("--sample only applies to bulk migrations, it can't be used with Dataflow migrations")
+		return subcommands.ExitUsageError
+	}
+
+	conv.TableReadParallelism = cmd.tableReadParallelism
+	conv.TableOrder = tableOrder
+	conv.SetDataSample(sample)
+	conv.TableFilter = tableFilter
+	var stopStatusServer func()
+	conv.Status, stopStatusServer, err = startStatusServer(cmd.statusAddress, cmd.statusPort)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	defer stopStatusServer()
+	var stopInterrupt func()
+	conv.Ctx, stopInterrupt = interruptContext(ctx)
+	defer stopInterrupt()
+	closeDeadLetter, err := openDeadLetter(ctx, conv, cmd.deadLetter)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	defer closeDeadLetter()
+
 	var (
 		dbURI string
 	)
-	if !cmd.dryRun {
+	if cmd.exportURI != "" && !cmd.dryRun {
+		// The converted rows are written to files: no Spanner database is
+		// created or written to.
+		var closeExport func() error
+		closeExport, err = openExport(ctx, conv, cmd.exportURI, cmd.exportFormat)
+		if err != nil {
+			return subcommands.ExitUsageError
+		}
+		convImpl := &conversion.ConvImpl{}
+		bw, err = convImpl.DataConv(ctx, cmd.project, sourceProfile, targetProfile, &ioHelper, nil, conv, true, cmd.WriteLimit, &conversion.DataFromSourceImpl{})
+		if cerr := closeExport(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			err = fmt.Errorf("can't finish data export for db %s: %v", dbName, err)
+			return subcommands.ExitFailure
+		}
+		banner = utils.GetBanner(dataCoversionStartTime, cmd.exportURI)
+	} else if !cmd.dryRun {
 		now := time.Now()
 		bw, err = MigrateDatabase(ctx, cmd.project, targetProfile, sourceProfile, dbName, &ioHelper, cmd, conv, nil)
 		if err != nil {
@@ -197,8 +323,15 @@ func (cmd *DataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface
 	reportImpl := conversion.ReportImpl{}
 	reportImpl.GenerateReport(sourceProfile.Driver, bw.DroppedRowsByTable(), ioHelper.BytesRead, banner, conv, cmd.filePrefix, dbName, ioHelper.Out)
 	conversion.WriteBadData(bw, conv, banner, cmd.filePrefix+badDataFile, ioHelper.Out)
+	conversion.WriteCheckpoint(conv, cmd.filePrefix+checkpointFile, ioHelper.Out)
+	if cmd.recordRun && !cmd.dryRun && cmd.exportURI == "" {
+		recordMigrationRun(ctx, conv, sourceProfile, targetProfile, cmd.sessionJSON, dataCoversionStartTime)
+	}
 	// Cleanup smt tmp data directory.
 	os.RemoveAll(filepath.Join(os.TempDir(), constants.SMT_TMP_DIR))
+	if conv.Interrupted() {
+		return subcommands.ExitFailure
+	}
 	return subcommands.ExitSuccess
 }
 
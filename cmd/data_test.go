@@ -19,6 +19,8 @@ import (
         "testing"
 
         "github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+        "github.com/GoogleCloudPlatform/spanner-migration-tool/common/export"
+        "github.com/GoogleCloudPlatform/spanner-migration-tool/common/status"
         "github.com/stretchr/testify/assert"
 )
 
@@ -43,6 +45,10 @@ func TestDataSetFlags(t *testing.T) {
                                 SkipForeignKeys:  false,
                                 validate:         false,
                                 dataflowTemplate: constants.DEFAULT_TEMPLATE_PATH,
+                                exportFormat: export.FormatCSV,
+                                reloadMode: constants.RELOAD_DELETE,
+                                tableReadParallelism: 1,
+                                statusAddress: status.DefaultAddress,
                         },
                 },
                 {
@@ -60,6 +66,10 @@ func TestDataSetFlags(t *testing.T) {
                                 SkipForeignKeys:  false,
                                 validate:         false,
                                 dataflowTemplate: constants.DEFAULT_TEMPLATE_PATH,
+                                exportFormat: export.FormatCSV,
+                                reloadMode: constants.RELOAD_DELETE,
+                                tableReadParallelism: 1,
+                                statusAddress: status.DefaultAddress,
                         },
                 },
                 {
@@ -77,6 +87,10 @@ func TestDataSetFlags(t *testing.T) {
                                 SkipForeignKeys:  false,
                                 validate:         false,
                                 dataflowTemplate: constants.DEFAULT_TEMPLATE_PATH,
+                                exportFormat: export.FormatCSV,
+                                reloadMode: constants.RELOAD_DELETE,
+                                tableReadParallelism: 1,
+                                statusAddress: status.DefaultAddress,
                         },
                 },
                 {
@@ -94,6 +108,10 @@ func TestDataSetFlags(t *testing.T) {
                                 SkipForeignKeys:  false,
                                 validate:         false,
                                 dataflowTemplate: constants.DEFAULT_TEMPLATE_PATH,
+                                exportFormat: export.FormatCSV,
+                                reloadMode: constants.RELOAD_DELETE,
+                                tableReadParallelism: 1,
+                                statusAddress: status.DefaultAddress,
                         },
                 },
                 {
@@ -111,6 +129,10 @@ func TestDataSetFlags(t *testing.T) {
                                 SkipForeignKeys:  false,
                                 validate:         false,
                                 dataflowTemplate: constants.DEFAULT_TEMPLATE_PATH,
+                                exportFormat: export.FormatCSV,
+                                reloadMode: constants.RELOAD_DELETE,
+                                tableReadParallelism: 1,
+                                statusAddress: status.DefaultAddress,
                         },
                 },
                 {
@@ -128,6 +150,10 @@ func TestDataSetFlags(t *testing.T) {
                                 SkipForeignKeys:  true,
                                 validate:         true,
                                 dataflowTemplate: constants.DEFAULT_TEMPLATE_PATH,
+                                exportFormat: export.FormatCSV,
+                                reloadMode: constants.RELOAD_DELETE,
+                                tableReadParallelism: 1,
+                                statusAddress: status.DefaultAddress,
                         },
                 },
                 {
@@ -145,6 +171,10 @@ func TestDataSetFlags(t *testing.T) {
                                 SkipForeignKeys:  false,
                                 validate:         false,
                                 dataflowTemplate: "gs://my-bucket/my-template",
+                                exportFormat: export.FormatCSV,
+                                reloadMode: constants.RELOAD_DELETE,
+                                tableReadParallelism: 1,
+                                statusAddress: status.DefaultAddress,
                         },
                 },
                 {
@@ -174,6 +204,10 @@ func TestDataSetFlags(t *testing.T) {
                                 SkipForeignKeys:  true,
                                 validate:         true,
                                 dataflowTemplate: "gs://custom/template",
+                                exportFormat: export.FormatCSV,
+                                reloadMode: constants.RELOAD_DELETE,
+                                tableReadParallelism: 1,
+                                statusAddress: status.DefaultAddress,
                         },
                 },
         }
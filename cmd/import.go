@@ -18,10 +18,12 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"time"
 	"unicode"
@@ -40,6 +42,11 @@ import (
 	"github.com/google/subcommands"
 )
 
+var (
+	kmsKeyNameRegex             = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+$`)
+	versionRetentionPeriodRegex = regexp.MustCompile(`^[0-9]+[smhd]$`)
+)
+
 type ImportDataCmd struct {
 	instance          string
 	database          string
@@ -52,6 +59,15 @@ type ImportDataCmd struct {
 	project           string
 	databaseDialect   string
 	logLevel          string
+	// inferSchema infers the schema of a csv or jsonl file from a sample of its
+	// rows instead of reading it from schemaUri.
+	inferSchema          bool
+	inferSampleRows      int
+	inferredSchemaOutput string
+	// Options of the database created when it doesn't exist.
+	kmsKeyName             string
+	versionRetentionPeriod string
+	defaultLeader          string
 }
 
 func (cmd *ImportDataCmd) SetFlags(set *flag.FlagSet) {
@@ -59,13 +75,19 @@ func (cmd *ImportDataCmd) SetFlags(set *flag.FlagSet) {
 	set.StringVar(&cmd.database, "database", "", "Spanner database name. If one with the specified name does not exist, a new one will be created with the same")
 	set.StringVar(&cmd.tableName, "table-name", "", "Spanner table name. Optional. If not specified, source-uri name will be used")
 	set.StringVar(&cmd.sourceUri, "source-uri", "", "URI of the file to import")
-	set.StringVar(&cmd.sourceFormat, "source-format", "", fmt.Sprintf("Format of the file to import. Valid values {%s, %s, %s}", constants.MYSQLDUMP, constants.PGDUMP, constants.CSV))
-	set.StringVar(&cmd.schemaUri, "schema-uri", "", "URI of the file with schema for the csv to import. Only non-optional for csv format.")
+	set.StringVar(&cmd.sourceFormat, "source-format", "", fmt.Sprintf("Format of the file to import. Valid values {%s, %s, %s, %s}", constants.MYSQLDUMP, constants.PGDUMP, constants.CSV, constants.JSONL))
+	set.StringVar(&cmd.schemaUri, "schema-uri", "", "URI of the file with schema for the csv or jsonl file to import. Only non-optional for csv and jsonl formats.")
 	set.StringVar(&cmd.csvLineDelimiter, "csv-line-delimiter", "\n", "Token to be used as line delimiter for csv format. Optional. Defaults to '\\n'. Only used for csv format.")
 	set.StringVar(&cmd.csvFieldDelimiter, "csv-field-delimiter", ",", "Token to be used as field delimiter for csv format. Optional. Defaults to ','. Only used for csv format.")
 	set.StringVar(&cmd.project, "project", "", "Project id for all resources related to this import. Optional")
 	set.StringVar(&cmd.databaseDialect, "database-dialect", constants.DIALECT_GOOGLESQL, fmt.Sprintf("Spanner database dialect. Defaults to %s. Valid values {%s, %s}", constants.DIALECT_GOOGLESQL, constants.DIALECT_GOOGLESQL, constants.DIALECT_POSTGRESQL))
 	set.StringVar(&cmd.logLevel, "log-level", "INFO", "Configure the logging level for the command (INFO, DEBUG), defaults to DEBUG")
+	set.BoolVar(&cmd.inferSchema, "infer-schema", false, "Infer the schema of the file to import from a sample of its rows, instead of reading it from --schema-uri. For csv format, the first row must be a header row. Optional. Only used for csv and jsonl formats.")
+	set.IntVar(&cmd.inferSampleRows, "infer-sample-rows", 1000, "Number of rows sampled to infer the schema of the file to import. Optional. Defaults to 1000. Only used with --infer-schema.")
+	set.StringVar(&cmd.kmsKeyName, "kms-key-name", "", "Cloud KMS key used to encrypt the database when it is created, in the form projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>. Optional. Defaults to Google-managed encryption.")
+	set.StringVar(&cmd.versionRetentionPeriod, "version-retention-period", "", "Version retention period of the database when it is created, e.g. 7d. Optional. Defaults to 1h.")
+	set.StringVar(&cmd.defaultLeader, "default-leader", "", "Default leader region of the database when it is created, for multi-region instances. Optional. Defaults to the default leader of the instance configuration.")
+	set.StringVar(&cmd.inferredSchemaOutput, "inferred-schema-output", "", "Path of a file to write the inferred schema to, in the --schema-uri format, for review. Nothing is imported when set. Optional. Only used with --infer-schema.")
 }
 
 func (cmd *ImportDataCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...interface{}) subcommands.ExitStatus {
@@ -82,6 +104,15 @@ func (cmd *ImportDataCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...
 		return subcommands.ExitFailure
 	}
 
+	if cmd.inferredSchemaOutput != "" {
+		err = cmd.writeInferredSchema(ctx)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("Unable to infer schema %v", err))
+			return subcommands.ExitFailure
+		}
+		return subcommands.ExitSuccess
+	}
+
 	dialect := getDialectWithDefaults(cmd.databaseDialect)
 	dbURI := getDBUri(cmd.project, cmd.instance, cmd.database)
 
@@ -91,7 +122,7 @@ func (cmd *ImportDataCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...
 		return subcommands.ExitFailure
 	}
 
-	err = createDatabase(ctx, dbURI, dialect, spannerAccessor)
+	err = createDatabase(ctx, dbURI, dialect, spannerAccessor, cmd.databaseOptions())
 	if err != nil {
 		logger.Log.Error(fmt.Sprintf("Failed to create database. Reason %v", err))
 		return subcommands.ExitFailure
@@ -107,6 +138,13 @@ func (cmd *ImportDataCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...
 
 	switch cmd.sourceFormat {
 	case constants.CSV:
+		if cmd.inferSchema {
+			schemaReader, err = cmd.inferSourceSchema(ctx, sourceReader)
+			if err != nil {
+				logger.Log.Error(fmt.Sprintf("Unable to infer csv schema %v", err))
+				return subcommands.ExitFailure
+			}
+		}
 		// schemaReader will only be valid if sourceFormat is CSV
 		defer schemaReader.Close()
 		err := cmd.handleCsv(ctx, dbURI, dialect, spannerAccessor, sourceReader, schemaReader)
@@ -115,6 +153,21 @@ func (cmd *ImportDataCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...
 			return subcommands.ExitFailure
 		}
 		return subcommands.ExitSuccess
+	case constants.JSONL:
+		if cmd.inferSchema {
+			schemaReader, err = cmd.inferSourceSchema(ctx, sourceReader)
+			if err != nil {
+				logger.Log.Error(fmt.Sprintf("Unable to infer jsonl schema %v", err))
+				return subcommands.ExitFailure
+			}
+		}
+		defer schemaReader.Close()
+		err := cmd.handleJsonl(ctx, dbURI, dialect, spannerAccessor, sourceReader, schemaReader)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("Unable to handle Jsonl %v", err))
+			return subcommands.ExitFailure
+		}
+		return subcommands.ExitSuccess
 	case constants.MYSQLDUMP, constants.PGDUMP:
 		err := cmd.handleDatabaseDumpFile(ctx, dbURI, cmd.sourceFormat, dialect, spannerAccessor, sourceReader)
 		if err != nil {
@@ -128,7 +181,19 @@ func (cmd *ImportDataCmd) Execute(ctx context.Context, f *flag.FlagSet, args ...
 	return subcommands.ExitFailure
 }
 
-func createDatabase(ctx context.Context, dbURI, targetDialect string, spannerAccessor spanneraccessor.SpannerAccessor) error {
+// databaseOptions returns the options of the database created by the import.
+func (cmd *ImportDataCmd) databaseOptions() spanneraccessor.DatabaseOptions {
+	return spanneraccessor.DatabaseOptions{
+		KmsKeyName:             cmd.kmsKeyName,
+		VersionRetentionPeriod: cmd.versionRetentionPeriod,
+		DefaultLeader:          cmd.defaultLeader,
+	}
+}
+
+// createDatabase creates the database with the given options unless it
+// already exists, in which case its dialect is checked and the options are
+// left unchanged.
+func createDatabase(ctx context.Context, dbURI, targetDialect string, spannerAccessor spanneraccessor.SpannerAccessor, opts spanneraccessor.DatabaseOptions) error {
 	if exists, _ := spannerAccessor.CheckExistingDb(ctx, dbURI); exists {
 
 		skipDialectValidation := os.Getenv("IMPORT_CMD_SKIP_DIALECT_VALIDATION")
@@ -149,7 +214,7 @@ func createDatabase(ctx context.Context, dbURI, targetDialect string, spannerAcc
 		}
 		return nil
 	}
-	return spannerAccessor.CreateEmptyDatabase(ctx, dbURI, targetDialect)
+	return spannerAccessor.CreateEmptyDatabaseWithOptions(ctx, dbURI, targetDialect, opts)
 }
 
 // validateSpannerAccessor validate if spanner is accessible by the provided dbURI. Return spannerAccessor, error.
@@ -164,7 +229,7 @@ func validateSpannerAccessor(ctx context.Context, dbURI string) (spanneraccessor
 }
 
 // validateUriRemote validate if source URI and schema URI are accessible. Return sourceReader, schemaReader, error.
-// If sourceFormat is not CSV, schemaReader will be nil.
+// If sourceFormat is not CSV or JSONL, or the schema is inferred, schemaReader will be nil.
 func validateUriRemote(ctx context.Context, input *ImportDataCmd) (file_reader.FileReader, file_reader.FileReader, error) {
 	sourceReader, err := file_reader.NewFileReader(ctx, input.sourceUri)
 	if err != nil {
@@ -172,7 +237,7 @@ func validateUriRemote(ctx context.Context, input *ImportDataCmd) (file_reader.F
 	}
 
 	var schemaReader file_reader.FileReader
-	if input.sourceFormat == constants.CSV {
+	if (input.sourceFormat == constants.CSV || input.sourceFormat == constants.JSONL) && !input.inferSchema {
 		schemaReader, err = file_reader.NewFileReader(ctx, input.schemaUri)
 		if err != nil {
 			sourceReader.Close()
@@ -200,7 +265,7 @@ func getDialectWithDefaults(dialect string) string {
 2. database name is mandatory and accessible
 3. source uri is mandatory and accessible
 4. source format is valid
-5. If CSV, schema URI is mandatory and accessible
+5. If CSV or JSONL, schema URI is mandatory and accessible, unless the schema is inferred
 */
 func validateInputLocal(input *ImportDataCmd) error {
 
@@ -221,13 +286,85 @@ func validateInputLocal(input *ImportDataCmd) error {
 		return fmt.Errorf("Please specify sourceFormat using the --source-format parameter. Received  sourceFormat: %v", input.sourceFormat)
 	}
 
-	if input.sourceFormat == constants.CSV && len(input.schemaUri) == 0 {
-		return fmt.Errorf("Please specify schemaUri using the --schema-uri parameter. Received  schemaUri: %v", input.sourceFormat)
+	if input.inferSchema {
+		if input.sourceFormat != constants.CSV && input.sourceFormat != constants.JSONL {
+			return fmt.Errorf("--infer-schema is only supported for %s and %s formats. Received  sourceFormat: %v", constants.CSV, constants.JSONL, input.sourceFormat)
+		}
+		if len(input.schemaUri) != 0 {
+			return fmt.Errorf("Please specify only one of --schema-uri and --infer-schema")
+		}
+		if input.inferSampleRows <= 0 {
+			return fmt.Errorf("Please specify a positive number of rows using the --infer-sample-rows parameter. Received  inferSampleRows: %v", input.inferSampleRows)
+		}
+	} else if len(input.inferredSchemaOutput) != 0 {
+		return fmt.Errorf("--inferred-schema-output can only be used with --infer-schema")
+	}
+
+	if (input.sourceFormat == constants.CSV || input.sourceFormat == constants.JSONL) && len(input.schemaUri) == 0 && !input.inferSchema {
+		return fmt.Errorf("Please specify schemaUri using the --schema-uri parameter or use --infer-schema. Received  schemaUri: %v", input.sourceFormat)
+	}
+
+	if len(input.kmsKeyName) != 0 && !kmsKeyNameRegex.MatchString(input.kmsKeyName) {
+		return fmt.Errorf("Please specify kmsKeyName in the form projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key> using the --kms-key-name parameter. Received  kmsKeyName: %v", input.kmsKeyName)
+	}
+
+	if len(input.versionRetentionPeriod) != 0 && !versionRetentionPeriodRegex.MatchString(input.versionRetentionPeriod) {
+		return fmt.Errorf("Please specify versionRetentionPeriod as a number of seconds, minutes, hours or days, e.g. 7d, using the --version-retention-period parameter. Received  versionRetentionPeriod: %v", input.versionRetentionPeriod)
 	}
 
 	return err
 }
 
+// inferSourceSchema infers the schema of the csv or jsonl file read by
+// sourceReader and returns a reader of it in the --schema-uri format.
+func (cmd *ImportDataCmd) inferSourceSchema(ctx context.Context, sourceReader file_reader.FileReader) (file_reader.FileReader, error) {
+	r, err := sourceReader.CreateReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := r.(io.Closer); ok {
+		defer c.Close()
+	}
+	var colDefs []import_file.ColumnDefinition
+	if cmd.sourceFormat == constants.JSONL {
+		colDefs, err = import_file.InferJsonlSchema(r, cmd.inferSampleRows)
+	} else {
+		colDefs, err = import_file.InferCsvSchema(r, rune(cmd.csvFieldDelimiter[0]), cmd.inferSampleRows)
+	}
+	if err != nil {
+		return nil, err
+	}
+	schema, err := import_file.MarshalCsvSchema(colDefs)
+	if err != nil {
+		return nil, err
+	}
+	logger.Log.Info(fmt.Sprintf("Inferred schema from up to %d rows:\n%s", cmd.inferSampleRows, schema))
+	return file_reader.NewBytesFileReader(schema), nil
+}
+
+// writeInferredSchema writes the inferred schema of the file to import to
+// inferredSchemaOutput, so that it can be reviewed and passed with --schema-uri.
+func (cmd *ImportDataCmd) writeInferredSchema(ctx context.Context) error {
+	sourceReader, err := file_reader.NewFileReader(ctx, cmd.sourceUri)
+	if err != nil {
+		return fmt.Errorf("sourceUri:%v not accessible. Please check the input and access permissions and try again", cmd.sourceUri)
+	}
+	defer sourceReader.Close()
+	schemaReader, err := cmd.inferSourceSchema(ctx, sourceReader)
+	if err != nil {
+		return err
+	}
+	schema, err := schemaReader.ReadAll(ctx)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(cmd.inferredSchemaOutput, schema, 0644); err != nil {
+		return fmt.Errorf("can't write inferred schema to %s: %v", cmd.inferredSchemaOutput, err)
+	}
+	logger.Log.Info(fmt.Sprintf("Wrote inferred schema to %s. Review it and pass it with --schema-uri to import the file", cmd.inferredSchemaOutput))
+	return nil
+}
+
 func (cmd *ImportDataCmd) handleCsv(ctx context.Context, dbURI, dialect string,
 	sp spanneraccessor.SpannerAccessor, sourceReader file_reader.FileReader, schemaReader file_reader.FileReader) error {
 
@@ -262,6 +399,38 @@ func (cmd *ImportDataCmd) handleCsv(ctx context.Context, dbURI, dialect string,
 
 }
 
+// handleJsonl creates the table of the jsonl file to import from its schema,
+// which has the same format as the schema of a csv, and imports its records.
+func (cmd *ImportDataCmd) handleJsonl(ctx context.Context, dbURI, dialect string,
+	sp spanneraccessor.SpannerAccessor, sourceReader file_reader.FileReader, schemaReader file_reader.FileReader) error {
+
+	cmd.tableName = handleTableNameDefaults(cmd.tableName, cmd.sourceUri)
+
+	infoSchema, err := spanner.NewInfoSchemaImplWithSpannerClient(ctx, dbURI, dialect)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Unable to instantiate spanner client %v", err))
+		return err
+	}
+
+	startTime := time.Now()
+	schema := import_file.NewCsvSchema(cmd.project, cmd.instance,
+		cmd.database, cmd.tableName, cmd.schemaUri, schemaReader)
+	err = schema.CreateSchema(ctx, dialect, sp)
+
+	endTime1 := time.Now()
+	logger.Log.Info(fmt.Sprintf("Schema creation took %f secs", endTime1.Sub(startTime).Seconds()))
+	if err != nil {
+		return err
+	}
+
+	jsonlData := import_file.NewJsonlData(cmd.project, cmd.instance,
+		cmd.database, cmd.tableName, cmd.sourceUri, sourceReader)
+	err = jsonlData.ImportData(ctx, infoSchema, dialect, internal.MakeConv(), &common.InfoSchemaImpl{})
+
+	logger.Log.Info(fmt.Sprintf("Data import took %f secs", time.Since(endTime1).Seconds()))
+	return err
+}
+
 func getDBUri(projectId, instanceId, databaseName string) string {
 	return fmt.Sprintf("projects/%s/instances/%s/databases/%s", projectId, instanceId, databaseName)
 }
@@ -16,8 +16,11 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -72,6 +75,12 @@ func TestImportDataCmd_SetFlags(t *testing.T) {
 	assert.NotNil(t, fs.Lookup("csv-line-delimiter"))
 	assert.NotNil(t, fs.Lookup("csv-field-delimiter"))
 	assert.NotNil(t, fs.Lookup("project"))
+	assert.NotNil(t, fs.Lookup("infer-schema"))
+	assert.NotNil(t, fs.Lookup("infer-sample-rows"))
+	assert.NotNil(t, fs.Lookup("inferred-schema-output"))
+	assert.NotNil(t, fs.Lookup("kms-key-name"))
+	assert.NotNil(t, fs.Lookup("version-retention-period"))
+	assert.NotNil(t, fs.Lookup("default-leader"))
 }
 
 func TestValidateInputLocal_MissingInstanceID(t *testing.T) {
@@ -122,6 +131,100 @@ func TestValidateInputLocal_SuccessCSV(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestValidateInputLocal_InferSchema(t *testing.T) {
+	input := &ImportDataCmd{
+		instance:        "test-instance",
+		database:        "test-db",
+		sourceUri:       "file:///tmp/data.csv",
+		sourceFormat:    constants.CSV,
+		inferSchema:     true,
+		inferSampleRows: 1000,
+	}
+	assert.NoError(t, validateInputLocal(input))
+
+	input.schemaUri = "file:///tmp/schema.csv"
+	assert.ErrorContains(t, validateInputLocal(input), "Please specify only one of --schema-uri and --infer-schema")
+
+	input.schemaUri = ""
+	input.inferSampleRows = 0
+	assert.ErrorContains(t, validateInputLocal(input), "Please specify a positive number of rows")
+
+	input.inferSampleRows = 1000
+	input.sourceFormat = constants.MYSQLDUMP
+	assert.ErrorContains(t, validateInputLocal(input), "--infer-schema is only supported for csv and jsonl formats")
+
+	input.sourceFormat = constants.CSV
+	input.inferSchema = false
+	input.schemaUri = "file:///tmp/schema.csv"
+	input.inferredSchemaOutput = "schema.json"
+	assert.ErrorContains(t, validateInputLocal(input), "--inferred-schema-output can only be used with --infer-schema")
+}
+
+func TestValidateInputLocal_DatabaseOptions(t *testing.T) {
+	input := &ImportDataCmd{
+		instance:               "test-instance",
+		database:               "test-db",
+		sourceUri:              "../test_data/basic_mysql_dump.test.out",
+		sourceFormat:           constants.MYSQLDUMP,
+		kmsKeyName:             "projects/p/locations/us-central1/keyRings/r/cryptoKeys/k",
+		versionRetentionPeriod: "7d",
+		defaultLeader:          "us-central1",
+	}
+	assert.NoError(t, validateInputLocal(input))
+
+	input.versionRetentionPeriod = "7 days"
+	assert.ErrorContains(t, validateInputLocal(input), "--version-retention-period")
+
+	input.versionRetentionPeriod = "7d"
+	input.kmsKeyName = "k"
+	assert.ErrorContains(t, validateInputLocal(input), "--kms-key-name")
+}
+
+func TestCreateDatabase_Options(t *testing.T) {
+	ctx := context.Background()
+	opts := spanneraccessor.DatabaseOptions{KmsKeyName: "projects/p/locations/l/keyRings/r/cryptoKeys/k", VersionRetentionPeriod: "7d", DefaultLeader: "us-central1"}
+	var created spanneraccessor.DatabaseOptions
+	sa := &spanneraccessor.SpannerAccessorMock{
+		CheckExistingDbMock: func(ctx context.Context, dbURI string) (bool, error) { return false, nil },
+		CreateEmptyDatabaseWithOptionsMock: func(ctx context.Context, dbURI, dialect string, opts spanneraccessor.DatabaseOptions) error {
+			created = opts
+			return nil
+		},
+	}
+	assert.NoError(t, createDatabase(ctx, "projects/p/instances/i/databases/d", constants.DIALECT_GOOGLESQL, sa, opts))
+	assert.Equal(t, opts, created)
+
+	// The options of an existing database are left unchanged.
+	created = spanneraccessor.DatabaseOptions{}
+	sa.CheckExistingDbMock = func(ctx context.Context, dbURI string) (bool, error) { return true, nil }
+	sa.GetDatabaseDialectMock = func(ctx context.Context, dbURI string) (string, error) { return constants.DIALECT_GOOGLESQL, nil }
+	assert.NoError(t, createDatabase(ctx, "projects/p/instances/i/databases/d", constants.DIALECT_GOOGLESQL, sa, opts))
+	assert.Equal(t, spanneraccessor.DatabaseOptions{}, created)
+}
+
+func TestImportDataCmd_WriteInferredSchema(t *testing.T) {
+	dir := t.TempDir()
+	sourceUri := filepath.Join(dir, "data.csv")
+	assert.NoError(t, os.WriteFile(sourceUri, []byte("id,name\n1,a\n2,b\n"), 0644))
+	cmd := &ImportDataCmd{
+		sourceUri:            sourceUri,
+		csvFieldDelimiter:    ",",
+		inferSchema:          true,
+		inferSampleRows:      1000,
+		inferredSchemaOutput: filepath.Join(dir, "schema.json"),
+	}
+	assert.NoError(t, cmd.writeInferredSchema(context.Background()))
+
+	schema, err := os.ReadFile(cmd.inferredSchemaOutput)
+	assert.NoError(t, err)
+	var colDefs []import_file.ColumnDefinition
+	assert.NoError(t, json.Unmarshal(schema, &colDefs))
+	assert.Equal(t, []import_file.ColumnDefinition{
+		{Name: "id", Type: "INT64", NotNull: true, PkOrder: 1},
+		{Name: "name", Type: "STRING(MAX)"},
+	}, colDefs)
+}
+
 func TestValidateInputLocal_SuccessNonCSV(t *testing.T) {
 	input := &ImportDataCmd{
 		instance:        "test-instance",
@@ -310,7 +413,7 @@ func TestImportDataCmd_HandleCsvExecute(t *testing.T) {
 					CheckExistingDbMock: func(ctx context.Context, dbURI string) (bool, error) {
 						return false, nil
 					},
-					CreateEmptyDatabaseMock: func(ctx context.Context, dbURI, dialect string) error {
+					CreateEmptyDatabaseWithOptionsMock: func(ctx context.Context, dbURI, dialect string, opts spanneraccessor.DatabaseOptions) error {
 						return nil
 					},
 					GetDatabaseDialectMock: func(ctx context.Context, dbURI string) (string, error) {
@@ -450,7 +553,7 @@ func TestImportDataCmd_HandleDumpExecute(t *testing.T) {
 					CheckExistingDbMock: func(ctx context.Context, dbURI string) (bool, error) {
 						return false, nil
 					},
-					CreateEmptyDatabaseMock: func(ctx context.Context, dbURI, dialect string) error {
+					CreateEmptyDatabaseWithOptionsMock: func(ctx context.Context, dbURI, dialect string, opts spanneraccessor.DatabaseOptions) error {
 						return nil
 					},
 					UpdateDatabaseMock: func(ctx context.Context, dbURI string, conv *internal.Conv, driver string) error {
@@ -839,6 +942,88 @@ func TestHandleCsv(t *testing.T) {
 	}
 }
 
+func TestHandleJsonl(t *testing.T) {
+	expectedDbUri := "projects/test-project/instances/test-instance/databases/test-db"
+
+	testCases := []struct {
+		desc          string
+		expectedErr   error
+		csvSchemaFunc func(projectId, instanceId, dbName, tableName, schemaUri string, schemaFileReader file_reader.FileReader) import_file.CsvSchema
+		jsonlDataFunc func(projectId, instanceId, dbName, tableName, sourceUri string, sourceFileReader file_reader.FileReader) import_file.JsonlData
+	}{
+		{
+			desc: "Successful JSONL import",
+			csvSchemaFunc: func(projectId, instanceId, dbName, tableName, schemaUri string, schemaFileReader file_reader.FileReader) import_file.CsvSchema {
+				assert.Equal(t, "events", tableName)
+				assert.Equal(t, "gs://test-bucket/test_schema.json", schemaUri)
+				return &import_file.MockCsvSchema{}
+			},
+			jsonlDataFunc: func(projectId, instanceId, dbName, tableName, sourceUri string, sourceFileReader file_reader.FileReader) import_file.JsonlData {
+				assert.Equal(t, "events", tableName)
+				assert.Equal(t, "gs://test-bucket/events.jsonl", sourceUri)
+				return &import_file.MockJsonlData{}
+			},
+		},
+		{
+			desc: "Schema creation fails",
+			csvSchemaFunc: func(projectId, instanceId, dbName, tableName, schemaUri string, schemaFileReader file_reader.FileReader) import_file.CsvSchema {
+				return &import_file.MockCsvSchema{
+					CreateSchemaFn: func(ctx context.Context, dialect string, sp spanneraccessor.SpannerAccessor) error {
+						return fmt.Errorf("schema creation error")
+					},
+				}
+			},
+			expectedErr: fmt.Errorf("schema creation error"),
+		},
+		{
+			desc: "Data import fails",
+			csvSchemaFunc: func(projectId, instanceId, dbName, tableName, schemaUri string, schemaFileReader file_reader.FileReader) import_file.CsvSchema {
+				return &import_file.MockCsvSchema{}
+			},
+			jsonlDataFunc: func(projectId, instanceId, dbName, tableName, sourceUri string, sourceFileReader file_reader.FileReader) import_file.JsonlData {
+				return &import_file.MockJsonlData{
+					ImportDataFn: func(ctx context.Context, spannerInfoSchema *sourcesspanner.InfoSchemaImpl, dialect string, conv *internal.Conv, commonInfoSchema common.InfoSchemaInterface) error {
+						return fmt.Errorf("data import error")
+					},
+				}
+			},
+			expectedErr: fmt.Errorf("data import error"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			cmd := &ImportDataCmd{
+				project:   "test-project",
+				instance:  "test-instance",
+				database:  "test-db",
+				sourceUri: "gs://test-bucket/events.jsonl",
+				schemaUri: "gs://test-bucket/test_schema.json",
+			}
+			originalNewInfoSchemaFunc := sourcesspanner.NewInfoSchemaImplWithSpannerClient
+			originalNewCsvSchema := import_file.NewCsvSchema
+			originalNewJsonlData := import_file.NewJsonlData
+			defer func() {
+				sourcesspanner.NewInfoSchemaImplWithSpannerClient = originalNewInfoSchemaFunc
+				import_file.NewCsvSchema = originalNewCsvSchema
+				import_file.NewJsonlData = originalNewJsonlData
+			}()
+			sourcesspanner.NewInfoSchemaImplWithSpannerClient = func(ctx context.Context, dbURI string, spDialect string) (*sourcesspanner.InfoSchemaImpl, error) {
+				return &sourcesspanner.InfoSchemaImpl{}, nil
+			}
+			import_file.NewCsvSchema = tC.csvSchemaFunc
+			import_file.NewJsonlData = tC.jsonlDataFunc
+
+			err := cmd.handleJsonl(context.Background(), expectedDbUri, constants.DIALECT_GOOGLESQL, &spanneraccessor.SpannerAccessorMock{}, &file_reader.GcsFileReaderImpl{}, &file_reader.LocalFileReaderImpl{})
+			if tC.expectedErr != nil {
+				assert.EqualError(t, err, tC.expectedErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func fetchDDLString(conv *internal.Conv) string {
 	return strings.Replace(strings.Join(
 		ddl.GetDDL(
@@ -24,6 +24,7 @@ import (
 	"strings"
 	"time"
 
+	spanneraccessor "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/spanner"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/conversion"
@@ -49,6 +50,12 @@ type SchemaCmd struct {
 	validate        bool
 	sessionJSON     string
 	sessionFileName string
+	emulatorDryRun  bool
+	emulatorHost    string
+	includeTables   string
+	excludeTables   string
+	recordRun       bool
+	config          string
 }
 
 // Name returns the name of operation.
@@ -85,9 +92,20 @@ func (cmd *SchemaCmd) SetFlags(f *flag.FlagSet) {
 	f.BoolVar(&cmd.validate, "validate", false, "Flag for validating if all the required input parameters are present")
 	f.StringVar(&cmd.sessionJSON, "session", "", "Optional. Specifies the file we restore session state from.")
 	f.StringVar(&cmd.sessionFileName, "session-file-name", "", "Optional. Specifies the name of the file we store session state in.")
+	f.BoolVar(&cmd.emulatorDryRun, "emulator-dry-run", false, "Optional. Applies the generated DDL to the Spanner emulator and reports the statements it rejects.")
+	f.StringVar(&cmd.emulatorHost, "emulator-host", "", "Optional. Address of the Spanner emulator used by --emulator-dry-run, defaults to $SPANNER_EMULATOR_HOST. If neither is set, an emulator is started with docker.")
+	f.StringVar(&cmd.includeTables, "include-tables", "", "Optional. Regular expression matching the whole names of the source tables to migrate, e.g. \"orders|order_items\"; other tables are skipped")
+	f.StringVar(&cmd.excludeTables, "exclude-tables", "", "Optional. Regular expression matching the whole names of the source tables to skip, e.g. \"tmp_.*\"")
+	f.BoolVar(&cmd.recordRun, "record-run", false, "Optional. Records the migration run, with its source, target, duration, row counts and session file, in the SMT_MIGRATION_RUN table of the spannermigrationtool_metadata database")
+	f.StringVar(&cmd.config, "config", "", "Optional. YAML profile file with the source, target and orchestration settings of the migration, overridden by the flags passed")
 }
 
 func (cmd *SchemaCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	// Flags which aren't passed are set from the profile file, if any.
+	if err := applyProfileFile(f, cmd.config); err != nil {
+		logger.Log.Error(fmt.Sprintf("Error applying the profile file: %v", err))
+		return subcommands.ExitUsageError
+	}
 	// Cleanup smt tmp data directory in case residuals remain from prev runs.
 	os.RemoveAll(filepath.Join(os.TempDir(), constants.SMT_TMP_DIR))
 	var err error
@@ -108,6 +126,11 @@ func (cmd *SchemaCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interfa
 		err = fmt.Errorf("error while preparing prerequisites for migration: %v", err)
 		return subcommands.ExitUsageError
 	}
+	sourceProfile.IncludeTables, sourceProfile.ExcludeTables = cmd.includeTables, cmd.excludeTables
+	_, err = internal.NewTableFilter(cmd.includeTables, cmd.excludeTables)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
 	if cmd.project == "" {
 		getInfo := &utils.GetUtilInfoImpl{}
 		cmd.project, err = getInfo.GetProject()
@@ -121,6 +144,21 @@ func (cmd *SchemaCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interfa
 		return subcommands.ExitSuccess
 	}
 
+	if sourceProfile.IsSeparateMultiDatabase() {
+		if cmd.sessionJSON != "" {
+			err = fmt.Errorf("--session can't be used when the source profile names several databases")
+			return subcommands.ExitUsageError
+		}
+		return runForEachSourceDatabase(cmd.targetProfile, cmd.filePrefix, sourceProfile, func(srcDb, sourceProfileString, targetProfileString, filePrefix string) subcommands.ExitStatus {
+			dbCmd := *cmd
+			dbCmd.sourceProfile, dbCmd.targetProfile, dbCmd.filePrefix = sourceProfileString, targetProfileString, filePrefix
+			if dbCmd.sessionFileName != "" {
+				dbCmd.sessionFileName = srcDb + "." + dbCmd.sessionFileName
+			}
+			return dbCmd.Execute(ctx, f)
+		})
+	}
+
 	// If filePrefix not explicitly set, use generated dbName.
 	if cmd.filePrefix == "" {
 		cmd.filePrefix = dbName
@@ -175,6 +213,13 @@ func (cmd *SchemaCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interfa
 		logger.Log.Error("Could not initialize conversion context from")
 		return subcommands.ExitFailure
 	}
+	if cmd.emulatorDryRun {
+		err = spanneraccessor.EmulatorDryRun(ctx, conv, sourceProfile.Driver, cmd.emulatorHost)
+		if err != nil {
+			logger.Log.Error("Emulator dry run failed", zap.Error(err))
+			return subcommands.ExitFailure
+		}
+	}
 	conversion.WriteSchemaFile(conv, schemaConversionStartTime, cmd.filePrefix+schemaFile, ioHelper.Out, sourceProfile.Driver)
 
 	// We always write the session file to accommodate for a re-run that might change anything.
@@ -183,6 +228,7 @@ func (cmd *SchemaCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interfa
 
 	// Generate overrides file for schema mapping information
 	conversion.WriteOverridesFile(conv, cmd.filePrefix+overridesFile, ioHelper.Out)
+	conversion.WriteDeferredDdlScript(conv, targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance, targetProfile.Conn.Sp.Dbname, cmd.filePrefix+deferredDdlFile, ioHelper.Out)
 
 	// Populate migration request id and migration type in conv object.
 	conv.Audit.MigrationRequestId, _ = utils.GenerateName("smt-job")
@@ -202,6 +248,9 @@ func (cmd *SchemaCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interfa
 	banner := utils.GetBanner(schemaConversionStartTime, dbName)
 	reportImpl := conversion.ReportImpl{}
 	reportImpl.GenerateReport(sourceProfile.Driver, nil, ioHelper.BytesRead, banner, conv, cmd.filePrefix, dbName, ioHelper.Out)
+	if cmd.recordRun && !cmd.dryRun {
+		recordMigrationRun(ctx, conv, sourceProfile, targetProfile, sessionFileName, schemaConversionStartTime)
+	}
 	// Cleanup smt tmp data directory.
 	os.RemoveAll(filepath.Join(os.TempDir(), constants.SMT_TMP_DIR))
 	return subcommands.ExitSuccess
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/status"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/conversion"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/expressions_api"
@@ -39,19 +40,30 @@ import (
 
 // SchemaAndDataCmd struct with flags.
 type SchemaAndDataCmd struct {
-	source           string
-	sourceProfile    string
-	target           string
-	targetProfile    string
-	SkipForeignKeys  bool
-	filePrefix       string // TODO: move filePrefix to global flags
-	project          string
-	WriteLimit       int64
-	dryRun           bool
-	logLevel         string
-	validate         bool
-	dataflowTemplate string
-	sessionFileName  string
+	source               string
+	sourceProfile        string
+	target               string
+	targetProfile        string
+	SkipForeignKeys      bool
+	filePrefix           string // TODO: move filePrefix to global flags
+	project              string
+	WriteLimit           int64
+	dryRun               bool
+	logLevel             string
+	validate             bool
+	dataflowTemplate     string
+	sessionFileName      string
+	deadLetter           string
+	tableReadParallelism int
+	includeTables        string
+	excludeTables        string
+	tableOrder           string
+	recordRun            bool
+	config               string
+	statusPort           int
+	statusAddress        string
+	maskingProfile       string
+	sample               string
 }
 
 // Name returns the name of operation.
@@ -90,9 +102,25 @@ func (cmd *SchemaAndDataCmd) SetFlags(f *flag.FlagSet) {
 	f.BoolVar(&cmd.validate, "validate", false, "Flag for validating if all the required input parameters are present")
 	f.StringVar(&cmd.dataflowTemplate, "dataflow-template", constants.DEFAULT_TEMPLATE_PATH, "GCS path of the Dataflow template")
 	f.StringVar(&cmd.sessionFileName, "session-file-name", "", "Optional. Specifies the name of the file we store session state in.")
+	f.StringVar(&cmd.deadLetter, "dead-letter", "", "Optional. Writes every bad row to a local file, a GCS object (gs://bucket/object) or a BigQuery table (bq://project.dataset.table)")
+	f.StringVar(&cmd.includeTables, "include-tables", "", "Optional. Regular expression matching the whole names of the source tables to migrate, e.g. \"orders|order_items\"; other tables are skipped")
+	f.StringVar(&cmd.excludeTables, "exclude-tables", "", "Optional. Regular expression matching the whole names of the source tables to skip, e.g. \"tmp_.*\"")
+	f.StringVar(&cmd.tableOrder, "table-order", "", "Optional. Comma separated priority classes of tables whose data is migrated first, each a regular expression matching whole source table names, e.g. \"countries|currencies,customers\"; other tables are migrated last")
+	f.BoolVar(&cmd.recordRun, "record-run", false, "Optional. Records the migration run, with its source, target, duration, row counts and session file, in the SMT_MIGRATION_RUN table of the spannermigrationtool_metadata database")
+	f.IntVar(&cmd.tableReadParallelism, "table-read-parallelism", 1, "Number of workers reading each large MySQL or PostgreSQL table by primary key range, or the data files of a mydumper export, defaults to 1")
+	f.StringVar(&cmd.config, "config", "", "Optional. YAML profile file with the source, target and orchestration settings of the migration, overridden by the flags passed")
+	f.IntVar(&cmd.statusPort, "status-port", 0, "Optional. Port of an HTTP server reporting the progress of the migration as JSON on /status, e.g. the rows written and errors of each table and the estimated time left")
+	f.StringVar(&cmd.statusAddress, "status-address", status.DefaultAddress, "Optional. Address the server of --status-port listens on, e.g. 0.0.0.0 to let other hosts read the progress")
+	f.StringVar(&cmd.maskingProfile, "masking-profile", "", "Optional. JSON file with the strategies masking columns during data migration (hash, shuffle, synthetic or nullify), e.g. to populate a staging database without personal data. The key of the hashes is read from the "+internal.MaskingKeyEnv+" environment variable, a random key is used by default")
+	f.StringVar(&cmd.sample, "sample", "", "Optional. Migrates a sample of the data to quickly produce a small but referentially consistent database, e.g. for application testing: a percentage of the rows, e.g. 1%, or a number of rows per table, e.g. 10k. Rows referencing other rows, through foreign keys or interleaving, are migrated only if the rows they reference are")
 }
 
 func (cmd *SchemaAndDataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	// Flags which aren't passed are set from the profile file, if any.
+	if err := applyProfileFile(f, cmd.config); err != nil {
+		logger.Log.Error(fmt.Sprintf("Error applying the profile file: %v", err))
+		return subcommands.ExitUsageError
+	}
 	// Cleanup smt tmp data directory in case residuals remain from prev runs.
 	os.RemoveAll(filepath.Join(os.TempDir(), constants.SMT_TMP_DIR))
 	var err error
@@ -114,6 +142,27 @@ func (cmd *SchemaAndDataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...
 		err = fmt.Errorf("error while preparing prerequisites for migration: %v", err)
 		return subcommands.ExitUsageError
 	}
+	sourceProfile.IncludeTables, sourceProfile.ExcludeTables = cmd.includeTables, cmd.excludeTables
+	_, err = internal.NewTableFilter(cmd.includeTables, cmd.excludeTables)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	tableOrder, err := internal.NewTableOrder(cmd.tableOrder)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	sample, err := internal.ParseDataSample(cmd.sample)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	if cmd.maskingProfile != "" && migratedByDataflow(sourceProfile) {
+		err = fmt.Errorf("--masking-profile only applies to bulk migrations, it can't be used with Dataflow migrations")
+		return subcommands.ExitUsageError
+	}
+	if sample.Enabled() && migratedByDataflow(sourceProfile) {
+		err = fmt.Errorf("--sample only applies to bulk migrations, it can't be used with Dataflow migrations")
+		return subcommands.ExitUsageError
+	}
 	if cmd.project == "" {
 		getInfo := &utils.GetUtilInfoImpl{}
 		cmd.project, err = getInfo.GetProject()
@@ -125,7 +174,23 @@ func (cmd *SchemaAndDataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...
 	if cmd.validate {
 		return subcommands.ExitSuccess
 	}
+
+	if sourceProfile.IsSeparateMultiDatabase() {
+		return runForEachSourceDatabase(cmd.targetProfile, cmd.filePrefix, sourceProfile, func(srcDb, sourceProfileString, targetProfileString, filePrefix string) subcommands.ExitStatus {
+			dbCmd := *cmd
+			dbCmd.sourceProfile, dbCmd.targetProfile, dbCmd.filePrefix = sourceProfileString, targetProfileString, filePrefix
+			if dbCmd.sessionFileName != "" {
+				dbCmd.sessionFileName = srcDb + "." + dbCmd.sessionFileName
+			}
+			return dbCmd.Execute(ctx, f)
+		})
+	}
 	schemaConversionStartTime := time.Now()
+	migrationStatus, stopStatusServer, err := startStatusServer(cmd.statusAddress, cmd.statusPort)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	defer stopStatusServer()
 
 	// If filePrefix not explicitly set, use dbName as prefix.
 	if cmd.filePrefix == "" {
@@ -167,12 +232,30 @@ func (cmd *SchemaAndDataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...
 	conv.Audit.MigrationRequestId = strings.Replace(conv.Audit.MigrationRequestId, "_", "-", -1)
 	conv.Audit.MigrationType = migration.MigrationData_SCHEMA_AND_DATA.Enum()
 
+	// The masks are recorded in the session file.
+	if err = applyMaskingProfile(conv, cmd.maskingProfile); err != nil {
+		return subcommands.ExitUsageError
+	}
+
 	conversion.WriteSchemaFile(conv, schemaConversionStartTime, cmd.filePrefix+schemaFile, ioHelper.Out, sourceProfile.Driver)
 	sessionFileName := GetSessionFileName(cmd.sessionFileName, cmd.filePrefix)
 	conversion.WriteSessionFile(conv, sessionFileName, ioHelper.Out)
 	// Generate overrides file for schema mapping information
 	conversion.WriteOverridesFile(conv, cmd.filePrefix+overridesFile, ioHelper.Out)
+	conversion.WriteDeferredDdlScript(conv, targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance, targetProfile.Conn.Sp.Dbname, cmd.filePrefix+deferredDdlFile, ioHelper.Out)
 	conv.Audit.SkipMetricsPopulation = os.Getenv("SKIP_METRICS_POPULATION") == "true"
+	conv.TableReadParallelism = cmd.tableReadParallelism
+	conv.TableOrder = tableOrder
+	conv.SetDataSample(sample)
+	conv.Status = migrationStatus
+	var stopInterrupt func()
+	conv.Ctx, stopInterrupt = interruptContext(ctx)
+	defer stopInterrupt()
+	closeDeadLetter, err := openDeadLetter(ctx, conv, cmd.deadLetter)
+	if err != nil {
+		return subcommands.ExitUsageError
+	}
+	defer closeDeadLetter()
 	reportImpl := conversion.ReportImpl{}
 	if !cmd.dryRun {
 		reportImpl.GenerateReport(sourceProfile.Driver, nil, ioHelper.BytesRead, "", conv, cmd.filePrefix, dbName, ioHelper.Out)
@@ -209,8 +292,15 @@ func (cmd *SchemaAndDataCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...
 	}
 	reportImpl.GenerateReport(sourceProfile.Driver, bw.DroppedRowsByTable(), ioHelper.BytesRead, banner, conv, cmd.filePrefix, dbName, ioHelper.Out)
 	conversion.WriteBadData(bw, conv, banner, cmd.filePrefix+badDataFile, ioHelper.Out)
+	conversion.WriteCheckpoint(conv, cmd.filePrefix+checkpointFile, ioHelper.Out)
+	if cmd.recordRun && !cmd.dryRun {
+		recordMigrationRun(ctx, conv, sourceProfile, targetProfile, sessionFileName, schemaConversionStartTime)
+	}
 
 	// Cleanup smt tmp data directory.
 	os.RemoveAll(filepath.Join(os.TempDir(), constants.SMT_TMP_DIR))
+	if conv.Interrupted() {
+		return subcommands.ExitFailure
+	}
 	return subcommands.ExitSuccess
 }
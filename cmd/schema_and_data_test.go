@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/status"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -32,126 +33,140 @@ func TestSchemaAndDataSetFlags(t *testing.T) {
 			testName: "Default Values",
 			flagArgs: []string{},
 			expectedValues: SchemaAndDataCmd{
-				source:           "",
-				sourceProfile:    "",
-				target:           "Spanner",
-				targetProfile:    "",
-				filePrefix:       "",
-				WriteLimit:       DefaultWritersLimit,
-				dryRun:           false,
-				logLevel:         "DEBUG",
-				SkipForeignKeys:  false,
-				validate:         false,
-				dataflowTemplate: constants.DEFAULT_TEMPLATE_PATH,
-				sessionFileName:  "",
+				source:               "",
+				sourceProfile:        "",
+				target:               "Spanner",
+				targetProfile:        "",
+				filePrefix:           "",
+				WriteLimit:           DefaultWritersLimit,
+				dryRun:               false,
+				logLevel:             "DEBUG",
+				SkipForeignKeys:      false,
+				validate:             false,
+				dataflowTemplate:     constants.DEFAULT_TEMPLATE_PATH,
+				tableReadParallelism: 1,
+				statusAddress:        status.DefaultAddress,
+				sessionFileName:      "",
 			},
 		},
 		{
 			testName: "Source and Target",
 			flagArgs: []string{"--source=PostgreSQL", "--target=Spanner"},
 			expectedValues: SchemaAndDataCmd{
-				source:           "PostgreSQL",
-				sourceProfile:    "",
-				target:           "Spanner",
-				targetProfile:    "",
-				filePrefix:       "",
-				WriteLimit:       DefaultWritersLimit,
-				dryRun:           false,
-				logLevel:         "DEBUG",
-				SkipForeignKeys:  false,
-				validate:         false,
-				dataflowTemplate: constants.DEFAULT_TEMPLATE_PATH,
-				sessionFileName:  "",
+				source:               "PostgreSQL",
+				sourceProfile:        "",
+				target:               "Spanner",
+				targetProfile:        "",
+				filePrefix:           "",
+				WriteLimit:           DefaultWritersLimit,
+				dryRun:               false,
+				logLevel:             "DEBUG",
+				SkipForeignKeys:      false,
+				validate:             false,
+				dataflowTemplate:     constants.DEFAULT_TEMPLATE_PATH,
+				tableReadParallelism: 1,
+				statusAddress:        status.DefaultAddress,
+				sessionFileName:      "",
 			},
 		},
 		{
 			testName: "Source and Target Profiles",
 			flagArgs: []string{"--source-profile=source.json", "--target-profile=target.json"},
 			expectedValues: SchemaAndDataCmd{
-				source:           "",
-				sourceProfile:    "source.json",
-				target:           "Spanner",
-				targetProfile:    "target.json",
-				filePrefix:       "",
-				WriteLimit:       DefaultWritersLimit,
-				dryRun:           false,
-				logLevel:         "DEBUG",
-				SkipForeignKeys:  false,
-				validate:         false,
-				dataflowTemplate: constants.DEFAULT_TEMPLATE_PATH,
-				sessionFileName:  "",
+				source:               "",
+				sourceProfile:        "source.json",
+				target:               "Spanner",
+				targetProfile:        "target.json",
+				filePrefix:           "",
+				WriteLimit:           DefaultWritersLimit,
+				dryRun:               false,
+				logLevel:             "DEBUG",
+				SkipForeignKeys:      false,
+				validate:             false,
+				dataflowTemplate:     constants.DEFAULT_TEMPLATE_PATH,
+				tableReadParallelism: 1,
+				statusAddress:        status.DefaultAddress,
+				sessionFileName:      "",
 			},
 		},
 		{
 			testName: "File Prefix and Write Limit",
 			flagArgs: []string{"--prefix=test", "--write-limit=100"},
 			expectedValues: SchemaAndDataCmd{
-				source:           "",
-				sourceProfile:    "",
-				target:           "Spanner",
-				targetProfile:    "",
-				filePrefix:       "test",
-				WriteLimit:       100,
-				dryRun:           false,
-				logLevel:         "DEBUG",
-				SkipForeignKeys:  false,
-				validate:         false,
-				dataflowTemplate: constants.DEFAULT_TEMPLATE_PATH,
-				sessionFileName:  "",
+				source:               "",
+				sourceProfile:        "",
+				target:               "Spanner",
+				targetProfile:        "",
+				filePrefix:           "test",
+				WriteLimit:           100,
+				dryRun:               false,
+				logLevel:             "DEBUG",
+				SkipForeignKeys:      false,
+				validate:             false,
+				dataflowTemplate:     constants.DEFAULT_TEMPLATE_PATH,
+				tableReadParallelism: 1,
+				statusAddress:        status.DefaultAddress,
+				sessionFileName:      "",
 			},
 		},
 		{
 			testName: "Dry Run and Log Level",
 			flagArgs: []string{"--dry-run", "--log-level=INFO"},
 			expectedValues: SchemaAndDataCmd{
-				source:           "",
-				sourceProfile:    "",
-				target:           "Spanner",
-				targetProfile:    "",
-				filePrefix:       "",
-				WriteLimit:       DefaultWritersLimit,
-				dryRun:           true,
-				logLevel:         "INFO",
-				SkipForeignKeys:  false,
-				validate:         false,
-				dataflowTemplate: constants.DEFAULT_TEMPLATE_PATH,
-				sessionFileName:  "",
+				source:               "",
+				sourceProfile:        "",
+				target:               "Spanner",
+				targetProfile:        "",
+				filePrefix:           "",
+				WriteLimit:           DefaultWritersLimit,
+				dryRun:               true,
+				logLevel:             "INFO",
+				SkipForeignKeys:      false,
+				validate:             false,
+				dataflowTemplate:     constants.DEFAULT_TEMPLATE_PATH,
+				tableReadParallelism: 1,
+				statusAddress:        status.DefaultAddress,
+				sessionFileName:      "",
 			},
 		},
 		{
 			testName: "Skip Foreign Keys and Validate",
 			flagArgs: []string{"--skip-foreign-keys", "--validate"},
 			expectedValues: SchemaAndDataCmd{
-				source:           "",
-				sourceProfile:    "",
-				target:           "Spanner",
-				targetProfile:    "",
-				filePrefix:       "",
-				WriteLimit:       DefaultWritersLimit,
-				dryRun:           false,
-				logLevel:         "DEBUG",
-				SkipForeignKeys:  true,
-				validate:         true,
-				dataflowTemplate: constants.DEFAULT_TEMPLATE_PATH,
-				sessionFileName:  "",
+				source:               "",
+				sourceProfile:        "",
+				target:               "Spanner",
+				targetProfile:        "",
+				filePrefix:           "",
+				WriteLimit:           DefaultWritersLimit,
+				dryRun:               false,
+				logLevel:             "DEBUG",
+				SkipForeignKeys:      true,
+				validate:             true,
+				dataflowTemplate:     constants.DEFAULT_TEMPLATE_PATH,
+				tableReadParallelism: 1,
+				statusAddress:        status.DefaultAddress,
+				sessionFileName:      "",
 			},
 		},
 		{
 			testName: "Custom Dataflow Template and Proper Session File Name with Extension",
 			flagArgs: []string{"--dataflow-template=gs://my-bucket/my-template", "--session-file-name=migration_session.json"},
 			expectedValues: SchemaAndDataCmd{
-				source:           "",
-				sourceProfile:    "",
-				target:           "Spanner",
-				targetProfile:    "",
-				filePrefix:       "",
-				WriteLimit:       DefaultWritersLimit,
-				dryRun:           false,
-				logLevel:         "DEBUG",
-				SkipForeignKeys:  false,
-				validate:         false,
-				dataflowTemplate: "gs://my-bucket/my-template",
-				sessionFileName:  "migration_session.json",
+				source:               "",
+				sourceProfile:        "",
+				target:               "Spanner",
+				targetProfile:        "",
+				filePrefix:           "",
+				WriteLimit:           DefaultWritersLimit,
+				dryRun:               false,
+				logLevel:             "DEBUG",
+				SkipForeignKeys:      false,
+				validate:             false,
+				dataflowTemplate:     "gs://my-bucket/my-template",
+				tableReadParallelism: 1,
+				statusAddress:        status.DefaultAddress,
+				sessionFileName:      "migration_session.json",
 			},
 		},
 		{
@@ -171,18 +186,20 @@ func TestSchemaAndDataSetFlags(t *testing.T) {
 				"--session-file-name=my_session_file",
 			},
 			expectedValues: SchemaAndDataCmd{
-				source:           "MySQL",
-				sourceProfile:    "mysql.json",
-				target:           "Spanner",
-				targetProfile:    "spanner.json",
-				filePrefix:       "output",
-				WriteLimit:       50,
-				dryRun:           true,
-				logLevel:         "WARN",
-				SkipForeignKeys:  true,
-				validate:         true,
-				dataflowTemplate: "gs://custom/template",
-				sessionFileName:  "my_session_file",
+				source:               "MySQL",
+				sourceProfile:        "mysql.json",
+				target:               "Spanner",
+				targetProfile:        "spanner.json",
+				filePrefix:           "output",
+				WriteLimit:           50,
+				dryRun:               true,
+				logLevel:             "WARN",
+				SkipForeignKeys:      true,
+				validate:             true,
+				dataflowTemplate:     "gs://custom/template",
+				tableReadParallelism: 1,
+				statusAddress:        status.DefaultAddress,
+				sessionFileName:      "my_session_file",
 			},
 		},
 	}
@@ -17,9 +17,14 @@ package cmd
 import (
 	"context"
 	"encoding/base64"
+	"flag"
 	"fmt"
+	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	sp "cloud.google.com/go/spanner"
@@ -30,8 +35,11 @@ import (
 	spanneraccessor "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/spanner"
 	storageaccessor "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/storage"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/deadletter"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/export"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/metrics"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/parse"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/status"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/conversion"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
@@ -39,16 +47,19 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/writer"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/helpers"
+	"github.com/google/subcommands"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/proto"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
 )
 
 var (
-	badDataFile   = ".dropped.txt"
-	schemaFile    = ".schema.txt"
-	sessionFile   = ".session.json"
-	overridesFile = ".overrides.json"
+	badDataFile     = ".dropped.txt"
+	schemaFile      = ".schema.txt"
+	sessionFile     = ".session.json"
+	overridesFile   = ".overrides.json"
+	deferredDdlFile = ".deferred_ddl.sh"
+	checkpointFile  = ".checkpoint.json"
 )
 
 const (
@@ -82,6 +93,108 @@ func GetSessionFileName(sessionFileName, filePrefix string) string {
 	return sessionFileName
 }
 
+// openDeadLetter configures conv to write every bad row to the dead-letter
+// output at uri. The returned function closes the output, flushing the rows
+// it buffers, and must be called once the data migration ends, also when it
+// fails. An empty uri disables the output.
+func openDeadLetter(ctx context.Context, conv *internal.Conv, uri string) (func(), error) {
+	if uri == "" {
+		return func() {}, nil
+	}
+	w, err := deadletter.NewWriter(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("can't open dead-letter output: %v", err)
+	}
+	conv.DeadLetter = w
+	return func() {
+		if err := w.Close(); err != nil {
+			logger.Log.Error(fmt.Sprintf("Error while closing dead-letter output %s: %v", uri, err))
+		}
+		conv.DeadLetter = nil
+	}, nil
+}
+
+// applyMaskingProfile adds the column masks of the masking profile file at
+// path to conv, for --masking-profile. An empty path adds none.
+func applyMaskingProfile(conv *internal.Conv, path string) error {
+	if path == "" {
+		return nil
+	}
+	profile, err := internal.ReadMaskingProfile(path)
+	if err != nil {
+		return err
+	}
+	return conv.ApplyMaskingProfile(profile)
+}
+
+// migratedByDataflow returns whether the data of sourceProfile is migrated
+// by Dataflow, e.g. for minimal downtime migrations: the settings of the
+// rows converted by the tool, such as column masks, don't apply to it.
+func migratedByDataflow(sourceProfile profiles.SourceProfile) bool {
+	return sourceProfile.Conn.Streaming || (sourceProfile.Ty == profiles.SourceProfileTypeConfig && sourceProfile.Config.ConfigType != constants.BULK_MIGRATION)
+}
+
+// openExport configures conv to export the converted rows in format under
+// uri instead of writing them to Spanner. The returned function closes the
+// files, and must be called once the data conversion is complete.
+func openExport(ctx context.Context, conv *internal.Conv, uri, format string) (func() error, error) {
+	w, err := export.NewWriter(ctx, conv, uri, format)
+	if err != nil {
+		return nil, fmt.Errorf("can't open export output: %v", err)
+	}
+	conv.Export = w
+	return func() error {
+		conv.Export = nil
+		if err := w.Close(); err != nil {
+			return fmt.Errorf("can't close export output %s: %v", uri, err)
+		}
+		return nil
+	}, nil
+}
+
+// startStatusServer serves the progress of the migration on address and
+// port, for --status-address and --status-port. The returned status must be
+// set on the conversion of the migration, and the returned function stops the
+// server. A zero port disables the server.
+func startStatusServer(address string, port int) (*internal.MigrationStatus, func(), error) {
+	if port == 0 {
+		return nil, func() {}, nil
+	}
+	s := internal.NewMigrationStatus()
+	stop, err := status.Serve(address, port, s)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, func() {
+		s.Done()
+		stop()
+	}, nil
+}
+
+// interruptContext returns a copy of ctx canceled on the first SIGINT or
+// SIGTERM, for the context of a data migration: reading the source stops,
+// while the rows already read are still written, and the tables left are
+// recorded in a checkpoint. A second signal terminates the process. The returned
+// function releases the signal handler.
+func interruptContext(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-c:
+			signal.Stop(c)
+			logger.Log.Warn("Interrupted: the migration stops once the rows read are written. Interrupt again to exit immediately.")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(c)
+		cancel()
+	}
+}
+
 // CreateDatabaseClient creates new database client and admin client.
 func CreateDatabaseClient(ctx context.Context, targetProfile profiles.TargetProfile, driver, dbName string, ioHelper utils.IOStreams) (*database.DatabaseAdminClient, *sp.Client, string, error) {
 	if targetProfile.Conn.Sp.Dbname == "" {
@@ -109,6 +222,56 @@ func CreateDatabaseClient(ctx context.Context, targetProfile profiles.TargetProf
 	return adminClient, client, dbURI, nil
 }
 
+// recordMigrationRun records the migration run in the metadata database.
+// Failing to record it doesn't fail the migration.
+func recordMigrationRun(ctx context.Context, conv *internal.Conv, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, sessionFile string, startedAt time.Time) {
+	if sessionFile != "" {
+		if abs, err := filepath.Abs(sessionFile); err == nil {
+			sessionFile = abs
+		}
+	}
+	// The metadata database may predate the SMT_MIGRATION_RUN table.
+	if !helpers.CheckOrCreateMetadataDb(targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance) {
+		logger.Log.Warn("Could not record the migration run: the metadata database can't be created or updated\n")
+		return
+	}
+	run := conversion.NewMigrationRun(conv, sourceProfile, targetProfile, sessionFile, startedAt)
+	if err := conversion.RecordMigrationRun(ctx, run); err != nil {
+		logger.Log.Warn(fmt.Sprintf("Could not record the migration run: %v\n", err))
+	}
+}
+
+// applyProfileFile sets the flags of f from the profile file at path, unless
+// they were passed on the command line. Settings of the file which aren't flags
+// of the command are rejected.
+func applyProfileFile(f *flag.FlagSet, path string) error {
+	if path == "" {
+		return nil
+	}
+	profileFile, err := profiles.LoadProfileFile(path)
+	if err != nil {
+		return err
+	}
+	values, err := profileFile.FlagValues()
+	if err != nil {
+		return fmt.Errorf("invalid profile file %s: %v", path, err)
+	}
+	passed := make(map[string]bool)
+	f.Visit(func(fl *flag.Flag) { passed[fl.Name] = true })
+	for name, value := range values {
+		if passed[name] {
+			continue
+		}
+		if f.Lookup(name) == nil {
+			return fmt.Errorf("profile file %s sets --%s, which isn't supported by this command", path, name)
+		}
+		if err := f.Set(name, value); err != nil {
+			return fmt.Errorf("invalid --%s in profile file %s: %v", name, path, err)
+		}
+	}
+	return nil
+}
+
 // PrepareMigrationPrerequisites creates source and target profiles, opens a new IOStream and generates the database name.
 func PrepareMigrationPrerequisites(sourceProfileString, targetProfileString, source string, dryRun bool) (profiles.SourceProfile, profiles.TargetProfile, utils.IOStreams, string, error) {
 	targetProfile, err := profiles.NewTargetProfile(targetProfileString, dryRun)
@@ -125,6 +288,9 @@ func PrepareMigrationPrerequisites(sourceProfileString, targetProfileString, sou
 	if err != nil {
 		return profiles.SourceProfile{}, targetProfile, utils.IOStreams{}, "", err
 	}
+	if err := internal.NameTemplates(targetProfile.NameTemplates).Validate(); err != nil {
+		return profiles.SourceProfile{}, targetProfile, utils.IOStreams{}, "", err
+	}
 
 	dumpFilePath := ""
 	if sourceProfile.Ty == profiles.SourceProfileTypeFile && (sourceProfile.File.Format == "" || sourceProfile.File.Format == "dump") {
@@ -146,6 +312,92 @@ func PrepareMigrationPrerequisites(sourceProfileString, targetProfileString, sou
 	return sourceProfile, targetProfile, ioHelper, dbName, nil
 }
 
+// runForEachSourceDatabase migrates every database named by a multi database
+// source profile to its own Spanner database, by calling run once per source
+// database with single database source and target profiles.
+func runForEachSourceDatabase(targetProfileString, filePrefix string, sourceProfile profiles.SourceProfile, run func(srcDb, sourceProfileString, targetProfileString, filePrefix string) subcommands.ExitStatus) subcommands.ExitStatus {
+	dbNames, err := conversion.ListSourceDatabases(sourceProfile)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Could not list the source databases to migrate: %v\n", err))
+		return subcommands.ExitFailure
+	}
+	targetParams, err := profiles.ParseMap(targetProfileString)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Could not parse target profile: %v\n", err))
+		return subcommands.ExitUsageError
+	}
+	var failed []string
+	for _, srcDb := range dbNames {
+		srcProfileString, err := singleDatabaseSourceProfile(sourceProfile, srcDb)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("Could not build source profile for source database %s: %v\n", srcDb, err))
+			failed = append(failed, srcDb)
+			continue
+		}
+		spDbName := profiles.GetSpannerDbNameForSourceDb(targetParams["dbName"], srcDb)
+		tgtProfileString, err := profiles.SetParam(targetProfileString, "dbName", spDbName)
+		if err != nil {
+			logger.Log.Error(fmt.Sprintf("Could not build target profile for source database %s: %v\n", srcDb, err))
+			failed = append(failed, srcDb)
+			continue
+		}
+		dbFilePrefix := srcDb
+		if filePrefix != "" {
+			dbFilePrefix = filePrefix + "." + srcDb
+		}
+		logger.Log.Info(fmt.Sprintf("Migrating source database %s to Spanner database %s\n", srcDb, spDbName))
+		if status := run(srcDb, srcProfileString, tgtProfileString, dbFilePrefix); status != subcommands.ExitSuccess {
+			failed = append(failed, srcDb)
+		}
+	}
+	if len(failed) > 0 {
+		logger.Log.Error(fmt.Sprintf("Migration failed for source databases: %s\n", strings.Join(failed, ", ")))
+		return subcommands.ExitFailure
+	}
+	return subcommands.ExitSuccess
+}
+
+// singleDatabaseSourceProfile returns a source profile string for database
+// srcDb of a multi database source profile. The connection params are passed
+// explicitly so that environment variables are not read and the password is
+// not prompted for again.
+func singleDatabaseSourceProfile(sourceProfile profiles.SourceProfile, srcDb string) (string, error) {
+	params := map[string]string{"dbName": srcDb}
+	var replica profiles.SourceProfileReplica
+	var consistentSnapshot bool
+	switch sourceProfile.Driver {
+	case constants.MYSQL:
+		mysql := sourceProfile.Conn.Mysql
+		params["host"], params["port"], params["user"], params["password"] = mysql.Host, mysql.Port, mysql.User, mysql.Pwd
+		replica, consistentSnapshot = mysql.Replica, mysql.ConsistentSnapshot
+	case constants.POSTGRES:
+		pg := sourceProfile.Conn.Pg
+		params["host"], params["port"], params["user"], params["password"] = pg.Host, pg.Port, pg.User, pg.Pwd
+		if len(pg.Schemas) > 0 {
+			params["schemas"] = strings.Join(pg.Schemas, ";")
+		}
+		replica, consistentSnapshot = pg.Replica, pg.ConsistentSnapshot
+	}
+	if consistentSnapshot {
+		params["consistentSnapshot"] = "true"
+	}
+	if replica.Host != "" {
+		params["replicaHost"], params["replicaPort"] = replica.Host, replica.Port
+		params["replicaSyncTimeout"] = fmt.Sprint(int(replica.SyncTimeout.Seconds()))
+		if replica.WaitForPrimary {
+			params["replicaConsistency"] = "primary"
+		}
+	}
+	s := ""
+	var err error
+	for k, v := range params {
+		if s, err = profiles.SetParam(s, k, v); err != nil {
+			return "", err
+		}
+	}
+	return s, nil
+}
+
 // MigrateData creates database and populates data in it.
 func MigrateDatabase(ctx context.Context, migrationProjectId string, targetProfile profiles.TargetProfile, sourceProfile profiles.SourceProfile, dbName string, ioHelper *utils.IOStreams, cmd interface{}, conv *internal.Conv, migrationError *error) (*writer.BatchWriter, error) {
 	var (
@@ -190,17 +442,71 @@ func migrateSchema(ctx context.Context, targetProfile profiles.TargetProfile, so
 	if err != nil {
 		return err
 	}
+	err = createOrUpdateDatabase(ctx, spA, targetProfile, sourceProfile, conv, dbURI, client)
+	if err != nil {
+		return err
+	}
+	metricsPopulation(ctx, sourceProfile.Driver, conv)
+	conv.Audit.Progress.UpdateProgress("Schema migration complete.", completionPercentage, internal.SchemaMigrationComplete)
+	return nil
+}
+
+// createOrUpdateDatabase creates the tables of conv in the Spanner database,
+// creating the database if it doesn't exist. Tables which already exist in it
+// are handled according to the existingTables mode of the target profile:
+// they fail the migration by default, are skipped if their columns are
+// compatible, or are merged by adding the missing columns. Nothing is created
+// while objects of the schema have the same name or exceed Spanner limits.
+func createOrUpdateDatabase(ctx context.Context, spA *spanneraccessor.SpannerAccessorImpl, targetProfile profiles.TargetProfile, sourceProfile profiles.SourceProfile,
+	conv *internal.Conv, dbURI string, client *sp.Client) error {
+	if err := conv.ValidateNames(); err != nil {
+		return err
+	}
+	if err := conv.ValidateLimits(); err != nil {
+		return err
+	}
 	tablesExistingOnSpanner, err := spA.GetTableNamesFromSpanner(ctx, conv.SpDialect, dbURI, client)
 	if err != nil {
 		return err
 	}
-	err = spA.CreateOrUpdateDatabase(ctx, dbURI, sourceProfile.Driver, conv, sourceProfile.Config.ConfigType, tablesExistingOnSpanner)
+	mode := targetProfile.Conn.Sp.ExistingTables
+	if mode == "" || mode == constants.EXISTING_TABLES_FAIL || len(tablesExistingOnSpanner) == 0 {
+		err = spA.CreateOrUpdateDatabase(ctx, dbURI, sourceProfile.Driver, conv, sourceProfile.Config.ConfigType, tablesExistingOnSpanner)
+		if err != nil {
+			return fmt.Errorf("can't create/update database: %v", err)
+		}
+		return nil
+	}
+	if conv.SpDialect != constants.DIALECT_POSTGRESQL && sourceProfile.Config.ConfigType == constants.DATAFLOW_MIGRATION {
+		return fmt.Errorf("spanner migration tool does not support minimal downtime schema/schema-and-data migrations to an existing database")
+	}
+	spannerConv := internal.MakeConv()
+	spannerConv.SpDialect = conv.SpDialect
+	spannerConv.SpProjectId = conv.SpProjectId
+	spannerConv.SpInstanceId = conv.SpInstanceId
+	err = utils.ReadSpannerSchema(ctx, spannerConv, client)
+	if err != nil {
+		return fmt.Errorf("can't read spanner schema: %v", err)
+	}
+	diffs := utils.DiffExistingTables(conv, spannerConv)
+	existingTableIds, statements, err := utils.ExistingTablesStatements(conv, diffs, mode, sourceProfile.Driver)
 	if err != nil {
-		err = fmt.Errorf("can't create/update database: %v", err)
 		return err
 	}
-	metricsPopulation(ctx, sourceProfile.Driver, conv)
-	conv.Audit.Progress.UpdateProgress("Schema migration complete.", completionPercentage, internal.SchemaMigrationComplete)
+	for _, diff := range diffs {
+		logger.Log.Info(fmt.Sprintf("Table %s already exists in the Spanner database, it won't be created (%s).", diff.Name, mode))
+	}
+	for _, stmt := range statements {
+		logger.Log.Info(fmt.Sprintf("Adding missing column to existing table: %s", stmt))
+	}
+	if conv.DatabaseOptions.DefaultTimezone != "" {
+		logger.Log.Warn("Spanner database already contains tables, can not set default time zone of '" + conv.DatabaseOptions.DefaultTimezone + "'. See https://docs.cloud.google.com/spanner/docs/set-default-time-zone#limitations.")
+		conv.DatabaseOptions.DefaultTimezone = ""
+	}
+	err = spA.UpdateDatabaseSkippingTables(ctx, dbURI, conv, sourceProfile.Driver, existingTableIds, statements)
+	if err != nil {
+		return fmt.Errorf("can't update database schema: %v", err)
+	}
 	return nil
 }
 
@@ -219,6 +525,23 @@ func migrateData(ctx context.Context, migrationProjectId string, targetProfile p
 		logger.Log.Info(fmt.Sprintf("Schema validated successfully for data migration for db %s\n", dbURI))
 	}
 
+	// Foreign keys dropped along with the tables to reload.
+	var reloadFkStmts []string
+	if cmd.reloadTables != "" {
+		tableIds, err := reloadTableIds(conv, cmd.reloadTables)
+		if err != nil {
+			return nil, err
+		}
+		spA, err := spanneraccessor.NewSpannerAccessorClientImplWithSpannerClient(ctx, dbURI)
+		if err != nil {
+			return nil, err
+		}
+		reloadFkStmts, err = spA.ReloadTables(ctx, dbURI, conv, tableIds, cmd.reloadMode, sourceProfile.Driver)
+		if err != nil {
+			return nil, fmt.Errorf("can't empty the tables to reload: %v", err)
+		}
+	}
+
 	// If migration type is Minimal Downtime, validate if required resources can be generated
 	if !conv.UI && sourceProfile.Driver == constants.MYSQL && sourceProfile.Ty == profiles.SourceProfileTypeConfig && sourceProfile.Config.ConfigType == constants.DATAFLOW_MIGRATION {
 		err = ValidateResourceGenerationHelper(ctx, migrationProjectId, targetProfile.Conn.Sp.Instance, sourceProfile, conv)
@@ -235,7 +558,19 @@ func migrateData(ctx context.Context, migrationProjectId string, targetProfile p
 		return nil, err
 	}
 	conv.Audit.Progress.UpdateProgress("Data migration complete.", completionPercentage, internal.DataMigrationComplete)
-	if !cmd.SkipForeignKeys {
+	// Foreign keys of an interrupted migration are added once it is resumed.
+	switch {
+	case cmd.reloadTables != "" && conv.Interrupted() && len(reloadFkStmts) > 0:
+		logger.Log.Warn(fmt.Sprintf("The foreign keys dropped to reload tables must be recreated once their rows are migrated: %s", strings.Join(reloadFkStmts, "; ")))
+	case cmd.reloadTables != "":
+		// The other foreign keys of the database are left untouched, and
+		// the dropped ones are recreated even with --skip-foreign-keys.
+		spA, err := spanneraccessor.NewSpannerAccessorClientImpl(ctx)
+		if err != nil {
+			return bw, err
+		}
+		spA.CreateForeignKeys(ctx, dbURI, conv, reloadFkStmts)
+	case !cmd.SkipForeignKeys && !conv.Interrupted():
 		spA, err := spanneraccessor.NewSpannerAccessorClientImpl(ctx)
 		if err != nil {
 			return bw, err
@@ -245,19 +580,54 @@ func migrateData(ctx context.Context, migrationProjectId string, targetProfile p
 	return bw, nil
 }
 
+// reloadTableIds returns the ids of the tables of conv named in tables, a
+// comma separated list of Spanner table names or "*" for all the tables,
+// along with the tables interleaved in them, whose rows would otherwise be
+// deleted or dropped with their parents.
+func reloadTableIds(conv *internal.Conv, tables string) ([]string, error) {
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(tables, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for tableId, table := range conv.SpSchema {
+			if name == "*" || strings.EqualFold(table.Name, name) {
+				selected[tableId] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("table %s of --reload-tables not found in the session", name)
+		}
+	}
+	for added := true; added; {
+		added = false
+		for tableId, table := range conv.SpSchema {
+			if !selected[tableId] && selected[table.ParentTable.Id] {
+				logger.Log.Info(fmt.Sprintf("Reloading table %s as well, since it is interleaved in a reloaded table", table.Name))
+				selected[tableId] = true
+				added = true
+			}
+		}
+	}
+	var tableIds []string
+	for tableId := range selected {
+		tableIds = append(tableIds, tableId)
+	}
+	sort.Strings(tableIds)
+	return tableIds, nil
+}
+
 func migrateSchemaAndData(ctx context.Context, migrationProjectId string, targetProfile profiles.TargetProfile, sourceProfile profiles.SourceProfile,
 	ioHelper *utils.IOStreams, conv *internal.Conv, dbURI string, adminClient *database.DatabaseAdminClient, client *sp.Client, cmd *SchemaAndDataCmd) (*writer.BatchWriter, error) {
 	spA, err := spanneraccessor.NewSpannerAccessorClientImpl(ctx)
 	if err != nil {
 		return nil, err
 	}
-	tablesExistingOnSpanner, err := spA.GetTableNamesFromSpanner(ctx, conv.SpDialect, dbURI, client)
-	if err != nil {
-		return nil, err
-	}
-	err = spA.CreateOrUpdateDatabase(ctx, dbURI, sourceProfile.Driver, conv, sourceProfile.Config.ConfigType, tablesExistingOnSpanner)
+	err = createOrUpdateDatabase(ctx, spA, targetProfile, sourceProfile, conv, dbURI, client)
 	if err != nil {
-		err = fmt.Errorf("can't create/update database: %v", err)
 		return nil, err
 	}
 	metricsPopulation(ctx, sourceProfile.Driver, conv)
@@ -280,7 +650,7 @@ func migrateSchemaAndData(ctx context.Context, migrationProjectId string, target
 	}
 
 	conv.Audit.Progress.UpdateProgress("Data migration complete.", completionPercentage, internal.DataMigrationComplete)
-	if !cmd.SkipForeignKeys {
+	if !cmd.SkipForeignKeys && !conv.Interrupted() {
 		spA.UpdateDDLForeignKeys(ctx, dbURI, conv, sourceProfile.Driver, sourceProfile.Config.ConfigType)
 	}
 	return bw, nil
@@ -15,8 +15,15 @@
 package cmd
 
 import (
+	"flag"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -72,3 +79,66 @@ func TestGetSessionFileName(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyProfileFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "migration.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+source:
+  type: mysql
+  params:
+    host: localhost
+orchestration:
+  project: file-project
+  dryRun: true
+  writeLimit: 10
+`), 0644))
+	cmd := &SchemaCmd{}
+	f := flag.NewFlagSet("schema", flag.ContinueOnError)
+	cmd.SetFlags(f)
+	assert.NoError(t, f.Parse([]string{"-project=cli-project"}))
+	// writeLimit isn't a flag of the schema command.
+	assert.Error(t, applyProfileFile(f, path))
+
+	dataCmd := &DataCmd{}
+	f = flag.NewFlagSet("data", flag.ContinueOnError)
+	dataCmd.SetFlags(f)
+	assert.NoError(t, f.Parse([]string{"-project=cli-project"}))
+	assert.NoError(t, applyProfileFile(f, path))
+	assert.Equal(t, "mysql", dataCmd.source)
+	assert.Equal(t, "host=localhost", dataCmd.sourceProfile)
+	assert.Equal(t, "cli-project", dataCmd.project)
+	assert.True(t, dataCmd.dryRun)
+	assert.Equal(t, int64(10), dataCmd.WriteLimit)
+}
+
+func TestMigratedByDataflow(t *testing.T) {
+	assert.False(t, migratedByDataflow(profiles.SourceProfile{}))
+	assert.False(t, migratedByDataflow(profiles.SourceProfile{Ty: profiles.SourceProfileTypeConfig, Config: profiles.SourceProfileConfig{ConfigType: constants.BULK_MIGRATION}}))
+	assert.True(t, migratedByDataflow(profiles.SourceProfile{Ty: profiles.SourceProfileTypeConfig, Config: profiles.SourceProfileConfig{ConfigType: constants.DATAFLOW_MIGRATION}}))
+	assert.True(t, migratedByDataflow(profiles.SourceProfile{Conn: profiles.SourceProfileConnection{Streaming: true}}))
+}
+
+func TestReloadTableIds(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SpSchema = ddl.Schema{
+		"t1": {Name: "Users", Id: "t1"},
+		"t2": {Name: "orders", Id: "t2", ParentTable: ddl.InterleavedParent{Id: "t1"}},
+		"t3": {Name: "items", Id: "t3", ParentTable: ddl.InterleavedParent{Id: "t2"}},
+		"t4": {Name: "audit", Id: "t4"},
+	}
+	// The tables interleaved in reloaded tables are reloaded as well.
+	tableIds, err := reloadTableIds(conv, "users, audit")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"t1", "t2", "t3", "t4"}, tableIds)
+
+	tableIds, err = reloadTableIds(conv, "items")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"t3"}, tableIds)
+
+	tableIds, err = reloadTableIds(conv, "*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"t1", "t2", "t3", "t4"}, tableIds)
+
+	_, err = reloadTableIds(conv, "customers")
+	assert.Error(t, err)
+}
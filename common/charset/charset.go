@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package charset maps the charsets of source databases to their encodings.
+// It should not import any Spanner migration tool packages, so that the
+// profiles can check the charsets they are given.
+package charset
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+)
+
+// charsetEncodings maps MySQL charsets to their encoding. Note that MySQL's
+// latin1 is cp1252 rather than ISO 8859-1.
+var charsetEncodings = map[string]encoding.Encoding{
+	"latin1":   charmap.Windows1252,
+	"latin2":   charmap.ISO8859_2,
+	"latin5":   charmap.ISO8859_9,
+	"latin7":   charmap.ISO8859_13,
+	"greek":    charmap.ISO8859_7,
+	"hebrew":   charmap.ISO8859_8,
+	"cp1250":   charmap.Windows1250,
+	"cp1251":   charmap.Windows1251,
+	"cp1256":   charmap.Windows1256,
+	"cp1257":   charmap.Windows1257,
+	"cp850":    charmap.CodePage850,
+	"cp852":    charmap.CodePage852,
+	"cp866":    charmap.CodePage866,
+	"koi8r":    charmap.KOI8R,
+	"koi8u":    charmap.KOI8U,
+	"macroman": charmap.Macintosh,
+	"gbk":      simplifiedchinese.GBK,
+	"gb2312":   simplifiedchinese.GBK,
+	"gb18030":  simplifiedchinese.GB18030,
+	"big5":     traditionalchinese.Big5,
+	"sjis":     japanese.ShiftJIS,
+	"cp932":    japanese.ShiftJIS,
+	"ujis":     japanese.EUCJP,
+	"eucjpms":  japanese.EUCJP,
+	"euckr":    korean.EUCKR,
+}
+
+// IsUTF8 returns true for charsets whose values need no conversion
+// to UTF-8.
+func IsUTF8(charset string) bool {
+	switch strings.ToLower(charset) {
+	case "", "utf8", "utf8mb3", "utf8mb4", "ascii", "binary":
+		return true
+	}
+	return false
+}
+
+// Encoding returns the encoding of charset, or nil for UTF-8
+// charsets.
+func Encoding(charset string) (encoding.Encoding, error) {
+	if IsUTF8(charset) {
+		return nil, nil
+	}
+	if e, ok := charsetEncodings[strings.ToLower(charset)]; ok {
+		return e, nil
+	}
+	return nil, fmt.Errorf("unsupported charset %s", charset)
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package charset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoding(t *testing.T) {
+	for _, charset := range []string{"", "utf8", "UTF8MB4", "binary"} {
+		e, err := Encoding(charset)
+		assert.NoError(t, err)
+		assert.Nil(t, e, charset)
+	}
+	e, err := Encoding("Latin1")
+	assert.NoError(t, err)
+	assert.NotNil(t, e)
+	_, err = Encoding("ebcdic")
+	assert.Error(t, err)
+}
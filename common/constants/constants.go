@@ -40,6 +40,9 @@ const (
 	// CSV is the driver name when loading data using csv.
 	CSV string = "csv"
 
+	// JSONL is the source format for importing newline delimited JSON files.
+	JSONL string = "jsonl"
+
 	// ORACLE is the driver name for Oracle.
 	// This is an experimental driver; implementation in progress.
 	ORACLE string = "oracle"
@@ -47,6 +50,14 @@ const (
 	// CASSANDRA is the driver name for Cassandra.
 	CASSANDRA string = "cassandra"
 
+	// MONGODB is the driver name for MongoDB. Collections are read from
+	// mongoexport files.
+	MONGODB string = "mongodb"
+
+	// DB2 is the driver name for IBM Db2 for Linux, Unix and Windows.
+	// Only schema conversion is supported.
+	DB2 string = "db2"
+
 	// Target db for which schema is being generated.
 	// This can be removed once the support for global flags is removed.
 	TargetSpanner              string = "spanner"
@@ -83,6 +94,10 @@ const (
 	DATAFLOW_MIGRATION = "dataflow"
 	// DMS migration type
 	DMS_MIGRATION = "dms"
+	// Multi database modes, i.e. how a source profile naming several databases
+	// is migrated.
+	MULTI_DB_SEPARATE = "separate"
+	MULTI_DB_MERGED   = "merged"
 
 	SESSION_FILE = "sessionFile"
 
@@ -107,12 +122,36 @@ const (
 	// Metadata table names
 	SMT_JOB_TABLE      string = "SMT_JOB"
 	SMT_RESOURCE_TABLE string = "SMT_RESOURCE"
+	// Table of the metadata database recording the history of migration runs.
+	SMT_MIGRATION_RUN_TABLE string = "SMT_MIGRATION_RUN"
 	// Auto Generated Keys
 	UUID           string = "UUID"
 	SEQUENCE       string = "Sequence"
 	AUTO_INCREMENT string = "Auto Increment"
 	SERIAL         string = "Serial" // For PostgreSQL source DBs (the Postgres dialect uses IDENTITY for auto-gen)
 	IDENTITY       string = "Identity"
+	// Strategies for synthetic primary keys added to tables without a primary key.
+	SYNTH_PK_STRING_SEQUENCE       string = "string_sequence"
+	SYNTH_PK_UUID                  string = "uuid"
+	SYNTH_PK_BIT_REVERSED_SEQUENCE string = "bit_reversed_sequence"
+	SYNTH_PK_COMPOSITE             string = "composite"
+	// Policies mapping MySQL BIGINT UNSIGNED columns, whose values may not fit in INT64.
+	UNSIGNED_INT_INT64   string = "int64"
+	UNSIGNED_INT_NUMERIC string = "numeric"
+	UNSIGNED_INT_STRING  string = "string"
+	// Policies converting source values without a time zone to Spanner timestamps.
+	TIMEZONE_POLICY_UTC    string = "utc"
+	TIMEZONE_POLICY_SOURCE string = "source"
+	// Modes of migrating tables which already exist in the target database.
+	EXISTING_TABLES_FAIL  string = "fail"
+	EXISTING_TABLES_SKIP  string = "skip-existing"
+	EXISTING_TABLES_MERGE string = "merge-with-diff"
+	// Modes of deleting the rows of the tables reloaded by a data migration.
+	RELOAD_DELETE   string = "delete"
+	RELOAD_RECREATE string = "recreate"
+	// Policies writing values with more digits after the decimal point than a NUMERIC can store.
+	NUMERIC_PRECISION_ROUND string = "round"
+	NUMERIC_PRECISION_FAIL  string = "fail"
 	// Default gcs path of the Dataflow template.
 	DEFAULT_TEMPLATE_PATH string = "gs://dataflow-templates/latest/flex/Cloud_Datastream_to_Spanner"
 
@@ -133,6 +172,7 @@ const (
 	DEFAULT_GENERATED  = "DEFAULT_GENERATED"
 	STORED_GENERATED   = "STORED"
 	VIRTUAL_GENERATED  = "VIRTUAL"
+	VIEW_EXPRESSION    = "VIEW"
 	TEMP_DB            = "smt-staging-db"
 	DB_URI             = "projects/%s/instances/%s/databases/%s"
 
@@ -0,0 +1,183 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package deadletter implements outputs for the bad rows of a data migration,
+// so that they can be repaired and re-imported later.
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	storageclient "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients/storage"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	bigquery "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	gcsPrefix      = "gs://"
+	bigQueryPrefix = "bq://"
+	// bigQueryBatchSize is the number of rows sent per BigQuery insert.
+	bigQueryBatchSize = 500
+)
+
+// NewWriter returns a dead-letter writer for uri, which is one of:
+//   - gs://bucket/object: newline delimited JSON written to Cloud Storage.
+//   - bq://project.dataset.table: rows streamed into a BigQuery table, which
+//     is created if it does not exist.
+//   - a local file path: newline delimited JSON written to the file.
+func NewWriter(ctx context.Context, uri string) (internal.DeadLetterWriter, error) {
+	switch {
+	case strings.HasPrefix(uri, gcsPrefix):
+		bucket, object, found := strings.Cut(strings.TrimPrefix(uri, gcsPrefix), "/")
+		if !found || bucket == "" || object == "" {
+			return nil, fmt.Errorf("invalid GCS path %s, expected gs://bucket/object", uri)
+		}
+		client, err := storageclient.NewStorageClientImpl(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("can't create storage client: %v", err)
+		}
+		return newJSONWriter(client.Bucket(bucket).Object(object).NewWriter(ctx)), nil
+	case strings.HasPrefix(uri, bigQueryPrefix):
+		return newBigQueryWriter(ctx, uri)
+	default:
+		f, err := os.Create(uri)
+		if err != nil {
+			return nil, fmt.Errorf("can't create dead-letter file %s: %v", uri, err)
+		}
+		return newJSONWriter(f), nil
+	}
+}
+
+// jsonWriter writes bad rows as newline delimited JSON.
+type jsonWriter struct {
+	mu  sync.Mutex
+	w   io.WriteCloser
+	enc *json.Encoder
+}
+
+func newJSONWriter(w io.WriteCloser) *jsonWriter {
+	return &jsonWriter{w: w, enc: json.NewEncoder(w)}
+}
+
+func (jw *jsonWriter) Write(r internal.BadRow) error {
+	jw.mu.Lock()
+	defer jw.mu.Unlock()
+	return jw.enc.Encode(r)
+}
+
+func (jw *jsonWriter) Close() error {
+	jw.mu.Lock()
+	defer jw.mu.Unlock()
+	return jw.w.Close()
+}
+
+// bigQueryWriter buffers bad rows and streams them into a BigQuery table.
+type bigQueryWriter struct {
+	mu        sync.Mutex
+	ctx       context.Context
+	tabledata *bigquery.TabledataService
+	projectId string
+	datasetId string
+	tableId   string
+	rows      []*bigquery.TableDataInsertAllRequestRows
+}
+
+// parseBigQueryURI splits bq://project.dataset.table into its parts.
+func parseBigQueryURI(uri string) (string, string, string, error) {
+	parts := strings.Split(strings.TrimPrefix(uri, bigQueryPrefix), ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("invalid BigQuery table %s, expected bq://project.dataset.table", uri)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func newBigQueryWriter(ctx context.Context, uri string) (*bigQueryWriter, error) {
+	projectId, datasetId, tableId, err := parseBigQueryURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := bigquery.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't create BigQuery client: %v", err)
+	}
+	if _, err := svc.Tables.Get(projectId, datasetId, tableId).Context(ctx).Do(); err != nil {
+		if e, ok := err.(*googleapi.Error); !ok || e.Code != http.StatusNotFound {
+			return nil, fmt.Errorf("can't get BigQuery table %s: %v", uri, err)
+		}
+		table := &bigquery.Table{
+			TableReference: &bigquery.TableReference{ProjectId: projectId, DatasetId: datasetId, TableId: tableId},
+			Schema:         badRowSchema(),
+		}
+		if _, err := svc.Tables.Insert(projectId, datasetId, table).Context(ctx).Do(); err != nil {
+			return nil, fmt.Errorf("can't create BigQuery table %s: %v", uri, err)
+		}
+	}
+	return &bigQueryWriter{ctx: ctx, tabledata: svc.Tabledata, projectId: projectId, datasetId: datasetId, tableId: tableId}, nil
+}
+
+// badRowSchema is the BigQuery schema of internal.BadRow.
+func badRowSchema() *bigquery.TableSchema {
+	return &bigquery.TableSchema{Fields: []*bigquery.TableFieldSchema{
+		{Name: "table", Type: "STRING"},
+		{Name: "cols", Type: "STRING", Mode: "REPEATED"},
+		{Name: "vals", Type: "STRING", Mode: "REPEATED"},
+		{Name: "reason", Type: "STRING"},
+	}}
+}
+
+func (bw *bigQueryWriter) Write(r internal.BadRow) error {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	bw.rows = append(bw.rows, &bigquery.TableDataInsertAllRequestRows{Json: map[string]bigquery.JsonValue{
+		"table":  r.Table,
+		"cols":   r.Cols,
+		"vals":   r.Vals,
+		"reason": r.Reason,
+	}})
+	if len(bw.rows) < bigQueryBatchSize {
+		return nil
+	}
+	return bw.flush()
+}
+
+func (bw *bigQueryWriter) Close() error {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	return bw.flush()
+}
+
+// flush inserts the buffered rows. Callers must hold bw.mu.
+func (bw *bigQueryWriter) flush() error {
+	if len(bw.rows) == 0 {
+		return nil
+	}
+	rows := bw.rows
+	bw.rows = nil
+	resp, err := bw.tabledata.InsertAll(bw.projectId, bw.datasetId, bw.tableId, &bigquery.TableDataInsertAllRequest{Rows: rows}).Context(bw.ctx).Do()
+	if err != nil {
+		return fmt.Errorf("can't insert %d rows into BigQuery: %v", len(rows), err)
+	}
+	if len(resp.InsertErrors) > 0 {
+		return fmt.Errorf("%d of %d rows couldn't be inserted into BigQuery, e.g. %v", len(resp.InsertErrors), len(rows), resp.InsertErrors[0].Errors)
+	}
+	return nil
+}
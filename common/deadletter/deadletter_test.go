@@ -0,0 +1,54 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadletter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWriter_LocalFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad_rows.json")
+	w, err := NewWriter(context.Background(), path)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Write(internal.BadRow{Table: "t1", Cols: []string{"a", "b"}, Vals: []string{"1", "x"}, Reason: "can't convert x to INT64"}))
+	assert.Nil(t, w.Write(internal.BadRow{Table: "t2", Cols: []string{"c"}, Vals: []string{"y"}}))
+	assert.Nil(t, w.Close())
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"table":"t1","cols":["a","b"],"vals":["1","x"],"reason":"can't convert x to INT64"}
+{"table":"t2","cols":["c"],"vals":["y"],"reason":""}
+`, string(content))
+}
+
+func TestNewWriter_InvalidURI(t *testing.T) {
+	_, err := NewWriter(context.Background(), "gs://bucket")
+	assert.NotNil(t, err)
+	_, err = NewWriter(context.Background(), "bq://project.dataset")
+	assert.NotNil(t, err)
+}
+
+func TestParseBigQueryURI(t *testing.T) {
+	project, dataset, table, err := parseBigQueryURI("bq://my-project.migration.bad_rows")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"my-project", "migration", "bad_rows"}, []string{project, dataset, table})
+	_, _, _, err = parseBigQueryURI("bq://my-project..bad_rows")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,236 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// avroBlockRows is the number of rows of a block of an Avro file.
+const avroBlockRows = 1000
+
+// avroEncoder writes rows to an Avro object container file, without
+// compression. Columns are nullable fields of the Avro type matching their
+// Spanner type: BOOL, INT64, FLOAT32, FLOAT64 and BYTES values are written as
+// boolean, long, float, double and bytes, and the other values, e.g. NUMERIC,
+// DATE and TIMESTAMP, as strings.
+type avroEncoder struct {
+	w     io.Writer
+	cols  []ddl.ColumnDef
+	sync  [16]byte
+	block bytes.Buffer
+	rows  int64
+}
+
+// avroType returns the Avro type of the values of a Spanner type.
+func avroType(t ddl.Type) string {
+	switch t.Name {
+	case ddl.Bool:
+		return "boolean"
+	case ddl.Int64:
+		return "long"
+	case ddl.Float32:
+		return "float"
+	case ddl.Float64:
+		return "double"
+	case ddl.Bytes:
+		return "bytes"
+	}
+	return "string"
+}
+
+// avroSchema returns the schema of the records of a table: nullable fields
+// named after its columns, arrays having nullable elements.
+func avroSchema(name string, cols []ddl.ColumnDef) ([]byte, error) {
+	type field struct {
+		Name    string      `json:"name"`
+		Type    interface{} `json:"type"`
+		Default interface{} `json:"default"`
+	}
+	var fields []field
+	for _, col := range cols {
+		var t interface{} = avroType(col.T)
+		if col.T.IsArray {
+			t = map[string]interface{}{"type": "array", "items": []interface{}{"null", t}}
+		}
+		fields = append(fields, field{Name: col.Name, Type: []interface{}{"null", t}})
+	}
+	return json.Marshal(map[string]interface{}{"type": "record", "name": name, "fields": fields})
+}
+
+func newAvroEncoder(w io.Writer, name string, cols []ddl.ColumnDef) (*avroEncoder, error) {
+	e := &avroEncoder{w: w, cols: cols}
+	if _, err := rand.Read(e.sync[:]); err != nil {
+		return nil, err
+	}
+	schema, err := avroSchema(name, cols)
+	if err != nil {
+		return nil, err
+	}
+	var header bytes.Buffer
+	header.WriteString("Obj\x01")
+	writeLong(&header, 2)
+	writeBytes(&header, []byte("avro.schema"))
+	writeBytes(&header, schema)
+	writeBytes(&header, []byte("avro.codec"))
+	writeBytes(&header, []byte("null"))
+	writeLong(&header, 0)
+	header.Write(e.sync[:])
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *avroEncoder) write(vals []interface{}) error {
+	var record bytes.Buffer
+	for i, v := range vals {
+		if err := writeField(&record, e.cols[i].T, v); err != nil {
+			return fmt.Errorf("column %s: %v", e.cols[i].Name, err)
+		}
+	}
+	e.block.Write(record.Bytes())
+	e.rows++
+	if e.rows == avroBlockRows {
+		return e.flush()
+	}
+	return nil
+}
+
+func (e *avroEncoder) close() error {
+	return e.flush()
+}
+
+// flush writes the rows buffered as a block.
+func (e *avroEncoder) flush() error {
+	if e.rows == 0 {
+		return nil
+	}
+	var header bytes.Buffer
+	writeLong(&header, e.rows)
+	writeLong(&header, int64(e.block.Len()))
+	for _, b := range [][]byte{header.Bytes(), e.block.Bytes(), e.sync[:]} {
+		if _, err := e.w.Write(b); err != nil {
+			return err
+		}
+	}
+	e.block.Reset()
+	e.rows = 0
+	return nil
+}
+
+// writeField writes v, the value of a nullable field of Spanner type t, as
+// the branch of its union.
+func writeField(buf *bytes.Buffer, t ddl.Type, v interface{}) error {
+	if !t.IsArray {
+		x, ok := scalar(v)
+		if !ok {
+			writeLong(buf, 0)
+			return nil
+		}
+		writeLong(buf, 1)
+		return writeScalar(buf, t, x)
+	}
+	if v == nil {
+		writeLong(buf, 0)
+		return nil
+	}
+	l, err := elements(v)
+	if err != nil {
+		return err
+	}
+	writeLong(buf, 1)
+	if len(l) > 0 {
+		writeLong(buf, int64(len(l)))
+		for _, e := range l {
+			if err := writeField(buf, ddl.Type{Name: t.Name}, e); err != nil {
+				return err
+			}
+		}
+	}
+	writeLong(buf, 0)
+	return nil
+}
+
+// writeScalar writes x, a non NULL value of Spanner type t.
+func writeScalar(buf *bytes.Buffer, t ddl.Type, x interface{}) error {
+	switch avroType(t) {
+	case "boolean":
+		b, ok := x.(bool)
+		if !ok {
+			return fmt.Errorf("can't export %v (%T) as boolean", x, x)
+		}
+		if b {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case "long":
+		switch n := x.(type) {
+		case int64:
+			writeLong(buf, n)
+		case string:
+			i, err := strconv.ParseInt(n, 10, 64)
+			if err != nil {
+				return fmt.Errorf("can't export %q as long: %v", n, err)
+			}
+			writeLong(buf, i)
+		default:
+			return fmt.Errorf("can't export %v (%T) as long", x, x)
+		}
+	case "float":
+		f, ok := x.(float32)
+		if !ok {
+			return fmt.Errorf("can't export %v (%T) as float", x, x)
+		}
+		binary.Write(buf, binary.LittleEndian, math.Float32bits(f))
+	case "double":
+		f, ok := x.(float64)
+		if !ok {
+			return fmt.Errorf("can't export %v (%T) as double", x, x)
+		}
+		binary.Write(buf, binary.LittleEndian, math.Float64bits(f))
+	case "bytes":
+		b, ok := x.([]byte)
+		if !ok {
+			return fmt.Errorf("can't export %v (%T) as bytes", x, x)
+		}
+		writeBytes(buf, b)
+	default:
+		writeBytes(buf, []byte(formatScalar(x)))
+	}
+	return nil
+}
+
+// writeLong writes n with the zig-zag variable length encoding of Avro.
+func writeLong(buf *bytes.Buffer, n int64) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutVarint(b[:], n)])
+}
+
+// writeBytes writes the length of b followed by b.
+func writeBytes(buf *bytes.Buffer, b []byte) {
+	writeLong(buf, int64(len(b)))
+	buf.Write(b)
+}
@@ -0,0 +1,243 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	bigquery "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/googleapi"
+)
+
+// bigQueryBatchSize is the number of rows sent per BigQuery insert.
+const bigQueryBatchSize = 500
+
+// bigQueryWriter streams converted rows into BigQuery staging tables, one per
+// Spanner table, named after it.
+type bigQueryWriter struct {
+	mu        sync.Mutex
+	ctx       context.Context
+	conv      *internal.Conv
+	svc       *bigquery.Service
+	projectId string
+	datasetId string
+	tables    map[string]*bigQueryTable
+}
+
+// bigQueryTable is a staging table and its rows not inserted yet.
+type bigQueryTable struct {
+	cols    []ddl.ColumnDef
+	index   map[string]int
+	rows    []*bigquery.TableDataInsertAllRequestRows
+	pending []pendingRow // Source of rows, reported if their insert fails.
+}
+
+// pendingRow is a row buffered for a BigQuery insert.
+type pendingRow struct {
+	cols []string
+	vals []interface{}
+}
+
+// parseBigQueryDataset splits bq://project.dataset into its parts.
+func parseBigQueryDataset(uri string) (string, string, error) {
+	parts := strings.Split(strings.TrimPrefix(uri, bigQueryPrefix), ".")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid BigQuery dataset %s, expected bq://project.dataset", uri)
+	}
+	return parts[0], parts[1], nil
+}
+
+func newBigQueryWriter(ctx context.Context, conv *internal.Conv, uri string) (*bigQueryWriter, error) {
+	projectId, datasetId, err := parseBigQueryDataset(uri)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := bigquery.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't create BigQuery client: %v", err)
+	}
+	return &bigQueryWriter{ctx: ctx, conv: conv, svc: svc, projectId: projectId, datasetId: datasetId, tables: make(map[string]*bigQueryTable)}, nil
+}
+
+// bigQueryType returns the BigQuery type of the values of a Spanner type.
+// GoogleSQL NUMERIC values have the precision of BigQuery NUMERIC values,
+// PostgreSQL ones need BIGNUMERIC.
+func bigQueryType(t ddl.Type, dialect string) string {
+	switch t.Name {
+	case ddl.Bool:
+		return "BOOLEAN"
+	case ddl.Int64:
+		return "INTEGER"
+	case ddl.Float32, ddl.Float64:
+		return "FLOAT"
+	case ddl.Numeric:
+		if dialect == constants.DIALECT_POSTGRESQL {
+			return "BIGNUMERIC"
+		}
+		return "NUMERIC"
+	case ddl.Bytes, ddl.Date, ddl.Timestamp, ddl.JSON:
+		return t.Name
+	}
+	return "STRING"
+}
+
+// bigQuerySchema returns the schema of the staging table of columns cols.
+// Array columns are repeated fields.
+func bigQuerySchema(cols []ddl.ColumnDef, dialect string) *bigquery.TableSchema {
+	schema := &bigquery.TableSchema{}
+	for _, col := range cols {
+		mode := "NULLABLE"
+		if col.T.IsArray {
+			mode = "REPEATED"
+		}
+		schema.Fields = append(schema.Fields, &bigquery.TableFieldSchema{Name: col.Name, Type: bigQueryType(col.T, dialect), Mode: mode})
+	}
+	return schema
+}
+
+// bigQueryRow returns the JSON values of a row with the columns cols. Values
+// are in their text representation, which BigQuery converts to the type of
+// their field. NULL elements of arrays are dropped, since repeated fields
+// can't hold them.
+func bigQueryRow(cols []ddl.ColumnDef, spCols []string, spVals []interface{}, index map[string]int) (map[string]bigquery.JsonValue, error) {
+	row := make(map[string]bigquery.JsonValue)
+	for i, name := range spCols {
+		j, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("can't export column %s: not a column of the table", name)
+		}
+		if !cols[j].T.IsArray {
+			if x, ok := scalar(spVals[i]); ok {
+				row[name] = formatScalar(x)
+			}
+			continue
+		}
+		if spVals[i] == nil {
+			continue
+		}
+		l, err := elements(spVals[i])
+		if err != nil {
+			return nil, err
+		}
+		a := []string{}
+		for _, e := range l {
+			if x, ok := scalar(e); ok {
+				a = append(a, formatScalar(x))
+			}
+		}
+		row[name] = a
+	}
+	return row, nil
+}
+
+func (bw *bigQueryWriter) Write(spTable string, spCols []string, spVals []interface{}) error {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	t, err := bw.table(spTable)
+	if err != nil {
+		return err
+	}
+	row, err := bigQueryRow(t.cols, spCols, spVals, t.index)
+	if err != nil {
+		return fmt.Errorf("can't export row of table %s: %v", spTable, err)
+	}
+	if len(t.rows) >= bigQueryBatchSize {
+		// The buffered rows are inserted before adding the row, which isn't
+		// counted as exported yet. Failed rows are counted by flush.
+		if err := bw.flush(spTable, t); err != nil {
+			bw.conv.Unexpected(err.Error())
+		}
+	}
+	t.rows = append(t.rows, &bigquery.TableDataInsertAllRequestRows{Json: row})
+	t.pending = append(t.pending, pendingRow{cols: spCols, vals: spVals})
+	return nil
+}
+
+// table returns the staging table of spTable, creating it in BigQuery if it
+// does not exist. Callers must hold bw.mu.
+func (bw *bigQueryWriter) table(spTable string) (*bigQueryTable, error) {
+	if t, ok := bw.tables[spTable]; ok {
+		return t, nil
+	}
+	cols, index, err := tableColumns(bw.conv, spTable)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := bw.svc.Tables.Get(bw.projectId, bw.datasetId, spTable).Context(bw.ctx).Do(); err != nil {
+		if e, ok := err.(*googleapi.Error); !ok || e.Code != http.StatusNotFound {
+			return nil, fmt.Errorf("can't get BigQuery table %s.%s: %v", bw.datasetId, spTable, err)
+		}
+		table := &bigquery.Table{
+			TableReference: &bigquery.TableReference{ProjectId: bw.projectId, DatasetId: bw.datasetId, TableId: spTable},
+			Schema:         bigQuerySchema(cols, bw.conv.SpDialect),
+		}
+		if _, err := bw.svc.Tables.Insert(bw.projectId, bw.datasetId, table).Context(bw.ctx).Do(); err != nil {
+			return nil, fmt.Errorf("can't create BigQuery table %s.%s: %v", bw.datasetId, spTable, err)
+		}
+	}
+	t := &bigQueryTable{cols: cols, index: index}
+	bw.tables[spTable] = t
+	return t, nil
+}
+
+// flush inserts the buffered rows of a table. BigQuery rejects the whole
+// insert if any row is invalid, so all the rows of a failed insert are
+// reported to the conversion as failed exports. Callers must hold bw.mu.
+func (bw *bigQueryWriter) flush(spTable string, t *bigQueryTable) error {
+	if len(t.rows) == 0 {
+		return nil
+	}
+	rows, pending := t.rows, t.pending
+	t.rows, t.pending = nil, nil
+	resp, err := bw.svc.Tabledata.InsertAll(bw.projectId, bw.datasetId, spTable, &bigquery.TableDataInsertAllRequest{Rows: rows}).Context(bw.ctx).Do()
+	if err != nil {
+		err = fmt.Errorf("can't insert %d rows into BigQuery table %s.%s: %v", len(rows), bw.datasetId, spTable, err)
+	} else if len(resp.InsertErrors) > 0 {
+		err = fmt.Errorf("%d of %d rows couldn't be inserted into BigQuery table %s.%s, e.g. %v", len(resp.InsertErrors), len(rows), bw.datasetId, spTable, resp.InsertErrors[0].Errors)
+	}
+	if err != nil {
+		for _, r := range pending {
+			bw.conv.ExportFailed(spTable, r.cols, r.vals, err)
+		}
+	}
+	return err
+}
+
+// Close inserts the rows buffered for all tables, and returns the first
+// error.
+func (bw *bigQueryWriter) Close() error {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	var tables []string
+	for spTable := range bw.tables {
+		tables = append(tables, spTable)
+	}
+	sort.Strings(tables)
+	var firstErr error
+	for _, spTable := range tables {
+		if err := bw.flush(spTable, bw.tables[spTable]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// scalar returns the value held by v, a converted value or an element of a
+// converted array, and false if it is NULL.
+func scalar(v interface{}) (interface{}, bool) {
+	switch x := v.(type) {
+	case nil:
+		return nil, false
+	case spanner.NullString:
+		return x.StringVal, x.Valid
+	case spanner.NullInt64:
+		return x.Int64, x.Valid
+	case spanner.NullFloat64:
+		return x.Float64, x.Valid
+	case spanner.NullFloat32:
+		return x.Float32, x.Valid
+	case spanner.NullBool:
+		return x.Bool, x.Valid
+	case spanner.NullDate:
+		return x.Date, x.Valid
+	case spanner.NullTime:
+		return x.Time, x.Valid
+	case spanner.NullNumeric:
+		return &x.Numeric, x.Valid
+	case spanner.NullJSON:
+		if !x.Valid {
+			return nil, false
+		}
+		return x.String(), true
+	case spanner.PGNumeric:
+		return x.Numeric, x.Valid
+	case []byte:
+		return x, x != nil
+	}
+	// Columns written with the commit timestamp get the time of the export.
+	if v == internal.CommitTimestamp {
+		return time.Now(), true
+	}
+	return v, true
+}
+
+// elements returns the elements of v, a converted array value.
+func elements(v interface{}) ([]interface{}, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("can't export %v (%T) as an array", v, v)
+	}
+	l := make([]interface{}, rv.Len())
+	for i := range l {
+		l[i] = rv.Index(i).Interface()
+	}
+	return l, nil
+}
+
+// formatScalar returns the text representation of a non NULL value, as
+// accepted by the Spanner import of text files.
+func formatScalar(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(x), 'g', -1, 32)
+	case bool:
+		return strconv.FormatBool(x)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(x)
+	case civil.Date:
+		return x.String()
+	case time.Time:
+		return x.UTC().Format(time.RFC3339Nano)
+	case *big.Rat:
+		return spanner.NumericString(x)
+	}
+	return fmt.Sprint(v)
+}
+
+// formatValue returns the text representation of v, arrays being JSON
+// arrays, and false if it is NULL.
+func formatValue(col ddl.ColumnDef, v interface{}) (string, bool, error) {
+	if !col.T.IsArray {
+		x, ok := scalar(v)
+		if !ok {
+			return "", false, nil
+		}
+		return formatScalar(x), true, nil
+	}
+	if v == nil {
+		return "", false, nil
+	}
+	l, err := elements(v)
+	if err != nil {
+		return "", false, err
+	}
+	a := make([]interface{}, len(l))
+	for i, e := range l {
+		if x, ok := scalar(e); ok {
+			a[i] = formatScalar(x)
+		}
+	}
+	b, err := json.Marshal(a)
+	return string(b), true, err
+}
+
+// csvEncoder writes rows as CSV records without header, NULL values being
+// empty fields.
+type csvEncoder struct {
+	w      *csv.Writer
+	cols   []ddl.ColumnDef
+	record []string
+}
+
+func newCSVEncoder(w io.Writer, cols []ddl.ColumnDef) *csvEncoder {
+	return &csvEncoder{w: csv.NewWriter(w), cols: cols, record: make([]string, len(cols))}
+}
+
+func (e *csvEncoder) write(vals []interface{}) error {
+	for i, v := range vals {
+		s, _, err := formatValue(e.cols[i], v)
+		if err != nil {
+			return err
+		}
+		e.record[i] = s
+	}
+	return e.w.Write(e.record)
+}
+
+func (e *csvEncoder) close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
@@ -0,0 +1,276 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package export implements writing the converted rows of a data migration
+// to CSV or Avro files, partitioned per table, or to BigQuery staging tables,
+// instead of to Spanner. The rows can be inspected and validated before they
+// are loaded, e.g. with the Dataflow import templates.
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	storageclient "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients/storage"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// Formats of the exported files.
+const (
+	FormatCSV  = "csv"
+	FormatAvro = "avro"
+)
+
+const (
+	gcsPrefix      = "gs://"
+	bigQueryPrefix = "bq://"
+	// rowsPerFile is the number of rows after which a new file is started
+	// for a table.
+	rowsPerFile = 1000000
+	// manifestFile is the name of the file listing the exported tables,
+	// their columns and files, written when the export is closed.
+	manifestFile = "manifest.json"
+)
+
+// IsValidFormat returns true if format is a known export format.
+func IsValidFormat(format string) bool {
+	return format == FormatCSV || format == FormatAvro
+}
+
+// createFunc creates the file name under the export directory.
+type createFunc func(name string) (io.WriteCloser, error)
+
+// Writer writes converted rows under an export directory, in a directory per
+// Spanner table. Rows are written with all the columns of their table, in
+// the order of the Spanner schema; columns missing from a row are NULL.
+type Writer struct {
+	mu     sync.Mutex
+	conv   *internal.Conv
+	dir    string
+	format string
+	create createFunc
+	tables map[string]*tableFiles
+}
+
+// tableFiles are the files of a table written so far.
+type tableFiles struct {
+	cols  []ddl.ColumnDef
+	index map[string]int
+	files []string
+	w     io.WriteCloser
+	enc   rowEncoder
+	rows  int64
+}
+
+// rowEncoder encodes the rows of a file.
+type rowEncoder interface {
+	write(vals []interface{}) error
+	// close flushes the rows buffered, without closing the file.
+	close() error
+}
+
+// NewWriter returns a writer exporting the rows of the tables of conv to uri,
+// which is one of:
+//   - gs://bucket/path: files in format written to Cloud Storage.
+//   - bq://project.dataset: rows streamed into a BigQuery table per Spanner
+//     table, created if it does not exist. format is ignored.
+//   - a local directory: files in format.
+func NewWriter(ctx context.Context, conv *internal.Conv, uri, format string) (internal.ExportWriter, error) {
+	if strings.HasPrefix(uri, bigQueryPrefix) {
+		return newBigQueryWriter(ctx, conv, uri)
+	}
+	if !IsValidFormat(format) {
+		return nil, fmt.Errorf("invalid export format %s, expected %s or %s", format, FormatCSV, FormatAvro)
+	}
+	w := &Writer{conv: conv, dir: uri, format: format, tables: make(map[string]*tableFiles)}
+	if strings.HasPrefix(uri, gcsPrefix) {
+		bucket, prefix, _ := strings.Cut(strings.TrimPrefix(uri, gcsPrefix), "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("invalid GCS path %s, expected gs://bucket/path", uri)
+		}
+		client, err := storageclient.NewStorageClientImpl(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("can't create storage client: %v", err)
+		}
+		w.create = func(name string) (io.WriteCloser, error) {
+			return client.Bucket(bucket).Object(path.Join(prefix, name)).NewWriter(ctx), nil
+		}
+		return w, nil
+	}
+	if err := os.MkdirAll(uri, 0755); err != nil {
+		return nil, fmt.Errorf("can't create export directory %s: %v", uri, err)
+	}
+	w.create = func(name string) (io.WriteCloser, error) {
+		p := filepath.Join(uri, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			return nil, err
+		}
+		return os.Create(p)
+	}
+	return w, nil
+}
+
+// Write writes a converted row of spTable, whose columns spCols have the
+// values spVals.
+func (w *Writer) Write(spTable string, spCols []string, spVals []interface{}) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	t, err := w.table(spTable)
+	if err != nil {
+		return err
+	}
+	if t.w == nil || t.rows == rowsPerFile {
+		if err := w.nextFile(spTable, t); err != nil {
+			return err
+		}
+	}
+	vals := make([]interface{}, len(t.cols))
+	for i, col := range spCols {
+		j, ok := t.index[col]
+		if !ok {
+			return fmt.Errorf("can't export column %s: not a column of table %s", col, spTable)
+		}
+		vals[j] = spVals[i]
+	}
+	if err := t.enc.write(vals); err != nil {
+		return fmt.Errorf("can't export row of table %s: %v", spTable, err)
+	}
+	t.rows++
+	return nil
+}
+
+func (w *Writer) table(spTable string) (*tableFiles, error) {
+	if t, ok := w.tables[spTable]; ok {
+		return t, nil
+	}
+	cols, index, err := tableColumns(w.conv, spTable)
+	if err != nil {
+		return nil, err
+	}
+	t := &tableFiles{cols: cols, index: index}
+	w.tables[spTable] = t
+	return t, nil
+}
+
+// tableColumns returns the columns of spTable, in the order of the Spanner
+// schema, and their positions keyed by name.
+func tableColumns(conv *internal.Conv, spTable string) ([]ddl.ColumnDef, map[string]int, error) {
+	tableId, err := internal.GetTableIdFromSpName(conv.SpSchema, spTable)
+	if err != nil {
+		return nil, nil, err
+	}
+	table := conv.SpSchema[tableId]
+	var cols []ddl.ColumnDef
+	index := make(map[string]int)
+	for _, colId := range table.ColIds {
+		col := table.ColDefs[colId]
+		index[col.Name] = len(cols)
+		cols = append(cols, col)
+	}
+	return cols, index, nil
+}
+
+// nextFile closes the current file of t, if any, and starts a new one.
+func (w *Writer) nextFile(spTable string, t *tableFiles) error {
+	if err := t.closeFile(); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s/%s-%05d.%s", spTable, spTable, len(t.files), w.format)
+	f, err := w.create(name)
+	if err != nil {
+		return fmt.Errorf("can't create export file %s: %v", name, err)
+	}
+	t.w, t.rows = f, 0
+	t.files = append(t.files, name)
+	if w.format == FormatAvro {
+		t.enc, err = newAvroEncoder(f, spTable, t.cols)
+	} else {
+		t.enc = newCSVEncoder(f, t.cols)
+	}
+	return err
+}
+
+func (t *tableFiles) closeFile() error {
+	if t.w == nil {
+		return nil
+	}
+	err := t.enc.close()
+	if cerr := t.w.Close(); err == nil {
+		err = cerr
+	}
+	t.w, t.enc = nil, nil
+	if err != nil {
+		return fmt.Errorf("can't write export file %s: %v", t.files[len(t.files)-1], err)
+	}
+	return nil
+}
+
+// manifest lists the exported tables, in the format of the manifest of the
+// Dataflow template importing text files to Spanner.
+type manifest struct {
+	Format string          `json:"format"`
+	Tables []manifestTable `json:"tables"`
+}
+
+type manifestTable struct {
+	TableName    string           `json:"table_name"`
+	FilePatterns []string         `json:"file_patterns"`
+	Columns      []manifestColumn `json:"columns"`
+}
+
+type manifestColumn struct {
+	ColumnName string `json:"column_name"`
+	TypeName   string `json:"type_name"`
+}
+
+// Close closes the files of all tables and writes the manifest listing them.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	m := manifest{Format: w.format, Tables: []manifestTable{}}
+	for spTable, t := range w.tables {
+		if err := t.closeFile(); err != nil {
+			return err
+		}
+		mt := manifestTable{TableName: spTable}
+		for _, f := range t.files {
+			mt.FilePatterns = append(mt.FilePatterns, strings.TrimSuffix(w.dir, "/")+"/"+f)
+		}
+		for _, col := range t.cols {
+			mt.Columns = append(mt.Columns, manifestColumn{ColumnName: col.Name, TypeName: col.T.PrintColumnDefType(false)})
+		}
+		m.Tables = append(m.Tables, mt)
+	}
+	sort.Slice(m.Tables, func(i, j int) bool { return m.Tables[i].TableName < m.Tables[j].TableName })
+	f, err := w.create(manifestFile)
+	if err != nil {
+		return fmt.Errorf("can't create export manifest: %v", err)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		f.Close()
+		return fmt.Errorf("can't write export manifest: %v", err)
+	}
+	return f.Close()
+}
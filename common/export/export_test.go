@@ -0,0 +1,153 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cloud.google.com/go/civil"
+	"cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+	bigquery "google.golang.org/api/bigquery/v2"
+)
+
+func exportConv() *internal.Conv {
+	conv := internal.MakeConv()
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			Name:   "orders",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3", "c4", "c5"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Name: "note", Id: "c2", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				"c3": {Name: "amount", Id: "c3", T: ddl.Type{Name: ddl.Numeric}},
+				"c4": {Name: "day", Id: "c4", T: ddl.Type{Name: ddl.Date}},
+				"c5": {Name: "tags", Id: "c5", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}},
+			},
+		},
+	}
+	return conv
+}
+
+func TestWriter_CSV(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewWriter(context.Background(), exportConv(), dir, FormatCSV)
+	assert.Nil(t, err)
+	assert.Nil(t, w.Write("orders", []string{"note", "id", "amount", "day", "tags"}, []interface{}{"a, b", int64(1), big.NewRat(5, 2), civil.Date{Year: 2024, Month: 3, Day: 1}, []spanner.NullString{{StringVal: "x", Valid: true}, {}}}))
+	assert.Nil(t, w.Write("orders", []string{"id"}, []interface{}{int64(2)}))
+	assert.NotNil(t, w.Write("orders", []string{"id", "missing"}, []interface{}{int64(3), "x"}))
+	assert.NotNil(t, w.Write("customers", []string{"id"}, []interface{}{int64(1)}))
+	assert.Nil(t, w.Close())
+
+	content, err := os.ReadFile(filepath.Join(dir, "orders", "orders-00000.csv"))
+	assert.Nil(t, err)
+	assert.Equal(t, "1,\"a, b\",2.500000000,2024-03-01,\"[\"\"x\"\",null]\"\n2,,,,\n", string(content))
+
+	var m manifest
+	content, err = os.ReadFile(filepath.Join(dir, manifestFile))
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(content, &m))
+	assert.Equal(t, manifest{Format: FormatCSV, Tables: []manifestTable{{
+		TableName:    "orders",
+		FilePatterns: []string{dir + "/orders/orders-00000.csv"},
+		Columns: []manifestColumn{
+			{ColumnName: "id", TypeName: "INT64"},
+			{ColumnName: "note", TypeName: "STRING(MAX)"},
+			{ColumnName: "amount", TypeName: "NUMERIC"},
+			{ColumnName: "day", TypeName: "DATE"},
+			{ColumnName: "tags", TypeName: "ARRAY<STRING(MAX)>"},
+		},
+	}}}, m)
+}
+
+func TestWriter_InvalidFormat(t *testing.T) {
+	_, err := NewWriter(context.Background(), exportConv(), t.TempDir(), "parquet")
+	assert.NotNil(t, err)
+	_, err = NewWriter(context.Background(), exportConv(), "gs://", FormatAvro)
+	assert.NotNil(t, err)
+}
+
+func TestAvroEncoder(t *testing.T) {
+	cols := []ddl.ColumnDef{
+		{Name: "id", T: ddl.Type{Name: ddl.Int64}},
+		{Name: "ok", T: ddl.Type{Name: ddl.Bool}},
+		{Name: "tags", T: ddl.Type{Name: ddl.Int64, IsArray: true}},
+	}
+	var buf bytes.Buffer
+	e, err := newAvroEncoder(&buf, "orders", cols)
+	assert.Nil(t, err)
+	assert.Nil(t, e.write([]interface{}{int64(-2), true, []spanner.NullInt64{{Int64: 1, Valid: true}, {}}}))
+	assert.Nil(t, e.write([]interface{}{int64(3), nil, nil}))
+	assert.NotNil(t, e.write([]interface{}{"x", nil, nil}))
+	assert.Nil(t, e.close())
+
+	schema, err := avroSchema("orders", cols)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"fields":[{"name":"id","type":["null","long"],"default":null},{"name":"ok","type":["null","boolean"],"default":null},{"name":"tags","type":["null",{"items":["null","long"],"type":"array"}],"default":null}],"name":"orders","type":"record"}`, string(schema))
+
+	data := buf.Bytes()
+	assert.Equal(t, []byte("Obj\x01"), data[:4])
+	assert.True(t, bytes.Contains(data, schema))
+	// The block of the 2 rows follows the header: the count and size of the
+	// block, the rows, and the sync marker ending the header and the block.
+	block := []byte{
+		4, 28,
+		2, 3, 2, 1, 2, 4, 2, 2, 0, 0, // -2, true, [1, NULL]
+		2, 6, 0, 0, // 3, NULL, NULL
+	}
+	assert.Equal(t, append(block, e.sync[:]...), data[len(data)-len(block)-16:])
+	assert.Equal(t, e.sync[:], data[len(data)-len(block)-32:len(data)-len(block)-16])
+}
+
+func TestParseBigQueryDataset(t *testing.T) {
+	project, dataset, err := parseBigQueryDataset("bq://my-project.staging")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"my-project", "staging"}, []string{project, dataset})
+	_, _, err = parseBigQueryDataset("bq://my-project.staging.orders")
+	assert.NotNil(t, err)
+	_, err = NewWriter(context.Background(), exportConv(), "bq://my-project", FormatCSV)
+	assert.NotNil(t, err)
+}
+
+func TestBigQuerySchema(t *testing.T) {
+	cols, _, err := tableColumns(exportConv(), "orders")
+	assert.Nil(t, err)
+	var fields []string
+	for _, f := range bigQuerySchema(cols, constants.DIALECT_GOOGLESQL).Fields {
+		fields = append(fields, f.Name+" "+f.Type+" "+f.Mode)
+	}
+	assert.Equal(t, []string{"id INTEGER NULLABLE", "note STRING NULLABLE", "amount NUMERIC NULLABLE", "day DATE NULLABLE", "tags STRING REPEATED"}, fields)
+	assert.Equal(t, "BIGNUMERIC", bigQuerySchema(cols, constants.DIALECT_POSTGRESQL).Fields[2].Type)
+}
+
+func TestBigQueryRow(t *testing.T) {
+	cols, index, err := tableColumns(exportConv(), "orders")
+	assert.Nil(t, err)
+	row, err := bigQueryRow(cols, []string{"id", "amount", "tags"}, []interface{}{int64(1), big.NewRat(1, 4), []spanner.NullString{{StringVal: "x", Valid: true}, {}}}, index)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bigquery.JsonValue{"id": "1", "amount": "0.250000000", "tags": []string{"x"}}, row)
+	_, err = bigQueryRow(cols, []string{"missing"}, []interface{}{"x"}, index)
+	assert.NotNil(t, err)
+}
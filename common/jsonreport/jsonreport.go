@@ -0,0 +1,112 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonreport defines the machine-readable migration report emitted by
+// the CLI (as <prefix>.report.json) and by the web UI.
+//
+// The format is versioned by SchemaVersion. Fields are only ever added within
+// a major version; renaming or removing a field, or changing its meaning,
+// bumps the major version. Consumers should check SchemaVersion and ignore
+// unknown fields.
+package jsonreport
+
+import "time"
+
+// SchemaVersion is the version of the report format defined in this package.
+const SchemaVersion = "1.0"
+
+// Table conversion statuses.
+const (
+	StatusConverted             = "CONVERTED"
+	StatusConvertedWithWarnings = "CONVERTED_WITH_WARNINGS"
+	StatusConvertedWithErrors   = "CONVERTED_WITH_ERRORS"
+	StatusDropped               = "DROPPED"
+)
+
+// Issue severities.
+const (
+	SeverityError      = "ERROR"
+	SeverityWarning    = "WARNING"
+	SeveritySuggestion = "SUGGESTION"
+	SeverityNote       = "NOTE"
+)
+
+// Dropped object types.
+const (
+	ObjectTable      = "TABLE"
+	ObjectColumn     = "COLUMN"
+	ObjectIndex      = "INDEX"
+	ObjectForeignKey = "FOREIGN_KEY"
+	ObjectStatement  = "STATEMENT"
+)
+
+// Report is the top level of the JSON migration report.
+type Report struct {
+	SchemaVersion  string          `json:"schemaVersion"`
+	GeneratedAt    time.Time       `json:"generatedAt"`
+	Driver         string          `json:"driver"`
+	DatabaseName   string          `json:"databaseName"`
+	Dialect        string          `json:"dialect"`
+	MigrationType  string          `json:"migrationType"`
+	Summary        Summary         `json:"summary"`
+	Tables         []Table         `json:"tables"`
+	DroppedObjects []DroppedObject `json:"droppedObjects"`
+	SampleBadRows  []string        `json:"sampleBadRows"`
+}
+
+// Summary holds the overall conversion rating and totals.
+type Summary struct {
+	Rating        string `json:"rating"`
+	Text          string `json:"text"`
+	TotalTables   int    `json:"totalTables"`
+	DroppedTables int    `json:"droppedTables"`
+	TotalRows     int64  `json:"totalRows"`
+	BadRows       int64  `json:"badRows"`
+	BadWrites     int64  `json:"badWrites"`
+}
+
+// Table is the conversion result of a single source table.
+type Table struct {
+	SourceName          string    `json:"sourceName"`
+	SpannerName         string    `json:"spannerName,omitempty"`
+	Status              string    `json:"status"`
+	SchemaRating        string    `json:"schemaRating,omitempty"`
+	DataRating          string    `json:"dataRating,omitempty"`
+	Columns             int64     `json:"columns"`
+	SyntheticPrimaryKey string    `json:"syntheticPrimaryKey,omitempty"`
+	Rows                RowCounts `json:"rows"`
+	Issues              []Issue   `json:"issues"`
+}
+
+// RowCounts holds the data conversion counts of a table.
+type RowCounts struct {
+	Total     int64 `json:"total"`
+	Bad       int64 `json:"bad"`
+	BadWrites int64 `json:"badWrites"`
+}
+
+// Issue is a single schema conversion issue of a table.
+type Issue struct {
+	Severity    string `json:"severity"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+}
+
+// DroppedObject is a source object which has no counterpart in the Spanner
+// schema.
+type DroppedObject struct {
+	Type        string `json:"type"`
+	SourceTable string `json:"sourceTable,omitempty"`
+	Name        string `json:"name"`
+}
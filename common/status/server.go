@@ -0,0 +1,74 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package status implements the HTTP server reporting the progress of a
+// migration run from the command line, started with --status-port.
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+)
+
+// Handler returns the handler serving the progress of a migration as JSON on
+// /status. Any origin may read it, so that the web UI can poll a migration
+// run from the command line.
+func Handler(s *internal.MigrationStatus) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(s.Report())
+	})
+	return mux
+}
+
+// DefaultAddress is the address the status server listens on unless
+// --status-address is set, so that only the local host can read the progress.
+const DefaultAddress = "127.0.0.1"
+
+// Serve starts serving the progress of a migration on address and port, in
+// the background. The returned function stops the server.
+func Serve(address string, port int, s *internal.MigrationStatus) (func(), error) {
+	hostPort := net.JoinHostPort(address, strconv.Itoa(port))
+	l, err := net.Listen("tcp", hostPort)
+	if err != nil {
+		return nil, fmt.Errorf("can't start status server on %s: %v", hostPort, err)
+	}
+	srv := &http.Server{Handler: Handler(s), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(l); err != nil && err != http.ErrServerClosed {
+			logger.Log.Error(fmt.Sprintf("Status server stopped: %v", err))
+		}
+	}()
+	logger.Log.Info(fmt.Sprintf("Serving the migration status on http://%s/status", l.Addr()))
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SrcSchema = map[string]schema.Table{"t1": {Name: "Orders", Id: "t1"}}
+	conv.SpSchema = ddl.Schema{"t1": {Name: "orders", Id: "t1"}}
+	conv.Stats.Rows["Orders"] = 10
+	s := internal.NewMigrationStatus()
+	s.StartData(conv)
+	s.AddWrittenRows("orders", 4)
+	s.AddDroppedRow("orders")
+
+	rr := httptest.NewRecorder()
+	Handler(s).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "*", rr.Header().Get("Access-Control-Allow-Origin"))
+	var report internal.StatusReport
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &report))
+	assert.Equal(t, internal.StatusPhaseData, report.Phase)
+	assert.Equal(t, int64(10), report.TotalRows)
+	assert.Equal(t, int64(4), report.RowsWritten)
+	assert.Equal(t, int64(1), report.Errors)
+	assert.NotNil(t, report.EtaSeconds)
+	assert.Equal(t, []internal.TableStatus{{Table: "Orders", TotalRows: 10, RowsWritten: 4, Errors: 1}}, report.Tables)
+
+	rr = httptest.NewRecorder()
+	Handler(s).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/status", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
+
+func TestServe(t *testing.T) {
+	l, err := net.Listen("tcp", net.JoinHostPort(DefaultAddress, "0"))
+	assert.Nil(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	stop, err := Serve(DefaultAddress, port, internal.NewMigrationStatus())
+	assert.Nil(t, err)
+	defer stop()
+	resp, err := http.Get(fmt.Sprintf("http://%s/status", net.JoinHostPort(DefaultAddress, strconv.Itoa(port))))
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The port is taken on the loopback address only.
+	_, err = Serve(DefaultAddress, port, internal.NewMigrationStatus())
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,326 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"google.golang.org/api/iterator"
+)
+
+var (
+	createTableRegexp = regexp.MustCompile(`(?i)\bCREATE\s+(?:UNLOGGED\s+)?TABLE\s+(?:IF\s+NOT\s+EXISTS\s+)?([^\s(]+)`)
+	referencesRegexp  = regexp.MustCompile(`(?i)\bREFERENCES\s+([^\s(]+)`)
+	dataRegexp        = regexp.MustCompile(`(?i)^\s*(?:INSERT\s+INTO|COPY)\s+([^\s(]+)`)
+)
+
+// Groups of dump files, in the order in which they are processed.
+const (
+	createDatabaseFiles = iota // mydumper *-schema-create.sql files.
+	schemaFiles                // Files creating tables, possibly with data.
+	dataFiles                  // Files with the data of tables created by other files.
+	postFiles                  // mydumper views, triggers and routines.
+)
+
+// dumpFile is a dump file and the tables it creates, refers to and loads.
+type dumpFile struct {
+	path       string
+	name       string // Name shown in logs, e.g. the GCS object name.
+	group      int
+	creates    []string
+	references []string
+	loads      string // First table the file loads data into, if any.
+}
+
+// IsDumpDirectory returns true if dumpFile is a local directory or a GCS
+// prefix ending with "/", i.e. a set of dump files.
+func IsDumpDirectory(dumpFile string) bool {
+	if strings.HasPrefix(dumpFile, constants.GCS_SCHEME+"://") {
+		return strings.HasSuffix(dumpFile, "/")
+	}
+	fi, err := os.Stat(dumpFile)
+	return err == nil && fi.IsDir()
+}
+
+// OpenDumpDirectory reads the .sql files of the local directory or GCS prefix
+// dir, e.g. the per-table files written by mydumper, and returns a file with
+// their concatenation so that they are migrated as one dump, and the local
+// directory of the files. Files are concatenated in dependency order:
+// database creation, tables (parent tables before the tables referring to
+// them), table data and finally views and triggers. Each file is reported as
+// it is loaded.
+func OpenDumpDirectory(dir string, out *os.File) (*os.File, string, error) {
+	localDir := dir
+	prefix := ""
+	if strings.HasPrefix(dir, constants.GCS_SCHEME+"://") {
+		var err error
+		if localDir, err = downloadGCSDumpFiles(dir); err != nil {
+			return nil, "", err
+		}
+		prefix = dir
+	}
+	paths, err := filepath.Glob(filepath.Join(localDir, "*.sql"))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(paths) == 0 {
+		return nil, "", fmt.Errorf("no .sql files found in %s", dir)
+	}
+	var files []dumpFile
+	for _, p := range paths {
+		f, err := scanDumpFile(p)
+		if err != nil {
+			return nil, "", err
+		}
+		f.name = p
+		if prefix != "" {
+			f.name = prefix + filepath.Base(p)
+		}
+		files = append(files, f)
+	}
+	files = orderDumpFiles(files)
+
+	tmpDir := filepath.Join(os.TempDir(), constants.SMT_TMP_DIR)
+	if err := os.MkdirAll(tmpDir, os.ModePerm); err != nil {
+		return nil, "", err
+	}
+	dump, err := os.Create(filepath.Join(tmpDir, "spanner-migration-tool.dump.sql"))
+	if err != nil {
+		return nil, "", fmt.Errorf("can't create the file concatenating the dump files: %v", err)
+	}
+	for i, f := range files {
+		in, err := os.Open(f.path)
+		if err != nil {
+			dump.Close()
+			return nil, "", err
+		}
+		n, err := io.Copy(dump, in)
+		in.Close()
+		if err == nil {
+			// Files may not end with a new line.
+			_, err = dump.WriteString("\n")
+		}
+		if err != nil {
+			dump.Close()
+			return nil, "", fmt.Errorf("can't read dump file %s: %v", f.name, err)
+		}
+		msg := fmt.Sprintf("Loaded dump file %d/%d: %s (%d bytes)", i+1, len(files), f.name, n)
+		logger.Log.Info(msg)
+		fmt.Fprintln(out, msg)
+	}
+	if _, err := dump.Seek(0, 0); err != nil {
+		dump.Close()
+		return nil, "", err
+	}
+	return dump, localDir, nil
+}
+
+// downloadGCSDumpFiles downloads the .sql objects of the GCS prefix, and the
+// metadata file of mydumper exports, to a temporary directory and returns the
+// directory.
+func downloadGCSDumpFiles(prefix string) (string, error) {
+	u, err := url.Parse(prefix)
+	if err != nil {
+		return "", fmt.Errorf("can't parse GCS path %s: %v", prefix, err)
+	}
+	// DownloadFromGCS creates files relative to the temporary directory.
+	const subDir = "gcs-dump"
+	localDir := filepath.Join(os.TempDir(), constants.SMT_TMP_DIR, subDir)
+	if err := os.RemoveAll(localDir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(localDir, os.ModePerm); err != nil {
+		return "", err
+	}
+	ctx := context.Background()
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return "", fmt.Errorf("can't create GCS client: %v", err)
+	}
+	defer client.Close()
+	it := client.Bucket(u.Host).Objects(ctx, &storage.Query{Prefix: strings.TrimPrefix(u.Path, "/"), Delimiter: "/"})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("can't list the files of %s: %v", prefix, err)
+		}
+		base := path.Base(attrs.Name)
+		if attrs.Name == "" || (!strings.HasSuffix(base, ".sql") && base != "metadata") {
+			continue
+		}
+		f, err := DownloadFromGCS(u.Host, attrs.Name, subDir+"/"+base)
+		if err != nil {
+			return "", err
+		}
+		f.Close()
+	}
+	return localDir, nil
+}
+
+// scanDumpFile finds the tables created and referred to by the file at path,
+// up to its first data statement, and the table this statement loads.
+// Scanning stops there so that large data files aren't read twice.
+func scanDumpFile(path string) (dumpFile, error) {
+	f := dumpFile{path: path, group: schemaFiles}
+	base := filepath.Base(path)
+	switch {
+	case strings.HasSuffix(base, "-schema-create.sql"):
+		f.group = createDatabaseFiles
+	case strings.HasSuffix(base, "-schema-view.sql"), strings.HasSuffix(base, "-schema-triggers.sql"), strings.HasSuffix(base, "-schema-post.sql"):
+		f.group = postFiles
+	}
+	in, err := os.Open(path)
+	if err != nil {
+		return f, err
+	}
+	defer in.Close()
+	r := bufio.NewReader(in)
+	for {
+		// Only the beginning of long lines, e.g. extended inserts, is needed.
+		line, isPrefix, err := r.ReadLine()
+		for isPrefix && err == nil {
+			_, isPrefix, err = r.ReadLine()
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return f, fmt.Errorf("can't read dump file %s: %v", path, err)
+		}
+		s := string(line)
+		if m := dataRegexp.FindStringSubmatch(s); m != nil {
+			f.loads = normalizeDumpTableName(m[1])
+			break
+		}
+		for _, m := range createTableRegexp.FindAllStringSubmatch(s, -1) {
+			f.creates = append(f.creates, normalizeDumpTableName(m[1]))
+		}
+		for _, m := range referencesRegexp.FindAllStringSubmatch(s, -1) {
+			f.references = append(f.references, normalizeDumpTableName(m[1]))
+		}
+	}
+	if f.group == schemaFiles && len(f.creates) == 0 && f.loads != "" {
+		f.group = dataFiles
+	}
+	return f, nil
+}
+
+// normalizeDumpTableName returns the unqualified, unquoted and lower case
+// name of a table of a dump, e.g. orders for `shop`.`Orders`.
+func normalizeDumpTableName(name string) string {
+	name = strings.TrimRight(name, ";,")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.ToLower(strings.Trim(name, "`\"[]"))
+}
+
+// orderDumpFiles sorts files by group, then by the rank of their tables in
+// the dependency order of tables, then by name.
+func orderDumpFiles(files []dumpFile) []dumpFile {
+	refs := make(map[string][]string)
+	for _, f := range files {
+		for _, t := range f.creates {
+			refs[t] = append(refs[t], f.references...)
+		}
+	}
+	rank := rankTables(refs)
+	fileRank := func(f dumpFile) int {
+		r := len(rank)
+		for _, t := range f.creates {
+			if rank[t] < r {
+				r = rank[t]
+			}
+		}
+		if len(f.creates) == 0 {
+			if tr, ok := rank[f.loads]; ok {
+				r = tr
+			}
+		}
+		return r
+	}
+	sorted := append([]dumpFile{}, files...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.group != b.group {
+			return a.group < b.group
+		}
+		if ra, rb := fileRank(a), fileRank(b); ra != rb {
+			return ra < rb
+		}
+		return a.name < b.name
+	})
+	return sorted
+}
+
+// rankTables returns the position of each table of refs in an order where
+// tables come after the tables they refer to. Ties are broken by name, and
+// tables in a reference cycle are ranked by name.
+func rankTables(refs map[string][]string) map[string]int {
+	var tables []string
+	for t := range refs {
+		tables = append(tables, t)
+	}
+	sort.Strings(tables)
+	rank := make(map[string]int)
+	for len(rank) < len(tables) {
+		progress := false
+		for _, t := range tables {
+			if _, ok := rank[t]; ok {
+				continue
+			}
+			ready := true
+			for _, r := range refs[t] {
+				if _, known := refs[r]; !known || r == t {
+					continue
+				}
+				if _, ok := rank[r]; !ok {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				rank[t] = len(rank)
+				progress = true
+			}
+		}
+		if !progress {
+			// Break a cycle with the first remaining table.
+			for _, t := range tables {
+				if _, ok := rank[t]; !ok {
+					rank[t] = len(rank)
+					break
+				}
+			}
+		}
+	}
+	return rank
+}
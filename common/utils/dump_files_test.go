@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenDumpDirectory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		// mydumper layout.
+		"shop-schema-create.sql":            "CREATE DATABASE `shop`;\n",
+		"shop.orders-schema.sql":            "CREATE TABLE `orders` (\n  `id` int,\n  `user_id` int,\n  CONSTRAINT `fk` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)\n);\n",
+		"shop.users-schema.sql":             "CREATE TABLE `users` (\n  `id` int\n);\n",
+		"shop.orders.00000.sql":             "INSERT INTO `orders` VALUES (1,1);",
+		"shop.users.00000.sql":              "INSERT INTO `users` VALUES (1);\n",
+		"shop.users.00001.sql":              "INSERT INTO `users` VALUES (2);\n",
+		"shop.active_users-schema-view.sql": "CREATE VIEW `active_users` AS SELECT * FROM `users`;\n",
+		"README.txt":                        "not a dump file",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.True(t, IsDumpDirectory(dir))
+	assert.False(t, IsDumpDirectory(filepath.Join(dir, "shop.users.00000.sql")))
+	assert.True(t, IsDumpDirectory("gs://bucket/dumps/"))
+	assert.False(t, IsDumpDirectory("gs://bucket/dumps/shop.sql"))
+
+	out, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	f, localDir, err := OpenDumpDirectory(dir, out)
+	assert.NoError(t, err)
+	assert.Equal(t, dir, localDir)
+	defer f.Close()
+	dump, err := io.ReadAll(f)
+	assert.NoError(t, err)
+	// Files are concatenated in dependency order, each followed by a new line.
+	expected := ""
+	for _, name := range []string{"shop-schema-create.sql", "shop.users-schema.sql", "shop.orders-schema.sql", "shop.users.00000.sql", "shop.users.00001.sql", "shop.orders.00000.sql", "shop.active_users-schema-view.sql"} {
+		expected += files[name] + "\n"
+	}
+	assert.Equal(t, expected, string(dump))
+
+	_, _, err = OpenDumpDirectory(t.TempDir(), out)
+	assert.Error(t, err)
+}
+
+func TestRankTables(t *testing.T) {
+	rank := rankTables(map[string][]string{
+		"orders":    {"users", "products"},
+		"users":     {"users"},    // Self reference.
+		"products":  {"catalogs"}, // Unknown table.
+		"a":         {"b"},        // Cycle.
+		"b":         {"a"},
+		"shipments": {"orders"},
+	})
+	assert.Equal(t, map[string]int{"products": 0, "users": 1, "orders": 2, "shipments": 3, "a": 4, "b": 5}, rank)
+}
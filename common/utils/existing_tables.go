@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// TableDiff is the difference between a table to migrate and the table of the
+// same name which already exists in the Spanner database.
+type TableDiff struct {
+	// TableId is the id of the table to migrate.
+	TableId string
+	Name    string
+	// MissingColIds are the ids of the columns to migrate which don't exist
+	// in the Spanner table.
+	MissingColIds []string
+	// Conflicts describe why the rows of the table to migrate can't be
+	// written to the Spanner table, e.g. columns of different types.
+	Conflicts []string
+}
+
+// DiffExistingTables compares the tables of conv with the tables of the same
+// name in spannerConv, the schema read from the Spanner database, and returns
+// their differences sorted by table name. Tables only present in one schema
+// are ignored. Names are compared case-insensitively, like Spanner does.
+func DiffExistingTables(conv, spannerConv *internal.Conv) []TableDiff {
+	var diffs []TableDiff
+	for tableId, table := range conv.SpSchema {
+		spTable, ok := findTable(spannerConv.SpSchema, table.Name)
+		if !ok {
+			continue
+		}
+		diff := TableDiff{TableId: tableId, Name: table.Name}
+		if pk, spPk := primaryKeyNames(table), primaryKeyNames(spTable); !strings.EqualFold(strings.Join(pk, ","), strings.Join(spPk, ",")) {
+			diff.Conflicts = append(diff.Conflicts, fmt.Sprintf("primary key (%s) differs from (%s)", strings.Join(pk, ", "), strings.Join(spPk, ", ")))
+		}
+		matched := make(map[string]bool)
+		for _, colId := range table.ColIds {
+			col := table.ColDefs[colId]
+			spColId, spCol, ok := findColumn(spTable, col.Name)
+			if !ok {
+				diff.MissingColIds = append(diff.MissingColIds, colId)
+				continue
+			}
+			matched[spColId] = true
+			switch {
+			case col.T.Name != spCol.T.Name || col.T.IsArray != spCol.T.IsArray:
+				diff.Conflicts = append(diff.Conflicts, fmt.Sprintf("column %s is %s in Spanner instead of %s", col.Name, printType(spCol.T), printType(col.T)))
+			case col.T.Len > spCol.T.Len:
+				diff.Conflicts = append(diff.Conflicts, fmt.Sprintf("column %s is shorter in Spanner: %s instead of %s", col.Name, printType(spCol.T), printType(col.T)))
+			case spCol.NotNull && !col.NotNull && !FindInPrimaryKey(colId, table.PrimaryKeys):
+				// Spanner makes the primary key columns of PostgreSQL
+				// dialect databases NOT NULL, so they aren't compared.
+				diff.Conflicts = append(diff.Conflicts, fmt.Sprintf("column %s is NOT NULL in Spanner", col.Name))
+			}
+		}
+		for _, spColId := range spTable.ColIds {
+			spCol := spTable.ColDefs[spColId]
+			if !matched[spColId] && spCol.NotNull && !spCol.DefaultValue.IsPresent && !spCol.GeneratedColumn.IsPresent && spCol.AutoGen.Name == "" {
+				diff.Conflicts = append(diff.Conflicts, fmt.Sprintf("column %s is NOT NULL in Spanner, without default value, and isn't migrated", spCol.Name))
+			}
+		}
+		diffs = append(diffs, diff)
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs
+}
+
+// ExistingTablesStatements returns the ids of the tables of conv which must
+// not be created, since they already exist in the Spanner database, and the
+// statements adding the missing columns to them, for the given mode of
+// migrating existing tables. Missing NOT NULL columns without default value
+// are added nullable. It returns an error if the rows of a table can't
+// be written to the existing table.
+func ExistingTablesStatements(conv *internal.Conv, diffs []TableDiff, mode, driver string) ([]string, []string, error) {
+	var existingTableIds, statements, conflicts []string
+	for _, diff := range diffs {
+		existingTableIds = append(existingTableIds, diff.TableId)
+		for _, c := range diff.Conflicts {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %s", diff.Name, c))
+		}
+		if len(diff.MissingColIds) == 0 {
+			continue
+		}
+		if mode != constants.EXISTING_TABLES_MERGE {
+			var names []string
+			for _, colId := range diff.MissingColIds {
+				names = append(names, conv.SpSchema[diff.TableId].ColDefs[colId].Name)
+			}
+			conflicts = append(conflicts, fmt.Sprintf("%s: columns %s don't exist in Spanner", diff.Name, strings.Join(names, ", ")))
+			continue
+		}
+		c := ddl.Config{ProtectIds: true, SpDialect: conv.SpDialect, Source: driver}
+		for _, colId := range diff.MissingColIds {
+			col := conv.SpSchema[diff.TableId].ColDefs[colId]
+			// Spanner can't add a NOT NULL column without default value to
+			// a table with rows, so such columns are added nullable.
+			if col.NotNull && !col.DefaultValue.IsPresent {
+				col.NotNull = false
+			}
+			statements = append(statements, col.PrintAddColumn(diff.Name, c))
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, nil, fmt.Errorf("some tables to be migrated already exist in Spanner and are incompatible: %s", strings.Join(conflicts, "; "))
+	}
+	return existingTableIds, statements, nil
+}
+
+func findTable(schema ddl.Schema, name string) (ddl.CreateTable, bool) {
+	for _, table := range schema {
+		if strings.EqualFold(table.Name, name) {
+			return table, true
+		}
+	}
+	return ddl.CreateTable{}, false
+}
+
+func findColumn(table ddl.CreateTable, name string) (string, ddl.ColumnDef, bool) {
+	for colId, col := range table.ColDefs {
+		if strings.EqualFold(col.Name, name) {
+			return colId, col, true
+		}
+	}
+	return "", ddl.ColumnDef{}, false
+}
+
+func primaryKeyNames(table ddl.CreateTable) []string {
+	pks := append([]ddl.IndexKey{}, table.PrimaryKeys...)
+	sortKeysByOrder(pks)
+	var names []string
+	for _, pk := range pks {
+		names = append(names, table.ColDefs[pk.ColId].Name)
+	}
+	return names
+}
+
+func printType(t ddl.Type) string {
+	return t.PrintColumnDefType(false)
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func existingTablesConvs() (*internal.Conv, *internal.Conv) {
+	conv := internal.MakeConv()
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			Name:   "Users",
+			ColIds: []string{"c1", "c2", "c3"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+				"c2": {Name: "name", Id: "c2", T: ddl.Type{Name: ddl.String, Len: 50}},
+				"c3": {Name: "email", Id: "c3", T: ddl.Type{Name: ddl.String, Len: 100}},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+		},
+		"t2": {
+			Name:        "orders",
+			ColIds:      []string{"c4"},
+			ColDefs:     map[string]ddl.ColumnDef{"c4": {Name: "id", Id: "c4", T: ddl.Type{Name: ddl.Int64}}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c4", Order: 1}},
+		},
+	}
+	spannerConv := internal.MakeConv()
+	spannerConv.SpSchema = ddl.Schema{
+		"s1": {
+			Name:   "users",
+			ColIds: []string{"s1c1", "s1c2"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"s1c1": {Name: "ID", Id: "s1c1", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+				"s1c2": {Name: "name", Id: "s1c2", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "s1c1", Order: 1}},
+		},
+	}
+	return conv, spannerConv
+}
+
+func TestDiffExistingTables(t *testing.T) {
+	conv, spannerConv := existingTablesConvs()
+	diffs := DiffExistingTables(conv, spannerConv)
+	assert.Equal(t, []TableDiff{{TableId: "t1", Name: "Users", MissingColIds: []string{"c3"}}}, diffs)
+
+	spTable := spannerConv.SpSchema["s1"]
+	spTable.ColIds = append(spTable.ColIds, "s1c3")
+	spTable.ColDefs["s1c2"] = ddl.ColumnDef{Name: "name", Id: "s1c2", T: ddl.Type{Name: ddl.String, Len: 10}, NotNull: true}
+	spTable.ColDefs["s1c3"] = ddl.ColumnDef{Name: "created", Id: "s1c3", T: ddl.Type{Name: ddl.Timestamp}, NotNull: true}
+	spannerConv.SpSchema["s1"] = spTable
+	diffs = DiffExistingTables(conv, spannerConv)
+	assert.Equal(t, []string{
+		"column name is shorter in Spanner: STRING(10) instead of STRING(50)",
+		"column created is NOT NULL in Spanner, without default value, and isn't migrated",
+	}, diffs[0].Conflicts)
+}
+
+func TestExistingTablesStatements(t *testing.T) {
+	conv, spannerConv := existingTablesConvs()
+	diffs := DiffExistingTables(conv, spannerConv)
+
+	existingTableIds, statements, err := ExistingTablesStatements(conv, diffs, constants.EXISTING_TABLES_MERGE, constants.MYSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"t1"}, existingTableIds)
+	assert.Equal(t, []string{"ALTER TABLE `Users` ADD COLUMN `email` STRING(100)"}, statements)
+
+	// NOT NULL columns are added nullable, unless they have a default value.
+	email := conv.SpSchema["t1"].ColDefs["c3"]
+	email.NotNull = true
+	conv.SpSchema["t1"].ColDefs["c3"] = email
+	_, statements, err = ExistingTablesStatements(conv, diffs, constants.EXISTING_TABLES_MERGE, constants.MYSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ALTER TABLE `Users` ADD COLUMN `email` STRING(100)"}, statements)
+	email.DefaultValue = ddl.DefaultValue{IsPresent: true, Value: ddl.Expression{Statement: "''"}}
+	conv.SpSchema["t1"].ColDefs["c3"] = email
+	_, statements, err = ExistingTablesStatements(conv, diffs, constants.EXISTING_TABLES_MERGE, constants.MYSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ALTER TABLE `Users` ADD COLUMN `email` STRING(100) NOT NULL  DEFAULT ('')"}, statements)
+
+	// Existing tables are only skipped when all their columns exist.
+	_, _, err = ExistingTablesStatements(conv, diffs, constants.EXISTING_TABLES_SKIP, constants.MYSQL)
+	assert.ErrorContains(t, err, "Users: columns email don't exist in Spanner")
+}
@@ -9,6 +9,10 @@ import (
 	secretmanageraccessor "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/secretmanager"
 )
 
+// SecretManagerUriPrefix prefixes the values read from Secret Manager at
+// runtime, e.g. "sm://projects/p/secrets/s/versions/latest".
+const SecretManagerUriPrefix = "sm://"
+
 // FetchPasswordFromSecretManager fetches the password from Secret Manager.
 // It returns the resolved secret ID (with version if added), the password, and any error.
 func FetchPasswordFromSecretManager(secretId string) (string, string, error) {
@@ -29,3 +33,18 @@ func FetchPasswordFromSecretManager(secretId string) (string, string, error) {
 	}
 	return secretId, pwd, nil
 }
+
+// ResolveSecretManagerUri returns the secret referenced by a Secret Manager URI,
+// or value itself if it isn't one. The latest version of the secret is read if
+// the URI doesn't specify a version.
+func ResolveSecretManagerUri(value string) (string, error) {
+	secretId, ok := strings.CutPrefix(value, SecretManagerUriPrefix)
+	if !ok {
+		return value, nil
+	}
+	if !strings.HasPrefix(secretId, "projects/") || !strings.Contains(secretId, "/secrets/") {
+		return "", fmt.Errorf("invalid Secret Manager URI %s, must be of the form sm://projects/<project>/secrets/<secret>[/versions/<version>]", value)
+	}
+	_, secret, err := FetchPasswordFromSecretManager(secretId)
+	return secret, err
+}
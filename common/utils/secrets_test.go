@@ -111,3 +111,30 @@ func TestFetchPasswordFromSecretManager(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveSecretManagerUri(t *testing.T) {
+	mockClient := new(MockSecretManagerClient)
+	oldNewClient := secretmanagerclient.NewSecretManagerClient
+	secretmanagerclient.NewSecretManagerClient = func(ctx context.Context) (secretmanagerclient.SecretManagerClient, error) {
+		return mockClient, nil
+	}
+	defer func() { secretmanagerclient.NewSecretManagerClient = oldNewClient }()
+	mockClient.On("AccessSecretVersion", mock.Anything, mock.MatchedBy(func(req *secretmanagerpb.AccessSecretVersionRequest) bool {
+		return req.Name == "projects/p/secrets/s/versions/2"
+	}), mock.Anything).Return(&secretmanagerpb.AccessSecretVersionResponse{
+		Payload: &secretmanagerpb.SecretPayload{Data: []byte("password123")},
+	}, nil).Once()
+
+	secret, err := ResolveSecretManagerUri("sm://projects/p/secrets/s/versions/2")
+	assert.NoError(t, err)
+	assert.Equal(t, "password123", secret)
+
+	// Values which aren't Secret Manager URIs are returned as is.
+	secret, err = ResolveSecretManagerUri("projects/p/secrets/s")
+	assert.NoError(t, err)
+	assert.Equal(t, "projects/p/secrets/s", secret)
+
+	_, err = ResolveSecretManagerUri("sm://p/s")
+	assert.Error(t, err)
+	mockClient.AssertExpectations(t)
+}
@@ -60,6 +60,7 @@ var (
 type IOStreams struct {
 	In, SeekableIn, Out *os.File
 	BytesRead           int64
+	DumpDir             string // Local directory of the dump files, when the dump file is a directory or a GCS prefix.
 }
 
 // Spanner migration tool accepts a manifest file in the form of a json which unmarshalls into the ManifestTables struct.
@@ -83,6 +84,8 @@ var newDatabaseAdminClient = database.NewDatabaseAdminClient
 
 // NewIOStreams returns a new IOStreams struct such that input stream is set
 // to open file descriptor for dumpFile if driver is PGDUMP or MYSQLDUMP.
+// If dumpFile is a directory or a GCS prefix, the input stream is the
+// concatenation of its dump files (see OpenDumpDirectory).
 // Input stream defaults to stdin. Output stream is always set to stdout.
 func NewIOStreams(driver string, dumpFile string) IOStreams {
 	io := IOStreams{In: os.Stdin, Out: os.Stdout}
@@ -95,7 +98,9 @@ func NewIOStreams(driver string, dumpFile string) IOStreams {
 		logger.Log.Info(fmt.Sprintf("\nLoading dump file from path: %s\n", dumpFile))
 		var f *os.File
 		var err error
-		if u.Scheme == constants.GCS_SCHEME {
+		if IsDumpDirectory(dumpFile) {
+			f, io.DumpDir, err = OpenDumpDirectory(dumpFile, io.Out)
+		} else if u.Scheme == constants.GCS_SCHEME {
 			bucketName := u.Host
 			filePath := u.Path[1:] // removes "/" from beginning of path
 			f, err = DownloadFromGCS(bucketName, filePath, "spanner-migration-tool.gcs.data")
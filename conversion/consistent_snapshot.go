@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mysql"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/postgres"
+)
+
+// beginConsistentSnapshot takes a consistent snapshot of the source database
+// when the source profile asks for one, and returns infoSchema reading all table
+// data from it. The captured source position is recorded in conv for the report.
+// The returned snapshot is nil if none was taken, and must otherwise be released
+// once the data migration is done.
+func beginConsistentSnapshot(conv *internal.Conv, sourceProfile profiles.SourceProfile, infoSchema common.InfoSchema) (common.InfoSchema, *common.ConsistentSnapshot, error) {
+	var snapshot *common.ConsistentSnapshot
+	var err error
+	switch isi := infoSchema.(type) {
+	case mysql.InfoSchemaImpl:
+		if !sourceProfile.Conn.Mysql.ConsistentSnapshot {
+			return infoSchema, nil, nil
+		}
+		if snapshot, err = takeConsistentSnapshot(isi.Db, constants.MYSQL); err != nil {
+			return infoSchema, nil, err
+		}
+		isi.Snapshot = snapshot
+		infoSchema = isi
+	case postgres.InfoSchemaImpl:
+		if !sourceProfile.Conn.Pg.ConsistentSnapshot {
+			return infoSchema, nil, nil
+		}
+		if snapshot, err = takeConsistentSnapshot(isi.Db, constants.POSTGRES); err != nil {
+			return infoSchema, nil, err
+		}
+		isi.Snapshot = snapshot
+		infoSchema = isi
+	default:
+		return infoSchema, nil, nil
+	}
+	conv.Audit.SnapshotPosition = snapshot.Position
+	if snapshot.Position == "" {
+		logger.Log.Warn("Reading data from a consistent snapshot, but the source position could not be captured. Is GTID based replication enabled?\n")
+	} else {
+		logger.Log.Info(fmt.Sprintf("Reading data from a consistent snapshot at source position %s\n", snapshot.Position))
+	}
+	return infoSchema, snapshot, nil
+}
+
+// takeConsistentSnapshot captures the current GTID set (MySQL) or WAL LSN
+// (PostgreSQL) of db and then starts a read only snapshot transaction on a
+// dedicated connection. Since the position is captured first, change data
+// capture started from it may replay changes already in the snapshot, which is
+// harmless as rows are written to Spanner with insert-or-update semantics.
+func takeConsistentSnapshot(db *sql.DB, driver string) (*common.ConsistentSnapshot, error) {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get a connection for the consistent snapshot: %w", err)
+	}
+	snapshot := &common.ConsistentSnapshot{Conn: conn}
+	switch driver {
+	case constants.MYSQL:
+		if err = conn.QueryRowContext(ctx, "SELECT @@GLOBAL.gtid_executed").Scan(&snapshot.Position); err != nil {
+			err = fmt.Errorf("couldn't read GTID set: %w", err)
+			break
+		}
+		if _, err = conn.ExecContext(ctx, "SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ"); err != nil {
+			break
+		}
+		if _, err = conn.ExecContext(ctx, "START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY"); err != nil {
+			err = fmt.Errorf("couldn't start consistent snapshot: %w", err)
+		}
+	case constants.POSTGRES:
+		// A standby has no WAL of its own, its replay position is used instead.
+		q := "SELECT (CASE WHEN pg_is_in_recovery() THEN pg_last_wal_replay_lsn() ELSE pg_current_wal_lsn() END)::text"
+		if err = conn.QueryRowContext(ctx, q).Scan(&snapshot.Position); err != nil {
+			err = fmt.Errorf("couldn't read WAL LSN: %w", err)
+			break
+		}
+		if _, err = conn.ExecContext(ctx, "BEGIN TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY"); err != nil {
+			err = fmt.Errorf("couldn't start consistent snapshot: %w", err)
+			break
+		}
+		if err = conn.QueryRowContext(ctx, "SELECT pg_export_snapshot()").Scan(&snapshot.SnapshotId); err != nil {
+			err = fmt.Errorf("couldn't export snapshot: %w", err)
+			conn.ExecContext(ctx, "ROLLBACK")
+		}
+	default:
+		err = fmt.Errorf("consistent snapshots are not supported for driver %s", driver)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mysql"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTakeConsistentSnapshot(t *testing.T) {
+	gtid := "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5"
+	testCases := []struct {
+		name               string
+		driver             string
+		setup              func(mock sqlmock.Sqlmock)
+		expectedPosition   string
+		expectedSnapshotId string
+		expectedErr        string
+	}{
+		{
+			name:   "mysql",
+			driver: constants.MYSQL,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT @@GLOBAL.gtid_executed")).WillReturnRows(sqlmock.NewRows([]string{"gtid"}).AddRow(gtid))
+				mock.ExpectExec("SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedPosition: gtid,
+		},
+		{
+			name:   "postgres",
+			driver: constants.POSTGRES,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("pg_current_wal_lsn()")).WillReturnRows(sqlmock.NewRows([]string{"lsn"}).AddRow("0/3000060"))
+				mock.ExpectExec("BEGIN TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_export_snapshot()")).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("00000003-0000001B-1"))
+			},
+			expectedPosition:   "0/3000060",
+			expectedSnapshotId: "00000003-0000001B-1",
+		},
+		{
+			name:   "postgres export fails",
+			driver: constants.POSTGRES,
+			setup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("pg_current_wal_lsn()")).WillReturnRows(sqlmock.NewRows([]string{"lsn"}).AddRow("0/3000060"))
+				mock.ExpectExec("BEGIN TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_export_snapshot()")).WillReturnError(fmt.Errorf("permission denied"))
+				mock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedErr: "couldn't export snapshot: permission denied",
+		},
+		{
+			name:        "unsupported driver",
+			driver:      constants.SQLSERVER,
+			setup:       func(mock sqlmock.Sqlmock) {},
+			expectedErr: "consistent snapshots are not supported for driver sqlserver",
+		},
+	}
+	for _, tc := range testCases {
+		db, mock, err := sqlmock.New()
+		assert.Nil(t, err)
+		tc.setup(mock)
+		snapshot, err := takeConsistentSnapshot(db, tc.driver)
+		if tc.expectedErr != "" {
+			assert.EqualError(t, err, tc.expectedErr, tc.name)
+			assert.Nil(t, snapshot, tc.name)
+		} else {
+			assert.Nil(t, err, tc.name)
+			assert.Equal(t, tc.expectedPosition, snapshot.Position, tc.name)
+			assert.Equal(t, tc.expectedSnapshotId, snapshot.SnapshotId, tc.name)
+		}
+		assert.Nil(t, mock.ExpectationsWereMet(), tc.name)
+	}
+}
+
+func TestBeginConsistentSnapshot(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	gtid := "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5"
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT @@GLOBAL.gtid_executed")).WillReturnRows(sqlmock.NewRows([]string{"gtid"}).AddRow(gtid))
+	mock.ExpectExec("SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("START TRANSACTION WITH CONSISTENT SNAPSHOT, READ ONLY").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM `test`.`t`;")).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectExec("ROLLBACK").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	conv := internal.MakeConv()
+	sourceProfile := profiles.SourceProfile{Conn: profiles.SourceProfileConnection{Mysql: profiles.SourceProfileConnectionMySQL{ConsistentSnapshot: true}}}
+	infoSchema, snapshot, err := beginConsistentSnapshot(conv, sourceProfile, mysql.InfoSchemaImpl{DbName: "test", Db: db})
+	assert.Nil(t, err)
+	assert.Equal(t, gtid, conv.Audit.SnapshotPosition)
+	assert.Equal(t, snapshot, infoSchema.(mysql.InfoSchemaImpl).Snapshot)
+	count, err := infoSchema.GetRowCount(common.SchemaAndName{Schema: "test", Name: "t"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.Nil(t, snapshot.Release())
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// No snapshot is taken unless the source profile asks for one.
+	conv = internal.MakeConv()
+	pgInfoSchema := postgres.InfoSchemaImpl{Db: db}
+	infoSchema, snapshot, err = beginConsistentSnapshot(conv, profiles.SourceProfile{}, pgInfoSchema)
+	assert.Nil(t, err)
+	assert.Nil(t, snapshot)
+	assert.Equal(t, pgInfoSchema, infoSchema)
+	assert.Equal(t, "", conv.Audit.SnapshotPosition)
+}
@@ -78,22 +78,35 @@ type ConvImpl struct{}
 // The SourceProfile param provides the connection details to use the go SQL library.
 func (ci *ConvImpl) SchemaConv(migrationProjectId string, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, ioHelper *utils.IOStreams, schemaFromSource SchemaFromSourceInterface) (*internal.Conv, error) {
 	var conv *internal.Conv
-	var err error
+	tableFilter, err := internal.NewTableFilter(sourceProfile.IncludeTables, sourceProfile.ExcludeTables)
+	if err != nil {
+		return nil, err
+	}
 	switch sourceProfile.Driver {
-	case constants.POSTGRES, constants.MYSQL, constants.DYNAMODB, constants.SQLSERVER, constants.ORACLE, constants.CASSANDRA:
+	case constants.POSTGRES, constants.MYSQL, constants.DYNAMODB, constants.SQLSERVER, constants.ORACLE, constants.CASSANDRA, constants.MONGODB, constants.DB2:
 		conv, err = schemaFromSource.schemaFromDatabase(migrationProjectId, sourceProfile, targetProfile, &GetInfoImpl{}, &common.ProcessSchemaImpl{})
 	case constants.PGDUMP, constants.MYSQLDUMP:
 		ddlVerifier, err := expressions_api.NewDDLVerifierImpl(context.Background(), targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance)
 		if err != nil {
 			fmt.Printf("Warning: failed to initialize expression verifier: %v\n", err)
 		}
-		conv, err = schemaFromSource.SchemaFromDump(targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance, sourceProfile.Driver, targetProfile.Conn.Sp.Dialect, ioHelper, &ProcessDumpByDialectImpl{ExpressionVerificationAccessor: ddlVerifier.Expressions, DdlVerifier: ddlVerifier}, targetProfile.DefaultIdentityOptions)
+		conv, err = schemaFromSource.SchemaFromDump(targetProfile.Conn.Sp.Project, targetProfile.Conn.Sp.Instance, sourceProfile.Driver, targetProfile.Conn.Sp.Dialect, ioHelper, &ProcessDumpByDialectImpl{ExpressionVerificationAccessor: ddlVerifier.Expressions, DdlVerifier: ddlVerifier}, targetProfile.DefaultIdentityOptions, targetProfile.SyntheticPKeyStrategy, internal.NameTemplates(targetProfile.NameTemplates), targetProfile.UnsignedIntPolicy, tableFilter)
 	default:
 		return nil, fmt.Errorf("schema conversion for driver %s not supported", sourceProfile.Driver)
 	}
 	conv.DatabaseOptions = ddl.DatabaseOptions{
 		DefaultTimezone: targetProfile.Conn.Sp.DefaultTimezone,
 	}
+	if targetProfile.CollationShadowColumns {
+		conv.AddNormalizedColumns()
+	}
+	if targetProfile.DropForeignKeys {
+		conv.DropAllForeignKeys()
+	}
+	if targetProfile.DropSecondaryIndexes {
+		conv.DropAllSecondaryIndexes()
+	}
+	conv.UpdateInterleaveStructureIssues()
 	return conv, err
 }
 
@@ -101,13 +114,26 @@ func (ci *ConvImpl) SchemaConv(migrationProjectId string, sourceProfile profiles
 // The SourceProfile param provides the connection details to use the go SQL library.
 func (ci *ConvImpl) DataConv(ctx context.Context, migrationProjectId string, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, ioHelper *utils.IOStreams, client *sp.Client, conv *internal.Conv, dataOnly bool, writeLimit int64, dataFromSource DataFromSourceInterface) (*writer.BatchWriter, error) {
 	config := writer.BatchWriterConfig{
-		BytesLimit: 100 * 1000 * 1000,
-		WriteLimit: writeLimit,
-		RetryLimit: 1000,
-		Verbose:    internal.Verbose(),
+		BytesLimit:     100 * 1000 * 1000,
+		WriteLimit:     writeLimit,
+		RetryLimit:     1000,
+		Verbose:        internal.Verbose(),
+		DeferLimit:     writer.DefaultDeferLimit,
+		RetryPolicy:    writer.DefaultRetryPolicy,
+		CircuitBreaker: writer.DefaultCircuitBreakerConfig,
+	}
+	config.CombineInterleaved = targetProfile.InterleavedBatches
+	if targetProfile.TimezonePolicy != "" {
+		conv.TimezonePolicy = targetProfile.TimezonePolicy
+	}
+	if targetProfile.NumericPrecisionPolicy != "" {
+		conv.NumericPrecisionPolicy = targetProfile.NumericPrecisionPolicy
+	}
+	if sourceProfile.File.Charset != "" {
+		conv.SourceCharset = sourceProfile.File.Charset
 	}
 	switch sourceProfile.Driver {
-	case constants.POSTGRES, constants.MYSQL, constants.DYNAMODB, constants.SQLSERVER, constants.ORACLE:
+	case constants.POSTGRES, constants.MYSQL, constants.DYNAMODB, constants.SQLSERVER, constants.ORACLE, constants.MONGODB:
 		return dataFromSource.dataFromDatabase(ctx, migrationProjectId, sourceProfile, targetProfile, config, conv, client, &GetInfoImpl{}, &DataFromDatabaseImpl{}, &SnapshotMigrationImpl{})
 	case constants.PGDUMP, constants.MYSQLDUMP:
 		if conv.SpSchema.CheckInterleaved() {
@@ -145,6 +171,14 @@ func (r *ReportImpl) GenerateReport(driver string, badWrites map[string]int64, B
 	}
 	f.Write(fBytes)
 
+	//Write the versioned JSON report file
+	jsonReportFileName := fmt.Sprintf("%s.%s", reportFileName, "migration_report.json")
+	jsonReport := reports.GenerateJSONReport(driver, dbName, conv, badWrites)
+	fBytes, _ = json.MarshalIndent(jsonReport, "", " ")
+	if err := os.WriteFile(jsonReportFileName, fBytes, 0644); err != nil {
+		fmt.Fprintf(out, "Can't write out JSON report file %s: %v\n", jsonReportFileName, err)
+	}
+
 	//Write the text report file from the structured report
 	textReportFileName := fmt.Sprintf("%s.%s", reportFileName, "report.txt")
 	f, err = os.Create(textReportFileName)
@@ -171,6 +205,7 @@ func (r *ReportImpl) GenerateReport(driver string, badWrites map[string]int64, B
 		fmt.Fprintf(out, "Processed source database via %s driver (%d rows of data, %d unexpected conditions).\n",
 			driver, conv.Rows(), conv.Unexpecteds())
 	}
+	fmt.Fprintf(out, "%s.\n", structuredReport.ObjectSummary)
 	// We've already written summary to f (as part of GenerateReport).
 	// In the case where f is stdout, don't write a duplicate copy.
 	if f != out {
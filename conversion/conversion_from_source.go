@@ -32,6 +32,8 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/csv"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mongodb"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mysql"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/writer"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/streaming"
@@ -40,7 +42,7 @@ import (
 
 type SchemaFromSourceInterface interface {
 	schemaFromDatabase(migrationProjectId string, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, getInfo GetInfoInterface, processSchema common.ProcessSchemaInterface) (*internal.Conv, error)
-	SchemaFromDump(SpProjectId string, SpInstanceId string, driver string, spDialect string, ioHelper *utils.IOStreams, processDump ProcessDumpByDialectInterface, defaultIdentityOptions profiles.DefaultIdentityOptions) (*internal.Conv, error)
+	SchemaFromDump(SpProjectId string, SpInstanceId string, driver string, spDialect string, ioHelper *utils.IOStreams, processDump ProcessDumpByDialectInterface, defaultIdentityOptions profiles.DefaultIdentityOptions, syntheticPKeyStrategy string, nameTemplates internal.NameTemplates, unsignedIntPolicy string, tableFilter internal.TableFilter) (*internal.Conv, error)
 }
 
 type SchemaFromSourceImpl struct {
@@ -66,10 +68,17 @@ func (sads *SchemaFromSourceImpl) schemaFromDatabase(migrationProjectId string,
 		SkipRangeMax: targetProfile.DefaultIdentityOptions.SkipRangeMax,
 		StartCounterWith: targetProfile.DefaultIdentityOptions.StartCounterWith,
 	}
+	conv.SyntheticPKeyStrategy = targetProfile.SyntheticPKeyStrategy
+	conv.NameTemplates = internal.NameTemplates(targetProfile.NameTemplates)
+	conv.UnsignedIntPolicy = targetProfile.UnsignedIntPolicy
+	var err error
+	conv.TableFilter, err = internal.NewTableFilter(sourceProfile.IncludeTables, sourceProfile.ExcludeTables)
+	if err != nil {
+		return nil, err
+	}
 	//handle fetching schema differently for sharded migrations, we only connect to the primary shard to
 	//fetch the schema. We reuse the SourceProfileConnection object for this purpose.
 	var infoSchema common.InfoSchema
-	var err error
 	isSharded := false
 	switch sourceProfile.Ty {
 	case profiles.SourceProfileTypeConfig:
@@ -115,10 +124,33 @@ func (sads *SchemaFromSourceImpl) schemaFromDatabase(migrationProjectId string,
 		DdlV:                           sads.DdlVerifier,
 		ExpressionVerificationAccessor: expressionVerificationAccessor,
 	}
-	return conv, processSchema.ProcessSchema(conv, infoSchema, common.DefaultWorkers, additionalSchemaAttributes, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
+	err = processSchema.ProcessSchema(conv, infoSchema, common.DefaultWorkers, additionalSchemaAttributes, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
+	if err == nil && sourceProfile.Driver == constants.MONGODB {
+		mongodb.InterleaveChildTables(conv)
+	}
+	if estimator, ok := infoSchema.(rowCountEstimator); ok && err == nil {
+		estimator.EstimateRowCounts(conv)
+	}
+	if collector, ok := infoSchema.(columnStatsCollector); ok && err == nil && sourceProfile.ColumnStatsSample() > 0 {
+		logger.Log.Info(fmt.Sprintf("Sampling %d rows of each table for column statistics", sourceProfile.ColumnStatsSample()))
+		collector.CollectColumnStats(conv, sourceProfile.ColumnStatsSample())
+	}
+	return conv, err
 }
 
-func (sads *SchemaFromSourceImpl) SchemaFromDump(SpProjectId string, SpInstanceId string, driver string, spDialect string, ioHelper *utils.IOStreams, processDump ProcessDumpByDialectInterface, defaultIdentityOptions profiles.DefaultIdentityOptions) (*internal.Conv, error) {
+// rowCountEstimator is implemented by the info schemas of the sources which
+// estimate the number of rows of their tables in their catalog.
+type rowCountEstimator interface {
+	EstimateRowCounts(conv *internal.Conv)
+}
+
+// columnStatsCollector is implemented by the info schemas of the sources
+// which can sample their tables for column statistics.
+type columnStatsCollector interface {
+	CollectColumnStats(conv *internal.Conv, sampleRows int)
+}
+
+func (sads *SchemaFromSourceImpl) SchemaFromDump(SpProjectId string, SpInstanceId string, driver string, spDialect string, ioHelper *utils.IOStreams, processDump ProcessDumpByDialectInterface, defaultIdentityOptions profiles.DefaultIdentityOptions, syntheticPKeyStrategy string, nameTemplates internal.NameTemplates, unsignedIntPolicy string, tableFilter internal.TableFilter) (*internal.Conv, error) {
 	f, n, err := getSeekable(ioHelper.In)
 	if err != nil {
 		utils.PrintSeekError(driver, err, ioHelper.Out)
@@ -136,6 +168,10 @@ func (sads *SchemaFromSourceImpl) SchemaFromDump(SpProjectId string, SpInstanceI
 		SkipRangeMax: defaultIdentityOptions.SkipRangeMax,
 		StartCounterWith: defaultIdentityOptions.StartCounterWith,
 	}
+	conv.SyntheticPKeyStrategy = syntheticPKeyStrategy
+	conv.NameTemplates = nameTemplates
+	conv.UnsignedIntPolicy = unsignedIntPolicy
+	conv.TableFilter = tableFilter
 	p := internal.NewProgress(n, "Generating schema", internal.Verbose(), false, int(internal.SchemaCreationInProgress))
 	r := internal.NewReader(bufio.NewReader(f), p)
 	conv.SetSchemaMode() // Build schema and ignore data in dump.
@@ -172,10 +208,37 @@ func (sads *DataFromSourceImpl) dataFromDump(driver string, config writer.BatchW
 	totalRows := conv.Rows()
 
 	conv.Audit.Progress = *internal.NewProgress(totalRows, "Writing data to Spanner", internal.Verbose(), false, int(internal.DataWriteInProgress))
-	r := internal.NewReader(bufio.NewReader(ioHelper.SeekableIn), nil)
 	batchWriter := populateDataConv.populateDataConv(conv, config, client)
-	processDump.ProcessDump(driver, conv, r)
-	batchWriter.Flush()
+	// A sampled dump is read once per round, the rows referenced by the
+	// rows of a round being written in the previous rounds.
+	rounds := conv.DataSampleRounds()
+	if rounds == nil {
+		rounds = [][]string{nil}
+	}
+	for i, round := range rounds {
+		if conv.Interrupted() {
+			break
+		}
+		if i > 0 {
+			if _, err := ioHelper.SeekableIn.Seek(0, 0); err != nil {
+				return nil, fmt.Errorf("can't seek to start of file for round %d of the sampled data migration: %v", i+1, err)
+			}
+		}
+		conv.SetDataSampleRound(round)
+		if driver == constants.MYSQLDUMP && mysql.IsMydumperExport(ioHelper.DumpDir) {
+			// The data files of mydumper exports are loaded in parallel.
+			if err := mysql.ProcessMydumperData(conv, ioHelper.DumpDir, conv.TableReadParallelism); err != nil {
+				logger.Log.Error(fmt.Sprintf("Error loading mydumper data: %v", err))
+				fmt.Fprintf(ioHelper.Out, "Error loading mydumper data: %v\n", err)
+			}
+		} else {
+			processDump.ProcessDump(driver, conv, internal.NewReader(bufio.NewReader(ioHelper.SeekableIn), nil))
+		}
+		conv.FlushMaskedRows()
+		batchWriter.Flush()
+	}
+	conv.SetDataSampleRound(nil)
+	batchWriter.RetryDeferredRows()
 	conv.Audit.Progress.Done()
 
 	return batchWriter, nil
@@ -219,7 +282,9 @@ func (sads *DataFromSourceImpl) dataFromCSV(ctx context.Context, sourceProfile p
 	if err != nil {
 		return nil, fmt.Errorf("can't process csv: %v", err)
 	}
+	conv.FlushMaskedRows()
 	batchWriter.Flush()
+	batchWriter.RetryDeferredRows()
 	conv.Audit.Progress.Done()
 	return batchWriter, nil
 }
@@ -342,6 +407,22 @@ func (sads *DataFromSourceImpl) dataFromDatabase(ctx context.Context, migrationP
 			return bw, nil
 		}
 		//bulk migration for a single shard
+		replicaProfile, useReplica, err := replicaSourceProfile(sourceProfile)
+		if err != nil {
+			return nil, err
+		}
+		if useReplica {
+			// Schema metadata was read from the primary, data is read from the replica.
+			infoSchema, err = getInfo.GetInfoSchema(migrationProjectId, replicaProfile, targetProfile)
+			if err != nil {
+				return nil, err
+			}
+		}
+		infoSchema, consistentSnapshot, err := beginConsistentSnapshot(conv, sourceProfile, infoSchema)
+		if err != nil {
+			return nil, err
+		}
+		defer consistentSnapshot.Release()
 		return snapshotMigration.performSnapshotMigration(config, conv, client, infoSchema, internal.AdditionalDataAttributes{ShardId: ""}, &common.InfoSchemaImpl{}, &PopulateDataConvImpl{}), nil
 	}
 }
@@ -119,14 +119,22 @@ func (pdc *PopulateDataConvImpl) populateDataConv(conv *internal.Conv, config wr
 		conv.Audit.Progress.MaybeReport(atomic.LoadInt64(&rows))
 		return nil
 	}
+	config.OnDroppedRow = func(table string, cols []string, vals []interface{}, err error) {
+		conv.Status.AddDroppedRow(table)
+		conv.CollectBadWrite(table, cols, vals, err)
+	}
+	config.OnWrittenRows = conv.Status.AddWrittenRows
+	config.Interleaved = writer.InterleavedTables(conv.SpSchema)
 	batchWriter := writer.NewBatchWriter(config)
 	conv.SetDataMode()
+	conv.Status.StartData(conv)
 	if !conv.Audit.DryRun {
 		conv.SetDataSink(
 			func(table string, cols []string, vals []interface{}) {
 				batchWriter.AddRow(table, cols, vals)
 			})
 		conv.DataFlush = func() {
+			conv.FlushMaskedRows()
 			batchWriter.Flush()
 		}
 	}
@@ -151,7 +159,7 @@ func ConnectionConfig(sourceProfile profiles.SourceProfile) (interface{}, error)
 		// If empty, this is called as part of the legacy mode witih global CLI flags.
 		// When using source-profile mode is used, the sqlConnectionStr is already populated.
 		mysqlConn := sourceProfile.Conn.Mysql
-		if !(mysqlConn.Host != "" && mysqlConn.User != "" && mysqlConn.Db != "") {
+		if !(mysqlConn.Host != "" && mysqlConn.User != "" && (mysqlConn.Db != "" || len(mysqlConn.DbNames) > 0)) {
 			return profiles.GenerateMYSQLConnectionStr()
 		} else {
 			return profiles.GetSQLConnectionStr(sourceProfile), nil
@@ -163,9 +171,14 @@ func ConnectionConfig(sourceProfile profiles.SourceProfile) (interface{}, error)
 		return profiles.GetSQLConnectionStr(sourceProfile), nil
 	case constants.ORACLE:
 		return profiles.GetSQLConnectionStr(sourceProfile), nil
+	case constants.DB2:
+		return profiles.GetSQLConnectionStr(sourceProfile), nil
 	// Returns an empty string as Cassandra connections are managed directly by the gocql session.	
 	case constants.CASSANDRA:
 		return "", nil
+	// MongoDB collections are read from the mongoexport files of the source profile.
+	case constants.MONGODB:
+		return "", nil
 	default:
 		return "", fmt.Errorf("driver %s not supported", sourceProfile.Driver)
 	}
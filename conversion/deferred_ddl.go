@@ -0,0 +1,122 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+)
+
+// deferredDdlScriptTemplate is the body of the deferred DDL script. Each
+// statement is applied with a separate schema update, and recorded in the
+// state file once applied so that a re-run resumes after the last applied
+// statement.
+const deferredDdlScriptTemplate = `#!/usr/bin/env bash
+# Generated by Spanner migration tool on %s.
+#
+# Creates the secondary indexes and foreign keys dropped from the schema of
+# the migrated database, e.g. during a low-traffic window once the data is
+# loaded. Indexes are created before foreign keys. The statements applied are
+# recorded in STATE_FILE: if the script is interrupted, re-run it to resume.
+# PROJECT, INSTANCE, DATABASE and STATE_FILE can be overridden with
+# environment variables.
+set -uo pipefail
+
+PROJECT="${PROJECT:-%s}"
+INSTANCE="${INSTANCE:-%s}"
+DATABASE="${DATABASE:-%s}"
+STATE_FILE="${STATE_FILE:-${DATABASE}.deferred_ddl.state}"
+
+if [[ -z "$PROJECT" || -z "$INSTANCE" || -z "$DATABASE" ]]; then
+  echo "PROJECT, INSTANCE and DATABASE must be set" >&2
+  exit 1
+fi
+touch "$STATE_FILE"
+
+apply() {
+  local id="$1" stmt="$2" out
+  if grep -qxF "$id" "$STATE_FILE"; then
+    echo "Skipping $id: already applied"
+    return
+  fi
+  echo "Applying $id"
+  if ! out=$(gcloud spanner databases ddl update "$DATABASE" --project="$PROJECT" --instance="$INSTANCE" --ddl="$stmt" 2>&1); then
+    # The object may have been created by an interrupted run.
+    if [[ "$out" != *"Duplicate name in schema"* ]]; then
+      echo "$out" >&2
+      echo "Failed to apply $id, re-run the script to resume" >&2
+      exit 1
+    fi
+    echo "$id already exists"
+  fi
+  echo "$id" >> "$STATE_FILE"
+}
+
+%s
+echo "All deferred statements are applied"
+`
+
+// GetDeferredDdlScript returns a bash script creating the foreign keys and
+// secondary indexes dropped in bulk from the schema of conv (see
+// Conv.DropAllForeignKeys), or "" if none were dropped. project, instance and
+// dbName identify the Spanner database, and can be left empty to be set with
+// environment variables when running the script.
+func GetDeferredDdlScript(conv *internal.Conv, project, instance, dbName string, now time.Time) string {
+	var indexes, fks []string
+	var tableIds []string
+	for tableId := range conv.DroppedObjects {
+		tableIds = append(tableIds, tableId)
+	}
+	sort.Strings(tableIds)
+	for _, tableId := range tableIds {
+		for _, o := range conv.DroppedObjects[tableId] {
+			call := fmt.Sprintf("apply %s %s", shellQuote(o.Kind+" "+o.Name), shellQuote(o.Statement))
+			if o.Kind == "INDEX" {
+				indexes = append(indexes, call)
+			} else {
+				fks = append(fks, call)
+			}
+		}
+	}
+	if len(indexes)+len(fks) == 0 {
+		return ""
+	}
+	calls := strings.Join(append(indexes, fks...), "\n")
+	return fmt.Sprintf(deferredDdlScriptTemplate, now.Format("2006-01-02 15:04:05"), project, instance, dbName, calls)
+}
+
+// WriteDeferredDdlScript writes the script returned by GetDeferredDdlScript
+// to file name, if any foreign keys or secondary indexes were dropped.
+func WriteDeferredDdlScript(conv *internal.Conv, project, instance, dbName, name string, out *os.File) {
+	script := GetDeferredDdlScript(conv, project, instance, dbName, time.Now())
+	if script == "" {
+		return
+	}
+	if err := os.WriteFile(name, []byte(script), 0755); err != nil {
+		fmt.Fprintf(out, "Can't write out deferred DDL script %s: %v\n", name, err)
+		return
+	}
+	fmt.Fprintf(out, "Wrote the script creating the dropped foreign keys and indexes to file '%s'.\n", name)
+}
+
+// shellQuote quotes s as a single bash word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDeferredDdlScript(t *testing.T) {
+	conv := internal.MakeConv()
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Equal(t, "", GetDeferredDdlScript(conv, "p", "i", "db", now))
+
+	conv.DroppedObjects = map[string][]internal.DroppedObject{
+		"t1": {{Kind: "FOREIGN KEY", Name: "fk_users", Statement: "ALTER TABLE `orders` ADD CONSTRAINT `fk_users` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)"}},
+		"t2": {{Kind: "INDEX", Name: "users_name", Statement: "CREATE INDEX `users_name` ON `users` (`name`) WHERE name != 'x'"}},
+	}
+	script := GetDeferredDdlScript(conv, "p", "i", "db", now)
+	assert.True(t, strings.HasPrefix(script, "#!/usr/bin/env bash\n# Generated by Spanner migration tool on 2025-01-02 03:04:05."))
+	assert.Contains(t, script, `PROJECT="${PROJECT:-p}"`)
+	assert.Contains(t, script, `DATABASE="${DATABASE:-db}"`)
+	// Indexes are created before foreign keys, and quotes are escaped.
+	assert.Contains(t, script, "apply 'INDEX users_name' 'CREATE INDEX `users_name` ON `users` (`name`) WHERE name != '\\''x'\\'''\n"+
+		"apply 'FOREIGN KEY fk_users' 'ALTER TABLE `orders` ADD CONSTRAINT `fk_users` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)'\n")
+}
@@ -28,7 +28,9 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/cassandra"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/db2"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/dynamodb"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mongodb"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mysql"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/oracle"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/postgres"
@@ -172,7 +174,7 @@ func (gi *GetInfoImpl) GetInfoSchema(migrationProjectId string, sourceProfile pr
 	driver := sourceProfile.Driver
 	switch driver {
 	case constants.MYSQL:
-		db, err := sql.Open(driver, connectionConfig.(string))
+		db, err := openSQLSource(driver, connectionConfig.(string), sourceProfile.ConnectionPool())
 		dbName := getDbNameFromSQLConnectionStr(driver, connectionConfig.(string))
 		if err != nil {
 			return nil, err
@@ -180,6 +182,14 @@ func (gi *GetInfoImpl) GetInfoSchema(migrationProjectId string, sourceProfile pr
 		if err = db.Ping(); err != nil {
 			return nil, fmt.Errorf("failed to connect to source database: %w", err)
 		}
+		if len(sourceProfile.Conn.Mysql.DbNames) > 0 {
+			// Resolve the database patterns so that the tables of all matching
+			// databases are merged into one Spanner database.
+			sourceProfile.Conn.Mysql.DbNames, err = listDatabases(db, driver, sourceProfile.Conn.Mysql.DbNames)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return mysql.InfoSchemaImpl{
 			DbName:             dbName,
 			Db:                 db,
@@ -188,7 +198,7 @@ func (gi *GetInfoImpl) GetInfoSchema(migrationProjectId string, sourceProfile pr
 			TargetProfile:      targetProfile,
 		}, nil
 	case constants.POSTGRES:
-		db, err := sql.Open(driver, connectionConfig.(string))
+		db, err := openSQLSource(driver, connectionConfig.(string), sourceProfile.ConnectionPool())
 		if err != nil {
 			return nil, err
 		}
@@ -246,7 +256,79 @@ func (gi *GetInfoImpl) GetInfoSchema(migrationProjectId string, sourceProfile pr
 			SourceProfile:    sourceProfile,
 			TargetProfile:    targetProfile,
 		}, nil
+	case constants.MONGODB:
+		return mongodb.NewInfoSchemaImpl(sourceProfile.Conn.MongoDB.ExportDir, profiles.GetSchemaSampleSize(sourceProfile)), nil
+	case constants.DB2:
+		db, err := db2.Open(connectionConfig.(string))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't open DB2 connection: %w", err)
+		}
+		if err = db.Ping(); err != nil {
+			return nil, fmt.Errorf("failed to connect to source database: %w", err)
+		}
+		return db2.InfoSchemaImpl{DbName: sourceProfile.Conn.DB2.Db, Schema: sourceProfile.Conn.DB2.Schema, Db: db}, nil
 	default:
 		return nil, fmt.Errorf("driver %s not supported", driver)
 	}
 }
+
+// ListSourceDatabases returns the databases of the source server matching the
+// database names and patterns of a MySQL or PostgreSQL source profile.
+func ListSourceDatabases(sourceProfile profiles.SourceProfile) ([]string, error) {
+	var patterns []string
+	switch sourceProfile.Driver {
+	case constants.MYSQL:
+		patterns = sourceProfile.Conn.Mysql.DbNames
+	case constants.POSTGRES:
+		patterns = sourceProfile.Conn.Pg.DbNames
+		// PostgreSQL connections are always to a database, the catalog of
+		// databases is readable from the default one.
+		sourceProfile.Conn.Pg.Db = "postgres"
+	default:
+		return nil, fmt.Errorf("driver %s does not support migrating several databases", sourceProfile.Driver)
+	}
+	connectionConfig, err := ConnectionConfig(sourceProfile)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(sourceProfile.Driver, connectionConfig.(string))
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return listDatabases(db, sourceProfile.Driver, patterns)
+}
+
+// listDatabases returns the user databases matching any of the patterns.
+func listDatabases(db *sql.DB, driver string, patterns []string) ([]string, error) {
+	var q string
+	var system map[string]bool
+	switch driver {
+	case constants.MYSQL:
+		q = "SELECT schema_name FROM information_schema.schemata ORDER BY schema_name"
+		system = map[string]bool{"information_schema": true, "mysql": true, "performance_schema": true, "sys": true}
+	case constants.POSTGRES:
+		q = "SELECT datname FROM pg_database WHERE NOT datistemplate ORDER BY datname"
+		system = map[string]bool{"postgres": true}
+	}
+	rows, err := db.Query(q)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't list source databases: %w", err)
+	}
+	defer rows.Close()
+	var available []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("couldn't list source databases: %w", err)
+		}
+		if !system[name] {
+			available = append(available, name)
+		}
+	}
+	dbNames := profiles.MatchDatabaseNames(patterns, available)
+	if len(dbNames) == 0 {
+		return nil, fmt.Errorf("no source databases match %s", strings.Join(patterns, ";"))
+	}
+	return dbNames, nil
+}
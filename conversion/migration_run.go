@@ -0,0 +1,112 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+)
+
+// MigrationRun is a row of the SMT_MIGRATION_RUN table of the metadata
+// database, which keeps the history of the migrations run with the tool.
+type MigrationRun struct {
+	RunId               string
+	MigrationType       string
+	SourceDatabaseType  string
+	SourceDatabaseName  string // Database name, or dump file path, of the source.
+	SpannerProjectId    string
+	SpannerInstanceId   string
+	SpannerDatabaseName string
+	Dialect             string
+	StartedAt           time.Time
+	EndedAt             time.Time
+	RowCount            int64 // ROWS is a reserved keyword.
+	GoodRows            int64
+	BadRows             int64
+	SessionFile         string // Session file the schema of the run can be restored from.
+}
+
+// NewMigrationRun builds the record of a migration run which started at
+// startedAt and just ended.
+func NewMigrationRun(conv *internal.Conv, sourceProfile profiles.SourceProfile, targetProfile profiles.TargetProfile, sessionFile string, startedAt time.Time) MigrationRun {
+	run := MigrationRun{
+		RunId:               conv.Audit.MigrationRequestId,
+		SourceDatabaseType:  sourceProfile.Driver,
+		SourceDatabaseName:  sourceDatabaseName(sourceProfile),
+		SpannerProjectId:    targetProfile.Conn.Sp.Project,
+		SpannerInstanceId:   targetProfile.Conn.Sp.Instance,
+		SpannerDatabaseName: targetProfile.Conn.Sp.Dbname,
+		Dialect:             conv.SpDialect,
+		StartedAt:           startedAt,
+		EndedAt:             time.Now(),
+		RowCount:            conv.Rows(),
+		BadRows:             conv.BadRows(),
+		SessionFile:         sessionFile,
+	}
+	if conv.Audit.MigrationType != nil {
+		run.MigrationType = conv.Audit.MigrationType.String()
+	}
+	for _, n := range conv.Stats.GoodRows {
+		run.GoodRows += n
+	}
+	return run
+}
+
+// RecordMigrationRun writes run to the metadata database of the Spanner
+// instance the migration targeted.
+func RecordMigrationRun(ctx context.Context, run MigrationRun) error {
+	dbURI := fmt.Sprintf("projects/%s/instances/%s/databases/%s", run.SpannerProjectId, run.SpannerInstanceId, constants.METADATA_DB)
+	client, err := utils.GetClient(ctx, dbURI)
+	if err != nil {
+		return fmt.Errorf("can't create client for db %s: %v", dbURI, err)
+	}
+	defer client.Close()
+	mutation, err := spanner.InsertOrUpdateStruct(constants.SMT_MIGRATION_RUN_TABLE, run)
+	if err != nil {
+		return err
+	}
+	if _, err := client.Apply(ctx, []*spanner.Mutation{mutation}); err != nil {
+		return fmt.Errorf("can't record migration run %s: %v", run.RunId, err)
+	}
+	logger.Log.Info(fmt.Sprintf("Recorded migration run %s in the '%s' database.\n", run.RunId, constants.METADATA_DB))
+	return nil
+}
+
+// sourceDatabaseName returns the name of the source database, or the path of
+// the dump file, of sourceProfile.
+func sourceDatabaseName(sourceProfile profiles.SourceProfile) string {
+	if sourceProfile.Ty == profiles.SourceProfileTypeFile {
+		return sourceProfile.File.Path
+	}
+	switch sourceProfile.Driver {
+	case constants.MYSQL:
+		return sourceProfile.Conn.Mysql.Db
+	case constants.POSTGRES:
+		return sourceProfile.Conn.Pg.Db
+	case constants.SQLSERVER:
+		return sourceProfile.Conn.SqlServer.Db
+	case constants.ORACLE:
+		return sourceProfile.Conn.Oracle.Db
+	}
+	return ""
+}
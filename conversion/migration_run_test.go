@@ -0,0 +1,65 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/proto/migration"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMigrationRun(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SpDialect = constants.DIALECT_GOOGLESQL
+	conv.Audit.MigrationRequestId = "smt-job-1"
+	conv.Audit.MigrationType = migration.MigrationData_SCHEMA_AND_DATA.Enum()
+	conv.Stats.Rows = map[string]int64{"t1": 10, "t2": 5}
+	conv.Stats.GoodRows = map[string]int64{"t1": 8, "t2": 5}
+	conv.Stats.BadRows = map[string]int64{"t1": 2}
+	sourceProfile := profiles.SourceProfile{
+		Driver: constants.MYSQL,
+		Ty:     profiles.SourceProfileTypeConnection,
+		Conn:   profiles.SourceProfileConnection{Mysql: profiles.SourceProfileConnectionMySQL{Db: "shop"}},
+	}
+	targetProfile := profiles.TargetProfile{Conn: profiles.TargetProfileConnection{Sp: profiles.TargetProfileConnectionSpanner{Project: "p", Instance: "i", Dbname: "db"}}}
+	start := time.Now().Add(-time.Minute)
+
+	run := NewMigrationRun(conv, sourceProfile, targetProfile, "/tmp/session.json", start)
+	assert.False(t, run.EndedAt.Before(start))
+	run.EndedAt = time.Time{}
+	assert.Equal(t, MigrationRun{
+		RunId:               "smt-job-1",
+		MigrationType:       "SCHEMA_AND_DATA",
+		SourceDatabaseType:  constants.MYSQL,
+		SourceDatabaseName:  "shop",
+		SpannerProjectId:    "p",
+		SpannerInstanceId:   "i",
+		SpannerDatabaseName: "db",
+		Dialect:             constants.DIALECT_GOOGLESQL,
+		StartedAt:           start,
+		RowCount:            15,
+		GoodRows:            13,
+		BadRows:             2,
+		SessionFile:         "/tmp/session.json",
+	}, run)
+
+	sourceProfile = profiles.SourceProfile{Driver: constants.PGDUMP, Ty: profiles.SourceProfileTypeFile, File: profiles.SourceProfileFile{Path: "dump.sql"}}
+	assert.Equal(t, "dump.sql", NewMigrationRun(conv, sourceProfile, targetProfile, "", start).SourceDatabaseName)
+}
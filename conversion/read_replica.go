@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+)
+
+// replicaPollInterval is how often a PostgreSQL replica is checked for having
+// replayed the primary's WAL position.
+var replicaPollInterval = time.Second
+
+// replicaSourceProfile returns the source profile of the read replica that data
+// is extracted from, and false if no read replica is configured. When the replica
+// must be consistent with the primary, it first waits for the replica to apply
+// the primary's current position.
+func replicaSourceProfile(sourceProfile profiles.SourceProfile) (profiles.SourceProfile, bool, error) {
+	replicaProfile, ok := sourceProfile.ReplicaProfile()
+	if !ok {
+		return sourceProfile, false, nil
+	}
+	var replica profiles.SourceProfileReplica
+	switch sourceProfile.Driver {
+	case constants.MYSQL:
+		replica = sourceProfile.Conn.Mysql.Replica
+	case constants.POSTGRES:
+		replica = sourceProfile.Conn.Pg.Replica
+	}
+	logger.Log.Info(fmt.Sprintf("Reading data from read replica %s:%s\n", replica.Host, replica.Port))
+	if !replica.WaitForPrimary {
+		return replicaProfile, true, nil
+	}
+	primaryDb, err := openSourceDb(sourceProfile)
+	if err != nil {
+		return sourceProfile, false, err
+	}
+	defer primaryDb.Close()
+	replicaDb, err := openSourceDb(replicaProfile)
+	if err != nil {
+		return sourceProfile, false, fmt.Errorf("failed to connect to read replica: %w", err)
+	}
+	defer replicaDb.Close()
+	if err := waitForReplica(primaryDb, replicaDb, sourceProfile.Driver, replica.SyncTimeout); err != nil {
+		return sourceProfile, false, err
+	}
+	return replicaProfile, true, nil
+}
+
+func openSourceDb(sourceProfile profiles.SourceProfile) (*sql.DB, error) {
+	connectionConfig, err := ConnectionConfig(sourceProfile)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openSQLSource(sourceProfile.Driver, connectionConfig.(string), sourceProfile.ConnectionPool())
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to source database: %w", err)
+	}
+	return db, nil
+}
+
+// waitForReplica captures the current GTID set (MySQL) or WAL LSN (PostgreSQL)
+// of the primary and waits until the replica has applied it, so that data read
+// from the replica is at least as recent as the primary was when the data
+// migration started.
+func waitForReplica(primaryDb, replicaDb *sql.DB, driver string, timeout time.Duration) error {
+	switch driver {
+	case constants.MYSQL:
+		var gtidSet string
+		if err := primaryDb.QueryRow("SELECT @@GLOBAL.gtid_executed").Scan(&gtidSet); err != nil {
+			return fmt.Errorf("couldn't read GTID set of primary: %w", err)
+		}
+		logger.Log.Info(fmt.Sprintf("Waiting for read replica to apply primary GTID set %s\n", gtidSet))
+		// WAIT_FOR_EXECUTED_GTID_SET returns 0 once the GTIDs are applied and 1 on timeout.
+		var timedOut int
+		if err := replicaDb.QueryRow("SELECT WAIT_FOR_EXECUTED_GTID_SET(?, ?)", gtidSet, int(timeout.Seconds())).Scan(&timedOut); err != nil {
+			return fmt.Errorf("couldn't wait for read replica to apply GTID set %s: %w", gtidSet, err)
+		}
+		if timedOut != 0 {
+			return fmt.Errorf("read replica did not apply GTID set %s within %v", gtidSet, timeout)
+		}
+	case constants.POSTGRES:
+		var lsn string
+		if err := primaryDb.QueryRow("SELECT pg_current_wal_lsn()::text").Scan(&lsn); err != nil {
+			return fmt.Errorf("couldn't read WAL LSN of primary: %w", err)
+		}
+		logger.Log.Info(fmt.Sprintf("Waiting for read replica to replay primary WAL LSN %s\n", lsn))
+		deadline := time.Now().Add(timeout)
+		for {
+			var replayed bool
+			if err := replicaDb.QueryRow("SELECT COALESCE(pg_last_wal_replay_lsn() >= $1::pg_lsn, false)", lsn).Scan(&replayed); err != nil {
+				return fmt.Errorf("couldn't wait for read replica to replay WAL LSN %s: %w", lsn, err)
+			}
+			if replayed {
+				break
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("read replica did not replay WAL LSN %s within %v", lsn, timeout)
+			}
+			time.Sleep(replicaPollInterval)
+		}
+	default:
+		return fmt.Errorf("read replicas are not supported for driver %s", driver)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForReplica(t *testing.T) {
+	replicaPollInterval = time.Millisecond
+	gtid := "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5"
+	testCases := []struct {
+		name        string
+		driver      string
+		setup       func(primary, replica sqlmock.Sqlmock)
+		expectedErr string
+	}{
+		{
+			name:   "mysql replica caught up",
+			driver: constants.MYSQL,
+			setup: func(primary, replica sqlmock.Sqlmock) {
+				primary.ExpectQuery(regexp.QuoteMeta("SELECT @@GLOBAL.gtid_executed")).WillReturnRows(sqlmock.NewRows([]string{"gtid"}).AddRow(gtid))
+				replica.ExpectQuery(regexp.QuoteMeta("SELECT WAIT_FOR_EXECUTED_GTID_SET(?, ?)")).WithArgs(gtid, 60).WillReturnRows(sqlmock.NewRows([]string{"r"}).AddRow(0))
+			},
+		},
+		{
+			name:   "mysql replica timed out",
+			driver: constants.MYSQL,
+			setup: func(primary, replica sqlmock.Sqlmock) {
+				primary.ExpectQuery(regexp.QuoteMeta("SELECT @@GLOBAL.gtid_executed")).WillReturnRows(sqlmock.NewRows([]string{"gtid"}).AddRow(gtid))
+				replica.ExpectQuery(regexp.QuoteMeta("SELECT WAIT_FOR_EXECUTED_GTID_SET(?, ?)")).WithArgs(gtid, 60).WillReturnRows(sqlmock.NewRows([]string{"r"}).AddRow(1))
+			},
+			expectedErr: "read replica did not apply GTID set " + gtid + " within 1m0s",
+		},
+		{
+			name:   "postgres replica replays after polling",
+			driver: constants.POSTGRES,
+			setup: func(primary, replica sqlmock.Sqlmock) {
+				primary.ExpectQuery(regexp.QuoteMeta("SELECT pg_current_wal_lsn()::text")).WillReturnRows(sqlmock.NewRows([]string{"lsn"}).AddRow("0/3000060"))
+				replica.ExpectQuery(regexp.QuoteMeta("pg_last_wal_replay_lsn()")).WithArgs("0/3000060").WillReturnRows(sqlmock.NewRows([]string{"r"}).AddRow(false))
+				replica.ExpectQuery(regexp.QuoteMeta("pg_last_wal_replay_lsn()")).WithArgs("0/3000060").WillReturnRows(sqlmock.NewRows([]string{"r"}).AddRow(true))
+			},
+		},
+		{
+			name:        "unsupported driver",
+			driver:      constants.SQLSERVER,
+			setup:       func(primary, replica sqlmock.Sqlmock) {},
+			expectedErr: "read replicas are not supported for driver sqlserver",
+		},
+	}
+	for _, tc := range testCases {
+		primaryDb, primary, err := sqlmock.New()
+		assert.Nil(t, err)
+		replicaDb, replica, err := sqlmock.New()
+		assert.Nil(t, err)
+		tc.setup(primary, replica)
+		err = waitForReplica(primaryDb, replicaDb, tc.driver, time.Minute)
+		if tc.expectedErr == "" {
+			assert.Nil(t, err, tc.name)
+		} else {
+			assert.EqualError(t, err, tc.expectedErr, tc.name)
+		}
+		assert.Nil(t, primary.ExpectationsWereMet(), tc.name)
+		assert.Nil(t, replica.ExpectationsWereMet(), tc.name)
+	}
+}
+
+func TestReplicaSourceProfileWithoutReplica(t *testing.T) {
+	sourceProfile := profiles.SourceProfile{
+		Driver: constants.MYSQL,
+		Ty:     profiles.SourceProfileTypeConnection,
+		Conn:   profiles.SourceProfileConnection{Ty: profiles.SourceProfileConnectionTypeMySQL, Mysql: profiles.SourceProfileConnectionMySQL{Host: "primary"}},
+	}
+	replicaProfile, useReplica, err := replicaSourceProfile(sourceProfile)
+	assert.Nil(t, err)
+	assert.False(t, useReplica)
+	assert.Equal(t, "primary", replicaProfile.Conn.Mysql.Host)
+
+	sourceProfile.Conn.Mysql.Replica = profiles.SourceProfileReplica{Host: "replica", Port: "3307"}
+	replicaProfile, useReplica, err = replicaSourceProfile(sourceProfile)
+	assert.Nil(t, err)
+	assert.True(t, useReplica)
+	assert.Equal(t, "replica", replicaProfile.Conn.Mysql.Host)
+	assert.Equal(t, "3307", replicaProfile.Conn.Mysql.Port)
+}
@@ -39,7 +39,9 @@ func (sm *SnapshotMigrationImpl) performSnapshotMigration(config writer.BatchWri
 	}
 	batchWriter := populateDataConv.populateDataConv(conv, config, client)
 	infoSchemaI.ProcessData(conv, infoSchema, additionalAttributes)
+	conv.FlushMaskedRows()
 	batchWriter.Flush()
+	batchWriter.RetryDeferredRows()
 	return batchWriter
 }
 
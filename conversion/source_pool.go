@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+// openSQLSource opens the connections to a MySQL or PostgreSQL source used to
+// read its schema and data, tuned by the connection pool params of the source
+// profile.
+func openSQLSource(driverName, dsn string, pool profiles.SourceProfilePool) (*sql.DB, error) {
+	var db *sql.DB
+	switch {
+	case driverName == constants.MYSQL && (pool.StatementTimeout > 0 || pool.Keepalive > 0):
+		cfg, err := mysqldriver.ParseDSN(dsn)
+		if err != nil {
+			return nil, err
+		}
+		if pool.StatementTimeout > 0 {
+			if cfg.Params == nil {
+				cfg.Params = make(map[string]string)
+			}
+			// MySQL only times out SELECT statements, which are the ones
+			// reading the source.
+			cfg.Params["max_execution_time"] = fmt.Sprint(pool.StatementTimeout.Milliseconds())
+		}
+		if pool.Keepalive > 0 && cfg.Net == "tcp" {
+			cfg.Net = registerMySQLKeepalive(pool.Keepalive)
+		}
+		connector, err := mysqldriver.NewConnector(cfg)
+		if err != nil {
+			return nil, err
+		}
+		db = sql.OpenDB(connector)
+	case driverName == constants.POSTGRES && (pool.StatementTimeout > 0 || pool.Keepalive > 0):
+		if pool.StatementTimeout > 0 {
+			dsn = fmt.Sprintf("%s statement_timeout=%d", dsn, pool.StatementTimeout.Milliseconds())
+		}
+		// Check the connection string up front, since connections are only
+		// opened when first used.
+		if _, err := pq.NewConnector(dsn); err != nil {
+			return nil, err
+		}
+		db = sql.OpenDB(pqConnector{dsn: dsn, dialer: keepaliveDialer{net.Dialer{KeepAlive: pool.Keepalive}}})
+	default:
+		var err error
+		db, err = sql.Open(driverName, dsn)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if pool.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(pool.MaxOpenConns)
+	}
+	if pool.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	return db, nil
+}
+
+// registerMySQLKeepalive registers a network dialing MySQL over TCP with the
+// given keepalive period, and returns its name.
+func registerMySQLKeepalive(keepalive time.Duration) string {
+	name := fmt.Sprintf("tcp-keepalive-%s", keepalive)
+	mysqldriver.RegisterDialContext(name, func(ctx context.Context, addr string) (net.Conn, error) {
+		d := net.Dialer{KeepAlive: keepalive}
+		return d.DialContext(ctx, "tcp", addr)
+	})
+	return name
+}
+
+// pqConnector opens PostgreSQL connections with a custom dialer.
+type pqConnector struct {
+	dsn    string
+	dialer pq.Dialer
+}
+
+func (c pqConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return pq.DialOpen(c.dialer, c.dsn)
+}
+
+func (c pqConnector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
+
+// keepaliveDialer is a pq.Dialer setting the keepalive period of the TCP
+// connections it dials.
+type keepaliveDialer struct {
+	d net.Dialer
+}
+
+func (k keepaliveDialer) Dial(network, address string) (net.Conn, error) {
+	return k.d.Dial(network, address)
+}
+
+func (k keepaliveDialer) DialTimeout(network, address string, timeout time.Duration) (net.Conn, error) {
+	d := k.d
+	d.Timeout = timeout
+	return d.Dial(network, address)
+}
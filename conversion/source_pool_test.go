@@ -0,0 +1,47 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conversion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenSQLSource(t *testing.T) {
+	pool := profiles.SourceProfilePool{MaxOpenConns: 8, MaxIdleConns: 2, StatementTimeout: time.Minute, Keepalive: 30 * time.Second}
+	for driver, dsn := range map[string]string{
+		constants.MYSQL:    "user:pwd@tcp(localhost:3306)/db",
+		constants.POSTGRES: "host=localhost port=5432 user=user password=pwd dbname=db sslmode=disable",
+	} {
+		// Connections are only opened when used.
+		db, err := openSQLSource(driver, dsn, pool)
+		assert.NoError(t, err, driver)
+		assert.Equal(t, 8, db.Stats().MaxOpenConnections, driver)
+		db.Close()
+
+		db, err = openSQLSource(driver, dsn, profiles.SourceProfilePool{})
+		assert.NoError(t, err, driver)
+		assert.Equal(t, 0, db.Stats().MaxOpenConnections, driver)
+		db.Close()
+	}
+	_, err := openSQLSource(constants.MYSQL, "user@tcp(localhost:3306", pool)
+	assert.Error(t, err)
+	_, err = openSQLSource(constants.POSTGRES, "host='localhost", pool)
+	assert.Error(t, err)
+}
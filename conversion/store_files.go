@@ -44,6 +44,8 @@ func WriteSchemaFile(conv *internal.Conv, now time.Time, name string, out *os.Fi
 	// intended for explanatory and documentation purposes, and is not strictly
 	// legal Cloud Spanner DDL (Cloud Spanner doesn't currently support comments).
 	spDDL := ddl.GetDDL(ddl.Config{Comments: true, ProtectIds: false, Tables: true, ForeignKeys: true, SpDialect: conv.SpDialect, Source: driver}, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions)
+	spDDL = append(spDDL, ddl.GetViewDDL(ddl.Config{SpDialect: conv.SpDialect}, conv.SpViews)...)
+	spDDL = append(spDDL, ddl.GetAccessControlDDL(ddl.Config{SpDialect: conv.SpDialect}, conv.SpSchema, conv.SpRoles, conv.SpGrants)...)
 	if len(spDDL) == 0 {
 		spDDL = []string{"\n-- Schema is empty -- no tables found\n"}
 	}
@@ -72,6 +74,8 @@ func WriteSchemaFile(conv *internal.Conv, now time.Time, name string, out *os.Fi
 	// We change 'Comments' to false and 'ProtectIds' to true below to write out a
 	// schema file that is a legal Cloud Spanner DDL.
 	spDDL = ddl.GetDDL(ddl.Config{Comments: false, ProtectIds: true, Tables: true, ForeignKeys: true, SpDialect: conv.SpDialect, Source: driver}, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions)
+	spDDL = append(spDDL, ddl.GetViewDDL(ddl.Config{ProtectIds: true, SpDialect: conv.SpDialect}, conv.SpViews)...)
+	spDDL = append(spDDL, ddl.GetAccessControlDDL(ddl.Config{ProtectIds: true, SpDialect: conv.SpDialect, Source: driver}, conv.SpSchema, conv.SpRoles, conv.SpGrants)...)
 	if len(spDDL) == 0 {
 		spDDL = []string{"\n-- Schema is empty -- no tables found\n"}
 	}
@@ -145,6 +149,28 @@ func ReadSessionFile(conv *internal.Conv, sessionJSON string) error {
 	return nil
 }
 
+// WriteCheckpoint writes the checkpoint of an interrupted data migration to
+// file 'name' and prints how to resume it. The checkpoint of a previous run
+// is removed when the migration wasn't interrupted.
+func WriteCheckpoint(conv *internal.Conv, name string, out *os.File) {
+	if !conv.Interrupted() {
+		os.Remove(name)
+		return
+	}
+	c := conv.Checkpoint()
+	fmt.Fprint(out, c.Summary())
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		fmt.Fprintf(out, "Can't encode checkpoint: %v\n", err)
+		return
+	}
+	if err := os.WriteFile(name, b, 0644); err != nil {
+		fmt.Fprintf(out, "Can't write checkpoint file %s: %v\n", name, err)
+		return
+	}
+	fmt.Fprintf(out, "Wrote checkpoint to file '%s'.\n", name)
+}
+
 // WriteBadData prints summary stats about bad rows and writes detailed info
 // to file 'name'.
 func WriteBadData(bw *writer.BatchWriter, conv *internal.Conv, banner, name string, out *os.File) {
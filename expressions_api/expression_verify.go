@@ -2,19 +2,24 @@ package expressions_api
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	mtrand "math/rand"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"cloud.google.com/go/spanner"
 	spannerclient "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients/spanner/client"
 	spanneraccessor "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/spanner"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/task"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"google.golang.org/grpc/codes"
 )
 
 const THREAD_POOL = 500
@@ -29,6 +34,29 @@ type ExpressionVerificationAccessor interface {
 type ExpressionVerificationAccessorImpl struct {
 	SpannerAccessor spanneraccessor.SpannerAccessor
 	tempDB          string
+	// Results of previous verifications, so that expressions which didn't
+	// change since the last verification of the same schema, e.g. between two
+	// edits in the UI, are not verified again.
+	cacheMu sync.Mutex
+	cache   map[expressionCacheKey]internal.ExpressionVerificationOutput
+}
+
+// expressionCacheKey identifies the verification of an expression against a
+// schema. Expressions are verified against the schema stripped of all
+// expressions (see removeExpressions), whose DDL is hashed in schemaHash.
+type expressionCacheKey struct {
+	schemaHash    string
+	exprType      string
+	expression    string
+	referenceName string
+	spTableName   string
+}
+
+// tableVerificationOutput is the result of the verification of the CREATE
+// TABLE statement of a table.
+type tableVerificationOutput struct {
+	tableId string
+	err     error
 }
 
 func NewExpressionVerificationAccessorImpl(ctx context.Context, project string, instance string) (*ExpressionVerificationAccessorImpl, error) {
@@ -76,44 +104,82 @@ func (ev *ExpressionVerificationAccessorImpl) VerifyExpressions(ctx context.Cont
 	if err != nil {
 		return internal.VerifyExpressionsOutput{Err: err}
 	}
-	dbURI := ev.SpannerAccessor.GetDatabaseName()
-	dbExists, err := ev.SpannerAccessor.CheckExistingDb(ctx, dbURI)
+	verifyExpressionsInput.Conv, err = ev.removeExpressions(verifyExpressionsInput.Conv)
 	if err != nil {
 		return internal.VerifyExpressionsOutput{Err: err}
 	}
-	if dbExists {
-		err := ev.SpannerAccessor.DropDatabase(ctx, dbURI)
+	schemaHash := hashSchema(verifyExpressionsInput.Conv, verifyExpressionsInput.Source)
+	// Expressions of all tables are verified in a single batch against one
+	// staging database. Identical expressions, e.g. the same default value
+	// on several columns, are verified once, and cached results are reused.
+	keys := make([]expressionCacheKey, len(verifyExpressionsInput.ExpressionDetailList))
+	results := make(map[expressionCacheKey]internal.ExpressionVerificationOutput)
+	var pending []internal.ExpressionDetail
+	pendingKeys := make(map[expressionCacheKey]bool)
+	for i, expressionDetail := range verifyExpressionsInput.ExpressionDetailList {
+		keys[i] = newExpressionCacheKey(schemaHash, expressionDetail)
+		if result, ok := ev.cachedResult(keys[i]); ok {
+			results[keys[i]] = result
+		} else if !pendingKeys[keys[i]] {
+			pendingKeys[keys[i]] = true
+			pending = append(pending, expressionDetail)
+		}
+	}
+	if len(pending) != 0 {
+		err = ev.verifyUncachedExpressions(ctx, verifyExpressionsInput, pending, schemaHash, results)
 		if err != nil {
 			return internal.VerifyExpressionsOutput{Err: err}
 		}
 	}
-	verifyExpressionsInput.Conv, err = ev.removeExpressions(verifyExpressionsInput.Conv)
+	var verifyExpressionsOutput internal.VerifyExpressionsOutput
+	var errorCount int16 = 0
+	for i, expressionDetail := range verifyExpressionsInput.ExpressionDetailList {
+		result := results[keys[i]]
+		result.ExpressionDetail = expressionDetail
+		verifyExpressionsOutput.ExpressionVerificationOutputList = append(verifyExpressionsOutput.ExpressionVerificationOutputList, result)
+		if result.Err != nil {
+			errorCount++
+		}
+	}
+	if errorCount != 0 {
+		verifyExpressionsOutput.Err = fmt.Errorf("%d expressions either failed verification or did not get verified. Please look at the individual errors returned for each expression", errorCount)
+
+	}
+	return verifyExpressionsOutput
+}
+
+// verifyUncachedExpressions verifies expressionDetails in parallel against a
+// staging database created from the stripped conv of verifyExpressionsInput,
+// and adds the results to results and to the cache.
+func (ev *ExpressionVerificationAccessorImpl) verifyUncachedExpressions(ctx context.Context, verifyExpressionsInput internal.VerifyExpressionsInput, expressionDetails []internal.ExpressionDetail, schemaHash string, results map[expressionCacheKey]internal.ExpressionVerificationOutput) error {
+	dbURI := ev.SpannerAccessor.GetDatabaseName()
+	dbExists, err := ev.SpannerAccessor.CheckExistingDb(ctx, dbURI)
 	if err != nil {
-		return internal.VerifyExpressionsOutput{Err: err}
+		return err
+	}
+	if dbExists {
+		err := ev.SpannerAccessor.DropDatabase(ctx, dbURI)
+		if err != nil {
+			return err
+		}
 	}
 	err = ev.SpannerAccessor.CreateDatabase(ctx, dbURI, verifyExpressionsInput.Conv, verifyExpressionsInput.Source, constants.DATAFLOW_MIGRATION)
 	if err != nil {
-		return internal.VerifyExpressionsOutput{Err: err}
+		return err
 	}
 	//Drop the staging database after verifications are completed.
 	defer ev.SpannerAccessor.DropDatabase(ctx, dbURI)
 	//This recreates a spanner client for the staging database before doing operations on it.
 	ev.SpannerAccessor.Refresh(ctx, dbURI)
 	r := task.RunParallelTasksImpl[internal.ExpressionDetail, internal.ExpressionVerificationOutput]{}
-	expressionVerificationOutputList, _ := r.RunParallelTasks(verifyExpressionsInput.ExpressionDetailList, THREAD_POOL, ev.verifyExpressionInternal, true)
-	var verifyExpressionsOutput internal.VerifyExpressionsOutput
-	var errorCount int16 = 0
+	expressionVerificationOutputList, _ := r.RunParallelTasks(expressionDetails, THREAD_POOL, ev.verifyExpressionInternal, true)
 	for _, expressionVerificationOutput := range expressionVerificationOutputList {
-		verifyExpressionsOutput.ExpressionVerificationOutputList = append(verifyExpressionsOutput.ExpressionVerificationOutputList, expressionVerificationOutput.Result)
-		if expressionVerificationOutput.Result.Err != nil {
-			errorCount++
-		}
-	}
-	if errorCount != 0 {
-		verifyExpressionsOutput.Err = fmt.Errorf("%d expressions either failed verification or did not get verified. Please look at the individual errors returned for each expression", errorCount)
-
+		result := expressionVerificationOutput.Result
+		key := newExpressionCacheKey(schemaHash, result.ExpressionDetail)
+		results[key] = result
+		ev.cacheResult(key, result)
 	}
-	return verifyExpressionsOutput
+	return nil
 }
 
 func (ev *ExpressionVerificationAccessorImpl) VerifyPrimaryKeysExpressionsUsingCreateTable(ctx context.Context, verifyExpressionsInput internal.VerifyExpressionsInput) internal.VerifyExpressionsOutput {
@@ -141,21 +207,46 @@ func (ev *ExpressionVerificationAccessorImpl) VerifyPrimaryKeysExpressionsUsingC
 	defer ev.SpannerAccessor.DropDatabase(ctx, dbURI)
 	//This recreates a spanner client for the staging database before doing operations on it.
 	ev.SpannerAccessor.Refresh(ctx, dbURI)
-	var verifyExpressionsOutput internal.VerifyExpressionsOutput
-
-	// For each table id, create a table using VerifyCreateTableDDL.
+	// Create the tables of the expressions in parallel, each table once even
+	// if it has several expressions.
+	var tableIds []string
+	expressionsByTable := make(map[string][]internal.ExpressionDetail)
 	for _, expressionDetails := range verifyExpressionsInput.ExpressionDetailList {
 		tableId := expressionDetails.Metadata["TableId"]
+		if _, ok := expressionsByTable[tableId]; !ok {
+			tableIds = append(tableIds, tableId)
+		}
+		expressionsByTable[tableId] = append(expressionsByTable[tableId], expressionDetails)
+	}
+	createTable := func(tableId string, mutex *sync.Mutex) task.TaskResult[tableVerificationOutput] {
 		err := ev.SpannerAccessor.VerifyCreateTableDDL(ctx, dbURI, verifyExpressionsInput.Conv, tableId, verifyExpressionsInput.Source)
-		if err != nil {
-			verifyExpressionsOutput.ExpressionVerificationOutputList = append(verifyExpressionsOutput.ExpressionVerificationOutputList, internal.ExpressionVerificationOutput{Err: err, ExpressionDetail: expressionDetails})
+		return task.TaskResult[tableVerificationOutput]{Result: tableVerificationOutput{tableId: tableId, err: err}}
+	}
+	r := task.RunParallelTasksImpl[string, tableVerificationOutput]{}
+	tableVerificationOutputList, _ := r.RunParallelTasks(tableIds, THREAD_POOL, createTable, false)
+	tableErrors := make(map[string]error)
+	for _, tableVerification := range tableVerificationOutputList {
+		tableErrors[tableVerification.Result.tableId] = tableVerification.Result.err
+	}
+	var verifyExpressionsOutput internal.VerifyExpressionsOutput
+	for _, tableId := range tableIds {
+		if err := tableErrors[tableId]; err != nil {
+			for _, expressionDetails := range expressionsByTable[tableId] {
+				verifyExpressionsOutput.ExpressionVerificationOutputList = append(verifyExpressionsOutput.ExpressionVerificationOutputList, internal.ExpressionVerificationOutput{Err: err, ExpressionDetail: expressionDetails})
+			}
 		}
 	}
 	return verifyExpressionsOutput
 }
 
 func (ev *ExpressionVerificationAccessorImpl) RefreshSpannerClient(ctx context.Context, project string, instance string) error {
-	spannerClient, err := spannerclient.NewSpannerClientImpl(ctx, fmt.Sprintf(constants.DB_URI, project, instance, ev.tempDB))
+	dbURI := fmt.Sprintf(constants.DB_URI, project, instance, ev.tempDB)
+	// Reuse the client of the staging database instead of creating one per
+	// verification.
+	if spannerClient := ev.SpannerAccessor.GetSpannerClient(); spannerClient != nil && spannerClient.DatabaseName() == dbURI {
+		return nil
+	}
+	spannerClient, err := spannerclient.NewSpannerClientImpl(ctx, dbURI)
 	if err != nil {
 		return err
 	}
@@ -172,6 +263,8 @@ func (ev *ExpressionVerificationAccessorImpl) verifyExpressionInternal(expressio
 		sqlStatement = fmt.Sprintf("SELECT CAST(%s as %s)", expressionDetail.Expression, expressionDetail.ReferenceElement.Name)
 	case constants.STORED_GENERATED, constants.VIRTUAL_GENERATED:
 		sqlStatement = fmt.Sprintf("SELECT %s as %s FROM %s", expressionDetail.Expression, expressionDetail.ReferenceElement.Name, expressionDetail.SpTableName)
+	case constants.VIEW_EXPRESSION:
+		sqlStatement = fmt.Sprintf("SELECT * FROM (%s) AS v LIMIT 0", strings.TrimSuffix(strings.TrimSpace(expressionDetail.Expression), ";"))
 	default:
 		return task.TaskResult[internal.ExpressionVerificationOutput]{Result: internal.ExpressionVerificationOutput{Result: false, Err: fmt.Errorf("invalid expression type requested"), ExpressionDetail: expressionDetail}, Err: nil}
 	}
@@ -179,6 +272,50 @@ func (ev *ExpressionVerificationAccessorImpl) verifyExpressionInternal(expressio
 	return task.TaskResult[internal.ExpressionVerificationOutput]{Result: internal.ExpressionVerificationOutput{Result: result, Err: err, ExpressionDetail: expressionDetail}, Err: nil}
 }
 
+func newExpressionCacheKey(schemaHash string, expressionDetail internal.ExpressionDetail) expressionCacheKey {
+	return expressionCacheKey{
+		schemaHash:    schemaHash,
+		exprType:      expressionDetail.Type,
+		expression:    expressionDetail.Expression,
+		referenceName: expressionDetail.ReferenceElement.Name,
+		spTableName:   expressionDetail.SpTableName,
+	}
+}
+
+// hashSchema returns a hash of the Spanner DDL of conv, against which
+// expressions are verified.
+func hashSchema(conv *internal.Conv, source string) string {
+	c := ddl.Config{ProtectIds: true, SpDialect: conv.SpDialect, Source: source}
+	h := sha256.Sum256([]byte(strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions), ";\n")))
+	return hex.EncodeToString(h[:])
+}
+
+func (ev *ExpressionVerificationAccessorImpl) cachedResult(key expressionCacheKey) (internal.ExpressionVerificationOutput, bool) {
+	ev.cacheMu.Lock()
+	defer ev.cacheMu.Unlock()
+	result, ok := ev.cache[key]
+	return result, ok
+}
+
+// cacheResult caches the result of a verification, unless it failed for a
+// reason other than the expression itself, e.g. a timeout, in which case it
+// must be verified again.
+func (ev *ExpressionVerificationAccessorImpl) cacheResult(key expressionCacheKey, result internal.ExpressionVerificationOutput) {
+	if result.Err != nil {
+		switch spanner.ErrCode(result.Err) {
+		case codes.InvalidArgument, codes.NotFound, codes.FailedPrecondition, codes.OutOfRange:
+		default:
+			return
+		}
+	}
+	ev.cacheMu.Lock()
+	defer ev.cacheMu.Unlock()
+	if ev.cache == nil {
+		ev.cache = make(map[expressionCacheKey]internal.ExpressionVerificationOutput)
+	}
+	ev.cache[key] = result
+}
+
 func (ev *ExpressionVerificationAccessorImpl) validateRequest(verifyExpressionsInput internal.VerifyExpressionsInput) error {
 	if verifyExpressionsInput.Conv == nil || verifyExpressionsInput.Source == "" {
 		return fmt.Errorf("one of conv or source is empty. These are mandatory fields = %v", verifyExpressionsInput)
@@ -207,6 +344,9 @@ func (ev *ExpressionVerificationAccessorImpl) removeExpressions(inputConv *inter
 	//Set sequences as nil
 	//TODO: Implement similar checks for DEFAULT and CHECK constraints as well
 	convCopy.SpSequences = nil
+	// Views are verified separately, and an invalid view must not prevent the
+	// creation of the staging database.
+	convCopy.SpViews = nil
 	for _, table := range convCopy.SpSchema {
 		table.CheckConstraints = []ddl.CheckConstraint{}
 		convCopy.SpSchema[table.Id] = table
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"os"
@@ -23,6 +24,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func init() {
@@ -293,6 +296,86 @@ func TestVerifyExpressions(t *testing.T) {
 
 }
 
+func TestVerifyExpressions_Cache(t *testing.T) {
+	ctx := context.Background()
+	conv := internal.MakeConv()
+	ReadSessionFile(conv, "../../test_data/session_expression_verify.json")
+	var queries, createdDbs int32
+	spannerMockClient := spannerclient.SpannerClientMock{
+		RefreshMock: func(ctx context.Context, dbURI string) error {
+			return nil
+		},
+		DatabaseNameMock: func() string {
+			return "projects/spanner-cloud-test/instances/foo/databases/foodb"
+		},
+		SingleMock: func() spannerclient.ReadOnlyTransaction {
+			return &spannerclient.ReadOnlyTransactionMock{
+				QueryMock: func(ctx context.Context, stmt spanner.Statement) spannerclient.RowIterator {
+					atomic.AddInt32(&queries, 1)
+					return &spannerclient.RowIteratorMock{
+						NextMock: func() (*spanner.Row, error) {
+							if strings.Contains(stmt.SQL, "timeout") {
+								return nil, status.Error(codes.DeadlineExceeded, "deadline exceeded")
+							}
+							return nil, iterator.Done
+						},
+						StopMock: func() {},
+					}
+				},
+			}
+		},
+	}
+	spannerAdminMockClient := &spanneradmin.AdminClientMock{
+		GetDatabaseMock: func(ctx context.Context, req *databasepb.GetDatabaseRequest, opts ...gax.CallOption) (*databasepb.Database, error) {
+			return nil, fmt.Errorf("database not found")
+		},
+		CreateDatabaseMock: func(ctx context.Context, req *databasepb.CreateDatabaseRequest, opts ...gax.CallOption) (spanneradmin.CreateDatabaseOperation, error) {
+			atomic.AddInt32(&createdDbs, 1)
+			return &spanneradmin.CreateDatabaseOperationMock{
+				WaitMock: func(ctx context.Context, opts ...gax.CallOption) (*databasepb.Database, error) { return nil, nil },
+			}, nil
+		},
+		DropDatabaseMock: func(ctx context.Context, req *databasepb.DropDatabaseRequest, opts ...gax.CallOption) error {
+			return nil
+		},
+	}
+	ev := &expressions_api.ExpressionVerificationAccessorImpl{SpannerAccessor: &spanneraccessor.SpannerAccessorImpl{SpannerClient: spannerMockClient, AdminClient: spannerAdminMockClient}}
+	input := internal.VerifyExpressionsInput{
+		Conv:   conv,
+		Source: "mysql",
+		ExpressionDetailList: []internal.ExpressionDetail{
+			{Expression: "0", Type: "DEFAULT", ReferenceElement: internal.ReferenceElement{Name: "INT64"}, ExpressionId: "1"},
+			{Expression: "0", Type: "DEFAULT", ReferenceElement: internal.ReferenceElement{Name: "INT64"}, ExpressionId: "2"},
+			{Expression: "timeout", Type: "DEFAULT", ReferenceElement: internal.ReferenceElement{Name: "INT64"}, ExpressionId: "3"},
+		},
+	}
+
+	// Identical expressions are verified once.
+	output := ev.VerifyExpressions(ctx, input)
+	assert.NotNil(t, output.Err)
+	assert.Equal(t, int32(2), queries)
+	assert.Equal(t, int32(1), createdDbs)
+	assert.Equal(t, 3, len(output.ExpressionVerificationOutputList))
+	for i, id := range []string{"1", "2", "3"} {
+		assert.Equal(t, id, output.ExpressionVerificationOutputList[i].ExpressionDetail.ExpressionId)
+	}
+	assert.True(t, output.ExpressionVerificationOutputList[1].Result)
+	assert.False(t, output.ExpressionVerificationOutputList[2].Result)
+
+	// Successful results are cached, while transient errors are verified
+	// again.
+	output = ev.VerifyExpressions(ctx, input)
+	assert.NotNil(t, output.Err)
+	assert.Equal(t, int32(3), queries)
+	assert.Equal(t, int32(2), createdDbs)
+
+	input.ExpressionDetailList = input.ExpressionDetailList[:2]
+	output = ev.VerifyExpressions(ctx, input)
+	assert.Nil(t, output.Err)
+	assert.Equal(t, int32(3), queries)
+	assert.Equal(t, int32(2), createdDbs)
+}
+
 func TestVerifyPrimaryKeysExpressionsUsingCreateTable(t *testing.T) {
 	ctx := context.Background()
 	conv := internal.MakeConv()
@@ -0,0 +1,31 @@
+package file_reader
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// BytesFileReaderImpl reads a file that is held in memory, such as a schema
+// generated on the fly instead of being read from a URI.
+type BytesFileReaderImpl struct {
+	data []byte
+}
+
+func NewBytesFileReader(data []byte) *BytesFileReaderImpl {
+	return &BytesFileReaderImpl{data: data}
+}
+
+func (reader *BytesFileReaderImpl) ResetReader(ctx context.Context) (io.Reader, error) {
+	return reader.CreateReader(ctx)
+}
+
+func (reader *BytesFileReaderImpl) CreateReader(_ context.Context) (io.Reader, error) {
+	return bytes.NewReader(reader.data), nil
+}
+
+func (reader *BytesFileReaderImpl) ReadAll(_ context.Context) ([]byte, error) {
+	return reader.data, nil
+}
+
+func (reader *BytesFileReaderImpl) Close() {}
@@ -0,0 +1,32 @@
+package file_reader
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytesFileReaderImpl(t *testing.T) {
+	ctx := context.Background()
+	reader := NewBytesFileReader([]byte("a,b\n1,2\n"))
+
+	r, err := reader.CreateReader(ctx)
+	assert.Nil(t, err)
+	b, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, "a,b\n1,2\n", string(b))
+
+	// Every reader starts at the beginning of the data.
+	r, err = reader.ResetReader(ctx)
+	assert.Nil(t, err)
+	b, err = io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, "a,b\n1,2\n", string(b))
+
+	b, err = reader.ReadAll(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "a,b\n1,2\n", string(b))
+	reader.Close()
+}
@@ -73,6 +73,7 @@ func (source *CsvDataImpl) ImportData(ctx context.Context, spannerInfoSchema *sp
 		return err
 	}
 	batchWriter.Flush()
+	batchWriter.RetryDeferredRows()
 	return err
 }
 
@@ -0,0 +1,120 @@
+package import_file
+
+import (
+	csvReader "encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Types that a column of a csv can be inferred as, from the most to the least
+// specific one. STRING(MAX) accepts any value.
+var inferableTypes = []struct {
+	name  string
+	parse func(s string) bool
+}{
+	{"INT64", func(s string) bool { _, err := strconv.ParseInt(s, 10, 64); return err == nil }},
+	{"FLOAT64", func(s string) bool { _, err := strconv.ParseFloat(s, 64); return err == nil }},
+	{"BOOL", func(s string) bool { _, err := strconv.ParseBool(s); return err == nil }},
+	// Same layout as the csv data conversion of TIMESTAMP values.
+	{"TIMESTAMP", func(s string) bool { _, err := time.Parse("2006-01-02 15:04:05", s); return err == nil }},
+}
+
+// inferredColumn tracks what the sampled values of a csv column allow it to be.
+type inferredColumn struct {
+	// candidates[i] is false once a value didn't parse as inferableTypes[i].
+	candidates []bool
+	hasEmpty   bool
+	// values seen so far, to detect whether the column could be a primary key.
+	// It is dropped once a value repeats.
+	values map[string]bool
+}
+
+// InferCsvSchema infers the schema of a csv from its header row and up to
+// sampleRows of the rows that follow. Each column gets the most specific of
+// INT64, FLOAT64, BOOL and TIMESTAMP that all of its non-empty sampled values
+// parse as, and STRING(MAX) otherwise. The first non-FLOAT64 column whose
+// sampled values are all present and unique becomes the primary key.
+func InferCsvSchema(r io.Reader, delimiter rune, sampleRows int) ([]ColumnDefinition, error) {
+	cr := csvReader.NewReader(r)
+	cr.Comma = delimiter
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("can't infer schema of an empty csv, the first row must be a header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read csv header row: %v", err)
+	}
+	cols := make([]inferredColumn, len(header))
+	for i := range cols {
+		cols[i].candidates = make([]bool, len(inferableTypes))
+		for j := range cols[i].candidates {
+			cols[i].candidates[j] = true
+		}
+		cols[i].values = map[string]bool{}
+	}
+
+	rows := 0
+	for ; rows < sampleRows; rows++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't read row for file due to: %v", err)
+		}
+		for i, v := range record {
+			col := &cols[i]
+			if v == "" {
+				col.hasEmpty = true
+				continue
+			}
+			for j, t := range inferableTypes {
+				if col.candidates[j] && !t.parse(v) {
+					col.candidates[j] = false
+				}
+			}
+			if col.values != nil {
+				if col.values[v] {
+					col.values = nil
+				} else {
+					col.values[v] = true
+				}
+			}
+		}
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("can't infer schema of a csv without data rows")
+	}
+
+	var colDefs []ColumnDefinition
+	pkFound := false
+	for i, name := range header {
+		colDef := ColumnDefinition{Name: name, Type: "STRING(MAX)"}
+		for j, t := range inferableTypes {
+			if cols[i].candidates[j] {
+				colDef.Type = t.name
+				break
+			}
+		}
+		// Floats make poor keys, since equal values may not compare equal once rounded.
+		if !pkFound && !cols[i].hasEmpty && cols[i].values != nil && colDef.Type != "FLOAT64" {
+			colDef.PkOrder = 1
+			colDef.NotNull = true
+			pkFound = true
+		}
+		colDefs = append(colDefs, colDef)
+	}
+	if !pkFound {
+		return nil, fmt.Errorf("no column of the sampled rows has unique, non-empty values to use as primary key, please specify the schema using the --schema-uri parameter")
+	}
+	return colDefs, nil
+}
+
+// MarshalCsvSchema returns colDefs in the format of the schema file of a csv import.
+func MarshalCsvSchema(colDefs []ColumnDefinition) ([]byte, error) {
+	return json.MarshalIndent(colDefs, "", "  ")
+}
@@ -0,0 +1,105 @@
+package import_file
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferCsvSchema(t *testing.T) {
+	tests := []struct {
+		name          string
+		csv           string
+		delimiter     rune
+		sampleRows    int
+		want          []ColumnDefinition
+		expectedError string
+	}{
+		{
+			name: "all inferable types",
+			csv: "id,price,active,created,name\n" +
+				"1,1.5,true,2024-01-02 03:04:05,a\n" +
+				"2,2,false,2024-01-03 03:04:05,b\n",
+			delimiter:  ',',
+			sampleRows: 100,
+			want: []ColumnDefinition{
+				{Name: "id", Type: "INT64", NotNull: true, PkOrder: 1},
+				{Name: "price", Type: "FLOAT64"},
+				{Name: "active", Type: "BOOL"},
+				{Name: "created", Type: "TIMESTAMP"},
+				{Name: "name", Type: "STRING(MAX)"},
+			},
+		},
+		{
+			name:       "empty values don't constrain the type but rule out the primary key",
+			csv:        "a|b|c\n|x|1\n2|x|2\n",
+			delimiter:  '|',
+			sampleRows: 100,
+			want: []ColumnDefinition{
+				{Name: "a", Type: "INT64"},
+				{Name: "b", Type: "STRING(MAX)"},
+				{Name: "c", Type: "INT64", NotNull: true, PkOrder: 1},
+			},
+		},
+		{
+			name:       "only sampled rows are inferred from",
+			csv:        "a,b\n1,x\n2,y\nz,y\n",
+			delimiter:  ',',
+			sampleRows: 2,
+			want: []ColumnDefinition{
+				{Name: "a", Type: "INT64", NotNull: true, PkOrder: 1},
+				{Name: "b", Type: "STRING(MAX)"},
+			},
+		},
+		{
+			name:          "no primary key",
+			csv:           "a,b\n1.5,x\n1.5,x\n",
+			delimiter:     ',',
+			sampleRows:    100,
+			expectedError: "no column of the sampled rows has unique, non-empty values",
+		},
+		{
+			name:          "empty csv",
+			csv:           "",
+			delimiter:     ',',
+			sampleRows:    100,
+			expectedError: "can't infer schema of an empty csv",
+		},
+		{
+			name:          "header only",
+			csv:           "a,b\n",
+			delimiter:     ',',
+			sampleRows:    100,
+			expectedError: "can't infer schema of a csv without data rows",
+		},
+		{
+			name:          "malformed row",
+			csv:           "a,b\n1\n",
+			delimiter:     ',',
+			sampleRows:    100,
+			expectedError: "can't read row for file",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := InferCsvSchema(strings.NewReader(tc.csv), tc.delimiter, tc.sampleRows)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestMarshalCsvSchema(t *testing.T) {
+	colDefs := []ColumnDefinition{{Name: "id", Type: "INT64", NotNull: true, PkOrder: 1}}
+	b, err := MarshalCsvSchema(colDefs)
+	assert.Nil(t, err)
+	// The inferred schema can be passed back with --schema-uri.
+	parsed, err := parseSchema(b)
+	assert.Nil(t, err)
+	assert.Equal(t, colDefs, parsed)
+}
@@ -94,6 +94,12 @@ func (source *ImportFromDumpImpl) CreateSchema(ctx context.Context, dialect stri
 		return nil, fmt.Errorf("failed to convert schema to spanner DDL: %v", err)
 	}
 
+	if err := conv.ValidateNames(); err != nil {
+		return nil, err
+	}
+	if err := conv.ValidateLimits(); err != nil {
+		return nil, err
+	}
 	err = source.SpannerAccessor.UpdateDatabase(ctx, source.dbUri, conv, source.SourceFormat)
 	if err != nil {
 		return nil, fmt.Errorf("can't update database: %v", err)
@@ -117,6 +123,7 @@ func (source *ImportFromDumpImpl) ImportData(ctx context.Context, conv *internal
 		return err
 	}
 	batchWriter.Flush()
+	batchWriter.RetryDeferredRows()
 
 	return nil
 }
@@ -0,0 +1,193 @@
+package import_file
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/file_reader"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/csv"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/writer"
+)
+
+var NewJsonlData = newJsonlData
+
+type JsonlData interface {
+	ImportData(ctx context.Context, spannerInfoSchema *spanner.InfoSchemaImpl, dialect string, conv *internal.Conv, commonInfoSchema common.InfoSchemaInterface) error
+}
+
+type JsonlDataImpl struct {
+	ProjectId        string
+	InstanceId       string
+	DbName           string
+	TableName        string
+	SourceUri        string
+	SourceFileReader file_reader.FileReader
+}
+
+func newJsonlData(projectId, instanceId, dbName, tableName, sourceUri string, sourceFileReader file_reader.FileReader) JsonlData {
+	return &JsonlDataImpl{
+		ProjectId:        projectId,
+		InstanceId:       instanceId,
+		DbName:           dbName,
+		TableName:        tableName,
+		SourceUri:        sourceUri,
+		SourceFileReader: sourceFileReader,
+	}
+}
+
+// jsonlRecord is a JSON Lines record, as the top-level fields of the object in
+// the order they appear.
+type jsonlRecord struct {
+	fields []string
+	values map[string]json.RawMessage
+}
+
+func (source *JsonlDataImpl) ImportData(ctx context.Context, spannerInfoSchema *spanner.InfoSchemaImpl, dialect string, conv *internal.Conv, commonInfoSchema common.InfoSchemaInterface) error {
+	sourceIoReader, err := source.SourceFileReader.CreateReader(ctx)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Unable to read source file %v", err))
+		return err
+	}
+
+	conv = getConvObject(source.ProjectId, source.InstanceId, dialect, conv)
+	batchWriter := writer.GetBatchWriterWithConfig(ctx, spannerInfoSchema.SpannerClient, conv)
+
+	err = spannerInfoSchema.PopulateSpannerSchema(ctx, conv, commonInfoSchema)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Unable to read Spanner schema %v", err))
+		return err
+	}
+
+	tableId, err := internal.GetTableIdFromSpName(conv.SpSchema, source.TableName)
+	if err != nil {
+		logger.Log.Error(fmt.Sprintf("Table %s not found in Spanner", source.TableName))
+		return err
+	}
+
+	err = processJsonl(conv, source.TableName, conv.SpSchema[tableId].ColDefs, sourceIoReader)
+	if err != nil {
+		return err
+	}
+	batchWriter.Flush()
+	batchWriter.RetryDeferredRows()
+	return err
+}
+
+// processJsonl writes every record of r to tableName. Top-level fields are
+// mapped to the columns of the same name, with values of JSON columns and
+// nested objects and arrays written as their JSON text. Fields without a
+// column are skipped.
+func processJsonl(conv *internal.Conv, tableName string, colDefs map[string]ddl.ColumnDef, r io.Reader) error {
+	skipped := map[string]bool{}
+	return readJsonl(r, -1, func(record jsonlRecord) {
+		var cols, vals []string
+		for _, f := range record.fields {
+			colId, err := internal.GetColIdFromSpName(colDefs, f)
+			if err != nil {
+				if !skipped[f] {
+					logger.Log.Warn(fmt.Sprintf("Skipping field %s, table %s has no column of that name", f, tableName))
+					skipped[f] = true
+				}
+				continue
+			}
+			v, null := jsonlValueText(record.values[f])
+			if null {
+				continue
+			}
+			if colDefs[colId].T.Name == ddl.JSON {
+				// Scalars stay JSON values, e.g. strings keep their quotes.
+				var b bytes.Buffer
+				if err := json.Compact(&b, record.values[f]); err == nil {
+					v = b.String()
+				}
+			}
+			cols = append(cols, f)
+			vals = append(vals, v)
+		}
+		csv.ProcessDataRow(conv, "", tableName, cols, colDefs, vals)
+	})
+}
+
+// jsonlValueText returns a JSON value in the text form that it is converted
+// from, and true if the value is null.
+func jsonlValueText(raw json.RawMessage) (string, bool) {
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case bytes.Equal(raw, []byte("null")):
+		return "", true
+	case len(raw) > 0 && raw[0] == '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			return s, false
+		}
+	case len(raw) > 0 && (raw[0] == '{' || raw[0] == '['):
+		var b bytes.Buffer
+		if err := json.Compact(&b, raw); err == nil {
+			return b.String(), false
+		}
+	}
+	return string(raw), false
+}
+
+// readJsonl calls process for each of the first maxRecords records of r, or
+// for all of them if maxRecords is negative. Blank lines are ignored.
+func readJsonl(r io.Reader, maxRecords int, process func(record jsonlRecord)) error {
+	br := bufio.NewReader(r)
+	for line, n := 1, 0; maxRecords < 0 || n < maxRecords; line++ {
+		b, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("can't read line %d of file due to: %v", line, err)
+		}
+		if len(bytes.TrimSpace(b)) > 0 {
+			record, perr := parseJsonlRecord(b)
+			if perr != nil {
+				return fmt.Errorf("can't parse line %d of file due to: %v", line, perr)
+			}
+			process(record)
+			n++
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	return nil
+}
+
+func parseJsonlRecord(b []byte) (jsonlRecord, error) {
+	record := jsonlRecord{values: map[string]json.RawMessage{}}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return record, fmt.Errorf("record is not a JSON object")
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return record, err
+		}
+		field := t.(string)
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return record, err
+		}
+		if _, ok := record.values[field]; !ok {
+			record.fields = append(record.fields, field)
+		}
+		record.values[field] = v
+	}
+	if _, err := dec.Token(); err != nil {
+		return record, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return record, fmt.Errorf("unexpected data after JSON object")
+	}
+	return record, nil
+}
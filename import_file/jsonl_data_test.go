@@ -0,0 +1,56 @@
+package import_file
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/file_reader"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonlDataImpl_ImportData(t *testing.T) {
+	source := JsonlDataImpl{
+		ProjectId:        "test-project",
+		InstanceId:       "test-instance",
+		DbName:           "test-db",
+		TableName:        "nonexistent-table",
+		SourceFileReader: file_reader.NewBytesFileReader([]byte("{\"col1\": 1}\n")),
+	}
+	spannerInfoSchema := &spanner.InfoSchemaImpl{SpannerClient: getSpannerClientMock(getDefaultRowIteratoMock())}
+	err := source.ImportData(context.Background(), spannerInfoSchema, constants.DIALECT_GOOGLESQL, internal.MakeConv(), getCommonInfoSchemaMock(0))
+	assert.Error(t, err)
+}
+
+func TestProcessJsonl(t *testing.T) {
+	colDefs := map[string]ddl.ColumnDef{
+		"c1": {Name: "id", T: ddl.Type{Name: ddl.Int64}},
+		"c2": {Name: "name", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+		"c3": {Name: "address", T: ddl.Type{Name: ddl.JSON}},
+		"c4": {Name: "tags", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}},
+	}
+	conv := internal.MakeConv()
+	var rows [][]interface{}
+	var cols [][]string
+	conv.SetDataMode()
+	conv.SetDataSink(
+		func(table string, c []string, vals []interface{}) {
+			cols = append(cols, c)
+			rows = append(rows, vals)
+		})
+	jsonl := `{"id": 1, "name": "a", "address": {"city": "c"}, "tags": ["x", "y"], "unknown": 1}
+{"id": 2, "name": null, "address": "somewhere"}
+`
+	err := processJsonl(conv, "t", colDefs, strings.NewReader(jsonl))
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"id", "name", "address", "tags"}, {"id", "address"}}, cols)
+	assert.Equal(t, []interface{}{int64(1), "a", `{"city":"c"}`}, rows[0][:3])
+	assert.Equal(t, []interface{}{int64(2), `"somewhere"`}, rows[1])
+
+	err = processJsonl(conv, "t", colDefs, strings.NewReader("{\"id\": 3}\nnot json\n"))
+	assert.ErrorContains(t, err, "can't parse line 2 of file")
+}
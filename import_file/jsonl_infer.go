@@ -0,0 +1,131 @@
+package import_file
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// inferredField tracks the kinds of the sampled values of a JSON Lines field.
+type inferredField struct {
+	ints, floats, bools, timestamps, strings, nested int
+	// present is the number of sampled records in which the field is not null.
+	present int
+	// values seen so far, to detect whether the field could be a primary key.
+	// It is dropped once a value repeats.
+	values map[string]bool
+}
+
+// InferJsonlSchema infers the schema of a JSON Lines file from up to
+// sampleRows of its records. Each top-level field becomes a column, in the
+// order fields first appear: INT64, FLOAT64 or BOOL for numbers and booleans,
+// TIMESTAMP for strings that are all timestamps, JSON for nested objects and
+// arrays, and STRING(MAX) for other strings and fields of mixed kinds. The
+// first field of INT64, STRING(MAX) or TIMESTAMP type that is present and
+// unique in all sampled records becomes the primary key.
+func InferJsonlSchema(r io.Reader, sampleRows int) ([]ColumnDefinition, error) {
+	var order []string
+	fields := map[string]*inferredField{}
+	rows := 0
+	err := readJsonl(r, sampleRows, func(record jsonlRecord) {
+		rows++
+		for _, name := range record.fields {
+			f, ok := fields[name]
+			if !ok {
+				f = &inferredField{values: map[string]bool{}}
+				fields[name] = f
+				order = append(order, name)
+			}
+			f.add(record.values[name])
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("can't infer schema of a file without records")
+	}
+
+	var colDefs []ColumnDefinition
+	pkFound := false
+	for _, name := range order {
+		f := fields[name]
+		colDef := ColumnDefinition{Name: name, Type: f.spannerType()}
+		keyType := colDef.Type == "INT64" || colDef.Type == "STRING(MAX)" || colDef.Type == "TIMESTAMP"
+		if !pkFound && keyType && f.present == rows && f.values != nil {
+			colDef.PkOrder = 1
+			colDef.NotNull = true
+			pkFound = true
+		}
+		colDefs = append(colDefs, colDef)
+	}
+	if !pkFound {
+		return nil, fmt.Errorf("no field of the sampled records has unique, non-null values to use as primary key, please specify the schema using the --schema-uri parameter")
+	}
+	return colDefs, nil
+}
+
+func (f *inferredField) add(raw json.RawMessage) {
+	v, null := jsonlValueText(raw)
+	if null {
+		return
+	}
+	f.present++
+	raw = bytes.TrimSpace(raw)
+	switch raw[0] {
+	case '{', '[':
+		f.nested++
+	case '"':
+		if isTimestamp(v) {
+			f.timestamps++
+		} else {
+			f.strings++
+		}
+	case 't', 'f':
+		f.bools++
+	default:
+		if bytes.ContainsAny(raw, ".eE") {
+			f.floats++
+		} else {
+			f.ints++
+		}
+	}
+	if f.values != nil {
+		if f.values[v] {
+			f.values = nil
+		} else {
+			f.values[v] = true
+		}
+	}
+}
+
+func (f *inferredField) spannerType() string {
+	switch {
+	case f.nested > 0:
+		// Any JSON value can be stored in a JSON column.
+		return "JSON"
+	case f.present == 0:
+		return "STRING(MAX)"
+	case f.ints == f.present:
+		return "INT64"
+	case f.ints+f.floats == f.present:
+		return "FLOAT64"
+	case f.bools == f.present:
+		return "BOOL"
+	case f.timestamps == f.present:
+		return "TIMESTAMP"
+	default:
+		return "STRING(MAX)"
+	}
+}
+
+// isTimestamp reports whether s is a timestamp that the data import converts.
+func isTimestamp(s string) bool {
+	if _, err := time.Parse("2006-01-02 15:04:05", s); err == nil {
+		return true
+	}
+	_, err := time.Parse(time.RFC3339Nano, s)
+	return err == nil
+}
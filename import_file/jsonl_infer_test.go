@@ -0,0 +1,102 @@
+package import_file
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferJsonlSchema(t *testing.T) {
+	tests := []struct {
+		name          string
+		jsonl         string
+		sampleRows    int
+		want          []ColumnDefinition
+		expectedError string
+	}{
+		{
+			name: "all inferable types",
+			jsonl: `{"id": 1, "price": 1.5, "active": true, "created": "2024-01-02T03:04:05Z", "name": "a", "tags": ["x"], "address": {"city": "c"}}
+{"id": 2, "price": 2, "active": false, "created": "2024-01-03 03:04:05", "name": "b", "tags": [], "address": null, "extra": 3}
+`,
+			sampleRows: 100,
+			want: []ColumnDefinition{
+				{Name: "id", Type: "INT64", NotNull: true, PkOrder: 1},
+				{Name: "price", Type: "FLOAT64"},
+				{Name: "active", Type: "BOOL"},
+				{Name: "created", Type: "TIMESTAMP"},
+				{Name: "name", Type: "STRING(MAX)"},
+				{Name: "tags", Type: "JSON"},
+				{Name: "address", Type: "JSON"},
+				{Name: "extra", Type: "INT64"},
+			},
+		},
+		{
+			name:       "fields missing from a record or of mixed kinds",
+			jsonl:      "{\"a\": 1, \"b\": \"x\"}\n\n{\"b\": \"y\", \"c\": 1}\n{\"a\": \"s\", \"b\": \"z\", \"c\": true}",
+			sampleRows: 100,
+			want: []ColumnDefinition{
+				{Name: "a", Type: "STRING(MAX)"},
+				{Name: "b", Type: "STRING(MAX)", NotNull: true, PkOrder: 1},
+				{Name: "c", Type: "STRING(MAX)"},
+			},
+		},
+		{
+			name:       "only sampled records are inferred from",
+			jsonl:      "{\"a\": 1}\n{\"a\": 2}\n{\"a\": \"x\"}\n",
+			sampleRows: 2,
+			want: []ColumnDefinition{
+				{Name: "a", Type: "INT64", NotNull: true, PkOrder: 1},
+			},
+		},
+		{
+			name:          "no primary key",
+			jsonl:         "{\"a\": 1.5, \"b\": true}\n{\"a\": 2.5, \"b\": false}\n",
+			sampleRows:    100,
+			expectedError: "no field of the sampled records has unique, non-null values",
+		},
+		{
+			name:          "empty file",
+			jsonl:         "\n",
+			sampleRows:    100,
+			expectedError: "can't infer schema of a file without records",
+		},
+		{
+			name:          "malformed record",
+			jsonl:         "{\"a\": 1}\n[1, 2]\n",
+			sampleRows:    100,
+			expectedError: "can't parse line 2 of file due to: record is not a JSON object",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := InferJsonlSchema(strings.NewReader(tc.jsonl), tc.sampleRows)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseJsonlRecord(t *testing.T) {
+	record, err := parseJsonlRecord([]byte(`{"b": 1, "a": {"x": [1, 2]}, "c": null}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b", "a", "c"}, record.fields)
+
+	v, null := jsonlValueText(record.values["a"])
+	assert.Equal(t, `{"x":[1,2]}`, v)
+	assert.False(t, null)
+	_, null = jsonlValueText(record.values["c"])
+	assert.True(t, null)
+	v, _ = jsonlValueText([]byte(`"a \"quoted\" string"`))
+	assert.Equal(t, `a "quoted" string`, v)
+
+	_, err = parseJsonlRecord([]byte(`{"a": 1} {"a": 2}`))
+	assert.EqualError(t, err, "unexpected data after JSON object")
+	_, err = parseJsonlRecord([]byte(`{"a": 1`))
+	assert.Error(t, err)
+}
@@ -32,3 +32,15 @@ func (m *MockCsvData) ImportData(ctx context.Context, spannerInfoSchema *spanner
 	}
 	return nil
 }
+
+// MockJsonlData for testing.
+type MockJsonlData struct {
+	ImportDataFn func(ctx context.Context, spannerInfoSchema *spanner.InfoSchemaImpl, dialect string, conv *internal.Conv, commonInfoSchema common.InfoSchemaInterface) error
+}
+
+func (m *MockJsonlData) ImportData(ctx context.Context, spannerInfoSchema *spanner.InfoSchemaImpl, dialect string, conv *internal.Conv, commonInfoSchema common.InfoSchemaInterface) error {
+	if m.ImportDataFn != nil {
+		return m.ImportDataFn(ctx, spannerInfoSchema, dialect, conv, commonInfoSchema)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+// AppFinding is a risk of the migration of a table found by the assessment of
+// the application code and queries, e.g. a query relying on the order of an
+// auto-increment column.
+type AppFinding struct {
+	ColId  string // Empty for findings about the whole table.
+	Issue  SchemaIssue
+	Source string // File, or "performance_schema" for the queries run on the source database.
+	Detail string // Query or code change of the finding.
+}
+
+// appIssues are the issues of AppFinding.
+var appIssues = []SchemaIssue{AppAutoIncrementOrdering, AppCaseInsensitiveLookup, AppSchemaChange}
+
+// SetAppFindings replaces the findings of the application code assessment of
+// the tables with findings, and adds their issues to the tables and columns
+// so that they are reviewed next to the schema conversion issues. Findings
+// about unknown tables or columns are ignored.
+func (conv *Conv) SetAppFindings(findings map[string][]AppFinding) {
+	for tableId, tableIssues := range conv.SchemaIssues {
+		tableIssues.TableLevelIssues = removeIssues(tableIssues.TableLevelIssues, appIssues)
+		for colId, l := range tableIssues.ColumnLevelIssues {
+			tableIssues.ColumnLevelIssues[colId] = removeIssues(l, appIssues)
+		}
+		conv.SchemaIssues[tableId] = tableIssues
+	}
+	conv.AppFindings = make(map[string][]AppFinding)
+	if conv.SchemaIssues == nil {
+		conv.SchemaIssues = make(map[string]TableIssues)
+	}
+	for tableId, l := range findings {
+		ct, ok := conv.SpSchema[tableId]
+		if !ok {
+			continue
+		}
+		for _, f := range l {
+			if f.ColId == "" {
+				conv.setTableIssue(tableId, f.Issue, true)
+			} else if _, ok := ct.ColDefs[f.ColId]; ok {
+				conv.addColumnIssue(tableId, f.ColId, f.Issue)
+			} else {
+				continue
+			}
+			conv.AppFindings[tableId] = append(conv.AppFindings[tableId], f)
+		}
+	}
+}
+
+// removeIssues returns l without issues.
+func removeIssues(l []SchemaIssue, issues []SchemaIssue) []SchemaIssue {
+	kept := []SchemaIssue{}
+	for _, i := range l {
+		if !Contains(issues, i) {
+			kept = append(kept, i)
+		}
+	}
+	if len(kept) == len(l) {
+		return l
+	}
+	return kept
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAppFindings(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			Name:    "users",
+			Id:      "t1",
+			ColIds:  []string{"c1", "c2"},
+			ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}}, "c2": {Name: "email", Id: "c2", T: ddl.Type{Name: ddl.String, Len: 100}}},
+		},
+	}
+	conv.SchemaIssues = map[string]TableIssues{
+		"t1": {ColumnLevelIssues: map[string][]SchemaIssue{"c1": {AutoIncrement}}},
+	}
+
+	lookup := AppFinding{ColId: "c2", Issue: AppCaseInsensitiveLookup, Source: "UserDao.java", Detail: "SELECT * FROM users WHERE email = ?"}
+	schemaChange := AppFinding{Issue: AppSchemaChange, Source: "UserDao.java", Detail: "id is generated by a sequence"}
+	conv.SetAppFindings(map[string][]AppFinding{
+		"t1": {lookup, schemaChange, {ColId: "c9", Issue: AppCaseInsensitiveLookup}},
+		"t9": {schemaChange},
+	})
+	assert.Equal(t, map[string][]AppFinding{"t1": {lookup, schemaChange}}, conv.AppFindings)
+	assert.Equal(t, []SchemaIssue{AutoIncrement}, conv.SchemaIssues["t1"].ColumnLevelIssues["c1"])
+	assert.Equal(t, []SchemaIssue{AppCaseInsensitiveLookup}, conv.SchemaIssues["t1"].ColumnLevelIssues["c2"])
+	assert.Equal(t, []SchemaIssue{AppSchemaChange}, conv.SchemaIssues["t1"].TableLevelIssues)
+
+	// Findings of a later assessment replace the ones of the previous one.
+	conv.SetAppFindings(nil)
+	assert.Empty(t, conv.AppFindings)
+	assert.Equal(t, []SchemaIssue{AutoIncrement}, conv.SchemaIssues["t1"].ColumnLevelIssues["c1"])
+	assert.Empty(t, conv.SchemaIssues["t1"].ColumnLevelIssues["c2"])
+	assert.Empty(t, conv.SchemaIssues["t1"].TableLevelIssues)
+}
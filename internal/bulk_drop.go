@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// DropAllForeignKeys removes the foreign keys of every Spanner table, for
+// migrations which create them once the data is loaded. The dropped foreign
+// keys are recorded in conv.DroppedObjects with the DDL recreating them, and
+// reported as table issues. Their names stay reserved so that they can be
+// recreated. Returns the number of dropped foreign keys.
+func (conv *Conv) DropAllForeignKeys() int {
+	c := conv.droppedObjectConfig()
+	n := 0
+	for _, tableId := range ddl.GetSortedTableIdsBySpName(conv.SpSchema) {
+		ct := conv.SpSchema[tableId]
+		if len(ct.ForeignKeys) == 0 {
+			continue
+		}
+		for _, fk := range ct.ForeignKeys {
+			conv.addDroppedObject(tableId, DroppedObject{Kind: "FOREIGN KEY", Name: fk.Name, Statement: fk.PrintForeignKeyAlterTable(conv.SpSchema, c, tableId)})
+			n++
+		}
+		ct.ForeignKeys = []ddl.Foreignkey{}
+		conv.SpSchema[tableId] = ct
+	}
+	return n
+}
+
+// DropAllSecondaryIndexes removes the secondary indexes of every Spanner
+// table, for migrations which create them once the data is loaded. Like
+// DropAllForeignKeys, the dropped indexes are recorded in
+// conv.DroppedObjects. Returns the number of dropped indexes.
+func (conv *Conv) DropAllSecondaryIndexes() int {
+	c := conv.droppedObjectConfig()
+	n := 0
+	for _, tableId := range ddl.GetSortedTableIdsBySpName(conv.SpSchema) {
+		ct := conv.SpSchema[tableId]
+		if len(ct.Indexes) == 0 {
+			continue
+		}
+		for _, index := range ct.Indexes {
+			conv.addDroppedObject(tableId, DroppedObject{Kind: "INDEX", Name: index.Name, Statement: index.PrintCreateIndex(ct, c)})
+			n++
+		}
+		ct.Indexes = []ddl.CreateIndex{}
+		conv.SpSchema[tableId] = ct
+	}
+	return n
+}
+
+func (conv *Conv) droppedObjectConfig() ddl.Config {
+	return ddl.Config{ProtectIds: true, SpDialect: conv.SpDialect, Source: conv.Source}
+}
+
+func (conv *Conv) addDroppedObject(tableId string, o DroppedObject) {
+	if conv.DroppedObjects == nil {
+		conv.DroppedObjects = make(map[string][]DroppedObject)
+	}
+	conv.DroppedObjects[tableId] = append(conv.DroppedObjects[tableId], o)
+	tableIssues := conv.SchemaIssues[tableId]
+	if !Contains(tableIssues.TableLevelIssues, ObjectDropped) {
+		tableIssues.TableLevelIssues = append(tableIssues.TableLevelIssues, ObjectDropped)
+	}
+	conv.SchemaIssues[tableId] = tableIssues
+}
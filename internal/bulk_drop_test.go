@@ -0,0 +1,56 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDropAllForeignKeysAndSecondaryIndexes(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			Name:        "users",
+			Id:          "t1",
+			ColIds:      []string{"c1", "c2"},
+			ColDefs:     map[string]ddl.ColumnDef{"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}}, "c2": {Name: "email", Id: "c2", T: ddl.Type{Name: ddl.String, Len: 100}}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+			Indexes:     []ddl.CreateIndex{{Name: "users_email", TableId: "t1", Id: "i1", Keys: []ddl.IndexKey{{ColId: "c2", Order: 1}}}},
+		},
+		"t2": {
+			Name:        "orders",
+			Id:          "t2",
+			ColIds:      []string{"c3", "c4"},
+			ColDefs:     map[string]ddl.ColumnDef{"c3": {Name: "id", Id: "c3", T: ddl.Type{Name: ddl.Int64}}, "c4": {Name: "user_id", Id: "c4", T: ddl.Type{Name: ddl.Int64}}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c3", Order: 1}},
+			ForeignKeys: []ddl.Foreignkey{{Name: "fk_users", ColIds: []string{"c4"}, ReferTableId: "t1", ReferColumnIds: []string{"c1"}, Id: "f1"}},
+		},
+	}
+
+	assert.Equal(t, 1, conv.DropAllForeignKeys())
+	assert.Empty(t, conv.SpSchema["t2"].ForeignKeys)
+	assert.Equal(t, 1, len(conv.SpSchema["t1"].Indexes))
+	assert.Equal(t, []DroppedObject{{Kind: "FOREIGN KEY", Name: "fk_users", Statement: "ALTER TABLE `orders` ADD CONSTRAINT `fk_users` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)"}}, conv.DroppedObjects["t2"])
+	assert.Equal(t, []SchemaIssue{ObjectDropped}, conv.SchemaIssues["t2"].TableLevelIssues)
+
+	assert.Equal(t, 1, conv.DropAllSecondaryIndexes())
+	assert.Empty(t, conv.SpSchema["t1"].Indexes)
+	assert.Equal(t, []DroppedObject{{Kind: "INDEX", Name: "users_email", Statement: "CREATE INDEX `users_email` ON `users` (`email`)"}}, conv.DroppedObjects["t1"])
+	assert.Equal(t, []SchemaIssue{ObjectDropped}, conv.SchemaIssues["t1"].TableLevelIssues)
+	assert.Equal(t, 0, conv.DropAllSecondaryIndexes())
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/charset"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"golang.org/x/text/encoding"
+)
+
+// ToUTF8 converts the values vals of the columns colIds of the Spanner table
+// tableId from conv.SourceCharset to UTF-8. Values of BYTES columns are left
+// unchanged. Invalid bytes, e.g. values which were not in the source charset,
+// are replaced with U+FFFD and their column is flagged with the InvalidUTF8
+// issue. An unsupported source charset is reported as an unexpected
+// condition, and values are then only checked for validity.
+func (conv *Conv) ToUTF8(tableId string, colIds []string, vals []string) []string {
+	enc, err := charset.Encoding(conv.SourceCharset)
+	if err != nil {
+		conv.Unexpected(err.Error())
+	}
+	var decoder *encoding.Decoder
+	if enc != nil {
+		decoder = enc.NewDecoder()
+	}
+	out := make([]string, len(vals))
+	for i, v := range vals {
+		out[i] = v
+		if i >= len(colIds) {
+			continue
+		}
+		col, ok := conv.SpSchema[tableId].ColDefs[colIds[i]]
+		if !ok || col.T.Name == ddl.Bytes {
+			continue
+		}
+		if decoder != nil {
+			if s, err := decoder.String(v); err == nil {
+				out[i] = s
+			}
+		}
+		if !utf8.ValidString(out[i]) {
+			out[i] = strings.ToValidUTF8(out[i], "\uFFFD")
+			conv.addColumnIssue(tableId, colIds[i], InvalidUTF8)
+		}
+	}
+	return out
+}
+
+func (conv *Conv) addColumnIssue(tableId, colId string, issue SchemaIssue) {
+	tableIssues := conv.SchemaIssues[tableId]
+	if tableIssues.ColumnLevelIssues == nil {
+		tableIssues.ColumnLevelIssues = make(map[string][]SchemaIssue)
+	}
+	if Contains(tableIssues.ColumnLevelIssues[colId], issue) {
+		return
+	}
+	tableIssues.ColumnLevelIssues[colId] = append(tableIssues.ColumnLevelIssues[colId], issue)
+	conv.SchemaIssues[tableId] = tableIssues
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToUTF8(t *testing.T) {
+	newConv := func(charset string) *Conv {
+		conv := MakeConv()
+		conv.SourceCharset = charset
+		conv.SpSchema["t1"] = ddl.CreateTable{
+			Name:   "t",
+			ColIds: []string{"c1", "c2", "c3"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "name", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				"c2": {Name: "data", T: ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}},
+				"c3": {Name: "id", T: ddl.Type{Name: ddl.Int64}},
+			},
+		}
+		return conv
+	}
+	colIds := []string{"c1", "c2", "c3"}
+
+	// latin1 is cp1252: 0xE9 is é and 0x80 is €.
+	conv := newConv("latin1")
+	assert.Equal(t, []string{"café €", "caf\xe9", "1"}, conv.ToUTF8("t1", colIds, []string{"caf\xe9 \x80", "caf\xe9", "1"}))
+	assert.Empty(t, conv.SchemaIssues["t1"].ColumnLevelIssues)
+
+	conv = newConv("cp1251")
+	assert.Equal(t, []string{"Привет"}, conv.ToUTF8("t1", colIds, []string{"\xcf\xf0\xe8\xe2\xe5\xf2"}))
+
+	// UTF-8 values are left unchanged, invalid bytes are replaced and flagged.
+	conv = newConv("utf8mb4")
+	assert.Equal(t, []string{"café", "\xff"}, conv.ToUTF8("t1", colIds, []string{"café", "\xff"}))
+	assert.Empty(t, conv.SchemaIssues["t1"].ColumnLevelIssues)
+	assert.Equal(t, []string{"caf\uFFFD"}, conv.ToUTF8("t1", colIds, []string{"caf\xe9"}))
+	assert.Equal(t, []SchemaIssue{InvalidUTF8}, conv.SchemaIssues["t1"].ColumnLevelIssues["c1"])
+	conv.ToUTF8("t1", colIds, []string{"\xff"})
+	assert.Equal(t, []SchemaIssue{InvalidUTF8}, conv.SchemaIssues["t1"].ColumnLevelIssues["c1"])
+
+	conv = newConv("ebcdic")
+	assert.Equal(t, []string{"abc"}, conv.ToUTF8("t1", colIds, []string{"abc"}))
+	assert.Equal(t, int64(1), conv.Stats.Unexpected["unsupported charset ebcdic"])
+}
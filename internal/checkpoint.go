@@ -0,0 +1,155 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Context returns the context of the data migration of conv, canceled when
+// the migration is interrupted, or the background context if none is set.
+func (conv *Conv) Context() context.Context {
+	if conv.Ctx == nil {
+		return context.Background()
+	}
+	return conv.Ctx
+}
+
+// Interrupted returns true if the data migration of conv was interrupted.
+// Readers of source data check it between tables and rows, and stop reading
+// once it is set; the rows already read are still written.
+func (conv *Conv) Interrupted() bool {
+	return conv.Ctx != nil && conv.Ctx.Err() != nil
+}
+
+// failedWrites records the Spanner tables some rows of which couldn't be
+// written. Rows are written concurrently by the goroutines of the writer.
+type failedWrites struct {
+	mu     sync.Mutex
+	tables map[string]bool
+}
+
+func (f *failedWrites) add(spTable string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.tables == nil {
+		f.tables = make(map[string]bool)
+	}
+	f.tables[spTable] = true
+}
+
+func (f *failedWrites) has(spTable string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tables[spTable]
+}
+
+// CompleteTable flushes the rows of table tableId read from the source and
+// records that all of them were written, unless the migration was
+// interrupted while reading them or some of them couldn't be written. Tables
+// whose rows were read but aren't complete are reported as partially written
+// by Checkpoint, to be reloaded when the migration is resumed. Rows deferred
+// by the writer, e.g. until their interleaving parent is written, are only
+// dropped after the last table, so Checkpoint checks again for rows which
+// couldn't be written.
+func (conv *Conv) CompleteTable(tableId string) {
+	if conv.DataFlush != nil {
+		conv.DataFlush()
+	}
+	if conv.Interrupted() || conv.failedWrites.has(conv.SpSchema[tableId].Name) {
+		return
+	}
+	if conv.Stats.CompletedTables == nil {
+		conv.Stats.CompletedTables = make(map[string]bool)
+	}
+	conv.Stats.CompletedTables[conv.SrcSchema[tableId].Name] = true
+}
+
+// Checkpoint is the state of an interrupted data migration, from which it
+// can be resumed.
+type Checkpoint struct {
+	Time            time.Time `json:"time"`
+	CompletedTables []string  `json:"completedTables"` // Source tables whose rows were all read and written.
+	PartialTables   []string  `json:"partialTables"`   // Source tables of which only some rows were written.
+	PendingTables   []string  `json:"pendingTables"`   // Source tables of which no row was read.
+	// ResumeFlags are the flags of the data subcommand migrating the
+	// remaining tables, emptying the partially written ones first.
+	ResumeFlags []string `json:"resumeFlags"`
+}
+
+// Checkpoint returns the state of the data migration of conv, the tables
+// sorted by name.
+func (conv *Conv) Checkpoint() Checkpoint {
+	c := Checkpoint{Time: time.Now(), CompletedTables: []string{}, PartialTables: []string{}, PendingTables: []string{}}
+	var remaining, reload []string
+	for tableId, srcTable := range conv.SrcSchema {
+		spTable, ok := conv.SpSchema[tableId]
+		if !ok || conv.SkipTable(srcTable.Name) {
+			continue
+		}
+		switch {
+		case conv.Stats.CompletedTables[srcTable.Name] && !conv.failedWrites.has(spTable.Name):
+			c.CompletedTables = append(c.CompletedTables, srcTable.Name)
+			continue
+		case conv.Stats.GoodRows[srcTable.Name] > 0:
+			c.PartialTables = append(c.PartialTables, srcTable.Name)
+			reload = append(reload, spTable.Name)
+		default:
+			c.PendingTables = append(c.PendingTables, srcTable.Name)
+		}
+		remaining = append(remaining, regexp.QuoteMeta(srcTable.Name))
+	}
+	sort.Strings(c.CompletedTables)
+	sort.Strings(c.PartialTables)
+	sort.Strings(c.PendingTables)
+	sort.Strings(remaining)
+	sort.Strings(reload)
+	if len(remaining) > 0 {
+		c.ResumeFlags = append(c.ResumeFlags, fmt.Sprintf("--include-tables='%s'", strings.Join(remaining, "|")))
+	}
+	if len(reload) > 0 {
+		c.ResumeFlags = append(c.ResumeFlags, fmt.Sprintf("--reload-tables=%s", strings.Join(reload, ",")))
+	}
+	return c
+}
+
+// Summary returns a description of the checkpoint for the user, with the
+// flags resuming the migration.
+func (c Checkpoint) Summary() string {
+	var b strings.Builder
+	b.WriteString("The data migration was interrupted.\n")
+	for _, l := range []struct {
+		title  string
+		tables []string
+	}{
+		{"Completed tables", c.CompletedTables},
+		{"Partially written tables", c.PartialTables},
+		{"Tables not started", c.PendingTables},
+	} {
+		if len(l.tables) > 0 {
+			fmt.Fprintf(&b, "%s: %s\n", l.title, strings.Join(l.tables, ", "))
+		}
+	}
+	if len(c.ResumeFlags) > 0 {
+		fmt.Fprintf(&b, "To resume, run the data subcommand again with: %s\n", strings.Join(c.ResumeFlags, " "))
+	}
+	return b.String()
+}
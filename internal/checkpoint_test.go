@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckpoint(t *testing.T) {
+	conv := MakeConv()
+	for id, name := range map[string]string{"t1": "customers", "t2": "orders", "t3": "sales.items", "t4": "tmp_orders", "t5": "payments", "t6": "invoices"} {
+		conv.SrcSchema[id] = schema.Table{Id: id, Name: name}
+		conv.SpSchema[id] = ddl.CreateTable{Id: id, Name: "sp_" + id}
+	}
+	conv.TableFilter, _ = NewTableFilter("", "tmp_.*")
+	assert.False(t, conv.Interrupted())
+	assert.Equal(t, context.Background(), conv.Context())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	conv.Ctx = ctx
+	flushes := 0
+	conv.DataFlush = func() { flushes++ }
+	conv.Stats.GoodRows["customers"] = 10
+	conv.CompleteTable("t1")
+	// Tables some rows of which couldn't be written are not complete.
+	conv.Stats.GoodRows["payments"] = 3
+	conv.CollectBadWrite("sp_t5", []string{"id"}, []interface{}{1}, nil)
+	conv.CompleteTable("t5")
+	conv.Stats.GoodRows["invoices"] = 4
+	conv.CompleteTable("t6")
+	conv.Stats.GoodRows["orders"] = 5
+	cancel()
+	assert.True(t, conv.Interrupted())
+	// Tables being read when the migration is interrupted are not complete.
+	conv.CompleteTable("t2")
+	assert.Equal(t, 4, flushes)
+	// A deferred row of a completed table is dropped once all tables were
+	// written.
+	conv.CollectBadWrite("sp_t6", []string{"id"}, []interface{}{2}, nil)
+
+	c := conv.Checkpoint()
+	assert.Equal(t, []string{"customers"}, c.CompletedTables)
+	assert.Equal(t, []string{"invoices", "orders", "payments"}, c.PartialTables)
+	assert.Equal(t, []string{"sales.items"}, c.PendingTables)
+	assert.Equal(t, []string{`--include-tables='invoices|orders|payments|sales\.items'`, "--reload-tables=sp_t2,sp_t5,sp_t6"}, c.ResumeFlags)
+	assert.Equal(t, "The data migration was interrupted.\n"+
+		"Completed tables: customers\n"+
+		"Partially written tables: invoices, orders, payments\n"+
+		"Tables not started: sales.items\n"+
+		"To resume, run the data subcommand again with: --include-tables='invoices|orders|payments|sales\\.items' --reload-tables=sp_t2,sp_t5,sp_t6\n", c.Summary())
+}
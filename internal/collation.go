@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// normalizedColumnSuffix is appended to the name of a column to name the
+// generated column storing its normalized values.
+const normalizedColumnSuffix = "_normalized"
+
+// CollationIssue returns the issue describing how comparisons on a source
+// column with collation c change once it is migrated to a Spanner column of
+// type ty. It returns false if comparisons don't change.
+func CollationIssue(c schema.Collation, ty ddl.Type) (SchemaIssue, bool) {
+	if c.Name == "" || c.Binary || ty.Name != ddl.String {
+		return 0, false
+	}
+	if c.CaseInsensitive {
+		return CaseInsensitiveCollation, true
+	}
+	return LocaleCollation, true
+}
+
+// AddNormalizedColumns preserves case-insensitive lookups on the indexed
+// columns of the source schema which have a case-insensitive collation. For
+// each such column, it adds a stored generated column with the lower case
+// values of the column, and an index on the generated column. The index is
+// unique if the column alone was a unique key in the source database, so
+// that values differing only by case are still rejected. Applications have
+// to look up LOWER(value) in the generated column to get the source
+// behavior.
+func (conv *Conv) AddNormalizedColumns() {
+	for tableId, ct := range conv.SpSchema {
+		srcTable, ok := conv.SrcSchema[tableId]
+		if !ok {
+			continue
+		}
+		for _, colId := range ct.ColIds {
+			srcCol, ok := srcTable.ColDefs[colId]
+			if !ok || !srcCol.Collation.CaseInsensitive {
+				continue
+			}
+			col := ct.ColDefs[colId]
+			if col.T.Name != ddl.String || col.T.IsArray {
+				continue
+			}
+			indexed, unique := sourceKeyUse(srcTable, colId)
+			if !indexed || hasNormalizedColumn(ct, col.Name) {
+				continue
+			}
+			name := conv.buildColumnNameWithBase(tableId, col.Name+normalizedColumnSuffix)
+			normId := GenerateColumnId()
+			ct.ColIds = append(ct.ColIds, normId)
+			ct.ColDefs[normId] = ddl.ColumnDef{
+				Name: name,
+				Id:   normId,
+				T:    col.T,
+				GeneratedColumn: ddl.GeneratedColumn{
+					IsPresent: true,
+					Value:     ddl.Expression{ExpressionId: GenerateExpressionId(), Statement: fmt.Sprintf("LOWER(%s)", col.Name)},
+					Type:      ddl.GeneratedColStored,
+				},
+			}
+			ct.Indexes = append(ct.Indexes, ddl.CreateIndex{
+				Name:    ToSpannerIndexName(conv, fmt.Sprintf("Index_%s_%s", ct.Name, name)),
+				TableId: tableId,
+				Unique:  unique,
+				Keys:    []ddl.IndexKey{{ColId: normId, Order: 1}},
+				Id:      GenerateIndexesId(),
+			})
+			conv.SpSchema[tableId] = ct
+			tableIssues := conv.SchemaIssues[tableId]
+			if tableIssues.ColumnLevelIssues == nil {
+				tableIssues.ColumnLevelIssues = make(map[string][]SchemaIssue)
+			}
+			tableIssues.ColumnLevelIssues[normId] = []SchemaIssue{NormalizedColumn}
+			conv.SchemaIssues[tableId] = tableIssues
+		}
+	}
+}
+
+// sourceKeyUse returns whether column colId of srcTable is part of its
+// primary key or of one of its indexes, and whether it is a unique key on its
+// own.
+func sourceKeyUse(srcTable schema.Table, colId string) (indexed, unique bool) {
+	for _, k := range srcTable.PrimaryKeys {
+		if k.ColId == colId {
+			indexed = true
+			unique = unique || len(srcTable.PrimaryKeys) == 1
+		}
+	}
+	for _, index := range srcTable.Indexes {
+		for _, k := range index.Keys {
+			if k.ColId == colId {
+				indexed = true
+				unique = unique || (index.Unique && len(index.Keys) == 1)
+			}
+		}
+	}
+	return indexed, unique
+}
+
+// hasNormalizedColumn returns true if table ct already has a generated column
+// with the lower case values of column name.
+func hasNormalizedColumn(ct ddl.CreateTable, name string) bool {
+	stmt := fmt.Sprintf("LOWER(%s)", name)
+	for _, col := range ct.ColDefs {
+		if col.GeneratedColumn.IsPresent && col.GeneratedColumn.Value.Statement == stmt {
+			return true
+		}
+	}
+	return false
+}
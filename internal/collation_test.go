@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollationIssue(t *testing.T) {
+	str := ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
+	tests := []struct {
+		name      string
+		collation schema.Collation
+		ty        ddl.Type
+		wantIssue SchemaIssue
+		wantOk    bool
+	}{
+		{"default collation", schema.Collation{}, str, 0, false},
+		{"binary", schema.Collation{Name: "utf8mb4_bin", Binary: true}, str, 0, false},
+		{"case-insensitive", schema.Collation{Name: "utf8mb4_0900_ai_ci", CaseInsensitive: true}, str, CaseInsensitiveCollation, true},
+		{"locale", schema.Collation{Name: "en_US.utf8"}, str, LocaleCollation, true},
+		{"not a string", schema.Collation{Name: "utf8mb4_0900_ai_ci", CaseInsensitive: true}, ddl.Type{Name: ddl.JSON}, 0, false},
+	}
+	for _, tc := range tests {
+		issue, ok := CollationIssue(tc.collation, tc.ty)
+		assert.Equal(t, tc.wantOk, ok, tc.name)
+		assert.Equal(t, tc.wantIssue, issue, tc.name)
+	}
+}
+
+func TestAddNormalizedColumns(t *testing.T) {
+	conv := MakeConv()
+	ci := schema.Collation{Name: "utf8mb4_0900_ai_ci", CaseInsensitive: true}
+	conv.SrcSchema = map[string]schema.Table{
+		"t1": {
+			Name:   "users",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3", "c4"},
+			ColDefs: map[string]schema.Column{
+				"c1": {Name: "id", Id: "c1"},
+				"c2": {Name: "email", Id: "c2", Collation: ci},
+				"c3": {Name: "city", Id: "c3", Collation: ci},
+				"c4": {Name: "bio", Id: "c4", Collation: ci},
+			},
+			PrimaryKeys: []schema.Key{{ColId: "c1"}},
+			Indexes: []schema.Index{
+				{Name: "email_idx", Id: "i1", Unique: true, Keys: []schema.Key{{ColId: "c2"}}},
+				{Name: "city_idx", Id: "i2", Keys: []schema.Key{{ColId: "c3"}, {ColId: "c1"}}},
+			},
+		},
+	}
+	str := ddl.Type{Name: ddl.String, Len: 100}
+	conv.SpSchema = map[string]ddl.CreateTable{
+		"t1": {
+			Name:   "users",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3", "c4"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Name: "email", Id: "c2", T: str},
+				"c3": {Name: "city", Id: "c3", T: str},
+				"c4": {Name: "bio", Id: "c4", T: str},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+		},
+	}
+	conv.AddNormalizedColumns()
+	// Calling it again doesn't add columns twice.
+	conv.AddNormalizedColumns()
+
+	ct := conv.SpSchema["t1"]
+	assert.Equal(t, 6, len(ct.ColIds))
+	assert.Equal(t, 2, len(ct.Indexes))
+	for i, want := range []struct {
+		name   string
+		expr   string
+		unique bool
+	}{
+		{"email_normalized", "LOWER(email)", true},
+		{"city_normalized", "LOWER(city)", false},
+	} {
+		col := ct.ColDefs[ct.ColIds[4+i]]
+		assert.Equal(t, want.name, col.Name)
+		assert.Equal(t, str, col.T)
+		assert.Equal(t, ddl.GeneratedColumn{IsPresent: true, Value: ddl.Expression{ExpressionId: col.GeneratedColumn.Value.ExpressionId, Statement: want.expr}, Type: ddl.GeneratedColStored}, col.GeneratedColumn)
+		assert.Equal(t, want.unique, ct.Indexes[i].Unique)
+		assert.Equal(t, []ddl.IndexKey{{ColId: col.Id, Order: 1}}, ct.Indexes[i].Keys)
+		assert.Equal(t, []SchemaIssue{NormalizedColumn}, conv.SchemaIssues["t1"].ColumnLevelIssues[col.Id])
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/google/uuid"
+)
+
+// Strategies used to fill columns which get no value from the source, e.g.
+// columns added to the Spanner schema.
+const (
+	FillConstant   = "constant"   // Value is the value of the column.
+	FillExpression = "expression" // Value is a template referencing other columns of the row, e.g. "{first_name} {last_name}".
+	FillUUID       = "uuid"       // A random UUID.
+	FillNow        = "now"        // The time the row is written.
+)
+
+var fillColumnRef = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// ColumnFill specifies how to fill a Spanner column in rows where the
+// column has no value (i.e. it is missing or NULL) during data migration.
+type ColumnFill struct {
+	Strategy string
+	Value    string
+}
+
+// SetColumnFill sets the strategy used to fill the column colId of the
+// Spanner table tableId during data migration, or removes it if the strategy
+// is empty.
+func (conv *Conv) SetColumnFill(tableId, colId string, fill ColumnFill) error {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return fmt.Errorf("table doesn't exist for tableId %s", tableId)
+	}
+	col, ok := ct.ColDefs[colId]
+	if !ok {
+		return fmt.Errorf("column %s doesn't exist in table %s", colId, ct.Name)
+	}
+	if fill.Strategy == "" {
+		delete(conv.ColumnFills[tableId], colId)
+		return nil
+	}
+	if col.T.IsArray {
+		return fmt.Errorf("array column %s can't be filled", col.Name)
+	}
+	switch fill.Strategy {
+	case FillConstant:
+		if _, err := toFillValue(col.T, fill.Value); err != nil {
+			return fmt.Errorf("invalid constant for column %s: %v", col.Name, err)
+		}
+	case FillExpression:
+		for _, m := range fillColumnRef.FindAllStringSubmatch(fill.Value, -1) {
+			if _, err := GetColIdFromSpName(ct.ColDefs, m[1]); err != nil {
+				return fmt.Errorf("expression for column %s references unknown column %s", col.Name, m[1])
+			}
+		}
+	case FillUUID:
+		if col.T.Name != ddl.String && col.T.Name != ddl.Bytes {
+			return fmt.Errorf("uuid can only fill %s or %s columns, column %s is %s", ddl.String, ddl.Bytes, col.Name, col.T.Name)
+		}
+	case FillNow:
+		if col.T.Name != ddl.Timestamp && col.T.Name != ddl.Date && col.T.Name != ddl.String {
+			return fmt.Errorf("now can only fill %s, %s or %s columns, column %s is %s", ddl.Timestamp, ddl.Date, ddl.String, col.Name, col.T.Name)
+		}
+	default:
+		return fmt.Errorf("unknown fill strategy %q, expected one of %s, %s, %s or %s", fill.Strategy, FillConstant, FillExpression, FillUUID, FillNow)
+	}
+	if conv.ColumnFills == nil {
+		conv.ColumnFills = make(map[string]map[string]ColumnFill)
+	}
+	if conv.ColumnFills[tableId] == nil {
+		conv.ColumnFills[tableId] = make(map[string]ColumnFill)
+	}
+	conv.ColumnFills[tableId][colId] = fill
+	return nil
+}
+
+// fillColumns adds the values of the columns of spTable with a fill
+// strategy to the row, when it has no value for them.
+func (conv *Conv) fillColumns(spTable string, spCols []string, spVals []interface{}) ([]string, []interface{}, error) {
+	if len(conv.ColumnFills) == 0 {
+		return spCols, spVals, nil
+	}
+	tableId, err := GetTableIdFromSpName(conv.SpSchema, spTable)
+	if err != nil {
+		return spCols, spVals, nil
+	}
+	fills := conv.ColumnFills[tableId]
+	if len(fills) == 0 {
+		return spCols, spVals, nil
+	}
+	newCols := append([]string{}, spCols...)
+	newVals := append([]interface{}{}, spVals...)
+	for colId, fill := range fills {
+		colDef, ok := conv.SpSchema[tableId].ColDefs[colId]
+		if !ok {
+			continue
+		}
+		i := slices.Index(newCols, colDef.Name)
+		if i != -1 && newVals[i] != nil {
+			continue
+		}
+		v, err := fillValue(colDef.T, fill, spCols, spVals)
+		if err != nil {
+			return spCols, spVals, fmt.Errorf("can't fill column %s: %v", colDef.Name, err)
+		}
+		if i == -1 {
+			newCols = append(newCols, colDef.Name)
+			newVals = append(newVals, v)
+		} else {
+			newVals[i] = v
+		}
+	}
+	return newCols, newVals, nil
+}
+
+// fillValue returns the value of a column of type ty filled with fill, in
+// a row with the source values spVals of the columns spCols.
+func fillValue(ty ddl.Type, fill ColumnFill, spCols []string, spVals []interface{}) (interface{}, error) {
+	switch fill.Strategy {
+	case FillConstant:
+		return toFillValue(ty, fill.Value)
+	case FillExpression:
+		var err error
+		s := fillColumnRef.ReplaceAllStringFunc(fill.Value, func(ref string) string {
+			i := slices.Index(spCols, ref[1:len(ref)-1])
+			if i == -1 || spVals[i] == nil {
+				err = fmt.Errorf("column %s referenced by the expression is NULL", ref[1:len(ref)-1])
+				return ""
+			}
+			return formatFillValue(spVals[i])
+		})
+		if err != nil {
+			return nil, err
+		}
+		return toFillValue(ty, s)
+	case FillUUID:
+		id := uuid.New()
+		if ty.Name == ddl.Bytes {
+			return id[:], nil
+		}
+		return id.String(), nil
+	case FillNow:
+		now := time.Now().UTC()
+		switch ty.Name {
+		case ddl.Date:
+			return civil.DateOf(now), nil
+		case ddl.String:
+			return now.Format(time.RFC3339Nano), nil
+		}
+		return now, nil
+	}
+	return nil, fmt.Errorf("unknown fill strategy %q", fill.Strategy)
+}
+
+// toFillValue converts s to a value of the Spanner type ty.
+func toFillValue(ty ddl.Type, s string) (interface{}, error) {
+	switch ty.Name {
+	case ddl.Bool:
+		return strconv.ParseBool(s)
+	case ddl.Bytes:
+		return []byte(s), nil
+	case ddl.Date:
+		return civil.ParseDate(s)
+	case ddl.Float32:
+		f, err := strconv.ParseFloat(s, 32)
+		return float32(f), err
+	case ddl.Float64:
+		return strconv.ParseFloat(s, 64)
+	case ddl.Int64:
+		return strconv.ParseInt(s, 10, 64)
+	case ddl.Numeric:
+		r, ok := new(big.Rat).SetString(s)
+		if !ok {
+			return nil, fmt.Errorf("%q is not a number", s)
+		}
+		return r, nil
+	case ddl.Timestamp:
+		return time.Parse(time.RFC3339Nano, s)
+	default:
+		return s, nil
+	}
+}
+
+// formatFillValue formats a converted Spanner value for use in a fill
+// expression.
+func formatFillValue(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case time.Time:
+		return x.Format(time.RFC3339Nano)
+	case *big.Rat:
+		return x.FloatString(9)
+	default:
+		return fmt.Sprintf("%v", x)
+	}
+}
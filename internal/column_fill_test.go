@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func fillTestConv() *Conv {
+	conv := MakeConv()
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			Name:   "users",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3", "c4", "c5", "c6", "c7"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Name: "first", Id: "c2", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				"c3": {Name: "last", Id: "c3", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				"c4": {Name: "full_name", Id: "c4", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				"c5": {Name: "ref", Id: "c5", T: ddl.Type{Name: ddl.String, Len: 36}},
+				"c6": {Name: "loaded", Id: "c6", T: ddl.Type{Name: ddl.Date}},
+				"c7": {Name: "score", Id: "c7", T: ddl.Type{Name: ddl.Numeric}},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+		},
+	}
+	return conv
+}
+
+func TestSetColumnFill(t *testing.T) {
+	conv := fillTestConv()
+	tests := []struct {
+		name    string
+		colId   string
+		fill    ColumnFill
+		wantErr bool
+	}{
+		{"constant", "c7", ColumnFill{Strategy: FillConstant, Value: "1.5"}, false},
+		{"invalid constant", "c7", ColumnFill{Strategy: FillConstant, Value: "high"}, true},
+		{"expression", "c4", ColumnFill{Strategy: FillExpression, Value: "{first} {last}"}, false},
+		{"expression with unknown column", "c4", ColumnFill{Strategy: FillExpression, Value: "{middle}"}, true},
+		{"uuid", "c5", ColumnFill{Strategy: FillUUID}, false},
+		{"uuid of numeric column", "c7", ColumnFill{Strategy: FillUUID}, true},
+		{"now", "c6", ColumnFill{Strategy: FillNow}, false},
+		{"now of numeric column", "c7", ColumnFill{Strategy: FillNow}, true},
+		{"unknown strategy", "c5", ColumnFill{Strategy: "random"}, true},
+		{"unknown column", "c9", ColumnFill{Strategy: FillUUID}, true},
+	}
+	for _, tc := range tests {
+		err := conv.SetColumnFill("t1", tc.colId, tc.fill)
+		assert.Equal(t, tc.wantErr, err != nil, tc.name)
+	}
+	assert.Equal(t, 4, len(conv.ColumnFills["t1"]))
+	assert.Nil(t, conv.SetColumnFill("t1", "c5", ColumnFill{}))
+	assert.Equal(t, 3, len(conv.ColumnFills["t1"]))
+}
+
+func TestWriteRowColumnFill(t *testing.T) {
+	conv := fillTestConv()
+	assert.Nil(t, conv.SetColumnFill("t1", "c4", ColumnFill{Strategy: FillExpression, Value: "{first} {last}"}))
+	assert.Nil(t, conv.SetColumnFill("t1", "c5", ColumnFill{Strategy: FillUUID}))
+	assert.Nil(t, conv.SetColumnFill("t1", "c6", ColumnFill{Strategy: FillNow}))
+	assert.Nil(t, conv.SetColumnFill("t1", "c7", ColumnFill{Strategy: FillConstant, Value: "0.5"}))
+	conv.SetDataMode()
+	vals := make(map[string]interface{})
+	conv.SetDataSink(func(table string, c []string, v []interface{}) {
+		for i := range c {
+			vals[c[i]] = v[i]
+		}
+	})
+
+	// Missing and NULL columns are filled.
+	conv.WriteRow("src", "users", []string{"id", "first", "last", "score"}, []interface{}{int64(1), "Ada", "Lovelace", nil})
+	assert.Equal(t, "Ada Lovelace", vals["full_name"])
+	_, err := uuid.Parse(vals["ref"].(string))
+	assert.Nil(t, err)
+	assert.Equal(t, civil.DateOf(time.Now().UTC()), vals["loaded"])
+	assert.Equal(t, big.NewRat(1, 2), vals["score"])
+
+	// Columns with a value are kept.
+	conv.WriteRow("src", "users", []string{"id", "first", "last", "full_name"}, []interface{}{int64(2), "Alan", "Turing", "A. Turing"})
+	assert.Equal(t, "A. Turing", vals["full_name"])
+
+	// Rows whose expression references a NULL column are bad rows.
+	conv.WriteRow("src", "users", []string{"id", "first"}, []interface{}{int64(3), "Grace"})
+	assert.Equal(t, int64(1), conv.BadRows())
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// Precision of Spanner NUMERIC values: 29 digits before the decimal point and
+// 9 after it.
+const (
+	numericIntegerDigits = 29
+	numericScale         = 9
+)
+
+// ColumnStats are statistics of the values of a source column, computed on a
+// sample of its rows. They back the Sampled* issues, which suggest tighter
+// types, and are shown in the review UI.
+type ColumnStats struct {
+	SampledRows int64
+	NullCount   int64
+	// Min and Max are the smallest and largest values, compared as numbers
+	// for numeric columns and as strings otherwise.
+	Min string
+	Max string
+	// Percentiles of the length of the values, in characters.
+	LengthP50 int64
+	LengthP95 int64
+	MaxLength int64
+	// For numeric columns, the largest number of digits before and after the
+	// decimal point.
+	MaxIntegerDigits int
+	MaxScale         int
+}
+
+// NullFraction returns the fraction of the sampled values which are NULL.
+func (s ColumnStats) NullFraction() float64 {
+	if s.SampledRows == 0 {
+		return 0
+	}
+	return float64(s.NullCount) / float64(s.SampledRows)
+}
+
+// ColumnStatsBuilder computes the ColumnStats of the values of a column.
+type ColumnStatsBuilder struct {
+	numeric  bool
+	stats    ColumnStats
+	lengths  []int64
+	min, max float64
+}
+
+// NewColumnStatsBuilder returns a builder for the values of a column, compared
+// as numbers if numeric is true.
+func NewColumnStatsBuilder(numeric bool) *ColumnStatsBuilder {
+	return &ColumnStatsBuilder{numeric: numeric}
+}
+
+// Add adds a value to the statistics, nil for NULL.
+func (b *ColumnStatsBuilder) Add(v *string) {
+	b.stats.SampledRows++
+	if v == nil {
+		b.stats.NullCount++
+		return
+	}
+	first := len(b.lengths) == 0
+	b.lengths = append(b.lengths, int64(utf8.RuneCountInString(*v)))
+	if b.numeric {
+		f, err := strconv.ParseFloat(strings.TrimSpace(*v), 64)
+		if err != nil {
+			return
+		}
+		if first || f < b.min {
+			b.min, b.stats.Min = f, *v
+		}
+		if first || f > b.max {
+			b.max, b.stats.Max = f, *v
+		}
+		integerDigits, scale := countDigits(*v)
+		if integerDigits > b.stats.MaxIntegerDigits {
+			b.stats.MaxIntegerDigits = integerDigits
+		}
+		if scale > b.stats.MaxScale {
+			b.stats.MaxScale = scale
+		}
+		return
+	}
+	if first || *v < b.stats.Min {
+		b.stats.Min = *v
+	}
+	if first || *v > b.stats.Max {
+		b.stats.Max = *v
+	}
+}
+
+// Stats returns the statistics of the values added so far.
+func (b *ColumnStatsBuilder) Stats() ColumnStats {
+	s := b.stats
+	if n := len(b.lengths); n > 0 {
+		lengths := append([]int64{}, b.lengths...)
+		sort.Slice(lengths, func(i, j int) bool { return lengths[i] < lengths[j] })
+		s.LengthP50 = lengths[(n-1)*50/100]
+		s.LengthP95 = lengths[(n-1)*95/100]
+		s.MaxLength = lengths[n-1]
+	}
+	return s
+}
+
+// countDigits returns the number of significant digits before and after the
+// decimal point of a number in decimal notation. Numbers in scientific
+// notation, e.g. floating point values, count as fitting NUMERIC.
+func countDigits(v string) (int, int) {
+	v = strings.TrimLeft(strings.TrimSpace(v), "+-")
+	if strings.ContainsAny(v, "eE") {
+		return 0, 0
+	}
+	integer, fraction, _ := strings.Cut(v, ".")
+	return len(strings.TrimLeft(integer, "0")), len(strings.TrimRight(fraction, "0"))
+}
+
+// SetColumnStats records the statistics of a column.
+func (conv *Conv) SetColumnStats(tableId, colId string, s ColumnStats) {
+	if conv.ColumnStats == nil {
+		conv.ColumnStats = make(map[string]map[string]ColumnStats)
+	}
+	if conv.ColumnStats[tableId] == nil {
+		conv.ColumnStats[tableId] = make(map[string]ColumnStats)
+	}
+	conv.ColumnStats[tableId][colId] = s
+}
+
+// SuggestedStringLength returns the STRING length suggested for a column
+// from its sampled values: twice the longest value, rounded up to a power of
+// two, leaving room for longer values than the sampled ones.
+func SuggestedStringLength(s ColumnStats) int64 {
+	length := int64(16)
+	for length < 2*s.MaxLength {
+		length *= 2
+	}
+	return length
+}
+
+// SuggestFromColumnStats flags the columns whose type can be improved given
+// their sampled values: STRING columns much longer than their values, NUMERIC
+// columns with values NUMERIC can't store, FLOAT64 columns mapped from
+// decimal types whose values fit NUMERIC, and nullable columns without NULL
+// values.
+func (conv *Conv) SuggestFromColumnStats() {
+	for tableId, colStats := range conv.ColumnStats {
+		table, ok := conv.SpSchema[tableId]
+		if !ok {
+			continue
+		}
+		for colId, s := range colStats {
+			col, ok := table.ColDefs[colId]
+			if !ok || s.SampledRows == 0 || col.T.IsArray {
+				continue
+			}
+			switch col.T.Name {
+			case ddl.String:
+				if s.NullCount < s.SampledRows && SuggestedStringLength(s) < col.T.Len {
+					conv.addColumnIssue(tableId, colId, SampledStringLength)
+				}
+			case ddl.Numeric:
+				if s.MaxIntegerDigits > numericIntegerDigits || s.MaxScale > numericScale {
+					conv.addColumnIssue(tableId, colId, SampledValuesExceedNumeric)
+				}
+			case ddl.Float64:
+				srcType := strings.ToLower(conv.SrcSchema[tableId].ColDefs[colId].Type.Name)
+				if (srcType == "decimal" || srcType == "numeric") && s.NullCount < s.SampledRows && s.MaxIntegerDigits <= numericIntegerDigits && s.MaxScale <= numericScale {
+					conv.addColumnIssue(tableId, colId, SampledValuesFitNumeric)
+				}
+			}
+			if !col.NotNull && s.NullCount == 0 {
+				conv.addColumnIssue(tableId, colId, SampledNoNulls)
+			}
+		}
+	}
+}
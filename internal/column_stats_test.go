@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnStatsBuilder(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	b := NewColumnStatsBuilder(false)
+	for _, v := range []*string{str("b"), nil, str("abc"), str("été"), nil} {
+		b.Add(v)
+	}
+	assert.Equal(t, ColumnStats{SampledRows: 5, NullCount: 2, Min: "abc", Max: "été", LengthP50: 3, LengthP95: 3, MaxLength: 3}, b.Stats())
+	assert.Equal(t, 0.4, b.Stats().NullFraction())
+
+	b = NewColumnStatsBuilder(true)
+	for _, v := range []*string{str("9.5"), str("-120.125"), str("10"), str("1e300")} {
+		b.Add(v)
+	}
+	s := b.Stats()
+	assert.Equal(t, "-120.125", s.Min)
+	assert.Equal(t, "1e300", s.Max)
+	assert.Equal(t, 3, s.MaxIntegerDigits)
+	assert.Equal(t, 3, s.MaxScale)
+
+	assert.Equal(t, ColumnStats{}, NewColumnStatsBuilder(true).Stats())
+	assert.Equal(t, 0.0, ColumnStats{}.NullFraction())
+}
+
+func TestSuggestFromColumnStats(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Name:   "t",
+		ColIds: []string{"c1", "c2", "c3", "c4", "c5"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Name: "name", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, NotNull: true},
+			"c2": {Name: "code", T: ddl.Type{Name: ddl.String, Len: 32}, NotNull: true},
+			"c3": {Name: "amount", T: ddl.Type{Name: ddl.Numeric}, NotNull: true},
+			"c4": {Name: "price", T: ddl.Type{Name: ddl.Float64}, NotNull: true},
+			"c5": {Name: "note", T: ddl.Type{Name: ddl.String, Len: 16}},
+		},
+	}
+	conv.SrcSchema["t1"] = schema.Table{
+		Name:    "t",
+		ColDefs: map[string]schema.Column{"c4": {Name: "price", Type: schema.Type{Name: "decimal"}}},
+	}
+	conv.SetColumnStats("t1", "c1", ColumnStats{SampledRows: 10, MaxLength: 40})
+	conv.SetColumnStats("t1", "c2", ColumnStats{SampledRows: 10, MaxLength: 20})
+	conv.SetColumnStats("t1", "c3", ColumnStats{SampledRows: 10, MaxIntegerDigits: 3, MaxScale: 12})
+	conv.SetColumnStats("t1", "c4", ColumnStats{SampledRows: 10, MaxIntegerDigits: 6, MaxScale: 2})
+	conv.SetColumnStats("t1", "c5", ColumnStats{SampledRows: 10, MaxLength: 4})
+	conv.SuggestFromColumnStats()
+
+	assert.Equal(t, map[string][]SchemaIssue{
+		"c1": {SampledStringLength},
+		"c3": {SampledValuesExceedNumeric},
+		"c4": {SampledValuesFitNumeric},
+		"c5": {SampledNoNulls},
+	}, conv.SchemaIssues["t1"].ColumnLevelIssues)
+	assert.Equal(t, int64(128), SuggestedStringLength(conv.ColumnStats["t1"]["c1"]))
+}
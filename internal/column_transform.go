@@ -0,0 +1,252 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+)
+
+// Types of column transforms.
+const (
+	TransformMerge = "merge" // Joins several source columns into one Spanner column.
+	TransformSplit = "split" // Splits one source column into several Spanner columns.
+)
+
+// ColumnTransform computes the values of Spanner columns which have no source
+// column from the values of source columns. The source columns don't need to
+// be part of the Spanner table, e.g. first_name and last_name can be merged
+// into full_name and then removed.
+//
+// Merged values are the non-NULL source values joined with Separator. Split
+// values are the submatches of Regex, if set, or else the parts of the source
+// value separated by Separator, the last column getting the remainder.
+// Missing parts are NULL.
+type ColumnTransform struct {
+	Type      string
+	SrcColIds []string
+	SpColIds  []string
+	Separator string
+	Regex     string
+}
+
+// Sources whose data conversion applies column transforms.
+var columnTransformSources = []string{constants.MYSQL, constants.MYSQLDUMP, constants.POSTGRES, constants.PGDUMP, constants.SQLSERVER, constants.ORACLE}
+
+// splitRegexps caches the compiled split regexes, which are applied to every
+// row.
+var splitRegexps sync.Map
+
+// SetColumnTransforms replaces the column transforms of table tableId.
+func (conv *Conv) SetColumnTransforms(tableId string, transforms []ColumnTransform) error {
+	if len(transforms) > 0 && conv.Source != "" && !slices.Contains(columnTransformSources, conv.Source) {
+		return fmt.Errorf("column transforms are not supported for %s", conv.Source)
+	}
+	sp, ok := conv.SpSchema[tableId]
+	if !ok {
+		return fmt.Errorf("table doesn't exist for tableId %s", tableId)
+	}
+	src, ok := conv.SrcSchema[tableId]
+	if !ok {
+		return fmt.Errorf("table %s has no source table", sp.Name)
+	}
+	targets := make(map[string]bool)
+	for _, t := range transforms {
+		switch t.Type {
+		case TransformMerge:
+			if len(t.SrcColIds) < 2 || len(t.SpColIds) != 1 {
+				return fmt.Errorf("merge transform must merge at least 2 source columns into 1 Spanner column")
+			}
+		case TransformSplit:
+			if len(t.SrcColIds) != 1 || len(t.SpColIds) < 2 {
+				return fmt.Errorf("split transform must split 1 source column into at least 2 Spanner columns")
+			}
+			if t.Regex != "" {
+				re, err := regexp.Compile(t.Regex)
+				if err != nil {
+					return fmt.Errorf("invalid split regex %q: %v", t.Regex, err)
+				}
+				if re.NumSubexp() != len(t.SpColIds) {
+					return fmt.Errorf("split regex %q must have one group for each of the %d Spanner columns", t.Regex, len(t.SpColIds))
+				}
+			} else if t.Separator == "" {
+				return fmt.Errorf("split transform needs a separator or a regex")
+			}
+		default:
+			return fmt.Errorf("unknown column transform %q, expected %s or %s", t.Type, TransformMerge, TransformSplit)
+		}
+		for _, colId := range t.SrcColIds {
+			if _, ok := src.ColDefs[colId]; !ok {
+				return fmt.Errorf("column %s doesn't exist in source table %s", colId, src.Name)
+			}
+		}
+		for _, colId := range t.SpColIds {
+			col, ok := sp.ColDefs[colId]
+			if !ok {
+				return fmt.Errorf("column %s doesn't exist in table %s", colId, sp.Name)
+			}
+			if _, ok := src.ColDefs[colId]; ok {
+				return fmt.Errorf("column %s has a source column, only added columns can be computed by a transform", col.Name)
+			}
+			if col.T.IsArray {
+				return fmt.Errorf("array column %s can't be computed by a transform", col.Name)
+			}
+			if targets[colId] {
+				return fmt.Errorf("column %s is computed by more than one transform", col.Name)
+			}
+			targets[colId] = true
+		}
+	}
+	if conv.ColumnTransforms == nil {
+		conv.ColumnTransforms = make(map[string][]ColumnTransform)
+	}
+	if len(transforms) == 0 {
+		delete(conv.ColumnTransforms, tableId)
+	} else {
+		conv.ColumnTransforms[tableId] = transforms
+	}
+	return nil
+}
+
+// RemoveColumnTransforms removes the transforms of table tableId which
+// compute the Spanner column colId.
+func (conv *Conv) RemoveColumnTransforms(tableId, colId string) {
+	var transforms []ColumnTransform
+	for _, t := range conv.ColumnTransforms[tableId] {
+		if !slices.Contains(t.SpColIds, colId) {
+			transforms = append(transforms, t)
+		}
+	}
+	if len(transforms) == 0 {
+		delete(conv.ColumnTransforms, tableId)
+	} else {
+		conv.ColumnTransforms[tableId] = transforms
+	}
+}
+
+// ColumnTransformInputs returns the source columns of table tableId read by
+// its column transforms.
+func (conv *Conv) ColumnTransformInputs(tableId string) []string {
+	var colIds []string
+	for _, t := range conv.ColumnTransforms[tableId] {
+		for _, colId := range t.SrcColIds {
+			if !slices.Contains(colIds, colId) {
+				colIds = append(colIds, colId)
+			}
+		}
+	}
+	return colIds
+}
+
+// ApplyColumnTransforms computes the columns of table tableId given by its
+// column transforms, from the source values vals of the columns colIds.
+// toString returns the text of a source value, and false if it is NULL.
+// It returns the columns and values of colIds which are part of the Spanner
+// table, followed by the Spanner names and converted values of the computed
+// columns.
+func ApplyColumnTransforms[T any](conv *Conv, tableId string, colIds []string, vals []T, toString func(T) (string, bool)) ([]string, []T, []string, []interface{}, error) {
+	transforms := conv.ColumnTransforms[tableId]
+	if len(transforms) == 0 {
+		return colIds, vals, nil, nil, nil
+	}
+	sp := conv.SpSchema[tableId]
+	var keptColIds []string
+	var keptVals []T
+	srcVals := make(map[string]string)
+	for i, colId := range colIds {
+		if s, ok := toString(vals[i]); ok {
+			srcVals[colId] = s
+		}
+		if _, ok := sp.ColDefs[colId]; ok {
+			keptColIds = append(keptColIds, colId)
+			keptVals = append(keptVals, vals[i])
+		}
+	}
+	var cols []string
+	var cvtVals []interface{}
+	for _, t := range transforms {
+		parts, err := transformValues(t, srcVals)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		for i, colId := range t.SpColIds {
+			if i >= len(parts) || parts[i] == nil {
+				continue
+			}
+			col := sp.ColDefs[colId]
+			v, err := toFillValue(col.T, *parts[i])
+			if err != nil {
+				return nil, nil, nil, nil, fmt.Errorf("can't convert %q for column %s: %v", *parts[i], col.Name, err)
+			}
+			cols = append(cols, col.Name)
+			cvtVals = append(cvtVals, v)
+		}
+	}
+	return keptColIds, keptVals, cols, cvtVals, nil
+}
+
+// transformValues returns the values of the Spanner columns of t, nil for
+// NULL, given the non-NULL source values srcVals.
+func transformValues(t ColumnTransform, srcVals map[string]string) ([]*string, error) {
+	switch t.Type {
+	case TransformMerge:
+		var parts []string
+		for _, colId := range t.SrcColIds {
+			if s, ok := srcVals[colId]; ok {
+				parts = append(parts, s)
+			}
+		}
+		if len(parts) == 0 {
+			return nil, nil
+		}
+		merged := strings.Join(parts, t.Separator)
+		return []*string{&merged}, nil
+	case TransformSplit:
+		s, ok := srcVals[t.SrcColIds[0]]
+		if !ok {
+			return nil, nil
+		}
+		var values []*string
+		if t.Regex == "" {
+			for _, p := range strings.SplitN(s, t.Separator, len(t.SpColIds)) {
+				values = append(values, &p)
+			}
+			return values, nil
+		}
+		re, ok := splitRegexps.Load(t.Regex)
+		if !ok {
+			re, _ = splitRegexps.LoadOrStore(t.Regex, regexp.MustCompile(t.Regex))
+		}
+		m := re.(*regexp.Regexp).FindStringSubmatchIndex(s)
+		if m == nil {
+			return nil, fmt.Errorf("value %q doesn't match split regex %q", s, t.Regex)
+		}
+		for i := 1; i <= len(t.SpColIds); i++ {
+			if m[2*i] < 0 {
+				values = append(values, nil)
+				continue
+			}
+			p := s[m[2*i]:m[2*i+1]]
+			values = append(values, &p)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("unknown column transform %q", t.Type)
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func transformTestConv() *Conv {
+	conv := MakeConv()
+	conv.SrcSchema = map[string]schema.Table{
+		"t1": {
+			Name:   "users",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3", "c4"},
+			ColDefs: map[string]schema.Column{
+				"c1": {Name: "id", Id: "c1", Type: schema.Type{Name: "int"}},
+				"c2": {Name: "first_name", Id: "c2", Type: schema.Type{Name: "varchar"}},
+				"c3": {Name: "last_name", Id: "c3", Type: schema.Type{Name: "varchar"}},
+				"c4": {Name: "birth", Id: "c4", Type: schema.Type{Name: "varchar"}},
+			},
+		},
+	}
+	// first_name and last_name are merged into full_name and removed, birth
+	// is split into year and month.
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			Name:   "users",
+			Id:     "t1",
+			ColIds: []string{"c1", "c4", "c5", "c6", "c7"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+				"c4": {Name: "birth", Id: "c4", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				"c5": {Name: "full_name", Id: "c5", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				"c6": {Name: "birth_year", Id: "c6", T: ddl.Type{Name: ddl.Int64}},
+				"c7": {Name: "birth_month", Id: "c7", T: ddl.Type{Name: ddl.Int64}},
+			},
+		},
+	}
+	return conv
+}
+
+func TestSetColumnTransforms(t *testing.T) {
+	merge := ColumnTransform{Type: TransformMerge, SrcColIds: []string{"c2", "c3"}, SpColIds: []string{"c5"}, Separator: " "}
+	split := ColumnTransform{Type: TransformSplit, SrcColIds: []string{"c4"}, SpColIds: []string{"c6", "c7"}, Separator: "-"}
+	tests := []struct {
+		name       string
+		transforms []ColumnTransform
+		wantErr    bool
+	}{
+		{"merge and split", []ColumnTransform{merge, split}, false},
+		{"split with regex", []ColumnTransform{{Type: TransformSplit, SrcColIds: []string{"c4"}, SpColIds: []string{"c6", "c7"}, Regex: `^(\d+)-(\d+)`}}, false},
+		{"regex groups don't match columns", []ColumnTransform{{Type: TransformSplit, SrcColIds: []string{"c4"}, SpColIds: []string{"c6", "c7"}, Regex: `^(\d+)`}}, true},
+		{"split without separator", []ColumnTransform{{Type: TransformSplit, SrcColIds: []string{"c4"}, SpColIds: []string{"c6", "c7"}}}, true},
+		{"merge of one column", []ColumnTransform{{Type: TransformMerge, SrcColIds: []string{"c2"}, SpColIds: []string{"c5"}}}, true},
+		{"target with source column", []ColumnTransform{{Type: TransformMerge, SrcColIds: []string{"c2", "c3"}, SpColIds: []string{"c4"}}}, true},
+		{"target computed twice", []ColumnTransform{merge, merge}, true},
+		{"unknown type", []ColumnTransform{{Type: "join", SrcColIds: []string{"c2", "c3"}, SpColIds: []string{"c5"}}}, true},
+	}
+	for _, tc := range tests {
+		conv := transformTestConv()
+		err := conv.SetColumnTransforms("t1", tc.transforms)
+		assert.Equal(t, tc.wantErr, err != nil, tc.name)
+	}
+
+	conv := transformTestConv()
+	conv.Source = "dynamodb"
+	assert.NotNil(t, conv.SetColumnTransforms("t1", []ColumnTransform{merge}))
+}
+
+func TestRemoveColumnTransforms(t *testing.T) {
+	conv := transformTestConv()
+	assert.Nil(t, conv.SetColumnTransforms("t1", []ColumnTransform{
+		{Type: TransformMerge, SrcColIds: []string{"c2", "c3"}, SpColIds: []string{"c5"}, Separator: " "},
+		{Type: TransformSplit, SrcColIds: []string{"c4"}, SpColIds: []string{"c6", "c7"}, Separator: "-"},
+	}))
+	assert.Equal(t, []string{"c2", "c3", "c4"}, conv.ColumnTransformInputs("t1"))
+	conv.RemoveColumnTransforms("t1", "c7")
+	assert.Equal(t, []string{"c2", "c3"}, conv.ColumnTransformInputs("t1"))
+	conv.RemoveColumnTransforms("t1", "c5")
+	assert.Nil(t, conv.ColumnTransforms["t1"])
+}
+
+func TestApplyColumnTransforms(t *testing.T) {
+	isNotNull := func(s string) (string, bool) { return s, s != "NULL" }
+	conv := transformTestConv()
+	assert.Nil(t, conv.SetColumnTransforms("t1", []ColumnTransform{
+		{Type: TransformMerge, SrcColIds: []string{"c2", "c3"}, SpColIds: []string{"c5"}, Separator: " "},
+		{Type: TransformSplit, SrcColIds: []string{"c4"}, SpColIds: []string{"c6", "c7"}, Regex: `^(\d{4})(?:-(\d{2}))?`},
+	}))
+	colIds := []string{"c1", "c2", "c3", "c4"}
+
+	cols, vals, tCols, tVals, err := ApplyColumnTransforms(conv, "t1", colIds, []string{"1", "Ada", "Lovelace", "1815-12"}, isNotNull)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"c1", "c4"}, cols)
+	assert.Equal(t, []string{"1", "1815-12"}, vals)
+	assert.Equal(t, []string{"full_name", "birth_year", "birth_month"}, tCols)
+	assert.Equal(t, []interface{}{"Ada Lovelace", int64(1815), int64(12)}, tVals)
+
+	// NULL source values are skipped, and optional groups that don't match
+	// are NULL.
+	_, _, tCols, tVals, err = ApplyColumnTransforms(conv, "t1", colIds, []string{"2", "Turing", "NULL", "1912"}, isNotNull)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"full_name", "birth_year"}, tCols)
+	assert.Equal(t, []interface{}{"Turing", int64(1912)}, tVals)
+
+	_, _, _, _, err = ApplyColumnTransforms(conv, "t1", colIds, []string{"3", "Grace", "Hopper", "unknown"}, isNotNull)
+	assert.NotNil(t, err)
+}
@@ -15,33 +15,48 @@
 package internal
 
 import (
+	"context"
 	"fmt"
+	"math/bits"
+	"regexp"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/proto/migration"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"google.golang.org/genproto/googleapis/type/datetime"
 )
 
 // Conv contains all schema and data conversion state.
 type Conv struct {
-	mode                   mode                         // Schema mode or data mode.
-	SpSchema               ddl.Schema                   // Maps Spanner table name to Spanner schema.
-	SyntheticPKeys         map[string]SyntheticPKey     // Maps Spanner table name to synthetic primary key (if needed).
-	SrcSchema              map[string]schema.Table      // Maps source-DB table name to schema information.
-	SchemaIssues           map[string]TableIssues       // Maps source-DB table/col to list of schema conversion issues.
-	InvalidCheckExp        map[string][]InvalidCheckExp // List of check constraint expressions and corresponding issues.
-	ToSpanner              map[string]NameAndCols       // Maps from source-DB table name to Spanner name and column mapping.
-	ToSource               map[string]NameAndCols       `json:"-"` // Maps from Spanner table name to source-DB table name and column mapping.
-	UsedNames              map[string]bool              `json:"-"` // Map storing the names that are already assigned to tables, indices or foreign key contraints.
+	mode                   mode                            // Schema mode or data mode.
+	SpSchema               ddl.Schema                      // Maps Spanner table name to Spanner schema.
+	SyntheticPKeys         map[string]SyntheticPKey        // Maps Spanner table name to synthetic primary key (if needed).
+	SrcSchema              map[string]schema.Table         // Maps source-DB table name to schema information.
+	SchemaIssues           map[string]TableIssues          // Maps source-DB table/col to list of schema conversion issues.
+	InvalidCheckExp        map[string][]InvalidCheckExp    // List of check constraint expressions and corresponding issues.
+	DdlRejections          map[string][]DdlRejection       // Maps Spanner table id to its DDL statements rejected by the Spanner emulator.
+	DroppedObjects         map[string][]DroppedObject      // Maps Spanner table id to its foreign keys and secondary indexes dropped in bulk.
+	AppFindings            map[string][]AppFinding         // Maps Spanner table id to the findings of the application code assessment about it.
+	JSONPathSuggestions    map[string][]JSONPathSuggestion // Maps Spanner table id to the JSON paths the application filters or orders its rows by.
+	ToSpanner              map[string]NameAndCols          // Maps from source-DB table name to Spanner name and column mapping.
+	ToSource               map[string]NameAndCols          `json:"-"` // Maps from Spanner table name to source-DB table name and column mapping.
+	UsedNames              map[string]bool                 `json:"-"` // Map storing the names that are already assigned to tables, indices or foreign key contraints.
 	dataSink               func(table string, cols []string, values []interface{})
 	DataFlush              func()                  `json:"-"` // Data flush is used to flush out remaining writes and wait for them to complete.
 	Location               *time.Location          // Timezone (for timestamp conversion).
 	sampleBadRows          rowSamples              // Rows that generated errors during conversion.
+	DeadLetter             DeadLetterWriter        `json:"-"` // Optional sink receiving every bad row, in addition to the in-memory samples.
+	Export                 ExportWriter            `json:"-"` // Optional sink receiving the converted rows instead of Spanner.
+	Status                 *MigrationStatus        `json:"-"` // Optional progress of the migration, served by the status server.
+	Ctx                    context.Context         `json:"-"` // Optional context of the data migration, canceled when it is interrupted.
 	Stats                  stats                   `json:"-"`
 	TimezoneOffset         string                  // Timezone offset for timestamp conversion.
 	SpDialect              string                  // The dialect of the spanner database to which Spanner migration tool is writing.
@@ -59,7 +74,30 @@ type Conv struct {
 	SpInstanceId           string                  // Spanner Instance Id
 	Source                 string                  // Source Database type being migrated
 	DatabaseOptions        ddl.DatabaseOptions
-	DefaultIdentityOptions ddl.IdentityOptions // Default values to use for IDENTITY columns
+	DefaultIdentityOptions ddl.IdentityOptions               // Default values to use for IDENTITY columns
+	SyntheticPKeyStrategy  string                            // Default strategy used to generate synthetic primary keys for tables without one.
+	UnsignedIntPolicy      string                            // Spanner type of MySQL BIGINT UNSIGNED columns: int64 (default), numeric or string.
+	TimezonePolicy         string                            // Time zone of source values without one: utc (default) or source.
+	NumericPrecisionPolicy string                            // Handling of values with more digits after the decimal point than NUMERIC stores: round (default) or fail.
+	SourceCharset          string                            // Charset of the string values of dumps, e.g. latin1 from SET NAMES. Values are converted to UTF-8 when loaded, empty for UTF-8.
+	ColumnTimezones        map[string]map[string]string      // Maps Spanner table id and column id to the time zone of its values without one, overriding TimezonePolicy.
+	NameTemplates          NameTemplates                     // Templates used to name generated columns, indexes and sequences.
+	CommitTimestampCols    map[string]map[string]string      // Maps Spanner table id to the columns written with the commit timestamp, and the source sentinel value (if any) replaced by it.
+	ColumnFills            map[string]map[string]ColumnFill  // Maps Spanner table id and column id to the strategy filling the column when the source has no value for it.
+	ColumnMasks            map[string]map[string]ColumnMask  // Maps Spanner table id and column id to the strategy masking its values during data migration.
+	masking                *maskState                        // State of the masking of the migrated rows.
+	sample                 *dataSampler                      // Selects the migrated rows of a sampled data migration, from --sample.
+	failedWrites           failedWrites                      // Spanner tables some rows of which couldn't be written, see CompleteTable.
+	ColumnTransforms       map[string][]ColumnTransform      // Maps Spanner table id to the transforms computing its added columns from source columns.
+	SpRoles                map[string]ddl.CreateRole         // Maps Spanner role id to fine-grained access control role.
+	SpGrants               []ddl.Grant                       // Fine-grained access control grants to Spanner roles.
+	SpViews                map[string]ddl.CreateView         // Maps Spanner view id to view definition.
+	TableReadParallelism   int                               `json:"-"` // Number of workers reading a source table by primary key range, or the data files of a mydumper export; a table is read with a single query when at most 1.
+	TableFilter            TableFilter                       `json:"-"` // Regexes selecting the source tables to migrate, from --include-tables and --exclude-tables.
+	TableOrder             TableOrder                        `json:"-"` // Priority classes of the tables whose data is migrated first, from --table-order.
+	ExcludedTables         []string                          // Sorted names of the source tables skipped by TableFilter during schema conversion.
+	ColumnStats            map[string]map[string]ColumnStats // Maps Spanner table id and column id to statistics sampled from the source column, when enabled by the columnStatsSample source profile param.
+	SrcRowCounts           map[string]int64                  // Maps Spanner table id to the number of rows of the source table estimated by the source database, read during schema conversion.
 }
 
 type InvalidCheckExp struct {
@@ -67,6 +105,21 @@ type InvalidCheckExp struct {
 	Expression string
 }
 
+// DdlRejection is a DDL statement rejected by the Spanner emulator during an
+// emulator dry run, and the error returned by the emulator.
+type DdlRejection struct {
+	Statement string
+	Error     string
+}
+
+// DroppedObject is a foreign key or secondary index dropped in bulk (see
+// Conv.DropAllForeignKeys), and the DDL statement recreating it.
+type DroppedObject struct {
+	Kind      string // "FOREIGN KEY" or "INDEX".
+	Name      string
+	Statement string
+}
+
 type TableIssues struct {
 	ColumnLevelIssues map[string][]SchemaIssue
 	TableLevelIssues  []SchemaIssue
@@ -78,6 +131,9 @@ type AdditionalSchemaAttributes struct {
 
 type AdditionalDataAttributes struct {
 	ShardId string
+	// Location is the time zone of the source session the values of the
+	// table are read in, if it overrides the time zone of the conversion.
+	Location *time.Location
 }
 
 type mode int
@@ -91,8 +147,10 @@ const (
 // count for a table, if needed. We use a synthetic primary key when
 // the source DB table has no primary key.
 type SyntheticPKey struct {
-	ColId    string
-	Sequence int64
+	ColId      string
+	Sequence   int64
+	Strategy   string // Strategy used to generate key values. Empty implies constants.SYNTH_PK_STRING_SEQUENCE.
+	SequenceId string // Id of the Spanner sequence generating the key values of the bit_reversed_sequence strategy.
 }
 
 // SchemaIssue specifies a schema conversion issue.
@@ -159,6 +217,41 @@ const (
 	PossibleOverflow
 	IdentitySkipRange
 	GeneratedColumnValueError
+	PartitionedTable
+	IndexStoringSuggestion
+	IndexNullsOrder
+	UnsignedInteger
+	TimeAsSeconds
+	Interval
+	IntervalAsMicroseconds
+	DomainType
+	CompositeType
+	CaseInsensitiveCollation
+	LocaleCollation
+	NormalizedColumn
+	DdlRejected
+	ObjectDropped
+	InvalidUTF8
+	SampledStringLength
+	SampledValuesExceedNumeric
+	SampledValuesFitNumeric
+	SampledNoNulls
+	InterleaveOnDeleteDiverges
+	InterleaveCycle
+	InterleaveTooDeep
+	CheckConstraintColumnRetyped
+	TooManyColumns
+	TooManyIndexes
+	KeyLimitExceeded
+	InvalidLength
+	CommitLimitExceeded
+	WideRow
+	AppAutoIncrementOrdering
+	AppCaseInsensitiveLookup
+	AppSchemaChange
+	VectorColumn
+	JSONPathIndexSuggestion
+	JSONPathColumn
 )
 
 const (
@@ -180,6 +273,33 @@ type FkeyAndIdxs struct {
 	ForeignKey map[string]string
 	Index      map[string]string
 }
+
+// BadRow is a row which could not be converted, or could not be written to
+// Spanner, along with the reason.
+type BadRow struct {
+	Table  string   `json:"table"`
+	Cols   []string `json:"cols"`
+	Vals   []string `json:"vals"`
+	Reason string   `json:"reason"`
+}
+
+// DeadLetterWriter receives all bad rows of a data migration so that they can
+// be repaired and re-imported later. Implementations must be safe for
+// concurrent use, since rows failing to be written to Spanner are reported
+// from the writer goroutines.
+type DeadLetterWriter interface {
+	Write(r BadRow) error
+	Close() error
+}
+
+// ExportWriter receives the converted rows of a data migration exported to
+// files instead of being written to Spanner. Implementations must be safe for
+// concurrent use, since tables can be read by several workers.
+type ExportWriter interface {
+	Write(spTable string, spCols []string, spVals []interface{}) error
+	Close() error
+}
+
 type rowSamples struct {
 	rows       []*row
 	bytes      int64 // Bytes consumed by l.
@@ -199,12 +319,16 @@ type row struct {
 // c) successfully converted, but an error occurs when writing the row to Spanner.
 // d) unsuccessfully converted (we won't try to write such rows to Spanner).
 type stats struct {
-	Rows       map[string]int64          // Count of rows encountered during processing (a + b + c + d), broken down by source table.
-	GoodRows   map[string]int64          // Count of rows successfully converted (b + c), broken down by source table.
-	BadRows    map[string]int64          // Count of rows where conversion failed (d), broken down by source table.
-	Statement  map[string]*statementStat // Count of processed statements, broken down by statement type.
-	Unexpected map[string]int64          // Count of unexpected conditions, broken down by condition description.
-	Reparsed   int64                     // Count of times we re-parse dump data looking for end-of-statement.
+	Rows              map[string]int64            // Count of rows encountered during processing (a + b + c + d), broken down by source table.
+	GoodRows          map[string]int64            // Count of rows successfully converted (b + c), broken down by source table.
+	BadRows           map[string]int64            // Count of rows where conversion failed (d), broken down by source table.
+	Statement         map[string]*statementStat   // Count of processed statements, broken down by statement type.
+	Unexpected        map[string]int64            // Count of unexpected conditions, broken down by condition description.
+	Reparsed          int64                       // Count of times we re-parse dump data looking for end-of-statement.
+	ShiftedTimestamps map[string]int64            // Count of values without time zone shifted to a non-UTC time zone, broken down by source table.
+	RoundedNumerics   map[string]map[string]int64 // Count of values rounded when written to NUMERIC columns, broken down by source table and column.
+	CompletedTables   map[string]bool             // Source tables whose rows were all read and written, in data mode.
+	SampledOutRows    map[string]int64            // Count of rows left out of a sampled data migration, broken down by source table.
 }
 
 type statementStat struct {
@@ -225,6 +349,17 @@ type Audit struct {
 	StreamingStats           streamingStats                         `json:"-"` // Stores information related to streaming migration process.
 	Progress                 Progress                               `json:"-"` // Stores information related to progress of the migration progress
 	SkipMetricsPopulation    bool                                   `json:"-"` // Flag to identify if outgoing metrics metadata needs to skipped
+	SnapshotPosition         string                                 `json:"-"` // Source GTID set or WAL LSN of the consistent snapshot data was read from.
+	SchemaEdits              []SchemaEdit                           `json:"-"` // Schema edits made through the web UI, in order.
+}
+
+// SchemaEdit records a change made to the schema through the web UI.
+type SchemaEdit struct {
+	Timestamp time.Time `json:"timestamp"`
+	User      string    `json:"user,omitempty"` // Email of the user, when authentication is enabled.
+	Method    string    `json:"method"`
+	Endpoint  string    `json:"endpoint"`
+	Payload   string    `json:"payload,omitempty"` // Request payload, truncated.
 }
 
 // Stores information related to generated Dataflow Resources.
@@ -363,6 +498,7 @@ func MakeConv() *Conv {
 		UsedNames:      make(map[string]bool),
 		Location:       time.Local, // By default, use go's local time, which uses $TZ (when set).
 		sampleBadRows:  rowSamples{bytesLimit: 10 * 1000 * 1000},
+		masking:        newMaskState(),
 		Stats: stats{
 			Rows:       make(map[string]int64),
 			GoodRows:   make(map[string]int64),
@@ -380,6 +516,9 @@ func MakeConv() *Conv {
 		SpSequences:     make(map[string]ddl.Sequence),
 		SrcSequences:    make(map[string]ddl.Sequence),
 		DatabaseOptions: ddl.DatabaseOptions{},
+		SpRoles:         make(map[string]ddl.CreateRole),
+		SpViews:         make(map[string]ddl.CreateView),
+		SrcRowCounts:    make(map[string]int64),
 	}
 }
 
@@ -420,9 +559,64 @@ func (conv *Conv) SetDataMode() {
 	conv.mode = dataOnly
 }
 
+var roleNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{0,127}$`)
+
+// commitTimestamp is the type of CommitTimestamp.
+type commitTimestamp struct{}
+
+// CommitTimestamp is a placeholder written to columns which should be set to
+// the Spanner commit timestamp. Data sinks must replace it with
+// PENDING_COMMIT_TIMESTAMP().
+var CommitTimestamp = commitTimestamp{}
+
 // WriteRow calls dataSink and updates row stats.
 func (conv *Conv) WriteRow(srcTable, spTable string, spCols []string, spVals []interface{}) {
-	if conv.Audit.DryRun {
+	spCols, spVals = conv.setCommitTimestamps(spTable, spCols, spVals)
+	// Masking comes first so that fill expressions only see masked values.
+	spVals, shuffled, err := conv.maskColumns(spTable, spCols, spVals)
+	if err != nil {
+		conv.Unexpected(err.Error())
+		conv.StatsAddBadRow(srcTable, conv.DataMode())
+		conv.CollectBadWrite(spTable, spCols, spVals, err)
+		return
+	}
+	if len(shuffled) > 0 {
+		// The row is written once its shuffled values are exchanged with
+		// the ones of other rows.
+		for _, row := range conv.masking.shuffle(maskedRow{srcTable, spTable, spCols, spVals}, shuffled) {
+			conv.writeMaskedRow(row.srcTable, row.spTable, row.spCols, row.spVals)
+		}
+		return
+	}
+	conv.writeMaskedRow(srcTable, spTable, spCols, spVals)
+}
+
+// writeMaskedRow fills the columns of a row of WriteRow whose columns are
+// masked, and writes it.
+func (conv *Conv) writeMaskedRow(srcTable, spTable string, spCols []string, spVals []interface{}) {
+	spCols, spVals, err := conv.fillColumns(spTable, spCols, spVals)
+	if err != nil {
+		conv.Unexpected(err.Error())
+		conv.StatsAddBadRow(srcTable, conv.DataMode())
+		conv.CollectBadWrite(spTable, spCols, spVals, err)
+		return
+	}
+	// Rows are sampled on the values written, which the rows referencing
+	// them are masked alike, and which failed writes are reported with.
+	if !conv.sampleRow(spTable, spCols, spVals) {
+		conv.statsAddSampledOutRow(srcTable, conv.DataMode())
+		return
+	}
+	if conv.Export != nil {
+		if err := conv.Export.Write(spTable, spCols, spVals); err != nil {
+			conv.Unexpected(err.Error())
+			conv.StatsAddBadRow(srcTable, conv.DataMode())
+			conv.CollectBadWrite(spTable, spCols, spVals, err)
+			return
+		}
+		conv.statsAddGoodRow(srcTable, conv.DataMode())
+		conv.Status.AddWrittenRows(spTable, 1)
+	} else if conv.Audit.DryRun {
 		conv.statsAddGoodRow(srcTable, conv.DataMode())
 	} else if conv.dataSink == nil {
 		msg := "Internal error: ProcessDataRow called but dataSink not configured"
@@ -437,6 +631,60 @@ func (conv *Conv) WriteRow(srcTable, spTable string, spCols []string, spVals []i
 	}
 }
 
+// setCommitTimestamps replaces the values of commit timestamp columns of
+// spTable with CommitTimestamp when they are NULL (i.e. missing from spCols)
+// or equal to the configured sentinel timestamp.
+func (conv *Conv) setCommitTimestamps(spTable string, spCols []string, spVals []interface{}) ([]string, []interface{}) {
+	if len(conv.CommitTimestampCols) == 0 {
+		return spCols, spVals
+	}
+	tableId, err := GetTableIdFromSpName(conv.SpSchema, spTable)
+	if err != nil {
+		return spCols, spVals
+	}
+	cols := conv.CommitTimestampCols[tableId]
+	if len(cols) == 0 {
+		return spCols, spVals
+	}
+	newCols := append([]string{}, spCols...)
+	newVals := append([]interface{}{}, spVals...)
+	for colId, sentinel := range cols {
+		colDef, ok := conv.SpSchema[tableId].ColDefs[colId]
+		if !ok || !colDef.AllowsCommitTimestamp() {
+			continue
+		}
+		i := slices.Index(newCols, colDef.Name)
+		if i == -1 {
+			newCols = append(newCols, colDef.Name)
+			newVals = append(newVals, CommitTimestamp)
+			continue
+		}
+		if newVals[i] == nil || isCommitTimestampSentinel(newVals[i], sentinel) {
+			newVals[i] = CommitTimestamp
+		}
+	}
+	return newCols, newVals
+}
+
+// isCommitTimestampSentinel returns true if the converted value v matches the
+// sentinel timestamp, given in RFC3339 format.
+func isCommitTimestampSentinel(v interface{}, sentinel string) bool {
+	if sentinel == "" {
+		return false
+	}
+	s, err := time.Parse(time.RFC3339Nano, sentinel)
+	if err != nil {
+		return false
+	}
+	switch t := v.(type) {
+	case time.Time:
+		return t.Equal(s)
+	case *time.Time:
+		return t != nil && t.Equal(s)
+	}
+	return false
+}
+
 // Rows returns the total count of data rows processed.
 func (conv *Conv) Rows() int64 {
 	n := int64(0)
@@ -483,6 +731,13 @@ func (conv *Conv) Unexpecteds() int64 {
 // CollectBadRow updates the list of bad rows, while respecting
 // the byte limit for bad rows.
 func (conv *Conv) CollectBadRow(srcTable string, srcCols, vals []string) {
+	conv.CollectBadRowWithReason(srcTable, srcCols, vals, "")
+}
+
+// CollectBadRowWithReason is like CollectBadRow, and additionally records
+// why the row could not be converted when writing it to the dead-letter sink.
+func (conv *Conv) CollectBadRowWithReason(srcTable string, srcCols, vals []string, reason string) {
+	conv.WriteDeadLetter(BadRow{Table: srcTable, Cols: srcCols, Vals: vals, Reason: reason})
 	r := &row{table: srcTable, cols: srcCols, vals: vals}
 	bytes := byteSize(r)
 	// Cap storage used by badRows. Keep at least one bad row.
@@ -492,6 +747,56 @@ func (conv *Conv) CollectBadRow(srcTable string, srcCols, vals []string) {
 	}
 }
 
+// ExportFailed records that a row of spTable accepted by conv.Export couldn't
+// be exported after all, e.g. because the batch holding it was rejected: the
+// row is counted bad instead of good, and collected as a bad write.
+func (conv *Conv) ExportFailed(spTable string, spCols []string, spVals []interface{}, err error) {
+	srcTable := spTable
+	if tableId, e := GetTableIdFromSpName(conv.SpSchema, spTable); e == nil {
+		if t, ok := conv.SrcSchema[tableId]; ok {
+			srcTable = t.Name
+		}
+	}
+	if conv.DataMode() {
+		conv.Stats.GoodRows[srcTable]--
+	}
+	conv.StatsAddBadRow(srcTable, conv.DataMode())
+	conv.Status.AddDroppedRow(spTable)
+	conv.CollectBadWrite(spTable, spCols, spVals, err)
+}
+
+// CollectBadWrite writes a row which could not be written to Spanner to the
+// dead-letter sink, if one is configured, and leaves the rows referencing it
+// out of a sampled data migration. It is safe for concurrent use.
+func (conv *Conv) CollectBadWrite(spTable string, spCols []string, vals []interface{}, err error) {
+	conv.failedWrites.add(spTable)
+	conv.unsampleRow(spTable, spCols, vals)
+	if conv.DeadLetter == nil {
+		return
+	}
+	var strVals []string
+	for _, v := range vals {
+		strVals = append(strVals, fmt.Sprintf("%v", v))
+	}
+	reason := ""
+	if err != nil {
+		reason = err.Error()
+	}
+	conv.WriteDeadLetter(BadRow{Table: spTable, Cols: spCols, Vals: strVals, Reason: reason})
+}
+
+// WriteDeadLetter writes r to the dead-letter sink, if one is configured.
+// Failures to write are logged but otherwise ignored, so that a broken sink
+// does not fail the migration.
+func (conv *Conv) WriteDeadLetter(r BadRow) {
+	if conv.DeadLetter == nil {
+		return
+	}
+	if err := conv.DeadLetter.Write(r); err != nil {
+		logger.Log.Warn(fmt.Sprintf("Couldn't write bad row of table %s to dead-letter output: %v", r.Table, err))
+	}
+}
+
 // SampleBadRows returns a string-formatted list of rows that generated errors.
 // Returns at most n rows.
 func (conv *Conv) SampleBadRows(n int) []string {
@@ -508,7 +813,7 @@ func (conv *Conv) SampleBadRows(n int) []string {
 func (conv *Conv) AddShardIdColumn() {
 	for t, ct := range conv.SpSchema {
 		if ct.ShardIdColumn == "" {
-			colName := conv.buildColumnNameWithBase(t, ShardIdColumn)
+			colName := conv.buildColumnNameWithBase(t, conv.NameTemplates.ShardIdColumnName(ct.Name))
 			columnId := GenerateColumnId()
 			ct.ColIds = append(ct.ColIds, columnId)
 			ct.ColDefs[columnId] = ddl.ColumnDef{Name: colName, Id: columnId, T: ddl.Type{Name: ddl.String, Len: 50}, NotNull: false, AutoGen: ddl.AutoGenCol{Name: "", GenerationType: ""}}
@@ -544,12 +849,18 @@ func (conv *Conv) AddPrimaryKeys() {
 				}
 			}
 			if !primaryKeyPopulated {
-				k := conv.buildColumnNameWithBase(t, SyntheticPrimaryKey)
+				k := conv.buildColumnNameWithBase(t, conv.NameTemplates.SyntheticPKeyName(ct.Name))
 				columnId := GenerateColumnId()
 				ct.ColIds = append(ct.ColIds, columnId)
-				ct.ColDefs[columnId] = ddl.ColumnDef{Name: k, Id: columnId, T: ddl.Type{Name: ddl.String, Len: 50}, AutoGen: ddl.AutoGenCol{Name: "", GenerationType: ""}}
+				synthPk := SyntheticPKey{ColId: columnId, Sequence: 0, Strategy: conv.SyntheticPKeyStrategy}
+				seqName := ""
+				if conv.SyntheticPKeyStrategy == constants.SYNTH_PK_BIT_REVERSED_SEQUENCE {
+					seq := conv.addSyntheticPKeySequence(t, ct.Name, columnId, k)
+					synthPk.SequenceId, seqName = seq.Id, seq.Name
+				}
+				ct.ColDefs[columnId] = syntheticPKeyColumnDef(k, columnId, conv.SyntheticPKeyStrategy, seqName)
 				ct.PrimaryKeys = []ddl.IndexKey{{ColId: columnId, Order: 1}}
-				conv.SyntheticPKeys[t] = SyntheticPKey{columnId, 0}
+				conv.SyntheticPKeys[t] = synthPk
 				addMissingPrimaryKeyWarning(ct.Id, columnId, conv, MissingPrimaryKey)
 			}
 			conv.SpSchema[t] = ct
@@ -557,6 +868,381 @@ func (conv *Conv) AddPrimaryKeys() {
 	}
 }
 
+// IsValidSyntheticPKeyStrategy returns true if strategy can be used to
+// generate a synthetic primary key column. The composite strategy is not
+// included since it reuses existing columns instead of adding a new one.
+func IsValidSyntheticPKeyStrategy(strategy string) bool {
+	switch strategy {
+	case "", constants.SYNTH_PK_STRING_SEQUENCE, constants.SYNTH_PK_UUID, constants.SYNTH_PK_BIT_REVERSED_SEQUENCE:
+		return true
+	default:
+		return false
+	}
+}
+
+// syntheticPKeyColumnDef returns the Spanner column definition of a synthetic
+// primary key column generated using strategy. Values of the
+// bit_reversed_sequence strategy default to the next value of sequence seqName.
+func syntheticPKeyColumnDef(name, colId, strategy, seqName string) ddl.ColumnDef {
+	switch strategy {
+	case constants.SYNTH_PK_UUID:
+		return ddl.ColumnDef{Name: name, Id: colId, T: ddl.Type{Name: ddl.String, Len: 36}, AutoGen: ddl.AutoGenCol{Name: constants.UUID, GenerationType: "Pre-defined"}}
+	case constants.SYNTH_PK_BIT_REVERSED_SEQUENCE:
+		return ddl.ColumnDef{Name: name, Id: colId, T: ddl.Type{Name: ddl.Int64}, AutoGen: ddl.AutoGenCol{Name: seqName, GenerationType: constants.SEQUENCE}}
+	default:
+		return ddl.ColumnDef{Name: name, Id: colId, T: ddl.Type{Name: ddl.String, Len: 50}, AutoGen: ddl.AutoGenCol{Name: "", GenerationType: ""}}
+	}
+}
+
+// SetSyntheticPKeyStrategy changes the strategy used to generate the synthetic
+// primary key of tableId, updating the type of the synthetic column accordingly.
+func (conv *Conv) SetSyntheticPKeyStrategy(tableId, strategy string) error {
+	if !IsValidSyntheticPKeyStrategy(strategy) {
+		return fmt.Errorf("invalid synthetic primary key strategy: %s", strategy)
+	}
+	synthPk, ok := conv.SyntheticPKeys[tableId]
+	if !ok {
+		return fmt.Errorf("table %s doesn't have a synthetic primary key", tableId)
+	}
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return fmt.Errorf("table doesn't exist for tableId %s", tableId)
+	}
+	conv.DropSyntheticPKeySequence(tableId)
+	synthPk = conv.SyntheticPKeys[tableId]
+	colName := ct.ColDefs[synthPk.ColId].Name
+	seqName := ""
+	if strategy == constants.SYNTH_PK_BIT_REVERSED_SEQUENCE {
+		seq := conv.addSyntheticPKeySequence(tableId, ct.Name, synthPk.ColId, colName)
+		synthPk.SequenceId, seqName = seq.Id, seq.Name
+	}
+	ct.ColDefs[synthPk.ColId] = syntheticPKeyColumnDef(colName, synthPk.ColId, strategy, seqName)
+	conv.SpSchema[tableId] = ct
+	synthPk.Strategy = strategy
+	conv.SyntheticPKeys[tableId] = synthPk
+	return nil
+}
+
+// addSyntheticPKeySequence adds the bit-reversed sequence generating the
+// values of the synthetic primary key column colId, named colName, of table
+// tableId.
+func (conv *Conv) addSyntheticPKeySequence(tableId, tableName, colId, colName string) ddl.Sequence {
+	seq := ddl.Sequence{
+		Id:              GenerateSequenceId(),
+		Name:            conv.NameTemplates.SequenceName(tableName, colName),
+		SequenceKind:    "BIT REVERSED POSITIVE",
+		ColumnsUsingSeq: map[string][]string{tableId: {colId}},
+	}
+	if conv.SpSequences == nil {
+		conv.SpSequences = make(map[string]ddl.Sequence)
+	}
+	conv.SpSequences[seq.Id] = seq
+	return seq
+}
+
+// DropSyntheticPKeySequence drops the sequence generating the synthetic
+// primary key values of table tableId, if any.
+func (conv *Conv) DropSyntheticPKeySequence(tableId string) {
+	synthPk, ok := conv.SyntheticPKeys[tableId]
+	if !ok || synthPk.SequenceId == "" {
+		return
+	}
+	delete(conv.SpSequences, synthPk.SequenceId)
+	synthPk.SequenceId = ""
+	conv.SyntheticPKeys[tableId] = synthPk
+}
+
+// NextSyntheticPKeyValue returns the Spanner column name and the next value of
+// the synthetic primary key of tableId. It returns false if the table doesn't
+// use a synthetic primary key, or if its values are generated by Spanner: the
+// column of the bit_reversed_sequence strategy defaults to the next value of
+// its sequence, for the migrated rows as for the rows inserted later.
+func (conv *Conv) NextSyntheticPKeyValue(tableId string) (string, interface{}, bool) {
+	synthPk, ok := conv.SyntheticPKeys[tableId]
+	if !ok || synthPk.Strategy == constants.SYNTH_PK_BIT_REVERSED_SEQUENCE {
+		return "", nil, false
+	}
+	var val interface{}
+	switch synthPk.Strategy {
+	case constants.SYNTH_PK_UUID:
+		val = uuid.New().String()
+	default:
+		val = fmt.Sprintf("%d", int64(bits.Reverse64(uint64(synthPk.Sequence))))
+	}
+	synthPk.Sequence++
+	conv.SyntheticPKeys[tableId] = synthPk
+	return conv.SpSchema[tableId].ColDefs[synthPk.ColId].Name, val, true
+}
+
+// SetRowDeletionPolicy sets the row deletion policy (TTL) of the Spanner
+// table tableId. The policy column must be a TIMESTAMP column of the table
+// and the interval must be a positive number of days. A policy with an
+// empty ColId removes any existing row deletion policy.
+func (conv *Conv) SetRowDeletionPolicy(tableId string, policy ddl.RowDeletionPolicy) error {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return fmt.Errorf("table doesn't exist for tableId %s", tableId)
+	}
+	if policy.ColId != "" {
+		col, ok := ct.ColDefs[policy.ColId]
+		if !ok {
+			return fmt.Errorf("column %s doesn't exist in table %s", policy.ColId, ct.Name)
+		}
+		if col.T.Name != ddl.Timestamp || col.T.IsArray {
+			return fmt.Errorf("row deletion policy column %s must be of type %s", col.Name, ddl.Timestamp)
+		}
+		if policy.NumDays <= 0 {
+			return fmt.Errorf("row deletion policy interval must be a positive number of days, got %d", policy.NumDays)
+		}
+	}
+	ct.RowDeletionPolicy = policy
+	conv.SpSchema[tableId] = ct
+	return nil
+}
+
+// SetVectorIndex adds the vector index to the Spanner table tableId, or
+// replaces the vector index of the table with the same Id. The indexed column
+// must be an array of FLOAT32 or FLOAT64 with a vector length, and stored
+// columns must be other columns of the table. An index without Id gets a new
+// one.
+func (conv *Conv) SetVectorIndex(tableId string, index ddl.VectorIndex) error {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return fmt.Errorf("table doesn't exist for tableId %s", tableId)
+	}
+	pos := -1
+	for i, vi := range ct.VectorIndexes {
+		if index.Id != "" && vi.Id == index.Id {
+			pos = i
+		}
+	}
+	if _, changed := FixName(index.Name); changed || index.Name == "" {
+		return fmt.Errorf("%s is not a valid Spanner identifier", index.Name)
+	}
+	if conv.UsedNames[strings.ToLower(index.Name)] && (pos == -1 || !strings.EqualFold(ct.VectorIndexes[pos].Name, index.Name)) {
+		return fmt.Errorf("name %s is already used by a table, index or foreign key", index.Name)
+	}
+	col, ok := ct.ColDefs[index.ColId]
+	if !ok {
+		return fmt.Errorf("column %s doesn't exist in table %s", index.ColId, ct.Name)
+	}
+	if !isVectorType(col.T) {
+		return fmt.Errorf("vector index column %s must be an array of %s or %s with a vector length", col.Name, ddl.Float32, ddl.Float64)
+	}
+	switch index.DistanceType {
+	case ddl.CosineDistance, ddl.EuclideanDistance, ddl.DotProduct:
+	default:
+		return fmt.Errorf("unknown distance type %q, must be one of %s, %s or %s", index.DistanceType, ddl.CosineDistance, ddl.EuclideanDistance, ddl.DotProduct)
+	}
+	if index.TreeDepth != 0 && index.TreeDepth != 2 && index.TreeDepth != 3 {
+		return fmt.Errorf("vector index tree depth must be 2 or 3, got %d", index.TreeDepth)
+	}
+	if index.NumLeaves < 0 || index.NumBranches < 0 {
+		return fmt.Errorf("vector index number of leaves and branches can't be negative")
+	}
+	if index.NumBranches > 0 && index.TreeDepth != 3 {
+		return fmt.Errorf("vector index number of branches is only used by trees of depth 3")
+	}
+	for _, colId := range index.StoredColumnIds {
+		if _, ok := ct.ColDefs[colId]; !ok || colId == index.ColId {
+			return fmt.Errorf("stored column %s must be another column of table %s", colId, ct.Name)
+		}
+	}
+	if pos == -1 {
+		if index.Id == "" {
+			index.Id = GenerateIndexesId()
+		}
+		ct.VectorIndexes = append(ct.VectorIndexes, index)
+	} else {
+		delete(conv.UsedNames, strings.ToLower(ct.VectorIndexes[pos].Name))
+		ct.VectorIndexes[pos] = index
+	}
+	conv.UsedNames[strings.ToLower(index.Name)] = true
+	conv.SpSchema[tableId] = ct
+	return nil
+}
+
+// RemoveInvalidVectorIndexes removes the vector indexes of the Spanner table
+// tableId whose column is no longer an array of FLOAT32 or FLOAT64 with a
+// vector length, e.g. after a change of its type.
+func (conv *Conv) RemoveInvalidVectorIndexes(tableId string) {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return
+	}
+	var vectorIndexes []ddl.VectorIndex
+	for _, vi := range ct.VectorIndexes {
+		if col, ok := ct.ColDefs[vi.ColId]; ok && isVectorType(col.T) {
+			vectorIndexes = append(vectorIndexes, vi)
+			continue
+		}
+		delete(conv.UsedNames, strings.ToLower(vi.Name))
+	}
+	if len(vectorIndexes) != len(ct.VectorIndexes) {
+		ct.VectorIndexes = vectorIndexes
+		conv.SpSchema[tableId] = ct
+	}
+}
+
+// isVectorType reports whether columns of type t can be indexed by a vector
+// index.
+func isVectorType(t ddl.Type) bool {
+	return t.IsArray && (t.Name == ddl.Float32 || t.Name == ddl.Float64) && t.VectorLength > 0
+}
+
+// RemoveVectorIndex removes the vector index indexId of the Spanner table
+// tableId.
+func (conv *Conv) RemoveVectorIndex(tableId, indexId string) error {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return fmt.Errorf("table doesn't exist for tableId %s", tableId)
+	}
+	for i, vi := range ct.VectorIndexes {
+		if vi.Id == indexId {
+			delete(conv.UsedNames, strings.ToLower(vi.Name))
+			ct.VectorIndexes = append(ct.VectorIndexes[:i:i], ct.VectorIndexes[i+1:]...)
+			conv.SpSchema[tableId] = ct
+			return nil
+		}
+	}
+	return fmt.Errorf("vector index doesn't exist for indexId %s", indexId)
+}
+
+// SetCommitTimestamp marks the TIMESTAMP column colId of the Spanner table
+// tableId as accepting the commit timestamp (allow_commit_timestamp=true), or
+// unmarks it if enabled is false. During data migration, NULL source values
+// and values equal to sentinel (an RFC3339 timestamp, optional) are written
+// as PENDING_COMMIT_TIMESTAMP().
+func (conv *Conv) SetCommitTimestamp(tableId, colId string, enabled bool, sentinel string) error {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return fmt.Errorf("table doesn't exist for tableId %s", tableId)
+	}
+	col, ok := ct.ColDefs[colId]
+	if !ok {
+		return fmt.Errorf("column %s doesn't exist in table %s", colId, ct.Name)
+	}
+	if !enabled {
+		delete(col.Opts, ddl.AllowCommitTimestampOpt)
+		delete(conv.CommitTimestampCols[tableId], colId)
+		ct.ColDefs[colId] = col
+		return nil
+	}
+	if col.T.Name != ddl.Timestamp || col.T.IsArray {
+		return fmt.Errorf("commit timestamp column %s must be of type %s", col.Name, ddl.Timestamp)
+	}
+	if sentinel != "" {
+		ts, err := time.Parse(time.RFC3339Nano, sentinel)
+		if err != nil {
+			return fmt.Errorf("commit timestamp sentinel %q must be an RFC3339 timestamp: %v", sentinel, err)
+		}
+		sentinel = ts.UTC().Format(time.RFC3339Nano)
+	}
+	if col.Opts == nil {
+		col.Opts = make(map[string]string)
+	}
+	col.Opts[ddl.AllowCommitTimestampOpt] = "true"
+	ct.ColDefs[colId] = col
+	if conv.CommitTimestampCols == nil {
+		conv.CommitTimestampCols = make(map[string]map[string]string)
+	}
+	if conv.CommitTimestampCols[tableId] == nil {
+		conv.CommitTimestampCols[tableId] = make(map[string]string)
+	}
+	conv.CommitTimestampCols[tableId][colId] = sentinel
+	return nil
+}
+
+// AddRole adds a Spanner database role for fine-grained access control and
+// returns its id.
+func (conv *Conv) AddRole(name string) (string, error) {
+	if !roleNameRegex.MatchString(name) || strings.EqualFold(name, "public") || strings.HasPrefix(strings.ToLower(name), "spanner_") {
+		return "", fmt.Errorf("invalid role name %s: role names must start with a letter, contain only letters, digits and underscores, and not be public or start with spanner_", name)
+	}
+	for _, r := range conv.SpRoles {
+		if strings.EqualFold(r.Name, name) {
+			return "", fmt.Errorf("role %s already exists", name)
+		}
+	}
+	if conv.SpRoles == nil {
+		conv.SpRoles = make(map[string]ddl.CreateRole)
+	}
+	id := GenerateRoleId()
+	conv.SpRoles[id] = ddl.CreateRole{Id: id, Name: name}
+	return id, nil
+}
+
+// AddGrant adds a grant of table privileges, or of a role, to Spanner roles.
+func (conv *Conv) AddGrant(g ddl.Grant) error {
+	if len(g.GranteeIds) == 0 {
+		return fmt.Errorf("grant has no grantees")
+	}
+	for _, id := range g.GranteeIds {
+		if _, ok := conv.SpRoles[id]; !ok {
+			return fmt.Errorf("role doesn't exist for roleId %s", id)
+		}
+	}
+	if g.RoleId != "" {
+		if _, ok := conv.SpRoles[g.RoleId]; !ok {
+			return fmt.Errorf("role doesn't exist for roleId %s", g.RoleId)
+		}
+		if g.TableId != "" || len(g.Privileges) > 0 {
+			return fmt.Errorf("a grant can't grant both a role and table privileges")
+		}
+		if slices.Contains(g.GranteeIds, g.RoleId) {
+			return fmt.Errorf("role %s can't be granted to itself", conv.SpRoles[g.RoleId].Name)
+		}
+		conv.SpGrants = append(conv.SpGrants, g)
+		return nil
+	}
+	ct, ok := conv.SpSchema[g.TableId]
+	if !ok {
+		return fmt.Errorf("table doesn't exist for tableId %s", g.TableId)
+	}
+	if len(g.Privileges) == 0 {
+		return fmt.Errorf("grant on table %s has no privileges", ct.Name)
+	}
+	for _, p := range g.Privileges {
+		switch p {
+		case "SELECT", "INSERT", "UPDATE":
+		case "DELETE":
+			if len(g.ColIds) > 0 {
+				return fmt.Errorf("DELETE can't be granted on columns of table %s", ct.Name)
+			}
+		default:
+			return fmt.Errorf("unsupported privilege %s, must be one of SELECT, INSERT, UPDATE or DELETE", p)
+		}
+	}
+	for _, colId := range g.ColIds {
+		if _, ok := ct.ColDefs[colId]; !ok {
+			return fmt.Errorf("column %s doesn't exist in table %s", colId, ct.Name)
+		}
+	}
+	conv.SpGrants = append(conv.SpGrants, g)
+	return nil
+}
+
+// DropRole removes the Spanner role roleId, along with grants of the role
+// and grants to it.
+func (conv *Conv) DropRole(roleId string) error {
+	if _, ok := conv.SpRoles[roleId]; !ok {
+		return fmt.Errorf("role doesn't exist for roleId %s", roleId)
+	}
+	delete(conv.SpRoles, roleId)
+	var grants []ddl.Grant
+	for _, g := range conv.SpGrants {
+		if g.RoleId == roleId {
+			continue
+		}
+		g.GranteeIds = slices.DeleteFunc(slices.Clone(g.GranteeIds), func(id string) bool { return id == roleId })
+		if len(g.GranteeIds) > 0 {
+			grants = append(grants, g)
+		}
+	}
+	conv.SpGrants = grants
+	return nil
+}
+
 // Add 'Missing Primary Key' as a Warning inside ColumnLevelIssues of conv object
 func addMissingPrimaryKeyWarning(tableId string, colId string, conv *Conv, schemaIssue SchemaIssue) {
 	tableLevelIssues := conv.SchemaIssues[tableId].TableLevelIssues
@@ -617,6 +1303,25 @@ func (conv *Conv) Unexpected(u string) {
 	}
 }
 
+// AddDdlRejection records that the Spanner emulator rejected DDL statement
+// stmt of table tableId with error err. Statements which don't belong to a
+// table, e.g. sequences, are recorded as unexpected conditions.
+func (conv *Conv) AddDdlRejection(tableId, stmt string, err error) {
+	if _, ok := conv.SpSchema[tableId]; !ok {
+		conv.Unexpected(fmt.Sprintf("Spanner emulator rejected statement %q: %v", stmt, err))
+		return
+	}
+	if conv.DdlRejections == nil {
+		conv.DdlRejections = make(map[string][]DdlRejection)
+	}
+	conv.DdlRejections[tableId] = append(conv.DdlRejections[tableId], DdlRejection{Statement: stmt, Error: err.Error()})
+	tableIssues := conv.SchemaIssues[tableId]
+	if !Contains(tableIssues.TableLevelIssues, DdlRejected) {
+		tableIssues.TableLevelIssues = append(tableIssues.TableLevelIssues, DdlRejected)
+	}
+	conv.SchemaIssues[tableId] = tableIssues
+}
+
 // StatsAddRow increments the count of rows for 'srcTable' if b is
 // true.  The boolean arg 'b' is used to avoid double counting of
 // stats. Specifically, some code paths that report row stats run in
@@ -639,11 +1344,23 @@ func (conv *Conv) statsAddGoodRow(srcTable string, b bool) {
 	}
 }
 
+// statsAddSampledOutRow increments the count of rows of 'srcTable' left
+// out of a sampled data migration if b is true.
+func (conv *Conv) statsAddSampledOutRow(srcTable string, b bool) {
+	if b {
+		if conv.Stats.SampledOutRows == nil {
+			conv.Stats.SampledOutRows = make(map[string]int64)
+		}
+		conv.Stats.SampledOutRows[srcTable]++
+	}
+}
+
 // StatsAddBadRow increments the bad-row stats for 'srcTable' if b is
 // true.  See StatsAddRow comments for context.
 func (conv *Conv) StatsAddBadRow(srcTable string, b bool) {
 	if b {
 		conv.Stats.BadRows[srcTable]++
+		conv.Status.AddBadRow(srcTable)
 	}
 }
 
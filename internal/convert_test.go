@@ -15,12 +15,16 @@
 package internal
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 )
 
@@ -79,6 +83,20 @@ func TestUnexpecteds(t *testing.T) {
 	assert.Equal(t, int64(1), conv.Unexpecteds())
 }
 
+func TestAddDdlRejection(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema["t1"] = ddl.CreateTable{Name: "users", Id: "t1"}
+	conv.AddDdlRejection("t1", "CREATE INDEX a ON users (x)", fmt.Errorf("column not found"))
+	conv.AddDdlRejection("t1", "CREATE INDEX b ON users (y)", fmt.Errorf("column not found"))
+	conv.AddDdlRejection("", "CREATE SEQUENCE s", fmt.Errorf("invalid option"))
+	assert.Equal(t, []DdlRejection{
+		{Statement: "CREATE INDEX a ON users (x)", Error: "column not found"},
+		{Statement: "CREATE INDEX b ON users (y)", Error: "column not found"},
+	}, conv.DdlRejections["t1"])
+	assert.Equal(t, []SchemaIssue{DdlRejected}, conv.SchemaIssues["t1"].TableLevelIssues)
+	assert.Equal(t, int64(1), conv.Unexpecteds())
+}
+
 func TestGetBadRows(t *testing.T) {
 	conv := MakeConv()
 	row1 := row{"table", []string{"col1", "col2"}, []string{"a", "1"}}
@@ -87,6 +105,96 @@ func TestGetBadRows(t *testing.T) {
 	assert.Equal(t, 2, len(conv.SampleBadRows(100)))
 }
 
+type deadLetterMock struct {
+	rows []BadRow
+}
+
+func (d *deadLetterMock) Write(r BadRow) error {
+	d.rows = append(d.rows, r)
+	return nil
+}
+
+func (d *deadLetterMock) Close() error {
+	return nil
+}
+
+func TestDeadLetter(t *testing.T) {
+	conv := MakeConv()
+	// Without a dead-letter sink, bad rows are only sampled.
+	conv.CollectBadRow("table", []string{"col1"}, []string{"a"})
+	dl := &deadLetterMock{}
+	conv.DeadLetter = dl
+	conv.CollectBadRowWithReason("table", []string{"col1", "col2"}, []string{"b", "x"}, "can't convert x")
+	conv.CollectBadWrite("sp_table", []string{"col1", "col2"}, []interface{}{"c", int64(1)}, fmt.Errorf("already exists"))
+	assert.Equal(t, []BadRow{
+		{Table: "table", Cols: []string{"col1", "col2"}, Vals: []string{"b", "x"}, Reason: "can't convert x"},
+		{Table: "sp_table", Cols: []string{"col1", "col2"}, Vals: []string{"c", "1"}, Reason: "already exists"},
+	}, dl.rows)
+	assert.Equal(t, 2, len(conv.SampleBadRows(100)))
+}
+
+type exportMock struct {
+	rows int
+}
+
+func (e *exportMock) Write(spTable string, spCols []string, spVals []interface{}) error {
+	e.rows++
+	return nil
+}
+
+func (e *exportMock) Close() error {
+	return nil
+}
+
+func TestExportFailed(t *testing.T) {
+	conv := MakeConv()
+	conv.SrcSchema = map[string]schema.Table{"t1": {Name: "src", Id: "t1"}}
+	conv.SpSchema = ddl.Schema{"t1": {Name: "table", Id: "t1"}}
+	conv.SetDataMode()
+	e := &exportMock{}
+	conv.Export = e
+	dl := &deadLetterMock{}
+	conv.DeadLetter = dl
+	conv.WriteRow("src", "table", []string{"a"}, []interface{}{int64(1)})
+	conv.WriteRow("src", "table", []string{"a"}, []interface{}{int64(2)})
+	assert.Equal(t, 2, e.rows)
+	assert.Equal(t, int64(2), conv.Stats.GoodRows["src"])
+	// A row accepted by the export but rejected later is counted bad.
+	conv.ExportFailed("table", []string{"a"}, []interface{}{int64(1)}, fmt.Errorf("insert failed"))
+	assert.Equal(t, int64(1), conv.Stats.GoodRows["src"])
+	assert.Equal(t, int64(1), conv.Stats.BadRows["src"])
+	assert.Equal(t, []BadRow{{Table: "table", Cols: []string{"a"}, Vals: []string{"1"}, Reason: "insert failed"}}, dl.rows)
+}
+
+func TestWriteRowCommitTimestamp(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			Name:   "table",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "a", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Name: "updated", Id: "c2", T: ddl.Type{Name: ddl.Timestamp}, Opts: map[string]string{ddl.AllowCommitTimestampOpt: "true"}},
+				"c3": {Name: "created", Id: "c3", T: ddl.Type{Name: ddl.Timestamp}, Opts: map[string]string{ddl.AllowCommitTimestampOpt: "true"}},
+			},
+		},
+	}
+	conv.CommitTimestampCols = map[string]map[string]string{"t1": {"c2": "", "c3": "1970-01-01T00:00:00Z"}}
+	var cols []string
+	var vals []interface{}
+	conv.SetDataSink(func(table string, c []string, v []interface{}) {
+		cols, vals = c, v
+	})
+	ts := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Missing (NULL) and sentinel values are replaced, other values are kept.
+	conv.WriteRow("src", "table", []string{"a", "created"}, []interface{}{int64(1), time.Unix(0, 0)})
+	assert.Equal(t, []string{"a", "created", "updated"}, cols)
+	assert.Equal(t, []interface{}{int64(1), CommitTimestamp, CommitTimestamp}, vals)
+	conv.WriteRow("src", "table", []string{"a", "updated", "created"}, []interface{}{int64(1), ts, ts})
+	assert.Equal(t, []interface{}{int64(1), ts, ts}, vals)
+}
+
 func TestAddPrimaryKeys(t *testing.T) {
 	addPrimaryKeyTests := []struct {
 		name           string
@@ -284,3 +392,237 @@ func TestAddPrimaryKeys(t *testing.T) {
 		}
 	}
 }
+
+func TestAddPrimaryKeysWithSyntheticPKeyStrategy(t *testing.T) {
+	tests := []struct {
+		name            string
+		strategy        string
+		expectedType    ddl.Type
+		expectedAutoGen ddl.AutoGenCol
+	}{
+		{
+			name:         "default strategy",
+			strategy:     "",
+			expectedType: ddl.Type{Name: ddl.String, Len: 50},
+		},
+		{
+			name:            "uuid strategy",
+			strategy:        constants.SYNTH_PK_UUID,
+			expectedType:    ddl.Type{Name: ddl.String, Len: 36},
+			expectedAutoGen: ddl.AutoGenCol{Name: constants.UUID, GenerationType: "Pre-defined"},
+		},
+		{
+			name:            "bit reversed sequence strategy",
+			strategy:        constants.SYNTH_PK_BIT_REVERSED_SEQUENCE,
+			expectedType:    ddl.Type{Name: ddl.Int64},
+			expectedAutoGen: ddl.AutoGenCol{Name: "Sequence_table_synth_id", GenerationType: constants.SEQUENCE},
+		},
+	}
+	for _, tc := range tests {
+		conv := MakeConv()
+		conv.SyntheticPKeyStrategy = tc.strategy
+		conv.SpSchema = map[string]ddl.CreateTable{
+			"t1": {
+				Name:    "table",
+				Id:      "t1",
+				ColIds:  []string{"c1"},
+				ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "a", Id: "c1", T: ddl.Type{Name: ddl.Int64}}},
+			},
+		}
+		conv.AddPrimaryKeys()
+		synthPk, ok := conv.SyntheticPKeys["t1"]
+		assert.True(t, ok, tc.name)
+		assert.Equal(t, tc.strategy, synthPk.Strategy, tc.name)
+		assert.Equal(t, tc.expectedType, conv.SpSchema["t1"].ColDefs[synthPk.ColId].T, tc.name)
+		assert.Equal(t, tc.expectedAutoGen, conv.SpSchema["t1"].ColDefs[synthPk.ColId].AutoGen, tc.name)
+		if tc.strategy == constants.SYNTH_PK_BIT_REVERSED_SEQUENCE {
+			seq := conv.SpSequences[synthPk.SequenceId]
+			assert.Equal(t, "Sequence_table_synth_id", seq.Name, tc.name)
+			assert.Equal(t, "BIT REVERSED POSITIVE", seq.SequenceKind, tc.name)
+			assert.Equal(t, map[string][]string{"t1": {synthPk.ColId}}, seq.ColumnsUsingSeq, tc.name)
+		} else {
+			assert.Empty(t, conv.SpSequences, tc.name)
+		}
+	}
+}
+
+func TestNextSyntheticPKeyValue(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema = map[string]ddl.CreateTable{
+		"t1": {
+			Name:    "table",
+			Id:      "t1",
+			ColIds:  []string{"c1"},
+			ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "synth_id", Id: "c1", T: ddl.Type{Name: ddl.String, Len: 50}}},
+		},
+	}
+	_, _, ok := conv.NextSyntheticPKeyValue("t1")
+	assert.False(t, ok)
+
+	conv.SyntheticPKeys["t1"] = SyntheticPKey{ColId: "c1", Sequence: 0}
+	col, val, ok := conv.NextSyntheticPKeyValue("t1")
+	assert.True(t, ok)
+	assert.Equal(t, "synth_id", col)
+	assert.Equal(t, "0", val)
+	_, val, _ = conv.NextSyntheticPKeyValue("t1")
+	assert.Equal(t, "-9223372036854775808", val)
+
+	// Values of the bit_reversed_sequence strategy are generated by Spanner.
+	assert.Nil(t, conv.SetSyntheticPKeyStrategy("t1", constants.SYNTH_PK_BIT_REVERSED_SEQUENCE))
+	assert.Equal(t, ddl.Type{Name: ddl.Int64}, conv.SpSchema["t1"].ColDefs["c1"].T)
+	assert.Equal(t, ddl.AutoGenCol{Name: "Sequence_table_synth_id", GenerationType: constants.SEQUENCE}, conv.SpSchema["t1"].ColDefs["c1"].AutoGen)
+	assert.Len(t, conv.SpSequences, 1)
+	_, _, ok = conv.NextSyntheticPKeyValue("t1")
+	assert.False(t, ok)
+
+	assert.Nil(t, conv.SetSyntheticPKeyStrategy("t1", constants.SYNTH_PK_UUID))
+	assert.Empty(t, conv.SpSequences)
+	assert.Empty(t, conv.SyntheticPKeys["t1"].SequenceId)
+	_, val, _ = conv.NextSyntheticPKeyValue("t1")
+	assert.Len(t, val, 36)
+
+	assert.NotNil(t, conv.SetSyntheticPKeyStrategy("t1", constants.SYNTH_PK_COMPOSITE))
+	assert.NotNil(t, conv.SetSyntheticPKeyStrategy("t2", constants.SYNTH_PK_UUID))
+}
+
+func TestSetRowDeletionPolicy(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Name:   "table1",
+		ColIds: []string{"c1", "c2", "c3"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+			"c2": {Name: "created_at", Id: "c2", T: ddl.Type{Name: ddl.Timestamp}},
+			"c3": {Name: "name", Id: "c3", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+		},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+		Id:          "t1",
+	}
+	assert.NotNil(t, conv.SetRowDeletionPolicy("t2", ddl.RowDeletionPolicy{ColId: "c2", NumDays: 30}))
+	assert.NotNil(t, conv.SetRowDeletionPolicy("t1", ddl.RowDeletionPolicy{ColId: "c4", NumDays: 30}))
+	assert.NotNil(t, conv.SetRowDeletionPolicy("t1", ddl.RowDeletionPolicy{ColId: "c3", NumDays: 30}))
+	assert.NotNil(t, conv.SetRowDeletionPolicy("t1", ddl.RowDeletionPolicy{ColId: "c2", NumDays: 0}))
+
+	assert.Nil(t, conv.SetRowDeletionPolicy("t1", ddl.RowDeletionPolicy{ColId: "c2", NumDays: 30}))
+	assert.Equal(t, ddl.RowDeletionPolicy{ColId: "c2", NumDays: 30}, conv.SpSchema["t1"].RowDeletionPolicy)
+
+	assert.Nil(t, conv.SetRowDeletionPolicy("t1", ddl.RowDeletionPolicy{}))
+	assert.Equal(t, ddl.RowDeletionPolicy{}, conv.SpSchema["t1"].RowDeletionPolicy)
+}
+
+func TestSetVectorIndex(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Name:   "docs",
+		ColIds: []string{"c1", "c2", "c3"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+			"c2": {Name: "embedding", Id: "c2", T: ddl.Type{Name: ddl.Float32, IsArray: true, VectorLength: 3}},
+			"c3": {Name: "title", Id: "c3", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+		},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+		Id:          "t1",
+	}
+	conv.UsedNames["docs"] = true
+	index := ddl.VectorIndex{Name: "docs_embedding", Id: "i1", ColId: "c2", DistanceType: ddl.CosineDistance}
+	withIndex := func(f func(*ddl.VectorIndex)) ddl.VectorIndex {
+		vi := index
+		f(&vi)
+		return vi
+	}
+	assert.NotNil(t, conv.SetVectorIndex("t2", index))
+	assert.NotNil(t, conv.SetVectorIndex("t1", withIndex(func(vi *ddl.VectorIndex) { vi.Name = "docs" })))
+	assert.NotNil(t, conv.SetVectorIndex("t1", withIndex(func(vi *ddl.VectorIndex) { vi.Name = "docs-embedding" })))
+	assert.NotNil(t, conv.SetVectorIndex("t1", withIndex(func(vi *ddl.VectorIndex) { vi.ColId = "c4" })))
+	assert.NotNil(t, conv.SetVectorIndex("t1", withIndex(func(vi *ddl.VectorIndex) { vi.ColId = "c3" })))
+	assert.NotNil(t, conv.SetVectorIndex("t1", withIndex(func(vi *ddl.VectorIndex) { vi.DistanceType = "MANHATTAN" })))
+	assert.NotNil(t, conv.SetVectorIndex("t1", withIndex(func(vi *ddl.VectorIndex) { vi.TreeDepth = 4 })))
+	assert.NotNil(t, conv.SetVectorIndex("t1", withIndex(func(vi *ddl.VectorIndex) { vi.TreeDepth = 2; vi.NumBranches = 100 })))
+	assert.NotNil(t, conv.SetVectorIndex("t1", withIndex(func(vi *ddl.VectorIndex) { vi.StoredColumnIds = []string{"c2"} })))
+
+	assert.Nil(t, conv.SetVectorIndex("t1", index))
+	assert.Equal(t, []ddl.VectorIndex{index}, conv.SpSchema["t1"].VectorIndexes)
+	assert.True(t, conv.UsedNames["docs_embedding"])
+
+	renamed := withIndex(func(vi *ddl.VectorIndex) { vi.Name = "docs_vectors"; vi.StoredColumnIds = []string{"c3"} })
+	assert.Nil(t, conv.SetVectorIndex("t1", renamed))
+	assert.Equal(t, []ddl.VectorIndex{renamed}, conv.SpSchema["t1"].VectorIndexes)
+	assert.False(t, conv.UsedNames["docs_embedding"])
+	assert.True(t, conv.UsedNames["docs_vectors"])
+
+	assert.NotNil(t, conv.RemoveVectorIndex("t1", "i2"))
+	assert.Nil(t, conv.RemoveVectorIndex("t1", "i1"))
+	assert.Empty(t, conv.SpSchema["t1"].VectorIndexes)
+	assert.False(t, conv.UsedNames["docs_vectors"])
+
+	// The index is removed once its column isn't a vector anymore.
+	assert.Nil(t, conv.SetVectorIndex("t1", index))
+	col := conv.SpSchema["t1"].ColDefs["c2"]
+	col.T = ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
+	conv.SpSchema["t1"].ColDefs["c2"] = col
+	conv.RemoveInvalidVectorIndexes("t1")
+	assert.Empty(t, conv.SpSchema["t1"].VectorIndexes)
+	assert.False(t, conv.UsedNames["docs_embedding"])
+}
+
+func TestSetCommitTimestamp(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Name:   "table1",
+		ColIds: []string{"c1", "c2"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+			"c2": {Name: "updated_at", Id: "c2", T: ddl.Type{Name: ddl.Timestamp}},
+		},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+		Id:          "t1",
+	}
+	assert.NotNil(t, conv.SetCommitTimestamp("t2", "c2", true, ""))
+	assert.NotNil(t, conv.SetCommitTimestamp("t1", "c3", true, ""))
+	assert.NotNil(t, conv.SetCommitTimestamp("t1", "c1", true, ""))
+	assert.NotNil(t, conv.SetCommitTimestamp("t1", "c2", true, "yesterday"))
+
+	assert.Nil(t, conv.SetCommitTimestamp("t1", "c2", true, "1970-01-01T01:00:00+01:00"))
+	assert.True(t, conv.SpSchema["t1"].ColDefs["c2"].AllowsCommitTimestamp())
+	assert.Equal(t, map[string]map[string]string{"t1": {"c2": "1970-01-01T00:00:00Z"}}, conv.CommitTimestampCols)
+
+	assert.Nil(t, conv.SetCommitTimestamp("t1", "c2", false, ""))
+	assert.False(t, conv.SpSchema["t1"].ColDefs["c2"].AllowsCommitTimestamp())
+	assert.Equal(t, map[string]map[string]string{"t1": {}}, conv.CommitTimestampCols)
+}
+
+func TestRolesAndGrants(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Name:    "table1",
+		ColIds:  []string{"c1", "c2"},
+		ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "id", Id: "c1"}, "c2": {Name: "name", Id: "c2"}},
+		Id:      "t1",
+	}
+	for _, name := range []string{"1reader", "has-dash", "public", "spanner_admin"} {
+		_, err := conv.AddRole(name)
+		assert.NotNil(t, err, name)
+	}
+	reader, err := conv.AddRole("reader")
+	assert.Nil(t, err)
+	_, err = conv.AddRole("READER")
+	assert.NotNil(t, err)
+	app, err := conv.AddRole("app")
+	assert.Nil(t, err)
+
+	assert.NotNil(t, conv.AddGrant(ddl.Grant{Privileges: []string{"SELECT"}, TableId: "t1"}))
+	assert.NotNil(t, conv.AddGrant(ddl.Grant{Privileges: []string{"SELECT"}, TableId: "t2", GranteeIds: []string{reader}}))
+	assert.NotNil(t, conv.AddGrant(ddl.Grant{Privileges: []string{"EXECUTE"}, TableId: "t1", GranteeIds: []string{reader}}))
+	assert.NotNil(t, conv.AddGrant(ddl.Grant{Privileges: []string{"DELETE"}, TableId: "t1", ColIds: []string{"c1"}, GranteeIds: []string{reader}}))
+	assert.NotNil(t, conv.AddGrant(ddl.Grant{Privileges: []string{"UPDATE"}, TableId: "t1", ColIds: []string{"c3"}, GranteeIds: []string{reader}}))
+	assert.NotNil(t, conv.AddGrant(ddl.Grant{RoleId: reader, GranteeIds: []string{reader}}))
+
+	assert.Nil(t, conv.AddGrant(ddl.Grant{Privileges: []string{"SELECT"}, TableId: "t1", GranteeIds: []string{reader}}))
+	assert.Nil(t, conv.AddGrant(ddl.Grant{Privileges: []string{"UPDATE"}, TableId: "t1", ColIds: []string{"c2"}, GranteeIds: []string{app, reader}}))
+	assert.Nil(t, conv.AddGrant(ddl.Grant{RoleId: reader, GranteeIds: []string{app}}))
+	assert.Equal(t, 3, len(conv.SpGrants))
+
+	assert.Nil(t, conv.DropRole(reader))
+	assert.NotNil(t, conv.DropRole(reader))
+	assert.Equal(t, []ddl.Grant{{Privileges: []string{"UPDATE"}, TableId: "t1", ColIds: []string{"c2"}, GranteeIds: []string{app}}}, conv.SpGrants)
+}
@@ -0,0 +1,219 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+type exprTokenKind int
+
+const (
+	exprOther exprTokenKind = iota // Operators, numbers, whitespace and comments.
+	exprIdent                      // Unquoted identifier or keyword.
+	exprQuotedIdent
+	exprString
+)
+
+// exprToken is a token of a SQL expression, e.g. of a check constraint.
+type exprToken struct {
+	kind  exprTokenKind
+	text  string // Text of the token in the expression.
+	ident string // Identifier, without quotes, for exprIdent and exprQuotedIdent.
+	quote byte   // Quote of exprQuotedIdent tokens.
+}
+
+// tokenizeExpr splits the SQL expression expr into tokens, so that
+// identifiers can be told apart from string literals and from parts of
+// longer identifiers. Backticks quote identifiers in both dialects, and
+// double quotes quote identifiers in the PostgreSQL dialect and strings in
+// GoogleSQL. The concatenation of the texts of the tokens is expr.
+func tokenizeExpr(expr, dialect string) []exprToken {
+	var tokens []exprToken
+	isIdentStart := func(c rune) bool { return c == '_' || unicode.IsLetter(c) }
+	isIdent := func(c rune) bool { return c == '_' || c == '$' || unicode.IsLetter(c) || unicode.IsDigit(c) }
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		start := i
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// Quotes are escaped by doubling them, or with a backslash in
+			// GoogleSQL.
+			i++
+			for i < len(runes) {
+				if runes[i] == '\\' && dialect != constants.DIALECT_POSTGRESQL && i+1 < len(runes) {
+					i += 2
+					continue
+				}
+				if runes[i] == c {
+					if i+1 < len(runes) && runes[i+1] == c {
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				i++
+			}
+			text := string(runes[start:i])
+			if c == '`' || (c == '"' && dialect == constants.DIALECT_POSTGRESQL) {
+				ident := strings.TrimSuffix(strings.TrimPrefix(text, string(c)), string(c))
+				ident = strings.ReplaceAll(ident, string([]rune{c, c}), string(c))
+				tokens = append(tokens, exprToken{kind: exprQuotedIdent, text: text, ident: ident, quote: byte(c)})
+			} else {
+				tokens = append(tokens, exprToken{kind: exprString, text: text})
+			}
+		case c == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: exprOther, text: string(runes[start:i])})
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			i += 2
+			if i > len(runes) {
+				i = len(runes)
+			}
+			tokens = append(tokens, exprToken{kind: exprOther, text: string(runes[start:i])})
+		case isIdentStart(c):
+			for i < len(runes) && isIdent(runes[i]) {
+				i++
+			}
+			text := string(runes[start:i])
+			tokens = append(tokens, exprToken{kind: exprIdent, text: text, ident: text})
+		case unicode.IsDigit(c):
+			// Numbers, including the exponent and the suffixes of hexadecimal
+			// numbers, aren't identifiers.
+			for i < len(runes) && (isIdent(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: exprOther, text: string(runes[start:i])})
+		default:
+			i++
+			tokens = append(tokens, exprToken{kind: exprOther, text: string(c)})
+		}
+	}
+	return tokens
+}
+
+// isColumnRef returns true if the identifier tokens[i] is a column
+// reference: not a function name, followed by '(', nor a field of another
+// identifier, preceded by '.'.
+func isColumnRef(tokens []exprToken, i int) bool {
+	if tokens[i].kind != exprIdent && tokens[i].kind != exprQuotedIdent {
+		return false
+	}
+	for j := i - 1; j >= 0; j-- {
+		if t := strings.TrimSpace(tokens[j].text); t != "" {
+			if t == "." {
+				return false
+			}
+			break
+		}
+	}
+	for j := i + 1; j < len(tokens); j++ {
+		if t := strings.TrimSpace(tokens[j].text); t != "" {
+			return t != "("
+		}
+	}
+	return true
+}
+
+// ExprColumnRefs returns the names of the columns among cols referenced by
+// the SQL expression expr, in the order of their first reference. Names are
+// compared ignoring case, like Spanner does.
+func ExprColumnRefs(expr, dialect string, cols []string) []string {
+	var refs []string
+	seen := map[string]bool{}
+	tokens := tokenizeExpr(expr, dialect)
+	for i, t := range tokens {
+		if !isColumnRef(tokens, i) {
+			continue
+		}
+		for _, col := range cols {
+			if strings.EqualFold(t.ident, col) && !seen[col] {
+				seen[col] = true
+				refs = append(refs, col)
+			}
+		}
+	}
+	return refs
+}
+
+// RenameExprColumn returns the SQL expression expr with its references to
+// column oldName renamed to newName. Quoted references stay quoted; string
+// literals, function names and parts of longer identifiers are left
+// unchanged.
+func RenameExprColumn(expr, dialect, oldName, newName string) string {
+	tokens := tokenizeExpr(expr, dialect)
+	var b strings.Builder
+	for i, t := range tokens {
+		switch {
+		case !isColumnRef(tokens, i) || !strings.EqualFold(t.ident, oldName):
+			b.WriteString(t.text)
+		case t.kind == exprQuotedIdent:
+			q := string(t.quote)
+			b.WriteString(q + strings.ReplaceAll(newName, q, q+q) + q)
+		default:
+			b.WriteString(newName)
+		}
+	}
+	return b.String()
+}
+
+// CheckConstraintColumns returns the ids of the columns of table tableId
+// referenced by check constraint cc.
+func (conv *Conv) CheckConstraintColumns(tableId string, cc ddl.CheckConstraint) []string {
+	ct := conv.SpSchema[tableId]
+	var names []string
+	byName := map[string]string{}
+	for _, colId := range ct.ColIds {
+		names = append(names, ct.ColDefs[colId].Name)
+		byName[ct.ColDefs[colId].Name] = colId
+	}
+	var colIds []string
+	for _, name := range ExprColumnRefs(cc.Expr, conv.SpDialect, names) {
+		colIds = append(colIds, byName[name])
+	}
+	return colIds
+}
+
+// FlagCheckConstraints records issue, e.g. ColumnNotFoundError before column
+// colId of table tableId is dropped, for each check constraint of the table
+// referencing the column, so that the constraints are reported until they
+// are fixed.
+func (conv *Conv) FlagCheckConstraints(tableId, colId string, issue SchemaIssue) {
+	for _, cc := range conv.SpSchema[tableId].CheckConstraints {
+		if !Contains(conv.CheckConstraintColumns(tableId, cc), colId) {
+			continue
+		}
+		if conv.InvalidCheckExp == nil {
+			conv.InvalidCheckExp = make(map[string][]InvalidCheckExp)
+		}
+		e := InvalidCheckExp{IssueType: issue, Expression: cc.Expr}
+		if !Contains(conv.InvalidCheckExp[tableId], e) {
+			conv.InvalidCheckExp[tableId] = append(conv.InvalidCheckExp[tableId], e)
+		}
+		conv.setTableIssue(tableId, issue, true)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExprColumnRefs(t *testing.T) {
+	cols := []string{"total", "total_tax", "status", "my col"}
+	tests := []struct {
+		name     string
+		expr     string
+		dialect  string
+		expected []string
+	}{
+		{name: "plain", expr: "(total > 0 AND total_tax >= 0)", dialect: constants.DIALECT_GOOGLESQL, expected: []string{"total", "total_tax"}},
+		{name: "case", expr: "TOTAL > 0", dialect: constants.DIALECT_GOOGLESQL, expected: []string{"total"}},
+		{name: "string literal", expr: "status != 'total'", dialect: constants.DIALECT_GOOGLESQL, expected: []string{"status"}},
+		{name: "double quoted string", expr: `status != "total"`, dialect: constants.DIALECT_GOOGLESQL, expected: []string{"status"}},
+		{name: "double quoted identifier", expr: `"total" > 0`, dialect: constants.DIALECT_POSTGRESQL, expected: []string{"total"}},
+		{name: "backquoted identifier", expr: "`my col` > 0", dialect: constants.DIALECT_GOOGLESQL, expected: []string{"my col"}},
+		{name: "escaped quote", expr: `status != 'it\'s total'`, dialect: constants.DIALECT_GOOGLESQL, expected: []string{"status"}},
+		{name: "function", expr: "status(1) > 0", dialect: constants.DIALECT_GOOGLESQL},
+		{name: "field", expr: "t.total > 0", dialect: constants.DIALECT_GOOGLESQL},
+		{name: "comment", expr: "1 > 0 -- total\n/* status */", dialect: constants.DIALECT_GOOGLESQL},
+		{name: "number", expr: "1e5 > 0x1F", dialect: constants.DIALECT_GOOGLESQL},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, ExprColumnRefs(tc.expr, tc.dialect, cols), tc.name)
+	}
+}
+
+func TestRenameExprColumn(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		dialect  string
+		expected string
+	}{
+		{name: "plain", expr: "(total > 0 AND total_tax >= 0)", dialect: constants.DIALECT_GOOGLESQL, expected: "(amount > 0 AND total_tax >= 0)"},
+		{name: "string literal", expr: "total > 0 OR note = 'total'", dialect: constants.DIALECT_GOOGLESQL, expected: "amount > 0 OR note = 'total'"},
+		{name: "double quoted identifier", expr: `"Total" > 0`, dialect: constants.DIALECT_POSTGRESQL, expected: `"amount" > 0`},
+		{name: "backquoted identifier", expr: "`total` > 0", dialect: constants.DIALECT_GOOGLESQL, expected: "`amount` > 0"},
+		{name: "function and field", expr: "total(t.total) > 0", dialect: constants.DIALECT_GOOGLESQL, expected: "total(t.total) > 0"},
+		{name: "unterminated comment", expr: "total > 0 /* total", dialect: constants.DIALECT_GOOGLESQL, expected: "amount > 0 /* total"},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, RenameExprColumn(tc.expr, tc.dialect, "total", "amount"), tc.name)
+	}
+}
+
+func TestFlagCheckConstraints(t *testing.T) {
+	conv := renameTestConv()
+	conv.FlagCheckConstraints("t1", "c1", ColumnNotFoundError)
+	assert.Empty(t, conv.InvalidCheckExp["t1"])
+
+	conv.FlagCheckConstraints("t1", "c2", CheckConstraintColumnRetyped)
+	conv.FlagCheckConstraints("t1", "c2", CheckConstraintColumnRetyped)
+	assert.Equal(t, []InvalidCheckExp{{IssueType: CheckConstraintColumnRetyped, Expression: "(total > 0)"}}, conv.InvalidCheckExp["t1"])
+	assert.Contains(t, conv.SchemaIssues["t1"].TableLevelIssues, CheckConstraintColumnRetyped)
+	assert.Equal(t, []string{"c2", "c3"}, conv.CheckConstraintColumns("t1", ddl.CheckConstraint{Expr: "total > 0 AND Total_tax > 0 AND 'id' != ''"}))
+}
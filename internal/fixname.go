@@ -15,9 +15,16 @@
 package internal
 
 import (
+	"fmt"
+	"hash/fnv"
 	"regexp"
 )
 
+// MaxIdentifierLength is the maximum length of the names of Spanner tables,
+// columns, indexes and constraints, for both GoogleSQL and PostgreSQL
+// dialect databases.
+const MaxIdentifierLength = 128
+
 var nameRegexp = regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9_]*$")
 var badFirstChar = regexp.MustCompile("^[^a-zA-Z]")
 var badOtherChar = regexp.MustCompile("[^a-zA-Z0-9_]")
@@ -26,17 +33,52 @@ var badOtherChar = regexp.MustCompile("[^a-zA-Z0-9_]")
 // spanner will accept. table_name, column_name or index_name must all
 // adhere to the following regexp:
 //   {a-z|A-Z}[{a-z|A-Z|0-9|_}+]
+// and be at most MaxIdentifierLength characters long.
 // If the first character of the name is not allowed, we replace it by "A".
-// We replace all other problem characters by "_".
+// We replace all other problem characters by "_", and truncate names that
+// are too long using TruncateName.
 // Returns a Spanner-acceptable name, and whether we had to change the name.
 func FixName(name string) (string, bool) {
-	if nameRegexp.MatchString(name) {
+	if nameRegexp.MatchString(name) && len(name) <= MaxIdentifierLength {
 		return name, false
 	}
 	if len(name) == 0 {
 		return "BogusEmptyId", true // Don't expect this case.
 	}
-	name = badFirstChar.ReplaceAllString(name, "A")
-	name = badOtherChar.ReplaceAllString(name, "_")
-	return name, true
+	fixed := badFirstChar.ReplaceAllString(name, "A")
+	fixed = badOtherChar.ReplaceAllString(fixed, "_")
+	if len(fixed) > MaxIdentifierLength {
+		// Hash the original name, so that long names differing only in
+		// characters replaced by "_" are still truncated to distinct names.
+		fixed = truncateName(fixed, name, MaxIdentifierLength)
+	}
+	return fixed, true
+}
+
+// TruncateName shortens a legal Spanner name to at most maxLen characters.
+// Names that are too long keep a prefix and end with a hash of the full
+// name, so that the result is deterministic and long names sharing a prefix
+// remain distinct.
+func TruncateName(name string, maxLen int) string {
+	if len(name) <= maxLen {
+		return name
+	}
+	return truncateName(name, name, maxLen)
+}
+
+func truncateName(name, hashed string, maxLen int) string {
+	h := fnv.New32a()
+	h.Write([]byte(hashed))
+	suffix := fmt.Sprintf("_%08x", h.Sum32())
+	return name[:maxLen-len(suffix)] + suffix
+}
+
+// AppendNameSuffix appends suffix to a legal Spanner name, shortening the
+// name so that the result is at most MaxIdentifierLength characters long.
+// It is used to make names unique, e.g. name_1.
+func AppendNameSuffix(name, suffix string) string {
+	if len(name)+len(suffix) > MaxIdentifierLength {
+		name = name[:MaxIdentifierLength-len(suffix)]
+	}
+	return name + suffix
 }
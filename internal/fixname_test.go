@@ -15,6 +15,7 @@
 package internal
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -39,3 +40,40 @@ func TestFixName(t *testing.T) {
 		assert.Equal(t, tc.changed, c, tc.name)
 	}
 }
+
+func TestFixName_Length(t *testing.T) {
+	exact := strings.Repeat("a", MaxIdentifierLength)
+	n, c := FixName(exact)
+	assert.Equal(t, exact, n)
+	assert.False(t, c)
+
+	long := strings.Repeat("a", MaxIdentifierLength+10)
+	n, c = FixName(long)
+	assert.True(t, c)
+	assert.Equal(t, MaxIdentifierLength, len(n))
+	assert.True(t, strings.HasPrefix(n, strings.Repeat("a", MaxIdentifierLength-9)))
+	n2, _ := FixName(long)
+	assert.Equal(t, n, n2, "truncation must be deterministic")
+
+	// Long names which only differ in illegal characters are fixed to
+	// distinct names.
+	n1, _ := FixName(long + "$")
+	n2, _ = FixName(long + "#")
+	assert.NotEqual(t, n1, n2)
+	assert.Equal(t, MaxIdentifierLength, len(n1))
+}
+
+func TestTruncateName(t *testing.T) {
+	assert.Equal(t, "short", TruncateName("short", 10))
+	a := TruncateName("abcdefghijklmnopqrstuvwxyz_1", 20)
+	b := TruncateName("abcdefghijklmnopqrstuvwxyz_2", 20)
+	assert.Equal(t, 20, len(a))
+	assert.Equal(t, "abcdefghijk", a[:11])
+	assert.NotEqual(t, a, b)
+}
+
+func TestAppendNameSuffix(t *testing.T) {
+	assert.Equal(t, "name_1", AppendNameSuffix("name", "_1"))
+	long := strings.Repeat("a", MaxIdentifierLength)
+	assert.Equal(t, strings.Repeat("a", MaxIdentifierLength-3)+"_12", AppendNameSuffix(long, "_12"))
+}
@@ -91,6 +91,18 @@ func GenerateFunctionId() string {
 func GenerateViewId() string {
 	return GenerateId("vw")
 }
+func GenerateRoleId() string {
+	return GenerateId("ro")
+}
+
+// IsAddedTable returns true if tableId is a Spanner table added to the
+// schema without a source table, e.g. from the web UI. Such tables have no
+// data to migrate.
+func IsAddedTable(conv *Conv, tableId string) bool {
+	_, inSpanner := conv.SpSchema[tableId]
+	_, inSource := conv.SrcSchema[tableId]
+	return inSpanner && !inSource
+}
 
 func GetSrcColNameIdMap(srcs schema.Table) map[string]string {
 	if len(srcs.ColNameIdMap) > 0 {
@@ -163,6 +175,9 @@ func ComputeUsedNames(conv *Conv) map[string]bool {
 		for _, index := range table.Indexes {
 			usedNames[strings.ToLower(index.Name)] = true
 		}
+		for _, index := range table.VectorIndexes {
+			usedNames[strings.ToLower(index.Name)] = true
+		}
 		for _, fk := range table.ForeignKeys {
 			usedNames[strings.ToLower(fk.Name)] = true
 		}
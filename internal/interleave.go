@@ -0,0 +1,374 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// InterleaveOnDeleteMismatch describes a table interleaved in place of a
+// source foreign key whose ON DELETE action differs from the one of the
+// interleaving.
+type InterleaveOnDeleteMismatch struct {
+	ForeignKey       string // Name of the source foreign key.
+	SourceOnDelete   string // ON DELETE action of the source foreign key.
+	InterleaveAction string // ON DELETE action of the interleaving, or "" if none is enforced.
+}
+
+// Description returns the description of m for table, interleaved in parent.
+func (m InterleaveOnDeleteMismatch) Description(table, parent string) string {
+	action := "doesn't enforce the relationship on delete (INTERLEAVE IN)"
+	if m.InterleaveAction != "" {
+		action = "is ON DELETE " + m.InterleaveAction
+	}
+	return fmt.Sprintf("Table '%s' is interleaved in '%s' in place of foreign key '%s' with ON DELETE %s, but the interleaving %s", table, parent, m.ForeignKey, m.SourceOnDelete, action)
+}
+
+// interleaveOnDeleteAction returns the ON DELETE action Spanner enforces for
+// an interleaving, or "" for INTERLEAVE IN, which allows deleting parent rows
+// without deleting their child rows.
+func interleaveOnDeleteAction(interleaveType, onDelete string) string {
+	if interleaveType == "IN" {
+		return ""
+	}
+	if strings.ToUpper(onDelete) == constants.FK_CASCADE {
+		return constants.FK_CASCADE
+	}
+	return constants.FK_NO_ACTION
+}
+
+// sourceOnDeleteAction returns the ON DELETE action of a source foreign key
+// in Spanner terms, RESTRICT being checked at the end of the statement like
+// NO ACTION.
+func sourceOnDeleteAction(fk schema.ForeignKey) string {
+	switch action := strings.ToUpper(fk.OnDelete); action {
+	case constants.FK_RESTRICT:
+		return constants.FK_NO_ACTION
+	default:
+		return action
+	}
+}
+
+// CheckInterleaveOnDelete compares the ON DELETE action of interleaving table
+// tableId in parentTableId, with the given type and action, with the one of
+// the source foreign key it replaces. It returns false if they match, or if
+// the source foreign key is unknown or has no ON DELETE action, e.g. for
+// sources which don't report them.
+func (conv *Conv) CheckInterleaveOnDelete(tableId, parentTableId, interleaveType, onDelete string) (InterleaveOnDeleteMismatch, bool) {
+	var fk schema.ForeignKey
+	found := false
+	for _, f := range conv.SrcSchema[tableId].ForeignKeys {
+		if f.ReferTableId == parentTableId {
+			fk, found = f, true
+			break
+		}
+	}
+	if !found || fk.OnDelete == "" {
+		return InterleaveOnDeleteMismatch{}, false
+	}
+	action := interleaveOnDeleteAction(interleaveType, onDelete)
+	if sourceOnDeleteAction(fk) == action {
+		return InterleaveOnDeleteMismatch{}, false
+	}
+	return InterleaveOnDeleteMismatch{ForeignKey: fk.Name, SourceOnDelete: strings.ToUpper(fk.OnDelete), InterleaveAction: action}, true
+}
+
+// InterleaveOnDeleteMismatch returns the mismatch between the ON DELETE
+// action of the current interleaving of table tableId and the source foreign
+// key it replaces, if any.
+func (conv *Conv) InterleaveOnDeleteMismatch(tableId string) (InterleaveOnDeleteMismatch, bool) {
+	parent := conv.SpSchema[tableId].ParentTable
+	if parent.Id == "" {
+		return InterleaveOnDeleteMismatch{}, false
+	}
+	return conv.CheckInterleaveOnDelete(tableId, parent.Id, parent.InterleaveType, parent.OnDelete)
+}
+
+// UpdateInterleaveOnDeleteIssue adds the InterleaveOnDeleteDiverges issue to
+// table tableId if the ON DELETE action of its interleaving differs from the
+// one of the source foreign key it replaces, and removes it otherwise. It is
+// called whenever the interleaving of a table changes.
+func (conv *Conv) UpdateInterleaveOnDeleteIssue(tableId string) {
+	_, mismatch := conv.InterleaveOnDeleteMismatch(tableId)
+	conv.setTableIssue(tableId, InterleaveOnDeleteDiverges, mismatch)
+}
+
+// InterleaveCandidate is a foreign key which can be replaced by interleaving
+// its table in the referenced table: its columns are a prefix of the primary
+// key of the table and reference the whole primary key of the parent table,
+// with the same names and types, as Spanner requires.
+type InterleaveCandidate struct {
+	TableId       string
+	ParentTableId string
+	ForeignKeyId  string
+	ForeignKey    string // Name of the foreign key.
+	// OnDelete is the ON DELETE action of the interleaving, the one of the
+	// foreign key.
+	OnDelete string
+}
+
+// InterleaveCandidates returns the foreign keys of conv.SpSchema which can be
+// converted to interleavings, sorted by table name.
+func (conv *Conv) InterleaveCandidates() []InterleaveCandidate {
+	var candidates []InterleaveCandidate
+	for _, tableId := range ddl.GetSortedTableIdsBySpName(conv.SpSchema) {
+		if c, ok := conv.InterleaveCandidate(tableId); ok {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates
+}
+
+// InterleaveCandidate returns the first foreign key of table tableId which
+// can be converted to an interleaving, if any. Tables which are already
+// interleaved or have a synthetic primary key have none.
+func (conv *Conv) InterleaveCandidate(tableId string) (InterleaveCandidate, bool) {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok || ct.ParentTable.Id != "" {
+		return InterleaveCandidate{}, false
+	}
+	if _, ok := conv.SyntheticPKeys[tableId]; ok {
+		return InterleaveCandidate{}, false
+	}
+	for _, fk := range ct.ForeignKeys {
+		if conv.isInterleaveCandidate(ct, fk) {
+			onDelete := constants.FK_NO_ACTION
+			if strings.ToUpper(fk.OnDelete) == constants.FK_CASCADE {
+				onDelete = constants.FK_CASCADE
+			}
+			return InterleaveCandidate{TableId: tableId, ParentTableId: fk.ReferTableId, ForeignKeyId: fk.Id, ForeignKey: fk.Name, OnDelete: onDelete}, true
+		}
+	}
+	return InterleaveCandidate{}, false
+}
+
+// isInterleaveCandidate returns true if foreign key fk of table ct can be
+// converted to an interleaving.
+func (conv *Conv) isInterleaveCandidate(ct ddl.CreateTable, fk ddl.Foreignkey) bool {
+	parent, ok := conv.SpSchema[fk.ReferTableId]
+	if !ok || parent.Id == ct.Id || len(fk.ColIds) != len(fk.ReferColumnIds) {
+		return false
+	}
+	// Interleaving in a descendant of the table would create a cycle.
+	if depth := conv.InterleaveDepth(ct.Id, parent.Id); depth == 0 || depth > MaxInterleaveDepth {
+		return false
+	}
+	childPks, parentPks := sortedPrimaryKeys(ct), sortedPrimaryKeys(parent)
+	if len(parentPks) == 0 || len(fk.ColIds) != len(parentPks) || len(childPks) < len(parentPks) {
+		return false
+	}
+	for i, pk := range parentPks {
+		if fk.ColIds[i] != childPks[i].ColId || fk.ReferColumnIds[i] != pk.ColId {
+			return false
+		}
+		c, p := ct.ColDefs[fk.ColIds[i]], parent.ColDefs[pk.ColId]
+		if c.Name != p.Name || c.T.Name != p.T.Name || c.T.Len != p.T.Len || c.T.IsArray != p.T.IsArray {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedPrimaryKeys returns the primary key columns of ct in key order.
+func sortedPrimaryKeys(ct ddl.CreateTable) []ddl.IndexKey {
+	pks := append([]ddl.IndexKey{}, ct.PrimaryKeys...)
+	sort.SliceStable(pks, func(i, j int) bool { return pks[i].Order < pks[j].Order })
+	return pks
+}
+
+// MaxInterleaveDepth is the maximum number of levels of interleaved tables
+// supported by Spanner, counting the top-level table.
+const MaxInterleaveDepth = 7
+
+// InterleaveNode is a table of the tree of interleaved tables of a schema.
+type InterleaveNode struct {
+	TableId        string
+	Name           string
+	InterleaveType string // Interleave type of the table in its parent, "" for top-level tables.
+	OnDelete       string
+	Depth          int // 1 for top-level tables.
+	Children       []*InterleaveNode
+}
+
+// interleaveLevel returns the level of table tableId in the tree of
+// interleaved tables, 1 for top-level tables, and the tables of the cycle of
+// parent tables it belongs to or leads to, if any. A parent table missing
+// from conv.SpSchema is ignored.
+func (conv *Conv) interleaveLevel(tableId string) (int, []string) {
+	seen := map[string]int{}
+	var path []string
+	for id := tableId; ; id = conv.SpSchema[id].ParentTable.Id {
+		if i, ok := seen[id]; ok {
+			return 0, path[i:]
+		}
+		seen[id] = len(path)
+		path = append(path, id)
+		if _, ok := conv.SpSchema[conv.SpSchema[id].ParentTable.Id]; !ok {
+			return len(path), nil
+		}
+	}
+}
+
+// interleaveHeight returns the number of levels of the tree of tables
+// interleaved in table tableId, 1 if none is. children maps table ids to the
+// ids of the tables interleaved in them.
+func interleaveHeight(tableId string, children map[string][]string, seen map[string]bool) int {
+	if seen[tableId] {
+		return 0
+	}
+	seen[tableId] = true
+	h := 0
+	for _, child := range children[tableId] {
+		if c := interleaveHeight(child, children, seen); c > h {
+			h = c
+		}
+	}
+	return h + 1
+}
+
+// interleaveChildren returns the ids of the tables interleaved in each table,
+// sorted by name.
+func (conv *Conv) interleaveChildren() map[string][]string {
+	children := map[string][]string{}
+	for _, tableId := range ddl.GetSortedTableIdsBySpName(conv.SpSchema) {
+		if parent := conv.SpSchema[tableId].ParentTable.Id; parent != "" {
+			children[parent] = append(children[parent], tableId)
+		}
+	}
+	return children
+}
+
+// InterleaveDepth returns the number of levels of interleaved tables once
+// table tableId, with the tables interleaved in it, is interleaved in table
+// parentTableId, or 0 if it would create a cycle.
+func (conv *Conv) InterleaveDepth(tableId, parentTableId string) int {
+	level, cycle := conv.interleaveLevel(parentTableId)
+	if cycle != nil {
+		return 0
+	}
+	for id, i := parentTableId, 0; i < level; id, i = conv.SpSchema[id].ParentTable.Id, i+1 {
+		if id == tableId {
+			return 0
+		}
+	}
+	return level + interleaveHeight(tableId, conv.interleaveChildren(), map[string]bool{})
+}
+
+// InterleaveTree returns the trees of interleaved tables of conv.SpSchema,
+// one per top-level table, sorted by name. Tables whose parent tables form a
+// cycle aren't part of any tree.
+func (conv *Conv) InterleaveTree() []*InterleaveNode {
+	children := conv.interleaveChildren()
+	seen := map[string]bool{}
+	var build func(tableId string, depth int) *InterleaveNode
+	build = func(tableId string, depth int) *InterleaveNode {
+		seen[tableId] = true
+		ct := conv.SpSchema[tableId]
+		n := &InterleaveNode{TableId: tableId, Name: ct.Name, InterleaveType: ct.ParentTable.InterleaveType, OnDelete: ct.ParentTable.OnDelete, Depth: depth, Children: []*InterleaveNode{}}
+		for _, child := range children[tableId] {
+			if !seen[child] {
+				n.Children = append(n.Children, build(child, depth+1))
+			}
+		}
+		return n
+	}
+	roots := []*InterleaveNode{}
+	for _, tableId := range ddl.GetSortedTableIdsBySpName(conv.SpSchema) {
+		if _, ok := conv.SpSchema[conv.SpSchema[tableId].ParentTable.Id]; !ok {
+			roots = append(roots, build(tableId, 1))
+		}
+	}
+	return roots
+}
+
+// InterleaveCycle returns the ids of the tables of the cycle of parent
+// tables table tableId belongs to, starting with it, or nil if it doesn't
+// belong to one.
+func (conv *Conv) InterleaveCycle(tableId string) []string {
+	_, cycle := conv.interleaveLevel(tableId)
+	if len(cycle) == 0 || cycle[0] != tableId {
+		return nil
+	}
+	return cycle
+}
+
+// tableNames returns the Spanner names of the tables tableIds.
+func (conv *Conv) tableNames(tableIds []string) []string {
+	var names []string
+	for _, id := range tableIds {
+		names = append(names, conv.SpSchema[id].Name)
+	}
+	return names
+}
+
+// InterleaveCycleDescription returns the cycle of parent tables table tableId
+// belongs to, e.g. "a -> b -> a", or "" if it doesn't belong to one.
+func (conv *Conv) InterleaveCycleDescription(tableId string) string {
+	cycle := conv.InterleaveCycle(tableId)
+	if cycle == nil {
+		return ""
+	}
+	return strings.Join(conv.tableNames(append(cycle, tableId)), " -> ")
+}
+
+// InterleaveLevel returns the level of table tableId in the tree of
+// interleaved tables, 1 for top-level tables, or 0 if its parent tables form
+// a cycle.
+func (conv *Conv) InterleaveLevel(tableId string) int {
+	level, _ := conv.interleaveLevel(tableId)
+	return level
+}
+
+// UpdateInterleaveStructureIssues adds the InterleaveCycle issue to the tables
+// whose parent tables form a cycle, and the InterleaveTooDeep issue to the
+// tables nested deeper than MaxInterleaveDepth, and removes them from the
+// other tables. It is called whenever the interleaving of tables changes.
+func (conv *Conv) UpdateInterleaveStructureIssues() {
+	for tableId := range conv.SpSchema {
+		conv.setTableIssue(tableId, InterleaveCycle, conv.InterleaveCycle(tableId) != nil)
+		conv.setTableIssue(tableId, InterleaveTooDeep, conv.InterleaveLevel(tableId) > MaxInterleaveDepth)
+	}
+}
+
+// setTableIssue adds the table level issue to table tableId if present is
+// true, and removes it otherwise. The issues of the table are left untouched
+// if they are already up to date.
+func (conv *Conv) setTableIssue(tableId string, issue SchemaIssue, present bool) {
+	tableIssues := conv.SchemaIssues[tableId]
+	if present == Contains(tableIssues.TableLevelIssues, issue) {
+		return
+	}
+	if conv.SchemaIssues == nil {
+		conv.SchemaIssues = make(map[string]TableIssues)
+	}
+	if present {
+		tableIssues.TableLevelIssues = append(tableIssues.TableLevelIssues, issue)
+	} else {
+		issues := []SchemaIssue{}
+		for _, i := range tableIssues.TableLevelIssues {
+			if i != issue {
+				issues = append(issues, i)
+			}
+		}
+		tableIssues.TableLevelIssues = issues
+	}
+	conv.SchemaIssues[tableId] = tableIssues
+}
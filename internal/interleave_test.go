@@ -0,0 +1,207 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckInterleaveOnDelete(t *testing.T) {
+	tests := []struct {
+		name           string
+		srcOnDelete    string
+		interleaveType string
+		onDelete       string
+		expected       InterleaveOnDeleteMismatch
+		mismatch       bool
+	}{
+		{name: "cascade", srcOnDelete: "cascade", interleaveType: "IN PARENT", onDelete: "CASCADE"},
+		{name: "restrict", srcOnDelete: "RESTRICT", interleaveType: "IN PARENT", onDelete: "NO ACTION"},
+		{name: "default no action", srcOnDelete: "NO ACTION", interleaveType: "IN PARENT"},
+		{name: "unknown action", srcOnDelete: "", interleaveType: "IN"},
+		{name: "cascade to no action", srcOnDelete: "CASCADE", interleaveType: "IN PARENT", onDelete: "NO ACTION",
+			expected: InterleaveOnDeleteMismatch{ForeignKey: "fk1", SourceOnDelete: "CASCADE", InterleaveAction: "NO ACTION"}, mismatch: true},
+		{name: "set null", srcOnDelete: "SET NULL", interleaveType: "IN PARENT", onDelete: "CASCADE",
+			expected: InterleaveOnDeleteMismatch{ForeignKey: "fk1", SourceOnDelete: "SET NULL", InterleaveAction: "CASCADE"}, mismatch: true},
+		{name: "interleave in", srcOnDelete: "NO ACTION", interleaveType: "IN",
+			expected: InterleaveOnDeleteMismatch{ForeignKey: "fk1", SourceOnDelete: "NO ACTION"}, mismatch: true},
+	}
+	for _, tc := range tests {
+		conv := MakeConv()
+		conv.SrcSchema["t2"] = schema.Table{Id: "t2", ForeignKeys: []schema.ForeignKey{
+			{Name: "fk0", ReferTableId: "t3", OnDelete: "SET NULL"},
+			{Name: "fk1", ReferTableId: "t1", OnDelete: tc.srcOnDelete},
+		}}
+		m, ok := conv.CheckInterleaveOnDelete("t2", "t1", tc.interleaveType, tc.onDelete)
+		assert.Equal(t, tc.mismatch, ok, tc.name)
+		assert.Equal(t, tc.expected, m, tc.name)
+	}
+}
+
+func TestUpdateInterleaveOnDeleteIssue(t *testing.T) {
+	conv := MakeConv()
+	conv.SrcSchema["t2"] = schema.Table{Id: "t2", ForeignKeys: []schema.ForeignKey{{Name: "fk1", ReferTableId: "t1", OnDelete: "CASCADE"}}}
+	conv.SpSchema["t1"] = ddl.CreateTable{Name: "parent", Id: "t1"}
+	conv.SpSchema["t2"] = ddl.CreateTable{Name: "child", Id: "t2", ParentTable: ddl.InterleavedParent{Id: "t1", OnDelete: "NO ACTION", InterleaveType: "IN PARENT"}}
+	conv.SchemaIssues["t2"] = TableIssues{TableLevelIssues: []SchemaIssue{RowLimitExceeded}}
+
+	conv.UpdateInterleaveOnDeleteIssue("t2")
+	conv.UpdateInterleaveOnDeleteIssue("t2")
+	assert.Equal(t, []SchemaIssue{RowLimitExceeded, InterleaveOnDeleteDiverges}, conv.SchemaIssues["t2"].TableLevelIssues)
+	m, _ := conv.InterleaveOnDeleteMismatch("t2")
+	assert.Equal(t, "Table 'child' is interleaved in 'parent' in place of foreign key 'fk1' with ON DELETE CASCADE, but the interleaving is ON DELETE NO ACTION", m.Description("child", "parent"))
+
+	ct := conv.SpSchema["t2"]
+	ct.ParentTable.OnDelete = "CASCADE"
+	conv.SpSchema["t2"] = ct
+	conv.UpdateInterleaveOnDeleteIssue("t2")
+	assert.Equal(t, []SchemaIssue{RowLimitExceeded}, conv.SchemaIssues["t2"].TableLevelIssues)
+
+	// Tables which aren't interleaved have no issue.
+	conv.UpdateInterleaveOnDeleteIssue("t1")
+	_, ok := conv.SchemaIssues["t1"]
+	assert.False(t, ok)
+}
+
+func TestInterleaveCandidates(t *testing.T) {
+	conv := MakeConv()
+	intCol := func(name string) ddl.ColumnDef { return ddl.ColumnDef{Name: name, T: ddl.Type{Name: ddl.Int64}} }
+	conv.SpSchema = ddl.Schema{
+		"t1": {Name: "singers", Id: "t1",
+			ColDefs:     map[string]ddl.ColumnDef{"c1": intCol("singer_id")},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}}},
+		"t2": {Name: "albums", Id: "t2",
+			ColDefs:     map[string]ddl.ColumnDef{"c2": intCol("singer_id"), "c3": intCol("album_id")},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c3", Order: 2}, {ColId: "c2", Order: 1}},
+			ForeignKeys: []ddl.Foreignkey{{Name: "fk_albums", Id: "f1", ColIds: []string{"c2"}, ReferTableId: "t1", ReferColumnIds: []string{"c1"}, OnDelete: "CASCADE"}}},
+		// The foreign key columns aren't a prefix of the primary key.
+		"t3": {Name: "songs", Id: "t3",
+			ColDefs:     map[string]ddl.ColumnDef{"c4": intCol("song_id"), "c5": intCol("singer_id")},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c4", Order: 1}, {ColId: "c5", Order: 2}},
+			ForeignKeys: []ddl.Foreignkey{{Name: "fk_songs", Id: "f2", ColIds: []string{"c5"}, ReferTableId: "t1", ReferColumnIds: []string{"c1"}}}},
+		// The foreign key column has another name.
+		"t4": {Name: "concerts", Id: "t4",
+			ColDefs:     map[string]ddl.ColumnDef{"c6": intCol("artist_id"), "c7": intCol("concert_id")},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c6", Order: 1}, {ColId: "c7", Order: 2}},
+			ForeignKeys: []ddl.Foreignkey{{Name: "fk_concerts", Id: "f3", ColIds: []string{"c6"}, ReferTableId: "t1", ReferColumnIds: []string{"c1"}}}},
+		// The table is already interleaved.
+		"t5": {Name: "tours", Id: "t5",
+			ColDefs:     map[string]ddl.ColumnDef{"c8": intCol("singer_id"), "c9": intCol("tour_id")},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c8", Order: 1}, {ColId: "c9", Order: 2}},
+			ForeignKeys: []ddl.Foreignkey{{Name: "fk_tours", Id: "f4", ColIds: []string{"c8"}, ReferTableId: "t1", ReferColumnIds: []string{"c1"}}},
+			ParentTable: ddl.InterleavedParent{Id: "t1", InterleaveType: "IN"}},
+	}
+	assert.Equal(t, []InterleaveCandidate{
+		{TableId: "t2", ParentTableId: "t1", ForeignKeyId: "f1", ForeignKey: "fk_albums", OnDelete: "CASCADE"},
+	}, conv.InterleaveCandidates())
+
+	// Interleaving tours in singers would create a cycle once singers are
+	// interleaved in tours.
+	ct := conv.SpSchema["t5"]
+	ct.ParentTable = ddl.InterleavedParent{}
+	conv.SpSchema["t5"] = ct
+	_, ok := conv.InterleaveCandidate("t5")
+	assert.True(t, ok)
+	ct = conv.SpSchema["t1"]
+	ct.ParentTable = ddl.InterleavedParent{Id: "t5", InterleaveType: "IN"}
+	conv.SpSchema["t1"] = ct
+	_, ok = conv.InterleaveCandidate("t5")
+	assert.False(t, ok)
+}
+
+func interleaveChain(n int) ddl.Schema {
+	s := ddl.Schema{}
+	for i := 1; i <= n; i++ {
+		id := fmt.Sprintf("t%d", i)
+		ct := ddl.CreateTable{Name: fmt.Sprintf("table%d", i), Id: id}
+		if i > 1 {
+			ct.ParentTable = ddl.InterleavedParent{Id: fmt.Sprintf("t%d", i-1), OnDelete: "CASCADE", InterleaveType: "IN PARENT"}
+		}
+		s[id] = ct
+	}
+	return s
+}
+
+func TestInterleaveDepth(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema = interleaveChain(4)
+	conv.SpSchema["t5"] = ddl.CreateTable{Name: "table5", Id: "t5"}
+	conv.SpSchema["t6"] = ddl.CreateTable{Name: "table6", Id: "t6", ParentTable: ddl.InterleavedParent{Id: "t5", InterleaveType: "IN"}}
+
+	assert.Equal(t, 1, conv.InterleaveLevel("t1"))
+	assert.Equal(t, 4, conv.InterleaveLevel("t4"))
+	// t5 and t6 nested below t4.
+	assert.Equal(t, 6, conv.InterleaveDepth("t5", "t4"))
+	// t2 is an ancestor of t4.
+	assert.Equal(t, 0, conv.InterleaveDepth("t2", "t4"))
+	assert.Equal(t, 0, conv.InterleaveDepth("t4", "t4"))
+
+	conv.UpdateInterleaveStructureIssues()
+	assert.Empty(t, conv.SchemaIssues)
+}
+
+func TestUpdateInterleaveStructureIssues(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema = interleaveChain(MaxInterleaveDepth + 1)
+	conv.SpSchema["c1"] = ddl.CreateTable{Name: "cycle1", Id: "c1", ParentTable: ddl.InterleavedParent{Id: "c2"}}
+	conv.SpSchema["c2"] = ddl.CreateTable{Name: "cycle2", Id: "c2", ParentTable: ddl.InterleavedParent{Id: "c1"}}
+	conv.SpSchema["c3"] = ddl.CreateTable{Name: "cycle3", Id: "c3", ParentTable: ddl.InterleavedParent{Id: "c2"}}
+
+	conv.UpdateInterleaveStructureIssues()
+	assert.Equal(t, map[string]TableIssues{
+		"t8": {TableLevelIssues: []SchemaIssue{InterleaveTooDeep}},
+		"c1": {TableLevelIssues: []SchemaIssue{InterleaveCycle}},
+		"c2": {TableLevelIssues: []SchemaIssue{InterleaveCycle}},
+	}, conv.SchemaIssues)
+	assert.Equal(t, "cycle1 -> cycle2 -> cycle1", conv.InterleaveCycleDescription("c1"))
+	assert.Equal(t, "", conv.InterleaveCycleDescription("c3"))
+
+	delete(conv.SpSchema, "t1")
+	conv.SpSchema["c1"] = ddl.CreateTable{Name: "cycle1", Id: "c1"}
+	conv.UpdateInterleaveStructureIssues()
+	for _, tableIssues := range conv.SchemaIssues {
+		assert.Empty(t, tableIssues.TableLevelIssues)
+	}
+}
+
+func TestInterleaveTree(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema = interleaveChain(3)
+	conv.SpSchema["t4"] = ddl.CreateTable{Name: "table4", Id: "t4", ParentTable: ddl.InterleavedParent{Id: "t1", InterleaveType: "IN"}}
+	conv.SpSchema["t5"] = ddl.CreateTable{Name: "table5", Id: "t5"}
+
+	assert.Equal(t, []*InterleaveNode{
+		{TableId: "t1", Name: "table1", Depth: 1, Children: []*InterleaveNode{
+			{TableId: "t2", Name: "table2", InterleaveType: "IN PARENT", OnDelete: "CASCADE", Depth: 2, Children: []*InterleaveNode{
+				{TableId: "t3", Name: "table3", InterleaveType: "IN PARENT", OnDelete: "CASCADE", Depth: 3, Children: []*InterleaveNode{}},
+			}},
+			{TableId: "t4", Name: "table4", InterleaveType: "IN", Depth: 2, Children: []*InterleaveNode{}},
+		}},
+		{TableId: "t5", Name: "table5", Depth: 1, Children: []*InterleaveNode{}},
+	}, conv.InterleaveTree())
+
+	// Tables interleaved in each other, and the tables interleaved in them,
+	// aren't part of any tree.
+	conv.SpSchema["t6"] = ddl.CreateTable{Name: "table6", Id: "t6", ParentTable: ddl.InterleavedParent{Id: "t7", InterleaveType: "IN PARENT"}}
+	conv.SpSchema["t7"] = ddl.CreateTable{Name: "table7", Id: "t7", ParentTable: ddl.InterleavedParent{Id: "t6", InterleaveType: "IN PARENT"}}
+	conv.SpSchema["t8"] = ddl.CreateTable{Name: "table8", Id: "t8", ParentTable: ddl.InterleavedParent{Id: "t7", InterleaveType: "IN"}}
+	assert.Len(t, conv.InterleaveTree(), 2)
+	assert.Equal(t, 0, conv.InterleaveDepth("t5", "t8"))
+}
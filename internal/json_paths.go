@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// JSONPathSuggestion is a path of a JSON column which the application filters
+// or orders rows by, found by the assessment of its queries. Such paths are
+// worth a generated column extracting them, with an index.
+type JSONPathSuggestion struct {
+	ColId string
+	Path  string // e.g. $.address.city
+	Count int    // Number of queries using the path.
+}
+
+// jsonPathRegexp matches the paths which can be extracted by a generated
+// column: member accesses with identifier keys only.
+var jsonPathRegexp = regexp.MustCompile(`^\$(\.[A-Za-z_][A-Za-z0-9_]*)+$`)
+
+// SetJSONPathSuggestions replaces the JSON path suggestions of the tables
+// with suggestions, and adds the JSONPathIndexSuggestion issue to their
+// columns. Suggestions about unknown columns, columns which are not JSON in
+// Spanner, or paths which can't be extracted by a generated column are
+// ignored. The suggestions of each column are sorted by decreasing count.
+func (conv *Conv) SetJSONPathSuggestions(suggestions map[string][]JSONPathSuggestion) {
+	for tableId, tableIssues := range conv.SchemaIssues {
+		for colId, l := range tableIssues.ColumnLevelIssues {
+			tableIssues.ColumnLevelIssues[colId] = removeIssues(l, []SchemaIssue{JSONPathIndexSuggestion})
+		}
+		conv.SchemaIssues[tableId] = tableIssues
+	}
+	conv.JSONPathSuggestions = make(map[string][]JSONPathSuggestion)
+	if conv.SchemaIssues == nil {
+		conv.SchemaIssues = make(map[string]TableIssues)
+	}
+	for tableId, l := range suggestions {
+		ct, ok := conv.SpSchema[tableId]
+		if !ok {
+			continue
+		}
+		var kept []JSONPathSuggestion
+		for _, s := range l {
+			col, ok := ct.ColDefs[s.ColId]
+			if !ok || col.T.Name != ddl.JSON || col.T.IsArray || !jsonPathRegexp.MatchString(s.Path) {
+				continue
+			}
+			conv.addColumnIssue(tableId, s.ColId, JSONPathIndexSuggestion)
+			kept = append(kept, s)
+		}
+		if len(kept) == 0 {
+			continue
+		}
+		sort.SliceStable(kept, func(i, j int) bool {
+			if kept[i].Count != kept[j].Count {
+				return kept[i].Count > kept[j].Count
+			}
+			return kept[i].Path < kept[j].Path
+		})
+		conv.JSONPathSuggestions[tableId] = kept
+	}
+}
+
+// AddJSONPathIndexes adds, for each JSON path suggestion, a stored generated
+// column with the value of the path as a string and an index on it, so that
+// the queries filtering or ordering by the path don't scan the table.
+// Applications have to use the generated column in their queries. Paths which
+// already have a generated column are skipped. Returns the number of added
+// indexes.
+func (conv *Conv) AddJSONPathIndexes() int {
+	n := 0
+	for _, tableId := range ddl.GetSortedTableIdsBySpName(conv.SpSchema) {
+		ct := conv.SpSchema[tableId]
+		for _, s := range conv.JSONPathSuggestions[tableId] {
+			col, ok := ct.ColDefs[s.ColId]
+			if !ok || col.T.Name != ddl.JSON || col.T.IsArray {
+				continue
+			}
+			stmt := jsonPathStatement(col.Name, s.Path, conv.SpDialect)
+			if hasGeneratedColumn(ct, stmt) {
+				continue
+			}
+			keys := strings.Split(s.Path, ".")[1:]
+			name := conv.buildColumnNameWithBase(tableId, col.Name+"_"+strings.Join(keys, "_"))
+			pathId := GenerateColumnId()
+			ct.ColIds = append(ct.ColIds, pathId)
+			ct.ColDefs[pathId] = ddl.ColumnDef{
+				Name: name,
+				Id:   pathId,
+				T:    ddl.Type{Name: ddl.String, Len: ddl.MaxLength},
+				GeneratedColumn: ddl.GeneratedColumn{
+					IsPresent: true,
+					Value:     ddl.Expression{ExpressionId: GenerateExpressionId(), Statement: stmt},
+					Type:      ddl.GeneratedColStored,
+				},
+			}
+			ct.Indexes = append(ct.Indexes, ddl.CreateIndex{
+				Name:    ToSpannerIndexName(conv, fmt.Sprintf("Index_%s_%s", ct.Name, name)),
+				TableId: tableId,
+				Keys:    []ddl.IndexKey{{ColId: pathId, Order: 1}},
+				Id:      GenerateIndexesId(),
+			})
+			conv.SpSchema[tableId] = ct
+			conv.addColumnIssue(tableId, pathId, JSONPathColumn)
+			n++
+		}
+	}
+	return n
+}
+
+// jsonPathStatement returns the expression extracting path from the JSON
+// column name as a string, in the Spanner dialect. The column name is quoted
+// since it may be a reserved word.
+func jsonPathStatement(name, path, dialect string) string {
+	if dialect != constants.DIALECT_POSTGRESQL {
+		return fmt.Sprintf("JSON_VALUE(`%s`, '%s')", name, path)
+	}
+	keys := strings.Split(path, ".")[1:]
+	stmt := fmt.Sprintf(`"%s"`, name)
+	for i, key := range keys {
+		op := "->"
+		if i == len(keys)-1 {
+			op = "->>"
+		}
+		stmt += fmt.Sprintf(" %s '%s'", op, key)
+	}
+	return stmt
+}
+
+// hasGeneratedColumn returns true if table ct already has a generated column
+// computed by stmt.
+func hasGeneratedColumn(ct ddl.CreateTable, stmt string) bool {
+	for _, col := range ct.ColDefs {
+		if col.GeneratedColumn.IsPresent && col.GeneratedColumn.Value.Statement == stmt {
+			return true
+		}
+	}
+	return false
+}
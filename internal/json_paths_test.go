@@ -0,0 +1,98 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func jsonPathTestConv() *Conv {
+	conv := MakeConv()
+	conv.SpSchema = map[string]ddl.CreateTable{
+		"t1": {
+			Name:   "orders",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Name: "doc", Id: "c2", T: ddl.Type{Name: ddl.JSON}},
+				"c3": {Name: "note", Id: "c3", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+		},
+	}
+	return conv
+}
+
+func TestSetJSONPathSuggestions(t *testing.T) {
+	conv := jsonPathTestConv()
+	conv.SetJSONPathSuggestions(map[string][]JSONPathSuggestion{
+		"t1": {
+			{ColId: "c2", Path: "$.status", Count: 1},
+			{ColId: "c2", Path: "$.customer.city", Count: 3},
+			{ColId: "c2", Path: "$.items[0].sku", Count: 5},
+			{ColId: "c3", Path: "$.status", Count: 2},
+			{ColId: "c4", Path: "$.status", Count: 2},
+		},
+		"t2": {{ColId: "c1", Path: "$.a", Count: 1}},
+	})
+	assert.Equal(t, map[string][]JSONPathSuggestion{
+		"t1": {
+			{ColId: "c2", Path: "$.customer.city", Count: 3},
+			{ColId: "c2", Path: "$.status", Count: 1},
+		},
+	}, conv.JSONPathSuggestions)
+	assert.Equal(t, []SchemaIssue{JSONPathIndexSuggestion}, conv.SchemaIssues["t1"].ColumnLevelIssues["c2"])
+
+	conv.SetJSONPathSuggestions(nil)
+	assert.Empty(t, conv.JSONPathSuggestions)
+	assert.Empty(t, conv.SchemaIssues["t1"].ColumnLevelIssues["c2"])
+}
+
+func TestAddJSONPathIndexes(t *testing.T) {
+	for _, tc := range []struct {
+		dialect string
+		stmts   []string
+	}{
+		{constants.DIALECT_GOOGLESQL, []string{"JSON_VALUE(`doc`, '$.customer.city')", "JSON_VALUE(`doc`, '$.status')"}},
+		{constants.DIALECT_POSTGRESQL, []string{`"doc" -> 'customer' ->> 'city'`, `"doc" ->> 'status'`}},
+	} {
+		conv := jsonPathTestConv()
+		conv.SpDialect = tc.dialect
+		conv.SetJSONPathSuggestions(map[string][]JSONPathSuggestion{
+			"t1": {{ColId: "c2", Path: "$.customer.city", Count: 3}, {ColId: "c2", Path: "$.status", Count: 1}},
+		})
+		assert.Equal(t, 2, conv.AddJSONPathIndexes(), tc.dialect)
+		// Calling it again doesn't add columns twice.
+		assert.Equal(t, 0, conv.AddJSONPathIndexes(), tc.dialect)
+
+		ct := conv.SpSchema["t1"]
+		assert.Equal(t, 5, len(ct.ColIds), tc.dialect)
+		assert.Equal(t, 2, len(ct.Indexes), tc.dialect)
+		for i, name := range []string{"doc_customer_city", "doc_status"} {
+			col := ct.ColDefs[ct.ColIds[3+i]]
+			assert.Equal(t, name, col.Name, tc.dialect)
+			assert.Equal(t, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, col.T, tc.dialect)
+			assert.Equal(t, ddl.GeneratedColumn{IsPresent: true, Value: ddl.Expression{ExpressionId: col.GeneratedColumn.Value.ExpressionId, Statement: tc.stmts[i]}, Type: ddl.GeneratedColStored}, col.GeneratedColumn, tc.dialect)
+			assert.Equal(t, "Index_orders_"+name, ct.Indexes[i].Name, tc.dialect)
+			assert.Equal(t, []ddl.IndexKey{{ColId: col.Id, Order: 1}}, ct.Indexes[i].Keys, tc.dialect)
+			assert.Equal(t, []SchemaIssue{JSONPathColumn}, conv.SchemaIssues["t1"].ColumnLevelIssues[col.Id], tc.dialect)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// Limits of Spanner schemas and commits, see
+// https://cloud.google.com/spanner/quotas.
+const (
+	MaxColumnsPerTable    = 1024
+	MaxIndexesPerTable    = 128
+	MaxKeyColumns         = 16
+	MaxKeySize            = 8192 // Bytes.
+	MaxMutationsPerCommit = 80000
+	MaxCommitSize         = 100 << 20 // Bytes.
+)
+
+// keyValueSize is the size in bytes of the values of the fixed size types in
+// keys and rows.
+var keyValueSize = map[string]int64{
+	ddl.Bool:      1,
+	ddl.Int64:     8,
+	ddl.Float32:   4,
+	ddl.Float64:   8,
+	ddl.Date:      4,
+	ddl.Timestamp: 12,
+	ddl.Numeric:   22,
+}
+
+// LimitViolation is a Spanner limit exceeded by a table.
+type LimitViolation struct {
+	Issue       SchemaIssue
+	Description string
+}
+
+// valueSize returns the maximum size in bytes of the values of type t, or 0
+// if their size isn't bounded by the type, e.g. for STRING(MAX). Lengths of
+// strings count one byte per character, so sizes are lower bounds.
+func valueSize(t ddl.Type) int64 {
+	if t.IsArray {
+		return 0
+	}
+	switch t.Name {
+	case ddl.String, ddl.Bytes:
+		if t.Len == ddl.MaxLength {
+			return 0
+		}
+		return t.Len
+	}
+	return keyValueSize[t.Name]
+}
+
+// keySize returns the maximum size in bytes of a key made of the columns
+// colIds of table ct, ignoring the columns whose size isn't bounded.
+func keySize(ct ddl.CreateTable, colIds []string) int64 {
+	var size int64
+	for _, colId := range colIds {
+		size += valueSize(ct.ColDefs[colId].T)
+	}
+	return size
+}
+
+// checkKey returns the violations of the key limits by the key columns
+// colIds of the primary key or index described by name.
+func checkKey(ct ddl.CreateTable, name string, colIds []string) []LimitViolation {
+	var l []LimitViolation
+	if len(colIds) > MaxKeyColumns {
+		l = append(l, LimitViolation{Issue: KeyLimitExceeded, Description: fmt.Sprintf("%s has %d key columns, the limit is %d", name, len(colIds), MaxKeyColumns)})
+	}
+	if size := keySize(ct, colIds); size > MaxKeySize {
+		l = append(l, LimitViolation{Issue: KeyLimitExceeded, Description: fmt.Sprintf("%s has keys of up to %d bytes, the limit is %d", name, size, MaxKeySize)})
+	}
+	return l
+}
+
+// TableLimitViolations returns the Spanner limits exceeded by table tableId:
+// the number of columns and indexes, the number and size of key columns, the
+// bounds of STRING and BYTES lengths, and the mutations and size of a row
+// compared to the limits of a commit.
+func (conv *Conv) TableLimitViolations(tableId string) []LimitViolation {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return nil
+	}
+	var l []LimitViolation
+	if len(ct.ColIds) > MaxColumnsPerTable {
+		l = append(l, LimitViolation{Issue: TooManyColumns, Description: fmt.Sprintf("the table has %d columns, the limit is %d", len(ct.ColIds), MaxColumnsPerTable)})
+	}
+	if len(ct.Indexes) > MaxIndexesPerTable {
+		l = append(l, LimitViolation{Issue: TooManyIndexes, Description: fmt.Sprintf("the table has %d indexes, the limit is %d", len(ct.Indexes), MaxIndexesPerTable)})
+	}
+	var pkColIds []string
+	for _, pk := range sortedPrimaryKeys(ct) {
+		pkColIds = append(pkColIds, pk.ColId)
+	}
+	l = append(l, checkKey(ct, "the primary key", pkColIds)...)
+	// Index entries are keyed by the index keys followed by the primary key.
+	mutations := len(ct.ColIds)
+	for _, idx := range ct.Indexes {
+		var colIds []string
+		for _, k := range idx.Keys {
+			colIds = append(colIds, k.ColId)
+		}
+		for _, colId := range pkColIds {
+			if !Contains(colIds, colId) {
+				colIds = append(colIds, colId)
+			}
+		}
+		l = append(l, checkKey(ct, fmt.Sprintf("index %s", idx.Name), colIds)...)
+		mutations += len(colIds) + len(idx.StoredColumnIds)
+	}
+	var rowSize int64
+	for _, colId := range ct.ColIds {
+		cd := ct.ColDefs[colId]
+		maxLen := int64(ddl.StringMaxLength)
+		if cd.T.Name == ddl.Bytes {
+			maxLen = ddl.BytesMaxLength
+		}
+		if (cd.T.Name == ddl.String || cd.T.Name == ddl.Bytes) && cd.T.Len != ddl.MaxLength && (cd.T.Len < 1 || cd.T.Len > maxLen) {
+			l = append(l, LimitViolation{Issue: InvalidLength, Description: fmt.Sprintf("column %s is %s(%d), lengths must be between 1 and %d", cd.Name, cd.T.Name, cd.T.Len, maxLen)})
+		}
+		switch {
+		case cd.T.Name == ddl.String && cd.T.Len == ddl.MaxLength:
+			rowSize += ddl.StringMaxLength
+		case cd.T.Name == ddl.Bytes && cd.T.Len == ddl.MaxLength:
+			rowSize += ddl.BytesMaxLength
+		default:
+			rowSize += valueSize(cd.T)
+		}
+	}
+	if mutations > MaxMutationsPerCommit {
+		l = append(l, LimitViolation{Issue: CommitLimitExceeded, Description: fmt.Sprintf("writing a row takes %d mutations, the limit of a commit is %d", mutations, MaxMutationsPerCommit)})
+	}
+	if rowSize > MaxCommitSize {
+		l = append(l, LimitViolation{Issue: WideRow, Description: fmt.Sprintf("rows can be up to %d MB, more than the %d MB limit of a commit", rowSize>>20, MaxCommitSize>>20)})
+	}
+	return l
+}
+
+// limitIssues are the issues of the limits checked by TableLimitViolations.
+var limitIssues = []SchemaIssue{TooManyColumns, TooManyIndexes, KeyLimitExceeded, InvalidLength, CommitLimitExceeded, WideRow}
+
+// UpdateLimitIssues adds the issues of the limits exceeded by each table to
+// the table, and removes the issues of the limits it no longer exceeds.
+func (conv *Conv) UpdateLimitIssues() {
+	for tableId := range conv.SpSchema {
+		exceeded := make(map[SchemaIssue]bool)
+		for _, v := range conv.TableLimitViolations(tableId) {
+			exceeded[v.Issue] = true
+		}
+		for _, issue := range limitIssues {
+			conv.setTableIssue(tableId, issue, exceeded[issue])
+		}
+	}
+}
+
+// ValidateLimits returns an error listing the Spanner limits exceeded by the
+// tables, if any, since their DDL would be rejected or their rows couldn't be
+// written. Rows which may exceed the size of a commit are only reported.
+func (conv *Conv) ValidateLimits() error {
+	var l []string
+	for tableId, ct := range conv.SpSchema {
+		for _, v := range conv.TableLimitViolations(tableId) {
+			if v.Issue != WideRow {
+				l = append(l, fmt.Sprintf("table %s: %s", ct.Name, v.Description))
+			}
+		}
+	}
+	if len(l) == 0 {
+		return nil
+	}
+	sort.Strings(l)
+	return fmt.Errorf("the schema exceeds Spanner limits, fix it before applying the schema: %s", strings.Join(l, "; "))
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func limitsTestTable(cols int, t ddl.Type) ddl.CreateTable {
+	ct := ddl.CreateTable{Name: "wide", Id: "t1", ColDefs: map[string]ddl.ColumnDef{}}
+	for i := 0; i < cols; i++ {
+		colId := fmt.Sprintf("c%d", i)
+		ct.ColIds = append(ct.ColIds, colId)
+		ct.ColDefs[colId] = ddl.ColumnDef{Name: fmt.Sprintf("col%d", i), Id: colId, T: t}
+	}
+	ct.PrimaryKeys = []ddl.IndexKey{{ColId: "c0", Order: 1}}
+	return ct
+}
+
+func TestTableLimitViolations(t *testing.T) {
+	int64Type := ddl.Type{Name: ddl.Int64}
+	tests := []struct {
+		name     string
+		table    func() ddl.CreateTable
+		expected []LimitViolation
+	}{
+		{
+			name:  "within limits",
+			table: func() ddl.CreateTable { return limitsTestTable(10, int64Type) },
+		},
+		{
+			name:  "too many columns",
+			table: func() ddl.CreateTable { return limitsTestTable(1025, int64Type) },
+			expected: []LimitViolation{
+				{Issue: TooManyColumns, Description: "the table has 1025 columns, the limit is 1024"},
+			},
+		},
+		{
+			name: "too many indexes",
+			table: func() ddl.CreateTable {
+				ct := limitsTestTable(2, int64Type)
+				for i := 0; i < 129; i++ {
+					ct.Indexes = append(ct.Indexes, ddl.CreateIndex{Name: fmt.Sprintf("idx%d", i), Keys: []ddl.IndexKey{{ColId: "c1"}}})
+				}
+				return ct
+			},
+			expected: []LimitViolation{
+				{Issue: TooManyIndexes, Description: "the table has 129 indexes, the limit is 128"},
+			},
+		},
+		{
+			name: "key limits",
+			table: func() ddl.CreateTable {
+				ct := limitsTestTable(17, ddl.Type{Name: ddl.String, Len: 600})
+				ct.PrimaryKeys = nil
+				for i, colId := range ct.ColIds {
+					ct.PrimaryKeys = append(ct.PrimaryKeys, ddl.IndexKey{ColId: colId, Order: i + 1})
+				}
+				ct.Indexes = []ddl.CreateIndex{{Name: "idx", Keys: []ddl.IndexKey{{ColId: "c1"}}}}
+				return ct
+			},
+			expected: []LimitViolation{
+				{Issue: KeyLimitExceeded, Description: "the primary key has 17 key columns, the limit is 16"},
+				{Issue: KeyLimitExceeded, Description: "the primary key has keys of up to 10200 bytes, the limit is 8192"},
+				{Issue: KeyLimitExceeded, Description: "index idx has 17 key columns, the limit is 16"},
+				{Issue: KeyLimitExceeded, Description: "index idx has keys of up to 10200 bytes, the limit is 8192"},
+			},
+		},
+		{
+			name: "unbounded keys",
+			table: func() ddl.CreateTable {
+				return limitsTestTable(1, ddl.Type{Name: ddl.String, Len: ddl.MaxLength})
+			},
+		},
+		{
+			name: "invalid lengths",
+			table: func() ddl.CreateTable {
+				ct := limitsTestTable(3, int64Type)
+				ct.ColDefs["c1"] = ddl.ColumnDef{Name: "s", Id: "c1", T: ddl.Type{Name: ddl.String, Len: 3000000}}
+				ct.ColDefs["c2"] = ddl.ColumnDef{Name: "b", Id: "c2", T: ddl.Type{Name: ddl.Bytes, Len: 0}}
+				return ct
+			},
+			expected: []LimitViolation{
+				{Issue: InvalidLength, Description: "column s is STRING(3000000), lengths must be between 1 and 2621440"},
+				{Issue: InvalidLength, Description: "column b is BYTES(0), lengths must be between 1 and 10485760"},
+			},
+		},
+		{
+			name:  "wide rows",
+			table: func() ddl.CreateTable { return limitsTestTable(11, ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}) },
+			expected: []LimitViolation{
+				{Issue: WideRow, Description: "rows can be up to 110 MB, more than the 100 MB limit of a commit"},
+			},
+		},
+		{
+			name: "too many mutations",
+			table: func() ddl.CreateTable {
+				ct := limitsTestTable(1000, int64Type)
+				for i := 0; i < 100; i++ {
+					ct.Indexes = append(ct.Indexes, ddl.CreateIndex{Name: fmt.Sprintf("idx%d", i), Keys: []ddl.IndexKey{{ColId: "c1"}}, StoredColumnIds: ct.ColIds[2:800]})
+				}
+				return ct
+			},
+			expected: []LimitViolation{
+				{Issue: CommitLimitExceeded, Description: "writing a row takes 81000 mutations, the limit of a commit is 80000"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		conv := MakeConv()
+		conv.SpSchema = ddl.Schema{"t1": tc.table()}
+		assert.Equal(t, tc.expected, conv.TableLimitViolations("t1"), tc.name)
+	}
+}
+
+func TestUpdateLimitIssues(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema = ddl.Schema{"t1": limitsTestTable(1025, ddl.Type{Name: ddl.Int64})}
+	conv.UpdateLimitIssues()
+	assert.Equal(t, []SchemaIssue{TooManyColumns}, conv.SchemaIssues["t1"].TableLevelIssues)
+	assert.EqualError(t, conv.ValidateLimits(), "the schema exceeds Spanner limits, fix it before applying the schema: table wide: the table has 1025 columns, the limit is 1024")
+
+	conv.SpSchema["t1"] = limitsTestTable(11, ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength})
+	conv.UpdateLimitIssues()
+	assert.Equal(t, []SchemaIssue{WideRow}, conv.SchemaIssues["t1"].TableLevelIssues)
+	// Wide rows are only reported.
+	assert.NoError(t, conv.ValidateLimits())
+}
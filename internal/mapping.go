@@ -88,7 +88,7 @@ func GetSpannerCol(conv *Conv, tableId, colId string, spColDef map[string]ddl.Co
 		// so need to iterate
 		id := len(spColDef)
 		for {
-			c := spColName + "_" + strconv.Itoa(id)
+			c := AppendNameSuffix(spColName, "_"+strconv.Itoa(id))
 			if _, found := usedColNames[c]; !found {
 				spColName = c
 				break
@@ -264,7 +264,7 @@ func GetSpannerValidName(conv *Conv, srcName string) string {
 		// so need to iterate.
 		id := len(conv.UsedNames)
 		for {
-			c := spKeyName + "_" + strconv.Itoa(id)
+			c := AppendNameSuffix(spKeyName, "_"+strconv.Itoa(id))
 			if _, found := conv.UsedNames[strings.ToLower(c)]; !found {
 				spKeyName = c
 				break
@@ -15,6 +15,7 @@
 package internal
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
@@ -511,6 +512,11 @@ func TestGetSpannerID(t *testing.T) {
 		spKeyName := GetSpannerValidName(conv, tc.srcKeyName)
 		assert.Equal(t, tc.spKeyName, spKeyName, tc.name)
 	}
+	long := strings.Repeat("k", MaxIdentifierLength)
+	assert.Equal(t, long, GetSpannerValidName(conv, long))
+	collision := GetSpannerValidName(conv, long)
+	assert.Equal(t, MaxIdentifierLength, len(collision))
+	assert.True(t, strings.HasSuffix(collision, "_15"))
 }
 
 func TestResolveRefs(t *testing.T) {
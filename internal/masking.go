@@ -0,0 +1,556 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	mrand "math/rand"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// Strategies used to mask the values of columns during data migration, so
+// that non-production databases can be populated without personal data.
+// NULL values stay NULL, except shuffled ones which are moved like the other
+// values.
+const (
+	MaskHash      = "hash"      // A keyed hash of the value. Equal values get the same hash in every table, so that keys and joins keep working.
+	MaskShuffle   = "shuffle"   // The value of another row among the next shuffleWindowSize rows of the table.
+	MaskSynthetic = "synthetic" // A fake value derived from the value, e.g. a fake email for an email.
+	MaskNullify   = "nullify"   // NULL.
+)
+
+// Kinds of the fake values of STRING columns masked with MaskSynthetic.
+const (
+	MaskKindText    = "text" // Random letters, the default.
+	MaskKindName    = "name"
+	MaskKindEmail   = "email"
+	MaskKindPhone   = "phone"
+	MaskKindAddress = "address"
+)
+
+// MaskingKeyEnv is the environment variable with the key of the hash and
+// synthetic strategies. Without it, a random key is used and masked values
+// differ from one migration to the next.
+const MaskingKeyEnv = "SMT_MASKING_KEY"
+
+// shuffleWindowSize is the number of rows of a table with shuffled columns
+// held back to exchange their values.
+const shuffleWindowSize = 1000
+
+// minHashLength is the minimum length of STRING and BYTES columns masked with
+// MaskHash, to keep hashes unique.
+const minHashLength = 16
+
+var (
+	fakeFirstNames = []string{"Alex", "Blair", "Casey", "Dana", "Eden", "Frankie", "Gray", "Harper", "Indy", "Jordan", "Kai", "Logan", "Morgan", "Noel", "Parker", "Quinn", "Riley", "Sage", "Taylor", "Val"}
+	fakeLastNames  = []string{"Adams", "Brooks", "Carter", "Diaz", "Evans", "Foster", "Garcia", "Hughes", "Ito", "Jensen", "Kim", "Lopez", "Moreau", "Novak", "Okafor", "Patel", "Rossi", "Silva", "Tanaka", "Weber"}
+	fakeStreets    = []string{"Oak", "Maple", "Cedar", "Pine", "Elm", "Willow", "Birch", "Lake", "Hill", "Park"}
+)
+
+// ColumnMask specifies how to mask the values of a Spanner column during
+// data migration. Kind is the kind of fake values of STRING columns masked
+// with MaskSynthetic.
+type ColumnMask struct {
+	Strategy string
+	Kind     string `json:",omitempty"`
+}
+
+// MaskingProfile is a set of column masks, e.g. for a staging environment,
+// read from a JSON file. Tables and columns are named by their Spanner names:
+//
+//	{"Tables": {"users": {"email": {"Strategy": "synthetic", "Kind": "email"}}}}
+type MaskingProfile struct {
+	Tables map[string]map[string]ColumnMask
+}
+
+// maskState is the state of the masking of the rows of a migration.
+type maskState struct {
+	key      []byte    // Random key of the hashes when MaskingKeyEnv isn't set.
+	keyOnce  sync.Once // Sets key on the first hash, so that conversions without masked columns stay comparable.
+	mu       sync.Mutex
+	shuffles map[string]*shuffleWindow // By table id.
+}
+
+// shuffleWindow holds the rows of a table with shuffled columns which aren't
+// written yet. The values of the shuffled columns are exchanged between the
+// rows of the window, so that no row keeps its own values, unless a table
+// has a single row.
+type shuffleWindow struct {
+	rows []maskedRow
+	cols []string // Names of the shuffled columns.
+	rand *mrand.Rand
+}
+
+// maskedRow is a row of WriteRow whose columns are masked.
+type maskedRow struct {
+	srcTable, spTable string
+	spCols            []string
+	spVals            []interface{}
+}
+
+func newMaskState() *maskState {
+	return &maskState{shuffles: make(map[string]*shuffleWindow)}
+}
+
+// hashKey returns the key of the hashes, read from MaskingKeyEnv or
+// generated on the first call.
+func (m *maskState) hashKey() []byte {
+	m.keyOnce.Do(func() {
+		if k := os.Getenv(MaskingKeyEnv); k != "" {
+			m.key = []byte(k)
+			return
+		}
+		m.key = make([]byte, 32)
+		rand.Read(m.key)
+	})
+	return m.key
+}
+
+// SetColumnMask sets the strategy masking the column colId of the Spanner
+// table tableId during data migration, or removes it if the strategy is
+// empty. Primary key columns can only be hashed, which keeps them unique.
+// The columns of foreign keys, the columns they reference and the primary
+// keys of interleaved tables can only be hashed too, and are masked together
+// so that the masked rows keep referencing each other.
+func (conv *Conv) SetColumnMask(tableId, colId string, mask ColumnMask) error {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return fmt.Errorf("table doesn't exist for tableId %s", tableId)
+	}
+	col, ok := ct.ColDefs[colId]
+	if !ok {
+		return fmt.Errorf("column %s doesn't exist in table %s", colId, ct.Name)
+	}
+	linked := conv.linkedKeyColumns(tableId, colId)
+	if mask.Strategy == "" {
+		for _, c := range linked {
+			delete(conv.ColumnMasks[c.tableId], c.colId)
+		}
+		return nil
+	}
+	if len(linked) > 1 && mask.Strategy != MaskHash {
+		return fmt.Errorf("column %s is a key referenced by or referencing another table, it can only be masked with %s", col.Name, MaskHash)
+	}
+	for _, c := range linked {
+		other := conv.SpSchema[c.tableId]
+		otherCol := other.ColDefs[c.colId]
+		if otherCol.T.Name != col.T.Name || otherCol.T.Len != col.T.Len {
+			return fmt.Errorf("column %s of table %s must have the same type as column %s of table %s to be hashed alike", otherCol.Name, other.Name, col.Name, ct.Name)
+		}
+		if err := checkColumnMask(other, otherCol, mask); err != nil {
+			return err
+		}
+	}
+	if conv.ColumnMasks == nil {
+		conv.ColumnMasks = make(map[string]map[string]ColumnMask)
+	}
+	for _, c := range linked {
+		if conv.ColumnMasks[c.tableId] == nil {
+			conv.ColumnMasks[c.tableId] = make(map[string]ColumnMask)
+		}
+		conv.ColumnMasks[c.tableId][c.colId] = mask
+	}
+	return nil
+}
+
+// HasColumnMasks returns whether columns are masked during data migration.
+func (conv *Conv) HasColumnMasks() bool {
+	for _, masks := range conv.ColumnMasks {
+		if len(masks) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkColumnMask returns an error if mask can't mask column col of ct.
+func checkColumnMask(ct ddl.CreateTable, col ddl.ColumnDef, mask ColumnMask) error {
+	isKey := slices.ContainsFunc(ct.PrimaryKeys, func(k ddl.IndexKey) bool { return k.ColId == col.Id })
+	if isKey && mask.Strategy != MaskHash {
+		return fmt.Errorf("primary key column %s can only be masked with %s", col.Name, MaskHash)
+	}
+	if col.T.IsArray && mask.Strategy != MaskShuffle && mask.Strategy != MaskNullify {
+		return fmt.Errorf("array column %s can only be masked with %s or %s", col.Name, MaskShuffle, MaskNullify)
+	}
+	if col.GeneratedColumn.IsPresent {
+		return fmt.Errorf("generated column %s can't be masked", col.Name)
+	}
+	switch mask.Strategy {
+	case MaskHash:
+		switch col.T.Name {
+		case ddl.String, ddl.Bytes:
+			if col.T.Len != ddl.MaxLength && col.T.Len < minHashLength {
+				return fmt.Errorf("column %s is too short to hold a hash, it needs a length of at least %d", col.Name, minHashLength)
+			}
+		case ddl.Int64:
+		default:
+			return fmt.Errorf("hash can only mask %s, %s or %s columns, column %s is %s", ddl.String, ddl.Bytes, ddl.Int64, col.Name, col.T.Name)
+		}
+	case MaskShuffle:
+		// Shuffled values would still be unique, but rows would be found
+		// by the key of another row.
+		for _, index := range ct.Indexes {
+			if index.Unique && slices.ContainsFunc(index.Keys, func(k ddl.IndexKey) bool { return k.ColId == col.Id }) {
+				return fmt.Errorf("column %s of unique index %s can't be shuffled", col.Name, index.Name)
+			}
+		}
+	case MaskSynthetic:
+		switch col.T.Name {
+		case ddl.String:
+			switch mask.Kind {
+			case "", MaskKindText, MaskKindName, MaskKindEmail, MaskKindPhone, MaskKindAddress:
+			default:
+				return fmt.Errorf("unknown synthetic kind %q, expected one of %s, %s, %s, %s or %s", mask.Kind, MaskKindText, MaskKindName, MaskKindEmail, MaskKindPhone, MaskKindAddress)
+			}
+		case ddl.Int64, ddl.Float32, ddl.Float64, ddl.Bool, ddl.Date, ddl.Timestamp:
+		default:
+			return fmt.Errorf("synthetic values can't mask column %s of type %s", col.Name, col.T.Name)
+		}
+	case MaskNullify:
+		if col.NotNull {
+			return fmt.Errorf("NOT NULL column %s can't be nullified", col.Name)
+		}
+	default:
+		return fmt.Errorf("unknown mask strategy %q, expected one of %s, %s, %s or %s", mask.Strategy, MaskHash, MaskShuffle, MaskSynthetic, MaskNullify)
+	}
+	return nil
+}
+
+// tableColumn is a column colId of table tableId.
+type tableColumn struct {
+	tableId, colId string
+}
+
+// linkedKeyColumns returns column colId of table tableId and the columns
+// whose values must be equal to its values for rows to reference each other:
+// the columns of the foreign keys referencing it or it is part of, the
+// columns they reference, and the columns at the same position of the primary
+// keys of interleaved tables, transitively.
+func (conv *Conv) linkedKeyColumns(tableId, colId string) []tableColumn {
+	linked := []tableColumn{{tableId, colId}}
+	seen := map[tableColumn]bool{linked[0]: true}
+	add := func(c tableColumn) {
+		if _, ok := conv.SpSchema[c.tableId].ColDefs[c.colId]; ok && !seen[c] {
+			seen[c] = true
+			linked = append(linked, c)
+		}
+	}
+	pkIndex := func(ct ddl.CreateTable, colId string) int {
+		return slices.IndexFunc(ct.PrimaryKeys, func(k ddl.IndexKey) bool { return k.ColId == colId })
+	}
+	for i := 0; i < len(linked); i++ {
+		c := linked[i]
+		ct := conv.SpSchema[c.tableId]
+		for _, other := range conv.SpSchema {
+			for _, fk := range other.ForeignKeys {
+				for j := range fk.ColIds {
+					if j >= len(fk.ReferColumnIds) {
+						break
+					}
+					if other.Id == c.tableId && fk.ColIds[j] == c.colId {
+						add(tableColumn{fk.ReferTableId, fk.ReferColumnIds[j]})
+					}
+					if fk.ReferTableId == c.tableId && fk.ReferColumnIds[j] == c.colId {
+						add(tableColumn{other.Id, fk.ColIds[j]})
+					}
+				}
+			}
+			if k := pkIndex(ct, c.colId); k >= 0 && other.ParentTable.Id == c.tableId && other.Id != c.tableId && k < len(other.PrimaryKeys) {
+				add(tableColumn{other.Id, other.PrimaryKeys[k].ColId})
+			}
+		}
+		if parent, ok := conv.SpSchema[ct.ParentTable.Id]; ok && parent.Id != c.tableId {
+			if k := pkIndex(ct, c.colId); k >= 0 && k < len(parent.PrimaryKeys) {
+				add(tableColumn{parent.Id, parent.PrimaryKeys[k].ColId})
+			}
+		}
+	}
+	return linked
+}
+
+// ReadMaskingProfile reads the masking profile in the JSON file path.
+func ReadMaskingProfile(path string) (MaskingProfile, error) {
+	var profile MaskingProfile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return profile, fmt.Errorf("can't read masking profile %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return profile, fmt.Errorf("can't parse masking profile %s: %v", path, err)
+	}
+	return profile, nil
+}
+
+// ApplyMaskingProfile sets the column masks of profile, in addition to the
+// masks set in the session.
+func (conv *Conv) ApplyMaskingProfile(profile MaskingProfile) error {
+	for tableName, masks := range profile.Tables {
+		tableId, err := GetTableIdFromSpName(conv.SpSchema, tableName)
+		if err != nil {
+			return fmt.Errorf("masking profile: table %s doesn't exist", tableName)
+		}
+		for colName, mask := range masks {
+			colId, err := GetColIdFromSpName(conv.SpSchema[tableId].ColDefs, colName)
+			if err != nil {
+				return fmt.Errorf("masking profile: column %s doesn't exist in table %s", colName, tableName)
+			}
+			if err := conv.SetColumnMask(tableId, colId, mask); err != nil {
+				return fmt.Errorf("masking profile: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// maskColumns replaces the values of the masked columns of spTable, except
+// the values of the shuffled columns, whose names are returned: they are
+// exchanged between rows by maskState.shuffle.
+func (conv *Conv) maskColumns(spTable string, spCols []string, spVals []interface{}) ([]interface{}, []string, error) {
+	if len(conv.ColumnMasks) == 0 {
+		return spVals, nil, nil
+	}
+	tableId, err := GetTableIdFromSpName(conv.SpSchema, spTable)
+	if err != nil {
+		return spVals, nil, nil
+	}
+	masks := conv.ColumnMasks[tableId]
+	if len(masks) == 0 {
+		return spVals, nil, nil
+	}
+	if conv.masking == nil {
+		return spVals, nil, fmt.Errorf("masking is not initialized")
+	}
+	newVals := append([]interface{}{}, spVals...)
+	var shuffled []string
+	for colId, mask := range masks {
+		colDef, ok := conv.SpSchema[tableId].ColDefs[colId]
+		if !ok {
+			continue
+		}
+		i := slices.Index(spCols, colDef.Name)
+		if i == -1 {
+			continue
+		}
+		if mask.Strategy == MaskShuffle {
+			shuffled = append(shuffled, colDef.Name)
+			continue
+		}
+		if newVals[i] == nil || newVals[i] == CommitTimestamp {
+			continue
+		}
+		v, err := conv.masking.mask(colDef.T, mask, newVals[i])
+		if err != nil {
+			return spVals, nil, fmt.Errorf("can't mask column %s: %v", colDef.Name, err)
+		}
+		newVals[i] = v
+	}
+	return newVals, shuffled, nil
+}
+
+// FlushMaskedRows writes the rows held back to shuffle the values of their
+// masked columns. It must be called once the rows of the migrated tables are
+// converted, before flushing the data sink.
+func (conv *Conv) FlushMaskedRows() {
+	if conv.masking == nil {
+		return
+	}
+	for _, row := range conv.masking.flush() {
+		conv.writeMaskedRow(row.srcTable, row.spTable, row.spCols, row.spVals)
+	}
+}
+
+// mask returns the masked value of v, a value of a column of type ty. The
+// values of shuffled columns are masked by shuffle.
+func (m *maskState) mask(ty ddl.Type, mask ColumnMask, v interface{}) (interface{}, error) {
+	switch mask.Strategy {
+	case MaskHash:
+		return m.hash(ty, v)
+	case MaskSynthetic:
+		return m.synthetic(ty, mask.Kind, v)
+	case MaskNullify:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown mask strategy %q", mask.Strategy)
+}
+
+// sum returns the keyed hash of v.
+func (m *maskState) sum(v interface{}) []byte {
+	h := hmac.New(sha256.New, m.hashKey())
+	h.Write([]byte(formatFillValue(v)))
+	return h.Sum(nil)
+}
+
+func (m *maskState) hash(ty ddl.Type, v interface{}) (interface{}, error) {
+	sum := m.sum(v)
+	switch x := v.(type) {
+	case string:
+		return truncate(hex.EncodeToString(sum), ty.Len), nil
+	case []byte:
+		if ty.Len != ddl.MaxLength && int64(len(sum)) > ty.Len {
+			return sum[:ty.Len], nil
+		}
+		return sum, nil
+	case int64:
+		return int64(binary.BigEndian.Uint64(sum) >> 1), nil
+	default:
+		return nil, fmt.Errorf("can't hash value %v of type %T", x, v)
+	}
+}
+
+// shuffle adds row, whose columns cols are shuffled, to the window of its
+// table, and returns the rows to write. Once the window is full, its oldest
+// row exchanges the values of cols with another row of the window and is
+// written.
+func (m *maskState) shuffle(row maskedRow, cols []string) []maskedRow {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.shuffles[row.spTable]
+	if !ok {
+		w = &shuffleWindow{rand: mrand.New(mrand.NewSource(time.Now().UnixNano()))}
+		m.shuffles[row.spTable] = w
+	}
+	w.cols = cols
+	w.rows = append(w.rows, row)
+	if len(w.rows) <= shuffleWindowSize {
+		return nil
+	}
+	w.exchange(0, 1+w.rand.Intn(len(w.rows)-1))
+	oldest := w.rows[0]
+	w.rows[0] = maskedRow{}
+	w.rows = w.rows[1:]
+	return []maskedRow{oldest}
+}
+
+// flush empties the windows, and returns their rows once they exchanged
+// the values of their shuffled columns along a random cycle, so that no row
+// keeps its values.
+func (m *maskState) flush() []maskedRow {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var rows []maskedRow
+	for _, w := range m.shuffles {
+		// Sattolo's algorithm.
+		for i := len(w.rows) - 1; i > 0; i-- {
+			w.exchange(i, w.rand.Intn(i))
+		}
+		rows = append(rows, w.rows...)
+	}
+	m.shuffles = make(map[string]*shuffleWindow)
+	return rows
+}
+
+// exchange exchanges the values of the shuffled columns of rows i and j.
+func (w *shuffleWindow) exchange(i, j int) {
+	a, b := w.rows[i], w.rows[j]
+	for _, col := range w.cols {
+		k, l := slices.Index(a.spCols, col), slices.Index(b.spCols, col)
+		if k >= 0 && l >= 0 {
+			a.spVals[k], b.spVals[l] = b.spVals[l], a.spVals[k]
+		}
+	}
+}
+
+// synthetic returns a fake value of type ty derived from v, so that equal
+// values get the same fake value.
+func (m *maskState) synthetic(ty ddl.Type, kind string, v interface{}) (interface{}, error) {
+	sum := m.sum(v)
+	r := mrand.New(mrand.NewSource(int64(binary.BigEndian.Uint64(sum))))
+	switch x := v.(type) {
+	case string:
+		return truncate(fakeString(r, kind, x), ty.Len), nil
+	case int64:
+		if x == 0 {
+			return x, nil
+		}
+		// A number with as many digits and the same sign.
+		digits := len(fmt.Sprint(x))
+		if x < 0 {
+			digits--
+		}
+		lo := int64(math.Pow10(digits - 1))
+		span := int64(math.MaxInt64) - lo
+		if digits < 19 {
+			span = 9 * lo
+		}
+		n := lo + r.Int63n(span)
+		if x < 0 {
+			n = -n
+		}
+		return n, nil
+	case float64:
+		return x * (0.5 + r.Float64()), nil
+	case float32:
+		return x * float32(0.5+r.Float64()), nil
+	case bool:
+		return r.Intn(2) == 1, nil
+	case civil.Date:
+		return x.AddDays(r.Intn(731) - 365), nil
+	case time.Time:
+		return x.Add(time.Duration(r.Int63n(int64(730*24*time.Hour))) - 365*24*time.Hour), nil
+	default:
+		return nil, fmt.Errorf("can't generate a synthetic value for %v of type %T", x, v)
+	}
+}
+
+// fakeString returns a fake string of the given kind, replacing s.
+func fakeString(r *mrand.Rand, kind, s string) string {
+	pick := func(l []string) string { return l[r.Intn(len(l))] }
+	switch kind {
+	case MaskKindName:
+		return pick(fakeFirstNames) + " " + pick(fakeLastNames)
+	case MaskKindEmail:
+		return fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(pick(fakeFirstNames)), strings.ToLower(pick(fakeLastNames)), r.Intn(1000))
+	case MaskKindPhone:
+		return fmt.Sprintf("+1-555-%03d-%04d", r.Intn(1000), r.Intn(10000))
+	case MaskKindAddress:
+		return fmt.Sprintf("%d %s Street", 1+r.Intn(9999), pick(fakeStreets))
+	}
+	// Random letters, keeping the length and the other characters.
+	b := []rune(s)
+	for i, c := range b {
+		switch {
+		case c >= 'a' && c <= 'z':
+			b[i] = rune('a' + r.Intn(26))
+		case c >= 'A' && c <= 'Z':
+			b[i] = rune('A' + r.Intn(26))
+		case c >= '0' && c <= '9':
+			b[i] = rune('0' + r.Intn(10))
+		}
+	}
+	return string(b)
+}
+
+// truncate returns the first n characters of s, or s if n is
+// ddl.MaxLength.
+func truncate(s string, n int64) string {
+	if n == ddl.MaxLength || int64(len([]rune(s))) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
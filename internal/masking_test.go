@@ -0,0 +1,213 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/civil"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func maskTestConv() *Conv {
+	conv := MakeConv()
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Name:   "users",
+		Id:     "t1",
+		ColIds: []string{"c1", "c2", "c3", "c4", "c5", "c6", "c7"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.String, Len: 36}, NotNull: true},
+			"c2": {Name: "email", Id: "c2", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+			"c3": {Name: "phone", Id: "c3", T: ddl.Type{Name: ddl.String, Len: 10}},
+			"c4": {Name: "salary", Id: "c4", T: ddl.Type{Name: ddl.Int64}},
+			"c5": {Name: "birth", Id: "c5", T: ddl.Type{Name: ddl.Date}, NotNull: true},
+			"c6": {Name: "city", Id: "c6", T: ddl.Type{Name: ddl.String, Len: 50}},
+			"c7": {Name: "score", Id: "c7", T: ddl.Type{Name: ddl.Numeric}},
+		},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+	}
+	return conv
+}
+
+func TestSetColumnMask(t *testing.T) {
+	conv := maskTestConv()
+	assert.NotNil(t, conv.SetColumnMask("t2", "c1", ColumnMask{Strategy: MaskHash}))
+	assert.NotNil(t, conv.SetColumnMask("t1", "c9", ColumnMask{Strategy: MaskHash}))
+	assert.NotNil(t, conv.SetColumnMask("t1", "c2", ColumnMask{Strategy: "redact"}))
+	assert.NotNil(t, conv.SetColumnMask("t1", "c1", ColumnMask{Strategy: MaskShuffle}))
+	assert.NotNil(t, conv.SetColumnMask("t1", "c3", ColumnMask{Strategy: MaskHash}))
+	assert.NotNil(t, conv.SetColumnMask("t1", "c5", ColumnMask{Strategy: MaskHash}))
+	assert.NotNil(t, conv.SetColumnMask("t1", "c5", ColumnMask{Strategy: MaskNullify}))
+	assert.NotNil(t, conv.SetColumnMask("t1", "c7", ColumnMask{Strategy: MaskSynthetic}))
+	assert.NotNil(t, conv.SetColumnMask("t1", "c2", ColumnMask{Strategy: MaskSynthetic, Kind: "ssn"}))
+
+	assert.Nil(t, conv.SetColumnMask("t1", "c1", ColumnMask{Strategy: MaskHash}))
+	assert.Nil(t, conv.SetColumnMask("t1", "c2", ColumnMask{Strategy: MaskSynthetic, Kind: MaskKindEmail}))
+	assert.Equal(t, map[string]ColumnMask{
+		"c1": {Strategy: MaskHash},
+		"c2": {Strategy: MaskSynthetic, Kind: MaskKindEmail},
+	}, conv.ColumnMasks["t1"])
+
+	assert.Nil(t, conv.SetColumnMask("t1", "c2", ColumnMask{}))
+	assert.Equal(t, map[string]ColumnMask{"c1": {Strategy: MaskHash}}, conv.ColumnMasks["t1"])
+}
+
+func TestApplyMaskingProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "staging.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{"Tables": {"users": {"email": {"Strategy": "synthetic", "Kind": "email"}, "city": {"Strategy": "nullify"}}}}`), 0644))
+	profile, err := ReadMaskingProfile(path)
+	assert.Nil(t, err)
+	conv := maskTestConv()
+	assert.Nil(t, conv.ApplyMaskingProfile(profile))
+	assert.Equal(t, map[string]ColumnMask{
+		"c2": {Strategy: MaskSynthetic, Kind: MaskKindEmail},
+		"c6": {Strategy: MaskNullify},
+	}, conv.ColumnMasks["t1"])
+
+	assert.NotNil(t, conv.ApplyMaskingProfile(MaskingProfile{Tables: map[string]map[string]ColumnMask{"orders": {"id": {Strategy: MaskHash}}}}))
+	assert.NotNil(t, conv.ApplyMaskingProfile(MaskingProfile{Tables: map[string]map[string]ColumnMask{"users": {"name": {Strategy: MaskHash}}}}))
+	assert.NotNil(t, conv.ApplyMaskingProfile(MaskingProfile{Tables: map[string]map[string]ColumnMask{"users": {"birth": {Strategy: MaskNullify}}}}))
+	_, err = ReadMaskingProfile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.NotNil(t, err)
+}
+
+func TestWriteRowColumnMask(t *testing.T) {
+	conv := maskTestConv()
+	assert.Nil(t, conv.SetColumnMask("t1", "c1", ColumnMask{Strategy: MaskHash}))
+	assert.Nil(t, conv.SetColumnMask("t1", "c2", ColumnMask{Strategy: MaskSynthetic, Kind: MaskKindEmail}))
+	assert.Nil(t, conv.SetColumnMask("t1", "c3", ColumnMask{Strategy: MaskSynthetic, Kind: MaskKindPhone}))
+	assert.Nil(t, conv.SetColumnMask("t1", "c4", ColumnMask{Strategy: MaskSynthetic}))
+	assert.Nil(t, conv.SetColumnMask("t1", "c5", ColumnMask{Strategy: MaskSynthetic}))
+	assert.Nil(t, conv.SetColumnMask("t1", "c6", ColumnMask{Strategy: MaskNullify}))
+	conv.SetDataMode()
+	var rows [][]interface{}
+	conv.SetDataSink(func(table string, c []string, v []interface{}) {
+		rows = append(rows, v)
+	})
+	cols := []string{"id", "email", "phone", "salary", "birth", "city"}
+	birth := civil.Date{Year: 1990, Month: 5, Day: 17}
+	row := []interface{}{"0b6a5c1e-2f5d-4a8e-9a43-61f1c1d5e6a1", "ada@lovelace.org", "5551234567", int64(52000), birth, "London"}
+	conv.WriteRow("src", "users", cols, row)
+	conv.WriteRow("src", "users", cols, row)
+	conv.WriteRow("src", "users", cols, []interface{}{"e3c1f7a2-9b1d-4c6e-8f20-3d4a5b6c7d8e", nil, nil, int64(-7), birth, nil})
+	assert.Equal(t, 3, len(rows))
+	assert.Equal(t, int64(0), conv.BadRows())
+
+	masked := rows[0]
+	// The source values of the row aren't modified.
+	assert.Equal(t, "ada@lovelace.org", row[1])
+	assert.Len(t, masked[0], 36)
+	assert.NotEqual(t, row[0], masked[0])
+	assert.True(t, strings.HasSuffix(masked[1].(string), "@example.com"))
+	assert.Len(t, masked[2], 10)
+	assert.Len(t, fmt.Sprint(masked[3]), 5)
+	assert.LessOrEqual(t, birth.DaysSince(masked[4].(civil.Date)), 365)
+	assert.GreaterOrEqual(t, birth.DaysSince(masked[4].(civil.Date)), -365)
+	assert.Nil(t, masked[5])
+	// Equal values are masked in the same way.
+	assert.Equal(t, masked, rows[1])
+	// NULL values stay NULL.
+	assert.Nil(t, rows[2][1])
+	assert.Less(t, rows[2][3].(int64), int64(0))
+}
+
+func TestShuffle(t *testing.T) {
+	conv := maskTestConv()
+	assert.Nil(t, conv.SetColumnMask("t1", "c6", ColumnMask{Strategy: MaskShuffle}))
+	conv.SetDataMode()
+	written := make(map[string]string)
+	conv.SetDataSink(func(table string, c []string, v []interface{}) {
+		written[v[0].(string)] = v[1].(string)
+	})
+	cols := []string{"id", "city"}
+	n := 2*shuffleWindowSize + 10
+	for i := 0; i < n; i++ {
+		conv.WriteRow("src", "users", cols, []interface{}{fmt.Sprint(i), fmt.Sprint("city", i)})
+		// Rows are held back until the window is full.
+		assert.Equal(t, max(0, i+1-shuffleWindowSize), len(written))
+	}
+	conv.FlushMaskedRows()
+	assert.Equal(t, n, len(written))
+	cities := make(map[string]bool)
+	for id, city := range written {
+		// No row keeps its value, and values are moved, not duplicated.
+		assert.NotEqual(t, "city"+id, city)
+		cities[city] = true
+	}
+	assert.Equal(t, n, len(cities))
+	assert.Equal(t, int64(n), conv.Stats.GoodRows["src"])
+}
+
+func TestSetColumnMaskKeys(t *testing.T) {
+	conv := maskTestConv()
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Name:        "users",
+		Id:          "t1",
+		ColIds:      []string{"c1", "c2"},
+		ColDefs:     map[string]ddl.ColumnDef{"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.String, Len: 36}, NotNull: true}, "c2": {Name: "email", Id: "c2", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}}},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+		Indexes:     []ddl.CreateIndex{{Name: "users_email", Unique: true, Keys: []ddl.IndexKey{{ColId: "c2"}}}},
+	}
+	conv.SpSchema["t2"] = ddl.CreateTable{
+		Name:        "orders",
+		Id:          "t2",
+		ColIds:      []string{"c1", "c2", "c3"},
+		ColDefs:     map[string]ddl.ColumnDef{"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}, NotNull: true}, "c2": {Name: "user_id", Id: "c2", T: ddl.Type{Name: ddl.String, Len: 36}}, "c3": {Name: "note", Id: "c3", T: ddl.Type{Name: ddl.String, Len: 36}}},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+		ForeignKeys: []ddl.Foreignkey{{Name: "fk_user", ColIds: []string{"c2"}, ReferTableId: "t1", ReferColumnIds: []string{"c1"}}},
+	}
+	conv.SpSchema["t3"] = ddl.CreateTable{
+		Name:        "order_items",
+		Id:          "t3",
+		ColIds:      []string{"c1", "c2"},
+		ColDefs:     map[string]ddl.ColumnDef{"c1": {Name: "order_id", Id: "c1", T: ddl.Type{Name: ddl.Int64}, NotNull: true}, "c2": {Name: "line", Id: "c2", T: ddl.Type{Name: ddl.Int64}, NotNull: true}},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}, {ColId: "c2", Order: 2}},
+		ParentTable: ddl.InterleavedParent{Id: "t2", OnDelete: "CASCADE"},
+	}
+	conv.SpSchema["t4"] = ddl.CreateTable{
+		Name:        "invoices",
+		Id:          "t4",
+		ColIds:      []string{"c1", "c2"},
+		ColDefs:     map[string]ddl.ColumnDef{"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}, NotNull: true}, "c2": {Name: "user_id", Id: "c2", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}}},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+	}
+
+	// Shuffled unique values would find rows by the key of another row.
+	assert.NotNil(t, conv.SetColumnMask("t1", "c2", ColumnMask{Strategy: MaskShuffle}))
+	// Foreign keys can only be hashed.
+	assert.NotNil(t, conv.SetColumnMask("t2", "c2", ColumnMask{Strategy: MaskSynthetic}))
+
+	// Hashing a primary key hashes the foreign keys referencing it.
+	assert.Nil(t, conv.SetColumnMask("t1", "c1", ColumnMask{Strategy: MaskHash}))
+	assert.Equal(t, map[string]map[string]ColumnMask{"t1": {"c1": {Strategy: MaskHash}}, "t2": {"c2": {Strategy: MaskHash}}}, conv.ColumnMasks)
+	assert.Nil(t, conv.SetColumnMask("t2", "c2", ColumnMask{}))
+	assert.Equal(t, map[string]map[string]ColumnMask{"t1": {}, "t2": {}}, conv.ColumnMasks)
+
+	// And the primary keys of the interleaved tables.
+	assert.Nil(t, conv.SetColumnMask("t2", "c1", ColumnMask{Strategy: MaskHash}))
+	assert.Equal(t, ColumnMask{Strategy: MaskHash}, conv.ColumnMasks["t3"]["c1"])
+	assert.NotContains(t, conv.ColumnMasks["t3"], "c2")
+
+	// Keys are hashed alike only if they have the same type.
+	invoices := conv.SpSchema["t4"]
+	invoices.ForeignKeys = []ddl.Foreignkey{{Name: "fk_invoice_user", ColIds: []string{"c2"}, ReferTableId: "t1", ReferColumnIds: []string{"c1"}}}
+	conv.SpSchema["t4"] = invoices
+	assert.NotNil(t, conv.SetColumnMask("t1", "c1", ColumnMask{Strategy: MaskHash}))
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kinds of the Spanner schema objects whose names must be unique across the
+// database.
+const (
+	TableObject           = "table"
+	ViewObject            = "view"
+	SequenceObject        = "sequence"
+	IndexObject           = "index"
+	ForeignKeyObject      = "foreign key"
+	CheckConstraintObject = "check constraint"
+)
+
+// NamedObject is a Spanner schema object sharing the database namespace.
+type NamedObject struct {
+	Kind    string
+	TableId string // Table of indexes, foreign keys and check constraints.
+	Id      string
+	Name    string
+	// Proposed is the name proposed to resolve the conflict the object is
+	// part of, empty for the object keeping the name.
+	Proposed string
+}
+
+// NameConflict is a set of schema objects with the same name, ignoring case.
+// Spanner rejects the DDL of all but the first of them.
+type NameConflict struct {
+	Name    string
+	Objects []NamedObject
+}
+
+// namedObjects returns the objects of the Spanner schema sharing the
+// database namespace. Objects which are the hardest to rename come first:
+// tables, views and sequences, whose names are used by queries, before
+// indexes and constraints.
+func (conv *Conv) namedObjects() []NamedObject {
+	tableIds := make([]string, 0, len(conv.SpSchema))
+	for id := range conv.SpSchema {
+		tableIds = append(tableIds, id)
+	}
+	sort.Slice(tableIds, func(i, j int) bool {
+		a, b := conv.SpSchema[tableIds[i]], conv.SpSchema[tableIds[j]]
+		if !strings.EqualFold(a.Name, b.Name) {
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		}
+		return a.Id < b.Id
+	})
+	var objects, constraints []NamedObject
+	for _, id := range tableIds {
+		ct := conv.SpSchema[id]
+		objects = append(objects, NamedObject{Kind: TableObject, Id: id, Name: ct.Name})
+		for _, idx := range ct.Indexes {
+			constraints = append(constraints, NamedObject{Kind: IndexObject, TableId: id, Id: idx.Id, Name: idx.Name})
+		}
+		for _, fk := range ct.ForeignKeys {
+			if fk.Name != "" {
+				constraints = append(constraints, NamedObject{Kind: ForeignKeyObject, TableId: id, Id: fk.Id, Name: fk.Name})
+			}
+		}
+		for _, cc := range ct.CheckConstraints {
+			if cc.Name != "" {
+				constraints = append(constraints, NamedObject{Kind: CheckConstraintObject, TableId: id, Id: cc.Id, Name: cc.Name})
+			}
+		}
+	}
+	var others []NamedObject
+	for id, v := range conv.SpViews {
+		others = append(others, NamedObject{Kind: ViewObject, Id: id, Name: v.Name})
+	}
+	for id, s := range conv.SpSequences {
+		others = append(others, NamedObject{Kind: SequenceObject, Id: id, Name: s.Name})
+	}
+	sort.Slice(others, func(i, j int) bool {
+		if others[i].Kind != others[j].Kind {
+			return others[i].Kind == ViewObject
+		}
+		return others[i].Id < others[j].Id
+	})
+	objects = append(objects, others...)
+	return append(objects, constraints...)
+}
+
+// NameConflicts returns the sets of Spanner schema objects with the same
+// name, ordered by name, with a unique name proposed for all but the first
+// object of each set.
+func (conv *Conv) NameConflicts() []NameConflict {
+	objects := conv.namedObjects()
+	byName := make(map[string][]NamedObject)
+	taken := make(map[string]bool)
+	for name := range conv.UsedNames {
+		taken[strings.ToLower(name)] = true
+	}
+	for _, o := range objects {
+		name := strings.ToLower(o.Name)
+		byName[name] = append(byName[name], o)
+		taken[name] = true
+	}
+	var conflicts []NameConflict
+	for name, l := range byName {
+		if len(l) > 1 {
+			conflicts = append(conflicts, NameConflict{Name: name, Objects: l})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Name < conflicts[j].Name })
+	for _, c := range conflicts {
+		for i := 1; i < len(c.Objects); i++ {
+			for n := 1; ; n++ {
+				proposed := AppendNameSuffix(c.Objects[i].Name, "_"+strconv.Itoa(n))
+				if !taken[strings.ToLower(proposed)] {
+					taken[strings.ToLower(proposed)] = true
+					c.Objects[i].Proposed = proposed
+					break
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// ValidateNames returns an error listing the Spanner schema objects with the
+// same name, if any, since the DDL of the schema can't be applied until they
+// are renamed.
+func (conv *Conv) ValidateNames() error {
+	conflicts := conv.NameConflicts()
+	if len(conflicts) == 0 {
+		return nil
+	}
+	var l []string
+	for _, c := range conflicts {
+		var objects []string
+		for _, o := range c.Objects {
+			objects = append(objects, fmt.Sprintf("%s %s", o.Kind, o.Name))
+		}
+		l = append(l, strings.Join(objects, ", "))
+	}
+	return fmt.Errorf("schema objects must have unique names, rename them before applying the schema: %s", strings.Join(l, "; "))
+}
+
+// ResolveNameConflicts renames the Spanner schema objects with the same name
+// as another object to the names proposed by NameConflicts, and returns the
+// number of objects renamed.
+func (conv *Conv) ResolveNameConflicts() (int, error) {
+	renamed := 0
+	for _, c := range conv.NameConflicts() {
+		for _, o := range c.Objects {
+			if o.Proposed == "" {
+				continue
+			}
+			if err := conv.renameObject(o); err != nil {
+				return renamed, err
+			}
+			renamed++
+		}
+		// The first object still uses the name.
+		conv.UsedNames[c.Name] = true
+	}
+	return renamed, nil
+}
+
+// renameObject renames object o to o.Proposed.
+func (conv *Conv) renameObject(o NamedObject) error {
+	switch o.Kind {
+	case TableObject:
+		return conv.RenameTable(o.Id, o.Proposed)
+	case ViewObject:
+		v := conv.SpViews[o.Id]
+		v.Name = o.Proposed
+		conv.SpViews[o.Id] = v
+	case SequenceObject:
+		s := conv.SpSequences[o.Id]
+		s.Name = o.Proposed
+		conv.SpSequences[o.Id] = s
+	case IndexObject, ForeignKeyObject, CheckConstraintObject:
+		ct := conv.SpSchema[o.TableId]
+		for i := range ct.Indexes {
+			if o.Kind == IndexObject && ct.Indexes[i].Id == o.Id {
+				ct.Indexes[i].Name = o.Proposed
+			}
+		}
+		for i := range ct.ForeignKeys {
+			if o.Kind == ForeignKeyObject && ct.ForeignKeys[i].Id == o.Id {
+				ct.ForeignKeys[i].Name = o.Proposed
+			}
+		}
+		for i := range ct.CheckConstraints {
+			if o.Kind == CheckConstraintObject && ct.CheckConstraints[i].Id == o.Id {
+				ct.CheckConstraints[i].Name = o.Proposed
+			}
+		}
+		conv.SpSchema[o.TableId] = ct
+	default:
+		return fmt.Errorf("can't rename %s %s", o.Kind, o.Name)
+	}
+	conv.UsedNames[strings.ToLower(o.Proposed)] = true
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func nameConflictsTestConv() *Conv {
+	conv := MakeConv()
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			Name:             "orders",
+			Id:               "t1",
+			Indexes:          []ddl.CreateIndex{{Name: "Customers", Id: "i1"}, {Name: "orders_by_date", Id: "i2"}},
+			CheckConstraints: []ddl.CheckConstraint{{Name: "orders_by_date", Id: "ck1"}},
+		},
+		"t2": {
+			Name:        "customers",
+			Id:          "t2",
+			ForeignKeys: []ddl.Foreignkey{{Name: "customers_1", Id: "f1"}},
+		},
+	}
+	conv.SpViews = map[string]ddl.CreateView{"v1": {Id: "v1", Name: "ORDERS"}}
+	conv.UsedNames = map[string]bool{"orders": true, "customers": true, "orders_by_date": true, "customers_1": true}
+	return conv
+}
+
+func TestNameConflicts(t *testing.T) {
+	conv := nameConflictsTestConv()
+	assert.Equal(t, []NameConflict{
+		{Name: "customers", Objects: []NamedObject{
+			{Kind: TableObject, Id: "t2", Name: "customers"},
+			{Kind: IndexObject, TableId: "t1", Id: "i1", Name: "Customers", Proposed: "Customers_2"},
+		}},
+		{Name: "orders", Objects: []NamedObject{
+			{Kind: TableObject, Id: "t1", Name: "orders"},
+			{Kind: ViewObject, Id: "v1", Name: "ORDERS", Proposed: "ORDERS_1"},
+		}},
+		{Name: "orders_by_date", Objects: []NamedObject{
+			{Kind: IndexObject, TableId: "t1", Id: "i2", Name: "orders_by_date"},
+			{Kind: CheckConstraintObject, TableId: "t1", Id: "ck1", Name: "orders_by_date", Proposed: "orders_by_date_1"},
+		}},
+	}, conv.NameConflicts())
+	assert.EqualError(t, conv.ValidateNames(), "schema objects must have unique names, rename them before applying the schema: "+
+		"table customers, index Customers; table orders, view ORDERS; index orders_by_date, check constraint orders_by_date")
+}
+
+func TestResolveNameConflicts(t *testing.T) {
+	conv := nameConflictsTestConv()
+	renamed, err := conv.ResolveNameConflicts()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, renamed)
+	assert.Equal(t, "Customers_2", conv.SpSchema["t1"].Indexes[0].Name)
+	assert.Equal(t, "orders_by_date_1", conv.SpSchema["t1"].CheckConstraints[0].Name)
+	assert.Equal(t, "ORDERS_1", conv.SpViews["v1"].Name)
+	assert.True(t, conv.UsedNames["orders_1"])
+	assert.True(t, conv.UsedNames["orders"])
+	assert.Empty(t, conv.NameConflicts())
+	assert.NoError(t, conv.ValidateNames())
+}
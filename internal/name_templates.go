@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Placeholders of name templates.
+const (
+	TemplateTable   = "{table}"
+	TemplateColumn  = "{col}"
+	TemplateColumns = "{cols}"
+)
+
+// Default templates of generated names.
+const (
+	DefaultIndexNameTemplate    = "Index_" + TemplateTable
+	DefaultSequenceNameTemplate = "Sequence_" + TemplateTable + "_" + TemplateColumn
+)
+
+var templatePlaceholder = regexp.MustCompile(`\{[^{}]*\}`)
+
+// NameTemplates holds the templates used to name the objects generated by
+// Spanner migration tool. An empty template means the default one is used.
+// Templates may contain placeholders, which are replaced by the names of the
+// objects the generated object belongs to.
+type NameTemplates struct {
+	SyntheticPKey string // Name of synthetic primary key columns, may use {table}.
+	ShardIdColumn string // Name of the shard id columns of sharded migrations, may use {table}.
+	Index         string // Name of generated indexes, may use {table} and {cols}.
+	Sequence      string // Name of generated sequences, may use {table} and {col}.
+}
+
+// Validate checks that the templates only use their supported placeholders
+// and that they expand to legal Spanner names.
+func (nt NameTemplates) Validate() error {
+	templates := []struct {
+		kind         string
+		template     string
+		placeholders []string
+	}{
+		{"synthetic primary key", nt.SyntheticPKey, []string{TemplateTable}},
+		{"shard id column", nt.ShardIdColumn, []string{TemplateTable}},
+		{"index", nt.Index, []string{TemplateTable, TemplateColumns}},
+		{"sequence", nt.Sequence, []string{TemplateTable, TemplateColumn}},
+	}
+	for _, t := range templates {
+		if t.template == "" {
+			continue
+		}
+		for _, p := range templatePlaceholder.FindAllString(t.template, -1) {
+			supported := false
+			for _, s := range t.placeholders {
+				supported = supported || p == s
+			}
+			if !supported {
+				return fmt.Errorf("%s name template %q uses unsupported placeholder %s, supported placeholders are %s", t.kind, t.template, p, strings.Join(t.placeholders, ", "))
+			}
+		}
+		// Expand the placeholders with legal names to check the rest of
+		// the template.
+		name := templatePlaceholder.ReplaceAllString(t.template, "x")
+		if !nameRegexp.MatchString(name) {
+			return fmt.Errorf("%s name template %q doesn't generate legal Spanner names", t.kind, t.template)
+		}
+	}
+	return nil
+}
+
+// SyntheticPKeyName returns the name of the synthetic primary key column of
+// table.
+func (nt NameTemplates) SyntheticPKeyName(table string) string {
+	return expandNameTemplate(nt.SyntheticPKey, SyntheticPrimaryKey, map[string]string{TemplateTable: table})
+}
+
+// ShardIdColumnName returns the name of the shard id column of table.
+func (nt NameTemplates) ShardIdColumnName(table string) string {
+	return expandNameTemplate(nt.ShardIdColumn, ShardIdColumn, map[string]string{TemplateTable: table})
+}
+
+// IndexName returns the name of a generated index of table on cols.
+func (nt NameTemplates) IndexName(table string, cols []string) string {
+	return expandNameTemplate(nt.Index, DefaultIndexNameTemplate, map[string]string{TemplateTable: table, TemplateColumns: strings.Join(cols, "_")})
+}
+
+// SequenceName returns the name of a generated sequence for column col of
+// table.
+func (nt NameTemplates) SequenceName(table, col string) string {
+	return expandNameTemplate(nt.Sequence, DefaultSequenceNameTemplate, map[string]string{TemplateTable: table, TemplateColumn: col})
+}
+
+// expandNameTemplate replaces the placeholders of template, or of def if
+// template is empty, and fixes the resulting name so that it is a legal
+// Spanner name. Unknown placeholders are left as is, and then fixed.
+func expandNameTemplate(template, def string, values map[string]string) string {
+	if template == "" {
+		template = def
+	}
+	name := templatePlaceholder.ReplaceAllStringFunc(template, func(p string) string {
+		if v, ok := values[p]; ok {
+			return v
+		}
+		return p
+	})
+	name, _ = FixName(name)
+	return name
+}
+
+// SetNameTemplates changes the templates of generated names. Synthetic primary
+// key and shard id columns which were already added, and still have their
+// generated name, are renamed according to the new templates. Other generated
+// objects keep their names.
+func (conv *Conv) SetNameTemplates(nt NameTemplates) error {
+	if err := nt.Validate(); err != nil {
+		return err
+	}
+	old := conv.NameTemplates
+	conv.NameTemplates = nt
+	for tableId, synthPk := range conv.SyntheticPKeys {
+		conv.renameGeneratedColumn(tableId, synthPk.ColId, old.SyntheticPKeyName, nt.SyntheticPKeyName)
+	}
+	for tableId, ct := range conv.SpSchema {
+		if ct.ShardIdColumn != "" {
+			conv.renameGeneratedColumn(tableId, ct.ShardIdColumn, old.ShardIdColumnName, nt.ShardIdColumnName)
+		}
+	}
+	return nil
+}
+
+// renameGeneratedColumn renames column colId of table tableId to the name
+// generated by newName, if the column has the name generated by oldName.
+func (conv *Conv) renameGeneratedColumn(tableId, colId string, oldName, newName func(table string) string) {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return
+	}
+	col, ok := ct.ColDefs[colId]
+	if !ok || !strings.HasPrefix(col.Name, oldName(ct.Name)) {
+		return
+	}
+	// Generated names may have a numeric suffix when they collided with a
+	// source column, see buildColumnNameWithBase.
+	if strings.TrimLeft(strings.TrimPrefix(col.Name, oldName(ct.Name)), "0123456789") != "" {
+		return
+	}
+	name := newName(ct.Name)
+	if name == col.Name {
+		return
+	}
+	delete(ct.ColDefs, colId)
+	col.Name = conv.buildColumnNameWithBase(tableId, name)
+	ct.ColDefs[colId] = col
+	conv.SpSchema[tableId] = ct
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameTemplatesValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates NameTemplates
+		wantErr   bool
+	}{
+		{"defaults", NameTemplates{}, false},
+		{"all set", NameTemplates{SyntheticPKey: "{table}_id", ShardIdColumn: "shard", Index: "idx_{table}_{cols}", Sequence: "seq_{table}_{col}"}, false},
+		{"unsupported placeholder", NameTemplates{Index: "idx_{table}_{col}"}, true},
+		{"illegal character", NameTemplates{SyntheticPKey: "synth-id"}, true},
+		{"illegal first character", NameTemplates{Sequence: "_{table}"}, true},
+	}
+	for _, tc := range tests {
+		err := tc.templates.Validate()
+		assert.Equal(t, tc.wantErr, err != nil, tc.name)
+	}
+}
+
+func TestNameTemplatesExpand(t *testing.T) {
+	defaults := NameTemplates{}
+	assert.Equal(t, "synth_id", defaults.SyntheticPKeyName("orders"))
+	assert.Equal(t, "migration_shard_id", defaults.ShardIdColumnName("orders"))
+	assert.Equal(t, "Index_orders", defaults.IndexName("orders", []string{"a", "b"}))
+	assert.Equal(t, "Sequence_orders_id", defaults.SequenceName("orders", "id"))
+
+	nt := NameTemplates{SyntheticPKey: "{table}_rowid", ShardIdColumn: "shard", Index: "idx_{table}_{cols}", Sequence: "seq_{col}"}
+	assert.Equal(t, "orders_rowid", nt.SyntheticPKeyName("orders"))
+	assert.Equal(t, "shard", nt.ShardIdColumnName("orders"))
+	assert.Equal(t, "idx_orders_a_b", nt.IndexName("orders", []string{"a", "b"}))
+	assert.Equal(t, "seq_id", nt.SequenceName("orders", "id"))
+	// Names of the source objects are fixed to legal Spanner names.
+	assert.Equal(t, "idx_order_items_a_b", nt.IndexName("order items", []string{"a", "b"}))
+}
+
+func TestAddPrimaryKeysWithNameTemplate(t *testing.T) {
+	conv := MakeConv()
+	conv.NameTemplates = NameTemplates{SyntheticPKey: "{table}_rowid"}
+	conv.SpSchema = map[string]ddl.CreateTable{
+		"t1": {
+			Name:    "orders",
+			Id:      "t1",
+			ColIds:  []string{"c1"},
+			ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "orders_rowid", Id: "c1", T: ddl.Type{Name: ddl.Int64}}},
+		},
+	}
+	conv.AddPrimaryKeys()
+	synthPk := conv.SyntheticPKeys["t1"]
+	assert.Equal(t, "orders_rowid0", conv.SpSchema["t1"].ColDefs[synthPk.ColId].Name)
+}
+
+func TestSetNameTemplates(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema = map[string]ddl.CreateTable{
+		"t1": {
+			Name:    "orders",
+			Id:      "t1",
+			ColIds:  []string{"c1"},
+			ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "a", Id: "c1", T: ddl.Type{Name: ddl.Int64}}},
+		},
+		"t2": {
+			Name:    "items",
+			Id:      "t2",
+			ColIds:  []string{"c2"},
+			ColDefs: map[string]ddl.ColumnDef{"c2": {Name: "b", Id: "c2", T: ddl.Type{Name: ddl.Int64}}},
+		},
+	}
+	conv.AddPrimaryKeys()
+	// Synthetic primary keys renamed by the user keep their name.
+	renamed := conv.SpSchema["t2"]
+	renamedCol := renamed.ColDefs[conv.SyntheticPKeys["t2"].ColId]
+	renamedCol.Name = "item_id"
+	renamed.ColDefs[renamedCol.Id] = renamedCol
+
+	err := conv.SetNameTemplates(NameTemplates{SyntheticPKey: "{table}_rowid"})
+	assert.Nil(t, err)
+	assert.Equal(t, "orders_rowid", conv.SpSchema["t1"].ColDefs[conv.SyntheticPKeys["t1"].ColId].Name)
+	assert.Equal(t, "item_id", conv.SpSchema["t2"].ColDefs[conv.SyntheticPKeys["t2"].ColId].Name)
+
+	err = conv.SetNameTemplates(NameTemplates{SyntheticPKey: "row-id"})
+	assert.NotNil(t, err)
+	assert.Equal(t, "{table}_rowid", conv.NameTemplates.SyntheticPKey)
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+)
+
+// Numeric precision policies, which control how values with more digits after
+// the decimal point than a GoogleSQL NUMERIC can store (e.g. FLOAT and DOUBLE
+// values migrated to NUMERIC) are written.
+const (
+	NumericRound = constants.NUMERIC_PRECISION_ROUND // Values are rounded to 9 digits after the decimal point (default).
+	NumericFail  = constants.NUMERIC_PRECISION_FAIL  // Rows with such values are bad rows.
+)
+
+// numericScaleFactor is 10^numericScale: NUMERIC values are exact when their
+// product by it is an integer.
+var numericScaleFactor = new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(numericScale), nil))
+
+// CheckNumericPrecision checks whether r, a value of column srcCol of source
+// table srcTable, is stored exactly by a Spanner NUMERIC. Values which are
+// rounded are counted in the stats of the column, or rejected with an error
+// under the fail policy. PostgreSQL NUMERIC values are never rounded.
+func (conv *Conv) CheckNumericPrecision(srcTable, srcCol string, r *big.Rat) error {
+	if conv.SpDialect == constants.DIALECT_POSTGRESQL || new(big.Rat).Mul(r, numericScaleFactor).IsInt() {
+		return nil
+	}
+	if conv.NumericPrecisionPolicy == NumericFail {
+		return fmt.Errorf("value %s of column %s has more than %d digits after the decimal point and would be rounded", r.FloatString(2*numericScale), srcCol, numericScale)
+	}
+	if conv.DataMode() {
+		if conv.Stats.RoundedNumerics == nil {
+			conv.Stats.RoundedNumerics = make(map[string]map[string]int64)
+		}
+		if conv.Stats.RoundedNumerics[srcTable] == nil {
+			conv.Stats.RoundedNumerics[srcTable] = make(map[string]int64)
+		}
+		conv.Stats.RoundedNumerics[srcTable][srcCol]++
+	}
+	return nil
+}
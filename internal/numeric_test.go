@@ -0,0 +1,45 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckNumericPrecision(t *testing.T) {
+	rat := func(s string) *big.Rat {
+		r, _ := new(big.Rat).SetString(s)
+		return r
+	}
+	conv := MakeConv()
+	conv.SetDataMode()
+	assert.Nil(t, conv.CheckNumericPrecision("t", "price", rat("12.123456789")))
+	assert.Nil(t, conv.CheckNumericPrecision("t", "price", rat("1e-9")))
+	assert.Nil(t, conv.CheckNumericPrecision("t", "price", rat("3.141592653589793")))
+	assert.Nil(t, conv.CheckNumericPrecision("t", "ratio", rat("1.5e-12")))
+	assert.Nil(t, conv.CheckNumericPrecision("t", "ratio", rat("0.1234567891")))
+	assert.Equal(t, map[string]map[string]int64{"t": {"price": 1, "ratio": 2}}, conv.Stats.RoundedNumerics)
+
+	conv.NumericPrecisionPolicy = NumericFail
+	assert.Nil(t, conv.CheckNumericPrecision("t", "price", rat("-0.5")))
+	assert.NotNil(t, conv.CheckNumericPrecision("t", "price", rat("0.1234567891")))
+
+	conv.SpDialect = constants.DIALECT_POSTGRESQL
+	assert.Nil(t, conv.CheckNumericPrecision("t", "price", rat("0.1234567891")))
+}
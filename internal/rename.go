@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RenameTable renames the Spanner table tableId to newName, and updates the
+// references to the table by name: the source to Spanner mappings, the names
+// in use and the queries of views. Foreign keys, interleaving, sequences and
+// schema issues refer to tables by id and need no update. The conv is left
+// unchanged if the rename is not possible.
+func (conv *Conv) RenameTable(tableId, newName string) error {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return fmt.Errorf("table doesn't exist for tableId %s", tableId)
+	}
+	oldName := ct.Name
+	if newName == oldName {
+		return nil
+	}
+	if _, changed := FixName(newName); changed {
+		return fmt.Errorf("%s is not a valid Spanner identifier", newName)
+	}
+	// Names differing only in case are the same name for Spanner.
+	if !strings.EqualFold(newName, oldName) && conv.UsedNames[strings.ToLower(newName)] {
+		return fmt.Errorf("name %s is already used by a table, index or foreign key", newName)
+	}
+	for _, v := range conv.SpViews {
+		if strings.EqualFold(v.Name, newName) {
+			return fmt.Errorf("name %s is already used by a view", newName)
+		}
+	}
+
+	ct.Name = newName
+	conv.SpSchema[tableId] = ct
+	delete(conv.UsedNames, strings.ToLower(oldName))
+	conv.UsedNames[strings.ToLower(newName)] = true
+	if srcTable, ok := conv.SrcSchema[tableId]; ok {
+		if m, ok := conv.ToSpanner[srcTable.Name]; ok {
+			m.Name = newName
+			conv.ToSpanner[srcTable.Name] = m
+		}
+	}
+	if m, ok := conv.ToSource[oldName]; ok {
+		delete(conv.ToSource, oldName)
+		conv.ToSource[newName] = m
+	}
+	re := identifierRegexp(oldName)
+	for id, v := range conv.SpViews {
+		v.Query = re.ReplaceAllLiteralString(v.Query, newName)
+		conv.SpViews[id] = v
+	}
+	return nil
+}
+
+// RenameColumn renames the column colId of the Spanner table tableId to
+// newName, and updates the references to the column by name: the source to
+// Spanner mappings, the check constraints, default values and generated
+// columns of the table, and the references qualified by the table name in
+// the queries of views. The conv is left unchanged if the rename is not
+// possible.
+func (conv *Conv) RenameColumn(tableId, colId, newName string) error {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return fmt.Errorf("table doesn't exist for tableId %s", tableId)
+	}
+	col, ok := ct.ColDefs[colId]
+	if !ok {
+		return fmt.Errorf("column doesn't exist for colId %s of table %s", colId, ct.Name)
+	}
+	oldName := col.Name
+	if newName == oldName {
+		return nil
+	}
+	if _, changed := FixName(newName); changed {
+		return fmt.Errorf("%s is not a valid Spanner identifier", newName)
+	}
+	for id, cd := range ct.ColDefs {
+		if id != colId && strings.EqualFold(cd.Name, newName) {
+			return fmt.Errorf("column %s already exists in table %s", newName, ct.Name)
+		}
+	}
+
+	col.Name = newName
+	ct.ColDefs[colId] = col
+	for id, cd := range ct.ColDefs {
+		if cd.DefaultValue.IsPresent {
+			cd.DefaultValue.Value.Statement = RenameExprColumn(cd.DefaultValue.Value.Statement, conv.SpDialect, oldName, newName)
+		}
+		if cd.GeneratedColumn.IsPresent {
+			cd.GeneratedColumn.Value.Statement = RenameExprColumn(cd.GeneratedColumn.Value.Statement, conv.SpDialect, oldName, newName)
+		}
+		ct.ColDefs[id] = cd
+	}
+	for i := range ct.CheckConstraints {
+		ct.CheckConstraints[i].Expr = RenameExprColumn(ct.CheckConstraints[i].Expr, conv.SpDialect, oldName, newName)
+	}
+	conv.SpSchema[tableId] = ct
+
+	if srcTable, ok := conv.SrcSchema[tableId]; ok {
+		if srcCol, ok := srcTable.ColDefs[colId]; ok {
+			if m, ok := conv.ToSpanner[srcTable.Name]; ok && m.Cols != nil {
+				m.Cols[srcCol.Name] = newName
+			}
+		}
+	}
+	if m, ok := conv.ToSource[ct.Name]; ok && m.Cols != nil {
+		if srcColName, ok := m.Cols[oldName]; ok {
+			delete(m.Cols, oldName)
+			m.Cols[newName] = srcColName
+		}
+	}
+	// Unqualified column names of view queries can't be attributed to a
+	// table, only table.column references are updated.
+	qualified := regexp.MustCompile(`(?i)\b(` + regexp.QuoteMeta(ct.Name) + `\s*\.\s*)` + regexp.QuoteMeta(oldName) + `\b`)
+	for id, v := range conv.SpViews {
+		v.Query = qualified.ReplaceAllString(v.Query, "${1}"+newName)
+		conv.SpViews[id] = v
+	}
+	return nil
+}
+
+// identifierRegexp matches name as a whole word, ignoring case like Spanner does
+// for identifiers.
+func identifierRegexp(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(name) + `\b`)
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func renameTestConv() *Conv {
+	conv := MakeConv()
+	conv.SrcSchema = map[string]schema.Table{
+		"t1": {Name: "Orders", Id: "t1", ColDefs: map[string]schema.Column{"c1": {Name: "Id", Id: "c1"}, "c2": {Name: "Total", Id: "c2"}}},
+	}
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			Name:   "orders",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Name: "total", Id: "c2", T: ddl.Type{Name: ddl.Numeric}, DefaultValue: ddl.DefaultValue{IsPresent: true, Value: ddl.Expression{Statement: "0"}}},
+				"c3": {Name: "total_tax", Id: "c3", T: ddl.Type{Name: ddl.Numeric}, GeneratedColumn: ddl.GeneratedColumn{IsPresent: true, Type: ddl.GeneratedColStored, Value: ddl.Expression{Statement: "total * 0.2"}}},
+			},
+			CheckConstraints: []ddl.CheckConstraint{{Name: "positive_total", Expr: "(total > 0)"}},
+		},
+		"t2": {Name: "items", Id: "t2"},
+	}
+	conv.ToSpanner = map[string]NameAndCols{"Orders": {Name: "orders", Cols: map[string]string{"Id": "id", "Total": "total"}}}
+	conv.ToSource = map[string]NameAndCols{"orders": {Name: "Orders", Cols: map[string]string{"id": "Id", "total": "Total"}}}
+	conv.UsedNames = map[string]bool{"orders": true, "items": true, "positive_total": true}
+	conv.SpViews = map[string]ddl.CreateView{"v1": {Id: "v1", Name: "big_orders", Query: "SELECT orders.total FROM orders WHERE total > 100"}}
+	return conv
+}
+
+func TestRenameTable(t *testing.T) {
+	conv := renameTestConv()
+	assert.Error(t, conv.RenameTable("t9", "purchases"))
+	assert.Error(t, conv.RenameTable("t1", "Items"))
+	assert.Error(t, conv.RenameTable("t1", "big_orders"))
+	assert.Error(t, conv.RenameTable("t1", "bad name"))
+	assert.Equal(t, "orders", conv.SpSchema["t1"].Name)
+
+	assert.NoError(t, conv.RenameTable("t1", "purchases"))
+	assert.Equal(t, "purchases", conv.SpSchema["t1"].Name)
+	assert.Equal(t, "purchases", conv.ToSpanner["Orders"].Name)
+	assert.Equal(t, "Orders", conv.ToSource["purchases"].Name)
+	assert.NotContains(t, conv.ToSource, "orders")
+	assert.Equal(t, map[string]bool{"purchases": true, "items": true, "positive_total": true}, conv.UsedNames)
+	assert.Equal(t, "SELECT purchases.total FROM purchases WHERE total > 100", conv.SpViews["v1"].Query)
+
+	// A table can be renamed to a name differing only in case.
+	assert.NoError(t, conv.RenameTable("t1", "Purchases"))
+	assert.Equal(t, "Purchases", conv.SpSchema["t1"].Name)
+}
+
+func TestRenameColumn(t *testing.T) {
+	conv := renameTestConv()
+	assert.Error(t, conv.RenameColumn("t1", "c9", "amount"))
+	assert.Error(t, conv.RenameColumn("t1", "c2", "ID"))
+	assert.Error(t, conv.RenameColumn("t1", "c2", "1amount"))
+
+	assert.NoError(t, conv.RenameColumn("t1", "c2", "amount"))
+	ct := conv.SpSchema["t1"]
+	assert.Equal(t, "amount", ct.ColDefs["c2"].Name)
+	assert.Equal(t, "0", ct.ColDefs["c2"].DefaultValue.Value.Statement)
+	// total_tax isn't a reference to total.
+	assert.Equal(t, "total_tax", ct.ColDefs["c3"].Name)
+	assert.Equal(t, "amount * 0.2", ct.ColDefs["c3"].GeneratedColumn.Value.Statement)
+	assert.Equal(t, "(amount > 0)", ct.CheckConstraints[0].Expr)
+	assert.Equal(t, "amount", conv.ToSpanner["Orders"].Cols["Total"])
+	assert.Equal(t, map[string]string{"id": "Id", "amount": "Total"}, conv.ToSource["orders"].Cols)
+	// Only references qualified by the table name are updated in views.
+	assert.Equal(t, "SELECT orders.amount FROM orders WHERE total > 100", conv.SpViews["v1"].Query)
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reports
+
+import (
+	"bufio"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/jsonreport"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+)
+
+// Weights of the issues of each severity in the complexity score.
+var complexityWeights = map[string]int64{
+	jsonreport.SeverityError:      10,
+	jsonreport.SeverityWarning:    3,
+	jsonreport.SeveritySuggestion: 1,
+}
+
+// ObjectCounts is the number of objects of each kind of a schema.
+type ObjectCounts struct {
+	Tables           int `json:"tables"`
+	Columns          int `json:"columns"`
+	Indexes          int `json:"indexes"`
+	ForeignKeys      int `json:"foreignKeys"`
+	CheckConstraints int `json:"checkConstraints"`
+	Sequences        int `json:"sequences"`
+	Views            int `json:"views"`
+}
+
+// ObjectSummary counts the objects of the source and Spanner schemas, and
+// rates the complexity of the migration from the issues found. The
+// complexity score is the sum of the issues weighted by severity, and the
+// complexity is Low, Medium or High according to the average score per table.
+type ObjectSummary struct {
+	Source          ObjectCounts `json:"source"`
+	Spanner         ObjectCounts `json:"spanner"`
+	Errors          int64        `json:"errors"`
+	Warnings        int64        `json:"warnings"`
+	Suggestions     int64        `json:"suggestions"`
+	ComplexityScore int64        `json:"complexityScore"`
+	Complexity      string       `json:"complexity"`
+}
+
+// GenerateObjectSummary returns the object summary of conv.
+func GenerateObjectSummary(conv *internal.Conv) ObjectSummary {
+	return buildObjectSummary(conv, AnalyzeTables(conv, nil))
+}
+
+func buildObjectSummary(conv *internal.Conv, tableReports []tableReport) ObjectSummary {
+	var s ObjectSummary
+	for _, t := range conv.SrcSchema {
+		s.Source.Tables++
+		s.Source.Columns += len(t.ColIds)
+		s.Source.Indexes += len(t.Indexes)
+		s.Source.ForeignKeys += len(t.ForeignKeys)
+		s.Source.CheckConstraints += len(t.CheckConstraints)
+	}
+	s.Source.Sequences = len(conv.SrcSequences)
+	// Source views aren't part of the source schema, but dumps count their
+	// statements.
+	for _, stmt := range []string{"CreateViewStmt", "ViewStmt"} {
+		if stat, ok := conv.Stats.Statement[stmt]; ok {
+			s.Source.Views += int(stat.Schema + stat.Skip + stat.Error)
+		}
+	}
+	for tableId, t := range conv.SpSchema {
+		if internal.IsAddedTable(conv, tableId) {
+			continue
+		}
+		s.Spanner.Tables++
+		s.Spanner.Columns += len(t.ColIds)
+		s.Spanner.Indexes += len(t.Indexes)
+		s.Spanner.ForeignKeys += len(t.ForeignKeys)
+		s.Spanner.CheckConstraints += len(t.CheckConstraints)
+	}
+	s.Spanner.Sequences = len(conv.SpSequences)
+	s.Spanner.Views = len(conv.SpViews)
+	for _, t := range tableReports {
+		for _, body := range t.Body {
+			severity := toJSONSeverity(body.Heading)
+			n := int64(len(body.IssueBody))
+			switch severity {
+			case jsonreport.SeverityError:
+				s.Errors += n
+			case jsonreport.SeverityWarning:
+				s.Warnings += n
+			case jsonreport.SeveritySuggestion:
+				s.Suggestions += n
+			}
+			s.ComplexityScore += n * complexityWeights[severity]
+		}
+	}
+	s.Complexity = rateComplexity(s.ComplexityScore, s.Source.Tables)
+	return s
+}
+
+// rateComplexity rates the complexity of a migration from its complexity
+// score and number of tables.
+func rateComplexity(score int64, tables int) string {
+	if tables == 0 {
+		tables = 1
+	}
+	switch perTable := float64(score) / float64(tables); {
+	case perTable < 1:
+		return "Low"
+	case perTable < 5:
+		return "Medium"
+	default:
+		return "High"
+	}
+}
+
+// String returns a one-line summary of s, e.g. for the console.
+func (s ObjectSummary) String() string {
+	c := s.Spanner
+	return fmt.Sprintf("Spanner schema: %d tables, %d columns, %d indexes, %d foreign keys, %d check constraints, %d sequences, %d views (complexity %s, score %d)",
+		c.Tables, c.Columns, c.Indexes, c.ForeignKeys, c.CheckConstraints, c.Sequences, c.Views, s.Complexity, s.ComplexityScore)
+}
+
+func writeObjectSummary(structuredReport StructuredReport, w *bufio.Writer) {
+	s := structuredReport.ObjectSummary
+	writeHeading(w, "Schema Object Summary")
+	fmt.Fprintf(w, "  %-18s %8s %8s\n", "object", "source", "spanner")
+	for _, row := range []struct {
+		name        string
+		src, target int
+	}{
+		{"tables", s.Source.Tables, s.Spanner.Tables},
+		{"columns", s.Source.Columns, s.Spanner.Columns},
+		{"indexes", s.Source.Indexes, s.Spanner.Indexes},
+		{"foreign keys", s.Source.ForeignKeys, s.Spanner.ForeignKeys},
+		{"check constraints", s.Source.CheckConstraints, s.Spanner.CheckConstraints},
+		{"sequences", s.Source.Sequences, s.Spanner.Sequences},
+		{"views", s.Source.Views, s.Spanner.Views},
+	} {
+		fmt.Fprintf(w, "  %-18s %8d %8d\n", row.name, row.src, row.target)
+	}
+	w.WriteString("\n")
+	justifyLines(w, fmt.Sprintf("Complexity: %s (score %d, from %d errors, %d warnings "+
+		"and %d suggestions weighted %d, %d and %d).", s.Complexity, s.ComplexityScore,
+		s.Errors, s.Warnings, s.Suggestions, complexityWeights[jsonreport.SeverityError],
+		complexityWeights[jsonreport.SeverityWarning], complexityWeights[jsonreport.SeveritySuggestion]), 80, 0)
+	w.WriteString("\n\n")
+}
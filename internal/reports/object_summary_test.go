@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reports
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/proto/migration"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateObjectSummary(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SpDialect = constants.DIALECT_GOOGLESQL
+	conv.Audit.MigrationType = migration.MigrationData_SCHEMA_ONLY.Enum()
+	conv.SchemaStatement("CreateViewStmt")
+	conv.SrcSchema = map[string]schema.Table{
+		"t1": {
+			Name:   "orders",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3"},
+			ColDefs: map[string]schema.Column{
+				"c1": {Name: "id", Id: "c1", Type: schema.Type{Name: "bigint"}},
+				"c2": {Name: "amount", Id: "c2", Type: schema.Type{Name: "float"}},
+				"c3": {Name: "customer", Id: "c3", Type: schema.Type{Name: "bigint"}},
+			},
+			PrimaryKeys:      []schema.Key{{ColId: "c1"}},
+			Indexes:          []schema.Index{{Name: "idx_amount", Id: "i1", Keys: []schema.Key{{ColId: "c2"}}}},
+			ForeignKeys:      []schema.ForeignKey{{Name: "fk_customer", Id: "f1", ColIds: []string{"c3"}, ReferTableId: "t2", ReferColumnIds: []string{"c4"}}},
+			CheckConstraints: []schema.CheckConstraint{{Name: "ck_amount", Id: "k1", Expr: "amount > 0"}},
+		},
+		"t2": {
+			Name:        "customers",
+			Id:          "t2",
+			ColIds:      []string{"c4"},
+			ColDefs:     map[string]schema.Column{"c4": {Name: "id", Id: "c4", Type: schema.Type{Name: "bigint"}}},
+			PrimaryKeys: []schema.Key{{ColId: "c4"}},
+		},
+	}
+	conv.SrcSequences = map[string]ddl.Sequence{"s1": {Name: "orders_seq", Id: "s1"}}
+	conv.SpSchema = map[string]ddl.CreateTable{
+		"t1": {
+			Name:   "orders",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Name: "amount", Id: "c2", T: ddl.Type{Name: ddl.Float64}},
+				"c3": {Name: "customer", Id: "c3", T: ddl.Type{Name: ddl.Int64}},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1"}},
+			Indexes:     []ddl.CreateIndex{{Name: "idx_amount", Id: "i1", TableId: "t1", Keys: []ddl.IndexKey{{ColId: "c2"}}}},
+			ForeignKeys: []ddl.Foreignkey{{Name: "fk_customer", Id: "f1", ColIds: []string{"c3"}, ReferTableId: "t2", ReferColumnIds: []string{"c4"}}},
+		},
+		"t2": {
+			Name:        "customers",
+			Id:          "t2",
+			ColIds:      []string{"c4"},
+			ColDefs:     map[string]ddl.ColumnDef{"c4": {Name: "id", Id: "c4", T: ddl.Type{Name: ddl.Int64}}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c4"}},
+		},
+		// Tables added in Spanner aren't counted.
+		"t3": {
+			Name:        "added",
+			Id:          "t3",
+			ColIds:      []string{"c5"},
+			ColDefs:     map[string]ddl.ColumnDef{"c5": {Name: "id", Id: "c5", T: ddl.Type{Name: ddl.Int64}}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c5"}},
+		},
+	}
+	conv.SpSequences = map[string]ddl.Sequence{"s1": {Name: "orders_seq", Id: "s1"}}
+	conv.SchemaIssues = map[string]internal.TableIssues{
+		"t1": {ColumnLevelIssues: map[string][]internal.SchemaIssue{"c2": {internal.Widened}}},
+	}
+
+	s := GenerateObjectSummary(conv)
+	assert.Equal(t, ObjectCounts{Tables: 2, Columns: 4, Indexes: 1, ForeignKeys: 1, CheckConstraints: 1, Sequences: 1, Views: 1}, s.Source)
+	assert.Equal(t, ObjectCounts{Tables: 2, Columns: 4, Indexes: 1, ForeignKeys: 1, Sequences: 1}, s.Spanner)
+	assert.Equal(t, int64(0), s.Errors)
+	assert.Equal(t, int64(2), s.Warnings)
+	assert.Equal(t, int64(6), s.ComplexityScore)
+	assert.Equal(t, "Medium", s.Complexity)
+}
+
+func TestRateComplexity(t *testing.T) {
+	assert.Equal(t, "Low", rateComplexity(0, 0))
+	assert.Equal(t, "Low", rateComplexity(9, 10))
+	assert.Equal(t, "Medium", rateComplexity(10, 10))
+	assert.Equal(t, "Medium", rateComplexity(3, 1))
+	assert.Equal(t, "High", rateComplexity(50, 10))
+}
@@ -107,8 +107,67 @@ func buildTableReportBody(conv *internal.Conv, tableId string, issues map[string
 					}
 					l = append(l, toAppend)
 				}
+				if issue == internal.InterleaveCycle {
+					toAppend := Issue{
+						Category:    IssueDB[issue].Category,
+						Description: fmt.Sprintf("Table '%s': The table is part of a cycle of interleaved tables %s - %s", conv.SpSchema[tableId].Name, conv.InterleaveCycleDescription(tableId), IssueDB[issue].Brief),
+					}
+					l = append(l, toAppend)
+				}
+				if issue == internal.InterleaveTooDeep {
+					toAppend := Issue{
+						Category:    IssueDB[issue].Category,
+						Description: fmt.Sprintf("Table '%s': The table is interleaved at level %d - %s", conv.SpSchema[tableId].Name, conv.InterleaveLevel(tableId), IssueDB[issue].Brief),
+					}
+					l = append(l, toAppend)
+				}
+				if issue == internal.DdlRejected {
+					for _, r := range conv.DdlRejections[tableId] {
+						toAppend := Issue{
+							Category:    IssueDB[internal.DdlRejected].Category,
+							Description: fmt.Sprintf("Table '%s': The Spanner emulator rejected the statement `%s`: %s", conv.SpSchema[tableId].Name, r.Statement, r.Error),
+						}
+						l = append(l, toAppend)
+					}
+				}
+			}
+
+		}
+
+		if p.severity == note && internal.Contains(tableLevelIssues, internal.ObjectDropped) {
+			for _, o := range conv.DroppedObjects[tableId] {
+				toAppend := Issue{
+					Category:    IssueDB[internal.ObjectDropped].Category,
+					Description: fmt.Sprintf("Table '%s': The %s %s was dropped and can be recreated after the migration with `%s`", conv.SpSchema[tableId].Name, strings.ToLower(o.Kind), o.Name, o.Statement),
+				}
+				l = append(l, toAppend)
+			}
+		}
+
+		for _, v := range conv.TableLimitViolations(tableId) {
+			if IssueDB[v.Issue].Severity == p.severity && internal.Contains(tableLevelIssues, v.Issue) {
+				toAppend := Issue{
+					Category:    IssueDB[v.Issue].Category,
+					Description: fmt.Sprintf("Table '%s': %s%s", conv.SpSchema[tableId].Name, strings.ToUpper(v.Description[:1]), v.Description[1:]),
+				}
+				l = append(l, toAppend)
 			}
+		}
+
+		for _, issue := range []internal.SchemaIssue{internal.AppAutoIncrementOrdering, internal.AppCaseInsensitiveLookup, internal.AppSchemaChange} {
+			if IssueDB[issue].Severity == p.severity && internal.Contains(tableLevelIssues, issue) {
+				l = append(l, appFindingIssue(conv, tableId, "", issue))
+			}
+		}
 
+		if p.severity == warning && internal.Contains(tableLevelIssues, internal.InterleaveOnDeleteDiverges) {
+			if m, ok := conv.InterleaveOnDeleteMismatch(tableId); ok {
+				toAppend := Issue{
+					Category:    IssueDB[internal.InterleaveOnDeleteDiverges].Category,
+					Description: m.Description(conv.SpSchema[tableId].Name, conv.SpSchema[conv.SpSchema[tableId].ParentTable.Id].Name),
+				}
+				l = append(l, toAppend)
+			}
 		}
 
 		// added if condition to add table level warnings
@@ -152,6 +211,12 @@ func buildTableReportBody(conv *internal.Conv, tableId string, issues map[string
 						Description: fmt.Sprintf("Table '%s': An error occurred in the check constraint %s. Please verify the conditions and ensure the constraint logic is valid. As a result, the check constraint has not been applied and has been dropped", conv.SpSchema[tableId].Name, invalidExp.Expression),
 					}
 					l = append(l, toAppend)
+				case internal.CheckConstraintColumnRetyped:
+					toAppend := Issue{
+						Category:    IssueDB[invalidExp.IssueType].Category,
+						Description: fmt.Sprintf("Table '%s': The check constraint %s references a column whose type was changed. Please verify that the constraint logic still applies to the new type", conv.SpSchema[tableId].Name, invalidExp.Expression),
+					}
+					l = append(l, toAppend)
 				}
 			}
 		}
@@ -273,6 +338,14 @@ func buildTableReportBody(conv *internal.Conv, tableId string, issues map[string
 			}
 		}
 
+		if p.severity == suggestion && srcSchema.Partitioning.Method != "" {
+			toAppend := Issue{
+				Category:    IssueDB[internal.PartitionedTable].Category,
+				Description: partitioningSuggestion(conv, tableId, srcSchema),
+			}
+			l = append(l, toAppend)
+		}
+
 		issueBatcher := make(map[internal.SchemaIssue]bool)
 		for _, colName := range colNames {
 			colId, _ := internal.GetColIdFromSpName(conv.SpSchema[tableId].ColDefs, colName)
@@ -312,6 +385,39 @@ func buildTableReportBody(conv *internal.Conv, tableId string, issues map[string
 						Description: fmt.Sprintf("%s for table '%s' e.g. column '%s'", IssueDB[i].Brief, conv.SpSchema[tableId].Name, spColName),
 					}
 					l = append(l, toAppend)
+				case internal.AppAutoIncrementOrdering, internal.AppCaseInsensitiveLookup, internal.AppSchemaChange:
+					l = append(l, appFindingIssue(conv, tableId, colId, i))
+				case internal.CaseInsensitiveCollation, internal.LocaleCollation:
+					toAppend := Issue{
+						Category:    IssueDB[i].Category,
+						Description: fmt.Sprintf("Table '%s': Column '%s' uses collation %s. %s", conv.SpSchema[tableId].Name, spColName, srcSchema.ColDefs[colId].Collation.Name, IssueDB[i].Brief),
+					}
+					l = append(l, toAppend)
+				case internal.SampledStringLength:
+					stats := conv.ColumnStats[tableId][colId]
+					toAppend := Issue{
+						Category:    IssueDB[i].Category,
+						Description: fmt.Sprintf("Table '%s': Column '%s' is %s but its sampled values have at most %d characters. %s, e.g. STRING(%d)", conv.SpSchema[tableId].Name, spColName, spColType, stats.MaxLength, IssueDB[i].Brief, internal.SuggestedStringLength(stats)),
+					}
+					l = append(l, toAppend)
+				case internal.JSONPathIndexSuggestion:
+					var paths []string
+					for _, p := range conv.JSONPathSuggestions[tableId] {
+						if p.ColId == colId {
+							paths = append(paths, fmt.Sprintf("%s (%d queries)", p.Path, p.Count))
+						}
+					}
+					toAppend := Issue{
+						Category:    IssueDB[i].Category,
+						Description: fmt.Sprintf("Table '%s': Column '%s' is queried by the JSON paths %s, which %s", conv.SpSchema[tableId].Name, spColName, strings.Join(paths, ", "), IssueDB[i].Brief),
+					}
+					l = append(l, toAppend)
+				case internal.NormalizedColumn, internal.JSONPathColumn, internal.InvalidUTF8, internal.SampledNoNulls:
+					toAppend := Issue{
+						Category:    IssueDB[i].Category,
+						Description: fmt.Sprintf("Table '%s': Column '%s' %s", conv.SpSchema[tableId].Name, spColName, IssueDB[i].Brief),
+					}
+					l = append(l, toAppend)
 				case internal.ForeignKey:
 					toAppend := Issue{
 						Category:    IssueDB[i].Category,
@@ -541,6 +647,26 @@ func Contains(l []Issue, str string) bool {
 	return false
 }
 
+// partitioningSuggestion describes the partitioning scheme of a source table.
+// Range partitioning on a single date/time column is typically used to drop
+// old data, which maps to a row deletion policy (TTL) in Spanner.
+func partitioningSuggestion(conv *internal.Conv, tableId string, srcSchema schema.Table) string {
+	part := srcSchema.Partitioning
+	msg := fmt.Sprintf("Table '%s': %s, source table is partitioned by %s (%s) and the partitioning is not migrated",
+		conv.SpSchema[tableId].Name, IssueDB[internal.PartitionedTable].Brief, part.Method, part.Expression)
+	if !strings.HasPrefix(part.Method, "RANGE") || len(part.ColIds) != 1 {
+		return msg
+	}
+	spCol, ok := conv.SpSchema[tableId].ColDefs[part.ColIds[0]]
+	if !ok || (spCol.T.Name != ddl.Timestamp && spCol.T.Name != ddl.Date) {
+		return msg
+	}
+	if spCol.T.Name == ddl.Date {
+		return fmt.Sprintf("%s. If partitions are used to expire old rows, consider converting column '%s' to TIMESTAMP and adding a row deletion policy on it", msg, spCol.Name)
+	}
+	return fmt.Sprintf("%s. If partitions are used to expire old rows, consider adding a row deletion policy on column '%s'", msg, spCol.Name)
+}
+
 func getInterleaveDetail(conv *internal.Conv, tableId string, colId string, issueType internal.SchemaIssue) (parent, fkName, referColName string) {
 	table := conv.SpSchema[tableId]
 	for _, fk := range table.ForeignKeys {
@@ -593,12 +719,14 @@ func fillRowStats(conv *internal.Conv, srcTable string, badWrites map[string]int
 	goodConvRows := conv.Stats.GoodRows[srcTable]
 	badConvRows := conv.Stats.BadRows[srcTable]
 	badRowWrites := badWrites[srcTable]
+	sampledOutRows := conv.Stats.SampledOutRows[srcTable]
 	// Note on rows:
 	// rows: all rows we encountered during processing.
 	// goodConvRows: rows we successfully converted.
 	// badConvRows: rows we failed to convert.
 	// badRowWrites: rows we converted, but could not write to Spanner.
-	if rows != goodConvRows+badConvRows || badRowWrites > goodConvRows {
+	// sampledOutRows: rows left out of a sampled data migration.
+	if rows != goodConvRows+badConvRows+sampledOutRows || badRowWrites > goodConvRows {
 		conv.Unexpected(fmt.Sprintf("Inconsistent row counts for table %s: %d %d %d %d\n", srcTable, rows, goodConvRows, badConvRows, badRowWrites))
 	}
 	tr.rows = rows
@@ -660,7 +788,7 @@ var IssueDB = map[internal.SchemaIssue]struct {
 		CategoryDescription: "Some tables can be interleaved with parent table if primary key order parameter is changed to 1"},
 	internal.InterleavedAddColumn: {Brief: "Candidate for Interleaved Table", Severity: suggestion, Category: "ADD_INTERLEAVED_COLUMN",
 		CategoryDescription: "If there is some primary key added in table, it can be interleaved"},
-	internal.IllegalName: {Brief: "Names must adhere to the spanner regular expression {a-z|A-Z}[{a-z|A-Z|0-9|_}+]", Severity: warning, Category: "ILLEGAL_NAME"},
+	internal.IllegalName: {Brief: "Names must adhere to the spanner regular expression {a-z|A-Z}[{a-z|A-Z|0-9|_}+] and be at most 128 characters long", Severity: warning, Category: "ILLEGAL_NAME"},
 	internal.InterleavedRenameColumn: {Brief: "Candidate for Interleaved Table", Severity: suggestion, Category: "RENAME_INTERLEAVED_COLUMN_PRIMARY_KEY",
 		CategoryDescription: "If primary key is renamed in table to match the foreign key, the table can be interleaved"},
 	internal.InterleavedChangeColumnSize: {Brief: "Candidate for Interleaved Table", Severity: suggestion, Category: "CHANGE_INTERLEAVED_COLUMN_SIZE",
@@ -686,6 +814,42 @@ var IssueDB = map[internal.SchemaIssue]struct {
 	internal.CassandraTIMEUUID:            {Brief: "Cassandra TimeUUIDs map to Spanner's BYTES(16). This generic type doesn't validate embedded timestamps.", Severity: warning, Category: "CASSANDRA_TIMEUUID_USES"},
 	internal.CassandraMAP:                 {Brief: "Cassandra MAP type maps to Spanner's JSON. Spanner does not validate internal JSON structure or types, unlike Cassandra's MAP.", Severity: warning, Category: "CASSANDRA_MAP_USES"},
 	internal.PossibleOverflow:             {Brief: "Possible overflow in Spanner. Source type does not entirely fit inside Spanner's type. Please check if the data fits within the target type's limits.", Severity: warning, Category: "POSSIBLE_OVERFLOW"},
+	internal.PartitionedTable:             {Brief: "Spanner automatically partitions data and does not support user defined partitions", Severity: suggestion, Category: "PARTITIONED_TABLE"},
+	internal.IndexStoringSuggestion: {Brief: "Large column is a trailing index key. If it is only used to cover queries, consider moving it to the STORING clause of the index", Severity: suggestion, Category: "INDEX_STORING_SUGGESTION",
+		CategoryDescription: "Some index key columns can be moved to the STORING clause"},
+	internal.IndexNullsOrder:              {Brief: "Spanner GoogleSQL dialect does not support NULLS FIRST/LAST in index keys. The source null ordering of this index key is not preserved", Severity: warning, Category: "INDEX_NULLS_ORDER"},
+	internal.UnsignedInteger:              {Brief: "Unsigned integer column mapped to a type that preserves values above the maximum of INT64, as configured by the unsignedIntPolicy", Severity: warning, Category: "UNSIGNED_INTEGER"},
+	internal.TimeAsSeconds:                {Brief: "TIME values are stored as the number of seconds they represent, values with fractional seconds can't be converted", Severity: warning, Category: "TIME_AS_SECONDS"},
+	internal.Interval:                     {Brief: "Spanner does not support interval types, intervals are stored as ISO 8601 durations, e.g. P1Y2M3DT4H5M6S", Severity: warning, Category: "INTERVAL_TYPE_USES"},
+	internal.IntervalAsMicroseconds:       {Brief: "Intervals are stored as a number of microseconds, counting a month as 30 days and a year as 365.25 days", Severity: warning, Category: "INTERVAL_AS_MICROSECONDS"},
+	internal.DomainType:                   {Brief: "Spanner does not support domains, the column uses the base type of the domain and the domain constraints are converted to check constraints", Severity: warning, Category: "DOMAIN_TYPE"},
+	internal.CompositeType:                {Brief: "Spanner does not support composite types, values are stored as JSON objects with a string property for each field of the composite type", Severity: warning, Category: "COMPOSITE_TYPE"},
+	internal.CaseInsensitiveCollation:     {Brief: "Spanner compares strings byte by byte, so comparisons, unique indexes and lookups on the column become case-sensitive", Severity: warning, Category: "CASE_INSENSITIVE_COLLATION"},
+	internal.LocaleCollation:              {Brief: "Spanner compares strings byte by byte, so the column is sorted by code point instead of the locale-specific order of the collation", Severity: warning, Category: "LOCALE_COLLATION"},
+	internal.NormalizedColumn:             {Brief: "is a generated column storing the lower case values of a column with a case-insensitive collation, and is indexed so that lookups can stay case-insensitive", Severity: note, Category: "NORMALIZED_COLUMN_ADDED"},
+	internal.DdlRejected:                  {Brief: "DDL statement rejected by the Spanner emulator", Severity: Errors, Category: "DDL_REJECTED"},
+	internal.ObjectDropped:                {Brief: "Foreign key or secondary index dropped by the migration profile, to be recreated after the migration", Severity: note, Category: "OBJECT_DROPPED"},
+	internal.InvalidUTF8:                  {Brief: "has values which are not valid UTF-8 after conversion from the charset of the source, their invalid bytes were replaced with U+FFFD", Severity: warning, Category: "INVALID_UTF8"},
+	internal.SampledStringLength:          {Brief: "Sampled values are much shorter than the length of the column, a tighter STRING length can be used", Severity: suggestion, Category: "SAMPLED_STRING_LENGTH"},
+	internal.SampledValuesExceedNumeric:   {Brief: "Sampled values exceed the precision of NUMERIC, 29 digits before and 9 after the decimal point. FLOAT64 or STRING can store them", Severity: warning, Category: "SAMPLED_VALUES_EXCEED_NUMERIC"},
+	internal.SampledValuesFitNumeric:      {Brief: "Sampled values fit the precision of NUMERIC, which stores them exactly unlike FLOAT64", Severity: suggestion, Category: "SAMPLED_VALUES_FIT_NUMERIC"},
+	internal.SampledNoNulls:               {Brief: "has no NULL values in the sampled rows and could be made NOT NULL", Severity: suggestion, Category: "SAMPLED_NO_NULLS"},
+	internal.InterleaveOnDeleteDiverges:   {Brief: "The ON DELETE action of the interleaving differs from the one of the source foreign key it replaces", Severity: warning, Category: "INTERLEAVE_ON_DELETE"},
+	internal.InterleaveCycle:              {Brief: "The parent tables of interleaved tables can't form a cycle", Severity: Errors, Category: "INTERLEAVE_CYCLE"},
+	internal.InterleaveTooDeep:            {Brief: fmt.Sprintf("Spanner supports at most %d levels of interleaved tables", internal.MaxInterleaveDepth), Severity: Errors, Category: "INTERLEAVE_TOO_DEEP"},
+	internal.CheckConstraintColumnRetyped: {Brief: "A column referenced by a check constraint changed type", Severity: warning, Category: "CHECK_CONSTRAINT_COLUMN_RETYPED"},
+	internal.TooManyColumns:               {Brief: "The table has more columns than Spanner supports", Severity: Errors, Category: "TOO_MANY_COLUMNS"},
+	internal.TooManyIndexes:               {Brief: "The table has more secondary indexes than Spanner supports", Severity: Errors, Category: "TOO_MANY_INDEXES"},
+	internal.KeyLimitExceeded:             {Brief: "A primary key or index key exceeds the Spanner limits of key columns or key size", Severity: Errors, Category: "KEY_LIMIT_EXCEEDED"},
+	internal.InvalidLength:                {Brief: "The length of a STRING or BYTES column is out of the bounds supported by Spanner", Severity: Errors, Category: "INVALID_LENGTH"},
+	internal.CommitLimitExceeded:          {Brief: "Writing a row exceeds the Spanner limit of mutations per commit", Severity: Errors, Category: "COMMIT_LIMIT_EXCEEDED"},
+	internal.WideRow:                      {Brief: "Rows with values of the maximum length exceed the Spanner commit size limit and can't be written", Severity: warning, Category: "WIDE_ROW"},
+	internal.AppAutoIncrementOrdering:     {Brief: "The application orders rows by the auto-increment column or reads its latest value, but Spanner sequences and identity columns generate unordered values", Severity: warning, Category: "APP_AUTO_INCREMENT_ORDERING"},
+	internal.AppCaseInsensitiveLookup:     {Brief: "The application looks up values of the column, which become case-sensitive in Spanner", Severity: warning, Category: "APP_CASE_INSENSITIVE_LOOKUP"},
+	internal.AppSchemaChange:              {Brief: "The application code has to change for the schema conversion", Severity: note, Category: "APP_SCHEMA_CHANGE"},
+	internal.VectorColumn:                 {Brief: "HNSW and IVFFlat indexes are not migrated: create a vector index and query nearest neighbors with APPROX_COSINE_DISTANCE() or APPROX_EUCLIDEAN_DISTANCE(), and exact distances with COSINE_DISTANCE() or EUCLIDEAN_DISTANCE()", Severity: note, Category: "VECTOR_COLUMN"},
+	internal.JSONPathIndexSuggestion:      {Brief: "can be indexed with the JSON path index bulk action, which adds a generated column and an index for each path", Severity: suggestion, Category: "JSON_PATH_INDEX_SUGGESTION"},
+	internal.JSONPathColumn:               {Brief: "is a generated column storing a JSON path the application filters or orders rows by, and is indexed so that these queries don't scan the table", Severity: note, Category: "JSON_PATH_COLUMN_ADDED"},
 }
 
 type Severity int
@@ -697,6 +861,27 @@ const (
 	Errors
 )
 
+// appFindingIssue describes the findings of the application code assessment
+// of issue about the column colId of the table, or about the table if colId
+// is empty, with an example of the queries or code of the findings.
+func appFindingIssue(conv *internal.Conv, tableId, colId string, issue internal.SchemaIssue) Issue {
+	var findings []internal.AppFinding
+	for _, f := range conv.AppFindings[tableId] {
+		if f.ColId == colId && f.Issue == issue {
+			findings = append(findings, f)
+		}
+	}
+	description := fmt.Sprintf("Table '%s': ", conv.SpSchema[tableId].Name)
+	if colId != "" {
+		description += fmt.Sprintf("Column '%s': ", conv.SpSchema[tableId].ColDefs[colId].Name)
+	}
+	description += IssueDB[issue].Brief
+	if len(findings) > 0 {
+		description += fmt.Sprintf(". Found %d times, e.g. `%s` in %s", len(findings), findings[0].Detail, findings[0].Source)
+	}
+	return Issue{Category: IssueDB[issue].Category, Description: description}
+}
+
 // AnalyzeCols returns information about the quality of schema mappings
 // for table 'srcTable'. It assumes 'srcTable' is in the conv.SrcSchema map.
 func AnalyzeCols(conv *internal.Conv, tableId string) (map[string][]internal.SchemaIssue, int64, int64) {
@@ -0,0 +1,167 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reports
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/jsonreport"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/proto/migration"
+)
+
+// maxSampleBadRows caps the number of bad rows included in the JSON report.
+const maxSampleBadRows = 100
+
+// GenerateJSONReport generates the versioned, machine-readable migration
+// report defined by the jsonreport package. Unlike the structured report,
+// its format is stable across releases.
+func GenerateJSONReport(driverName string, dbName string, conv *internal.Conv, badWrites map[string]int64) jsonreport.Report {
+	tableReports := AnalyzeTables(conv, badWrites)
+	rating, summary := GenerateSummary(conv, tableReports, badWrites)
+	report := jsonreport.Report{
+		SchemaVersion:  jsonreport.SchemaVersion,
+		GeneratedAt:    time.Now().UTC(),
+		Driver:         driverName,
+		DatabaseName:   dbName,
+		Dialect:        conv.SpDialect,
+		MigrationType:  mapMigrationType(*conv.Audit.MigrationType),
+		Summary:        jsonreport.Summary{Rating: rating, Text: summary},
+		Tables:         []jsonreport.Table{},
+		DroppedObjects: fetchDroppedObjects(conv),
+		SampleBadRows:  []string{},
+	}
+	for _, t := range tableReports {
+		table := toJSONTable(conv, t, badWrites[t.SrcTable])
+		report.Summary.TotalRows += table.Rows.Total
+		report.Summary.BadRows += table.Rows.Bad
+		report.Summary.BadWrites += table.Rows.BadWrites
+		report.Tables = append(report.Tables, table)
+	}
+	for _, d := range report.DroppedObjects {
+		if d.Type == jsonreport.ObjectTable {
+			report.Tables = append(report.Tables, jsonreport.Table{SourceName: d.Name, Status: jsonreport.StatusDropped, Issues: []jsonreport.Issue{}})
+			report.Summary.DroppedTables++
+		}
+	}
+	report.Summary.TotalTables = len(report.Tables)
+	for _, row := range conv.SampleBadRows(maxSampleBadRows) {
+		report.SampleBadRows = append(report.SampleBadRows, strings.TrimSuffix(row, "\n"))
+	}
+	return report
+}
+
+func toJSONTable(conv *internal.Conv, t tableReport, badWrites int64) jsonreport.Table {
+	table := jsonreport.Table{
+		SourceName: conv.SrcSchema[t.SrcTable].Name,
+		Columns:    t.Cols,
+		Issues:     []jsonreport.Issue{},
+	}
+	if spTable, ok := conv.SpSchema[t.SpTable]; ok {
+		table.SpannerName = spTable.Name
+		if pk, ok := conv.SyntheticPKeys[t.SrcTable]; ok {
+			table.SyntheticPrimaryKey = spTable.ColDefs[pk.ColId].Name
+		}
+	}
+	switch {
+	case t.Errors > 0:
+		table.Status = jsonreport.StatusConvertedWithErrors
+	case t.Warnings > 0:
+		table.Status = jsonreport.StatusConvertedWithWarnings
+	default:
+		table.Status = jsonreport.StatusConverted
+	}
+	if !conv.SchemaMode() {
+		table.Rows = jsonreport.RowCounts{Total: t.rows, Bad: conv.Stats.BadRows[t.SrcTable], BadWrites: badWrites}
+		table.DataRating, _ = rateData(t.rows, t.badRows, conv.Audit.DryRun)
+	}
+	if *conv.Audit.MigrationType != migration.MigrationData_DATA_ONLY {
+		table.SchemaRating, _ = RateSchema(t.Cols, t.Warnings, t.Errors, t.SyntheticPKey != "", false)
+	}
+	for _, body := range t.Body {
+		severity := toJSONSeverity(body.Heading)
+		for _, issue := range body.IssueBody {
+			table.Issues = append(table.Issues, jsonreport.Issue{Severity: severity, Category: issue.Category, Description: issue.Description})
+		}
+	}
+	return table
+}
+
+// toJSONSeverity maps a table report heading such as "Warnings" to an issue
+// severity.
+func toJSONSeverity(heading string) string {
+	switch strings.TrimSuffix(heading, "s") {
+	case "Error":
+		return jsonreport.SeverityError
+	case "Warning":
+		return jsonreport.SeverityWarning
+	case "Suggestion":
+		return jsonreport.SeveritySuggestion
+	case "Note":
+		return jsonreport.SeverityNote
+	}
+	return jsonreport.SeverityError
+}
+
+// fetchDroppedObjects lists the source tables, columns, indexes and foreign
+// keys which are not part of the Spanner schema, along with the source
+// statements which were skipped.
+func fetchDroppedObjects(conv *internal.Conv) []jsonreport.DroppedObject {
+	dropped := []jsonreport.DroppedObject{}
+	var tableIds []string
+	for tableId := range conv.SrcSchema {
+		tableIds = append(tableIds, tableId)
+	}
+	sort.Slice(tableIds, func(i, j int) bool { return conv.SrcSchema[tableIds[i]].Name < conv.SrcSchema[tableIds[j]].Name })
+	for _, tableId := range tableIds {
+		srcTable := conv.SrcSchema[tableId]
+		spTable, ok := conv.SpSchema[tableId]
+		if !ok {
+			dropped = append(dropped, jsonreport.DroppedObject{Type: jsonreport.ObjectTable, Name: srcTable.Name})
+			continue
+		}
+		for _, colId := range srcTable.ColIds {
+			if _, ok := spTable.ColDefs[colId]; !ok {
+				dropped = append(dropped, jsonreport.DroppedObject{Type: jsonreport.ObjectColumn, SourceTable: srcTable.Name, Name: srcTable.ColDefs[colId].Name})
+			}
+		}
+		spIndexes := make(map[string]bool)
+		for _, spIdx := range spTable.Indexes {
+			spIndexes[spIdx.Id] = true
+		}
+		for _, srcIdx := range srcTable.Indexes {
+			if !spIndexes[srcIdx.Id] {
+				dropped = append(dropped, jsonreport.DroppedObject{Type: jsonreport.ObjectIndex, SourceTable: srcTable.Name, Name: srcIdx.Name})
+			}
+		}
+		spFks := make(map[string]bool)
+		for _, spFk := range spTable.ForeignKeys {
+			spFks[spFk.Id] = true
+		}
+		for _, srcFk := range srcTable.ForeignKeys {
+			if !spFks[srcFk.Id] {
+				dropped = append(dropped, jsonreport.DroppedObject{Type: jsonreport.ObjectForeignKey, SourceTable: srcTable.Name, Name: srcFk.Name})
+			}
+		}
+	}
+	ignored := fetchIgnoredStatements(conv)
+	sort.Slice(ignored, func(i, j int) bool { return ignored[i].Statement < ignored[j].Statement })
+	for _, s := range ignored {
+		dropped = append(dropped, jsonreport.DroppedObject{Type: jsonreport.ObjectStatement, Name: s.Statement})
+	}
+	return dropped
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reports
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/jsonreport"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/proto/migration"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateJSONReport(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SpDialect = constants.DIALECT_GOOGLESQL
+	conv.Audit.MigrationType = migration.MigrationData_SCHEMA_AND_DATA.Enum()
+	conv.SkipStatement("CreateTrigStmt")
+	conv.SetDataMode()
+	conv.SrcSchema = map[string]schema.Table{
+		"t1": {
+			Name:   "orders",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3"},
+			ColDefs: map[string]schema.Column{
+				"c1": {Name: "id", Id: "c1", Type: schema.Type{Name: "bigint"}},
+				"c2": {Name: "amount", Id: "c2", Type: schema.Type{Name: "float"}},
+				"c3": {Name: "notes", Id: "c3", Type: schema.Type{Name: "text"}},
+			},
+			PrimaryKeys: []schema.Key{{ColId: "c1"}},
+			Indexes:     []schema.Index{{Name: "idx_amount", Id: "i1", Keys: []schema.Key{{ColId: "c2"}}}},
+		},
+		"t2": {
+			Name:    "audit",
+			Id:      "t2",
+			ColIds:  []string{"c4"},
+			ColDefs: map[string]schema.Column{"c4": {Name: "msg", Id: "c4", Type: schema.Type{Name: "text"}}},
+		},
+	}
+	conv.SpSchema = map[string]ddl.CreateTable{
+		"t1": {
+			Name:   "orders",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Name: "amount", Id: "c2", T: ddl.Type{Name: ddl.Float64}},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1"}},
+		},
+	}
+	conv.SchemaIssues = map[string]internal.TableIssues{
+		"t1": {ColumnLevelIssues: map[string][]internal.SchemaIssue{"c2": {internal.Widened}}},
+	}
+	conv.Stats.Rows["t1"] = 10
+	conv.Stats.GoodRows["t1"] = 8
+	conv.Stats.BadRows["t1"] = 2
+	conv.CollectBadRow("orders", []string{"id", "amount"}, []string{"1", "abc"})
+
+	report := GenerateJSONReport(constants.MYSQL, "db", conv, map[string]int64{"t1": 1})
+
+	assert.Equal(t, jsonreport.SchemaVersion, report.SchemaVersion)
+	assert.Equal(t, "SCHEMA_AND_DATA", report.MigrationType)
+	assert.Equal(t, 2, report.Summary.TotalTables)
+	assert.Equal(t, 1, report.Summary.DroppedTables)
+	assert.Equal(t, int64(10), report.Summary.TotalRows)
+	assert.Equal(t, int64(2), report.Summary.BadRows)
+	assert.Equal(t, int64(1), report.Summary.BadWrites)
+	assert.Equal(t, []jsonreport.DroppedObject{
+		{Type: jsonreport.ObjectTable, Name: "audit"},
+		{Type: jsonreport.ObjectColumn, SourceTable: "orders", Name: "notes"},
+		{Type: jsonreport.ObjectIndex, SourceTable: "orders", Name: "idx_amount"},
+		{Type: jsonreport.ObjectStatement, Name: "CreateTrigStmt"},
+	}, report.DroppedObjects)
+
+	orders := report.Tables[0]
+	assert.Equal(t, "orders", orders.SourceName)
+	assert.Equal(t, "orders", orders.SpannerName)
+	assert.Equal(t, jsonreport.StatusConvertedWithWarnings, orders.Status)
+	assert.Equal(t, jsonreport.RowCounts{Total: 10, Bad: 2, BadWrites: 1}, orders.Rows)
+	assert.Len(t, orders.Issues, 1)
+	assert.Equal(t, jsonreport.SeverityWarning, orders.Issues[0].Severity)
+	assert.Equal(t, IssueDB[internal.Widened].Category, orders.Issues[0].Category)
+	assert.Equal(t, jsonreport.Table{SourceName: "audit", Status: jsonreport.StatusDropped, Issues: []jsonreport.Issue{}}, report.Tables[1])
+	assert.Len(t, report.SampleBadRows, 1)
+}
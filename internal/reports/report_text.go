@@ -18,6 +18,13 @@ func (r *ReportImpl) GenerateTextReport(structuredReport StructuredReport, w *bu
 	w.WriteString(structuredReport.Summary.Text)
 	w.WriteString("\n")
 	w.WriteString(writeConversionMetadata(structuredReport.ConversionMetadata, w))
+	writeObjectSummary(structuredReport, w)
+	if structuredReport.SnapshotPosition != "" {
+		justifyLines(w, fmt.Sprintf("Data was read from a consistent snapshot of the source "+
+			"database. To capture subsequent changes, start change data capture from "+
+			"source position %s.", structuredReport.SnapshotPosition), 80, 0)
+		w.WriteString("\n\n")
+	}
 	if len(structuredReport.IgnoredStatements) > 0 {
 		justifyLines(w, fmt.Sprintf("Note that the following source DB statements "+
 			"were detected but ignored: %s.",
@@ -42,6 +49,11 @@ func (r *ReportImpl) GenerateTextReport(structuredReport StructuredReport, w *bu
 		writeStatementStats(structuredReport, w)
 	}
 	writeNameChanges(structuredReport, w)
+	writeAddedTables(structuredReport, w)
+	writeExcludedTables(structuredReport, w)
+	writeShiftedTimestamps(structuredReport, w)
+	writeRoundedNumerics(structuredReport, w)
+	writeStorageEstimates(structuredReport, w)
 	writeTableReports(structuredReport, w)
 	writeUnexpectedConditionsv2(structuredReport, w)
 
@@ -159,6 +171,93 @@ func writeNameChanges(structuredReport StructuredReport, w *bufio.Writer) {
 	}
 }
 
+func writeAddedTables(structuredReport StructuredReport, w *bufio.Writer) {
+	if len(structuredReport.AddedTables) == 0 {
+		return
+	}
+	writeHeading(w, "Tables Added in Spanner")
+	justifyLines(w, "The following tables were added to the Spanner schema and have no "+
+		"source table, so no data is migrated to them: "+
+		strings.Join(structuredReport.AddedTables, ", ")+".", 80, 0)
+	w.WriteString("\n\n")
+}
+
+func writeExcludedTables(structuredReport StructuredReport, w *bufio.Writer) {
+	if len(structuredReport.ExcludedTables) == 0 {
+		return
+	}
+	writeHeading(w, "Excluded Tables")
+	justifyLines(w, "The following source tables were excluded by --include-tables or "+
+		"--exclude-tables, so neither their schema nor their data is migrated: "+
+		strings.Join(structuredReport.ExcludedTables, ", ")+".", 80, 0)
+	w.WriteString("\n\n")
+}
+
+func writeShiftedTimestamps(structuredReport StructuredReport, w *bufio.Writer) {
+	if len(structuredReport.ShiftedTimestamps) == 0 {
+		return
+	}
+	writeHeading(w, "Timezone Adjustments")
+	justifyLines(w, "The following tables have date and time values without a time zone "+
+		"which were converted to Spanner timestamps in a non-UTC time zone, "+
+		"according to the timezone policy and the time zones of columns.", 80, 0)
+	w.WriteString("\n\n")
+	fmt.Fprintf(w, "  %10s  %s\n", "values", "table")
+	for _, s := range structuredReport.ShiftedTimestamps {
+		fmt.Fprintf(w, "  %10d  %s\n", s.Count, s.SrcTable)
+	}
+	w.WriteString("\n")
+}
+
+func writeRoundedNumerics(structuredReport StructuredReport, w *bufio.Writer) {
+	if len(structuredReport.RoundedNumerics) == 0 {
+		return
+	}
+	writeHeading(w, "Numeric Precision Loss")
+	justifyLines(w, "The following columns have values with more than 9 digits after the "+
+		"decimal point, which were rounded when written to Spanner NUMERIC columns. "+
+		"Use the numericPrecisionPolicy target profile param to reject these rows instead.", 80, 0)
+	w.WriteString("\n\n")
+	fmt.Fprintf(w, "  %10s  %s\n", "values", "column")
+	for _, r := range structuredReport.RoundedNumerics {
+		fmt.Fprintf(w, "  %10d  %s.%s\n", r.Count, r.SrcTable, r.SrcColumn)
+	}
+	w.WriteString("\n")
+}
+
+func writeStorageEstimates(structuredReport StructuredReport, w *bufio.Writer) {
+	if len(structuredReport.StorageEstimates) == 0 {
+		return
+	}
+	writeHeading(w, "Estimated Spanner Storage")
+	justifyLines(w, "The following estimates of the Spanner storage of each table are based on "+
+		"the types of its columns, the number of rows of the source table and the lengths of "+
+		"the sampled source values, if any. Use them for capacity planning only. "+
+		fmt.Sprintf("The estimated storage of the database is %s.", formatBytes(structuredReport.EstimatedStorage)), 80, 0)
+	w.WriteString("\n\n")
+	fmt.Fprintf(w, "  %12s  %10s  %10s  %s\n", "rows", "data", "indexes", "table")
+	for _, e := range structuredReport.StorageEstimates {
+		fmt.Fprintf(w, "  %12d  %10s  %10s  %s\n", e.Rows, formatBytes(e.DataBytes), formatBytes(e.IndexBytes), e.SpTable)
+	}
+	w.WriteString("\n")
+}
+
+// formatBytes prints a number of bytes in the largest unit it has at least
+// one of, e.g. 1.5 GB.
+func formatBytes(n int64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	v := float64(n)
+	i := 0
+	for v >= 1024 && i < len(units)-1 {
+		v /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f %s", v, units[i])
+}
+
 func writeStatementStats(structuredReport StructuredReport, w *bufio.Writer) {
 	type stat struct {
 		statement string
@@ -16,6 +16,7 @@
 package reports
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
@@ -23,15 +24,16 @@ import (
 )
 
 // A report consists of the following parts:
-// 1. Summary (overall quality of conversion)
+// 1. Summary (overall quality of conversion, and counts of schema objects)
 // 2. Sharding information
 // 2. Ignored statements
 // 3. Conversion duration
 // 4. Migration Type
 // 5. Statement stats (in case of dumps)
 // 6. Name changes
-// 7. Individual table reports (Detailed + Quality of conversion for each)
-// 8. Unexpected conditions
+// 7. Tables added in Spanner, without a source table
+// 8. Individual table reports (Detailed + Quality of conversion for each)
+// 9. Unexpected conditions
 //
 // This method the RAW structured report in JSON format. Several utilities can be built on top of
 // this raw, nested JSON data to output the reports in different user and machine friendly formats
@@ -43,6 +45,7 @@ func (r *ReportImpl) GenerateStructuredReport(driverName string, dbName string,
 	//1. Generate summary
 	rating, summary := GenerateSummary(conv, tableReports, badWrites)
 	smtReport.Summary = Summary{Text: summary, Rating: rating, DbName: dbName}
+	smtReport.ObjectSummary = buildObjectSummary(conv, tableReports)
 
 	//2. Sharding information
 	smtReport.IsSharded = conv.IsSharded
@@ -70,16 +73,26 @@ func (r *ReportImpl) GenerateStructuredReport(driverName string, dbName string,
 	//7. Name changes
 	smtReport.NameChanges = fetchNameChanges(conv)
 
-	//8. Table Reports
+	//8. Tables added in Spanner
+	smtReport.AddedTables = fetchAddedTables(conv)
+	smtReport.ExcludedTables = conv.ExcludedTables
+	smtReport.ShiftedTimestamps = fetchShiftedTimestamps(conv)
+	smtReport.RoundedNumerics = fetchRoundedNumerics(conv)
+	smtReport.StorageEstimates, smtReport.EstimatedStorage = fetchStorageEstimates(conv)
+
+	//9. Table Reports
 	if printTableReports {
 		smtReport.TableReports = fetchTableReports(tableReports, conv)
 	}
 
-	//9. Unexpected Conditions
+	//10. Unexpected Conditions
 	if printUnexpecteds {
 		smtReport.UnexpectedConditions = fetchUnexceptedConditions(driverName, conv)
 	}
 
+	//11. Consistent snapshot position
+	smtReport.SnapshotPosition = conv.Audit.SnapshotPosition
+
 	return smtReport
 }
 
@@ -125,7 +138,10 @@ func fetchStatementStats(driverName string, conv *internal.Conv) (statementStats
 
 func fetchNameChanges(conv *internal.Conv) (nameChanges []NameChange) {
 	for tableId, spTable := range conv.SpSchema {
-		srcTable := conv.SrcSchema[tableId]
+		srcTable, ok := conv.SrcSchema[tableId]
+		if !ok {
+			continue
+		}
 		if srcTable.Name != spTable.Name {
 			nameChanges = append(nameChanges, NameChange{NameChangeType: "TableName", SourceTable: srcTable.Name, OldName: srcTable.Name, NewName: spTable.Name})
 		}
@@ -156,10 +172,71 @@ func fetchNameChanges(conv *internal.Conv) (nameChanges []NameChange) {
 				nameChanges = append(nameChanges, NameChange{NameChangeType: "Index", SourceTable: srcTable.Name, OldName: srcIdx.Name, NewName: spIdx.Name})
 			}
 		}
+		for _, spCc := range conv.SpSchema[tableId].CheckConstraints {
+			for _, srcCc := range srcTable.CheckConstraints {
+				if srcCc.Id == spCc.Id && srcCc.Name != spCc.Name {
+					nameChanges = append(nameChanges, NameChange{NameChangeType: "CheckConstraint", SourceTable: srcTable.Name, OldName: srcCc.Name, NewName: spCc.Name})
+				}
+			}
+		}
 	}
 	return nameChanges
 }
 
+// fetchAddedTables returns the sorted names of the Spanner tables which were
+// added to the schema without a source table.
+func fetchAddedTables(conv *internal.Conv) (addedTables []string) {
+	for tableId, spTable := range conv.SpSchema {
+		if internal.IsAddedTable(conv, tableId) {
+			addedTables = append(addedTables, spTable.Name)
+		}
+	}
+	sort.Strings(addedTables)
+	return addedTables
+}
+
+// fetchShiftedTimestamps returns the number of timestamps shifted by the
+// timezone policy for each source table, sorted by table name.
+func fetchShiftedTimestamps(conv *internal.Conv) (shifted []ShiftedTimestamps) {
+	for srcTable, n := range conv.Stats.ShiftedTimestamps {
+		shifted = append(shifted, ShiftedTimestamps{SrcTable: srcTable, Count: n})
+	}
+	sort.Slice(shifted, func(i, j int) bool { return shifted[i].SrcTable < shifted[j].SrcTable })
+	return shifted
+}
+
+// fetchStorageEstimates returns the estimated Spanner storage of each table,
+// sorted by table name, and of the database. Nothing is returned if the
+// number of rows of the source tables isn't known.
+func fetchStorageEstimates(conv *internal.Conv) ([]StorageEstimate, int64) {
+	tables, total := conv.EstimateStorage()
+	if total == 0 {
+		return nil, 0
+	}
+	var estimates []StorageEstimate
+	for _, t := range tables {
+		estimates = append(estimates, StorageEstimate{SpTable: conv.SpSchema[t.TableId].Name, Rows: t.Rows, DataBytes: t.DataBytes, IndexBytes: t.IndexBytes})
+	}
+	return estimates, total
+}
+
+// fetchRoundedNumerics returns the number of values rounded when written to
+// NUMERIC columns for each source column, sorted by table and column name.
+func fetchRoundedNumerics(conv *internal.Conv) (rounded []RoundedNumerics) {
+	for srcTable, cols := range conv.Stats.RoundedNumerics {
+		for srcCol, n := range cols {
+			rounded = append(rounded, RoundedNumerics{SrcTable: srcTable, SrcColumn: srcCol, Count: n})
+		}
+	}
+	sort.Slice(rounded, func(i, j int) bool {
+		if rounded[i].SrcTable != rounded[j].SrcTable {
+			return rounded[i].SrcTable < rounded[j].SrcTable
+		}
+		return rounded[i].SrcColumn < rounded[j].SrcColumn
+	})
+	return rounded
+}
+
 func fetchTableReports(inputTableReports []tableReport, conv *internal.Conv) (tableReports []TableReport) {
 	for _, t := range inputTableReports {
 		//1. src and Sp Table Names
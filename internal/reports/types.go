@@ -107,16 +107,48 @@ type UnexpectedConditions struct {
 	UnexpectedConditions []UnexpectedCondition `json:"unexpectedConditions"`
 }
 
+// ShiftedTimestamps is the number of values without time zone of a source
+// table which were shifted to a non-UTC time zone by the timezone policy.
+type ShiftedTimestamps struct {
+	SrcTable string `json:"srcTable"`
+	Count    int64  `json:"count"`
+}
+
+// RoundedNumerics is the number of values of a source column which were
+// rounded when written to a Spanner NUMERIC column.
+type RoundedNumerics struct {
+	SrcTable  string `json:"srcTable"`
+	SrcColumn string `json:"srcColumn"`
+	Count     int64  `json:"count"`
+}
+
+// StorageEstimate is the estimated Spanner storage of a table once its source
+// rows are migrated, in bytes.
+type StorageEstimate struct {
+	SpTable    string `json:"spTable"`
+	Rows       int64  `json:"rows"`
+	DataBytes  int64  `json:"dataBytes"`
+	IndexBytes int64  `json:"indexBytes"`
+}
+
 type StructuredReport struct {
 	Summary              Summary              `json:"summary"`
+	ObjectSummary        ObjectSummary        `json:"objectSummary"`
 	IsSharded            bool                 `json:"isSharded"`
 	IgnoredStatements    []IgnoredStatement   `json:"ignoredStatements"`
 	ConversionMetadata   []ConversionMetadata `json:"conversionMetadata"`
 	MigrationType        string               `json:"migrationType"`
 	StatementStats       StatementStats       `json:"statementStats"`
 	NameChanges          []NameChange         `json:"nameChanges"`
+	AddedTables          []string             `json:"addedTables,omitempty"`
+	ExcludedTables       []string             `json:"excludedTables,omitempty"`
+	ShiftedTimestamps    []ShiftedTimestamps  `json:"shiftedTimestamps,omitempty"`
+	RoundedNumerics      []RoundedNumerics    `json:"roundedNumerics,omitempty"`
+	StorageEstimates     []StorageEstimate    `json:"storageEstimates,omitempty"`
+	EstimatedStorage     int64                `json:"estimatedStorage,omitempty"`
 	TableReports         []TableReport        `json:"tableReports"`
 	UnexpectedConditions UnexpectedConditions `json:"unexpectedConditions"`
+	SnapshotPosition     string               `json:"snapshotPosition,omitempty"`
 	SchemaOnly           bool                 `json:"-"`
 }
 
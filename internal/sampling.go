@@ -0,0 +1,377 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// DataSample selects the rows of a sampled data migration, which quickly
+// produces a small but referentially consistent database, e.g. to test the
+// application. Rows of the tables referencing no other table are kept with
+// a probability of Percent, each table being sampled independently. Rows of
+// the other tables are kept if the rows they reference, through foreign keys
+// or interleaving, were written, so that the sampled parents keep all their
+// children. At most Rows rows are kept per table.
+//
+// The rows referenced by a row must be written before it: the tables of a
+// direct connection are migrated parents first, and dump files are read once
+// per round of DataSampleRounds.
+type DataSample struct {
+	Percent float64 // Percentage of the rows of the tables referencing no other table, 0 keeping them all.
+	Rows    int64   // Maximum number of rows per table, 0 for no maximum.
+}
+
+// ParseDataSample returns the sample s, a percentage of the rows, e.g. "1%",
+// or a number of rows per table, e.g. "10000" or "10k". An empty s keeps
+// all rows.
+func ParseDataSample(s string) (DataSample, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return DataSample{}, nil
+	}
+	if p, ok := strings.CutSuffix(s, "%"); ok {
+		percent, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return DataSample{}, fmt.Errorf("invalid sample %q: the percentage must be greater than 0 and at most 100", s)
+		}
+		return DataSample{Percent: percent}, nil
+	}
+	multiplier := int64(1)
+	switch {
+	case strings.HasSuffix(strings.ToLower(s), "k"):
+		multiplier, s = 1000, s[:len(s)-1]
+	case strings.HasSuffix(strings.ToLower(s), "m"):
+		multiplier, s = 1000000, s[:len(s)-1]
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return DataSample{}, fmt.Errorf("invalid sample %q: expected a percentage of the rows, e.g. 1%%, or a number of rows per table, e.g. 10k", s)
+	}
+	return DataSample{Rows: n * multiplier}, nil
+}
+
+// Enabled returns whether s leaves rows out.
+func (s DataSample) Enabled() bool {
+	return s.Percent > 0 || s.Rows > 0
+}
+
+// dataSampler is the state of a sampled data migration.
+type dataSampler struct {
+	DataSample
+	mu     sync.Mutex
+	tables map[string]*sampledTable   // By Spanner table name.
+	keys   map[string]map[string]bool // Keys of the kept rows, by referenced key.
+	round  map[string]bool            // Source tables of the current pass over a dump, nil when all tables are read.
+}
+
+// sampledTable is how the rows of a table reference and are referenced by
+// other rows.
+type sampledTable struct {
+	root       bool
+	kept       int64
+	references []sampledKey // Keys of the rows referenced by the rows of the table.
+	referenced []sampledKey // Keys of the rows of the table referenced by other rows.
+	pk         []string     // Primary key column names.
+}
+
+// sampledKey is a key of a table referenced by foreign keys or interleaved
+// tables: id identifies the key, and cols are the columns of its values.
+type sampledKey struct {
+	id   string
+	cols []string
+}
+
+// SetDataSample makes the data migration keep the rows selected by s only.
+func (conv *Conv) SetDataSample(s DataSample) {
+	if !s.Enabled() {
+		conv.sample = nil
+		return
+	}
+	conv.sample = &dataSampler{DataSample: s, tables: make(map[string]*sampledTable), keys: make(map[string]map[string]bool)}
+}
+
+// DataSample returns the sample of the data migration.
+func (conv *Conv) DataSample() DataSample {
+	if conv.sample == nil {
+		return DataSample{}
+	}
+	return conv.sample.DataSample
+}
+
+// sampleRow returns whether the row of spTable is kept by the sample of the
+// data migration, and records its keys if it is.
+func (conv *Conv) sampleRow(spTable string, spCols []string, spVals []interface{}) bool {
+	s := conv.sample
+	if s == nil {
+		return true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tables[spTable]
+	if !ok {
+		t = conv.newSampledTable(spTable)
+		s.tables[spTable] = t
+	}
+	if s.Rows > 0 && t.kept >= s.Rows {
+		return false
+	}
+	values := sampleValues(spCols, spVals)
+	for _, ref := range t.references {
+		if key, ok := sampleKeyValue(ref.cols, values); ok && !s.keys[ref.id][key] {
+			return false
+		}
+	}
+	if t.root && s.Percent > 0 {
+		cols := t.pk
+		if len(cols) == 0 {
+			cols = spCols
+		}
+		key, _ := sampleKeyValue(cols, values)
+		// The table is part of the hash, so that the tables with the same
+		// keys aren't sampled alike.
+		h := fnv.New64a()
+		h.Write([]byte(spTable))
+		h.Write([]byte{0})
+		h.Write([]byte(key))
+		if float64(h.Sum64()%1000000) >= s.Percent*10000 {
+			return false
+		}
+	}
+	t.kept++
+	for _, ref := range t.referenced {
+		if key, ok := sampleKeyValue(ref.cols, values); ok {
+			if s.keys[ref.id] == nil {
+				s.keys[ref.id] = make(map[string]bool)
+			}
+			s.keys[ref.id][key] = true
+		}
+	}
+	return true
+}
+
+// unsampleRow forgets the keys of a row of spTable kept by the sample whose
+// write failed, so that the rows referencing it are left out of the sample
+// too. The rows referencing it which were written already are orphans.
+func (conv *Conv) unsampleRow(spTable string, spCols []string, spVals []interface{}) {
+	s := conv.sample
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tables[spTable]
+	if !ok {
+		return
+	}
+	values := sampleValues(spCols, spVals)
+	for _, ref := range t.referenced {
+		if key, ok := sampleKeyValue(ref.cols, values); ok {
+			delete(s.keys[ref.id], key)
+		}
+	}
+}
+
+func sampleValues(spCols []string, spVals []interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	for i, c := range spCols {
+		if i < len(spVals) {
+			values[c] = spVals[i]
+		}
+	}
+	return values
+}
+
+// sampleKeyValue returns the values of cols in values, or false if one of
+// them is NULL: such rows reference no row.
+func sampleKeyValue(cols []string, values map[string]interface{}) (string, bool) {
+	var b strings.Builder
+	for i, c := range cols {
+		v, ok := values[c]
+		if !ok || v == nil {
+			return "", false
+		}
+		if i > 0 {
+			b.WriteByte(0)
+		}
+		fmt.Fprintf(&b, "%v", v)
+	}
+	return b.String(), true
+}
+
+// newSampledTable returns the keys the rows of spTable reference and are
+// referenced by.
+func (conv *Conv) newSampledTable(spTable string) *sampledTable {
+	t := &sampledTable{root: true}
+	tableId, err := GetTableIdFromSpName(conv.SpSchema, spTable)
+	if err != nil {
+		return t
+	}
+	ct := conv.SpSchema[tableId]
+	names := func(ct ddl.CreateTable, colIds []string) []string {
+		var l []string
+		for _, colId := range colIds {
+			l = append(l, ct.ColDefs[colId].Name)
+		}
+		return l
+	}
+	for _, k := range ct.PrimaryKeys {
+		t.pk = append(t.pk, ct.ColDefs[k.ColId].Name)
+	}
+	for _, fk := range ct.ForeignKeys {
+		if _, ok := conv.SpSchema[fk.ReferTableId]; ok {
+			t.references = append(t.references, sampledKey{id: sampledKeyId(fk.ReferTableId, fk.ReferColumnIds), cols: names(ct, fk.ColIds)})
+			// Rows referencing rows of their own table are sampled like the other rows.
+			t.root = t.root && fk.ReferTableId == tableId
+		}
+	}
+	if parent, ok := conv.SpSchema[ct.ParentTable.Id]; ok && ct.ParentTable.Id != tableId && len(parent.PrimaryKeys) <= len(ct.PrimaryKeys) {
+		var parentCols, cols []string
+		for i, k := range parent.PrimaryKeys {
+			parentCols = append(parentCols, k.ColId)
+			cols = append(cols, ct.PrimaryKeys[i].ColId)
+		}
+		t.references = append(t.references, sampledKey{id: sampledKeyId(parent.Id, parentCols), cols: names(ct, cols)})
+		t.root = false
+	}
+	seen := make(map[string]bool)
+	addReferenced := func(colIds []string) {
+		id := sampledKeyId(tableId, colIds)
+		if !seen[id] {
+			seen[id] = true
+			t.referenced = append(t.referenced, sampledKey{id: id, cols: names(ct, colIds)})
+		}
+	}
+	for _, other := range conv.SpSchema {
+		for _, fk := range other.ForeignKeys {
+			if fk.ReferTableId == tableId {
+				addReferenced(fk.ReferColumnIds)
+			}
+		}
+		if other.ParentTable.Id == tableId && other.Id != tableId {
+			var pk []string
+			for _, k := range ct.PrimaryKeys {
+				pk = append(pk, k.ColId)
+			}
+			addReferenced(pk)
+		}
+	}
+	return t
+}
+
+func sampledKeyId(tableId string, colIds []string) string {
+	return tableId + ":" + strings.Join(colIds, ",")
+}
+
+// orderParentsFirst returns tableIds ordered so that the tables referenced by
+// a table, through foreign keys or interleaving, come before it. Tables keep
+// their order otherwise, and cycles are broken in that order.
+func (conv *Conv) orderParentsFirst(tableIds []string) []string {
+	pending := slices.Clone(tableIds)
+	var ordered []string
+	ready := func(tableId string) bool {
+		for _, p := range sampleParents(conv.SpSchema[tableId]) {
+			if slices.Contains(pending, p) {
+				return false
+			}
+		}
+		return true
+	}
+	for len(pending) > 0 {
+		i := slices.IndexFunc(pending, ready)
+		if i < 0 {
+			i = 0
+		}
+		ordered = append(ordered, pending[i])
+		pending = slices.Delete(pending, i, i+1)
+	}
+	return ordered
+}
+
+// sampleParents returns the ids of the tables ct is interleaved in or
+// references with foreign keys, except ct itself.
+func sampleParents(ct ddl.CreateTable) []string {
+	parents := []string{ct.ParentTable.Id}
+	for _, fk := range ct.ForeignKeys {
+		parents = append(parents, fk.ReferTableId)
+	}
+	return slices.DeleteFunc(parents, func(p string) bool { return p == "" || p == ct.Id })
+}
+
+// DataSampleRounds returns the source tables of the successive passes over
+// a dump of a sampled data migration, or nil if the data migration isn't
+// sampled. The rows of a dump are read in the order of the dump, but the
+// tables of a round only reference tables of the previous rounds, so that
+// the rows they reference are written first. Tables of a cycle of
+// references are in the rounds of their order in OrderTableIds.
+func (conv *Conv) DataSampleRounds() [][]string {
+	if conv.sample == nil {
+		return nil
+	}
+	ordered := conv.OrderTableIds(ddl.GetSortedTableIdsBySpName(conv.SpSchema))
+	var rounds [][]string
+	round := make(map[string]int)
+	for i, tableId := range ordered {
+		r := 0
+		for _, p := range sampleParents(conv.SpSchema[tableId]) {
+			if slices.Contains(ordered[:i], p) {
+				r = max(r, round[p]+1)
+			}
+		}
+		round[tableId] = r
+		srcTable, ok := conv.SrcSchema[tableId]
+		if !ok {
+			continue
+		}
+		for len(rounds) <= r {
+			rounds = append(rounds, nil)
+		}
+		rounds[r] = append(rounds[r], srcTable.Name)
+	}
+	return rounds
+}
+
+// SetDataSampleRound makes the data migration read the rows of srcTables
+// only, one of the rounds of DataSampleRounds, or all rows if srcTables is
+// nil.
+func (conv *Conv) SetDataSampleRound(srcTables []string) {
+	if conv.sample == nil {
+		return
+	}
+	conv.sample.mu.Lock()
+	defer conv.sample.mu.Unlock()
+	conv.sample.round = nil
+	if srcTables != nil {
+		conv.sample.round = make(map[string]bool)
+		for _, t := range srcTables {
+			conv.sample.round[t] = true
+		}
+	}
+}
+
+// outOfRound returns whether the rows of srcTable aren't read in the current
+// round of the sampled data migration.
+func (s *dataSampler) outOfRound(srcTable string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.round != nil && !s.round[srcTable]
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDataSample(t *testing.T) {
+	for _, tc := range []struct {
+		s    string
+		want DataSample
+	}{
+		{"", DataSample{}},
+		{"1%", DataSample{Percent: 1}},
+		{" 0.5 % ", DataSample{Percent: 0.5}},
+		{"10000", DataSample{Rows: 10000}},
+		{"10k", DataSample{Rows: 10000}},
+		{"2M", DataSample{Rows: 2000000}},
+	} {
+		got, err := ParseDataSample(tc.s)
+		assert.NoError(t, err, tc.s)
+		assert.Equal(t, tc.want, got, tc.s)
+	}
+	for _, s := range []string{"0%", "101%", "-5", "0", "ten", "1.5k"} {
+		_, err := ParseDataSample(s)
+		assert.Error(t, err, s)
+	}
+}
+
+// sampleTestConv returns a conv with customers, their orders referencing them
+// with a foreign key, and the items of the orders interleaved in them.
+func sampleTestConv() *Conv {
+	conv := MakeConv()
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Id: "t1", Name: "customers", ColIds: []string{"c1"},
+		ColDefs:     map[string]ddl.ColumnDef{"c1": {Id: "c1", Name: "id", T: ddl.Type{Name: ddl.Int64}}},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+	}
+	conv.SpSchema["t2"] = ddl.CreateTable{
+		Id: "t2", Name: "orders", ColIds: []string{"c2", "c3"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c2": {Id: "c2", Name: "id", T: ddl.Type{Name: ddl.Int64}},
+			"c3": {Id: "c3", Name: "customer_id", T: ddl.Type{Name: ddl.Int64}},
+		},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c2", Order: 1}},
+		ForeignKeys: []ddl.Foreignkey{{Id: "f1", Name: "fk_customer", ColIds: []string{"c3"}, ReferTableId: "t1", ReferColumnIds: []string{"c1"}}},
+	}
+	conv.SpSchema["t3"] = ddl.CreateTable{
+		Id: "t3", Name: "items", ColIds: []string{"c4", "c5"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c4": {Id: "c4", Name: "order_id", T: ddl.Type{Name: ddl.Int64}},
+			"c5": {Id: "c5", Name: "line", T: ddl.Type{Name: ddl.Int64}},
+		},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c4", Order: 1}, {ColId: "c5", Order: 2}},
+		ParentTable: ddl.InterleavedParent{Id: "t2"},
+	}
+	return conv
+}
+
+func TestSampleRowPercent(t *testing.T) {
+	conv := sampleTestConv()
+	conv.SetDataSample(DataSample{Percent: 10})
+	kept := make(map[int64]bool)
+	for id := int64(0); id < 1000; id++ {
+		if conv.sampleRow("customers", []string{"id"}, []interface{}{id}) {
+			kept[id] = true
+		}
+	}
+	assert.Greater(t, len(kept), 50)
+	assert.Less(t, len(kept), 150)
+	for id := int64(0); id < 1000; id++ {
+		customer := id % 100
+		// Orders of the sampled customers, and orders without customer, are kept.
+		assert.Equal(t, kept[customer], conv.sampleRow("orders", []string{"id", "customer_id"}, []interface{}{id, customer}))
+		assert.Equal(t, kept[customer], conv.sampleRow("items", []string{"order_id", "line"}, []interface{}{id, int64(1)}))
+	}
+	assert.True(t, conv.sampleRow("orders", []string{"id", "customer_id"}, []interface{}{int64(5000), nil}))
+
+	// The sample is deterministic.
+	conv.SetDataSample(DataSample{Percent: 10})
+	for id := int64(0); id < 1000; id++ {
+		assert.Equal(t, kept[id], conv.sampleRow("customers", []string{"id"}, []interface{}{id}))
+	}
+}
+
+func TestSampleRowRows(t *testing.T) {
+	conv := sampleTestConv()
+	conv.SetDataSample(DataSample{Rows: 2})
+	assert.True(t, conv.sampleRow("customers", []string{"id"}, []interface{}{int64(1)}))
+	assert.True(t, conv.sampleRow("customers", []string{"id"}, []interface{}{int64(2)}))
+	assert.False(t, conv.sampleRow("customers", []string{"id"}, []interface{}{int64(3)}))
+	assert.False(t, conv.sampleRow("orders", []string{"id", "customer_id"}, []interface{}{int64(1), int64(3)}))
+	assert.True(t, conv.sampleRow("orders", []string{"id", "customer_id"}, []interface{}{int64(2), int64(2)}))
+	assert.False(t, conv.sampleRow("items", []string{"order_id", "line"}, []interface{}{int64(1), int64(1)}))
+	assert.True(t, conv.sampleRow("items", []string{"order_id", "line"}, []interface{}{int64(2), int64(1)}))
+}
+
+func TestWriteRowSample(t *testing.T) {
+	conv := sampleTestConv()
+	conv.SetDataSample(DataSample{Rows: 1})
+	conv.SetDataMode()
+	var rows []interface{}
+	conv.SetDataSink(func(table string, cols []string, vals []interface{}) { rows = append(rows, vals[0]) })
+	for id := int64(1); id <= 3; id++ {
+		conv.StatsAddRow("customers", true)
+		conv.WriteRow("customers", "customers", []string{"id"}, []interface{}{id})
+	}
+	assert.Equal(t, []interface{}{int64(1)}, rows)
+	assert.Equal(t, int64(1), conv.Stats.GoodRows["customers"])
+	assert.Equal(t, int64(2), conv.Stats.SampledOutRows["customers"])
+}
+
+func TestOrderTableIdsSample(t *testing.T) {
+	conv := sampleTestConv()
+	conv.SpSchema["t0"] = ddl.CreateTable{
+		Id: "t0", Name: "audit", ForeignKeys: []ddl.Foreignkey{{ReferTableId: "t2"}},
+	}
+	tableIds := []string{"t0", "t3", "t2", "t1"}
+	assert.Equal(t, tableIds, conv.OrderTableIds(tableIds))
+	conv.SetDataSample(DataSample{Percent: 1})
+	assert.Equal(t, []string{"t1", "t2", "t0", "t3"}, conv.OrderTableIds(tableIds))
+}
+
+func TestSampleRowIndependentTables(t *testing.T) {
+	conv := sampleTestConv()
+	conv.SpSchema["t4"] = ddl.CreateTable{
+		Id: "t4", Name: "products", ColIds: []string{"c6"},
+		ColDefs:     map[string]ddl.ColumnDef{"c6": {Id: "c6", Name: "id", T: ddl.Type{Name: ddl.Int64}}},
+		PrimaryKeys: []ddl.IndexKey{{ColId: "c6", Order: 1}},
+	}
+	conv.SetDataSample(DataSample{Percent: 50})
+	same := 0
+	for id := int64(0); id < 1000; id++ {
+		if conv.sampleRow("customers", []string{"id"}, []interface{}{id}) == conv.sampleRow("products", []string{"id"}, []interface{}{id}) {
+			same++
+		}
+	}
+	// Tables with the same keys aren't sampled alike.
+	assert.Less(t, same, 600)
+}
+
+func TestCollectBadWriteSample(t *testing.T) {
+	conv := sampleTestConv()
+	conv.SetDataSample(DataSample{Rows: 10})
+	assert.True(t, conv.sampleRow("customers", []string{"id"}, []interface{}{int64(1)}))
+	assert.True(t, conv.sampleRow("customers", []string{"id"}, []interface{}{int64(2)}))
+	// The orders of a customer whose write failed are left out.
+	conv.CollectBadWrite("customers", []string{"id"}, []interface{}{int64(1)}, fmt.Errorf("write failed"))
+	assert.False(t, conv.sampleRow("orders", []string{"id", "customer_id"}, []interface{}{int64(1), int64(1)}))
+	assert.True(t, conv.sampleRow("orders", []string{"id", "customer_id"}, []interface{}{int64(2), int64(2)}))
+}
+
+func TestDataSampleRounds(t *testing.T) {
+	conv := sampleTestConv()
+	conv.SpSchema["t0"] = ddl.CreateTable{
+		Id: "t0", Name: "audit", ForeignKeys: []ddl.Foreignkey{{ReferTableId: "t2"}},
+	}
+	for _, id := range []string{"t0", "t1", "t2", "t3"} {
+		conv.SrcSchema[id] = schema.Table{Id: id, Name: "src_" + conv.SpSchema[id].Name}
+	}
+	assert.Nil(t, conv.DataSampleRounds())
+	conv.SetDataSample(DataSample{Percent: 1})
+	rounds := conv.DataSampleRounds()
+	assert.Equal(t, [][]string{{"src_customers"}, {"src_orders"}, {"src_audit", "src_items"}}, rounds)
+
+	conv.SetDataMode()
+	conv.SetDataSampleRound(rounds[1])
+	assert.True(t, conv.SkipTable("src_customers"))
+	assert.False(t, conv.SkipTable("src_orders"))
+	conv.SetDataSampleRound(nil)
+	assert.False(t, conv.SkipTable("src_customers"))
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Phases of a migration reported by MigrationStatus.
+const (
+	StatusPhaseSchema = "schema"
+	StatusPhaseData   = "data"
+	StatusPhaseDone   = "done"
+)
+
+// MigrationStatus tracks the progress of a migration run from the command
+// line, for the status server of --status-port. Unlike the stats of Conv, it
+// is safe for concurrent use, so that it can be read while the data is
+// written. A nil MigrationStatus ignores all updates.
+type MigrationStatus struct {
+	mu        sync.Mutex
+	start     time.Time
+	dataStart time.Time
+	phase     string
+	spToSrc   map[string]string // Maps Spanner table name to source table name.
+	tables    map[string]*TableStatus
+}
+
+// TableStatus is the progress of the data migration of a source table.
+type TableStatus struct {
+	Table string `json:"table"`
+	// TotalRows is the number of rows of the table in the source database,
+	// estimated for some sources, or 0 if unknown.
+	TotalRows   int64 `json:"totalRows"`
+	RowsWritten int64 `json:"rowsWritten"`
+	// Errors is the number of rows which couldn't be converted or written.
+	Errors int64 `json:"errors"`
+}
+
+// StatusReport is a snapshot of a MigrationStatus.
+type StatusReport struct {
+	Phase          string    `json:"phase"`
+	StartTime      time.Time `json:"startTime"`
+	ElapsedSeconds int64     `json:"elapsedSeconds"`
+	TotalRows      int64     `json:"totalRows"`
+	RowsWritten    int64     `json:"rowsWritten"`
+	Errors         int64     `json:"errors"`
+	// EtaSeconds is the estimated time left to migrate the data, at the
+	// current rate, or nil if it can't be estimated yet.
+	EtaSeconds *int64        `json:"etaSeconds"`
+	Tables     []TableStatus `json:"tables"`
+}
+
+// NewMigrationStatus returns the status of a migration starting now, with
+// its schema phase.
+func NewMigrationStatus() *MigrationStatus {
+	return &MigrationStatus{start: time.Now(), phase: StatusPhaseSchema, tables: make(map[string]*TableStatus)}
+}
+
+// StartData starts the data phase of the migration of conv, whose row stats
+// hold the number of rows of each source table.
+func (s *MigrationStatus) StartData(conv *Conv) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.phase = StatusPhaseData
+	s.dataStart = time.Now()
+	s.spToSrc = make(map[string]string)
+	for id, table := range conv.SpSchema {
+		if srcTable, ok := conv.SrcSchema[id]; ok {
+			s.spToSrc[table.Name] = srcTable.Name
+		}
+	}
+	for srcTable, n := range conv.Stats.Rows {
+		s.table(srcTable).TotalRows = n
+	}
+}
+
+// Done ends the migration.
+func (s *MigrationStatus) Done() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.phase = StatusPhaseDone
+}
+
+// table returns the status of srcTable. Callers must hold s.mu.
+func (s *MigrationStatus) table(srcTable string) *TableStatus {
+	t, ok := s.tables[srcTable]
+	if !ok {
+		t = &TableStatus{Table: srcTable}
+		s.tables[srcTable] = t
+	}
+	return t
+}
+
+// srcTable returns the source table of spTable. Callers must hold s.mu.
+func (s *MigrationStatus) srcTable(spTable string) string {
+	if srcTable, ok := s.spToSrc[spTable]; ok {
+		return srcTable
+	}
+	return spTable
+}
+
+// AddWrittenRows records n rows written to the Spanner table spTable.
+func (s *MigrationStatus) AddWrittenRows(spTable string, n int64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table(s.srcTable(spTable)).RowsWritten += n
+}
+
+// AddDroppedRow records a row which couldn't be written to the Spanner table
+// spTable.
+func (s *MigrationStatus) AddDroppedRow(spTable string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table(s.srcTable(spTable)).Errors++
+}
+
+// AddBadRow records a row of srcTable which couldn't be converted.
+func (s *MigrationStatus) AddBadRow(srcTable string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.table(srcTable).Errors++
+}
+
+// Report returns the current status, the tables sorted by name.
+func (s *MigrationStatus) Report() StatusReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elapsed := time.Since(s.start)
+	r := StatusReport{Phase: s.phase, StartTime: s.start, ElapsedSeconds: int64(elapsed.Seconds()), Tables: []TableStatus{}}
+	for _, t := range s.tables {
+		r.TotalRows += t.TotalRows
+		r.RowsWritten += t.RowsWritten
+		r.Errors += t.Errors
+		r.Tables = append(r.Tables, *t)
+	}
+	sort.Slice(r.Tables, func(i, j int) bool { return r.Tables[i].Table < r.Tables[j].Table })
+	switch done := r.RowsWritten + r.Errors; {
+	case s.phase == StatusPhaseDone:
+		eta := int64(0)
+		r.EtaSeconds = &eta
+	case s.phase == StatusPhaseData && done > 0 && r.TotalRows > done:
+		eta := int64(time.Since(s.dataStart).Seconds() * float64(r.TotalRows-done) / float64(done))
+		r.EtaSeconds = &eta
+	}
+	return r
+}
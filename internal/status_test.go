@@ -0,0 +1,53 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationStatus(t *testing.T) {
+	// A nil status ignores updates.
+	var none *MigrationStatus
+	none.StartData(MakeConv())
+	none.AddWrittenRows("t", 1)
+	none.AddBadRow("t")
+	none.Done()
+
+	s := NewMigrationStatus()
+	r := s.Report()
+	assert.Equal(t, StatusPhaseSchema, r.Phase)
+	assert.Nil(t, r.EtaSeconds)
+
+	conv := MakeConv()
+	conv.Status = s
+	conv.SetDataMode()
+	conv.Stats.Rows["src"] = 3
+	s.StartData(conv)
+	conv.StatsAddBadRow("src", true)
+	s.AddWrittenRows("unknown", 2)
+	r = s.Report()
+	assert.Equal(t, StatusPhaseData, r.Phase)
+	assert.Equal(t, []TableStatus{{Table: "src", TotalRows: 3, Errors: 1}, {Table: "unknown", RowsWritten: 2}}, r.Tables)
+	assert.Equal(t, int64(3), r.TotalRows)
+	assert.Nil(t, r.EtaSeconds)
+
+	s.Done()
+	r = s.Report()
+	assert.Equal(t, StatusPhaseDone, r.Phase)
+	assert.Equal(t, int64(0), *r.EtaSeconds)
+}
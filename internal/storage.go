@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+const (
+	// cellOverhead is the storage used by Spanner for each value besides the
+	// value itself, e.g. for its commit timestamp.
+	cellOverhead = 8
+	// defaultValueLength is the length assumed for the values of variable
+	// length columns without sampled statistics.
+	defaultValueLength = 64
+)
+
+// TableStorage is the estimated Spanner storage of a table, in bytes.
+type TableStorage struct {
+	TableId    string
+	Rows       int64 // Number of rows of the source table.
+	RowBytes   int64 // Average size of a row.
+	DataBytes  int64
+	IndexBytes int64 // Size of the secondary indexes of the table.
+}
+
+// columnBytes returns the estimated average size of the values of column
+// colId of table tableId, using the statistics sampled from the source column
+// if available.
+func (conv *Conv) columnBytes(tableId, colId string) int64 {
+	cd := conv.SpSchema[tableId].ColDefs[colId]
+	stats, sampled := conv.ColumnStats[tableId][colId]
+	notNull := 1.0
+	if sampled {
+		notNull -= stats.NullFraction()
+	}
+	size := keyValueSize[cd.T.Name]
+	switch {
+	case cd.T.IsArray:
+		size = defaultValueLength
+	case cd.T.Name == ddl.String || cd.T.Name == ddl.Bytes || cd.T.Name == ddl.JSON:
+		switch {
+		case sampled && stats.SampledRows > stats.NullCount:
+			size = stats.LengthP50
+		case cd.T.Len != ddl.MaxLength && cd.T.Len > 0 && cd.T.Len < defaultValueLength:
+			size = cd.T.Len
+		default:
+			size = defaultValueLength
+		}
+	}
+	return int64(float64(size+cellOverhead) * notNull)
+}
+
+// srcRowCount returns the number of rows of the source table of table
+// tableId: the number of rows counted for the data migration if known,
+// otherwise the estimate read from the source database during schema
+// conversion.
+func (conv *Conv) srcRowCount(tableId, srcName string) int64 {
+	if n := conv.Stats.Rows[srcName]; n > 0 {
+		return n
+	}
+	return conv.SrcRowCounts[tableId]
+}
+
+// EstimateTableStorage returns the estimated Spanner storage of table tableId
+// once its source rows are migrated, from the types of its columns, the
+// number of rows of the source table and the statistics sampled from the
+// source columns. Each entry of a secondary index holds its key columns, the
+// primary key of the table and the stored columns.
+func (conv *Conv) EstimateTableStorage(tableId string) (TableStorage, bool) {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return TableStorage{}, false
+	}
+	srcName := ct.Name
+	if srcTable, ok := conv.SrcSchema[tableId]; ok {
+		srcName = srcTable.Name
+	}
+	ts := TableStorage{TableId: tableId, Rows: conv.srcRowCount(tableId, srcName)}
+	for _, colId := range ct.ColIds {
+		ts.RowBytes += conv.columnBytes(tableId, colId)
+	}
+	var pkBytes int64
+	for _, pk := range ct.PrimaryKeys {
+		pkBytes += conv.columnBytes(tableId, pk.ColId)
+	}
+	var entryBytes int64
+	for _, idx := range ct.Indexes {
+		entryBytes += pkBytes
+		for _, k := range idx.Keys {
+			entryBytes += conv.columnBytes(tableId, k.ColId)
+		}
+		for _, colId := range idx.StoredColumnIds {
+			entryBytes += conv.columnBytes(tableId, colId)
+		}
+	}
+	ts.DataBytes = ts.Rows * ts.RowBytes
+	ts.IndexBytes = ts.Rows * entryBytes
+	return ts, true
+}
+
+// EstimateStorage returns the estimated Spanner storage of each table,
+// ordered by table name, and the total storage of the database in bytes.
+func (conv *Conv) EstimateStorage() ([]TableStorage, int64) {
+	var tables []TableStorage
+	var total int64
+	for tableId := range conv.SpSchema {
+		ts, _ := conv.EstimateTableStorage(tableId)
+		tables = append(tables, ts)
+		total += ts.DataBytes + ts.IndexBytes
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return strings.ToLower(conv.SpSchema[tables[i].TableId].Name) < strings.ToLower(conv.SpSchema[tables[j].TableId].Name)
+	})
+	return tables, total
+}
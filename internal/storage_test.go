@@ -0,0 +1,72 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateStorage(t *testing.T) {
+	conv := MakeConv()
+	conv.SrcSchema = map[string]schema.Table{"t1": {Name: "Users", Id: "t1"}, "t2": {Name: "Events", Id: "t2"}}
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			Name:   "users",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Name: "email", Id: "c2", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+				"c3": {Name: "code", Id: "c3", T: ddl.Type{Name: ddl.String, Len: 4}},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+			Indexes:     []ddl.CreateIndex{{Name: "users_by_email", Keys: []ddl.IndexKey{{ColId: "c2"}}}},
+		},
+		"t2": {
+			Name:        "events",
+			Id:          "t2",
+			ColIds:      []string{"c4"},
+			ColDefs:     map[string]ddl.ColumnDef{"c4": {Name: "payload", Id: "c4", T: ddl.Type{Name: ddl.JSON}}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c4", Order: 1}},
+		},
+	}
+	conv.ColumnStats = map[string]map[string]ColumnStats{
+		"t1": {"c2": {SampledRows: 10, NullCount: 5, LengthP50: 24}},
+	}
+	conv.Stats.Rows = map[string]int64{"Users": 1000}
+
+	// id: 8 + 8, email: (24 + 8) / 2 as half of the values are NULL, code: 4 + 8.
+	ts, ok := conv.EstimateTableStorage("t1")
+	assert.True(t, ok)
+	assert.Equal(t, TableStorage{TableId: "t1", Rows: 1000, RowBytes: 44, DataBytes: 44000, IndexBytes: 32000}, ts)
+
+	tables, total := conv.EstimateStorage()
+	assert.Equal(t, []TableStorage{{TableId: "t2", RowBytes: 72}, ts}, tables)
+	assert.Equal(t, int64(76000), total)
+
+	_, ok = conv.EstimateTableStorage("t9")
+	assert.False(t, ok)
+
+	// Without counted rows, e.g. during schema conversion, the row count
+	// estimated by the source database is used.
+	conv.Stats.Rows = map[string]int64{}
+	conv.SrcRowCounts = map[string]int64{"t2": 10}
+	ts, _ = conv.EstimateTableStorage("t2")
+	assert.Equal(t, TableStorage{TableId: "t2", Rows: 10, RowBytes: 72, DataBytes: 720}, ts)
+}
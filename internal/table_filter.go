@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// TableFilter selects the source tables to migrate, e.g. to iterate on a
+// subset of a large schema. Regular expressions are matched against the whole
+// source table name, which is qualified with its schema for tables outside
+// of the default schema, e.g. "sales.orders" for PostgreSQL. The zero value
+// selects all tables.
+type TableFilter struct {
+	Include *regexp.Regexp // Only tables matching Include are migrated, when set.
+	Exclude *regexp.Regexp // Tables matching Exclude are not migrated, when set.
+}
+
+// NewTableFilter returns the filter of the include and exclude regular
+// expressions, either of which can be empty.
+func NewTableFilter(include, exclude string) (TableFilter, error) {
+	var f TableFilter
+	var err error
+	if include != "" {
+		if f.Include, err = regexp.Compile("^(?:" + include + ")$"); err != nil {
+			return f, fmt.Errorf("invalid include tables regex %q: %w", include, err)
+		}
+	}
+	if exclude != "" {
+		if f.Exclude, err = regexp.Compile("^(?:" + exclude + ")$"); err != nil {
+			return f, fmt.Errorf("invalid exclude tables regex %q: %w", exclude, err)
+		}
+	}
+	return f, nil
+}
+
+// IsEmpty returns true if the filter selects all tables.
+func (f TableFilter) IsEmpty() bool {
+	return f.Include == nil && f.Exclude == nil
+}
+
+// Matches returns true if the source table srcTable is selected by the
+// filter.
+func (f TableFilter) Matches(srcTable string) bool {
+	if f.Include != nil && !f.Include.MatchString(srcTable) {
+		return false
+	}
+	return f.Exclude == nil || !f.Exclude.MatchString(srcTable)
+}
+
+// SkipTable returns true if the source table srcTable is excluded by
+// conv.TableFilter, recording it in conv.ExcludedTables in schema mode, or
+// isn't read in the current round of a sampled data migration.
+func (conv *Conv) SkipTable(srcTable string) bool {
+	if conv.sample != nil && conv.sample.outOfRound(srcTable) {
+		return true
+	}
+	if conv.TableFilter.Matches(srcTable) {
+		return false
+	}
+	if conv.SchemaMode() {
+		i := sort.SearchStrings(conv.ExcludedTables, srcTable)
+		if i == len(conv.ExcludedTables) || conv.ExcludedTables[i] != srcTable {
+			conv.ExcludedTables = append(conv.ExcludedTables, "")
+			copy(conv.ExcludedTables[i+1:], conv.ExcludedTables[i:])
+			conv.ExcludedTables[i] = srcTable
+		}
+	}
+	return true
+}
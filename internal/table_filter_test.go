@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  string
+		exclude  string
+		selected []string
+		skipped  []string
+	}{
+		{
+			name:     "Empty",
+			selected: []string{"orders", "sales.orders"},
+		},
+		{
+			name:     "Include",
+			include:  "orders|order_.*",
+			selected: []string{"orders", "order_items"},
+			skipped:  []string{"customers", "old_orders", "sales.orders"},
+		},
+		{
+			name:     "Exclude",
+			exclude:  `tmp_.*|sales\..*`,
+			selected: []string{"orders", "orders_tmp_1"},
+			skipped:  []string{"tmp_orders", "sales.orders"},
+		},
+		{
+			name:     "Include and exclude",
+			include:  "order.*",
+			exclude:  ".*_archive",
+			selected: []string{"orders", "order_items"},
+			skipped:  []string{"orders_archive", "customers"},
+		},
+	}
+	for _, tc := range tests {
+		f, err := NewTableFilter(tc.include, tc.exclude)
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.include == "" && tc.exclude == "", f.IsEmpty(), tc.name)
+		for _, table := range tc.selected {
+			assert.True(t, f.Matches(table), "%s: %s", tc.name, table)
+		}
+		for _, table := range tc.skipped {
+			assert.False(t, f.Matches(table), "%s: %s", tc.name, table)
+		}
+	}
+	_, err := NewTableFilter("orders(", "")
+	assert.Error(t, err)
+	_, err = NewTableFilter("", "[a-")
+	assert.Error(t, err)
+}
+
+func TestSkipTable(t *testing.T) {
+	conv := MakeConv()
+	conv.TableFilter, _ = NewTableFilter("", "[a-d]")
+	conv.SetSchemaMode()
+	for _, table := range []string{"c", "orders", "a", "c", "b"} {
+		assert.Equal(t, table != "orders", conv.SkipTable(table), table)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, conv.ExcludedTables)
+
+	conv.SetDataMode()
+	assert.True(t, conv.SkipTable("d"))
+	assert.Equal(t, []string{"a", "b", "c"}, conv.ExcludedTables)
+}
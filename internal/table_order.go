@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TableOrder sets the order in which the data of tables is migrated, e.g. to
+// load small reference tables first so that the application can be verified
+// while large history tables are still loading. It is a list of priority
+// classes, each a regular expression matched against the whole source table
+// name: tables of the first class are migrated first, then those of the
+// second one, etc., and tables matching no class last. Within a class,
+// tables keep their default order. The zero value keeps the default order.
+type TableOrder []*regexp.Regexp
+
+// NewTableOrder returns the order of classes, a comma separated list of
+// regular expressions, e.g. "countries|currencies,customers,.*_history".
+func NewTableOrder(classes string) (TableOrder, error) {
+	var o TableOrder
+	if strings.TrimSpace(classes) == "" {
+		return o, nil
+	}
+	for _, c := range strings.Split(classes, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			return nil, fmt.Errorf("invalid table order %q: empty priority class", classes)
+		}
+		re, err := regexp.Compile("^(?:" + c + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid table order class %q: %w", c, err)
+		}
+		o = append(o, re)
+	}
+	return o, nil
+}
+
+// Class returns the priority class of srcTable, lower classes being migrated
+// first.
+func (o TableOrder) Class(srcTable string) int {
+	for i, re := range o {
+		if re.MatchString(srcTable) {
+			return i
+		}
+	}
+	return len(o)
+}
+
+// OrderTableIds returns tableIds, in the default order of the data
+// migration, sorted by the priority class of conv.TableOrder of their source
+// table. A table interleaved in another one is never migrated before its
+// parent: its class is raised to the class of its parent. In a sampled data
+// migration, the tables referenced by foreign keys are migrated first too.
+func (conv *Conv) OrderTableIds(tableIds []string) []string {
+	ordered := conv.orderByClass(tableIds)
+	if conv.sample != nil {
+		ordered = conv.orderParentsFirst(ordered)
+	}
+	return ordered
+}
+
+func (conv *Conv) orderByClass(tableIds []string) []string {
+	if len(conv.TableOrder) == 0 {
+		return tableIds
+	}
+	classes := make(map[string]int)
+	var class func(tableId string) int
+	class = func(tableId string) int {
+		if c, ok := classes[tableId]; ok {
+			return c
+		}
+		name := conv.SpSchema[tableId].Name
+		if srcTable, ok := conv.SrcSchema[tableId]; ok {
+			name = srcTable.Name
+		}
+		c := conv.TableOrder.Class(name)
+		if parentId := conv.SpSchema[tableId].ParentTable.Id; parentId != "" && parentId != tableId {
+			if _, ok := conv.SpSchema[parentId]; ok {
+				if p := class(parentId); p > c {
+					c = p
+				}
+			}
+		}
+		classes[tableId] = c
+		return c
+	}
+	ordered := append([]string{}, tableIds...)
+	sort.SliceStable(ordered, func(i, j int) bool { return class(ordered[i]) < class(ordered[j]) })
+	return ordered
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTableOrder(t *testing.T) {
+	o, err := NewTableOrder("")
+	assert.NoError(t, err)
+	assert.Empty(t, o)
+	o, err = NewTableOrder("countries|currencies, customers")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, o.Class("currencies"))
+	assert.Equal(t, 1, o.Class("customers"))
+	assert.Equal(t, 2, o.Class("customers_history"))
+	_, err = NewTableOrder("customers,,orders")
+	assert.Error(t, err)
+	_, err = NewTableOrder("orders(")
+	assert.Error(t, err)
+}
+
+func TestOrderTableIds(t *testing.T) {
+	conv := MakeConv()
+	for id, name := range map[string]string{"t1": "audit_log", "t2": "countries", "t3": "customers", "t4": "orders", "t5": "order_items"} {
+		conv.SrcSchema[id] = schema.Table{Id: id, Name: name}
+		conv.SpSchema[id] = ddl.CreateTable{Id: id, Name: name}
+	}
+	// order_items is interleaved in orders.
+	items := conv.SpSchema["t5"]
+	items.ParentTable = ddl.InterleavedParent{Id: "t4"}
+	conv.SpSchema["t5"] = items
+	tableIds := []string{"t1", "t2", "t3", "t4", "t5"}
+	assert.Equal(t, tableIds, conv.OrderTableIds(tableIds))
+
+	conv.TableOrder, _ = NewTableOrder("countries,order_items|customers")
+	assert.Equal(t, []string{"t2", "t3", "t1", "t4", "t5"}, conv.OrderTableIds(tableIds))
+}
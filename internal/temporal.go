@@ -0,0 +1,54 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fractionalSeconds matches the fractional seconds of a time of day, e.g.
+// ".123456" in "2024-01-02 03:04:05.123456+05:30".
+var fractionalSeconds = regexp.MustCompile(`\d:\d{2}\.(\d+)`)
+
+// CheckFractionalSeconds returns an error if the fractional seconds of the
+// source value val, e.g. DATETIME(6) or timestamptz microseconds, are not
+// preserved by the converted Spanner timestamp t. Spanner timestamps have
+// nanosecond precision, so this catches both parsing bugs and source values
+// with more than 9 fractional digits.
+func CheckFractionalSeconds(val string, t time.Time) error {
+	m := fractionalSeconds.FindStringSubmatch(val)
+	if m == nil {
+		if t.Nanosecond() != 0 {
+			return fmt.Errorf("value %q has no fractional seconds but was converted to %s", val, t.Format(time.RFC3339Nano))
+		}
+		return nil
+	}
+	digits := m[1]
+	if len(digits) > 9 {
+		if strings.TrimRight(digits[9:], "0") != "" {
+			return fmt.Errorf("fractional seconds of value %q exceed the nanosecond precision of Spanner timestamps", val)
+		}
+		digits = digits[:9]
+	}
+	ns, _ := strconv.Atoi(digits + strings.Repeat("0", 9-len(digits)))
+	if t.Nanosecond() != ns {
+		return fmt.Errorf("fractional seconds of value %q were not preserved, got %s", val, t.Format(time.RFC3339Nano))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFractionalSeconds(t *testing.T) {
+	ts := func(ns int) time.Time { return time.Date(2024, 1, 2, 3, 4, 5, ns, time.UTC) }
+	tests := []struct {
+		name    string
+		val     string
+		t       time.Time
+		wantErr bool
+	}{
+		{"no fractional seconds", "2024-01-02 03:04:05", ts(0), false},
+		{"milliseconds", "2024-01-02 03:04:05.123", ts(123000000), false},
+		{"microseconds", "2024-01-02 03:04:05.000001", ts(1000), false},
+		{"microseconds with time zone", "2024-01-02 03:04:05.123456+05:30", ts(123456000), false},
+		{"nanoseconds", "2024-01-02T03:04:05.123456789Z", ts(123456789), false},
+		{"trailing zeros beyond nanoseconds", "2024-01-02 03:04:05.1234567890", ts(123456789), false},
+		{"truncated", "2024-01-02 03:04:05.123456", ts(123000000), true},
+		{"added", "2024-01-02 03:04:05", ts(1000), true},
+		{"beyond nanoseconds", "2024-01-02 03:04:05.1234567891", ts(123456789), true},
+	}
+	for _, tc := range tests {
+		err := CheckFractionalSeconds(tc.val, tc.t)
+		assert.Equal(t, tc.wantErr, err != nil, tc.name)
+	}
+}
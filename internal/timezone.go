@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// Timezone policies, which control how source values without a time zone
+// (e.g. MySQL DATETIME or PostgreSQL timestamp without time zone) are
+// converted to Spanner timestamps.
+const (
+	TimezoneUTC    = constants.TIMEZONE_POLICY_UTC    // Values are UTC times (default).
+	TimezoneSource = constants.TIMEZONE_POLICY_SOURCE // Values are local times of the source server.
+)
+
+var timezoneOffset = regexp.MustCompile(`^([+-])(\d{1,2}):(\d{2})$`)
+
+// timezoneLocations caches the locations returned by ParseTimezone, which is
+// called for every converted value.
+var timezoneLocations sync.Map
+
+// ParseTimezone returns the location of tz, which is either an offset like
+// "+05:30" (as used by MySQL) or an IANA time zone name like "Asia/Kolkata".
+func ParseTimezone(tz string) (*time.Location, error) {
+	if loc, ok := timezoneLocations.Load(tz); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := parseTimezone(tz)
+	if err != nil {
+		return nil, err
+	}
+	timezoneLocations.Store(tz, loc)
+	return loc, nil
+}
+
+func parseTimezone(tz string) (*time.Location, error) {
+	if m := timezoneOffset.FindStringSubmatch(tz); m != nil {
+		h, _ := strconv.Atoi(m[2])
+		mins, _ := strconv.Atoi(m[3])
+		if h > 14 || mins > 59 {
+			return nil, fmt.Errorf("invalid time zone offset %s", tz)
+		}
+		secs := h*3600 + mins*60
+		if m[1] == "-" {
+			secs = -secs
+		}
+		return time.FixedZone(tz, secs), nil
+	}
+	return time.LoadLocation(tz)
+}
+
+// SetColumnTimezone sets the time zone in which the values without a time
+// zone of column colId of table tableId are interpreted, overriding the
+// timezone policy, or removes the override if tz is empty.
+func (conv *Conv) SetColumnTimezone(tableId, colId, tz string) error {
+	ct, ok := conv.SpSchema[tableId]
+	if !ok {
+		return fmt.Errorf("table doesn't exist for tableId %s", tableId)
+	}
+	col, ok := ct.ColDefs[colId]
+	if !ok {
+		return fmt.Errorf("column %s doesn't exist in table %s", colId, ct.Name)
+	}
+	if tz == "" {
+		delete(conv.ColumnTimezones[tableId], colId)
+		return nil
+	}
+	if col.T.Name != ddl.Timestamp {
+		return fmt.Errorf("time zone can only be set for %s columns, column %s is %s", ddl.Timestamp, col.Name, col.T.Name)
+	}
+	if _, err := ParseTimezone(tz); err != nil {
+		return fmt.Errorf("invalid time zone for column %s: %v", col.Name, err)
+	}
+	if conv.ColumnTimezones == nil {
+		conv.ColumnTimezones = make(map[string]map[string]string)
+	}
+	if conv.ColumnTimezones[tableId] == nil {
+		conv.ColumnTimezones[tableId] = make(map[string]string)
+	}
+	conv.ColumnTimezones[tableId][colId] = tz
+	return nil
+}
+
+// TimestampLocation returns the location in which the values without a time
+// zone of column colId of table tableId are interpreted, given the location
+// srcLoc of the source server. It returns nil if the values are UTC times.
+func (conv *Conv) TimestampLocation(tableId, colId string, srcLoc *time.Location) *time.Location {
+	if tz, ok := conv.ColumnTimezones[tableId][colId]; ok {
+		if loc, err := ParseTimezone(tz); err == nil {
+			return loc
+		}
+	}
+	if conv.TimezonePolicy == TimezoneSource && srcLoc != nil {
+		return srcLoc
+	}
+	return nil
+}
+
+// ShiftTimestamp returns the time with the same wall clock as t (a time
+// without time zone parsed as UTC) in loc. Values that are shifted are
+// counted in the stats of srcTable.
+func (conv *Conv) ShiftTimestamp(srcTable string, t time.Time, loc *time.Location) time.Time {
+	shifted := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	if !shifted.Equal(t) && conv.DataMode() {
+		if conv.Stats.ShiftedTimestamps == nil {
+			conv.Stats.ShiftedTimestamps = make(map[string]int64)
+		}
+		conv.Stats.ShiftedTimestamps[srcTable]++
+	}
+	return shifted
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimezone(t *testing.T) {
+	tests := []struct {
+		tz         string
+		wantOffset int
+		wantErr    bool
+	}{
+		{"+05:30", 5*3600 + 30*60, false},
+		{"-08:00", -8 * 3600, false},
+		{"+0:00", 0, false},
+		{"UTC", 0, false},
+		{"+15:00", 0, true},
+		{"SYSTEM", 0, true},
+	}
+	for _, tc := range tests {
+		loc, err := ParseTimezone(tc.tz)
+		assert.Equal(t, tc.wantErr, err != nil, tc.tz)
+		if err == nil {
+			_, offset := time.Date(2024, 1, 1, 0, 0, 0, 0, loc).Zone()
+			assert.Equal(t, tc.wantOffset, offset, tc.tz)
+		}
+	}
+}
+
+func TestTimestampLocation(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			Name:   "events",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2", "c3"},
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Name: "created", Id: "c2", T: ddl.Type{Name: ddl.Timestamp}},
+				"c3": {Name: "updated", Id: "c3", T: ddl.Type{Name: ddl.Timestamp}},
+			},
+		},
+	}
+	srcLoc, err := ParseTimezone("+02:00")
+	assert.Nil(t, err)
+
+	assert.NotNil(t, conv.SetColumnTimezone("t1", "c1", "UTC"))
+	assert.NotNil(t, conv.SetColumnTimezone("t1", "c2", "Mars/Olympus"))
+	assert.Nil(t, conv.SetColumnTimezone("t1", "c2", "-05:00"))
+
+	// With the default policy only columns with a time zone are shifted.
+	assert.Equal(t, "-05:00", conv.TimestampLocation("t1", "c2", srcLoc).String())
+	assert.Nil(t, conv.TimestampLocation("t1", "c3", srcLoc))
+
+	conv.TimezonePolicy = TimezoneSource
+	assert.Equal(t, "-05:00", conv.TimestampLocation("t1", "c2", srcLoc).String())
+	assert.Equal(t, srcLoc, conv.TimestampLocation("t1", "c3", srcLoc))
+
+	assert.Nil(t, conv.SetColumnTimezone("t1", "c2", ""))
+	assert.Equal(t, srcLoc, conv.TimestampLocation("t1", "c2", srcLoc))
+}
+
+func TestShiftTimestamp(t *testing.T) {
+	conv := MakeConv()
+	conv.SetDataMode()
+	ts := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	loc, _ := ParseTimezone("+02:00")
+
+	shifted := conv.ShiftTimestamp("events", ts, loc)
+	assert.Equal(t, time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC), shifted.UTC())
+	assert.Equal(t, ts, conv.ShiftTimestamp("events", ts, time.UTC))
+	assert.Equal(t, map[string]int64{"events": 1}, conv.Stats.ShiftedTimestamps)
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// AddView adds a Spanner view defined by query and returns its id.
+func (conv *Conv) AddView(name, query string) (string, error) {
+	if err := conv.checkView("", name, query); err != nil {
+		return "", err
+	}
+	if conv.SpViews == nil {
+		conv.SpViews = make(map[string]ddl.CreateView)
+	}
+	id := GenerateViewId()
+	conv.SpViews[id] = ddl.CreateView{Id: id, Name: name, Query: query}
+	return id, nil
+}
+
+// UpdateView changes the name and query of the Spanner view viewId.
+func (conv *Conv) UpdateView(viewId, name, query string) error {
+	if _, ok := conv.SpViews[viewId]; !ok {
+		return fmt.Errorf("view doesn't exist for viewId %s", viewId)
+	}
+	if err := conv.checkView(viewId, name, query); err != nil {
+		return err
+	}
+	conv.SpViews[viewId] = ddl.CreateView{Id: viewId, Name: name, Query: query}
+	return nil
+}
+
+// DropView removes the Spanner view viewId.
+func (conv *Conv) DropView(viewId string) error {
+	if _, ok := conv.SpViews[viewId]; !ok {
+		return fmt.Errorf("view doesn't exist for viewId %s", viewId)
+	}
+	delete(conv.SpViews, viewId)
+	return nil
+}
+
+// checkView checks that a view named name can be defined by query, and that
+// name isn't used by a table or by a view other than viewId.
+func (conv *Conv) checkView(viewId, name, query string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("view name is empty")
+	}
+	if strings.TrimSpace(query) == "" {
+		return fmt.Errorf("query of view %s is empty", name)
+	}
+	for _, ct := range conv.SpSchema {
+		if strings.EqualFold(ct.Name, name) {
+			return fmt.Errorf("view %s has the same name as a table", name)
+		}
+	}
+	for id, v := range conv.SpViews {
+		if id != viewId && strings.EqualFold(v.Name, name) {
+			return fmt.Errorf("view %s already exists", name)
+		}
+	}
+	return nil
+}
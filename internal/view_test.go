@@ -0,0 +1,45 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViews(t *testing.T) {
+	conv := MakeConv()
+	conv.SpSchema = ddl.Schema{"t1": {Name: "orders", Id: "t1"}}
+
+	_, err := conv.AddView("Orders", "SELECT 1")
+	assert.Error(t, err)
+	_, err = conv.AddView("open_orders", " ")
+	assert.Error(t, err)
+	id, err := conv.AddView("open_orders", "SELECT id FROM orders WHERE open")
+	assert.NoError(t, err)
+	_, err = conv.AddView("OPEN_ORDERS", "SELECT 1")
+	assert.Error(t, err)
+
+	// A view can keep its own name.
+	assert.NoError(t, conv.UpdateView(id, "open_orders", "SELECT id, total FROM orders WHERE open"))
+	assert.Equal(t, ddl.CreateView{Id: id, Name: "open_orders", Query: "SELECT id, total FROM orders WHERE open"}, conv.SpViews[id])
+	assert.Error(t, conv.UpdateView("vw99", "other", "SELECT 1"))
+
+	assert.NoError(t, conv.DropView(id))
+	assert.Empty(t, conv.SpViews)
+	assert.Error(t, conv.DropView(id))
+}
@@ -1,21 +1,92 @@
 package logger
 
 import (
+	"flag"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 const LOG_FILE_NAME = "spanner-migration-tool.log"
 
+// Environment variables configuring the logger. The corresponding flags take
+// precedence over them.
+const (
+	EnvModuleLevels = "SMT_LOG_MODULE_LEVELS"
+	EnvEncoding     = "SMT_LOG_ENCODING"
+	EnvMaxSizeMB    = "SMT_LOG_MAX_SIZE_MB"
+	EnvMaxBackups   = "SMT_LOG_MAX_BACKUPS"
+)
+
 var Log *zap.Logger
 
+// Options configures the logger beyond the log level of each command.
+type Options struct {
+	// ModuleLevels overrides the log level of modules, e.g.
+	// "sources=debug,webv2=warn". A module is any directory of the tool,
+	// e.g. sources, webv2, writer or assessment, and the deepest directory
+	// of the caller with a level set wins.
+	ModuleLevels string
+	// Encoding of the console logs, console (default) or json. The log file
+	// is always JSON encoded.
+	Encoding string
+	// MaxSizeMB rotates the log file once it reaches this size. Rotation is
+	// disabled if it is 0.
+	MaxSizeMB int
+	// MaxBackups is the number of rotated log files kept, 0 keeps them all.
+	MaxBackups int
+}
+
+// options are the logger options set by flags.
+var options Options
+
 func init() {
 	Log = zap.NewExample()
 }
 
+// RegisterFlags registers the flags configuring the logger in f.
+func RegisterFlags(f *flag.FlagSet) {
+	f.StringVar(&options.ModuleLevels, "log-module-levels", "", fmt.Sprintf("Log levels of modules overriding --log-level, e.g. \"sources=debug,webv2=warn\" (env %s)", EnvModuleLevels))
+	f.StringVar(&options.Encoding, "log-encoding", "", fmt.Sprintf("Encoding of the console logs, console or json, defaults to console (env %s)", EnvEncoding))
+	f.IntVar(&options.MaxSizeMB, "log-max-size-mb", 0, fmt.Sprintf("Rotate the log file once it reaches this size in megabytes, 0 disables rotation (env %s)", EnvMaxSizeMB))
+	f.IntVar(&options.MaxBackups, "log-max-backups", 0, fmt.Sprintf("Number of rotated log files to keep, 0 keeps all of them (env %s)", EnvMaxBackups))
+}
+
+// getOptions returns the logger options set by flags, completed from the
+// environment.
+func getOptions() (Options, error) {
+	opts := options
+	if opts.ModuleLevels == "" {
+		opts.ModuleLevels = os.Getenv(EnvModuleLevels)
+	}
+	if opts.Encoding == "" {
+		opts.Encoding = os.Getenv(EnvEncoding)
+	}
+	for _, v := range []struct {
+		value *int
+		env   string
+	}{{&opts.MaxSizeMB, EnvMaxSizeMB}, {&opts.MaxBackups, EnvMaxBackups}} {
+		if s := os.Getenv(v.env); *v.value == 0 && s != "" {
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return opts, fmt.Errorf("invalid %s %q: %v", v.env, s, err)
+			}
+			*v.value = n
+		}
+	}
+	return opts, nil
+}
+
 func InitializeLogger(inputLogLevel string) error {
+	opts, err := getOptions()
+	if err != nil {
+		return err
+	}
 	// create zapper encoding config object
 	config := zap.NewProductionEncoderConfig()
 	// set logging timestamp format
@@ -24,23 +95,115 @@ func InitializeLogger(inputLogLevel string) error {
 	fileEncoder := zapcore.NewJSONEncoder(config)
 	// create encoder for logs that are written to console
 	// we create two encoders because we want to write human readable logs to console and
-	// JSON parsable logs to the file
-	consoleEncoder := zapcore.NewConsoleEncoder(config)
-	// specify log file.
-	logFile, _ := os.OpenFile(LOG_FILE_NAME, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	writer := zapcore.AddSync(logFile)
+	// JSON parsable logs to the file, unless JSON console logs are requested.
+	var consoleEncoder zapcore.Encoder
+	switch opts.Encoding {
+	case "", "console":
+		consoleEncoder = zapcore.NewConsoleEncoder(config)
+	case "json":
+		consoleEncoder = zapcore.NewJSONEncoder(config)
+	default:
+		return fmt.Errorf("invalid log encoding %q, must be console or json", opts.Encoding)
+	}
+	// specify log file, rotated by size if requested.
+	var writer zapcore.WriteSyncer
+	if opts.MaxSizeMB > 0 {
+		writer = zapcore.AddSync(&lumberjack.Logger{Filename: LOG_FILE_NAME, MaxSize: opts.MaxSizeMB, MaxBackups: opts.MaxBackups})
+	} else {
+		logFile, _ := os.OpenFile(LOG_FILE_NAME, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		writer = zapcore.AddSync(logFile)
+	}
 	// create and set the log level from the user input
 	zapLogLevel := new(zapcore.Level)
-	err := zapLogLevel.Set(inputLogLevel)
+	err = zapLogLevel.Set(inputLogLevel)
+	if err != nil {
+		return err
+	}
+	moduleLevels, err := parseModuleLevels(opts.ModuleLevels)
 	if err != nil {
 		return err
 	}
-	logLevel := zap.NewAtomicLevelAt(*zapLogLevel)
+	minLevel := *zapLogLevel
+	for _, l := range moduleLevels {
+		if l < minLevel {
+			minLevel = l
+		}
+	}
+	logLevel := zap.NewAtomicLevelAt(minLevel)
 	// create the logger
-	core := zapcore.NewTee(
+	var core zapcore.Core = zapcore.NewTee(
 		zapcore.NewCore(fileEncoder, writer, logLevel),
 		zapcore.NewCore(consoleEncoder, zapcore.AddSync(os.Stdout), logLevel),
 	)
+	if len(moduleLevels) > 0 {
+		core = &moduleCore{Core: core, level: *zapLogLevel, minLevel: minLevel, moduleLevels: moduleLevels}
+	}
 	Log = zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
 	return nil
 }
+
+// parseModuleLevels parses "module=level,..." pairs.
+func parseModuleLevels(s string) (map[string]zapcore.Level, error) {
+	levels := make(map[string]zapcore.Level)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		module, level, ok := strings.Cut(pair, "=")
+		module = strings.TrimSpace(module)
+		if !ok || module == "" {
+			return nil, fmt.Errorf("invalid module log level %q, must be of the form module=level", pair)
+		}
+		var l zapcore.Level
+		if err := l.Set(strings.TrimSpace(level)); err != nil {
+			return nil, fmt.Errorf("invalid log level of module %s: %v", module, err)
+		}
+		levels[module] = l
+	}
+	return levels, nil
+}
+
+// moduleCore filters the entries written to a core by the log level of the
+// module they were logged from, identified from the directories of the
+// caller. Entries are only filtered when written since the caller isn't known
+// when they are checked.
+type moduleCore struct {
+	zapcore.Core
+	level        zapcore.Level // Level of modules without a level of their own.
+	minLevel     zapcore.Level // Lowest level of all modules.
+	moduleLevels map[string]zapcore.Level
+}
+
+func (c *moduleCore) Enabled(l zapcore.Level) bool {
+	return l >= c.minLevel
+}
+
+func (c *moduleCore) With(fields []zapcore.Field) zapcore.Core {
+	return &moduleCore{Core: c.Core.With(fields), level: c.level, minLevel: c.minLevel, moduleLevels: c.moduleLevels}
+}
+
+func (c *moduleCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *moduleCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level < c.levelOf(ent.Caller.File) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// levelOf returns the log level of the deepest directory of file with a
+// level set.
+func (c *moduleCore) levelOf(file string) zapcore.Level {
+	dirs := strings.Split(file, "/")
+	for i := len(dirs) - 2; i >= 0; i-- {
+		if l, ok := c.moduleLevels[dirs[i]]; ok {
+			return l
+		}
+	}
+	return c.level
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestParseModuleLevels(t *testing.T) {
+	levels, err := parseModuleLevels("sources=debug, webv2 = warn,")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]zapcore.Level{"sources": zapcore.DebugLevel, "webv2": zapcore.WarnLevel}, levels)
+
+	levels, err = parseModuleLevels("")
+	assert.NoError(t, err)
+	assert.Empty(t, levels)
+
+	_, err = parseModuleLevels("sources")
+	assert.Error(t, err)
+	_, err = parseModuleLevels("sources=loud")
+	assert.Error(t, err)
+}
+
+func TestModuleCore(t *testing.T) {
+	observed, logs := observer.New(zapcore.DebugLevel)
+	core := &moduleCore{
+		Core:         observed,
+		level:        zapcore.InfoLevel,
+		minLevel:     zapcore.DebugLevel,
+		moduleLevels: map[string]zapcore.Level{"sources": zapcore.DebugLevel, "writer": zapcore.ErrorLevel},
+	}
+	log := zap.New(core).With(zap.String("k", "v"))
+	for _, e := range []struct {
+		file  string
+		level zapcore.Level
+	}{
+		{"/src/spanner-migration-tool/sources/mysql/infoschema.go", zapcore.DebugLevel},
+		{"/src/spanner-migration-tool/spanner/writer/batchwriter.go", zapcore.WarnLevel},
+		{"/src/spanner-migration-tool/spanner/writer/batchwriter.go", zapcore.ErrorLevel},
+		{"/src/spanner-migration-tool/webv2/web.go", zapcore.DebugLevel},
+		{"/src/spanner-migration-tool/webv2/web.go", zapcore.InfoLevel},
+	} {
+		if ce := log.Check(e.level, e.file); ce != nil {
+			ce.Entry.Caller = zapcore.EntryCaller{Defined: true, File: e.file}
+			ce.Write()
+		}
+	}
+	var written []string
+	for _, l := range logs.All() {
+		written = append(written, l.Level.String()+" "+l.Message)
+		assert.Equal(t, map[string]interface{}{"k": "v"}, l.ContextMap())
+	}
+	assert.Equal(t, []string{
+		"debug /src/spanner-migration-tool/sources/mysql/infoschema.go",
+		"error /src/spanner-migration-tool/spanner/writer/batchwriter.go",
+		"info /src/spanner-migration-tool/webv2/web.go",
+	}, written)
+	assert.Nil(t, zap.New(&moduleCore{Core: observed, level: zapcore.InfoLevel, minLevel: zapcore.InfoLevel}).Check(zapcore.DebugLevel, "m"))
+}
+
+func TestGetOptions(t *testing.T) {
+	t.Setenv(EnvModuleLevels, "sources=debug")
+	t.Setenv(EnvEncoding, "json")
+	t.Setenv(EnvMaxSizeMB, "10")
+	options = Options{Encoding: "console"}
+	defer func() { options = Options{} }()
+	opts, err := getOptions()
+	assert.NoError(t, err)
+	// Flags take precedence over the environment.
+	assert.Equal(t, Options{ModuleLevels: "sources=debug", Encoding: "console", MaxSizeMB: 10}, opts)
+
+	t.Setenv(EnvMaxBackups, "many")
+	_, err = getOptions()
+	assert.Error(t, err)
+}
@@ -31,6 +31,7 @@ import (
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/cmd"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2"
 	"github.com/google/subcommands"
 )
@@ -53,9 +54,11 @@ func main() {
 	subcommands.Register(&cmd.DataCmd{}, "")
 	subcommands.Register(&cmd.SchemaAndDataCmd{}, "")
 	subcommands.Register(&cmd.CleanupCmd{}, "")
+	subcommands.Register(&cmd.CutoverCmd{}, "")
 	subcommands.Register(&cmd.AssessmentCmd{}, "")
 	subcommands.Register(&webv2.WebCmd{DistDir: distDir}, "")
 	subcommands.Register(&cmd.ImportDataCmd{}, "")
+	logger.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 	os.Exit(int(subcommands.Execute(ctx)))
 }
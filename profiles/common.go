@@ -15,9 +15,13 @@
 package profiles
 
 import (
+	"bytes"
 	"encoding/csv"
 	"fmt"
 	"os"
+	"path"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -87,6 +91,84 @@ func ParseList(s string) ([]string, error) {
 	return records[0], nil
 }
 
+// SetParam returns the profile string `s` with `key` set to `value`, adding
+// the key if it is not present.
+func SetParam(s string, key string, value string) (string, error) {
+	params, err := ParseMap(s)
+	if err != nil {
+		return "", err
+	}
+	params[key] = value
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	record := make([]string, 0, len(keys))
+	for _, k := range keys {
+		record = append(record, fmt.Sprintf("%s=%s", k, params[k]))
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(record)
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n"), w.Error()
+}
+
+// IsMultiDatabaseName returns true if a dbName in a source profile names more
+// than one database, i.e. it is a ';' separated list or contains a wildcard.
+func IsMultiDatabaseName(dbName string) bool {
+	return strings.ContainsAny(dbName, ";*?")
+}
+
+// ParseDatabaseNames splits a ';' separated list of database names or patterns.
+func ParseDatabaseNames(dbName string) []string {
+	var names []string
+	for _, name := range strings.Split(dbName, ";") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// MatchDatabaseNames returns the databases in `available` matching any of
+// `patterns`, in the order they appear in `available`. Patterns support the
+// '*' and '?' wildcards.
+func MatchDatabaseNames(patterns []string, available []string) []string {
+	var matched []string
+	for _, db := range available {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, db); ok {
+				matched = append(matched, db)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+var invalidSpannerDbNameChars = regexp.MustCompile("[^a-z0-9_-]+")
+
+// GetSpannerDbNameForSourceDb returns the Spanner database name used for source
+// database `srcDb` when each source database is migrated to its own Spanner
+// database. `base` is the dbName of the target profile and may be empty.
+func GetSpannerDbNameForSourceDb(base string, srcDb string) string {
+	name := srcDb
+	if base != "" {
+		name = base + "-" + srcDb
+	}
+	name = invalidSpannerDbNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	if name == "" || name[0] < 'a' || name[0] > 'z' {
+		name = "db-" + name
+	}
+	// Spanner database ids are at most 30 characters long.
+	if len(name) > 30 {
+		name = name[:30]
+	}
+	return strings.TrimRight(name, "-_")
+}
+
 func GetSQLConnectionStr(sourceProfile SourceProfile) string {
 	sqlConnectionStr := ""
 	if sourceProfile.Ty == SourceProfileTypeConnection {
@@ -108,6 +190,9 @@ func GetSQLConnectionStr(sourceProfile SourceProfile) string {
 		case SourceProfileConnectionTypeOracle:
 			connParams := sourceProfile.Conn.Oracle
 			return getORACLEConnectionStr(connParams.Host, connParams.Port, connParams.User, connParams.Pwd, connParams.Db)
+		case SourceProfileConnectionTypeDB2:
+			connParams := sourceProfile.Conn.DB2
+			return getDB2ConnectionStr(connParams.Host, connParams.Port, connParams.User, connParams.Pwd, connParams.Db)
 		}
 	}
 	return sqlConnectionStr
@@ -167,10 +252,19 @@ func GetSchemaSampleSize(sourceProfile SourceProfile) int64 {
 				schemaSampleSize = sourceProfile.Conn.Dydb.SchemaSampleSize
 			}
 		}
+		if sourceProfile.Conn.Ty == SourceProfileConnectionTypeMongoDB {
+			if sourceProfile.Conn.MongoDB.SchemaSampleSize != 0 {
+				schemaSampleSize = sourceProfile.Conn.MongoDB.SchemaSampleSize
+			}
+		}
 	}
 	return schemaSampleSize
 }
 
+func getDB2ConnectionStr(server, port, user, password, dbName string) string {
+	return fmt.Sprintf("HOSTNAME=%s;PORT=%s;DATABASE=%s;UID=%s;PWD=%s;PROTOCOL=TCPIP", server, port, dbName, user, password)
+}
+
 func getORACLEConnectionStr(server, port, user, password, dbName string) string {
 	portNumber, _ := strconv.Atoi(port)
 	return go_ora.BuildUrl(server, portNumber, dbName, user, password, nil)
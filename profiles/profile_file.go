@@ -0,0 +1,190 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profiles
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileFile gathers the source, target and orchestration parameters of a
+// migration in a single YAML file, passed to the commands with --config. Each
+// parameter stands for a command line flag, which takes precedence over the
+// file when both are set. The yaml, flag and doc tags of the fields are used to
+// parse the file, map it to flags and document it.
+type ProfileFile struct {
+	Source        ProfileFileSource        `yaml:"source" doc:"Source database of the migration."`
+	Target        ProfileFileTarget        `yaml:"target" doc:"Target Spanner database of the migration."`
+	Orchestration ProfileFileOrchestration `yaml:"orchestration" doc:"Settings of the migration run."`
+}
+
+// ProfileFileSource is the source section of a profile file.
+type ProfileFileSource struct {
+	Type   string            `yaml:"type" flag:"source" required:"true" doc:"Source database, e.g. MySQL or PostgreSQL."`
+	Params map[string]string `yaml:"params" flag:"source-profile" doc:"Source profile parameters, e.g. host, port, user, password and dbName, or file and format."`
+}
+
+// ProfileFileTarget is the target section of a profile file.
+type ProfileFileTarget struct {
+	Params map[string]string `yaml:"params" flag:"target-profile" doc:"Target profile parameters, e.g. project, instance, dbName and dialect."`
+}
+
+// ProfileFileOrchestration is the orchestration section of a profile file.
+// Settings which don't apply to a command are rejected by that command.
+type ProfileFileOrchestration struct {
+	Project              string `yaml:"project" flag:"project" doc:"Project id of the resources generated for the migration."`
+	Prefix               string `yaml:"prefix" flag:"prefix" doc:"Prefix of the generated files."`
+	LogLevel             string `yaml:"logLevel" flag:"log-level" doc:"Log level, e.g. INFO or DEBUG."`
+	DryRun               bool   `yaml:"dryRun" flag:"dry-run" doc:"Convert without creating or writing to a Spanner database."`
+	Session              string `yaml:"session" flag:"session" doc:"Session file to restore the schema from."`
+	SessionFileName      string `yaml:"sessionFileName" flag:"session-file-name" doc:"Name of the session file written."`
+	IncludeTables        string `yaml:"includeTables" flag:"include-tables" doc:"Regular expression matching the source tables to migrate."`
+	ExcludeTables        string `yaml:"excludeTables" flag:"exclude-tables" doc:"Regular expression matching the source tables to skip."`
+	TableOrder           string `yaml:"tableOrder" flag:"table-order" doc:"Comma separated priority classes of tables whose data is migrated first."`
+	SkipForeignKeys      bool   `yaml:"skipForeignKeys" flag:"skip-foreign-keys" doc:"Don't create foreign keys after the data migration."`
+	WriteLimit           int64  `yaml:"writeLimit" flag:"write-limit" doc:"Maximum number of concurrent writes to Spanner."`
+	TableReadParallelism int    `yaml:"tableReadParallelism" flag:"table-read-parallelism" doc:"Number of workers reading each large table."`
+	DeadLetter           string `yaml:"deadLetter" flag:"dead-letter" doc:"Local file, GCS object or BigQuery table bad rows are written to."`
+	DataflowTemplate     string `yaml:"dataflowTemplate" flag:"dataflow-template" doc:"GCS path of the Dataflow template."`
+	RecordRun            bool   `yaml:"recordRun" flag:"record-run" doc:"Record the migration run in the metadata database."`
+	ReloadTables         string `yaml:"reloadTables" flag:"reload-tables" doc:"Spanner tables emptied before their data is migrated again."`
+	ReloadMode           string `yaml:"reloadMode" flag:"reload-mode" doc:"How reloaded tables are emptied, delete or recreate."`
+	ExportURI            string `yaml:"exportUri" flag:"export-uri" doc:"GCS path, local directory or BigQuery dataset the converted rows are exported to instead of Spanner."`
+	ExportFormat         string `yaml:"exportFormat" flag:"export-format" doc:"Format of the exported files, csv or avro."`
+	StatusPort           int    `yaml:"statusPort" flag:"status-port" doc:"Port of the HTTP server reporting the progress of the migration."`
+	StatusAddress        string `yaml:"statusAddress" flag:"status-address" doc:"Address the HTTP server reporting the progress of the migration listens on."`
+	Sample               string `yaml:"sample" flag:"sample" doc:"Percentage of the rows, or number of rows per table, of a sampled data migration."`
+}
+
+// LoadProfileFile reads and validates the profile file at path.
+func LoadProfileFile(path string) (ProfileFile, error) {
+	var p ProfileFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return p, fmt.Errorf("can't read profile file %s: %v", path, err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil {
+		return p, fmt.Errorf("can't parse profile file %s: %v", path, err)
+	}
+	if err := checkRequired(reflect.ValueOf(p), ""); err != nil {
+		return p, fmt.Errorf("invalid profile file %s: %v", path, err)
+	}
+	return p, nil
+}
+
+// FlagValues returns the values of the flags set by the profile file, keyed
+// by flag name. Parameters left empty in the file are omitted.
+func (p ProfileFile) FlagValues() (map[string]string, error) {
+	values := make(map[string]string)
+	err := walkProfileFile(reflect.TypeOf(p), "", func(field reflect.StructField, path string, index []int) error {
+		name := field.Tag.Get("flag")
+		v := reflect.ValueOf(p).FieldByIndex(index)
+		if name == "" || v.IsZero() {
+			return nil
+		}
+		if params, ok := v.Interface().(map[string]string); ok {
+			s, err := formatParams(params)
+			if err != nil {
+				return fmt.Errorf("invalid %s: %v", path, err)
+			}
+			values[name] = s
+			return nil
+		}
+		values[name] = fmt.Sprint(v.Interface())
+		return nil
+	})
+	return values, err
+}
+
+// ProfileFileDoc documents the parameters of profile files, in markdown.
+func ProfileFileDoc() string {
+	var b strings.Builder
+	walkProfileFile(reflect.TypeOf(ProfileFile{}), "", func(field reflect.StructField, path string, index []int) error {
+		indent := strings.Repeat("  ", len(index)-1)
+		fmt.Fprintf(&b, "%s* **`%s`**", indent, path)
+		if field.Tag.Get("required") == "true" {
+			b.WriteString(" (required)")
+		}
+		fmt.Fprintf(&b, ": %s", field.Tag.Get("doc"))
+		if name := field.Tag.Get("flag"); name != "" {
+			fmt.Fprintf(&b, " Same as `--%s`.", name)
+		}
+		b.WriteString("\n")
+		return nil
+	})
+	return b.String()
+}
+
+// walkProfileFile calls fn on the fields of t and of its nested structs, in
+// order, with their dotted yaml path and index.
+func walkProfileFile(t reflect.Type, prefix string, fn func(field reflect.StructField, path string, index []int) error, index ...int) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		path := prefix + field.Tag.Get("yaml")
+		fieldIndex := append(append([]int{}, index...), i)
+		if err := fn(field, path, fieldIndex); err != nil {
+			return err
+		}
+		if field.Type.Kind() == reflect.Struct {
+			if err := walkProfileFile(field.Type, path+".", fn, fieldIndex...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkRequired returns an error if a field of v tagged as required is empty.
+func checkRequired(v reflect.Value, prefix string) error {
+	return walkProfileFile(v.Type(), prefix, func(field reflect.StructField, path string, index []int) error {
+		if field.Tag.Get("required") == "true" && v.FieldByIndex(index).IsZero() {
+			return fmt.Errorf("%s is required", path)
+		}
+		return nil
+	})
+}
+
+// formatParams formats params as the "key1=value1,key2=value2" string parsed
+// by ParseMap, quoting the pairs which need it.
+func formatParams(params map[string]string) (string, error) {
+	var keys []string
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, k := range keys {
+		if k == "" || strings.Contains(k, "=") {
+			return "", fmt.Errorf("invalid parameter name %q", k)
+		}
+		pairs = append(pairs, k+"="+params[k])
+	}
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.Write(pairs); err != nil {
+		return "", err
+	}
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n"), w.Error()
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeProfileFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "migration.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadProfileFile(t *testing.T) {
+	path := writeProfileFile(t, `
+source:
+  type: mysql
+  params:
+    host: localhost
+    port: 3306
+    password: "a,b=c"
+target:
+  params:
+    instance: my-instance
+    dialect: google_standard_sql
+orchestration:
+  project: my-project
+  dryRun: true
+  writeLimit: 40
+`)
+	p, err := LoadProfileFile(path)
+	assert.NoError(t, err)
+	values, err := p.FlagValues()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"source":         "mysql",
+		"source-profile": `host=localhost,"password=a,b=c",port=3306`,
+		"target-profile": "dialect=google_standard_sql,instance=my-instance",
+		"project":        "my-project",
+		"dry-run":        "true",
+		"write-limit":    "40",
+	}, values)
+	// The profile strings are parsed back into the parameters of the file.
+	params, err := ParseMap(values["source-profile"])
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"host": "localhost", "port": "3306", "password": "a,b=c"}, params)
+}
+
+func TestLoadProfileFileErrors(t *testing.T) {
+	for name, content := range map[string]string{
+		"missing source type": "source:\n  params:\n    host: localhost\n",
+		"unknown key":         "source:\n  type: mysql\norchestration:\n  projectId: p\n",
+		"invalid value":       "source:\n  type: mysql\norchestration:\n  writeLimit: many\n",
+	} {
+		_, err := LoadProfileFile(writeProfileFile(t, content))
+		assert.Error(t, err, name)
+	}
+	_, err := LoadProfileFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestProfileFileDoc(t *testing.T) {
+	doc := ProfileFileDoc()
+	assert.Contains(t, doc, "* **`source`**: Source database of the migration.\n")
+	assert.Contains(t, doc, "  * **`source.type`** (required): Source database, e.g. MySQL or PostgreSQL. Same as `--source`.\n")
+	assert.Contains(t, doc, "  * **`orchestration.writeLimit`**: Maximum number of concurrent writes to Spanner. Same as `--write-limit`.\n")
+	// The documentation of profile files is generated from the struct tags.
+	content, err := os.ReadFile(filepath.Join("..", "docs", "cli", "profile-file.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), doc)
+}
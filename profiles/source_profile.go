@@ -21,7 +21,9 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/charset"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
@@ -39,8 +41,9 @@ const (
 )
 
 type SourceProfileFile struct {
-	Path   string
-	Format string
+	Path    string
+	Format  string
+	Charset string // Charset of the dump, overriding the one set by the dump e.g. with SET NAMES.
 }
 
 // Interface to create source profiles for different database dialects
@@ -53,6 +56,8 @@ type SourceProfileDialectInterface interface {
 	NewSourceProfileConnectionDynamoDB(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionDynamoDB, error)
 	NewSourceProfileConnectionOracle(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionOracle, error)
 	NewSourceProfileConnectionCassandra(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionCassandra, error)
+	NewSourceProfileConnectionMongoDB(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionMongoDB, error)
+	NewSourceProfileConnectionDB2(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionDB2, error)
 }
 
 type SourceProfileDialectImpl struct{}
@@ -79,6 +84,7 @@ func (nsp *NewSourceProfileImpl) NewSourceProfileFile(params map[string]string)
 		logger.Log.Info(fmt.Sprintf("source-profile format defaulting to `dump`\n"))
 		profile.Format = "dump"
 	}
+	profile.Charset = strings.ToLower(params["charset"])
 	return profile
 }
 
@@ -92,6 +98,8 @@ const (
 	SourceProfileConnectionTypeSqlServer
 	SourceProfileConnectionTypeOracle
 	SourceProfileConnectionTypeCassandra
+	SourceProfileConnectionTypeMongoDB
+	SourceProfileConnectionTypeDB2
 )
 
 type SourceProfileConnectionTypeCloudSQL int
@@ -157,6 +165,16 @@ type SourceProfileConnectionMySQL struct {
 	Db              string // Same as MYSQLDATABASE environment variable
 	Pwd             string // Same as MYSQLPWD environment variable
 	StreamingConfig string
+	DbNames         []string // Databases or patterns when dbName names several databases, Db is then empty.
+	MultiDb         string   // How several databases are migrated, one of constants.MULTI_DB_SEPARATE or constants.MULTI_DB_MERGED.
+	Replica         SourceProfileReplica
+	Pool            SourceProfilePool
+	// ConsistentSnapshot reads all tables within a single consistent snapshot and
+	// records the GTID set from which change data capture can be started.
+	ConsistentSnapshot bool
+	// ColumnStatsSample is the number of rows of each table sampled to compute
+	// the statistics of its columns during schema conversion, none when 0.
+	ColumnStatsSample int
 }
 
 func (spd *SourceProfileDialectImpl) NewSourceProfileConnectionMySQL(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionMySQL, error) {
@@ -211,6 +229,34 @@ func (spd *SourceProfileDialectImpl) NewSourceProfileConnectionMySQL(params map[
 		// Set default port for mysql, which rarely changes.
 		mysql.Port = "3306"
 	}
+	replica, err := newSourceProfileReplica(params, mysql.Port)
+	if err != nil {
+		return mysql, err
+	}
+	if replica.Host != "" && mysql.StreamingConfig != "" {
+		return mysql, fmt.Errorf("replicaHost is only supported for bulk data migrations")
+	}
+	mysql.Replica = replica
+	if mysql.ConsistentSnapshot, err = parseConsistentSnapshot(params, mysql.StreamingConfig); err != nil {
+		return mysql, err
+	}
+	if mysql.Pool, err = newSourceProfilePool(params); err != nil {
+		return mysql, err
+	}
+	if mysql.ColumnStatsSample, err = parseColumnStatsSample(params); err != nil {
+		return mysql, err
+	}
+	if IsMultiDatabaseName(mysql.Db) {
+		mysql.DbNames = ParseDatabaseNames(mysql.Db)
+		mysql.Db = ""
+		mysql.MultiDb, err = parseMultiDb(params)
+		if err != nil {
+			return mysql, err
+		}
+		if mysql.StreamingConfig != "" {
+			return mysql, fmt.Errorf("streaming migrations are not supported when dbName names several databases")
+		}
+	}
 	if mysql.Pwd == "" {
 		mysql.Pwd = g.GetPassword()
 	}
@@ -218,6 +264,151 @@ func (spd *SourceProfileDialectImpl) NewSourceProfileConnectionMySQL(params map[
 	return mysql, nil
 }
 
+// SourceProfileReplica is a read replica of a MySQL or PostgreSQL source from
+// which data is extracted, while schema metadata is read from the primary.
+type SourceProfileReplica struct {
+	Host string
+	Port string
+	// WaitForPrimary makes the data migration wait until the replica has applied
+	// the primary's GTID set or WAL LSN captured at the start of the migration.
+	WaitForPrimary bool
+	SyncTimeout    time.Duration
+}
+
+// newSourceProfileReplica reads the read replica params of a source profile.
+// The replica listens on the primary's port unless replicaPort is specified.
+func newSourceProfileReplica(params map[string]string, primaryPort string) (SourceProfileReplica, error) {
+	replica := SourceProfileReplica{SyncTimeout: 5 * time.Minute}
+	host, hostOk := params["replicaHost"]
+	if !hostOk {
+		for _, p := range []string{"replicaPort", "replicaConsistency", "replicaSyncTimeout"} {
+			if _, ok := params[p]; ok {
+				return replica, fmt.Errorf("%s requires replicaHost to be specified in the source-profile", p)
+			}
+		}
+		return replica, nil
+	}
+	if host == "" {
+		return replica, fmt.Errorf("specify a non-empty replicaHost")
+	}
+	replica.Host = host
+	replica.Port = primaryPort
+	if port, ok := params["replicaPort"]; ok && port != "" {
+		replica.Port = port
+	}
+	switch consistency := params["replicaConsistency"]; strings.ToLower(consistency) {
+	case "", "none":
+	case "primary":
+		replica.WaitForPrimary = true
+	default:
+		return replica, fmt.Errorf("invalid replicaConsistency %s, must be one of none or primary", consistency)
+	}
+	if timeout, ok := params["replicaSyncTimeout"]; ok {
+		seconds, err := strconv.Atoi(timeout)
+		if err != nil || seconds <= 0 {
+			return replica, fmt.Errorf("replicaSyncTimeout must be a positive number of seconds, found %s", timeout)
+		}
+		replica.SyncTimeout = time.Duration(seconds) * time.Second
+	}
+	return replica, nil
+}
+
+// SourceProfilePool tunes the connections to a MySQL or PostgreSQL source used
+// to read its schema and data. Zero values keep the defaults of the drivers.
+type SourceProfilePool struct {
+	MaxOpenConns int
+	MaxIdleConns int
+	// StatementTimeout aborts the queries running for longer, it is set as
+	// max_execution_time on MySQL and statement_timeout on PostgreSQL.
+	StatementTimeout time.Duration
+	// Keepalive is the period of the TCP keepalive probes of the connections,
+	// which keep idle connections from being dropped on long migrations.
+	Keepalive time.Duration
+}
+
+// newSourceProfilePool reads the connection pool params of a source profile.
+func newSourceProfilePool(params map[string]string) (SourceProfilePool, error) {
+	pool := SourceProfilePool{}
+	for _, p := range []struct {
+		name    string
+		value   *int
+		seconds *time.Duration
+	}{
+		{name: "maxOpenConns", value: &pool.MaxOpenConns},
+		{name: "maxIdleConns", value: &pool.MaxIdleConns},
+		{name: "statementTimeout", seconds: &pool.StatementTimeout},
+		{name: "keepalive", seconds: &pool.Keepalive},
+	} {
+		value, ok := params[p.name]
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return pool, fmt.Errorf("%s must be a positive number, found %s", p.name, value)
+		}
+		if p.value != nil {
+			*p.value = n
+		} else {
+			*p.seconds = time.Duration(n) * time.Second
+		}
+	}
+	if pool.MaxOpenConns > 0 && pool.MaxIdleConns > pool.MaxOpenConns {
+		return pool, fmt.Errorf("maxIdleConns can't be greater than maxOpenConns")
+	}
+	return pool, nil
+}
+
+// parseConsistentSnapshot reads whether the tables of a bulk data migration
+// are read within a single consistent snapshot of the source.
+func parseConsistentSnapshot(params map[string]string, streamingConfig string) (bool, error) {
+	value, ok := params["consistentSnapshot"]
+	if !ok {
+		return false, nil
+	}
+	var consistentSnapshot bool
+	switch strings.ToLower(value) {
+	case "yes", "true":
+		consistentSnapshot = true
+	case "no", "false":
+	default:
+		return false, fmt.Errorf("please specify a valid choice for consistentSnapshot: available choices(yes, no, true, false)")
+	}
+	if consistentSnapshot && streamingConfig != "" {
+		return false, fmt.Errorf("consistentSnapshot is only supported for bulk data migrations")
+	}
+	return consistentSnapshot, nil
+}
+
+// parseColumnStatsSample reads the number of rows of each table sampled to
+// compute column statistics.
+func parseColumnStatsSample(params map[string]string) (int, error) {
+	value, ok := params["columnStatsSample"]
+	if !ok {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("columnStatsSample must be a positive number of rows, found %s", value)
+	}
+	return n, nil
+}
+
+// parseMultiDb reads how a source profile naming several databases is migrated,
+// defaulting to one Spanner database per source database.
+func parseMultiDb(params map[string]string) (string, error) {
+	multiDb, ok := params["multiDb"]
+	if !ok {
+		return constants.MULTI_DB_SEPARATE, nil
+	}
+	switch strings.ToLower(multiDb) {
+	case constants.MULTI_DB_SEPARATE, constants.MULTI_DB_MERGED:
+		return strings.ToLower(multiDb), nil
+	default:
+		return "", fmt.Errorf("invalid multiDb %s, must be one of %s or %s", multiDb, constants.MULTI_DB_SEPARATE, constants.MULTI_DB_MERGED)
+	}
+}
+
 type SourceProfileConnectionCloudSQLPostgreSQL struct {
 	User         string
 	Db           string
@@ -271,6 +462,16 @@ type SourceProfileConnectionPostgreSQL struct {
 	Db              string // Same as PGDATABASE environment variable
 	Pwd             string // Same as PGPASSWORD environment variable
 	StreamingConfig string
+	DbNames         []string // Databases or patterns when dbName names several databases, Db is then empty.
+	Schemas         []string // Schemas or patterns to migrate, all user schemas when empty.
+	Replica         SourceProfileReplica
+	Pool            SourceProfilePool
+	// ConsistentSnapshot reads all tables within a single exported snapshot and
+	// records the WAL LSN from which change data capture can be started.
+	ConsistentSnapshot bool
+	// ColumnStatsSample is the number of rows of each table sampled to compute
+	// the statistics of its columns during schema conversion, none when 0.
+	ColumnStatsSample int
 }
 
 func (spd *SourceProfileDialectImpl) NewSourceProfileConnectionPostgreSQL(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionPostgreSQL, error) {
@@ -318,6 +519,38 @@ func (spd *SourceProfileDialectImpl) NewSourceProfileConnectionPostgreSQL(params
 		// Set default port for postgresql, which rarely changes.
 		pg.Port = "5432"
 	}
+	if schemas, ok := params["schemas"]; ok {
+		pg.Schemas = ParseDatabaseNames(schemas)
+	}
+	replica, err := newSourceProfileReplica(params, pg.Port)
+	if err != nil {
+		return pg, err
+	}
+	if replica.Host != "" && pg.StreamingConfig != "" {
+		return pg, fmt.Errorf("replicaHost is only supported for bulk data migrations")
+	}
+	pg.Replica = replica
+	if pg.ConsistentSnapshot, err = parseConsistentSnapshot(params, pg.StreamingConfig); err != nil {
+		return pg, err
+	}
+	if pg.Pool, err = newSourceProfilePool(params); err != nil {
+		return pg, err
+	}
+	if pg.ColumnStatsSample, err = parseColumnStatsSample(params); err != nil {
+		return pg, err
+	}
+	if IsMultiDatabaseName(pg.Db) {
+		pg.DbNames = ParseDatabaseNames(pg.Db)
+		pg.Db = ""
+		// PostgreSQL databases can't be queried over a single connection, so
+		// merging is done on the schemas of one database instead.
+		if multiDb, _ := parseMultiDb(params); multiDb == constants.MULTI_DB_MERGED {
+			return pg, fmt.Errorf("multiDb=merged is not supported across PostgreSQL databases, specify a single dbName and the schemas to merge with schemas=")
+		}
+		if pg.StreamingConfig != "" {
+			return pg, fmt.Errorf("streaming migrations are not supported when dbName names several databases")
+		}
+	}
 	if pg.Pwd == "" {
 		pg.Pwd = g.GetPassword()
 	}
@@ -528,6 +761,74 @@ func (spd *SourceProfileDialectImpl) NewSourceProfileConnectionCassandra(params
 	return cs, nil
 }
 
+type SourceProfileConnectionMongoDB struct {
+	// ExportDir is a local directory with one mongoexport file per collection,
+	// named <collection>.json, each holding one Extended JSON document per line.
+	ExportDir        string
+	SchemaSampleSize int64 // Number of documents per collection to use for inferring schema (default 100,000)
+}
+
+func (spd *SourceProfileDialectImpl) NewSourceProfileConnectionMongoDB(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionMongoDB, error) {
+	mongo := SourceProfileConnectionMongoDB{}
+	dir, ok := params["dir"]
+	if !ok || dir == "" {
+		return mongo, fmt.Errorf("please specify the directory with the mongoexport files of the collections to migrate using dir in the source-profile")
+	}
+	mongo.ExportDir = dir
+	if schemaSampleSize, ok := params["schema-sample-size"]; ok {
+		schemaSampleSizeInt, err := strconv.Atoi(schemaSampleSize)
+		if err != nil || schemaSampleSizeInt <= 0 {
+			return mongo, fmt.Errorf("could not parse schema-sample-size = %v as a valid positive int64", schemaSampleSize)
+		}
+		mongo.SchemaSampleSize = int64(schemaSampleSizeInt)
+	}
+	return mongo, nil
+}
+
+type SourceProfileConnectionDB2 struct {
+	Host   string
+	Port   string
+	User   string
+	Db     string
+	Pwd    string
+	Schema string // Schema of the tables to convert (default: the user, upper-cased).
+}
+
+func (spd *SourceProfileDialectImpl) NewSourceProfileConnectionDB2(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionDB2, error) {
+	db2 := SourceProfileConnectionDB2{}
+	host, hostOk := params["host"]
+	user, userOk := params["user"]
+	db, dbOk := params["dbName"]
+	port := params["port"]
+	pwd := params["password"]
+
+	if hostOk && userOk && dbOk {
+		// All connection params provided through source-profile. Port and password handled later.
+		db2.Host, db2.User, db2.Db, db2.Port, db2.Pwd = host, user, db, port, pwd
+		// Throw error if the input entered is empty.
+		if db2.Host == "" || db2.User == "" || db2.Db == "" {
+			return db2, fmt.Errorf("found empty string for host/user/dbName. Please specify host, port, user and dbName in the source-profile")
+		}
+	} else {
+		return db2, fmt.Errorf("please specify host, port, user and dbName in the source-profile")
+	}
+
+	if db2.Port == "" {
+		// Set default port for db2, which rarely changes.
+		db2.Port = "50000"
+	}
+	// Unqualified names are resolved in the schema of the user by Db2.
+	db2.Schema = strings.ToUpper(db2.User)
+	if schema, ok := params["schema"]; ok && schema != "" {
+		db2.Schema = schema
+	}
+	if db2.Pwd == "" {
+		db2.Pwd = g.GetPassword()
+	}
+
+	return db2, nil
+}
+
 type SourceProfileConnection struct {
 	Ty        SourceProfileConnectionType
 	Streaming bool
@@ -537,6 +838,8 @@ type SourceProfileConnection struct {
 	SqlServer SourceProfileConnectionSqlServer
 	Oracle    SourceProfileConnectionOracle
 	Cassandra SourceProfileConnectionCassandra
+	MongoDB   SourceProfileConnectionMongoDB
+	DB2       SourceProfileConnectionDB2
 }
 
 type SourceProfileConnectionCloudSQL struct {
@@ -610,6 +913,22 @@ func (nsp *NewSourceProfileImpl) NewSourceProfileConnection(source string, param
 				return conn, err
 			}
 		}
+	case "mongodb", "mongo":
+		{
+			conn.Ty = SourceProfileConnectionTypeMongoDB
+			conn.MongoDB, err = s.NewSourceProfileConnectionMongoDB(params, &utils.GetUtilInfoImpl{})
+			if err != nil {
+				return conn, err
+			}
+		}
+	case "db2":
+		{
+			conn.Ty = SourceProfileConnectionTypeDB2
+			conn.DB2, err = s.NewSourceProfileConnectionDB2(params, &utils.GetUtilInfoImpl{})
+			if err != nil {
+				return conn, err
+			}
+		}
 	default:
 		return conn, fmt.Errorf("please specify a valid source database using -source flag, received source = %v", source)
 	}
@@ -780,6 +1099,83 @@ type SourceProfile struct {
 	ConnCloudSQL SourceProfileConnectionCloudSQL
 	Config       SourceProfileConfig
 	Csv          SourceProfileCsv
+	// Regular expressions of the tables to migrate and to skip, set from the
+	// --include-tables and --exclude-tables flags, see internal.NewTableFilter.
+	IncludeTables string
+	ExcludeTables string
+}
+
+// ReplicaProfile returns a copy of the source profile that connects to the read
+// replica, and false if no read replica is configured.
+func (src SourceProfile) ReplicaProfile() (SourceProfile, bool) {
+	if src.Ty != SourceProfileTypeConnection {
+		return src, false
+	}
+	replicaSrc := src
+	switch src.Conn.Ty {
+	case SourceProfileConnectionTypeMySQL:
+		replica := src.Conn.Mysql.Replica
+		if replica.Host == "" {
+			return src, false
+		}
+		replicaSrc.Conn.Mysql.Host, replicaSrc.Conn.Mysql.Port = replica.Host, replica.Port
+		replicaSrc.Conn.Mysql.Replica = SourceProfileReplica{}
+	case SourceProfileConnectionTypePostgreSQL:
+		replica := src.Conn.Pg.Replica
+		if replica.Host == "" {
+			return src, false
+		}
+		replicaSrc.Conn.Pg.Host, replicaSrc.Conn.Pg.Port = replica.Host, replica.Port
+		replicaSrc.Conn.Pg.Replica = SourceProfileReplica{}
+	default:
+		return src, false
+	}
+	return replicaSrc, true
+}
+
+// ConnectionPool returns the connection pool settings of a MySQL or PostgreSQL
+// source profile.
+func (src SourceProfile) ConnectionPool() SourceProfilePool {
+	if src.Ty != SourceProfileTypeConnection {
+		return SourceProfilePool{}
+	}
+	switch src.Conn.Ty {
+	case SourceProfileConnectionTypeMySQL:
+		return src.Conn.Mysql.Pool
+	case SourceProfileConnectionTypePostgreSQL:
+		return src.Conn.Pg.Pool
+	}
+	return SourceProfilePool{}
+}
+
+// ColumnStatsSample returns the number of rows of each table sampled to
+// compute column statistics, 0 when they aren't collected.
+func (src SourceProfile) ColumnStatsSample() int {
+	if src.Ty != SourceProfileTypeConnection {
+		return 0
+	}
+	switch src.Conn.Ty {
+	case SourceProfileConnectionTypeMySQL:
+		return src.Conn.Mysql.ColumnStatsSample
+	case SourceProfileConnectionTypePostgreSQL:
+		return src.Conn.Pg.ColumnStatsSample
+	}
+	return 0
+}
+
+// IsSeparateMultiDatabase returns true if the source profile names several
+// databases, each of which is migrated to its own Spanner database.
+func (src SourceProfile) IsSeparateMultiDatabase() bool {
+	if src.Ty != SourceProfileTypeConnection {
+		return false
+	}
+	switch src.Conn.Ty {
+	case SourceProfileConnectionTypeMySQL:
+		return len(src.Conn.Mysql.DbNames) > 0 && src.Conn.Mysql.MultiDb == constants.MULTI_DB_SEPARATE
+	case SourceProfileConnectionTypePostgreSQL:
+		return len(src.Conn.Pg.DbNames) > 0
+	}
+	return false
 }
 
 // UseTargetSchema returns true if the driver expects an existing schema
@@ -805,6 +1201,10 @@ func (src SourceProfile) ToLegacyDriver(source string) (string, error) {
 				return "", fmt.Errorf("dump files are not supported with DynamoDB")
 			case "cassandra":
 				return "", fmt.Errorf("dump files are not supported with Cassandra")	
+			case "mongodb", "mongo":
+				return "", fmt.Errorf("dump files are not supported with MongoDB, please specify the directory of the mongoexport files using dir")
+			case "db2":
+				return "", fmt.Errorf("dump files are not supported with DB2")
 			default:
 				return "", fmt.Errorf("please specify a valid source database using -source flag, received source = %v", source)
 			}
@@ -825,6 +1225,10 @@ func (src SourceProfile) ToLegacyDriver(source string) (string, error) {
 				return constants.ORACLE, nil
 			case "cassandra":
 				return constants.CASSANDRA, nil
+			case "mongodb", "mongo":
+				return constants.MONGODB, nil
+			case "db2":
+				return constants.DB2, nil
 			default:
 				return "", fmt.Errorf("please specify a valid source database using -source flag, received source = %v", source)
 			}
@@ -880,12 +1284,18 @@ func NewSourceProfile(s string, source string, n NewSourceProfileInterface) (Sou
 	if err != nil {
 		return SourceProfile{}, fmt.Errorf("could not parse source-profile, error = %v", err)
 	}
+	if err := resolveSecrets(params); err != nil {
+		return SourceProfile{}, err
+	}
 	if strings.ToLower(source) == constants.CSV {
 		return SourceProfile{Ty: SourceProfileTypeCsv, Csv: NewSourceProfileCsv(params)}, nil
 	}
 
 	if _, ok := params["file"]; ok || filePipedToStdin() {
 		profile := n.NewSourceProfileFile(params)
+		if _, err := charset.Encoding(profile.Charset); err != nil {
+			return SourceProfile{Ty: SourceProfileTypeFile}, fmt.Errorf("invalid charset in source-profile: %v", err)
+		}
 		return SourceProfile{Ty: SourceProfileTypeFile, File: profile}, nil
 	} else if format, ok := params["format"]; ok {
 		// File is not passed in from stdin or specified using "file" flag.
@@ -906,6 +1316,24 @@ func NewSourceProfile(s string, source string, n NewSourceProfileInterface) (Sou
 	}
 }
 
+// resolveSecrets replaces the params of the source-profile which are Secret
+// Manager URIs, e.g. "password=sm://projects/p/secrets/s/versions/latest", by
+// the secrets they reference, so that credentials don't need to be passed on
+// the command line.
+func resolveSecrets(params map[string]string) error {
+	for k, v := range params {
+		if !strings.HasPrefix(v, utils.SecretManagerUriPrefix) {
+			continue
+		}
+		secret, err := utils.ResolveSecretManagerUri(v)
+		if err != nil {
+			return fmt.Errorf("could not read %s of source-profile from Secret Manager: %v", k, err)
+		}
+		params[k] = secret
+	}
+	return nil
+}
+
 var filePipedToStdin = func() bool {
 	stat, _ := os.Stdin.Stat()
 	// Data is being piped to stdin, if true. Else, stdin is from a terminal.
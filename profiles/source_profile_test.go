@@ -76,6 +76,16 @@ func (m *MockSourceProfileDialect) NewSourceProfileConnectionCassandra(params ma
 	return args.Get(0).(SourceProfileConnectionCassandra), args.Error(1)
 }
 
+func (m *MockSourceProfileDialect) NewSourceProfileConnectionMongoDB(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionMongoDB, error) {
+	args := m.Called(params, g)
+	return args.Get(0).(SourceProfileConnectionMongoDB), args.Error(1)
+}
+
+func (m *MockSourceProfileDialect) NewSourceProfileConnectionDB2(params map[string]string, g utils.GetUtilInfoInterface) (SourceProfileConnectionDB2, error) {
+	args := m.Called(params, g)
+	return args.Get(0).(SourceProfileConnectionDB2), args.Error(1)
+}
+
 func setEnvVariables() {
 	// My Sql variables
 	os.Setenv("MYSQLHOST", "0.0.0.0")
@@ -228,6 +238,12 @@ func TestNewSourceProfileFile(t *testing.T) {
 			pipedToStdin: false,
 			want:         SourceProfileFile{Format: "dump", Path: "file1.mysqldump"},
 		},
+		{
+			name:         "charset param",
+			params:       map[string]string{"file": "file1.mysqldump", "charset": "CP1251"},
+			pipedToStdin: false,
+			want:         SourceProfileFile{Format: "dump", Path: "file1.mysqldump", Charset: "cp1251"},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -388,6 +404,142 @@ func TestNewSourceProfileConnectionSQL(t *testing.T) {
 	}
 }
 
+func TestNewSourceProfileConnectionSQLMultiDatabase(t *testing.T) {
+	sourceProfileDialect := SourceProfileDialectImpl{}
+	g := GetUtilInfoMock{}
+	setGetInfoMockValues(&g)
+
+	mysql, err := sourceProfileDialect.NewSourceProfileConnectionMySQL(map[string]string{"host": "a", "user": "b", "dbName": "shop;crm_*", "password": "e"}, &g)
+	assert.Nil(t, err)
+	assert.Equal(t, "", mysql.Db)
+	assert.Equal(t, []string{"shop", "crm_*"}, mysql.DbNames)
+	assert.Equal(t, constants.MULTI_DB_SEPARATE, mysql.MultiDb)
+
+	mysql, err = sourceProfileDialect.NewSourceProfileConnectionMySQL(map[string]string{"host": "a", "user": "b", "dbName": "*", "password": "e", "multiDb": "merged"}, &g)
+	assert.Nil(t, err)
+	assert.Equal(t, constants.MULTI_DB_MERGED, mysql.MultiDb)
+
+	_, err = sourceProfileDialect.NewSourceProfileConnectionMySQL(map[string]string{"host": "a", "user": "b", "dbName": "*", "password": "e", "multiDb": "joined"}, &g)
+	assert.EqualError(t, err, "invalid multiDb joined, must be one of separate or merged")
+
+	_, err = sourceProfileDialect.NewSourceProfileConnectionMySQL(map[string]string{"host": "a", "user": "b", "dbName": "*", "password": "e", "streamingCfg": "cfg.json"}, &g)
+	assert.NotNil(t, err)
+
+	pg, err := sourceProfileDialect.NewSourceProfileConnectionPostgreSQL(map[string]string{"host": "a", "user": "b", "dbName": "c", "password": "e", "schemas": "sales;hr_*"}, &g)
+	assert.Nil(t, err)
+	assert.Equal(t, "c", pg.Db)
+	assert.Equal(t, []string{"sales", "hr_*"}, pg.Schemas)
+
+	pg, err = sourceProfileDialect.NewSourceProfileConnectionPostgreSQL(map[string]string{"host": "a", "user": "b", "dbName": "app_*", "password": "e"}, &g)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"app_*"}, pg.DbNames)
+
+	_, err = sourceProfileDialect.NewSourceProfileConnectionPostgreSQL(map[string]string{"host": "a", "user": "b", "dbName": "app_*", "password": "e", "multiDb": "merged"}, &g)
+	assert.NotNil(t, err)
+
+	src := SourceProfile{Ty: SourceProfileTypeConnection, Conn: SourceProfileConnection{Ty: SourceProfileConnectionTypePostgreSQL, Pg: pg}}
+	assert.True(t, src.IsSeparateMultiDatabase())
+	src.Conn.Pg.DbNames = nil
+	assert.False(t, src.IsSeparateMultiDatabase())
+}
+
+func TestNewSourceProfileConnectionSQLReplica(t *testing.T) {
+	sourceProfileDialect := SourceProfileDialectImpl{}
+	g := GetUtilInfoMock{}
+	setGetInfoMockValues(&g)
+
+	mysql, err := sourceProfileDialect.NewSourceProfileConnectionMySQL(map[string]string{"host": "a", "user": "b", "dbName": "c", "password": "e", "replicaHost": "r"}, &g)
+	assert.Nil(t, err)
+	assert.Equal(t, SourceProfileReplica{Host: "r", Port: "3306", SyncTimeout: 5 * time.Minute}, mysql.Replica)
+
+	pg, err := sourceProfileDialect.NewSourceProfileConnectionPostgreSQL(map[string]string{"host": "a", "user": "b", "dbName": "c", "password": "e", "replicaHost": "r", "replicaPort": "6432", "replicaConsistency": "primary", "replicaSyncTimeout": "30"}, &g)
+	assert.Nil(t, err)
+	assert.Equal(t, SourceProfileReplica{Host: "r", Port: "6432", WaitForPrimary: true, SyncTimeout: 30 * time.Second}, pg.Replica)
+
+	src := SourceProfile{Ty: SourceProfileTypeConnection, Conn: SourceProfileConnection{Ty: SourceProfileConnectionTypePostgreSQL, Pg: pg}}
+	replicaSrc, ok := src.ReplicaProfile()
+	assert.True(t, ok)
+	assert.Equal(t, "r", replicaSrc.Conn.Pg.Host)
+	assert.Equal(t, "6432", replicaSrc.Conn.Pg.Port)
+	assert.Equal(t, "a", src.Conn.Pg.Host)
+
+	errorCases := []map[string]string{
+		{"host": "a", "user": "b", "dbName": "c", "password": "e", "replicaPort": "3307"},
+		{"host": "a", "user": "b", "dbName": "c", "password": "e", "replicaHost": ""},
+		{"host": "a", "user": "b", "dbName": "c", "password": "e", "replicaHost": "r", "replicaConsistency": "gtid"},
+		{"host": "a", "user": "b", "dbName": "c", "password": "e", "replicaHost": "r", "replicaSyncTimeout": "-1"},
+		{"host": "a", "user": "b", "dbName": "c", "password": "e", "replicaHost": "r", "streamingCfg": "cfg.json"},
+	}
+	for _, params := range errorCases {
+		_, err = sourceProfileDialect.NewSourceProfileConnectionMySQL(params, &g)
+		assert.NotNil(t, err, params)
+	}
+}
+
+func TestNewSourceProfileConnectionSQLConsistentSnapshot(t *testing.T) {
+	sourceProfileDialect := SourceProfileDialectImpl{}
+	g := GetUtilInfoMock{}
+	setGetInfoMockValues(&g)
+
+	mysql, err := sourceProfileDialect.NewSourceProfileConnectionMySQL(map[string]string{"host": "a", "user": "b", "dbName": "c", "password": "e", "consistentSnapshot": "true"}, &g)
+	assert.Nil(t, err)
+	assert.True(t, mysql.ConsistentSnapshot)
+
+	pg, err := sourceProfileDialect.NewSourceProfileConnectionPostgreSQL(map[string]string{"host": "a", "user": "b", "dbName": "c", "password": "e", "consistentSnapshot": "no"}, &g)
+	assert.Nil(t, err)
+	assert.False(t, pg.ConsistentSnapshot)
+
+	_, err = sourceProfileDialect.NewSourceProfileConnectionMySQL(map[string]string{"host": "a", "user": "b", "dbName": "c", "password": "e", "consistentSnapshot": "maybe"}, &g)
+	assert.NotNil(t, err)
+	_, err = sourceProfileDialect.NewSourceProfileConnectionPostgreSQL(map[string]string{"host": "a", "user": "b", "dbName": "c", "password": "e", "consistentSnapshot": "true", "streamingCfg": "cfg.json"}, &g)
+	assert.EqualError(t, err, "consistentSnapshot is only supported for bulk data migrations")
+}
+
+func TestNewSourceProfileConnectionSQLPool(t *testing.T) {
+	sourceProfileDialect := SourceProfileDialectImpl{}
+	g := GetUtilInfoMock{}
+	setGetInfoMockValues(&g)
+
+	mysql, err := sourceProfileDialect.NewSourceProfileConnectionMySQL(map[string]string{"host": "a", "user": "b", "dbName": "c", "password": "e", "maxOpenConns": "20", "maxIdleConns": "5", "statementTimeout": "600", "keepalive": "30"}, &g)
+	assert.Nil(t, err)
+	assert.Equal(t, SourceProfilePool{MaxOpenConns: 20, MaxIdleConns: 5, StatementTimeout: 10 * time.Minute, Keepalive: 30 * time.Second}, mysql.Pool)
+	src := SourceProfile{Ty: SourceProfileTypeConnection, Conn: SourceProfileConnection{Ty: SourceProfileConnectionTypeMySQL, Mysql: mysql}}
+	assert.Equal(t, mysql.Pool, src.ConnectionPool())
+
+	pg, err := sourceProfileDialect.NewSourceProfileConnectionPostgreSQL(map[string]string{"host": "a", "user": "b", "dbName": "c", "password": "e", "maxIdleConns": "5"}, &g)
+	assert.Nil(t, err)
+	assert.Equal(t, SourceProfilePool{MaxIdleConns: 5}, pg.Pool)
+
+	errorCases := []map[string]string{
+		{"host": "a", "user": "b", "dbName": "c", "password": "e", "maxOpenConns": "0"},
+		{"host": "a", "user": "b", "dbName": "c", "password": "e", "statementTimeout": "1m"},
+		{"host": "a", "user": "b", "dbName": "c", "password": "e", "maxOpenConns": "5", "maxIdleConns": "10"},
+	}
+	for _, params := range errorCases {
+		_, err = sourceProfileDialect.NewSourceProfileConnectionPostgreSQL(params, &g)
+		assert.NotNil(t, err, params)
+	}
+}
+
+func TestNewSourceProfileConnectionSQLColumnStatsSample(t *testing.T) {
+	sourceProfileDialect := SourceProfileDialectImpl{}
+	g := GetUtilInfoMock{}
+	setGetInfoMockValues(&g)
+
+	mysql, err := sourceProfileDialect.NewSourceProfileConnectionMySQL(map[string]string{"host": "a", "user": "b", "dbName": "c", "password": "e", "columnStatsSample": "1000"}, &g)
+	assert.Nil(t, err)
+	assert.Equal(t, 1000, mysql.ColumnStatsSample)
+	src := SourceProfile{Ty: SourceProfileTypeConnection, Conn: SourceProfileConnection{Ty: SourceProfileConnectionTypeMySQL, Mysql: mysql}}
+	assert.Equal(t, 1000, src.ColumnStatsSample())
+
+	pg, err := sourceProfileDialect.NewSourceProfileConnectionPostgreSQL(map[string]string{"host": "a", "user": "b", "dbName": "c", "password": "e"}, &g)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, pg.ColumnStatsSample)
+
+	_, err = sourceProfileDialect.NewSourceProfileConnectionPostgreSQL(map[string]string{"host": "a", "user": "b", "dbName": "c", "password": "e", "columnStatsSample": "all"}, &g)
+	assert.EqualError(t, err, "columnStatsSample must be a positive number of rows, found all")
+}
+
 func TestNewSourceProfileConnectionDynamoDB(t *testing.T) {
 	// Avoid getting/settinng env variables in the unit tests.
 	testCases := []struct {
@@ -674,6 +826,88 @@ func TestNewSourceProfileConnectionCassandra(t *testing.T) {
 	}
 }
 
+func TestNewSourceProfileConnectionMongoDB(t *testing.T) {
+	testCases := []struct {
+		name          string
+		params        map[string]string
+		want          SourceProfileConnectionMongoDB
+		errorExpected bool
+	}{
+		{
+			name:   "export directory provided",
+			params: map[string]string{"dir": "/tmp/export"},
+			want:   SourceProfileConnectionMongoDB{ExportDir: "/tmp/export"},
+		},
+		{
+			name:   "schema sample size provided",
+			params: map[string]string{"dir": "/tmp/export", "schema-sample-size": "500"},
+			want:   SourceProfileConnectionMongoDB{ExportDir: "/tmp/export", SchemaSampleSize: 500},
+		},
+		{
+			name:          "export directory not specified",
+			params:        map[string]string{"schema-sample-size": "500"},
+			errorExpected: true,
+		},
+		{
+			name:          "invalid schema sample size",
+			params:        map[string]string{"dir": "/tmp/export", "schema-sample-size": "0"},
+			errorExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		sourceProfileDialect := SourceProfileDialectImpl{}
+		g := GetUtilInfoMock{}
+		setGetInfoMockValues(&g)
+		got, err := sourceProfileDialect.NewSourceProfileConnectionMongoDB(tc.params, &g)
+		assert.Equal(t, tc.errorExpected, err != nil, tc.name)
+		if !tc.errorExpected {
+			assert.Equal(t, tc.want, got, tc.name)
+		}
+	}
+}
+
+func TestNewSourceProfileConnectionDB2(t *testing.T) {
+	testCases := []struct {
+		name          string
+		params        map[string]string
+		want          SourceProfileConnectionDB2
+		errorExpected bool
+	}{
+		{
+			name:   "mandatory params provided",
+			params: map[string]string{"host": "a", "user": "db2inst1", "dbName": "c"},
+			want:   SourceProfileConnectionDB2{Host: "a", Port: "50000", User: "db2inst1", Db: "c", Pwd: "password", Schema: "DB2INST1"},
+		},
+		{
+			name:   "all params provided",
+			params: map[string]string{"host": "a", "user": "b", "dbName": "c", "port": "d", "password": "e", "schema": "SALES"},
+			want:   SourceProfileConnectionDB2{Host: "a", Port: "d", User: "b", Db: "c", Pwd: "e", Schema: "SALES"},
+		},
+		{
+			name:          "host is blank",
+			params:        map[string]string{"host": "", "user": "b", "dbName": "c"},
+			errorExpected: true,
+		},
+		{
+			name:          "dbName is not specified",
+			params:        map[string]string{"host": "a", "user": "b"},
+			errorExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		sourceProfileDialect := SourceProfileDialectImpl{}
+		g := GetUtilInfoMock{}
+		setGetInfoMockValues(&g)
+		got, err := sourceProfileDialect.NewSourceProfileConnectionDB2(tc.params, &g)
+		assert.Equal(t, tc.errorExpected, err != nil, tc.name)
+		if !tc.errorExpected {
+			assert.Equal(t, tc.want, got, tc.name)
+		}
+	}
+}
+
 // code for testing cloud sql mysql connection
 func TestNewSourceProfileConnectionCloudSQLMySQL(t *testing.T) {
 	// Avoid getting/setting env variables in the unit tests.
@@ -1210,6 +1444,15 @@ func TestNewSourceProfile(t *testing.T) {
 			returnTy:      SourceProfileTypeFile,
 			errorExpected: false,
 		},
+		{
+			name:          "unsupported charset for file",
+			params:        "file='file.txt',charset='ebcdic'",
+			source:        "file",
+			function:      "NewSourceProfileFile",
+			mockReturn:    SourceProfileFile{Charset: "ebcdic"},
+			returnTy:      SourceProfileTypeFile,
+			errorExpected: true,
+		},
 		{
 			name:          "invalid source profile for file",
 			params:        "format='some-format'",
@@ -1438,3 +1681,27 @@ func TestNewSourceProfileConnectionCloudSQLPostgreSQL_SecretManager_ImplicitLate
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestResolveSecrets(t *testing.T) {
+	origNewClient := secretmanagerclient.NewSecretManagerClient
+	defer func() { secretmanagerclient.NewSecretManagerClient = origNewClient }()
+
+	mockClient := new(MockSecretManagerClient)
+	mockClient.On("AccessSecretVersion", mock.Anything, mock.MatchedBy(func(req *secretmanagerpb.AccessSecretVersionRequest) bool {
+		return req.Name == "projects/p/secrets/s/versions/latest"
+	}), mock.Anything).Return(&secretmanagerpb.AccessSecretVersionResponse{
+		Payload: &secretmanagerpb.SecretPayload{
+			Data: []byte("secret-password"),
+		},
+	}, nil)
+	secretmanagerclient.NewSecretManagerClient = func(ctx context.Context) (secretmanagerclient.SecretManagerClient, error) {
+		return mockClient, nil
+	}
+
+	params := map[string]string{"host": "a", "user": "b", "password": "sm://projects/p/secrets/s"}
+	assert.Nil(t, resolveSecrets(params))
+	assert.Equal(t, map[string]string{"host": "a", "user": "b", "password": "secret-password"}, params)
+	mockClient.AssertExpectations(t)
+
+	assert.NotNil(t, resolveSecrets(map[string]string{"password": "sm://secrets/s"}))
+}
@@ -52,6 +52,9 @@ type TargetProfileConnectionSpanner struct {
 	Dbname   string
 	Dialect  string
 	DefaultTimezone string
+	// ExistingTables is the mode of migrating tables which already exist in
+	// the database.
+	ExistingTables string
 }
 
 type TargetProfileConnection struct {
@@ -63,6 +66,26 @@ type TargetProfile struct {
 	Ty   TargetProfileType
 	Conn TargetProfileConnection
 	DefaultIdentityOptions DefaultIdentityOptions
+	SyntheticPKeyStrategy string
+	NameTemplates NameTemplates
+	UnsignedIntPolicy string
+	TimezonePolicy string
+	NumericPrecisionPolicy string
+	CollationShadowColumns bool
+	DropForeignKeys bool
+	DropSecondaryIndexes bool
+	InterleavedBatches bool
+}
+
+// NameTemplates holds the templates of the names of the objects generated by
+// the migration, from the syntheticPKeyName, shardIdColumnName,
+// indexNameTemplate and sequenceNameTemplate params. Their placeholders are
+// checked before the migration starts, see internal.NameTemplates.
+type NameTemplates struct {
+	SyntheticPKey string
+	ShardIdColumn string
+	Index         string
+	Sequence      string
 }
 
 type DefaultIdentityOptions struct {
@@ -203,6 +226,11 @@ func NewTargetProfile(s string, isDryRun bool) (TargetProfile, error) {
 		sp.DefaultTimezone = defaultTimezone
 	}
 
+	sp.ExistingTables = strings.ToLower(params["existingTables"])
+	if !isOneOf(sp.ExistingTables, constants.EXISTING_TABLES_FAIL, constants.EXISTING_TABLES_SKIP, constants.EXISTING_TABLES_MERGE) {
+		return TargetProfile{}, fmt.Errorf("invalid value for existingTables: %s, expected one of %s, %s or %s", params["existingTables"], constants.EXISTING_TABLES_FAIL, constants.EXISTING_TABLES_SKIP, constants.EXISTING_TABLES_MERGE)
+	}
+
 	if sp.Dialect == "" && isDryRun {
 		sp.Dialect = constants.DIALECT_GOOGLESQL
 	}
@@ -216,6 +244,63 @@ func NewTargetProfile(s string, isDryRun bool) (TargetProfile, error) {
 		return TargetProfile{}, err
 	}
 
+	syntheticPKeyStrategy := params["syntheticPKeyStrategy"]
+	if !isOneOf(syntheticPKeyStrategy, constants.SYNTH_PK_STRING_SEQUENCE, constants.SYNTH_PK_UUID, constants.SYNTH_PK_BIT_REVERSED_SEQUENCE) {
+		return TargetProfile{}, fmt.Errorf("invalid value for syntheticPKeyStrategy: %s, expected one of %s, %s or %s", syntheticPKeyStrategy, constants.SYNTH_PK_STRING_SEQUENCE, constants.SYNTH_PK_UUID, constants.SYNTH_PK_BIT_REVERSED_SEQUENCE)
+	}
+
+	nameTemplates := NameTemplates{
+		SyntheticPKey: params["syntheticPKeyName"],
+		ShardIdColumn: params["shardIdColumnName"],
+		Index:         params["indexNameTemplate"],
+		Sequence:      params["sequenceNameTemplate"],
+	}
+
+	unsignedIntPolicy := strings.ToLower(params["unsignedIntPolicy"])
+	if !isOneOf(unsignedIntPolicy, constants.UNSIGNED_INT_INT64, constants.UNSIGNED_INT_NUMERIC, constants.UNSIGNED_INT_STRING) {
+		return TargetProfile{}, fmt.Errorf("invalid value for unsignedIntPolicy: %s, expected one of %s, %s or %s", params["unsignedIntPolicy"], constants.UNSIGNED_INT_INT64, constants.UNSIGNED_INT_NUMERIC, constants.UNSIGNED_INT_STRING)
+	}
+
+	timezonePolicy := strings.ToLower(params["timezonePolicy"])
+	if !isOneOf(timezonePolicy, constants.TIMEZONE_POLICY_UTC, constants.TIMEZONE_POLICY_SOURCE) {
+		return TargetProfile{}, fmt.Errorf("invalid value for timezonePolicy: %s, expected one of %s or %s", params["timezonePolicy"], constants.TIMEZONE_POLICY_UTC, constants.TIMEZONE_POLICY_SOURCE)
+	}
+
+	numericPrecisionPolicy := strings.ToLower(params["numericPrecisionPolicy"])
+	if !isOneOf(numericPrecisionPolicy, constants.NUMERIC_PRECISION_ROUND, constants.NUMERIC_PRECISION_FAIL) {
+		return TargetProfile{}, fmt.Errorf("invalid value for numericPrecisionPolicy: %s, expected one of %s or %s", params["numericPrecisionPolicy"], constants.NUMERIC_PRECISION_ROUND, constants.NUMERIC_PRECISION_FAIL)
+	}
+
+	var collationShadowColumns bool
+	if v, ok := params["collationShadowColumns"]; ok {
+		collationShadowColumns, err = strconv.ParseBool(v)
+		if err != nil {
+			return TargetProfile{}, fmt.Errorf("invalid value for collationShadowColumns: %s, expected true or false", v)
+		}
+	}
+
+	var dropForeignKeys, dropSecondaryIndexes bool
+	if v, ok := params["dropForeignKeys"]; ok {
+		dropForeignKeys, err = strconv.ParseBool(v)
+		if err != nil {
+			return TargetProfile{}, fmt.Errorf("invalid value for dropForeignKeys: %s, expected true or false", v)
+		}
+	}
+	if v, ok := params["dropSecondaryIndexes"]; ok {
+		dropSecondaryIndexes, err = strconv.ParseBool(v)
+		if err != nil {
+			return TargetProfile{}, fmt.Errorf("invalid value for dropSecondaryIndexes: %s, expected true or false", v)
+		}
+	}
+
+	var interleavedBatches bool
+	if v, ok := params["interleavedBatches"]; ok {
+		interleavedBatches, err = strconv.ParseBool(v)
+		if err != nil {
+			return TargetProfile{}, fmt.Errorf("invalid value for interleavedBatches: %s, expected true or false", v)
+		}
+	}
+
 	// if target-profile is not empty, it must contain spanner instance
 	if s != "" && sp.Instance == "" {
 		return TargetProfile{}, fmt.Errorf("found empty string for instance. please specify instance (spanner instance) in the target-profile")
@@ -229,7 +314,20 @@ func NewTargetProfile(s string, isDryRun bool) (TargetProfile, error) {
 	}
 
 	conn := TargetProfileConnection{Ty: TargetProfileConnectionTypeSpanner, Sp: sp}
-	return TargetProfile{Ty: TargetProfileTypeConnection, Conn: conn, DefaultIdentityOptions: defaultIdentityOptions}, nil
+	return TargetProfile{Ty: TargetProfileTypeConnection, Conn: conn, DefaultIdentityOptions: defaultIdentityOptions, SyntheticPKeyStrategy: syntheticPKeyStrategy, NameTemplates: nameTemplates, UnsignedIntPolicy: unsignedIntPolicy, TimezonePolicy: timezonePolicy, NumericPrecisionPolicy: numericPrecisionPolicy, CollationShadowColumns: collationShadowColumns, DropForeignKeys: dropForeignKeys, DropSecondaryIndexes: dropSecondaryIndexes, InterleavedBatches: interleavedBatches}, nil
+}
+
+// isOneOf returns true if value is empty, for the default, or one of values.
+func isOneOf(value string, values ...string) bool {
+	if value == "" {
+		return true
+	}
+	for _, v := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
 }
 
 func extractDefaultIdentityOptions(params map[string]string) (DefaultIdentityOptions, error) {
@@ -29,6 +29,15 @@ func TestNewTargetProfile(t *testing.T) {
 		targetProfileString          string
 		expectedTargetProfileDetails TargetProfileConnectionSpanner
 		expectedDefaultIdentityOptions DefaultIdentityOptions
+		expectedSyntheticPKeyStrategy string
+		expectedNameTemplates        NameTemplates
+		expectedUnsignedIntPolicy    string
+		expectedTimezonePolicy       string
+		expectedNumericPrecisionPolicy string
+		expectedCollationShadowColumns bool
+		expectedDropForeignKeys      bool
+		expectedDropSecondaryIndexes bool
+		expectedInterleavedBatches   bool
 		expectedErr                  bool
 	}{
 		{
@@ -97,6 +106,111 @@ func TestNewTargetProfile(t *testing.T) {
 			targetProfileString: "instance=test-instance,defaultIdentityStartCounterWith=",
 			expectedErr: true,
 		},
+		{
+			targetProfileString: "instance=test-instance,syntheticPKeyStrategy=uuid",
+			expectedTargetProfileDetails: TargetProfileConnectionSpanner{
+				Instance: "test-instance",
+			},
+			expectedSyntheticPKeyStrategy: "uuid",
+			expectedErr: false,
+		},
+		{
+			targetProfileString: "instance=test-instance,syntheticPKeyStrategy=composite",
+			expectedErr: true,
+		},
+		{
+			targetProfileString: "instance=test-instance,syntheticPKeyName=row_id,indexNameTemplate=idx_{table}_{cols}",
+			expectedTargetProfileDetails: TargetProfileConnectionSpanner{
+				Instance: "test-instance",
+			},
+			expectedNameTemplates: NameTemplates{SyntheticPKey: "row_id", Index: "idx_{table}_{cols}"},
+			expectedErr: false,
+		},
+		{
+			targetProfileString: "instance=test-instance,unsignedIntPolicy=NUMERIC",
+			expectedTargetProfileDetails: TargetProfileConnectionSpanner{
+				Instance: "test-instance",
+			},
+			expectedUnsignedIntPolicy: "numeric",
+			expectedErr: false,
+		},
+		{
+			targetProfileString: "instance=test-instance,unsignedIntPolicy=uint64",
+			expectedErr: true,
+		},
+		{
+			targetProfileString: "instance=test-instance,timezonePolicy=Source",
+			expectedTargetProfileDetails: TargetProfileConnectionSpanner{
+				Instance: "test-instance",
+			},
+			expectedTimezonePolicy: "source",
+			expectedErr: false,
+		},
+		{
+			targetProfileString: "instance=test-instance,timezonePolicy=local",
+			expectedErr: true,
+		},
+		{
+			targetProfileString: "instance=test-instance,numericPrecisionPolicy=Fail",
+			expectedTargetProfileDetails: TargetProfileConnectionSpanner{
+				Instance: "test-instance",
+			},
+			expectedNumericPrecisionPolicy: "fail",
+			expectedErr: false,
+		},
+		{
+			targetProfileString: "instance=test-instance,numericPrecisionPolicy=truncate",
+			expectedErr: true,
+		},
+		{
+			targetProfileString: "instance=test-instance,existingTables=Merge-With-Diff",
+			expectedTargetProfileDetails: TargetProfileConnectionSpanner{
+				Instance:       "test-instance",
+				ExistingTables: "merge-with-diff",
+			},
+			expectedErr: false,
+		},
+		{
+			targetProfileString: "instance=test-instance,existingTables=overwrite",
+			expectedErr: true,
+		},
+		{
+			targetProfileString: "instance=test-instance,collationShadowColumns=true",
+			expectedTargetProfileDetails: TargetProfileConnectionSpanner{
+				Instance: "test-instance",
+			},
+			expectedCollationShadowColumns: true,
+			expectedErr: false,
+		},
+		{
+			targetProfileString: "instance=test-instance,collationShadowColumns=yes",
+			expectedErr: true,
+		},
+		{
+			targetProfileString: "instance=test-instance,dropForeignKeys=true,dropSecondaryIndexes=true",
+			expectedTargetProfileDetails: TargetProfileConnectionSpanner{
+				Instance: "test-instance",
+			},
+			expectedDropForeignKeys: true,
+			expectedDropSecondaryIndexes: true,
+			expectedErr: false,
+		},
+		{
+			targetProfileString: "instance=test-instance,dropSecondaryIndexes=all",
+			expectedErr: true,
+		},
+		{
+			targetProfileString: "instance=test-instance,interleavedBatches=true",
+			expectedTargetProfileDetails: TargetProfileConnectionSpanner{
+				Instance: "test-instance",
+			},
+			expectedInterleavedBatches: true,
+			expectedErr: false,
+		},
+		{
+			targetProfileString: "instance=test-instance,interleavedBatches=parent",
+			expectedErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -112,6 +226,15 @@ func TestNewTargetProfile(t *testing.T) {
 					Sp: tc.expectedTargetProfileDetails,
 				},
 				DefaultIdentityOptions: tc.expectedDefaultIdentityOptions,
+				SyntheticPKeyStrategy: tc.expectedSyntheticPKeyStrategy,
+				NameTemplates: tc.expectedNameTemplates,
+				UnsignedIntPolicy: tc.expectedUnsignedIntPolicy,
+				TimezonePolicy: tc.expectedTimezonePolicy,
+				NumericPrecisionPolicy: tc.expectedNumericPrecisionPolicy,
+				CollationShadowColumns: tc.expectedCollationShadowColumns,
+				DropForeignKeys: tc.expectedDropForeignKeys,
+				DropSecondaryIndexes: tc.expectedDropSecondaryIndexes,
+				InterleavedBatches: tc.expectedInterleavedBatches,
 			}
 
 			assert.Equal(t, expectedTargetProfile, actual)
@@ -45,6 +45,8 @@ type Table struct {
 	CheckConstraints []CheckConstraint
 	Indexes          []Index
 	Id               string
+	Comment          string       // Table comment defined in the source database, if any.
+	Partitioning     Partitioning // Partitioning scheme defined in the source database, if any.
 }
 
 // Column represents a database column.
@@ -58,8 +60,35 @@ type Column struct {
 	AutoGen         ddl.AutoGenCol
 	DefaultValue    ddl.DefaultValue
 	GeneratedColumn ddl.GeneratedColumn
+	Comment         string   // Column comment defined in the source database, if any.
+	UserType        UserType // User-defined type the column is declared with, if it was expanded for conversion.
+	Collation       Collation
 }
 
+// Collation describes the collation of a string column in the source
+// database. Spanner compares STRING values byte by byte, so columns with
+// other collations compare and sort differently once migrated.
+type Collation struct {
+	Name            string // Empty if the column uses the default collation of the database.
+	Binary          bool   // Values are compared byte by byte.
+	CaseInsensitive bool   // Values differing only by case (or accents) compare equal.
+}
+
+// UserType describes the user-defined type of a column, e.g. a PostgreSQL
+// domain or composite type. Domains are expanded to their base type (and
+// their constraints to check constraints of the table), and composite types
+// to the "record" type.
+type UserType struct {
+	Name   string
+	Kind   string   // UserTypeDomain or UserTypeComposite.
+	Fields []string // Field names of a composite type.
+}
+
+const (
+	UserTypeDomain    = "domain"
+	UserTypeComposite = "composite"
+)
+
 // ForeignKey represents a foreign key.
 // Note that the fields onDelete and onUpdate describe actions
 // for when keys are deleted or updated. Different source databases
@@ -89,9 +118,10 @@ type CheckConstraint struct {
 
 // Key respresents a primary key or index key.
 type Key struct {
-	ColId string
-	Desc  bool // By default, order is ASC. Set to true to specifiy DESC.
-	Order int
+	ColId      string
+	Desc       bool // By default, order is ASC. Set to true to specifiy DESC.
+	Order      int
+	NullsOrder string // ddl.NullsFirst or ddl.NullsLast if the source records the null ordering of the key.
 }
 
 // Index represents a database index.
@@ -113,6 +143,17 @@ type Index struct {
 	StoredColumnIds []string
 }
 
+// Partitioning represents the partitioning scheme of a source table e.g.
+// MySQL's PARTITION BY RANGE (YEAR(created_at)). Spanner has no equivalent
+// of user defined partitions, so this is only used to report the
+// partitioning intent (e.g. expiring old data) and suggest alternatives.
+type Partitioning struct {
+	Method      string   // Partitioning method e.g. RANGE, RANGE COLUMNS, LIST, HASH or KEY. Empty if the table isn't partitioned.
+	Expression  string   // Partitioning expression or column list.
+	ColIds      []string // Columns referenced by the partitioning expression.
+	ColumnNames []string `json:"-"`
+}
+
 // Type represents the type of a column.
 type Type struct {
 	Name        string
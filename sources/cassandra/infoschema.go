@@ -20,6 +20,7 @@ import (
 
 	sp "cloud.google.com/go/spanner"
 	cc "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients/cassandra"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
@@ -37,9 +38,9 @@ type InfoSchemaImpl struct {
 
 // GetToDdl implements the common.InfoSchema interface
 func (isi InfoSchemaImpl) GetToDdl() common.ToDdl {
-	return ToDdlImpl{
+	return common.LookupToDdl(constants.CASSANDRA, ToDdlImpl{
 		typeMapper: NewCassandraTypeMapper(),
-	}
+	})
 }
 
 // GetTableName returns table name
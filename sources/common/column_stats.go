@@ -0,0 +1,63 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// CollectColumnStats computes the statistics of the columns colIds of the
+// source table tableId from rows, a sample of the values of these columns,
+// and records them in conv. Values are compared as numbers for the columns
+// mapped to numeric Spanner types.
+func CollectColumnStats(conv *internal.Conv, tableId string, colIds []string, rows *sql.Rows) error {
+	builders := make([]*internal.ColumnStatsBuilder, len(colIds))
+	for i, colId := range colIds {
+		switch conv.SpSchema[tableId].ColDefs[colId].T.Name {
+		case ddl.Int64, ddl.Float32, ddl.Float64, ddl.Numeric:
+			builders[i] = internal.NewColumnStatsBuilder(true)
+		default:
+			builders[i] = internal.NewColumnStatsBuilder(false)
+		}
+	}
+	values := make([]sql.NullString, len(colIds))
+	dest := make([]interface{}, len(colIds))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return fmt.Errorf("can't scan the sampled rows of %s: %v", conv.SrcSchema[tableId].Name, err)
+		}
+		for i, v := range values {
+			if v.Valid {
+				builders[i].Add(&v.String)
+			} else {
+				builders[i].Add(nil)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("can't read the sampled rows of %s: %v", conv.SrcSchema[tableId].Name, err)
+	}
+	for i, colId := range colIds {
+		conv.SetColumnStats(tableId, colId, builders[i].Stats())
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectColumnStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+		AddRow(int64(10), "bob").
+		AddRow(int64(2), nil).
+		AddRow(int64(300), "alice"))
+	rows, err := db.Query("SELECT id, name FROM t LIMIT 3")
+	assert.Nil(t, err)
+
+	conv := internal.MakeConv()
+	conv.SpSchema["t1"] = ddl.CreateTable{
+		Name:   "t",
+		ColIds: []string{"c1", "c2"},
+		ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Name: "id", T: ddl.Type{Name: ddl.Int64}},
+			"c2": {Name: "name", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+		},
+	}
+	assert.Nil(t, CollectColumnStats(conv, "t1", []string{"c1", "c2"}, rows))
+	assert.Equal(t, internal.ColumnStats{SampledRows: 3, Min: "2", Max: "300", LengthP50: 2, LengthP95: 2, MaxLength: 3, MaxIntegerDigits: 3}, conv.ColumnStats["t1"]["c1"])
+	assert.Equal(t, internal.ColumnStats{SampledRows: 3, NullCount: 1, Min: "alice", Max: "bob", LengthP50: 3, LengthP95: 3, MaxLength: 5}, conv.ColumnStats["t1"]["c2"])
+}
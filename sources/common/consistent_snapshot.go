@@ -0,0 +1,59 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ConsistentSnapshot is a read only transaction, held open on a single source
+// connection, that all table readers of a data migration share so that every
+// table is read as of the same point in time.
+type ConsistentSnapshot struct {
+	Conn *sql.Conn
+	// Position is the GTID set (MySQL) or WAL LSN (PostgreSQL) of the source
+	// captured just before the snapshot was taken. Change data capture started
+	// from Position replays every change not contained in the snapshot.
+	Position string
+	// SnapshotId is the id of the PostgreSQL exported snapshot, which other
+	// sessions can import with SET TRANSACTION SNAPSHOT.
+	SnapshotId string
+}
+
+// Query runs q within the snapshot, or directly on db if there is no snapshot.
+func (s *ConsistentSnapshot) Query(db *sql.DB, q string, args ...interface{}) (*sql.Rows, error) {
+	return s.QueryContext(context.Background(), db, q, args...)
+}
+
+// QueryContext is like Query, the rows being read until ctx is canceled.
+func (s *ConsistentSnapshot) QueryContext(ctx context.Context, db *sql.DB, q string, args ...interface{}) (*sql.Rows, error) {
+	if s == nil {
+		return db.QueryContext(ctx, q, args...)
+	}
+	return s.Conn.QueryContext(ctx, q, args...)
+}
+
+// Release ends the snapshot transaction and returns its connection to the pool.
+func (s *ConsistentSnapshot) Release() error {
+	if s == nil {
+		return nil
+	}
+	_, err := s.Conn.ExecContext(context.Background(), "ROLLBACK")
+	if closeErr := s.Conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -49,9 +49,11 @@ type InfoSchema interface {
 
 // SchemaAndName contains the schema and name for a table
 type SchemaAndName struct {
-	Schema string
-	Name   string
-	Id     string
+	Schema       string
+	Name         string
+	Id           string
+	Comment      string              // Source table comment, if any.
+	Partitioning schema.Partitioning // Source table partitioning scheme, if any.
 }
 
 // FkConstraint contains foreign key constraints
@@ -108,6 +110,7 @@ func (is *InfoSchemaImpl) GenerateSrcSchema(conv *internal.Conv, infoSchema Info
 	if err != nil {
 		return 0, err
 	}
+	tables = filterTables(conv, infoSchema, tables)
 
 	if numWorkers < 1 {
 		numWorkers = DefaultWorkers
@@ -137,14 +140,26 @@ func (is *InfoSchemaImpl) GenerateSrcSchema(conv *internal.Conv, infoSchema Info
 // 'db'. For each table, we extract and convert the data to Spanner data
 // (based on the source and Spanner schemas), and write it to Spanner.
 // If we can't get/process data for a table, we skip that table and process
-// the remaining tables.
+// the remaining tables. Tables excluded by conv.TableFilter are skipped too.
+// Once the migration is interrupted, the remaining tables are skipped; the
+// tables read entirely are recorded for its checkpoint.
 func (is *InfoSchemaImpl) ProcessData(conv *internal.Conv, infoSchema InfoSchema, additionalAttributes internal.AdditionalDataAttributes) {
 	// Tables are ordered in alphabetical order with one exception: interleaved
-	// tables appear after the population of their parent table.
-	tableIds := ddl.GetSortedTableIdsBySpName(conv.SpSchema)
+	// tables appear after the population of their parent table. The priority
+	// classes of conv.TableOrder come first.
+	tableIds := conv.OrderTableIds(ddl.GetSortedTableIdsBySpName(conv.SpSchema))
 
 	for _, tableId := range tableIds {
+		if conv.Interrupted() {
+			return
+		}
+		if internal.IsAddedTable(conv, tableId) {
+			continue
+		}
 		srcSchema := conv.SrcSchema[tableId]
+		if conv.SkipTable(srcSchema.Name) {
+			continue
+		}
 		spSchema, ok := conv.SpSchema[tableId]
 		if !ok {
 			conv.Stats.BadRows[srcSchema.Name] += conv.Stats.Rows[srcSchema.Name]
@@ -159,9 +174,7 @@ func (is *InfoSchemaImpl) ProcessData(conv *internal.Conv, infoSchema InfoSchema
 		if err != nil {
 			return
 		}
-		if conv.DataFlush != nil {
-			conv.DataFlush()
-		}
+		conv.CompleteTable(tableId)
 	}
 }
 
@@ -172,7 +185,7 @@ func (is *InfoSchemaImpl) SetRowStats(conv *internal.Conv, infoSchema InfoSchema
 		conv.Unexpected(fmt.Sprintf("Couldn't get list of table: %s", err))
 		return
 	}
-	for _, t := range tables {
+	for _, t := range filterTables(conv, infoSchema, tables) {
 		tableName := infoSchema.GetTableName(t.Schema, t.Name)
 		count, err := infoSchema.GetRowCount(t)
 		if err != nil {
@@ -183,6 +196,22 @@ func (is *InfoSchemaImpl) SetRowStats(conv *internal.Conv, infoSchema InfoSchema
 	}
 }
 
+// filterTables returns the tables which aren't excluded by conv.TableFilter.
+func filterTables(conv *internal.Conv, infoSchema InfoSchema, tables []SchemaAndName) []SchemaAndName {
+	if conv.TableFilter.IsEmpty() {
+		return tables
+	}
+	var filtered []SchemaAndName
+	for _, t := range tables {
+		if conv.SkipTable(infoSchema.GetTableName(t.Schema, t.Name)) {
+			continue
+		}
+		filtered = append(filtered, t)
+	}
+	logger.Log.Info(fmt.Sprintf("%d of %d tables selected by the table filter", len(filtered), len(tables)))
+	return filtered
+}
+
 func (is *InfoSchemaImpl) ProcessTable(conv *internal.Conv, table SchemaAndName, infoSchema InfoSchema) (schema.Table, error) {
 	var t schema.Table
 	logger.Log.Info(fmt.Sprintf("processing schema for table %s", table))
@@ -216,6 +245,12 @@ func (is *InfoSchemaImpl) ProcessTable(conv *internal.Conv, table SchemaAndName,
 	for _, k := range primaryKeys {
 		schemaPKeys = append(schemaPKeys, schema.Key{ColId: colNameIdMap[k]})
 	}
+	partitioning := table.Partitioning
+	for _, colName := range partitioning.ColumnNames {
+		if colId, ok := colNameIdMap[colName]; ok {
+			partitioning.ColIds = append(partitioning.ColIds, colId)
+		}
+	}
 	t = schema.Table{
 		Id:               tblId,
 		Name:             name,
@@ -226,7 +261,9 @@ func (is *InfoSchemaImpl) ProcessTable(conv *internal.Conv, table SchemaAndName,
 		PrimaryKeys:      schemaPKeys,
 		CheckConstraints: checkConstraints,
 		Indexes:          indexes,
-		ForeignKeys:      foreignKeys}
+		ForeignKeys:      foreignKeys,
+		Comment:          table.Comment,
+		Partitioning:     partitioning}
 	return t, nil
 }
 
@@ -235,12 +272,18 @@ func (is *InfoSchemaImpl) ProcessTable(conv *internal.Conv, table SchemaAndName,
 func (is *InfoSchemaImpl) GetIncludedSrcTablesFromConv(conv *internal.Conv) (schemaToTablesMap map[string]internal.SchemaDetails, err error) {
 	schemaToTablesMap = make(map[string]internal.SchemaDetails)
 	for spTable := range conv.SpSchema {
+		if internal.IsAddedTable(conv, spTable) {
+			continue
+		}
 		//lookup the spanner table in the source tables via ID
 		srcTable, ok := conv.SrcSchema[spTable]
 		if !ok {
 			err := fmt.Errorf("id for spanner and source tables do not match, this is most likely a bug")
 			return nil, err
 		}
+		if conv.SkipTable(srcTable.Name) {
+			continue
+		}
 		if _, exists := schemaToTablesMap[srcTable.Schema]; !exists {
 			schemaToTablesMap[srcTable.Schema] = internal.SchemaDetails{
 				TableDetails: []internal.TableDetails{},
@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// TypeConverter converts columns of a custom source type, e.g. a user-defined
+// type, to a Spanner type. Like ToDdl.ToSpannerType, spType is the Spanner
+// type requested for the column, or "" for the default mapping.
+type TypeConverter func(conv *internal.Conv, spType string, srcType schema.Type, isPk bool) (ddl.Type, []internal.SchemaIssue)
+
+var (
+	registryMu     sync.RWMutex
+	toDdlRegistry  = make(map[string]ToDdl)
+	typeConverters = make(map[string]map[string]TypeConverter)
+)
+
+// RegisterToDdl replaces the ToDdl implementation of the source database
+// named source (a driver name, e.g. constants.MYSQL), for both its dump and
+// direct connection paths. It is meant to be called from an init function,
+// so that programs embedding the tool can customize type conversion without
+// modifying it.
+func RegisterToDdl(source string, impl ToDdl) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	toDdlRegistry[source] = impl
+}
+
+// RegisterTypeConverter registers fn to convert the columns of type
+// srcTypeName of the source database named source. Converters take
+// precedence over the ToDdl implementation of the source, and type names are
+// matched case-insensitively.
+func RegisterTypeConverter(source, srcTypeName string, fn TypeConverter) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if typeConverters[source] == nil {
+		typeConverters[source] = make(map[string]TypeConverter)
+	}
+	typeConverters[source][strings.ToLower(srcTypeName)] = fn
+}
+
+// LookupToDdl returns the ToDdl implementation to use for the source
+// database named source: the registered implementation if any, builtin
+// otherwise, extended with the type converters registered for source.
+func LookupToDdl(source string, builtin ToDdl) ToDdl {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	toddl := builtin
+	if impl, ok := toDdlRegistry[source]; ok {
+		toddl = impl
+	}
+	if len(typeConverters[source]) == 0 {
+		return toddl
+	}
+	converters := make(map[string]TypeConverter)
+	for name, fn := range typeConverters[source] {
+		converters[name] = fn
+	}
+	p := pluginToDdl{ToDdl: toddl, converters: converters}
+	// Keep the type options of sources like Cassandra.
+	if op, ok := toddl.(OptionProvider); ok {
+		return pluginOptionToDdl{pluginToDdl: p, OptionProvider: op}
+	}
+	return p
+}
+
+// pluginToDdl extends a ToDdl implementation with registered type
+// converters.
+type pluginToDdl struct {
+	ToDdl
+	converters map[string]TypeConverter
+}
+
+func (p pluginToDdl) ToSpannerType(conv *internal.Conv, spType string, srcType schema.Type, isPk bool) (ddl.Type, []internal.SchemaIssue) {
+	if fn, ok := p.converters[strings.ToLower(srcType.Name)]; ok {
+		return fn(conv, spType, srcType, isPk)
+	}
+	return p.ToDdl.ToSpannerType(conv, spType, srcType, isPk)
+}
+
+type pluginOptionToDdl struct {
+	pluginToDdl
+	OptionProvider
+}
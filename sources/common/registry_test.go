@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func resetRegistry() {
+	toDdlRegistry = make(map[string]ToDdl)
+	typeConverters = make(map[string]map[string]TypeConverter)
+}
+
+func TestLookupToDdl(t *testing.T) {
+	defer resetRegistry()
+	conv := internal.MakeConv()
+	builtin := new(MockToDdl)
+	builtin.On("ToSpannerType", conv, "", schema.Type{Name: "text"}, false).Return(ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{})
+
+	// Without registrations, the builtin implementation is used as is.
+	assert.Equal(t, builtin, LookupToDdl("mysql", builtin))
+
+	RegisterTypeConverter("mysql", "GEOHASH", func(conv *internal.Conv, spType string, srcType schema.Type, isPk bool) (ddl.Type, []internal.SchemaIssue) {
+		return ddl.Type{Name: ddl.String, Len: 12}, nil
+	})
+	toddl := LookupToDdl("mysql", builtin)
+	ty, _ := toddl.ToSpannerType(conv, "", schema.Type{Name: "geohash"}, false)
+	assert.Equal(t, ddl.Type{Name: ddl.String, Len: 12}, ty)
+	ty, _ = toddl.ToSpannerType(conv, "", schema.Type{Name: "text"}, false)
+	assert.Equal(t, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, ty)
+	// Converters only apply to the source they are registered for.
+	assert.Equal(t, builtin, LookupToDdl("postgres", builtin))
+
+	custom := new(MockToDdl)
+	RegisterToDdl("postgres", custom)
+	assert.Equal(t, custom, LookupToDdl("postgres", builtin))
+}
+
+func TestLookupToDdl_OptionProvider(t *testing.T) {
+	defer resetRegistry()
+	builtin := new(MockOptionProvider)
+	builtin.On("GetTypeOption", "udt", ddl.Type{Name: ddl.JSON}).Return("frozen<udt>")
+	RegisterTypeConverter("cassandra", "udt", func(conv *internal.Conv, spType string, srcType schema.Type, isPk bool) (ddl.Type, []internal.SchemaIssue) {
+		return ddl.Type{Name: ddl.JSON}, nil
+	})
+	op, ok := LookupToDdl("cassandra", builtin).(OptionProvider)
+	assert.True(t, ok)
+	assert.Equal(t, "frozen<udt>", op.GetTypeOption("udt", ddl.Type{Name: ddl.JSON}))
+}
@@ -0,0 +1,343 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+)
+
+const (
+	// chunksPerWorker splits a table into more chunks than there are workers,
+	// so that a skewed key distribution doesn't leave a single worker reading
+	// most of the table.
+	chunksPerWorker = 4
+	// minRowsPerChunk is the smallest number of rows worth reading as a chunk.
+	// Tables with fewer rows than two chunks are read with a single query.
+	minRowsPerChunk = 10000
+	// chunkReadRetries is the number of times reading a chunk is retried.
+	chunkReadRetries = 3
+)
+
+// chunkRetryDelay is the delay before the first retry of a chunk, which grows
+// linearly with every further retry.
+var chunkRetryDelay = time.Second
+
+// TableChunk is a range of primary key values of a table. Lower is exclusive
+// and Upper is inclusive, and a nil bound leaves that end of the range open.
+type TableChunk struct {
+	Lower *string
+	Upper *string
+}
+
+func (c TableChunk) String() string {
+	bound := func(b *string) string {
+		if b == nil {
+			return "unbounded"
+		}
+		return *b
+	}
+	return fmt.Sprintf("(%s, %s]", bound(c.Lower), bound(c.Upper))
+}
+
+// ChunkedTableReader reads a table with a single column primary key by key
+// range, with several workers reading ranges of the table concurrently.
+type ChunkedTableReader struct {
+	Db *sql.DB
+	// SnapshotId, when set, is a PostgreSQL exported snapshot that every worker
+	// imports, so that all chunks are read from the same consistent snapshot.
+	SnapshotId string
+	Table      string // Quoted table name.
+	Key        string // Quoted primary key column.
+	KeyName    string // Primary key column name, as returned by Query.
+	// IntegerKey splits the key range arithmetically between the smallest and
+	// largest key. Other keys are split at key values sampled from the table.
+	IntegerKey bool
+	// Query selects the rows of the table. A range condition on Key and an
+	// ORDER BY Key are appended to it.
+	Query string
+	// Bound renders a key value in a range condition, where arg is the position
+	// of the next query parameter. It returns either an inline literal and no
+	// args, or a query parameter and the value bound to it.
+	Bound func(value string, arg int) (string, []interface{})
+	// NewRow returns the destinations a row of n columns is scanned into. They
+	// must not be reused across rows, since rows are handed over to the caller.
+	NewRow func(n int) []interface{}
+}
+
+// NumTableChunks returns the number of chunks that a table of rowCount rows is
+// read in by parallelism workers, or 1 if the table is read with a single query.
+func NumTableChunks(rowCount int64, parallelism int) int {
+	if parallelism <= 1 {
+		return 1
+	}
+	n := int64(parallelism * chunksPerWorker)
+	if byRows := rowCount / minRowsPerChunk; byRows < n {
+		n = byRows
+	}
+	if n < 2 {
+		return 1
+	}
+	return int(n)
+}
+
+// Split divides the key range of the table into at most n chunks, which
+// together cover every key of the table.
+func (r ChunkedTableReader) Split(n int, rowCount int64) ([]TableChunk, error) {
+	var bounds []string
+	var err error
+	if r.IntegerKey {
+		bounds, err = r.integerBounds(n)
+	} else {
+		bounds, err = r.sampledBounds(n, rowCount)
+	}
+	if err != nil {
+		return nil, err
+	}
+	chunks := []TableChunk{}
+	var lower *string
+	for i := range bounds {
+		chunks = append(chunks, TableChunk{Lower: lower, Upper: &bounds[i]})
+		lower = &bounds[i]
+	}
+	return append(chunks, TableChunk{Lower: lower}), nil
+}
+
+func (r ChunkedTableReader) integerBounds(n int) ([]string, error) {
+	var lo, hi sql.NullString
+	q := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM %s", r.Key, r.Key, r.Table)
+	if err := r.Db.QueryRow(q).Scan(&lo, &hi); err != nil {
+		return nil, fmt.Errorf("couldn't get key range of %s: %w", r.Table, err)
+	}
+	if !lo.Valid || !hi.Valid {
+		return nil, nil
+	}
+	first, ok1 := new(big.Int).SetString(lo.String, 10)
+	last, ok2 := new(big.Int).SetString(hi.String, 10)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("key range [%s, %s] of %s is not an integer range", lo.String, hi.String, r.Table)
+	}
+	span := new(big.Int).Sub(last, first)
+	var bounds []string
+	for i := 1; i < n; i++ {
+		b := new(big.Int).Mul(span, big.NewInt(int64(i)))
+		b.Div(b, big.NewInt(int64(n))).Add(b, first)
+		bounds = appendBound(bounds, b.String())
+	}
+	return bounds, nil
+}
+
+func (r ChunkedTableReader) sampledBounds(n int, rowCount int64) ([]string, error) {
+	var bounds []string
+	for i := 1; i < n; i++ {
+		var b sql.NullString
+		q := fmt.Sprintf("SELECT %s FROM %s ORDER BY %s LIMIT 1 OFFSET %d", r.Key, r.Table, r.Key, rowCount*int64(i)/int64(n))
+		err := r.Db.QueryRow(q).Scan(&b)
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("couldn't sample keys of %s: %w", r.Table, err)
+		}
+		if b.Valid {
+			bounds = appendBound(bounds, b.String)
+		}
+	}
+	return bounds, nil
+}
+
+// appendBound appends b to bounds unless it repeats the last bound, which would
+// make an empty chunk.
+func appendBound(bounds []string, b string) []string {
+	if len(bounds) > 0 && bounds[len(bounds)-1] == b {
+		return bounds
+	}
+	return append(bounds, b)
+}
+
+// chunkRow is a row read by a worker, or the error scanning it.
+type chunkRow struct {
+	cols []string
+	vals []interface{}
+	err  error
+}
+
+// Read reads all chunks with parallelism workers. The rows are passed to
+// process one at a time, on the calling goroutine, in no particular order. A
+// chunk that fails is retried from the row after the last one it returned;
+// Read returns an error once a chunk has failed chunkReadRetries times, or
+// the error of ctx once it is canceled, after the rows already read.
+func (r ChunkedTableReader) Read(parent context.Context, chunks []TableChunk, parallelism int, process func(cols []string, vals []interface{}, err error)) error {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	chunkCh := make(chan TableChunk)
+	rowCh := make(chan chunkRow, parallelism*100)
+	errCh := make(chan error, parallelism)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range chunkCh {
+				if err := r.readChunk(ctx, chunk, rowCh); err != nil {
+					errCh <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(chunkCh)
+		for _, chunk := range chunks {
+			select {
+			case chunkCh <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(rowCh)
+	}()
+	for row := range rowCh {
+		process(row.cols, row.vals, row.err)
+	}
+	select {
+	case err := <-errCh:
+		return err
+	default:
+		return parent.Err()
+	}
+}
+
+func (r ChunkedTableReader) readChunk(ctx context.Context, chunk TableChunk, out chan<- chunkRow) error {
+	var err error
+	for attempt := 0; attempt <= chunkReadRetries; attempt++ {
+		if attempt > 0 {
+			logger.Log.Debug(fmt.Sprintf("Retrying read of %s key range %s after error: %v", r.Table, chunk, err))
+			select {
+			case <-time.After(chunkRetryDelay * time.Duration(attempt)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		var last *string
+		last, err = r.readRange(ctx, chunk, out)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if last != nil {
+			// Rows up to last have been handed over, resume after them.
+			chunk.Lower = last
+		}
+	}
+	return fmt.Errorf("couldn't read %s key range %s after %d retries: %w", r.Table, chunk, chunkReadRetries, err)
+}
+
+// readRange reads the rows of a chunk in key order and returns the key of the
+// last row handed over.
+func (r ChunkedTableReader) readRange(ctx context.Context, chunk TableChunk, out chan<- chunkRow) (*string, error) {
+	var conds []string
+	var args []interface{}
+	if chunk.Lower != nil {
+		b, a := r.Bound(*chunk.Lower, len(args)+1)
+		conds = append(conds, fmt.Sprintf("%s > %s", r.Key, b))
+		args = append(args, a...)
+	}
+	if chunk.Upper != nil {
+		b, a := r.Bound(*chunk.Upper, len(args)+1)
+		conds = append(conds, fmt.Sprintf("%s <= %s", r.Key, b))
+		args = append(args, a...)
+	}
+	q := r.Query
+	if len(conds) > 0 {
+		q += " WHERE " + strings.Join(conds, " AND ")
+	}
+	q += " ORDER BY " + r.Key
+
+	conn, err := r.Db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if r.SnapshotId != "" {
+		if _, err := conn.ExecContext(ctx, "BEGIN TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY"); err != nil {
+			return nil, err
+		}
+		defer conn.ExecContext(context.Background(), "ROLLBACK")
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET TRANSACTION SNAPSHOT '%s'", r.SnapshotId)); err != nil {
+			return nil, err
+		}
+	}
+	rows, err := conn.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	keyIdx := -1
+	for i, c := range cols {
+		if c == r.KeyName {
+			keyIdx = i
+		}
+	}
+	if keyIdx < 0 {
+		return nil, fmt.Errorf("key column %s is not read by the query", r.KeyName)
+	}
+	var last *string
+	for rows.Next() {
+		vals := r.NewRow(len(cols))
+		row := chunkRow{cols: cols, vals: vals, err: rows.Scan(vals...)}
+		select {
+		case out <- row:
+		case <-ctx.Done():
+			return last, ctx.Err()
+		}
+		if row.err == nil {
+			key := scannedKey(vals[keyIdx])
+			last = &key
+		}
+	}
+	return last, rows.Err()
+}
+
+// scannedKey returns the key value scanned into dest as a string.
+func scannedKey(dest interface{}) string {
+	switch d := dest.(type) {
+	case *[]byte:
+		return string(*d)
+	case *interface{}:
+		if b, ok := (*d).([]byte); ok {
+			return string(b)
+		}
+		return fmt.Sprint(*d)
+	}
+	return fmt.Sprint(dest)
+}
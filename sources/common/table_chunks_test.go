@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testChunkedTableReader(t *testing.T, integerKey bool) (ChunkedTableReader, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	return ChunkedTableReader{
+		Db:         db,
+		Table:      "`test`.`t`",
+		Key:        "`id`",
+		KeyName:    "id",
+		IntegerKey: integerKey,
+		Query:      "SELECT `id`,`name` FROM `test`.`t`",
+		Bound: func(value string, _ int) (string, []interface{}) {
+			return value, nil
+		},
+		NewRow: func(n int) []interface{} {
+			vals := make([]interface{}, n)
+			for i := range vals {
+				vals[i] = new([]byte)
+			}
+			return vals
+		},
+	}, mock
+}
+
+func chunkBounds(chunks []TableChunk) []string {
+	var s []string
+	for _, c := range chunks {
+		s = append(s, c.String())
+	}
+	return s
+}
+
+func TestNumTableChunks(t *testing.T) {
+	assert.Equal(t, 1, NumTableChunks(1000000, 1))
+	assert.Equal(t, 1, NumTableChunks(15000, 4))
+	assert.Equal(t, 3, NumTableChunks(30000, 4))
+	assert.Equal(t, 16, NumTableChunks(1000000, 4))
+}
+
+func TestChunkedTableReaderSplit(t *testing.T) {
+	r, mock := testChunkedTableReader(t, true)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT MIN(`id`), MAX(`id`) FROM `test`.`t`")).WillReturnRows(sqlmock.NewRows([]string{"min", "max"}).AddRow(1, 100))
+	chunks, err := r.Split(4, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"(unbounded, 25]", "(25, 50]", "(50, 75]", "(75, unbounded]"}, chunkBounds(chunks))
+
+	// An empty table is read as a single chunk.
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT MIN(`id`), MAX(`id`) FROM `test`.`t`")).WillReturnRows(sqlmock.NewRows([]string{"min", "max"}).AddRow(nil, nil))
+	chunks, err = r.Split(4, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"(unbounded, unbounded]"}, chunkBounds(chunks))
+
+	r, mock = testChunkedTableReader(t, false)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `test`.`t` ORDER BY `id` LIMIT 1 OFFSET 10")).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("bob"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `test`.`t` ORDER BY `id` LIMIT 1 OFFSET 20")).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("bob"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `test`.`t` ORDER BY `id` LIMIT 1 OFFSET 30")).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	chunks, err = r.Split(4, 40)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"(unbounded, bob]", "(bob, unbounded]"}, chunkBounds(chunks))
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestChunkedTableReaderRead(t *testing.T) {
+	chunkRetryDelay = time.Millisecond
+	r, mock := testChunkedTableReader(t, true)
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id`,`name` FROM `test`.`t` WHERE `id` <= 2 ORDER BY `id`")).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b"))
+	// The second chunk fails after its first row and is resumed after it.
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id`,`name` FROM `test`.`t` WHERE `id` > 2 ORDER BY `id`")).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "c").AddRow(4, "d").RowError(1, fmt.Errorf("connection reset")))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id`,`name` FROM `test`.`t` WHERE `id` > 3 ORDER BY `id`")).WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(4, "d"))
+	lower := "2"
+	chunks := []TableChunk{{Upper: &lower}, {Lower: &lower}}
+	var read []string
+	err := r.Read(context.Background(), chunks, 2, func(cols []string, vals []interface{}, err error) {
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"id", "name"}, cols)
+		read = append(read, string(*vals[0].(*[]byte))+string(*vals[1].(*[]byte)))
+	})
+	assert.Nil(t, err)
+	sort.Strings(read)
+	assert.Equal(t, []string{"1a", "2b", "3c", "4d"}, read)
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// A chunk that keeps failing fails the read.
+	r, mock = testChunkedTableReader(t, true)
+	for i := 0; i <= chunkReadRetries; i++ {
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT `id`,`name` FROM `test`.`t` ORDER BY `id`")).WillReturnError(fmt.Errorf("connection refused"))
+	}
+	err = r.Read(context.Background(), []TableChunk{{}}, 1, func(cols []string, vals []interface{}, err error) {})
+	assert.EqualError(t, err, "couldn't read `test`.`t` key range (unbounded, unbounded] after 3 retries: connection refused")
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// A canceled read stops without reading the remaining chunks.
+	r, _ = testChunkedTableReader(t, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = r.Read(ctx, chunks, 2, func(cols []string, vals []interface{}, err error) {
+		t.Errorf("unexpected row %v", vals)
+	})
+	assert.Equal(t, context.Canceled, err)
+}
@@ -126,6 +126,7 @@ func (ss *SchemaToSpannerImpl) SchemaToSpannerDDL(conv *internal.Conv, toddl ToD
 	}
 
 	internal.ResolveRefs(conv)
+	conv.UpdateLimitIssues()
 	return nil
 }
 
@@ -411,6 +412,12 @@ func (ss *SchemaToSpannerImpl) SchemaToSpannerDDLHelper(conv *internal.Conv, tod
 		if srcCol.Ignored.Default {
 			issues = append(issues, internal.DefaultValue)
 		}
+		if srcCol.UserType.Kind == schema.UserTypeDomain {
+			issues = append(issues, internal.DomainType)
+		}
+		if issue, ok := internal.CollationIssue(srcCol.Collation, ty); ok {
+			issues = append(issues, issue)
+		}
 		if srcCol.Ignored.AutoIncrement { // TODO(adibh) - check why this is not there in postgres
 			issues = append(issues, internal.AutoIncrement)
 		}
@@ -448,7 +455,7 @@ func (ss *SchemaToSpannerImpl) SchemaToSpannerDDLHelper(conv *internal.Conv, tod
 			Name:    colName,
 			T:       ty,
 			NotNull: isNotNull,
-			Comment: "From: " + quoteIfNeeded(srcCol.Name) + " " + srcCol.Type.Print(),
+			Comment: appendSourceComment("From: "+quoteIfNeeded(srcCol.Name)+" "+srcCol.Type.Print(), srcCol.Comment),
 			Id:      srcColId,
 			AutoGen: *autoGenCol,
 		}
@@ -475,7 +482,7 @@ func (ss *SchemaToSpannerImpl) SchemaToSpannerDDLHelper(conv *internal.Conv, tod
 		TableLevelIssues:  tableLevelIssues,
 		ColumnLevelIssues: columnLevelIssues,
 	}
-	comment := "Spanner schema for source table " + quoteIfNeeded(srcTable.Name)
+	comment := appendSourceComment("Spanner schema for source table "+quoteIfNeeded(srcTable.Name), srcTable.Comment)
 	conv.SpSchema[srcTable.Id] = ddl.CreateTable{
 		Name:             spTableName,
 		ColIds:           spColIds,
@@ -526,6 +533,17 @@ func quoteIfNeeded(s string) string {
 	return s
 }
 
+// appendSourceComment appends the comment defined in the source database
+// to the generated DDL comment. DDL comments are printed as single-line
+// '--' comments, so newlines in the source comment are collapsed.
+func appendSourceComment(comment, srcComment string) string {
+	srcComment = strings.Join(strings.Fields(srcComment), " ")
+	if srcComment == "" {
+		return comment
+	}
+	return comment + " (" + srcComment + ")"
+}
+
 func cvtPrimaryKeys(srcKeys []schema.Key) []ddl.IndexKey {
 	var spKeys []ddl.IndexKey
 	for _, k := range srcKeys {
@@ -562,6 +580,10 @@ func cvtCheckConstraint(conv *internal.Conv, srcKeys []schema.CheckConstraint) [
 }
 
 func CvtForeignKeysHelper(conv *internal.Conv, spTableName string, srcTableId string, srcKey schema.ForeignKey, isRestore bool) (ddl.Foreignkey, error) {
+	// Foreign keys referencing a table excluded by the table filter are dropped.
+	if srcKey.ReferTableId == "" && srcKey.ReferTableName != "" && !conv.TableFilter.Matches(srcKey.ReferTableName) {
+		return ddl.Foreignkey{}, fmt.Errorf("referenced table %s is excluded by the table filter", srcKey.ReferTableName)
+	}
 	if len(srcKey.ColIds) != len(srcKey.ReferColumnIds) {
 		conv.Unexpected(fmt.Sprintf("ConvertForeignKeys: ColIds and referColumns don't have the same lengths: len(columns)=%d, len(referColumns)=%d for source tableId: %s, referenced table: %s", len(srcKey.ColIds), len(srcKey.ReferColumnIds), srcTableId, srcKey.ReferTableId))
 		return ddl.Foreignkey{}, fmt.Errorf("ConvertForeignKeys: columns and referColumns don't have the same lengths")
@@ -631,6 +653,7 @@ func SrcTableToSpannerDDL(conv *internal.Conv, toddl ToDdl, srcTable schema.Tabl
 	}
 
 	internal.ResolveRefs(conv)
+	conv.UpdateLimitIssues()
 	return nil
 }
 
@@ -672,7 +695,7 @@ func CvtIndexHelper(conv *internal.Conv, tableId string, srcIndex schema.Index,
 			conv.Unexpected(fmt.Sprintf("Can't map index key column for tableId %s columnId %s", tableId, k.ColId))
 			continue
 		}
-		spKeys = append(spKeys, ddl.IndexKey{ColId: k.ColId, Desc: k.Desc, Order: k.Order})
+		spKeys = append(spKeys, ddl.IndexKey{ColId: k.ColId, Desc: k.Desc, Order: k.Order, NullsOrder: cvtNullsOrder(conv, tableId, k)})
 	}
 	for _, colId := range srcIndex.StoredColumnIds {
 		isPresent := false
@@ -691,7 +714,14 @@ func CvtIndexHelper(conv *internal.Conv, tableId string, srcIndex schema.Index,
 	if srcIndex.Name == "" {
 		// Generate a name if index name is empty in MySQL.
 		// Collision of index name will be handled by ToSpannerIndexName.
-		srcIndex.Name = fmt.Sprintf("Index_%s", conv.SrcSchema[tableId].Name)
+		srcTable := conv.SrcSchema[tableId]
+		var colNames []string
+		for _, k := range srcIndex.Keys {
+			if col, ok := srcTable.ColDefs[k.ColId]; ok {
+				colNames = append(colNames, col.Name)
+			}
+		}
+		srcIndex.Name = conv.NameTemplates.IndexName(srcTable.Name, colNames)
 	}
 	spIndexName := internal.ToSpannerIndexName(conv, srcIndex.Name)
 	spIndex := ddl.CreateIndex{
@@ -705,6 +735,36 @@ func CvtIndexHelper(conv *internal.Conv, tableId string, srcIndex schema.Index,
 	return spIndex
 }
 
+// cvtNullsOrder returns the null ordering of an index key in Spanner.
+// PostgreSQL dialect sorts NULLs last in ascending order (first in descending
+// order) by default, so only other null orderings are emitted. GoogleSQL
+// dialect doesn't support NULLS FIRST/LAST and sorts NULLs first in ascending
+// order (last in descending order), so a source null ordering that differs
+// from it can't be preserved and is reported as an issue.
+func cvtNullsOrder(conv *internal.Conv, tableId string, k schema.Key) string {
+	if k.NullsOrder == "" {
+		return ""
+	}
+	nullsFirst := k.NullsOrder == ddl.NullsFirst
+	if conv.SpDialect == constants.DIALECT_POSTGRESQL {
+		if nullsFirst == k.Desc {
+			return ""
+		}
+		return k.NullsOrder
+	}
+	if nullsFirst == k.Desc {
+		tableIssues := conv.SchemaIssues[tableId]
+		if tableIssues.ColumnLevelIssues == nil {
+			tableIssues.ColumnLevelIssues = make(map[string][]internal.SchemaIssue)
+		}
+		if !IsSchemaIssuePresent(tableIssues.ColumnLevelIssues[k.ColId], internal.IndexNullsOrder) {
+			tableIssues.ColumnLevelIssues[k.ColId] = append(tableIssues.ColumnLevelIssues[k.ColId], internal.IndexNullsOrder)
+		}
+		conv.SchemaIssues[tableId] = tableIssues
+	}
+	return ""
+}
+
 // For primary key with Generated expression, we remove the expression and add column error.
 // This happens when the expression itself is correct but the expression is not allowed by Spanner.
 // For eg, multi-column dependencies.
@@ -60,6 +60,24 @@ func Test_quoteIfNeeded(t *testing.T) {
 	}
 }
 
+func Test_appendSourceComment(t *testing.T) {
+	tests := []struct {
+		name       string
+		comment    string
+		srcComment string
+		expected   string
+	}{
+		{name: "no source comment", comment: "From: a int", srcComment: "", expected: "From: a int"},
+		{name: "source comment", comment: "From: a int", srcComment: "order id", expected: "From: a int (order id)"},
+		{name: "multi-line source comment", comment: "From: a int", srcComment: "order\n  id\r\n", expected: "From: a int (order id)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, appendSourceComment(tt.comment, tt.srcComment))
+		})
+	}
+}
+
 func Test_cvtPrimaryKeys(t *testing.T) {
 	srcKeys := []schema.Key{
 		{
@@ -192,6 +210,20 @@ func Test_cvtForeignKeys(t *testing.T) {
 	}
 }
 
+func Test_cvtForeignKeysExcludedTable(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.TableFilter, _ = internal.NewTableFilter("", "customers")
+	srcKeys := []schema.ForeignKey{{
+		Name:             "fk1",
+		ColIds:           []string{"c1"},
+		ReferTableName:   "customers",
+		ReferColumnNames: []string{"id"},
+		Id:               "f1",
+	}}
+	assert.Empty(t, cvtForeignKeys(conv, "orders", "t1", srcKeys, false))
+	assert.Empty(t, conv.Stats.Unexpected)
+}
+
 func Test_cvtIndexes(t *testing.T) {
 	tableId := "t1"
 	spColIds := []string{"c1", "c2", "c3"}
@@ -288,6 +320,37 @@ func Test_cvtIndexes(t *testing.T) {
 	}
 }
 
+func Test_cvtNullsOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		dialect       string
+		key           schema.Key
+		expected      string
+		expectedIssue bool
+	}{
+		// PostgreSQL sources record the null ordering of every index key,
+		// including the PostgreSQL default of NULLS LAST for ASC and NULLS
+		// FIRST for DESC.
+		{name: "asc default nulls last not supported in GoogleSQL", key: schema.Key{ColId: "c1", NullsOrder: ddl.NullsLast}, expected: "", expectedIssue: true},
+		{name: "desc default nulls first not supported in GoogleSQL", key: schema.Key{ColId: "c1", Desc: true, NullsOrder: ddl.NullsFirst}, expected: "", expectedIssue: true},
+		{name: "asc nulls first matches GoogleSQL", key: schema.Key{ColId: "c1", NullsOrder: ddl.NullsFirst}, expected: ""},
+		{name: "desc nulls last matches GoogleSQL", key: schema.Key{ColId: "c1", Desc: true, NullsOrder: ddl.NullsLast}, expected: ""},
+		{name: "null ordering not recorded by the source", key: schema.Key{ColId: "c1", Desc: true}, expected: ""},
+		{name: "PG dialect asc default nulls last", dialect: constants.DIALECT_POSTGRESQL, key: schema.Key{ColId: "c1", NullsOrder: ddl.NullsLast}, expected: ""},
+		{name: "PG dialect desc default nulls first", dialect: constants.DIALECT_POSTGRESQL, key: schema.Key{ColId: "c1", Desc: true, NullsOrder: ddl.NullsFirst}, expected: ""},
+		{name: "PG dialect asc nulls first", dialect: constants.DIALECT_POSTGRESQL, key: schema.Key{ColId: "c1", NullsOrder: ddl.NullsFirst}, expected: ddl.NullsFirst},
+		{name: "PG dialect desc nulls last", dialect: constants.DIALECT_POSTGRESQL, key: schema.Key{ColId: "c1", Desc: true, NullsOrder: ddl.NullsLast}, expected: ddl.NullsLast},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conv := internal.MakeConv()
+			conv.SpDialect = tt.dialect
+			assert.Equal(t, tt.expected, cvtNullsOrder(conv, "t1", tt.key))
+			assert.Equal(t, tt.expectedIssue, IsSchemaIssuePresent(conv.SchemaIssues["t1"].ColumnLevelIssues["c1"], internal.IndexNullsOrder))
+		})
+	}
+}
+
 func Test_cvtForeignKeysForAReferenceTable(t *testing.T) {
 	conv := internal.Conv{
 		SrcSchema: map[string]schema.Table{
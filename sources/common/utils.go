@@ -16,6 +16,7 @@ package common
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
@@ -131,6 +132,19 @@ func GetCommonColumnIds(conv *internal.Conv, tableId string, colIds []string) []
 			commonColIds = append(commonColIds, colIds[i])
 		}
 	}
+	return AppendColumnTransformInputs(conv, tableId, commonColIds, srcSchema.ColIds)
+}
+
+// AppendColumnTransformInputs appends to commonColIds the source columns in
+// srcColIds which are read by the column transforms of table tableId, and
+// are not part of the Spanner table, e.g. columns merged into another column
+// and then removed.
+func AppendColumnTransformInputs(conv *internal.Conv, tableId string, commonColIds, srcColIds []string) []string {
+	for _, colId := range conv.ColumnTransformInputs(tableId) {
+		if slices.Contains(srcColIds, colId) && !slices.Contains(commonColIds, colId) {
+			commonColIds = append(commonColIds, colId)
+		}
+	}
 	return commonColIds
 }
 
@@ -144,7 +158,7 @@ func PrepareColumns(conv *internal.Conv, tableId string, srcCols []string) ([]st
 		}
 		srcColIds = append(srcColIds, colId)
 	}
-	commonIds := IntersectionOfTwoStringSlices(spColIds, srcColIds)
+	commonIds := AppendColumnTransformInputs(conv, tableId, IntersectionOfTwoStringSlices(spColIds, srcColIds), srcColIds)
 	if len(commonIds) == 0 {
 		return []string{}, fmt.Errorf("no common columns between source and spanner table")
 	}
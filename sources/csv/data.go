@@ -195,7 +195,7 @@ func getCSVDataRowCount(r *csvReader.Reader, colNames []string) (int64, error) {
 // ProcessCSV writes data across the tables provided in the manifest file. Each table's data can be provided
 // across multiple CSV files hence, the manifest accepts a list of file paths in the input.
 func (c *CsvImpl) ProcessCSV(conv *internal.Conv, tables []utils.ManifestTable, nullStr string, delimiter rune) error {
-	tableIds := ddl.GetSortedTableIdsBySpName(conv.SpSchema)
+	tableIds := conv.OrderTableIds(ddl.GetSortedTableIdsBySpName(conv.SpSchema))
 	nameToFiles := map[string][]string{}
 	for _, table := range tables {
 		nameToFiles[table.Table_name] = table.File_patterns
@@ -206,6 +206,9 @@ func (c *CsvImpl) ProcessCSV(conv *internal.Conv, tables []utils.ManifestTable,
 	}
 
 	for _, table := range orderedTables {
+		if conv.Interrupted() {
+			break
+		}
 		for _, filePath := range table.File_patterns {
 			// Default column order is same as in Spanner schema.
 			tableId, err := internal.GetTableIdFromSpName(conv.SpSchema, table.Table_name)
@@ -259,7 +262,7 @@ func (c *CsvImpl) ProcessSingleCSV(conv *internal.Conv, tableName string,
 		processDataRow(conv, nullStr, tableName, columnNames, colDefs, srcCols)
 	}
 
-	for {
+	for !conv.Interrupted() {
 		values, err := r.Read()
 		if err == io.EOF {
 			break
@@ -272,6 +275,14 @@ func (c *CsvImpl) ProcessSingleCSV(conv *internal.Conv, tableName string,
 	return nil
 }
 
+// ProcessDataRow converts a row of values in text form, such as the fields of
+// a JSON Lines record, and writes it to tableName. Values equal to nullStr are
+// written as NULL.
+func ProcessDataRow(conv *internal.Conv, nullStr, tableName string,
+	srcCols []string, colDefs map[string]ddl.ColumnDef, values []string) {
+	processDataRow(conv, nullStr, tableName, srcCols, colDefs, values)
+}
+
 // processDataRow converts a row into go data types as per the client libs.
 func processDataRow(conv *internal.Conv, nullStr, tableName string,
 	srcCols []string, colDefs map[string]ddl.ColumnDef, values []string) {
@@ -585,6 +596,10 @@ func convNumeric(val string) (big.Rat, error) {
 
 func convTimestamp(val string) (t time.Time, err error) {
 	t, err = time.Parse("2006-01-02 15:04:05", val)
+	if err != nil {
+		// Also accept RFC 3339 timestamps, as used by JSON exports.
+		t, err = time.Parse(time.RFC3339Nano, val)
+	}
 	if err != nil {
 		return t, fmt.Errorf("can't convert to timestamp: %s", val)
 	}
@@ -0,0 +1,27 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The Db2 driver requires cgo and the IBM Data Server Driver (clidriver), so
+// it is only linked into builds with the db2 build tag.
+
+//go:build db2
+
+package db2
+
+import (
+	_ "github.com/ibmdb/go_ibm_db" // The driver should be used via the database/sql package.
+)
+
+// driverLinked reports whether the Db2 driver is linked into the build.
+const driverLinked = true
@@ -0,0 +1,413 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db2
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	sp "cloud.google.com/go/spanner"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// DriverName is the name the Db2 database/sql driver registers itself with.
+const DriverName = "go_ibm_db"
+
+// Open opens a connection to the Db2 database of connStr. It fails if the
+// tool was built without the db2 build tag, which links the Db2 driver.
+func Open(connStr string) (*sql.DB, error) {
+	if !driverLinked {
+		return nil, fmt.Errorf("this build of the tool doesn't support %s sources: build it with the db2 build tag, see sources/db2/README.md", constants.DB2)
+	}
+	return sql.Open(DriverName, connStr)
+}
+
+// errDataNotSupported is returned for data migrations, which are not
+// supported for Db2 yet.
+var errDataNotSupported = fmt.Errorf("data migration from %s is not supported, only schema conversion is", constants.DB2)
+
+// InfoSchemaImpl reads the schema of the tables of a Db2 schema from the
+// SYSCAT catalog views.
+type InfoSchemaImpl struct {
+	DbName string
+	Schema string
+	Db     *sql.DB
+}
+
+// GetToDdl function below implement the common.InfoSchema interface.
+func (isi InfoSchemaImpl) GetToDdl() common.ToDdl {
+	return common.LookupToDdl(constants.DB2, ToDdlImpl{})
+}
+
+// GetTableName returns table name. Tables are read from a single schema, so
+// the schema is not part of the name.
+func (isi InfoSchemaImpl) GetTableName(schema string, tableName string) string {
+	return tableName
+}
+
+// GetRowsFromTable is not supported as data migrations from Db2 are not
+// supported.
+func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string) (interface{}, error) {
+	return nil, errDataNotSupported
+}
+
+// ProcessData is not supported as data migrations from Db2 are not
+// supported.
+func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, commonColIds []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
+	return errDataNotSupported
+}
+
+// GetRowCount returns the number of rows of a table.
+func (isi InfoSchemaImpl) GetRowCount(table common.SchemaAndName) (int64, error) {
+	q := fmt.Sprintf(`SELECT COUNT(*) FROM "%s"."%s"`, table.Schema, table.Name)
+	rows, err := isi.Db.Query(q)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	var count int64
+	if rows.Next() {
+		err := rows.Scan(&count)
+		return count, err
+	}
+	return 0, nil
+}
+
+// GetTables returns the regular tables of the schema.
+func (isi InfoSchemaImpl) GetTables() ([]common.SchemaAndName, error) {
+	q := `
+		SELECT TABNAME
+		FROM SYSCAT.TABLES
+		WHERE TABSCHEMA = ? AND TYPE = 'T'
+		ORDER BY TABNAME
+	`
+	rows, err := isi.Db.Query(q, isi.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get tables: %w", err)
+	}
+	defer rows.Close()
+	var tableName string
+	var tables []common.SchemaAndName
+	for rows.Next() {
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("couldn't get tables: %w", err)
+		}
+		tables = append(tables, common.SchemaAndName{Schema: isi.Schema, Name: tableName})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("couldn't get tables: %w", err)
+	}
+	return tables, nil
+}
+
+// GetColumns returns a list of Column objects and names
+func (isi InfoSchemaImpl) GetColumns(conv *internal.Conv, table common.SchemaAndName, constraints map[string][]string, primaryKeys []string) (map[string]schema.Column, []string, error) {
+	q := `
+		SELECT
+			COLNAME,
+			TYPENAME,
+			NULLS,
+			DEFAULT,
+			LENGTH,
+			SCALE,
+			CODEPAGE,
+			IDENTITY
+		FROM SYSCAT.COLUMNS
+		WHERE TABSCHEMA = ? AND TABNAME = ?
+		ORDER BY COLNO
+	`
+	cols, err := isi.Db.Query(q, table.Schema, table.Name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't get schema for table %s.%s: %s", table.Schema, table.Name, err)
+	}
+	defer cols.Close()
+	colDefs := make(map[string]schema.Column)
+	var colIds []string
+	var colName, typeName, nulls, identity string
+	var colDefault sql.NullString
+	var length, scale, codepage int64
+	for cols.Next() {
+		err := cols.Scan(&colName, &typeName, &nulls, &colDefault, &length, &scale, &codepage, &identity)
+		if err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		ignored := schema.Ignored{}
+		for _, c := range constraints[colName] {
+			// We've already filtered out PRIMARY KEY, and unique
+			// constraints are handled as indexes.
+			switch c {
+			case "CHECK":
+				ignored.Check = true
+			}
+		}
+		ignored.Default = colDefault.Valid
+		ignored.Identity = strings.TrimSpace(identity) == "Y"
+		colId := internal.GenerateColumnId()
+		c := schema.Column{
+			Id:      colId,
+			Name:    colName,
+			Type:    toType(strings.TrimSpace(typeName), length, scale, codepage),
+			NotNull: strings.TrimSpace(nulls) == "N",
+			Ignored: ignored,
+		}
+		colDefs[colId] = c
+		colIds = append(colIds, colId)
+	}
+	return colDefs, colIds, nil
+}
+
+// GetConstraints returns a list of primary keys, the check constraints
+// and by-column map of other constraints. Note: we need to preserve
+// ordinal order of columns in primary key constraints.
+// Note that foreign key constraints are handled in getForeignKeys.
+func (isi InfoSchemaImpl) GetConstraints(conv *internal.Conv, table common.SchemaAndName) ([]string, []schema.CheckConstraint, map[string][]string, error) {
+	q := `
+		SELECT
+			K.COLNAME,
+			C.TYPE
+		FROM SYSCAT.TABCONST AS C
+		INNER JOIN SYSCAT.KEYCOLUSE AS K
+			ON K.CONSTNAME = C.CONSTNAME AND K.TABSCHEMA = C.TABSCHEMA AND K.TABNAME = C.TABNAME
+		WHERE C.TABSCHEMA = ? AND C.TABNAME = ? AND C.TYPE IN ('P', 'U')
+		ORDER BY C.CONSTNAME, K.COLSEQ
+	`
+	rows, err := isi.Db.Query(q, table.Schema, table.Name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer rows.Close()
+	var primaryKeys []string
+	var col, constraint string
+	m := make(map[string][]string)
+	for rows.Next() {
+		err := rows.Scan(&col, &constraint)
+		if err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		// P (primary key) and U (unique) constraints in Db2.
+		switch strings.TrimSpace(constraint) {
+		case "P":
+			primaryKeys = append(primaryKeys, col)
+		case "U":
+			m[col] = append(m[col], "UNIQUE")
+		}
+	}
+	checkKeys, err := isi.getCheckConstraints(conv, table)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return primaryKeys, checkKeys, m, nil
+}
+
+// getCheckConstraints returns the check constraints of a table. Check
+// constraints generated by Db2, e.g. for functional dependencies, are
+// skipped.
+func (isi InfoSchemaImpl) getCheckConstraints(conv *internal.Conv, table common.SchemaAndName) ([]schema.CheckConstraint, error) {
+	q := `
+		SELECT
+			CONSTNAME,
+			TEXT
+		FROM SYSCAT.CHECKS
+		WHERE TABSCHEMA = ? AND TABNAME = ? AND TYPE = 'C'
+		ORDER BY CONSTNAME
+	`
+	rows, err := isi.Db.Query(q, table.Schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var checkKeys []schema.CheckConstraint
+	var name, text string
+	for rows.Next() {
+		err := rows.Scan(&name, &text)
+		if err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		expr := strings.TrimSpace(text)
+		if !strings.HasPrefix(expr, "(") || !strings.HasSuffix(expr, ")") {
+			expr = "(" + expr + ")"
+		}
+		checkKeys = append(checkKeys, schema.CheckConstraint{Name: name, Expr: expr, ExprId: internal.GenerateExpressionId(), Id: internal.GenerateCheckConstrainstId()})
+	}
+	return checkKeys, nil
+}
+
+// GetForeignKeys return list all the foreign keys constraints. Foreign keys
+// referencing tables of other schemas are ignored as only the tables of
+// one schema are migrated.
+func (isi InfoSchemaImpl) GetForeignKeys(conv *internal.Conv, table common.SchemaAndName) (foreignKeys []schema.ForeignKey, err error) {
+	q := `
+		SELECT
+			R.CONSTNAME,
+			R.REFTABNAME,
+			K.COLNAME,
+			RK.COLNAME,
+			R.DELETERULE,
+			R.UPDATERULE
+		FROM SYSCAT.REFERENCES AS R
+		INNER JOIN SYSCAT.KEYCOLUSE AS K
+			ON K.CONSTNAME = R.CONSTNAME AND K.TABSCHEMA = R.TABSCHEMA AND K.TABNAME = R.TABNAME
+		INNER JOIN SYSCAT.KEYCOLUSE AS RK
+			ON RK.CONSTNAME = R.REFKEYNAME AND RK.TABSCHEMA = R.REFTABSCHEMA AND RK.TABNAME = R.REFTABNAME AND RK.COLSEQ = K.COLSEQ
+		WHERE R.TABSCHEMA = ? AND R.TABNAME = ? AND R.REFTABSCHEMA = R.TABSCHEMA
+		ORDER BY R.CONSTNAME, K.COLSEQ
+	`
+	rows, err := isi.Db.Query(q, table.Schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var name, refTable, col, refCol, deleteRule, updateRule string
+	fKeys := make(map[string]schema.ForeignKey)
+	var keyNames []string
+	for rows.Next() {
+		err := rows.Scan(&name, &refTable, &col, &refCol, &deleteRule, &updateRule)
+		if err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		fk, found := fKeys[name]
+		if !found {
+			keyNames = append(keyNames, name)
+			fk = schema.ForeignKey{
+				Id:             internal.GenerateForeignkeyId(),
+				Name:           name,
+				ReferTableName: refTable,
+				OnDelete:       toFkAction(deleteRule),
+				OnUpdate:       toFkAction(updateRule),
+			}
+		}
+		fk.ColumnNames = append(fk.ColumnNames, col)
+		fk.ReferColumnNames = append(fk.ReferColumnNames, refCol)
+		fKeys[name] = fk
+	}
+	for _, k := range keyNames {
+		foreignKeys = append(foreignKeys, fKeys[k])
+	}
+	return foreignKeys, nil
+}
+
+// GetIndexes return a list of all indexes for the specified table, except
+// for the index of the primary key. Indexes on expressions are skipped.
+func (isi InfoSchemaImpl) GetIndexes(conv *internal.Conv, table common.SchemaAndName, colNameIdMap map[string]string) ([]schema.Index, error) {
+	q := `
+		SELECT
+			I.INDNAME,
+			C.COLNAME,
+			C.COLORDER,
+			I.UNIQUERULE
+		FROM SYSCAT.INDEXES AS I
+		INNER JOIN SYSCAT.INDEXCOLUSE AS C
+			ON C.INDSCHEMA = I.INDSCHEMA AND C.INDNAME = I.INDNAME
+		WHERE I.TABSCHEMA = ? AND I.TABNAME = ? AND I.UNIQUERULE <> 'P'
+		ORDER BY I.INDNAME, C.COLSEQ
+	`
+	rows, err := isi.Db.Query(q, table.Schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var name, column, colOrder, uniqueRule string
+	indexMap := make(map[string]schema.Index)
+	var indexNames []string
+	ignoredIndex := make(map[string]bool)
+	var indexes []schema.Index
+	for rows.Next() {
+		if err := rows.Scan(&name, &column, &colOrder, &uniqueRule); err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		colId, ok := colNameIdMap[column]
+		if !ok {
+			ignoredIndex[name] = true
+		}
+		if _, found := indexMap[name]; !found {
+			indexNames = append(indexNames, name)
+			indexMap[name] = schema.Index{
+				Id:     internal.GenerateIndexesId(),
+				Name:   name,
+				Unique: strings.TrimSpace(uniqueRule) == "U"}
+		}
+		index := indexMap[name]
+		// COLORDER is A (ascending), D (descending) or I (include column).
+		switch strings.TrimSpace(colOrder) {
+		case "I":
+			index.StoredColumnIds = append(index.StoredColumnIds, colId)
+		default:
+			index.Keys = append(index.Keys, schema.Key{
+				ColId: colId,
+				Desc:  strings.TrimSpace(colOrder) == "D"})
+		}
+		indexMap[name] = index
+	}
+	for _, k := range indexNames {
+		if !ignoredIndex[k] {
+			indexes = append(indexes, indexMap[k])
+		}
+	}
+	return indexes, nil
+}
+
+// We leave the 2 functions below empty to be able to pass this as an infoSchema interface. We don't need these for now.
+func (isi InfoSchemaImpl) StartChangeDataCapture(ctx context.Context, conv *internal.Conv) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (isi InfoSchemaImpl) StartStreamingMigration(ctx context.Context, migrationProjectId string, client *sp.Client, conv *internal.Conv, streamingInfo map[string]interface{}) (internal.DataflowOutput, error) {
+	return internal.DataflowOutput{}, nil
+}
+
+// toType returns the source type of a column of SYSCAT.COLUMNS. Character
+// columns of code page 0 hold binary data, i.e. are FOR BIT DATA columns.
+func toType(typeName string, length, scale, codepage int64) schema.Type {
+	switch typeName {
+	case "CHARACTER", "VARCHAR", "LONG VARCHAR":
+		if codepage == 0 {
+			typeName += " FOR BIT DATA"
+		}
+		return schema.Type{Name: typeName, Mods: []int64{length}}
+	case "GRAPHIC", "VARGRAPHIC", "LONG VARGRAPHIC", "BINARY", "VARBINARY", "CLOB", "DBCLOB", "BLOB":
+		return schema.Type{Name: typeName, Mods: []int64{length}}
+	case "DECIMAL":
+		return schema.Type{Name: typeName, Mods: []int64{length, scale}}
+	default:
+		return schema.Type{Name: typeName}
+	}
+}
+
+// toFkAction maps the DELETERULE and UPDATERULE of SYSCAT.REFERENCES to
+// foreign key actions.
+func toFkAction(rule string) string {
+	switch strings.TrimSpace(rule) {
+	case "C":
+		return constants.FK_CASCADE
+	case "N":
+		return constants.FK_SET_NULL
+	case "R":
+		return constants.FK_RESTRICT
+	default:
+		return constants.FK_NO_ACTION
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db2
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+func init() {
+	logger.Log = zap.NewNop()
+}
+
+type mockSpec struct {
+	query string
+	args  []driver.Value   // Query args.
+	cols  []string         // Columns names for returned rows.
+	rows  [][]driver.Value // Set of rows returned.
+}
+
+func TestGetTables(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "SELECT TABNAME FROM SYSCAT.TABLES (.+)",
+			args:  []driver.Value{"SALES"},
+			cols:  []string{"TABNAME"},
+			rows:  [][]driver.Value{{"CUSTOMER"}, {"ORDERS"}},
+		},
+	}
+	isi := InfoSchemaImpl{Schema: "SALES", Db: mkMockDB(t, ms)}
+	tables, err := isi.GetTables()
+	assert.Nil(t, err)
+	assert.Equal(t, []common.SchemaAndName{{Schema: "SALES", Name: "CUSTOMER"}, {Schema: "SALES", Name: "ORDERS"}}, tables)
+}
+
+func TestGetTablesScanError(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "SELECT TABNAME FROM SYSCAT.TABLES (.+)",
+			args:  []driver.Value{"SALES"},
+			cols:  []string{"TABNAME"},
+			rows:  [][]driver.Value{{nil}},
+		},
+	}
+	isi := InfoSchemaImpl{Schema: "SALES", Db: mkMockDB(t, ms)}
+	_, err := isi.GetTables()
+	assert.Error(t, err)
+}
+
+func TestOpenWithoutDriver(t *testing.T) {
+	if driverLinked {
+		t.Skip("the Db2 driver is linked")
+	}
+	_, err := Open("HOSTNAME=localhost")
+	assert.ErrorContains(t, err, "db2 build tag")
+}
+
+func TestGetColumns(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "SELECT (.+) FROM SYSCAT.COLUMNS (.+)",
+			args:  []driver.Value{"SALES", "ORDERS"},
+			cols:  []string{"COLNAME", "TYPENAME", "NULLS", "DEFAULT", "LENGTH", "SCALE", "CODEPAGE", "IDENTITY"},
+			rows: [][]driver.Value{
+				{"ID", "INTEGER", "N", nil, 4, 0, 0, "Y"},
+				{"CODE", "CHARACTER", "N", nil, 16, 0, 0, "N"},
+				{"NAME", "VARCHAR", "Y", nil, 100, 0, 1208, "N"},
+				{"PRICE", "DECIMAL", "Y", "0", 10, 2, 0, "N"},
+				{"PLACED", "TIMESTAMP", "Y", "CURRENT TIMESTAMP", 10, 6, 0, "N"},
+			},
+		},
+	}
+	isi := InfoSchemaImpl{Schema: "SALES", Db: mkMockDB(t, ms)}
+	conv := internal.MakeConv()
+	colDefs, colIds, err := isi.GetColumns(conv, common.SchemaAndName{Schema: "SALES", Name: "ORDERS"}, map[string][]string{"NAME": {"CHECK"}}, []string{"ID"})
+	assert.Nil(t, err)
+	var cols []schema.Column
+	for _, id := range colIds {
+		c := colDefs[id]
+		c.Id = ""
+		cols = append(cols, c)
+	}
+	assert.Equal(t, []schema.Column{
+		{Name: "ID", Type: schema.Type{Name: "INTEGER"}, NotNull: true, Ignored: schema.Ignored{Identity: true}},
+		{Name: "CODE", Type: schema.Type{Name: "CHARACTER FOR BIT DATA", Mods: []int64{16}}, NotNull: true},
+		{Name: "NAME", Type: schema.Type{Name: "VARCHAR", Mods: []int64{100}}, Ignored: schema.Ignored{Check: true}},
+		{Name: "PRICE", Type: schema.Type{Name: "DECIMAL", Mods: []int64{10, 2}}, Ignored: schema.Ignored{Default: true}},
+		{Name: "PLACED", Type: schema.Type{Name: "TIMESTAMP"}, Ignored: schema.Ignored{Default: true}},
+	}, cols)
+	assert.Equal(t, int64(0), conv.Unexpecteds())
+}
+
+func TestGetConstraints(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "SELECT (.+) FROM SYSCAT.TABCONST (.+)",
+			args:  []driver.Value{"SALES", "ORDERS"},
+			cols:  []string{"COLNAME", "TYPE"},
+			rows: [][]driver.Value{
+				{"ID", "P"},
+				{"REGION", "P"},
+				{"CODE", "U"},
+			},
+		},
+		{
+			query: "SELECT (.+) FROM SYSCAT.CHECKS (.+)",
+			args:  []driver.Value{"SALES", "ORDERS"},
+			cols:  []string{"CONSTNAME", "TEXT"},
+			rows: [][]driver.Value{
+				{"CK_PRICE", "PRICE > 0"},
+				{"CK_QTY", "(QTY BETWEEN 1 AND 100)"},
+			},
+		},
+	}
+	isi := InfoSchemaImpl{Schema: "SALES", Db: mkMockDB(t, ms)}
+	conv := internal.MakeConv()
+	primaryKeys, checkKeys, m, err := isi.GetConstraints(conv, common.SchemaAndName{Schema: "SALES", Name: "ORDERS"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ID", "REGION"}, primaryKeys)
+	assert.Equal(t, map[string][]string{"CODE": {"UNIQUE"}}, m)
+	assert.Equal(t, 2, len(checkKeys))
+	assert.Equal(t, "CK_PRICE", checkKeys[0].Name)
+	assert.Equal(t, "(PRICE > 0)", checkKeys[0].Expr)
+	assert.Equal(t, "(QTY BETWEEN 1 AND 100)", checkKeys[1].Expr)
+}
+
+func TestGetForeignKeys(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "SELECT (.+) FROM SYSCAT.REFERENCES (.+)",
+			args:  []driver.Value{"SALES", "ORDERS"},
+			cols:  []string{"CONSTNAME", "REFTABNAME", "COLNAME", "COLNAME", "DELETERULE", "UPDATERULE"},
+			rows: [][]driver.Value{
+				{"FK_CUSTOMER", "CUSTOMER", "CUSTOMER_ID", "ID", "C", "A"},
+				{"FK_PRODUCT", "PRODUCT", "PRODUCT_ID", "ID", "R", "R"},
+				{"FK_PRODUCT", "PRODUCT", "REGION", "REGION", "R", "R"},
+			},
+		},
+	}
+	isi := InfoSchemaImpl{Schema: "SALES", Db: mkMockDB(t, ms)}
+	conv := internal.MakeConv()
+	foreignKeys, err := isi.GetForeignKeys(conv, common.SchemaAndName{Schema: "SALES", Name: "ORDERS"})
+	assert.Nil(t, err)
+	for i := range foreignKeys {
+		foreignKeys[i].Id = ""
+	}
+	assert.Equal(t, []schema.ForeignKey{
+		{Name: "FK_CUSTOMER", ColumnNames: []string{"CUSTOMER_ID"}, ReferTableName: "CUSTOMER", ReferColumnNames: []string{"ID"}, OnDelete: constants.FK_CASCADE, OnUpdate: constants.FK_NO_ACTION},
+		{Name: "FK_PRODUCT", ColumnNames: []string{"PRODUCT_ID", "REGION"}, ReferTableName: "PRODUCT", ReferColumnNames: []string{"ID", "REGION"}, OnDelete: constants.FK_RESTRICT, OnUpdate: constants.FK_RESTRICT},
+	}, foreignKeys)
+}
+
+func TestGetIndexes(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "SELECT (.+) FROM SYSCAT.INDEXES (.+)",
+			args:  []driver.Value{"SALES", "ORDERS"},
+			cols:  []string{"INDNAME", "COLNAME", "COLORDER", "UNIQUERULE"},
+			rows: [][]driver.Value{
+				{"IDX_CODE", "CODE", "A", "U"},
+				{"IDX_CODE", "NAME", "I", "U"},
+				{"IDX_EXPR", "K00", "A", "D"},
+				{"IDX_PLACED", "PLACED", "D", "D"},
+			},
+		},
+	}
+	isi := InfoSchemaImpl{Schema: "SALES", Db: mkMockDB(t, ms)}
+	conv := internal.MakeConv()
+	colNameIdMap := map[string]string{"CODE": "c1", "NAME": "c2", "PLACED": "c3"}
+	indexes, err := isi.GetIndexes(conv, common.SchemaAndName{Schema: "SALES", Name: "ORDERS"}, colNameIdMap)
+	assert.Nil(t, err)
+	for i := range indexes {
+		indexes[i].Id = ""
+	}
+	assert.Equal(t, []schema.Index{
+		{Name: "IDX_CODE", Unique: true, Keys: []schema.Key{{ColId: "c1"}}, StoredColumnIds: []string{"c2"}},
+		{Name: "IDX_PLACED", Keys: []schema.Key{{ColId: "c3", Desc: true}}},
+	}, indexes)
+}
+
+func TestProcessData(t *testing.T) {
+	isi := InfoSchemaImpl{Schema: "SALES"}
+	err := isi.ProcessData(internal.MakeConv(), "t1", schema.Table{}, nil, ddl.CreateTable{}, internal.AdditionalDataAttributes{})
+	assert.Equal(t, errDataNotSupported, err)
+}
+
+func mkMockDB(t *testing.T, ms []mockSpec) *sql.DB {
+	db, mock, err := sqlmock.New()
+	assert.Nil(t, err)
+	for _, m := range ms {
+		rows := sqlmock.NewRows(m.cols)
+		for _, r := range m.rows {
+			rows.AddRow(r...)
+		}
+		if len(m.args) > 0 {
+			mock.ExpectQuery(m.query).WithArgs(m.args...).WillReturnRows(rows)
+		} else {
+			mock.ExpectQuery(m.query).WillReturnRows(rows)
+		}
+	}
+	return db
+}
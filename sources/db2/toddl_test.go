@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+func TestToSpannerTypeInternal(t *testing.T) {
+	tests := []struct {
+		srcType    schema.Type
+		spType     string
+		wantType   ddl.Type
+		wantIssues []internal.SchemaIssue
+	}{
+		{schema.Type{Name: "BIGINT"}, "", ddl.Type{Name: ddl.Int64}, nil},
+		{schema.Type{Name: "INTEGER"}, "", ddl.Type{Name: ddl.Int64}, []internal.SchemaIssue{internal.Widened}},
+		{schema.Type{Name: "SMALLINT"}, ddl.String, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.Widened}},
+		{schema.Type{Name: "REAL"}, "", ddl.Type{Name: ddl.Float32}, nil},
+		{schema.Type{Name: "DOUBLE"}, "", ddl.Type{Name: ddl.Float64}, nil},
+		{schema.Type{Name: "DECIMAL", Mods: []int64{10, 2}}, "", ddl.Type{Name: ddl.Numeric}, nil},
+		{schema.Type{Name: "DECIMAL", Mods: []int64{31, 12}}, "", ddl.Type{Name: ddl.Numeric}, []internal.SchemaIssue{internal.PrecisionLoss}},
+		{schema.Type{Name: "DECFLOAT"}, ddl.Float64, ddl.Type{Name: ddl.Float64}, []internal.SchemaIssue{internal.PrecisionLoss}},
+		{schema.Type{Name: "BOOLEAN"}, "", ddl.Type{Name: ddl.Bool}, nil},
+		{schema.Type{Name: "VARCHAR", Mods: []int64{100}}, "", ddl.Type{Name: ddl.String, Len: 100}, nil},
+		{schema.Type{Name: "VARGRAPHIC", Mods: []int64{20}}, ddl.Bytes, ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}, nil},
+		{schema.Type{Name: "CLOB", Mods: []int64{1048576}}, "", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, nil},
+		{schema.Type{Name: "CHARACTER FOR BIT DATA", Mods: []int64{16}}, "", ddl.Type{Name: ddl.Bytes, Len: 16}, nil},
+		{schema.Type{Name: "BLOB", Mods: []int64{1048576}}, "", ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}, nil},
+		{schema.Type{Name: "DATE"}, "", ddl.Type{Name: ddl.Date}, nil},
+		{schema.Type{Name: "TIMESTAMP"}, "", ddl.Type{Name: ddl.Timestamp}, []internal.SchemaIssue{internal.Timestamp}},
+		{schema.Type{Name: "TIME"}, "", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.Time}},
+		{schema.Type{Name: "XML"}, "", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, nil},
+		{schema.Type{Name: "ROWID"}, "", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.NoGoodType}},
+	}
+	for _, tc := range tests {
+		ty, issues := toSpannerTypeInternal(tc.srcType, tc.spType)
+		assert.Equal(t, tc.wantType, ty, tc.srcType.Name)
+		assert.Equal(t, tc.wantIssues, issues, tc.srcType.Name)
+	}
+}
+
+func TestToSpannerType_PGDialect(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SpDialect = constants.DIALECT_POSTGRESQL
+	ty, issues := ToDdlImpl{}.ToSpannerType(conv, "", schema.Type{Name: "DECIMAL", Mods: []int64{10, 2}}, true)
+	assert.Equal(t, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, ty)
+	assert.Equal(t, []internal.SchemaIssue{internal.NumericPKNotSupported}, issues)
+}
@@ -40,7 +40,7 @@ func ProcessDataRow(m map[string]*dynamodb.AttributeValue, conv *internal.Conv,
 	} else {
 		conv.Unexpected(fmt.Sprintf("Data conversion error for table %s in column(s) %s\n", srcTableName, badCols))
 		conv.StatsAddBadRow(srcTableName, conv.DataMode())
-		conv.CollectBadRow(srcTableName, srcColNames, srcStrVals)
+		conv.CollectBadRowWithReason(srcTableName, srcColNames, srcStrVals, fmt.Sprintf("data conversion error in column(s) %s", badCols))
 	}
 }
 
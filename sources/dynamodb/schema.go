@@ -28,6 +28,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/aws/aws-sdk-go/service/dynamodbstreams/dynamodbstreamsiface"
 
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
@@ -59,7 +60,7 @@ type InfoSchemaImpl struct {
 }
 
 func (isi InfoSchemaImpl) GetToDdl() common.ToDdl {
-	return ToDdlImpl{}
+	return common.LookupToDdl(constants.DYNAMODB, ToDdlImpl{})
 }
 
 func (isi InfoSchemaImpl) GetTableName(schema string, tableName string) string {
@@ -97,6 +98,9 @@ func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, srcTable string)
 	srcTableName := conv.SrcSchema[srcTable].Name
 	var lastEvaluatedKey map[string]*dynamodb.AttributeValue
 	for {
+		if conv.Interrupted() {
+			return nil, conv.Context().Err()
+		}
 		// Build the query input parameters.
 		params := &dynamodb.ScanInput{
 			TableName: aws.String(srcTableName),
@@ -190,6 +194,9 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 	}
 	// Iterate the items returned.
 	for _, attrsMap := range rows.([]map[string]*dynamodb.AttributeValue) {
+		if conv.Interrupted() {
+			break
+		}
 		ProcessDataRow(attrsMap, conv, tableId, srcSchema, colIds, spSchema)
 	}
 	return nil
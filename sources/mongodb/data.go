@@ -0,0 +1,219 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/spanner"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// ProcessDataRow converts a row, given as the values of its source columns,
+// and writes it to Spanner.
+func ProcessDataRow(conv *internal.Conv, tableId string, row map[string]interface{}, srcSchema schema.Table, colIds []string, spSchema ddl.CreateTable) {
+	spVals, badCols, srcStrVals := cvtRow(conv, row, srcSchema, spSchema, colIds)
+	srcTableName := srcSchema.Name
+	spColNames := []string{}
+	srcColNames := []string{}
+	for _, colId := range colIds {
+		srcColNames = append(srcColNames, srcSchema.ColDefs[colId].Name)
+		spColNames = append(spColNames, spSchema.ColDefs[colId].Name)
+	}
+	if len(badCols) == 0 {
+		conv.WriteRow(srcTableName, spSchema.Name, spColNames, spVals)
+	} else {
+		conv.Unexpected(fmt.Sprintf("Data conversion error for table %s in column(s) %s\n", srcTableName, badCols))
+		conv.StatsAddBadRow(srcTableName, conv.DataMode())
+		conv.CollectBadRowWithReason(srcTableName, srcColNames, srcStrVals, fmt.Sprintf("data conversion error in column(s) %s", badCols))
+	}
+}
+
+// documentRow returns the values of the source columns of a row from the
+// fields of a document and the values of key columns that don't come from
+// the document. Fields that aren't migrated to any column, except for the
+// skipped ones, are collected in the _residual column.
+func documentRow(fields map[string]interface{}, keys map[string]interface{}, skip map[string]bool, srcSchema schema.Table, colIds []string) map[string]interface{} {
+	cols := make(map[string]bool)
+	for _, colId := range colIds {
+		cols[srcSchema.ColDefs[colId].Name] = true
+	}
+	row := make(map[string]interface{})
+	residual := make(map[string]interface{})
+	for f, v := range fields {
+		if skip[f] {
+			continue
+		}
+		if _, isKey := keys[f]; !isKey && f != residualCol && cols[f] {
+			row[f] = v
+		} else {
+			residual[f] = v
+		}
+	}
+	for k, v := range keys {
+		row[k] = v
+	}
+	if len(residual) > 0 {
+		row[residualCol] = residual
+	}
+	return row
+}
+
+func cvtRow(conv *internal.Conv, row map[string]interface{}, srcSchema schema.Table, spSchema ddl.CreateTable, colIds []string) ([]interface{}, []string, []string) {
+	var srcStrVals []string
+	var spVals []interface{}
+	var badCols []string
+	for _, colId := range colIds {
+		srcColName := srcSchema.ColDefs[colId].Name
+		var spVal interface{}
+		srcStrVal := "null"
+		if v := row[srcColName]; v != nil {
+			var err error
+			spVal, err = convValue(conv, v, spSchema.ColDefs[colId].T)
+			if err != nil {
+				badCols = append(badCols, srcColName)
+			}
+			srcStrVal, _ = toString(v)
+		}
+		srcStrVals = append(srcStrVals, srcStrVal)
+		spVals = append(spVals, spVal)
+	}
+	return spVals, badCols, srcStrVals
+}
+
+// convValue converts a non-null document value to a value of Spanner type t.
+func convValue(conv *internal.Conv, v interface{}, t ddl.Type) (interface{}, error) {
+	if t.IsArray {
+		return nil, fmt.Errorf("can't convert %v to an array of %s", v, t.Name)
+	}
+	switch t.Name {
+	case ddl.String:
+		return toString(v)
+	case ddl.JSON:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("can't convert %v to JSON: %v", v, err)
+		}
+		return string(b), nil
+	case ddl.Int64:
+		switch v := v.(type) {
+		case int64:
+			return v, nil
+		case float64:
+			if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+				return int64(v), nil
+			}
+		case string:
+			return strconv.ParseInt(v, 10, 64)
+		}
+	case ddl.Float64:
+		switch v := v.(type) {
+		case int64:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		case Decimal:
+			return strconv.ParseFloat(string(v), 64)
+		case string:
+			return strconv.ParseFloat(v, 64)
+		}
+	case ddl.Numeric:
+		var s string
+		switch v := v.(type) {
+		case int64:
+			s = strconv.FormatInt(v, 10)
+		case float64:
+			s = strconv.FormatFloat(v, 'f', -1, 64)
+		case Decimal:
+			s = string(v)
+		case string:
+			s = v
+		default:
+			return nil, fmt.Errorf("can't convert %v to %s", v, t.Name)
+		}
+		if conv.SpDialect == constants.DIALECT_POSTGRESQL {
+			return spanner.PGNumeric{Numeric: s, Valid: true}, nil
+		}
+		r, ok := new(big.Rat).SetString(s)
+		if !ok {
+			return nil, fmt.Errorf("can't convert %q to big.Rat", s)
+		}
+		return r, nil
+	case ddl.Bool:
+		switch v := v.(type) {
+		case bool:
+			return v, nil
+		case string:
+			return strconv.ParseBool(v)
+		}
+	case ddl.Timestamp:
+		switch v := v.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			return time.Parse(time.RFC3339Nano, v)
+		}
+	case ddl.Bytes:
+		switch v := v.(type) {
+		case []byte:
+			return v, nil
+		case ObjectId:
+			return hex.DecodeString(string(v))
+		case string:
+			return []byte(v), nil
+		}
+	}
+	return nil, fmt.Errorf("can't convert %v to %s", v, t.Name)
+}
+
+// toString returns the text of a document value: the JSON text of embedded
+// documents and arrays, and base64 for binary data as in Extended JSON.
+func toString(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case ObjectId:
+		return string(v), nil
+	case Decimal:
+		return string(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v), nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("can't convert %v to JSON: %v", v, err)
+		}
+		return string(b), nil
+	}
+}
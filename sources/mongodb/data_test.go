@@ -0,0 +1,62 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+func TestConvValue(t *testing.T) {
+	conv := internal.MakeConv()
+	at := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   interface{}
+		t    ddl.Type
+		want interface{}
+	}{
+		{ObjectId("5f1d7f3b9d1e8a0001a1b2c3"), ddl.Type{Name: ddl.String, Len: 24}, "5f1d7f3b9d1e8a0001a1b2c3"},
+		{ObjectId("0102"), ddl.Type{Name: ddl.Bytes}, []byte{1, 2}},
+		{int64(3), ddl.Type{Name: ddl.Int64}, int64(3)},
+		{float64(3), ddl.Type{Name: ddl.Int64}, int64(3)},
+		{int64(3), ddl.Type{Name: ddl.Float64}, float64(3)},
+		{int64(3), ddl.Type{Name: ddl.String}, "3"},
+		{true, ddl.Type{Name: ddl.Bool}, true},
+		{at, ddl.Type{Name: ddl.Timestamp}, at},
+		{at, ddl.Type{Name: ddl.String}, "2020-01-01T00:00:00Z"},
+		{[]byte{1, 2}, ddl.Type{Name: ddl.String}, "AQI="},
+		{map[string]interface{}{"a": []interface{}{int64(1), "b"}}, ddl.Type{Name: ddl.JSON}, `{"a":[1,"b"]}`},
+		{"x", ddl.Type{Name: ddl.JSON}, `"x"`},
+	}
+	for _, tc := range tests {
+		got, err := convValue(conv, tc.in, tc.t)
+		assert.Nil(t, err, tc.in)
+		assert.Equal(t, tc.want, got, tc.in)
+	}
+
+	got, err := convValue(conv, Decimal("12.5"), ddl.Type{Name: ddl.Numeric})
+	assert.Nil(t, err)
+	assert.Equal(t, "25/2", got.(interface{ String() string }).String())
+
+	for _, in := range []interface{}{2.5, "abc", true} {
+		_, err := convValue(conv, in, ddl.Type{Name: ddl.Int64})
+		assert.NotNil(t, err, in)
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Source types of document fields, named after the MongoDB $type aliases.
+// typeMixed is used for fields whose sampled values have conflicting types.
+const (
+	typeObjectId = "objectId"
+	typeString   = "string"
+	typeLong     = "long"
+	typeDouble   = "double"
+	typeDecimal  = "decimal"
+	typeBool     = "bool"
+	typeDate     = "date"
+	typeBinData  = "binData"
+	typeObject   = "object"
+	typeArray    = "array"
+	typeMixed    = "mixed"
+)
+
+// ObjectId is a MongoDB ObjectId, as its hex string.
+type ObjectId string
+
+// Decimal is a MongoDB Decimal128, as its decimal string.
+type Decimal string
+
+// readDocuments calls process for each of the first maxDocs documents of r,
+// or for all of them if maxDocs is negative. r holds one Extended JSON
+// document per line, as written by mongoexport. Blank lines are ignored.
+func readDocuments(r io.Reader, maxDocs int64, process func(doc map[string]interface{}) error) error {
+	br := bufio.NewReader(r)
+	var n int64
+	for line := 1; maxDocs < 0 || n < maxDocs; line++ {
+		b, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("can't read line %d: %v", line, err)
+		}
+		if len(bytes.TrimSpace(b)) > 0 {
+			doc, perr := parseDocument(b)
+			if perr != nil {
+				return fmt.Errorf("can't parse document at line %d: %v", line, perr)
+			}
+			if perr = process(doc); perr != nil {
+				return perr
+			}
+			n++
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+	return nil
+}
+
+// parseDocument parses an Extended JSON document, in either canonical or
+// relaxed mode, into Go values: see normalize.
+func parseDocument(b []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after document")
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("line is not a JSON object")
+	}
+	doc, err := normalize(m)
+	if err != nil {
+		return nil, err
+	}
+	return doc.(map[string]interface{}), nil
+}
+
+// normalize replaces the Extended JSON representations of BSON types in v by
+// Go values: ObjectId for $oid, time.Time for $date and $timestamp, int64
+// for $numberInt, $numberLong and integral numbers, float64 for $numberDouble
+// and other numbers, Decimal for $numberDecimal and []byte for $binary.
+// Other values are kept as decoded by encoding/json.
+func normalize(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case []interface{}:
+		for i := range v {
+			n, err := normalize(v[i])
+			if err != nil {
+				return nil, err
+			}
+			v[i] = n
+		}
+		return v, nil
+	case map[string]interface{}:
+		if n, ok, err := normalizeWrapper(v); ok || err != nil {
+			return n, err
+		}
+		for k := range v {
+			n, err := normalize(v[k])
+			if err != nil {
+				return nil, err
+			}
+			v[k] = n
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// normalizeWrapper converts m if it is the Extended JSON representation of a
+// BSON type, and reports whether it is.
+func normalizeWrapper(m map[string]interface{}) (interface{}, bool, error) {
+	if len(m) == 2 {
+		// Legacy binary: {"$binary": "<base64>", "$type": "<subtype>"}.
+		if s, ok := m["$binary"].(string); ok {
+			if _, ok := m["$type"]; ok {
+				b, err := base64.StdEncoding.DecodeString(s)
+				return b, true, err
+			}
+		}
+		return nil, false, nil
+	}
+	if len(m) != 1 {
+		return nil, false, nil
+	}
+	for k, v := range m {
+		switch k {
+		case "$oid":
+			s, ok := v.(string)
+			if !ok {
+				return nil, true, fmt.Errorf("invalid $oid %v", v)
+			}
+			return ObjectId(s), true, nil
+		case "$numberInt", "$numberLong":
+			s, ok := v.(string)
+			if !ok {
+				return nil, true, fmt.Errorf("invalid %s %v", k, v)
+			}
+			i, err := strconv.ParseInt(s, 10, 64)
+			return i, true, err
+		case "$numberDouble":
+			s, ok := v.(string)
+			if !ok {
+				return nil, true, fmt.Errorf("invalid $numberDouble %v", v)
+			}
+			f, err := strconv.ParseFloat(s, 64)
+			return f, true, err
+		case "$numberDecimal":
+			s, ok := v.(string)
+			if !ok {
+				return nil, true, fmt.Errorf("invalid $numberDecimal %v", v)
+			}
+			return Decimal(s), true, nil
+		case "$date":
+			t, err := parseDate(v)
+			return t, true, err
+		case "$timestamp":
+			ts, _ := v.(map[string]interface{})
+			t, ok := ts["t"].(json.Number)
+			if !ok {
+				return nil, true, fmt.Errorf("invalid $timestamp %v", v)
+			}
+			secs, err := t.Int64()
+			return time.Unix(secs, 0).UTC(), true, err
+		case "$binary":
+			bin, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, true, fmt.Errorf("invalid $binary %v", v)
+			}
+			s, _ := bin["base64"].(string)
+			b, err := base64.StdEncoding.DecodeString(s)
+			return b, true, err
+		}
+	}
+	return nil, false, nil
+}
+
+// parseDate parses the value of a $date: an ISO-8601 string in relaxed mode,
+// or milliseconds since the epoch in canonical mode, either as a number or as
+// a $numberLong.
+func parseDate(v interface{}) (time.Time, error) {
+	switch v := v.(type) {
+	case string:
+		return time.Parse(time.RFC3339Nano, v)
+	case json.Number:
+		ms, err := v.Int64()
+		return time.UnixMilli(ms).UTC(), err
+	case map[string]interface{}:
+		if s, ok := v["$numberLong"].(string); ok {
+			ms, err := strconv.ParseInt(s, 10, 64)
+			return time.UnixMilli(ms).UTC(), err
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid $date %v", v)
+}
+
+// typeOf returns the source type of a normalized value, or "" for null.
+func typeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return ""
+	case ObjectId:
+		return typeObjectId
+	case string:
+		return typeString
+	case int64:
+		return typeLong
+	case float64:
+		return typeDouble
+	case Decimal:
+		return typeDecimal
+	case bool:
+		return typeBool
+	case time.Time:
+		return typeDate
+	case []byte:
+		return typeBinData
+	case []interface{}:
+		return typeArray
+	default:
+		return typeObject
+	}
+}
+
+// isDocumentArray reports whether v is an array whose elements are all
+// embedded documents.
+func isDocumentArray(v interface{}) bool {
+	arr, ok := v.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, e := range arr {
+		if _, ok := e.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDocument(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want map[string]interface{}
+	}{
+		{
+			name: "canonical",
+			in:   `{"_id": {"$oid": "5f1d7f3b9d1e8a0001a1b2c3"}, "n": {"$numberInt": "7"}, "l": {"$numberLong": "9007199254740993"}, "d": {"$numberDouble": "1.5"}, "dec": {"$numberDecimal": "12.345"}, "at": {"$date": {"$numberLong": "1577836800000"}}, "bin": {"$binary": {"base64": "AQI=", "subType": "00"}}}`,
+			want: map[string]interface{}{
+				"_id": ObjectId("5f1d7f3b9d1e8a0001a1b2c3"),
+				"n":   int64(7),
+				"l":   int64(9007199254740993),
+				"d":   1.5,
+				"dec": Decimal("12.345"),
+				"at":  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				"bin": []byte{1, 2},
+			},
+		},
+		{
+			name: "relaxed",
+			in:   `{"_id": 1, "name": "a", "price": 2.5, "ok": true, "at": {"$date": "2020-01-01T00:00:00Z"}, "tags": ["x", {"n": 2}], "meta": {"ts": {"$timestamp": {"t": 1577836800, "i": 1}}}, "none": null}`,
+			want: map[string]interface{}{
+				"_id":   int64(1),
+				"name":  "a",
+				"price": 2.5,
+				"ok":    true,
+				"at":    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				"tags":  []interface{}{"x", map[string]interface{}{"n": int64(2)}},
+				"meta":  map[string]interface{}{"ts": time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+				"none":  nil,
+			},
+		},
+		{
+			name: "legacy binary",
+			in:   `{"_id": "k", "bin": {"$binary": "AQI=", "$type": "00"}}`,
+			want: map[string]interface{}{"_id": "k", "bin": []byte{1, 2}},
+		},
+	}
+	for _, tc := range tests {
+		doc, err := parseDocument([]byte(tc.in))
+		assert.Nil(t, err, tc.name)
+		assert.Equal(t, tc.want, doc, tc.name)
+	}
+
+	for _, in := range []string{`[1, 2]`, `{"a": 1} {"b": 2}`, `{"a": {"$oid": 1}}`, `{"a": {"$date": "yesterday"}}`} {
+		_, err := parseDocument([]byte(in))
+		assert.NotNil(t, err, in)
+	}
+}
+
+func TestReadDocuments(t *testing.T) {
+	in := "{\"_id\": 1}\n\n{\"_id\": 2}\n{\"_id\": 3}"
+	var ids []interface{}
+	err := readDocuments(strings.NewReader(in), -1, func(doc map[string]interface{}) error {
+		ids = append(ids, doc["_id"])
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{int64(1), int64(2), int64(3)}, ids)
+
+	ids = nil
+	err = readDocuments(strings.NewReader(in), 2, func(doc map[string]interface{}) error {
+		ids = append(ids, doc["_id"])
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{int64(1), int64(2)}, ids)
+
+	err = readDocuments(strings.NewReader("{\"_id\": 1}\n{\"_id\": "), -1, func(doc map[string]interface{}) error { return nil })
+	assert.Contains(t, err.Error(), "can't parse document at line 2")
+}
@@ -0,0 +1,446 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	sp "cloud.google.com/go/spanner"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+const (
+	// idCol is the primary key of a collection's table. In the tables of
+	// arrays of embedded documents, it holds the _id of the parent document.
+	idCol = "_id"
+	// idxCol is the position of an embedded document in its array.
+	idxCol = "_idx"
+	// residualCol holds, as a JSON document, the fields that aren't mapped to
+	// any other column.
+	residualCol = "_residual"
+
+	exportFileExt = ".json"
+)
+
+var errNotSupported = fmt.Errorf("operation not supported for MongoDB exports")
+
+// InfoSchemaImpl reads MongoDB collections from the files written by
+// mongoexport, one <collection>.json file per collection in ExportDir.
+//
+// Each collection maps to a table with a column per top-level field seen in
+// the sampled documents, keyed by _id. Fields whose values are arrays of
+// embedded documents map instead to a table named <collection>.<field>,
+// interleaved in the collection's table and keyed by (_id, _idx), with a
+// column per field of the embedded documents. Fields of a document that are
+// not migrated to any column or table are kept in the _residual JSON column.
+type InfoSchemaImpl struct {
+	ExportDir  string
+	SampleSize int64
+	samples    map[string]*tableSample
+	mutex      *sync.Mutex
+}
+
+// tableSample holds the types of the field values of a table's sampled rows.
+type tableSample struct {
+	collection string
+	// field is the array of embedded documents that the table holds, or empty
+	// for the collection's own table.
+	field string
+	rows  int64
+	types map[string]map[string]int64
+}
+
+func NewInfoSchemaImpl(exportDir string, sampleSize int64) InfoSchemaImpl {
+	return InfoSchemaImpl{
+		ExportDir:  exportDir,
+		SampleSize: sampleSize,
+		samples:    make(map[string]*tableSample),
+		mutex:      &sync.Mutex{},
+	}
+}
+
+func (isi InfoSchemaImpl) GetToDdl() common.ToDdl {
+	return common.LookupToDdl(constants.MONGODB, ToDdlImpl{})
+}
+
+func (isi InfoSchemaImpl) GetTableName(schema string, tableName string) string {
+	return tableName
+}
+
+// GetTables returns a table per collection and per array of embedded
+// documents found by sampling the collection.
+func (isi InfoSchemaImpl) GetTables() ([]common.SchemaAndName, error) {
+	collections, err := isi.listCollections()
+	if err != nil {
+		return nil, err
+	}
+	isi.mutex.Lock()
+	defer isi.mutex.Unlock()
+	var tables []common.SchemaAndName
+	for _, c := range collections {
+		if _, ok := isi.samples[c]; !ok {
+			samples, err := isi.sampleCollection(c)
+			if err != nil {
+				return nil, err
+			}
+			for _, s := range samples {
+				isi.samples[s.tableName()] = s
+			}
+		}
+		tables = append(tables, common.SchemaAndName{Schema: c, Name: c})
+		var children []string
+		for name, s := range isi.samples {
+			if s.collection == c && s.field != "" {
+				children = append(children, name)
+			}
+		}
+		sort.Strings(children)
+		for _, name := range children {
+			tables = append(tables, common.SchemaAndName{Schema: c, Name: name})
+		}
+	}
+	return tables, nil
+}
+
+func (isi InfoSchemaImpl) GetColumns(conv *internal.Conv, table common.SchemaAndName, constraints map[string][]string, primaryKeys []string) (map[string]schema.Column, []string, error) {
+	s, idType, err := isi.getSample(table)
+	if err != nil {
+		return nil, nil, err
+	}
+	colDefs := make(map[string]schema.Column)
+	var colIds []string
+	addColumn := func(name, ty string, notNull bool) {
+		colId := internal.GenerateColumnId()
+		colDefs[colId] = schema.Column{Id: colId, Name: name, Type: schema.Type{Name: ty}, NotNull: notNull}
+		colIds = append(colIds, colId)
+	}
+	addColumn(idCol, idType, true)
+	if s.field != "" {
+		addColumn(idxCol, typeLong, true)
+	}
+	var fields []string
+	for f := range s.types {
+		if f != idCol {
+			fields = append(fields, f)
+		}
+	}
+	sort.Strings(fields)
+	for _, f := range fields {
+		addColumn(f, inferType(s.types[f]), false)
+	}
+	addColumn(residualCol, typeObject, false)
+	return colDefs, colIds, nil
+}
+
+// GetRowsFromTable returns a reader of the export file of the table's
+// collection, which the caller must close.
+func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string) (interface{}, error) {
+	return os.Open(isi.exportFile(conv.SrcSchema[tableId].Schema))
+}
+
+// GetRowCount returns the number of documents of a collection, or of embedded
+// documents of an array field.
+func (isi InfoSchemaImpl) GetRowCount(table common.SchemaAndName) (int64, error) {
+	f, err := os.Open(isi.exportFile(table.Schema))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	field := childField(table.Schema, table.Name)
+	var count int64
+	err = readDocuments(f, -1, func(doc map[string]interface{}) error {
+		if field == "" {
+			count++
+		} else if isDocumentArray(doc[field]) {
+			count += int64(len(doc[field].([]interface{})))
+		}
+		return nil
+	})
+	return count, err
+}
+
+func (isi InfoSchemaImpl) GetConstraints(conv *internal.Conv, table common.SchemaAndName) (primaryKeys []string, checkConstraints []schema.CheckConstraint, constraints map[string][]string, err error) {
+	if childField(table.Schema, table.Name) != "" {
+		return []string{idCol, idxCol}, nil, nil, nil
+	}
+	return []string{idCol}, nil, nil, nil
+}
+
+// GetForeignKeys returns no foreign keys: the tables of arrays of embedded
+// documents are interleaved in their collection's table instead, see
+// InterleaveChildTables.
+func (isi InfoSchemaImpl) GetForeignKeys(conv *internal.Conv, table common.SchemaAndName) (foreignKeys []schema.ForeignKey, err error) {
+	return foreignKeys, err
+}
+
+// GetIndexes returns no indexes, since mongoexport doesn't export them.
+func (isi InfoSchemaImpl) GetIndexes(conv *internal.Conv, table common.SchemaAndName, colNameIdMap map[string]string) (indexes []schema.Index, err error) {
+	return indexes, err
+}
+
+// ProcessData performs data conversion for a MongoDB collection, or for an
+// array of embedded documents of a collection, reading the collection's
+// export file. The mapping of fields to columns follows the current source
+// and Spanner schemas, so that changes made to them, e.g. in the web UI, are
+// honored: fields of dropped columns or tables end up in the _residual column.
+func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, colIds []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
+	rows, err := isi.GetRowsFromTable(conv, tableId)
+	if err != nil {
+		conv.Unexpected(fmt.Sprintf("Couldn't get data for table %s : err = %s", srcSchema.Name, err))
+		return err
+	}
+	f := rows.(io.ReadCloser)
+	defer f.Close()
+
+	field := childField(srcSchema.Schema, srcSchema.Name)
+	migratedFields := migratedChildFields(conv, srcSchema.Schema)
+	err = readDocuments(f, -1, func(doc map[string]interface{}) error {
+		if field == "" {
+			ProcessDataRow(conv, tableId, documentRow(doc, nil, migratedFields, srcSchema, colIds), srcSchema, colIds, spSchema)
+			return nil
+		}
+		if !isDocumentArray(doc[field]) {
+			if doc[field] != nil {
+				conv.Unexpected(fmt.Sprintf("Field %s of document %v of collection %s is not an array of embedded documents", field, doc[idCol], srcSchema.Schema))
+			}
+			return nil
+		}
+		for i, e := range doc[field].([]interface{}) {
+			keys := map[string]interface{}{idCol: doc[idCol], idxCol: int64(i)}
+			ProcessDataRow(conv, tableId, documentRow(e.(map[string]interface{}), keys, nil, srcSchema, colIds), srcSchema, colIds, spSchema)
+		}
+		return nil
+	})
+	if err != nil {
+		conv.Unexpected(fmt.Sprintf("Couldn't read data for table %s : err = %s", srcSchema.Name, err))
+	}
+	return err
+}
+
+func (isi InfoSchemaImpl) StartChangeDataCapture(ctx context.Context, conv *internal.Conv) (map[string]interface{}, error) {
+	return nil, errNotSupported
+}
+
+func (isi InfoSchemaImpl) StartStreamingMigration(ctx context.Context, migrationProjectId string, client *sp.Client, conv *internal.Conv, streamInfo map[string]interface{}) (internal.DataflowOutput, error) {
+	return internal.DataflowOutput{}, errNotSupported
+}
+
+// InterleaveChildTables interleaves the Spanner tables of arrays of embedded
+// documents in the tables of their collections, so that embedded documents
+// are stored and deleted with their parent documents.
+func InterleaveChildTables(conv *internal.Conv) {
+	parentIds := make(map[string]string)
+	for id, t := range conv.SrcSchema {
+		if t.Name == t.Schema {
+			parentIds[t.Schema] = id
+		}
+	}
+	for id, t := range conv.SrcSchema {
+		if childField(t.Schema, t.Name) == "" {
+			continue
+		}
+		parentId, ok := parentIds[t.Schema]
+		spTable, childOk := conv.SpSchema[id]
+		if _, parentOk := conv.SpSchema[parentId]; !ok || !childOk || !parentOk {
+			continue
+		}
+		spTable.ParentTable = ddl.InterleavedParent{Id: parentId, OnDelete: constants.FK_CASCADE, InterleaveType: "IN PARENT"}
+		conv.SpSchema[id] = spTable
+	}
+}
+
+func (isi InfoSchemaImpl) listCollections() ([]string, error) {
+	entries, err := os.ReadDir(isi.ExportDir)
+	if err != nil {
+		return nil, fmt.Errorf("can't read directory %s of the mongoexport files: %v", isi.ExportDir, err)
+	}
+	var collections []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), exportFileExt) {
+			collections = append(collections, strings.TrimSuffix(e.Name(), exportFileExt))
+		}
+	}
+	if len(collections) == 0 {
+		return nil, fmt.Errorf("no mongoexport files (<collection>%s) found in directory %s", exportFileExt, isi.ExportDir)
+	}
+	return collections, nil
+}
+
+func (isi InfoSchemaImpl) exportFile(collection string) string {
+	return filepath.Join(isi.ExportDir, collection+exportFileExt)
+}
+
+// getSample returns the sample of a table, along with the type of the _id
+// of its collection.
+func (isi InfoSchemaImpl) getSample(table common.SchemaAndName) (*tableSample, string, error) {
+	isi.mutex.Lock()
+	defer isi.mutex.Unlock()
+	s, ok := isi.samples[table.Name]
+	root, rootOk := isi.samples[table.Schema]
+	if !ok || !rootOk {
+		return nil, "", fmt.Errorf("collection %s wasn't sampled", table.Name)
+	}
+	idType := typeObjectId
+	if types, ok := root.types[idCol]; ok {
+		idType = inferType(types)
+	}
+	return s, idType, nil
+}
+
+func (isi InfoSchemaImpl) sampleCollection(collection string) ([]*tableSample, error) {
+	f, err := os.Open(isi.exportFile(collection))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	logger.Log.Info(fmt.Sprintf("sampling up to %d documents of collection %s", isi.SampleSize, collection))
+	samples, err := sampleDocuments(f, collection, isi.SampleSize)
+	if err != nil {
+		return nil, fmt.Errorf("can't sample collection %s: %v", collection, err)
+	}
+	return samples, nil
+}
+
+// sampleDocuments samples up to sampleSize documents of a collection. It
+// returns the sample of the collection's table, followed by those of the
+// tables of its fields that are arrays of embedded documents in all of the
+// sampled documents where they are not null.
+func sampleDocuments(r io.Reader, collection string, sampleSize int64) ([]*tableSample, error) {
+	root := newTableSample(collection, "")
+	children := make(map[string]*tableSample)
+	notChildren := make(map[string]bool)
+	err := readDocuments(r, sampleSize, func(doc map[string]interface{}) error {
+		root.rows++
+		for f, v := range doc {
+			if f == residualCol {
+				continue
+			}
+			root.add(f, v)
+			if v == nil {
+				continue
+			}
+			if f == idCol || !isDocumentArray(v) {
+				notChildren[f] = true
+				continue
+			}
+			for _, e := range v.([]interface{}) {
+				child, ok := children[f]
+				if !ok {
+					child = newTableSample(collection, f)
+					children[f] = child
+				}
+				child.rows++
+				for ef, ev := range e.(map[string]interface{}) {
+					// These fields would clash with the columns of the table
+					// and are kept in the residual.
+					if ef != idCol && ef != idxCol && ef != residualCol {
+						child.add(ef, ev)
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	samples := []*tableSample{root}
+	var fields []string
+	for f := range children {
+		if !notChildren[f] {
+			fields = append(fields, f)
+		}
+	}
+	sort.Strings(fields)
+	for _, f := range fields {
+		delete(root.types, f)
+		samples = append(samples, children[f])
+	}
+	return samples, nil
+}
+
+func newTableSample(collection, field string) *tableSample {
+	return &tableSample{collection: collection, field: field, types: make(map[string]map[string]int64)}
+}
+
+func (s *tableSample) tableName() string {
+	if s.field == "" {
+		return s.collection
+	}
+	return s.collection + "." + s.field
+}
+
+func (s *tableSample) add(field string, v interface{}) {
+	if _, ok := s.types[field]; !ok {
+		s.types[field] = make(map[string]int64)
+	}
+	if t := typeOf(v); t != "" {
+		s.types[field][t]++
+	}
+}
+
+// inferType returns the type of a field from the counts of the types of its
+// sampled values: their single type, double for a mix of longs and doubles,
+// and mixed otherwise.
+func inferType(counts map[string]int64) string {
+	switch {
+	case len(counts) == 1:
+		for t := range counts {
+			return t
+		}
+	case len(counts) == 2 && counts[typeLong] > 0 && counts[typeDouble] > 0:
+		return typeDouble
+	}
+	return typeMixed
+}
+
+// childField returns the array field of the documents of a collection that a
+// table holds, or empty if the table is the collection's own table.
+func childField(collection, tableName string) string {
+	if tableName == collection {
+		return ""
+	}
+	return strings.TrimPrefix(tableName, collection+".")
+}
+
+// migratedChildFields returns the array fields of a collection whose tables
+// are migrated to Spanner.
+func migratedChildFields(conv *internal.Conv, collection string) map[string]bool {
+	fields := make(map[string]bool)
+	for id, t := range conv.SrcSchema {
+		if t.Schema != collection {
+			continue
+		}
+		if f := childField(t.Schema, t.Name); f != "" {
+			if _, ok := conv.SpSchema[id]; ok {
+				fields[f] = true
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+func init() {
+	logger.Log = zap.NewNop()
+}
+
+const ordersExport = `{"_id": {"$oid": "5f1d7f3b9d1e8a0001a1b2c3"}, "customer": "alice", "total": 10, "items": [{"sku": "a", "qty": 1}, {"sku": "b", "qty": 2, "note": "gift"}], "tags": ["x"]}
+{"_id": {"$oid": "5f1d7f3b9d1e8a0001a1b2c4"}, "customer": "bob", "total": 2.5, "items": [], "extra": {"k": 1}}
+`
+
+func writeExport(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	return dir
+}
+
+type spannerData struct {
+	table string
+	cols  []string
+	vals  []interface{}
+}
+
+func TestInfoSchemaImpl_GetTables(t *testing.T) {
+	dir := writeExport(t, map[string]string{"orders.json": ordersExport, "users.json": `{"_id": 1}`, "notes.txt": "skipped"})
+	isi := NewInfoSchemaImpl(dir, 100)
+	tables, err := isi.GetTables()
+	assert.Nil(t, err)
+	assert.Equal(t, []common.SchemaAndName{
+		{Schema: "orders", Name: "orders"},
+		{Schema: "orders", Name: "orders.items"},
+		{Schema: "users", Name: "users"},
+	}, tables)
+
+	_, err = NewInfoSchemaImpl(t.TempDir(), 100).GetTables()
+	assert.NotNil(t, err)
+}
+
+func TestInfoSchemaImpl_GetColumns(t *testing.T) {
+	dir := writeExport(t, map[string]string{"orders.json": ordersExport})
+	isi := NewInfoSchemaImpl(dir, 100)
+	_, err := isi.GetTables()
+	assert.Nil(t, err)
+
+	columns := func(table common.SchemaAndName) []schema.Column {
+		colDefs, colIds, err := isi.GetColumns(internal.MakeConv(), table, nil, nil)
+		assert.Nil(t, err)
+		var cols []schema.Column
+		for _, id := range colIds {
+			c := colDefs[id]
+			c.Id = ""
+			cols = append(cols, c)
+		}
+		return cols
+	}
+	assert.Equal(t, []schema.Column{
+		{Name: "_id", Type: schema.Type{Name: typeObjectId}, NotNull: true},
+		{Name: "customer", Type: schema.Type{Name: typeString}},
+		{Name: "extra", Type: schema.Type{Name: typeObject}},
+		{Name: "tags", Type: schema.Type{Name: typeArray}},
+		{Name: "total", Type: schema.Type{Name: typeDouble}},
+		{Name: "_residual", Type: schema.Type{Name: typeObject}},
+	}, columns(common.SchemaAndName{Schema: "orders", Name: "orders"}))
+	assert.Equal(t, []schema.Column{
+		{Name: "_id", Type: schema.Type{Name: typeObjectId}, NotNull: true},
+		{Name: "_idx", Type: schema.Type{Name: typeLong}, NotNull: true},
+		{Name: "note", Type: schema.Type{Name: typeString}},
+		{Name: "qty", Type: schema.Type{Name: typeLong}},
+		{Name: "sku", Type: schema.Type{Name: typeString}},
+		{Name: "_residual", Type: schema.Type{Name: typeObject}},
+	}, columns(common.SchemaAndName{Schema: "orders", Name: "orders.items"}))
+
+	pks, _, _, err := isi.GetConstraints(internal.MakeConv(), common.SchemaAndName{Schema: "orders", Name: "orders.items"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"_id", "_idx"}, pks)
+
+	count, err := isi.GetRowCount(common.SchemaAndName{Schema: "orders", Name: "orders.items"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestSampleDocuments(t *testing.T) {
+	// "a" has a document that isn't an array of embedded documents, so it
+	// stays a column of the collection's table.
+	in := `{"_id": 1, "a": [{"x": 1}], "b": [{"_id": 5, "y": true}], "c": null}
+{"_id": 2, "a": "text", "b": null}`
+	samples, err := sampleDocuments(strings.NewReader(in), "c", 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(samples))
+	assert.Equal(t, int64(2), samples[0].rows)
+	assert.Equal(t, typeMixed, inferType(samples[0].types["a"]))
+	assert.Equal(t, typeMixed, inferType(samples[0].types["c"]))
+	_, ok := samples[0].types["b"]
+	assert.False(t, ok)
+	assert.Equal(t, "c.b", samples[1].tableName())
+	assert.Equal(t, map[string]map[string]int64{"y": {typeBool: 1}}, samples[1].types)
+}
+
+func TestInterleaveChildTables(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SrcSchema = map[string]schema.Table{
+		"t1": {Id: "t1", Schema: "orders", Name: "orders"},
+		"t2": {Id: "t2", Schema: "orders", Name: "orders.items"},
+		"t3": {Id: "t3", Schema: "users", Name: "users"},
+	}
+	conv.SpSchema = map[string]ddl.CreateTable{
+		"t1": {Id: "t1", Name: "orders"},
+		"t2": {Id: "t2", Name: "orders_items"},
+		"t3": {Id: "t3", Name: "users"},
+	}
+	InterleaveChildTables(conv)
+	assert.Equal(t, ddl.InterleavedParent{Id: "t1", OnDelete: constants.FK_CASCADE, InterleaveType: "IN PARENT"}, conv.SpSchema["t2"].ParentTable)
+	assert.Equal(t, ddl.InterleavedParent{}, conv.SpSchema["t1"].ParentTable)
+	assert.Equal(t, ddl.InterleavedParent{}, conv.SpSchema["t3"].ParentTable)
+}
+
+func TestInfoSchemaImpl_ProcessData(t *testing.T) {
+	dir := writeExport(t, map[string]string{"orders.json": ordersExport})
+	isi := NewInfoSchemaImpl(dir, 100)
+	conv := internal.MakeConv()
+	conv.SrcSchema = map[string]schema.Table{
+		"t1": {Id: "t1", Schema: "orders", Name: "orders", ColIds: []string{"c1", "c2", "c3", "c4"}, ColDefs: map[string]schema.Column{
+			"c1": {Id: "c1", Name: "_id", Type: schema.Type{Name: typeObjectId}},
+			"c2": {Id: "c2", Name: "customer", Type: schema.Type{Name: typeString}},
+			"c3": {Id: "c3", Name: "total", Type: schema.Type{Name: typeDouble}},
+			"c4": {Id: "c4", Name: "_residual", Type: schema.Type{Name: typeObject}},
+		}},
+		"t2": {Id: "t2", Schema: "orders", Name: "orders.items", ColIds: []string{"c5", "c6", "c7", "c8"}, ColDefs: map[string]schema.Column{
+			"c5": {Id: "c5", Name: "_id", Type: schema.Type{Name: typeObjectId}},
+			"c6": {Id: "c6", Name: "_idx", Type: schema.Type{Name: typeLong}},
+			"c7": {Id: "c7", Name: "sku", Type: schema.Type{Name: typeString}},
+			"c8": {Id: "c8", Name: "_residual", Type: schema.Type{Name: typeObject}},
+		}},
+	}
+	conv.SpSchema = map[string]ddl.CreateTable{
+		"t1": {Id: "t1", Name: "orders", ColIds: []string{"c1", "c2", "c3", "c4"}, ColDefs: map[string]ddl.ColumnDef{
+			"c1": {Id: "c1", Name: "Aid", T: ddl.Type{Name: ddl.String, Len: 24}},
+			"c2": {Id: "c2", Name: "customer", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+			"c3": {Id: "c3", Name: "total", T: ddl.Type{Name: ddl.Float64}},
+			"c4": {Id: "c4", Name: "Aresidual", T: ddl.Type{Name: ddl.JSON}},
+		}},
+		"t2": {Id: "t2", Name: "orders_items", ColIds: []string{"c5", "c6", "c7", "c8"}, ColDefs: map[string]ddl.ColumnDef{
+			"c5": {Id: "c5", Name: "Aid", T: ddl.Type{Name: ddl.String, Len: 24}},
+			"c6": {Id: "c6", Name: "Aidx", T: ddl.Type{Name: ddl.Int64}},
+			"c7": {Id: "c7", Name: "sku", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+			"c8": {Id: "c8", Name: "Aresidual", T: ddl.Type{Name: ddl.JSON}},
+		}},
+	}
+	var rows []spannerData
+	conv.SetDataMode()
+	conv.SetDataSink(
+		func(table string, cols []string, vals []interface{}) {
+			rows = append(rows, spannerData{table: table, cols: cols, vals: vals})
+		})
+
+	err := isi.ProcessData(conv, "t1", conv.SrcSchema["t1"], conv.SrcSchema["t1"].ColIds, conv.SpSchema["t1"], internal.AdditionalDataAttributes{})
+	assert.Nil(t, err)
+	err = isi.ProcessData(conv, "t2", conv.SrcSchema["t2"], conv.SrcSchema["t2"].ColIds, conv.SpSchema["t2"], internal.AdditionalDataAttributes{})
+	assert.Nil(t, err)
+	assert.Equal(t, []spannerData{
+		// items are migrated to their own table, tags and extra have no column.
+		{table: "orders", cols: []string{"Aid", "customer", "total", "Aresidual"}, vals: []interface{}{"5f1d7f3b9d1e8a0001a1b2c3", "alice", float64(10), `{"tags":["x"]}`}},
+		{table: "orders", cols: []string{"Aid", "customer", "total", "Aresidual"}, vals: []interface{}{"5f1d7f3b9d1e8a0001a1b2c4", "bob", 2.5, `{"extra":{"k":1}}`}},
+		{table: "orders_items", cols: []string{"Aid", "Aidx", "sku", "Aresidual"}, vals: []interface{}{"5f1d7f3b9d1e8a0001a1b2c3", int64(0), "a", `{"qty":1}`}},
+		{table: "orders_items", cols: []string{"Aid", "Aidx", "sku", "Aresidual"}, vals: []interface{}{"5f1d7f3b9d1e8a0001a1b2c3", int64(1), "b", `{"note":"gift","qty":2}`}},
+	}, rows)
+	assert.Equal(t, int64(0), conv.Unexpecteds())
+
+	// Once the items table is dropped, items are kept in the residual.
+	delete(conv.SpSchema, "t2")
+	rows = nil
+	err = isi.ProcessData(conv, "t1", conv.SrcSchema["t1"], []string{"c1", "c4"}, conv.SpSchema["t1"], internal.AdditionalDataAttributes{})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"customer":"bob","extra":{"k":1},"items":[],"total":2.5}`, rows[1].vals[1])
+}
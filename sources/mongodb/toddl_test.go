@@ -0,0 +1,51 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+func TestToSpannerType(t *testing.T) {
+	conv := internal.MakeConv()
+	tests := []struct {
+		srcType string
+		spType  string
+		want    ddl.Type
+	}{
+		{typeObjectId, "", ddl.Type{Name: ddl.String, Len: 24}},
+		{typeObjectId, ddl.Bytes, ddl.Type{Name: ddl.Bytes, Len: 12}},
+		{typeString, "", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+		{typeLong, "", ddl.Type{Name: ddl.Int64}},
+		{typeDouble, "", ddl.Type{Name: ddl.Float64}},
+		{typeDecimal, "", ddl.Type{Name: ddl.Numeric}},
+		{typeBool, "", ddl.Type{Name: ddl.Bool}},
+		{typeDate, "", ddl.Type{Name: ddl.Timestamp}},
+		{typeBinData, "", ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}},
+		{typeObject, "", ddl.Type{Name: ddl.JSON}},
+		{typeArray, "", ddl.Type{Name: ddl.JSON}},
+		{typeMixed, ddl.String, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+	}
+	for _, tc := range tests {
+		ty, _ := ToDdlImpl{}.ToSpannerType(conv, tc.spType, schema.Type{Name: tc.srcType}, false)
+		assert.Equal(t, tc.want, ty, tc.srcType)
+	}
+}
@@ -16,8 +16,8 @@ package mysql
 
 import (
 	"fmt"
+	"math"
 	"math/big"
-	"math/bits"
 	"strconv"
 	"strings"
 	"time"
@@ -44,7 +44,7 @@ func ProcessDataRow(conv *internal.Conv, tableId string, colIds []string, srcSch
 	if err != nil {
 		conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
 		conv.StatsAddBadRow(srcTableName, conv.DataMode())
-		conv.CollectBadRow(srcTableName, srcCols, vals)
+		conv.CollectBadRowWithReason(srcTableName, srcCols, vals, err.Error())
 	} else {
 		conv.WriteRow(srcTableName, spTableName, cvtCols, cvtVals)
 	}
@@ -60,6 +60,18 @@ func ConvertData(conv *internal.Conv, tableId string, colIds []string, srcSchema
 	if len(colIds) != len(vals) {
 		return "", []string{}, []interface{}{}, fmt.Errorf("ConvertData: colIds and vals don't all have the same lengths: len(colIds)=%d, len(vals)=%d", len(colIds), len(vals))
 	}
+	colIds, vals, transformedCols, transformedVals, err := internal.ApplyColumnTransforms(conv, tableId, colIds, vals, func(s string) (string, bool) {
+		return s, s != "<nil>" && s != "NULL"
+	})
+	if err != nil {
+		return "", []string{}, []interface{}{}, err
+	}
+	// Time zone of the source session, in which TIMESTAMP values are read,
+	// also used to interpret DATETIME values with the source timezone policy.
+	srcLoc := additionalAttributes.Location
+	if srcLoc == nil {
+		srcLoc, _ = internal.ParseTimezone(conv.TimezoneOffset)
+	}
 	for i, colId := range colIds {
 		// Skip columns with 'NULL' values. When processing data rows from mysqldump, these values
 		// are represented as nil (by pingcap/tidb/types/parser_driver's ValueExpr), which is
@@ -80,20 +92,27 @@ func ConvertData(conv *internal.Conv, tableId string, colIds []string, srcSchema
 		var err error
 		if spColDef.T.IsArray {
 			x, err = convArray(spColDef.T, srcColDef.Type.Name, vals[i])
+		} else if loc := conv.TimestampLocation(tableId, colId, srcLoc); loc != nil && spColDef.T.Name == ddl.Timestamp && srcColDef.Type.Name != "timestamp" {
+			x, err = convLocalTimestamp(conv, srcSchema.Name, srcColDef.Type.Name, loc, vals[i])
 		} else {
-			x, err = convScalar(conv, spColDef.T, srcColDef.Type.Name, conv.TimezoneOffset, vals[i])
+			x, err = convScalar(conv, spColDef.T, srcColDef.Type.Name, srcLoc, vals[i])
 		}
 		if err != nil {
 			return "", []string{}, []interface{}{}, err
 		}
+		if r, ok := x.(*big.Rat); ok {
+			if err := conv.CheckNumericPrecision(srcSchema.Name, srcColDef.Name, r); err != nil {
+				return "", []string{}, []interface{}{}, err
+			}
+		}
 		v = append(v, x)
 		c = append(c, spCol)
 	}
-	if aux, ok := conv.SyntheticPKeys[tableId]; ok {
-		c = append(c, conv.SpSchema[tableId].ColDefs[aux.ColId].Name)
-		v = append(v, fmt.Sprintf("%d", int64(bits.Reverse64(uint64(aux.Sequence)))))
-		aux.Sequence++
-		conv.SyntheticPKeys[tableId] = aux
+	c = append(c, transformedCols...)
+	v = append(v, transformedVals...)
+	if synthCol, synthVal, ok := conv.NextSyntheticPKeyValue(tableId); ok {
+		c = append(c, synthCol)
+		v = append(v, synthVal)
 	}
 	colId := conv.SpSchema[tableId].ShardIdColumn
 	if colId != "" {
@@ -107,7 +126,7 @@ func ConvertData(conv *internal.Conv, tableId string, colIds []string, srcSchema
 // appropriate Spanner value. It is the caller's responsibility to
 // detect and handle NULL values: convScalar will return error if a
 // NULL value is passed.
-func convScalar(conv *internal.Conv, spannerType ddl.Type, srcTypeName string, TimezoneOffset string, val string) (interface{}, error) {
+func convScalar(conv *internal.Conv, spannerType ddl.Type, srcTypeName string, location *time.Location, val string) (interface{}, error) {
 	// Whitespace within the val string is considered part of the data value.
 	// Note that many of the underlying conversions functions we use (like
 	// strconv.ParseFloat and strconv.ParseInt) return "invalid syntax"
@@ -125,13 +144,19 @@ func convScalar(conv *internal.Conv, spannerType ddl.Type, srcTypeName string, T
 	case ddl.Float64:
 		return convFloat64(val)
 	case ddl.Int64:
+		switch srcTypeName {
+		case "time":
+			return convTimeToSeconds(val)
+		case "bit":
+			return convBitToInt64(val)
+		}
 		return convInt64(val)
 	case ddl.Numeric:
 		return convNumeric(conv, val)
 	case ddl.String:
 		return val, nil
 	case ddl.Timestamp:
-		return convTimestamp(srcTypeName, TimezoneOffset, val)
+		return convTimestamp(srcTypeName, location, val)
 	case ddl.JSON:
 		return val, nil
 	default:
@@ -210,6 +235,51 @@ func convInt64(val string) (int64, error) {
 	return i, err
 }
 
+// convTimeToSeconds maps a MySQL TIME value, e.g. "-838:59:59", to the
+// number of seconds it represents. Values with fractional seconds are
+// rejected since they can't be represented exactly.
+func convTimeToSeconds(val string) (int64, error) {
+	s, neg := strings.CutPrefix(val, "-")
+	s, frac, _ := strings.Cut(s, ".")
+	if strings.TrimRight(frac, "0") != "" {
+		return 0, fmt.Errorf("can't convert %q to seconds without losing its fractional seconds", val)
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("can't convert %q to seconds: expected hh:mm:ss", val)
+	}
+	var hms [3]int64
+	for i, p := range parts {
+		n, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, fmt.Errorf("can't convert %q to seconds: invalid time", val)
+		}
+		hms[i] = n
+	}
+	secs := hms[0]*3600 + hms[1]*60 + hms[2]
+	if neg {
+		secs = -secs
+	}
+	return secs, nil
+}
+
+// convBitToInt64 maps a MySQL BIT value, i.e. its bits packed in big-endian
+// bytes, to the unsigned number it represents, e.g. "\x01\x02" is 258.
+// BIT(64) values which don't fit in an INT64 are rejected.
+func convBitToInt64(val string) (int64, error) {
+	if len(val) > 8 {
+		return 0, fmt.Errorf("can't convert bit value of %d bytes to int64", len(val))
+	}
+	var u uint64
+	for i := 0; i < len(val); i++ {
+		u = u<<8 | uint64(val[i])
+	}
+	if u > math.MaxInt64 {
+		return 0, fmt.Errorf("can't convert bit value %d to int64: out of range", u)
+	}
+	return int64(u), nil
+}
+
 // convNumeric maps a source database string value (representing a numeric)
 // into a string representing a valid Spanner numeric.
 func convNumeric(conv *internal.Conv, val string) (interface{}, error) {
@@ -226,21 +296,20 @@ func convNumeric(conv *internal.Conv, val string) (interface{}, error) {
 
 // convTimestamp maps a source DB timestamp into a go Time Spanner timestamp
 // It handles both datetime and timestamp conversions.
-func convTimestamp(srcTypeName string, TimezoneOffset string, val string) (t time.Time, err error) {
+func convTimestamp(srcTypeName string, location *time.Location, val string) (t time.Time, err error) {
 	// mysqldump outputs timestamps as ISO 8601, except
 	// it uses space instead of T.
 	if srcTypeName == "timestamp" {
 		// We consider timezone for timestamp datatype.
 		// If timezone is not specified in mysqldump, we consider UTC time.
-		if TimezoneOffset == "" {
-			TimezoneOffset = "+00:00"
+		if location == nil {
+			location = time.UTC
 		}
-		// convert timestamp from format "2006-01-02 15:04:05" to
-		// "2006-01-02T15:04:05+00:00".
-		timeNew := strings.Split(val, " ")
-		timeJoined := strings.Join(timeNew, "T")
-		timeJoined = timeJoined + TimezoneOffset
-		t, err = time.Parse(time.RFC3339, timeJoined)
+		t, err = time.ParseInLocation("2006-01-02 15:04:05", val, location)
+		// Keep just the offset in effect at that time, as if it had been
+		// dumped along with the value.
+		_, offset := t.Zone()
+		t = t.In(time.FixedZone("", offset))
 	} else {
 		// datetime: data should just consist of date and time.
 		// timestamp conversion should ignore timezone. We mimic this using Parse
@@ -250,7 +319,17 @@ func convTimestamp(srcTypeName string, TimezoneOffset string, val string) (t tim
 	if err != nil {
 		return t, fmt.Errorf("can't convert to timestamp (mysql type: %s)", srcTypeName)
 	}
-	return t, err
+	return t, internal.CheckFractionalSeconds(val, t)
+}
+
+// convLocalTimestamp converts a source DB value without time zone, e.g. a
+// DATETIME, to the timestamp with the same wall clock time in loc.
+func convLocalTimestamp(conv *internal.Conv, srcTable string, srcTypeName string, loc *time.Location, val string) (time.Time, error) {
+	t, err := convTimestamp(srcTypeName, nil, val)
+	if err != nil {
+		return t, err
+	}
+	return conv.ShiftTimestamp(srcTable, t, loc), nil
 }
 
 // convArray converts a source database string value (representing an
@@ -16,6 +16,7 @@ package mysql
 
 import (
 	"fmt"
+	"math"
 	"math/bits"
 	"testing"
 	"time"
@@ -98,6 +99,13 @@ func TestConvertData(t *testing.T) {
 		{"string", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, "", "eh", "eh"},
 		{"datetime", ddl.Type{Name: ddl.Timestamp}, "datetime", "2019-10-29 05:30:00", getTimeWithoutTimezone(t, "2019-10-29 05:30:00")},
 		{"timestamp", ddl.Type{Name: ddl.Timestamp}, "timestamp", "2019-10-29 05:30:00", getTime(t, "2019-10-29T05:30:00+05:30")},
+		{"time(6)", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, "time", "12:30:00.123456", "12:30:00.123456"},
+		{"time as seconds", ddl.Type{Name: ddl.Int64}, "time", "-01:30:05", int64(-5405)},
+		{"time above a day as seconds", ddl.Type{Name: ddl.Int64}, "time", "838:59:59.000", int64(3020399)},
+		{"year", ddl.Type{Name: ddl.Int64}, "year", "2024", int64(2024)},
+		{"bit", ddl.Type{Name: ddl.Bool}, "bit", "\x01", true},
+		{"bit as bytes", ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}, "bit", "\x01\x02", []byte{0x01, 0x02}},
+		{"bit as int64", ddl.Type{Name: ddl.Int64}, "bit", "\x01\x02", int64(258)},
 		{"json", ddl.Type{Name: ddl.JSON}, "", "{\"key1\": \"value1\"}", "{\"key1\": \"value1\"}"},
 		{"string array(set)", ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}, "", "1,Travel,3,Dance", []spanner.NullString{
 			spanner.NullString{StringVal: "1", Valid: true},
@@ -128,13 +136,19 @@ func TestConvertData(t *testing.T) {
 
 func TestConvertTimestampData(t *testing.T) {
 	timestampTests := []struct {
-		name  string
-		srcTy string
-		in    string
-		e     interface{}
+		name   string
+		srcTy  string
+		policy string
+		in     string
+		e      interface{}
 	}{
-		{"timestampt", "timestamp", "2019-10-29 05:30:00", getTime(t, "2019-10-29T05:30:00+10:00")},
-		{"datetime", "datetime", "2019-10-29 05:30:00", getTimeWithoutTimezone(t, "2019-10-29 05:30:00")},
+		{"timestampt", "timestamp", "", "2019-10-29 05:30:00", getTime(t, "2019-10-29T05:30:00+10:00")},
+		{"datetime", "datetime", "", "2019-10-29 05:30:00", getTimeWithoutTimezone(t, "2019-10-29 05:30:00")},
+		{"timestamp with source timezone", "timestamp", internal.TimezoneSource, "2019-10-29 05:30:00", getTime(t, "2019-10-29T05:30:00+10:00")},
+		{"datetime with source timezone", "datetime", internal.TimezoneSource, "2019-10-29 05:30:00", getTime(t, "2019-10-29T05:30:00+10:00")},
+		{"timestamp(6)", "timestamp", "", "2019-10-29 05:30:00.123456", getTime(t, "2019-10-29T05:30:00.123456+10:00")},
+		{"datetime(6)", "datetime", "", "2019-10-29 05:30:00.000001", getTime(t, "2019-10-29T05:30:00.000001Z")},
+		{"datetime(3) with source timezone", "datetime", internal.TimezoneSource, "2019-10-29 05:30:00.5", getTime(t, "2019-10-29T05:30:00.5+10:00")},
 	}
 	tableName := "testtable"
 	tableId := "t1"
@@ -153,6 +167,7 @@ func TestConvertTimestampData(t *testing.T) {
 				ColIds:  []string{colId},
 				ColDefs: map[string]schema.Column{colId: schema.Column{Name: col, Id: colId, Type: schema.Type{Name: tc.srcTy}}}})
 		conv.TimezoneOffset = "+10:00" // Set offset so test is robust i.e. doesn't depent on local timezone.
+		conv.TimezonePolicy = tc.policy
 		t.Run(tc.in, func(t *testing.T) {
 			atable, ac, av, err := ConvertData(conv, tableId, []string{colId}, conv.SrcSchema[tableId], conv.SpSchema[tableId], []string{tc.in}, internal.AdditionalDataAttributes{})
 			assert.Nil(t, err, tc.name)
@@ -167,6 +182,34 @@ func TestConvertTimestampData(t *testing.T) {
 	}
 }
 
+func TestConvertTimestampInSessionLocation(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	assert.Nil(t, err)
+	tableId := "t1"
+	colId := "c1"
+	conv := buildConv(
+		ddl.CreateTable{
+			Name:    "testtable",
+			Id:      tableId,
+			ColIds:  []string{colId},
+			ColDefs: map[string]ddl.ColumnDef{colId: {Name: "a", Id: colId, T: ddl.Type{Name: ddl.Timestamp}}}},
+		schema.Table{
+			Name:    "testtable",
+			Id:      tableId,
+			ColIds:  []string{colId},
+			ColDefs: map[string]schema.Column{colId: {Name: "a", Id: colId, Type: schema.Type{Name: "timestamp"}}}})
+	conv.TimezonePolicy = internal.TimezoneSource
+	// The offset of the session location changes with daylight saving time.
+	for in, e := range map[string]string{
+		"2019-01-15 12:00:00": "2019-01-15T12:00:00+01:00",
+		"2019-07-15 12:00:00": "2019-07-15T12:00:00+02:00",
+	} {
+		_, _, av, err := ConvertData(conv, tableId, []string{colId}, conv.SrcSchema[tableId], conv.SpSchema[tableId], []string{in}, internal.AdditionalDataAttributes{Location: loc})
+		assert.Nil(t, err)
+		assert.True(t, av[0].(time.Time).Equal(getTime(t, e)), in)
+	}
+}
+
 func TestConvertMultiColData(t *testing.T) {
 	multiColTests := []struct {
 		name   string
@@ -239,6 +282,21 @@ func TestConvertMultiColData(t *testing.T) {
 	}
 }
 
+func TestConvTimeToSeconds(t *testing.T) {
+	for _, val := range []string{"12:30:00.5", "12:60:00", "12:30", "noon"} {
+		_, err := convTimeToSeconds(val)
+		assert.NotNil(t, err, val)
+	}
+}
+
+func TestConvBitToInt64(t *testing.T) {
+	i, err := convBitToInt64("\x7f\xff\xff\xff\xff\xff\xff\xff")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(math.MaxInt64), i)
+	_, err = convBitToInt64("\x80\x00\x00\x00\x00\x00\x00\x00")
+	assert.NotNil(t, err)
+}
+
 func TestConvertError(t *testing.T) {
 	errorTests := []struct {
 		name string
@@ -21,6 +21,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	sp "cloud.google.com/go/spanner"
 	_ "github.com/go-sql-driver/mysql" // The driver should be used via the database/sql package.
@@ -28,6 +29,7 @@ import (
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
@@ -37,6 +39,11 @@ import (
 
 var collationRegex = regexp.MustCompile(constants.DB_COLLATION_REGEX)
 
+var (
+	quotedIdentifierRegex   = regexp.MustCompile("`([^`]+)`")
+	unquotedIdentifierRegex = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_$]*\s*\(?`)
+)
+
 // InfoSchemaImpl is MySQL specific implementation for InfoSchema.
 type InfoSchemaImpl struct {
 	DbName             string
@@ -44,18 +51,38 @@ type InfoSchemaImpl struct {
 	MigrationProjectId string
 	SourceProfile      profiles.SourceProfile
 	TargetProfile      profiles.TargetProfile
+	// Snapshot, when set, is the consistent snapshot that table data is read from.
+	Snapshot *common.ConsistentSnapshot
 }
 
 // GetToDdl implement the common.InfoSchema interface.
 func (isi InfoSchemaImpl) GetToDdl() common.ToDdl {
-	return ToDdlImpl{}
+	return common.LookupToDdl(constants.MYSQL, ToDdlImpl{})
 }
 
 // GetTableName returns table name.
 func (isi InfoSchemaImpl) GetTableName(dbName string, tableName string) string {
+	if isi.isMerged() {
+		return fmt.Sprintf("%s.%s", dbName, tableName)
+	}
 	return tableName
 }
 
+// isMerged returns true if several databases are migrated into a single
+// Spanner database, in which case table names are prefixed with their database.
+func (isi InfoSchemaImpl) isMerged() bool {
+	mysql := isi.SourceProfile.Conn.Mysql
+	return len(mysql.DbNames) > 0 && mysql.MultiDb == constants.MULTI_DB_MERGED
+}
+
+// dbNames returns the databases whose tables are migrated.
+func (isi InfoSchemaImpl) dbNames() []string {
+	if isi.isMerged() {
+		return isi.SourceProfile.Conn.Mysql.DbNames
+	}
+	return []string{isi.DbName}
+}
+
 // GetRowsFromTable returns a sql Rows object for a table.
 func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string) (interface{}, error) {
 	srcSchema := conv.SrcSchema[tableId]
@@ -72,11 +99,134 @@ func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string)
 	// Ideally we would pass schema/name as a query parameter,
 	// but MySQL doesn't support this. So we quote it instead.
 	colNameList := buildColNameList(srcSchema, srcCols)
-	q := fmt.Sprintf("SELECT %s FROM `%s`.`%s`;", colNameList, isi.DbName, srcSchema.Name)
-	rows, err := isi.Db.Query(q)
+	q := fmt.Sprintf("SELECT %s FROM %s;", colNameList, isi.quotedTableName(srcSchema))
+	rows, err := isi.Snapshot.QueryContext(conv.Context(), isi.Db, q)
 	return rows, err
 }
 
+// CollectColumnStats samples up to sampleRows rows of each table to compute
+// the statistics of its columns, then flags the columns whose type can be
+// improved given their values. Tables which can't be sampled are skipped.
+func (isi InfoSchemaImpl) CollectColumnStats(conv *internal.Conv, sampleRows int) {
+	for tableId, srcSchema := range conv.SrcSchema {
+		if len(srcSchema.ColIds) == 0 {
+			continue
+		}
+		var srcCols []string
+		for _, colId := range srcSchema.ColIds {
+			srcCols = append(srcCols, srcSchema.ColDefs[colId].Name)
+		}
+		q := fmt.Sprintf("SELECT %s FROM %s LIMIT %d;", buildColNameList(srcSchema, srcCols), isi.quotedTableName(srcSchema), sampleRows)
+		rows, err := isi.Db.Query(q)
+		if err != nil {
+			logger.Log.Warn(fmt.Sprintf("Couldn't sample table %s for column statistics: %v", srcSchema.Name, err))
+			continue
+		}
+		err = common.CollectColumnStats(conv, tableId, srcSchema.ColIds, rows)
+		rows.Close()
+		if err != nil {
+			logger.Log.Warn(err.Error())
+		}
+	}
+	conv.SuggestFromColumnStats()
+}
+
+// EstimateRowCounts reads the number of rows of each table estimated by MySQL
+// in information_schema.TABLES, which is much cheaper than counting them, to
+// estimate the Spanner storage of the tables during schema conversion.
+func (isi InfoSchemaImpl) EstimateRowCounts(conv *internal.Conv) {
+	for tableId, srcSchema := range conv.SrcSchema {
+		dbName, tableName := isi.dbAndTableName(srcSchema)
+		var n sql.NullInt64
+		err := isi.Db.QueryRow("SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?", dbName, tableName).Scan(&n)
+		if err != nil {
+			logger.Log.Warn(fmt.Sprintf("Couldn't read the estimated number of rows of table %s: %v", srcSchema.Name, err))
+			continue
+		}
+		if n.Valid {
+			conv.SrcRowCounts[tableId] = n.Int64
+		}
+	}
+}
+
+// dbAndTableName returns the database and table name of a source table.
+func (isi InfoSchemaImpl) dbAndTableName(srcSchema schema.Table) (string, string) {
+	if isi.isMerged() {
+		return srcSchema.Schema, strings.TrimPrefix(srcSchema.Name, srcSchema.Schema+".")
+	}
+	return isi.DbName, srcSchema.Name
+}
+
+// quotedTableName returns the quoted database and table name of a source table.
+func (isi InfoSchemaImpl) quotedTableName(srcSchema schema.Table) string {
+	dbName, tableName := isi.dbAndTableName(srcSchema)
+	return fmt.Sprintf("`%s`.`%s`", dbName, tableName)
+}
+
+// chunkedTableReader returns a reader of the table by primary key range, and
+// the chunks it is split into, when the table is large enough to be read by
+// several workers and has a single column key of an integer, decimal or
+// character type.
+func (isi InfoSchemaImpl) chunkedTableReader(conv *internal.Conv, tableId string) (common.ChunkedTableReader, []common.TableChunk, bool) {
+	srcSchema := conv.SrcSchema[tableId]
+	rowCount := conv.Stats.Rows[srcSchema.Name]
+	n := common.NumTableChunks(rowCount, conv.TableReadParallelism)
+	if n == 1 || len(srcSchema.PrimaryKeys) != 1 || len(srcSchema.ColIds) == 0 {
+		return common.ChunkedTableReader{}, nil, false
+	}
+	if isi.Snapshot != nil {
+		// A consistent snapshot of MySQL is bound to a single connection.
+		logger.Log.Debug(fmt.Sprintf("Reading table %s with a single query from the consistent snapshot", srcSchema.Name))
+		return common.ChunkedTableReader{}, nil, false
+	}
+	key := srcSchema.ColDefs[srcSchema.PrimaryKeys[0].ColId]
+	var integerKey, characterKey bool
+	switch strings.ToLower(key.Type.Name) {
+	case "tinyint", "smallint", "mediumint", "integer", "int", "bigint", "smallint unsigned", "mediumint unsigned", "integer unsigned", "int unsigned", "bigint unsigned":
+		integerKey = true
+	case "numeric", "decimal":
+	case "varchar", "char":
+		characterKey = true
+	default:
+		return common.ChunkedTableReader{}, nil, false
+	}
+	srcCols := []string{}
+	for _, colId := range srcSchema.ColIds {
+		srcCols = append(srcCols, srcSchema.ColDefs[colId].Name)
+	}
+	reader := common.ChunkedTableReader{
+		Db:         isi.Db,
+		Table:      isi.quotedTableName(srcSchema),
+		Key:        "`" + key.Name + "`",
+		KeyName:    key.Name,
+		IntegerKey: integerKey,
+		Query:      fmt.Sprintf("SELECT %s FROM %s", buildColNameList(srcSchema, srcCols), isi.quotedTableName(srcSchema)),
+		// Key values are inlined rather than passed as parameters, since
+		// parameters make the driver return rows in the binary protocol, whose
+		// values are formatted differently. Character values are hex encoded,
+		// and compared with the collation of the key column.
+		Bound: func(value string, _ int) (string, []interface{}) {
+			if characterKey {
+				return fmt.Sprintf("_utf8mb4 X'%x'", value), nil
+			}
+			return value, nil
+		},
+		NewRow: func(n int) []interface{} {
+			vals := make([]interface{}, n)
+			for i := range vals {
+				vals[i] = new([]byte)
+			}
+			return vals
+		},
+	}
+	chunks, err := reader.Split(n, rowCount)
+	if err != nil {
+		logger.Log.Debug(fmt.Sprintf("Reading table %s with a single query, couldn't split it into key ranges: %v", srcSchema.Name, err))
+		return common.ChunkedTableReader{}, nil, false
+	}
+	return reader, chunks, true
+}
+
 // Building list of column names to support mysql spatial datatypes instead of
 // using 'SELECT *' because spatial columns will be fetched using ST_AsText(colName).
 func buildColNameList(srcSchema schema.Table, srcColName []string) string {
@@ -97,9 +247,48 @@ func buildColNameList(srcSchema schema.Table, srcColName []string) string {
 	return colList[:len(colList)-1]
 }
 
-// ProcessData performs data conversion for source database.
+// ProcessData performs data conversion for source database. Large tables are
+// read by conv.TableReadParallelism workers, each reading a range of the
+// primary key.
 func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, commonColIds []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
 	srcTableName := conv.SrcSchema[tableId].Name
+	if conv.TimezonePolicy == internal.TimezoneSource {
+		loc, err := isi.sessionLocation()
+		if err != nil {
+			return fmt.Errorf("couldn't get the time zone of the source server: %v", err)
+		}
+		additionalAttributes.Location = loc
+	}
+	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
+	processRow := func(srcCols []string, values []string) {
+		newValues, err := common.PrepareValues(conv, tableId, colNameIdMap, commonColIds, srcCols, values)
+		if err != nil {
+			conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
+			conv.StatsAddBadRow(srcTableName, conv.DataMode())
+			conv.CollectBadRowWithReason(srcTableName, srcCols, values, err.Error())
+			return
+		}
+
+		ProcessDataRow(conv, tableId, commonColIds, srcSchema, spSchema, newValues, additionalAttributes)
+	}
+	if reader, chunks, ok := isi.chunkedTableReader(conv, tableId); ok {
+		logger.Log.Info(fmt.Sprintf("Reading table %s in %d key ranges with %d workers", srcTableName, len(chunks), conv.TableReadParallelism))
+		err := reader.Read(conv.Context(), chunks, conv.TableReadParallelism, func(srcCols []string, vals []interface{}, err error) {
+			if err != nil {
+				conv.Unexpected(fmt.Sprintf("Couldn't process sql data row: %s", err))
+				conv.StatsAddBadRow(srcTableName, conv.DataMode())
+				return
+			}
+			processRow(srcCols, scannedValsToStrings(vals))
+		})
+		if conv.Interrupted() {
+			return nil
+		}
+		if err != nil {
+			conv.Unexpected(fmt.Sprintf("Couldn't get data for table %s : err = %s", srcTableName, err))
+		}
+		return err
+	}
 	rowsInterface, err := isi.GetRowsFromTable(conv, tableId)
 	if err != nil {
 		conv.Unexpected(fmt.Sprintf("Couldn't get data for table %s : err = %s", srcTableName, err))
@@ -109,8 +298,7 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 	defer rows.Close()
 	srcCols, _ := rows.Columns()
 	v, scanArgs := buildVals(len(srcCols))
-	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
-	for rows.Next() {
+	for !conv.Interrupted() && rows.Next() {
 		// get RawBytes from data.
 		err := rows.Scan(scanArgs...)
 		if err != nil {
@@ -119,19 +307,48 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 			conv.StatsAddBadRow(srcTableName, conv.DataMode())
 			continue
 		}
-		values := valsToStrings(v)
+		processRow(srcCols, valsToStrings(v))
+	}
+	return nil
+}
 
-		newValues, err := common.PrepareValues(conv, tableId, colNameIdMap, commonColIds, srcCols, values)
-		if err != nil {
-			conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
-			conv.StatsAddBadRow(srcTableName, conv.DataMode())
-			conv.CollectBadRow(srcTableName, srcCols, values)
-			continue
-		}
+// sessionLocation returns the session time zone of the source server, in
+// which TIMESTAMP values are read. Named time zones follow daylight saving
+// time. SYSTEM stands for the time zone of the server, which is often an
+// abbreviation such as CEST missing from the time zone database: its current
+// offset is used then.
+func (isi InfoSchemaImpl) sessionLocation() (*time.Location, error) {
+	var tz, systemTz string
+	if err := isi.Db.QueryRow("SELECT @@session.time_zone, @@global.system_time_zone").Scan(&tz, &systemTz); err != nil {
+		return nil, err
+	}
+	if tz == "SYSTEM" {
+		tz = systemTz
+	}
+	if loc, err := internal.ParseTimezone(tz); err == nil {
+		return loc, nil
+	}
+	offset, err := isi.sessionTimezoneOffset()
+	if err != nil {
+		return nil, err
+	}
+	logger.Log.Warn(fmt.Sprintf("Time zone %s of the source server isn't in the time zone database: using its current offset %s, which doesn't follow daylight saving time", tz, offset))
+	return internal.ParseTimezone(offset)
+}
 
-		ProcessDataRow(conv, tableId, commonColIds, srcSchema, spSchema, newValues, additionalAttributes)
+// sessionTimezoneOffset returns the current offset from UTC of the session
+// time zone of the source server, e.g. "+05:30".
+func (isi InfoSchemaImpl) sessionTimezoneOffset() (string, error) {
+	var mins int
+	if err := isi.Db.QueryRow("SELECT TIMESTAMPDIFF(MINUTE, UTC_TIMESTAMP(), NOW())").Scan(&mins); err != nil {
+		return "", err
 	}
-	return nil
+	sign := "+"
+	if mins < 0 {
+		sign = "-"
+		mins = -mins
+	}
+	return fmt.Sprintf("%s%02d:%02d", sign, mins/60, mins%60), nil
 }
 
 // GetRowCount with number of rows in each table.
@@ -140,7 +357,7 @@ func (isi InfoSchemaImpl) GetRowCount(table common.SchemaAndName) (int64, error)
 	// Ideally we would pass schema/name as a query parameter,
 	// but MySQL doesn't support this. So we quote it instead.
 	q := fmt.Sprintf("SELECT COUNT(*) FROM `%s`.`%s`;", table.Schema, table.Name)
-	rows, err := isi.Db.Query(q)
+	rows, err := isi.Snapshot.Query(isi.Db, q)
 	if err != nil {
 		return 0, err
 	}
@@ -159,24 +376,39 @@ func (isi InfoSchemaImpl) GetRowCount(table common.SchemaAndName) (int64, error)
 // but unfortunately there is no way to extract it from sql.DB.
 func (isi InfoSchemaImpl) GetTables() ([]common.SchemaAndName, error) {
 	// In MySQL, schema is the same as database name.
-	q := "SELECT table_name FROM information_schema.tables where table_type = 'BASE TABLE' and table_schema=?"
-	rows, err := isi.Db.Query(q, isi.DbName)
-	if err != nil {
-		return nil, fmt.Errorf("couldn't get tables: %w", err)
-	}
-	defer rows.Close()
-	var tableName string
+	// Partitioning details are fetched along with the tables. All partitions of a
+	// table share the same method and expression, hence the DISTINCT.
+	q := `SELECT t.table_name, t.table_comment, p.partition_method, p.partition_expression
+              FROM information_schema.tables t
+              LEFT JOIN (SELECT DISTINCT table_schema, table_name, partition_method, partition_expression
+                  FROM information_schema.partitions WHERE partition_method IS NOT NULL) p
+              ON t.table_schema = p.table_schema AND t.table_name = p.table_name
+              where t.table_type = 'BASE TABLE' and t.table_schema=?`
 	var tables []common.SchemaAndName
-	for rows.Next() {
-		rows.Scan(&tableName)
-		tables = append(tables, common.SchemaAndName{Schema: isi.DbName, Name: tableName})
+	for _, dbName := range isi.dbNames() {
+		rows, err := isi.Db.Query(q, dbName)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get tables: %w", err)
+		}
+		var tableName string
+		var tableComment, partitionMethod, partitionExpression sql.NullString
+		for rows.Next() {
+			rows.Scan(&tableName, &tableComment, &partitionMethod, &partitionExpression)
+			tables = append(tables, common.SchemaAndName{
+				Schema:       dbName,
+				Name:         tableName,
+				Comment:      tableComment.String,
+				Partitioning: toPartitioning(partitionMethod.String, partitionExpression.String),
+			})
+		}
+		rows.Close()
 	}
 	return tables, nil
 }
 
 // GetColumns returns a list of Column objects and names// ProcessColumns
 func (isi InfoSchemaImpl) GetColumns(conv *internal.Conv, table common.SchemaAndName, constraints map[string][]string, primaryKeys []string) (map[string]schema.Column, []string, error) {
-	q := `SELECT c.column_name, c.data_type, c.column_type, c.is_nullable, c.column_default, c.character_maximum_length, c.numeric_precision, c.numeric_scale, c.generation_expression, c.extra
+	q := `SELECT c.column_name, c.data_type, c.column_type, c.is_nullable, c.column_default, c.character_maximum_length, c.numeric_precision, c.numeric_scale, c.generation_expression, c.extra, c.column_comment, c.collation_name
               FROM information_schema.COLUMNS c
               where table_schema = ? and table_name = ? ORDER BY c.ordinal_position;`
 	cols, err := isi.Db.Query(q, table.Schema, table.Name)
@@ -187,11 +419,11 @@ func (isi InfoSchemaImpl) GetColumns(conv *internal.Conv, table common.SchemaAnd
 	colDefs := make(map[string]schema.Column)
 	var colIds []string
 	var colName, dataType, isNullable, columnType string
-	var colDefault, colExtra, colGeneratedExpression sql.NullString
+	var colDefault, colExtra, colGeneratedExpression, colComment, colCollation sql.NullString
 	var charMaxLen, numericPrecision, numericScale sql.NullInt64
 	var colAutoGen ddl.AutoGenCol
 	for cols.Next() {
-		err := cols.Scan(&colName, &dataType, &columnType, &isNullable, &colDefault, &charMaxLen, &numericPrecision, &numericScale, &colGeneratedExpression, &colExtra)
+		err := cols.Scan(&colName, &dataType, &columnType, &isNullable, &colDefault, &charMaxLen, &numericPrecision, &numericScale, &colGeneratedExpression, &colExtra, &colComment, &colCollation)
 		if err != nil {
 			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
 			continue
@@ -253,6 +485,8 @@ func (isi InfoSchemaImpl) GetColumns(conv *internal.Conv, table common.SchemaAnd
 			AutoGen:         colAutoGen,
 			DefaultValue:    defaultVal,
 			GeneratedColumn: generatedColumn,
+			Comment:         colComment.String,
+			Collation:       toCollation(colCollation.String),
 		}
 		colDefs[colId] = c
 		colIds = append(colIds, colId)
@@ -439,7 +673,7 @@ func (isi InfoSchemaImpl) GetForeignKeys(conv *internal.Conv, table common.Schem
 				Id:               internal.GenerateForeignkeyId(),
 				Name:             fKeys[k].Name,
 				ColumnNames:      fKeys[k].Cols,
-				ReferTableName:   fKeys[k].Table,
+				ReferTableName:   isi.GetTableName(table.Schema, fKeys[k].Table),
 				ReferColumnNames: fKeys[k].Refcols,
 				OnDelete:         fKeys[k].OnDelete,
 				OnUpdate:         fKeys[k].OnUpdate,
@@ -553,13 +787,14 @@ func toType(dataType string, columnType string, charLen sql.NullInt64, numericPr
 			return schema.Type{Name: dataType}
 		}
 		return schema.Type{Name: dataType, Mods: []int64{length}}
-	case dataType == "bigint" && len(columnType) > len("bigint") && strings.Contains(strings.ToUpper(columnType), "UNSIGNED"):
+	case isUnsignedIntType(dataType, columnType):
+		name := dataType + " unsigned"
 		if numericPrecision.Valid && numericScale.Valid && numericScale.Int64 != 0 {
-			return schema.Type{Name: "bigint unsigned", Mods: []int64{numericPrecision.Int64, numericScale.Int64}}
+			return schema.Type{Name: name, Mods: []int64{numericPrecision.Int64, numericScale.Int64}}
 		} else if numericPrecision.Valid {
-			return schema.Type{Name: "bigint unsigned", Mods: []int64{numericPrecision.Int64}}
+			return schema.Type{Name: name, Mods: []int64{numericPrecision.Int64}}
 		} else {
-			return schema.Type{Name: "bigint unsigned"}
+			return schema.Type{Name: name}
 		}
 	case numericPrecision.Valid && numericScale.Valid && numericScale.Int64 != 0:
 		return schema.Type{Name: dataType, Mods: []int64{numericPrecision.Int64, numericScale.Int64}}
@@ -570,6 +805,60 @@ func toType(dataType string, columnType string, charLen sql.NullInt64, numericPr
 	}
 }
 
+// isUnsignedIntType returns true if columnType is an UNSIGNED variant of the
+// integer type dataType. Tinyint is excluded since tinyint(1) is used as a
+// boolean, and unsigned tinyint values always fit in INT64.
+func isUnsignedIntType(dataType, columnType string) bool {
+	switch dataType {
+	case "smallint", "mediumint", "int", "integer", "bigint":
+		return strings.Contains(strings.ToUpper(columnType), "UNSIGNED")
+	}
+	return false
+}
+
+// toCollation describes MySQL collation name. Collation names end with _bin
+// for binary collations, _ci for case-insensitive ones and _cs for
+// case-sensitive ones, e.g. utf8mb4_0900_ai_ci or utf8mb4_0900_as_cs.
+func toCollation(name string) schema.Collation {
+	if name == "" {
+		return schema.Collation{}
+	}
+	return schema.Collation{
+		Name:            name,
+		Binary:          name == "binary" || strings.HasSuffix(name, "_bin"),
+		CaseInsensitive: strings.HasSuffix(name, "_ci"),
+	}
+}
+
+// toPartitioning builds the partitioning scheme of a table from its
+// partitioning method and expression. For RANGE COLUMNS/LIST COLUMNS/KEY the
+// expression is a column list; otherwise it is an arbitrary expression such
+// as YEAR(`created_at`), from which we extract the referenced columns.
+func toPartitioning(method, expression string) schema.Partitioning {
+	if method == "" {
+		return schema.Partitioning{}
+	}
+	var colNames []string
+	if matches := quotedIdentifierRegex.FindAllStringSubmatch(expression, -1); len(matches) > 0 {
+		for _, m := range matches {
+			colNames = append(colNames, m[1])
+		}
+	} else {
+		for _, m := range unquotedIdentifierRegex.FindAllString(expression, -1) {
+			// Skip function names such as YEAR( or TO_DAYS(.
+			if strings.HasSuffix(m, "(") {
+				continue
+			}
+			colNames = append(colNames, strings.TrimSpace(m))
+		}
+	}
+	return schema.Partitioning{
+		Method:      strings.ToUpper(method),
+		Expression:  expression,
+		ColumnNames: colNames,
+	}
+}
+
 // buildVals constructs []sql.RawBytes value containers to scan row
 // results into.  Returns both the underlying containers (as a slice)
 // as well as an interface{} of pointers to containers to pass to
@@ -599,9 +888,24 @@ func valsToStrings(vals []sql.RawBytes) []string {
 	return s
 }
 
-func createSequence(conv *internal.Conv) ddl.Sequence {
+// scannedValsToStrings is like valsToStrings, for rows scanned into *[]byte
+// values by a common.ChunkedTableReader.
+func scannedValsToStrings(vals []interface{}) []string {
+	var s []string
+	for _, v := range vals {
+		b := *v.(*[]byte)
+		if b == nil {
+			s = append(s, "NULL")
+		} else {
+			s = append(s, string(b))
+		}
+	}
+	return s
+}
+
+func createSequence(conv *internal.Conv, tableName, colName string) ddl.Sequence {
 	id := internal.GenerateSequenceId()
-	sequenceName := "Sequence" + id[1:]
+	sequenceName := conv.NameTemplates.SequenceName(tableName, colName)
 	sequence := ddl.Sequence{
 		Id:           id,
 		Name:         sequenceName,
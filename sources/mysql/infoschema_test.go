@@ -43,15 +43,15 @@ type mockSpec struct {
 func TestProcessSchemaMYSQL(t *testing.T) {
 	ms := []mockSpec{
 		{
-			query: "SELECT (.+) FROM information_schema.tables where table_type = 'BASE TABLE'  and (.+)",
+			query: "SELECT (.+) FROM information_schema.tables (.+)",
 			args:  []driver.Value{"test"},
-			cols:  []string{"table_name"},
+			cols:  []string{"table_name", "table_comment", "partition_method", "partition_expression"},
 			rows: [][]driver.Value{
-				{"user"},
-				{"cart"},
-				{"product"},
-				{"test"},
-				{"test_ref"},
+				{"user", "", nil, nil},
+				{"cart", "", nil, nil},
+				{"product", "", nil, nil},
+				{"test", "", nil, nil},
+				{"test_ref", "", nil, nil},
 			},
 		},
 		{
@@ -82,11 +82,11 @@ func TestProcessSchemaMYSQL(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"test", "user"},
-			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra"},
+			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra", "column_comment", "collation_name"},
 			rows: [][]driver.Value{
-				{"user_id", "text", "text", "NO", "uuid()", nil, nil, nil, nil, constants.DEFAULT_GENERATED},
-				{"name", "text", "text", "NO", "default_name", nil, nil, nil, nil, nil},
-				{"ref", "bigint", "bigint", "NO", nil, nil, nil, nil, nil, nil}},
+				{"user_id", "text", "text", "NO", "uuid()", nil, nil, nil, nil, constants.DEFAULT_GENERATED, nil, nil},
+				{"name", "text", "text", "NO", "default_name", nil, nil, nil, nil, nil, nil, nil},
+				{"ref", "bigint", "bigint", "NO", nil, nil, nil, nil, nil, nil, nil, nil}},
 		},
 		// db call to fetch index happens after fetching of column
 		{
@@ -123,11 +123,11 @@ func TestProcessSchemaMYSQL(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"test", "cart"},
-			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra"},
+			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra", "column_comment", "collation_name"},
 			rows: [][]driver.Value{
-				{"productid", "text", "text", "NO", nil, nil, nil, nil, nil, nil},
-				{"userid", "text", "text", "NO", nil, nil, nil, nil, nil, nil},
-				{"quantity", "bigint", "bigint", "YES", nil, nil, 64, 0, nil, nil},
+				{"productid", "text", "text", "NO", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"userid", "text", "text", "NO", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"quantity", "bigint", "bigint", "YES", nil, nil, 64, 0, nil, nil, nil, nil},
 			},
 		},
 		// db call to fetch index happens after fetching of column
@@ -167,10 +167,10 @@ func TestProcessSchemaMYSQL(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"test", "product"},
-			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra"},
+			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra", "column_comment", "collation_name"},
 			rows: [][]driver.Value{
-				{"product_id", "text", "text", "NO", nil, nil, nil, nil, nil, nil},
-				{"product_name", "text", "text", "NO", nil, nil, nil, nil, nil, nil},
+				{"product_id", "text", "text", "NO", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"product_name", "text", "text", "NO", nil, nil, nil, nil, nil, nil, nil, nil},
 			},
 		},
 		// db call to fetch index happens after fetching of column
@@ -205,29 +205,29 @@ func TestProcessSchemaMYSQL(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"test", "test"},
-			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra"},
+			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra", "column_comment", "collation_name"},
 			rows: [][]driver.Value{
-				{"id", "bigint", "bigint", "NO", nil, nil, 64, 0, nil, nil},
-				{"s", "set", "set", "YES", nil, nil, nil, nil, nil, nil},
-				{"txt", "text", "text", "NO", nil, nil, nil, nil, nil, nil},
-				{"b", "boolean", "boolean", "YES", nil, nil, nil, nil, nil, nil},
-				{"bs", "bigint", "bigint", "NO", "nextval('test11_bs_seq'::regclass)", nil, 64, 0, nil, nil},
-				{"bl", "blob", "blob", "YES", nil, nil, nil, nil, nil, nil},
-				{"c", "char", "char(1)", "YES", nil, 1, nil, nil, nil, nil},
-				{"c8", "char", "char(8)", "YES", nil, 8, nil, nil, nil, nil},
-				{"d", "date", "date", "YES", nil, nil, nil, nil, nil, nil},
-				{"dec", "decimal", "decimal(20,5)", "YES", nil, nil, 20, 5, nil, nil},
-				{"f8", "double", "double", "YES", nil, nil, 53, nil, nil, nil},
-				{"f4", "float", "float", "YES", nil, nil, 24, nil, nil, nil},
-				{"i8", "bigint", "bigint", "YES", nil, nil, 64, 0, nil, nil},
-				{"i4", "integer", "integer", "YES", nil, nil, 32, 0, nil, "auto_increment"},
-				{"i2", "smallint", "smallint", "YES", nil, nil, 16, 0, nil, nil},
-				{"si", "integer", "integer", "NO", "nextval('test11_s_seq'::regclass)", nil, 32, 0, nil, nil},
-				{"ts", "datetime", "datetime", "YES", nil, nil, nil, nil, nil, nil},
-				{"tz", "timestamp", "timestamp", "YES", nil, nil, nil, nil, nil, nil},
-				{"vc", "varchar", "varchar", "YES", nil, nil, nil, nil, nil, nil},
-				{"vc6", "varchar", "varchar(6)", "YES", nil, 6, nil, nil, nil, nil},
-				{"bu", "bigint", "bigint(20) unsigned", "YES", nil, nil, 20, 0, nil, nil},
+				{"id", "bigint", "bigint", "NO", nil, nil, 64, 0, nil, nil, nil, nil},
+				{"s", "set", "set", "YES", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"txt", "text", "text", "NO", nil, nil, nil, nil, nil, nil, nil, "utf8mb4_0900_ai_ci"},
+				{"b", "boolean", "boolean", "YES", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"bs", "bigint", "bigint", "NO", "nextval('test11_bs_seq'::regclass)", nil, 64, 0, nil, nil, nil, nil},
+				{"bl", "blob", "blob", "YES", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"c", "char", "char(1)", "YES", nil, 1, nil, nil, nil, nil, nil, nil},
+				{"c8", "char", "char(8)", "YES", nil, 8, nil, nil, nil, nil, nil, nil},
+				{"d", "date", "date", "YES", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"dec", "decimal", "decimal(20,5)", "YES", nil, nil, 20, 5, nil, nil, nil, nil},
+				{"f8", "double", "double", "YES", nil, nil, 53, nil, nil, nil, nil, nil},
+				{"f4", "float", "float", "YES", nil, nil, 24, nil, nil, nil, nil, nil},
+				{"i8", "bigint", "bigint", "YES", nil, nil, 64, 0, nil, nil, nil, nil},
+				{"i4", "integer", "integer", "YES", nil, nil, 32, 0, nil, "auto_increment", nil, nil},
+				{"i2", "smallint", "smallint", "YES", nil, nil, 16, 0, nil, nil, nil, nil},
+				{"si", "integer", "integer", "NO", "nextval('test11_s_seq'::regclass)", nil, 32, 0, nil, nil, nil, nil},
+				{"ts", "datetime", "datetime", "YES", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"tz", "timestamp", "timestamp", "YES", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"vc", "varchar", "varchar", "YES", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"vc6", "varchar", "varchar(6)", "YES", nil, 6, nil, nil, nil, nil, nil, "utf8mb4_bin"},
+				{"bu", "bigint", "bigint(20) unsigned", "YES", nil, nil, 20, 0, nil, nil, nil, nil},
 			},
 		},
 		// db call to fetch index happens after fetching of column
@@ -261,11 +261,11 @@ func TestProcessSchemaMYSQL(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"test", "test_ref"},
-			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra"},
+			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra", "column_comment", "collation_name"},
 			rows: [][]driver.Value{
-				{"ref_id", "bigint", "bigint", "NO", nil, nil, 64, 0, nil, nil},
-				{"ref_txt", "text", "text", "NO", nil, nil, nil, nil, nil, nil},
-				{"abc", "text", "text", "NO", nil, nil, nil, nil, nil, nil},
+				{"ref_id", "bigint", "bigint", "NO", nil, nil, 64, 0, nil, nil, nil, nil},
+				{"ref_txt", "text", "text", "NO", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"abc", "text", "text", "NO", nil, nil, nil, nil, nil, nil, nil, nil},
 			},
 		},
 		// db call to fetch index happens after fetching of column
@@ -277,7 +277,7 @@ func TestProcessSchemaMYSQL(t *testing.T) {
 	}
 	db := mkMockDB(t, ms)
 	conv := internal.MakeConv()
-	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}}
+	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, nil}
 	commonInfoSchema := common.InfoSchemaImpl{}
 	_, err := commonInfoSchema.GenerateSrcSchema(conv, isi, 1)
 	assert.Nil(t, err)
@@ -325,10 +325,10 @@ func TestProcessSchemaMYSQL(t *testing.T) {
 			"s":   schema.Column{Name: "s", Type: schema.Type{Name: "set", Mods: []int64(nil), ArrayBounds: []int64{-1}}, NotNull: false, Ignored: schema.Ignored{Check: false, Identity: false, Default: false, Exclusion: false, ForeignKey: false, AutoIncrement: false}, Id: ""},
 			"si":  schema.Column{Name: "si", Type: schema.Type{Name: "integer", Mods: []int64{32}, ArrayBounds: []int64(nil)}, NotNull: true, Ignored: schema.Ignored{Check: false, Identity: false, Default: true, Exclusion: false, ForeignKey: false, AutoIncrement: false}, Id: "", DefaultValue: ddl.DefaultValue{IsPresent: true, Value: ddl.Expression{ExpressionId: "e39", Statement: "nextval('test11_s_seq'::regclass)"}}},
 			"ts":  schema.Column{Name: "ts", Type: schema.Type{Name: "datetime", Mods: []int64(nil), ArrayBounds: []int64(nil)}, NotNull: false, Ignored: schema.Ignored{Check: false, Identity: false, Default: false, Exclusion: false, ForeignKey: false, AutoIncrement: false}, Id: ""},
-			"txt": schema.Column{Name: "txt", Type: schema.Type{Name: "text", Mods: []int64(nil), ArrayBounds: []int64(nil)}, NotNull: true, Ignored: schema.Ignored{Check: false, Identity: false, Default: false, Exclusion: false, ForeignKey: false, AutoIncrement: false}, Id: ""},
+			"txt": schema.Column{Name: "txt", Type: schema.Type{Name: "text", Mods: []int64(nil), ArrayBounds: []int64(nil)}, NotNull: true, Ignored: schema.Ignored{Check: false, Identity: false, Default: false, Exclusion: false, ForeignKey: false, AutoIncrement: false}, Id: "", Collation: schema.Collation{Name: "utf8mb4_0900_ai_ci", CaseInsensitive: true}},
 			"tz":  schema.Column{Name: "tz", Type: schema.Type{Name: "timestamp", Mods: []int64(nil), ArrayBounds: []int64(nil)}, NotNull: false, Ignored: schema.Ignored{Check: false, Identity: false, Default: false, Exclusion: false, ForeignKey: false, AutoIncrement: false}, Id: ""},
 			"vc":  schema.Column{Name: "vc", Type: schema.Type{Name: "varchar", Mods: []int64(nil), ArrayBounds: []int64(nil)}, NotNull: false, Ignored: schema.Ignored{Check: false, Identity: false, Default: false, Exclusion: false, ForeignKey: false, AutoIncrement: false}, Id: ""},
-			"vc6": schema.Column{Name: "vc6", Type: schema.Type{Name: "varchar", Mods: []int64{6}, ArrayBounds: []int64(nil)}, NotNull: false, Ignored: schema.Ignored{Check: false, Identity: false, Default: false, Exclusion: false, ForeignKey: false, AutoIncrement: false}, Id: ""},
+			"vc6": schema.Column{Name: "vc6", Type: schema.Type{Name: "varchar", Mods: []int64{6}, ArrayBounds: []int64(nil)}, NotNull: false, Ignored: schema.Ignored{Check: false, Identity: false, Default: false, Exclusion: false, ForeignKey: false, AutoIncrement: false}, Id: "", Collation: schema.Collation{Name: "utf8mb4_bin", Binary: true}},
 			"bu":  schema.Column{Name: "bu", Type: schema.Type{Name: "bigint unsigned", Mods: []int64{20}, ArrayBounds: []int64(nil)}, NotNull: false, Ignored: schema.Ignored{Check: false, Identity: false, Default: false, Exclusion: false, ForeignKey: false, AutoIncrement: false}, Id: ""}},
 			PrimaryKeys: []schema.Key{schema.Key{ColId: "id", Desc: false, Order: 0}},
 			ForeignKeys: []schema.ForeignKey{schema.ForeignKey{Name: "fk_test4", ColIds: []string{"id", "txt"}, ReferTableId: "test_ref", ReferColumnIds: []string{"ref_id", "ref_txt"}, OnUpdate: constants.FK_RESTRICT, OnDelete: constants.FK_CASCADE, Id: ""}},
@@ -354,11 +354,11 @@ func TestProcessSchemaMYSQL(t *testing.T) {
 func TestProcessSchemaMYSQLPKOrdering(t *testing.T) {
 	ms := []mockSpec{
 		{
-			query: "SELECT (.+) FROM information_schema.tables where table_type = 'BASE TABLE'  and (.+)",
+			query: "SELECT (.+) FROM information_schema.tables (.+)",
 			args:  []driver.Value{"test"},
-			cols:  []string{"table_name"},
+			cols:  []string{"table_name", "table_comment", "partition_method", "partition_expression"},
 			rows: [][]driver.Value{
-				{"pk_order"},
+				{"pk_order", "", nil, nil},
 			},
 		},
 		{
@@ -386,10 +386,10 @@ func TestProcessSchemaMYSQLPKOrdering(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"test", "pk_order"},
-			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra"},
+			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra", "column_comment", "collation_name"},
 			rows: [][]driver.Value{
-				{"pk_1", "text", "text", "NO", nil, nil, nil, nil, nil, nil},
-				{"pk_2", "text", "text", "NO", nil, nil, nil, nil, nil, nil},
+				{"pk_1", "text", "text", "NO", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"pk_2", "text", "text", "NO", nil, nil, nil, nil, nil, nil, nil, nil},
 			},
 		},
 		{
@@ -400,7 +400,7 @@ func TestProcessSchemaMYSQLPKOrdering(t *testing.T) {
 	}
 	db := mkMockDB(t, ms)
 	conv := internal.MakeConv()
-	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}}
+	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, nil}
 	commonInfoSchema := common.InfoSchemaImpl{}
 	_, err := commonInfoSchema.GenerateSrcSchema(conv, isi, 1)
 	assert.Nil(t, err)
@@ -473,7 +473,7 @@ func TestProcessData(t *testing.T) {
 		func(table string, cols []string, vals []interface{}) {
 			rows = append(rows, spannerData{table: table, cols: cols, vals: vals})
 		})
-	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}}
+	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, nil}
 	commonInfoSchema := common.InfoSchemaImpl{}
 	commonInfoSchema.ProcessData(conv, isi, internal.AdditionalDataAttributes{})
 	assert.Equal(t,
@@ -495,10 +495,10 @@ func TestProcessData_MultiCol(t *testing.T) {
 	// ProcessInfoSchema.
 	ms := []mockSpec{
 		{
-			query: "SELECT table_name FROM information_schema.tables where table_type = 'BASE TABLE' and (.+)",
+			query: "SELECT (.+) FROM information_schema.tables (.+)",
 			args:  []driver.Value{"test"},
-			cols:  []string{"table_name"},
-			rows:  [][]driver.Value{{"test"}},
+			cols:  []string{"table_name", "table_comment", "partition_method", "partition_expression"},
+			rows:  [][]driver.Value{{"test", "", nil, nil}},
 		},
 		{
 			query: regexp.QuoteMeta(`SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES WHERE (TABLE_SCHEMA = 'information_schema' OR TABLE_SCHEMA = 'INFORMATION_SCHEMA') AND TABLE_NAME = 'CHECK_CONSTRAINTS';`),
@@ -522,11 +522,11 @@ func TestProcessData_MultiCol(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"test", "test"},
-			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra"},
+			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra", "column_comment", "collation_name"},
 			rows: [][]driver.Value{
-				{"a", "text", "text", "NO", nil, nil, nil, nil, nil, nil},
-				{"b", "double", "double", "YES", nil, nil, 53, nil, []byte("a+2.0"), "STORED GENERATED"}, // Maps to STORED
-				{"c", "bigint", "bigint", "YES", nil, nil, 64, 0, []byte("a+1"), "VIRTUAL GENERATED"},    // Maps to VIRTUAL
+				{"a", "text", "text", "NO", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"b", "double", "double", "YES", nil, nil, 53, nil, []byte("a+2.0"), "STORED GENERATED", nil, nil}, // Maps to STORED
+				{"c", "bigint", "bigint", "YES", nil, nil, 64, 0, []byte("a+1"), "VIRTUAL GENERATED", nil, nil},    // Maps to VIRTUAL
 			},
 		},
 		{
@@ -548,7 +548,7 @@ func TestProcessData_MultiCol(t *testing.T) {
 	conv.Source = constants.MYSQL
 	conv.SpProjectId = "p"
 	conv.SpInstanceId = "i"
-	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}}
+	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, nil}
 	processSchema := common.ProcessSchemaImpl{}
 	mockAccessor := &expressions_api.MockExpressionVerificationAccessor{
 		RefreshSpannerClientMock: func(ctx context.Context, project, instance string) error {
@@ -655,10 +655,10 @@ func TestProcessSchema_Sharded(t *testing.T) {
 	// ProcessInfoSchema.
 	ms := []mockSpec{
 		{
-			query: "SELECT table_name FROM information_schema.tables where table_type = 'BASE TABLE' and (.+)",
+			query: "SELECT (.+) FROM information_schema.tables (.+)",
 			args:  []driver.Value{"test"},
-			cols:  []string{"table_name"},
-			rows:  [][]driver.Value{{"test"}},
+			cols:  []string{"table_name", "table_comment", "partition_method", "partition_expression"},
+			rows:  [][]driver.Value{{"test", "", nil, nil}},
 		},
 		{
 			query: regexp.QuoteMeta(`SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES WHERE (TABLE_SCHEMA = 'information_schema' OR TABLE_SCHEMA = 'INFORMATION_SCHEMA') AND TABLE_NAME = 'CHECK_CONSTRAINTS';`),
@@ -682,11 +682,11 @@ func TestProcessSchema_Sharded(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"test", "test"},
-			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra"},
+			cols:  []string{"column_name", "data_type", "column_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "generation_expression", "extra", "column_comment", "collation_name"},
 			rows: [][]driver.Value{
-				{"a", "text", "text", "NO", nil, nil, nil, nil, nil, nil},
-				{"b", "double", "double", "YES", nil, nil, 53, nil, []byte("a+2.0"), nil},                     // Maps to STORED
-				{"c", "bigint", "bigint", "YES", nil, nil, 64, 0, []byte("a+1"), []byte("VIRTUAL GENERATED")}, // Maps to VIRTUAL
+				{"a", "text", "text", "NO", nil, nil, nil, nil, nil, nil, nil, nil},
+				{"b", "double", "double", "YES", nil, nil, 53, nil, []byte("a+2.0"), nil, nil, nil},                     // Maps to STORED
+				{"c", "bigint", "bigint", "YES", nil, nil, 64, 0, []byte("a+1"), []byte("VIRTUAL GENERATED"), nil, nil}, // Maps to VIRTUAL
 			},
 		},
 		{
@@ -708,7 +708,7 @@ func TestProcessSchema_Sharded(t *testing.T) {
 	conv.Source = constants.MYSQL
 	conv.SpProjectId = "p"
 	conv.SpInstanceId = "i"
-	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}}
+	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, nil}
 	mockAccessor := &expressions_api.MockExpressionVerificationAccessor{
 		RefreshSpannerClientMock: func(ctx context.Context, project, instance string) error {
 			return nil
@@ -784,10 +784,10 @@ func TestProcessSchema_Sharded(t *testing.T) {
 func TestSetRowStats(t *testing.T) {
 	ms := []mockSpec{
 		{
-			query: "SELECT table_name FROM information_schema.tables where table_type = 'BASE TABLE' and (.+)",
+			query: "SELECT (.+) FROM information_schema.tables (.+)",
 			args:  []driver.Value{"test"},
-			cols:  []string{"table_name"},
-			rows:  [][]driver.Value{{"test1"}, {"test2"}},
+			cols:  []string{"table_name", "table_comment", "partition_method", "partition_expression"},
+			rows:  [][]driver.Value{{"test1", "", nil, nil}, {"test2", "", nil, nil}},
 		}, {
 			query: "SELECT COUNT[(][*][)] FROM `test`.`test1`",
 			cols:  []string{"count"},
@@ -801,7 +801,7 @@ func TestSetRowStats(t *testing.T) {
 	db := mkMockDB(t, ms)
 	conv := internal.MakeConv()
 	conv.SetDataMode()
-	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}}
+	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, nil}
 	commonInfoSchema := common.InfoSchemaImpl{}
 	commonInfoSchema.SetRowStats(conv, isi)
 	assert.Equal(t, int64(5), conv.Stats.Rows["test1"])
@@ -809,6 +809,37 @@ func TestSetRowStats(t *testing.T) {
 	assert.Equal(t, int64(0), conv.Unexpecteds())
 }
 
+func TestEstimateRowCounts(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: regexp.QuoteMeta("SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?"),
+			args:  []driver.Value{"test", "test1"},
+			cols:  []string{"TABLE_ROWS"},
+			rows:  [][]driver.Value{{1200}},
+		},
+	}
+	db := mkMockDB(t, ms)
+	conv := internal.MakeConv()
+	conv.SrcSchema = map[string]schema.Table{"t1": {Name: "test1", Id: "t1"}}
+	isi := InfoSchemaImpl{"test", db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, nil}
+	isi.EstimateRowCounts(conv)
+	assert.Equal(t, map[string]int64{"t1": 1200}, conv.SrcRowCounts)
+}
+
+func TestSessionLocation(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: regexp.QuoteMeta("SELECT @@session.time_zone, @@global.system_time_zone"),
+			cols:  []string{"time_zone", "system_time_zone"},
+			rows:  [][]driver.Value{{"SYSTEM", "Europe/Paris"}},
+		},
+	}
+	isi := InfoSchemaImpl{"test", mkMockDB(t, ms), "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, nil}
+	loc, err := isi.sessionLocation()
+	assert.Nil(t, err)
+	assert.Equal(t, "Europe/Paris", loc.String())
+}
+
 func mkMockDB(t *testing.T, ms []mockSpec) *sql.DB {
 	db, mock, err := sqlmock.New()
 	assert.Nil(t, err)
@@ -879,3 +910,67 @@ func TestGetConstraints_CheckConstraintsTableAbsent(t *testing.T) {
 	_, _, _, err := isi.GetConstraints(conv, common.SchemaAndName{Schema: "your_schema", Name: "your_table"})
 	assert.Error(t, err)
 }
+
+func TestGetTables_Partitioning(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "SELECT (.+) FROM information_schema.tables (.+)",
+			args:  []driver.Value{"test"},
+			cols:  []string{"table_name", "table_comment", "partition_method", "partition_expression"},
+			rows: [][]driver.Value{
+				{"events", "", "RANGE", "year(`created_at`)"},
+				{"users", "", nil, nil},
+			},
+		},
+	}
+	db := mkMockDB(t, ms)
+	isi := InfoSchemaImpl{Db: db, DbName: "test"}
+	tables, err := isi.GetTables()
+	assert.NoError(t, err)
+	assert.Equal(t, []common.SchemaAndName{
+		{Schema: "test", Name: "events", Partitioning: schema.Partitioning{Method: "RANGE", Expression: "year(`created_at`)", ColumnNames: []string{"created_at"}}},
+		{Schema: "test", Name: "users"},
+	}, tables)
+}
+
+func TestGetTables_MergedDatabases(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "SELECT (.+) FROM information_schema.tables (.+)",
+			args:  []driver.Value{"shop"},
+			cols:  []string{"table_name", "table_comment", "partition_method", "partition_expression"},
+			rows:  [][]driver.Value{{"orders", "", nil, nil}},
+		},
+		{
+			query: "SELECT (.+) FROM information_schema.tables (.+)",
+			args:  []driver.Value{"crm"},
+			cols:  []string{"table_name", "table_comment", "partition_method", "partition_expression"},
+			rows:  [][]driver.Value{{"orders", "", nil, nil}},
+		},
+	}
+	db := mkMockDB(t, ms)
+	sourceProfile := profiles.SourceProfile{Conn: profiles.SourceProfileConnection{Mysql: profiles.SourceProfileConnectionMySQL{DbNames: []string{"shop", "crm"}, MultiDb: constants.MULTI_DB_MERGED}}}
+	isi := InfoSchemaImpl{Db: db, SourceProfile: sourceProfile}
+	tables, err := isi.GetTables()
+	assert.NoError(t, err)
+	assert.Equal(t, []common.SchemaAndName{{Schema: "shop", Name: "orders"}, {Schema: "crm", Name: "orders"}}, tables)
+	assert.Equal(t, "crm.orders", isi.GetTableName("crm", "orders"))
+	assert.Equal(t, "orders", InfoSchemaImpl{DbName: "crm"}.GetTableName("crm", "orders"))
+}
+
+func TestToPartitioning(t *testing.T) {
+	tests := []struct {
+		method     string
+		expression string
+		expected   schema.Partitioning
+	}{
+		{"", "", schema.Partitioning{}},
+		{"RANGE", "year(`created_at`)", schema.Partitioning{Method: "RANGE", Expression: "year(`created_at`)", ColumnNames: []string{"created_at"}}},
+		{"RANGE COLUMNS", "`region`,`created_at`", schema.Partitioning{Method: "RANGE COLUMNS", Expression: "`region`,`created_at`", ColumnNames: []string{"region", "created_at"}}},
+		{"hash", "TO_DAYS(created_at)", schema.Partitioning{Method: "HASH", Expression: "TO_DAYS(created_at)", ColumnNames: []string{"created_at"}}},
+		{"KEY", "id", schema.Partitioning{Method: "KEY", Expression: "id", ColumnNames: []string{"id"}}},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, toPartitioning(tc.method, tc.expression))
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/csv"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+)
+
+// loadDataNull is the value of NULL fields passed to the CSV conversion,
+// chosen so that it can't be mistaken for the value of a field.
+const loadDataNull = "\x00NULL\x00"
+
+// processLoadDataStmt loads the CSV or TSV file referenced by a LOAD DATA
+// INFILE statement, e.g. written by mysqldump --tab, through the CSV
+// conversion path. The file can be local, with a path relative to the working
+// directory, or in GCS. In schema mode, the rows of the file are counted. The
+// columns set from user variables are not supported and left unset.
+func processLoadDataStmt(conv *internal.Conv, stmt *ast.LoadDataStmt) {
+	if stmt.Table == nil {
+		logStmtError(conv, stmt, fmt.Errorf("table is nil"))
+		return
+	}
+	srcTable, err := getTableName(stmt.Table)
+	if err != nil {
+		logStmtError(conv, stmt, fmt.Errorf("can't get table name: %w", err))
+		return
+	}
+	tableId, err := internal.GetTableIdFromSrcName(conv.SrcSchema, srcTable)
+	if err != nil {
+		logStmtError(conv, stmt, fmt.Errorf("can't get schema of table %s", srcTable))
+		return
+	}
+	srcSchema := conv.SrcSchema[tableId]
+	// The Spanner schema is built once the whole dump is read: in schema
+	// mode, the rows of the file are only counted.
+	var spSchema ddl.CreateTable
+	// Spanner column of each field of the file, "" for fields which aren't loaded.
+	var spCols []string
+	if !conv.SchemaMode() {
+		var ok bool
+		spSchema, ok = conv.SpSchema[tableId]
+		if !ok {
+			logStmtError(conv, stmt, fmt.Errorf("can't get Spanner schema of table %s", srcTable))
+			return
+		}
+		if len(stmt.ColumnsAndUserVars) == 0 {
+			for _, colId := range srcSchema.ColIds {
+				spCols = append(spCols, spSchema.ColDefs[colId].Name)
+			}
+		}
+		for _, c := range stmt.ColumnsAndUserVars {
+			if c.ColumnName == nil {
+				conv.Unexpected(fmt.Sprintf("LOAD DATA of table %s sets columns from user variables, which is not supported", srcTable))
+				spCols = append(spCols, "")
+				continue
+			}
+			colId, err := internal.GetColIdFromSrcName(srcSchema.ColDefs, c.ColumnName.Name.String())
+			if err != nil {
+				logStmtError(conv, stmt, fmt.Errorf("can't find column %s of table %s", c.ColumnName.Name.String(), srcTable))
+				return
+			}
+			spCols = append(spCols, spSchema.ColDefs[colId].Name)
+		}
+	}
+
+	f, err := openLoadDataFile(stmt.Path)
+	if err != nil {
+		logStmtError(conv, stmt, fmt.Errorf("can't open file %s loaded into table %s: %w", stmt.Path, srcTable, err))
+		return
+	}
+	defer f.Close()
+	r := newLoadDataReader(f, stmt.FieldsInfo, stmt.LinesInfo)
+	if stmt.IgnoreLines != nil {
+		for i := uint64(0); i < *stmt.IgnoreLines; i++ {
+			if _, err := r.Read(); err != nil {
+				break
+			}
+		}
+	}
+	if conv.SchemaMode() {
+		conv.DataStatement(NodeType(stmt))
+	}
+	logger.Log.Info(fmt.Sprintf("Loading file %s into table %s", stmt.Path, srcTable))
+	for {
+		fields, err := r.Read()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			logStmtError(conv, stmt, fmt.Errorf("can't read file %s: %w", stmt.Path, err))
+			return
+		}
+		if conv.SchemaMode() {
+			conv.Stats.Rows[srcTable]++
+			continue
+		}
+		var cols, vals, colIds []string
+		for i, v := range fields {
+			if i >= len(spCols) || spCols[i] == "" {
+				continue
+			}
+			colId, _ := internal.GetColIdFromSpName(spSchema.ColDefs, spCols[i])
+			cols = append(cols, spCols[i])
+			vals = append(vals, v)
+			colIds = append(colIds, colId)
+		}
+		vals = conv.ToUTF8(tableId, colIds, vals)
+		csv.ProcessDataRow(conv, loadDataNull, spSchema.Name, cols, spSchema.ColDefs, vals)
+	}
+}
+
+// openLoadDataFile opens the local file or GCS object at path.
+func openLoadDataFile(path string) (*os.File, error) {
+	u, err := url.Parse(path)
+	if err == nil && u.Scheme == constants.GCS_SCHEME {
+		return utils.DownloadFromGCS(u.Host, strings.TrimPrefix(u.Path, "/"), "spanner-migration-tool.load_data")
+	}
+	return os.Open(path)
+}
+
+// loadDataReader reads the rows of a file in the format of LOAD DATA, as set
+// by its FIELDS and LINES clauses. NULL fields are read as loadDataNull.
+type loadDataReader struct {
+	r           *bufio.Reader
+	fieldTerm   []byte
+	lineTerm    []byte
+	lineStart   []byte
+	enclosed    byte // 0 if fields aren't enclosed.
+	escaped     byte // 0 if there is no escape character.
+	hasEnclosed bool
+}
+
+func newLoadDataReader(r io.Reader, fields *ast.FieldsClause, lines *ast.LinesClause) *loadDataReader {
+	// Defaults of MySQL: FIELDS TERMINATED BY '\t' ENCLOSED BY '' ESCAPED BY '\\'
+	// LINES TERMINATED BY '\n' STARTING BY ''.
+	l := &loadDataReader{r: bufio.NewReader(r), fieldTerm: []byte("\t"), lineTerm: []byte("\n"), escaped: '\\'}
+	if fields != nil {
+		if fields.Terminated != nil {
+			l.fieldTerm = []byte(*fields.Terminated)
+		}
+		if fields.Enclosed != nil && len(*fields.Enclosed) == 1 {
+			l.enclosed = (*fields.Enclosed)[0]
+			l.hasEnclosed = true
+		}
+		if fields.Escaped != nil {
+			l.escaped = 0
+			if len(*fields.Escaped) == 1 {
+				l.escaped = (*fields.Escaped)[0]
+			}
+		}
+	}
+	if lines != nil {
+		if lines.Terminated != nil {
+			l.lineTerm = []byte(*lines.Terminated)
+		}
+		if lines.Starting != nil {
+			l.lineStart = []byte(*lines.Starting)
+		}
+	}
+	return l
+}
+
+// Read returns the fields of the next row, or io.EOF at the end of the file.
+func (l *loadDataReader) Read() ([]string, error) {
+	if len(l.lineStart) > 0 {
+		// Lines which don't have the prefix are skipped.
+		if err := l.skipTo(l.lineStart); err != nil {
+			return nil, err
+		}
+	} else if _, err := l.r.Peek(1); err != nil {
+		return nil, err
+	}
+	var fields []string
+	for {
+		field, endOfLine, err := l.readField()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		fields = append(fields, field)
+		if endOfLine || err == io.EOF {
+			return fields, nil
+		}
+	}
+}
+
+// readField reads a field and its terminator, and returns whether the
+// terminator ends the line.
+func (l *loadDataReader) readField() (string, bool, error) {
+	var b bytes.Buffer
+	quoted := false
+	if l.hasEnclosed {
+		if c, err := l.r.Peek(1); err == nil && c[0] == l.enclosed {
+			l.r.ReadByte()
+			quoted = true
+		}
+	}
+	raw := true // The field has no escape sequences, for NULL detection.
+	for {
+		if !quoted {
+			if l.consume(l.lineTerm) {
+				return l.fieldValue(b.String(), raw), true, nil
+			}
+			if l.consume(l.fieldTerm) {
+				return l.fieldValue(b.String(), raw), false, nil
+			}
+		}
+		c, err := l.r.ReadByte()
+		if err == io.EOF {
+			if quoted {
+				return b.String(), true, io.EOF
+			}
+			return l.fieldValue(b.String(), raw), true, io.EOF
+		}
+		if err != nil {
+			return "", false, err
+		}
+		switch {
+		case l.escaped != 0 && c == l.escaped:
+			e, err := l.r.ReadByte()
+			if err != nil {
+				b.WriteByte(c)
+				continue
+			}
+			if e == 'N' && !quoted && b.Len() == 0 && raw {
+				// \N is NULL, unless other characters follow it.
+				if l.atTerminator() {
+					b.WriteString(loadDataNull)
+					raw = false
+					continue
+				}
+			}
+			raw = false
+			b.WriteByte(unescapeLoadData(e))
+		case quoted && c == l.enclosed:
+			// A doubled enclosing character is the character itself.
+			if next, err := l.r.Peek(1); err == nil && next[0] == l.enclosed {
+				l.r.ReadByte()
+				b.WriteByte(c)
+				continue
+			}
+			quoted = false
+			raw = false
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+// fieldValue returns the value of a field which isn't enclosed: the word NULL
+// is NULL when fields can be enclosed.
+func (l *loadDataReader) fieldValue(s string, raw bool) string {
+	if raw && l.hasEnclosed && s == "NULL" {
+		return loadDataNull
+	}
+	return s
+}
+
+// atTerminator returns true if the next bytes are a field or line terminator,
+// or the end of the file.
+func (l *loadDataReader) atTerminator() bool {
+	for _, t := range [][]byte{l.fieldTerm, l.lineTerm} {
+		if next, err := l.r.Peek(len(t)); err == nil && bytes.Equal(next, t) {
+			return true
+		}
+	}
+	_, err := l.r.Peek(1)
+	return err == io.EOF
+}
+
+// consume reads t if it is next in the file.
+func (l *loadDataReader) consume(t []byte) bool {
+	if len(t) == 0 {
+		return false
+	}
+	next, err := l.r.Peek(len(t))
+	if err != nil || !bytes.Equal(next, t) {
+		return false
+	}
+	l.r.Discard(len(t))
+	return true
+}
+
+// skipTo reads up to and including the next occurrence of prefix.
+func (l *loadDataReader) skipTo(prefix []byte) error {
+	for {
+		if l.consume(prefix) {
+			return nil
+		}
+		if _, err := l.r.ReadByte(); err != nil {
+			return err
+		}
+	}
+}
+
+// unescapeLoadData returns the character of the escape sequence \c.
+func unescapeLoadData(c byte) byte {
+	switch c {
+	case '0':
+		return 0
+	case 'b':
+		return '\b'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'Z':
+		return 0x1a
+	default:
+		return c
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/tidb/pkg/parser/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadDataReader(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name     string
+		input    string
+		fields   *ast.FieldsClause
+		lines    *ast.LinesClause
+		expected [][]string
+	}{
+		{
+			name:  "Defaults",
+			input: "1\ta\\tb\t\\N\n2\tx\\\\N\tNULL\n3\t\\0\t\n",
+			expected: [][]string{
+				{"1", "a\tb", loadDataNull},
+				{"2", "x\\N", "NULL"},
+				{"3", "\x00", ""},
+			},
+		},
+		{
+			name:   "Enclosed CSV",
+			input:  "1,\"a,b\",NULL\r\n2,\"say \"\"hi\"\"\",\"NULL\"\r\n3,\"line\nbreak\",\\N",
+			fields: &ast.FieldsClause{Terminated: str(","), Enclosed: str("\"")},
+			lines:  &ast.LinesClause{Terminated: str("\r\n")},
+			expected: [][]string{
+				{"1", "a,b", loadDataNull},
+				{"2", "say \"hi\"", "NULL"},
+				{"3", "line\nbreak", loadDataNull},
+			},
+		},
+		{
+			name:   "No escape and line prefix",
+			input:  "xxx|1|a\\b\nignored\nxxx|2|c\n",
+			fields: &ast.FieldsClause{Terminated: str("|"), Escaped: str("")},
+			lines:  &ast.LinesClause{Starting: str("xxx|")},
+			expected: [][]string{
+				{"1", "a\\b"},
+				{"2", "c"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		r := newLoadDataReader(strings.NewReader(tc.input), tc.fields, tc.lines)
+		var rows [][]string
+		for {
+			fields, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err, tc.name)
+			if err != nil {
+				break
+			}
+			rows = append(rows, fields)
+		}
+		assert.Equal(t, tc.expected, rows, tc.name)
+	}
+}
+
+func TestProcessMySQLDump_LoadData(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.txt")
+	if err := os.WriteFile(path, []byte("id,name,ignored\n1,\"a\",x\n2,NULL,y\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	conv, rows := runProcessMySQLDump("CREATE TABLE users (id bigint PRIMARY KEY, name varchar(10));\n" +
+		"LOAD DATA LOCAL INFILE '" + path + "' INTO TABLE users FIELDS TERMINATED BY ',' ENCLOSED BY '\"' IGNORE 1 LINES (id, name, @ignored);\n" +
+		"LOAD DATA INFILE '" + filepath.Join(t.TempDir(), "missing.txt") + "' INTO TABLE users;\n")
+	assert.Equal(t, []spannerData{
+		{table: "users", cols: []string{"id", "name"}, vals: []interface{}{int64(1), "a"}},
+		{table: "users", cols: []string{"id"}, vals: []interface{}{int64(2)}},
+	}, rows)
+	assert.Equal(t, int64(2), conv.Stats.Rows["users"])
+	// The missing file is reported.
+	assert.Equal(t, int64(1), conv.Stats.Statement["LoadDataStmt"].Error)
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/pingcap/tidb/pkg/parser/ast"
+)
+
+// mydumperMetadataFile is the file written by mydumper with the start and
+// end time of the export, and the binlog position it is consistent with.
+const mydumperMetadataFile = "metadata"
+
+// mydumperDataFileRegexp matches the data files of mydumper exports:
+// db.table.sql, or db.table.00000.sql for the chunks of large tables.
+var mydumperDataFileRegexp = regexp.MustCompile(`^[^.]+\.[^.]+(\.\d+)*\.sql$`)
+
+// MydumperMetadata is the content of the metadata file of a mydumper export.
+// The binlog position can be used to start replicating the changes made to
+// the source database after the export.
+type MydumperMetadata struct {
+	Started    string
+	Finished   string
+	BinlogFile string
+	BinlogPos  string
+	GtidSet    string
+}
+
+// IsMydumperExport returns true if dir has the metadata file written by
+// mydumper.
+func IsMydumperExport(dir string) bool {
+	if dir == "" {
+		return false
+	}
+	fi, err := os.Stat(filepath.Join(dir, mydumperMetadataFile))
+	return err == nil && !fi.IsDir()
+}
+
+// ReadMydumperMetadata parses the metadata file of the mydumper export in
+// dir. Both the "SHOW MASTER STATUS:" layout of older versions of mydumper
+// and the ini layout of newer versions are supported. Only the first binlog
+// position is kept, which is the one of the exported server.
+func ReadMydumperMetadata(dir string) (MydumperMetadata, error) {
+	var m MydumperMetadata
+	b, err := os.ReadFile(filepath.Join(dir, mydumperMetadataFile))
+	if err != nil {
+		return m, fmt.Errorf("can't read mydumper metadata: %w", err)
+	}
+	set := func(field *string, v string) {
+		if *field == "" {
+			*field = v
+		}
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		i := strings.IndexAny(line, ":=")
+		if i < 0 {
+			continue
+		}
+		key := strings.TrimSpace(line[:i])
+		v := strings.Trim(strings.TrimSpace(line[i+1:]), `'"`)
+		switch key {
+		case "Started dump at":
+			set(&m.Started, v)
+		case "Finished dump at":
+			set(&m.Finished, v)
+		case "Log", "File":
+			set(&m.BinlogFile, v)
+		case "Pos", "Position":
+			set(&m.BinlogPos, v)
+		case "GTID", "Executed_Gtid_Set":
+			set(&m.GtidSet, v)
+		}
+	}
+	return m, nil
+}
+
+// MydumperDataFiles returns the data files of the mydumper export in dir,
+// sorted by name so that the chunks of a table are next to each other.
+// Schema files (*-schema*.sql) are excluded.
+func MydumperDataFiles(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, p := range paths {
+		base := filepath.Base(p)
+		if strings.Contains(base, "-schema") || !mydumperDataFileRegexp.MatchString(base) {
+			continue
+		}
+		files = append(files, p)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// mydumperTable returns the name of the table of the data file at path.
+func mydumperTable(path string) string {
+	return strings.Split(filepath.Base(path), ".")[1]
+}
+
+// mydumperChunk is the statements parsed from a part of a data file, or the
+// error reading the file. reparsed is the number of times the file was
+// reparsed looking for the end of a statement, set once the file is read.
+type mydumperChunk struct {
+	stmts    []ast.StmtNode
+	reparsed int64
+	err      error
+}
+
+// ProcessMydumperData loads the data files of the mydumper export in dir,
+// using conv's schema as for a mysqldump. Data files are read and parsed by
+// parallelism workers, which is much faster than a single dump stream for
+// exports of large tables split in chunks. Statements are applied to conv
+// one at a time, on the calling goroutine, in no particular order across
+// files.
+func ProcessMydumperData(conv *internal.Conv, dir string, parallelism int) error {
+	if m, err := ReadMydumperMetadata(dir); err != nil {
+		logger.Log.Warn(err.Error())
+	} else {
+		logger.Log.Info(fmt.Sprintf("Loading mydumper export started at %s, finished at %s, consistent with binlog file %s position %s (GTID set %q)", m.Started, m.Finished, m.BinlogFile, m.BinlogPos, m.GtidSet))
+	}
+	files, err := MydumperDataFiles(dir)
+	if err != nil {
+		return err
+	}
+	// Files of skipped tables, e.g. outside the current round of a sampled
+	// data migration, aren't read.
+	files = slices.DeleteFunc(files, func(f string) bool { return conv.SkipTable(mydumperTable(f)) })
+	// Files of the priority classes of conv.TableOrder are loaded first.
+	sort.SliceStable(files, func(i, j int) bool {
+		return conv.TableOrder.Class(mydumperTable(files[i])) < conv.TableOrder.Class(mydumperTable(files[j]))
+	})
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	logger.Log.Info(fmt.Sprintf("Loading %d mydumper data files with %d workers", len(files), parallelism))
+
+	// Files not started yet are skipped once the migration is interrupted.
+	ctx := conv.Context()
+	fileCh := make(chan string)
+	chunkCh := make(chan mydumperChunk, parallelism*10)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range fileCh {
+				readMydumperDataFile(f, chunkCh)
+			}
+		}()
+	}
+	go func() {
+		defer close(fileCh)
+		for _, f := range files {
+			select {
+			case fileCh <- f:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(chunkCh)
+	}()
+	var errs []string
+	for chunk := range chunkCh {
+		if conv.Interrupted() {
+			// Drain the files being read by the workers.
+			continue
+		}
+		if chunk.err != nil {
+			errs = append(errs, chunk.err.Error())
+			continue
+		}
+		conv.Stats.Reparsed += chunk.reparsed
+		for _, stmt := range chunk.stmts {
+			processStatement(conv, stmt)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("can't load %d mydumper data files: %s", len(errs), strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// readMydumperDataFile parses the data file at path and sends its statements
+// to out. Parsing is done with a conv of its own since conv isn't safe for
+// concurrent use; in data mode, parsing only updates the reparse count.
+func readMydumperDataFile(path string, out chan<- mydumperChunk) {
+	f, err := os.Open(path)
+	if err != nil {
+		out <- mydumperChunk{err: err}
+		return
+	}
+	defer f.Close()
+	scratch := internal.MakeConv()
+	scratch.SetDataMode()
+	r := internal.NewReader(bufio.NewReader(f), nil)
+	for !r.EOF {
+		_, stmts, err := readAndParseChunk(scratch, r)
+		if err != nil {
+			out <- mydumperChunk{err: fmt.Errorf("%s: %w", filepath.Base(path), err)}
+			return
+		}
+		if len(stmts) > 0 {
+			out <- mydumperChunk{stmts: stmts}
+		}
+	}
+	out <- mydumperChunk{reparsed: scratch.Stats.Reparsed}
+	logger.Log.Debug(fmt.Sprintf("Parsed mydumper data file %s", path))
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeMydumperFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestReadMydumperMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+	}{
+		{
+			name: "Show master status layout",
+			metadata: "Started dump at: 2024-05-01 10:00:00\n" +
+				"SHOW MASTER STATUS:\n\tLog: mysql-bin.000003\n\tPos: 154\n\tGTID:3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5\n\n" +
+				"SHOW SLAVE STATUS:\n\tHost: 10.0.0.2\n\tLog: mysql-bin.000001\n\tPos: 4\n\n" +
+				"Finished dump at: 2024-05-01 10:05:00\n",
+		},
+		{
+			name: "Ini layout",
+			metadata: "# Started dump at: 2024-05-01 10:00:00\n[master]\n# Channel_Name = '' # It can be use to setup replication FOR CHANNEL\n" +
+				"File = mysql-bin.000003\nPosition = 154\nExecuted_Gtid_Set = 3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5\n\n" +
+				"# Finished dump at: 2024-05-01 10:05:00\n",
+		},
+	}
+	expected := MydumperMetadata{
+		Started:    "2024-05-01 10:00:00",
+		Finished:   "2024-05-01 10:05:00",
+		BinlogFile: "mysql-bin.000003",
+		BinlogPos:  "154",
+		GtidSet:    "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-5",
+	}
+	for _, tc := range tests {
+		dir := writeMydumperFiles(t, map[string]string{"metadata": tc.metadata})
+		assert.True(t, IsMydumperExport(dir), tc.name)
+		m, err := ReadMydumperMetadata(dir)
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, expected, m, tc.name)
+	}
+	assert.False(t, IsMydumperExport(t.TempDir()))
+	assert.False(t, IsMydumperExport(""))
+}
+
+func TestProcessMydumperData(t *testing.T) {
+	schema := "CREATE TABLE users (id int NOT NULL, name varchar(20), PRIMARY KEY (id));\n" +
+		"CREATE TABLE orders (id int NOT NULL, user_id int, PRIMARY KEY (id));\n"
+	conv, _ := runProcessMySQLDump(schema)
+	dir := writeMydumperFiles(t, map[string]string{
+		"metadata":               "Started dump at: 2024-05-01 10:00:00\n",
+		"shop-schema-create.sql": "CREATE DATABASE `shop`;\n",
+		"shop.users-schema.sql":  "CREATE TABLE `users` (`id` int NOT NULL, `name` varchar(20), PRIMARY KEY (`id`));\n",
+		"shop.orders-schema.sql": "CREATE TABLE `orders` (`id` int NOT NULL, `user_id` int, PRIMARY KEY (`id`));\n",
+		"shop.users.00000.sql":   "/*!40101 SET NAMES binary*/;\nINSERT INTO `users` VALUES (1,'a'),(2,'b');\n",
+		"shop.users.00001.sql":   "INSERT INTO `users` VALUES (3,'c');\n",
+		"shop.orders.sql":        "INSERT INTO `orders` VALUES (10,1);",
+	})
+	files, err := MydumperDataFiles(dir)
+	assert.NoError(t, err)
+	var names []string
+	for _, f := range files {
+		names = append(names, filepath.Base(f))
+	}
+	assert.Equal(t, []string{"shop.orders.sql", "shop.users.00000.sql", "shop.users.00001.sql"}, names)
+
+	var rows []spannerData
+	conv.SetDataSink(func(table string, cols []string, vals []interface{}) {
+		rows = append(rows, spannerData{table: table, cols: cols, vals: vals})
+	})
+	assert.NoError(t, ProcessMydumperData(conv, dir, 2))
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].table != rows[j].table {
+			return rows[i].table < rows[j].table
+		}
+		return rows[i].vals[0].(int64) < rows[j].vals[0].(int64)
+	})
+	assert.Equal(t, []spannerData{
+		{table: "orders", cols: []string{"id", "user_id"}, vals: []interface{}{int64(10), int64(1)}},
+		{table: "users", cols: []string{"id", "name"}, vals: []interface{}{int64(1), "a"}},
+		{table: "users", cols: []string{"id", "name"}, vals: []interface{}{int64(2), "b"}},
+		{table: "users", cols: []string{"id", "name"}, vals: []interface{}{int64(3), "c"}},
+	}, rows)
+}
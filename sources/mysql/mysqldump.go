@@ -15,6 +15,8 @@
 package mysql
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -58,7 +60,7 @@ type DbDumpImpl struct {
 
 // GetToDdl function below implement the common.DbDump interface.
 func (ddi DbDumpImpl) GetToDdl() common.ToDdl {
-	return ToDdlImpl{}
+	return common.LookupToDdl(constants.MYSQL, ToDdlImpl{})
 }
 
 // ProcessDump processes the mysql dump.
@@ -84,7 +86,8 @@ func processMySQLDump(conv *internal.Conv, r *internal.Reader) error {
 			internal.VerbosePrintf("Parsed SQL command at line=%d/fpos=%d: %d stmts (%d lines, %d bytes) Insert Statement=%v\n", startLine, startOffset, 1, r.LineNumber-startLine, len(b), isInsert)
 			logger.Log.Debug(fmt.Sprintf("Parsed SQL command at line=%d/fpos=%d: %d stmts (%d lines, %d bytes) Insert Statement=%v\n", startLine, startOffset, 1, r.LineNumber-startLine, len(b), isInsert))
 		}
-		if r.EOF {
+		// An interrupted data migration stops after the current statement.
+		if r.EOF || conv.Interrupted() {
 			break
 		}
 	}
@@ -157,6 +160,10 @@ func readAndParseChunk(conv *internal.Conv, r *internal.Reader) ([]byte, []ast.S
 // statements, updating Conv with new schema information, and returning
 // true if INSERT statement is encountered.
 func processStatement(conv *internal.Conv, stmt ast.StmtNode) bool {
+	if tableName, ok := stmtTableName(stmt); ok && conv.SkipTable(tableName) {
+		conv.SkipStatement(NodeType(stmt))
+		return false
+	}
 	switch s := stmt.(type) {
 	case *ast.CreateTableStmt:
 		if conv.SchemaMode() {
@@ -173,6 +180,8 @@ func processStatement(conv *internal.Conv, stmt ast.StmtNode) bool {
 	case *ast.InsertStmt:
 		processInsertStmt(conv, s)
 		return true
+	case *ast.LoadDataStmt:
+		processLoadDataStmt(conv, s)
 	case *ast.CreateIndexStmt:
 		if conv.SchemaMode() {
 			processCreateIndex(conv, s)
@@ -183,6 +192,43 @@ func processStatement(conv *internal.Conv, stmt ast.StmtNode) bool {
 	return false
 }
 
+// stmtTableName returns the name of the table of statements which only
+// change or load a single table, for the table filter.
+func stmtTableName(stmt ast.StmtNode) (string, bool) {
+	var name string
+	var err error
+	switch s := stmt.(type) {
+	case *ast.CreateTableStmt:
+		if s.Table == nil {
+			return "", false
+		}
+		name, err = getTableName(s.Table)
+	case *ast.AlterTableStmt:
+		if s.Table == nil {
+			return "", false
+		}
+		name, err = getTableName(s.Table)
+	case *ast.CreateIndexStmt:
+		if s.Table == nil {
+			return "", false
+		}
+		name, err = getTableName(s.Table)
+	case *ast.InsertStmt:
+		if s.Table == nil {
+			return "", false
+		}
+		name, err = getTableNameInsert(s.Table)
+	case *ast.LoadDataStmt:
+		if s.Table == nil {
+			return "", false
+		}
+		name, err = getTableName(s.Table)
+	default:
+		return "", false
+	}
+	return name, err == nil
+}
+
 func processCreateIndex(conv *internal.Conv, stmt *ast.CreateIndexStmt) {
 	if stmt.Table == nil {
 		logStmtError(conv, stmt, fmt.Errorf("cannot process index statement with nil table"))
@@ -212,6 +258,16 @@ func processCreateIndex(conv *internal.Conv, stmt *ast.CreateIndexStmt) {
 func processSetStmt(conv *internal.Conv, stmt *ast.SetStmt) {
 	if stmt.Variables != nil && len(stmt.Variables) > 0 {
 		for _, variable := range stmt.Variables {
+			if variable.Name == ast.SetNames || variable.Name == ast.SetCharset {
+				// The charset of the dump's string values, e.g. SET NAMES latin1
+				// in the header of the dump. Only the first one is kept, and
+				// DEFAULT and the restore statements at the end of the dump
+				// are ignored.
+				if val, ok := variable.Value.(*driver.ValueExpr); ok && conv.SourceCharset == "" {
+					conv.SourceCharset = strings.ToLower(val.GetString())
+				}
+				continue
+			}
 			if variable.Name == "TIME_ZONE" {
 				value := variable.Value
 				switch val := value.(type) {
@@ -256,6 +312,15 @@ func processCreateTable(conv *internal.Conv, stmt *ast.CreateTableStmt) {
 	var index []schema.Index
 
 	checkConstraints := getCheckConstraints(stmt.Constraints)
+	var tableComment, tableCollation string
+	for _, opt := range stmt.Options {
+		switch opt.Tp {
+		case ast.TableOptionComment:
+			tableComment = opt.StrValue
+		case ast.TableOptionCollate:
+			tableCollation = opt.StrValue
+		}
+	}
 
 	for _, element := range stmt.Cols {
 		_, col, constraint, err := processColumn(conv, tableName, element)
@@ -264,6 +329,11 @@ func processCreateTable(conv *internal.Conv, stmt *ast.CreateTableStmt) {
 			return
 		}
 		col.Id = internal.GenerateColumnId() //assigns new id
+		// String columns without their own character set or collation use
+		// the default collation of the table.
+		if col.Collation.Name == "" && tableCollation != "" && element.Tp.GetCharset() == "" && isStringType(col.Type.Name) {
+			col.Collation = toCollation(tableCollation)
+		}
 		colDef[col.Id] = col
 		colIds = append(colIds, col.Id)
 		colNameIdMap[col.Name] = col.Id
@@ -293,6 +363,12 @@ func processCreateTable(conv *internal.Conv, stmt *ast.CreateTableStmt) {
 			})
 		}
 	}
+	partitioning := getPartitioning(stmt.Partition)
+	for _, colName := range partitioning.ColumnNames {
+		if colId, ok := colNameIdMap[colName]; ok {
+			partitioning.ColIds = append(partitioning.ColIds, colId)
+		}
+	}
 	conv.SchemaStatement(NodeType(stmt))
 	conv.SrcSchema[tableId] = schema.Table{
 		Id:               tableId,
@@ -304,6 +380,8 @@ func processCreateTable(conv *internal.Conv, stmt *ast.CreateTableStmt) {
 		ForeignKeys:      fkeys,
 		Indexes:          index,
 		CheckConstraints: checkConstraints,
+		Comment:          tableComment,
+		Partitioning:     partitioning,
 	}
 	for _, constraint := range stmt.Constraints {
 		processConstraint(conv, tableId, constraint, "CREATE TABLE", conv.SrcSchema[tableId].ColNameIdMap)
@@ -357,6 +435,29 @@ func getCheckConstraints(constraints []*ast.Constraint) (checkConstraints []sche
 	return checkConstraints
 }
 
+// getPartitioning returns the partitioning scheme of a CREATE TABLE statement,
+// or an empty Partitioning if the table isn't partitioned.
+func getPartitioning(p *ast.PartitionOptions) schema.Partitioning {
+	if p == nil {
+		return schema.Partitioning{}
+	}
+	method := p.Tp.String()
+	var expression string
+	if len(p.ColumnNames) > 0 {
+		var cols []string
+		for _, c := range p.ColumnNames {
+			cols = append(cols, c.Name.O)
+		}
+		expression = strings.Join(cols, ",")
+		if method == "RANGE" || method == "LIST" {
+			method += " COLUMNS"
+		}
+	} else if p.Expr != nil {
+		expression = expressionToString(p.Expr)
+	}
+	return toPartitioning(method, expression)
+}
+
 // converts an AST expression node to its string representation.
 func expressionToString(expr ast.Node) string {
 	var sb strings.Builder
@@ -532,10 +633,20 @@ func processColumn(conv *internal.Conv, tableName string, col *ast.ColumnDef) (s
 		Name:        tid,
 		Mods:        mods,
 		ArrayBounds: getArrayBounds(col.Tp.String(), col.Tp.GetElems())}
-	column := schema.Column{Name: name, Type: ty}
+	column := schema.Column{Name: name, Type: ty, Collation: toCollation(col.Tp.GetCollate())}
 	return name, column, updateColsByOption(conv, tableName, col, &column), nil
 }
 
+// isStringType returns true if MySQL type ty has a character set and a
+// collation.
+func isStringType(ty string) bool {
+	switch ty {
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "enum", "set":
+		return true
+	}
+	return false
+}
+
 type columnConstraint struct {
 	isPk        bool
 	isUniqueKey bool
@@ -572,8 +683,14 @@ func updateColsByOption(conv *internal.Conv, tableName string, col *ast.ColumnDe
 			}
 		case ast.ColumnOptionUniqKey:
 			cc.isUniqueKey = true
+		case ast.ColumnOptionComment:
+			if v, ok := elem.Expr.(*driver.ValueExpr); ok {
+				column.Comment = fmt.Sprintf("%v", v.GetValue())
+			}
 		case ast.ColumnOptionCheck:
 			column.Ignored.Check = true
+		case ast.ColumnOptionCollate:
+			column.Collation = toCollation(elem.StrValue)
 		case ast.ColumnOptionReference:
 			column := col.Name.String()
 			referTable, err := getTableName(elem.Refer.Table)
@@ -636,10 +753,10 @@ func getTypeModsAndID(conv *internal.Conv, columnType string) (string, []int64)
 	if strings.Contains(id, " ") {
 		id = strings.TrimSuffix(columnType, " BINARY")
 	}
-	// Bigint unsigned comes as bigint(20) UNSIGNED in columnType and is treated as bigint in id.
+	// Unsigned integers come as e.g. bigint(20) UNSIGNED in columnType and are treated as bigint in id.
 	// An extra check is added to respect the unsigned nature.
-	if id == "bigint" && strings.Contains(strings.ToUpper(columnType), "UNSIGNED") {
-		id = "bigint unsigned"
+	if fields := strings.Fields(id); len(fields) > 0 && isUnsignedIntType(fields[0], columnType) {
+		id = fields[0] + " unsigned"
 	}
 	return id, mods
 }
@@ -823,17 +940,24 @@ func processInsertStmt(conv *internal.Conv, stmt *ast.InsertStmt) {
 		logStmtError(conv, stmt, fmt.Errorf("can't get column values"))
 		return
 	}
-	commonColIds := common.IntersectionOfTwoStringSlices(conv.SpSchema[tableId].ColIds, srcColIds)
+	commonColIds := common.AppendColumnTransformInputs(conv, tableId, common.IntersectionOfTwoStringSlices(conv.SpSchema[tableId].ColIds, srcColIds), srcColIds)
 	spSchema := conv.SpSchema[tableId]
 	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
 	for _, row := range stmt.Lists {
 		values, err = getVals(row)
+		if err != nil {
+			conv.Unexpected(fmt.Sprintf("Error while reading values: %s\n", err))
+			conv.StatsAddBadRow(srcSchema.Name, conv.DataMode())
+			conv.CollectBadRowWithReason(srcSchema.Name, srcCols, nil, err.Error())
+			continue
+		}
+		values = conv.ToUTF8(tableId, srcColIds, values)
 		//prepare values
 		newValues, err2 := common.PrepareValues(conv, tableId, colNameIdMap, commonColIds, srcCols, values)
 		if err2 != nil {
 			conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
 			conv.StatsAddBadRow(srcSchema.Name, conv.DataMode())
-			conv.CollectBadRow(srcSchema.Name, srcCols, values)
+			conv.CollectBadRowWithReason(srcSchema.Name, srcCols, values, err2.Error())
 			continue
 		}
 		ProcessDataRow(conv, tableId, commonColIds, srcSchema, spSchema, newValues, internal.AdditionalDataAttributes{ShardId: ""})
@@ -859,7 +983,13 @@ func getVals(row []ast.ExprNode) ([]string, error) {
 	for _, item := range row {
 		switch valueNode := item.(type) {
 		case *driver.ValueExpr:
-			values = append(values, fmt.Sprintf("%v", valueNode.GetValue()))
+			values = append(values, getLiteralVal(valueNode))
+		case *ast.FuncCallExpr:
+			value, err := getBinaryFuncVal(valueNode)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
 		case *ast.UnaryOperationExpr:
 			if valueNode.Op != opcode.Minus {
 				return nil, fmt.Errorf("unexpected UnaryOperationExpr node with opcode %v", valueNode.Op)
@@ -880,6 +1010,54 @@ func getVals(row []ast.ExprNode) ([]string, error) {
 	return values, nil
 }
 
+// getLiteralVal returns the value of a literal. Hexadecimal and bit-value
+// literals, e.g. 0x0102 and X'0102' written by mysqldump --hex-blob or b'101',
+// are binary strings: their bytes are returned rather than their 0x... form,
+// as are the bytes of binary strings such as _binary'...'.
+func getLiteralVal(valExpr *driver.ValueExpr) string {
+	switch v := valExpr.GetValue().(type) {
+	case types.BinaryLiteral:
+		return string(v)
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// getBinaryFuncVal decodes the UNHEX('...') and FROM_BASE64('...') calls
+// used by some tools to write binary values. A NULL argument gives NULL, like
+// in MySQL.
+func getBinaryFuncVal(f *ast.FuncCallExpr) (string, error) {
+	name := f.FnName.L
+	if name != ast.Unhex && name != ast.FromBase64 {
+		return "", fmt.Errorf("unexpected function call %s in values", f.FnName.O)
+	}
+	if len(f.Args) != 1 {
+		return "", fmt.Errorf("unexpected number of arguments for %s: %d", f.FnName.O, len(f.Args))
+	}
+	arg, ok := f.Args[0].(*driver.ValueExpr)
+	if !ok {
+		return "", fmt.Errorf("unexpected argument of %s with type %T", f.FnName.O, f.Args[0])
+	}
+	if arg.GetValue() == nil {
+		return "NULL", nil
+	}
+	s := getLiteralVal(arg)
+	var b []byte
+	var err error
+	if name == ast.Unhex {
+		b, err = hex.DecodeString(s)
+	} else {
+		// MySQL ignores whitespace, e.g. the line breaks of long values.
+		b, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+	}
+	if err != nil {
+		return "", fmt.Errorf("can't decode %s argument: %w", f.FnName.O, err)
+	}
+	return string(b), nil
+}
+
 func getNegativeUnaryVals(valExpr *driver.ValueExpr) (string, error) {
 	switch val := valExpr.GetValue().(type) {
 	case int64:
@@ -28,6 +28,7 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/expressions_api"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/mocks"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 	"github.com/stretchr/testify/assert"
@@ -906,6 +907,104 @@ func TestProcessMySQLDump_GetDDL(t *testing.T) {
 	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions), " "))
 }
 
+func TestProcessMySQLDump_Comments(t *testing.T) {
+	conv, _ := runProcessMySQLDump("CREATE TABLE cart (productid text COMMENT 'product id', quantity bigint, PRIMARY KEY (productid)) COMMENT='shopping cart';\n")
+	srcTable, ok := internal.GetSrcTableByName(conv.SrcSchema, "cart")
+	assert.True(t, ok)
+	assert.Equal(t, "shopping cart", srcTable.Comment)
+	assert.Equal(t, "product id", srcTable.ColDefs[srcTable.ColNameIdMap["productid"]].Comment)
+	assert.Equal(t, "", srcTable.ColDefs[srcTable.ColNameIdMap["quantity"]].Comment)
+	spTable := conv.SpSchema[srcTable.Id]
+	assert.Equal(t, "Spanner schema for source table cart (shopping cart)", spTable.Comment)
+	assert.Equal(t, "From: productid text (product id)", spTable.ColDefs[srcTable.ColNameIdMap["productid"]].Comment)
+}
+
+func TestProcessMySQLDump_Partitioning(t *testing.T) {
+	conv, _ := runProcessMySQLDump("CREATE TABLE events (id bigint, created_at datetime, PRIMARY KEY (id, created_at))\n" +
+		"PARTITION BY RANGE (YEAR(created_at)) (PARTITION p0 VALUES LESS THAN (2020), PARTITION p1 VALUES LESS THAN MAXVALUE);\n" +
+		"CREATE TABLE logs (id bigint, region varchar(10), PRIMARY KEY (id, region))\n" +
+		"PARTITION BY LIST COLUMNS (region) (PARTITION p0 VALUES IN ('us'), PARTITION p1 VALUES IN ('eu'));\n")
+	events, ok := internal.GetSrcTableByName(conv.SrcSchema, "events")
+	assert.True(t, ok)
+	assert.Equal(t, "RANGE", events.Partitioning.Method)
+	assert.Equal(t, []string{events.ColNameIdMap["created_at"]}, events.Partitioning.ColIds)
+	logs, ok := internal.GetSrcTableByName(conv.SrcSchema, "logs")
+	assert.True(t, ok)
+	assert.Equal(t, "LIST COLUMNS", logs.Partitioning.Method)
+	assert.Equal(t, "region", logs.Partitioning.Expression)
+	assert.Equal(t, []string{logs.ColNameIdMap["region"]}, logs.Partitioning.ColIds)
+}
+
+func TestProcessMySQLDump_Collations(t *testing.T) {
+	conv, _ := runProcessMySQLDump("CREATE TABLE users (id bigint, email varchar(100) COLLATE utf8mb4_bin, name varchar(100), bio text CHARACTER SET latin1, PRIMARY KEY (id))" +
+		" ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;\n")
+	users, ok := internal.GetSrcTableByName(conv.SrcSchema, "users")
+	assert.True(t, ok)
+	assert.Equal(t, schema.Collation{}, users.ColDefs[users.ColNameIdMap["id"]].Collation)
+	assert.Equal(t, schema.Collation{Name: "utf8mb4_bin", Binary: true}, users.ColDefs[users.ColNameIdMap["email"]].Collation)
+	// Columns without their own collation use the default collation of the table.
+	assert.Equal(t, schema.Collation{Name: "utf8mb4_0900_ai_ci", CaseInsensitive: true}, users.ColDefs[users.ColNameIdMap["name"]].Collation)
+	assert.Equal(t, schema.Collation{}, users.ColDefs[users.ColNameIdMap["bio"]].Collation)
+	issues := conv.SchemaIssues[users.Id].ColumnLevelIssues
+	assert.NotContains(t, issues[users.ColNameIdMap["email"]], internal.CaseInsensitiveCollation)
+	assert.Contains(t, issues[users.ColNameIdMap["name"]], internal.CaseInsensitiveCollation)
+}
+
+func TestProcessMySQLDump_BitLiterals(t *testing.T) {
+	_, rows := runProcessMySQLDump("CREATE TABLE test (a bit(1), b bit(12));\n" +
+		"INSERT INTO test (a, b) VALUES (b'1', b'000100000010'), (0x00, 0x0102);")
+	assert.Equal(t, []spannerData{
+		{table: "test", cols: []string{"a", "b", "synth_id"}, vals: []interface{}{true, []byte{0x01, 0x02}, fmt.Sprintf("%d", bitReverse(0))}},
+		{table: "test", cols: []string{"a", "b", "synth_id"}, vals: []interface{}{false, []byte{0x01, 0x02}, fmt.Sprintf("%d", bitReverse(1))}},
+	}, rows)
+}
+
+func TestProcessMySQLDump_BinaryLiterals(t *testing.T) {
+	conv, rows := runProcessMySQLDump("CREATE TABLE test (id int PRIMARY KEY, a tinyblob, b blob, c mediumblob, d longblob, e varbinary(10), f binary(2));\n" +
+		"INSERT INTO test VALUES (1, 0x0102, X'0304', _binary 0x0506, _binary'\\0\\'a', UNHEX('0708'), FROM_BASE64('CQo=')),\n" +
+		"(2, '', X'', NULL, UNHEX(NULL), FROM_BASE64('Cw\\nw='), b'0000110100001110');\n" +
+		"INSERT INTO test VALUES (3, UNHEX('xyz'), NULL, NULL, NULL, NULL, NULL);\n" +
+		"INSERT INTO test VALUES (4, MD5('a'), NULL, NULL, NULL, NULL, NULL);\n")
+	assert.Equal(t, []spannerData{
+		{table: "test", cols: []string{"id", "a", "b", "c", "d", "e", "f"}, vals: []interface{}{int64(1), []byte{0x01, 0x02}, []byte{0x03, 0x04}, []byte{0x05, 0x06}, []byte{0x00, '\'', 'a'}, []byte{0x07, 0x08}, []byte{0x09, 0x0a}}},
+		{table: "test", cols: []string{"id", "a", "b", "e", "f"}, vals: []interface{}{int64(2), []byte{}, []byte{}, []byte{0x0b, 0x0c}, []byte{0x0d, 0x0e}}},
+	}, rows)
+	// Values which can't be decoded make bad rows.
+	assert.Equal(t, int64(2), conv.BadRows())
+}
+
+func TestProcessMySQLDump_Charset(t *testing.T) {
+	conv, rows := runProcessMySQLDump("/*!40101 SET NAMES latin1 */;\n" +
+		"CREATE TABLE test (id bigint PRIMARY KEY, name varchar(10), data varbinary(10));\n" +
+		"INSERT INTO test VALUES (1, 'caf\xe9', 'caf\xe9');\n" +
+		"/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;\n")
+	assert.Equal(t, "latin1", conv.SourceCharset)
+	assert.Equal(t, []spannerData{
+		{table: "test", cols: []string{"id", "name", "data"}, vals: []interface{}{int64(1), "café", []byte("caf\xe9")}},
+	}, rows)
+}
+
+func TestProcessMySQLDump_TableFilter(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.TableFilter, _ = internal.NewTableFilter("", "tmp_.*")
+	conv, rows := runProcessMySQLDumpWithConv(conv, "CREATE TABLE orders (id bigint PRIMARY KEY, tmp_id bigint);\n"+
+		"CREATE TABLE tmp_orders (id bigint PRIMARY KEY);\n"+
+		"ALTER TABLE orders ADD CONSTRAINT fk FOREIGN KEY (tmp_id) REFERENCES tmp_orders (id);\n"+
+		"CREATE INDEX idx ON tmp_orders (id);\n"+
+		"INSERT INTO orders VALUES (1, 2);\n"+
+		"INSERT INTO tmp_orders VALUES (2);\n")
+	noIssues(conv, t, "Table filter")
+	assert.Equal(t, []string{"tmp_orders"}, conv.ExcludedTables)
+	assert.Equal(t, 1, len(conv.SpSchema))
+	for _, table := range conv.SpSchema {
+		assert.Equal(t, "orders", table.Name)
+		assert.Empty(t, table.ForeignKeys)
+	}
+	assert.Equal(t, []spannerData{
+		{table: "orders", cols: []string{"id", "tmp_id"}, vals: []interface{}{int64(1), int64(2)}},
+	}, rows)
+}
+
 func TestProcessMySQLDump_Rows(t *testing.T) {
 	conv, _ := runProcessMySQLDump("CREATE TABLE cart (a text, n bigint);\n" +
 		"INSERT INTO cart (a, n) VALUES ('a42', 2);")
@@ -973,7 +1072,12 @@ func TestProcessMySQLDump_AddPrimaryKeys(t *testing.T) {
 }
 
 func runProcessMySQLDump(s string) (*internal.Conv, []spannerData) {
-	conv := internal.MakeConv()
+	return runProcessMySQLDumpWithConv(internal.MakeConv(), s)
+}
+
+// runProcessMySQLDumpWithConv is runProcessMySQLDump with conv, e.g. for
+// options set on conv before the schema is built.
+func runProcessMySQLDumpWithConv(conv *internal.Conv, s string) (*internal.Conv, []spannerData) {
 	conv.SetLocation(time.UTC)
 	conv.SetSchemaMode()
 	mockAccessor := new(mocks.MockExpressionVerificationAccessor)
@@ -60,7 +60,22 @@ func getMaxSize(srcType string) (int64, []internal.SchemaIssue) {
 // conversion issues encountered.
 // Functions below implement the common.ToDdl interface
 func (tdi ToDdlImpl) ToSpannerType(conv *internal.Conv, spType string, srcType schema.Type, isPk bool) (ddl.Type, []internal.SchemaIssue) {
+	unsignedPolicyType := ""
+	if spType == "" && srcType.Name == "bigint unsigned" {
+		switch conv.UnsignedIntPolicy {
+		case constants.UNSIGNED_INT_NUMERIC:
+			unsignedPolicyType = ddl.Numeric
+		case constants.UNSIGNED_INT_STRING:
+			unsignedPolicyType = ddl.String
+		}
+	}
 	ty, issues := toSpannerTypeInternal(srcType, spType)
+	if unsignedPolicyType != "" {
+		// Values above math.MaxInt64 don't fit in INT64, map the column to
+		// the type configured by the unsigned integer policy.
+		ty, _ = toSpannerTypeInternal(srcType, unsignedPolicyType)
+		issues = []internal.SchemaIssue{internal.UnsignedInteger}
+	}
 	if len(srcType.ArrayBounds) > 1 {
 		ty = ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
 		issues = append(issues, internal.MultiDimensionalArray)
@@ -164,7 +179,7 @@ func toSpannerTypeInternal(srcType schema.Type, spType string) (ddl.Type, []inte
 		default:
 			return ddl.Type{Name: ddl.Int64}, []internal.SchemaIssue{internal.PossibleOverflow}
 		}
-	case "smallint", "mediumint", "integer", "int":
+	case "smallint", "mediumint", "integer", "int", "smallint unsigned", "mediumint unsigned", "integer unsigned", "int unsigned":
 		switch spType {
 		case ddl.String:
 			return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.Widened}
@@ -175,6 +190,9 @@ func toSpannerTypeInternal(srcType schema.Type, spType string) (ddl.Type, []inte
 		switch spType {
 		case ddl.String:
 			return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, nil
+		case ddl.Int64:
+			// Bits are read as an unsigned big-endian number, e.g. b'101' is 5.
+			return ddl.Type{Name: ddl.Int64}, nil
 		default:
 			if len(srcType.Mods) > 0 && srcType.Mods[0] == 1 {
 				return ddl.Type{Name: ddl.Bool}, nil
@@ -259,8 +277,21 @@ func toSpannerTypeInternal(srcType schema.Type, spType string) (ddl.Type, []inte
 		default:
 			return ddl.Type{Name: ddl.Timestamp}, nil
 		}
-	case "time", "year":
-		return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.Time}
+	case "time":
+		switch spType {
+		case ddl.Int64:
+			// Number of seconds, e.g. '-01:30:00' is -5400.
+			return ddl.Type{Name: ddl.Int64}, []internal.SchemaIssue{internal.TimeAsSeconds}
+		default:
+			return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.Time}
+		}
+	case "year":
+		switch spType {
+		case ddl.Int64:
+			return ddl.Type{Name: ddl.Int64}, nil
+		default:
+			return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.Time}
+		}
 
 	}
 	return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.NoGoodType}
@@ -153,6 +153,18 @@ func TestToSpannerTypeInternal(t *testing.T) {
 	if errCheck == nil {
 		t.Errorf("Error in time to string conversion")
 	}
+	ty, issues := toSpannerTypeInternal(schema.Type{Name: "time"}, ddl.Int64)
+	assert.Equal(t, ddl.Type{Name: ddl.Int64}, ty)
+	assert.Equal(t, []internal.SchemaIssue{internal.TimeAsSeconds}, issues)
+	ty, issues = toSpannerTypeInternal(schema.Type{Name: "year"}, ddl.Int64)
+	assert.Equal(t, ddl.Type{Name: ddl.Int64}, ty)
+	assert.Nil(t, issues)
+	ty, issues = toSpannerTypeInternal(schema.Type{Name: "bit", Mods: []int64{12}}, ddl.Int64)
+	assert.Equal(t, ddl.Type{Name: ddl.Int64}, ty)
+	assert.Nil(t, issues)
+	ty, issues = toSpannerTypeInternal(schema.Type{Name: "year"}, "")
+	assert.Equal(t, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, ty)
+	assert.Equal(t, []internal.SchemaIssue{internal.Time}, issues)
 	_, errCheck = toSpannerTypeInternal(schema.Type{Name: "DEFAULT", Mods: []int64{1, 2, 3}, ArrayBounds: []int64{1, 2, 3}}, "")
 	if errCheck == nil {
 		t.Errorf("Error in default conversion for unidentified source datatype")
@@ -509,6 +521,31 @@ func Test_GetColumnAutoGen(t *testing.T) {
 
 }
 
+func TestToSpannerTypeUnsignedIntPolicy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     string
+		srcType    string
+		spType     string
+		want       ddl.Type
+		wantIssues []internal.SchemaIssue
+	}{
+		{"default policy", "", "bigint unsigned", "", ddl.Type{Name: ddl.Int64}, []internal.SchemaIssue{internal.PossibleOverflow}},
+		{"int64 policy", constants.UNSIGNED_INT_INT64, "bigint unsigned", "", ddl.Type{Name: ddl.Int64}, []internal.SchemaIssue{internal.PossibleOverflow}},
+		{"numeric policy", constants.UNSIGNED_INT_NUMERIC, "bigint unsigned", "", ddl.Type{Name: ddl.Numeric}, []internal.SchemaIssue{internal.UnsignedInteger}},
+		{"string policy", constants.UNSIGNED_INT_STRING, "bigint unsigned", "", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.UnsignedInteger}},
+		{"explicit type overrides policy", constants.UNSIGNED_INT_STRING, "bigint unsigned", ddl.Int64, ddl.Type{Name: ddl.Int64}, []internal.SchemaIssue{internal.PossibleOverflow}},
+		{"smaller unsigned integers fit in INT64", constants.UNSIGNED_INT_NUMERIC, "int unsigned", "", ddl.Type{Name: ddl.Int64}, []internal.SchemaIssue{internal.Widened}},
+	}
+	for _, tc := range testCases {
+		conv := internal.MakeConv()
+		conv.UnsignedIntPolicy = tc.policy
+		ty, issues := ToDdlImpl{}.ToSpannerType(conv, tc.spType, schema.Type{Name: tc.srcType}, false)
+		assert.Equal(t, tc.want, ty, tc.name)
+		assert.Equal(t, tc.wantIssues, issues, tc.name)
+	}
+}
+
 func TestGetMaxSize(t *testing.T) {
 	// testCases defines the table for our tests.
 	testCases := []struct {
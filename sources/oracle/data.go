@@ -18,7 +18,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
-	"math/bits"
 	"strconv"
 	"strings"
 	"time"
@@ -42,7 +41,7 @@ func ProcessDataRow(conv *internal.Conv, tableId string, colIds []string, srcSch
 	if err != nil {
 		conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
 		conv.StatsAddBadRow(srcTableName, conv.DataMode())
-		conv.CollectBadRow(srcTableName, srcCols, vals)
+		conv.CollectBadRowWithReason(srcTableName, srcCols, vals, err.Error())
 	} else {
 		conv.WriteRow(srcTableName, spTableName, cvtCols, cvtVals)
 	}
@@ -58,6 +57,12 @@ func convertData(conv *internal.Conv, tableId string, colIds []string, srcSchema
 	if len(colIds) != len(vals) {
 		return "", []string{}, []interface{}{}, fmt.Errorf("ConvertData: colIds and vals don't all have the same lengths: len(colIds)=%d, len(vals)=%d", len(colIds), len(vals))
 	}
+	colIds, vals, transformedCols, transformedVals, err := internal.ApplyColumnTransforms(conv, tableId, colIds, vals, func(s string) (string, bool) {
+		return s, s != "NULL"
+	})
+	if err != nil {
+		return "", []string{}, []interface{}{}, err
+	}
 	for i, colId := range colIds {
 		// Skip columns with 'NULL' values., these values
 		// 'NULL' values are represented as "NULL" (because we retrieve the values as strings).
@@ -84,11 +89,11 @@ func convertData(conv *internal.Conv, tableId string, colIds []string, srcSchema
 		v = append(v, x)
 		c = append(c, spColDef.Name)
 	}
-	if aux, ok := conv.SyntheticPKeys[tableId]; ok {
-		c = append(c, conv.SpSchema[tableId].ColDefs[aux.ColId].Name)
-		v = append(v, fmt.Sprintf("%d", int64(bits.Reverse64(uint64(aux.Sequence)))))
-		aux.Sequence++
-		conv.SyntheticPKeys[tableId] = aux
+	c = append(c, transformedCols...)
+	v = append(v, transformedVals...)
+	if synthCol, synthVal, ok := conv.NextSyntheticPKeyValue(tableId); ok {
+		c = append(c, synthCol)
+		v = append(v, synthVal)
 	}
 	return spSchema.Name, c, v, nil
 }
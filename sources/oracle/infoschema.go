@@ -42,7 +42,7 @@ type InfoSchemaImpl struct {
 
 // GetToDdl function below implement the common.InfoSchema interface.
 func (isi InfoSchemaImpl) GetToDdl() common.ToDdl {
-	return ToDdlImpl{}
+	return common.LookupToDdl(constants.ORACLE, ToDdlImpl{})
 }
 
 // GetTableName returns table name.
@@ -59,7 +59,7 @@ func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string)
 		return nil, nil
 	}
 	q := getSelectQuery(isi.DbName, tbl.Schema, tbl.Name, tbl.ColIds, tbl.ColDefs)
-	rows, err := isi.Db.Query(q)
+	rows, err := isi.Db.QueryContext(conv.Context(), q)
 	return rows, err
 }
 
@@ -114,7 +114,7 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 	srcCols, _ := rows.Columns()
 	v, scanArgs := buildVals(len(srcCols))
 	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
-	for rows.Next() {
+	for !conv.Interrupted() && rows.Next() {
 		// get RawBytes from data.
 		err := rows.Scan(scanArgs...)
 		if err != nil {
@@ -128,7 +128,7 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 		if err != nil {
 			conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
 			conv.StatsAddBadRow(srcTableName, conv.DataMode())
-			conv.CollectBadRow(srcTableName, srcCols, values)
+			conv.CollectBadRowWithReason(srcTableName, srcCols, values, err.Error())
 			continue
 		}
 		ProcessDataRow(conv, tableId, commonColIds, srcSchema, spSchema, newValues)
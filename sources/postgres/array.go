@@ -0,0 +1,171 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// convArrayToJSON maps a source database array value, e.g.
+// `{{1,2},{3,NULL}}`, to a JSON value with the same shape, e.g.
+// `[[1,2],[3,null]]`. It is used for multi-dimensional arrays, which are
+// stored as JSON since Spanner arrays have a single dimension. Elements are
+// converted to JSON numbers, booleans or strings based on srcTypeName, the
+// element type of the array.
+func convArrayToJSON(srcTypeName, v string) (string, error) {
+	v = strings.TrimSpace(v)
+	// Arrays with lower bounds other than 1 are prefixed with their
+	// dimensions, e.g. `[0:1][1:2]={{1,2},{3,4}}`.
+	if strings.HasPrefix(v, "[") {
+		i := strings.Index(v, "=")
+		if i < 0 {
+			return "", fmt.Errorf("unrecognized data format for array: %q", v)
+		}
+		v = v[i+1:]
+	}
+	p := arrayParser{s: v, srcTypeName: srcTypeName}
+	a, err := p.parseArray()
+	if err != nil {
+		return "", err
+	}
+	if p.skipSpaces(); p.pos != len(p.s) {
+		return "", fmt.Errorf("unrecognized data format for array: unexpected %q after end of array", p.s[p.pos:])
+	}
+	b, err := json.Marshal(a)
+	if err != nil {
+		return "", fmt.Errorf("can't convert array to JSON: %w", err)
+	}
+	return string(b), nil
+}
+
+// arrayParser parses the text representation of PostgreSQL arrays,
+// including nested arrays and quoted elements.
+type arrayParser struct {
+	s           string
+	pos         int
+	srcTypeName string
+}
+
+func (p *arrayParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+func (p *arrayParser) parseArray() ([]interface{}, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.s) || p.s[p.pos] != '{' {
+		return nil, fmt.Errorf("unrecognized data format for array: expected {v1, v2, ...}")
+	}
+	p.pos++
+	a := []interface{}{}
+	p.skipSpaces()
+	if p.pos < len(p.s) && p.s[p.pos] == '}' {
+		p.pos++
+		return a, nil
+	}
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.s) {
+			return nil, fmt.Errorf("unrecognized data format for array: missing }")
+		}
+		var e interface{}
+		var err error
+		switch p.s[p.pos] {
+		case '{':
+			e, err = p.parseArray()
+		case '"':
+			e, err = p.parseQuoted()
+		default:
+			e, err = p.parseUnquoted()
+		}
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, e)
+		p.skipSpaces()
+		if p.pos >= len(p.s) {
+			return nil, fmt.Errorf("unrecognized data format for array: missing }")
+		}
+		switch p.s[p.pos] {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return a, nil
+		default:
+			return nil, fmt.Errorf("unrecognized data format for array: unexpected %q", p.s[p.pos])
+		}
+	}
+}
+
+func (p *arrayParser) parseQuoted() (interface{}, error) {
+	var b strings.Builder
+	for p.pos++; p.pos < len(p.s); p.pos++ {
+		switch c := p.s[p.pos]; c {
+		case '\\':
+			p.pos++
+			if p.pos < len(p.s) {
+				b.WriteByte(p.s[p.pos])
+			}
+		case '"':
+			p.pos++
+			return p.element(b.String())
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return nil, fmt.Errorf("unrecognized data format for array: unterminated quoted element")
+}
+
+func (p *arrayParser) parseUnquoted() (interface{}, error) {
+	start := p.pos
+	for p.pos < len(p.s) && p.s[p.pos] != ',' && p.s[p.pos] != '}' {
+		p.pos++
+	}
+	s := strings.TrimSpace(p.s[start:p.pos])
+	if strings.EqualFold(s, "NULL") {
+		return nil, nil
+	}
+	return p.element(s)
+}
+
+// element converts an array element to a JSON value.
+func (p *arrayParser) element(s string) (interface{}, error) {
+	switch p.srcTypeName {
+	case "bool", "boolean":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("can't convert %q to bool: %w", s, err)
+		}
+		return b, nil
+	case "smallint", "integer", "bigint", "int2", "int4", "int8",
+		"real", "double precision", "float4", "float8", "numeric", "decimal":
+		// NaN and Infinity have no JSON number representation.
+		if _, err := strconv.ParseFloat(s, 64); err != nil || !json.Valid([]byte(s)) {
+			return s, nil
+		}
+		return json.Number(s), nil
+	case "json", "jsonb":
+		if !json.Valid([]byte(s)) {
+			return nil, fmt.Errorf("invalid JSON array element %q", s)
+		}
+		return json.RawMessage(s), nil
+	}
+	return s, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvArrayToJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		srcTypeName string
+		in          string
+		want        string
+		wantErr     bool
+	}{
+		{"integers", "integer", "{{1,2},{3,NULL}}", "[[1,2],[3,null]]", false},
+		{"three dimensions", "bigint", "{{{1},{2}},{{3},{4}}}", "[[[1],[2]],[[3],[4]]]", false},
+		{"empty", "integer", "{}", "[]", false},
+		{"explicit bounds", "integer", "[0:1][1:2]={{1,2},{3,4}}", "[[1,2],[3,4]]", false},
+		{"numeric NaN", "numeric", "{{1.5,NaN}}", `[[1.5,"NaN"]]`, false},
+		{"bools", "boolean", "{{t,f},{true,NULL}}", "[[true,false],[true,null]]", false},
+		{"quoted text", "text", `{{"a b","NULL"},{"say \"hi\"",c}}`, `[["a b","NULL"],["say \"hi\"","c"]]`, false},
+		{"jsonb", "jsonb", `{{"{\"a\": 1}",null}}`, `[[{"a":1},null]]`, false},
+		{"missing brace", "integer", "{{1,2},{3,4}", "", true},
+		{"trailing data", "integer", "{{1}}x", "", true},
+		{"bad bool", "boolean", "{{yes}}", "", true},
+	}
+	for _, tc := range tests {
+		got, err := convArrayToJSON(tc.srcTypeName, tc.in)
+		assert.Equal(t, tc.wantErr, err != nil, tc.name)
+		assert.Equal(t, tc.want, got, tc.name)
+	}
+}
+
+func TestConvertDataMultiDimensionalArray(t *testing.T) {
+	conv := buildConv(
+		ddl.CreateTable{
+			Name:    "grid",
+			Id:      "t1",
+			ColIds:  []string{"c1"},
+			ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "cells", Id: "c1", T: ddl.Type{Name: ddl.JSON}}}},
+		schema.Table{
+			Name:    "grid",
+			Id:      "t1",
+			ColIds:  []string{"c1"},
+			ColDefs: map[string]schema.Column{"c1": {Name: "cells", Id: "c1", Type: schema.Type{Name: "float8", ArrayBounds: []int64{-1, -1}}}}})
+	_, cols, vals, err := ConvertData(conv, "t1", []string{"c1"}, []string{"{{1.5,2},{NULL,-3e2}}"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"cells"}, cols)
+	assert.Equal(t, []interface{}{"[[1.5,2],[null,-3e2]]"}, vals)
+}
@@ -18,7 +18,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/big"
-	"math/bits"
 	"reflect"
 	"strconv"
 	"strings"
@@ -28,6 +27,7 @@ import (
 	"cloud.google.com/go/spanner"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 )
 
@@ -46,7 +46,7 @@ func ProcessDataRow(conv *internal.Conv, tableId string, colIds, vals []string)
 	if err != nil {
 		conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
 		conv.StatsAddBadRow(srcTableName, conv.DataMode())
-		conv.CollectBadRow(srcTableName, srcCols, vals)
+		conv.CollectBadRowWithReason(srcTableName, srcCols, vals, err.Error())
 	} else {
 		conv.WriteRow(srcTableName, spTableName, spCols, spVals)
 	}
@@ -72,6 +72,12 @@ func ConvertData(conv *internal.Conv, tableId string, colIds []string, vals []st
 	if len(colIds) != len(vals) {
 		return "", []string{}, []interface{}{}, fmt.Errorf("ConvertData: colIds and vals don't all have the same lengths: len(colIds)=%d, len(vals)=%d", len(colIds), len(vals))
 	}
+	colIds, vals, transformedCols, transformedVals, err := internal.ApplyColumnTransforms(conv, tableId, colIds, vals, func(s string) (string, bool) {
+		return s, s != "\\N" && s != "NULL"
+	})
+	if err != nil {
+		return "", []string{}, []interface{}{}, err
+	}
 	for i, colId := range colIds {
 		// "\\N" is for PostgreSQL representation of empty column in COPY-FROM blocks.
 		// TODO: Consider using NullString to differentiate between an actual column having "NULL" as a string
@@ -88,20 +94,30 @@ func ConvertData(conv *internal.Conv, tableId string, colIds []string, vals []st
 		var err error
 		if spColDef.T.IsArray {
 			x, err = convArray(spColDef.T, srcColDef.Type.Name, conv.Location, vals[i])
+		} else if len(srcColDef.Type.ArrayBounds) > 1 && spColDef.T.Name == ddl.JSON {
+			x, err = convArrayToJSON(srcColDef.Type.Name, vals[i])
+		} else if srcColDef.UserType.Kind == schema.UserTypeComposite && spColDef.T.Name == ddl.JSON {
+			x, err = convCompositeToJSON(srcColDef.UserType.Fields, vals[i])
 		} else {
 			x, err = convScalar(conv, spColDef.T, srcColDef.Type.Name, conv.Location, vals[i])
+			x = shiftLocalTimestamp(conv, tableId, colId, srcSchema.Name, srcColDef.Type.Name, x)
 		}
 		if err != nil {
 			return "", []string{}, []interface{}{}, err
 		}
+		if r, ok := x.(*big.Rat); ok {
+			if err := conv.CheckNumericPrecision(srcSchema.Name, srcColDef.Name, r); err != nil {
+				return "", []string{}, []interface{}{}, err
+			}
+		}
 		v = append(v, x)
 		c = append(c, spColDef.Name)
 	}
-	if aux, ok := conv.SyntheticPKeys[tableId]; ok {
-		c = append(c, conv.SpSchema[tableId].ColDefs[aux.ColId].Name)
-		v = append(v, fmt.Sprintf("%d", int64(bits.Reverse64(uint64(aux.Sequence)))))
-		aux.Sequence++
-		conv.SyntheticPKeys[tableId] = aux
+	c = append(c, transformedCols...)
+	v = append(v, transformedVals...)
+	if synthCol, synthVal, ok := conv.NextSyntheticPKeyValue(tableId); ok {
+		c = append(c, synthCol)
+		v = append(v, synthVal)
 	}
 	return spSchema.Name, c, v, nil
 }
@@ -120,6 +136,9 @@ func convScalar(conv *internal.Conv, spannerType ddl.Type, srcTypeName string, l
 	case ddl.Bool:
 		return convBool(val)
 	case ddl.Bytes:
+		if isBitString(srcTypeName) {
+			return convBitsToBytes(val)
+		}
 		return convBytes(val)
 	case ddl.Date:
 		return convDate(val)
@@ -128,10 +147,19 @@ func convScalar(conv *internal.Conv, spannerType ddl.Type, srcTypeName string, l
 	case ddl.Float64:
 		return convFloat64(val)
 	case ddl.Int64:
+		if srcTypeName == "interval" {
+			return convIntervalMicros(val)
+		}
+		if isBitString(srcTypeName) {
+			return convBitsToInt64(val)
+		}
 		return convInt64(val)
 	case ddl.Numeric:
 		return convNumeric(conv, val)
 	case ddl.String:
+		if srcTypeName == "interval" {
+			return convInterval(val)
+		}
 		return val, nil
 	case ddl.Timestamp:
 		return convTimestamp(srcTypeName, location, val)
@@ -193,6 +221,68 @@ func convInt64(val string) (int64, error) {
 	return i, err
 }
 
+// convInterval maps a source database interval value to an ISO 8601
+// duration, e.g. "1 year 2 mons 3 days 04:05:06" to "P1Y2M3DT4H5M6S".
+func convInterval(val string) (string, error) {
+	iv, err := parseInterval(val)
+	if err != nil {
+		return "", err
+	}
+	return iv.iso8601(), nil
+}
+
+// convIntervalMicros maps a source database interval value to its length in
+// microseconds.
+func convIntervalMicros(val string) (int64, error) {
+	iv, err := parseInterval(val)
+	if err != nil {
+		return 0, err
+	}
+	return iv.totalMicros(), nil
+}
+
+// isBitString returns true if srcTypeName is a bit string type i.e. bit(n)
+// or bit varying(n), which pg_dump names varbit.
+func isBitString(srcTypeName string) bool {
+	switch srcTypeName {
+	case "bit", "varbit", "bit varying":
+		return true
+	}
+	return false
+}
+
+// convBitsToBytes maps a source database bit string, e.g. "100000010", to
+// the big-endian bytes of the number it represents, e.g. {0x01, 0x02}. Like
+// MySQL BIT values, bits are right-aligned: leading bits of the first byte
+// are zero.
+func convBitsToBytes(val string) ([]byte, error) {
+	b := make([]byte, (len(val)+7)/8)
+	for i := 0; i < len(val); i++ {
+		bit := len(val) - 1 - i // Position of the bit from the right.
+		switch val[i] {
+		case '1':
+			b[len(b)-1-bit/8] |= 1 << (bit % 8)
+		case '0':
+		default:
+			return nil, fmt.Errorf("can't convert %q to bytes: not a bit string", val)
+		}
+	}
+	return b, nil
+}
+
+// convBitsToInt64 maps a source database bit string, e.g. "101", to the
+// unsigned number it represents, e.g. 5.
+func convBitsToInt64(val string) (int64, error) {
+	if val == "" {
+		return 0, nil
+	}
+	i, err := strconv.ParseInt(val, 2, 64)
+	if err != nil {
+		return 0, fmt.Errorf("can't convert bit string %q to int64: %w", val, err)
+	}
+	return i, nil
+}
+
 // convNumeric maps a source database string value (representing a numeric)
 // into a string representing a valid Spanner numeric.
 func convNumeric(conv *internal.Conv, val string) (interface{}, error) {
@@ -244,7 +334,21 @@ func convTimestamp(srcTypeName string, location *time.Location, val string) (t t
 	if err != nil {
 		return t, fmt.Errorf("can't convert to timestamp (posgres type: %s)", srcTypeName)
 	}
-	return t, err
+	return t, internal.CheckFractionalSeconds(val, t)
+}
+
+// shiftLocalTimestamp moves a timestamp value v of a column without time zone
+// to the time zone given by the timezone policy or the column's time zone.
+// Other values are returned unchanged.
+func shiftLocalTimestamp(conv *internal.Conv, tableId, colId, srcTable, srcTypeName string, v interface{}) interface{} {
+	t, ok := v.(time.Time)
+	if !ok || srcTypeName == "timestamptz" || srcTypeName == "timestamp with time zone" {
+		return v
+	}
+	if loc := conv.TimestampLocation(tableId, colId, conv.Location); loc != nil {
+		return conv.ShiftTimestamp(srcTable, t, loc)
+	}
+	return v
 }
 
 // convArray converts a source database string value (representing an
@@ -255,6 +359,9 @@ func convTimestamp(srcTypeName string, location *time.Location, val string) (t t
 // NULL, 2}", but it does not handle "NULL" (it returns error).
 func convArray(spannerType ddl.Type, srcTypeName string, location *time.Location, v string) (interface{}, error) {
 	v = strings.TrimSpace(v)
+	if isVectorType(srcTypeName) {
+		return convVector(spannerType, v)
+	}
 	// Handle empty array. Note that we use an empty NullString array
 	// for all Spanner array types since this will be converted to the
 	// appropriate type by the Spanner client.
@@ -265,6 +372,10 @@ func convArray(spannerType ddl.Type, srcTypeName string, location *time.Location
 		return []interface{}{}, fmt.Errorf("unrecognized data format for array: expected {v1, v2, ...}")
 	}
 	a := strings.Split(v[1:len(v)-1], ",")
+	// Postgres allows whitespace around array elements, e.g. {42, 6}.
+	for i := range a {
+		a[i] = strings.TrimSpace(a[i])
+	}
 
 	// The Spanner client for go does not accept []interface{} for arrays.
 	// Instead it only accepts slices of a specific type e.g. []int64, []string.
@@ -414,6 +525,45 @@ func convArray(spannerType ddl.Type, srcTypeName string, location *time.Location
 	return []interface{}{}, fmt.Errorf("array type conversion not implemented for type %v", reflect.TypeOf(spannerType))
 }
 
+// convVector converts a pgvector value, e.g. [1,2.5,3], to a Spanner
+// FLOAT32 or FLOAT64 array. Vectors whose dimension differs from the vector
+// length of the column are rejected, as Spanner would reject them.
+func convVector(spannerType ddl.Type, v string) (interface{}, error) {
+	if len(v) < 2 || v[0] != '[' || v[len(v)-1] != ']' {
+		return nil, fmt.Errorf("unrecognized data format for vector: expected [v1, v2, ...]")
+	}
+	var a []string
+	if s := strings.TrimSpace(v[1 : len(v)-1]); s != "" {
+		a = strings.Split(s, ",")
+	}
+	if spannerType.VectorLength > 0 && int64(len(a)) != spannerType.VectorLength {
+		return nil, fmt.Errorf("vector has %d dimensions, expected %d", len(a), spannerType.VectorLength)
+	}
+	switch spannerType.Name {
+	case ddl.Float32:
+		r := make([]float32, 0, len(a))
+		for _, s := range a {
+			f, err := convFloat32(strings.TrimSpace(s))
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, f)
+		}
+		return r, nil
+	case ddl.Float64:
+		r := make([]float64, 0, len(a))
+		for _, s := range a {
+			f, err := convFloat64(strings.TrimSpace(s))
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, f)
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("can't convert vector to array of %s", spannerType.Name)
+}
+
 // processQuote returns the unquoted version of s.
 // Note: The element values of PostgreSQL arrays may have double
 // quotes around them.  The array output routine will put double
@@ -83,6 +83,14 @@ func TestConvertData(t *testing.T) {
 		{"string", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, "", "eh", "eh"},
 		{"timestamptz", ddl.Type{Name: ddl.Timestamp}, "timestamptz", "2019-10-29 05:30:00+10", getTime(t, "2019-10-29T05:30:00+10:00")},
 		{"timestamp", ddl.Type{Name: ddl.Timestamp}, "timestamp", "2019-10-29 05:30:00", getTime(t, "2019-10-29T05:30:00Z")},
+		{"interval", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, "interval", "1 year 2 mons 3 days 04:05:06.789", "P1Y2M3DT4H5M6.789S"},
+		{"interval mixed units", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, "interval", "1 day 2 hours -30 minutes", "P1DT1H30M"},
+		{"interval microseconds", ddl.Type{Name: ddl.Int64}, "interval", "1 day 00:00:01.5", int64(86401500000)},
+		{"bit(1)", ddl.Type{Name: ddl.Bool}, "bit", "1", true},
+		{"bit as bytes", ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}, "bit", "100000010", []byte{0x01, 0x02}},
+		{"varbit as bytes", ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}, "varbit", "", []byte{}},
+		{"bit as int64", ddl.Type{Name: ddl.Int64}, "bit", "0101", int64(5)},
+		{"varbit as string", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, "varbit", "0101", "0101"},
 
 		// Add cases for each array type, since each is a separate code path.
 		// Note: the PostgreSQL array output routine puts double quotes around
@@ -119,6 +127,9 @@ func TestConvertData(t *testing.T) {
 			spanner.NullTime{Time: getTime(t, "2019-10-29T05:30:00+10:00"), Valid: true},
 			spanner.NullTime{Valid: false}}},
 		{"empty array", ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}, "", "{}", []spanner.NullString{}},
+		{"vector", ddl.Type{Name: ddl.Float32, IsArray: true, VectorLength: 3}, "vector", "[1,2.5,-3]", []float32{1, 2.5, -3}},
+		{"vector as float64 array", ddl.Type{Name: ddl.Float64, IsArray: true}, "public.vector", "[0.5, 1]", []float64{0.5, 1}},
+		{"vector as string", ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, "vector", "[1,2]", "[1,2]"},
 	}
 	tableName := "testtable"
 	tableId := "t1"
@@ -151,6 +162,9 @@ func TestConvertData(t *testing.T) {
 		{"timestamptz hour/min timezone", "timestamptz", "2019-10-29 05:30:00+10:30", getTime(t, "2019-10-29T05:30:00+10:30")},
 		{"timestamptz no timezone", "timestamptz", "2019-10-29 05:30:00", getTime(t, "2019-10-29T05:30:00+11:00")},
 		{"timestamp", "timestamp", "2019-10-29 05:30:00", getTime(t, "2019-10-29T05:30:00Z")},
+		{"timestamptz microseconds", "timestamptz", "2019-10-29 05:30:00.123456+10", getTime(t, "2019-10-29T05:30:00.123456+10:00")},
+		{"timestamptz milliseconds no timezone", "timestamptz", "2019-10-29 05:30:00.123", getTime(t, "2019-10-29T05:30:00.123+11:00")},
+		{"timestamp microseconds", "timestamp", "2019-10-29 05:30:00.000001", getTime(t, "2019-10-29T05:30:00.000001Z")},
 	}
 	for _, tc := range timestampTests {
 		col := "a"
@@ -332,3 +346,15 @@ func getDate(s string) civil.Date {
 	d, _ := civil.ParseDate(s)
 	return d
 }
+
+func TestConvVector(t *testing.T) {
+	_, err := convVector(ddl.Type{Name: ddl.Float32, IsArray: true, VectorLength: 3}, "[1,2]")
+	assert.EqualError(t, err, "vector has 2 dimensions, expected 3")
+	_, err = convVector(ddl.Type{Name: ddl.Float32, IsArray: true}, "{1,2}")
+	assert.NotNil(t, err)
+	_, err = convVector(ddl.Type{Name: ddl.Float32, IsArray: true}, "[1,x]")
+	assert.NotNil(t, err)
+	v, err := convVector(ddl.Type{Name: ddl.Float32, IsArray: true}, "[]")
+	assert.Nil(t, err)
+	assert.Equal(t, []float32{}, v)
+}
@@ -17,8 +17,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"math/bits"
 	"reflect"
 	"slices"
 	"sort"
@@ -32,6 +33,7 @@ import (
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
@@ -46,6 +48,8 @@ type InfoSchemaImpl struct {
 	SourceProfile      profiles.SourceProfile
 	TargetProfile      profiles.TargetProfile
 	IsSchemaUnique     *bool
+	// Snapshot, when set, is the consistent snapshot that table data is read from.
+	Snapshot *common.ConsistentSnapshot
 }
 
 func (isi InfoSchemaImpl) populateSchemaIsUnique(schemaAndNames []common.SchemaAndName) {
@@ -111,7 +115,7 @@ func (isi InfoSchemaImpl) StartStreamingMigration(ctx context.Context, migration
 
 // GetToDdl function below implement the common.InfoSchema interface.
 func (isi InfoSchemaImpl) GetToDdl() common.ToDdl {
-	return ToDdlImpl{}
+	return common.LookupToDdl(constants.POSTGRES, ToDdlImpl{})
 }
 
 // GetTableName returns table name.
@@ -129,19 +133,118 @@ func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string)
 	// PostgreSQL schema and name can be arbitrary strings.
 	// Ideally we would pass schema/name as a query parameter,
 	// but PostgreSQL doesn't support this. So we quote it instead.
-	isSchemaNamePrefixed := strings.HasPrefix(conv.SrcSchema[tableId].Name, conv.SrcSchema[tableId].Schema+".")
+	q := fmt.Sprintf(`SELECT * FROM %s;`, quotedTableName(conv.SrcSchema[tableId]))
+	rows, err := isi.Snapshot.QueryContext(conv.Context(), isi.Db, q)
+	if err != nil {
+		return nil, err
+	}
+	return rows, err
+}
+
+// CollectColumnStats samples up to sampleRows rows of each table to compute
+// the statistics of its columns, then flags the columns whose type can be
+// improved given their values. Tables which can't be sampled are skipped.
+func (isi InfoSchemaImpl) CollectColumnStats(conv *internal.Conv, sampleRows int) {
+	for tableId, srcSchema := range conv.SrcSchema {
+		if len(srcSchema.ColIds) == 0 {
+			continue
+		}
+		var cols []string
+		for _, colId := range srcSchema.ColIds {
+			cols = append(cols, `"`+strings.ReplaceAll(srcSchema.ColDefs[colId].Name, `"`, `""`)+`"`)
+		}
+		q := fmt.Sprintf(`SELECT %s FROM %s LIMIT %d;`, strings.Join(cols, ", "), quotedTableName(srcSchema), sampleRows)
+		rows, err := isi.Db.Query(q)
+		if err != nil {
+			logger.Log.Warn(fmt.Sprintf("Couldn't sample table %s for column statistics: %v", srcSchema.Name, err))
+			continue
+		}
+		err = common.CollectColumnStats(conv, tableId, srcSchema.ColIds, rows)
+		rows.Close()
+		if err != nil {
+			logger.Log.Warn(err.Error())
+		}
+	}
+	conv.SuggestFromColumnStats()
+}
+
+// EstimateRowCounts reads the number of rows of each table estimated by
+// Postgres in pg_class, which is much cheaper than counting them, to estimate
+// the Spanner storage of the tables during schema conversion. Tables which
+// were never analyzed have no estimate.
+func (isi InfoSchemaImpl) EstimateRowCounts(conv *internal.Conv) {
+	for tableId, srcSchema := range conv.SrcSchema {
+		var n int64
+		err := isi.Db.QueryRow("SELECT reltuples::bigint FROM pg_class WHERE oid = $1::regclass", quotedTableName(srcSchema)).Scan(&n)
+		if err != nil {
+			logger.Log.Warn(fmt.Sprintf("Couldn't read the estimated number of rows of table %s: %v", srcSchema.Name, err))
+			continue
+		}
+		if n >= 0 {
+			conv.SrcRowCounts[tableId] = n
+		}
+	}
+}
+
+// quotedTableName returns the quoted schema and table name of a source table.
+func quotedTableName(srcSchema schema.Table) string {
+	isSchemaNamePrefixed := strings.HasPrefix(srcSchema.Name, srcSchema.Schema+".")
 	var tableName string
 	if isSchemaNamePrefixed {
-		tableName = strings.TrimPrefix(conv.SrcSchema[tableId].Name, conv.SrcSchema[tableId].Schema+".")
+		tableName = strings.TrimPrefix(srcSchema.Name, srcSchema.Schema+".")
 	} else {
-		tableName = conv.SrcSchema[tableId].Name
+		tableName = srcSchema.Name
 	}
-	q := fmt.Sprintf(`SELECT * FROM "%s"."%s";`, conv.SrcSchema[tableId].Schema, tableName)
-	rows, err := isi.Db.Query(q)
+	return fmt.Sprintf(`"%s"."%s"`, srcSchema.Schema, tableName)
+}
+
+// chunkedTableReader returns a reader of the table by primary key range, and
+// the chunks it is split into, when the table is large enough to be read by
+// several workers and has a single column key of an integer, numeric or
+// character type.
+func (isi InfoSchemaImpl) chunkedTableReader(conv *internal.Conv, tableId string) (common.ChunkedTableReader, []common.TableChunk, bool) {
+	srcSchema := conv.SrcSchema[tableId]
+	rowCount := conv.Stats.Rows[srcSchema.Name]
+	n := common.NumTableChunks(rowCount, conv.TableReadParallelism)
+	if n == 1 || len(srcSchema.PrimaryKeys) != 1 {
+		return common.ChunkedTableReader{}, nil, false
+	}
+	key := srcSchema.ColDefs[srcSchema.PrimaryKeys[0].ColId]
+	var integerKey bool
+	switch key.Type.Name {
+	case "int8", "bigint", "bigserial", "int4", "integer", "serial", "int2", "smallint", "smallserial":
+		integerKey = true
+	case "numeric", "text", "varchar", "character varying", "bpchar", "character":
+	default:
+		return common.ChunkedTableReader{}, nil, false
+	}
+	reader := common.ChunkedTableReader{
+		Db:         isi.Db,
+		Table:      quotedTableName(srcSchema),
+		Key:        fmt.Sprintf(`"%s"`, key.Name),
+		KeyName:    key.Name,
+		IntegerKey: integerKey,
+		Query:      fmt.Sprintf(`SELECT * FROM %s`, quotedTableName(srcSchema)),
+		Bound: func(value string, arg int) (string, []interface{}) {
+			return fmt.Sprintf("$%d", arg), []interface{}{value}
+		},
+		NewRow: func(n int) []interface{} {
+			vals := make([]interface{}, n)
+			for i := range vals {
+				vals[i] = new(interface{})
+			}
+			return vals
+		},
+	}
+	if isi.Snapshot != nil {
+		reader.SnapshotId = isi.Snapshot.SnapshotId
+	}
+	chunks, err := reader.Split(n, rowCount)
 	if err != nil {
-		return nil, err
+		logger.Log.Debug(fmt.Sprintf("Reading table %s with a single query, couldn't split it into key ranges: %v", srcSchema.Name, err))
+		return common.ChunkedTableReader{}, nil, false
 	}
-	return rows, err
+	return reader, chunks, true
 }
 
 // ProcessDataRows performs data conversion for source database
@@ -165,6 +268,49 @@ func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string)
 // *interface{} parameters to row.Scan.
 func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSchema schema.Table, colIds []string, spSchema ddl.CreateTable, additionalAttributes internal.AdditionalDataAttributes) error {
 	srcTableName := conv.SrcSchema[tableId].Name
+	if conv.TimezonePolicy == internal.TimezoneSource {
+		loc, err := isi.sessionLocation()
+		if err != nil {
+			return fmt.Errorf("couldn't get the time zone of the source server: %v", err)
+		}
+		conv.SetLocation(loc)
+	}
+	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
+	processRow := func(srcCols []string, v []interface{}) {
+		newValues, err1 := common.PrepareValues(conv, tableId, colNameIdMap, colIds, srcCols, v)
+		cvtCols, cvtVals, err2 := convertSQLRow(conv, tableId, colIds, srcSchema, spSchema, newValues)
+		if err1 != nil || err2 != nil {
+			conv.Unexpected(fmt.Sprintf("Couldn't process sql data row: %s", errors.Join(err1, err2)))
+			conv.StatsAddBadRow(srcTableName, conv.DataMode())
+			conv.CollectBadRowWithReason(srcTableName, srcCols, valsToStrings(v), errors.Join(err1, err2).Error())
+			return
+		}
+		conv.WriteRow(srcTableName, conv.SpSchema[tableId].Name, cvtCols, cvtVals)
+	}
+	// Large tables are read by conv.TableReadParallelism workers, each reading
+	// a range of the primary key.
+	if reader, chunks, ok := isi.chunkedTableReader(conv, tableId); ok {
+		logger.Log.Info(fmt.Sprintf("Reading table %s in %d key ranges with %d workers", srcTableName, len(chunks), conv.TableReadParallelism))
+		err := reader.Read(conv.Context(), chunks, conv.TableReadParallelism, func(srcCols []string, vals []interface{}, err error) {
+			if err != nil {
+				conv.Unexpected(fmt.Sprintf("Couldn't process sql data row: %s", err))
+				conv.StatsAddBadRow(srcTableName, conv.DataMode())
+				return
+			}
+			v := make([]interface{}, len(vals))
+			for i := range vals {
+				v[i] = *vals[i].(*interface{})
+			}
+			processRow(srcCols, v)
+		})
+		if conv.Interrupted() {
+			return nil
+		}
+		if err != nil {
+			conv.Unexpected(fmt.Sprintf("Couldn't get data for table %s : err = %s", srcTableName, err))
+		}
+		return err
+	}
 	rowsInterface, err := isi.GetRowsFromTable(conv, tableId)
 	if err != nil {
 		conv.Unexpected(fmt.Sprintf("Couldn't get data for table %s : err = %s", srcTableName, err))
@@ -174,8 +320,7 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 	defer rows.Close()
 	srcCols, _ := rows.Columns()
 	v, iv := buildVals(len(srcCols))
-	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
-	for rows.Next() {
+	for !conv.Interrupted() && rows.Next() {
 		err := rows.Scan(iv...)
 		if err != nil {
 			conv.Unexpected(fmt.Sprintf("Couldn't process sql data row: %s", err))
@@ -183,15 +328,7 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 			conv.StatsAddBadRow(srcTableName, conv.DataMode())
 			continue
 		}
-		newValues, err1 := common.PrepareValues(conv, tableId, colNameIdMap, colIds, srcCols, v)
-		cvtCols, cvtVals, err2 := convertSQLRow(conv, tableId, colIds, srcSchema, spSchema, newValues)
-		if err1 != nil || err2 != nil {
-			conv.Unexpected(fmt.Sprintf("Couldn't process sql data row: %s", err))
-			conv.StatsAddBadRow(srcTableName, conv.DataMode())
-			conv.CollectBadRow(srcTableName, srcCols, valsToStrings(v))
-			continue
-		}
-		conv.WriteRow(srcTableName, conv.SpSchema[tableId].Name, cvtCols, cvtVals)
+		processRow(srcCols, v)
 	}
 	return nil
 }
@@ -203,6 +340,10 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 // because cols can change when we add a column (synthetic primary
 // key) or because we drop columns (handling of NULL values).
 func convertSQLRow(conv *internal.Conv, tableId string, colIds []string, srcSchema schema.Table, spSchema ddl.CreateTable, srcVals []interface{}) ([]string, []interface{}, error) {
+	colIds, srcVals, transformedCols, transformedVals, err := internal.ApplyColumnTransforms(conv, tableId, colIds, srcVals, sqlValToString)
+	if err != nil {
+		return nil, nil, err
+	}
 	var vs []interface{}
 	var cs []string
 	for i, colId := range colIds {
@@ -220,6 +361,7 @@ func convertSQLRow(conv *internal.Conv, tableId string, colIds []string, srcSche
 			spVal, err = cvtSQLArray(conv, srcCd, spCd, srcVals[i])
 		} else {
 			spVal, err = cvtSQLScalar(conv, srcCd, spCd, srcVals[i])
+			spVal = shiftLocalTimestamp(conv, tableId, colId, srcSchema.Name, srcCd.Type.Name, spVal)
 		}
 		if err != nil { // Skip entire row if we hit error.
 			return nil, nil, fmt.Errorf("can't convert sql data for column id %s of table %s: %w", colIds, conv.SrcSchema[tableId].Name, err)
@@ -227,22 +369,31 @@ func convertSQLRow(conv *internal.Conv, tableId string, colIds []string, srcSche
 		vs = append(vs, spVal)
 		cs = append(cs, spCd.Name)
 	}
-	if aux, ok := conv.SyntheticPKeys[tableId]; ok {
-		cs = append(cs, conv.SpSchema[tableId].ColDefs[aux.ColId].Name)
-		vs = append(vs, fmt.Sprintf("%d", int64(bits.Reverse64(uint64(aux.Sequence)))))
-		aux.Sequence++
-		conv.SyntheticPKeys[tableId] = aux
+	cs = append(cs, transformedCols...)
+	vs = append(vs, transformedVals...)
+	if synthCol, synthVal, ok := conv.NextSyntheticPKeyValue(tableId); ok {
+		cs = append(cs, synthCol)
+		vs = append(vs, synthVal)
 	}
 	return cs, vs, nil
 }
 
+// sessionLocation returns the session time zone of the source server.
+func (isi InfoSchemaImpl) sessionLocation() (*time.Location, error) {
+	var tz string
+	if err := isi.Db.QueryRow("SHOW TIMEZONE").Scan(&tz); err != nil {
+		return nil, err
+	}
+	return internal.ParseTimezone(tz)
+}
+
 // GetRowCount with number of rows in each table.
 func (isi InfoSchemaImpl) GetRowCount(table common.SchemaAndName) (int64, error) {
 	// PostgreSQL schema and name can be arbitrary strings.
 	// Ideally we would pass schema/name as a query parameter,
 	// but PostgreSQL doesn't support this. So we quote it instead.
 	q := fmt.Sprintf(`SELECT COUNT(*) FROM "%s"."%s";`, table.Schema, table.Name)
-	rows, err := isi.Db.Query(q)
+	rows, err := isi.Snapshot.Query(isi.Db, q)
 	if err != nil {
 		return 0, err
 	}
@@ -266,19 +417,24 @@ func (isi InfoSchemaImpl) GetTables() ([]common.SchemaAndName, error) {
 	for _, s := range []string{"information_schema", "postgres", "pg_catalog", "pg_temp_1", "pg_toast", "pg_toast_temp_1"} {
 		ignored[s] = true
 	}
-	q := "SELECT table_schema, table_name FROM information_schema.tables where table_type = 'BASE TABLE'"
+	q := "SELECT table_schema, table_name, obj_description(format('%I.%I', table_schema, table_name)::regclass, 'pg_class') FROM information_schema.tables where table_type = 'BASE TABLE'"
 	rows, err := isi.Db.Query(q)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't get tables: %w", err)
 	}
 	defer rows.Close()
 	var tableSchema, tableName string
+	var tableComment sql.NullString
 	var tables []common.SchemaAndName
 	for rows.Next() {
-		rows.Scan(&tableSchema, &tableName)
-		if !ignored[tableSchema] {
-			tables = append(tables, common.SchemaAndName{Schema: tableSchema, Name: tableName})
+		rows.Scan(&tableSchema, &tableName, &tableComment)
+		if ignored[tableSchema] {
+			continue
 		}
+		if schemas := isi.SourceProfile.Conn.Pg.Schemas; len(schemas) > 0 && len(profiles.MatchDatabaseNames(schemas, []string{tableSchema})) == 0 {
+			continue
+		}
+		tables = append(tables, common.SchemaAndName{Schema: tableSchema, Name: tableName, Comment: tableComment.String})
 	}
 	isi.populateSchemaIsUnique(tables)
 	return tables, nil
@@ -286,7 +442,20 @@ func (isi InfoSchemaImpl) GetTables() ([]common.SchemaAndName, error) {
 
 // GetColumns returns a list of Column objects and names
 func (isi InfoSchemaImpl) GetColumns(conv *internal.Conv, table common.SchemaAndName, constraints map[string][]string, primaryKeys []string) (map[string]schema.Column, []string, error) {
-	q := `SELECT c.column_name, c.data_type, e.data_type, c.is_nullable, c.column_default, c.character_maximum_length, c.numeric_precision, c.numeric_scale
+	q := `SELECT c.column_name, c.data_type, e.data_type, c.is_nullable, c.column_default, c.character_maximum_length, c.numeric_precision, c.numeric_scale,
+                 col_description(format('%I.%I', c.table_schema, c.table_name)::regclass, c.ordinal_position),
+                 (SELECT a.attndims FROM pg_attribute a
+                     WHERE a.attrelid = format('%I.%I', c.table_schema, c.table_name)::regclass AND a.attname = c.column_name),
+                 c.domain_name, c.udt_name,
+                 CASE WHEN c.data_type = 'USER-DEFINED' THEN
+                     (SELECT json_agg(a.attname ORDER BY a.attnum) FROM pg_type t JOIN pg_attribute a ON a.attrelid = t.typrelid
+                         WHERE t.oid = format('%I.%I', c.udt_schema, c.udt_name)::regtype AND t.typtype = 'c' AND a.attnum > 0 AND NOT a.attisdropped)
+                 END,
+                 c.collation_name,
+                 CASE WHEN c.udt_name = 'vector' THEN
+                     (SELECT a.atttypmod FROM pg_attribute a
+                         WHERE a.attrelid = format('%I.%I', c.table_schema, c.table_name)::regclass AND a.attname = c.column_name)
+                 END
               FROM information_schema.COLUMNS c LEFT JOIN information_schema.element_types e
                  ON ((c.table_catalog, c.table_schema, c.table_name, 'TABLE', c.dtd_identifier)
                      = (e.object_catalog, e.object_schema, e.object_name, e.object_type, e.collection_type_identifier))
@@ -300,10 +469,10 @@ func (isi InfoSchemaImpl) GetColumns(conv *internal.Conv, table common.SchemaAnd
 	colDefs := make(map[string]schema.Column)
 	var colIds []string
 	var colName, dataType, isNullable string
-	var colDefault, elementDataType sql.NullString
-	var charMaxLen, numericPrecision, numericScale sql.NullInt64
+	var colDefault, elementDataType, colComment, domainName, udtName, compositeFields, collation sql.NullString
+	var charMaxLen, numericPrecision, numericScale, arrayDims, vectorDims sql.NullInt64
 	for cols.Next() {
-		err := cols.Scan(&colName, &dataType, &elementDataType, &isNullable, &colDefault, &charMaxLen, &numericPrecision, &numericScale)
+		err := cols.Scan(&colName, &dataType, &elementDataType, &isNullable, &colDefault, &charMaxLen, &numericPrecision, &numericScale, &colComment, &arrayDims, &domainName, &udtName, &compositeFields, &collation, &vectorDims)
 		if err != nil {
 			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
 			continue
@@ -324,12 +493,36 @@ func (isi InfoSchemaImpl) GetColumns(conv *internal.Conv, table common.SchemaAnd
 		ignored.Default = colDefault.Valid && !isSerialColumn
 		colId := internal.GenerateColumnId()
 		c := schema.Column{
-			Id:      colId,
-			Name:    colName,
-			Type:    toType(dataType, elementDataType, charMaxLen, numericPrecision, numericScale),
-			NotNull: common.ToNotNull(conv, isNullable),
-			Ignored: ignored,
-			AutoGen: toAutoGen(isSerialColumn),
+			Id:        colId,
+			Name:      colName,
+			Type:      toType(dataType, elementDataType, arrayDims, charMaxLen, numericPrecision, numericScale),
+			NotNull:   common.ToNotNull(conv, isNullable),
+			Ignored:   ignored,
+			AutoGen:   toAutoGen(isSerialColumn),
+			Comment:   colComment.String,
+			Collation: toCollation(collation.String),
+		}
+		// Note that the type of columns declared with a domain is already
+		// the base type of the domain.
+		if domainName.Valid {
+			c.UserType = schema.UserType{Name: domainName.String, Kind: schema.UserTypeDomain}
+		}
+		// The dimensions of pgvector columns are the type modifier of the
+		// column, -1 if the column accepts vectors of any dimensions.
+		if dataType == "USER-DEFINED" && udtName.String == "vector" {
+			c.Type = schema.Type{Name: "vector"}
+			if vectorDims.Valid && vectorDims.Int64 > 0 {
+				c.Type.Mods = []int64{vectorDims.Int64}
+			}
+		}
+		if compositeFields.Valid {
+			var fields []string
+			if err := json.Unmarshal([]byte(compositeFields.String), &fields); err != nil {
+				conv.Unexpected(fmt.Sprintf("Can't get fields of composite type %s: %v", udtName.String, err))
+			} else {
+				c.UserType = schema.UserType{Name: udtName.String, Kind: schema.UserTypeComposite, Fields: fields}
+				c.Type = schema.Type{Name: "record"}
+			}
 		}
 		colDefs[colId] = c
 		colIds = append(colIds, colId)
@@ -397,7 +590,43 @@ func (isi InfoSchemaImpl) GetConstraints(conv *internal.Conv, table common.Schem
 			m[col] = append(m[col], constraint)
 		}
 	}
-	return primaryKeys, nil, m, nil
+	checkConstraints, err := isi.getDomainCheckConstraints(conv, table)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return primaryKeys, checkConstraints, m, nil
+}
+
+// getDomainCheckConstraints returns the check constraints of the domains
+// used by the columns of a table, as check constraints of the columns.
+func (isi InfoSchemaImpl) getDomainCheckConstraints(conv *internal.Conv, table common.SchemaAndName) ([]schema.CheckConstraint, error) {
+	q := `SELECT c.column_name, dc.constraint_name, cc.check_clause
+              FROM information_schema.domain_constraints dc
+                INNER JOIN information_schema.check_constraints cc
+                  ON cc.constraint_schema = dc.constraint_schema AND cc.constraint_name = dc.constraint_name
+                INNER JOIN information_schema.columns c
+                  ON c.domain_schema = dc.domain_schema AND c.domain_name = dc.domain_name
+              WHERE c.table_schema = $1 AND c.table_name = $2 ORDER BY c.ordinal_position, dc.constraint_name;`
+	rows, err := isi.Db.Query(q, table.Schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var checkConstraints []schema.CheckConstraint
+	var col, name, clause string
+	for rows.Next() {
+		err := rows.Scan(&col, &name, &clause)
+		if err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		// check_clause doesn't include the CHECK keyword e.g. "((VALUE > 0))".
+		if !strings.HasPrefix(clause, "(") || !strings.HasSuffix(clause, ")") {
+			clause = "(" + clause + ")"
+		}
+		checkConstraints = append(checkConstraints, domainCheckConstraint(col, name, clause))
+	}
+	return checkConstraints, nil
 }
 
 // GetForeignKeys returns a list of all the foreign key constraints.
@@ -480,7 +709,9 @@ func (isi InfoSchemaImpl) GetIndexes(conv *internal.Conv, table common.SchemaAnd
 			a.attname AS column_name,
 			1 + Array_position(i.indkey, a.attnum) AS column_position,
 			i.indisunique AS is_unique,
-			CASE o.OPTION & 1 WHEN 1 THEN 'DESC' ELSE 'ASC' END AS order
+			CASE o.OPTION & 1 WHEN 1 THEN 'DESC' ELSE 'ASC' END AS order,
+			array_position(i.indkey, a.attnum) >= i.indnkeyatts AS is_included,
+			CASE o.OPTION & 2 WHEN 2 THEN 'NULLS FIRST' ELSE 'NULLS LAST' END AS nulls_order
 		FROM pg_index AS i
 		JOIN pg_class AS trel
 		ON trel.oid = i.indrelid
@@ -502,19 +733,20 @@ func (isi InfoSchemaImpl) GetIndexes(conv *internal.Conv, table common.SchemaAnd
            		irel.relname,
            		a.attname,
            		array_position(i.indkey, a.attnum),
-           		o.OPTION,i.indisunique
+           		o.OPTION,i.indisunique,
+           		i.indnkeyatts
 		ORDER BY irel.relname, array_position(i.indkey, a.attnum);`
 	rows, err := isi.Db.Query(q, table.Schema, table.Name)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var name, column, sequence, isUnique, collation string
+	var name, column, sequence, isUnique, collation, isIncluded, nullsOrder string
 	indexMap := make(map[string]schema.Index)
 	var indexNames []string
 	var indexes []schema.Index
 	for rows.Next() {
-		if err := rows.Scan(&name, &column, &sequence, &isUnique, &collation); err != nil {
+		if err := rows.Scan(&name, &column, &sequence, &isUnique, &collation, &isIncluded, &nullsOrder); err != nil {
 			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
 			continue
 		}
@@ -526,9 +758,16 @@ func (isi InfoSchemaImpl) GetIndexes(conv *internal.Conv, table common.SchemaAnd
 				Unique: (isUnique == "true")}
 		}
 		index := indexMap[name]
-		index.Keys = append(index.Keys, schema.Key{
-			ColId: colNameIdMap[column],
-			Desc:  (collation == "DESC")})
+		// INCLUDE columns of a covering index map to the STORING clause in Spanner.
+		if isIncluded == "true" {
+			index.StoredColumnIds = append(index.StoredColumnIds, colNameIdMap[column])
+		} else {
+			desc := (collation == "DESC")
+			index.Keys = append(index.Keys, schema.Key{
+				ColId:      colNameIdMap[column],
+				Desc:       desc,
+				NullsOrder: toNullsOrder(nullsOrder == ddl.NullsFirst)})
+		}
 		indexMap[name] = index
 	}
 	for _, k := range indexNames {
@@ -537,12 +776,38 @@ func (isi InfoSchemaImpl) GetIndexes(conv *internal.Conv, table common.SchemaAnd
 	return indexes, nil
 }
 
-func toType(dataType string, elementDataType sql.NullString, charLen sql.NullInt64, numericPrecision, numericScale sql.NullInt64) schema.Type {
+// toCollation describes PostgreSQL collation name. Collations other than
+// the C ones follow the rules of a locale. Nondeterministic ICU collations
+// may be case-insensitive, which we can only detect from the standard names
+// of their locale, e.g. und-u-ks-level2.
+func toCollation(name string) schema.Collation {
+	name = strings.TrimPrefix(name, "pg_catalog.")
+	if name == "" || name == "default" {
+		return schema.Collation{}
+	}
+	switch name {
+	case "C", "POSIX", "ucs_basic", "pg_c_utf8":
+		return schema.Collation{Name: name, Binary: true}
+	}
+	return schema.Collation{
+		Name:            name,
+		CaseInsensitive: strings.Contains(name, "ks-level1") || strings.Contains(name, "ks-level2"),
+	}
+}
+
+// toType builds a source schema type. For arrays, arrayDims is the number
+// of dimensions declared for the column (attndims). PostgreSQL doesn't
+// enforce it, and reports 0 for arrays declared without dimensions, in which
+// case the array is assumed to have one dimension.
+func toType(dataType string, elementDataType sql.NullString, arrayDims, charLen sql.NullInt64, numericPrecision, numericScale sql.NullInt64) schema.Type {
 	switch {
 	case dataType == "ARRAY" && elementDataType.Valid:
-		return schema.Type{Name: elementDataType.String, ArrayBounds: []int64{-1}}
+		bounds := []int64{-1}
+		for i := int64(1); i < arrayDims.Int64; i++ {
+			bounds = append(bounds, -1)
+		}
+		return schema.Type{Name: elementDataType.String, ArrayBounds: bounds}
 		// TODO: handle error cases.
-		// TODO: handle case of multiple array bounds.
 	case charLen.Valid:
 		return schema.Type{Name: dataType, Mods: []int64{charLen.Int64}}
 	case numericPrecision.Valid && numericScale.Valid && numericScale.Int64 != 0:
@@ -593,12 +858,17 @@ func cvtSQLScalar(conv *internal.Conv, srcCd schema.Column, spCd ddl.ColumnDef,
 		switch v := val.(type) {
 		case bool:
 			return v, nil
+		case []byte: // Bit strings, e.g. "1".
+			return convBool(string(v))
 		case string:
 			return convBool(v)
 		}
 	case ddl.Bytes:
 		switch v := val.(type) {
 		case []byte:
+			if isBitString(srcCd.Type.Name) {
+				return convBitsToBytes(string(v))
+			}
 			return v, nil
 		}
 	case ddl.Date:
@@ -615,6 +885,19 @@ func cvtSQLScalar(conv *internal.Conv, srcCd schema.Column, spCd ddl.ColumnDef,
 			return civil.DateOf(v), nil
 		}
 	case ddl.Int64:
+		if srcCd.Type.Name == "interval" {
+			switch v := val.(type) {
+			case []byte:
+				return convIntervalMicros(string(v))
+			case string:
+				return convIntervalMicros(v)
+			}
+		}
+		if isBitString(srcCd.Type.Name) {
+			if v, ok := val.([]byte); ok {
+				return convBitsToInt64(string(v))
+			}
+		}
 		switch v := val.(type) {
 		case []byte: // Parse as int64.
 			return convInt64(string(v))
@@ -659,6 +942,14 @@ func cvtSQLScalar(conv *internal.Conv, srcCd schema.Column, spCd ddl.ColumnDef,
 			return convNumeric(conv, string(v))
 		}
 	case ddl.String:
+		if srcCd.Type.Name == "interval" {
+			switch v := val.(type) {
+			case []byte:
+				return convInterval(string(v))
+			case string:
+				return convInterval(v)
+			}
+		}
 		switch v := val.(type) {
 		case bool:
 			return strconv.FormatBool(v), nil
@@ -673,6 +964,12 @@ func cvtSQLScalar(conv *internal.Conv, srcCd schema.Column, spCd ddl.ColumnDef,
 		case string:
 			return v, nil
 		case time.Time:
+			switch srcCd.Type.Name {
+			case "time", "time without time zone":
+				return v.Format("15:04:05.999999"), nil
+			case "timetz", "time with time zone":
+				return v.Format("15:04:05.999999-07:00"), nil
+			}
 			return v.String(), nil
 		}
 	case ddl.Timestamp:
@@ -683,6 +980,22 @@ func cvtSQLScalar(conv *internal.Conv, srcCd schema.Column, spCd ddl.ColumnDef,
 			return v, nil
 		}
 	case ddl.JSON:
+		if srcCd.UserType.Kind == schema.UserTypeComposite {
+			switch v := val.(type) {
+			case []byte:
+				return convCompositeToJSON(srcCd.UserType.Fields, string(v))
+			case string:
+				return convCompositeToJSON(srcCd.UserType.Fields, v)
+			}
+		}
+		if len(srcCd.Type.ArrayBounds) > 1 {
+			switch v := val.(type) {
+			case []byte:
+				return convArrayToJSON(srcCd.Type.Name, string(v))
+			case string:
+				return convArrayToJSON(srcCd.Type.Name, v)
+			}
+		}
 		switch v := val.(type) {
 		case string:
 			return string(v), nil
@@ -705,6 +1018,20 @@ func buildVals(n int) (v []interface{}, iv []interface{}) {
 	return v, iv
 }
 
+// sqlValToString returns the text of a value scanned from a row, and false
+// if it is NULL.
+func sqlValToString(val interface{}) (string, bool) {
+	switch v := val.(type) {
+	case nil:
+		return "", false
+	case []byte:
+		return string(v), true
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true
+	}
+	return fmt.Sprintf("%v", val), true
+}
+
 func valsToStrings(vals []interface{}) []string {
 	toString := func(val interface{}) string {
 		if val == nil {
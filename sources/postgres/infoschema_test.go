@@ -52,14 +52,14 @@ type mockSpec struct {
 func TestProcessSchema(t *testing.T) {
 	ms := []mockSpec{
 		{
-			query: "SELECT table_schema, table_name FROM information_schema.tables where table_type = 'BASE TABLE'",
-			cols:  []string{"table_schema", "table_name"},
+			query: "SELECT table_schema, table_name, (.+) FROM information_schema.tables where table_type = 'BASE TABLE'",
+			cols:  []string{"table_schema", "table_name", "obj_description"},
 			rows: [][]driver.Value{
-				{"public", "user"},
-				{"public", "cart"},
-				{"public", "product"},
-				{"public", "test"},
-				{"public", "test_ref"}},
+				{"public", "user", nil},
+				{"public", "cart", nil},
+				{"public", "product", nil},
+				{"public", "test", nil},
+				{"public", "test_ref", nil}},
 		},
 		{
 			query: "SELECT (.+) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS (.+)",
@@ -69,6 +69,11 @@ func TestProcessSchema(t *testing.T) {
 				{"user_id", "PRIMARY KEY"},
 				{"ref", "FOREIGN KEY"}},
 		},
+		{
+			query: "SELECT (.+) FROM information_schema.domain_constraints (.+)",
+			args:  []driver.Value{"public", "user"},
+			cols:  []string{"column_name", "constraint_name", "check_clause"},
+		},
 		{
 			query: "SELECT (.+) FROM INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS (.+) JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE (.+) JOIN INFORMATION_SCHEMA.CONSTRAINT_COLUMN_USAGE (.+)",
 			args:  []driver.Value{"public", "user"},
@@ -85,17 +90,17 @@ func TestProcessSchema(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"public", "user"},
-			cols:  []string{"column_name", "data_type", "data_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale"},
+			cols:  []string{"column_name", "data_type", "data_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "col_description", "attndims", "domain_name", "udt_name", "json_agg", "collation_name", "atttypmod"},
 			rows: [][]driver.Value{
-				{"user_id", "text", nil, "NO", nil, nil, nil, nil},
-				{"name", "text", nil, "NO", nil, nil, nil, nil},
-				{"ref", "bigint", nil, "YES", nil, nil, nil, nil}},
+				{"user_id", "text", nil, "NO", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"name", "text", nil, "NO", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"ref", "bigint", nil, "YES", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil}},
 		},
 		// db call to fetch index happens after fetching of column
 		{
 			query: "SELECT (.+) FROM pg_index (.+)",
 			args:  []driver.Value{"public", "user"},
-			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
+			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order", "is_included", "nulls_order"},
 		},
 
 		{
@@ -106,6 +111,11 @@ func TestProcessSchema(t *testing.T) {
 				{"productid", "PRIMARY KEY"},
 				{"userid", "PRIMARY KEY"}},
 		},
+		{
+			query: "SELECT (.+) FROM information_schema.domain_constraints (.+)",
+			args:  []driver.Value{"public", "cart"},
+			cols:  []string{"column_name", "constraint_name", "check_clause"},
+		},
 		{
 			query: "SELECT (.+) FROM INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS (.+) JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE (.+) JOIN INFORMATION_SCHEMA.CONSTRAINT_COLUMN_USAGE (.+)",
 			args:  []driver.Value{"public", "cart"},
@@ -122,22 +132,23 @@ func TestProcessSchema(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"public", "cart"},
-			cols:  []string{"column_name", "data_type", "data_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale"},
+			cols:  []string{"column_name", "data_type", "data_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "col_description", "attndims", "domain_name", "udt_name", "json_agg", "collation_name", "atttypmod"},
 			rows: [][]driver.Value{
-				{"productid", "text", nil, "NO", nil, nil, nil, nil},
-				{"userid", "text", nil, "NO", nil, nil, nil, nil},
-				{"quantity", "bigint", nil, "YES", nil, nil, 64, 0}},
+				{"productid", "text", nil, "NO", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"userid", "text", nil, "NO", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"quantity", "bigint", nil, "YES", nil, nil, 64, 0, nil, nil, nil, nil, nil, nil, nil}},
 		},
 		// db call to fetch index happens after fetching of column
 		{
 			query: "SELECT (.+) FROM pg_index (.+)",
 			args:  []driver.Value{"public", "cart"},
-			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
-			rows: [][]driver.Value{{"index1", "userid", 1, "false", "ASC"},
-				{"index2", "userid", 1, "true", "ASC"},
-				{"index2", "productid", 2, "true", "DESC"},
-				{"index3", "productid", 1, "true", "DESC"},
-				{"index3", "userid", 2, "true", "ASC"},
+			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order", "is_included", "nulls_order"},
+			rows: [][]driver.Value{{"index1", "userid", 1, "false", "ASC", "false", "NULLS LAST"},
+				{"index1", "quantity", 2, "false", "ASC", "true", "NULLS LAST"},
+				{"index2", "userid", 1, "true", "ASC", "false", "NULLS LAST"},
+				{"index2", "productid", 2, "true", "DESC", "false", "NULLS FIRST"},
+				{"index3", "productid", 1, "true", "DESC", "false", "NULLS FIRST"},
+				{"index3", "userid", 2, "true", "ASC", "false", "NULLS LAST"},
 			},
 		},
 		{
@@ -147,6 +158,11 @@ func TestProcessSchema(t *testing.T) {
 			rows: [][]driver.Value{
 				{"product_id", "PRIMARY KEY"}},
 		},
+		{
+			query: "SELECT (.+) FROM information_schema.domain_constraints (.+)",
+			args:  []driver.Value{"public", "product"},
+			cols:  []string{"column_name", "constraint_name", "check_clause"},
+		},
 		{
 			query: "SELECT (.+) FROM INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS (.+) JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE (.+) JOIN INFORMATION_SCHEMA.CONSTRAINT_COLUMN_USAGE (.+)",
 			args:  []driver.Value{"public", "product"},
@@ -160,16 +176,16 @@ func TestProcessSchema(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"public", "product"},
-			cols:  []string{"column_name", "data_type", "data_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale"},
+			cols:  []string{"column_name", "data_type", "data_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "col_description", "attndims", "domain_name", "udt_name", "json_agg", "collation_name", "atttypmod"},
 			rows: [][]driver.Value{
-				{"product_id", "text", nil, "NO", nil, nil, nil, nil},
-				{"product_name", "text", nil, "NO", nil, nil, nil, nil}},
+				{"product_id", "text", nil, "NO", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"product_name", "text", nil, "NO", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil}},
 		},
 		// db call to fetch index happens after fetching of column
 		{
 			query: "SELECT (.+) FROM pg_index (.+)",
 			args:  []driver.Value{"public", "product"},
-			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
+			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order", "is_included", "nulls_order"},
 		},
 
 		{
@@ -177,6 +193,12 @@ func TestProcessSchema(t *testing.T) {
 			args:  []driver.Value{"public", "test"},
 			cols:  []string{"column_name", "constraint_type"},
 			rows:  [][]driver.Value{{"id", "PRIMARY KEY"}},
+		},
+		{
+			query: "SELECT (.+) FROM information_schema.domain_constraints (.+)",
+			args:  []driver.Value{"public", "test"},
+			cols:  []string{"column_name", "constraint_name", "check_clause"},
+			rows:  [][]driver.Value{{"price", "price_check", "((VALUE > (0)::numeric))"}},
 		}, {
 			query: "SELECT (.+) FROM INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS (.+) JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE (.+) JOIN INFORMATION_SCHEMA.CONSTRAINT_COLUMN_USAGE (.+)",
 			args:  []driver.Value{"public", "test"},
@@ -194,35 +216,38 @@ func TestProcessSchema(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"public", "test"},
-			cols:  []string{"column_name", "data_type", "data_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale"},
+			cols:  []string{"column_name", "data_type", "data_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "col_description", "attndims", "domain_name", "udt_name", "json_agg", "collation_name", "atttypmod"},
 			rows: [][]driver.Value{
-				{"id", "bigint", nil, "NO", "nextval('public.test_id_seq'::regclass)", nil, 64, 0},
-				{"aint", "ARRAY", "integer", "YES", nil, nil, nil, nil},
-				{"atext", "ARRAY", "text", "YES", nil, nil, nil, nil},
-				{"b", "boolean", nil, "YES", nil, nil, nil, nil},
-				{"bs", "bigint", nil, "NO", "nextval('test11_bs_seq'::regclass)", nil, 64, 0},
-				{"by", "bytea", nil, "YES", nil, nil, nil, nil},
-				{"c", "character", nil, "YES", nil, 1, nil, nil},
-				{"c_8", "character", nil, "YES", nil, 8, nil, nil},
-				{"d", "date", nil, "YES", nil, nil, nil, nil},
-				{"f8", "double precision", nil, "YES", nil, nil, 53, nil},
-				{"f4", "real", nil, "YES", nil, nil, 24, nil},
-				{"i8", "bigint", nil, "YES", nil, nil, 64, 0},
-				{"i4", "integer", nil, "YES", nil, nil, 32, 0},
-				{"i2", "smallint", nil, "YES", nil, nil, 16, 0},
-				{"num", "numeric", nil, "YES", nil, nil, nil, nil},
-				{"s", "integer", nil, "NO", "nextval('test11_s_seq'::regclass)", nil, 32, 0},
-				{"ts", "timestamp without time zone", nil, "YES", nil, nil, nil, nil},
-				{"tz", "timestamp with time zone", nil, "YES", nil, nil, nil, nil},
-				{"txt", "text", nil, "NO", nil, nil, nil, nil},
-				{"vc", "character varying", nil, "YES", nil, nil, nil, nil},
-				{"vc6", "character varying", nil, "YES", nil, 6, nil, nil}},
+				{"id", "bigint", nil, "NO", "nextval('public.test_id_seq'::regclass)", nil, 64, 0, nil, nil, nil, nil, nil, nil, nil},
+				{"aint", "ARRAY", "integer", "YES", nil, nil, nil, nil, nil, 1, nil, nil, nil, nil, nil},
+				{"atext", "ARRAY", "text", "YES", nil, nil, nil, nil, nil, 0, nil, nil, nil, nil, nil},
+				{"amat", "ARRAY", "integer", "YES", nil, nil, nil, nil, nil, 2, nil, nil, nil, nil, nil},
+				{"b", "boolean", nil, "YES", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"bs", "bigint", nil, "NO", "nextval('test11_bs_seq'::regclass)", nil, 64, 0, nil, nil, nil, nil, nil, nil, nil},
+				{"by", "bytea", nil, "YES", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"c", "character", nil, "YES", nil, 1, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"c_8", "character", nil, "YES", nil, 8, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"d", "date", nil, "YES", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"f8", "double precision", nil, "YES", nil, nil, 53, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"f4", "real", nil, "YES", nil, nil, 24, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"i8", "bigint", nil, "YES", nil, nil, 64, 0, nil, nil, nil, nil, nil, nil, nil},
+				{"i4", "integer", nil, "YES", nil, nil, 32, 0, nil, nil, nil, nil, nil, nil, nil},
+				{"i2", "smallint", nil, "YES", nil, nil, 16, 0, nil, nil, nil, nil, nil, nil, nil},
+				{"num", "numeric", nil, "YES", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"s", "integer", nil, "NO", "nextval('test11_s_seq'::regclass)", nil, 32, 0, nil, nil, nil, nil, nil, nil, nil},
+				{"ts", "timestamp without time zone", nil, "YES", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"tz", "timestamp with time zone", nil, "YES", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"txt", "text", nil, "NO", nil, nil, nil, nil, nil, nil, nil, nil, nil, "C", nil},
+				{"vc", "character varying", nil, "YES", nil, nil, nil, nil, nil, nil, nil, nil, nil, "und-u-ks-level2", nil},
+				{"vc6", "character varying", nil, "YES", nil, 6, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"price", "numeric", nil, "YES", nil, nil, nil, nil, nil, nil, "positive_price", "numeric", nil, nil, nil},
+				{"addr", "USER-DEFINED", nil, "YES", nil, nil, nil, nil, nil, nil, nil, "address", `["street", "city"]`, nil, nil}},
 		},
 		// db call to fetch index happens after fetching of column
 		{
 			query: "SELECT (.+) FROM pg_index (.+)",
 			args:  []driver.Value{"public", "test"},
-			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
+			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order", "is_included", "nulls_order"},
 		},
 
 		{
@@ -232,6 +257,11 @@ func TestProcessSchema(t *testing.T) {
 			rows: [][]driver.Value{
 				{"ref_id", "PRIMARY KEY"},
 				{"ref_txt", "PRIMARY KEY"}},
+		},
+		{
+			query: "SELECT (.+) FROM information_schema.domain_constraints (.+)",
+			args:  []driver.Value{"public", "test_ref"},
+			cols:  []string{"column_name", "constraint_name", "check_clause"},
 		}, {
 			query: "SELECT (.+) FROM INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS (.+) JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE (.+) JOIN INFORMATION_SCHEMA.CONSTRAINT_COLUMN_USAGE (.+)",
 			args:  []driver.Value{"public", "test_ref"},
@@ -245,17 +275,17 @@ func TestProcessSchema(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"public", "test_ref"},
-			cols:  []string{"column_name", "data_type", "data_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale"},
+			cols:  []string{"column_name", "data_type", "data_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "col_description", "attndims", "domain_name", "udt_name", "json_agg", "collation_name", "atttypmod"},
 			rows: [][]driver.Value{
-				{"ref_id", "bigint", nil, "NO", nil, nil, 64, 0},
-				{"ref_txt", "text", nil, "NO", nil, nil, nil, nil},
-				{"abc", "text", nil, "NO", nil, nil, nil, nil}},
+				{"ref_id", "bigint", nil, "NO", nil, nil, 64, 0, nil, nil, nil, nil, nil, nil, nil},
+				{"ref_txt", "text", nil, "NO", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"abc", "text", nil, "NO", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil}},
 		},
 		// db call to fetch index happens after fetching of column
 		{
 			query: "SELECT (.+) FROM pg_index (.+)",
 			args:  []driver.Value{"public", "test_ref"},
-			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
+			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order", "is_included", "nulls_order"},
 		},
 	}
 	db := mkMockDB(t, ms)
@@ -272,7 +302,7 @@ func TestProcessSchema(t *testing.T) {
 		ExpressionVerificationAccessor: mockAccessor,
 		DdlV:                           &expressions_api.MockDDLVerifier{},
 	}
-	err := processSchema.ProcessSchema(conv, InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr()}, 1, internal.AdditionalSchemaAttributes{}, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
+	err := processSchema.ProcessSchema(conv, InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr(), nil}, 1, internal.AdditionalSchemaAttributes{}, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
 	assert.Nil(t, err)
 	expectedSchema := map[string]ddl.CreateTable{
 		"user": ddl.CreateTable{
@@ -296,7 +326,7 @@ func TestProcessSchema(t *testing.T) {
 			PrimaryKeys: []ddl.IndexKey{ddl.IndexKey{ColId: "productid", Order: 1}, ddl.IndexKey{ColId: "userid", Order: 2}},
 			ForeignKeys: []ddl.Foreignkey{ddl.Foreignkey{Name: "fk_test2", ColIds: []string{"productid"}, ReferTableId: "product", ReferColumnIds: []string{"product_id"}, OnDelete: constants.FK_NO_ACTION, OnUpdate: constants.FK_NO_ACTION},
 				ddl.Foreignkey{Name: "fk_test3", ColIds: []string{"userid"}, ReferTableId: "user", ReferColumnIds: []string{"user_id"}, OnDelete: constants.FK_NO_ACTION, OnUpdate: constants.FK_NO_ACTION}},
-			Indexes: []ddl.CreateIndex{ddl.CreateIndex{Name: "index1", TableId: "cart", Unique: false, Keys: []ddl.IndexKey{ddl.IndexKey{ColId: "userid", Desc: false, Order: 1}}},
+			Indexes: []ddl.CreateIndex{ddl.CreateIndex{Name: "index1", TableId: "cart", Unique: false, Keys: []ddl.IndexKey{ddl.IndexKey{ColId: "userid", Desc: false, Order: 1}}, StoredColumnIds: []string{"quantity"}},
 				ddl.CreateIndex{Name: "index2", TableId: "cart", Unique: true, Keys: []ddl.IndexKey{ddl.IndexKey{ColId: "userid", Desc: false, Order: 1}, ddl.IndexKey{ColId: "productid", Desc: true, Order: 2}}},
 				ddl.CreateIndex{Name: "index3", TableId: "cart", Unique: true, Keys: []ddl.IndexKey{ddl.IndexKey{ColId: "productid", Desc: true, Order: 1}, ddl.IndexKey{ColId: "userid", Desc: false, Order: 2}}}}},
 		"product": ddl.CreateTable{
@@ -309,11 +339,12 @@ func TestProcessSchema(t *testing.T) {
 			PrimaryKeys: []ddl.IndexKey{ddl.IndexKey{ColId: "product_id", Order: 1}}},
 		"test": ddl.CreateTable{
 			Name:   "test",
-			ColIds: []string{"id", "aint", "atext", "b", "bs", "by", "c", "c_8", "d", "f8", "f4", "i8", "i4", "i2", "num", "s", "ts", "tz", "txt", "vc", "vc6"},
+			ColIds: []string{"id", "aint", "atext", "amat", "b", "bs", "by", "c", "c_8", "d", "f8", "f4", "i8", "i4", "i2", "num", "s", "ts", "tz", "txt", "vc", "vc6", "price", "addr"},
 			ColDefs: map[string]ddl.ColumnDef{
 				"id":    ddl.ColumnDef{Name: "id", T: ddl.Type{Name: ddl.Int64}, NotNull: true, AutoGen: ddl.AutoGenCol{Name: constants.IDENTITY, GenerationType: constants.IDENTITY}},
-				"aint":  ddl.ColumnDef{Name: "aint", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: false}},
-				"atext": ddl.ColumnDef{Name: "atext", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: false}},
+				"aint":  ddl.ColumnDef{Name: "aint", T: ddl.Type{Name: ddl.Int64, IsArray: true}},
+				"atext": ddl.ColumnDef{Name: "atext", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}},
+				"amat":  ddl.ColumnDef{Name: "amat", T: ddl.Type{Name: ddl.JSON}},
 				"b":     ddl.ColumnDef{Name: "b", T: ddl.Type{Name: ddl.Bool}},
 				"bs":    ddl.ColumnDef{Name: "bs", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
 				"by":    ddl.ColumnDef{Name: "by", T: ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}},
@@ -332,9 +363,12 @@ func TestProcessSchema(t *testing.T) {
 				"txt":   ddl.ColumnDef{Name: "txt", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, NotNull: true},
 				"vc":    ddl.ColumnDef{Name: "vc", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
 				"vc6":   ddl.ColumnDef{Name: "vc6", T: ddl.Type{Name: ddl.String, Len: int64(6)}},
+				"price": ddl.ColumnDef{Name: "price", T: ddl.Type{Name: ddl.Numeric}},
+				"addr":  ddl.ColumnDef{Name: "addr", T: ddl.Type{Name: ddl.JSON}},
 			},
-			PrimaryKeys: []ddl.IndexKey{ddl.IndexKey{ColId: "id", Order: 1}},
-			ForeignKeys: []ddl.Foreignkey{ddl.Foreignkey{Name: "fk_test4", ColIds: []string{"id", "txt"}, ReferTableId: "test_ref", ReferColumnIds: []string{"ref_id", "ref_txt"}, OnDelete: constants.FK_CASCADE, OnUpdate: constants.FK_NO_ACTION}}},
+			PrimaryKeys:      []ddl.IndexKey{ddl.IndexKey{ColId: "id", Order: 1}},
+			CheckConstraints: []ddl.CheckConstraint{{Name: "price_price_check", Expr: "((price > (0)::numeric))"}},
+			ForeignKeys:      []ddl.Foreignkey{ddl.Foreignkey{Name: "fk_test4", ColIds: []string{"id", "txt"}, ReferTableId: "test_ref", ReferColumnIds: []string{"ref_id", "ref_txt"}, OnDelete: constants.FK_CASCADE, OnUpdate: constants.FK_NO_ACTION}}},
 		"test_ref": ddl.CreateTable{
 			Name:   "test_ref",
 			ColIds: []string{"ref_id", "ref_txt", "abc"},
@@ -348,7 +382,12 @@ func TestProcessSchema(t *testing.T) {
 	internal.AssertSpSchema(conv, t, expectedSchema, stripSchemaComments(conv.SpSchema))
 	cartTableId, err := internal.GetTableIdFromSpName(conv.SpSchema, "cart")
 	assert.Equal(t, nil, err)
-	assert.Equal(t, len(conv.SchemaIssues[cartTableId].ColumnLevelIssues), 0)
+	// GoogleSQL sorts NULLs first in ascending order, unlike the PostgreSQL
+	// default ordering of the cart indexes.
+	internal.AssertTableIssues(conv, t, cartTableId, map[string][]internal.SchemaIssue{
+		"userid":    []internal.SchemaIssue{internal.IndexNullsOrder},
+		"productid": []internal.SchemaIssue{internal.IndexNullsOrder},
+	}, conv.SchemaIssues[cartTableId].ColumnLevelIssues)
 	expectedIssues := map[string][]internal.SchemaIssue{
 		"id":  []internal.SchemaIssue{internal.IdentitySkipRange},
 		"aint":  []internal.SchemaIssue{internal.Widened, internal.ArrayTypeNotSupported},
@@ -358,6 +397,10 @@ func TestProcessSchema(t *testing.T) {
 		"s":     []internal.SchemaIssue{internal.Widened, internal.DefaultValue},
 		"ts":    []internal.SchemaIssue{internal.Timestamp},
 		"atext": []internal.SchemaIssue{internal.ArrayTypeNotSupported},
+		"amat":  []internal.SchemaIssue{internal.MultiDimensionalArray},
+		"price": []internal.SchemaIssue{internal.DomainType},
+		"addr":  []internal.SchemaIssue{internal.CompositeType},
+		"vc":    []internal.SchemaIssue{internal.CaseInsensitiveCollation},
 	}
 	testTableId, err := internal.GetTableIdFromSpName(conv.SpSchema, "test")
 	assert.Equal(t, nil, err)
@@ -408,7 +451,7 @@ func TestProcessData(t *testing.T) {
 			rows = append(rows, spannerData{table: table, cols: cols, vals: vals})
 		})
 	commonInfoSchema := common.InfoSchemaImpl{}
-	commonInfoSchema.ProcessData(conv, InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr()}, internal.AdditionalDataAttributes{})
+	commonInfoSchema.ProcessData(conv, InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr(), nil}, internal.AdditionalDataAttributes{})
 
 	assert.Equal(t,
 		[]spannerData{
@@ -459,8 +502,14 @@ func TestConvertSqlRow_SingleCol(t *testing.T) {
 		{name: "string float64", srcType: schema.Type{Name: "float8"}, spType: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, in: float64(42.3), e: "42.3"},
 		{name: "string time", srcType: schema.Type{Name: "timestamp"}, spType: ddl.Type{Name: ddl.String, Len: ddl.MaxLength},
 			in: getTime(t, "2019-10-29T05:30:00+10:00"), e: "2019-10-29 05:30:00 +1000 +1000"},
+		{name: "string time of day", srcType: schema.Type{Name: "time"}, spType: ddl.Type{Name: ddl.String, Len: ddl.MaxLength},
+			in: getTime(t, "0000-01-01T05:30:00.123456Z"), e: "05:30:00.123456"},
+		{name: "string time of day with time zone", srcType: schema.Type{Name: "timetz"}, spType: ddl.Type{Name: ddl.String, Len: ddl.MaxLength},
+			in: getTime(t, "0000-01-01T05:30:00.5+10:00"), e: "05:30:00.5+10:00"},
 		{name: "timestamptz", srcType: schema.Type{Name: "timestamptz"}, spType: ddl.Type{Name: ddl.Timestamp},
 			in: getTime(t, "2019-10-29T05:30:00+10:00"), e: getTime(t, "2019-10-29T05:30:00+10:00")},
+		{name: "timestamptz microseconds", srcType: schema.Type{Name: "timestamptz"}, spType: ddl.Type{Name: ddl.Timestamp},
+			in: "2019-10-29 05:30:00.123456+10:00", e: getTime(t, "2019-10-29T05:30:00.123456+10:00")},
 		{name: "timestamptz string", srcType: schema.Type{Name: "timestamptz"}, spType: ddl.Type{Name: ddl.Timestamp},
 			in: "2019-10-29 05:30:00+10:00", e: getTime(t, "2019-10-29T05:30:00+10:00")},
 		{name: "timestamp", srcType: schema.Type{Name: "timestamptz"}, spType: ddl.Type{Name: ddl.Timestamp},
@@ -481,6 +530,8 @@ func TestConvertSqlRow_SingleCol(t *testing.T) {
 			e: []spanner.NullTime{
 				spanner.NullTime{Time: getTime(t, "2019-10-29T05:30:00+10:00"), Valid: true},
 				spanner.NullTime{Valid: false}}},
+		{name: "multi-dimensional array", srcType: schema.Type{Name: "integer", ArrayBounds: []int64{-1, -1}}, spType: ddl.Type{Name: ddl.JSON},
+			in: []byte("{{1,2},{3,NULL}}"), e: "[[1,2],[3,null]]"},
 	}
 	tableName := "testtable"
 	tableId := "t1"
@@ -518,15 +569,20 @@ func TestConvertSqlRow_MultiCol(t *testing.T) {
 	// ProcessInfoSchema.
 	ms := []mockSpec{
 		{
-			query: "SELECT table_schema, table_name FROM information_schema.tables where table_type = 'BASE TABLE'",
-			cols:  []string{"table_schema", "table_name"},
-			rows:  [][]driver.Value{{"public", "test"}},
+			query: "SELECT table_schema, table_name, (.+) FROM information_schema.tables where table_type = 'BASE TABLE'",
+			cols:  []string{"table_schema", "table_name", "obj_description"},
+			rows:  [][]driver.Value{{"public", "test", nil}},
 		}, {
 			query: "SELECT (.+) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS (.+)",
 			args:  []driver.Value{"public", "test"},
 			cols:  []string{"column_name", "constraint_type"},
 			rows:  [][]driver.Value{}, // No primary key --> force generation of synthetic key.
 		},
+		{
+			query: "SELECT (.+) FROM information_schema.domain_constraints (.+)",
+			args:  []driver.Value{"public", "test"},
+			cols:  []string{"column_name", "constraint_name", "check_clause"},
+		},
 		{
 			query: "SELECT (.+) FROM INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS (.+) JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE (.+) JOIN INFORMATION_SCHEMA.CONSTRAINT_COLUMN_USAGE (.+)",
 			args:  []driver.Value{"public", "test"},
@@ -540,17 +596,17 @@ func TestConvertSqlRow_MultiCol(t *testing.T) {
 		{
 			query: "SELECT (.+) FROM information_schema.COLUMNS (.+)",
 			args:  []driver.Value{"public", "test"},
-			cols:  []string{"column_name", "data_type", "data_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale"},
+			cols:  []string{"column_name", "data_type", "data_type", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale", "col_description", "attndims", "domain_name", "udt_name", "json_agg", "collation_name", "atttypmod"},
 			rows: [][]driver.Value{
-				{"a", "text", nil, "NO", nil, nil, nil, nil},
-				{"b", "double precision", nil, "YES", nil, nil, 53, nil},
-				{"c", "bigint", nil, "YES", nil, nil, 64, 0}},
+				{"a", "text", nil, "NO", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"b", "double precision", nil, "YES", nil, nil, 53, nil, nil, nil, nil, nil, nil, nil, nil},
+				{"c", "bigint", nil, "YES", nil, nil, 64, 0, nil, nil, nil, nil, nil, nil, nil}},
 		},
 		// db call to fetch index happens after fetching of column
 		{
 			query: "SELECT (.+) FROM pg_index (.+)",
 			args:  []driver.Value{"public", "test"},
-			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order"},
+			cols:  []string{"index_name", "column_name", "column_position", "is_unique", "order", "is_included", "nulls_order"},
 		},
 		{
 			query: `SELECT [*] FROM "public"."test"`, // query is a regexp!
@@ -574,7 +630,7 @@ func TestConvertSqlRow_MultiCol(t *testing.T) {
 		ExpressionVerificationAccessor: mockAccessor,
 		DdlV:                           &expressions_api.MockDDLVerifier{},
 	}
-	err := processSchema.ProcessSchema(conv, InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr()}, 1, internal.AdditionalSchemaAttributes{}, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
+	err := processSchema.ProcessSchema(conv, InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr(), nil}, 1, internal.AdditionalSchemaAttributes{}, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
 	assert.Nil(t, err)
 	conv.SetDataMode()
 	var rows []spannerData
@@ -583,7 +639,7 @@ func TestConvertSqlRow_MultiCol(t *testing.T) {
 			rows = append(rows, spannerData{table: table, cols: cols, vals: vals})
 		})
 	commonInfoSchema := common.InfoSchemaImpl{}
-	commonInfoSchema.ProcessData(conv, InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr()}, internal.AdditionalDataAttributes{})
+	commonInfoSchema.ProcessData(conv, InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr(), nil}, internal.AdditionalDataAttributes{})
 	assert.Equal(t, []spannerData{
 		{table: "test", cols: []string{"a", "b", "synth_id"}, vals: []interface{}{"cat", float64(42.3), "0"}},
 		{table: "test", cols: []string{"a", "c", "synth_id"}, vals: []interface{}{"dog", int64(22), "-9223372036854775808"}}},
@@ -594,9 +650,9 @@ func TestConvertSqlRow_MultiCol(t *testing.T) {
 func TestSetRowStats(t *testing.T) {
 	ms := []mockSpec{
 		{
-			query: "SELECT table_schema, table_name FROM information_schema.tables where table_type = 'BASE TABLE'",
-			cols:  []string{"table_schema", "table_name"},
-			rows:  [][]driver.Value{{"public", "test1"}, {"public", "test2"}},
+			query: "SELECT table_schema, table_name, (.+) FROM information_schema.tables where table_type = 'BASE TABLE'",
+			cols:  []string{"table_schema", "table_name", "obj_description"},
+			rows:  [][]driver.Value{{"public", "test1", nil}, {"public", "test2", nil}},
 		}, {
 			query: `SELECT COUNT[(][*][)] FROM "public"."test1"`,
 			cols:  []string{"count"},
@@ -611,7 +667,7 @@ func TestSetRowStats(t *testing.T) {
 	conv := internal.MakeConv()
 	conv.SetDataMode()
 	commonInfoSchema := common.InfoSchemaImpl{}
-	commonInfoSchema.SetRowStats(conv, InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr()})
+	commonInfoSchema.SetRowStats(conv, InfoSchemaImpl{db, "migration-project-id", profiles.SourceProfile{}, profiles.TargetProfile{}, newFalsePtr(), nil})
 	assert.Equal(t, int64(5), conv.Stats.Rows["test1"])
 	assert.Equal(t, int64(142), conv.Stats.Rows["test2"])
 	assert.Equal(t, int64(0), conv.Unexpecteds())
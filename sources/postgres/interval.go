@@ -0,0 +1,239 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	microsPerSecond = int64(1000000)
+	microsPerDay    = 86400 * microsPerSecond
+)
+
+// interval is a PostgreSQL interval. Like PostgreSQL, it keeps months, days
+// and microseconds separately since the length of months and days varies.
+type interval struct {
+	months int64
+	days   int64
+	micros int64
+}
+
+var (
+	isoInterval = regexp.MustCompile(`^P(?:([-+]?[\d.]+)Y)?(?:([-+]?[\d.]+)M)?(?:([-+]?[\d.]+)W)?(?:([-+]?[\d.]+)D)?(?:T(?:([-+]?[\d.]+)H)?(?:([-+]?[\d.]+)M)?(?:([-+]?[\d.]+)S)?)?$`)
+	// Time of day part of an interval, e.g. "-04:05:06.789" or "4:05".
+	intervalTime = regexp.MustCompile(`^([-+]?)(\d+):(\d+)(?::(\d+(?:\.\d+)?))?$`)
+	// Year-month part of a sql_standard interval, e.g. "1-2".
+	intervalYearMonth = regexp.MustCompile(`^([-+]?)(\d+)-(\d+)$`)
+)
+
+// intervalUnits maps the units accepted in interval literals to their
+// length in months, days or microseconds.
+var intervalUnits = map[string]struct {
+	months int64
+	micros int64
+	days   int64
+}{
+	"millennium": {months: 12000}, "millennia": {months: 12000}, "mil": {months: 12000}, "mils": {months: 12000},
+	"century": {months: 1200}, "centuries": {months: 1200}, "c": {months: 1200},
+	"decade": {months: 120}, "decades": {months: 120}, "dec": {months: 120}, "decs": {months: 120},
+	"year": {months: 12}, "years": {months: 12}, "yr": {months: 12}, "yrs": {months: 12}, "y": {months: 12},
+	"month": {months: 1}, "months": {months: 1}, "mon": {months: 1}, "mons": {months: 1},
+	"week": {days: 7}, "weeks": {days: 7}, "w": {days: 7},
+	"day": {days: 1}, "days": {days: 1}, "d": {days: 1},
+	"hour": {micros: 3600 * microsPerSecond}, "hours": {micros: 3600 * microsPerSecond}, "hr": {micros: 3600 * microsPerSecond}, "hrs": {micros: 3600 * microsPerSecond}, "h": {micros: 3600 * microsPerSecond},
+	"minute": {micros: 60 * microsPerSecond}, "minutes": {micros: 60 * microsPerSecond}, "min": {micros: 60 * microsPerSecond}, "mins": {micros: 60 * microsPerSecond}, "m": {micros: 60 * microsPerSecond},
+	"second": {micros: microsPerSecond}, "seconds": {micros: microsPerSecond}, "sec": {micros: microsPerSecond}, "secs": {micros: microsPerSecond}, "s": {micros: microsPerSecond},
+	"millisecond": {micros: 1000}, "milliseconds": {micros: 1000}, "ms": {micros: 1000}, "msec": {micros: 1000}, "msecs": {micros: 1000},
+	"microsecond": {micros: 1}, "microseconds": {micros: 1}, "us": {micros: 1}, "usec": {micros: 1}, "usecs": {micros: 1},
+}
+
+// parseInterval parses a PostgreSQL interval in any of the output styles
+// (postgres, postgres_verbose, sql_standard and iso_8601), as well as
+// interval literals mixing units, e.g. "1 day 2 hours -30 minutes".
+func parseInterval(s string) (interval, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "P") {
+		return parseISOInterval(s)
+	}
+	var iv interval
+	fields := strings.Fields(strings.ToLower(s))
+	ago := false
+	if len(fields) > 0 && fields[0] == "@" {
+		fields = fields[1:]
+	}
+	if len(fields) > 0 && fields[len(fields)-1] == "ago" {
+		ago = true
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields) == 0 {
+		return iv, fmt.Errorf("can't convert %q to interval", s)
+	}
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if m := intervalYearMonth.FindStringSubmatch(f); m != nil {
+			years, _ := strconv.ParseInt(m[2], 10, 64)
+			months, _ := strconv.ParseInt(m[3], 10, 64)
+			months += 12 * years
+			if m[1] == "-" {
+				months = -months
+			}
+			iv.months += months
+			continue
+		}
+		if m := intervalTime.FindStringSubmatch(f); m != nil {
+			micros, err := parseIntervalTime(m)
+			if err != nil {
+				return iv, fmt.Errorf("can't convert %q to interval: %w", s, err)
+			}
+			iv.micros += micros
+			continue
+		}
+		n, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return iv, fmt.Errorf("can't convert %q to interval: unexpected %q", s, f)
+		}
+		// A number without unit is a number of days when followed by a
+		// time (e.g. "3 4:05:06" in the sql_standard style), and a number
+		// of seconds otherwise.
+		if i+1 < len(fields) && intervalTime.MatchString(fields[i+1]) {
+			iv.addFraction(n, 0, 1, 0)
+			continue
+		}
+		if i+1 == len(fields) {
+			iv.addFraction(n, 0, 0, microsPerSecond)
+			continue
+		}
+		unit, ok := intervalUnits[fields[i+1]]
+		if !ok {
+			return iv, fmt.Errorf("can't convert %q to interval: unknown unit %q", s, fields[i+1])
+		}
+		iv.addFraction(n, unit.months, unit.days, unit.micros)
+		i++
+	}
+	if ago {
+		iv = interval{months: -iv.months, days: -iv.days, micros: -iv.micros}
+	}
+	return iv, nil
+}
+
+// parseIntervalTime returns the microseconds of the time of day part m of
+// an interval matched by intervalTime.
+func parseIntervalTime(m []string) (int64, error) {
+	h, err := strconv.ParseInt(m[2], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	mins, _ := strconv.ParseInt(m[3], 10, 64)
+	var secs float64
+	if m[4] != "" {
+		secs, _ = strconv.ParseFloat(m[4], 64)
+	}
+	micros := (h*3600+mins*60)*microsPerSecond + int64(math.Round(secs*float64(microsPerSecond)))
+	if m[1] == "-" {
+		micros = -micros
+	}
+	return micros, nil
+}
+
+// addFraction adds n units of the given length to iv. Like PostgreSQL,
+// fractions of months are converted to days (of 30 days per month) and
+// fractions of days to microseconds.
+func (iv *interval) addFraction(n float64, months, days, micros int64) {
+	if months != 0 {
+		whole, frac := math.Modf(n * float64(months))
+		iv.months += int64(whole)
+		n, days = frac, 30
+	}
+	if days != 0 {
+		whole, frac := math.Modf(n * float64(days))
+		iv.days += int64(whole)
+		n, micros = frac, microsPerDay
+	}
+	iv.micros += int64(math.Round(n * float64(micros)))
+}
+
+// parseISOInterval parses an ISO 8601 duration, e.g. "P1Y2M3DT4H5M6.5S" or
+// "P-1Y-2M3DT-4H" (the iso_8601 output style of PostgreSQL).
+func parseISOInterval(s string) (interval, error) {
+	var iv interval
+	m := isoInterval.FindStringSubmatch(s)
+	if m == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return iv, fmt.Errorf("can't convert %q to interval", s)
+	}
+	lengths := []struct{ months, days, micros int64 }{
+		{months: 12}, {months: 1}, {days: 7}, {days: 1},
+		{micros: 3600 * microsPerSecond}, {micros: 60 * microsPerSecond}, {micros: microsPerSecond},
+	}
+	for i, l := range lengths {
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(m[i+1], 64)
+		if err != nil {
+			return iv, fmt.Errorf("can't convert %q to interval: %w", s, err)
+		}
+		iv.addFraction(n, l.months, l.days, l.micros)
+	}
+	return iv, nil
+}
+
+// iso8601 formats iv as an ISO 8601 duration, in the same form as the
+// iso_8601 output style of PostgreSQL, e.g. "P1Y2M3DT4H5M6.789S".
+func (iv interval) iso8601() string {
+	if iv == (interval{}) {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteString("P")
+	if y := iv.months / 12; y != 0 {
+		fmt.Fprintf(&b, "%dY", y)
+	}
+	if m := iv.months % 12; m != 0 {
+		fmt.Fprintf(&b, "%dM", m)
+	}
+	if iv.days != 0 {
+		fmt.Fprintf(&b, "%dD", iv.days)
+	}
+	if iv.micros != 0 {
+		b.WriteString("T")
+		h := iv.micros / (3600 * microsPerSecond)
+		mins := iv.micros % (3600 * microsPerSecond) / (60 * microsPerSecond)
+		micros := iv.micros % (60 * microsPerSecond)
+		if h != 0 {
+			fmt.Fprintf(&b, "%dH", h)
+		}
+		if mins != 0 {
+			fmt.Fprintf(&b, "%dM", mins)
+		}
+		if micros != 0 {
+			secs := strconv.FormatFloat(float64(micros)/float64(microsPerSecond), 'f', -1, 64)
+			fmt.Fprintf(&b, "%sS", secs)
+		}
+	}
+	return b.String()
+}
+
+// totalMicros returns the length of iv in microseconds, counting months as
+// 30 days and years as 365.25 days like EXTRACT(EPOCH FROM interval).
+func (iv interval) totalMicros() int64 {
+	years := iv.months / 12
+	months := iv.months % 12
+	return years*31557600*microsPerSecond + (months*30+iv.days)*microsPerDay + iv.micros
+}
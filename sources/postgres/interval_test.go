@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		in         string
+		wantISO    string
+		wantMicros int64
+		wantErr    bool
+	}{
+		{in: "1 year 2 mons 3 days 04:05:06.789", wantISO: "P1Y2M3DT4H5M6.789S", wantMicros: 31557600*microsPerSecond + 63*microsPerDay + 14706789000},
+		{in: "-1 days +02:03:00", wantISO: "P-1DT2H3M", wantMicros: -microsPerDay + 7380*microsPerSecond},
+		{in: "@ 1 year 2 mons ago", wantISO: "P-1Y-2M", wantMicros: -31557600*microsPerSecond - 60*microsPerDay},
+		{in: "+1-2 +3 +4:05:06", wantISO: "P1Y2M3DT4H5M6S", wantMicros: 31557600*microsPerSecond + 63*microsPerDay + 14706*microsPerSecond},
+		{in: "P1Y2M3DT4H5M6.5S", wantISO: "P1Y2M3DT4H5M6.5S", wantMicros: 31557600*microsPerSecond + 63*microsPerDay + 14706500000},
+		{in: "1.5 days", wantISO: "P1DT12H", wantMicros: 36 * 3600 * microsPerSecond},
+		{in: "1 day 2 hours -30 minutes", wantISO: "P1DT1H30M", wantMicros: microsPerDay + 5400*microsPerSecond},
+		{in: "10", wantISO: "PT10S", wantMicros: 10 * microsPerSecond},
+		{in: "00:00:00", wantISO: "PT0S"},
+		{in: "", wantErr: true},
+		{in: "1 fortnight", wantErr: true},
+		{in: "P", wantErr: true},
+		{in: "P1DT", wantErr: true},
+	}
+	for _, tc := range tests {
+		iv, err := parseInterval(tc.in)
+		assert.Equal(t, tc.wantErr, err != nil, tc.in)
+		if err == nil {
+			assert.Equal(t, tc.wantISO, iv.iso8601(), tc.in)
+			assert.Equal(t, tc.wantMicros, iv.totalMicros(), tc.in)
+		}
+	}
+}
@@ -41,6 +41,7 @@ type copyOrInsert struct {
 	table string
 	cols  []string
 	rows  [][]string // Empty for COPY-FROM.
+	skip  bool       // The table is excluded by the table filter.
 }
 
 type stmtType int
@@ -52,7 +53,7 @@ const (
 
 // GetToDdl functions below implement the common.DbDump interface
 func (ddi DbDumpImpl) GetToDdl() common.ToDdl {
-	return ToDdlImpl{}
+	return common.LookupToDdl(constants.POSTGRES, ToDdlImpl{})
 }
 
 // ProcessDump calls processPgDump to read a Postgres dump file
@@ -66,6 +67,7 @@ func (ddi DbDumpImpl) ProcessDump(conv *internal.Conv, r *internal.Reader) error
 // In data mode, ProcessPgDump uses this schema to convert PostgreSQL data
 // and writes it to Spanner, using the data sink specified in conv.
 func processPgDump(conv *internal.Conv, r *internal.Reader) error {
+	types := newUserTypes()
 	for {
 		startLine := r.LineNumber
 		startOffset := r.Offset
@@ -73,12 +75,16 @@ func processPgDump(conv *internal.Conv, r *internal.Reader) error {
 		if err != nil {
 			return err
 		}
-		ci := processStatements(conv, stmts)
+		ci := processStatements(conv, stmts, types)
 		internal.VerbosePrintf("Parsed SQL command at line=%d/fpos=%d: %d stmts (%d lines, %d bytes) ci=%v\n", startLine, startOffset, len(stmts), r.LineNumber-startLine, len(b), ci != nil)
 		logger.Log.Debug(fmt.Sprintf("Parsed SQL command at line=%d/fpos=%d: %d stmts (%d lines, %d bytes) ci=%v\n", startLine, startOffset, len(stmts), r.LineNumber-startLine, len(b), ci != nil))
 		if ci != nil {
 			switch ci.stmt {
 			case copyFrom:
+				if ci.skip {
+					skipCopyBlock(r)
+					break
+				}
 				commonColIds, err := common.PrepareColumns(conv, ci.table, ci.cols)
 				if err != nil && !conv.SchemaMode() {
 					return err
@@ -109,14 +115,15 @@ func processPgDump(conv *internal.Conv, r *internal.Reader) error {
 						srcTableName := conv.SrcSchema[ci.table].Name
 						conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
 						conv.StatsAddBadRow(srcTableName, conv.DataMode())
-						conv.CollectBadRow(srcTableName, colNames, vals)
+						conv.CollectBadRowWithReason(srcTableName, colNames, vals, err.Error())
 						continue
 					}
 					ProcessDataRow(conv, ci.table, commonColIds, newVals)
 				}
 			}
 		}
-		if r.EOF {
+		// An interrupted data migration stops after the current statement.
+		if r.EOF || conv.Interrupted() {
 			break
 		}
 	}
@@ -163,6 +170,16 @@ func readAndParseChunk(conv *internal.Conv, r *internal.Reader) ([]byte, []*pg_q
 	}
 }
 
+// skipCopyBlock reads past the data of a COPY-FROM statement.
+func skipCopyBlock(r *internal.Reader) {
+	for {
+		b := r.ReadLine()
+		if string(b) == "\\.\n" || string(b) == "\\.\r\n" || r.EOF {
+			return
+		}
+	}
+}
+
 func processCopyBlock(conv *internal.Conv, tableId string, commonColIds, srcCols []string, r *internal.Reader) {
 	srcTableName := conv.SrcSchema[tableId].Name
 	internal.VerbosePrintf("Parsing COPY-FROM stdin block starting at line=%d/fpos=%d\n", r.LineNumber, r.Offset)
@@ -200,7 +217,7 @@ func processCopyBlock(conv *internal.Conv, tableId string, commonColIds, srcCols
 		if err != nil {
 			conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
 			conv.StatsAddBadRow(srcTableName, conv.DataMode())
-			conv.CollectBadRow(srcTableName, srcCols, values)
+			conv.CollectBadRowWithReason(srcTableName, srcCols, values, err.Error())
 			continue
 		}
 		ProcessDataRow(conv, tableId, commonColIds, newValues)
@@ -212,13 +229,13 @@ func processCopyBlock(conv *internal.Conv, tableId string, commonColIds, srcCols
 // copyOrInsert if a COPY-FROM or INSERT statement is encountered.
 // Note that the actual parsing/processing of COPY-FROM data blocks is
 // handled elsewhere (see process.go).
-func processStatements(conv *internal.Conv, rawStmts []*pg_query.RawStmt) *copyOrInsert {
+func processStatements(conv *internal.Conv, rawStmts []*pg_query.RawStmt, types *userTypes) *copyOrInsert {
 	// Typically we'll have only one statement, but we handle the general case.
 	for i, rawStmt := range rawStmts {
 		node := rawStmt.Stmt
 		switch n := node.GetNode().(type) {
 		case *pg_query.Node_AlterTableStmt:
-			if conv.SchemaMode() {
+			if conv.SchemaMode() && !skipTable(conv, n.AlterTableStmt.Relation, printNodeType(n.AlterTableStmt)) {
 				processAlterTableStmt(conv, n.AlterTableStmt)
 			}
 		case *pg_query.Node_CopyStmt:
@@ -228,17 +245,28 @@ func processStatements(conv *internal.Conv, rawStmts []*pg_query.RawStmt) *copyO
 			}
 			return processCopyStmt(conv, n.CopyStmt)
 		case *pg_query.Node_CreateStmt:
+			if conv.SchemaMode() && !skipTable(conv, n.CreateStmt.Relation, printNodeType(n.CreateStmt)) {
+				processCreateStmt(conv, n.CreateStmt, types)
+			}
+		case *pg_query.Node_CreateDomainStmt:
 			if conv.SchemaMode() {
-				processCreateStmt(conv, n.CreateStmt)
+				processCreateDomainStmt(conv, n.CreateDomainStmt, types)
+			}
+		case *pg_query.Node_CompositeTypeStmt:
+			if conv.SchemaMode() {
+				processCompositeTypeStmt(conv, n.CompositeTypeStmt, types)
 			}
 		case *pg_query.Node_InsertStmt:
+			if skipTable(conv, n.InsertStmt.Relation, printNodeType(n.InsertStmt)) {
+				return nil
+			}
 			return processInsertStmt(conv, n.InsertStmt)
 		case *pg_query.Node_VariableSetStmt:
 			if conv.SchemaMode() {
 				processVariableSetStmt(conv, n.VariableSetStmt)
 			}
 		case *pg_query.Node_IndexStmt:
-			if conv.SchemaMode() {
+			if conv.SchemaMode() && !skipTable(conv, n.IndexStmt.Relation, printNodeType(n.IndexStmt)) {
 				processIndexStmt(conv, n.IndexStmt)
 			}
 		case *pg_query.Node_CreateSeqStmt:
@@ -249,6 +277,10 @@ func processStatements(conv *internal.Conv, rawStmts []*pg_query.RawStmt) *copyO
 			if conv.SchemaMode() {
 				processAlterSeqStmt(conv, n.AlterSeqStmt)
 			}
+		case *pg_query.Node_CommentStmt:
+			if conv.SchemaMode() {
+				processCommentStmt(conv, n.CommentStmt)
+			}
 		default:
 			conv.SkipStatement(printNodeType(n))
 		}
@@ -256,6 +288,79 @@ func processStatements(conv *internal.Conv, rawStmts []*pg_query.RawStmt) *copyO
 	return nil
 }
 
+// skipTable returns true if the table of relation is excluded by the table
+// filter, counting the statement as skipped.
+func skipTable(conv *internal.Conv, relation *pg_query.RangeVar, stmtType string) bool {
+	if relation == nil {
+		return false
+	}
+	table, err := getTableName(conv, relation)
+	if err != nil || !conv.SkipTable(table) {
+		return false
+	}
+	conv.SkipStatement(stmtType)
+	return true
+}
+
+// processCommentStmt handles COMMENT ON TABLE and COMMENT ON COLUMN
+// statements by recording the comment on the corresponding source table
+// or column. Comments on other object types are skipped.
+func processCommentStmt(conv *internal.Conv, n *pg_query.CommentStmt) {
+	if n.Objtype != pg_query.ObjectType_OBJECT_TABLE && n.Objtype != pg_query.ObjectType_OBJECT_COLUMN {
+		conv.SkipStatement(printNodeType(n))
+		return
+	}
+	var ids []string
+	for _, item := range n.Object.GetList().GetItems() {
+		s, err := getString(item)
+		if err != nil {
+			logStmtError(conv, n, fmt.Errorf("can't get object name: %w", err))
+			return
+		}
+		ids = append(ids, s)
+	}
+	var colName string
+	if n.Objtype == pg_query.ObjectType_OBJECT_COLUMN {
+		if len(ids) < 2 {
+			logStmtError(conv, n, fmt.Errorf("can't get column name from %v", ids))
+			return
+		}
+		colName = ids[len(ids)-1]
+		ids = ids[:len(ids)-1]
+	}
+	// Build the table name the same way getTableName does: drop "public".
+	if len(ids) > 1 && ids[len(ids)-2] == "public" {
+		ids = append(ids[:len(ids)-2], ids[len(ids)-1])
+	}
+	if len(ids) == 0 {
+		logStmtError(conv, n, fmt.Errorf("relname is empty: can't build table name"))
+		return
+	}
+	tableName := strings.Join(ids, ".")
+	if conv.SkipTable(tableName) {
+		conv.SkipStatement(printNodeType(n))
+		return
+	}
+	tbl, ok := internal.GetSrcTableByName(conv.SrcSchema, tableName)
+	if !ok {
+		conv.Unexpected(fmt.Sprintf("Table %s not found while processing COMMENT statement", tableName))
+		return
+	}
+	if colName == "" {
+		tbl.Comment = n.Comment
+		conv.SrcSchema[tbl.Id] = *tbl
+		return
+	}
+	colId, ok := tbl.ColNameIdMap[colName]
+	if !ok {
+		conv.Unexpected(fmt.Sprintf("Column %s not found in table %s while processing COMMENT statement", colName, tableName))
+		return
+	}
+	col := tbl.ColDefs[colId]
+	col.Comment = n.Comment
+	tbl.ColDefs[colId] = col
+}
+
 func processIndexStmt(conv *internal.Conv, n *pg_query.IndexStmt) {
 	if n.Relation == nil {
 		logStmtError(conv, n, fmt.Errorf("cannot process index statement with nil relation"))
@@ -273,6 +378,8 @@ func processIndexStmt(conv *internal.Conv, n *pg_query.IndexStmt) {
 			Name:   n.Idxname,
 			Unique: n.Unique,
 			Keys:   toIndexKeys(conv, n.Idxname, n.IndexParams, ctable.ColNameIdMap),
+			// INCLUDE columns of a covering index map to the STORING clause in Spanner.
+			StoredColumnIds: toStoredColumnIds(conv, n.Idxname, n.IndexIncludingParams, ctable.ColNameIdMap),
 		})
 		conv.SrcSchema[tbl.Id] = ctable
 	} else {
@@ -339,7 +446,7 @@ func processAlterTableStmt(conv *internal.Conv, n *pg_query.AlterTableStmt) {
 	}
 }
 
-func processCreateStmt(conv *internal.Conv, n *pg_query.CreateStmt) {
+func processCreateStmt(conv *internal.Conv, n *pg_query.CreateStmt, types *userTypes) {
 	colDef := make(map[string]schema.Column)
 	if n.Relation == nil {
 		logStmtError(conv, n, fmt.Errorf("relation is nil"))
@@ -360,6 +467,7 @@ func processCreateStmt(conv *internal.Conv, n *pg_query.CreateStmt) {
 		return
 	}
 	var constraints []constraint
+	var checkConstraints []schema.CheckConstraint
 	var colIds []string
 	colNameIdMap := make(map[string]string)
 	for _, te := range n.TableElts {
@@ -370,6 +478,7 @@ func processCreateStmt(conv *internal.Conv, n *pg_query.CreateStmt) {
 				logStmtError(conv, n, err)
 				return
 			}
+			checkConstraints = append(checkConstraints, types.expand(&col)...)
 			col.Id = internal.GenerateColumnId()
 			colDef[col.Id] = col
 			colIds = append(colIds, col.Id)
@@ -387,11 +496,12 @@ func processCreateStmt(conv *internal.Conv, n *pg_query.CreateStmt) {
 	conv.SchemaStatement(printNodeType(n))
 	tableId := internal.GenerateTableId()
 	conv.SrcSchema[tableId] = schema.Table{
-		Id:           tableId,
-		Name:         table,
-		ColIds:       colIds,
-		ColNameIdMap: colNameIdMap,
-		ColDefs:      colDef,
+		Id:               tableId,
+		Name:             table,
+		ColIds:           colIds,
+		ColNameIdMap:     colNameIdMap,
+		ColDefs:          colDef,
+		CheckConstraints: checkConstraints,
 	}
 	// Note: constraints contains all info about primary keys, not-null keys
 	// and foreign keys.
@@ -413,7 +523,15 @@ func processColumn(conv *internal.Conv, n *pg_query.ColumnDef, table string) (st
 		Mods:        mods,
 		ArrayBounds: getArrayBounds(conv, n.TypeName.ArrayBounds)}
 	autoGen := getAutoGenFromTypeName(tid)
-	return name, schema.Column{Name: name, Type: ty, AutoGen: autoGen}, analyzeColDefConstraints(conv, printNodeType(n), table, n.Constraints, name), nil
+	var collation schema.Collation
+	if n.CollClause != nil {
+		collName, err := getTypeID(n.CollClause.Collname)
+		if err != nil {
+			return "", schema.Column{}, nil, fmt.Errorf("can't get collation for %s: %w", name, err)
+		}
+		collation = toCollation(collName)
+	}
+	return name, schema.Column{Name: name, Type: ty, AutoGen: autoGen, Collation: collation}, analyzeColDefConstraints(conv, printNodeType(n), table, n.Constraints, name), nil
 }
 
 func getAutoGenFromTypeName(typeName string) ddl.AutoGenCol {
@@ -485,6 +603,10 @@ func processCopyStmt(conv *internal.Conv, n *pg_query.CopyStmt) *copyOrInsert {
 	} else {
 		logStmtError(conv, n, fmt.Errorf("relation is nil"))
 	}
+	if conv.SkipTable(table) {
+		conv.SkipStatement(printNodeType(n))
+		return &copyOrInsert{stmt: copyFrom, table: table, skip: true}
+	}
 	if !conv.SchemaMode() {
 		table, _ = internal.GetTableIdFromSrcName(conv.SrcSchema, table)
 	}
@@ -964,12 +1086,40 @@ func toIndexKeys(conv *internal.Conv, idxName string, s []*pg_query.Node, colNam
 			if e.IndexElem.Ordering == pg_query.SortByDir_SORTBY_DESC {
 				desc = true
 			}
-			l = append(l, schema.Key{ColId: colNameIdMap[e.IndexElem.Name], Desc: desc})
+			// PostgreSQL sorts NULLs last in ascending order and first in
+			// descending order by default.
+			nullsFirst := desc
+			switch e.IndexElem.NullsOrdering {
+			case pg_query.SortByNulls_SORTBY_NULLS_FIRST:
+				nullsFirst = true
+			case pg_query.SortByNulls_SORTBY_NULLS_LAST:
+				nullsFirst = false
+			}
+			l = append(l, schema.Key{ColId: colNameIdMap[e.IndexElem.Name], Desc: desc, NullsOrder: toNullsOrder(nullsFirst)})
 		}
 	}
 	return
 }
 
+// toNullsOrder returns the null ordering of an index key. It is recorded also
+// when it is the PostgreSQL default, since other databases sort NULLs
+// differently.
+func toNullsOrder(nullsFirst bool) string {
+	if nullsFirst {
+		return ddl.NullsFirst
+	}
+	return ddl.NullsLast
+}
+
+// toStoredColumnIds converts the INCLUDE columns of a PostgreSQL index to
+// column ids.
+func toStoredColumnIds(conv *internal.Conv, idxName string, s []*pg_query.Node, colNameIdMap map[string]string) (l []string) {
+	for _, k := range toIndexKeys(conv, idxName, s, colNameIdMap) {
+		l = append(l, k.ColId)
+	}
+	return
+}
+
 // toForeignKeys converts a string list of PostgreSQL foreign keys to schema
 // foreign keys.
 func toForeignKeys(fk constraint) (fkey schema.ForeignKey) {
@@ -30,6 +30,7 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/expressions_api"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/mocks"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 	pg_query "github.com/pganalyze/pg_query_go/v6"
@@ -312,10 +313,10 @@ func TestProcessPgDump(t *testing.T) {
 	}{
 		{"text", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}}},
 		{"text NOT NULL", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, NotNull: true}},
-		{"text array[4]", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: false}}},
-		{"text[4]", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: false}}},
-		{"text[]", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: false}}},
-		{"text[][]", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}}}, // Unrecognized array type mapped to string.
+		{"text array[4]", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}}},
+		{"text[4]", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}}},
+		{"text[]", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: true}}},
+		{"text[][]", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.JSON}}}, // Multi-dimensional arrays are mapped to JSON.
 	}
 	for _, tc := range singleColTests {
 		conv, _ := runProcessPgDump(fmt.Sprintf("CREATE TABLE t (a %s);", tc.ty))
@@ -548,6 +549,48 @@ func TestProcessPgDump(t *testing.T) {
 					PrimaryKeys: []ddl.IndexKey{ddl.IndexKey{ColId: "synth_id", Order: 1}},
 					Indexes:     []ddl.CreateIndex{ddl.CreateIndex{Name: "custom_index", TableId: "test", Unique: false, Keys: []ddl.IndexKey{ddl.IndexKey{ColId: "b", Desc: false, Order: 1}, ddl.IndexKey{ColId: "c", Desc: false, Order: 2}}}}}},
 		},
+		{
+			name: "Create index statement with nulls order",
+			input: "CREATE TABLE test (" +
+				"a smallint," +
+				"b text," +
+				"c text" +
+				");\n" +
+				"CREATE INDEX custom_index ON test (b NULLS FIRST, c DESC NULLS FIRST);\n",
+			expectedSchema: map[string]ddl.CreateTable{
+				"test": ddl.CreateTable{
+					Name:   "test",
+					ColIds: []string{"a", "b", "c", "synth_id"},
+					ColDefs: map[string]ddl.ColumnDef{
+						"a":        ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.Int64}},
+						"b":        ddl.ColumnDef{Name: "b", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+						"c":        ddl.ColumnDef{Name: "c", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+						"synth_id": ddl.ColumnDef{Name: "synth_id", T: ddl.Type{Name: ddl.String, Len: 50}},
+					},
+					PrimaryKeys: []ddl.IndexKey{ddl.IndexKey{ColId: "synth_id", Order: 1}},
+					Indexes:     []ddl.CreateIndex{ddl.CreateIndex{Name: "custom_index", TableId: "test", Unique: false, Keys: []ddl.IndexKey{ddl.IndexKey{ColId: "b", Desc: false, Order: 1}, ddl.IndexKey{ColId: "c", Desc: true, Order: 2}}}}}},
+		},
+		{
+			name: "Create index statement with include",
+			input: "CREATE TABLE test (" +
+				"a smallint," +
+				"b text," +
+				"c text" +
+				");\n" +
+				"CREATE INDEX custom_index ON test (b) INCLUDE (a, c);\n",
+			expectedSchema: map[string]ddl.CreateTable{
+				"test": ddl.CreateTable{
+					Name:   "test",
+					ColIds: []string{"a", "b", "c", "synth_id"},
+					ColDefs: map[string]ddl.ColumnDef{
+						"a":        ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.Int64}},
+						"b":        ddl.ColumnDef{Name: "b", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+						"c":        ddl.ColumnDef{Name: "c", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+						"synth_id": ddl.ColumnDef{Name: "synth_id", T: ddl.Type{Name: ddl.String, Len: 50}},
+					},
+					PrimaryKeys: []ddl.IndexKey{ddl.IndexKey{ColId: "synth_id", Order: 1}},
+					Indexes:     []ddl.CreateIndex{ddl.CreateIndex{Name: "custom_index", TableId: "test", Unique: false, Keys: []ddl.IndexKey{ddl.IndexKey{ColId: "b", Desc: false, Order: 1}}, StoredColumnIds: []string{"a", "c"}}}}},
+		},
 		{
 			name: "Create index statement with order",
 			input: "CREATE TABLE test (" +
@@ -958,7 +1001,7 @@ COPY test (id, a, b, c, d, e, f, g) FROM stdin;
 					table: "test", cols: []string{"int8", "float8", "bool", "timestamp", "date", "bytea", "arr", "float4", "synth_id"},
 					vals: []interface{}{int64(7), float64(42.1), true, getTime(t, "2019-10-29T05:30:00Z"),
 						getDate("2019-10-29"), []byte{0x0, 0x1, 0xbe, 0xef},
-						"{42,6}", float32(3.14),
+						[]spanner.NullInt64{{Int64: 42, Valid: true}, {Int64: 6, Valid: true}}, float32(3.14),
 						fmt.Sprintf("%d", bitReverse(0))}},
 				spannerData{table: "test", cols: []string{"int8", "synth_id"}, vals: []interface{}{int64(7), fmt.Sprintf("%d", bitReverse(1))}},
 				spannerData{table: "test", cols: []string{"float8", "synth_id"}, vals: []interface{}{float64(42.1), fmt.Sprintf("%d", bitReverse(2))}},
@@ -967,9 +1010,9 @@ COPY test (id, a, b, c, d, e, f, g) FROM stdin;
 				spannerData{table: "test", cols: []string{"date", "synth_id"}, vals: []interface{}{getDate("2019-10-29"), fmt.Sprintf("%d", bitReverse(5))}},
 				spannerData{table: "test", cols: []string{"bytea", "synth_id"}, vals: []interface{}{[]byte{0x0, 0x1, 0xbe, 0xef}, fmt.Sprintf("%d", bitReverse(6))}},
 				spannerData{table: "test", cols: []string{"arr", "synth_id"},
-					vals: []interface{}{"{42,6}", fmt.Sprintf("%d", bitReverse(7))}},
+					vals: []interface{}{[]spanner.NullInt64{{Int64: 42, Valid: true}, {Int64: 6, Valid: true}}, fmt.Sprintf("%d", bitReverse(7))}},
 				spannerData{table: "test", cols: []string{"arr", "synth_id"},
-					vals: []interface{}{"{42, 6}", fmt.Sprintf("%d", bitReverse(8))}},
+					vals: []interface{}{[]spanner.NullInt64{{Int64: 42, Valid: true}, {Int64: 6, Valid: true}}, fmt.Sprintf("%d", bitReverse(8))}},
 				spannerData{table: "test", cols: []string{"float4", "synth_id"}, vals: []interface{}{float32(3.14), fmt.Sprintf("%d", bitReverse(9))}},
 			},
 		},
@@ -1022,7 +1065,7 @@ func TestProcessPgDumpPGTarget(t *testing.T) {
 		columnId, _ := internal.GetColIdFromSrcName(conv.SrcSchema[tableId].ColDefs, "a")
 		assert.Equal(t, conv.SpSchema[tableId].ColDefs[columnId].T, tc.expected, "Scalar type: "+tc.ty)
 	}
-	// Next test array types and not null. For PG Spanner, all array types mapped to string.
+	// Next test array types and not null. For PG Spanner, one-dimensional array types are mapped to string.
 	singleColTests := []struct {
 		ty       string
 		expected ddl.ColumnDef
@@ -1032,7 +1075,7 @@ func TestProcessPgDumpPGTarget(t *testing.T) {
 		{"text array[4]", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}}},
 		{"text[4]", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}}},
 		{"text[]", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}}},
-		{"text[][]", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}}},
+		{"text[][]", ddl.ColumnDef{Name: "a", T: ddl.Type{Name: ddl.JSON}}},
 	}
 	for _, tc := range singleColTests {
 		conv, _ := runProcessPgDumpPGTarget(fmt.Sprintf("CREATE TABLE t (a %s);", tc.ty))
@@ -1688,6 +1731,20 @@ func TestProcessPgDump_GetPGDDL(t *testing.T) {
 	assert.Equal(t, expected, strings.Join(ddl.GetDDL(c, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions), " "))
 }
 
+func TestProcessPgDump_Comments(t *testing.T) {
+	conv, _ := runProcessPgDump("CREATE TABLE cart (productid text PRIMARY KEY, quantity bigint);\n" +
+		"COMMENT ON TABLE public.cart IS 'shopping cart';\n" +
+		"COMMENT ON COLUMN public.cart.productid IS 'product id';\n")
+	srcTable, ok := internal.GetSrcTableByName(conv.SrcSchema, "cart")
+	assert.True(t, ok)
+	assert.Equal(t, "shopping cart", srcTable.Comment)
+	assert.Equal(t, "product id", srcTable.ColDefs[srcTable.ColNameIdMap["productid"]].Comment)
+	assert.Equal(t, "", srcTable.ColDefs[srcTable.ColNameIdMap["quantity"]].Comment)
+	spTable := conv.SpSchema[srcTable.Id]
+	assert.Equal(t, "Spanner schema for source table cart (shopping cart)", spTable.Comment)
+	assert.Equal(t, "From: productid text (product id)", spTable.ColDefs[srcTable.ColNameIdMap["productid"]].Comment)
+}
+
 func TestProcessPgDump_Rows(t *testing.T) {
 	conv, _ := runProcessPgDump("CREATE TABLE cart (a text, n bigint);\n" +
 		"INSERT INTO cart (a, n) VALUES ('a42', 2);")
@@ -1752,6 +1809,49 @@ func TestProcessPgDump_AddPrimaryKeys(t *testing.T) {
 	}
 }
 
+func TestProcessPgDump_IndexNullsOrder(t *testing.T) {
+	input := "CREATE TABLE test (a text, b text, c text, d text);\n" +
+		"CREATE INDEX custom_index ON test (a, b DESC, c NULLS FIRST, d DESC NULLS LAST);\n"
+	for _, dialect := range []string{constants.DIALECT_GOOGLESQL, constants.DIALECT_POSTGRESQL} {
+		var conv *internal.Conv
+		if dialect == constants.DIALECT_POSTGRESQL {
+			conv, _ = runProcessPgDumpPGTarget(input)
+		} else {
+			conv, _ = runProcessPgDump(input)
+		}
+		tableId, err := internal.GetTableIdFromSrcName(conv.SrcSchema, "test")
+		assert.Nil(t, err)
+		colIds := make(map[string]string)
+		for _, name := range []string{"a", "b", "c", "d"} {
+			colIds[name], err = internal.GetColIdFromSrcName(conv.SrcSchema[tableId].ColDefs, name)
+			assert.Nil(t, err)
+		}
+		// The PostgreSQL default null ordering is recorded too.
+		assert.Equal(t, []schema.Key{
+			{ColId: colIds["a"], Order: 1, NullsOrder: ddl.NullsLast},
+			{ColId: colIds["b"], Desc: true, Order: 2, NullsOrder: ddl.NullsFirst},
+			{ColId: colIds["c"], Order: 3, NullsOrder: ddl.NullsFirst},
+			{ColId: colIds["d"], Desc: true, Order: 4, NullsOrder: ddl.NullsLast},
+		}, conv.SrcSchema[tableId].Indexes[0].Keys, dialect)
+		var nullsOrders []string
+		for _, k := range conv.SpSchema[tableId].Indexes[0].Keys {
+			nullsOrders = append(nullsOrders, k.NullsOrder)
+		}
+		issues := conv.SchemaIssues[tableId].ColumnLevelIssues
+		if dialect == constants.DIALECT_POSTGRESQL {
+			assert.Equal(t, []string{"", "", ddl.NullsFirst, ddl.NullsLast}, nullsOrders)
+			assert.False(t, common.IsSchemaIssuePresent(issues[colIds["a"]], internal.IndexNullsOrder))
+		} else {
+			// GoogleSQL sorts NULLs first in ascending order and last in
+			// descending order, unlike the PostgreSQL default.
+			assert.Equal(t, []string{"", "", "", ""}, nullsOrders)
+			for name, want := range map[string]bool{"a": true, "b": true, "c": false, "d": false} {
+				assert.Equal(t, want, common.IsSchemaIssuePresent(issues[colIds[name]], internal.IndexNullsOrder), name)
+			}
+		}
+	}
+}
+
 func TestProcessPgDump_WithUnparsableContent(t *testing.T) {
 	s := "This is unparsable content"
 	conv := internal.MakeConv()
@@ -17,6 +17,7 @@ package postgres
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
@@ -36,13 +37,18 @@ type ToDdlImpl struct {
 func (tdi ToDdlImpl) ToSpannerType(conv *internal.Conv, spType string, srcType schema.Type, isPk bool) (ddl.Type, []internal.SchemaIssue) {
 	ty, issues := toSpannerTypeInternal(srcType, spType)
 	if len(srcType.ArrayBounds) > 1 {
-		ty = ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
-		issues = append(issues, internal.MultiDimensionalArray)
+		// Spanner arrays have a single dimension, so multi-dimensional
+		// arrays are stored as nested JSON arrays to preserve their shape.
+		if spType == ddl.String {
+			ty = ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
+		} else {
+			ty = ddl.Type{Name: ddl.JSON}
+		}
+		issues = []internal.SchemaIssue{internal.MultiDimensionalArray}
 	} else if len(srcType.ArrayBounds) == 1 {
-		// This check has been added because we don't support Array<primitive type> to string conversions
-		// and Array datatype is currently not supported in datastream.
-		ty = ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
-		issues = append(issues, internal.ArrayTypeNotSupported)
+		// Note that the caller flags array columns with ArrayTypeNotSupported
+		// since arrays are not supported by Datastream.
+		ty.IsArray = true
 	}
 	if conv.SpDialect == constants.DIALECT_POSTGRESQL {
 		var pg_issues []internal.SchemaIssue
@@ -75,6 +81,9 @@ func (tdi ToDdlImpl) GetColumnAutoGen(conv *internal.Conv, autoGenCol ddl.AutoGe
 // then it will be used to build the returned ddl.Type. If not, the default
 // Spanner type for this source type will be used.
 func toSpannerTypeInternal(srcType schema.Type, spType string) (ddl.Type, []internal.SchemaIssue) {
+	if isVectorType(srcType.Name) {
+		return toSpannerVectorType(srcType, spType)
+	}
 	switch srcType.Name {
 	case "bool", "boolean":
 		switch spType {
@@ -181,6 +190,39 @@ func toSpannerTypeInternal(srcType schema.Type, spType string) (ddl.Type, []inte
 		default:
 			return ddl.Type{Name: ddl.Timestamp}, []internal.SchemaIssue{internal.Timestamp}
 		}
+	case "time", "time without time zone", "timetz", "time with time zone":
+		// Spanner has no time of day type, times are stored as strings
+		// which preserve their fractional seconds.
+		return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.Time}
+	case "interval":
+		switch spType {
+		case ddl.Int64:
+			return ddl.Type{Name: ddl.Int64}, []internal.SchemaIssue{internal.IntervalAsMicroseconds}
+		default:
+			return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.Interval}
+		}
+	case "bit", "varbit", "bit varying":
+		switch {
+		case spType == ddl.String:
+			// Bits as text, e.g. "0101", which preserves the length of bit
+			// varying values.
+			return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, nil
+		case spType == ddl.Int64 && (len(srcType.Mods) == 0 || srcType.Mods[0] <= 64):
+			// Bits are read as an unsigned number, e.g. "101" is 5. Values
+			// which don't fit in an INT64 are reported as bad rows.
+			return ddl.Type{Name: ddl.Int64}, nil
+		case srcType.Name == "bit" && len(srcType.Mods) > 0 && srcType.Mods[0] == 1:
+			return ddl.Type{Name: ddl.Bool}, nil
+		default:
+			return ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}, nil
+		}
+	case "record": // Composite types, see schema.UserType.
+		switch spType {
+		case ddl.String:
+			return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.CompositeType}
+		default:
+			return ddl.Type{Name: ddl.JSON}, []internal.SchemaIssue{internal.CompositeType}
+		}
 	case "json", "jsonb":
 		switch spType {
 		case ddl.String:
@@ -204,3 +246,27 @@ func toSpannerTypeInternal(srcType schema.Type, spType string) (ddl.Type, []inte
 	}
 	return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.NoGoodType}
 }
+
+// isVectorType returns whether the source type is the vector type of the
+// pgvector extension. pg_dump qualifies it with the schema of the extension.
+func isVectorType(srcTypeName string) bool {
+	return srcTypeName == "vector" || strings.HasSuffix(srcTypeName, ".vector")
+}
+
+// toSpannerVectorType maps pgvector columns to FLOAT32 arrays, or FLOAT64
+// arrays on request, whose vector length is the dimension of the column so
+// that they can be used by vector indexes.
+func toSpannerVectorType(srcType schema.Type, spType string) (ddl.Type, []internal.SchemaIssue) {
+	var vectorLength int64
+	if len(srcType.Mods) > 0 {
+		vectorLength = srcType.Mods[0]
+	}
+	switch spType {
+	case ddl.String:
+		return ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, []internal.SchemaIssue{internal.VectorColumn}
+	case ddl.Float64:
+		return ddl.Type{Name: ddl.Float64, IsArray: true, VectorLength: vectorLength}, []internal.SchemaIssue{internal.Widened, internal.VectorColumn}
+	default:
+		return ddl.Type{Name: ddl.Float32, IsArray: true, VectorLength: vectorLength}, []internal.SchemaIssue{internal.VectorColumn}
+	}
+}
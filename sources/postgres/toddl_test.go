@@ -123,6 +123,48 @@ func TestToSpannerTypeInternal(t *testing.T) {
 	if errCheck != nil {
 		t.Errorf("Error in varchar to bytes conversion")
 	}
+	for _, name := range []string{"time", "time without time zone", "timetz", "time with time zone"} {
+		ty, issues := toSpannerTypeInternal(schema.Type{Name: name, Mods: []int64{6}}, "")
+		assert.Equal(t, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, ty, name)
+		assert.Equal(t, []internal.SchemaIssue{internal.Time}, issues, name)
+	}
+	ty, issues := toSpannerTypeInternal(schema.Type{Name: "interval"}, "")
+	assert.Equal(t, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}, ty)
+	assert.Equal(t, []internal.SchemaIssue{internal.Interval}, issues)
+	ty, issues = toSpannerTypeInternal(schema.Type{Name: "interval"}, ddl.Int64)
+	assert.Equal(t, ddl.Type{Name: ddl.Int64}, ty)
+	assert.Equal(t, []internal.SchemaIssue{internal.IntervalAsMicroseconds}, issues)
+	bitTests := []struct {
+		srcType schema.Type
+		spType  string
+		want    ddl.Type
+	}{
+		{schema.Type{Name: "bit", Mods: []int64{1}}, "", ddl.Type{Name: ddl.Bool}},
+		{schema.Type{Name: "bit", Mods: []int64{8}}, "", ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}},
+		{schema.Type{Name: "bit", Mods: []int64{8}}, ddl.Int64, ddl.Type{Name: ddl.Int64}},
+		{schema.Type{Name: "varbit", Mods: []int64{100}}, ddl.Int64, ddl.Type{Name: ddl.Bytes, Len: ddl.MaxLength}},
+		{schema.Type{Name: "bit varying"}, ddl.String, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+	}
+	for _, tc := range bitTests {
+		ty, issues := toSpannerTypeInternal(tc.srcType, tc.spType)
+		assert.Equal(t, tc.want, ty, tc.srcType.Name)
+		assert.Nil(t, issues, tc.srcType.Name)
+	}
+	vectorTests := []struct {
+		srcType schema.Type
+		spType  string
+		want    ddl.Type
+	}{
+		{schema.Type{Name: "vector", Mods: []int64{3}}, "", ddl.Type{Name: ddl.Float32, IsArray: true, VectorLength: 3}},
+		{schema.Type{Name: "public.vector"}, "", ddl.Type{Name: ddl.Float32, IsArray: true}},
+		{schema.Type{Name: "vector", Mods: []int64{3}}, ddl.Float64, ddl.Type{Name: ddl.Float64, IsArray: true, VectorLength: 3}},
+		{schema.Type{Name: "vector", Mods: []int64{3}}, ddl.String, ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+	}
+	for _, tc := range vectorTests {
+		ty, issues := toSpannerTypeInternal(tc.srcType, tc.spType)
+		assert.Equal(t, tc.want, ty, tc.srcType.Name)
+		assert.Contains(t, issues, internal.VectorColumn, tc.srcType.Name)
+	}
 }
 
 // This is just a very basic smoke-test for toSpannerType.
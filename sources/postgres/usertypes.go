@@ -0,0 +1,249 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	pg_query "github.com/pganalyze/pg_query_go/v6"
+)
+
+// domainValue matches the VALUE keyword standing for the value of a domain
+// in its check constraints.
+var domainValue = regexp.MustCompile(`(?i)\bvalue\b`)
+
+// domain is a PostgreSQL domain i.e. a base type with constraints.
+type domain struct {
+	ty      schema.Type
+	notNull bool
+	checks  []domainCheck
+}
+
+type domainCheck struct {
+	name string
+	expr string // Check expression using VALUE for the value of the domain e.g. "(VALUE > 0)".
+}
+
+// userTypes keeps track of the domains and composite types created by a
+// pg_dump file, so that columns using them can be expanded when their
+// table is created.
+type userTypes struct {
+	domains    map[string]domain
+	composites map[string][]string // Maps composite type names to their field names.
+}
+
+func newUserTypes() *userTypes {
+	return &userTypes{domains: make(map[string]domain), composites: make(map[string][]string)}
+}
+
+// userTypeKey returns the key of a user-defined type named name. Types in
+// the public schema may be referred to with or without the schema name.
+func userTypeKey(name string) string {
+	return strings.TrimPrefix(name, "public.")
+}
+
+// processCreateDomainStmt records the base type and constraints of a domain.
+func processCreateDomainStmt(conv *internal.Conv, n *pg_query.CreateDomainStmt, types *userTypes) {
+	name, err := getTypeID(n.Domainname)
+	if err != nil {
+		logStmtError(conv, n, fmt.Errorf("can't get domain name: %w", err))
+		return
+	}
+	if n.TypeName == nil {
+		logStmtError(conv, n, fmt.Errorf("domain %s has no base type", name))
+		return
+	}
+	base, err := getTypeID(n.TypeName.Names)
+	if err != nil {
+		logStmtError(conv, n, fmt.Errorf("can't get base type of domain %s: %w", name, err))
+		return
+	}
+	d := domain{ty: schema.Type{
+		Name:        base,
+		Mods:        getTypeMods(conv, n.TypeName.Typmods),
+		ArrayBounds: getArrayBounds(conv, n.TypeName.ArrayBounds)}}
+	// Domains can be based on other domains, which we expand as well.
+	if b, ok := types.domains[userTypeKey(base)]; ok {
+		d.ty = schema.Type{Name: b.ty.Name, Mods: b.ty.Mods, ArrayBounds: concatBounds(b.ty.ArrayBounds, d.ty.ArrayBounds)}
+		d.notNull = b.notNull
+		d.checks = append(d.checks, b.checks...)
+	}
+	for _, c := range n.Constraints {
+		con := c.GetConstraint()
+		if con == nil {
+			continue
+		}
+		switch con.Contype {
+		case pg_query.ConstrType_CONSTR_NOTNULL:
+			d.notNull = true
+		case pg_query.ConstrType_CONSTR_CHECK:
+			expr, err := deparseExpr(con.RawExpr)
+			if err != nil {
+				conv.Unexpected(fmt.Sprintf("Can't process check constraint of domain %s: %s", name, err))
+				continue
+			}
+			d.checks = append(d.checks, domainCheck{name: con.Conname, expr: "(" + expr + ")"})
+		}
+	}
+	types.domains[userTypeKey(name)] = d
+	conv.SchemaStatement(printNodeType(n))
+}
+
+// processCompositeTypeStmt records the field names of a composite type.
+func processCompositeTypeStmt(conv *internal.Conv, n *pg_query.CompositeTypeStmt, types *userTypes) {
+	if n.Typevar == nil {
+		logStmtError(conv, n, fmt.Errorf("typevar is nil"))
+		return
+	}
+	name, err := getTableName(conv, n.Typevar)
+	if err != nil {
+		logStmtError(conv, n, fmt.Errorf("can't get composite type name: %w", err))
+		return
+	}
+	var fields []string
+	for _, c := range n.Coldeflist {
+		if cd := c.GetColumnDef(); cd != nil {
+			fields = append(fields, cd.Colname)
+		}
+	}
+	types.composites[userTypeKey(name)] = fields
+	conv.SchemaStatement(printNodeType(n))
+}
+
+// expand expands a column declared with a domain to the base type of the
+// domain, and a column declared with a composite type to the record type.
+// It returns the check constraints of the domain, rewritten for the column.
+func (types *userTypes) expand(col *schema.Column) []schema.CheckConstraint {
+	key := userTypeKey(col.Type.Name)
+	if d, ok := types.domains[key]; ok {
+		col.UserType = schema.UserType{Name: col.Type.Name, Kind: schema.UserTypeDomain}
+		col.Type = schema.Type{Name: d.ty.Name, Mods: d.ty.Mods, ArrayBounds: concatBounds(d.ty.ArrayBounds, col.Type.ArrayBounds)}
+		col.NotNull = col.NotNull || d.notNull
+		var checks []schema.CheckConstraint
+		for _, c := range d.checks {
+			checks = append(checks, domainCheckConstraint(col.Name, c.name, c.expr))
+		}
+		return checks
+	}
+	// Arrays of composite types are not expanded.
+	if fields, ok := types.composites[key]; ok && len(col.Type.ArrayBounds) == 0 {
+		col.UserType = schema.UserType{Name: col.Type.Name, Kind: schema.UserTypeComposite, Fields: fields}
+		col.Type = schema.Type{Name: "record"}
+	}
+	return nil
+}
+
+// concatBounds returns the array bounds of an array of arrays with bounds b
+// of a type with bounds a.
+func concatBounds(a, b []int64) []int64 {
+	if len(a) == 0 {
+		return b
+	}
+	return append(append([]int64{}, a...), b...)
+}
+
+// domainCheckConstraint converts a check constraint of a domain to a check
+// constraint of column col. Since several columns can use the same domain,
+// the constraint is named after the column.
+func domainCheckConstraint(col, name, expr string) schema.CheckConstraint {
+	if name == "" {
+		name = "check"
+	}
+	return schema.CheckConstraint{
+		Name:   fmt.Sprintf("%s_%s", col, name),
+		Expr:   domainValue.ReplaceAllLiteralString(expr, col),
+		ExprId: internal.GenerateExpressionId(),
+		Id:     internal.GenerateCheckConstrainstId(),
+	}
+}
+
+// deparseExpr returns the SQL text of expression n.
+func deparseExpr(n *pg_query.Node) (string, error) {
+	if n == nil {
+		return "", fmt.Errorf("expression is nil")
+	}
+	stmt := &pg_query.SelectStmt{
+		TargetList:  []*pg_query.Node{{Node: &pg_query.Node_ResTarget{ResTarget: &pg_query.ResTarget{Val: n}}}},
+		Op:          pg_query.SetOperation_SETOP_NONE,
+		LimitOption: pg_query.LimitOption_LIMIT_OPTION_DEFAULT,
+	}
+	s, err := pg_query.Deparse(&pg_query.ParseResult{Stmts: []*pg_query.RawStmt{{Stmt: &pg_query.Node{Node: &pg_query.Node_SelectStmt{SelectStmt: stmt}}}}})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(s, "SELECT "), nil
+}
+
+// convCompositeToJSON maps a source database composite value, e.g.
+// `(1 Main St,Springfield,)`, to a JSON object with a property for each
+// field, e.g. `{"city":"Springfield","street":"1 Main St","zip":null}`.
+// Field values are stored as strings, since they are represented as text
+// in composite values.
+func convCompositeToJSON(fields []string, v string) (string, error) {
+	v = strings.TrimSpace(v)
+	if len(v) < 2 || v[0] != '(' || v[len(v)-1] != ')' {
+		return "", fmt.Errorf("unrecognized data format for composite value: expected (v1,v2,...)")
+	}
+	var vals []interface{}
+	s := v[1 : len(v)-1]
+	for i := 0; ; {
+		var b strings.Builder
+		quoted := false
+		for ; i < len(s) && (quoted || s[i] != ','); i++ {
+			switch c := s[i]; {
+			case c == '\\' && i+1 < len(s):
+				i++
+				b.WriteByte(s[i])
+			case c == '"' && quoted && i+1 < len(s) && s[i+1] == '"':
+				i++
+				b.WriteByte('"')
+			case c == '"':
+				quoted = !quoted
+			default:
+				b.WriteByte(c)
+			}
+		}
+		if quoted {
+			return "", fmt.Errorf("unrecognized data format for composite value: unterminated quoted field")
+		}
+		// An unquoted empty field is NULL, while "" is an empty string.
+		if b.Len() == 0 && (i == 0 || s[i-1] != '"') {
+			vals = append(vals, nil)
+		} else {
+			vals = append(vals, b.String())
+		}
+		if i >= len(s) {
+			break
+		}
+		i++ // Skip the comma.
+	}
+	if len(vals) != len(fields) {
+		return "", fmt.Errorf("composite value has %d fields, expected %d", len(vals), len(fields))
+	}
+	m := make(map[string]interface{})
+	for i, f := range fields {
+		m[f] = vals[i]
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("can't convert composite value to JSON: %w", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvCompositeToJSON(t *testing.T) {
+	fields := []string{"street", "city", "zip"}
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"simple", "(1 Main St,Springfield,12345)", `{"city":"Springfield","street":"1 Main St","zip":"12345"}`, false},
+		{"null and empty", `(,"",12345)`, `{"city":"","street":null,"zip":"12345"}`, false},
+		{"quoted", `("1, Main ""St""",Spring\\field,)`, `{"city":"Spring\\field","street":"1, Main \"St\"","zip":null}`, false},
+		{"too few fields", "(1 Main St,Springfield)", "", true},
+		{"unterminated quote", `("1 Main St,Springfield,12345)`, "", true},
+		{"not a composite", "1 Main St", "", true},
+	}
+	for _, tc := range tests {
+		got, err := convCompositeToJSON(fields, tc.in)
+		assert.Equal(t, tc.wantErr, err != nil, tc.name)
+		assert.Equal(t, tc.want, got, tc.name)
+	}
+}
+
+func TestProcessPgDumpUserTypes(t *testing.T) {
+	conv, rows := runProcessPgDump(
+		"CREATE DOMAIN public.amount AS bigint CONSTRAINT amount_check CHECK ((VALUE > 0));\n" +
+			"CREATE DOMAIN public.price AS public.amount NOT NULL;\n" +
+			"CREATE TYPE public.address AS (street text, city text);\n" +
+			"CREATE TABLE public.orders (id bigint PRIMARY KEY, total public.price, shipping public.address);\n" +
+			"COPY public.orders (id, total, shipping) FROM stdin;\n" +
+			"1\t12\t(1 Main St,Springfield)\n" +
+			"\\.\n")
+	noIssues(conv, t, "user types")
+	tableId, err := internal.GetTableIdFromSrcName(conv.SrcSchema, "orders")
+	assert.Nil(t, err)
+	srcTable := conv.SrcSchema[tableId]
+	total := srcTable.ColDefs[srcTable.ColNameIdMap["total"]]
+	assert.Equal(t, schema.Type{Name: "int8"}, total.Type)
+	assert.Equal(t, schema.UserType{Name: "public.price", Kind: schema.UserTypeDomain}, total.UserType)
+	assert.True(t, total.NotNull)
+	shipping := srcTable.ColDefs[srcTable.ColNameIdMap["shipping"]]
+	assert.Equal(t, schema.Type{Name: "record"}, shipping.Type)
+	assert.Equal(t, schema.UserType{Name: "public.address", Kind: schema.UserTypeComposite, Fields: []string{"street", "city"}}, shipping.UserType)
+
+	spTable := conv.SpSchema[tableId]
+	assert.Equal(t, ddl.Type{Name: ddl.Int64}, spTable.ColDefs[total.Id].T)
+	assert.Equal(t, ddl.Type{Name: ddl.JSON}, spTable.ColDefs[shipping.Id].T)
+	assert.Equal(t, 1, len(spTable.CheckConstraints))
+	assert.Equal(t, "(total > 0)", spTable.CheckConstraints[0].Expr)
+	assert.Equal(t, []internal.SchemaIssue{internal.DomainType}, conv.SchemaIssues[tableId].ColumnLevelIssues[total.Id])
+	assert.Equal(t, []internal.SchemaIssue{internal.CompositeType}, conv.SchemaIssues[tableId].ColumnLevelIssues[shipping.Id])
+
+	assert.Equal(t, []spannerData{{
+		table: "orders",
+		cols:  []string{"id", "total", "shipping"},
+		vals:  []interface{}{int64(1), int64(12), `{"city":"Springfield","street":"1 Main St"}`}}}, rows)
+}
@@ -17,7 +17,6 @@ package sqlserver
 import (
 	"fmt"
 	"math/big"
-	"math/bits"
 	"strconv"
 	"strings"
 	"time"
@@ -44,7 +43,7 @@ func ProcessDataRow(conv *internal.Conv, tableId string, colIds []string, srcSch
 	if err != nil {
 		conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
 		conv.StatsAddBadRow(srcTableName, conv.DataMode())
-		conv.CollectBadRow(srcTableName, srcCols, vals)
+		conv.CollectBadRowWithReason(srcTableName, srcCols, vals, err.Error())
 	} else {
 		conv.WriteRow(srcTableName, spTableName, cvtCols, cvtVals)
 	}
@@ -60,6 +59,12 @@ func ConvertData(conv *internal.Conv, tableId string, colIds []string, srcSchema
 	if len(colIds) != len(vals) {
 		return "", []string{}, []interface{}{}, fmt.Errorf("ConvertData: colId and vals don't all have the same lengths: len(colIds)=%d, len(vals)=%d", len(colIds), len(vals))
 	}
+	colIds, vals, transformedCols, transformedVals, err := internal.ApplyColumnTransforms(conv, tableId, colIds, vals, func(s string) (string, bool) {
+		return s, s != "NULL"
+	})
+	if err != nil {
+		return "", []string{}, []interface{}{}, err
+	}
 	for i, colId := range colIds {
 		// Skip columns with 'NULL' values.
 		if vals[i] == "NULL" {
@@ -80,11 +85,11 @@ func ConvertData(conv *internal.Conv, tableId string, colIds []string, srcSchema
 		v = append(v, x)
 		c = append(c, spColDef.Name)
 	}
-	if aux, ok := conv.SyntheticPKeys[tableId]; ok {
-		c = append(c, conv.SpSchema[tableId].ColDefs[aux.ColId].Name)
-		v = append(v, fmt.Sprintf("%d", int64(bits.Reverse64(uint64(aux.Sequence)))))
-		aux.Sequence++
-		conv.SyntheticPKeys[tableId] = aux
+	c = append(c, transformedCols...)
+	v = append(v, transformedVals...)
+	if synthCol, synthVal, ok := conv.NextSyntheticPKeyValue(tableId); ok {
+		c = append(c, synthCol)
+		v = append(v, synthVal)
 	}
 	return spSchema.Name, c, v, nil
 }
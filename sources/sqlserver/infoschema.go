@@ -23,6 +23,7 @@ import (
 
 	sp "cloud.google.com/go/spanner"
 
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
@@ -50,7 +51,7 @@ type InfoSchemaImpl struct {
 
 // GetToDdl function below implement the common.InfoSchema interface.
 func (isi InfoSchemaImpl) GetToDdl() common.ToDdl {
-	return ToDdlImpl{}
+	return common.LookupToDdl(constants.SQLSERVER, ToDdlImpl{})
 }
 
 // We leave the 2 functions below empty to be able to pass this as an infoSchema interface. We don't need these for now.
@@ -96,7 +97,7 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 	srcCols, _ := rows.Columns()
 	v, scanArgs := buildVals(len(srcCols))
 	colNameIdMap := internal.GetSrcColNameIdMap(conv.SrcSchema[tableId])
-	for rows.Next() {
+	for !conv.Interrupted() && rows.Next() {
 		// get RawBytes from data.
 		err := rows.Scan(scanArgs...)
 		if err != nil {
@@ -110,7 +111,7 @@ func (isi InfoSchemaImpl) ProcessData(conv *internal.Conv, tableId string, srcSc
 		if err != nil {
 			conv.Unexpected(fmt.Sprintf("Error while converting data: %s\n", err))
 			conv.StatsAddBadRow(srcTableName, conv.DataMode())
-			conv.CollectBadRow(srcTableName, srcCols, values)
+			conv.CollectBadRowWithReason(srcTableName, srcCols, values, err.Error())
 			continue
 		}
 		ProcessDataRow(conv, tableId, commonColIds, srcSchema, spSchema, newValues)
@@ -125,7 +126,7 @@ func (isi InfoSchemaImpl) GetRowsFromTable(conv *internal.Conv, tableId string)
 	tblName := strings.Replace(tbl.Name, tbl.Schema+".", "", 1)
 
 	q := getSelectQuery(isi.DbName, tbl.Schema, tblName, tbl.ColIds, tbl.ColDefs)
-	rows, err := isi.Db.Query(q)
+	rows, err := isi.Db.QueryContext(conv.Context(), q)
 	if err != nil {
 		return nil, err
 	}
@@ -315,7 +316,107 @@ func (isi InfoSchemaImpl) GetConstraints(conv *internal.Conv, table common.Schem
 			m[col] = append(m[col], constraint)
 		}
 	}
-	return primaryKeys, nil, m, nil
+	checkKeys, err := isi.getCheckConstraints(conv, table)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return primaryKeys, checkKeys, m, nil
+}
+
+// getCheckConstraints returns the enabled check constraints of a table from
+// sys.check_constraints. SQL Server stores their definitions with bracketed
+// identifiers, e.g. ([price]>(0)), which are unquoted for Spanner.
+func (isi InfoSchemaImpl) getCheckConstraints(conv *internal.Conv, table common.SchemaAndName) ([]schema.CheckConstraint, error) {
+	q := `
+		SELECT 
+			CC.name, 
+			CC.definition
+		FROM sys.check_constraints AS CC
+		INNER JOIN sys.tables AS TBL 
+			ON TBL.object_id = CC.parent_object_id
+		INNER JOIN sys.schemas AS SCH 
+			ON SCH.schema_id = TBL.schema_id
+		WHERE SCH.name = @p1 AND TBL.name = @p2 AND CC.is_disabled = 0
+		ORDER BY CC.name;
+	`
+	rows, err := isi.Db.Query(q, table.Schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var checkKeys []schema.CheckConstraint
+	var name, definition string
+	for rows.Next() {
+		err := rows.Scan(&name, &definition)
+		if err != nil {
+			conv.Unexpected(fmt.Sprintf("Can't scan: %v", err))
+			continue
+		}
+		expr := quoteBracketedIdentifiers(definition)
+		if !strings.HasPrefix(expr, "(") || !strings.HasSuffix(expr, ")") {
+			expr = "(" + expr + ")"
+		}
+		checkKeys = append(checkKeys, schema.CheckConstraint{Name: name, Expr: expr, ExprId: internal.GenerateExpressionId(), Id: internal.GenerateCheckConstrainstId()})
+	}
+	return checkKeys, nil
+}
+
+// quoteBracketedIdentifiers converts the [name] quoting of identifiers used
+// by SQL Server in the definitions of check constraints to the `name` quoting
+// of Spanner. Brackets in string literals, such as the character ranges of
+// LIKE patterns, are left as they are.
+func quoteBracketedIdentifiers(definition string) string {
+	var b strings.Builder
+	for i := 0; i < len(definition); {
+		switch definition[i] {
+		case '\'':
+			// Copy the string literal, in which '' is an escaped quote.
+			j := i + 1
+			for j < len(definition) {
+				if definition[j] == '\'' {
+					if j+1 < len(definition) && definition[j+1] == '\'' {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			if j < len(definition) {
+				j++
+			}
+			b.WriteString(definition[i:j])
+			i = j
+		case '[':
+			// Read the identifier, in which ]] is an escaped bracket.
+			var name strings.Builder
+			j := i + 1
+			for j < len(definition) {
+				if definition[j] == ']' {
+					if j+1 < len(definition) && definition[j+1] == ']' {
+						name.WriteByte(']')
+						j += 2
+						continue
+					}
+					break
+				}
+				name.WriteByte(definition[j])
+				j++
+			}
+			if j == len(definition) {
+				// Unterminated identifier: leave the rest as it is.
+				b.WriteString(definition[i:])
+				i = j
+				continue
+			}
+			b.WriteString("`" + strings.ReplaceAll(name.String(), "`", "\\`") + "`")
+			i = j + 1
+		default:
+			b.WriteByte(definition[i])
+			i++
+		}
+	}
+	return b.String()
 }
 
 // GetForeignKeys returns a list of all the foreign key constraints.
@@ -64,6 +64,11 @@ func TestProcessSchema(t *testing.T) {
 				{"user_id", "PRIMARY KEY"},
 				{"ref", "FOREIGN KEY"}},
 		},
+		{
+			query: "SELECT (.+) FROM sys.check_constraints AS CC (.+)",
+			args:  []driver.Value{"dbo", "user"},
+			cols:  []string{"name", "definition"},
+		},
 		{
 			query: "SELECT (.+) FROM sys.foreign_keys AS FK (.+)",
 			args:  []driver.Value{"dbo.user"},
@@ -94,6 +99,11 @@ func TestProcessSchema(t *testing.T) {
 			rows: [][]driver.Value{
 				{"Id", "PRIMARY KEY"},
 			},
+		},
+		{
+			query: "SELECT (.+) FROM sys.check_constraints AS CC (.+)",
+			args:  []driver.Value{"dbo", "test"},
+			cols:  []string{"name", "definition"},
 		}, {
 			query: "SELECT (.+) FROM sys.foreign_keys AS FK (.+)",
 			args:  []driver.Value{"dbo.test"},
@@ -160,6 +170,11 @@ func TestProcessSchema(t *testing.T) {
 				{"userid", "PRIMARY KEY"},
 			},
 		},
+		{
+			query: "SELECT (.+) FROM sys.check_constraints AS CC (.+)",
+			args:  []driver.Value{"dbo", "cart"},
+			cols:  []string{"name", "definition"},
+		},
 		{
 			query: "SELECT (.+) FROM sys.foreign_keys AS FK (.+)",
 			args:  []driver.Value{"dbo.cart"},
@@ -198,6 +213,11 @@ func TestProcessSchema(t *testing.T) {
 				{"product_id", "PRIMARY KEY"},
 			},
 		},
+		{
+			query: "SELECT (.+) FROM sys.check_constraints AS CC (.+)",
+			args:  []driver.Value{"production", "product"},
+			cols:  []string{"name", "definition"},
+		},
 		{
 			query: "SELECT (.+) FROM sys.foreign_keys AS FK (.+)",
 			args:  []driver.Value{"production.product"},
@@ -228,6 +248,11 @@ func TestProcessSchema(t *testing.T) {
 				{"ref_txt", "PRIMARY KEY"},
 			},
 		},
+		{
+			query: "SELECT (.+) FROM sys.check_constraints AS CC (.+)",
+			args:  []driver.Value{"dbo", "test_ref"},
+			cols:  []string{"name", "definition"},
+		},
 		{
 			query: "SELECT (.+) FROM sys.foreign_keys AS FK (.+)",
 			args:  []driver.Value{"dbo.test_ref"},
@@ -371,6 +396,62 @@ func TestProcessSchema(t *testing.T) {
 
 }
 
+func TestGetConstraints(t *testing.T) {
+	ms := []mockSpec{
+		{
+			query: "SELECT (.+) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS (.+)",
+			args:  []driver.Value{"dbo", "product"},
+			cols:  []string{"column_name", "constraint_type"},
+			rows: [][]driver.Value{
+				{"product_id", "PRIMARY KEY"},
+				{"sku", "UNIQUE"},
+			},
+		},
+		{
+			query: "SELECT (.+) FROM sys.check_constraints AS CC (.+)",
+			args:  []driver.Value{"dbo", "product"},
+			cols:  []string{"name", "definition"},
+			rows: [][]driver.Value{
+				{"ck_price", "([price]>(0))"},
+				{"ck_sku", "[sku] like 'P%'"},
+			},
+		},
+	}
+	db := mkMockDB(t, ms)
+	isi := InfoSchemaImpl{Db: db}
+	conv := internal.MakeConv()
+
+	primaryKeys, checkKeys, m, err := isi.GetConstraints(conv, common.SchemaAndName{Schema: "dbo", Name: "product"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"product_id"}, primaryKeys)
+	assert.Equal(t, map[string][]string{"sku": {"UNIQUE"}}, m)
+	assert.Equal(t, 2, len(checkKeys))
+	assert.Equal(t, "ck_price", checkKeys[0].Name)
+	assert.Equal(t, "(`price`>(0))", checkKeys[0].Expr)
+	assert.Equal(t, "ck_sku", checkKeys[1].Name)
+	assert.Equal(t, "(`sku` like 'P%')", checkKeys[1].Expr)
+	assert.Equal(t, int64(0), conv.Unexpecteds())
+}
+
+func TestQuoteBracketedIdentifiers(t *testing.T) {
+	tests := []struct {
+		definition string
+		expected   string
+	}{
+		{"([price]>(0))", "(`price`>(0))"},
+		{"([order id]>(0))", "(`order id`>(0))"},
+		{"([code] LIKE '[A-Z]%')", "(`code` LIKE '[A-Z]%')"},
+		{"([name]<>N'it''s [x]')", "(`name`<>N'it''s [x]')"},
+		{"([a]]b]>(0))", "(`a]b`>(0))"},
+		{"([a`b]>(0))", "(`a\\`b`>(0))"},
+		{"([code]='[unterminated')", "(`code`='[unterminated')"},
+		{"([unterminated", "([unterminated"},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, quoteBracketedIdentifiers(tc.definition), tc.definition)
+	}
+}
+
 func mkMockDB(t *testing.T, ms []mockSpec) *sql.DB {
 	db, mock, err := sqlmock.New()
 	assert.Nil(t, err)
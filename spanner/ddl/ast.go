@@ -23,6 +23,7 @@ package ddl
 import (
 	"fmt"
 	"math"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -79,6 +80,12 @@ const (
 	PGTimestamptz string = "TIMESTAMPTZ"
 	// Jsonb represents the PG.JSONB type
 	PGJSONB string = "JSONB"
+	// PGCommitTimestamp represents the PG type of TIMESTAMPTZ columns which
+	// accept the Spanner commit timestamp.
+	PGCommitTimestamp string = "SPANNER.COMMIT_TIMESTAMP"
+	// AllowCommitTimestampOpt is the column option which lets a TIMESTAMP
+	// column accept the Spanner commit timestamp.
+	AllowCommitTimestampOpt string = "allow_commit_timestamp"
 	// PGMaxLength represents sentinel for Type's Len field in PG.
 	PGMaxLength                          = 2621440
 	GeneratedColStored  GeneratedColType = "STORED"
@@ -129,6 +136,9 @@ type Type struct {
 	// IsArray represents if Type is an array_type or not
 	// When false, column has type T; when true, it is an array of type T.
 	IsArray bool
+	// VectorLength is the number of elements of the FLOAT32 or FLOAT64
+	// arrays of a column used by vector indexes, 0 if it isn't fixed.
+	VectorLength int64 `json:",omitempty"`
 }
 
 // PrintColumnDefType unparses the type encoded in a ColumnDef.
@@ -146,6 +156,9 @@ func (ty Type) PrintColumnDefType(isVirtual bool) string {
 	}
 	if ty.IsArray {
 		str = "ARRAY<" + str + ">"
+		if ty.VectorLength > 0 {
+			str += fmt.Sprintf("(vector_length=>%d)", ty.VectorLength)
+		}
 	}
 	return str
 }
@@ -242,7 +255,11 @@ func (c Config) quote(s string) string {
 func (cd ColumnDef) PrintColumnDef(c Config) (string, string) {
 	var s string
 	if c.SpDialect == constants.DIALECT_POSTGRESQL {
-		s = fmt.Sprintf("%s %s", c.quote(cd.Name), cd.T.PGPrintColumnDefType(cd.GeneratedColumn.IsVirtual()))
+		colType := cd.T.PGPrintColumnDefType(cd.GeneratedColumn.IsVirtual())
+		if cd.AllowsCommitTimestamp() {
+			colType = PGCommitTimestamp
+		}
+		s = fmt.Sprintf("%s %s", c.quote(cd.Name), colType)
 		if cd.NotNull {
 			s += " NOT NULL "
 		}
@@ -264,12 +281,52 @@ func (cd ColumnDef) PrintColumnDef(c Config) (string, string) {
 			opts = append(opts, fmt.Sprintf("cassandra_type = '%s'", opt))
 		}
 	}
+	if c.SpDialect != constants.DIALECT_POSTGRESQL && cd.AllowsCommitTimestamp() {
+		opts = append(opts, AllowCommitTimestampOpt+" = true")
+	}
 	if len(opts) > 0 {
 		s += " OPTIONS (" + strings.Join(opts, ", ") + ")"
 	}
 	return s, cd.Comment
 }
 
+// AllowsCommitTimestamp returns true if the column is a TIMESTAMP column
+// which accepts the Spanner commit timestamp.
+func (cd ColumnDef) AllowsCommitTimestamp() bool {
+	return cd.T.Name == Timestamp && !cd.T.IsArray && cd.Opts[AllowCommitTimestampOpt] == "true"
+}
+
+// PrintAddColumn unparses the statement adding the column to an existing
+// table.
+func (cd ColumnDef) PrintAddColumn(tableName string, c Config) string {
+	col, _ := cd.PrintColumnDef(c)
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", c.quote(tableName), strings.TrimSpace(col))
+}
+
+// PrintDropTable unparses the statements dropping the table, preceded by the
+// statements dropping its indexes and named foreign keys, which Spanner
+// requires to be dropped first.
+func (ct CreateTable) PrintDropTable(c Config) []string {
+	var stmts []string
+	for _, index := range ct.Indexes {
+		stmts = append(stmts, fmt.Sprintf("DROP INDEX %s", c.quote(index.Name)))
+	}
+	for _, index := range ct.VectorIndexes {
+		stmts = append(stmts, index.PrintDropVectorIndex(c))
+	}
+	for _, fk := range ct.ForeignKeys {
+		if fk.Name != "" {
+			stmts = append(stmts, fk.PrintDropForeignKey(ct, c))
+		}
+	}
+	return append(stmts, fmt.Sprintf("DROP TABLE %s", c.quote(ct.Name)))
+}
+
+// PrintDeleteAll unparses the statement deleting all the rows of the table.
+func (ct CreateTable) PrintDeleteAll(c Config) string {
+	return fmt.Sprintf("DELETE FROM %s WHERE true", c.quote(ct.Name))
+}
+
 // IndexKey encodes the following DDL definition:
 //
 //	primary_key:
@@ -277,11 +334,19 @@ func (cd ColumnDef) PrintColumnDef(c Config) (string, string) {
 //	key_part:
 //	   column_name [{ ASC | DESC }]
 type IndexKey struct {
-	ColId string
-	Desc  bool // Default order is ascending i.e. Desc = false.
-	Order int
+	ColId      string
+	Desc       bool // Default order is ascending i.e. Desc = false.
+	Order      int
+	NullsOrder string `json:",omitempty"` // NullsFirst or NullsLast, only emitted for PostgreSQL dialect. Empty means default null ordering.
 }
 
+const (
+	// NullsFirst sorts NULL values before non-NULL values in an index key.
+	NullsFirst string = "NULLS FIRST"
+	// NullsLast sorts NULL values after non-NULL values in an index key.
+	NullsLast string = "NULLS LAST"
+)
+
 type CheckConstraint struct {
 	Id     string
 	Name   string
@@ -293,9 +358,12 @@ type CheckConstraint struct {
 func (idx IndexKey) PrintPkOrIndexKey(ct CreateTable, c Config) string {
 	col := c.quote(ct.ColDefs[idx.ColId].Name)
 	if idx.Desc {
-		return fmt.Sprintf("%s DESC", col)
+		col = fmt.Sprintf("%s DESC", col)
 	}
 	// Don't print out ASC -- that's the default.
+	if c.SpDialect == constants.DIALECT_POSTGRESQL && idx.NullsOrder != "" {
+		col = fmt.Sprintf("%s %s", col, idx.NullsOrder)
+	}
 	return col
 }
 
@@ -322,6 +390,29 @@ type InterleavedParent struct {
 	InterleaveType string
 }
 
+// RowDeletionPolicy encodes the following DDL definition:
+//
+//	ROW DELETION POLICY (OLDER_THAN(timestamp_column, INTERVAL num_days DAY))
+//
+// A zero value means the table has no row deletion policy.
+type RowDeletionPolicy struct {
+	ColId   string
+	NumDays int64
+}
+
+// PrintRowDeletionPolicy unparses the row deletion policy of ct. PostgreSQL
+// dialect uses the equivalent TTL INTERVAL ... ON ... clause.
+func (rdp RowDeletionPolicy) PrintRowDeletionPolicy(ct CreateTable, c Config) string {
+	if rdp.ColId == "" {
+		return ""
+	}
+	col := c.quote(ct.ColDefs[rdp.ColId].Name)
+	if c.SpDialect == constants.DIALECT_POSTGRESQL {
+		return fmt.Sprintf(" TTL INTERVAL '%d days' ON %s", rdp.NumDays, col)
+	}
+	return fmt.Sprintf(",\nROW DELETION POLICY (OLDER_THAN(%s, INTERVAL %d DAY))", col, rdp.NumDays)
+}
+
 // PrintForeignKey unparses the foreign keys.
 func (k Foreignkey) PrintForeignKey(c Config) string {
 	var cols, referCols []string
@@ -344,17 +435,19 @@ func (k Foreignkey) PrintForeignKey(c Config) string {
 //
 //	create_table: CREATE TABLE table_name ([column_def, ...] ) primary_key [, cluster]
 type CreateTable struct {
-	Name             string
-	ColIds           []string // Provides names and order of columns
-	ShardIdColumn    string
-	ColDefs          map[string]ColumnDef // Provides definition of columns (a map for simpler/faster lookup during type processing)
-	PrimaryKeys      []IndexKey
-	ForeignKeys      []Foreignkey
-	Indexes          []CreateIndex
-	ParentTable      InterleavedParent // if not empty, this table will be interleaved
-	CheckConstraints []CheckConstraint
-	Comment          string
-	Id               string
+	Name              string
+	ColIds            []string // Provides names and order of columns
+	ShardIdColumn     string
+	ColDefs           map[string]ColumnDef // Provides definition of columns (a map for simpler/faster lookup during type processing)
+	PrimaryKeys       []IndexKey
+	ForeignKeys       []Foreignkey
+	Indexes           []CreateIndex
+	ParentTable       InterleavedParent // if not empty, this table will be interleaved
+	CheckConstraints  []CheckConstraint
+	RowDeletionPolicy RowDeletionPolicy // if not empty, rows older than the policy are deleted by Spanner
+	VectorIndexes     []VectorIndex
+	Comment           string
+	Id                string
 }
 
 // PrintCreateTable unparses a CREATE TABLE statement.
@@ -427,13 +520,15 @@ func (ct CreateTable) PrintCreateTable(spSchema Schema, config Config) string {
 		checkString = ""
 	}
 
+	rowDeletionPolicy := ct.RowDeletionPolicy.PrintRowDeletionPolicy(ct, config)
+
 	if len(keys) == 0 {
-		return fmt.Sprintf("%sCREATE TABLE %s (\n%s%s) %s", tableComment, config.quote(ct.Name), cols, checkString, interleave)
+		return fmt.Sprintf("%sCREATE TABLE %s (\n%s%s) %s%s", tableComment, config.quote(ct.Name), cols, checkString, interleave, rowDeletionPolicy)
 	}
 	if config.SpDialect == constants.DIALECT_POSTGRESQL {
-		return fmt.Sprintf("%sCREATE TABLE %s (\n%s%s\tPRIMARY KEY (%s)\n)%s", tableComment, config.quote(ct.Name), cols, checkString, strings.Join(keys, ", "), interleave)
+		return fmt.Sprintf("%sCREATE TABLE %s (\n%s%s\tPRIMARY KEY (%s)\n)%s%s", tableComment, config.quote(ct.Name), cols, checkString, strings.Join(keys, ", "), interleave, rowDeletionPolicy)
 	}
-	return fmt.Sprintf("%sCREATE TABLE %s (\n%s%s) PRIMARY KEY (%s)%s", tableComment, config.quote(ct.Name), cols, checkString, strings.Join(keys, ", "), interleave)
+	return fmt.Sprintf("%sCREATE TABLE %s (\n%s%s) PRIMARY KEY (%s)%s%s", tableComment, config.quote(ct.Name), cols, checkString, strings.Join(keys, ", "), interleave, rowDeletionPolicy)
 }
 
 // CreateIndex encodes the following DDL definition:
@@ -450,6 +545,31 @@ type CreateIndex struct {
 	// interleaving clauses yet, so we omit them for now.
 }
 
+// Distance types of vector indexes.
+const (
+	CosineDistance    = "COSINE"
+	EuclideanDistance = "EUCLIDEAN"
+	DotProduct        = "DOT_PRODUCT"
+)
+
+// VectorIndex encodes the following DDL definition:
+//
+//	create vector index: CREATE VECTOR INDEX index_name ON table_name ( column_name ) [ storing_clause ] [ WHERE column_name IS NOT NULL ] OPTIONS ( distance_type = ... [, tree_depth = ...] [, num_leaves = ...] [, num_branches = ...] )
+//
+// The column must be an array of FLOAT32 or FLOAT64 with a vector length.
+// Zero tree depth, number of leaves and number of branches leave the
+// Spanner defaults.
+type VectorIndex struct {
+	Name            string
+	Id              string
+	ColId           string
+	StoredColumnIds []string
+	DistanceType    string // CosineDistance, EuclideanDistance or DotProduct.
+	TreeDepth       int64  // 2 or 3.
+	NumLeaves       int64
+	NumBranches     int64 // Only for trees of depth 3.
+}
+
 // GeneratedColumn represents a Generated Column.
 type GeneratedColumn struct {
 	IsPresent bool
@@ -624,6 +744,50 @@ func (ci CreateIndex) PrintCreateIndex(ct CreateTable, c Config) string {
 	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s)%s", unique, c.quote(ci.Name), c.quote(ct.Name), strings.Join(keys, ", "), storingClause)
 }
 
+// PrintVectorIndex unparses a CREATE VECTOR INDEX statement. Nullable
+// columns are filtered with WHERE column IS NOT NULL, which Spanner requires.
+// PostgreSQL dialect uses the equivalent CREATE INDEX ... USING ScaNN.
+func (vi VectorIndex) PrintVectorIndex(ct CreateTable, c Config) string {
+	col := ct.ColDefs[vi.ColId]
+	options := []string{fmt.Sprintf("distance_type = '%s'", vi.DistanceType)}
+	if vi.TreeDepth > 0 {
+		options = append(options, fmt.Sprintf("tree_depth = %d", vi.TreeDepth))
+	}
+	if vi.NumLeaves > 0 {
+		options = append(options, fmt.Sprintf("num_leaves = %d", vi.NumLeaves))
+	}
+	if vi.NumBranches > 0 {
+		options = append(options, fmt.Sprintf("num_branches = %d", vi.NumBranches))
+	}
+	var storingClause, whereClause string
+	if len(vi.StoredColumnIds) > 0 {
+		storedColumns := []string{}
+		for _, colId := range vi.StoredColumnIds {
+			storedColumns = append(storedColumns, c.quote(ct.ColDefs[colId].Name))
+		}
+		stored := "STORING"
+		if c.SpDialect == constants.DIALECT_POSTGRESQL {
+			stored = "INCLUDE"
+		}
+		storingClause = fmt.Sprintf(" %s (%s)", stored, strings.Join(storedColumns, ", "))
+	}
+	if !col.NotNull {
+		whereClause = fmt.Sprintf(" WHERE %s IS NOT NULL", c.quote(col.Name))
+	}
+	if c.SpDialect == constants.DIALECT_POSTGRESQL {
+		return fmt.Sprintf("CREATE INDEX %s ON %s USING ScaNN (%s)%s WITH (%s)%s", c.quote(vi.Name), c.quote(ct.Name), c.quote(col.Name), storingClause, strings.Join(options, ", "), whereClause)
+	}
+	return fmt.Sprintf("CREATE VECTOR INDEX %s ON %s (%s)%s%s OPTIONS (%s)", c.quote(vi.Name), c.quote(ct.Name), c.quote(col.Name), storingClause, whereClause, strings.Join(options, ", "))
+}
+
+// PrintDropVectorIndex unparses the statement dropping the vector index.
+func (vi VectorIndex) PrintDropVectorIndex(c Config) string {
+	if c.SpDialect == constants.DIALECT_POSTGRESQL {
+		return fmt.Sprintf("DROP INDEX %s", c.quote(vi.Name))
+	}
+	return fmt.Sprintf("DROP VECTOR INDEX %s", c.quote(vi.Name))
+}
+
 // Checks if the colId is part of the primary of a table
 // Used for detecting if a key needs to be skipped while creating the
 // storing clause.
@@ -654,6 +818,12 @@ func (k Foreignkey) PrintForeignKeyAlterTable(spannerSchema Schema, c Config, ta
 	return s
 }
 
+// PrintDropForeignKey unparses the statement dropping the foreign key of
+// table ct, which must be named.
+func (k Foreignkey) PrintDropForeignKey(ct CreateTable, c Config) string {
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", c.quote(ct.Name), c.quote(k.Name))
+}
+
 // FormatCheckConstraints formats the check constraints in SQL syntax.
 func FormatCheckConstraints(cks []CheckConstraint, dailect string) string {
 	var builder strings.Builder
@@ -688,7 +858,9 @@ func NewSchema() Schema {
 }
 
 // Tables are ordered in alphabetical order with one exception: interleaved
-// tables appear after the definition of their parent table.
+// tables appear after the definition of their parent table. Tables whose
+// parent tables form a cycle, which Spanner rejects but edits of the schema
+// can create, come last in alphabetical order.
 //
 // TODO: Move this method to mapping.go and preserve the table names in sorted
 // order in conv so that we don't need to order the table names multiple times.
@@ -703,7 +875,15 @@ func GetSortedTableIdsBySpName(s Schema) []string {
 	sort.Strings(tableNames)
 	tableQueue := tableNames
 	tableAdded := make(map[string]bool)
+	// Number of tables put back in the queue since a table was last added.
+	deferred := 0
 	for len(tableQueue) > 0 {
+		if deferred > len(tableQueue) {
+			// A whole pass made no progress: the remaining tables are in
+			// cycles of interleaved tables, or interleaved in one.
+			sortedTableNames = append(sortedTableNames, tableQueue...)
+			break
+		}
 		tableName := tableQueue[0]
 		table := s[tableNameIdMap[tableName]]
 		tableQueue = tableQueue[1:]
@@ -718,14 +898,15 @@ func GetSortedTableIdsBySpName(s Schema) []string {
 		if table.ParentTable.Id == "" || tableAdded[s[table.ParentTable.Id].Name] || !parentTableExists {
 			sortedTableNames = append(sortedTableNames, tableName)
 			tableAdded[tableName] = true
+			deferred = 0
 		} else {
 			// We can't add table t now because its parent hasn't been added.
 			// Add it at end of tables and we'll try again later.
-			// We might need multiple iterations to add chains of interleaved tables,
-			// but we will always make progress because interleaved tables can't
-			// have cycles. In principle this could be O(n^2), but in practice chains
-			// of interleaved tables are small.
+			// We might need multiple iterations to add chains of interleaved tables.
+			// In principle this could be O(n^2), but in practice chains of
+			// interleaved tables are small.
 			tableQueue = append(tableQueue, tableName)
+			deferred++
 		}
 	}
 	for _, tableName := range sortedTableNames {
@@ -769,6 +950,9 @@ func GetDDL(c Config, tableSchema Schema, sequenceSchema map[string]Sequence, db
 			for _, index := range tableSchema[tableId].Indexes {
 				ddl = append(ddl, index.PrintCreateIndex(tableSchema[tableId], c))
 			}
+			for _, index := range tableSchema[tableId].VectorIndexes {
+				ddl = append(ddl, index.PrintVectorIndex(tableSchema[tableId], c))
+			}
 		}
 	}
 	// Append foreign key constraints to DDL.
@@ -906,3 +1090,160 @@ func (dbOptions DatabaseOptions) PGPrintDatabaseOptions() []string {
 	}
 	return dbOptionsDdls
 }
+
+// CreateRole encodes the following DDL definition:
+//
+//	CREATE ROLE role_name
+type CreateRole struct {
+	Id   string
+	Name string
+}
+
+// CreateView encodes the following DDL definition:
+//
+//	CREATE VIEW view_name SQL SECURITY INVOKER AS query
+type CreateView struct {
+	Id    string
+	Name  string
+	Query string
+}
+
+// PrintCreateView unparses a CREATE VIEW statement.
+func (v CreateView) PrintCreateView(c Config) string {
+	query := strings.TrimSuffix(strings.TrimSpace(v.Query), ";")
+	return fmt.Sprintf("CREATE VIEW %s SQL SECURITY INVOKER AS %s", c.quote(v.Name), query)
+}
+
+// Grant encodes the following DDL definitions:
+//
+//	GRANT { privilege [(column_list)] }[, ...] ON TABLE table_name TO ROLE role_list
+//	GRANT ROLE role_name TO ROLE role_list
+//
+// Exactly one of TableId or RoleId is set.
+type Grant struct {
+	Privileges []string // Table privileges: SELECT, INSERT, UPDATE or DELETE.
+	TableId    string   // Table the privileges are granted on.
+	ColIds     []string // If not empty, the privileges only apply to these columns.
+	RoleId     string   // Role granted to the grantees (role membership).
+	GranteeIds []string // Roles receiving the grant.
+}
+
+// PrintCreateRole unparses a CREATE ROLE statement.
+func (r CreateRole) PrintCreateRole(c Config) string {
+	return "CREATE ROLE " + r.Name
+}
+
+// PrintGrant unparses a GRANT statement. Tables, columns and roles which no
+// longer exist are skipped, and an empty string is returned if nothing is
+// left to grant.
+func (g Grant) PrintGrant(spSchema Schema, roles map[string]CreateRole, c Config) string {
+	var grantees []string
+	for _, id := range g.GranteeIds {
+		if r, ok := roles[id]; ok {
+			grantees = append(grantees, r.Name)
+		}
+	}
+	if len(grantees) == 0 {
+		return ""
+	}
+	granteeList := strings.Join(grantees, ", ")
+	pg := c.SpDialect == constants.DIALECT_POSTGRESQL
+	if g.RoleId != "" {
+		r, ok := roles[g.RoleId]
+		if !ok {
+			return ""
+		}
+		if pg {
+			return fmt.Sprintf("GRANT %s TO %s", r.Name, granteeList)
+		}
+		return fmt.Sprintf("GRANT ROLE %s TO ROLE %s", r.Name, granteeList)
+	}
+	ct, ok := spSchema[g.TableId]
+	if !ok || len(g.Privileges) == 0 {
+		return ""
+	}
+	var cols []string
+	for _, colId := range g.ColIds {
+		if cd, ok := ct.ColDefs[colId]; ok {
+			cols = append(cols, c.quote(cd.Name))
+		}
+	}
+	if len(g.ColIds) > 0 && len(cols) == 0 {
+		return ""
+	}
+	var privs []string
+	for _, p := range g.Privileges {
+		if len(cols) > 0 {
+			p = fmt.Sprintf("%s(%s)", p, strings.Join(cols, ", "))
+		}
+		privs = append(privs, p)
+	}
+	if pg {
+		return fmt.Sprintf("GRANT %s ON TABLE %s TO %s", strings.Join(privs, ", "), c.quote(ct.Name), granteeList)
+	}
+	return fmt.Sprintf("GRANT %s ON TABLE %s TO ROLE %s", strings.Join(privs, ", "), c.quote(ct.Name), granteeList)
+}
+
+// GetViewDDL returns the CREATE VIEW statements of views, sorted by name
+// except that views come after the views their query refers to. It must be
+// applied after the tables the views refer to have been created.
+func GetViewDDL(c Config, views map[string]CreateView) []string {
+	var pending []CreateView
+	for _, v := range views {
+		pending = append(pending, v)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Name < pending[j].Name })
+	var ddl []string
+	for len(pending) > 0 {
+		// Pick the first view which doesn't refer to another pending view,
+		// or the first view if there is a cycle.
+		next := 0
+		for i, v := range pending {
+			if !refersToAny(v, pending) {
+				next = i
+				break
+			}
+		}
+		ddl = append(ddl, pending[next].PrintCreateView(c))
+		pending = append(pending[:next], pending[next+1:]...)
+	}
+	return ddl
+}
+
+// refersToAny returns true if the query of v mentions the name of one of
+// views other than v.
+func refersToAny(v CreateView, views []CreateView) bool {
+	query := strings.ToLower(v.Query)
+	for _, other := range views {
+		if other.Id == v.Id {
+			continue
+		}
+		if regexp.MustCompile(`\b` + regexp.QuoteMeta(strings.ToLower(other.Name)) + `\b`).MatchString(query) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAccessControlDDL returns the CREATE ROLE statements of roles, sorted by
+// name, followed by grants. It must be applied after the tables the grants
+// refer to have been created.
+func GetAccessControlDDL(c Config, spSchema Schema, roles map[string]CreateRole, grants []Grant) []string {
+	var ddl []string
+	var names []string
+	byName := make(map[string]CreateRole)
+	for _, r := range roles {
+		names = append(names, r.Name)
+		byName[r.Name] = r
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ddl = append(ddl, byName[name].PrintCreateRole(c))
+	}
+	for _, g := range grants {
+		if s := g.PrintGrant(spSchema, roles, c); s != "" {
+			ddl = append(ddl, s)
+		}
+	}
+	return ddl
+}
@@ -98,6 +98,7 @@ func TestPrintColumnDef(t *testing.T) {
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64, IsArray: true}}, expected: "col1 ARRAY<INT64>"},
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64}, NotNull: true}, expected: "col1 INT64 NOT NULL "},
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64, IsArray: true}, NotNull: true}, expected: "col1 ARRAY<INT64> NOT NULL "},
+		{in: ColumnDef{Name: "col1", T: Type{Name: Float32, IsArray: true, VectorLength: 128}}, expected: "col1 ARRAY<FLOAT32>(vector_length=>128)"},
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64}}, protectIds: true, expected: "`col1` INT64"},
 		{
 			in: ColumnDef{
@@ -118,6 +119,22 @@ func TestPrintColumnDef(t *testing.T) {
 			},
 			expected: "col1 INT64 OPTIONS (cassandra_type = 'bigint')",
 		},
+		{
+			in: ColumnDef{
+				Name: "col1",
+				T:    Type{Name: Timestamp},
+				Opts: map[string]string{AllowCommitTimestampOpt: "true"},
+			},
+			expected: "col1 TIMESTAMP OPTIONS (allow_commit_timestamp = true)",
+		},
+		{
+			in: ColumnDef{
+				Name: "col1",
+				T:    Type{Name: String, Len: MaxLength},
+				Opts: map[string]string{AllowCommitTimestampOpt: "true"},
+			},
+			expected: "col1 STRING(MAX)",
+		},
 		{
 			in: ColumnDef{
 				Name: "col1",
@@ -160,6 +177,7 @@ func TestPrintColumnDefPG(t *testing.T) {
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64}, NotNull: true}, expected: "col1 INT8 NOT NULL "},
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64, IsArray: true}, NotNull: true}, expected: "col1 VARCHAR(2621440) NOT NULL "},
 		{in: ColumnDef{Name: "col1", T: Type{Name: Int64}}, protectIds: true, expected: "\"col1\" INT8"},
+		{in: ColumnDef{Name: "col1", T: Type{Name: Timestamp}, Opts: map[string]string{AllowCommitTimestampOpt: "true"}}, expected: "col1 SPANNER.COMMIT_TIMESTAMP"},
 		{
 			in: ColumnDef{
 				Name: "col1",
@@ -575,6 +593,85 @@ func TestPrintCreateTablePG(t *testing.T) {
 	}
 }
 
+func TestPrintCreateTableWithRowDeletionPolicy(t *testing.T) {
+	s := Schema{
+		"t1": CreateTable{
+			Name:   "table1",
+			ColIds: []string{"col1", "col2"},
+			ColDefs: map[string]ColumnDef{
+				"col1": {Name: "col1", T: Type{Name: Int64}, NotNull: true},
+				"col2": {Name: "col2", T: Type{Name: Timestamp}},
+			},
+			PrimaryKeys:       []IndexKey{{ColId: "col1"}},
+			RowDeletionPolicy: RowDeletionPolicy{ColId: "col2", NumDays: 30},
+			Id:                "t1",
+		},
+		"t2": CreateTable{
+			Name:   "table2",
+			ColIds: []string{"col3", "col4"},
+			ColDefs: map[string]ColumnDef{
+				"col3": {Name: "col3", T: Type{Name: Int64}, NotNull: true},
+				"col4": {Name: "col4", T: Type{Name: Timestamp}},
+			},
+			PrimaryKeys:       []IndexKey{{ColId: "col3"}},
+			ParentTable:       InterleavedParent{Id: "t1", OnDelete: constants.FK_CASCADE, InterleaveType: "IN PARENT"},
+			RowDeletionPolicy: RowDeletionPolicy{ColId: "col4", NumDays: 7},
+			Id:                "t2",
+		},
+	}
+	tests := []struct {
+		name     string
+		dialect  string
+		ct       CreateTable
+		expected string
+	}{
+		{
+			"row deletion policy",
+			constants.DIALECT_GOOGLESQL,
+			s["t1"],
+			"CREATE TABLE table1 (\n" +
+				"	col1 INT64 NOT NULL ,\n" +
+				"	col2 TIMESTAMP,\n" +
+				") PRIMARY KEY (col1),\n" +
+				"ROW DELETION POLICY (OLDER_THAN(col2, INTERVAL 30 DAY))",
+		},
+		{
+			"row deletion policy on interleaved table",
+			constants.DIALECT_GOOGLESQL,
+			s["t2"],
+			"CREATE TABLE table2 (\n" +
+				"	col3 INT64 NOT NULL ,\n" +
+				"	col4 TIMESTAMP,\n" +
+				") PRIMARY KEY (col3),\n" +
+				"INTERLEAVE IN PARENT table1 ON DELETE CASCADE,\n" +
+				"ROW DELETION POLICY (OLDER_THAN(col4, INTERVAL 7 DAY))",
+		},
+		{
+			"row deletion policy pg",
+			constants.DIALECT_POSTGRESQL,
+			s["t1"],
+			"CREATE TABLE table1 (\n" +
+				"	col1 INT8 NOT NULL ,\n" +
+				"	col2 TIMESTAMPTZ,\n" +
+				"	PRIMARY KEY (col1)\n" +
+				") TTL INTERVAL '30 days' ON col2",
+		},
+		{
+			"row deletion policy on interleaved table pg",
+			constants.DIALECT_POSTGRESQL,
+			s["t2"],
+			"CREATE TABLE table2 (\n" +
+				"	col3 INT8 NOT NULL ,\n" +
+				"	col4 TIMESTAMPTZ,\n" +
+				"	PRIMARY KEY (col3)\n" +
+				") INTERLEAVE IN PARENT table1 ON DELETE CASCADE TTL INTERVAL '7 days' ON col4",
+		},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, tc.ct.PrintCreateTable(s, Config{SpDialect: tc.dialect}), tc.name)
+	}
+}
+
 func TestPrintCreateIndex(t *testing.T) {
 	ct := CreateTable{
 		Name:   "mytable",
@@ -602,6 +699,14 @@ func TestPrintCreateIndex(t *testing.T) {
 			"i2",
 			nil,
 		},
+		{
+			"myindex3",
+			"t1",
+			/*Unique =*/ false,
+			[]IndexKey{{ColId: "c1", Desc: true, NullsOrder: NullsLast}, {ColId: "c2", NullsOrder: NullsFirst}},
+			"i3",
+			nil,
+		},
 	}
 	tests := []struct {
 		name       string
@@ -615,12 +720,55 @@ func TestPrintCreateIndex(t *testing.T) {
 		{"unique key", true, "", ci[1], "CREATE UNIQUE INDEX `myindex2` ON `mytable` (`col1` DESC, `col2`)"},
 		{"quote non unique PG", true, constants.DIALECT_POSTGRESQL, ci[0], "CREATE INDEX \"myindex\" ON \"mytable\" (\"col1\" DESC, \"col2\")"},
 		{"unique key PG", true, constants.DIALECT_POSTGRESQL, ci[1], "CREATE UNIQUE INDEX \"myindex2\" ON \"mytable\" (\"col1\" DESC, \"col2\")"},
+		{"nulls order", false, "", ci[2], "CREATE INDEX myindex3 ON mytable (col1 DESC, col2)"},
+		{"nulls order PG", false, constants.DIALECT_POSTGRESQL, ci[2], "CREATE INDEX myindex3 ON mytable (col1 DESC NULLS LAST, col2 NULLS FIRST)"},
 	}
 	for _, tc := range tests {
 		assert.Equal(t, tc.expected, tc.index.PrintCreateIndex(ct, Config{ProtectIds: tc.protectIds, SpDialect: tc.spDialect}))
 	}
 }
 
+func TestPrintVectorIndex(t *testing.T) {
+	ct := CreateTable{
+		Name:   "docs",
+		Id:     "t1",
+		ColIds: []string{"c1", "c2", "c3"},
+		ColDefs: map[string]ColumnDef{
+			"c1": {Name: "id", Id: "c1", T: Type{Name: Int64}, NotNull: true},
+			"c2": {Name: "embedding", Id: "c2", T: Type{Name: Float32, IsArray: true, VectorLength: 3}},
+			"c3": {Name: "title", Id: "c3", T: Type{Name: String, Len: MaxLength}},
+		},
+		PrimaryKeys: []IndexKey{{ColId: "c1", Order: 1}},
+	}
+	notNull := ct
+	notNull.ColDefs = map[string]ColumnDef{
+		"c1": ct.ColDefs["c1"],
+		"c2": {Name: "embedding", Id: "c2", T: Type{Name: Float32, IsArray: true, VectorLength: 3}, NotNull: true},
+		"c3": ct.ColDefs["c3"],
+	}
+	vi := []VectorIndex{
+		{Name: "docs_embedding", Id: "i1", ColId: "c2", DistanceType: CosineDistance},
+		{Name: "docs_embedding", Id: "i1", ColId: "c2", StoredColumnIds: []string{"c3"}, DistanceType: DotProduct, TreeDepth: 3, NumLeaves: 10000, NumBranches: 100},
+	}
+	tests := []struct {
+		name      string
+		spDialect string
+		ct        CreateTable
+		index     VectorIndex
+		expected  string
+	}{
+		{"default options", "", ct, vi[0], "CREATE VECTOR INDEX `docs_embedding` ON `docs` (`embedding`) WHERE `embedding` IS NOT NULL OPTIONS (distance_type = 'COSINE')"},
+		{"not null column", "", notNull, vi[0], "CREATE VECTOR INDEX `docs_embedding` ON `docs` (`embedding`) OPTIONS (distance_type = 'COSINE')"},
+		{"all options", "", ct, vi[1], "CREATE VECTOR INDEX `docs_embedding` ON `docs` (`embedding`) STORING (`title`) WHERE `embedding` IS NOT NULL OPTIONS (distance_type = 'DOT_PRODUCT', tree_depth = 3, num_leaves = 10000, num_branches = 100)"},
+		{"all options PG", constants.DIALECT_POSTGRESQL, ct, vi[1], "CREATE INDEX \"docs_embedding\" ON \"docs\" USING ScaNN (\"embedding\") INCLUDE (\"title\") WITH (distance_type = 'DOT_PRODUCT', tree_depth = 3, num_leaves = 10000, num_branches = 100) WHERE \"embedding\" IS NOT NULL"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.index.PrintVectorIndex(tc.ct, Config{ProtectIds: true, SpDialect: tc.spDialect}))
+		})
+	}
+}
+
 func TestPrintForeignKey(t *testing.T) {
 	fk := []Foreignkey{
 		{
@@ -671,6 +819,27 @@ func TestPrintForeignKey(t *testing.T) {
 	}
 }
 
+func TestPrintAddColumn(t *testing.T) {
+	cd := ColumnDef{Name: "email", T: Type{Name: String, Len: 100}, NotNull: true}
+	assert.Equal(t, "ALTER TABLE `users` ADD COLUMN `email` STRING(100) NOT NULL", cd.PrintAddColumn("users", Config{ProtectIds: true}))
+	assert.Equal(t, "ALTER TABLE \"users\" ADD COLUMN \"email\" VARCHAR(100) NOT NULL", cd.PrintAddColumn("users", Config{ProtectIds: true, SpDialect: constants.DIALECT_POSTGRESQL}))
+}
+
+func TestPrintDropTable(t *testing.T) {
+	ct := CreateTable{
+		Name:          "orders",
+		Indexes:       []CreateIndex{{Name: "idx_user"}},
+		VectorIndexes: []VectorIndex{{Name: "idx_embedding"}},
+		ForeignKeys:   []Foreignkey{{Name: "fk_user"}, {}},
+	}
+	assert.Equal(t, []string{
+		"DROP INDEX `idx_user`",
+		"DROP VECTOR INDEX `idx_embedding`",
+		"ALTER TABLE `orders` DROP CONSTRAINT `fk_user`",
+		"DROP TABLE `orders`",
+	}, ct.PrintDropTable(Config{ProtectIds: true}))
+}
+
 func TestPrintForeignKeyAlterTable(t *testing.T) {
 	spannerSchema := map[string]CreateTable{
 		"t1": {
@@ -1515,6 +1684,32 @@ func TestGetSortedTableIdsBySpName(t *testing.T) {
 			},
 			expected: []string{"table_id_1"},
 		},
+		// Test Case 6: Schema with a cycle of interleaved tables
+		{
+			description: "Schema with a cycle of interleaved tables",
+			schema: Schema{
+				"table_id_1": CreateTable{
+					Name: "Table1",
+					Id:   "table_id_1",
+				},
+				"table_id_2": CreateTable{
+					Name:        "Table2",
+					Id:          "table_id_2",
+					ParentTable: InterleavedParent{Id: "table_id_3", InterleaveType: "IN PARENT"},
+				},
+				"table_id_3": CreateTable{
+					Name:        "Table3",
+					Id:          "table_id_3",
+					ParentTable: InterleavedParent{Id: "table_id_2", InterleaveType: "IN PARENT"},
+				},
+				"table_id_4": CreateTable{
+					Name:        "Table4",
+					Id:          "table_id_4",
+					ParentTable: InterleavedParent{Id: "table_id_3", InterleaveType: "IN"},
+				},
+			},
+			expected: []string{"table_id_1", "table_id_2", "table_id_3", "table_id_4"},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1770,3 +1965,61 @@ func TestPGPrintGeneratedColumn(t *testing.T) {
 		assert.Equal(t, tc.expected, tc.gc.PGPrintGeneratedColumn(tc.ty), tc.desc)
 	}
 }
+
+func TestGetAccessControlDDL(t *testing.T) {
+	s := Schema{
+		"t1": {
+			Name:   "orders",
+			Id:     "t1",
+			ColIds: []string{"c1", "c2"},
+			ColDefs: map[string]ColumnDef{
+				"c1": {Name: "id", Id: "c1", T: Type{Name: Int64}},
+				"c2": {Name: "status", Id: "c2", T: Type{Name: String, Len: MaxLength}},
+			},
+		},
+	}
+	roles := map[string]CreateRole{
+		"r1": {Id: "r1", Name: "reader"},
+		"r2": {Id: "r2", Name: "app"},
+	}
+	grants := []Grant{
+		{Privileges: []string{"SELECT"}, TableId: "t1", GranteeIds: []string{"r1"}},
+		{Privileges: []string{"SELECT", "UPDATE"}, TableId: "t1", ColIds: []string{"c2"}, GranteeIds: []string{"r2"}},
+		{RoleId: "r1", GranteeIds: []string{"r2"}},
+		// Dropped tables, columns and roles are skipped.
+		{Privileges: []string{"DELETE"}, TableId: "t2", GranteeIds: []string{"r2"}},
+		{Privileges: []string{"UPDATE"}, TableId: "t1", ColIds: []string{"c3"}, GranteeIds: []string{"r2"}},
+		{RoleId: "r3", GranteeIds: []string{"r2"}},
+	}
+	assert.Equal(t, []string{
+		"CREATE ROLE app",
+		"CREATE ROLE reader",
+		"GRANT SELECT ON TABLE orders TO ROLE reader",
+		"GRANT SELECT(status), UPDATE(status) ON TABLE orders TO ROLE app",
+		"GRANT ROLE reader TO ROLE app",
+	}, GetAccessControlDDL(Config{}, s, roles, grants))
+	assert.Equal(t, []string{
+		"CREATE ROLE app",
+		"CREATE ROLE reader",
+		"GRANT SELECT ON TABLE \"orders\" TO reader",
+		"GRANT SELECT(\"status\"), UPDATE(\"status\") ON TABLE \"orders\" TO app",
+		"GRANT reader TO app",
+	}, GetAccessControlDDL(Config{ProtectIds: true, SpDialect: constants.DIALECT_POSTGRESQL}, s, roles, grants))
+}
+
+func TestGetViewDDL(t *testing.T) {
+	views := map[string]CreateView{
+		"v1": {Id: "v1", Name: "active_orders", Query: "SELECT id FROM recent_orders WHERE status = 'active';"},
+		"v2": {Id: "v2", Name: "recent_orders", Query: "SELECT id, status FROM orders WHERE created > '2024-01-01'"},
+		"v3": {Id: "v3", Name: "big_orders", Query: "SELECT id FROM orders WHERE total > 1000"},
+	}
+	// Views come after the views they refer to.
+	assert.Equal(t, []string{
+		"CREATE VIEW big_orders SQL SECURITY INVOKER AS SELECT id FROM orders WHERE total > 1000",
+		"CREATE VIEW recent_orders SQL SECURITY INVOKER AS SELECT id, status FROM orders WHERE created > '2024-01-01'",
+		"CREATE VIEW active_orders SQL SECURITY INVOKER AS SELECT id FROM recent_orders WHERE status = 'active'",
+	}, GetViewDDL(Config{}, views))
+	assert.Equal(t, []string{
+		"CREATE VIEW \"big_orders\" SQL SECURITY INVOKER AS SELECT id FROM orders WHERE total > 1000",
+	}, GetViewDDL(Config{ProtectIds: true, SpDialect: constants.DIALECT_POSTGRESQL}, map[string]CreateView{"v3": views["v3"]}))
+}
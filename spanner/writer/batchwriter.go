@@ -22,6 +22,7 @@ import (
 	"fmt"
 	spannerclient "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients/spanner/client"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -29,6 +30,7 @@ import (
 
 	sp "cloud.google.com/go/spanner"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"google.golang.org/grpc/codes"
 )
 
 // Parameters used to control building batches to write to Spanner.
@@ -39,12 +41,23 @@ import (
 const (
 	countThreshold = 70 * 1000    // Spanner per-operation limit is 80,000.
 	byteThreshold  = 20 * 1 << 20 // Spanner per-operation limit is 100MB.
+	// Rows attached to their interleaving parent row are kept in its batch
+	// beyond the thresholds, up to these limits.
+	countLimit = 79 * 1000
+	byteLimit  = 90 * 1 << 20
 )
 
+// DefaultDeferLimit is the default number of passes over rows which
+// failed because a referenced row was missing.
+const DefaultDeferLimit = 3
+
 // BatchWriter accumulates rows of data (via AddRow) and assembles them
 // into batches that it asynchronously writes to Spanner.  Rows are
 // written to Spanner using insert semantics i.e. if a row already exists
-// in the database, the row will fail with error 'AlreadyExists'.  If
+// in the database, the row will fail with error 'AlreadyExists'. Writes
+// failing with a transient error are retried with insert semantics too; a
+// row failing with 'AlreadyExists' after a write of it timed out or was
+// unavailable, and so may have been committed, is counted as written.  If
 // Spanner returns an error for a batch, BatchWriter splits the batch
 // into smaller chunks to retry, as it attempts to isolate which row(s)
 // in a batch is bad.  BatchWriter respects Spanner's limits on byte size
@@ -54,22 +67,37 @@ const (
 // be active at any time.  See ExampleBatchWriter (batchwriter_test.go)
 // for sample usage code.
 type BatchWriter struct {
-	rows       []*row                     // Buffered rows.
-	rBytes     int64                      // Estimate of bytes for buffered rows.
-	rCount     int64                      // Mutation count for buffered rows.
-	write      func([]*sp.Mutation) error // Typically a closure that calls client.Apply, but structured this way for testing.
-	wg         sync.WaitGroup             // Tracks in-progress writes.
-	writeLimit int64                      // Limit on number of in-progress writes.
-	bytesLimit int64                      // Limit on bytes buffered. AddRow blocks if rBytes exceeded this value.
-	retryLimit int64                      // Limit on retries.
-	verbose    bool                       // If true, print out messages about each write batch.
-	async      asyncState
+	rows        []*row                     // Buffered rows.
+	rBytes      int64                      // Estimate of bytes for buffered rows.
+	rCount      int64                      // Mutation count for buffered rows.
+	write       func([]*sp.Mutation) error // Typically a closure that calls client.Apply, but structured this way for testing.
+	wg          sync.WaitGroup             // Tracks in-progress writes.
+	writeLimit  int64                      // Limit on number of in-progress writes.
+	bytesLimit  int64                      // Limit on bytes buffered. AddRow blocks if rBytes exceeded this value.
+	retryLimit  int64                      // Limit on retries.
+	verbose     bool                       // If true, print out messages about each write batch.
+	onDropped   func(table string, cols []string, vals []interface{}, err error)
+	onWritten   func(table string, n int64)
+	deferLimit  int         // Limit on passes over rows deferred due to referential errors.
+	retryPolicy RetryPolicy // Retries of writes failing with transient errors.
+	breaker     circuitBreaker
+	async       asyncState
+
+	// Fields ordering the writes of interleaved tables, accessed by AddRow
+	// and Flush only.
+	interleaved   map[string]InterleavedTable  // Interleaved tables, keyed by name.
+	combine       bool                         // If true, rows are held until their parent row is added.
+	parentKeyCols map[string][]string          // Primary key columns of parent tables, keyed by name.
+	parentKeys    map[string]map[string]bool   // Keys of the rows added to each parent table.
+	held          map[string]map[string][]*row // Rows held until the parent row with their key is added.
+	heldBytes     int64                        // Estimate of bytes for held rows.
 }
 
 type row struct {
-	table string
-	cols  []string
-	vals  []interface{}
+	table    string
+	cols     []string
+	vals     []interface{}
+	attached bool // Row written in the batch of its interleaving parent row.
 }
 
 // Fields in this struct are modified asynchronously e.g. by go routines writing
@@ -87,6 +115,14 @@ type asyncState struct {
 	sampleBadRows      []*row           // A sample of rows that generated errors; protected by lock.
 	sampleBadRowsBytes int64            // Estimate of bytes for sampleBadRows; protected by lock.
 	droppedRows        map[string]int64 // Count of dropped rows, broken down by table.
+	deferredRows       []deferredRow    // Rows which failed with a referential error; protected by lock.
+}
+
+// deferredRow is a row whose write failed because a row it references was
+// missing, along with the error of the last attempt.
+type deferredRow struct {
+	r   *row
+	err error
 }
 
 // BatchWriterConfig specifies parameters for configuring BatchWriter.
@@ -96,38 +132,84 @@ type BatchWriterConfig struct {
 	RetryLimit int64                      // Limit on retries.
 	Write      func([]*sp.Mutation) error // Function to call to write to Spanner (typically a closure that calls client.Apply).
 	Verbose    bool                       // If true, print out messages about each write batch.
+	// OnDroppedRow, if set, is called for every row that is not written to
+	// Spanner, with the error of the failed write. It must be thread-safe.
+	OnDroppedRow func(table string, cols []string, vals []interface{}, err error)
+	// OnWrittenRows, if set, is called after every successful write with the
+	// number of rows written to each table. It must be thread-safe.
+	OnWrittenRows func(table string, n int64)
+	// DeferLimit is the number of passes RetryDeferredRows makes over rows
+	// which failed because a referenced row (an interleaving parent or a
+	// foreign key target) was missing. Zero drops such rows immediately.
+	DeferLimit int
+	// RetryPolicy controls the retries of writes failing with transient
+	// errors, such as UNAVAILABLE or ABORTED. The zero value doesn't retry.
+	RetryPolicy RetryPolicy
+	// CircuitBreaker pauses all writes while Spanner is overloaded. The zero
+	// value disables it.
+	CircuitBreaker CircuitBreakerConfig
+	// Interleaved lists the tables interleaved in another table: the rows of
+	// each write are ordered parents first.
+	Interleaved map[string]InterleavedTable
+	// CombineInterleaved holds the rows of interleaved tables until their
+	// parent row is added, to write them in the same batch. It keeps the
+	// keys of the rows of parent tables in memory.
+	CombineInterleaved bool
 }
 
 // NewBatchWriter returns a new BatchWriter with parameters defined by config.
 func NewBatchWriter(config BatchWriterConfig) *BatchWriter {
-	return &BatchWriter{
-		write:      config.Write,
-		writeLimit: config.WriteLimit,
-		bytesLimit: config.BytesLimit,
-		retryLimit: config.RetryLimit,
-		verbose:    config.Verbose,
+	bw := &BatchWriter{
+		write:       config.Write,
+		writeLimit:  config.WriteLimit,
+		bytesLimit:  config.BytesLimit,
+		retryLimit:  config.RetryLimit,
+		verbose:     config.Verbose,
+		onDropped:   config.OnDroppedRow,
+		onWritten:   config.OnWrittenRows,
+		deferLimit:  config.DeferLimit,
+		retryPolicy: config.RetryPolicy,
+		breaker:     circuitBreaker{config: config.CircuitBreaker},
 		async: asyncState{
 			errors:      make(map[string]int64),
 			droppedRows: make(map[string]int64),
 		},
+		interleaved:   config.Interleaved,
+		combine:       config.CombineInterleaved,
+		parentKeyCols: make(map[string][]string),
+		parentKeys:    make(map[string]map[string]bool),
+		held:          make(map[string]map[string][]*row),
+	}
+	for _, t := range config.Interleaved {
+		bw.parentKeyCols[t.Parent] = t.ParentKey
+		bw.parentKeys[t.Parent] = make(map[string]bool)
 	}
+	return bw
 }
 
 // AddRow appends a new row of data to bw's buffer of rows. Depending on the
 // state of BatchWriter, AddRow may immediately return, or it may initiate writes,
 // or it may block (waiting for some of the writes already in progress to
-// complete) and then initiate writes.
+// complete) and then initiate writes. Values set to internal.CommitTimestamp
+// are written as the Spanner commit timestamp.
 func (bw *BatchWriter) AddRow(table string, cols []string, vals []interface{}) {
-	r := &row{table, cols, vals}
-	bw.rows = append(bw.rows, r)
-	bw.rBytes += byteSize(r)
-	bw.rCount += int64(len(r.cols))
+	for i := range vals {
+		if vals[i] == internal.CommitTimestamp {
+			vals[i] = sp.CommitTimestamp
+		}
+	}
+	r := &row{table: table, cols: cols, vals: vals}
+	if bw.combine && bw.holdRow(r) {
+		return
+	}
+	bw.bufferRow(r)
 	bw.writeData()
 }
 
 // Flush initiates writes to Spanner of all buffered rows of data, and waits
 // for them to complete.
 func (bw *BatchWriter) Flush() {
+	bw.releaseHeld()
 	for len(bw.rows) > 0 {
 		if atomic.LoadInt64(&bw.async.writes) < bw.writeLimit {
 			m, count, bytes := bw.getBatch()
@@ -196,8 +278,10 @@ func (bw *BatchWriter) getBadRowsForTest() []*row {
 }
 
 // getBatch returns a slice of data from the front of bw.rows.  The slice
-// returned is the largest one not exceeding countThreshold and byteThreshold.
+// returned is the largest one not exceeding countThreshold and byteThreshold,
+// its rows ordered parents first.
 func (bw *BatchWriter) getBatch() (rows []*row, count int64, bytes int64) {
+	defer func() { bw.sortByDepth(rows) }()
 	for i := range bw.rows {
 		c := count + int64(len(bw.rows[i].cols))
 		b := bytes + byteSize(bw.rows[i])
@@ -205,7 +289,11 @@ func (bw *BatchWriter) getBatch() (rows []*row, count int64, bytes int64) {
 		// we have at least one row. If a single row puts us over the
 		// thresholds, there's not much we can do: we just try sending it to Spanner
 		// (it might succeed, since our thresholds are conservative).
-		if (c >= countThreshold || b >= byteThreshold) && len(rows) >= 1 {
+		over := c >= countThreshold || b >= byteThreshold
+		if over && bw.rows[i].attached && c < countLimit && b < byteLimit {
+			over = false
+		}
+		if over && len(rows) >= 1 {
 			bw.rCount -= count
 			bw.rBytes -= bytes
 			bw.rows = bw.rows[i:]
@@ -233,7 +321,18 @@ func (bw *BatchWriter) errorStats(rows []*row, err error, retry bool) {
 	if retry {
 		return
 	}
-	// All rows in r will be dropped.
+	if len(rows) == 1 && bw.deferLimit > 0 && isReferentialError(err) {
+		// The row may reference a row which hasn't been written yet. Retry it
+		// once all rows have been written.
+		bw.async.deferredRows = append(bw.async.deferredRows, deferredRow{r: rows[0], err: err})
+		return
+	}
+	bw.dropRows(rows, err)
+}
+
+// dropRows records that rows won't be written to Spanner. Callers must hold
+// bw.async.lock.
+func (bw *BatchWriter) dropRows(rows []*row, err error) {
 	if len(rows) == 1 {
 		// This is a confirmed bad row: add it to the badRows list.
 		r := rows[0]
@@ -246,18 +345,76 @@ func (bw *BatchWriter) errorStats(rows []*row, err error, retry bool) {
 	}
 	for _, x := range rows {
 		bw.async.droppedRows[x.table]++
+		if bw.onDropped != nil {
+			bw.onDropped(x.table, x.cols, x.vals, err)
+		}
 	}
-	return
 }
 
-// Note: doWriteAndHandleErrors must be thread-safe because it is run
-// inside a go routine.
-func (bw *BatchWriter) doWriteAndHandleErrors(rows []*row) {
-	var m []*sp.Mutation
-	for _, x := range rows {
-		m = append(m, sp.Insert(x.table, x.cols, x.vals))
+// RetryDeferredRows re-attempts the rows which failed because a row they
+// reference, such as an interleaving parent or a foreign key target, was
+// missing at the time of the write. It should be called after the final
+// Flush, once all tables have been written. Rows are retried one at a time,
+// in passes, until all succeed, a pass makes no progress or the pass limit is
+// reached. Rows that still fail are dropped with a referential diagnostic.
+func (bw *BatchWriter) RetryDeferredRows() {
+	bw.wg.Wait()
+	bw.async.lock.Lock()
+	pending := bw.async.deferredRows
+	bw.async.deferredRows = nil
+	bw.async.lock.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+	logger.Log.Info(fmt.Sprintf("Retrying %d rows which failed due to missing referenced rows\n", len(pending)))
+	passes := 0
+	for passes < bw.deferLimit && len(pending) > 0 {
+		passes++
+		var failed []deferredRow
+		for _, d := range pending {
+			if err := bw.writeWithRetry([]*row{d.r}, newWriteAttempts()); err != nil {
+				failed = append(failed, deferredRow{r: d.r, err: err})
+			} else {
+				bw.reportWritten([]*row{d.r})
+			}
+		}
+		progress := len(failed) < len(pending)
+		pending = failed
+		if !progress {
+			break
+		}
+	}
+	bw.async.lock.Lock()
+	defer bw.async.lock.Unlock()
+	for _, d := range pending {
+		err := fmt.Errorf("row in table %s still references a missing row after %d deferred retries, check that the referenced parent or foreign key row exists in the source: %v", d.r.table, passes, d.err)
+		bw.dropRows([]*row{d.r}, err)
 	}
-	if err := bw.write(m); err != nil {
+}
+
+// isReferentialError returns true if err is caused by a write referencing a
+// row which doesn't exist: a missing parent row of an interleaved table, or a
+// foreign key violation.
+func isReferentialError(err error) bool {
+	switch sp.ErrCode(err) {
+	case codes.NotFound:
+		return strings.Contains(err.Error(), "Parent row")
+	case codes.FailedPrecondition:
+		return strings.Contains(err.Error(), "Foreign key")
+	}
+	return false
+}
+
+// Note: doWriteAndHandleErrors must be thread-safe because it is run
+// inside a go routine. Each chunk written to isolate bad rows gets its own
+// retry budget.
+func (bw *BatchWriter) doWriteAndHandleErrors(rows []*row, attempts *writeAttempts) {
+	if err := bw.writeWithRetry(rows, attempts); err != nil {
+		if writtenByFailedWrite(rows, err, attempts) {
+			logger.Log.Debug(fmt.Sprintf("Row of table %s was written by an earlier write which failed: %v\n", rows[0].table, err))
+			bw.reportWritten(rows)
+			return
+		}
 		hitRetryLimit := atomic.LoadInt64(&bw.async.retries) >= bw.retryLimit
 		retry := len(rows) > 1 && !hitRetryLimit
 		bw.errorStats(rows, err, retry)
@@ -284,8 +441,29 @@ func (bw *BatchWriter) doWriteAndHandleErrors(rows []*row) {
 		}
 		for i := 0; i < len(rows); i += k {
 			atomic.AddInt64(&bw.async.retries, 1)
-			bw.doWriteAndHandleErrors(rows[i:min(i+k, len(rows))])
+			bw.doWriteAndHandleErrors(rows[i:min(i+k, len(rows))], attempts.split())
 		}
+		return
+	}
+	bw.reportWritten(rows)
+}
+
+// reportWritten calls onWritten with the number of rows of each table
+// written.
+func (bw *BatchWriter) reportWritten(rows []*row) {
+	if bw.onWritten == nil {
+		return
+	}
+	counts := make(map[string]int64)
+	var tables []string
+	for _, r := range rows {
+		if counts[r.table] == 0 {
+			tables = append(tables, r.table)
+		}
+		counts[r.table]++
+	}
+	for _, t := range tables {
+		bw.onWritten(t, counts[t])
 	}
 }
 
@@ -294,7 +472,7 @@ func (bw *BatchWriter) doWriteAndHandleErrors(rows []*row) {
 func (bw *BatchWriter) backgroundWrite(rows []*row) {
 	defer bw.wg.Done()
 	defer atomic.AddInt64(&bw.async.writes, -1)
-	bw.doWriteAndHandleErrors(rows)
+	bw.doWriteAndHandleErrors(rows, newWriteAttempts())
 }
 
 // startWrite initiates an asynchronous write of rows to Spanner.
@@ -347,11 +525,16 @@ func byteSize(r *row) int64 {
 func GetBatchWriterWithConfig(ctx context.Context, spannerClient spannerclient.SpannerClient, conv *internal.Conv) *BatchWriter {
 	// TODO: review these limits
 	config := BatchWriterConfig{
-		BytesLimit: 100 * 1000 * 1000,
-		WriteLimit: 2000,
-		RetryLimit: 1000,
-		Verbose:    internal.Verbose(),
+		BytesLimit:     100 * 1000 * 1000,
+		WriteLimit:     2000,
+		RetryLimit:     1000,
+		Verbose:        internal.Verbose(),
+		DeferLimit:     DefaultDeferLimit,
+		RetryPolicy:    DefaultRetryPolicy,
+		CircuitBreaker: DefaultCircuitBreakerConfig,
 	}
+	config.OnDroppedRow = conv.CollectBadWrite
+	config.Interleaved = InterleavedTables(conv.SpSchema)
 
 	rows := int64(0)
 	config.Write = func(m []*sp.Mutation) error {
@@ -370,6 +553,7 @@ func GetBatchWriterWithConfig(ctx context.Context, spannerClient spannerclient.S
 			batchWriter.AddRow(table, cols, vals)
 		})
 	conv.DataFlush = func() {
+		conv.FlushMaskedRows()
 		batchWriter.Flush()
 	}
 	return batchWriter
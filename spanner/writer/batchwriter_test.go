@@ -31,6 +31,8 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func init() {
@@ -102,6 +104,111 @@ func TestFlush(t *testing.T) {
 	}
 }
 
+func TestOnDroppedRow(t *testing.T) {
+	var dropped []*row
+	var reasons []string
+	mutex := &sync.Mutex{}
+	config := BatchWriterConfig{
+		BytesLimit: 100 << 20,
+		WriteLimit: 40,
+		RetryLimit: 1000,
+		Write: func(m []*sp.Mutation) error {
+			for _, x := range m {
+				if reflect.DeepEqual(x, sp.Insert("test", []string{"col1"}, []interface{}{"bad"})) {
+					return errors.New("bad data")
+				}
+			}
+			return nil
+		},
+		OnDroppedRow: func(table string, cols []string, vals []interface{}, err error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			dropped = append(dropped, &row{table: table, cols: cols, vals: vals})
+			reasons = append(reasons, err.Error())
+		},
+	}
+	bw := NewBatchWriter(config)
+	bw.AddRow("test", []string{"col1"}, []interface{}{"good"})
+	bw.AddRow("test", []string{"col1"}, []interface{}{"bad"})
+	bw.Flush()
+	assert.Equal(t, []*row{{table: "test", cols: []string{"col1"}, vals: []interface{}{"bad"}}}, dropped)
+	assert.Equal(t, []string{"bad data"}, reasons)
+}
+
+func TestOnWrittenRows(t *testing.T) {
+	written := make(map[string]int64)
+	mutex := &sync.Mutex{}
+	config := BatchWriterConfig{
+		BytesLimit: 100 << 20,
+		WriteLimit: 40,
+		RetryLimit: 1000,
+		Write: func(m []*sp.Mutation) error {
+			for _, x := range m {
+				if reflect.DeepEqual(x, sp.Insert("t1", []string{"col1"}, []interface{}{"bad"})) {
+					return errors.New("bad data")
+				}
+			}
+			return nil
+		},
+		OnWrittenRows: func(table string, n int64) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			written[table] += n
+		},
+	}
+	bw := NewBatchWriter(config)
+	bw.AddRow("t1", []string{"col1"}, []interface{}{"good"})
+	bw.AddRow("t1", []string{"col1"}, []interface{}{"bad"})
+	bw.AddRow("t2", []string{"col1"}, []interface{}{"good"})
+	bw.Flush()
+	assert.Equal(t, map[string]int64{"t1": 1, "t2": 1}, written)
+}
+
+func TestRetryDeferredRows(t *testing.T) {
+	parent := sp.Insert("parent", []string{"id"}, []interface{}{int64(1)})
+	child := sp.Insert("child", []string{"id"}, []interface{}{int64(1)})
+	orphan := sp.Insert("child", []string{"id"}, []interface{}{int64(2)})
+	parentWritten := false
+	mutex := &sync.Mutex{}
+	var dropped []string
+	config := BatchWriterConfig{
+		BytesLimit: 100 << 20,
+		WriteLimit: 40,
+		RetryLimit: 1000,
+		DeferLimit: DefaultDeferLimit,
+		Write: func(m []*sp.Mutation) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, x := range m {
+				if reflect.DeepEqual(x, orphan) || (reflect.DeepEqual(x, child) && !parentWritten) {
+					return status.Errorf(codes.NotFound, "Parent row for row [%d] in table child is missing. Row cannot be written.", len(m))
+				}
+			}
+			for _, x := range m {
+				if reflect.DeepEqual(x, parent) {
+					parentWritten = true
+				}
+			}
+			return nil
+		},
+		OnDroppedRow: func(table string, cols []string, vals []interface{}, err error) {
+			dropped = append(dropped, fmt.Sprintf("%s %v: %v", table, vals, err))
+		},
+	}
+	bw := NewBatchWriter(config)
+	// The child is written before its parent, and the orphan has no parent.
+	bw.AddRow("child", []string{"id"}, []interface{}{int64(1)})
+	bw.AddRow("child", []string{"id"}, []interface{}{int64(2)})
+	bw.AddRow("parent", []string{"id"}, []interface{}{int64(1)})
+	bw.Flush()
+	assert.Empty(t, bw.DroppedRowsByTable())
+	bw.RetryDeferredRows()
+	assert.Equal(t, map[string]int64{"child": 1}, bw.DroppedRowsByTable())
+	assert.Equal(t, 1, len(dropped))
+	assert.Contains(t, dropped[0], "child [2]: row in table child still references a missing row after 2 deferred retries")
+	assert.Contains(t, dropped[0], "Parent row for row [1] in table child is missing")
+}
+
 func TestDroppedRowsByTable(t *testing.T) {
 	bw := NewBatchWriter(BatchWriterConfig{})
 	bw.async.lock.Lock()
@@ -118,8 +225,8 @@ func TestSampleBadRows(t *testing.T) {
 	bw := NewBatchWriter(BatchWriterConfig{})
 	bw.async.lock.Lock()
 	bw.async.sampleBadRows = []*row{
-		&row{"test", []string{"col1", "col2"}, []interface{}{"a", int64(42)}},
-		&row{"test", []string{"col1", "col2"}, []interface{}{"b", int64(6)}},
+		&row{table: "test", cols: []string{"col1", "col2"}, vals: []interface{}{"a", int64(42)}},
+		&row{table: "test", cols: []string{"col1", "col2"}, vals: []interface{}{"b", int64(6)}},
 	}
 	bw.async.lock.Unlock()
 	l := bw.SampleBadRows(1)
@@ -173,7 +280,7 @@ func generateRows(count int, size int) ([]*row, int) {
 	for i := 0; i < count; i++ {
 		// vals[0] serves as a unique id for each row.
 		vals := []interface{}{i, val}
-		r = append(r, &row{"table", cols, vals})
+		r = append(r, &row{table: "table", cols: cols, vals: vals})
 	}
 	// Find the max number of rows in a write for the (fixed sized)
 	// rows generated in this test data.
@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+)
+
+// InterleavedTable is a table interleaved in a parent table. Its rows can
+// only be written once the parent row they belong to exists.
+type InterleavedTable struct {
+	Parent string // Name of the parent table.
+	// ParentKey is the primary key columns of the parent table, which start
+	// the primary key of the table.
+	ParentKey []string
+}
+
+// InterleavedTables returns the tables of schema interleaved in another
+// table, keyed by name.
+func InterleavedTables(schema ddl.Schema) map[string]InterleavedTable {
+	tables := make(map[string]InterleavedTable)
+	for _, t := range schema {
+		parent, ok := schema[t.ParentTable.Id]
+		if t.ParentTable.Id == "" || !ok {
+			continue
+		}
+		pks := append([]ddl.IndexKey{}, parent.PrimaryKeys...)
+		sort.SliceStable(pks, func(i, j int) bool { return pks[i].Order < pks[j].Order })
+		var key []string
+		for _, pk := range pks {
+			key = append(key, parent.ColDefs[pk.ColId].Name)
+		}
+		tables[t.Name] = InterleavedTable{Parent: parent.Name, ParentKey: key}
+	}
+	return tables
+}
+
+// depth returns the number of ancestors of table.
+func (bw *BatchWriter) depth(table string) int {
+	d := 0
+	for t, ok := bw.interleaved[table]; ok && d < len(bw.interleaved); t, ok = bw.interleaved[t.Parent] {
+		d++
+	}
+	return d
+}
+
+// sortByDepth orders rows so that the rows of parent tables come before the
+// rows of the tables interleaved in them, since Spanner applies the
+// mutations of a write in order.
+func (bw *BatchWriter) sortByDepth(rows []*row) {
+	if len(bw.interleaved) == 0 {
+		return
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return bw.depth(rows[i].table) < bw.depth(rows[j].table) })
+}
+
+// rowKey returns the values of the columns cols of r, as a map key.
+func rowKey(r *row, cols []string) (string, bool) {
+	var b strings.Builder
+	for _, col := range cols {
+		i := 0
+		for i < len(r.cols) && r.cols[i] != col {
+			i++
+		}
+		if i == len(r.cols) {
+			return "", false
+		}
+		fmt.Fprintf(&b, "%v\x00", r.vals[i])
+	}
+	return b.String(), true
+}
+
+// holdRow holds r, a row of an interleaved table, if its parent row hasn't
+// been added yet, so that it is written right after it. It returns false if
+// r isn't held.
+func (bw *BatchWriter) holdRow(r *row) bool {
+	t, ok := bw.interleaved[r.table]
+	if !ok {
+		return false
+	}
+	key, ok := rowKey(r, t.ParentKey)
+	if !ok || bw.parentKeys[t.Parent][key] {
+		return false
+	}
+	if bw.held[t.Parent] == nil {
+		bw.held[t.Parent] = make(map[string][]*row)
+	}
+	bw.held[t.Parent][key] = append(bw.held[t.Parent][key], r)
+	bw.heldBytes += byteSize(r)
+	if bw.heldBytes > bw.bytesLimit {
+		// The parent rows may never come: write the rows held, which are
+		// retried once all tables are written.
+		logger.Log.Debug(fmt.Sprintf("Writing %d bytes of rows held for their interleaving parent rows\n", bw.heldBytes))
+		bw.releaseHeld()
+	}
+	return true
+}
+
+// bufferRow appends r to the rows to write, followed by the rows held for
+// it if it is the row of a parent table.
+func (bw *BatchWriter) bufferRow(r *row) {
+	bw.rows = append(bw.rows, r)
+	bw.rBytes += byteSize(r)
+	bw.rCount += int64(len(r.cols))
+	keyCols, ok := bw.parentKeyCols[r.table]
+	if !bw.combine || !ok {
+		return
+	}
+	key, ok := rowKey(r, keyCols)
+	if !ok {
+		return
+	}
+	bw.parentKeys[r.table][key] = true
+	children := bw.held[r.table][key]
+	delete(bw.held[r.table], key)
+	for _, c := range children {
+		bw.heldBytes -= byteSize(c)
+		c.attached = true
+		bw.bufferRow(c)
+	}
+}
+
+// releaseHeld appends all the rows held to the rows to write, parents
+// first.
+func (bw *BatchWriter) releaseHeld() {
+	var rows []*row
+	for parent, keys := range bw.held {
+		for _, l := range keys {
+			rows = append(rows, l...)
+		}
+		delete(bw.held, parent)
+	}
+	bw.heldBytes = 0
+	bw.sortByDepth(rows)
+	for _, r := range rows {
+		bw.rows = append(bw.rows, r)
+		bw.rBytes += byteSize(r)
+		bw.rCount += int64(len(r.cols))
+	}
+}
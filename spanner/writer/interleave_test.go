@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"testing"
+
+	sp "cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/stretchr/testify/assert"
+)
+
+func interleavedSchema() ddl.Schema {
+	return ddl.Schema{
+		"t1": {
+			Name:        "singers",
+			Id:          "t1",
+			ColDefs:     map[string]ddl.ColumnDef{"c1": {Name: "region"}, "c2": {Name: "singer_id"}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c2", Order: 2}, {ColId: "c1", Order: 1}},
+		},
+		"t2": {
+			Name:        "albums",
+			Id:          "t2",
+			ColDefs:     map[string]ddl.ColumnDef{"c3": {Name: "region"}, "c4": {Name: "singer_id"}, "c5": {Name: "album_id"}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c3", Order: 1}, {ColId: "c4", Order: 2}, {ColId: "c5", Order: 3}},
+			ParentTable: ddl.InterleavedParent{Id: "t1"},
+		},
+		"t3": {
+			Name:        "songs",
+			Id:          "t3",
+			ColDefs:     map[string]ddl.ColumnDef{"c6": {Name: "song_id"}},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c6", Order: 1}},
+			ParentTable: ddl.InterleavedParent{Id: "t2"},
+		},
+		"t4": {
+			Name: "labels",
+			Id:   "t4",
+		},
+	}
+}
+
+func TestInterleavedTables(t *testing.T) {
+	assert.Equal(t, map[string]InterleavedTable{
+		"albums": {Parent: "singers", ParentKey: []string{"region", "singer_id"}},
+		"songs":  {Parent: "albums", ParentKey: []string{"region", "singer_id", "album_id"}},
+	}, InterleavedTables(interleavedSchema()))
+}
+
+func newInterleavedBatchWriter(combine bool) *BatchWriter {
+	return NewBatchWriter(BatchWriterConfig{
+		BytesLimit:         100 << 20,
+		WriteLimit:         40,
+		RetryLimit:         1000,
+		Interleaved:        InterleavedTables(interleavedSchema()),
+		CombineInterleaved: combine,
+		Write:              func(m []*sp.Mutation) error { return nil },
+	})
+}
+
+func tables(rows []*row) []string {
+	var l []string
+	for _, r := range rows {
+		l = append(l, r.table)
+	}
+	return l
+}
+
+func TestGetBatch_Interleaved(t *testing.T) {
+	bw := newInterleavedBatchWriter(false)
+	for _, table := range []string{"songs", "labels", "albums", "singers", "songs"} {
+		bw.bufferRow(&row{table: table, cols: []string{"a"}, vals: []interface{}{1}})
+	}
+	rows, _, _ := bw.getBatch()
+	assert.Equal(t, []string{"labels", "singers", "albums", "songs", "songs"}, tables(rows))
+}
+
+func TestAddRow_CombineInterleaved(t *testing.T) {
+	bw := newInterleavedBatchWriter(true)
+	album := func(singer, album int64) *row {
+		return &row{table: "albums", cols: []string{"region", "singer_id", "album_id"}, vals: []interface{}{"eu", singer, album}}
+	}
+	singer := func(singer int64) *row {
+		return &row{table: "singers", cols: []string{"region", "singer_id"}, vals: []interface{}{"eu", singer}}
+	}
+
+	// Rows of albums whose singer wasn't added yet are held.
+	assert.True(t, bw.holdRow(album(1, 10)))
+	assert.True(t, bw.holdRow(album(2, 20)))
+	assert.True(t, bw.holdRow(album(1, 11)))
+	assert.Empty(t, bw.rows)
+
+	// Adding a singer writes its albums right after it.
+	bw.bufferRow(singer(1))
+	assert.Equal(t, []*row{singer(1), album(1, 10), album(1, 11)}, withoutAttached(bw.rows))
+	assert.True(t, bw.rows[1].attached)
+	assert.False(t, bw.holdRow(album(1, 12)))
+
+	// Rows never matched by a parent row are written by Flush.
+	bw.releaseHeld()
+	assert.Equal(t, []*row{album(2, 20)}, withoutAttached(bw.rows[3:]))
+	assert.Empty(t, bw.held)
+	assert.Equal(t, int64(0), bw.heldBytes)
+}
+
+// withoutAttached returns copies of rows with the attached field cleared.
+func withoutAttached(rows []*row) []*row {
+	var l []*row
+	for _, r := range rows {
+		c := *r
+		c.attached = false
+		l = append(l, &c)
+	}
+	return l
+}
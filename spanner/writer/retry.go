@@ -0,0 +1,236 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	sp "cloud.google.com/go/spanner"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"google.golang.org/grpc/codes"
+)
+
+// RetryPolicy controls how writes failing with a transient error, such as
+// an aborted transaction or an overloaded server, are retried as they are,
+// before BatchWriter falls back to splitting the batch to isolate bad rows.
+// Retries wait for an exponential backoff with full jitter: a random delay
+// up to InitialBackoff * Multiplier^attempt, capped at MaxBackoff. The zero
+// value doesn't retry.
+type RetryPolicy struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	MaxAttempts    int           // Limit on attempts of a write, including the first one.
+	Budget         time.Duration // Limit on the time spent retrying a write.
+}
+
+// DefaultRetryPolicy is the retry policy of data migrations.
+var DefaultRetryPolicy = RetryPolicy{
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+	Multiplier:     2,
+	MaxAttempts:    8,
+	Budget:         5 * time.Minute,
+}
+
+// backoff returns the delay before the retry following attempt, counted
+// from 0.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for i := 0; i < attempt && d < float64(p.MaxBackoff); i++ {
+		d *= p.Multiplier
+	}
+	if d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if d < 1 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d)))
+}
+
+// allow returns true if a write which started at start can be attempted
+// once more after attempts attempts.
+func (p RetryPolicy) allow(attempts int, start time.Time) bool {
+	return attempts < p.MaxAttempts && time.Since(start) < p.Budget
+}
+
+// isTransientError returns true if err may succeed when the same write is
+// retried, as opposed to errors caused by the data written.
+func isTransientError(err error) bool {
+	switch sp.ErrCode(err) {
+	case codes.Aborted, codes.Unavailable, codes.ResourceExhausted, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// isOverloadError returns true if err shows that Spanner can't keep up with
+// the writes, which then trip the circuit breaker.
+func isOverloadError(err error) bool {
+	switch sp.ErrCode(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+// CircuitBreakerConfig controls the circuit breaker shared by the writes of
+// a BatchWriter. The breaker opens after Threshold consecutive UNAVAILABLE or
+// RESOURCE_EXHAUSTED errors, pausing all writes for Cooldown. Writes then
+// resume automatically; the breaker opens again at the next such error
+// until a write succeeds. A zero Threshold disables the breaker.
+type CircuitBreakerConfig struct {
+	Threshold int
+	Cooldown  time.Duration
+}
+
+// DefaultCircuitBreakerConfig is the circuit breaker of data migrations.
+var DefaultCircuitBreakerConfig = CircuitBreakerConfig{
+	Threshold: 20,
+	Cooldown:  30 * time.Second,
+}
+
+// circuitBreaker pauses the writes of a BatchWriter while Spanner is
+// overloaded. It is safe for concurrent use.
+type circuitBreaker struct {
+	config    CircuitBreakerConfig
+	mu        sync.Mutex
+	failures  int       // Consecutive overload errors; protected by mu.
+	openUntil time.Time // Writes wait until openUntil; protected by mu.
+	trips     int64     // Number of times the breaker opened; protected by mu.
+}
+
+// wait blocks while the breaker is open.
+func (cb *circuitBreaker) wait() {
+	for {
+		cb.mu.Lock()
+		d := time.Until(cb.openUntil)
+		cb.mu.Unlock()
+		if d <= 0 {
+			return
+		}
+		time.Sleep(d)
+	}
+}
+
+// record updates the breaker with the result of a write.
+func (cb *circuitBreaker) record(err error) {
+	if cb.config.Threshold <= 0 {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if !isOverloadError(err) {
+		if err == nil {
+			cb.failures = 0
+		}
+		return
+	}
+	cb.failures++
+	if cb.failures < cb.config.Threshold || time.Now().Before(cb.openUntil) {
+		return
+	}
+	cb.openUntil = time.Now().Add(cb.config.Cooldown)
+	cb.trips++
+	// The next overload error opens the breaker again.
+	cb.failures = cb.config.Threshold - 1
+	logger.Log.Warn(fmt.Sprintf("Spanner is overloaded (%v): pausing all writes for %v\n", err, cb.config.Cooldown))
+}
+
+// isUncertainError returns true if err leaves the outcome of a write
+// unknown: the write may have been committed although it failed.
+func isUncertainError(err error) bool {
+	switch sp.ErrCode(err) {
+	case codes.DeadlineExceeded, codes.Unavailable:
+		return true
+	}
+	return false
+}
+
+// writeAttempts tracks the writes of a batch of rows, or of one of the
+// chunks it is split into to isolate bad rows, so that the retry policy
+// limits the retries of each write.
+type writeAttempts struct {
+	start     time.Time // Time of the first write.
+	retries   int       // Retries of transient errors so far.
+	uncertain bool      // If true, a failed write of the rows may have been committed.
+}
+
+func newWriteAttempts() *writeAttempts {
+	return &writeAttempts{start: time.Now()}
+}
+
+// split returns the attempts of a chunk of the rows of a: the chunk gets its
+// own retry budget, but its rows may still have been committed by a failed
+// write of a.
+func (a *writeAttempts) split() *writeAttempts {
+	return &writeAttempts{start: time.Now(), uncertain: a.uncertain}
+}
+
+// mutations returns the mutations inserting rows.
+func mutations(rows []*row) []*sp.Mutation {
+	var m []*sp.Mutation
+	for _, x := range rows {
+		m = append(m, sp.Insert(x.table, x.cols, x.vals))
+	}
+	return m
+}
+
+// writeWithRetry writes rows, retrying transient errors according to the
+// retry policy of bw, and waiting while the circuit breaker is open. Retries
+// are counted in a. Rows are always inserted, also on retries: a write whose
+// outcome is unknown is recorded in a, so that an ALREADY_EXISTS error of
+// its rows can be told apart from a duplicate row of the source.
+func (bw *BatchWriter) writeWithRetry(rows []*row, a *writeAttempts) error {
+	for {
+		bw.breaker.wait()
+		err := bw.write(mutations(rows))
+		bw.breaker.record(err)
+		if err == nil || !isTransientError(err) {
+			return err
+		}
+		if isUncertainError(err) {
+			a.uncertain = true
+		}
+		if !bw.retryPolicy.allow(a.retries+1, a.start) {
+			return err
+		}
+		d := bw.retryPolicy.backoff(a.retries)
+		a.retries++
+		logger.Log.Debug(fmt.Sprintf("Retrying write of %d rows in %v after transient error: %v\n", len(rows), d, err))
+		time.Sleep(d)
+	}
+}
+
+// writtenByFailedWrite returns true if err shows that rows were written by
+// an earlier write of them which failed with an unknown outcome. This is
+// only confirmed for a single row: ALREADY_EXISTS of a write of several rows
+// doesn't tell which of them exist, so they are split like for other errors
+// until each conflicting row is written on its own.
+func writtenByFailedWrite(rows []*row, err error, a *writeAttempts) bool {
+	return len(rows) == 1 && a.uncertain && sp.ErrCode(err) == codes.AlreadyExists
+}
+
+// BreakerTrips returns the number of times writes were paused because
+// Spanner was overloaded.
+func (bw *BatchWriter) BreakerTrips() int64 {
+	bw.breaker.mu.Lock()
+	defer bw.breaker.mu.Unlock()
+	return bw.breaker.trips
+}
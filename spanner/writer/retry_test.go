@@ -0,0 +1,212 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	sp "cloud.google.com/go/spanner"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var testRetryPolicy = RetryPolicy{
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	Multiplier:     2,
+	MaxAttempts:    3,
+	Budget:         time.Minute,
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2, MaxAttempts: 3, Budget: time.Second}
+	for attempt, limit := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		for i := 0; i < 20; i++ {
+			d := p.backoff(attempt)
+			assert.True(t, d >= 0 && d < limit, "attempt %d: %v", attempt, d)
+		}
+	}
+	assert.True(t, p.allow(1, time.Now()))
+	assert.False(t, RetryPolicy{}.allow(1, time.Now()))
+	assert.False(t, RetryPolicy{MaxAttempts: 3, Budget: time.Second}.allow(1, time.Now().Add(-2*time.Second)))
+}
+
+func TestWriteWithRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error // Errors of the successive attempts, then success.
+		policy    RetryPolicy
+		wantCalls int
+		wantErr   bool
+	}{
+		{"Transient errors are retried", []error{status.Error(codes.Unavailable, "unavailable"), status.Error(codes.Aborted, "aborted")}, testRetryPolicy, 3, false},
+		{"Attempts are limited", []error{status.Error(codes.Unavailable, "1"), status.Error(codes.Unavailable, "2"), status.Error(codes.Unavailable, "3")}, testRetryPolicy, 3, true},
+		{"Data errors aren't retried", []error{status.Error(codes.InvalidArgument, "bad value")}, testRetryPolicy, 1, true},
+		{"Other errors aren't retried", []error{errors.New("bad data")}, testRetryPolicy, 1, true},
+		{"Zero policy doesn't retry", []error{status.Error(codes.Unavailable, "unavailable")}, RetryPolicy{}, 1, true},
+	}
+	for _, tc := range tests {
+		calls := 0
+		bw := NewBatchWriter(BatchWriterConfig{
+			RetryPolicy: tc.policy,
+			Write: func(m []*sp.Mutation) error {
+				calls++
+				if calls <= len(tc.errs) {
+					return tc.errs[calls-1]
+				}
+				return nil
+			},
+		})
+		err := bw.writeWithRetry([]*row{{table: "t1", cols: []string{"col1"}, vals: []interface{}{"a"}}}, newWriteAttempts())
+		assert.Equal(t, tc.wantErr, err != nil, tc.name)
+		assert.Equal(t, tc.wantCalls, calls, tc.name)
+	}
+}
+
+func TestWriteWithRetry_Insert(t *testing.T) {
+	var writes [][]*sp.Mutation
+	bw := NewBatchWriter(BatchWriterConfig{
+		RetryPolicy: testRetryPolicy,
+		Write: func(m []*sp.Mutation) error {
+			writes = append(writes, m)
+			if len(writes) == 1 {
+				return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+			}
+			return nil
+		},
+	})
+	a := newWriteAttempts()
+	assert.Nil(t, bw.writeWithRetry([]*row{{table: "t1", cols: []string{"col1"}, vals: []interface{}{"a"}}}, a))
+	assert.Equal(t, [][]*sp.Mutation{
+		{sp.Insert("t1", []string{"col1"}, []interface{}{"a"})},
+		{sp.Insert("t1", []string{"col1"}, []interface{}{"a"})},
+	}, writes, "a write which may have been committed is retried with insert")
+	assert.True(t, a.uncertain)
+}
+
+func TestBatchWriter_AlreadyExistsAfterUncertainWrite(t *testing.T) {
+	tests := []struct {
+		name        string
+		errs        []error // Errors of the successive writes, then success.
+		wantWritten int64
+		wantDropped map[string]int64
+	}{
+		{"Row written by a write which timed out", []error{status.Error(codes.DeadlineExceeded, "deadline exceeded"), status.Error(codes.AlreadyExists, "row exists")}, 1, map[string]int64{}},
+		{"Row written by a write which was unavailable", []error{status.Error(codes.Unavailable, "unavailable"), status.Error(codes.AlreadyExists, "row exists")}, 1, map[string]int64{}},
+		{"Aborted write isn't committed", []error{status.Error(codes.Aborted, "aborted"), status.Error(codes.AlreadyExists, "row exists")}, 0, map[string]int64{"t1": 1}},
+		{"Duplicate row", []error{status.Error(codes.AlreadyExists, "row exists")}, 0, map[string]int64{"t1": 1}},
+	}
+	for _, tc := range tests {
+		calls := 0
+		var written int64
+		bw := NewBatchWriter(BatchWriterConfig{
+			BytesLimit:  100 << 20,
+			WriteLimit:  1,
+			RetryLimit:  1000,
+			RetryPolicy: testRetryPolicy,
+			Write: func(m []*sp.Mutation) error {
+				calls++
+				if calls <= len(tc.errs) {
+					return tc.errs[calls-1]
+				}
+				return nil
+			},
+			OnWrittenRows: func(table string, n int64) { written += n },
+		})
+		bw.AddRow("t1", []string{"col1"}, []interface{}{"a"})
+		bw.Flush()
+		assert.Equal(t, tc.wantWritten, written, tc.name)
+		assert.Equal(t, tc.wantDropped, bw.DroppedRowsByTable(), tc.name)
+	}
+}
+
+func TestBatchWriter_RetriesPerSplit(t *testing.T) {
+	var mutex sync.Mutex
+	calls := 0
+	bw := NewBatchWriter(BatchWriterConfig{
+		BytesLimit:  100 << 20,
+		WriteLimit:  1,
+		RetryLimit:  1000,
+		RetryPolicy: testRetryPolicy,
+		Write: func(m []*sp.Mutation) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			calls++
+			return status.Error(codes.Unavailable, "unavailable")
+		},
+	})
+	for i := 0; i < 5; i++ {
+		bw.AddRow("t1", []string{"col1"}, []interface{}{i})
+	}
+	bw.Flush()
+	assert.Equal(t, map[string]int64{"t1": 5}, bw.DroppedRowsByTable())
+	// The batch is written 3 times, then each of its 5 chunks 3 times too:
+	// each chunk has its own retry budget.
+	assert.Equal(t, 18, calls)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	cb := &circuitBreaker{config: CircuitBreakerConfig{Threshold: 2, Cooldown: 50 * time.Millisecond}}
+	overloaded := status.Error(codes.ResourceExhausted, "too many requests")
+	cb.record(overloaded)
+	cb.record(nil)
+	cb.record(overloaded)
+	start := time.Now()
+	cb.wait()
+	assert.True(t, time.Since(start) < 50*time.Millisecond, "a success resets the consecutive errors")
+
+	cb.record(overloaded)
+	cb.record(errors.New("bad data"))
+	assert.Equal(t, int64(1), cb.trips)
+	start = time.Now()
+	cb.wait()
+	assert.True(t, time.Since(start) >= 40*time.Millisecond, "writes pause while the breaker is open")
+
+	// Once resumed, the next overload error opens the breaker again.
+	cb.record(overloaded)
+	assert.Equal(t, int64(2), cb.trips)
+	cb.record(nil)
+	assert.Equal(t, 0, cb.failures)
+}
+
+func TestBatchWriter_CircuitBreaker(t *testing.T) {
+	var mutex sync.Mutex
+	calls := 0
+	bw := NewBatchWriter(BatchWriterConfig{
+		BytesLimit:     100 << 20,
+		WriteLimit:     1,
+		RetryLimit:     1000,
+		RetryPolicy:    testRetryPolicy,
+		CircuitBreaker: CircuitBreakerConfig{Threshold: 2, Cooldown: 10 * time.Millisecond},
+		Write: func(m []*sp.Mutation) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			calls++
+			if calls <= 2 {
+				return status.Error(codes.Unavailable, "unavailable")
+			}
+			return nil
+		},
+	})
+	bw.AddRow("t1", []string{"col1"}, []interface{}{"a"})
+	bw.Flush()
+	assert.Empty(t, bw.DroppedRowsByTable())
+	assert.Equal(t, int64(1), bw.BreakerTrips())
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,447 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package streaming
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	"cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Datastream reports how far behind the source a stream is through this metric.
+const datastreamFreshnessMetric = "datastream.googleapis.com/stream/freshness"
+
+// CutoverValidationQuery is a pair of queries run against the source and
+// Spanner before cutover. Each query must return a single value, and the
+// gate passes only when both sides return the same value. Values are
+// compared by type rather than by their text: numbers compare equal when
+// numerically equal, e.g. a MySQL DECIMAL 10.50 and a Spanner NUMERIC 10.5,
+// and timestamps when they denote the same instant.
+type CutoverValidationQuery struct {
+	Name        string `json:"name"`
+	SourceQuery string `json:"sourceQuery"`
+	TargetQuery string `json:"targetQuery"`
+}
+
+// CutoverOptions configures the gates checked and the actions taken by a cutover.
+type CutoverOptions struct {
+	MaxLag               time.Duration
+	ValidationQueries    []CutoverValidationQuery
+	PauseWritesStatement string
+	FlagFile             string
+	DryRun               bool
+}
+
+// CutoverDeps abstracts the calls a cutover makes to Datastream, the source and
+// Spanner so that the gating logic can be exercised without live resources.
+type CutoverDeps struct {
+	StreamLag   func(ctx context.Context, stream internal.DatastreamResources) (time.Duration, error)
+	SourceQuery func(ctx context.Context, query string) (interface{}, error)
+	TargetQuery func(ctx context.Context, query string) (interface{}, error)
+	SourceExec  func(ctx context.Context, stmt string) error
+}
+
+// CutoverGateResult records the outcome of a single cutover gate.
+type CutoverGateResult struct {
+	Gate        string
+	Passed      bool
+	Detail      string
+	SourceValue string `json:",omitempty"`
+	TargetValue string `json:",omitempty"`
+}
+
+// CutoverReport is the auditable record of a cutover attempt.
+type CutoverReport struct {
+	JobId            string
+	DryRun           bool
+	StartTime        time.Time
+	EndTime          time.Time
+	MaxLagSeconds    float64
+	Gates            []CutoverGateResult
+	CutoverPerformed bool
+	Actions          []string
+	Error            string `json:",omitempty"`
+}
+
+// ReadCutoverValidationQueries reads a JSON array of validation queries from a file.
+func ReadCutoverValidationQueries(filePath string) ([]CutoverValidationQuery, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("can't read validation queries file %s: %v", filePath, err)
+	}
+	var queries []CutoverValidationQuery
+	if err := json.Unmarshal(data, &queries); err != nil {
+		return nil, fmt.Errorf("can't parse validation queries file %s: %v", filePath, err)
+	}
+	for i, q := range queries {
+		if q.Name == "" || q.SourceQuery == "" || q.TargetQuery == "" {
+			return nil, fmt.Errorf("validation query at index %d must specify name, sourceQuery and targetQuery", i)
+		}
+	}
+	return queries, nil
+}
+
+// RunCutover checks the replication lag of every stream and runs the validation
+// queries. Writes are paused and the cutover flag is flipped only when every
+// gate passes and this is not a dry run.
+func RunCutover(ctx context.Context, jobId string, streams []internal.DatastreamResources, opts CutoverOptions, deps CutoverDeps) CutoverReport {
+	report := CutoverReport{
+		JobId:         jobId,
+		DryRun:        opts.DryRun,
+		StartTime:     time.Now().UTC(),
+		MaxLagSeconds: opts.MaxLag.Seconds(),
+	}
+	if len(streams) == 0 {
+		report.Gates = append(report.Gates, CutoverGateResult{Gate: "datastream-lag", Detail: fmt.Sprintf("no Datastream streams found for jobId %s", jobId)})
+	}
+	for _, stream := range streams {
+		report.Gates = append(report.Gates, checkStreamLag(ctx, stream, opts.MaxLag, deps))
+	}
+	for _, q := range opts.ValidationQueries {
+		report.Gates = append(report.Gates, runValidationQuery(ctx, q, deps))
+	}
+
+	passed := true
+	for _, gate := range report.Gates {
+		if !gate.Passed {
+			passed = false
+			logger.Log.Info(fmt.Sprintf("Cutover gate %s failed: %s\n", gate.Gate, gate.Detail))
+		}
+	}
+	switch {
+	case !passed:
+		report.Error = "one or more cutover gates failed, cutover was not performed"
+	case opts.DryRun:
+		logger.Log.Info("All cutover gates passed, skipping cutover actions for dry run\n")
+	default:
+		report.Error = performCutover(ctx, &report, opts, deps)
+		report.CutoverPerformed = report.Error == ""
+	}
+	report.EndTime = time.Now().UTC()
+	return report
+}
+
+func checkStreamLag(ctx context.Context, stream internal.DatastreamResources, maxLag time.Duration, deps CutoverDeps) CutoverGateResult {
+	gate := CutoverGateResult{Gate: fmt.Sprintf("datastream-lag:%s", stream.DatastreamName)}
+	lag, err := deps.StreamLag(ctx, stream)
+	if err != nil {
+		gate.Detail = fmt.Sprintf("can't get lag for stream %s: %v", stream.DatastreamName, err)
+		return gate
+	}
+	gate.Passed = lag <= maxLag
+	gate.Detail = fmt.Sprintf("lag %v, maximum allowed %v", lag, maxLag)
+	return gate
+}
+
+func runValidationQuery(ctx context.Context, q CutoverValidationQuery, deps CutoverDeps) CutoverGateResult {
+	gate := CutoverGateResult{Gate: fmt.Sprintf("validation:%s", q.Name)}
+	srcVal, err := deps.SourceQuery(ctx, q.SourceQuery)
+	if err != nil {
+		gate.Detail = fmt.Sprintf("source query failed: %v", err)
+		return gate
+	}
+	gate.SourceValue = formatCutoverValue(srcVal)
+	tgtVal, err := deps.TargetQuery(ctx, q.TargetQuery)
+	if err != nil {
+		gate.Detail = fmt.Sprintf("target query failed: %v", err)
+		return gate
+	}
+	gate.TargetValue = formatCutoverValue(tgtVal)
+	gate.Passed = cutoverValuesEqual(srcVal, tgtVal)
+	if gate.Passed {
+		gate.Detail = "source and target values match"
+	} else {
+		gate.Detail = "source and target values differ"
+	}
+	return gate
+}
+
+// performCutover pauses writes on the source and flips the cutover flag,
+// returning a description of the first failure, if any.
+func performCutover(ctx context.Context, report *CutoverReport, opts CutoverOptions, deps CutoverDeps) string {
+	if opts.PauseWritesStatement != "" {
+		if err := deps.SourceExec(ctx, opts.PauseWritesStatement); err != nil {
+			return fmt.Sprintf("can't pause writes on source: %v", err)
+		}
+		report.Actions = append(report.Actions, fmt.Sprintf("paused source writes with: %s", opts.PauseWritesStatement))
+	}
+	if opts.FlagFile != "" {
+		flag, _ := json.Marshal(map[string]string{
+			"JobId":       report.JobId,
+			"CutoverTime": time.Now().UTC().Format(time.RFC3339),
+		})
+		if err := os.WriteFile(opts.FlagFile, flag, 0644); err != nil {
+			return fmt.Sprintf("can't write cutover flag file %s: %v", opts.FlagFile, err)
+		}
+		report.Actions = append(report.Actions, fmt.Sprintf("wrote cutover flag to %s", opts.FlagFile))
+	}
+	return ""
+}
+
+// WriteCutoverReport writes the cutover report as JSON to filePath.
+func WriteCutoverReport(report CutoverReport, filePath string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("can't encode cutover report: %v", err)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return fmt.Errorf("can't write cutover report to %s: %v", filePath, err)
+	}
+	return nil
+}
+
+// FetchDatastreamResources returns the Datastream streams created for a migration job.
+func FetchDatastreamResources(ctx context.Context, migrationJobId string, dataShardIds []string, spannerProjectId string, instance string) ([]internal.DatastreamResources, error) {
+	resourcesList, err := FetchResources(ctx, migrationJobId, constants.DATASTREAM_RESOURCE, dataShardIds, spannerProjectId, instance)
+	if err != nil {
+		return nil, err
+	}
+	return parseDatastreamResources(resourcesList)
+}
+
+// parseDatastreamResources decodes the Datastream streams stored in the
+// metadata of resources.
+func parseDatastreamResources(resourcesList []SmtResource) ([]internal.DatastreamResources, error) {
+	var streams []internal.DatastreamResources
+	for _, resources := range resourcesList {
+		var datastreamResources internal.DatastreamResources
+		var minimalDowntimeResourceData MinimalDowntimeResourceData
+		if err := json.Unmarshal([]byte(resources.ResourceData), &minimalDowntimeResourceData); err != nil {
+			return nil, fmt.Errorf("can't read resource data of resource %s: %v", resources.ResourceId, err)
+		}
+		if err := json.Unmarshal([]byte(minimalDowntimeResourceData.ResourcePayload), &datastreamResources); err != nil {
+			return nil, fmt.Errorf("can't read Datastream metadata for resource %s: %v", resources.ResourceId, err)
+		}
+		streams = append(streams, datastreamResources)
+	}
+	return streams, nil
+}
+
+// GetDatastreamLag returns the most recent freshness reported by Cloud Monitoring
+// for a stream, i.e. how far the stream is behind the source.
+func GetDatastreamLag(ctx context.Context, project string, stream internal.DatastreamResources) (time.Duration, error) {
+	client, err := monitoring.NewMetricClient(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("monitoring client can not be created: %v", err)
+	}
+	defer client.Close()
+	now := time.Now()
+	req := &monitoringpb.ListTimeSeriesRequest{
+		Name: fmt.Sprintf("projects/%s", project),
+		Filter: fmt.Sprintf(`metric.type="%s" AND resource.labels.stream_id="%s" AND resource.labels.location="%s"`,
+			datastreamFreshnessMetric, stream.DatastreamName, stream.Region),
+		Interval: &monitoringpb.TimeInterval{
+			StartTime: timestamppb.New(now.Add(-10 * time.Minute)),
+			EndTime:   timestamppb.New(now),
+		},
+		View: monitoringpb.ListTimeSeriesRequest_FULL,
+	}
+	it := client.ListTimeSeries(ctx, req)
+	for {
+		ts, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		// Points are returned in reverse time order, so the first is the latest.
+		if len(ts.GetPoints()) > 0 {
+			v := ts.GetPoints()[0].GetValue()
+			seconds := v.GetDoubleValue()
+			if _, ok := v.GetValue().(*monitoringpb.TypedValue_Int64Value); ok {
+				seconds = float64(v.GetInt64Value())
+			}
+			return time.Duration(seconds * float64(time.Second)), nil
+		}
+	}
+	return 0, fmt.Errorf("no freshness data reported in the last 10 minutes")
+}
+
+// QuerySourceValue runs a query on the source database and returns its single
+// value, normalized for comparison with QuerySpannerValue.
+func QuerySourceValue(ctx context.Context, db *sql.DB, query string) (interface{}, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("query returned no rows")
+	}
+	colTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := rows.Scan(&v); err != nil {
+		return nil, err
+	}
+	return normalizeSourceValue(v, colTypes[0].DatabaseTypeName())
+}
+
+// sourceNumericTypes are the database type names of numeric source columns,
+// whose values some drivers return as text.
+var sourceNumericTypes = map[string]bool{
+	"TINYINT": true, "SMALLINT": true, "MEDIUMINT": true, "INT": true, "INTEGER": true, "BIGINT": true,
+	"INT2": true, "INT4": true, "INT8": true, "DECIMAL": true, "NUMERIC": true, "NUMBER": true,
+	"FLOAT": true, "FLOAT4": true, "FLOAT8": true, "DOUBLE": true, "REAL": true, "MONEY": true, "SMALLMONEY": true,
+}
+
+// normalizeSourceValue converts a value scanned from a source column of type
+// dbType into the types compared by cutoverValuesEqual.
+func normalizeSourceValue(v interface{}, dbType string) (interface{}, error) {
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return normalizeSourceValue(string(val), dbType)
+	case string:
+		if sourceNumericTypes[strings.TrimPrefix(strings.ToUpper(dbType), "UNSIGNED ")] {
+			return parseCutoverNumber(val)
+		}
+		return val, nil
+	case int64:
+		return new(big.Rat).SetInt64(val), nil
+	case int32:
+		return new(big.Rat).SetInt64(int64(val)), nil
+	case uint64:
+		return new(big.Rat).SetUint64(val), nil
+	case float64:
+		return floatCutoverValue(val), nil
+	case float32:
+		return floatCutoverValue(float64(val)), nil
+	case bool, time.Time:
+		return val, nil
+	default:
+		return fmt.Sprint(val), nil
+	}
+}
+
+// QuerySpannerValue runs a query on Spanner and returns its single value,
+// normalized for comparison with QuerySourceValue.
+func QuerySpannerValue(ctx context.Context, client *spanner.Client, query string) (interface{}, error) {
+	iter := client.Single().Query(ctx, spanner.Statement{SQL: query})
+	defer iter.Stop()
+	row, err := iter.Next()
+	if err == iterator.Done {
+		return nil, fmt.Errorf("query returned no rows")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var gcv spanner.GenericColumnValue
+	if err := row.Column(0, &gcv); err != nil {
+		return nil, err
+	}
+	return normalizeSpannerValue(gcv)
+}
+
+// normalizeSpannerValue converts a Spanner value into the types compared by
+// cutoverValuesEqual.
+func normalizeSpannerValue(gcv spanner.GenericColumnValue) (interface{}, error) {
+	if _, ok := gcv.Value.GetKind().(*structpb.Value_NullValue); ok {
+		return nil, nil
+	}
+	switch gcv.Type.GetCode() {
+	case sppb.TypeCode_INT64, sppb.TypeCode_NUMERIC:
+		return parseCutoverNumber(gcv.Value.GetStringValue())
+	case sppb.TypeCode_FLOAT64, sppb.TypeCode_FLOAT32:
+		if s, ok := gcv.Value.GetKind().(*structpb.Value_StringValue); ok {
+			// NaN and infinities are encoded as strings.
+			return s.StringValue, nil
+		}
+		return floatCutoverValue(gcv.Value.GetNumberValue()), nil
+	case sppb.TypeCode_BOOL:
+		return gcv.Value.GetBoolValue(), nil
+	case sppb.TypeCode_TIMESTAMP:
+		return time.Parse(time.RFC3339Nano, gcv.Value.GetStringValue())
+	case sppb.TypeCode_DATE:
+		return time.Parse("2006-01-02", gcv.Value.GetStringValue())
+	case sppb.TypeCode_STRING:
+		return gcv.Value.GetStringValue(), nil
+	default:
+		return fmt.Sprint(gcv.Value.AsInterface()), nil
+	}
+}
+
+// parseCutoverNumber parses the text of an integer or decimal value.
+func parseCutoverNumber(s string) (interface{}, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return nil, fmt.Errorf("can't parse %q as a number", s)
+	}
+	return r, nil
+}
+
+// floatCutoverValue returns the exact value of f, or its text if f is NaN or
+// infinite.
+func floatCutoverValue(f float64) interface{} {
+	if r := new(big.Rat).SetFloat64(f); r != nil {
+		return r
+	}
+	return fmt.Sprint(f)
+}
+
+// cutoverValuesEqual returns true if two normalized values are equal: numbers
+// by value, times by instant, and other values by type and value.
+func cutoverValuesEqual(a, b interface{}) bool {
+	switch x := a.(type) {
+	case *big.Rat:
+		y, ok := b.(*big.Rat)
+		return ok && x.Cmp(y) == 0
+	case time.Time:
+		y, ok := b.(time.Time)
+		return ok && x.Equal(y)
+	default:
+		return a == b
+	}
+}
+
+// formatCutoverValue returns the text of a normalized value for the cutover
+// report.
+func formatCutoverValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case *big.Rat:
+		if val.IsInt() {
+			return val.Num().String()
+		}
+		return strings.TrimRight(val.FloatString(9), "0")
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(val)
+	}
+}
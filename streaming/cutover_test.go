@@ -0,0 +1,168 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package streaming
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	sppb "cloud.google.com/go/spanner/apiv1/spannerpb"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func cutoverTestDeps(lag time.Duration, srcVal, tgtVal interface{}, executed *[]string) CutoverDeps {
+	return CutoverDeps{
+		StreamLag: func(ctx context.Context, stream internal.DatastreamResources) (time.Duration, error) {
+			if stream.DatastreamName == "broken" {
+				return 0, fmt.Errorf("no freshness data")
+			}
+			return lag, nil
+		},
+		SourceQuery: func(ctx context.Context, query string) (interface{}, error) { return srcVal, nil },
+		TargetQuery: func(ctx context.Context, query string) (interface{}, error) { return tgtVal, nil },
+		SourceExec: func(ctx context.Context, stmt string) error {
+			*executed = append(*executed, stmt)
+			return nil
+		},
+	}
+}
+
+func TestRunCutover(t *testing.T) {
+	streams := []internal.DatastreamResources{{DatastreamName: "s1", Region: "us-central1"}}
+	queries := []CutoverValidationQuery{{Name: "orders-count", SourceQuery: "SELECT COUNT(*) FROM orders", TargetQuery: "SELECT COUNT(*) FROM orders"}}
+	flagFile := filepath.Join(t.TempDir(), "cutover.flag")
+	opts := CutoverOptions{
+		MaxLag:               30 * time.Second,
+		ValidationQueries:    queries,
+		PauseWritesStatement: "SET GLOBAL read_only = ON",
+		FlagFile:             flagFile,
+	}
+	testCases := []struct {
+		name          string
+		streams       []internal.DatastreamResources
+		lag           time.Duration
+		srcVal        interface{}
+		tgtVal        interface{}
+		dryRun        bool
+		wantPerformed bool
+		wantFailed    []string
+	}{
+		{name: "all gates pass", streams: streams, lag: 5 * time.Second, srcVal: "10", tgtVal: "10", wantPerformed: true},
+		{name: "dry run", streams: streams, lag: 5 * time.Second, srcVal: "10", tgtVal: "10", dryRun: true},
+		{name: "lag too high", streams: streams, lag: time.Minute, srcVal: "10", tgtVal: "10", wantFailed: []string{"datastream-lag:s1"}},
+		{name: "lag unavailable", streams: []internal.DatastreamResources{{DatastreamName: "broken"}}, srcVal: "10", tgtVal: "10", wantFailed: []string{"datastream-lag:broken"}},
+		{name: "no streams", srcVal: "10", tgtVal: "10", wantFailed: []string{"datastream-lag"}},
+		{name: "values differ", streams: streams, srcVal: "10", tgtVal: "9", wantFailed: []string{"validation:orders-count"}},
+		{name: "numbers equal by value", streams: streams, srcVal: big.NewRat(21, 2), tgtVal: big.NewRat(105, 10), wantPerformed: true},
+		{name: "number and text differ", streams: streams, srcVal: big.NewRat(10, 1), tgtVal: "10", wantFailed: []string{"validation:orders-count"}},
+	}
+	for _, tc := range testCases {
+		os.Remove(flagFile)
+		var executed []string
+		opts.DryRun = tc.dryRun
+		report := RunCutover(context.Background(), "job1", tc.streams, opts, cutoverTestDeps(tc.lag, tc.srcVal, tc.tgtVal, &executed))
+		var failed []string
+		for _, gate := range report.Gates {
+			if !gate.Passed {
+				failed = append(failed, gate.Gate)
+			}
+		}
+		assert.Equal(t, tc.wantFailed, failed, tc.name)
+		assert.Equal(t, tc.wantPerformed, report.CutoverPerformed, tc.name)
+		_, err := os.Stat(flagFile)
+		if tc.wantPerformed {
+			assert.Equal(t, []string{"SET GLOBAL read_only = ON"}, executed, tc.name)
+			assert.Nil(t, err, tc.name)
+			assert.Equal(t, 2, len(report.Actions), tc.name)
+		} else {
+			assert.Nil(t, executed, tc.name)
+			assert.True(t, os.IsNotExist(err), tc.name)
+		}
+		assert.Equal(t, len(tc.wantFailed) > 0, report.Error != "", tc.name)
+	}
+}
+
+func TestReadCutoverValidationQueries(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	os.WriteFile(valid, []byte(`[{"name": "count", "sourceQuery": "SELECT COUNT(*) FROM t", "targetQuery": "SELECT COUNT(*) FROM t"}]`), 0644)
+	queries, err := ReadCutoverValidationQueries(valid)
+	assert.Nil(t, err)
+	assert.Equal(t, []CutoverValidationQuery{{Name: "count", SourceQuery: "SELECT COUNT(*) FROM t", TargetQuery: "SELECT COUNT(*) FROM t"}}, queries)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	os.WriteFile(invalid, []byte(`[{"name": "count", "sourceQuery": "SELECT 1"}]`), 0644)
+	_, err = ReadCutoverValidationQueries(invalid)
+	assert.EqualError(t, err, "validation query at index 0 must specify name, sourceQuery and targetQuery")
+}
+
+func TestCutoverValuesEqual(t *testing.T) {
+	spannerValue := func(code sppb.TypeCode, v *structpb.Value) interface{} {
+		val, err := normalizeSpannerValue(spanner.GenericColumnValue{Type: &sppb.Type{Code: code}, Value: v})
+		assert.Nil(t, err)
+		return val
+	}
+	sourceValue := func(v interface{}, dbType string) interface{} {
+		val, err := normalizeSourceValue(v, dbType)
+		assert.Nil(t, err)
+		return val
+	}
+	ts := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	testCases := []struct {
+		name      string
+		src       interface{}
+		tgt       interface{}
+		wantEqual bool
+	}{
+		{"MySQL count and Spanner INT64", sourceValue([]byte("10"), "BIGINT"), spannerValue(sppb.TypeCode_INT64, structpb.NewStringValue("10")), true},
+		{"PostgreSQL count and Spanner INT64", sourceValue(int64(10), "INT8"), spannerValue(sppb.TypeCode_INT64, structpb.NewStringValue("10")), true},
+		{"Different counts", sourceValue(int64(10), "INT8"), spannerValue(sppb.TypeCode_INT64, structpb.NewStringValue("9")), false},
+		{"DECIMAL and NUMERIC with different scales", sourceValue([]byte("10.50"), "DECIMAL"), spannerValue(sppb.TypeCode_NUMERIC, structpb.NewStringValue("10.500000000")), true},
+		{"Unsigned integer", sourceValue([]byte("7"), "UNSIGNED BIGINT"), spannerValue(sppb.TypeCode_INT64, structpb.NewStringValue("7")), true},
+		{"DOUBLE and FLOAT64", sourceValue(0.1, "DOUBLE"), spannerValue(sppb.TypeCode_FLOAT64, structpb.NewNumberValue(0.1)), true},
+		{"Integral FLOAT64 and INT64", sourceValue(int64(3), "BIGINT"), spannerValue(sppb.TypeCode_FLOAT64, structpb.NewNumberValue(3)), true},
+		{"Timestamps in different zones", sourceValue(ts.In(time.FixedZone("X", 3600)), "TIMESTAMP"), spannerValue(sppb.TypeCode_TIMESTAMP, structpb.NewStringValue("2024-05-01T10:30:00Z")), true},
+		{"Dates", sourceValue(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), "DATE"), spannerValue(sppb.TypeCode_DATE, structpb.NewStringValue("2024-05-01")), true},
+		{"Strings", sourceValue([]byte("abc"), "VARCHAR"), spannerValue(sppb.TypeCode_STRING, structpb.NewStringValue("abc")), true},
+		{"Numeric text isn't a number", sourceValue([]byte("10"), "VARCHAR"), spannerValue(sppb.TypeCode_INT64, structpb.NewStringValue("10")), false},
+		{"Booleans", sourceValue(true, "BOOL"), spannerValue(sppb.TypeCode_BOOL, structpb.NewBoolValue(true)), true},
+		{"NULLs", sourceValue(nil, "BIGINT"), spannerValue(sppb.TypeCode_INT64, structpb.NewNullValue()), true},
+		{"NULL and zero", sourceValue(nil, "BIGINT"), spannerValue(sppb.TypeCode_INT64, structpb.NewStringValue("0")), false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.wantEqual, cutoverValuesEqual(tc.src, tc.tgt), tc.name)
+	}
+	_, err := normalizeSourceValue([]byte("abc"), "DECIMAL")
+	assert.NotNil(t, err)
+	assert.Equal(t, "10.5", formatCutoverValue(big.NewRat(21, 2)))
+	assert.Equal(t, "10", formatCutoverValue(big.NewRat(10, 1)))
+	assert.Equal(t, "NULL", formatCutoverValue(nil))
+}
+
+func TestParseDatastreamResources(t *testing.T) {
+	streams, err := parseDatastreamResources([]SmtResource{{ResourceId: "r1", ResourceData: `{"DataShardId": "shard1", "ResourcePayload": "{\"DatastreamName\": \"s1\", \"Region\": \"us-central1\"}"}`}})
+	assert.Nil(t, err)
+	assert.Equal(t, []internal.DatastreamResources{{DatastreamName: "s1", Region: "us-central1"}}, streams)
+
+	_, err = parseDatastreamResources([]SmtResource{{ResourceId: "r1", ResourceData: "not json"}})
+	assert.EqualError(t, err, "can't read resource data of resource r1: invalid character 'o' in literal null (expecting 'u')")
+}
@@ -19,6 +19,7 @@ export enum SourceDbNames {
   SQLServer = 'SQL Server',
   Oracle = 'Oracle',
   Cassandra = 'cassandra',
+  MongoDB = 'mongodb',
 }
 
 export enum ObjectExplorerNodeType {
@@ -147,6 +148,28 @@ export const Dataflow = {
   CustomParameter: 'customParameter'
 }
 
+// Strategies masking the values of a column during data migration, with the
+// kind of fake values of the synthetic strategy after a colon.
+export const ColumnMasks = [
+  { value: '', label: 'None' },
+  { value: 'hash', label: 'Hash' },
+  { value: 'shuffle', label: 'Shuffle' },
+  { value: 'nullify', label: 'Nullify' },
+  { value: 'synthetic', label: 'Synthetic text' },
+  { value: 'synthetic:name', label: 'Synthetic name' },
+  { value: 'synthetic:email', label: 'Synthetic email' },
+  { value: 'synthetic:phone', label: 'Synthetic phone' },
+  { value: 'synthetic:address', label: 'Synthetic address' },
+]
+
+// Strategies generating the values of the synthetic primary key column added
+// to tables without a primary key.
+export const SyntheticPKeyStrategies = [
+  { value: 'string_sequence', label: 'String sequence' },
+  { value: 'uuid', label: 'UUID' },
+  { value: 'bit_reversed_sequence', label: 'Bit-reversed sequence' },
+]
+
 export const ColLength = {
   StorageMaxLength: 9223372036854775807,
   StringMaxLength: 2621440,
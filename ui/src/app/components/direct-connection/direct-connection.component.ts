@@ -36,6 +36,7 @@ export class DirectConnectionComponent implements OnInit {
     { value: 'oracle', displayName: 'Oracle' },
     { value: 'postgres', displayName: 'PostgreSQL' },
     { value: 'cassandra', displayName: 'Cassandra'},
+    { value: 'mongodb', displayName: 'MongoDB (mongoexport files)'},
   ]
 
   isTestConnectionSuccessful = false
@@ -96,6 +97,24 @@ export class DirectConnectionComponent implements OnInit {
       dataCenterControl?.clearValidators()
     }
     dataCenterControl?.updateValueAndValidity()
+
+    // MongoDB collections are read from the mongoexport files of a directory,
+    // entered as the database name, so no server connection details are needed.
+    const hostNameControl = this.connectForm.get('hostName')
+    const portControl = this.connectForm.get('port')
+    const userNameControl = this.connectForm.get('userName')
+    if (dbEngine === SourceDbNames.MongoDB) {
+      hostNameControl?.clearValidators()
+      portControl?.clearValidators()
+      userNameControl?.clearValidators()
+    } else {
+      hostNameControl?.setValidators([Validators.required])
+      portControl?.setValidators([Validators.required, Validators.pattern('^[0-9]+$')])
+      userNameControl?.setValidators([Validators.required])
+    }
+    hostNameControl?.updateValueAndValidity()
+    portControl?.updateValueAndValidity()
+    userNameControl?.updateValueAndValidity()
   }
 
   testConn() {
@@ -32,7 +32,7 @@ describe('ObjectDetailComponent', () => {
 
   beforeEach(async () => {
     mockIConv = createMockIConv();
-    dataServiceSpy = jasmine.createSpyObj('DataService', ['updateSequence', 'dropSequence', 'updateCheckConstraint', 'reviewTableUpdate', 'setInterleave', 'dropTable', 'getConversionRate']);
+    dataServiceSpy = jasmine.createSpyObj('DataService', ['updateSequence', 'dropSequence', 'updateCheckConstraint', 'reviewTableUpdate', 'setInterleave', 'dropTable', 'getConversionRate', 'updateVectorIndex', 'dropVectorIndex', 'convertInterleaveCandidate']);
     dataServiceSpy.updateSequence.and.returnValue(of({}));
     dataServiceSpy.dropSequence.and.returnValue(of(''));
     dataServiceSpy.reviewTableUpdate.and.returnValue(of(''));
@@ -95,6 +95,7 @@ describe('ObjectDetailComponent', () => {
         srcColMaxLength: 50,
         spColMaxLength: 50,
         spCassandraOption: '',
+        srcComment: '',
         spAutoGen: {
           Name: '',
           GenerationType: '',
@@ -145,6 +146,7 @@ describe('ObjectDetailComponent', () => {
         srcColMaxLength: 50,
         spColMaxLength: 50,
         spCassandraOption: '',
+        srcComment: '',
         spAutoGen: {
           Name: '',
           GenerationType: '',
@@ -507,6 +509,7 @@ describe('ObjectDetailComponent', () => {
         spIsNotNull: true,
         spColMaxLength: '',
         spCassandraOption: 'list<bigint>',
+        srcComment: '',
         spAutoGen: { Name: '', GenerationType: '', IdentityOptions: { SkipRangeMin: '', SkipRangeMax: '', StartCounterWith: '' } },
         spSkipRangeMin: '',
         spSkipRangeMax: '',
@@ -585,6 +588,59 @@ describe('ObjectDetailComponent', () => {
     });
   });
 
+  it('should call updateVectorIndex with the selected column and distance type', () => {
+    component.currentObject = { id: 't1' } as FlatNode;
+    component.vectorIndexName = 'docs_embedding';
+    component.vectorIndexColId = 'c2';
+    component.vectorIndexDistanceType = 'EUCLIDEAN';
+    dataServiceSpy.updateVectorIndex.and.returnValue(of(''));
+
+    component.addVectorIndex();
+
+    expect(dataServiceSpy.updateVectorIndex).toHaveBeenCalledWith('t1', {
+      Name: 'docs_embedding',
+      Id: '',
+      ColId: 'c2',
+      DistanceType: 'EUCLIDEAN',
+    });
+    expect(dialogSpyObj.open).not.toHaveBeenCalled();
+  });
+
+  it('should show error dialog when updateVectorIndex fails', () => {
+    const errorMessage = 'Vector index error';
+    component.currentObject = { id: 't1' } as FlatNode;
+    component.vectorIndexName = 'docs_embedding';
+    component.vectorIndexColId = 'c1';
+    dataServiceSpy.updateVectorIndex.and.returnValue(of(errorMessage));
+
+    component.addVectorIndex();
+
+    expect(dialogSpyObj.open).toHaveBeenCalledWith(InfodialogComponent, {
+      data: { message: errorMessage, type: 'error', title: 'Error' },
+      maxWidth: '500px',
+    });
+  });
+
+  it('should call dropVectorIndex with the index id', () => {
+    component.currentObject = { id: 't1' } as FlatNode;
+    dataServiceSpy.dropVectorIndex.and.returnValue(of(''));
+
+    component.dropVectorIndex('i1');
+
+    expect(dataServiceSpy.dropVectorIndex).toHaveBeenCalledWith('t1', 'i1');
+  });
+
+  it('should convert the interleave candidate of the table', () => {
+    component.currentObject = { id: 't1' } as FlatNode;
+    component.interleaveCandidate = { TableId: 't1', ParentTableId: 't2', ForeignKeyId: 'f1', ForeignKey: 'fk_parent', OnDelete: 'CASCADE' };
+    dataServiceSpy.convertInterleaveCandidate.and.returnValue(of(''));
+
+    component.convertInterleaveCandidate();
+
+    expect(dataServiceSpy.convertInterleaveCandidate).toHaveBeenCalledWith('t1');
+    expect(component.interleaveCandidate).toBeNull();
+  });
+
   it('should drop table successfully', () => {
     const dialogRefSpyObj = jasmine.createSpyObj({ afterClosed: of(ObjectDetailNodeType.Table), close: null });
     dialogSpyObj.open.and.returnValue(dialogRefSpyObj);
@@ -7,7 +7,7 @@ import { InfodialogComponent } from '../infodialog/infodialog.component'
 import IColumnTabData, { AutoGen, IIndexData, ISequenceData } from '../../model/edit-table'
 import { SnackbarService } from 'src/app/services/snackbar/snackbar.service'
 import IFkTabData from 'src/app/model/fk-tab-data'
-import { ColLength, Dialect, ObjectDetailNodeType, ObjectExplorerNodeType, SourceDbNames, StorageKeys, dialogConfigAddSequence, dialogConfigDropComponent } from 'src/app/app.constants'
+import { ColLength, ColumnMasks, Dialect, SyntheticPKeyStrategies, ObjectDetailNodeType, ObjectExplorerNodeType, SourceDbNames, StorageKeys, dialogConfigAddSequence, dialogConfigDropComponent } from 'src/app/app.constants'
 import FlatNode from 'src/app/model/schema-object-node'
 import { Subscription, take } from 'rxjs'
 import { MatTabChangeEvent } from '@angular/material/tabs/'
@@ -18,6 +18,9 @@ import IConv, {
   IIndexKey,
   ITableInterleaveStatus,
   IPrimaryKey,
+  IColumnMask,
+  IInterleaveCandidate,
+  IVectorIndex,
 } from 'src/app/model/conv'
 import { ConversionService } from 'src/app/services/conversion/conversion.service'
 import { DropObjectDetailDialogComponent } from '../drop-object-detail-dialog/drop-object-detail-dialog.component'
@@ -71,6 +74,7 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
   interleaveParentId: string | null = null
   interleaveParentName: string | null = null
   interleaveType: string | null = null
+  srcTableComment: string = ''
   localTableData: IColumnTabData[] = []
   localIndexData: IIndexData[] = []
   localSequenceData: ISequenceData = {}
@@ -83,6 +87,13 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
   supportsAutoGen: boolean = false
   foreignKeyActionsSupported: boolean = false
   spTablesForInterleaving: { id: string; name: string }[] = [];
+  interleaveCandidate: IInterleaveCandidate | null = null
+  vectorIndexes: IVectorIndex[] = []
+  vectorColumns: { id: string; name: string }[] = []
+  vectorIndexName: string = ''
+  vectorIndexColId: string | null = null
+  vectorIndexDistanceType: string = 'COSINE'
+  vectorDistanceTypes: string[] = ['COSINE', 'EUCLIDEAN', 'DOT_PRODUCT']
 
   ngOnInit(): void {
     this.subscriptions.add(
@@ -115,9 +126,12 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
     )
   }
 
-  srcDisplayedColumns = ['srcOrder', 'srcColName', 'srcDataType', 'srcColMaxLength', 'srcIsPk', 'srcIsNotNull']
+  srcDisplayedColumns = ['srcOrder', 'srcColName', 'srcDataType', 'srcColMaxLength', 'srcIsPk', 'srcIsNotNull', 'srcComment']
 
-  spDisplayedColumns = ['spColName', 'spDataType', 'spColMaxLength', 'spIsPk', 'spIsNotNull', 'dropButton']
+  spDisplayedColumns = ['spColName', 'spDataType', 'spColMaxLength', 'spIsPk', 'spIsNotNull', 'spMask', 'dropButton']
+  columnMasks = ColumnMasks
+  syntheticPKeyStrategies = SyntheticPKeyStrategies
+  syntheticPKeyStrategy: string = ''
   displayedFkColumns = [
     'srcName',
     'srcColumns',
@@ -211,7 +225,7 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
   pkObj: IPrimaryKey = {} as IPrimaryKey
   dataTypesWithColLen: string[] = ColLength.DataTypes
   spColspan: number = 6
-  srcColspan: number = 6
+  srcColspan: number = 7
 
   ngOnChanges(changes: SimpleChanges): void {
     this.fkData = changes['fkData']?.currentValue || this.fkData
@@ -224,11 +238,15 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
     this.currentTabIndex = this.currentObject?.type === ObjectExplorerNodeType.Index || this.currentObject?.type === ObjectExplorerNodeType.Sequence ? -1 : 0
     this.isObjectSelected = this.currentObject ? true : false
     this.pkData = this.conversion.getPkMapping(this.tableData)
+    this.syntheticPKeyStrategy = this.getSyntheticPKeyStrategyFromConv()
 
     this.interleaveParentId = this.getInterleaveParentIdFromConv()
     this.interleaveParentName = this.conv.SpSchema?.[this.interleaveParentId ?? '']?.Name ?? null
     this.interleaveType = this.getInterleaveTypeFromConv()
+    this.srcTableComment = this.currentObject?.type === ObjectExplorerNodeType.Table ? this.conv.SrcSchema?.[this.currentObject.id]?.Comment ?? '' : ''
     this.onDeleteAction = this.getInterleaveOnDeleteActionFromConv() ?? ''
+    this.setVectorIndexData()
+    this.setInterleaveCandidate()
 
     let tabIndex = 2
     if (this.srcDbName !== 'cassandra') {
@@ -326,6 +344,7 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
           srcDefaultValue: new FormControl(row.srcDefaultValue),
           srcGeneratedColExp: new FormControl(row.srcGeneratedColExp),
           srcGeneratedColExpType: new FormControl(row.srcGeneratedColExpType),
+          srcComment: new FormControl(row.srcComment),
           srcColMaxLength: new FormControl(row.srcColMaxLength),
           srcAutoGen: new FormControl(row.srcAutoGen),
           spOrder: new FormControl(row.srcOrder),
@@ -347,6 +366,7 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
           spDefaultValue: new FormControl(row.spDefaultValue ? row.spDefaultValue.Value.Statement : ''),
           spGeneratedColumn: new FormControl(row.spGeneratedColumnType && row.spGeneratedColumnType != 'N/A' ? row.spGeneratedColumn : ''),
           spGeneratedColumnType: new FormControl(row.spGeneratedColumnType && row.spGeneratedColumnType != 'N/A' ? row.spGeneratedColumnType : ''),
+          spMask: new FormControl(row.spMask || ''),
         }, { validators: linkedFieldsValidatorSequence('spSkipRangeMin', 'spSkipRangeMax') })
         // Disable spDefaultValue and spGeneratedColumn if spAutoGen is set
         if (row.spAutoGen.Name !== '') {
@@ -402,6 +422,7 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
             srcDefaultValue: new FormControl(col.srcDefaultValue),
             srcGeneratedColExp: new FormControl(col.srcGeneratedColExp),
             srcGeneratedColExpType: new FormControl(col.srcGeneratedColExpType),
+            srcComment: new FormControl(col.srcComment),
             srcColMaxLength: new FormControl(col.srcColMaxLength),
             srcAutoGen: new FormControl(col.srcAutoGen),
             spOrder: new FormControl(col.spOrder),
@@ -445,6 +466,7 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
             srcDefaultValue: new FormControl(col.srcDefaultValue),
             srcGeneratedColExp: new FormControl(col.srcGeneratedColExp),
             srcGeneratedColExpType: new FormControl(col.srcGeneratedColExpType),
+            srcComment: new FormControl(col.srcComment),
             srcColMaxLength: new FormControl(col.srcColMaxLength),
             srcAutoGen: new FormControl(col.srcAutoGen),
             spOrder: new FormControl(col.srcOrder),
@@ -491,6 +513,15 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
       this.isEditMode = true
     }
   }
+  // setColumnMask adds the mask of column colId to updateData if it changed.
+  setColumnMask(updateData: IUpdateTable, colId: string, mask: string | undefined, oldMask: string | undefined) {
+    if (mask === undefined || mask === (oldMask || '')) {
+      return
+    }
+    const [strategy, kind] = mask.split(':')
+    updateData.UpdateCols[colId].Mask = { Strategy: strategy as IColumnMask['Strategy'], Kind: kind || '' }
+  }
+
   saveColumnTable() {
     this.isEditMode = false
     let updateData: IUpdateTable = { UpdateCols: {} }
@@ -558,6 +589,7 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
               Type: (col.spGeneratedColumnType && col.spGeneratedColumnType != 'N/A') ? col.spGeneratedColumnType : ''
             }
           }
+          this.setColumnMask(updateData, this.tableData[j].srcId, col.spMask, oldRow.spMask)
           break
         }
         else if (col.spId == this.tableData[j].spId) {
@@ -585,6 +617,7 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
               Type: (col.spGeneratedColumnType && col.spGeneratedColumnType != 'N/A') ? col.spGeneratedColumnType : ''
             }
           }
+          this.setColumnMask(updateData, this.tableData[j].spId, col.spMask, oldRow.spMask)
         }
       }
     })
@@ -1376,6 +1409,32 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
     })
   }
 
+  // getSyntheticPKeyStrategyFromConv returns the strategy generating the
+  // synthetic primary key of the current table, or '' if it has none.
+  getSyntheticPKeyStrategyFromConv(): string {
+    let synthPk = this.conv.SyntheticPKeys?.[this.currentObject?.id ?? '']
+    if (!synthPk) {
+      return ''
+    }
+    return synthPk.Strategy || 'string_sequence'
+  }
+
+  setSyntheticPKeyStrategy(strategy: string) {
+    this.data
+      .updateSyntheticPk({ TableId: this.currentObject!.id, Strategy: strategy, Columns: [] })
+      .subscribe({
+        next: (res: string) => {
+          if (res != '') {
+            this.dialog.open(InfodialogComponent, {
+              data: { message: res, type: 'error' },
+              maxWidth: '500px',
+            })
+            this.syntheticPKeyStrategy = this.getSyntheticPKeyStrategyFromConv()
+          }
+        },
+      })
+  }
+
   dropPk(element: any) {
     let index = this.localTableData.map((item) => item.spColName).indexOf(element.value.spColName)
     let colId = this.localTableData[index].spId
@@ -1578,6 +1637,39 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
     return ind
   }
 
+  setInterleaveCandidate() {
+    this.interleaveCandidate = null
+    if (this.getInterleaveParentIdFromConv() !== null || this.currentObject?.type !== ObjectExplorerNodeType.Table || !this.currentObject.isSpannerNode || this.currentObject.isDeleted) {
+      return
+    }
+    let tableId = this.currentObject.id
+    this.fetchSerice.getInterleaveCandidates().pipe(take(1)).subscribe({
+      next: (candidates: IInterleaveCandidate[]) => {
+        if (this.currentObject?.id === tableId) {
+          this.interleaveCandidate = candidates.find((c) => c.TableId === tableId) ?? null
+        }
+      },
+    })
+  }
+
+  convertInterleaveCandidate() {
+    let tableId = this.currentObject!.id
+    this.data
+      .convertInterleaveCandidate(tableId)
+      .pipe(take(1))
+      .subscribe((error: string) => {
+        if (error) {
+          this.dialog.open(InfodialogComponent, {
+            data: { message: error, type: 'error', title: 'Error' },
+            maxWidth: '500px',
+          })
+        } else {
+          this.interleaveCandidate = null
+          this.snackbar.openSnackBar('Foreign key converted to interleaving successfully.', 'Close', 5)
+        }
+      })
+  }
+
   removeInterleave() {
     let tableId = this.currentObject!.id
     this.data
@@ -1623,6 +1715,64 @@ export class ObjectDetailComponent implements OnInit, OnDestroy {
       });
   }
 
+  setVectorIndexData() {
+    let spTable =
+      this.currentObject?.type === ObjectExplorerNodeType.Table && this.currentObject.isSpannerNode
+        ? this.conv.SpSchema?.[this.currentObject.id]
+        : undefined
+    this.vectorIndexes = spTable?.VectorIndexes ?? []
+    // Only arrays of FLOAT32 or FLOAT64 with a vector length can be indexed.
+    this.vectorColumns = (spTable?.ColIds ?? [])
+      .map((colId: string) => spTable!.ColDefs[colId])
+      .filter(
+        (col) =>
+          col.T.IsArray && ['FLOAT32', 'FLOAT64'].includes(col.T.Name) && (col.T.VectorLength ?? 0) > 0
+      )
+      .map((col) => ({ id: col.Id, name: col.Name }))
+    this.vectorIndexName = ''
+    this.vectorIndexColId = null
+    this.vectorIndexDistanceType = 'COSINE'
+  }
+
+  getVectorColumnName(colId: string) {
+    return this.conv.SpSchema[this.currentObject!.id]?.ColDefs[colId]?.Name ?? colId
+  }
+
+  addVectorIndex() {
+    let tableId = this.currentObject!.id
+    let payload: IVectorIndex = {
+      Name: this.vectorIndexName,
+      Id: '',
+      ColId: this.vectorIndexColId!,
+      DistanceType: this.vectorIndexDistanceType,
+    }
+    this.data
+      .updateVectorIndex(tableId, payload)
+      .pipe(take(1))
+      .subscribe((error: string) => {
+        if (error) {
+          this.dialog.open(InfodialogComponent, {
+            data: { message: error, type: 'error', title: 'Error' },
+            maxWidth: '500px',
+          })
+        } else {
+          this.setVectorIndexData()
+          this.snackbar.openSnackBar('Vector index added successfully.', 'Close', 5)
+        }
+      })
+  }
+
+  dropVectorIndex(indexId: string) {
+    this.data
+      .dropVectorIndex(this.currentObject!.id, indexId)
+      .pipe(take(1))
+      .subscribe((error: string) => {
+        if (error === '') {
+          this.setVectorIndexData()
+        }
+      })
+  }
+
   getInterleaveParentIdFromConv() {
     return this.currentObject?.type === ObjectExplorerNodeType.Table &&
       this.currentObject.isSpannerNode &&
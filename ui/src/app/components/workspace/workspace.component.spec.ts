@@ -77,7 +77,8 @@ describe('WorkspaceComponent', () => {
     ]);
     dataServiceSpy = jasmine.createSpyObj('DataService', [
       'getRateTypemapAndSummary',
-      'verifyCheckConstraintExpression'
+      'verifyCheckConstraintExpression',
+      'addJsonPathIndexes'
     ]);
     routerSpy = jasmine.createSpyObj('Router', ['navigate']);
 
@@ -284,6 +285,15 @@ describe('WorkspaceComponent', () => {
     expect(sidenavSpyObj.setSidenavDatabaseName).toHaveBeenCalledWith(dbName);
   });
 
+  it('should add the JSON path indexes', () => {
+    dataServiceSpy.addJsonPathIndexes.and.returnValue(of(''));
+    component.addJsonPathIndexes();
+    expect(dataServiceSpy.addJsonPathIndexes).toHaveBeenCalled();
+    expect(dialogSpyObj.open).toHaveBeenCalledWith(jasmine.any(Function), jasmine.objectContaining({
+      data: jasmine.objectContaining({ type: 'info' }),
+    }));
+  });
+
   it('should handle table errors', () => {
     fetchServiceSpy.getTableWithErrors.and.returnValue(
       of([{ Name: 'TableA', Id: 't1' }, { Name: 'TableB', Id: 't2' }])
@@ -61,6 +61,7 @@ export class WorkspaceComponent implements OnInit, OnDestroy {
   conversionRatePercentages: ConversionRate = { good: 0, ok: 0, bad: 0 }
   currentDatabase: string = 'spanner'
   dialect: string = ''
+  jsonPathSuggestionCount: number = 0
   structuredReport!: IStructuredReport
   ccData: ICcTabData[] = []
   constructor(
@@ -156,6 +157,10 @@ export class WorkspaceComponent implements OnInit, OnDestroy {
         )
       }
       this.dialect = (this.conv.SpDialect === "postgresql") ? "PostgreSQL" : "Google Standard SQL"
+      this.jsonPathSuggestionCount = Object.values(this.conv.JSONPathSuggestions ?? {}).reduce(
+        (count, suggestions) => count + suggestions.length,
+        0
+      )
     })
 
     this.converObj = this.data.conversionRate.subscribe((rates: any) => {
@@ -287,6 +292,27 @@ export class WorkspaceComponent implements OnInit, OnDestroy {
     }
     this.clickEvent.setViewAssesmentData(viewAssesmentData)
   }
+  addJsonPathIndexes() {
+    this.data.addJsonPathIndexes().subscribe((error: string) => {
+      if (error) {
+        this.dialog.open(InfodialogComponent, {
+          data: { message: error, type: 'error', title: 'Error' },
+          maxWidth: '500px',
+        })
+      } else {
+        this.dialog.open(InfodialogComponent, {
+          data: {
+            message:
+              'Generated columns and indexes were added for the JSON paths the application filters or orders rows by. Use the generated columns in the queries of the application.',
+            type: 'info',
+            title: 'Info',
+          },
+          maxWidth: '500px',
+        })
+      }
+    })
+  }
+
   openSaveSessionSidenav() {
     this.sidenav.openSidenav()
     this.sidenav.setSidenavComponent('saveSession')
@@ -397,6 +423,20 @@ export class WorkspaceComponent implements OnInit, OnDestroy {
     })
   }
 
+  // downloads a zip of the Spanner DDL, reports, issues and mapping rules of the session.
+  downloadArtifacts(){
+    var a = document.createElement('a')
+    this.fetch.getArtifactsZip().subscribe({
+      next: (res: Blob) => {
+        const url = URL.createObjectURL(res)
+        a.href = url
+        a.download = `${this.conv.DatabaseName}_migration_artifacts.zip`
+        a.click()
+        URL.revokeObjectURL(url)
+      }
+    })
+  }
+
   updateSpannerTable(data: IUpdateTableArgument) {
     this.spannerTree = this.conversion.createTreeNode(
       this.conv,
@@ -22,6 +22,47 @@ export default interface IConv {
   IsSharded: boolean
   SpSequences: Record<string, ICreateSequence>
   SrcSequences: Record<string, ICreateSequence>
+  NameTemplates?: INameTemplates
+  SpViews?: Record<string, IView>
+  DroppedObjects?: Record<string, IDroppedObject[]>
+  ColumnStats?: Record<string, Record<string, IColumnStats>>
+  ColumnMasks?: Record<string, Record<string, IColumnMask>>
+  JSONPathSuggestions?: Record<string, IJSONPathSuggestion[]>
+}
+
+export interface IJSONPathSuggestion {
+  ColId: string
+  Path: string
+  Count: number
+}
+
+export interface IColumnMask {
+  Strategy: '' | 'hash' | 'shuffle' | 'synthetic' | 'nullify'
+  Kind?: string
+}
+
+export interface IColumnStats {
+  SampledRows: number
+  NullCount: number
+  Min: string
+  Max: string
+  LengthP50: number
+  LengthP95: number
+  MaxLength: number
+  MaxIntegerDigits: number
+  MaxScale: number
+}
+
+export interface IDroppedObject {
+  Kind: string
+  Name: string
+  Statement: string
+}
+
+export interface IView {
+  Id: string
+  Name: string
+  Query: string
 }
 
 export interface IDefaultValue {
@@ -70,6 +111,7 @@ export interface ITable {
   ForeignKeys: IForeignKey[]
   CheckConstraints: ICheckConstraints[]
   Indexes: IIndex[]
+  Comment: string
 }
 
 export interface IColumn {
@@ -81,6 +123,7 @@ export interface IColumn {
   AutoGen: AutoGen
   DefaultValue: IDefaultValue
   GeneratedColumn: IGeneratedColumn
+  Comment: string
 }
 
 export interface IIgnored {
@@ -121,11 +164,23 @@ export interface ICreateTable {
   ForeignKeys: IForeignKey[]
   CheckConstraints: ICheckConstraints[]
   Indexes: ICreateIndex[]
+  VectorIndexes?: IVectorIndex[]
   ParentTable: IInterleavedParent
   Comment: string
   Id: string
 }
 
+export interface IVectorIndex {
+  Name: string
+  Id: string
+  ColId: string
+  StoredColumnIds?: string[]
+  DistanceType: string
+  TreeDepth?: number
+  NumLeaves?: number
+  NumBranches?: number
+}
+
 export interface ICreateIndex {
   Name: string
   TableId: string
@@ -151,6 +206,18 @@ export interface ICheckConstraints {
   ExprId: string
 }
 
+export interface IVerifyExpression {
+  TableId: string
+  ColId: string
+  Type: string
+  Expression: string
+}
+
+export interface IVerifyExpressionResponse {
+  Valid: boolean
+  Error: string
+}
+
 export interface IIndexKey {
   ColId: string
   Desc: boolean
@@ -179,11 +246,27 @@ export interface IType {
   Name: string
   Len: Number
   IsArray: boolean
+  VectorLength?: number
 }
 
 export interface ISyntheticPKey {
   ColId: string
   Sequence: Number
+  Strategy: string
+  SequenceId: string
+}
+
+export interface ISyntheticPrimaryKey {
+  TableId: string
+  Strategy: string
+  Columns: IIndexKey[]
+}
+
+export interface INameTemplates {
+  SyntheticPKey: string
+  ShardIdColumn: string
+  Index: string
+  Sequence: string
 }
 export interface ITableInterleaveStatus {
   Possible: boolean
@@ -191,12 +274,50 @@ export interface ITableInterleaveStatus {
   OnDeleteAction: string
   Parent: string
   Comment: string
+  Warning: string
 }
 
 export interface IInterleaveStatus {
   TableInterleaveStatus: ITableInterleaveStatus
 }
 
+export interface IInterleaveNode {
+  TableId: string
+  Name: string
+  InterleaveType: string
+  OnDelete: string
+  Depth: number
+  Children: IInterleaveNode[]
+}
+
+export interface IInterleaveTree {
+  Roots: IInterleaveNode[]
+  MaxDepth: number
+  Cycles: string[][]
+  TooDeep: string[]
+}
+
+export interface IInterleaveCandidate {
+  TableId: string
+  ParentTableId: string
+  ForeignKeyId: string
+  ForeignKey: string
+  OnDelete: string
+}
+
+export interface INamedObject {
+  Kind: string
+  TableId: string
+  Id: string
+  Name: string
+  Proposed: string
+}
+
+export interface INameConflict {
+  Name: string
+  Objects: INamedObject[]
+}
+
 export interface IPrimaryKey {
   TableId: string
   Columns: IIndexKey[]
@@ -18,6 +18,7 @@ export default interface IColumnTabData {
   srcDefaultValue: string
   srcGeneratedColExp: string
   srcGeneratedColExpType: string
+  srcComment: string
   srcId: string
   spId: string
   srcColMaxLength: Number | string | undefined
@@ -27,6 +28,7 @@ export default interface IColumnTabData {
   spDefaultValue: IDefaultValue
   spGeneratedColumn: string
   spGeneratedColumnType: string
+  spMask?: string
 }
 
 export interface AutoGen {
@@ -1,4 +1,4 @@
-import {IDefaultValue, IGeneratedColumn} from "./conv"
+import {IColumnMask, IDefaultValue, IGeneratedColumn} from "./conv"
 import { AutoGen } from "./edit-table"
 
 interface IUpdateCol {
@@ -11,6 +11,20 @@ interface IUpdateCol {
   AutoGen: AutoGen
   DefaultValue: IDefaultValue
   GeneratedColumn: IGeneratedColumn
+  Fill?: IColumnFill
+  Timezone?: string
+  Mask?: IColumnMask
+}
+export interface IColumnFill {
+  Strategy: '' | 'constant' | 'expression' | 'uuid' | 'now'
+  Value: string
+}
+export interface IColumnTransform {
+  Type: 'merge' | 'split'
+  SrcColIds: string[]
+  SpColIds: string[]
+  Separator: string
+  Regex: string
 }
 export interface ITableColumnChanges {
   ColumnId: string
@@ -34,11 +48,40 @@ export interface IReviewUpdateTable {
   DDL: string
 }
 
+export interface IDDLLine {
+  Op: string
+  Text: string
+}
+
+export interface IDDLObjectDiff {
+  Kind: string
+  Id: string
+  Name: string
+  Status: string
+  Lines: IDDLLine[]
+}
+
+export interface IReviewUpdateTableDiff {
+  Before: string
+  After: string
+  Objects: IDDLObjectDiff[]
+}
+
 export interface IAddColumn {
   Name: string
   Datatype: string
   Length: number
   IsNullable: boolean
   AutoGen: AutoGen
+  DefaultValue?: IDefaultValue
+  GeneratedColumn?: IGeneratedColumn
   Option?: string
 }
+
+export interface IAddTable {
+  Name: string
+  Columns: IAddColumn[]
+  PrimaryKeys: { Name: string; Desc: boolean }[]
+  ParentTableId: string
+  OnDelete: string
+}
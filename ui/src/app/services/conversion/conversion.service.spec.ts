@@ -121,6 +121,7 @@ describe('ConversionService', () => {
         Name: 'test',
         Id: '1',
         Schema: '',
+        Comment: '',
         ColIds: [],
         ColDefs: {},
         PrimaryKeys: [],
@@ -184,6 +185,7 @@ describe('ConversionService', () => {
         Name: 'test',
         Id: '1',
         Schema: '',
+        Comment: '',
         ColIds: [],
         ColDefs: {},
         PrimaryKeys: [],
@@ -247,6 +249,7 @@ describe('ConversionService', () => {
         Name: 'test',
         Id: '1',
         Schema: '',
+        Comment: '',
         ColIds: [],
         ColDefs: {},
         PrimaryKeys: [],
@@ -310,6 +313,7 @@ describe('ConversionService', () => {
         Name: 'test_table',
         Id: 't1',
         Schema: '',
+        Comment: '',
         ColIds: ['c1'],
         ColDefs: {
           c1: {
@@ -321,6 +325,7 @@ describe('ConversionService', () => {
             DefaultValue: { IsPresent: false, Value: { Statement: '' , ExpressionId: '' } },
             AutoGen: { Name: '', GenerationType: '', IdentityOptions: { SkipRangeMin: '', SkipRangeMax: '', StartCounterWith: '' } },
             GeneratedColumn: { IsPresent: false, Value: { Statement: '' , ExpressionId: '' }, Type: '' },
+            Comment: '',
           },
         },
         PrimaryKeys: [],
@@ -398,8 +403,10 @@ describe('ConversionService', () => {
         },
         spGeneratedColumn: '',
         spCassandraOption: '',
+        spMask: '',
         srcGeneratedColExp: '',
         srcGeneratedColExpType: '',
+        srcComment: '',
         spGeneratedColumnType: ''
       },
     ]
@@ -413,6 +420,7 @@ describe('ConversionService', () => {
         Name: 'test_table',
         Id: 't1',
         Schema: '',
+        Comment: '',
         ColIds: ['c1'],
         ColDefs: {
           c1: {
@@ -424,6 +432,7 @@ describe('ConversionService', () => {
             DefaultValue: { IsPresent: false, Value: { Statement: '' , ExpressionId: '' } },
             AutoGen: { Name: '', GenerationType: '', IdentityOptions: { SkipRangeMin: '', SkipRangeMax: '', StartCounterWith: '' } },
             GeneratedColumn: { IsPresent: false, Value: { Statement: '' , ExpressionId: '' }, Type: '' },
+            Comment: '',
           },
         },
         PrimaryKeys: [],
@@ -507,8 +516,10 @@ describe('ConversionService', () => {
         spGeneratedColumn: '',
         spDefaultValue: {IsPresent: false, Value: {ExpressionId: '', Statement: ''}},
         spCassandraOption: '',
+        spMask: '',
         srcGeneratedColExp: '',
         srcGeneratedColExpType: '',
+        srcComment: '',
         spGeneratedColumnType: ''
       },
       {
@@ -542,8 +553,10 @@ describe('ConversionService', () => {
         },
         spDefaultValue: {IsPresent: false, Value: {ExpressionId: '', Statement: ''}},
         spCassandraOption: '',
+        spMask: '',
         srcGeneratedColExp: '',
         srcGeneratedColExpType: '',
+        srcComment: '',
         spGeneratedColumn: '',
         spGeneratedColumnType: ''
       },
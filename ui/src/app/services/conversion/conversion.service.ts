@@ -7,6 +7,7 @@ import IConv, {
   IForeignKey,
   ISrcIndexKey,
   IColumnDef,
+  IColumnMask,
 } from '../../model/conv'
 import IColumnTabData, { IIndexData, ISequenceData } from '../../model/edit-table'
 import IFkTabData from 'src/app/model/fk-tab-data'
@@ -285,7 +286,7 @@ export class ConversionService {
   }
 
   getCheckConstraints(tableId: string, data: IConv): ICcTabData[] {
-    let srcArr = data.SrcSchema[tableId].CheckConstraints || []
+    let srcArr = data.SrcSchema[tableId]?.CheckConstraints || []
     let spArr = data.SpSchema[tableId].CheckConstraints || []
     let res: ICcTabData[] = []
      if (srcArr.length > spArr.length) {
@@ -321,16 +322,17 @@ export class ConversionService {
 
   getColumnMapping(tableId: string, data: IConv): IColumnTabData[] {
     let spTableName = this.getSpannerTableNameFromId(tableId, data)
-    let srcColIds = data.SrcSchema[tableId].ColIds
+    // Tables added in Spanner have no source table.
+    let srcColIds = data.SrcSchema[tableId]?.ColIds || []
     let spColIds = data.SpSchema[tableId] ? data.SpSchema[tableId].ColIds : null
-    let srcPks = data.SrcSchema[tableId].PrimaryKeys
+    let srcPks = data.SrcSchema[tableId]?.PrimaryKeys
     let spPks = spColIds ? data.SpSchema[tableId].PrimaryKeys : null
     let standardTypeToPGSQLTypeMap: Map<String, String>
     this.standardTypeToPGSQLTypeMap.subscribe((typemap) => {
       standardTypeToPGSQLTypeMap = typemap
     })
     const spColMax = ColLength.StorageMaxLength
-    const res: IColumnTabData[] = data.SrcSchema[tableId].ColIds.map((colId: string, i: number) => {
+    const res: IColumnTabData[] = srcColIds.map((colId: string, i: number) => {
       let spPkOrder
       if (spTableName) {
         data.SpSchema[tableId].PrimaryKeys.forEach((pk: IIndexKey) => {
@@ -373,6 +375,7 @@ export class ConversionService {
         srcDefaultValue: data.SrcSchema[tableId].ColDefs[colId].DefaultValue.Value.Statement,
         srcGeneratedColExp: data.SrcSchema[tableId].ColDefs[colId].GeneratedColumn.Value.Statement,
         srcGeneratedColExpType: data.SrcSchema[tableId].ColDefs[colId].GeneratedColumn.Type,
+        srcComment: data.SrcSchema[tableId].ColDefs[colId].Comment ?? '',
         spId: spannerColDef ? colId : '',
         spColMaxLength: spannerColDef?.T.Len != 0 ? (spannerColDef?.T.Len != spColMax ? spannerColDef?.T.Len: 'MAX') : '',
         srcColMaxLength: data.SrcSchema[tableId].ColDefs[colId].Type.Mods != null ? data.SrcSchema[tableId].ColDefs[colId].Type.Mods[0] : '',
@@ -399,6 +402,7 @@ export class ConversionService {
         spGeneratedColumn: spannerColDef?.GeneratedColumn != null ? spannerColDef?.GeneratedColumn.Value.Statement : '',
         spGeneratedColumnType: spannerColDef?.GeneratedColumn?.Type || '',
         spCassandraOption: spannerColDef?.Opts?.["cassandra_type"] || '',
+        spMask: spannerColDef ? this.columnMaskValue(data.ColumnMasks?.[tableId]?.[colId]) : '',
         }
     })
     if (spColIds) {
@@ -422,6 +426,7 @@ export class ConversionService {
             srcDefaultValue: '',
             srcGeneratedColExp: '',
             srcGeneratedColExpType: '',
+            srcComment: '',
             spId: colId,
             srcColMaxLength: '',
             spColMaxLength: spannerColDef?.T.Len,
@@ -448,6 +453,7 @@ export class ConversionService {
             },
             spGeneratedColumn: spannerColDef?.GeneratedColumn != null ? spannerColDef?.GeneratedColumn.Value.Statement : '',
             spGeneratedColumnType: spannerColDef?.GeneratedColumn?.Type || '',
+            spMask: this.columnMaskValue(data.ColumnMasks?.[tableId]?.[colId]),
           })
         }
       })
@@ -456,6 +462,15 @@ export class ConversionService {
     return res
   }
 
+  // columnMaskValue returns the value of a column mask edited in the column
+  // table, e.g. 'hash' or 'synthetic:email'.
+  columnMaskValue(mask: IColumnMask | undefined): string {
+    if (!mask || !mask.Strategy) {
+      return ''
+    }
+    return mask.Kind && mask.Kind !== 'text' ? `${mask.Strategy}:${mask.Kind}` : mask.Strategy
+  }
+
   getPkMapping(tableData: IColumnTabData[]): IColumnTabData[] {
     let pkColumns = tableData.filter((column: IColumnTabData) => {
       return column.spIsPk || column.srcIsPk
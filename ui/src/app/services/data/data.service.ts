@@ -1,6 +1,6 @@
 import { Injectable } from '@angular/core'
 import { FetchService } from '../fetch/fetch.service'
-import IConv, { ICheckConstraints, ICreateIndex, IForeignKey, IInterleaveStatus, IPrimaryKey, ITableInterleaveStatus } from '../../model/conv'
+import IConv, { ICheckConstraints, ICreateIndex, IForeignKey, IInterleaveStatus, IPrimaryKey, ISyntheticPrimaryKey, ITableInterleaveStatus, IVectorIndex } from '../../model/conv'
 import IRule from 'src/app/model/rule'
 import { BehaviorSubject, forkJoin, Observable, of, Subject } from 'rxjs'
 import { catchError, filter, map, tap } from 'rxjs/operators'
@@ -375,6 +375,24 @@ export class DataService {
     )
   }
 
+  updateSyntheticPk(synthPkObj: ISyntheticPrimaryKey) {
+    return this.fetch.updateSyntheticPk(synthPkObj).pipe(
+      catchError((e: any) => {
+        return of({ error: e.error })
+      }),
+      tap(console.log),
+      map((data: any) => {
+        if (data.error) {
+          return data.error
+        } else {
+          this.convSubject.next(data)
+          this.getDdl()
+          return ''
+        }
+      })
+    )
+  }
+
   updateCheckConstraint(tableId: string, updatedCC: ICheckConstraints[]): Observable<string> {
     return this.fetch.updateCheckConstraint(tableId, updatedCC).pipe(
       catchError((e: any) => {
@@ -394,6 +412,62 @@ export class DataService {
     );
   }
 
+  updateVectorIndex(tableId: string, payload: IVectorIndex): Observable<string> {
+    return this.fetch.updateVectorIndex(tableId, payload).pipe(
+      catchError((e: any) => {
+        return of({ error: e.error })
+      }),
+      tap(console.log),
+      map((data: any) => {
+        if (data.error) {
+          return data.error
+        } else {
+          this.convSubject.next(data)
+          this.getDdl()
+          return ''
+        }
+      })
+    )
+  }
+
+  dropVectorIndex(tableId: string, indexId: string): Observable<string> {
+    return this.fetch.dropVectorIndex(tableId, indexId).pipe(
+      catchError((e: any) => {
+        return of({ error: e.error })
+      }),
+      tap(console.log),
+      map((data: any) => {
+        if (data.error) {
+          this.snackbar.openSnackBar(data.error, 'Close')
+          return data.error
+        } else {
+          this.convSubject.next(data)
+          this.getDdl()
+          this.snackbar.openSnackBar('Vector index dropped successfully', 'Close', 5)
+          return ''
+        }
+      })
+    )
+  }
+
+  addJsonPathIndexes(): Observable<string> {
+    return this.fetch.addJsonPathIndexes().pipe(
+      catchError((e: any) => {
+        return of({ error: e.error })
+      }),
+      tap(console.log),
+      map((data: any) => {
+        if (data.error) {
+          return data.error
+        } else {
+          this.convSubject.next(data)
+          this.getDdl()
+          return ''
+        }
+      })
+    )
+  }
+
   verifyCheckConstraintExpression(): Observable<boolean> {
     return this.fetch.verifyCheckConstraintExpression().pipe(
       catchError((e: any) => {
@@ -617,6 +691,24 @@ export class DataService {
     })
   }
 
+  convertInterleaveCandidate(tableId: string): Observable<string> {
+    return this.fetch.convertInterleaveCandidate(tableId).pipe(
+      catchError((e: any) => {
+        return of({ error: e.error })
+      }),
+      tap(console.log),
+      map((data: any) => {
+        if (data.error) {
+          return data.error
+        } else {
+          this.convSubject.next(data)
+          this.getDdl()
+          return ''
+        }
+      })
+    )
+  }
+
   setInterleave(tableId: string, interleaveType: string, interleaveParentName: string, onDeleteAction: string): Observable<string> {
     return this.fetch.setInterleave(tableId, interleaveType, interleaveParentName, onDeleteAction).pipe(
       catchError((e: any) => {
@@ -2,15 +2,24 @@ import { HttpClient, HttpResponse } from '@angular/common/http'
 import { Injectable } from '@angular/core'
 import IDbConfig, { IDbConfigs } from 'src/app/model/db-config'
 import ISession, { ISaveSessionPayload } from '../../model/session'
-import IUpdateTable, { IAddColumn, IReviewUpdateTable } from '../../model/update-table'
+import IUpdateTable, { IAddColumn, IAddTable, IColumnTransform, IReviewUpdateTable, IReviewUpdateTableDiff } from '../../model/update-table'
 import IConv, {
   ICheckConstraints,
   ICreateIndex,
   IForeignKey,
   IInterleaveStatus,
+  IInterleaveCandidate,
+  IInterleaveTree,
+  INameConflict,
+  INameTemplates,
   IPrimaryKey,
+  ISyntheticPrimaryKey,
   ISessionSummary,
   ITableIdAndName,
+  IVerifyExpression,
+  IVerifyExpressionResponse,
+  IVectorIndex,
+  IView,
 } from '../../model/conv'
 import IDumpConfig, { IConvertFromDumpRequest } from '../../model/dump-config'
 import ISessionConfig from '../../model/session-config'
@@ -137,6 +146,10 @@ export class FetchService {
     return this.http.get<string>(`${this.url}/downloadDDLWoComments`)
   }
 
+  getArtifactsZip(){
+    return this.http.get(`${this.url}/downloadArtifacts`, { responseType: 'blob' })
+  }
+
   getIssueDescription(){
     return this.http.get<{[key: string]: string}>(`${this.url}/issueDescription`)
   }
@@ -204,6 +217,13 @@ export class FetchService {
     )
   }
 
+  reviewTableUpdateDiff(tableName: string, data: IUpdateTable) {
+    return this.http.post<IReviewUpdateTableDiff>(
+      `${this.url}/typemap/reviewTableSchemaDiff?table=${tableName}`,
+      data
+    )
+  }
+
   updateTable(tableName: string, data: IUpdateTable): any {
     return this.http.post<HttpResponse<IConv>>(`${this.url}/typemap/table?table=${tableName}`, data)
   }
@@ -220,10 +240,24 @@ export class FetchService {
     return this.http.get(`${this.url}/verifyCheckConstraintExpression`)
   }
 
+  verifyExpression(payload: IVerifyExpression) {
+    return this.http.post<IVerifyExpressionResponse>(`${this.url}/verifyExpression`, payload)
+  }
+
   updateCheckConstraint(tableId: string, payload: ICheckConstraints[]): any {
     return this.http.post<HttpResponse<IConv>>(`${this.url}/update/cc?table=${tableId}`, payload)
   }
 
+  updateVectorIndex(tableId: string, payload: IVectorIndex) {
+    return this.http.post<IConv>(`${this.url}/update/vectorIndex?table=${tableId}`, payload)
+  }
+
+  dropVectorIndex(tableId: string, indexId: string) {
+    return this.http.post<IConv>(`${this.url}/drop/vectorIndex?table=${tableId}`, {
+      Id: indexId,
+    })
+  }
+
   restoreTable(tableId: string) {
     return this.http.post<HttpResponse<IConv>>(`${this.url}/restore/table?table=${tableId}`, {})
   }
@@ -231,6 +265,21 @@ export class FetchService {
     return this.http.post<HttpResponse<IConv>>(`${this.url}/drop/table?table=${tableId}`, {})
   }
 
+  addJsonPathIndexes() {
+    return this.http.post<IConv>(`${this.url}/add/jsonPathIndexes`, {})
+  }
+
+  bulkDrop(foreignKeys: boolean, secondaryIndexes: boolean) {
+    return this.http.post<IConv>(`${this.url}/drop/bulk`, {
+      ForeignKeys: foreignKeys,
+      SecondaryIndexes: secondaryIndexes,
+    })
+  }
+
+  renameTable(tableId: string, name: string) {
+    return this.http.post<IConv>(`${this.url}/rename/table?table=${tableId}`, { Name: name })
+  }
+
   dropTables(payload: ITables) {
     return this.http.post(`${this.url}/drop/tables`, payload)
   }
@@ -239,6 +288,10 @@ export class FetchService {
     return this.http.post<HttpResponse<IConv>>(`${this.url}/primaryKey`, pkObj)
   }
 
+  updateSyntheticPk(synthPkObj: ISyntheticPrimaryKey) {
+    return this.http.post<HttpResponse<IConv>>(`${this.url}/syntheticPrimaryKey`, synthPkObj)
+  }
+
   updateFk(tableId: string, payload: IForeignKey[]): any {
     return this.http.post<HttpResponse<IConv>>(`${this.url}/update/fks?table=${tableId}`, payload)
   }
@@ -247,6 +300,10 @@ export class FetchService {
     return this.http.post(`${this.url}/AddColumn?table=${tableId}`, payload)
   }
 
+  addTable(payload: IAddTable) {
+    return this.http.post<IConv>(`${this.url}/AddTable`, payload)
+  }
+
   addSequence(payload: ICreateSequence) {
     return this.http.post(`${this.url}/AddSequence`, payload)
   }
@@ -299,6 +356,14 @@ export class FetchService {
     return this.http.post<IConv>(`${this.url}/update/indexes?table=${tableId}`, payload)
   }
 
+  updateNameTemplates(payload: INameTemplates) {
+    return this.http.post<IConv>(`${this.url}/update/nameTemplates`, payload)
+  }
+
+  updateColumnTransforms(tableId: string, payload: IColumnTransform[]) {
+    return this.http.post<IConv>(`${this.url}/update/columnTransforms?table=${tableId}`, payload)
+  }
+
   updateSequence(payload: ICreateSequence) {
     return this.http.post<IConv>(`${this.url}/UpdateSequence`, payload)
   }
@@ -313,6 +378,26 @@ export class FetchService {
     return this.http.post<IConv>(`${this.url}/drop/sequence?sequence=${sequenceId}`, {})
   }
 
+  getViews() {
+    return this.http.get<IView[]>(`${this.url}/views`)
+  }
+
+  addView(payload: IView) {
+    return this.http.post<IConv>(`${this.url}/AddView`, payload)
+  }
+
+  updateView(payload: IView) {
+    return this.http.post<IConv>(`${this.url}/UpdateView`, payload)
+  }
+
+  verifyView(payload: IView) {
+    return this.http.post<IVerifyExpressionResponse>(`${this.url}/verifyView`, payload)
+  }
+
+  dropView(viewId: string) {
+    return this.http.post<IConv>(`${this.url}/drop/view?id=${viewId}`, {})
+  }
+
   restoreIndex(tableId: string, indexId: string) {
     return this.http.post<HttpResponse<IConv>>(
       `${this.url}/restore/secondaryIndex?tableId=${tableId}&indexId=${indexId}`,
@@ -328,6 +413,30 @@ export class FetchService {
     return this.http.get(`${this.url}/setparent?table=${tableId}&interleaveType=${interleaveType}&parentTable=${interleaveParentName}&onDelete=${onDeleteAction}&update=true`)
   }
 
+  setInterleaveOnDelete(tableId: string, onDeleteAction: string) {
+    return this.http.post<IConv>(`${this.url}/setInterleaveOnDelete?tableId=${tableId}&onDelete=${onDeleteAction}`, {})
+  }
+
+  getInterleaveTree() {
+    return this.http.get<IInterleaveTree>(`${this.url}/interleaveTree`)
+  }
+
+  getInterleaveCandidates() {
+    return this.http.get<IInterleaveCandidate[]>(`${this.url}/interleaveCandidates`)
+  }
+
+  convertInterleaveCandidate(tableId: string) {
+    return this.http.post<IConv>(`${this.url}/convertInterleaveCandidate?tableId=${tableId}`, {})
+  }
+
+  getNameConflicts() {
+    return this.http.get<INameConflict[]>(`${this.url}/nameConflicts`)
+  }
+
+  resolveNameConflicts() {
+    return this.http.post<IConv>(`${this.url}/resolveNameConflicts`, {})
+  }
+
   getSourceDestinationSummary() {
     return this.http.get<ISessionSummary>(`${this.url}/GetSourceDestinationSummary`)
   }
@@ -0,0 +1,102 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/auth"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
+)
+
+// maxAuditPayload is the number of bytes of a request payload kept in the
+// audit log.
+const maxAuditPayload = 512
+
+// statusRecorder captures the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// AuditSchemaEdit records the successful calls of a handler which modifies
+// the schema in the audit log of the session, along with the authenticated
+// user.
+func AuditSchemaEdit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var payload []byte
+		if r.Body != nil {
+			payload, _ = io.ReadAll(r.Body)
+			r.Body = io.NopCloser(bytes.NewReader(payload))
+		}
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+		if rec.status >= http.StatusBadRequest {
+			return
+		}
+		session.RequestSessionState(r).UpdateConv(func(conv *internal.Conv) error {
+			conv.Audit.SchemaEdits = append(conv.Audit.SchemaEdits, internal.SchemaEdit{
+				Timestamp: time.Now(),
+				User:      auth.UserFromContext(r.Context()),
+				Method:    r.Method,
+				Endpoint:  r.URL.RequestURI(),
+				Payload:   summarizePayload(payload),
+			})
+			return nil
+		})
+	}
+}
+
+// GetAuditLog returns the schema edits made in the session.
+func GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	edits := []internal.SchemaEdit{}
+	session.RequestSessionState(r).ReadConv(func(conv *internal.Conv) error {
+		edits = getSchemaEdits(conv)
+		return nil
+	})
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(edits)
+}
+
+func getSchemaEdits(conv *internal.Conv) []internal.SchemaEdit {
+	if conv.Audit.SchemaEdits == nil {
+		return []internal.SchemaEdit{}
+	}
+	return conv.Audit.SchemaEdits
+}
+
+// summarizePayload compacts a JSON payload and truncates it to
+// maxAuditPayload bytes.
+func summarizePayload(payload []byte) string {
+	var b bytes.Buffer
+	if err := json.Compact(&b, payload); err == nil {
+		payload = b.Bytes()
+	}
+	if len(payload) > maxAuditPayload {
+		return strings.ToValidUTF8(string(payload[:maxAuditPayload]), "") + "..."
+	}
+	return string(payload)
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/api"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditSchemaEdit(t *testing.T) {
+	session.GetSessionState().Conv = internal.MakeConv()
+	var body string
+	handler := api.AuditSchemaEdit(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		if r.URL.Query().Get("fail") != "" {
+			http.Error(w, "failed", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("POST", "/rename/table?table=t1", strings.NewReader(`{ "NewName": "orders" }`))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	// The handler still gets the payload.
+	assert.Equal(t, `{ "NewName": "orders" }`, body)
+
+	req = httptest.NewRequest("POST", "/rename/table?fail=true", strings.NewReader(`{}`))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(api.GetAuditLog).ServeHTTP(rr, httptest.NewRequest("GET", "/audit", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var edits []internal.SchemaEdit
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &edits))
+	assert.Len(t, edits, 1)
+	assert.Equal(t, "POST", edits[0].Method)
+	assert.Equal(t, "/rename/table?table=t1", edits[0].Endpoint)
+	assert.Equal(t, `{"NewName":"orders"}`, edits[0].Payload)
+	assert.Equal(t, "", edits[0].User)
+	assert.False(t, edits[0].Timestamp.IsZero())
+}
+
+func TestGetAuditLogEmpty(t *testing.T) {
+	session.GetSessionState().Conv = internal.MakeConv()
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(api.GetAuditLog).ServeHTTP(rr, httptest.NewRequest("GET", "/audit", nil))
+	assert.Equal(t, "[]\n", rr.Body.String())
+}
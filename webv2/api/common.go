@@ -2,19 +2,20 @@ package api
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/index"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/utilities"
 )
 
-func dropSecondaryIndexHelper(tableId, idxId string) error {
+func dropSecondaryIndexHelper(sessionState *session.SessionState, conv *internal.Conv, tableId, idxId string) error {
 	if tableId == "" || idxId == "" {
 		return fmt.Errorf("Table id or index id is empty")
 	}
-	sessionState := session.GetSessionState()
-	sp := sessionState.Conv.SpSchema[tableId]
+	sp := conv.SpSchema[tableId]
 	position := -1
 	for i, index := range sp.Indexes {
 		if idxId == index.Id {
@@ -26,12 +27,38 @@ func dropSecondaryIndexHelper(tableId, idxId string) error {
 		return fmt.Errorf("No secondary index found at position %d", position)
 	}
 
-	usedNames := sessionState.Conv.UsedNames
+	usedNames := conv.UsedNames
 	delete(usedNames, strings.ToLower(sp.Indexes[position].Name))
-	index.RemoveIndexIssues(tableId, sp.Indexes[position])
+	index.RemoveIndexIssues(conv, tableId, sp.Indexes[position])
 
 	sp.Indexes = utilities.RemoveSecondaryIndex(sp.Indexes, position)
-	sessionState.Conv.SpSchema[tableId] = sp
-	session.UpdateSessionFile()
+	conv.SpSchema[tableId] = sp
+	session.UpdateSessionFile(sessionState)
 	return nil
-}
\ No newline at end of file
+}
+
+// readConv calls fn with the conversion of the session, holding its read lock,
+// and responds with an error if the schema isn't converted. fn writes the
+// response.
+func readConv(w http.ResponseWriter, sessionState *session.SessionState, fn func(conv *internal.Conv)) {
+	err := sessionState.ReadConv(func(conv *internal.Conv) error {
+		fn(conv)
+		return nil
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+	}
+}
+
+// updateConv calls fn with the conversion of the session, holding its write
+// lock, and responds with an error if the schema isn't converted. fn writes
+// the response.
+func updateConv(w http.ResponseWriter, sessionState *session.SessionState, fn func(conv *internal.Conv)) {
+	err := sessionState.UpdateConv(func(conv *internal.Conv) error {
+		fn(conv)
+		return nil
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+	}
+}
@@ -1,10 +1,12 @@
 package api
 
 import (
+	"archive/zip"
 	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -13,6 +15,7 @@ import (
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/conversion"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal/reports"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
@@ -26,94 +29,198 @@ type ReportAPIHandler struct {
 
 // getReportFile generates report file and returns file path.
 func (reportHandler *ReportAPIHandler) GetReportFile(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
 	ioHelper := &utils.IOStreams{In: os.Stdin, Out: os.Stdout}
 	var err error
 	now := time.Now()
-	filePrefix, err := utilities.GetFilePrefix(now)
+	filePrefix, err := utilities.GetFilePrefix(sessionState, now)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Can not get file prefix : %v", err), http.StatusInternalServerError)
 	}
 	reportFileName := "frontend/" + filePrefix
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	reportHandler.Report.GenerateReport(sessionState.Driver, nil, ioHelper.BytesRead, "", sessionState.Conv, reportFileName, sessionState.DbName, ioHelper.Out)
-	reportAbsPath, err := filepath.Abs(reportFileName)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Can not create absolute path : %v", err), http.StatusInternalServerError)
-	}
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(reportAbsPath))
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		reportHandler.Report.GenerateReport(sessionState.Driver, nil, ioHelper.BytesRead, "", conv, reportFileName, sessionState.DbName, ioHelper.Out)
+		reportAbsPath, err := filepath.Abs(reportFileName)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Can not create absolute path : %v", err), http.StatusInternalServerError)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(reportAbsPath))
+	})
 }
 
 // generates a downloadable structured report and send it as a JSON response
 func (reportHandler *ReportAPIHandler) GetDStructuredReport(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	structuredReport := reportHandler.ReportGenerator.GenerateStructuredReport(sessionState.Driver, sessionState.DbName, sessionState.Conv, nil, true, true)
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(structuredReport)
+	sessionState := session.RequestSessionState(r)
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		structuredReport := reportHandler.ReportGenerator.GenerateStructuredReport(sessionState.Driver, sessionState.DbName, conv, nil, true, true)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(structuredReport)
+	})
+}
+
+// GetJSONReport returns the versioned JSON migration report of the session.
+func GetJSONReport(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		jsonReport := reports.GenerateJSONReport(sessionState.Driver, sessionState.DbName, conv, nil)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(jsonReport)
+	})
+}
+
+// GetSchemaSummary returns the counts of the source and Spanner schema objects
+// of the session, along with the complexity of the migration.
+func GetSchemaSummary(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(reports.GenerateObjectSummary(conv))
+	})
 }
 
 // generates a downloadable text report and send it as a JSON response
 func (reportHandler *ReportAPIHandler) GetDTextReport(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	structuredReport := reportHandler.ReportGenerator.GenerateStructuredReport(sessionState.Driver, sessionState.DbName, sessionState.Conv, nil, true, true)
-	// creates a new buffer
-	buffer := bytes.NewBuffer([]byte{})
-	// initializes buffered writer that writes data to buffer
-	wb := bufio.NewWriter(buffer)
-	reportHandler.ReportGenerator.GenerateTextReport(structuredReport, wb)
-	// flushes buffered data to writer
-	wb.Flush()
-	// introduces a byte slice to represent the content of buffer
-	data := buffer.Bytes()
-	// converts byte slice to corressponding string representation
-	decodedString := string(data)
-	w.WriteHeader(http.StatusOK)
-	w.Header().Set("Content-Type", "text/plain")
-	json.NewEncoder(w).Encode(decodedString)
+	sessionState := session.RequestSessionState(r)
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		structuredReport := reportHandler.ReportGenerator.GenerateStructuredReport(sessionState.Driver, sessionState.DbName, conv, nil, true, true)
+		// creates a new buffer
+		buffer := bytes.NewBuffer([]byte{})
+		// initializes buffered writer that writes data to buffer
+		wb := bufio.NewWriter(buffer)
+		reportHandler.ReportGenerator.GenerateTextReport(structuredReport, wb)
+		// flushes buffered data to writer
+		wb.Flush()
+		// introduces a byte slice to represent the content of buffer
+		data := buffer.Bytes()
+		// converts byte slice to corressponding string representation
+		decodedString := string(data)
+		w.WriteHeader(http.StatusOK)
+		w.Header().Set("Content-Type", "text/plain")
+		json.NewEncoder(w).Encode(decodedString)
+	})
 }
 
 // generates a downloadable DDL(spanner) and send it as a JSON response
 func GetDSpannerDDL(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.RLock()
-	defer sessionState.Conv.ConvLock.RUnlock()
-	conv := sessionState.Conv
-	now := time.Now()
-	spDDL := ddl.GetDDL(ddl.Config{Comments: true, ProtectIds: false, Tables: true, ForeignKeys: true, SpDialect: conv.SpDialect, Source: sessionState.Driver}, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions)
-	if len(spDDL) == 0 {
-		spDDL = []string{"\n-- Schema is empty -- no tables found\n"}
-	}
-	l := []string{
-		fmt.Sprintf("-- Schema generated %s\n", now.Format("2006-01-02 15:04:05")),
-		strings.Join(spDDL, ";\n\n"),
-		"\n",
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(strings.Join(l, ""))
+	sessionState := session.RequestSessionState(r)
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(getDDLFile(conv, sessionState.Driver, true, false))
+	})
 }
 
 // generates a downloadable DDL(spanner) without comments and send it as a JSON response
 func GetSpannerDDLWoComments(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.RLock()
-	defer sessionState.Conv.ConvLock.RUnlock()
-	conv := sessionState.Conv
-	now := time.Now()
-	spDDL := ddl.GetDDL(ddl.Config{Comments: false, ProtectIds: true, Tables: true, ForeignKeys: true, SpDialect: conv.SpDialect, Source: sessionState.Driver}, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions)
+	sessionState := session.RequestSessionState(r)
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(getDDLFile(conv, sessionState.Driver, false, true))
+	})
+}
+
+// artifact is a named file bundled into the artifacts zip.
+type artifact struct {
+	name    string
+	content []byte
+}
+
+// GetArtifactsZip bundles the Spanner DDL, the structured and text reports,
+// the list of issues and the applied mapping rules of the current session
+// into a single zip file, with the script creating the foreign keys and
+// indexes dropped in bulk if any.
+func (reportHandler *ReportAPIHandler) GetArtifactsZip(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		structuredReport := reportHandler.ReportGenerator.GenerateStructuredReport(sessionState.Driver, sessionState.DbName, conv, nil, true, true)
+
+		buffer := bytes.NewBuffer([]byte{})
+		wb := bufio.NewWriter(buffer)
+		reportHandler.ReportGenerator.GenerateTextReport(structuredReport, wb)
+		wb.Flush()
+
+		var artifacts []artifact
+		artifacts = append(artifacts, artifact{name: "spanner_ddl.sql", content: []byte(getDDLFile(conv, sessionState.Driver, false, true))})
+		artifacts = append(artifacts, artifact{name: "spanner_ddl_with_comments.txt", content: []byte(getDDLFile(conv, sessionState.Driver, true, false))})
+		artifacts = append(artifacts, artifact{name: "report.txt", content: buffer.Bytes()})
+		if script := conversion.GetDeferredDdlScript(conv, conv.SpProjectId, conv.SpInstanceId, "", time.Now()); script != "" {
+			artifacts = append(artifacts, artifact{name: "deferred_ddl.sh", content: []byte(script)})
+		}
+		for _, f := range []struct {
+			name string
+			v    interface{}
+		}{
+			{"structured_report.json", structuredReport},
+			{"migration_report.json", reports.GenerateJSONReport(sessionState.Driver, sessionState.DbName, conv, nil)},
+			{"issues.json", getTableIssues(structuredReport)},
+			{"rules.json", conv.Rules},
+			{"audit_log.json", getSchemaEdits(conv)},
+		} {
+			content, err := json.MarshalIndent(f.v, "", "  ")
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Can not marshal %s : %v", f.name, err), http.StatusInternalServerError)
+				return
+			}
+			artifacts = append(artifacts, artifact{name: f.name, content: content})
+		}
+
+		zipBuffer := bytes.NewBuffer([]byte{})
+		if err := writeZip(zipBuffer, artifacts); err != nil {
+			http.Error(w, fmt.Sprintf("Can not create zip file : %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", sessionState.DbName+"_migration_artifacts.zip"))
+		w.WriteHeader(http.StatusOK)
+		w.Write(zipBuffer.Bytes())
+	})
+}
+
+// tableIssues lists the issues of a single table.
+type tableIssues struct {
+	SrcTableName string           `json:"srcTableName"`
+	SpTableName  string           `json:"spTableName"`
+	Issues       []reports.Issues `json:"issues"`
+}
+
+// getTableIssues extracts the issues of the tables which have any.
+func getTableIssues(structuredReport reports.StructuredReport) []tableIssues {
+	issues := []tableIssues{}
+	for _, tr := range structuredReport.TableReports {
+		if len(tr.Issues) == 0 {
+			continue
+		}
+		issues = append(issues, tableIssues{SrcTableName: tr.SrcTableName, SpTableName: tr.SpTableName, Issues: tr.Issues})
+	}
+	return issues
+}
+
+// getDDLFile generates the contents of a Spanner DDL file for conv.
+func getDDLFile(conv *internal.Conv, driver string, comments, protectIds bool) string {
+	spDDL := ddl.GetDDL(ddl.Config{Comments: comments, ProtectIds: protectIds, Tables: true, ForeignKeys: true, SpDialect: conv.SpDialect, Source: driver}, conv.SpSchema, conv.SpSequences, conv.DatabaseOptions)
+	spDDL = append(spDDL, ddl.GetViewDDL(ddl.Config{ProtectIds: protectIds, SpDialect: conv.SpDialect}, conv.SpViews)...)
+	spDDL = append(spDDL, ddl.GetAccessControlDDL(ddl.Config{ProtectIds: protectIds, SpDialect: conv.SpDialect, Source: driver}, conv.SpSchema, conv.SpRoles, conv.SpGrants)...)
 	if len(spDDL) == 0 {
 		spDDL = []string{"\n-- Schema is empty -- no tables found\n"}
 	}
 	l := []string{
-		fmt.Sprintf("-- Schema generated %s\n", now.Format("2006-01-02 15:04:05")),
+		fmt.Sprintf("-- Schema generated %s\n", time.Now().Format("2006-01-02 15:04:05")),
 		strings.Join(spDDL, ";\n\n"),
 		"\n",
 	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(strings.Join(l, ""))
+	return strings.Join(l, "")
+}
+
+func writeZip(w io.Writer, artifacts []artifact) error {
+	zw := zip.NewWriter(w)
+	for _, a := range artifacts {
+		f, err := zw.Create(a.name)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(a.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
 }
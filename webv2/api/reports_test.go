@@ -1,7 +1,9 @@
 package api_test
 
 import (
+	"archive/zip"
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -11,6 +13,7 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal/reports"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/api"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -84,6 +87,21 @@ func TestGetDTextReport(t *testing.T) {
 	assert.NotNil(t, textReport)
 }
 
+func TestGetSchemaSummary(t *testing.T) {
+	session.GetSessionState().Conv = internal.MakeConv()
+	req, err := http.NewRequest("GET", "/schemaSummary", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(api.GetSchemaSummary)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var summary reports.ObjectSummary
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &summary))
+	assert.Equal(t, reports.ObjectSummary{Complexity: "Low"}, summary)
+}
+
 func TestGetDSpannerDDL(t *testing.T) {
 	req, err := http.NewRequest("POST", "/downloadTextReport", nil)
 	if err != nil {
@@ -97,3 +115,28 @@ func TestGetDSpannerDDL(t *testing.T) {
 	assert.Contains(t, rr.Body.String(), "Schema is empty")
 	assert.Contains(t, rr.Body.String(), "no tables found")
 }
+
+func TestGetArtifactsZip(t *testing.T) {
+	session.GetSessionState().Conv = internal.MakeConv()
+	reportAPIHandler := api.ReportAPIHandler{
+		ReportGenerator: &GenerateReportMock{},
+	}
+	req, err := http.NewRequest("GET", "/downloadArtifacts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(reportAPIHandler.GetArtifactsZip)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/zip", rr.Header().Get("Content-Type"))
+	zr, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{"spanner_ddl.sql", "spanner_ddl_with_comments.txt", "report.txt", "structured_report.json", "migration_report.json", "issues.json", "rules.json", "audit_log.json"}, names)
+}
@@ -35,88 +35,88 @@ func ApplyRule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	if rule.Type == constants.GlobalDataTypeChange {
-		d, err := json.Marshal(rule.Data)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-			return
-		}
-		typeMap := map[string]string{}
-		err = json.Unmarshal(d, &typeMap)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-			return
-		}
-		setGlobalDataType(typeMap)
-	} else if rule.Type == constants.AddIndex {
-		d, err := json.Marshal(rule.Data)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-			return
-		}
-		newIdx := ddl.CreateIndex{}
-		err = json.Unmarshal(d, &newIdx)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-			return
-		}
-		addedIndex, err := addIndex(newIdx)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		rule.Data = addedIndex
-	} else if rule.Type == constants.EditColumnMaxLength {
-		d, err := json.Marshal(rule.Data)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-			return
-		}
-		var colMaxLength types.ColMaxLength
-		err = json.Unmarshal(d, &colMaxLength)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-			return
-		}
-		setSpColMaxLength(colMaxLength, rule.AssociatedObjects)
-	} else if rule.Type == constants.AddShardIdPrimaryKey {
-		d, err := json.Marshal(rule.Data)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-			return
-		}
-		var shardIdPrimaryKey types.ShardIdPrimaryKey
-		err = json.Unmarshal(d, &shardIdPrimaryKey)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-			return
-		}
-		tableName := checkInterleaving()
-		if tableName != "" {
-			http.Error(w, fmt.Sprintf("Rule cannot be added because some tables, eg: %v are interleaved. Please remove interleaving and try again.", tableName), http.StatusBadRequest)
+	sessionState := session.RequestSessionState(r)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		if rule.Type == constants.GlobalDataTypeChange {
+			d, err := json.Marshal(rule.Data)
+			if err != nil {
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+				return
+			}
+			typeMap := map[string]string{}
+			err = json.Unmarshal(d, &typeMap)
+			if err != nil {
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+				return
+			}
+			setGlobalDataType(sessionState, conv, typeMap)
+		} else if rule.Type == constants.AddIndex {
+			d, err := json.Marshal(rule.Data)
+			if err != nil {
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+				return
+			}
+			newIdx := ddl.CreateIndex{}
+			err = json.Unmarshal(d, &newIdx)
+			if err != nil {
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+				return
+			}
+			addedIndex, err := addIndex(sessionState, conv, newIdx)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			rule.Data = addedIndex
+		} else if rule.Type == constants.EditColumnMaxLength {
+			d, err := json.Marshal(rule.Data)
+			if err != nil {
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+				return
+			}
+			var colMaxLength types.ColMaxLength
+			err = json.Unmarshal(d, &colMaxLength)
+			if err != nil {
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+				return
+			}
+			setSpColMaxLength(conv, colMaxLength, rule.AssociatedObjects)
+		} else if rule.Type == constants.AddShardIdPrimaryKey {
+			d, err := json.Marshal(rule.Data)
+			if err != nil {
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+				return
+			}
+			var shardIdPrimaryKey types.ShardIdPrimaryKey
+			err = json.Unmarshal(d, &shardIdPrimaryKey)
+			if err != nil {
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+				return
+			}
+			tableName := checkInterleaving(conv)
+			if tableName != "" {
+				http.Error(w, fmt.Sprintf("Rule cannot be added because some tables, eg: %v are interleaved. Please remove interleaving and try again.", tableName), http.StatusBadRequest)
+				return
+			}
+			setShardIdColumnAsPrimaryKey(sessionState, conv, shardIdPrimaryKey.AddedAtTheStart)
+			addShardIdColumnToForeignKeys(conv, shardIdPrimaryKey.AddedAtTheStart)
+		} else {
+			http.Error(w, "Invalid rule type", http.StatusInternalServerError)
 			return
 		}
-		setShardIdColumnAsPrimaryKey(shardIdPrimaryKey.AddedAtTheStart)
-		addShardIdColumnToForeignKeys(shardIdPrimaryKey.AddedAtTheStart)
-	} else {
-		http.Error(w, "Invalid rule type", http.StatusInternalServerError)
-		return
-	}
 
-	ruleId := internal.GenerateRuleId()
-	rule.Id = ruleId
+		ruleId := internal.GenerateRuleId()
+		rule.Id = ruleId
 
-	sessionState.Conv.Rules = append(sessionState.Conv.Rules, rule)
-	session.UpdateSessionFile()
-	convm := session.ConvWithMetadata{
-		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+		conv.Rules = append(conv.Rules, rule)
+		session.UpdateSessionFile(sessionState)
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 func DropRule(w http.ResponseWriter, r *http.Request) {
@@ -125,139 +125,136 @@ func DropRule(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprint("Rule id is empty"), http.StatusBadRequest)
 		return
 	}
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	conv := sessionState.Conv
-	var rule internal.Rule
-	position := -1
+	sessionState := session.RequestSessionState(r)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		var rule internal.Rule
+		position := -1
 
-	for i, r := range conv.Rules {
-		if r.Id == ruleId {
-			rule = r
-			position = i
-			break
+		for i, r := range conv.Rules {
+			if r.Id == ruleId {
+				rule = r
+				position = i
+				break
+			}
+		}
+		if position == -1 {
+			http.Error(w, fmt.Sprint("Rule to be deleted not found"), http.StatusBadRequest)
+			return
 		}
-	}
-	if position == -1 {
-		http.Error(w, fmt.Sprint("Rule to be deleted not found"), http.StatusBadRequest)
-		return
-	}
 
-	if rule.Type == constants.AddIndex {
-		if rule.Enabled {
+		if rule.Type == constants.AddIndex {
+			if rule.Enabled {
+				d, err := json.Marshal(rule.Data)
+				if err != nil {
+					http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+					return
+				}
+				var index ddl.CreateIndex
+				err = json.Unmarshal(d, &index)
+				if err != nil {
+					http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+					return
+				}
+				tableId := index.TableId
+				indexId := index.Id
+				err = dropSecondaryIndexHelper(sessionState, conv, tableId, indexId)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("%v", err), http.StatusBadRequest)
+					return
+				}
+			}
+		} else if rule.Type == constants.GlobalDataTypeChange {
 			d, err := json.Marshal(rule.Data)
 			if err != nil {
 				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
 				return
 			}
-			var index ddl.CreateIndex
-			err = json.Unmarshal(d, &index)
+			typeMap := map[string]string{}
+			err = json.Unmarshal(d, &typeMap)
 			if err != nil {
 				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
 				return
 			}
-			tableId := index.TableId
-			indexId := index.Id
-			err = dropSecondaryIndexHelper(tableId, indexId)
+			revertGlobalDataType(sessionState, conv, typeMap)
+		} else if rule.Type == constants.EditColumnMaxLength {
+			d, err := json.Marshal(rule.Data)
 			if err != nil {
-				http.Error(w, fmt.Sprintf("%v", err), http.StatusBadRequest)
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
 				return
 			}
-		}
-	} else if rule.Type == constants.GlobalDataTypeChange {
-		d, err := json.Marshal(rule.Data)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-			return
-		}
-		typeMap := map[string]string{}
-		err = json.Unmarshal(d, &typeMap)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-			return
-		}
-		revertGlobalDataType(typeMap)
-	} else if rule.Type == constants.EditColumnMaxLength {
-		d, err := json.Marshal(rule.Data)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-			return
-		}
-		var colMaxLength types.ColMaxLength
-		err = json.Unmarshal(d, &colMaxLength)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-			return
-		}
-		revertSpColMaxLength(colMaxLength, rule.AssociatedObjects)
-	} else if rule.Type == constants.AddShardIdPrimaryKey {
-		d, err := json.Marshal(rule.Data)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+			var colMaxLength types.ColMaxLength
+			err = json.Unmarshal(d, &colMaxLength)
+			if err != nil {
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+				return
+			}
+			revertSpColMaxLength(sessionState, conv, colMaxLength, rule.AssociatedObjects)
+		} else if rule.Type == constants.AddShardIdPrimaryKey {
+			d, err := json.Marshal(rule.Data)
+			if err != nil {
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+				return
+			}
+			var shardIdPrimaryKey types.ShardIdPrimaryKey
+			err = json.Unmarshal(d, &shardIdPrimaryKey)
+			if err != nil {
+				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+				return
+			}
+			tableName := checkInterleaving(conv)
+			if tableName != "" {
+				http.Error(w, fmt.Sprintf("Rule cannot be deleted because some tables, eg: %v are interleaved. Please remove interleaving and try again.", tableName), http.StatusBadRequest)
+				return
+			}
+			revertShardIdColumnAsPrimaryKey(sessionState, conv, shardIdPrimaryKey.AddedAtTheStart)
+			removeShardIdColumnFromForeignKeys(conv, shardIdPrimaryKey.AddedAtTheStart)
+		} else {
+			http.Error(w, "Invalid rule type", http.StatusInternalServerError)
 			return
 		}
-		var shardIdPrimaryKey types.ShardIdPrimaryKey
-		err = json.Unmarshal(d, &shardIdPrimaryKey)
-		if err != nil {
-			http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-			return
+
+		conv.Rules = append(conv.Rules[:position], conv.Rules[position+1:]...)
+		if len(conv.Rules) == 0 {
+			conv.Rules = nil
 		}
-		tableName := checkInterleaving()
-		if tableName != "" {
-			http.Error(w, fmt.Sprintf("Rule cannot be deleted because some tables, eg: %v are interleaved. Please remove interleaving and try again.", tableName), http.StatusBadRequest)
-			return
+		session.UpdateSessionFile(sessionState)
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
 		}
-		revertShardIdColumnAsPrimaryKey(shardIdPrimaryKey.AddedAtTheStart)
-		removeShardIdColumnFromForeignKeys(shardIdPrimaryKey.AddedAtTheStart)
-	} else {
-		http.Error(w, "Invalid rule type", http.StatusInternalServerError)
-		return
-	}
-
-	sessionState.Conv.Rules = append(conv.Rules[:position], conv.Rules[position+1:]...)
-	if len(sessionState.Conv.Rules) == 0 {
-		sessionState.Conv.Rules = nil
-	}
-	session.UpdateSessionFile()
-	convm := session.ConvWithMetadata{
-		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 // setGlobalDataType allows to change Spanner type globally.
 // It takes a map from source type to Spanner type and updates
 // the Spanner schema accordingly.
-func setGlobalDataType(typeMap map[string]string) {
-	sessionState := session.GetSessionState()
-
+func setGlobalDataType(sessionState *session.SessionState, conv *internal.Conv, typeMap map[string]string) {
 	// Redo source-to-Spanner typeMap using t (the mapping specified in the http request).
 	// We drive this process by iterating over the Spanner schema because we want to preserve all
 	// other customizations that have been performed via the UI (dropping columns, renaming columns
 	// etc). In particular, note that we can't just blindly redo schema conversion (using an appropriate
 	// version of 'toDDL' with the new typeMap).
-	for tableId, spSchema := range sessionState.Conv.SpSchema {
+	for tableId, spSchema := range conv.SpSchema {
 		for colId := range spSchema.ColDefs {
-			srcColDef := sessionState.Conv.SrcSchema[tableId].ColDefs[colId]
+			srcColDef := conv.SrcSchema[tableId].ColDefs[colId]
 			// If the srcCol's type is in the map, then recalculate the Spanner type
 			// for this column using the map. Otherwise, leave the ColDef for this
 			// column as is. Note that per-column type overrides could be lost in
 			// this process -- the mapping in typeMap always takes precendence.
 			if _, found := typeMap[srcColDef.Type.Name]; found {
-				utilities.UpdateDataType(sessionState.Conv, typeMap[srcColDef.Type.Name], tableId, colId)
+				utilities.UpdateDataType(sessionState, conv, typeMap[srcColDef.Type.Name], tableId, colId)
 			}
 		}
-		common.ComputeNonKeyColumnSize(sessionState.Conv, tableId)
+		common.ComputeNonKeyColumnSize(conv, tableId)
 	}
 }
 
 // addIndex checks the new name for spanner name validity, ensures the new name is already not used by existing tables
 // secondary indexes or foreign key constraints. If above checks passed then new indexes are added to the schema else appropriate
 // error thrown.
-func addIndex(newIndex ddl.CreateIndex) (ddl.CreateIndex, error) {
+func addIndex(sessionState *session.SessionState, conv *internal.Conv, newIndex ddl.CreateIndex) (ddl.CreateIndex, error) {
 	// Check new name for spanner name validity.
 	newNames := []string{}
 	newNames = append(newNames, newIndex.Name)
@@ -266,72 +263,69 @@ func addIndex(newIndex ddl.CreateIndex) (ddl.CreateIndex, error) {
 		return ddl.CreateIndex{}, fmt.Errorf("following names are not valid Spanner identifiers: %s", strings.Join(invalidNames, ","))
 	}
 	// Check that the new names are not already used by existing tables, secondary indexes or foreign key constraints.
-	if ok, err := utilities.CanRename(newNames, newIndex.TableId); !ok {
+	if ok, err := utilities.CanRename(sessionState, newNames, newIndex.TableId); !ok {
 		return ddl.CreateIndex{}, err
 	}
 
-	sessionState := session.GetSessionState()
-	sp := sessionState.Conv.SpSchema[newIndex.TableId]
+	sp := conv.SpSchema[newIndex.TableId]
 
 	newIndexes := []ddl.CreateIndex{newIndex}
-	index.CheckIndexSuggestion(newIndexes, sp)
+	index.CheckIndexSuggestion(conv, newIndexes, sp)
 	for i := 0; i < len(newIndexes); i++ {
 		newIndexes[i].Id = internal.GenerateIndexesId()
 	}
 
-	sessionState.Conv.UsedNames[strings.ToLower(newIndex.Name)] = true
+	conv.UsedNames[strings.ToLower(newIndex.Name)] = true
 	sp.Indexes = append(sp.Indexes, newIndexes...)
-	sessionState.Conv.SpSchema[newIndex.TableId] = sp
+	conv.SpSchema[newIndex.TableId] = sp
 	return newIndexes[0], nil
 }
 
-func setSpColMaxLength(spColMaxLength types.ColMaxLength, associatedObjects string) {
-	sessionState := session.GetSessionState()
+func setSpColMaxLength(conv *internal.Conv, spColMaxLength types.ColMaxLength, associatedObjects string) {
 	if associatedObjects == "All table" {
-		for tId := range sessionState.Conv.SpSchema {
-			for _, colDef := range sessionState.Conv.SpSchema[tId].ColDefs {
+		for tId := range conv.SpSchema {
+			for _, colDef := range conv.SpSchema[tId].ColDefs {
 				if colDef.T.Name == spColMaxLength.SpDataType {
 					spColDef := colDef
 					if spColDef.T.Len == ddl.MaxLength {
 						spColDef.T.Len, _ = strconv.ParseInt(spColMaxLength.SpColMaxLength, 10, 64)
 					}
-					sessionState.Conv.SpSchema[tId].ColDefs[colDef.Id] = spColDef
+					conv.SpSchema[tId].ColDefs[colDef.Id] = spColDef
 				}
 			}
-			common.ComputeNonKeyColumnSize(sessionState.Conv, tId)
+			common.ComputeNonKeyColumnSize(conv, tId)
 		}
 	} else {
-		for _, colDef := range sessionState.Conv.SpSchema[associatedObjects].ColDefs {
+		for _, colDef := range conv.SpSchema[associatedObjects].ColDefs {
 			if colDef.T.Name == spColMaxLength.SpDataType {
 				spColDef := colDef
 				if spColDef.T.Len == ddl.MaxLength {
-					table.UpdateColumnSize(spColMaxLength.SpColMaxLength, associatedObjects, colDef.Id, sessionState.Conv)
+					table.UpdateColumnSize(spColMaxLength.SpColMaxLength, associatedObjects, colDef.Id, conv)
 				}
 			}
 		}
-		common.ComputeNonKeyColumnSize(sessionState.Conv, associatedObjects)
+		common.ComputeNonKeyColumnSize(conv, associatedObjects)
 	}
 }
 
-func revertSpColMaxLength(spColMaxLength types.ColMaxLength, associatedObjects string) {
-	sessionState := session.GetSessionState()
+func revertSpColMaxLength(sessionState *session.SessionState, conv *internal.Conv, spColMaxLength types.ColMaxLength, associatedObjects string) {
 	spColLen, _ := strconv.ParseInt(spColMaxLength.SpColMaxLength, 10, 64)
 	if associatedObjects == "All tables" {
-		for tId := range sessionState.Conv.SpSchema {
-			for colId, colDef := range sessionState.Conv.SpSchema[tId].ColDefs {
+		for tId := range conv.SpSchema {
+			for colId, colDef := range conv.SpSchema[tId].ColDefs {
 				if colDef.T.Name == spColMaxLength.SpDataType {
-					utilities.UpdateMaxColumnLen(sessionState.Conv, spColMaxLength.SpDataType, tId, colId, spColLen)
+					utilities.UpdateMaxColumnLen(sessionState, conv, spColMaxLength.SpDataType, tId, colId, spColLen)
 				}
 			}
-			common.ComputeNonKeyColumnSize(sessionState.Conv, tId)
+			common.ComputeNonKeyColumnSize(conv, tId)
 		}
 	} else {
-		for colId, colDef := range sessionState.Conv.SpSchema[associatedObjects].ColDefs {
+		for colId, colDef := range conv.SpSchema[associatedObjects].ColDefs {
 			if colDef.T.Name == spColMaxLength.SpDataType {
-				utilities.UpdateMaxColumnLen(sessionState.Conv, spColMaxLength.SpDataType, associatedObjects, colId, spColLen)
+				utilities.UpdateMaxColumnLen(sessionState, conv, spColMaxLength.SpDataType, associatedObjects, colId, spColLen)
 			}
 		}
-		common.ComputeNonKeyColumnSize(sessionState.Conv, associatedObjects)
+		common.ComputeNonKeyColumnSize(conv, associatedObjects)
 	}
 }
 
@@ -339,12 +333,10 @@ func revertSpColMaxLength(spColMaxLength types.ColMaxLength, associatedObjects s
 // when the rule that is used to apply the data-type change is deleted.
 // It takes a map from source type to Spanner type and updates
 // the Spanner schema accordingly.
-func revertGlobalDataType(typeMap map[string]string) {
-	sessionState := session.GetSessionState()
-
-	for tableId, spSchema := range sessionState.Conv.SpSchema {
+func revertGlobalDataType(sessionState *session.SessionState, conv *internal.Conv, typeMap map[string]string) {
+	for tableId, spSchema := range conv.SpSchema {
 		for colId, colDef := range spSchema.ColDefs {
-			srcColDef, found := sessionState.Conv.SrcSchema[tableId].ColDefs[colId]
+			srcColDef, found := conv.SrcSchema[tableId].ColDefs[colId]
 			if !found {
 				continue
 			}
@@ -355,16 +347,15 @@ func revertGlobalDataType(typeMap map[string]string) {
 			}
 
 			if colDef.T.Name == spType {
-				utilities.UpdateDataType(sessionState.Conv, "", tableId, colId)
+				utilities.UpdateDataType(sessionState, conv, "", tableId, colId)
 			}
 		}
-		common.ComputeNonKeyColumnSize(sessionState.Conv, tableId)
+		common.ComputeNonKeyColumnSize(conv, tableId)
 	}
 }
 
-func removeShardIdColumnFromForeignKeys(isAddedAtFirst bool) {
-	sessionState := session.GetSessionState()
-	for tableId, table := range sessionState.Conv.SpSchema {
+func removeShardIdColumnFromForeignKeys(conv *internal.Conv, isAddedAtFirst bool) {
+	for tableId, table := range conv.SpSchema {
 		for i, fk := range table.ForeignKeys {
 
 			if isAddedAtFirst {
@@ -374,14 +365,13 @@ func removeShardIdColumnFromForeignKeys(isAddedAtFirst bool) {
 				fk.ColIds = fk.ColIds[:len(fk.ColIds)-1]
 				fk.ReferColumnIds = fk.ReferColumnIds[:len(fk.ReferColumnIds)-1]
 			}
-			sessionState.Conv.SpSchema[tableId].ForeignKeys[i] = fk
+			conv.SpSchema[tableId].ForeignKeys[i] = fk
 		}
 	}
 }
 
-func revertShardIdColumnAsPrimaryKey(isAddedAtFirst bool) {
-	sessionState := session.GetSessionState()
-	for _, table := range sessionState.Conv.SpSchema {
+func revertShardIdColumnAsPrimaryKey(sessionState *session.SessionState, conv *internal.Conv, isAddedAtFirst bool) {
+	for _, table := range conv.SpSchema {
 		pkRequest := primarykey.PrimaryKeyRequest{
 			TableId: table.Id,
 			Columns: []ddl.IndexKey{},
@@ -396,13 +386,12 @@ func revertShardIdColumnAsPrimaryKey(isAddedAtFirst bool) {
 				pkRequest.Columns = append(pkRequest.Columns, ddl.IndexKey{ColId: pk.ColId, Order: pk.Order - decrement, Desc: pk.Desc})
 			}
 		}
-		primarykey.UpdatePrimaryKey(pkRequest)
+		primarykey.UpdatePrimaryKey(sessionState, pkRequest)
 	}
 }
 
-func checkInterleaving() string {
-	sessionState := session.GetSessionState()
-	for _, spSchema := range sessionState.Conv.SpSchema {
+func checkInterleaving(conv *internal.Conv) string {
+	for _, spSchema := range conv.SpSchema {
 		if spSchema.ParentTable.Id != "" {
 			return spSchema.Name
 		}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
@@ -21,6 +22,7 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/cassandra"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mongodb"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mysql"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/oracle"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/postgres"
@@ -39,20 +41,6 @@ type TableAPIHandler struct {
 	DDLVerifier expressions_api.DDLVerifier
 }
 
-var mysqlDefaultTypeMap = make(map[string]ddl.Type)
-var postgresDefaultTypeMap = make(map[string]ddl.Type)
-var sqlserverDefaultTypeMap = make(map[string]ddl.Type)
-var oracleDefaultTypeMap = make(map[string]ddl.Type)
-var cassandraDefaultTypeMap = make(map[string]ddl.Type)
-
-var (
-	mysqlTypeMap     = make(map[string][]types.TypeIssue)
-	postgresTypeMap  = make(map[string][]types.TypeIssue)
-	sqlserverTypeMap = make(map[string][]types.TypeIssue)
-	oracleTypeMap    = make(map[string][]types.TypeIssue)
-	cassandraTypeMap = make(map[string][]types.TypeIssue)
-)
-
 var autoGenMap = make(map[string][]types.AutoGen)
 
 type ExpressionsVerificationHandler struct {
@@ -61,8 +49,8 @@ type ExpressionsVerificationHandler struct {
 
 func init() {
 	sessionState := session.GetSessionState()
-	utilities.InitObjectId()
-	sessionState.Conv = internal.MakeConv()
+	utilities.InitObjectId(sessionState)
+	sessionState.SetConv(internal.MakeConv())
 	config := config.TryInitializeSpannerConfig()
 	session.SetSessionStorageConnectionState(config.GCPProjectID, config.SpannerProjectID, config.SpannerInstanceID)
 }
@@ -70,8 +58,8 @@ func init() {
 // ConvertSchemaSQL converts source database to Spanner when using
 // with postgres and mysql driver.
 func (expressionVerificationHandler *ExpressionsVerificationHandler) ConvertSchemaSQL(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	if (sessionState.SourceDB == nil && sessionState.Driver != constants.CASSANDRA) || sessionState.DbName == "" || sessionState.Driver == "" {
+	sessionState := session.RequestSessionState(r)
+	if (sessionState.SourceDB == nil && sessionState.Driver != constants.CASSANDRA && sessionState.Driver != constants.MONGODB) || sessionState.DbName == "" || sessionState.Driver == "" {
 		http.Error(w, fmt.Sprintf("Database is not configured or Database connection is lost. Please set configuration and connect to database."), http.StatusNotFound)
 		return
 	}
@@ -112,6 +100,12 @@ func (expressionVerificationHandler *ExpressionsVerificationHandler) ConvertSche
 		err = processSchema.ProcessSchema(conv, oracle.InfoSchemaImpl{DbName: strings.ToUpper(sessionState.DbName), Db: sessionState.SourceDB}, common.DefaultWorkers, additionalSchemaAttributes, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
 	case constants.CASSANDRA:
 		err = processSchema.ProcessSchema(conv, cassandra.InfoSchemaImpl{KeyspaceMetadata: sessionState.KeyspaceMetadata}, common.DefaultWorkers, additionalSchemaAttributes, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
+	case constants.MONGODB:
+		// The database name of a MongoDB session is the directory of its mongoexport files.
+		err = processSchema.ProcessSchema(conv, mongodb.NewInfoSchemaImpl(sessionState.DbName, profiles.GetSchemaSampleSize(profiles.SourceProfile{})), common.DefaultWorkers, additionalSchemaAttributes, &schemaToSpanner, &common.UtilsOrderImpl{}, &common.InfoSchemaImpl{})
+		if err == nil {
+			mongodb.InterleaveChildTables(conv)
+		}
 	default:
 		http.Error(w, fmt.Sprintf("Driver : '%s' is not supported", sessionState.Driver), http.StatusBadRequest)
 		return
@@ -121,14 +115,11 @@ func (expressionVerificationHandler *ExpressionsVerificationHandler) ConvertSche
 		return
 	}
 
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-
-	sessionState.Conv = conv
+	defer sessionState.ReplaceConv(conv)()
 
 	if sessionState.IsSharded {
-		setShardIdColumnAsPrimaryKey(true)
-		addShardIdColumnToForeignKeys(true)
+		setShardIdColumnAsPrimaryKey(sessionState, conv, true)
+		addShardIdColumnToForeignKeys(conv, true)
 		ruleId := internal.GenerateRuleId()
 		rule := internal.Rule{
 			Id:                ruleId,
@@ -141,13 +132,12 @@ func (expressionVerificationHandler *ExpressionsVerificationHandler) ConvertSche
 			Enabled: true,
 		}
 
-		sessionState := session.GetSessionState()
-		sessionState.Conv.Rules = append(sessionState.Conv.Rules, rule)
-		session.UpdateSessionFile()
+		conv.Rules = append(conv.Rules, rule)
+		session.UpdateSessionFile(sessionState)
 	}
 
-	primarykey.DetectHotspot()
-	index.IndexSuggestion()
+	primarykey.DetectHotspot(sessionState)
+	index.IndexSuggestion(conv)
 
 	sessionMetadata := session.SessionMetadata{
 		SessionName:  "NewSession",
@@ -158,7 +148,7 @@ func (expressionVerificationHandler *ExpressionsVerificationHandler) ConvertSche
 
 	convm := session.ConvWithMetadata{
 		SessionMetadata: sessionMetadata,
-		Conv:            sessionState.Conv,
+		Conv:            conv,
 	}
 	sessionState.SessionMetadata = sessionMetadata
 	w.WriteHeader(http.StatusOK)
@@ -189,10 +179,10 @@ func (expressionVerificationHandler *ExpressionsVerificationHandler) ConvertSche
 	sourceProfile, _ := profiles.NewSourceProfile("", dc.Config.Driver, &n)
 	sourceProfile.Driver = dc.Config.Driver
 	schemaFromSource := conversion.SchemaFromSourceImpl{}
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 	SpProjectId := sessionState.SpannerProjectId
 	SpInstanceId := sessionState.SpannerInstanceID
-	conv, err := schemaFromSource.SchemaFromDump(SpProjectId, SpInstanceId, sourceProfile.Driver, dc.SpannerDetails.Dialect, &utils.IOStreams{In: f, Out: os.Stdout}, &conversion.ProcessDumpByDialectImpl{ExpressionVerificationAccessor: expressionVerificationHandler.ExpressionVerificationAccessor}, profiles.DefaultIdentityOptions{})
+	conv, err := schemaFromSource.SchemaFromDump(SpProjectId, SpInstanceId, sourceProfile.Driver, dc.SpannerDetails.Dialect, &utils.IOStreams{In: f, Out: os.Stdout}, &conversion.ProcessDumpByDialectImpl{ExpressionVerificationAccessor: expressionVerificationHandler.ExpressionVerificationAccessor}, profiles.DefaultIdentityOptions{}, "", internal.NameTemplates{}, "", internal.TableFilter{})
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Schema Conversion Error : %v", err), http.StatusNotFound)
 		return
@@ -205,12 +195,10 @@ func (expressionVerificationHandler *ExpressionsVerificationHandler) ConvertSche
 		Dialect:      dc.SpannerDetails.Dialect,
 	}
 
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	sessionState.Conv = conv
+	defer sessionState.ReplaceConv(conv)()
 
-	primarykey.DetectHotspot()
-	index.IndexSuggestion()
+	primarykey.DetectHotspot(sessionState)
+	index.IndexSuggestion(conv)
 
 	sessionState.SessionMetadata = sessionMetadata
 	sessionState.Driver = dc.Config.Driver
@@ -236,37 +224,42 @@ func (expressionVerificationHandler *ExpressionsVerificationHandler) ConvertSche
 // respects the parent/child ordering of interleaved tables.
 // Though foreign keys and secondary indexes are displayed, getDDL cannot be used to
 // build DDL to send to Spanner.
+// The tables can be paginated, filtered and sorted like with ListTables, in
+// which case the number of tables matching the filter is returned in the
+// X-Total-Count header.
 func GetDDL(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.RLock()
-	defer sessionState.Conv.ConvLock.RUnlock()
-	c := ddl.Config{Comments: true, ProtectIds: false, SpDialect: sessionState.Conv.SpDialect, Source: sessionState.Driver}
-	var tables []string
-	for t := range sessionState.Conv.SpSchema {
-		tables = append(tables, t)
+	opts, err := parseTableListOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	sort.Strings(tables)
-	ddl := make(map[string]string)
-	for _, t := range tables {
-		table := sessionState.Conv.SpSchema[t]
-		tableDdl := table.PrintCreateTable(sessionState.Conv.SpSchema, c) + ";"
-		if len(table.Indexes) > 0 {
-			tableDdl = tableDdl + "\n"
-		}
-		for _, index := range table.Indexes {
-			tableDdl = tableDdl + "\n" + index.PrintCreateIndex(table, c) + ";"
-		}
-		if len(table.ForeignKeys) > 0 {
-			tableDdl = tableDdl + "\n"
-		}
-		for _, fk := range table.ForeignKeys {
-			tableDdl = tableDdl + "\n" + fk.PrintForeignKeyAlterTable(sessionState.Conv.SpSchema, c, t) + ";"
-		}
+	sessionState := session.RequestSessionState(r)
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		c := ddl.Config{Comments: true, ProtectIds: false, SpDialect: conv.SpDialect, Source: sessionState.Driver}
+		tables, total := selectTables(conv, opts)
+		w.Header().Set(totalCountHeader, strconv.Itoa(total))
+		ddl := make(map[string]string)
+		for _, t := range tables {
+			table := conv.SpSchema[t]
+			tableDdl := table.PrintCreateTable(conv.SpSchema, c) + ";"
+			if len(table.Indexes) > 0 {
+				tableDdl = tableDdl + "\n"
+			}
+			for _, index := range table.Indexes {
+				tableDdl = tableDdl + "\n" + index.PrintCreateIndex(table, c) + ";"
+			}
+			if len(table.ForeignKeys) > 0 {
+				tableDdl = tableDdl + "\n"
+			}
+			for _, fk := range table.ForeignKeys {
+				tableDdl = tableDdl + "\n" + fk.PrintForeignKeyAlterTable(conv.SpSchema, c, t) + ";"
+			}
 
-		ddl[t] = tableDdl
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(ddl)
+			ddl[t] = tableDdl
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(ddl)
+	})
 }
 
 func GetStandardTypeToPGSQLTypemap(w http.ResponseWriter, r *http.Request) {
@@ -280,117 +273,92 @@ func GetPGSQLToStandardTypeTypemap(w http.ResponseWriter, r *http.Request) {
 }
 
 func SpannerDefaultTypeMap(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 
-	if sessionState.Conv == nil || sessionState.Driver == "" {
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
 		http.Error(w, "Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner.", http.StatusNotFound)
 		return
 	}
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	initializeTypeMap()
-
-	var typeMap map[string]ddl.Type
-	switch sessionState.Driver {
-	case constants.MYSQL, constants.MYSQLDUMP:
-		typeMap = mysqlDefaultTypeMap
-	case constants.POSTGRES, constants.PGDUMP:
-		typeMap = postgresDefaultTypeMap
-	case constants.SQLSERVER:
-		typeMap = sqlserverDefaultTypeMap
-	case constants.ORACLE:
-		typeMap = oracleDefaultTypeMap
-	case constants.CASSANDRA:
-		typeMap = cassandraDefaultTypeMap
-	default:
-		http.Error(w, fmt.Sprintf("Driver : '%s' is not supported", sessionState.Driver), http.StatusBadRequest)
-		return
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(typeMap)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		typeMap, _, ok := initializeTypeMap(conv, sessionState.Driver)
+		if !ok {
+			http.Error(w, fmt.Sprintf("Driver : '%s' is not supported", sessionState.Driver), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(typeMap)
+	})
 }
 
 // GetTypeMap returns the source to Spanner typemap only for the
 // source types used in current conversion.
 func GetTypeMap(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	if sessionState.Conv == nil || sessionState.Driver == "" {
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
 		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
 		return
 	}
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	var typeMap map[string][]types.TypeIssue
-	initializeTypeMap()
-	switch sessionState.Driver {
-	case constants.MYSQL, constants.MYSQLDUMP:
-		typeMap = mysqlTypeMap
-	case constants.POSTGRES, constants.PGDUMP:
-		typeMap = postgresTypeMap
-	case constants.SQLSERVER:
-		typeMap = sqlserverTypeMap
-	case constants.ORACLE:
-		typeMap = oracleTypeMap
-	case constants.CASSANDRA:
-		typeMap = cassandraTypeMap
-	default:
-		http.Error(w, fmt.Sprintf("Driver : '%s' is not supported", sessionState.Driver), http.StatusBadRequest)
-		return
-	}
-	// Filter typeMap so it contains just the types SrcSchema uses.
-	filteredTypeMap := make(map[string][]types.TypeIssue)
-	for _, srcTable := range sessionState.Conv.SrcSchema {
-		for _, colDef := range srcTable.ColDefs {
-			if _, ok := filteredTypeMap[colDef.Type.Name]; ok {
-				continue
-			}
-			// Timestamp and interval types do not have exact key in typemap.
-			// Typemap for  TIMESTAMP(6), TIMESTAMP(6) WITH LOCAL TIMEZONE,TIMESTAMP(6) WITH TIMEZONE is stored into TIMESTAMP key.
-			// Same goes with interval types like INTERVAL YEAR(2) TO MONTH, INTERVAL DAY(2) TO SECOND(6) etc.
-			// If exact key not found then check with regex.
-			if _, ok := typeMap[colDef.Type.Name]; !ok {
-				if oracle.TimestampReg.MatchString(colDef.Type.Name) {
-					filteredTypeMap[colDef.Type.Name] = typeMap["TIMESTAMP"]
-				} else if oracle.IntervalReg.MatchString(colDef.Type.Name) {
-					filteredTypeMap[colDef.Type.Name] = typeMap["INTERVAL"]
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		_, typeMap, ok := initializeTypeMap(conv, sessionState.Driver)
+		if !ok {
+			http.Error(w, fmt.Sprintf("Driver : '%s' is not supported", sessionState.Driver), http.StatusBadRequest)
+			return
+		}
+		// Filter typeMap so it contains just the types SrcSchema uses.
+		filteredTypeMap := make(map[string][]types.TypeIssue)
+		for _, srcTable := range conv.SrcSchema {
+			for _, colDef := range srcTable.ColDefs {
+				if _, ok := filteredTypeMap[colDef.Type.Name]; ok {
+					continue
 				}
-				continue
+				// Timestamp and interval types do not have exact key in typemap.
+				// Typemap for  TIMESTAMP(6), TIMESTAMP(6) WITH LOCAL TIMEZONE,TIMESTAMP(6) WITH TIMEZONE is stored into TIMESTAMP key.
+				// Same goes with interval types like INTERVAL YEAR(2) TO MONTH, INTERVAL DAY(2) TO SECOND(6) etc.
+				// If exact key not found then check with regex.
+				if _, ok := typeMap[colDef.Type.Name]; !ok {
+					if oracle.TimestampReg.MatchString(colDef.Type.Name) {
+						filteredTypeMap[colDef.Type.Name] = typeMap["TIMESTAMP"]
+					} else if oracle.IntervalReg.MatchString(colDef.Type.Name) {
+						filteredTypeMap[colDef.Type.Name] = typeMap["INTERVAL"]
+					}
+					continue
+				}
+				filteredTypeMap[colDef.Type.Name] = typeMap[colDef.Type.Name]
 			}
-			filteredTypeMap[colDef.Type.Name] = typeMap[colDef.Type.Name]
 		}
-	}
-	for key, values := range filteredTypeMap {
-		for i := range values {
-			if sessionState.Dialect == constants.DIALECT_POSTGRESQL {
-				spType := ddl.Type{
-					Name: filteredTypeMap[key][i].T,
+		for key, values := range filteredTypeMap {
+			for i := range values {
+				if sessionState.Dialect == constants.DIALECT_POSTGRESQL {
+					spType := ddl.Type{
+						Name: filteredTypeMap[key][i].T,
+					}
+					filteredTypeMap[key][i].DisplayT = ddl.GetPGType(spType)
+				} else {
+					filteredTypeMap[key][i].DisplayT = filteredTypeMap[key][i].T
 				}
-				filteredTypeMap[key][i].DisplayT = ddl.GetPGType(spType)
-			} else {
-				filteredTypeMap[key][i].DisplayT = filteredTypeMap[key][i].T
 			}
 		}
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(filteredTypeMap)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(filteredTypeMap)
+	})
 }
 
 func GetAutoGenMap(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	if sessionState.Conv == nil || sessionState.Driver == "" {
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
 		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
 		return
 	}
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	switch sessionState.Driver {
-	case constants.MYSQL, constants.MYSQLDUMP:
-		initializeAutoGenMap(true)
-	case constants.POSTGRES, constants.PGDUMP:
-		initializeAutoGenMap(false)
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(autoGenMap)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		switch sessionState.Driver {
+		case constants.MYSQL, constants.MYSQLDUMP:
+			initializeAutoGenMap(conv, true)
+		case constants.POSTGRES, constants.PGDUMP:
+			initializeAutoGenMap(conv, false)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(autoGenMap)
+	})
 }
 
 func (tableHandler *TableAPIHandler) handleExpressionColError(
@@ -410,79 +378,79 @@ func (tableHandler *TableAPIHandler) handleExpressionColError(
 // GetTableWithErrors checks the errors in the spanner schema
 // and returns a list of tables with errors
 func (tableHandler *TableAPIHandler) GetTableWithErrors(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.RLock()
-
-	tableIds := common.GetSortedTableIdsBySpName(sessionState.Conv.SpSchema)
-	tableHandler.DDLVerifier.RefreshSpannerClient(context.Background(), sessionState.Conv.SpProjectId, sessionState.Conv.SpInstanceId)
-
-	expressionDetails := tableHandler.DDLVerifier.GetSpannerExpressionDetails(sessionState.Conv, tableIds)
-	expressions, err := tableHandler.DDLVerifier.VerifySpannerDDL(sessionState.Conv, expressionDetails)
-	generatedExpressionErrorTables := []string{}
-	if err != nil && strings.Contains(err.Error(), "expressions either failed verification") {
-		for _, exp := range expressions.ExpressionVerificationOutputList {
-			switch exp.ExpressionDetail.Type {
-			case "DEFAULT":
-				tableHandler.handleExpressionColError(&exp, sessionState.Conv, internal.DefaultValueError)
-			case constants.VIRTUAL_GENERATED, constants.STORED_GENERATED:
-				tableId := tableHandler.handleExpressionColError(&exp, sessionState.Conv, internal.GeneratedColumnValueError)
-				if len(tableId) != 0 {
-					generatedExpressionErrorTables = append(generatedExpressionErrorTables, tableId)
-				}
+	sessionState := session.RequestSessionState(r)
+	readConv(w, sessionState, func(conv *internal.Conv) {
+
+		tableIds := common.GetSortedTableIdsBySpName(conv.SpSchema)
+		tableHandler.DDLVerifier.RefreshSpannerClient(context.Background(), conv.SpProjectId, conv.SpInstanceId)
+
+		expressionDetails := tableHandler.DDLVerifier.GetSpannerExpressionDetails(conv, tableIds)
+		expressions, err := tableHandler.DDLVerifier.VerifySpannerDDL(conv, expressionDetails)
+		generatedExpressionErrorTables := []string{}
+		if err != nil && strings.Contains(err.Error(), "expressions either failed verification") {
+			for _, exp := range expressions.ExpressionVerificationOutputList {
+				switch exp.ExpressionDetail.Type {
+				case "DEFAULT":
+					tableHandler.handleExpressionColError(&exp, conv, internal.DefaultValueError)
+				case constants.VIRTUAL_GENERATED, constants.STORED_GENERATED:
+					tableId := tableHandler.handleExpressionColError(&exp, conv, internal.GeneratedColumnValueError)
+					if len(tableId) != 0 {
+						generatedExpressionErrorTables = append(generatedExpressionErrorTables, tableId)
+					}
 
+				}
 			}
-		}
-	} else if err != nil {
-		for _, tableId := range tableIds {
-			srcTable := sessionState.Conv.SrcSchema[tableId]
-			for _, srcColId := range srcTable.ColIds {
-				srcCol := srcTable.ColDefs[srcColId]
-				if srcCol.DefaultValue.IsPresent || srcCol.GeneratedColumn.IsPresent {
-					issues := sessionState.Conv.SchemaIssues[tableId]
-					sessionState.Conv.SchemaIssues[tableId] = issues
+		} else if err != nil {
+			for _, tableId := range tableIds {
+				srcTable := conv.SrcSchema[tableId]
+				for _, srcColId := range srcTable.ColIds {
+					srcCol := srcTable.ColDefs[srcColId]
+					if srcCol.DefaultValue.IsPresent || srcCol.GeneratedColumn.IsPresent {
+						issues := conv.SchemaIssues[tableId]
+						conv.SchemaIssues[tableId] = issues
+					}
 				}
 			}
 		}
-	}
 
-	if sessionState.Conv.SpProjectId != "" {
-		session.UpdateSessionFile()
-	}
-	defer sessionState.Conv.ConvLock.RUnlock()
-	sessionState.Conv.SchemaIssues = common.RemoveError(sessionState.Conv.SchemaIssues)
-	var tableIdName []types.TableIdAndName
-	for id, issues := range sessionState.Conv.SchemaIssues {
-		for _, issue := range issues.TableLevelIssues {
-			if reports.IssueDB[issue].Severity == reports.Errors {
-				t := types.TableIdAndName{
-					Id:   id,
-					Name: sessionState.Conv.SpSchema[id].Name,
-				}
-				tableIdName = append(tableIdName, t)
-			}
+		if conv.SpProjectId != "" {
+			session.UpdateSessionFile(sessionState)
 		}
-		for _, columnIssues := range issues.ColumnLevelIssues {
-			for _, issue := range columnIssues {
+		conv.SchemaIssues = common.RemoveError(conv.SchemaIssues)
+		var tableIdName []types.TableIdAndName
+		for id, issues := range conv.SchemaIssues {
+			for _, issue := range issues.TableLevelIssues {
 				if reports.IssueDB[issue].Severity == reports.Errors {
 					t := types.TableIdAndName{
 						Id:   id,
-						Name: sessionState.Conv.SpSchema[id].Name,
+						Name: conv.SpSchema[id].Name,
 					}
 					tableIdName = append(tableIdName, t)
 				}
 			}
+			for _, columnIssues := range issues.ColumnLevelIssues {
+				for _, issue := range columnIssues {
+					if reports.IssueDB[issue].Severity == reports.Errors {
+						t := types.TableIdAndName{
+							Id:   id,
+							Name: conv.SpSchema[id].Name,
+						}
+						tableIdName = append(tableIdName, t)
+					}
+				}
+			}
 		}
-	}
-	for _, tableId := range generatedExpressionErrorTables {
-		t := types.TableIdAndName{
-			Id:   tableId,
-			Name: sessionState.Conv.SpSchema[tableId].Name,
+		for _, tableId := range generatedExpressionErrorTables {
+			t := types.TableIdAndName{
+				Id:   tableId,
+				Name: conv.SpSchema[tableId].Name,
+			}
+			tableIdName = append(tableIdName, t)
 		}
-		tableIdName = append(tableIdName, t)
-	}
-	tableIdName = uniqueAndSortTableIdName(tableIdName)
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(tableIdName)
+		tableIdName = uniqueAndSortTableIdName(tableIdName)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(tableIdName)
+	})
 }
 
 func (tableHandler *TableAPIHandler) RestoreTables(w http.ResponseWriter, r *http.Request) {
@@ -497,22 +465,29 @@ func (tableHandler *TableAPIHandler) RestoreTables(w http.ResponseWriter, r *htt
 		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
 		return
 	}
-	var convm session.ConvWithMetadata
-	for _, tableId := range tables.TableList {
-		convm = tableHandler.restoreTableHelper(w, tableId)
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+	sessionState := session.RequestSessionState(r)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		var convm session.ConvWithMetadata
+		for _, tableId := range tables.TableList {
+			convm = tableHandler.restoreTableHelper(sessionState, conv, w, tableId)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 func (tableHandler *TableAPIHandler) RestoreTable(w http.ResponseWriter, r *http.Request) {
 	tableId := r.FormValue("table")
-	convm := tableHandler.restoreTableHelper(w, tableId)
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+	sessionState := session.RequestSessionState(r)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		convm := tableHandler.restoreTableHelper(sessionState, conv, w, tableId)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 func DropTables(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
@@ -524,26 +499,98 @@ func DropTables(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
 		return
 	}
-	var convm session.ConvWithMetadata
-	for _, tableId := range tables.TableList {
-		convm = dropTableHelper(w, tableId)
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		var convm session.ConvWithMetadata
+		for _, tableId := range tables.TableList {
+			convm = dropTableHelper(sessionState, conv, w, tableId)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 func DropTable(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
 	tableId := r.FormValue("table")
-	convm := dropTableHelper(w, tableId)
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		convm := dropTableHelper(sessionState, conv, w, tableId)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
+}
+
+// bulkDropRequest is the request body of BulkDrop.
+type bulkDropRequest struct {
+	ForeignKeys      bool `json:"ForeignKeys"`
+	SecondaryIndexes bool `json:"SecondaryIndexes"`
+}
+
+// BulkDrop drops all foreign keys and/or all secondary indexes of the
+// session schema. The dropped objects are listed in the conversion report
+// with the statements recreating them.
+func BulkDrop(w http.ResponseWriter, r *http.Request) {
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
+		return
+	}
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
+	req := bulkDropRequest{}
+	if err = json.Unmarshal(reqBody, &req); err != nil {
+		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
+		return
+	}
+
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		if req.ForeignKeys {
+			conv.DropAllForeignKeys()
+		}
+		if req.SecondaryIndexes {
+			conv.DropAllSecondaryIndexes()
+		}
+		session.UpdateSessionFile(sessionState)
+
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
+}
+
+// AddJSONPathIndexes adds a generated column and an index for each JSON path
+// suggested by the assessment of the application queries, i.e. the paths of
+// JSON columns which the queries filter or order rows by.
+func AddJSONPathIndexes(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
+
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		conv.AddJSONPathIndexes()
+		session.UpdateSessionFile(sessionState)
+
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 func RestoreSecondaryIndex(w http.ResponseWriter, r *http.Request) {
 	tableId := r.FormValue("tableId")
 	indexId := r.FormValue("indexId")
-	sessionState := session.GetSessionState()
-	if sessionState.Conv == nil || sessionState.Driver == "" {
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
 		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
 		return
 	}
@@ -556,79 +603,386 @@ func RestoreSecondaryIndex(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	var srcIndex schema.Index
-	srcIndexFound := false
-	for _, index := range sessionState.Conv.SrcSchema[tableId].Indexes {
-		if index.Id == indexId {
-			srcIndex = index
-			srcIndexFound = true
-			break
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		var srcIndex schema.Index
+		srcIndexFound := false
+		for _, index := range conv.SrcSchema[tableId].Indexes {
+			if index.Id == indexId {
+				srcIndex = index
+				srcIndexFound = true
+				break
+			}
 		}
+		if !srcIndexFound {
+			http.Error(w, fmt.Sprintf("Source index not found"), http.StatusBadRequest)
+			return
+		}
+
+		spIndex := common.CvtIndexHelper(conv, tableId, srcIndex, conv.SpSchema[tableId].ColIds, conv.SpSchema[tableId].ColDefs)
+		spIndexes := conv.SpSchema[tableId].Indexes
+		spIndexes = append(spIndexes, spIndex)
+		spTable := conv.SpSchema[tableId]
+		spTable.Indexes = spIndexes
+		conv.SpSchema[tableId] = spTable
+
+		index.AssignInitialOrders(conv)
+		index.IndexSuggestion(conv)
+
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
+}
+
+// UpdateCheckConstraint processes the request to update spanner table check constraints, ensuring session and schema validity, and responds with the updated conversion metadata.
+func UpdateCheckConstraint(w http.ResponseWriter, r *http.Request) {
+	tableId := r.FormValue("table")
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
 	}
-	if !srcIndexFound {
-		http.Error(w, fmt.Sprintf("Source index not found"), http.StatusBadRequest)
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
 		return
 	}
+	updateConv(w, sessionState, func(conv *internal.Conv) {
 
-	conv := sessionState.Conv
+		newCc := []ddl.CheckConstraint{}
+		if err = json.Unmarshal(reqBody, &newCc); err != nil {
+			http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
+			return
+		}
 
-	spIndex := common.CvtIndexHelper(conv, tableId, srcIndex, conv.SpSchema[tableId].ColIds, conv.SpSchema[tableId].ColDefs)
-	spIndexes := conv.SpSchema[tableId].Indexes
-	spIndexes = append(spIndexes, spIndex)
-	spTable := conv.SpSchema[tableId]
-	spTable.Indexes = spIndexes
-	conv.SpSchema[tableId] = spTable
+		for i := range newCc {
+			newCc[i].Expr = checkAndAddParentheses(newCc[i].Expr)
+		}
 
-	sessionState.Conv = conv
-	index.AssignInitialOrders()
-	index.IndexSuggestion()
+		sp := conv.SpSchema[tableId]
+		sp.CheckConstraints = newCc
+		conv.SpSchema[tableId] = sp
+		session.UpdateSessionFile(sessionState)
 
-	convm := session.ConvWithMetadata{
-		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
+}
+
+// UpdateRowDeletionPolicy sets the row deletion policy (TTL) of the given table.
+// An empty ColId in the request body removes the existing policy.
+func UpdateRowDeletionPolicy(w http.ResponseWriter, r *http.Request) {
+	tableId := r.FormValue("table")
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
+		return
 	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
+	policy := ddl.RowDeletionPolicy{}
+	if err = json.Unmarshal(reqBody, &policy); err != nil {
+		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
+		return
+	}
+
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		if err = conv.SetRowDeletionPolicy(tableId, policy); err != nil {
+			http.Error(w, fmt.Sprintf("Row deletion policy error : %v", err), http.StatusBadRequest)
+			return
+		}
+		session.UpdateSessionFile(sessionState)
+
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
-// UpdateCheckConstraint processes the request to update spanner table check constraints, ensuring session and schema validity, and responds with the updated conversion metadata.
-func UpdateCheckConstraint(w http.ResponseWriter, r *http.Request) {
+// UpdateVectorIndex adds a vector index to the given table, or replaces the
+// vector index of the table with the same Id.
+func UpdateVectorIndex(w http.ResponseWriter, r *http.Request) {
 	tableId := r.FormValue("table")
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
+		return
 	}
-	sessionState := session.GetSessionState()
-	if sessionState.Conv == nil || sessionState.Driver == "" {
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
+	index := ddl.VectorIndex{}
+	if err = json.Unmarshal(reqBody, &index); err != nil {
+		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
+		return
+	}
+
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		if err = conv.SetVectorIndex(tableId, index); err != nil {
+			http.Error(w, fmt.Sprintf("Vector index error : %v", err), http.StatusBadRequest)
+			return
+		}
+		session.UpdateSessionFile(sessionState)
+
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
+}
+
+// DropVectorIndex drops the vector index with the given Id from the given
+// table.
+func DropVectorIndex(w http.ResponseWriter, r *http.Request) {
+	tableId := r.FormValue("table")
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
+		return
+	}
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
 		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
 		return
 	}
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
+	var dropDetail struct{ Id string }
+	if err = json.Unmarshal(reqBody, &dropDetail); err != nil {
+		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
+		return
+	}
+
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		if err = conv.RemoveVectorIndex(tableId, dropDetail.Id); err != nil {
+			http.Error(w, fmt.Sprintf("Vector index error : %v", err), http.StatusBadRequest)
+			return
+		}
+		session.UpdateSessionFile(sessionState)
+
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
+}
+
+// commitTimestampCol is the request body of UpdateCommitTimestamp.
+type commitTimestampCol struct {
+	ColId    string `json:"ColId"`
+	Enabled  bool   `json:"Enabled"`
+	Sentinel string `json:"Sentinel"`
+}
+
+// UpdateCommitTimestamp sets or removes allow_commit_timestamp on a TIMESTAMP
+// column of the given table. Source values that are NULL or equal to the
+// optional Sentinel timestamp are written as PENDING_COMMIT_TIMESTAMP().
+func UpdateCommitTimestamp(w http.ResponseWriter, r *http.Request) {
+	tableId := r.FormValue("table")
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
+		return
+	}
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
+	col := commitTimestampCol{}
+	if err = json.Unmarshal(reqBody, &col); err != nil {
+		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
+		return
+	}
+
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		if err = conv.SetCommitTimestamp(tableId, col.ColId, col.Enabled, col.Sentinel); err != nil {
+			http.Error(w, fmt.Sprintf("Commit timestamp error : %v", err), http.StatusBadRequest)
+			return
+		}
+		session.UpdateSessionFile(sessionState)
+
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
+}
+
+// renameTableRequest is the request body of RenameTable.
+type renameTableRequest struct {
+	Name string `json:"Name"`
+}
 
-	newCc := []ddl.CheckConstraint{}
-	if err = json.Unmarshal(reqBody, &newCc); err != nil {
+// RenameTable renames the given Spanner table, and updates the views and
+// name mappings referring to it.
+func RenameTable(w http.ResponseWriter, r *http.Request) {
+	tableId := r.FormValue("table")
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
+		return
+	}
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
+	req := renameTableRequest{}
+	if err = json.Unmarshal(reqBody, &req); err != nil {
 		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
 		return
 	}
 
-	for i := range newCc {
-		newCc[i].Expr = checkAndAddParentheses(newCc[i].Expr)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		if err = conv.RenameTable(tableId, req.Name); err != nil {
+			http.Error(w, fmt.Sprintf("Table rename error : %v", err), http.StatusBadRequest)
+			return
+		}
+		session.UpdateSessionFile(sessionState)
+
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
+}
+
+// GetNameConflicts returns the objects of the session schema with the same
+// name as another table, view, sequence, index or constraint, with the names
+// proposed to resolve the conflicts. The schema can't be migrated until they
+// are resolved.
+func GetNameConflicts(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
 	}
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		conflicts := conv.NameConflicts()
+		if conflicts == nil {
+			conflicts = []internal.NameConflict{}
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(conflicts)
+	})
+}
 
-	sp := sessionState.Conv.SpSchema[tableId]
-	sp.CheckConstraints = newCc
-	sessionState.Conv.SpSchema[tableId] = sp
-	session.UpdateSessionFile()
+// ResolveNameConflicts renames the objects returned by GetNameConflicts to
+// their proposed names.
+func ResolveNameConflicts(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
 
-	convm := session.ConvWithMetadata{
-		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		if _, err := conv.ResolveNameConflicts(); err != nil {
+			http.Error(w, fmt.Sprintf("Name conflict resolution error : %v", err), http.StatusBadRequest)
+			return
+		}
+		session.UpdateSessionFile(sessionState)
+
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
+}
+
+// UpdateColumnTransforms replaces the transforms of the given table, which
+// merge or split source columns into added Spanner columns during data
+// migration.
+func UpdateColumnTransforms(w http.ResponseWriter, r *http.Request) {
+	tableId := r.FormValue("table")
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
+		return
 	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
+	var transforms []internal.ColumnTransform
+	if err = json.Unmarshal(reqBody, &transforms); err != nil {
+		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
+		return
+	}
+
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		if err = conv.SetColumnTransforms(tableId, transforms); err != nil {
+			http.Error(w, fmt.Sprintf("Column transform error : %v", err), http.StatusBadRequest)
+			return
+		}
+		session.UpdateSessionFile(sessionState)
+
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
+}
+
+// UpdateNameTemplates changes the templates used to name the columns, indexes
+// and sequences generated by the tool.
+func UpdateNameTemplates(w http.ResponseWriter, r *http.Request) {
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
+		return
+	}
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
+	nameTemplates := internal.NameTemplates{}
+	if err = json.Unmarshal(reqBody, &nameTemplates); err != nil {
+		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
+		return
+	}
+
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		if err = conv.SetNameTemplates(nameTemplates); err != nil {
+			http.Error(w, fmt.Sprintf("Name templates error : %v", err), http.StatusBadRequest)
+			return
+		}
+		session.UpdateSessionFile(sessionState)
+
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 // checkAndAddParentheses this method will check parentheses  if found it will return same string
@@ -653,74 +1007,85 @@ func checkAndAddParentheses(checkClause string) string {
 // VerifyExpression this function will use expression_api to validate check constraint expressions and add the relevant error
 // to suggestion tab and remove the check constraint which has error
 func (expressionVerificationHandler *ExpressionsVerificationHandler) VerifyCheckConstraintExpression(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	if sessionState.Conv == nil || sessionState.Driver == "" {
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
 		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
 		return
 	}
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-
-	spschema := sessionState.Conv.SpSchema
-	expressionDetailList := common.GenerateExpressionDetailList(spschema)
-	hasErrorOccurred := false
+	// The expressions are verified against a snapshot of the schema, so that
+	// the session can be reviewed while Spanner verifies them.
+	version := sessionState.ConvVersion()
+	snapshot, err := sessionState.SnapshotConv()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Can't read the session schema: %v", err), http.StatusInternalServerError)
+		return
+	}
+	expressionDetailList := common.GenerateExpressionDetailList(snapshot.SpSchema)
+	var issueTypes map[string][]internal.InvalidCheckExp
 	if len(expressionDetailList) != 0 {
 
 		ctx := context.Background()
 
 		verifyExpressionsInput := internal.VerifyExpressionsInput{
-			Conv:                 sessionState.Conv,
+			Conv:                 snapshot,
 			Source:               "mysql",
 			ExpressionDetailList: expressionDetailList,
 		}
 
-		sessionState.Conv.SchemaIssues = common.RemoveError(sessionState.Conv.SchemaIssues)
-		expressionVerificationHandler.ExpressionVerificationAccessor.RefreshSpannerClient(ctx, sessionState.Conv.SpProjectId, sessionState.Conv.SpInstanceId)
+		expressionVerificationHandler.ExpressionVerificationAccessor.RefreshSpannerClient(ctx, snapshot.SpProjectId, snapshot.SpInstanceId)
 		result := expressionVerificationHandler.ExpressionVerificationAccessor.VerifyExpressions(ctx, verifyExpressionsInput)
 		if result.ExpressionVerificationOutputList == nil {
 			http.Error(w, fmt.Sprintf("Unhandled error: : %s", result.Err.Error()), http.StatusInternalServerError)
 			return
 		}
+		issueTypes = common.GetErroredIssue(result)
+	}
 
-		issueTypes := common.GetErroredIssue(result)
-		if len(issueTypes) > 0 {
-			hasErrorOccurred = true
+	hasErrorOccurred := len(issueTypes) > 0
+	var convm session.ConvWithMetadata
+	err = sessionState.UpdateConv(func(conv *internal.Conv) error {
+		// The results only apply to the schema they were verified against.
+		if sessionState.ConvVersion() != version {
+			return fmt.Errorf("the schema was modified while the check constraints were verified, please verify them again")
+		}
+		if len(expressionDetailList) != 0 {
+			conv.SchemaIssues = common.RemoveError(conv.SchemaIssues)
 			for tableId, issues := range issueTypes {
 
-				if sessionState.Conv.InvalidCheckExp == nil {
-					sessionState.Conv.InvalidCheckExp = map[string][]internal.InvalidCheckExp{}
-					sessionState.Conv.InvalidCheckExp[tableId] = []internal.InvalidCheckExp{}
+				if conv.InvalidCheckExp == nil {
+					conv.InvalidCheckExp = map[string][]internal.InvalidCheckExp{}
 				}
 
-				sessionState.Conv.InvalidCheckExp[tableId] = []internal.InvalidCheckExp{}
-				invalidCheckExp := sessionState.Conv.InvalidCheckExp[tableId]
-				invalidCheckExp = append(invalidCheckExp, issues...)
-				sessionState.Conv.InvalidCheckExp[tableId] = invalidCheckExp
+				conv.InvalidCheckExp[tableId] = append([]internal.InvalidCheckExp{}, issues...)
 
 				for _, issue := range issues {
-					if _, exists := sessionState.Conv.SchemaIssues[tableId]; !exists {
-						sessionState.Conv.SchemaIssues[tableId] = internal.TableIssues{
+					if _, exists := conv.SchemaIssues[tableId]; !exists {
+						conv.SchemaIssues[tableId] = internal.TableIssues{
 							TableLevelIssues: []internal.SchemaIssue{},
 						}
 					}
 
-					tableIssue := sessionState.Conv.SchemaIssues[tableId]
+					tableIssue := conv.SchemaIssues[tableId]
 
 					if !utilities.IsSchemaIssuePresent(tableIssue.TableLevelIssues, issue.IssueType) {
 						tableIssue.TableLevelIssues = append(tableIssue.TableLevelIssues, issue.IssueType)
 					}
 
-					sessionState.Conv.SchemaIssues[tableId] = tableIssue
+					conv.SchemaIssues[tableId] = tableIssue
 				}
 			}
-		}
-
-		session.UpdateSessionFile()
-	}
 
-	convm := session.ConvWithMetadata{
-		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
+			session.UpdateSessionFile(sessionState)
+		}
+		convm = session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -730,117 +1095,205 @@ func (expressionVerificationHandler *ExpressionsVerificationHandler) VerifyCheck
 	})
 }
 
-// renameForeignKeys checks the new names for spanner name validity, ensures the new names are already not used by existing tables
-// secondary indexes or foreign key constraints. If above checks passed then foreignKey renaming reflected in the schema else appropriate
-// error thrown.
-func UpdateForeignKeys(w http.ResponseWriter, r *http.Request) {
-	tableId := r.FormValue("table")
+// VerifyExpression verifies a single check constraint, default value or
+// generated column expression against the session schema and returns the
+// result immediately, so that the UI can validate edits before saving them.
+// The session is not modified.
+func (expressionVerificationHandler *ExpressionsVerificationHandler) VerifyExpression(w http.ResponseWriter, r *http.Request) {
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
+		return
 	}
-
-	sessionState := session.GetSessionState()
-	if sessionState.Conv == nil || sessionState.Driver == "" {
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
 		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
 		return
 	}
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-
-	newFKs := []ddl.Foreignkey{}
-	if err = json.Unmarshal(reqBody, &newFKs); err != nil {
+	var verifyExpressionRequest types.VerifyExpressionRequest
+	if err = json.Unmarshal(reqBody, &verifyExpressionRequest); err != nil {
 		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
 		return
 	}
 
-	// Check new name for spanner name validity.
-	newNames := []string{}
-	newNamesMap := map[string]bool{}
-	for _, newFk := range newFKs {
-		if len(newFk.Name) == 0 {
-			continue
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		expressionDetail, err := getExpressionDetail(conv, verifyExpressionRequest)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
-		for _, oldFk := range sessionState.Conv.SpSchema[tableId].ForeignKeys {
-			if newFk.Id == oldFk.Id && newFk.Name != oldFk.Name && newFk.Name != "" {
-				newNames = append(newNames, strings.ToLower(newFk.Name))
-			}
+		ctx := context.Background()
+		if err = expressionVerificationHandler.ExpressionVerificationAccessor.RefreshSpannerClient(ctx, conv.SpProjectId, conv.SpInstanceId); err != nil {
+			http.Error(w, fmt.Sprintf("Error while creating the Spanner client : %v", err), http.StatusInternalServerError)
+			return
 		}
-	}
+		result := expressionVerificationHandler.ExpressionVerificationAccessor.VerifyExpressions(ctx, internal.VerifyExpressionsInput{
+			Conv:                 conv,
+			Source:               sessionState.Driver,
+			ExpressionDetailList: []internal.ExpressionDetail{expressionDetail},
+		})
+		if len(result.ExpressionVerificationOutputList) == 0 {
+			http.Error(w, fmt.Sprintf("Unhandled error: : %v", result.Err), http.StatusInternalServerError)
+			return
+		}
+		output := result.ExpressionVerificationOutputList[0]
+		response := types.VerifyExpressionResponse{Valid: output.Result && output.Err == nil}
+		if output.Err != nil {
+			response.Error = output.Err.Error()
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+	})
+}
 
-	for _, newFk := range newFKs {
-		if len(newFk.Name) == 0 {
-			continue
+// getExpressionDetail returns the expression to verify for req.
+func getExpressionDetail(conv *internal.Conv, req types.VerifyExpressionRequest) (internal.ExpressionDetail, error) {
+	table, ok := conv.SpSchema[req.TableId]
+	if !ok {
+		return internal.ExpressionDetail{}, fmt.Errorf("table %s not found", req.TableId)
+	}
+	if strings.TrimSpace(req.Expression) == "" {
+		return internal.ExpressionDetail{}, fmt.Errorf("expression is empty")
+	}
+	expressionDetail := internal.ExpressionDetail{
+		Expression:   req.Expression,
+		Type:         req.Type,
+		ExpressionId: internal.GenerateExpressionId(),
+		SpTableName:  table.Name,
+		Metadata:     map[string]string{"tableId": req.TableId},
+	}
+	switch req.Type {
+	case constants.CHECK_EXPRESSION:
+		expressionDetail.ReferenceElement = internal.ReferenceElement{Name: table.Name}
+	case constants.DEFAULT_EXPRESSION, constants.STORED_GENERATED, constants.VIRTUAL_GENERATED:
+		col, ok := table.ColDefs[req.ColId]
+		if !ok {
+			return internal.ExpressionDetail{}, fmt.Errorf("column %s not found in table %s", req.ColId, table.Name)
 		}
-		if _, ok := newNamesMap[strings.ToLower(newFk.Name)]; ok {
-			http.Error(w, fmt.Sprintf("Found duplicate names in input : %s", strings.ToLower(newFk.Name)), http.StatusBadRequest)
-			return
+		tyName := col.T.Name
+		if conv.SpDialect == constants.DIALECT_POSTGRESQL {
+			tyName = ddl.GetPGType(col.T)
 		}
-		newNamesMap[strings.ToLower(newFk.Name)] = true
+		expressionDetail.ReferenceElement = internal.ReferenceElement{Name: tyName}
+		expressionDetail.Metadata["colId"] = req.ColId
+	default:
+		return internal.ExpressionDetail{}, fmt.Errorf("invalid expression type %s", req.Type)
 	}
+	return expressionDetail, nil
+}
 
-	if ok, invalidNames := utilities.CheckSpannerNamesValidity(newNames); !ok {
-		http.Error(w, fmt.Sprintf("Following names are not valid Spanner identifiers: %s", strings.Join(invalidNames, ",")), http.StatusBadRequest)
-		return
+// renameForeignKeys checks the new names for spanner name validity, ensures the new names are already not used by existing tables
+// secondary indexes or foreign key constraints. If above checks passed then foreignKey renaming reflected in the schema else appropriate
+// error thrown.
+func UpdateForeignKeys(w http.ResponseWriter, r *http.Request) {
+	tableId := r.FormValue("table")
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
 	}
 
-	// Check that the new names are not already used by existing tables, secondary indexes or foreign key constraints.
-	if ok, err := utilities.CanRename(newNames, tableId); !ok {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
 		return
 	}
+	updateConv(w, sessionState, func(conv *internal.Conv) {
 
-	sp := sessionState.Conv.SpSchema[tableId]
-	usedNames := sessionState.Conv.UsedNames
-
-	// Update session with renamed foreignkeys.
-	updatedFKs := []ddl.Foreignkey{}
+		newFKs := []ddl.Foreignkey{}
+		if err = json.Unmarshal(reqBody, &newFKs); err != nil {
+			http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
+			return
+		}
 
-	for _, foreignKey := range sp.ForeignKeys {
-		for i, updatedForeignkey := range newFKs {
-			if foreignKey.Id == updatedForeignkey.Id && len(updatedForeignkey.ColIds) != 0 && updatedForeignkey.ReferTableId != "" {
-				delete(usedNames, strings.ToLower(foreignKey.Name))
-				foreignKey.Name = updatedForeignkey.Name
-				updatedFKs = append(updatedFKs, foreignKey)
+		// Check new name for spanner name validity.
+		newNames := []string{}
+		newNamesMap := map[string]bool{}
+		for _, newFk := range newFKs {
+			if len(newFk.Name) == 0 {
+				continue
 			}
-			if foreignKey.Id == updatedForeignkey.Id && len(updatedForeignkey.ReferColumnIds) == 0 && updatedForeignkey.ReferTableId == "" {
-				dropFkId := updatedForeignkey.Id
-
-				// To remove the interleavable suggestions if they exist on dropping fk
-				colId := sp.ForeignKeys[i].ColIds[0]
-				schemaIssue := []internal.SchemaIssue{}
-				for _, v := range sessionState.Conv.SchemaIssues[tableId].ColumnLevelIssues[colId] {
-					if v != internal.InterleavedAddColumn && v != internal.InterleavedRenameColumn && v != internal.InterleavedNotInOrder && v != internal.InterleavedChangeColumnSize {
-						schemaIssue = append(schemaIssue, v)
-					}
+			for _, oldFk := range conv.SpSchema[tableId].ForeignKeys {
+				if newFk.Id == oldFk.Id && newFk.Name != oldFk.Name && newFk.Name != "" {
+					newNames = append(newNames, strings.ToLower(newFk.Name))
 				}
-				if _, ok := sessionState.Conv.SchemaIssues[tableId]; ok {
-					sessionState.Conv.SchemaIssues[tableId].ColumnLevelIssues[colId] = schemaIssue
+			}
+		}
+
+		for _, newFk := range newFKs {
+			if len(newFk.Name) == 0 {
+				continue
+			}
+			if _, ok := newNamesMap[strings.ToLower(newFk.Name)]; ok {
+				http.Error(w, fmt.Sprintf("Found duplicate names in input : %s", strings.ToLower(newFk.Name)), http.StatusBadRequest)
+				return
+			}
+			newNamesMap[strings.ToLower(newFk.Name)] = true
+		}
+
+		if ok, invalidNames := utilities.CheckSpannerNamesValidity(newNames); !ok {
+			http.Error(w, fmt.Sprintf("Following names are not valid Spanner identifiers: %s", strings.Join(invalidNames, ",")), http.StatusBadRequest)
+			return
+		}
+
+		// Check that the new names are not already used by existing tables, secondary indexes or foreign key constraints.
+		if ok, err := utilities.CanRename(sessionState, newNames, tableId); !ok {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		sp := conv.SpSchema[tableId]
+		usedNames := conv.UsedNames
+
+		// Update session with renamed foreignkeys.
+		updatedFKs := []ddl.Foreignkey{}
+
+		for _, foreignKey := range sp.ForeignKeys {
+			for i, updatedForeignkey := range newFKs {
+				if foreignKey.Id == updatedForeignkey.Id && len(updatedForeignkey.ColIds) != 0 && updatedForeignkey.ReferTableId != "" {
+					delete(usedNames, strings.ToLower(foreignKey.Name))
+					foreignKey.Name = updatedForeignkey.Name
+					updatedFKs = append(updatedFKs, foreignKey)
 				}
-				var err error
-				sp.ForeignKeys, err = utilities.RemoveFk(sp.ForeignKeys, dropFkId, sessionState.Conv.SrcSchema[tableId], tableId)
-				if err != nil {
-					http.Error(w, err.Error(), http.StatusBadRequest)
+				if foreignKey.Id == updatedForeignkey.Id && len(updatedForeignkey.ReferColumnIds) == 0 && updatedForeignkey.ReferTableId == "" {
+					dropFkId := updatedForeignkey.Id
+
+					// To remove the interleavable suggestions if they exist on dropping fk
+					colId := sp.ForeignKeys[i].ColIds[0]
+					schemaIssue := []internal.SchemaIssue{}
+					for _, v := range conv.SchemaIssues[tableId].ColumnLevelIssues[colId] {
+						if v != internal.InterleavedAddColumn && v != internal.InterleavedRenameColumn && v != internal.InterleavedNotInOrder && v != internal.InterleavedChangeColumnSize {
+							schemaIssue = append(schemaIssue, v)
+						}
+					}
+					if _, ok := conv.SchemaIssues[tableId]; ok {
+						conv.SchemaIssues[tableId].ColumnLevelIssues[colId] = schemaIssue
+					}
+					var err error
+					sp.ForeignKeys, err = utilities.RemoveFk(sessionState, sp.ForeignKeys, dropFkId, conv.SrcSchema[tableId], tableId)
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusBadRequest)
+					}
 				}
 			}
 		}
-	}
-	sp.ForeignKeys = updatedFKs
-	sessionState.Conv.SpSchema[tableId] = sp
-	session.UpdateSessionFile()
+		sp.ForeignKeys = updatedFKs
+		conv.SpSchema[tableId] = sp
+		session.UpdateSessionFile(sessionState)
 
-	convm := session.ConvWithMetadata{
-		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 // renameIndexes checks the new names for spanner name validity, ensures the new names are already not used by existing tables
 // secondary indexes or foreign key constraints. If above checks passed then index renaming reflected in the schema else appropriate
 // error thrown.
 func RenameIndexes(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
 	table := r.FormValue("table")
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -870,33 +1323,36 @@ func RenameIndexes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check that the new names are not already used by existing tables, secondary indexes or foreign key constraints.
-	if ok, err := utilities.CanRename(newNames, table); !ok {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-	sessionState := session.GetSessionState()
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		// Check that the new names are not already used by existing tables, secondary indexes or foreign key constraints.
+		if ok, err := utilities.CanRename(sessionState, newNames, table); !ok {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-	sp := sessionState.Conv.SpSchema[table]
+		sp := conv.SpSchema[table]
 
-	// Update session with renamed secondary indexes.
-	newIndexes := []ddl.CreateIndex{}
-	for _, index := range sp.Indexes {
-		if newName, ok := renameMap[index.Id]; ok {
-			index.Name = newName
+		// Update session with renamed secondary indexes.
+		newIndexes := []ddl.CreateIndex{}
+		for _, index := range sp.Indexes {
+			if newName, ok := renameMap[index.Id]; ok {
+				delete(conv.UsedNames, strings.ToLower(index.Name))
+				conv.UsedNames[strings.ToLower(newName)] = true
+				index.Name = newName
+			}
+			newIndexes = append(newIndexes, index)
 		}
-		newIndexes = append(newIndexes, index)
-	}
-	sp.Indexes = newIndexes
+		sp.Indexes = newIndexes
 
-	sessionState.Conv.SpSchema[table] = sp
-	session.UpdateSessionFile()
-	convm := session.ConvWithMetadata{
-		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+		conv.SpSchema[table] = sp
+		session.UpdateSessionFile(sessionState)
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 // setParentTable checks whether specified table can be interleaved, and updates the schema to convert foreign
@@ -908,9 +1364,9 @@ func SetParentTable(w http.ResponseWriter, r *http.Request) {
 	onDelete := r.FormValue("onDelete")
 	update := r.FormValue("update") == "true"
 	interleaveType := r.FormValue("interleaveType")
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 
-	if sessionState.Conv == nil || sessionState.Driver == "" {
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
 		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
 		return
 	}
@@ -935,36 +1391,75 @@ func SetParentTable(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	tableInterleaveStatus := parentTableHelper(tableId, parentTableId, interleaveType, onDelete, update)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		tableInterleaveStatus := parentTableHelper(conv, tableId, parentTableId, interleaveType, onDelete, update)
+
+		index.IndexSuggestion(conv)
+		if tableInterleaveStatus.Possible {
+			session.UpdateSessionFile(sessionState)
+		}
+		w.WriteHeader(http.StatusOK)
+
+		if update {
+			convm := session.ConvWithMetadata{
+				SessionMetadata: sessionState.SessionMetadata,
+				Conv:            conv,
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"tableInterleaveStatus": tableInterleaveStatus,
+				"sessionState":          convm,
+			})
+		} else {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"tableInterleaveStatus": tableInterleaveStatus,
+			})
+		}
+	})
+}
 
-	index.IndexSuggestion()
-	if tableInterleaveStatus.Possible {
-		session.UpdateSessionFile()
+func RemoveParentTable(w http.ResponseWriter, r *http.Request) {
+	tableId := r.FormValue("tableId")
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
+	if tableId == "" {
+		http.Error(w, fmt.Sprintf("Table Id is empty"), http.StatusBadRequest)
+		return
 	}
-	w.WriteHeader(http.StatusOK)
 
-	if update {
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+
+		if conv.SpSchema[tableId].ParentTable.Id == "" {
+			http.Error(w, fmt.Sprintf("Table is not interleaved"), http.StatusBadRequest)
+			return
+		}
+		spTable := conv.SpSchema[tableId]
+		spTable.ParentTable.Id = ""
+		spTable.ParentTable.OnDelete = ""
+		spTable.ParentTable.InterleaveType = ""
+		conv.SpSchema[tableId] = spTable
+		conv.UpdateInterleaveOnDeleteIssue(tableId)
+		conv.UpdateInterleaveStructureIssues()
+
 		convm := session.ConvWithMetadata{
 			SessionMetadata: sessionState.SessionMetadata,
-			Conv:            sessionState.Conv,
+			Conv:            conv,
 		}
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"tableInterleaveStatus": tableInterleaveStatus,
-			"sessionState":          convm,
-		})
-	} else {
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"tableInterleaveStatus": tableInterleaveStatus,
-		})
-	}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
-func RemoveParentTable(w http.ResponseWriter, r *http.Request) {
+// SetInterleaveOnDelete changes the ON DELETE action of an INTERLEAVE IN PARENT
+// table, e.g. to match the foreign key the interleaving replaces. The
+// InterleaveOnDeleteDiverges issue of the table is updated accordingly.
+func SetInterleaveOnDelete(w http.ResponseWriter, r *http.Request) {
 	tableId := r.FormValue("tableId")
-	sessionState := session.GetSessionState()
-	if sessionState.Conv == nil || sessionState.Driver == "" {
+	onDelete := strings.ToUpper(r.FormValue("onDelete"))
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
 		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
 		return
 	}
@@ -972,29 +1467,148 @@ func RemoveParentTable(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("Table Id is empty"), http.StatusBadRequest)
 		return
 	}
+	if onDelete != constants.FK_NO_ACTION && onDelete != constants.FK_CASCADE {
+		http.Error(w, fmt.Sprintf("onDelete value is not valid"), http.StatusBadRequest)
+		return
+	}
+
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+
+		spTable, ok := conv.SpSchema[tableId]
+		if !ok {
+			http.Error(w, fmt.Sprintf("Table not found"), http.StatusNotFound)
+			return
+		}
+		if spTable.ParentTable.Id == "" || spTable.ParentTable.InterleaveType == "IN" {
+			http.Error(w, fmt.Sprintf("Table is not interleaved in parent"), http.StatusBadRequest)
+			return
+		}
+		spTable.ParentTable.OnDelete = onDelete
+		conv.SpSchema[tableId] = spTable
+		conv.UpdateInterleaveOnDeleteIssue(tableId)
+
+		session.UpdateSessionFile(sessionState)
 
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	conv := sessionState.Conv
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
+}
 
-	if conv.SpSchema[tableId].ParentTable.Id == "" {
-		http.Error(w, fmt.Sprintf("Table is not interleaved"), http.StatusBadRequest)
+// GetInterleaveTree returns the trees of interleaved tables of the session
+// schema, for their visualization, along with the tables whose parent tables
+// form a cycle and the ones nested deeper than Spanner supports.
+func GetInterleaveTree(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
 		return
 	}
-	spTable := conv.SpSchema[tableId]
-	spTable.ParentTable.Id = ""
-	spTable.ParentTable.OnDelete = ""
-	spTable.ParentTable.InterleaveType = ""
-	conv.SpSchema[tableId] = spTable
+	var res types.InterleaveTree
+	sessionState.ReadConv(func(conv *internal.Conv) error {
+		res = types.InterleaveTree{Roots: conv.InterleaveTree(), MaxDepth: internal.MaxInterleaveDepth, Cycles: [][]string{}, TooDeep: []string{}}
+		// Cycles are reported from the table of the smallest name.
+		var tableIds []string
+		for tableId := range conv.SpSchema {
+			tableIds = append(tableIds, tableId)
+		}
+		sort.Slice(tableIds, func(i, j int) bool { return conv.SpSchema[tableIds[i]].Name < conv.SpSchema[tableIds[j]].Name })
+		inCycle := map[string]bool{}
+		for _, tableId := range tableIds {
+			if cycle := conv.InterleaveCycle(tableId); cycle != nil && !inCycle[tableId] {
+				var names []string
+				for _, id := range cycle {
+					inCycle[id] = true
+					names = append(names, conv.SpSchema[id].Name)
+				}
+				res.Cycles = append(res.Cycles, names)
+			}
+			if conv.InterleaveLevel(tableId) > internal.MaxInterleaveDepth {
+				res.TooDeep = append(res.TooDeep, conv.SpSchema[tableId].Name)
+			}
+		}
+		return nil
+	})
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(res)
+}
 
-	sessionState.Conv = conv
+// GetInterleaveCandidates returns the foreign keys of the session schema which
+// can be converted to interleavings, for one-click conversion with
+// ConvertInterleaveCandidate.
+func GetInterleaveCandidates(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		candidates := conv.InterleaveCandidates()
+		if candidates == nil {
+			candidates = []internal.InterleaveCandidate{}
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(candidates)
+	})
+}
 
-	convm := session.ConvWithMetadata{
-		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
+// ConvertInterleaveCandidate replaces the foreign key of a table returned by
+// GetInterleaveCandidates by interleaving the table in the referenced table,
+// with the ON DELETE action of the foreign key.
+func ConvertInterleaveCandidate(w http.ResponseWriter, r *http.Request) {
+	tableId := r.FormValue("tableId")
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil || sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
 	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+	if tableId == "" {
+		http.Error(w, fmt.Sprintf("Table Id is empty"), http.StatusBadRequest)
+		return
+	}
+
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		candidate, ok := conv.InterleaveCandidate(tableId)
+		if !ok {
+			http.Error(w, fmt.Sprintf("Table has no foreign key which can be converted to interleaving"), http.StatusBadRequest)
+			return
+		}
+		tableInterleaveStatus := parentTableHelper(conv, tableId, candidate.ParentTableId, "IN PARENT", candidate.OnDelete, true)
+		if !tableInterleaveStatus.Possible {
+			http.Error(w, tableInterleaveStatus.Comment, http.StatusBadRequest)
+			return
+		}
+
+		// The interleaving replaces the foreign key.
+		spTable := conv.SpSchema[tableId]
+		delete(conv.UsedNames, strings.ToLower(candidate.ForeignKey))
+		fks, err := utilities.RemoveFk(sessionState, spTable.ForeignKeys, candidate.ForeignKeyId, conv.SrcSchema[tableId], tableId)
+		if err != nil {
+			// The foreign key was added in the session, it has no source
+			// foreign key nor issues.
+			fks = []ddl.Foreignkey{}
+			for _, fk := range spTable.ForeignKeys {
+				if fk.Id != candidate.ForeignKeyId {
+					fks = append(fks, fk)
+				}
+			}
+		}
+		spTable.ForeignKeys = fks
+		conv.SpSchema[tableId] = spTable
+
+		index.IndexSuggestion(conv)
+		session.UpdateSessionFile(sessionState)
+
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 func UpdateIndexes(w http.ResponseWriter, r *http.Request) {
@@ -1022,128 +1636,153 @@ func UpdateIndexes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	sp := sessionState.Conv.SpSchema[table]
+	sessionState := session.RequestSessionState(r)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		sp := conv.SpSchema[table]
+
+		if err = validateStoredColumns(sp, newIndexes[0]); err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusBadRequest)
+			return
+		}
 
-	st := sessionState.Conv.SrcSchema[table]
+		st := conv.SrcSchema[table]
 
-	for i, ind := range sp.Indexes {
-		if ind.TableId == newIndexes[0].TableId && ind.Id == newIndexes[0].Id {
+		for i, ind := range sp.Indexes {
+			if ind.TableId == newIndexes[0].TableId && ind.Id == newIndexes[0].Id {
 
-			index.RemoveIndexIssues(table, sp.Indexes[i])
+				index.RemoveIndexIssues(conv, table, sp.Indexes[i])
 
-			sp.Indexes[i].Keys = newIndexes[0].Keys
-			sp.Indexes[i].Name = newIndexes[0].Name
-			sp.Indexes[i].TableId = newIndexes[0].TableId
-			sp.Indexes[i].Unique = newIndexes[0].Unique
-			sp.Indexes[i].Id = newIndexes[0].Id
+				sp.Indexes[i].Keys = newIndexes[0].Keys
+				sp.Indexes[i].Name = newIndexes[0].Name
+				sp.Indexes[i].TableId = newIndexes[0].TableId
+				sp.Indexes[i].Unique = newIndexes[0].Unique
+				sp.Indexes[i].Id = newIndexes[0].Id
+				sp.Indexes[i].StoredColumnIds = newIndexes[0].StoredColumnIds
 
-			break
+				break
+			}
 		}
-	}
 
-	sessionState.Conv.SpSchema[table] = sp
+		conv.SpSchema[table] = sp
 
-	sessionState.Conv.SrcSchema[table] = st
+		conv.SrcSchema[table] = st
 
-	session.UpdateSessionFile()
+		session.UpdateSessionFile(sessionState)
 
-	convm := session.ConvWithMetadata{
-		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
+}
+
+// validateStoredColumns checks that the STORING columns of an index exist in
+// the table and are neither index keys nor primary keys, which Spanner stores
+// implicitly.
+func validateStoredColumns(sp ddl.CreateTable, idx ddl.CreateIndex) error {
+	for _, colId := range idx.StoredColumnIds {
+		colDef, ok := sp.ColDefs[colId]
+		if !ok {
+			return fmt.Errorf("stored column %s doesn't exist in table %s", colId, sp.Name)
+		}
+		for _, key := range idx.Keys {
+			if key.ColId == colId {
+				return fmt.Errorf("column %s can't be both a key and a stored column of index %s", colDef.Name, idx.Name)
+			}
+		}
+		for _, pk := range sp.PrimaryKeys {
+			if pk.ColId == colId {
+				return fmt.Errorf("primary key column %s is implicitly stored and can't be added to the STORING clause of index %s", colDef.Name, idx.Name)
+			}
+		}
 	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+	return nil
 }
 
 func DropSecondaryIndex(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
+	sessionState := session.RequestSessionState(r)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
 
-	table := r.FormValue("table")
-	reqBody, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
-	}
+		table := r.FormValue("table")
+		reqBody, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
+		}
 
-	var dropDetail struct{ Id string }
-	if err = json.Unmarshal(reqBody, &dropDetail); err != nil {
-		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
-		return
-	}
-	if sessionState.Conv == nil || sessionState.Driver == "" {
-		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
-		return
-	}
+		var dropDetail struct{ Id string }
+		if err = json.Unmarshal(reqBody, &dropDetail); err != nil {
+			http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
+			return
+		}
+		if conv == nil || sessionState.Driver == "" {
+			http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+			return
+		}
 
-	if table == "" || dropDetail.Id == "" {
-		http.Error(w, fmt.Sprintf("Table name or position is empty"), http.StatusBadRequest)
-	}
-	err = dropSecondaryIndexHelper(table, dropDetail.Id)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("%v", err), http.StatusBadRequest)
-		return
-	}
+		if table == "" || dropDetail.Id == "" {
+			http.Error(w, fmt.Sprintf("Table name or position is empty"), http.StatusBadRequest)
+		}
+		err = dropSecondaryIndexHelper(sessionState, conv, table, dropDetail.Id)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("%v", err), http.StatusBadRequest)
+			return
+		}
 
-	// To set enabled value to false for the rule associated with the dropped index.
-	indexId := dropDetail.Id
-	for i, rule := range sessionState.Conv.Rules {
-		if rule.Type == constants.AddIndex {
-			d, err := json.Marshal(rule.Data)
-			if err != nil {
-				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-				return
-			}
-			var index ddl.CreateIndex
-			err = json.Unmarshal(d, &index)
-			if err != nil {
-				http.Error(w, "Invalid rule data", http.StatusInternalServerError)
-				return
-			}
-			if index.Id == indexId {
-				sessionState.Conv.Rules[i].Enabled = false
-				break
+		// To set enabled value to false for the rule associated with the dropped index.
+		indexId := dropDetail.Id
+		for i, rule := range conv.Rules {
+			if rule.Type == constants.AddIndex {
+				d, err := json.Marshal(rule.Data)
+				if err != nil {
+					http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+					return
+				}
+				var index ddl.CreateIndex
+				err = json.Unmarshal(d, &index)
+				if err != nil {
+					http.Error(w, "Invalid rule data", http.StatusInternalServerError)
+					return
+				}
+				if index.Id == indexId {
+					conv.Rules[i].Enabled = false
+					break
+				}
 			}
 		}
-	}
 
-	convm := session.ConvWithMetadata{
-		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 // GetConversionRate returns table wise color coded conversion rate.
 func GetConversionRate(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	smt_reports := reports.AnalyzeTables(sessionState.Conv, nil)
-	rate := make(map[string]string)
-	for _, t := range smt_reports {
-		rate[t.SpTable], _ = reports.RateSchema(t.Cols, t.Warnings, t.Errors, t.SyntheticPKey != "", false)
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(rate)
+	sessionState := session.RequestSessionState(r)
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		smt_reports := reports.AnalyzeTables(conv, nil)
+		rate := make(map[string]string)
+		for _, t := range smt_reports {
+			rate[t.SpTable], _ = reports.RateSchema(t.Cols, t.Warnings, t.Errors, t.SyntheticPKey != "", false)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(rate)
+	})
 }
 
-func (tableHandler *TableAPIHandler) restoreTableHelper(w http.ResponseWriter, tableId string) session.ConvWithMetadata {
-	sessionState := session.GetSessionState()
-	if sessionState.Conv == nil || sessionState.Driver == "" {
+func (tableHandler *TableAPIHandler) restoreTableHelper(sessionState *session.SessionState, conv *internal.Conv, w http.ResponseWriter, tableId string) session.ConvWithMetadata {
+	if sessionState.Driver == "" {
 		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
 	}
 	if tableId == "" {
 		http.Error(w, fmt.Sprintf("Table Id is empty"), http.StatusBadRequest)
 	}
 
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	conv := sessionState.Conv
 	var toddl common.ToDdl
 	switch sessionState.Driver {
 	case constants.MYSQL:
@@ -1156,6 +1795,8 @@ func (tableHandler *TableAPIHandler) restoreTableHelper(w http.ResponseWriter, t
 		toddl = oracle.InfoSchemaImpl{}.GetToDdl()
 	case constants.CASSANDRA:
 		toddl = cassandra.InfoSchemaImpl{}.GetToDdl()
+	case constants.MONGODB:
+		toddl = mongodb.InfoSchemaImpl{}.GetToDdl()
 	case constants.MYSQLDUMP:
 		toddl = mysql.DbDumpImpl{}.GetToDdl()
 	case constants.PGDUMP:
@@ -1164,7 +1805,7 @@ func (tableHandler *TableAPIHandler) restoreTableHelper(w http.ResponseWriter, t
 		http.Error(w, fmt.Sprintf("Driver : '%s' is not supported", sessionState.Driver), http.StatusBadRequest)
 	}
 
-	err := common.SrcTableToSpannerDDL(conv, toddl, sessionState.Conv.SrcSchema[tableId], tableHandler.DDLVerifier)
+	err := common.SrcTableToSpannerDDL(conv, toddl, conv.SrcSchema[tableId], tableHandler.DDLVerifier)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Restoring spanner table fail"), http.StatusBadRequest)
 	}
@@ -1172,26 +1813,25 @@ func (tableHandler *TableAPIHandler) restoreTableHelper(w http.ResponseWriter, t
 	if sessionState.IsSharded {
 		conv.IsSharded = true
 		conv.AddShardIdColumn()
-		isPresent, isAddedAtFirst := hasShardIdPrimaryKeyRule()
+		isPresent, isAddedAtFirst := hasShardIdPrimaryKeyRule(conv)
 		if isPresent {
-			table := sessionState.Conv.SpSchema[tableId]
-			setShardIdColumnAsPrimaryKeyPerTable(isAddedAtFirst, table)
-			addShardIdToForeignKeyPerTable(isAddedAtFirst, table)
-			addShardIdToReferencedTableFks(tableId, isAddedAtFirst)
-			session.UpdateSessionFile()
+			table := conv.SpSchema[tableId]
+			setShardIdColumnAsPrimaryKeyPerTable(sessionState, conv, isAddedAtFirst, table)
+			addShardIdToForeignKeyPerTable(conv, isAddedAtFirst, table)
+			addShardIdToReferencedTableFks(conv, tableId, isAddedAtFirst)
+			session.UpdateSessionFile(sessionState)
 		}
 	}
-	sessionState.Conv = conv
-	primarykey.DetectHotspot()
+	primarykey.DetectHotspot(sessionState)
 
 	convm := session.ConvWithMetadata{
 		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
+		Conv:            conv,
 	}
 	return convm
 }
 
-func parentTableHelper(tableId string, parentTableId string, interleaveType string, onDelete string, update bool) *types.TableInterleaveStatus {
+func parentTableHelper(conv *internal.Conv, tableId string, parentTableId string, interleaveType string, onDelete string, update bool) *types.TableInterleaveStatus {
 	// Three scenarios:
 	// 1. If update is false and parentTableId is empty in request, then return current interleave status of the table. Comment doesnot matter in this case and hence is empty.
 	// 2. If update is false and parentTableId is not empty in request, then return whether the table can be interleaved in the parentTableId without updating the schema. If possible, then comment is empty else comment contains the reason why it is not possible.
@@ -1201,11 +1841,10 @@ func parentTableHelper(tableId string, parentTableId string, interleaveType stri
 		Possible: false,
 		Comment:  "",
 	}
-	sessionState := session.GetSessionState()
 
 	parentEmptyInRequest := parentTableId == ""
 
-	if _, found := sessionState.Conv.SyntheticPKeys[tableId]; found {
+	if _, found := conv.SyntheticPKeys[tableId]; found {
 		tableInterleaveStatus.Possible = false
 		tableInterleaveStatus.Comment = "Has synthetic pk"
 		return tableInterleaveStatus
@@ -1218,33 +1857,46 @@ func parentTableHelper(tableId string, parentTableId string, interleaveType stri
 	}
 
 	if !parentEmptyInRequest {
-		pk_condition := checkInterleavePrimaryKeyPrefixCondition(tableId, parentTableId)
+		pk_condition := checkInterleavePrimaryKeyPrefixCondition(conv, tableId, parentTableId)
 		if pk_condition != "" {
 			tableInterleaveStatus.Possible = false
 			tableInterleaveStatus.Comment = pk_condition
 			return tableInterleaveStatus
 		}
 
-		cycle_condition := checkInterleaveCycleCondition(tableId, parentTableId)
+		cycle_condition := checkInterleaveCycleCondition(conv, tableId, parentTableId)
 		if cycle_condition != "" {
 			tableInterleaveStatus.Possible = false
 			tableInterleaveStatus.Comment = cycle_condition
 			return tableInterleaveStatus
 		}
+
+		if depth := conv.InterleaveDepth(tableId, parentTableId); depth > internal.MaxInterleaveDepth {
+			tableInterleaveStatus.Possible = false
+			tableInterleaveStatus.Comment = fmt.Sprintf("Interleaving table '%s' in parent table '%s' will nest %d levels of interleaved tables, Spanner supports at most %d.", conv.SpSchema[tableId].Name, conv.SpSchema[parentTableId].Name, depth, internal.MaxInterleaveDepth)
+			return tableInterleaveStatus
+		}
 	}
 
-	sp := sessionState.Conv.SpSchema[tableId]
+	sp := conv.SpSchema[tableId]
 	if update {
 		sp.ParentTable.Id = parentTableId
 		sp.ParentTable.OnDelete = onDelete
 		sp.ParentTable.InterleaveType = interleaveType
-		sessionState.Conv.SpSchema[tableId] = sp
+		conv.SpSchema[tableId] = sp
+		conv.UpdateInterleaveOnDeleteIssue(tableId)
+		conv.UpdateInterleaveStructureIssues()
 	}
 	tableInterleaveStatus.Possible = true
 	tableInterleaveStatus.Comment = ""
 	tableInterleaveStatus.Parent = sp.ParentTable.Id
 	tableInterleaveStatus.OnDelete = sp.ParentTable.OnDelete
 	tableInterleaveStatus.InterleaveType = sp.ParentTable.InterleaveType
+	if !parentEmptyInRequest {
+		if m, ok := conv.CheckInterleaveOnDelete(tableId, parentTableId, interleaveType, onDelete); ok {
+			tableInterleaveStatus.Warning = m.Description(sp.Name, conv.SpSchema[parentTableId].Name)
+		}
+	}
 
 	return tableInterleaveStatus
 }
@@ -1264,10 +1916,9 @@ func hasCycleCheckDfs(tableId string, parentTableId string, undirectedGraph map[
 	return false
 }
 
-func checkInterleaveCycleCondition(tableId string, parentTableId string) string {
-	sessionState := session.GetSessionState()
+func checkInterleaveCycleCondition(conv *internal.Conv, tableId string, parentTableId string) string {
 	undirectedGraph := map[string][]string{}
-	for _, spTable := range sessionState.Conv.SpSchema {
+	for _, spTable := range conv.SpSchema {
 		if spTable.ParentTable.Id != "" && spTable.ParentTable.Id != parentTableId && spTable.Id != tableId {
 			undirectedGraph[spTable.Id] = append(undirectedGraph[spTable.Id], spTable.ParentTable.Id)
 			undirectedGraph[spTable.ParentTable.Id] = append(undirectedGraph[spTable.ParentTable.Id], spTable.Id)
@@ -1277,22 +1928,21 @@ func checkInterleaveCycleCondition(tableId string, parentTableId string) string
 	undirectedGraph[parentTableId] = append(undirectedGraph[parentTableId], tableId)
 	visited := map[string]bool{}
 	if hasCycleCheckDfs(tableId, "", undirectedGraph, visited) {
-		message := fmt.Sprintf("Interleaving table '%s' in parent table '%s' will create a cycle.", sessionState.Conv.SpSchema[tableId].Name, sessionState.Conv.SpSchema[parentTableId].Name)
+		message := fmt.Sprintf("Interleaving table '%s' in parent table '%s' will create a cycle.", conv.SpSchema[tableId].Name, conv.SpSchema[parentTableId].Name)
 		return message
 	}
 	return ""
 }
 
-func checkInterleavePrimaryKeyPrefixCondition(tableId string, refTableId string) string {
+func checkInterleavePrimaryKeyPrefixCondition(conv *internal.Conv, tableId string, refTableId string) string {
 	// Check if all parent primary keys are present in child primary keys with same order.
 	// If yes, then returns empty string else returns the comment why prefix condition is not met.
-	sessionState := session.GetSessionState()
-	childPks := sessionState.Conv.SpSchema[tableId].PrimaryKeys
-	parentPks := sessionState.Conv.SpSchema[refTableId].PrimaryKeys
-	parentTable := sessionState.Conv.SpSchema[refTableId]
-	childTable := sessionState.Conv.SpSchema[tableId]
-	parent_table_name := sessionState.Conv.SpSchema[refTableId].Name
-	child_table_name := sessionState.Conv.SpSchema[tableId].Name
+	childPks := conv.SpSchema[tableId].PrimaryKeys
+	parentPks := conv.SpSchema[refTableId].PrimaryKeys
+	parentTable := conv.SpSchema[refTableId]
+	childTable := conv.SpSchema[tableId]
+	parent_table_name := conv.SpSchema[refTableId].Name
+	child_table_name := conv.SpSchema[tableId].Name
 	if len(parentPks) == 0 || len(childPks) == 0 {
 		message := fmt.Sprintf("Both parent table '%s' and child table '%s' must have primary keys.", parent_table_name, child_table_name)
 		return message
@@ -1320,9 +1970,8 @@ func checkInterleavePrimaryKeyPrefixCondition(tableId string, refTableId string)
 	return ""
 }
 
-func hasShardIdPrimaryKeyRule() (bool, bool) {
-	sessionState := session.GetSessionState()
-	for _, rule := range sessionState.Conv.Rules {
+func hasShardIdPrimaryKeyRule(conv *internal.Conv) (bool, bool) {
+	for _, rule := range conv.Rules {
 		if rule.Type == constants.AddShardIdPrimaryKey {
 			v := rule.Data.(types.ShardIdPrimaryKey)
 			return true, v.AddedAtTheStart
@@ -1331,28 +1980,25 @@ func hasShardIdPrimaryKeyRule() (bool, bool) {
 	return false, false
 }
 
-func dropTableHelper(w http.ResponseWriter, tableId string) session.ConvWithMetadata {
-	sessionState := session.GetSessionState()
-	if sessionState.Conv == nil || sessionState.Driver == "" {
+func dropTableHelper(sessionState *session.SessionState, conv *internal.Conv, w http.ResponseWriter, tableId string) session.ConvWithMetadata {
+	if sessionState.Driver == "" {
 		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
 		return session.ConvWithMetadata{}
 	}
 	if tableId == "" {
 		http.Error(w, fmt.Sprintf("Table Id is empty"), http.StatusBadRequest)
 	}
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	spSchema := sessionState.Conv.SpSchema
-	issues := sessionState.Conv.SchemaIssues
-	syntheticPkey := sessionState.Conv.SyntheticPKeys
+	spSchema := conv.SpSchema
+	issues := conv.SchemaIssues
+	syntheticPkey := conv.SyntheticPKeys
 
 	// remove deleted name from usedName
-	usedNames := sessionState.Conv.UsedNames
-	delete(usedNames, strings.ToLower(sessionState.Conv.SpSchema[tableId].Name))
-	for _, index := range sessionState.Conv.SpSchema[tableId].Indexes {
+	usedNames := conv.UsedNames
+	delete(usedNames, strings.ToLower(conv.SpSchema[tableId].Name))
+	for _, index := range conv.SpSchema[tableId].Indexes {
 		delete(usedNames, index.Name)
 	}
-	for _, fk := range sessionState.Conv.SpSchema[tableId].ForeignKeys {
+	for _, fk := range conv.SpSchema[tableId].ForeignKeys {
 		delete(usedNames, fk.Name)
 	}
 
@@ -1384,8 +2030,10 @@ func dropTableHelper(w http.ResponseWriter, tableId string) session.ConvWithMeta
 			spTable.ParentTable.OnDelete = ""
 			spTable.ParentTable.InterleaveType = ""
 			spSchema[id] = spTable
+			conv.UpdateInterleaveOnDeleteIssue(id)
 		}
 	}
+	conv.UpdateInterleaveStructureIssues()
 
 	// remove interleavable suggestion on droping the parent table
 	for tableName, tableIssues := range issues {
@@ -1404,23 +2052,22 @@ func dropTableHelper(w http.ResponseWriter, tableId string) session.ConvWithMeta
 		}
 	}
 
-	sessionState.Conv.SpSchema = spSchema
-	sessionState.Conv.SchemaIssues = issues
-	sessionState.Conv.UsedNames = usedNames
+	conv.SpSchema = spSchema
+	conv.SchemaIssues = issues
+	conv.UsedNames = usedNames
 
 	convm := session.ConvWithMetadata{
 		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
+		Conv:            conv,
 	}
 	return convm
 }
 
-func addShardIdToReferencedTableFks(tableId string, isAddedAtFirst bool) {
-	sessionState := session.GetSessionState()
-	for _, table := range sessionState.Conv.SpSchema {
+func addShardIdToReferencedTableFks(conv *internal.Conv, tableId string, isAddedAtFirst bool) {
+	for _, table := range conv.SpSchema {
 		for i, fk := range table.ForeignKeys {
 			if fk.ReferTableId == tableId {
-				referredTableShardIdColumn := sessionState.Conv.SpSchema[fk.ReferTableId].ShardIdColumn
+				referredTableShardIdColumn := conv.SpSchema[fk.ReferTableId].ShardIdColumn
 				if isAddedAtFirst {
 					fk.ColIds = append([]string{table.ShardIdColumn}, fk.ColIds...)
 					fk.ReferColumnIds = append([]string{referredTableShardIdColumn}, fk.ReferColumnIds...)
@@ -1428,122 +2075,144 @@ func addShardIdToReferencedTableFks(tableId string, isAddedAtFirst bool) {
 					fk.ColIds = append(fk.ColIds, table.ShardIdColumn)
 					fk.ReferColumnIds = append(fk.ReferColumnIds, referredTableShardIdColumn)
 				}
-				sessionState.Conv.SpSchema[table.Id].ForeignKeys[i] = fk
+				conv.SpSchema[table.Id].ForeignKeys[i] = fk
 			}
 		}
 	}
 }
 
-func initializeTypeMap() {
-	sessionState := session.GetSessionState()
+// initializeTypeMap returns the type maps of the source database of driver:
+// the default Spanner type of each source type, and the Spanner types each
+// source type can be converted to. The maps are built from the settings of
+// conv, so they are built for each request rather than shared by sessions.
+// It returns false if driver isn't supported.
+func initializeTypeMap(conv *internal.Conv, driver string) (map[string]ddl.Type, map[string][]types.TypeIssue, bool) {
+	defaultTypeMap := make(map[string]ddl.Type)
+	typeMap := make(map[string][]types.TypeIssue)
 	var toddl common.ToDdl
-	// Initialize mysqlTypeMap.
-	toddl = mysql.InfoSchemaImpl{}.GetToDdl()
-	for _, srcTypeName := range []string{"bool", "boolean", "varchar", "char", "text", "tinytext", "mediumtext", "longtext", "set", "enum", "json", "bit", "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob", "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "bigint unsigned", "double", "float", "numeric", "decimal", "date", "datetime", "timestamp", "time", "year", "geometrycollection", "multipoint", "multilinestring", "multipolygon", "point", "linestring", "polygon", "geometry"} {
-		var l []types.TypeIssue
-		srcType := schema.MakeType()
-		srcType.Name = srcTypeName
-		for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
-			ty, issues := toddl.ToSpannerType(sessionState.Conv, spType, srcType, false)
-			l = addTypeToList(ty.Name, spType, issues, l)
-		}
-		if srcTypeName == "tinyint" {
-			l = append(l, types.TypeIssue{T: ddl.Bool, Brief: "Only tinyint(1) can be converted to BOOL, for any other mods it will be converted to INT64"})
-		}
-		ty, _ := toddl.ToSpannerType(sessionState.Conv, "", srcType, false)
-		mysqlDefaultTypeMap[srcTypeName] = ty
-		mysqlTypeMap[srcTypeName] = l
-	}
-	// Initialize postgresTypeMap.
-	toddl = postgres.InfoSchemaImpl{}.GetToDdl()
-	for _, srcTypeName := range []string{"bool", "boolean", "bigserial", "bpchar", "character", "bytea", "date", "float8", "double precision", "float4", "real", "int8", "bigint", "int4", "integer", "int2", "smallint", "numeric", "serial", "smallserial", "text", "timestamptz", "timestamp with time zone", "timestamp", "timestamp without time zone", "varchar", "character varying", "path"} {
-		var l []types.TypeIssue
-		srcType := schema.MakeType()
-		srcType.Name = srcTypeName
-		for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
-			ty, issues := toddl.ToSpannerType(sessionState.Conv, spType, srcType, false)
-			l = addTypeToList(ty.Name, spType, issues, l)
-		}
-		ty, _ := toddl.ToSpannerType(sessionState.Conv, "", srcType, false)
-		postgresDefaultTypeMap[srcTypeName] = ty
-		postgresTypeMap[srcTypeName] = l
-	}
-
-	// Initialize sqlserverTypeMap.
-	toddl = sqlserver.InfoSchemaImpl{}.GetToDdl()
-	for _, srcTypeName := range []string{"int", "tinyint", "smallint", "bigint", "bit", "float", "real", "numeric", "decimal", "money", "smallmoney", "char", "nchar", "varchar", "nvarchar", "text", "ntext", "date", "datetime", "datetime2", "smalldatetime", "datetimeoffset", "time", "timestamp", "rowversion", "binary", "varbinary", "image", "xml", "geography", "geometry", "uniqueidentifier", "sql_variant", "hierarchyid"} {
-		var l []types.TypeIssue
-		srcType := schema.MakeType()
-		srcType.Name = srcTypeName
-		for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
-			ty, issues := toddl.ToSpannerType(sessionState.Conv, spType, srcType, false)
-			l = addTypeToList(ty.Name, spType, issues, l)
-		}
-		ty, _ := toddl.ToSpannerType(sessionState.Conv, "", srcType, false)
-		sqlserverDefaultTypeMap[srcTypeName] = ty
-		sqlserverTypeMap[srcTypeName] = l
-	}
-
-	// Initialize oracleTypeMap.
-	toddl = oracle.InfoSchemaImpl{}.GetToDdl()
-	for _, srcTypeName := range []string{"NUMBER", "BFILE", "BLOB", "CHAR", "CLOB", "DATE", "BINARY_DOUBLE", "BINARY_FLOAT", "FLOAT", "LONG", "RAW", "LONG RAW", "NCHAR", "NVARCHAR2", "VARCHAR", "VARCHAR2", "NCLOB", "ROWID", "UROWID", "XMLTYPE", "TIMESTAMP", "INTERVAL", "SDO_GEOMETRY"} {
-		var l []types.TypeIssue
-		srcType := schema.MakeType()
-		srcType.Name = srcTypeName
-		for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
-			ty, issues := toddl.ToSpannerType(sessionState.Conv, spType, srcType, false)
-			l = addTypeToList(ty.Name, spType, issues, l)
-		}
-		ty, _ := toddl.ToSpannerType(sessionState.Conv, "", srcType, false)
-		oracleDefaultTypeMap[srcTypeName] = ty
-		oracleTypeMap[srcTypeName] = l
-	}
-
-	// Initialize cassandraTypeMap
-	toddl = cassandra.InfoSchemaImpl{}.GetToDdl()
-	for _, srcTypeName := range []string{"tinyint", "smallint", "int", "bigint", "float", "double", "decimal", "varint", "text", "varchar", "ascii", "uuid", "timeuuid", "inet", "blob", "date", "timestamp", "time", "duration", "boolean", "counter"} {
-		var l []types.TypeIssue
-		srcType := schema.MakeType()
-		srcType.Name = srcTypeName
-		for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
-			ty, issues := toddl.ToSpannerType(sessionState.Conv, spType, srcType, false)
-			l = addTypeToList(ty.Name, spType, issues, l)
-		}
-		ty, _ := toddl.ToSpannerType(sessionState.Conv, "", srcType, false)
-		cassandraDefaultTypeMap[srcTypeName] = ty
-		cassandraTypeMap[srcTypeName] = l
-	}
-	// Include collection types in Type Mapping
-	for _, srcTypeName := range []string{"tinyint", "smallint", "int", "bigint", "float", "double", "decimal", "varint", "text", "varchar", "ascii", "uuid", "timeuuid", "inet", "blob", "date", "timestamp", "time", "duration", "boolean", "counter"} {
-		listType := fmt.Sprintf("list<%s>", srcTypeName)
-		setType := fmt.Sprintf("set<%s>", srcTypeName)
-		var l []types.TypeIssue
-		srcType := schema.MakeType()
-		srcType.Name = listType
-		for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
-			ty, issues := toddl.ToSpannerType(sessionState.Conv, spType, srcType, false)
-			l = addTypeToList("ARRAY<"+ty.Name+">", "ARRAY<"+spType+">", issues, l)
-		}
-		ty, _ := toddl.ToSpannerType(sessionState.Conv, "", srcType, false)
-		cassandraDefaultTypeMap[listType] = ty
-		cassandraTypeMap[listType] = l
-		cassandraDefaultTypeMap[setType] = ty
-		cassandraTypeMap[setType] = l
-	}
-	for _, keyTypeName := range []string{"tinyint", "smallint", "int", "bigint", "float", "double", "decimal", "varint", "text", "varchar", "ascii", "uuid", "timeuuid", "inet", "blob", "date", "timestamp", "time", "duration", "boolean", "counter"} {
-		for _, valueTypeName := range []string{"tinyint", "smallint", "int", "bigint", "float", "double", "decimal", "varint", "text", "varchar", "ascii", "uuid", "timeuuid", "inet", "blob", "date", "timestamp", "time", "duration", "boolean", "counter"} {
-			mapType := fmt.Sprintf("map<%s,%s>", keyTypeName, valueTypeName)
+	switch driver {
+	case constants.MYSQL, constants.MYSQLDUMP:
+		toddl = mysql.InfoSchemaImpl{}.GetToDdl()
+		for _, srcTypeName := range []string{"bool", "boolean", "varchar", "char", "text", "tinytext", "mediumtext", "longtext", "set", "enum", "json", "bit", "binary", "varbinary", "blob", "tinyblob", "mediumblob", "longblob", "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "smallint unsigned", "mediumint unsigned", "int unsigned", "integer unsigned", "bigint unsigned", "double", "float", "numeric", "decimal", "date", "datetime", "timestamp", "time", "year", "geometrycollection", "multipoint", "multilinestring", "multipolygon", "point", "linestring", "polygon", "geometry"} {
+			var l []types.TypeIssue
+			srcType := schema.MakeType()
+			srcType.Name = srcTypeName
+			for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
+				ty, issues := toddl.ToSpannerType(conv, spType, srcType, false)
+				l = addTypeToList(ty.Name, spType, issues, l)
+			}
+			if srcTypeName == "tinyint" {
+				l = append(l, types.TypeIssue{T: ddl.Bool, Brief: "Only tinyint(1) can be converted to BOOL, for any other mods it will be converted to INT64"})
+			}
+			ty, _ := toddl.ToSpannerType(conv, "", srcType, false)
+			defaultTypeMap[srcTypeName] = ty
+			typeMap[srcTypeName] = l
+		}
+	case constants.POSTGRES, constants.PGDUMP:
+		toddl = postgres.InfoSchemaImpl{}.GetToDdl()
+		for _, srcTypeName := range []string{"bool", "boolean", "bigserial", "bpchar", "character", "bytea", "date", "float8", "double precision", "float4", "real", "int8", "bigint", "int4", "integer", "int2", "smallint", "numeric", "serial", "smallserial", "text", "timestamptz", "timestamp with time zone", "timestamp", "timestamp without time zone", "interval", "bit", "varbit", "varchar", "character varying", "path"} {
+			var l []types.TypeIssue
+			srcType := schema.MakeType()
+			srcType.Name = srcTypeName
+			for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
+				ty, issues := toddl.ToSpannerType(conv, spType, srcType, false)
+				l = addTypeToList(ty.Name, spType, issues, l)
+			}
+			ty, _ := toddl.ToSpannerType(conv, "", srcType, false)
+			defaultTypeMap[srcTypeName] = ty
+			typeMap[srcTypeName] = l
+		}
+	case constants.SQLSERVER:
+		toddl = sqlserver.InfoSchemaImpl{}.GetToDdl()
+		for _, srcTypeName := range []string{"int", "tinyint", "smallint", "bigint", "bit", "float", "real", "numeric", "decimal", "money", "smallmoney", "char", "nchar", "varchar", "nvarchar", "text", "ntext", "date", "datetime", "datetime2", "smalldatetime", "datetimeoffset", "time", "timestamp", "rowversion", "binary", "varbinary", "image", "xml", "geography", "geometry", "uniqueidentifier", "sql_variant", "hierarchyid"} {
+			var l []types.TypeIssue
+			srcType := schema.MakeType()
+			srcType.Name = srcTypeName
+			for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
+				ty, issues := toddl.ToSpannerType(conv, spType, srcType, false)
+				l = addTypeToList(ty.Name, spType, issues, l)
+			}
+			ty, _ := toddl.ToSpannerType(conv, "", srcType, false)
+			defaultTypeMap[srcTypeName] = ty
+			typeMap[srcTypeName] = l
+		}
+	case constants.ORACLE:
+		toddl = oracle.InfoSchemaImpl{}.GetToDdl()
+		for _, srcTypeName := range []string{"NUMBER", "BFILE", "BLOB", "CHAR", "CLOB", "DATE", "BINARY_DOUBLE", "BINARY_FLOAT", "FLOAT", "LONG", "RAW", "LONG RAW", "NCHAR", "NVARCHAR2", "VARCHAR", "VARCHAR2", "NCLOB", "ROWID", "UROWID", "XMLTYPE", "TIMESTAMP", "INTERVAL", "SDO_GEOMETRY"} {
+			var l []types.TypeIssue
+			srcType := schema.MakeType()
+			srcType.Name = srcTypeName
+			for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
+				ty, issues := toddl.ToSpannerType(conv, spType, srcType, false)
+				l = addTypeToList(ty.Name, spType, issues, l)
+			}
+			ty, _ := toddl.ToSpannerType(conv, "", srcType, false)
+			defaultTypeMap[srcTypeName] = ty
+			typeMap[srcTypeName] = l
+		}
+	case constants.CASSANDRA:
+		toddl = cassandra.InfoSchemaImpl{}.GetToDdl()
+		for _, srcTypeName := range []string{"tinyint", "smallint", "int", "bigint", "float", "double", "decimal", "varint", "text", "varchar", "ascii", "uuid", "timeuuid", "inet", "blob", "date", "timestamp", "time", "duration", "boolean", "counter"} {
 			var l []types.TypeIssue
 			srcType := schema.MakeType()
-			srcType.Name = mapType
-			// Currently, the map type can't be edited, so it's only mapped to JSON.
-			ty, issues := toddl.ToSpannerType(sessionState.Conv, ddl.JSON, srcType, false)
-			l = addTypeToList(ty.Name, ddl.JSON, issues, l)
-			ty, _ = toddl.ToSpannerType(sessionState.Conv, "", srcType, false)
-			cassandraDefaultTypeMap[mapType] = ty
-			cassandraTypeMap[mapType] = l
+			srcType.Name = srcTypeName
+			for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
+				ty, issues := toddl.ToSpannerType(conv, spType, srcType, false)
+				l = addTypeToList(ty.Name, spType, issues, l)
+			}
+			ty, _ := toddl.ToSpannerType(conv, "", srcType, false)
+			defaultTypeMap[srcTypeName] = ty
+			typeMap[srcTypeName] = l
+		}
+		// Include collection types in Type Mapping
+		for _, srcTypeName := range []string{"tinyint", "smallint", "int", "bigint", "float", "double", "decimal", "varint", "text", "varchar", "ascii", "uuid", "timeuuid", "inet", "blob", "date", "timestamp", "time", "duration", "boolean", "counter"} {
+			listType := fmt.Sprintf("list<%s>", srcTypeName)
+			setType := fmt.Sprintf("set<%s>", srcTypeName)
+			var l []types.TypeIssue
+			srcType := schema.MakeType()
+			srcType.Name = listType
+			for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
+				ty, issues := toddl.ToSpannerType(conv, spType, srcType, false)
+				l = addTypeToList("ARRAY<"+ty.Name+">", "ARRAY<"+spType+">", issues, l)
+			}
+			ty, _ := toddl.ToSpannerType(conv, "", srcType, false)
+			defaultTypeMap[listType] = ty
+			typeMap[listType] = l
+			defaultTypeMap[setType] = ty
+			typeMap[setType] = l
+		}
+		for _, keyTypeName := range []string{"tinyint", "smallint", "int", "bigint", "float", "double", "decimal", "varint", "text", "varchar", "ascii", "uuid", "timeuuid", "inet", "blob", "date", "timestamp", "time", "duration", "boolean", "counter"} {
+			for _, valueTypeName := range []string{"tinyint", "smallint", "int", "bigint", "float", "double", "decimal", "varint", "text", "varchar", "ascii", "uuid", "timeuuid", "inet", "blob", "date", "timestamp", "time", "duration", "boolean", "counter"} {
+				mapType := fmt.Sprintf("map<%s,%s>", keyTypeName, valueTypeName)
+				var l []types.TypeIssue
+				srcType := schema.MakeType()
+				srcType.Name = mapType
+				// Currently, the map type can't be edited, so it's only mapped to JSON.
+				ty, issues := toddl.ToSpannerType(conv, ddl.JSON, srcType, false)
+				l = addTypeToList(ty.Name, ddl.JSON, issues, l)
+				ty, _ = toddl.ToSpannerType(conv, "", srcType, false)
+				defaultTypeMap[mapType] = ty
+				typeMap[mapType] = l
+			}
+		}
+	case constants.MONGODB:
+		toddl = mongodb.InfoSchemaImpl{}.GetToDdl()
+		for _, srcTypeName := range []string{"objectId", "string", "long", "double", "decimal", "bool", "date", "binData", "object", "array", "mixed"} {
+			var l []types.TypeIssue
+			srcType := schema.MakeType()
+			srcType.Name = srcTypeName
+			for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
+				ty, issues := toddl.ToSpannerType(conv, spType, srcType, false)
+				l = addTypeToList(ty.Name, spType, issues, l)
+			}
+			ty, _ := toddl.ToSpannerType(conv, "", srcType, false)
+			defaultTypeMap[srcTypeName] = ty
+			typeMap[srcTypeName] = l
 		}
+	default:
+		return nil, nil, false
 	}
+	return defaultTypeMap, typeMap, true
 }
 
 func addTypeToList(convertedType string, spType string, issues []internal.SchemaIssue, l []types.TypeIssue) []types.TypeIssue {
@@ -1561,14 +2230,13 @@ func addTypeToList(convertedType string, spType string, issues []internal.Schema
 	return l
 }
 
-func setShardIdColumnAsPrimaryKey(isAddedAtFirst bool) {
-	sessionState := session.GetSessionState()
-	for _, table := range sessionState.Conv.SpSchema {
-		setShardIdColumnAsPrimaryKeyPerTable(isAddedAtFirst, table)
+func setShardIdColumnAsPrimaryKey(sessionState *session.SessionState, conv *internal.Conv, isAddedAtFirst bool) {
+	for _, table := range conv.SpSchema {
+		setShardIdColumnAsPrimaryKeyPerTable(sessionState, conv, isAddedAtFirst, table)
 	}
 }
 
-func setShardIdColumnAsPrimaryKeyPerTable(isAddedAtFirst bool, table ddl.CreateTable) {
+func setShardIdColumnAsPrimaryKeyPerTable(sessionState *session.SessionState, conv *internal.Conv, isAddedAtFirst bool, table ddl.CreateTable) {
 	pkRequest := primarykey.PrimaryKeyRequest{
 		TableId: table.Id,
 		Columns: []ddl.IndexKey{},
@@ -1586,20 +2254,18 @@ func setShardIdColumnAsPrimaryKeyPerTable(isAddedAtFirst bool, table ddl.CreateT
 		size := len(table.PrimaryKeys)
 		pkRequest.Columns = append(pkRequest.Columns, ddl.IndexKey{ColId: table.ShardIdColumn, Order: size + 1})
 	}
-	primarykey.UpdatePrimaryKey(pkRequest)
+	primarykey.UpdatePrimaryKey(sessionState, pkRequest)
 }
 
-func addShardIdColumnToForeignKeys(isAddedAtFirst bool) {
-	sessionState := session.GetSessionState()
-	for _, table := range sessionState.Conv.SpSchema {
-		addShardIdToForeignKeyPerTable(isAddedAtFirst, table)
+func addShardIdColumnToForeignKeys(conv *internal.Conv, isAddedAtFirst bool) {
+	for _, table := range conv.SpSchema {
+		addShardIdToForeignKeyPerTable(conv, isAddedAtFirst, table)
 	}
 }
 
-func addShardIdToForeignKeyPerTable(isAddedAtFirst bool, table ddl.CreateTable) {
-	sessionState := session.GetSessionState()
+func addShardIdToForeignKeyPerTable(conv *internal.Conv, isAddedAtFirst bool, table ddl.CreateTable) {
 	for i, fk := range table.ForeignKeys {
-		referredTableShardIdColumn := sessionState.Conv.SpSchema[fk.ReferTableId].ShardIdColumn
+		referredTableShardIdColumn := conv.SpSchema[fk.ReferTableId].ShardIdColumn
 		if isAddedAtFirst {
 			fk.ColIds = append([]string{table.ShardIdColumn}, fk.ColIds...)
 			fk.ReferColumnIds = append([]string{referredTableShardIdColumn}, fk.ReferColumnIds...)
@@ -1607,19 +2273,18 @@ func addShardIdToForeignKeyPerTable(isAddedAtFirst bool, table ddl.CreateTable)
 			fk.ColIds = append(fk.ColIds, table.ShardIdColumn)
 			fk.ReferColumnIds = append(fk.ReferColumnIds, referredTableShardIdColumn)
 		}
-		sessionState.Conv.SpSchema[table.Id].ForeignKeys[i] = fk
+		conv.SpSchema[table.Id].ForeignKeys[i] = fk
 	}
 }
 
-func initializeAutoGenMap(supportsUuidGeneration bool) {
-	sessionState := session.GetSessionState()
+func initializeAutoGenMap(conv *internal.Conv, supportsUuidGeneration bool) {
 	autoGenMap = make(map[string][]types.AutoGen)
-	switch sessionState.Conv.SpDialect {
+	switch conv.SpDialect {
 	case constants.DIALECT_POSTGRESQL:
-		makePostgresDialectAutoGenMap(sessionState.Conv.SpSequences, supportsUuidGeneration)
+		makePostgresDialectAutoGenMap(conv.SpSequences, supportsUuidGeneration)
 		return
 	default:
-		makeGoogleSqlDialectAutoGenMap(sessionState.Conv.SpSequences, supportsUuidGeneration)
+		makeGoogleSqlDialectAutoGenMap(conv.SpSequences, supportsUuidGeneration)
 		return
 	}
 }
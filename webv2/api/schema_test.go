@@ -8,7 +8,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
@@ -221,12 +223,55 @@ func TestGetTypeMapMySQL(t *testing.T) {
 			{T: ddl.String, Brief: reports.IssueDB[internal.Widened].Brief, DisplayT: ddl.String},
 			{T: ddl.Timestamp, DisplayT: ddl.Timestamp}},
 		"time": {
+			{T: ddl.Int64, Brief: reports.IssueDB[internal.TimeAsSeconds].Brief, DisplayT: ddl.Int64},
 			{T: ddl.String, Brief: reports.IssueDB[internal.Time].Brief, DisplayT: ddl.String}},
 	}
 	assert.Equal(t, expectedTypemap, typemap)
 
 }
 
+func TestGetTypeMapConcurrentSessions(t *testing.T) {
+	handler := session.WithSessionState(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionState := session.RequestSessionState(r)
+		conv := internal.MakeConv()
+		sessionState.Driver = r.URL.Query().Get("driver")
+		if sessionState.Driver == constants.POSTGRES {
+			sessionState.Dialect = constants.DIALECT_POSTGRESQL
+			buildConvPostgres(conv)
+		} else {
+			sessionState.Dialect = constants.DIALECT_GOOGLESQL
+			buildConvMySQL(conv)
+		}
+		sessionState.SetConv(conv)
+		api.GetTypeMap(w, r)
+	}))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for _, driver := range []string{constants.MYSQL, constants.POSTGRES} {
+			wg.Add(1)
+			go func(driver string) {
+				defer wg.Done()
+				rr := httptest.NewRecorder()
+				handler.ServeHTTP(rr, httptest.NewRequest("GET", "/typemap?driver="+driver, nil))
+				assert.Equal(t, http.StatusOK, rr.Code)
+				var typemap map[string][]types.TypeIssue
+				json.Unmarshal(rr.Body.Bytes(), &typemap)
+				// Each session gets the type map of its own driver and dialect.
+				if driver == constants.POSTGRES {
+					assert.Contains(t, typemap, "bigserial")
+					assert.NotContains(t, typemap, "enum")
+					assert.Equal(t, "INT8", typemap["bigserial"][0].DisplayT)
+				} else {
+					assert.Contains(t, typemap, "enum")
+					assert.NotContains(t, typemap, "bigserial")
+					assert.Equal(t, ddl.Int64, typemap["smallint"][0].DisplayT)
+				}
+			}(driver)
+		}
+	}
+	wg.Wait()
+}
+
 func TestGetConversionMySQL(t *testing.T) {
 	sessionState := session.GetSessionState()
 
@@ -255,6 +300,7 @@ func TestGetDDL(t *testing.T) {
 	tc := []struct {
 		name        string
 		conv        *internal.Conv
+		query       string
 		expectedDDL map[string]string
 		statusCode  int64
 	}{
@@ -281,6 +327,24 @@ func TestGetDDL(t *testing.T) {
 				"t2": "CREATE TABLE table2 (\n\td INT64 NOT NULL ,\n) ;"},
 			statusCode: http.StatusOK,
 		},
+		{
+			name: "Filter tables by name",
+			conv: &internal.Conv{
+				SpSchema: map[string]ddl.CreateTable{
+					"t1": {Name: "table1", ColIds: []string{"c1"}, ColDefs: map[string]ddl.ColumnDef{"c1": {Name: "a", T: ddl.Type{Name: ddl.Int64}, NotNull: true}}},
+					"t2": {Name: "table2", ColIds: []string{"c4"}, ColDefs: map[string]ddl.ColumnDef{"c4": {Name: "d", T: ddl.Type{Name: ddl.Int64}, NotNull: true}}},
+				},
+			},
+			query:       "?filter=TABLE2&limit=10",
+			expectedDDL: map[string]string{"t2": "CREATE TABLE table2 (\n\td INT64 NOT NULL ,\n) ;"},
+			statusCode:  http.StatusOK,
+		},
+		{
+			name:       "Invalid limit",
+			conv:       internal.MakeConv(),
+			query:      "?limit=-1",
+			statusCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, tc := range tc {
@@ -289,7 +353,7 @@ func TestGetDDL(t *testing.T) {
 		sessionState.Driver = constants.MYSQL
 		sessionState.Conv = tc.conv
 
-		req, err := http.NewRequest("GET", "/ddl", nil)
+		req, err := http.NewRequest("GET", "/ddl"+tc.query, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -309,6 +373,126 @@ func TestGetDDL(t *testing.T) {
 	}
 }
 
+func TestListTables(t *testing.T) {
+	sessionState := session.GetSessionState()
+	sessionState.Driver = constants.MYSQL
+	sessionState.Conv = internal.MakeConv()
+	sessionState.Conv.SpSchema = map[string]ddl.CreateTable{
+		"t1": {Name: "orders", Id: "t1", ColIds: []string{"c1", "c2", "c3"}},
+		"t2": {Name: "customers", Id: "t2", ColIds: []string{"c4"}},
+		"t3": {Name: "order_items", Id: "t3", ColIds: []string{"c5", "c6"}},
+	}
+	sessionState.Conv.SrcSchema = map[string]schema.Table{"t1": {Name: "src_orders"}}
+	sessionState.Conv.SchemaIssues = map[string]internal.TableIssues{
+		"t2": {TableLevelIssues: []internal.SchemaIssue{internal.MissingPrimaryKey}, ColumnLevelIssues: map[string][]internal.SchemaIssue{"c4": {internal.Widened}}},
+	}
+
+	tc := []struct {
+		name          string
+		query         string
+		expectedNames []string
+		expectedTotal int
+		statusCode    int
+	}{
+		{name: "All tables sorted by name", expectedNames: []string{"customers", "order_items", "orders"}, expectedTotal: 3, statusCode: http.StatusOK},
+		{name: "Filter and paginate", query: "?filter=ORDER&offset=1&limit=1", expectedNames: []string{"orders"}, expectedTotal: 2, statusCode: http.StatusOK},
+		{name: "Sort by columns descending", query: "?sort=-columns", expectedNames: []string{"orders", "order_items", "customers"}, expectedTotal: 3, statusCode: http.StatusOK},
+		{name: "Sort by issues", query: "?sort=-issues&limit=1", expectedNames: []string{"customers"}, expectedTotal: 3, statusCode: http.StatusOK},
+		{name: "Offset past the end", query: "?offset=5", expectedNames: []string{}, expectedTotal: 3, statusCode: http.StatusOK},
+		{name: "Invalid sort", query: "?sort=size", statusCode: http.StatusBadRequest},
+	}
+	for _, tc := range tc {
+		req, err := http.NewRequest("GET", "/tables"+tc.query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(api.ListTables).ServeHTTP(rr, req)
+		assert.Equal(t, tc.statusCode, rr.Code, tc.name)
+		if tc.statusCode != http.StatusOK {
+			continue
+		}
+		var res types.TableList
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res), tc.name)
+		names := []string{}
+		for _, table := range res.Tables {
+			names = append(names, table.Name)
+		}
+		assert.Equal(t, tc.expectedNames, names, tc.name)
+		assert.Equal(t, tc.expectedTotal, res.Total, tc.name)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	sessionState := session.GetSessionState()
+	sessionState.Driver = constants.MYSQL
+	sessionState.Conv = internal.MakeConv()
+	sessionState.Conv.SpSchema = map[string]ddl.CreateTable{
+		"t1": {
+			Name:             "orders",
+			Id:               "t1",
+			ColIds:           []string{"c1", "c2"},
+			ColDefs:          map[string]ddl.ColumnDef{"c1": {Name: "id", Id: "c1"}, "c2": {Name: "customer_id", Id: "c2"}},
+			Indexes:          []ddl.CreateIndex{{Name: "orders_by_customer", Id: "i1"}},
+			ForeignKeys:      []ddl.Foreignkey{{Name: "fk_customer", Id: "f1"}},
+			CheckConstraints: []ddl.CheckConstraint{{Name: "ck_positive", Id: "ck1", Expr: "(customer_id > 0)"}},
+		},
+		"t2": {Name: "customers", Id: "t2", ColIds: []string{"c3"}, ColDefs: map[string]ddl.ColumnDef{"c3": {Name: "name", Id: "c3"}}},
+	}
+	sessionState.Conv.SchemaIssues = map[string]internal.TableIssues{
+		"t2": {ColumnLevelIssues: map[string][]internal.SchemaIssue{"c3": {internal.Widened}}},
+	}
+
+	tc := []struct {
+		name       string
+		query      string
+		expected   []types.SearchResult
+		statusCode int
+	}{
+		{
+			name:  "Objects of all types",
+			query: "?q=CUSTOMER",
+			expected: []types.SearchResult{
+				{Type: "table", Id: "t2", TableId: "t2", Name: "customers", Match: "customers"},
+				{Type: "column", Id: "c2", TableId: "t1", ColId: "c2", Name: "customer_id", Match: "customer_id"},
+				{Type: "index", Id: "i1", TableId: "t1", Name: "orders_by_customer", Match: "orders_by_customer"},
+				{Type: "foreignKey", Id: "f1", TableId: "t1", Name: "fk_customer", Match: "fk_customer"},
+				{Type: "checkConstraint", Id: "ck1", TableId: "t1", Name: "ck_positive", Match: "(customer_id > 0)"},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Issue description",
+			query:      "?q=storage",
+			expected:   []types.SearchResult{{Type: "issue", TableId: "t2", ColId: "c3", Name: "name", Match: "Some columns will consume more storage in Spanner"}},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Limit",
+			query:      "?q=customer&limit=1",
+			expected:   []types.SearchResult{{Type: "table", Id: "t2", TableId: "t2", Name: "customers", Match: "customers"}},
+			statusCode: http.StatusOK,
+		},
+		{name: "No match", query: "?q=invoice", expected: []types.SearchResult{}, statusCode: http.StatusOK},
+		{name: "Empty query", query: "?q=", statusCode: http.StatusBadRequest},
+	}
+	for _, tc := range tc {
+		req, err := http.NewRequest("GET", "/search"+tc.query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(api.Search).ServeHTTP(rr, req)
+		assert.Equal(t, tc.statusCode, rr.Code, tc.name)
+		if tc.statusCode != http.StatusOK {
+			continue
+		}
+		var res []types.SearchResult
+		assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res), tc.name)
+		assert.Equal(t, tc.expected, res, tc.name)
+	}
+}
+
 func TestGetTableWithErrors(t *testing.T) {
 	tc := []struct {
 		name                string
@@ -1934,14 +2118,15 @@ func TestSetParentTable(t *testing.T) {
 					MigrationType: migration.MigrationData_SCHEMA_ONLY.Enum(),
 				},
 			},
-			table:            "t1",
-			parent:           "t2",
-			interleaveType:   "IN",
-			onDelete:         "",
-			statusCode:       http.StatusOK,
-			expectedResponse: &types.TableInterleaveStatus{Possible: true, Parent: "t2", InterleaveType: "IN"},
-			parentTable:      ddl.InterleavedParent{Id: "t2", OnDelete: "", InterleaveType: "IN"},
-			update:           true,
+			table:          "t1",
+			parent:         "t2",
+			interleaveType: "IN",
+			onDelete:       "",
+			statusCode:     http.StatusOK,
+			expectedResponse: &types.TableInterleaveStatus{Possible: true, Parent: "t2", InterleaveType: "IN",
+				Warning: "Table 't1' is interleaved in 't2' in place of foreign key 'fk1' with ON DELETE CASCADE, but the interleaving doesn't enforce the relationship on delete (INTERLEAVE IN)"},
+			parentTable: ddl.InterleavedParent{Id: "t2", OnDelete: "", InterleaveType: "IN"},
+			update:      true,
 		},
 		{
 			name: "successful interleave IN PARENT",
@@ -2369,6 +2554,201 @@ func TestRemoveParentTable(t *testing.T) { // TODO: convert this to table driven
 	}
 }
 
+func TestSetInterleaveOnDelete(t *testing.T) {
+	tc := []struct {
+		name             string
+		tableId          string
+		onDelete         string
+		statusCode       int64
+		expectedOnDelete string
+		expectedIssues   []internal.SchemaIssue
+	}{
+		{name: "Matching the source foreign key", tableId: "t1", onDelete: "cascade", statusCode: http.StatusOK, expectedOnDelete: constants.FK_CASCADE, expectedIssues: []internal.SchemaIssue{}},
+		{name: "Diverging from the source foreign key", tableId: "t1", onDelete: constants.FK_NO_ACTION, statusCode: http.StatusOK, expectedOnDelete: constants.FK_NO_ACTION, expectedIssues: []internal.SchemaIssue{internal.InterleaveOnDeleteDiverges}},
+		{name: "Invalid action", tableId: "t1", onDelete: constants.FK_SET_NULL, statusCode: http.StatusBadRequest},
+		{name: "Table not interleaved", tableId: "t2", onDelete: constants.FK_CASCADE, statusCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range tc {
+		sessionState := session.GetSessionState()
+		sessionState.Driver = constants.MYSQL
+		sessionState.Conv = &internal.Conv{
+			SrcSchema: map[string]schema.Table{
+				"t1": {Name: "table1", Id: "t1", ForeignKeys: []schema.ForeignKey{{Name: "fk1", ReferTableId: "t2", OnDelete: "CASCADE"}}},
+				"t2": {Name: "table2", Id: "t2"},
+			},
+			SpSchema: map[string]ddl.CreateTable{
+				"t1": {Name: "table1", Id: "t1", ParentTable: ddl.InterleavedParent{Id: "t2", OnDelete: constants.FK_NO_ACTION, InterleaveType: "IN PARENT"}},
+				"t2": {Name: "table2", Id: "t2"},
+			},
+			SchemaIssues: map[string]internal.TableIssues{
+				"t1": {TableLevelIssues: []internal.SchemaIssue{internal.InterleaveOnDeleteDiverges}},
+			},
+			Audit: internal.Audit{
+				MigrationType: migration.MigrationData_MIGRATION_TYPE_UNSPECIFIED.Enum(),
+			},
+		}
+		req, err := http.NewRequest("POST", "/setInterleaveOnDelete?tableId="+tc.tableId+"&onDelete="+url.QueryEscape(tc.onDelete), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.SetInterleaveOnDelete)
+		handler.ServeHTTP(rr, req)
+		if status := rr.Code; int64(status) != tc.statusCode {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v", tc.name, status, tc.statusCode)
+		}
+		if tc.statusCode == http.StatusOK {
+			var res *internal.Conv
+			json.Unmarshal(rr.Body.Bytes(), &res)
+			assert.Equal(t, tc.expectedOnDelete, res.SpSchema["t1"].ParentTable.OnDelete, tc.name)
+			assert.Equal(t, tc.expectedIssues, res.SchemaIssues["t1"].TableLevelIssues, tc.name)
+		}
+	}
+}
+
+func TestGetInterleaveTree(t *testing.T) {
+	sessionState := session.GetSessionState()
+	sessionState.Driver = constants.MYSQL
+	sessionState.Conv = &internal.Conv{
+		SpSchema: map[string]ddl.CreateTable{
+			"t1": {Name: "table1", Id: "t1"},
+			"t2": {Name: "table2", Id: "t2", ParentTable: ddl.InterleavedParent{Id: "t1", OnDelete: constants.FK_CASCADE, InterleaveType: "IN PARENT"}},
+			"t3": {Name: "table3", Id: "t3", ParentTable: ddl.InterleavedParent{Id: "t4", InterleaveType: "IN"}},
+			"t4": {Name: "table4", Id: "t4", ParentTable: ddl.InterleavedParent{Id: "t3", InterleaveType: "IN"}},
+		},
+		Audit: internal.Audit{
+			MigrationType: migration.MigrationData_MIGRATION_TYPE_UNSPECIFIED.Enum(),
+		},
+	}
+	req, err := http.NewRequest("GET", "/interleaveTree", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	handler := http.HandlerFunc(api.GetInterleaveTree)
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var res types.InterleaveTree
+	json.Unmarshal(rr.Body.Bytes(), &res)
+	assert.Equal(t, types.InterleaveTree{
+		Roots: []*internal.InterleaveNode{
+			{TableId: "t1", Name: "table1", Depth: 1, Children: []*internal.InterleaveNode{
+				{TableId: "t2", Name: "table2", InterleaveType: "IN PARENT", OnDelete: constants.FK_CASCADE, Depth: 2, Children: []*internal.InterleaveNode{}},
+			}},
+		},
+		MaxDepth: internal.MaxInterleaveDepth,
+		Cycles:   [][]string{{"table3", "table4"}},
+		TooDeep:  []string{},
+	}, res)
+}
+
+func TestConvertInterleaveCandidate(t *testing.T) {
+	tc := []struct {
+		name       string
+		tableId    string
+		statusCode int64
+	}{
+		{name: "Table with a convertible foreign key", tableId: "t1", statusCode: http.StatusOK},
+		{name: "Table without convertible foreign key", tableId: "t2", statusCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range tc {
+		sessionState := session.GetSessionState()
+		sessionState.Driver = constants.MYSQL
+		sessionState.Conv = &internal.Conv{
+			SrcSchema: map[string]schema.Table{
+				"t1": {Name: "table1", Id: "t1", ForeignKeys: []schema.ForeignKey{{Name: "fk1", Id: "f1", ReferTableId: "t2", OnDelete: "CASCADE"}}},
+				"t2": {Name: "table2", Id: "t2"},
+			},
+			SpSchema: map[string]ddl.CreateTable{
+				"t1": {
+					Name:   "table1",
+					Id:     "t1",
+					ColIds: []string{"c1", "c2"},
+					ColDefs: map[string]ddl.ColumnDef{
+						"c1": {Name: "a", Id: "c1", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+						"c2": {Name: "b", Id: "c2", T: ddl.Type{Name: ddl.Int64}, NotNull: true},
+					},
+					PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}, {ColId: "c2", Order: 2}},
+					ForeignKeys: []ddl.Foreignkey{{Name: "fk1", Id: "f1", ColIds: []string{"c1"}, ReferTableId: "t2", ReferColumnIds: []string{"c3"}, OnDelete: constants.FK_CASCADE}},
+				},
+				"t2": {
+					Name:        "table2",
+					Id:          "t2",
+					ColIds:      []string{"c3"},
+					ColDefs:     map[string]ddl.ColumnDef{"c3": {Name: "a", Id: "c3", T: ddl.Type{Name: ddl.Int64}, NotNull: true}},
+					PrimaryKeys: []ddl.IndexKey{{ColId: "c3", Order: 1}},
+				},
+			},
+			SchemaIssues: map[string]internal.TableIssues{},
+			UsedNames:    map[string]bool{"table1": true, "table2": true, "fk1": true},
+			Audit: internal.Audit{
+				MigrationType: migration.MigrationData_MIGRATION_TYPE_UNSPECIFIED.Enum(),
+			},
+		}
+		req, err := http.NewRequest("POST", "/convertInterleaveCandidate?tableId="+tc.tableId, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(api.ConvertInterleaveCandidate)
+		handler.ServeHTTP(rr, req)
+		if status := rr.Code; int64(status) != tc.statusCode {
+			t.Errorf("%s: handler returned wrong status code: got %v want %v", tc.name, status, tc.statusCode)
+		}
+		if tc.statusCode == http.StatusOK {
+			var res *internal.Conv
+			json.Unmarshal(rr.Body.Bytes(), &res)
+			assert.Equal(t, ddl.InterleavedParent{Id: "t2", OnDelete: constants.FK_CASCADE, InterleaveType: "IN PARENT"}, res.SpSchema["t1"].ParentTable, tc.name)
+			assert.Empty(t, res.SpSchema["t1"].ForeignKeys, tc.name)
+			assert.False(t, res.UsedNames["fk1"], tc.name)
+		}
+	}
+}
+
+func TestResolveNameConflicts(t *testing.T) {
+	sessionState := session.GetSessionState()
+	sessionState.Driver = constants.MYSQL
+	sessionState.Conv = &internal.Conv{
+		SpSchema: map[string]ddl.CreateTable{
+			"t1": {Name: "table1", Id: "t1", Indexes: []ddl.CreateIndex{{Name: "table2", Id: "i1", TableId: "t1"}}},
+			"t2": {Name: "table2", Id: "t2"},
+		},
+		SchemaIssues: map[string]internal.TableIssues{},
+		UsedNames:    map[string]bool{"table1": true, "table2": true},
+		Audit: internal.Audit{
+			MigrationType: migration.MigrationData_MIGRATION_TYPE_UNSPECIFIED.Enum(),
+		},
+	}
+
+	req, err := http.NewRequest("GET", "/nameConflicts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(api.GetNameConflicts).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var conflicts []internal.NameConflict
+	json.Unmarshal(rr.Body.Bytes(), &conflicts)
+	assert.Equal(t, []internal.NameConflict{{Name: "table2", Objects: []internal.NamedObject{
+		{Kind: internal.TableObject, Id: "t2", Name: "table2"},
+		{Kind: internal.IndexObject, TableId: "t1", Id: "i1", Name: "table2", Proposed: "table2_1"},
+	}}}, conflicts)
+
+	req, err = http.NewRequest("POST", "/resolveNameConflicts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	http.HandlerFunc(api.ResolveNameConflicts).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var res *internal.Conv
+	json.Unmarshal(rr.Body.Bytes(), &res)
+	assert.Equal(t, "table2_1", res.SpSchema["t1"].Indexes[0].Name)
+	assert.Empty(t, sessionState.Conv.NameConflicts())
+}
+
 func buildConvMySQL(conv *internal.Conv) {
 	conv.SrcSchema = map[string]schema.Table{
 		"t1": {
@@ -3006,6 +3386,90 @@ func TestVerifyCheckConstraintExpressions(t *testing.T) {
 	}
 }
 
+func TestVerifyExpression(t *testing.T) {
+	tests := []struct {
+		name           string
+		request        types.VerifyExpressionRequest
+		output         internal.ExpressionVerificationOutput
+		wantType       string
+		wantStatusCode int
+		wantResponse   types.VerifyExpressionResponse
+	}{
+		{
+			name:           "valid check constraint",
+			request:        types.VerifyExpressionRequest{TableId: "t1", Type: "CHECK", Expression: "(col1 > 0)"},
+			output:         internal.ExpressionVerificationOutput{Result: true},
+			wantType:       "table1",
+			wantStatusCode: http.StatusOK,
+			wantResponse:   types.VerifyExpressionResponse{Valid: true},
+		},
+		{
+			name:           "invalid default value",
+			request:        types.VerifyExpressionRequest{TableId: "t1", ColId: "c1", Type: "DEFAULT", Expression: "'abc'"},
+			output:         internal.ExpressionVerificationOutput{Result: false, Err: errors.New("invalid cast")},
+			wantType:       ddl.Int64,
+			wantStatusCode: http.StatusOK,
+			wantResponse:   types.VerifyExpressionResponse{Valid: false, Error: "invalid cast"},
+		},
+		{
+			name:           "unknown column",
+			request:        types.VerifyExpressionRequest{TableId: "t1", ColId: "c2", Type: "DEFAULT", Expression: "1"},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown type",
+			request:        types.VerifyExpressionRequest{TableId: "t1", Type: "INDEX", Expression: "1"},
+			wantStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAccessor := new(mocks.MockExpressionVerificationAccessor)
+			handler := &api.ExpressionsVerificationHandler{ExpressionVerificationAccessor: mockAccessor}
+			sessionState := session.GetSessionState()
+			sessionState.Driver = constants.MYSQL
+			sessionState.Conv = internal.MakeConv()
+			sessionState.Conv.SpSchema = map[string]ddl.CreateTable{
+				"t1": {
+					Name:        "table1",
+					Id:          "t1",
+					PrimaryKeys: []ddl.IndexKey{{ColId: "c1"}},
+					ColIds:      []string{"c1"},
+					ColDefs: map[string]ddl.ColumnDef{
+						"c1": {Name: "col1", Id: "c1", T: ddl.Type{Name: ddl.Int64}},
+					},
+				},
+			}
+			mockAccessor.On("RefreshSpannerClient", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			mockAccessor.On("VerifyExpressions", mock.Anything, mock.MatchedBy(func(input internal.VerifyExpressionsInput) bool {
+				return len(input.ExpressionDetailList) == 1 && input.ExpressionDetailList[0].ReferenceElement.Name == tc.wantType
+			})).Return(internal.VerifyExpressionsOutput{
+				ExpressionVerificationOutputList: []internal.ExpressionVerificationOutput{tc.output},
+			})
+
+			body, err := json.Marshal(tc.request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req, err := http.NewRequest("POST", "/verifyExpression", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			handler.VerifyExpression(rr, req)
+
+			assert.Equal(t, tc.wantStatusCode, rr.Code)
+			if tc.wantStatusCode == http.StatusOK {
+				var response types.VerifyExpressionResponse
+				if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, tc.wantResponse, response)
+			}
+		})
+	}
+}
+
 func TestHandleExpressionColError(t *testing.T) {
 	conv := internal.MakeConv()
 	conv.SchemaIssues = map[string]internal.TableIssues{
@@ -3044,3 +3508,38 @@ func TestHandleExpressionColError(t *testing.T) {
 	res2 := handler.HandleExpressionColErrorForTest(tc2, conv, internal.DefaultValueError)
 	assert.Equal(t, "", res2)
 }
+
+func TestRenameTable(t *testing.T) {
+	sessionState := session.GetSessionState()
+	sessionState.Driver = constants.MYSQL
+	sessionState.Conv = internal.MakeConv()
+	sessionState.Conv.SpSchema = ddl.Schema{"t1": {Name: "orders", Id: "t1"}, "t2": {Name: "items", Id: "t2"}}
+	sessionState.Conv.UsedNames = map[string]bool{"orders": true, "items": true}
+
+	tests := []struct {
+		name           string
+		newName        string
+		wantStatusCode int
+		wantName       string
+	}{
+		{name: "name used by another table", newName: "ITEMS", wantStatusCode: http.StatusBadRequest, wantName: "orders"},
+		{name: "invalid name", newName: "new orders", wantStatusCode: http.StatusBadRequest, wantName: "orders"},
+		{name: "valid name", newName: "purchases", wantStatusCode: http.StatusOK, wantName: "purchases"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(map[string]string{"Name": tc.newName})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req, err := http.NewRequest("POST", "/rename/table?table=t1", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(api.RenameTable).ServeHTTP(rr, req)
+			assert.Equal(t, tc.wantStatusCode, rr.Code)
+			assert.Equal(t, tc.wantName, sessionState.Conv.SpSchema["t1"].Name)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal/reports"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/types"
+)
+
+// Types of the search results.
+const (
+	searchTypeTable           = "table"
+	searchTypeColumn          = "column"
+	searchTypeIndex           = "index"
+	searchTypeForeignKey      = "foreignKey"
+	searchTypeCheckConstraint = "checkConstraint"
+	searchTypeIssue           = "issue"
+)
+
+// defaultSearchLimit is the maximum number of search results returned when
+// the request doesn't set one.
+const defaultSearchLimit = 100
+
+// Search matches the q query parameter, case-insensitively, against the names
+// of the tables, columns, indexes and constraints of the Spanner schema, the
+// expressions of the check constraints and the descriptions of the issues of
+// the session. At most limit results are returned, in table order.
+func Search(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.FormValue("q"))
+	if query == "" {
+		http.Error(w, "Search query q is empty", http.StatusBadRequest)
+		return
+	}
+	limit := defaultSearchLimit
+	if s := r.FormValue("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			http.Error(w, fmt.Sprintf("Invalid limit %q: must be a positive integer", s), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil {
+		http.Error(w, "Schema is not converted. Please retry converting the database to Spanner.", http.StatusNotFound)
+		return
+	}
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		results := searchSchema(conv, query)
+		if len(results) > limit {
+			results = results[:limit]
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(results)
+	})
+}
+
+// searchSchema returns the schema objects and issues of conv matching query.
+// Tables are searched by name, and the objects of each table in the order of
+// its definition.
+func searchSchema(conv *internal.Conv, query string) []types.SearchResult {
+	query = strings.ToLower(query)
+	matches := func(s string) bool { return strings.Contains(strings.ToLower(s), query) }
+	results := []types.SearchResult{}
+	for _, tableId := range common.GetSortedTableIdsBySpName(conv.SpSchema) {
+		table := conv.SpSchema[tableId]
+		if matches(table.Name) {
+			results = append(results, types.SearchResult{Type: searchTypeTable, Id: tableId, TableId: tableId, Name: table.Name, Match: table.Name})
+		}
+		for _, colId := range table.ColIds {
+			col := table.ColDefs[colId]
+			if matches(col.Name) {
+				results = append(results, types.SearchResult{Type: searchTypeColumn, Id: colId, TableId: tableId, ColId: colId, Name: col.Name, Match: col.Name})
+			}
+		}
+		for _, index := range table.Indexes {
+			if matches(index.Name) {
+				results = append(results, types.SearchResult{Type: searchTypeIndex, Id: index.Id, TableId: tableId, Name: index.Name, Match: index.Name})
+			}
+		}
+		for _, fk := range table.ForeignKeys {
+			if matches(fk.Name) {
+				results = append(results, types.SearchResult{Type: searchTypeForeignKey, Id: fk.Id, TableId: tableId, Name: fk.Name, Match: fk.Name})
+			}
+		}
+		for _, cc := range table.CheckConstraints {
+			switch {
+			case matches(cc.Name):
+				results = append(results, types.SearchResult{Type: searchTypeCheckConstraint, Id: cc.Id, TableId: tableId, Name: cc.Name, Match: cc.Name})
+			case matches(cc.Expr):
+				results = append(results, types.SearchResult{Type: searchTypeCheckConstraint, Id: cc.Id, TableId: tableId, Name: cc.Name, Match: cc.Expr})
+			}
+		}
+		results = append(results, searchIssues(conv, tableId, matches)...)
+	}
+	return results
+}
+
+// searchIssues returns the issues of a table, and of its columns, whose
+// description matches.
+func searchIssues(conv *internal.Conv, tableId string, matches func(string) bool) []types.SearchResult {
+	table := conv.SpSchema[tableId]
+	issues := conv.SchemaIssues[tableId]
+	var results []types.SearchResult
+	for _, issue := range issues.TableLevelIssues {
+		if brief := reports.IssueDB[issue].Brief; brief != "" && matches(brief) {
+			results = append(results, types.SearchResult{Type: searchTypeIssue, TableId: tableId, Name: table.Name, Match: brief})
+		}
+	}
+	var colIds []string
+	for colId := range issues.ColumnLevelIssues {
+		colIds = append(colIds, colId)
+	}
+	sort.Strings(colIds)
+	for _, colId := range colIds {
+		for _, issue := range issues.ColumnLevelIssues[colId] {
+			if brief := reports.IssueDB[issue].Brief; brief != "" && matches(brief) {
+				results = append(results, types.SearchResult{Type: searchTypeIssue, TableId: tableId, ColId: colId, Name: table.ColDefs[colId].Name, Match: brief})
+			}
+		}
+	}
+	return results
+}
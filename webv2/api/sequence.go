@@ -9,10 +9,10 @@ import (
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/utilities"
-	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
 )
 
 func AddNewSequence(w http.ResponseWriter, r *http.Request) {
@@ -31,34 +31,34 @@ func AddNewSequence(w http.ResponseWriter, r *http.Request) {
 	}
 	seq.ColumnsUsingSeq = make(map[string][]string)
 
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
+	sessionState := session.RequestSessionState(r)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
 
-	if ok, _ := utilities.CheckSpannerNamesValidity([]string{seq.Name}); !ok {
-		http.Error(w, fmt.Sprintf("Sequence Name is not valid: %v", seq.Name), http.StatusBadRequest)
-		return
-	}
+		if ok, _ := utilities.CheckSpannerNamesValidity([]string{seq.Name}); !ok {
+			http.Error(w, fmt.Sprintf("Sequence Name is not valid: %v", seq.Name), http.StatusBadRequest)
+			return
+		}
 
-	// Check that the new names are not already used by existing tables, secondary indexes, sequence or foreign key constraints.
-	if ok, err := utilities.CanRename([]string{seq.Name}, ""); !ok {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
+		// Check that the new names are not already used by existing tables, secondary indexes, sequence or foreign key constraints.
+		if ok, err := utilities.CanRename(sessionState, []string{seq.Name}, ""); !ok {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-	spSequences := sessionState.Conv.SpSequences
-	seq.Id = internal.GenerateSequenceId()
-	sessionState.Conv.UsedNames[strings.ToLower(seq.Name)] = true
+		spSequences := conv.SpSequences
+		seq.Id = internal.GenerateSequenceId()
+		conv.UsedNames[strings.ToLower(seq.Name)] = true
 
-	spSequences[seq.Id] = seq
-	sessionState.Conv.SpSequences = spSequences
+		spSequences[seq.Id] = seq
+		conv.SpSequences = spSequences
 
-	convm := session.ConvWithMetadata{
-		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 func UpdateSequence(w http.ResponseWriter, r *http.Request) {
@@ -76,66 +76,66 @@ func UpdateSequence(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	spSequences := sessionState.Conv.SpSequences
+	sessionState := session.RequestSessionState(r)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		spSequences := conv.SpSequences
 
-	for i, seq := range spSequences {
+		for i, seq := range spSequences {
 
-		if seq.Id == newSeq.Id {
-			newSeq.ColumnsUsingSeq = spSequences[i].ColumnsUsingSeq
-			spSequences[i] = newSeq
-			break
+			if seq.Id == newSeq.Id {
+				newSeq.ColumnsUsingSeq = spSequences[i].ColumnsUsingSeq
+				spSequences[i] = newSeq
+				break
+			}
 		}
-	}
 
-	sessionState.Conv.SpSequences = spSequences
+		conv.SpSequences = spSequences
 
-	convm := session.ConvWithMetadata{
-		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 func DropSequence(w http.ResponseWriter, r *http.Request) {
 	sequenceId := r.FormValue("sequence")
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
+	sessionState := session.RequestSessionState(r)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
 
-	if sessionState.Conv == nil || sessionState.Driver == "" {
-		http.Error(w, "Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner.", http.StatusNotFound)
-		return
-	}
+		if conv == nil || sessionState.Driver == "" {
+			http.Error(w, "Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner.", http.StatusNotFound)
+			return
+		}
 
-	spSequence := sessionState.Conv.SpSequences
-	if sequenceId == "" {
-		http.Error(w, "Sequence name is empty", http.StatusBadRequest)
-	}
+		spSequence := conv.SpSequences
+		if sequenceId == "" {
+			http.Error(w, "Sequence name is empty", http.StatusBadRequest)
+		}
 
-	if _, seqExists := spSequence[sequenceId]; !seqExists {
-		http.Error(w, "Sequence doesn't exist", http.StatusBadRequest)
-	}
+		if _, seqExists := spSequence[sequenceId]; !seqExists {
+			http.Error(w, "Sequence doesn't exist", http.StatusBadRequest)
+		}
 
-	updatedTables := dropSequenceHelper(spSequence[sequenceId].ColumnsUsingSeq, sessionState.Conv.SpSchema)
-	sessionState.Conv.SpSchema = updatedTables
+		updatedTables := dropSequenceHelper(spSequence[sequenceId].ColumnsUsingSeq, conv.SpSchema)
+		conv.SpSchema = updatedTables
 
-	sequenceName := getSequenceName(sequenceId, spSequence)
-	usedNames := sessionState.Conv.UsedNames
-	delete(usedNames, sequenceName)
+		sequenceName := getSequenceName(sequenceId, spSequence)
+		usedNames := conv.UsedNames
+		delete(usedNames, sequenceName)
 
-	delete(spSequence, sequenceId)
-	sessionState.Conv.SpSequences = spSequence
+		delete(spSequence, sequenceId)
+		conv.SpSequences = spSequence
 
-	convm := session.ConvWithMetadata{
-		SessionMetadata: sessionState.SessionMetadata,
-		Conv:            sessionState.Conv,
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(convm)
+		convm := session.ConvWithMetadata{
+			SessionMetadata: sessionState.SessionMetadata,
+			Conv:            conv,
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(convm)
+	})
 }
 
 func dropSequenceHelper(columnsUsingSeq map[string][]string, tables ddl.Schema) ddl.Schema {
@@ -150,24 +150,22 @@ func dropSequenceHelper(columnsUsingSeq map[string][]string, tables ddl.Schema)
 }
 
 func GetSequenceDDL(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-	conv := sessionState.Conv
-
-	seqDDL := make(map[string]string)
-	for seqName, seq := range sessionState.Conv.SpSequences {
-		var sDdl string
-		switch sessionState.Dialect {
-		case constants.POSTGRES:
-			sDdl = seq.PGPrintSequence(ddl.Config{ProtectIds: false, SpDialect: conv.SpDialect})
-		default:
-			sDdl = seq.PrintSequence(ddl.Config{ProtectIds: false, SpDialect: conv.SpDialect})
+	sessionState := session.RequestSessionState(r)
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		seqDDL := make(map[string]string)
+		for seqName, seq := range conv.SpSequences {
+			var sDdl string
+			switch sessionState.Dialect {
+			case constants.POSTGRES:
+				sDdl = seq.PGPrintSequence(ddl.Config{ProtectIds: false, SpDialect: conv.SpDialect})
+			default:
+				sDdl = seq.PrintSequence(ddl.Config{ProtectIds: false, SpDialect: conv.SpDialect})
+			}
+			seqDDL[seqName] = sDdl
 		}
-		seqDDL[seqName] = sDdl
-	}
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(seqDDL)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(seqDDL)
+	})
 }
 
 func GetSequenceKind(w http.ResponseWriter, r *http.Request) {
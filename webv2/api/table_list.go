@@ -0,0 +1,154 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/types"
+)
+
+// totalCountHeader is the response header carrying the number of tables
+// matching the filter of a paginated request, across all pages.
+const totalCountHeader = "X-Total-Count"
+
+// tableListOptions select a page of the tables of the session. They are read
+// from the offset, limit, filter and sort query parameters, so that the UI
+// doesn't load very large schemas at once.
+type tableListOptions struct {
+	Offset int
+	// Limit is the maximum number of tables returned, all of them when 0.
+	Limit int
+	// Filter keeps the tables whose name contains it, case-insensitively.
+	Filter string
+	// Sort is the order of the tables: name, columns or issues, prefixed by
+	// "-" for descending order. Defaults to name.
+	Sort string
+}
+
+func parseTableListOptions(r *http.Request) (tableListOptions, error) {
+	opts := tableListOptions{Filter: r.FormValue("filter"), Sort: r.FormValue("sort")}
+	for _, p := range []struct {
+		name  string
+		value *int
+	}{{"offset", &opts.Offset}, {"limit", &opts.Limit}} {
+		s := r.FormValue(p.name)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return opts, fmt.Errorf("invalid %s %q: must be a non-negative integer", p.name, s)
+		}
+		*p.value = n
+	}
+	switch strings.TrimPrefix(opts.Sort, "-") {
+	case "", "name", "columns", "issues":
+	default:
+		return opts, fmt.Errorf("invalid sort %q: must be name, columns or issues, optionally prefixed by -", opts.Sort)
+	}
+	return opts, nil
+}
+
+// selectTables returns the ids of the tables of conv in the page selected by
+// opts, and the number of tables matching the filter.
+func selectTables(conv *internal.Conv, opts tableListOptions) ([]string, int) {
+	filter := strings.ToLower(opts.Filter)
+	var ids []string
+	for id, table := range conv.SpSchema {
+		if strings.Contains(strings.ToLower(table.Name), filter) {
+			ids = append(ids, id)
+		}
+	}
+	key := strings.TrimPrefix(opts.Sort, "-")
+	desc := strings.HasPrefix(opts.Sort, "-")
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := conv.SpSchema[ids[i]], conv.SpSchema[ids[j]]
+		cmp := 0
+		switch key {
+		case "columns":
+			cmp = len(a.ColIds) - len(b.ColIds)
+		case "issues":
+			cmp = countTableIssues(conv, ids[i]) - countTableIssues(conv, ids[j])
+		}
+		// Ties, and the default sort, are ordered by name then id.
+		if cmp == 0 {
+			cmp = strings.Compare(a.Name, b.Name)
+		}
+		if cmp == 0 {
+			cmp = strings.Compare(ids[i], ids[j])
+		}
+		if desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	})
+	total := len(ids)
+	if opts.Offset >= total {
+		return []string{}, total
+	}
+	ids = ids[opts.Offset:]
+	if opts.Limit > 0 && opts.Limit < len(ids) {
+		ids = ids[:opts.Limit]
+	}
+	return ids, total
+}
+
+func countTableIssues(conv *internal.Conv, tableId string) int {
+	issues := conv.SchemaIssues[tableId]
+	n := len(issues.TableLevelIssues)
+	for _, colIssues := range issues.ColumnLevelIssues {
+		n += len(colIssues)
+	}
+	return n
+}
+
+// ListTables returns a page of the Spanner tables of the session, selected by
+// the offset, limit, filter and sort query parameters.
+func ListTables(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseTableListOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil {
+		http.Error(w, "Schema is not converted. Please retry converting the database to Spanner.", http.StatusNotFound)
+		return
+	}
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		ids, total := selectTables(conv, opts)
+		list := types.TableList{Tables: []types.TableListItem{}, Total: total}
+		for _, id := range ids {
+			table := conv.SpSchema[id]
+			list.Tables = append(list.Tables, types.TableListItem{
+				Id:      id,
+				Name:    table.Name,
+				SrcName: conv.SrcSchema[id].Name,
+				Columns: len(table.ColIds),
+				Issues:  countTableIssues(conv, id),
+			})
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(list)
+	})
+}
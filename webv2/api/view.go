@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/types"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/utilities"
+)
+
+// GetViews returns the Spanner views of the session, sorted by name.
+func GetViews(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil {
+		http.Error(w, fmt.Sprintf("Schema is not converted. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		views := []ddl.CreateView{}
+		for _, v := range conv.SpViews {
+			views = append(views, v)
+		}
+		sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(views)
+	})
+}
+
+// AddView adds a Spanner view to the session. The view is created after
+// the tables when the schema is migrated.
+func AddView(w http.ResponseWriter, r *http.Request) {
+	view, ok := readView(w, r)
+	if !ok {
+		return
+	}
+	sessionState := session.RequestSessionState(r)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		if _, err := conv.AddView(view.Name, view.Query); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeSession(sessionState, conv, w)
+	})
+}
+
+// UpdateView changes the name and query of a Spanner view of the session.
+func UpdateView(w http.ResponseWriter, r *http.Request) {
+	view, ok := readView(w, r)
+	if !ok {
+		return
+	}
+	sessionState := session.RequestSessionState(r)
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		if err := conv.UpdateView(view.Id, view.Name, view.Query); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeSession(sessionState, conv, w)
+	})
+}
+
+// DropView removes a Spanner view from the session.
+func DropView(w http.ResponseWriter, r *http.Request) {
+	viewId := r.FormValue("id")
+	sessionState := session.RequestSessionState(r)
+	if sessionState.GetConv() == nil {
+		http.Error(w, fmt.Sprintf("Schema is not converted. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
+	updateConv(w, sessionState, func(conv *internal.Conv) {
+		if err := conv.DropView(viewId); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeSession(sessionState, conv, w)
+	})
+}
+
+// VerifyView verifies the query of a view against the session schema, without
+// saving it into the session.
+func (expressionVerificationHandler *ExpressionsVerificationHandler) VerifyView(w http.ResponseWriter, r *http.Request) {
+	view, ok := readView(w, r)
+	if !ok {
+		return
+	}
+	sessionState := session.RequestSessionState(r)
+	if sessionState.Driver == "" {
+		http.Error(w, fmt.Sprintf("Schema is not converted or Driver is not configured properly. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return
+	}
+	readConv(w, sessionState, func(conv *internal.Conv) {
+		ctx := context.Background()
+		if err := expressionVerificationHandler.ExpressionVerificationAccessor.RefreshSpannerClient(ctx, conv.SpProjectId, conv.SpInstanceId); err != nil {
+			http.Error(w, fmt.Sprintf("Error while creating the Spanner client : %v", err), http.StatusInternalServerError)
+			return
+		}
+		result := expressionVerificationHandler.ExpressionVerificationAccessor.VerifyExpressions(ctx, internal.VerifyExpressionsInput{
+			Conv:   conv,
+			Source: sessionState.Driver,
+			ExpressionDetailList: []internal.ExpressionDetail{{
+				Expression:       view.Query,
+				Type:             constants.VIEW_EXPRESSION,
+				ReferenceElement: internal.ReferenceElement{Name: view.Name},
+				ExpressionId:     internal.GenerateExpressionId(),
+				Metadata:         map[string]string{"viewId": view.Id},
+			}},
+		})
+		if len(result.ExpressionVerificationOutputList) == 0 {
+			http.Error(w, fmt.Sprintf("Unhandled error: : %v", result.Err), http.StatusInternalServerError)
+			return
+		}
+		output := result.ExpressionVerificationOutputList[0]
+		response := types.VerifyExpressionResponse{Valid: output.Result && output.Err == nil}
+		if output.Err != nil {
+			response.Error = output.Err.Error()
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(response)
+	})
+}
+
+// readView parses the view of the request body, and writes an error to w if
+// it is invalid.
+func readView(w http.ResponseWriter, r *http.Request) (ddl.CreateView, bool) {
+	var view ddl.CreateView
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
+		return view, false
+	}
+	if err = json.Unmarshal(reqBody, &view); err != nil {
+		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
+		return view, false
+	}
+	if session.RequestSessionState(r).GetConv() == nil {
+		http.Error(w, fmt.Sprintf("Schema is not converted. Please retry converting the database to Spanner."), http.StatusNotFound)
+		return view, false
+	}
+	if ok, _ := utilities.CheckSpannerNamesValidity([]string{view.Name}); !ok {
+		http.Error(w, fmt.Sprintf("View Name is not valid: %v", view.Name), http.StatusBadRequest)
+		return view, false
+	}
+	return view, true
+}
+
+// writeSession saves the session and writes it to w.
+func writeSession(sessionState *session.SessionState, conv *internal.Conv, w http.ResponseWriter) {
+	session.UpdateSessionFile(sessionState)
+	convm := session.ConvWithMetadata{
+		SessionMetadata: sessionState.SessionMetadata,
+		Conv:            conv,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(convm)
+}
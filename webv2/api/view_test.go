@@ -0,0 +1,69 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/api"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViews(t *testing.T) {
+	sessionState := session.GetSessionState()
+	sessionState.Driver = constants.MYSQL
+	sessionState.Conv = internal.MakeConv()
+	sessionState.Conv.SpSchema = ddl.Schema{"t1": {Name: "orders", Id: "t1"}}
+
+	post := func(handler http.HandlerFunc, url string, view ddl.CreateView) *httptest.ResponseRecorder {
+		body, err := json.Marshal(view)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := post(api.AddView, "/AddView", ddl.CreateView{Name: "open_orders", Query: "SELECT id FROM orders WHERE open"})
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = post(api.AddView, "/AddView", ddl.CreateView{Name: "orders", Query: "SELECT 1"})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, 1, len(sessionState.Conv.SpViews))
+	var viewId string
+	for id := range sessionState.Conv.SpViews {
+		viewId = id
+	}
+
+	rr = post(api.UpdateView, "/UpdateView", ddl.CreateView{Id: viewId, Name: "open_orders", Query: "SELECT id, total FROM orders WHERE open"})
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	req, err := http.NewRequest("GET", "/views", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	http.HandlerFunc(api.GetViews).ServeHTTP(rr, req)
+	var views []ddl.CreateView
+	json.Unmarshal(rr.Body.Bytes(), &views)
+	assert.Equal(t, []ddl.CreateView{{Id: viewId, Name: "open_orders", Query: "SELECT id, total FROM orders WHERE open"}}, views)
+
+	req, err = http.NewRequest("POST", "/drop/view?id="+viewId, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr = httptest.NewRecorder()
+	http.HandlerFunc(api.DropView).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, sessionState.Conv.SpViews)
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth provides optional authentication and role based access control
+// for the web APIs, so that the web UI can be deployed centrally.
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"google.golang.org/api/idtoken"
+)
+
+const (
+	// ModeNone disables authentication. All requests are treated as editors.
+	ModeNone = "none"
+	// ModeIAP validates the signed header added by Identity-Aware Proxy.
+	ModeIAP = "iap"
+	// ModeOAuth validates a Google-signed OIDC bearer token in the
+	// Authorization header.
+	ModeOAuth = "oauth"
+
+	// IAPJWTHeader is the header carrying the JWT signed by Identity-Aware Proxy.
+	IAPJWTHeader = "X-Goog-IAP-JWT-Assertion"
+)
+
+// Role is the access level granted to an authenticated user.
+type Role int
+
+const (
+	// RoleNone grants no access.
+	RoleNone Role = iota
+	// RoleViewer grants read-only access: only GET, HEAD and OPTIONS requests.
+	RoleViewer
+	// RoleEditor grants full access, including modifying the session.
+	RoleEditor
+)
+
+// Config holds the authentication settings of the web server.
+type Config struct {
+	Mode     string   // One of ModeNone, ModeIAP or ModeOAuth.
+	Audience string   // Expected audience of the token.
+	Editors  []string // Emails of users with the editor role.
+	Viewers  []string // Emails of users with the viewer role.
+}
+
+// roleKey is the request context key of the authenticated user's role.
+type roleKey struct{}
+
+// userKey is the request context key of the authenticated user's email.
+type userKey struct{}
+
+// tokenValidator validates a token for an audience and returns its payload.
+// It is a variable so that tests can stub token validation.
+var tokenValidator = idtoken.Validate
+
+// Validate checks that the config is complete for the selected mode.
+func (c Config) Validate() error {
+	switch c.Mode {
+	case "", ModeNone:
+		return nil
+	case ModeIAP, ModeOAuth:
+		if c.Audience == "" {
+			return fmt.Errorf("an audience is required for auth mode %q", c.Mode)
+		}
+		if len(c.Editors) == 0 && len(c.Viewers) == 0 {
+			return fmt.Errorf("at least one editor or viewer is required for auth mode %q", c.Mode)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid auth mode %q, must be one of [%s, %s, %s]", c.Mode, ModeNone, ModeIAP, ModeOAuth)
+	}
+}
+
+// Enabled returns true if requests need to be authenticated.
+func (c Config) Enabled() bool {
+	return c.Mode != "" && c.Mode != ModeNone
+}
+
+// RoleOf returns the role assigned to the given email. Editors take
+// precedence over viewers, and "*" matches any authenticated user.
+func (c Config) RoleOf(email string) Role {
+	if matchEmail(c.Editors, email) {
+		return RoleEditor
+	}
+	if matchEmail(c.Viewers, email) {
+		return RoleViewer
+	}
+	return RoleNone
+}
+
+// Middleware rejects requests which are not authenticated, or whose user's
+// role does not allow the request method. It is a no-op if auth is disabled.
+func Middleware(c Config, next http.Handler) http.Handler {
+	if !c.Enabled() {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// CORS preflight requests carry no credentials.
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		email, err := c.authenticate(r.Context(), r)
+		if err != nil {
+			logger.Log.Debug(fmt.Sprintf("rejecting unauthenticated request to %s: %v", r.URL.Path, err))
+			http.Error(w, "Unauthenticated", http.StatusUnauthorized)
+			return
+		}
+		role := c.RoleOf(email)
+		if !allowed(role, r.Method) {
+			http.Error(w, fmt.Sprintf("User %s is not allowed to %s %s", email, r.Method, r.URL.Path), http.StatusForbidden)
+			return
+		}
+		ctx := context.WithValue(r.Context(), roleKey{}, role)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, userKey{}, email)))
+	})
+}
+
+// RequireEditor restricts a handler to editors regardless of the request
+// method. It is used for GET endpoints which modify the session. Requests
+// which were not authenticated by Middleware, i.e. when auth is disabled,
+// are let through.
+func RequireEditor(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if role, ok := r.Context().Value(roleKey{}).(Role); ok && role != RoleEditor {
+			http.Error(w, fmt.Sprintf("Editor role is required for %s", r.URL.Path), http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// UserFromContext returns the email of the user authenticated by Middleware,
+// or an empty string if auth is disabled.
+func UserFromContext(ctx context.Context) string {
+	email, _ := ctx.Value(userKey{}).(string)
+	return email
+}
+
+// authenticate validates the credentials of the request and returns the
+// email of the authenticated user.
+func (c Config) authenticate(ctx context.Context, r *http.Request) (string, error) {
+	var token string
+	switch c.Mode {
+	case ModeIAP:
+		token = r.Header.Get(IAPJWTHeader)
+	case ModeOAuth:
+		authHeader := r.Header.Get("Authorization")
+		if strings.HasPrefix(authHeader, "Bearer ") {
+			token = strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+		}
+	}
+	if token == "" {
+		return "", fmt.Errorf("missing credentials")
+	}
+	payload, err := tokenValidator(ctx, token, c.Audience)
+	if err != nil {
+		return "", err
+	}
+	email, _ := payload.Claims["email"].(string)
+	if email == "" {
+		return "", fmt.Errorf("token has no email claim")
+	}
+	return email, nil
+}
+
+func allowed(role Role, method string) bool {
+	switch role {
+	case RoleEditor:
+		return true
+	case RoleViewer:
+		return method == http.MethodGet || method == http.MethodHead
+	default:
+		return false
+	}
+}
+
+func matchEmail(emails []string, email string) bool {
+	for _, e := range emails {
+		if e == "*" || strings.EqualFold(e, email) {
+			return true
+		}
+	}
+	return false
+}
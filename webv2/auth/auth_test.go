@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/api/idtoken"
+)
+
+func init() {
+	logger.Log = zap.NewNop()
+}
+
+func stubTokenValidator(t *testing.T) {
+	orig := tokenValidator
+	tokenValidator = func(ctx context.Context, token, audience string) (*idtoken.Payload, error) {
+		if audience != "test-audience" {
+			return nil, fmt.Errorf("audience mismatch")
+		}
+		switch token {
+		case "editor-token":
+			return &idtoken.Payload{Claims: map[string]interface{}{"email": "editor@example.com"}}, nil
+		case "viewer-token":
+			return &idtoken.Payload{Claims: map[string]interface{}{"email": "viewer@example.com"}}, nil
+		case "other-token":
+			return &idtoken.Payload{Claims: map[string]interface{}{"email": "other@example.com"}}, nil
+		}
+		return nil, fmt.Errorf("invalid token")
+	}
+	t.Cleanup(func() { tokenValidator = orig })
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{"disabled", Config{}, false},
+		{"none", Config{Mode: ModeNone}, false},
+		{"iap", Config{Mode: ModeIAP, Audience: "aud", Editors: []string{"a@example.com"}}, false},
+		{"oauth viewers only", Config{Mode: ModeOAuth, Audience: "aud", Viewers: []string{"*"}}, false},
+		{"missing audience", Config{Mode: ModeIAP, Editors: []string{"a@example.com"}}, true},
+		{"missing users", Config{Mode: ModeOAuth, Audience: "aud"}, true},
+		{"invalid mode", Config{Mode: "basic"}, true},
+	}
+	for _, tc := range tests {
+		err := tc.config.Validate()
+		assert.Equal(t, tc.wantErr, err != nil, tc.name)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	stubTokenValidator(t)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	mux := http.NewServeMux()
+	mux.Handle("/ddl", next)
+	mux.Handle("/setparent", RequireEditor(next))
+	tests := []struct {
+		name   string
+		config Config
+		method string
+		path   string
+		header string
+		value  string
+		want   int
+	}{
+		{"auth disabled", Config{}, http.MethodPost, "/ddl", "", "", http.StatusOK},
+		{"missing token", Config{Mode: ModeIAP, Audience: "test-audience", Editors: []string{"editor@example.com"}}, http.MethodGet, "/ddl", "", "", http.StatusUnauthorized},
+		{"invalid token", Config{Mode: ModeIAP, Audience: "test-audience", Editors: []string{"editor@example.com"}}, http.MethodGet, "/ddl", IAPJWTHeader, "bad", http.StatusUnauthorized},
+		{"wrong audience", Config{Mode: ModeIAP, Audience: "other", Editors: []string{"editor@example.com"}}, http.MethodGet, "/ddl", IAPJWTHeader, "editor-token", http.StatusUnauthorized},
+		{"iap editor post", Config{Mode: ModeIAP, Audience: "test-audience", Editors: []string{"editor@example.com"}}, http.MethodPost, "/ddl", IAPJWTHeader, "editor-token", http.StatusOK},
+		{"oauth editor post", Config{Mode: ModeOAuth, Audience: "test-audience", Editors: []string{"editor@example.com"}}, http.MethodPost, "/ddl", "Authorization", "Bearer editor-token", http.StatusOK},
+		{"oauth without bearer", Config{Mode: ModeOAuth, Audience: "test-audience", Editors: []string{"editor@example.com"}}, http.MethodPost, "/ddl", "Authorization", "editor-token", http.StatusUnauthorized},
+		{"viewer get", Config{Mode: ModeIAP, Audience: "test-audience", Viewers: []string{"viewer@example.com"}}, http.MethodGet, "/ddl", IAPJWTHeader, "viewer-token", http.StatusOK},
+		{"viewer post", Config{Mode: ModeIAP, Audience: "test-audience", Viewers: []string{"viewer@example.com"}}, http.MethodPost, "/ddl", IAPJWTHeader, "viewer-token", http.StatusForbidden},
+		{"viewer mutating get", Config{Mode: ModeIAP, Audience: "test-audience", Viewers: []string{"viewer@example.com"}}, http.MethodGet, "/setparent", IAPJWTHeader, "viewer-token", http.StatusForbidden},
+		{"editor mutating get", Config{Mode: ModeIAP, Audience: "test-audience", Editors: []string{"editor@example.com"}}, http.MethodGet, "/setparent", IAPJWTHeader, "editor-token", http.StatusOK},
+		{"unknown user", Config{Mode: ModeIAP, Audience: "test-audience", Editors: []string{"editor@example.com"}}, http.MethodGet, "/ddl", IAPJWTHeader, "other-token", http.StatusForbidden},
+		{"wildcard viewer", Config{Mode: ModeIAP, Audience: "test-audience", Editors: []string{"editor@example.com"}, Viewers: []string{"*"}}, http.MethodGet, "/ddl", IAPJWTHeader, "other-token", http.StatusOK},
+		{"preflight", Config{Mode: ModeIAP, Audience: "test-audience", Editors: []string{"editor@example.com"}}, http.MethodOptions, "/ddl", "", "", http.StatusOK},
+	}
+	for _, tc := range tests {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.header != "" {
+			req.Header.Set(tc.header, tc.value)
+		}
+		rr := httptest.NewRecorder()
+		Middleware(tc.config, mux).ServeHTTP(rr, req)
+		assert.Equal(t, tc.want, rr.Code, tc.name)
+	}
+}
+
+func TestUserFromContext(t *testing.T) {
+	stubTokenValidator(t)
+	var user string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user = UserFromContext(r.Context())
+	})
+	req := httptest.NewRequest(http.MethodPost, "/ddl", nil)
+	req.Header.Set(IAPJWTHeader, "editor-token")
+	Middleware(Config{Mode: ModeIAP, Audience: "test-audience", Editors: []string{"editor@example.com"}}, next).ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "editor@example.com", user)
+
+	user = "unset"
+	Middleware(Config{}, next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/ddl", nil))
+	assert.Equal(t, "", user)
+}
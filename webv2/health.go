@@ -0,0 +1,132 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webv2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/spanner"
+	instance "cloud.google.com/go/spanner/admin/instance/apiv1"
+	helpers "github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/helpers"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
+	instancepb "google.golang.org/genproto/googleapis/spanner/admin/instance/v1"
+)
+
+// readinessTimeout bounds the time spent by each readiness check.
+const readinessTimeout = 5 * time.Second
+
+// expressionVerifierErr is the error, if any, met while creating the
+// expression verifier of the web APIs.
+var expressionVerifierErr error
+
+// readinessCheck checks that a dependency of the web server is available.
+type readinessCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// readinessResult is the result of a readiness check.
+type readinessResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// readinessChecks returns the checks run by the readiness probe. It is a
+// variable so that tests can stub the checks.
+var readinessChecks = func() []readinessCheck {
+	return []readinessCheck{
+		{"sessionStore", checkSessionStore},
+		{"spanner", checkSpannerInstance},
+		{"expressionVerifier", func(ctx context.Context) error { return expressionVerifierErr }},
+	}
+}
+
+// withProbes serves the liveness and readiness probes, and passes other
+// requests to next. Probes are served ahead of authentication so that load
+// balancers and Kubernetes can reach them.
+func withProbes(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			healthz(w, r)
+		case "/readyz":
+			readyz(w, r)
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// healthz reports that the web server is up.
+func healthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// readyz reports whether the web server can serve requests, i.e. whether the
+// session store, Spanner and the expression verifier are available.
+func readyz(w http.ResponseWriter, r *http.Request) {
+	status, results := http.StatusOK, []readinessResult{}
+	for _, c := range readinessChecks() {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+		err := c.check(ctx)
+		cancel()
+		result := readinessResult{Name: c.name, Status: "ok"}
+		if err != nil {
+			status = http.StatusServiceUnavailable
+			result.Status, result.Error = "unavailable", err.Error()
+		}
+		results = append(results, result)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": http.StatusText(status), "checks": results})
+}
+
+// checkSessionStore checks that the sessions can be saved. Offline sessions
+// are kept locally, while online sessions are saved in the metadata database.
+func checkSessionStore(ctx context.Context) error {
+	sessionState := session.GetSessionState()
+	if sessionState.IsOffline || sessionState.SpannerProjectId == "" || sessionState.SpannerInstanceID == "" {
+		return nil
+	}
+	client, err := spanner.NewClient(ctx, helpers.GetSpannerUri(sessionState.SpannerProjectId, sessionState.SpannerInstanceID))
+	if err != nil {
+		return fmt.Errorf("can't create client for the metadata database: %v", err)
+	}
+	defer client.Close()
+	return client.Single().Query(ctx, spanner.Statement{SQL: "SELECT 1"}).Do(func(*spanner.Row) error { return nil })
+}
+
+// checkSpannerInstance checks that the Spanner instance is reachable, when
+// one is configured.
+func checkSpannerInstance(ctx context.Context) error {
+	sessionState := session.GetSessionState()
+	if sessionState.SpannerProjectId == "" || sessionState.SpannerInstanceID == "" {
+		return nil
+	}
+	instanceClient, err := instance.NewInstanceAdminClient(ctx)
+	if err != nil {
+		return fmt.Errorf("can't create instance admin client: %v", err)
+	}
+	defer instanceClient.Close()
+	_, err = instanceClient.GetInstance(ctx, &instancepb.GetInstanceRequest{Name: fmt.Sprintf("projects/%s/instances/%s", sessionState.SpannerProjectId, sessionState.SpannerInstanceID)})
+	return err
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webv2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbes(t *testing.T) {
+	saved := readinessChecks
+	defer func() { readinessChecks = saved }()
+	var verifierErr error
+	readinessChecks = func() []readinessCheck {
+		return []readinessCheck{
+			{"sessionStore", func(ctx context.Context) error { return nil }},
+			{"expressionVerifier", func(ctx context.Context) error { return verifierErr }},
+		}
+	}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) })
+	// Probes are reachable without credentials when auth is enabled.
+	handler := withProbes(auth.Middleware(auth.Config{Mode: auth.ModeIAP, Audience: "a", Editors: []string{"*"}}, next))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	verifierErr = fmt.Errorf("no credentials")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	var body struct {
+		Status string            `json:"status"`
+		Checks []readinessResult `json:"checks"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Equal(t, "Service Unavailable", body.Status)
+	assert.Equal(t, []readinessResult{
+		{Name: "sessionStore", Status: "ok"},
+		{Name: "expressionVerifier", Status: "unavailable", Error: "no credentials"},
+	}, body.Checks)
+
+	// Other requests still need credentials.
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/ddl", nil))
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
@@ -96,6 +96,22 @@ var TABLE_STATEMENTS = []string{
 		ResourceData JSON,
 		CreatedAt TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true),
 	) PRIMARY KEY(ResourceId, CreatedAt)`,
+	`CREATE TABLE IF NOT EXISTS SMT_MIGRATION_RUN (
+		RunId STRING(100) NOT NULL,
+		MigrationType STRING(50),
+		SourceDatabaseType STRING(50) NOT NULL,
+		SourceDatabaseName STRING(MAX),
+		SpannerProjectId STRING(100) NOT NULL,
+		SpannerInstanceId STRING(100) NOT NULL,
+		SpannerDatabaseName STRING(100) NOT NULL,
+		Dialect STRING(50) NOT NULL,
+		StartedAt TIMESTAMP NOT NULL,
+		EndedAt TIMESTAMP NOT NULL,
+		RowCount INT64 NOT NULL,
+		GoodRows INT64 NOT NULL,
+		BadRows INT64 NOT NULL,
+		SessionFile STRING(MAX),
+	) PRIMARY KEY(RunId)`,
 }
 
 func GetSpannerUri(projectId string, instanceId string) string {
@@ -182,6 +198,8 @@ func GetSourceDatabaseFromDriver(driver string) (string, error) {
 		return driver, nil
 	case constants.CASSANDRA:
 		return constants.CASSANDRA, nil
+	case constants.MONGODB:
+		return constants.MONGODB, nil
 	default:
 		return "", fmt.Errorf("unsupported driver type: %v", driver)
 	}
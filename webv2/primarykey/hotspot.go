@@ -21,29 +21,23 @@ import (
 )
 
 // DetectHotspot adds hotspot detected suggestion in schema conversion process for database.
-func DetectHotspot() {
-
-	sessionState := session.GetSessionState()
-
+func DetectHotspot(sessionState *session.SessionState) {
 	for _, spannerTable := range sessionState.Conv.SpSchema {
 
-		isHotSpot(spannerTable.PrimaryKeys, spannerTable)
+		isHotSpot(sessionState, spannerTable.PrimaryKeys, spannerTable)
 	}
 
 }
 
 // Helper method for hotspot detection.
-func isHotSpot(insert []ddl.IndexKey, spannerTable ddl.CreateTable) {
-
-	hotspotTimestamp(insert, spannerTable)
-	hotspotAutoincrement(insert, spannerTable)
+func isHotSpot(sessionState *session.SessionState, insert []ddl.IndexKey, spannerTable ddl.CreateTable) {
+	hotspotTimestamp(sessionState, insert, spannerTable)
+	hotspotAutoincrement(sessionState, insert, spannerTable)
 }
 
 // hotspotTimestamp checks Timestamp hotspot.
 // If present adds HotspotTimestamp as an issue in Issues.
-func hotspotTimestamp(insert []ddl.IndexKey, spannerTable ddl.CreateTable) {
-	sessionState := session.GetSessionState()
-
+func hotspotTimestamp(sessionState *session.SessionState, insert []ddl.IndexKey, spannerTable ddl.CreateTable) {
 	for i := 0; i < len(insert); i++ {
 
 		for _, c := range spannerTable.ColDefs {
@@ -67,13 +61,12 @@ func hotspotTimestamp(insert []ddl.IndexKey, spannerTable ddl.CreateTable) {
 
 // hotspotAutoincrement check AutoIncrement hotspot.
 // If present adds AutoIncrement as an issue in Issues.
-func hotspotAutoincrement(insert []ddl.IndexKey, spannerTable ddl.CreateTable) {
-
+func hotspotAutoincrement(sessionState *session.SessionState, insert []ddl.IndexKey, spannerTable ddl.CreateTable) {
 	for i := 0; i < len(insert); i++ {
 		for _, c := range spannerTable.ColDefs {
 			if insert[i].ColId == c.Name {
 				spannerColumnId := c.Id
-				detecthotspotAutoincrement(spannerTable, spannerColumnId)
+				detecthotspotAutoincrement(sessionState, spannerTable, spannerColumnId)
 			}
 
 		}
@@ -82,9 +75,7 @@ func hotspotAutoincrement(insert []ddl.IndexKey, spannerTable ddl.CreateTable) {
 
 // detecthotspotAutoincrement checks for autoincrement hotspot.
 // If present it adds HotspotAutoIncrement as an issue in Issues.
-func detecthotspotAutoincrement(spannerTable ddl.CreateTable, spannerColumnId string) {
-
-	sessionState := session.GetSessionState()
+func detecthotspotAutoincrement(sessionState *session.SessionState, spannerTable ddl.CreateTable, spannerColumnId string) {
 	sourcetable := sessionState.Conv.SrcSchema[spannerTable.Id]
 
 	for _, s := range sourcetable.ColDefs {
@@ -65,7 +65,7 @@ func TestDetectHotspot(t *testing.T) {
 	for _, tt := range tc {
 		sessionState := session.GetSessionState()
 		sessionState.Conv = tt.conv
-		DetectHotspot()
+		DetectHotspot(session.GetSessionState())
 		actual := sessionState.Conv.SchemaIssues[tt.tableId].ColumnLevelIssues[tt.columnId]
 		if !reflect.DeepEqual(actual, tt.expectedIssue) {
 			t.Errorf("%s failed, expected: %v, got: %v", tt.name, tt.expectedIssue, actual)
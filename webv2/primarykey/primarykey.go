@@ -23,9 +23,8 @@ import (
 
 // updateprimaryKey insert or delete primary key column.
 // updateprimaryKey also update desc and order for primaryKey column.
-func updatePrimaryKey(pkRequest PrimaryKeyRequest, spannerTable ddl.CreateTable, synthColId string) (ddl.CreateTable, bool) {
-
-	spannerTable, isSynthPkRemoved := insertOrRemovePrimarykey(pkRequest, spannerTable, synthColId)
+func updatePrimaryKey(sessionState *session.SessionState, pkRequest PrimaryKeyRequest, spannerTable ddl.CreateTable, synthColId string) (ddl.CreateTable, bool) {
+	spannerTable, isSynthPkRemoved := insertOrRemovePrimarykey(sessionState, pkRequest, spannerTable, synthColId)
 
 	for i := 0; i < len(pkRequest.Columns); i++ {
 
@@ -45,17 +44,16 @@ func updatePrimaryKey(pkRequest PrimaryKeyRequest, spannerTable ddl.CreateTable,
 
 // insertOrRemovePrimarykey performs insert or remove primary key operation based on
 // difference of two pkRequest and spannerTable.PrimaryKeys.
-func insertOrRemovePrimarykey(pkRequest PrimaryKeyRequest, spannerTable ddl.CreateTable, synthColId string) (ddl.CreateTable, bool) {
-
+func insertOrRemovePrimarykey(sessionState *session.SessionState, pkRequest PrimaryKeyRequest, spannerTable ddl.CreateTable, synthColId string) (ddl.CreateTable, bool) {
 	cidRequestList := getColumnIdListFromPrimaryKeyRequest(pkRequest)
 	cidSpannerTableList := getColumnIdListOfSpannerTablePrimaryKey(spannerTable)
 
 	// primary key Id only presnt in pkeyrequest.
 	// hence new primary key add primary key into  spannerTable.Pk list
 	leftjoin := utilities.Difference(cidRequestList, cidSpannerTableList)
-	insert := addPrimaryKey(leftjoin, pkRequest, spannerTable)
+	insert := addPrimaryKey(sessionState, leftjoin, pkRequest, spannerTable)
 
-	isHotSpot(insert, spannerTable)
+	isHotSpot(sessionState, insert, spannerTable)
 
 	spannerTable.PrimaryKeys = append(spannerTable.PrimaryKeys, insert...)
 
@@ -71,7 +69,7 @@ func insertOrRemovePrimarykey(pkRequest PrimaryKeyRequest, spannerTable ddl.Crea
 	}
 
 	if len(rightjoin) > 0 {
-		nlist := removePrimaryKey(rightjoin, spannerTable)
+		nlist := removePrimaryKey(sessionState, rightjoin, spannerTable)
 		spannerTable.PrimaryKeys = nlist
 
 	}
@@ -82,9 +80,7 @@ func insertOrRemovePrimarykey(pkRequest PrimaryKeyRequest, spannerTable ddl.Crea
 }
 
 // addPrimaryKey insert primary key into list of IndexKey.
-func addPrimaryKey(add []string, pkRequest PrimaryKeyRequest, spannerTable ddl.CreateTable) []ddl.IndexKey {
-
-	sessionState := session.GetSessionState()
+func addPrimaryKey(sessionState *session.SessionState, add []string, pkRequest PrimaryKeyRequest, spannerTable ddl.CreateTable) []ddl.IndexKey {
 	list := []ddl.IndexKey{}
 
 	for _, val := range add {
@@ -136,9 +132,7 @@ func addPrimaryKey(add []string, pkRequest PrimaryKeyRequest, spannerTable ddl.C
 }
 
 // removePrimaryKey removes primary key from list of IndexKey.
-func removePrimaryKey(remove []string, spannerTable ddl.CreateTable) []ddl.IndexKey {
-
-	sessionState := session.GetSessionState()
+func removePrimaryKey(sessionState *session.SessionState, remove []string, spannerTable ddl.CreateTable) []ddl.IndexKey {
 	list := spannerTable.PrimaryKeys
 
 	for _, val := range remove {
@@ -21,6 +21,7 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/index"
@@ -43,6 +44,15 @@ type PrimaryKeyResponse struct {
 	Synth   bool           `json:"Synth"`
 }
 
+// SyntheticPrimaryKeyRequest represents the synthetic primary key API payload.
+// Columns are only used with the composite strategy, where the synthetic
+// column is replaced by a primary key made of existing columns.
+type SyntheticPrimaryKeyRequest struct {
+	TableId  string         `json:"TableId"`
+	Strategy string         `json:"Strategy"`
+	Columns  []ddl.IndexKey `json:"Columns"`
+}
+
 // primaryKey updates Primary keys in Spanner Table.
 func PrimaryKey(w http.ResponseWriter, r *http.Request) {
 
@@ -67,9 +77,8 @@ func PrimaryKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
+	sessionState := session.RequestSessionState(r)
+	defer sessionState.LockConv()()
 	spannerTable, found := getSpannerTable(sessionState, pkRequest)
 
 	if !found {
@@ -100,8 +109,8 @@ func PrimaryKey(w http.ResponseWriter, r *http.Request) {
 
 	}
 
-	UpdatePrimaryKey(pkRequest)
-	session.UpdateSessionFile()
+	UpdatePrimaryKey(sessionState, pkRequest)
+	session.UpdateSessionFile(sessionState)
 
 	convm := session.ConvWithMetadata{
 		SessionMetadata: sessionState.SessionMetadata,
@@ -114,9 +123,7 @@ func PrimaryKey(w http.ResponseWriter, r *http.Request) {
 	log.Println("request completed", "traceid", id.String(), "method", r.Method, "path", r.URL.Path, "remoteaddr", r.RemoteAddr)
 }
 
-func UpdatePrimaryKey(pkRequest PrimaryKeyRequest) {
-
-	sessionState := session.GetSessionState()
+func UpdatePrimaryKey(sessionState *session.SessionState, pkRequest PrimaryKeyRequest) {
 	spannerTable, _ := getSpannerTable(sessionState, pkRequest)
 	tableId := spannerTable.Id
 	synthColId := ""
@@ -124,9 +131,10 @@ func UpdatePrimaryKey(pkRequest PrimaryKeyRequest) {
 		synthColId = synthCol.ColId
 	}
 
-	spannerTable, isSynthPkRemoved := updatePrimaryKey(pkRequest, spannerTable, synthColId)
+	spannerTable, isSynthPkRemoved := updatePrimaryKey(sessionState, pkRequest, spannerTable, synthColId)
 
 	if isSynthPkRemoved {
+		sessionState.Conv.DropSyntheticPKeySequence(tableId)
 		synthPks := sessionState.Conv.SyntheticPKeys
 		delete(synthPks, tableId)
 		sessionState.Conv.SyntheticPKeys = synthPks
@@ -145,9 +153,83 @@ func UpdatePrimaryKey(pkRequest PrimaryKeyRequest) {
 		if pkRequest.TableId == table.Id {
 			sessionState.Conv.SpSchema[table.Id] = spannerTable
 			for _, ind := range spannerTable.Indexes {
-				index.RemoveIndexIssues(spannerTable.Id, ind)
+				index.RemoveIndexIssues(sessionState.Conv, spannerTable.Id, ind)
 			}
 		}
 	}
 	common.ComputeNonKeyColumnSize(sessionState.Conv, pkRequest.TableId)
 }
+
+// SyntheticPrimaryKey updates the strategy used to generate the synthetic
+// primary key of a Spanner table.
+func SyntheticPrimaryKey(w http.ResponseWriter, r *http.Request) {
+
+	id := uuid.New()
+
+	log.Println("request started", "traceid", id.String(), "method", r.Method, "path", r.URL.Path)
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Println("request's body Read Error")
+		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	synthPkRequest := SyntheticPrimaryKeyRequest{}
+	err = json.Unmarshal(reqBody, &synthPkRequest)
+	if err != nil {
+		log.Println("request's Body parse error")
+		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
+		return
+	}
+
+	sessionState := session.RequestSessionState(r)
+	defer sessionState.LockConv()()
+
+	if _, found := sessionState.Conv.SyntheticPKeys[synthPkRequest.TableId]; !found {
+		log.Println("table doesn't have a synthetic primary key")
+		http.Error(w, "table doesn't have a synthetic primary key", http.StatusBadRequest)
+		return
+	}
+
+	if synthPkRequest.Strategy == constants.SYNTH_PK_COMPOSITE {
+		pkRequest := PrimaryKeyRequest{TableId: synthPkRequest.TableId, Columns: synthPkRequest.Columns}
+		spannerTable, found := getSpannerTable(sessionState, pkRequest)
+		if !found {
+			log.Println("TableId not found")
+			http.Error(w, "tableId not found", http.StatusNotFound)
+			return
+		}
+		if len(pkRequest.Columns) == 0 {
+			http.Error(w, "composite synthetic primary key requires at least one column", http.StatusBadRequest)
+			return
+		}
+		if !isValidColumnIds(pkRequest, spannerTable) {
+			http.Error(w, "colummId not found error", http.StatusBadRequest)
+			return
+		}
+		if isValidColumnOrder(pkRequest) {
+			http.Error(w, "two primary key column can  not have same order", http.StatusBadRequest)
+			return
+		}
+		UpdatePrimaryKey(sessionState, pkRequest)
+	} else {
+		err = sessionState.Conv.SetSyntheticPKeyStrategy(synthPkRequest.TableId, synthPkRequest.Strategy)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		common.ComputeNonKeyColumnSize(sessionState.Conv, synthPkRequest.TableId)
+	}
+	session.UpdateSessionFile(sessionState)
+
+	convm := session.ConvWithMetadata{
+		SessionMetadata: sessionState.SessionMetadata,
+		Conv:            sessionState.Conv,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(convm)
+
+	log.Println("request completed", "traceid", id.String(), "method", r.Method, "path", r.URL.Path, "remoteaddr", r.RemoteAddr)
+}
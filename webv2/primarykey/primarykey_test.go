@@ -428,3 +428,90 @@ func TestPrimarykey(t *testing.T) {
 		}
 	}
 }
+
+func TestSyntheticPrimaryKey(t *testing.T) {
+	tc := []struct {
+		name       string
+		input      SyntheticPrimaryKeyRequest
+		statusCode int
+		strategy   string
+		colType    ddl.Type
+	}{
+		{
+			name:       "Table without synthetic primary key",
+			input:      SyntheticPrimaryKeyRequest{TableId: "t2", Strategy: "uuid"},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Synthetic primary key of a missing table",
+			input:      SyntheticPrimaryKeyRequest{TableId: "t3", Strategy: "composite", Columns: []ddl.IndexKey{{ColId: "c1", Order: 1}}},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Composite strategy without columns",
+			input:      SyntheticPrimaryKeyRequest{TableId: "t1", Strategy: "composite"},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Composite strategy with an unknown column",
+			input:      SyntheticPrimaryKeyRequest{TableId: "t1", Strategy: "composite", Columns: []ddl.IndexKey{{ColId: "c9", Order: 1}}},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Invalid strategy",
+			input:      SyntheticPrimaryKeyRequest{TableId: "t1", Strategy: "random"},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "UUID strategy",
+			input:      SyntheticPrimaryKeyRequest{TableId: "t1", Strategy: "uuid"},
+			statusCode: http.StatusOK,
+			strategy:   "uuid",
+			colType:    ddl.Type{Name: ddl.String, Len: 36},
+		},
+	}
+
+	for _, tt := range tc {
+		sessionState := session.GetSessionState()
+		sessionState.Conv = &internal.Conv{
+			SpSchema: map[string]ddl.CreateTable{
+				"t1": {
+					Name:   "events",
+					Id:     "t1",
+					ColIds: []string{"c1", "c2"},
+					ColDefs: map[string]ddl.ColumnDef{
+						"c1": {Name: "name", Id: "c1", T: ddl.Type{Name: ddl.String, Len: ddl.MaxLength}},
+						"c2": {Name: "synth_id", Id: "c2", T: ddl.Type{Name: ddl.String, Len: 50}},
+					},
+					PrimaryKeys: []ddl.IndexKey{{ColId: "c2", Order: 1}},
+				},
+				"t2": {Name: "logs", Id: "t2"},
+			},
+			SyntheticPKeys: map[string]internal.SyntheticPKey{
+				"t1": {ColId: "c2"},
+				"t3": {ColId: "c1"},
+			},
+			Audit: internal.Audit{
+				MigrationType: migration.MigrationData_MIGRATION_TYPE_UNSPECIFIED.Enum(),
+			},
+			SchemaIssues: make(map[string]internal.TableIssues),
+		}
+		inputBytes, err := json.Marshal(tt.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.NewRequest("POST", "/syntheticPrimaryKey", bytes.NewBuffer(inputBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(SyntheticPrimaryKey)
+		handler.ServeHTTP(rr, req)
+		assert.Equal(t, tt.statusCode, rr.Code, tt.name)
+		if tt.statusCode == http.StatusOK {
+			assert.Equal(t, tt.strategy, sessionState.Conv.SyntheticPKeys["t1"].Strategy, tt.name)
+			assert.Equal(t, tt.colType, sessionState.Conv.SpSchema["t1"].ColDefs["c2"].T, tt.name)
+		}
+	}
+}
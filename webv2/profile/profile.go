@@ -42,9 +42,8 @@ func ListConnectionProfiles(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("datastream client can not be created: %v", err), http.StatusBadRequest)
 	}
 	defer dsClient.Close()
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
+	sessionState := session.RequestSessionState(r)
+	defer sessionState.LockConv()()
 	source := r.FormValue("source") == "true"
 	if !source {
 		sessionState.Conv.Audit.MigrationRequestId, _ = utils.GenerateName("smt-job")
@@ -91,7 +90,7 @@ func GetStaticIps(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("datastream client can not be created: %v", err), http.StatusBadRequest)
 	}
 	defer dsClient.Close()
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 	req := &datastreampb.FetchStaticIpsRequest{
 		Name: fmt.Sprintf("projects/%s/locations/%s", sessionState.GCPProjectID, sessionState.Region),
 	}
@@ -133,9 +132,8 @@ func CreateConnectionProfile(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("datastream client can not be created: %v", err), http.StatusBadRequest)
 	}
 	defer dsClient.Close()
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
+	sessionState := session.RequestSessionState(r)
+	defer sessionState.LockConv()()
 	databaseType, err := helpers.GetSourceDatabaseFromDriver(sessionState.Driver)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error while getting source database: %v", err), http.StatusBadRequest)
@@ -216,7 +214,7 @@ func CreateConnectionProfile(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	setConnectionProfileFromSessionState(details.IsSource, *sessionState, req, databaseType)
+	setConnectionProfileFromSessionState(details.IsSource, sessionState, req, databaseType)
 
 	op, err := dsClient.CreateConnectionProfile(ctx, req)
 	if err != nil {
@@ -245,7 +243,7 @@ func (profileHandler *ProfileAPIHandler) VerifyJsonConfiguration(w http.Response
 	}
 
 	ctx := context.Background()
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 	sourceProfileConfig := srcConfig.MigrationProfile
 	sourceProfile := profiles.SourceProfile{Ty: profiles.SourceProfileTypeConfig, Config: sourceProfileConfig}
 
@@ -256,7 +254,7 @@ func (profileHandler *ProfileAPIHandler) VerifyJsonConfiguration(w http.Response
 	}
 }
 
-func setConnectionProfileFromSessionState(isSource bool, sessionState session.SessionState, req *datastreampb.CreateConnectionProfileRequest, databaseType string) {
+func setConnectionProfileFromSessionState(isSource bool, sessionState *session.SessionState, req *datastreampb.CreateConnectionProfileRequest, databaseType string) {
 	if isSource {
 		port, _ := strconv.ParseInt((sessionState.SourceDBConnDetails.Port), 10, 32)
 		if databaseType == constants.MYSQL {
@@ -304,9 +302,8 @@ func setConnectionProfileFromSessionState(isSource bool, sessionState session.Se
 // The underlying backend library exposes more hooks which can are not yet implemented on the UI, and are only available via the CLI.
 func CleanUpStreamingJobs(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
+	sessionState := session.RequestSessionState(r)
+	defer sessionState.LockConv()()
 	jobCleanupOptions := streaming.JobCleanupOptions{
 		Datastream: true,
 		Dataflow:   true,
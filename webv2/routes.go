@@ -28,6 +28,7 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/expressions_api"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal/reports"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/api"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/auth"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/config"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/primarykey"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/profile"
@@ -46,7 +47,7 @@ func getRoutes() *mux.Router {
 		ReportGenerator: &reports.ReportImpl{},
 	}
 	ctx := context.Background()
-	ddlVerifier, _ := expressions_api.NewDDLVerifierImpl(ctx, "", "")
+	ddlVerifier, ddlVerifierErr := expressions_api.NewDDLVerifierImpl(ctx, "", "")
 	tableHandler := api.TableAPIHandler{
 		DDLVerifier: ddlVerifier,
 	}
@@ -59,54 +60,88 @@ func getRoutes() *mux.Router {
 		ValidateResources: validateResourceImpl,
 	}
 
-	expressionVerificationAccessor, _ := expressions_api.NewExpressionVerificationAccessorImpl(ctx, session.GetSessionState().SpannerProjectId, session.GetSessionState().SpannerInstanceID)
+	expressionVerificationAccessor, expressionVerificationErr := expressions_api.NewExpressionVerificationAccessorImpl(ctx, session.GetSessionState().SpannerProjectId, session.GetSessionState().SpannerInstanceID)
+	// Reported by the readiness probe.
+	expressionVerifierErr = ddlVerifierErr
+	if expressionVerifierErr == nil {
+		expressionVerifierErr = expressionVerificationErr
+	}
 
 	expressionVerificationHandler := api.ExpressionsVerificationHandler{
 		ExpressionVerificationAccessor: expressionVerificationAccessor,
 	}
 
 	router.HandleFunc("/connect", databaseConnection).Methods("POST")
-	router.HandleFunc("/convert/infoschema", expressionVerificationHandler.ConvertSchemaSQL).Methods("GET")
+	router.HandleFunc("/convert/infoschema", auth.RequireEditor(expressionVerificationHandler.ConvertSchemaSQL)).Methods("GET")
 	router.HandleFunc("/convert/dump", expressionVerificationHandler.ConvertSchemaDump).Methods("POST")
 	router.HandleFunc("/convert/session", loadSession).Methods("POST")
 	router.HandleFunc("/ddl", api.GetDDL).Methods("GET")
+	router.HandleFunc("/tables", api.ListTables).Methods("GET")
+	router.HandleFunc("/search", api.Search).Methods("GET")
 	router.HandleFunc("/seqDdl", api.GetSequenceDDL).Methods("GET")
 	router.HandleFunc("/conversion", api.GetConversionRate).Methods("GET")
 	router.HandleFunc("/typemap", api.GetTypeMap).Methods("GET")
 	router.HandleFunc("/report", reportAPIHandler.GetReportFile).Methods("GET")
 	router.HandleFunc("/downloadStructuredReport", reportAPIHandler.GetDStructuredReport).Methods("GET")
+	router.HandleFunc("/downloadJSONReport", api.GetJSONReport).Methods("GET")
+	router.HandleFunc("/schemaSummary", api.GetSchemaSummary).Methods("GET")
+	router.HandleFunc("/audit", api.GetAuditLog).Methods("GET")
 	router.HandleFunc("/downloadTextReport", reportAPIHandler.GetDTextReport).Methods("GET")
 	router.HandleFunc("/downloadDDL", api.GetDSpannerDDL).Methods("GET")
+	router.HandleFunc("/downloadArtifacts", reportAPIHandler.GetArtifactsZip).Methods("GET")
 	router.HandleFunc("/downloadDDLWoComments", api.GetSpannerDDLWoComments).Methods("GET")
 	router.HandleFunc("/schema", getSchemaFile).Methods("GET")
-	router.HandleFunc("/applyrule", api.ApplyRule).Methods("POST")
-	router.HandleFunc("/dropRule", api.DropRule).Methods("POST")
-	router.HandleFunc("/typemap/table", table.UpdateTableSchema).Methods("POST")
+	router.HandleFunc("/applyrule", api.AuditSchemaEdit(api.ApplyRule)).Methods("POST")
+	router.HandleFunc("/dropRule", api.AuditSchemaEdit(api.DropRule)).Methods("POST")
+	router.HandleFunc("/typemap/table", api.AuditSchemaEdit(table.UpdateTableSchema)).Methods("POST")
 	router.HandleFunc("/typemap/reviewTableSchema", table.ReviewTableSchema).Methods("POST")
+	router.HandleFunc("/typemap/reviewTableSchemaDiff", table.ReviewTableSchemaDiff).Methods("POST")
 	router.HandleFunc("/typemap/GetStandardTypeToPGSQLTypemap", api.GetStandardTypeToPGSQLTypemap).Methods("GET")
 	router.HandleFunc("/typemap/GetPGSQLToStandardTypeTypemap", api.GetPGSQLToStandardTypeTypemap).Methods("GET")
 	router.HandleFunc("/spannerDefaultTypeMap", api.SpannerDefaultTypeMap).Methods("GET")
 	router.HandleFunc("/autoGenMap", api.GetAutoGenMap).Methods("GET")
 	router.HandleFunc("/getSequenceKind", api.GetSequenceKind).Methods("GET")
-	router.HandleFunc("/setparent", api.SetParentTable).Methods("GET")
-	router.HandleFunc("/removeParent", api.RemoveParentTable).Methods("POST")
+	router.HandleFunc("/setparent", api.AuditSchemaEdit(auth.RequireEditor(api.SetParentTable))).Methods("GET")
+	router.HandleFunc("/removeParent", api.AuditSchemaEdit(api.RemoveParentTable)).Methods("POST")
+	router.HandleFunc("/interleaveTree", api.GetInterleaveTree).Methods("GET")
+	router.HandleFunc("/interleaveCandidates", api.GetInterleaveCandidates).Methods("GET")
+	router.HandleFunc("/convertInterleaveCandidate", api.AuditSchemaEdit(auth.RequireEditor(api.ConvertInterleaveCandidate))).Methods("POST")
+	router.HandleFunc("/setInterleaveOnDelete", api.AuditSchemaEdit(auth.RequireEditor(api.SetInterleaveOnDelete))).Methods("POST")
 	router.HandleFunc("/verifyCheckConstraintExpression", expressionVerificationHandler.VerifyCheckConstraintExpression).Methods("GET")
+	router.HandleFunc("/verifyExpression", expressionVerificationHandler.VerifyExpression).Methods("POST")
+	router.HandleFunc("/verifyView", expressionVerificationHandler.VerifyView).Methods("POST")
 
 	// TODO:(searce) take constraint names themselves which are guaranteed to be unique for Spanner.
-	router.HandleFunc("/drop/secondaryindex", api.DropSecondaryIndex).Methods("POST")
-	router.HandleFunc("/restore/secondaryIndex", api.RestoreSecondaryIndex).Methods("POST")
-
-	router.HandleFunc("/restore/table", tableHandler.RestoreTable).Methods("POST")
-	router.HandleFunc("/restore/tables", tableHandler.RestoreTables).Methods("POST")
-	router.HandleFunc("/drop/table", api.DropTable).Methods("POST")
-	router.HandleFunc("/drop/tables", api.DropTables).Methods("POST")
-
-	router.HandleFunc("/drop/sequence", api.DropSequence).Methods("POST")
-	router.HandleFunc("/UpdateSequence", api.UpdateSequence).Methods("POST")
-
-	router.HandleFunc("/update/fks", api.UpdateForeignKeys).Methods("POST")
-	router.HandleFunc("/update/cc", api.UpdateCheckConstraint).Methods("POST")
-	router.HandleFunc("/update/indexes", api.UpdateIndexes).Methods("POST")
+	router.HandleFunc("/drop/secondaryindex", api.AuditSchemaEdit(api.DropSecondaryIndex)).Methods("POST")
+	router.HandleFunc("/restore/secondaryIndex", api.AuditSchemaEdit(api.RestoreSecondaryIndex)).Methods("POST")
+
+	router.HandleFunc("/restore/table", api.AuditSchemaEdit(tableHandler.RestoreTable)).Methods("POST")
+	router.HandleFunc("/restore/tables", api.AuditSchemaEdit(tableHandler.RestoreTables)).Methods("POST")
+	router.HandleFunc("/drop/table", api.AuditSchemaEdit(api.DropTable)).Methods("POST")
+	router.HandleFunc("/drop/tables", api.AuditSchemaEdit(api.DropTables)).Methods("POST")
+	router.HandleFunc("/drop/bulk", api.AuditSchemaEdit(api.BulkDrop)).Methods("POST")
+	router.HandleFunc("/add/jsonPathIndexes", api.AuditSchemaEdit(api.AddJSONPathIndexes)).Methods("POST")
+
+	router.HandleFunc("/drop/sequence", api.AuditSchemaEdit(api.DropSequence)).Methods("POST")
+	router.HandleFunc("/UpdateSequence", api.AuditSchemaEdit(api.UpdateSequence)).Methods("POST")
+
+	router.HandleFunc("/views", api.GetViews).Methods("GET")
+	router.HandleFunc("/AddView", api.AuditSchemaEdit(api.AddView)).Methods("POST")
+	router.HandleFunc("/UpdateView", api.AuditSchemaEdit(api.UpdateView)).Methods("POST")
+	router.HandleFunc("/drop/view", api.AuditSchemaEdit(api.DropView)).Methods("POST")
+
+	router.HandleFunc("/update/fks", api.AuditSchemaEdit(api.UpdateForeignKeys)).Methods("POST")
+	router.HandleFunc("/update/cc", api.AuditSchemaEdit(api.UpdateCheckConstraint)).Methods("POST")
+	router.HandleFunc("/update/rowDeletionPolicy", api.AuditSchemaEdit(api.UpdateRowDeletionPolicy)).Methods("POST")
+	router.HandleFunc("/update/vectorIndex", api.AuditSchemaEdit(api.UpdateVectorIndex)).Methods("POST")
+	router.HandleFunc("/drop/vectorIndex", api.AuditSchemaEdit(api.DropVectorIndex)).Methods("POST")
+	router.HandleFunc("/update/commitTimestamp", api.AuditSchemaEdit(api.UpdateCommitTimestamp)).Methods("POST")
+	router.HandleFunc("/rename/table", api.AuditSchemaEdit(api.RenameTable)).Methods("POST")
+	router.HandleFunc("/nameConflicts", api.GetNameConflicts).Methods("GET")
+	router.HandleFunc("/resolveNameConflicts", api.AuditSchemaEdit(auth.RequireEditor(api.ResolveNameConflicts))).Methods("POST")
+	router.HandleFunc("/update/columnTransforms", api.AuditSchemaEdit(api.UpdateColumnTransforms)).Methods("POST")
+	router.HandleFunc("/update/nameTemplates", api.AuditSchemaEdit(api.UpdateNameTemplates)).Methods("POST")
+	router.HandleFunc("/update/indexes", api.AuditSchemaEdit(api.UpdateIndexes)).Methods("POST")
 
 	// Session Management
 	router.HandleFunc("/IsOffline", session.IsOfflineSession).Methods("GET")
@@ -116,10 +151,12 @@ func getRoutes() *mux.Router {
 	router.HandleFunc("/ResumeSession/{versionId}", session.ResumeSession).Methods("POST")
 
 	// primarykey
-	router.HandleFunc("/primaryKey", primarykey.PrimaryKey).Methods("POST")
+	router.HandleFunc("/primaryKey", api.AuditSchemaEdit(primarykey.PrimaryKey)).Methods("POST")
+	router.HandleFunc("/syntheticPrimaryKey", api.AuditSchemaEdit(primarykey.SyntheticPrimaryKey)).Methods("POST")
 
-	router.HandleFunc("/AddColumn", table.AddNewColumn).Methods("POST")
-	router.HandleFunc("/AddSequence", api.AddNewSequence).Methods("POST")
+	router.HandleFunc("/AddColumn", api.AuditSchemaEdit(table.AddNewColumn)).Methods("POST")
+	router.HandleFunc("/AddTable", api.AuditSchemaEdit(table.AddNewTable)).Methods("POST")
+	router.HandleFunc("/AddSequence", api.AuditSchemaEdit(api.AddNewSequence)).Methods("POST")
 
 	// Summary
 	router.HandleFunc("/summary", summary.GetSummary).Methods("GET")
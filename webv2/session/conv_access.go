@@ -0,0 +1,132 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+)
+
+// The conversion of a session is read and updated by concurrent web requests
+// and by background jobs, e.g. migrations and expression verification. The
+// methods below guard both the conversion, with its ConvLock, and the Conv
+// pointer itself, which is replaced when a session is loaded or reset. Each
+// update or replacement of the conversion increments its version, so that
+// the results of a job working on a snapshot are only applied to the
+// conversion the snapshot was taken from.
+
+// GetConv returns the current conversion of the session.
+func (s *SessionState) GetConv() *internal.Conv {
+	s.convMu.RLock()
+	defer s.convMu.RUnlock()
+	return s.Conv
+}
+
+// SetConv replaces the conversion of the session. Requests holding the lock
+// of the previous conversion keep working on it until they release it.
+func (s *SessionState) SetConv(conv *internal.Conv) {
+	s.convMu.Lock()
+	defer s.convMu.Unlock()
+	s.Conv = conv
+	s.convVersion.Add(1)
+}
+
+// ReplaceConv takes the write lock of conv, replaces the conversion of the
+// session with it and returns the function releasing the lock, so that conv
+// is never visible to other requests before it is set up:
+//
+//	defer sessionState.ReplaceConv(conv)()
+func (s *SessionState) ReplaceConv(conv *internal.Conv) func() {
+	conv.ConvLock.Lock()
+	s.SetConv(conv)
+	return func() {
+		s.convVersion.Add(1)
+		conv.ConvLock.Unlock()
+	}
+}
+
+// ConvVersion returns the version of the conversion of the session.
+func (s *SessionState) ConvVersion() uint64 {
+	return s.convVersion.Load()
+}
+
+// LockConv takes the write lock of the current conversion and returns the
+// function releasing it, meant to be deferred:
+//
+//	defer sessionState.LockConv()()
+func (s *SessionState) LockConv() func() {
+	conv := s.GetConv()
+	conv.ConvLock.Lock()
+	return func() {
+		s.convVersion.Add(1)
+		conv.ConvLock.Unlock()
+	}
+}
+
+// RLockConv takes the read lock of the current conversion and returns the
+// function releasing it.
+func (s *SessionState) RLockConv() func() {
+	conv := s.GetConv()
+	conv.ConvLock.RLock()
+	return conv.ConvLock.RUnlock
+}
+
+// ReadConv calls fn with the current conversion, holding its read lock.
+func (s *SessionState) ReadConv(fn func(conv *internal.Conv) error) error {
+	conv := s.GetConv()
+	if conv == nil {
+		return fmt.Errorf("schema is not converted")
+	}
+	conv.ConvLock.RLock()
+	defer conv.ConvLock.RUnlock()
+	return fn(conv)
+}
+
+// UpdateConv calls fn with the current conversion, holding its write lock.
+func (s *SessionState) UpdateConv(fn func(conv *internal.Conv) error) error {
+	conv := s.GetConv()
+	if conv == nil {
+		return fmt.Errorf("schema is not converted")
+	}
+	conv.ConvLock.Lock()
+	defer conv.ConvLock.Unlock()
+	defer s.convVersion.Add(1)
+	return fn(conv)
+}
+
+// SnapshotConv returns a deep copy of the schema of the current conversion,
+// i.e. the content of its session file, taken under its read lock. Long
+// running jobs work on a snapshot so that the session can be reviewed and
+// edited meanwhile. The statistics and audit of the conversion aren't copied.
+// Use ConvVersion, read before the snapshot, to check whether the conversion
+// changed since.
+func (s *SessionState) SnapshotConv() (*internal.Conv, error) {
+	var data []byte
+	err := s.ReadConv(func(conv *internal.Conv) error {
+		var err error
+		data, err = json.Marshal(conv)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	snapshot := internal.MakeConv()
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("can't copy the conversion: %v", err)
+	}
+	return snapshot, nil
+}
@@ -42,13 +42,13 @@ type SessionParams struct {
 
 func IsOfflineSession(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(GetSessionState().IsOffline)
+	json.NewEncoder(w).Encode(RequestSessionState(r).IsOffline)
 }
 
 func GetSessions(w http.ResponseWriter, r *http.Request) {
 	var sessions []SchemaConversionSession
 	var err error
-	if GetSessionState().IsOffline {
+	if RequestSessionState(r).IsOffline {
 		sessions, err = getLocalSessions()
 	} else {
 		sessions, err = getRemoteSessions()
@@ -71,7 +71,7 @@ func GetConv(w http.ResponseWriter, r *http.Request) {
 
 	var convm ConvWithMetadata
 	var err error
-	if GetSessionState().IsOffline {
+	if RequestSessionState(r).IsOffline {
 		convm, err = getLocalConv(vid)
 	} else {
 		convm, err = getRemoteConv(vid)
@@ -94,7 +94,7 @@ func ResumeSession(w http.ResponseWriter, r *http.Request) {
 
 	var convm ConvWithMetadata
 	var err error
-	if GetSessionState().IsOffline {
+	if RequestSessionState(r).IsOffline {
 		convm, err = getLocalConv(vid)
 	} else {
 		convm, err = getRemoteConv(vid)
@@ -104,8 +104,8 @@ func ResumeSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionState := GetSessionState()
-	sessionState.Conv = convm.Conv
+	sessionState := RequestSessionState(r)
+	defer sessionState.ReplaceConv(convm.Conv)()
 	sessionState.Driver = convm.DatabaseType
 	sessionState.DbName = convm.DatabaseName
 	sessionState.SourceDBConnDetails = SourceDBConnDetails{
@@ -140,7 +140,7 @@ func SaveRemoteSession(w http.ResponseWriter, r *http.Request) {
 	}
 	defer spannerClient.Close()
 
-	sessionState := GetSessionState()
+	sessionState := RequestSessionState(r)
 	ssvc := NewSessionService(ctx, NewRemoteSessionStore(spannerClient))
 	conv, err := json.Marshal(sessionState.Conv)
 	if err != nil {
@@ -176,14 +176,14 @@ func SaveRemoteSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionMetaData := GetSessionState().SessionMetadata
+	sessionMetaData := RequestSessionState(r).SessionMetadata
 
 	sessionMetaData.DatabaseName = sm.DatabaseName
 	sessionMetaData.DatabaseType = sm.DatabaseType
 	sessionMetaData.SessionName = sm.SessionName
 	sessionMetaData.Dialect = sm.Dialect
 
-	GetSessionState().SessionMetadata = sessionMetaData
+	RequestSessionState(r).SessionMetadata = sessionMetaData
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode("Save successful, VersionId : " + scs.VersionId)
@@ -1,16 +1,47 @@
 package session
 
 import (
+	"context"
+	"net/http"
 	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/google/uuid"
 )
 
+// SessionCookieName is the name of the cookie which identifies a client's
+// session state when the web server runs with per-client sessions.
+const SessionCookieName = "smt_session_id"
+
+// SessionIdleTimeout is how long the state of a client's session is kept
+// after its last request.
+const SessionIdleTimeout = 24 * time.Hour
+
 var once sync.Once
 
-// sessionState maintains the current state of the session, and is used to
-// track state from one request to the next. Session state is global:
-// all requests see the same session state.
+// sessionState is the default session state: it is used by requests which
+// are not wrapped with WithSessionState, and by server-wide checks.
 var sessionState *SessionState
 
+// sessionEntry is the state of a client's session. mu serializes the
+// requests of the client, and lastUsed is the time of its last request.
+type sessionEntry struct {
+	state    *SessionState
+	mu       sync.Mutex
+	lastUsed time.Time
+}
+
+var (
+	// sessionStates maps a session id issued by the server to the state of
+	// that client's session.
+	sessionStates = make(map[string]*sessionEntry)
+	// sessionStatesMutex guards sessionStates.
+	sessionStatesMutex sync.Mutex
+)
+
+type sessionStateKey struct{}
+
 func GetSessionState() *SessionState {
 	if sessionState == nil {
 		once.Do(
@@ -20,3 +51,78 @@ func GetSessionState() *SessionState {
 	}
 	return sessionState
 }
+
+// RequestSessionState returns the session state of the client making the
+// request r, or the default session state if r is not wrapped with
+// WithSessionState.
+func RequestSessionState(r *http.Request) *SessionState {
+	if state, ok := r.Context().Value(sessionStateKey{}).(*SessionState); ok {
+		return state
+	}
+	return GetSessionState()
+}
+
+// WithSessionState isolates session state per client. Each client is
+// identified by the SessionCookieName cookie, which the server issues on the
+// client's first request. For the duration of a request, RequestSessionState
+// returns the state of the requesting client, so that concurrent users of a
+// shared deployment work on independent conversions. Requests of the same
+// client are serialized, and sessions idle for SessionIdleTimeout are evicted.
+func WithSessionState(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entry := getOrCreateSessionEntry(w, r, GetSessionState())
+		entry.mu.Lock()
+		defer entry.mu.Unlock()
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sessionStateKey{}, entry.state)))
+	})
+}
+
+// getOrCreateSessionEntry returns the session of the client making the
+// request r. Clients without a session, or with a session id the server did
+// not issue or has evicted, get a new session and its id in the response.
+// New sessions start with an empty conversion and inherit the Spanner
+// connection settings of the server's default session.
+func getOrCreateSessionEntry(w http.ResponseWriter, r *http.Request, defaultState *SessionState) *sessionEntry {
+	sessionStatesMutex.Lock()
+	defer sessionStatesMutex.Unlock()
+	now := time.Now()
+	if cookie, err := r.Cookie(SessionCookieName); err == nil {
+		if entry, ok := sessionStates[cookie.Value]; ok {
+			entry.lastUsed = now
+			return entry
+		}
+	}
+	evictIdleSessions(now)
+	id := uuid.New().String()
+	entry := &sessionEntry{
+		state: &SessionState{
+			Conv:              internal.MakeConv(),
+			IsOffline:         defaultState.IsOffline,
+			GCPProjectID:      defaultState.GCPProjectID,
+			SpannerProjectId:  defaultState.SpannerProjectId,
+			SpannerInstanceID: defaultState.SpannerInstanceID,
+			Region:            defaultState.Region,
+			Counter:           Counter{ObjectId: "0"},
+		},
+		lastUsed: now,
+	}
+	sessionStates[id] = entry
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    id,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return entry
+}
+
+// evictIdleSessions removes the sessions idle for SessionIdleTimeout. It must
+// be called with sessionStatesMutex held.
+func evictIdleSessions(now time.Time) {
+	for id, entry := range sessionStates {
+		if now.Sub(entry.lastUsed) > SessionIdleTimeout {
+			delete(sessionStates, id)
+		}
+	}
+}
@@ -9,11 +9,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -394,3 +396,109 @@ func TestSessionSave(t *testing.T) {
 	}
 
 }
+
+func TestWithSessionState(t *testing.T) {
+	defaultState := session.GetSessionState()
+	handler := session.WithSessionState(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionState := session.RequestSessionState(r)
+		if r.Method == http.MethodPost {
+			sessionState.DbName = r.URL.Query().Get("db")
+		}
+		w.Write([]byte(sessionState.DbName))
+	}))
+	serve := func(method, target string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve(http.MethodPost, "/?db=first", nil)
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, session.SessionCookieName, cookies[0].Name)
+	first := cookies[0]
+
+	rr = serve(http.MethodPost, "/?db=second", nil)
+	cookies = rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	second := cookies[0]
+	assert.NotEqual(t, first.Value, second.Value)
+
+	// Each client sees only its own state, and the existing cookie is reused.
+	rr = serve(http.MethodGet, "/", first)
+	assert.Equal(t, "first", rr.Body.String())
+	assert.Empty(t, rr.Result().Cookies())
+	rr = serve(http.MethodGet, "/", second)
+	assert.Equal(t, "second", rr.Body.String())
+
+	// Session ids the server did not issue are replaced.
+	rr = serve(http.MethodGet, "/", &http.Cookie{Name: session.SessionCookieName, Value: "forged"})
+	assert.Equal(t, "", rr.Body.String())
+	cookies = rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.NotEqual(t, "forged", cookies[0].Value)
+
+	// The default session state is left untouched.
+	assert.Same(t, defaultState, session.GetSessionState())
+	assert.Equal(t, "", defaultState.DbName)
+}
+
+func TestConvAccess(t *testing.T) {
+	sessionState := &session.SessionState{}
+	err := sessionState.ReadConv(func(conv *internal.Conv) error { return nil })
+	assert.Error(t, err)
+
+	sessionState.SetConv(internal.MakeConv())
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			sessionState.UpdateConv(func(conv *internal.Conv) error {
+				conv.UsedNames[fmt.Sprintf("t%d", i)] = true
+				return nil
+			})
+		}(i)
+		go func() {
+			defer wg.Done()
+			sessionState.ReadConv(func(conv *internal.Conv) error {
+				_ = len(conv.UsedNames)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, sessionState.GetConv().UsedNames, 10)
+
+	// Snapshots don't share the schema of the session.
+	sessionState.UpdateConv(func(conv *internal.Conv) error {
+		conv.SpSchema["t1"] = ddl.CreateTable{Id: "t1", Name: "table1"}
+		return nil
+	})
+	snapshot, err := sessionState.SnapshotConv()
+	require.NoError(t, err)
+	assert.Equal(t, "table1", snapshot.SpSchema["t1"].Name)
+	snapshot.SpSchema["t2"] = ddl.CreateTable{Id: "t2", Name: "table2"}
+	assert.NotContains(t, sessionState.GetConv().SpSchema, "t2")
+
+	// Reads keep the version of the conversion, updates and replacements don't.
+	version := sessionState.ConvVersion()
+	sessionState.ReadConv(func(conv *internal.Conv) error { return nil })
+	assert.Equal(t, version, sessionState.ConvVersion())
+	sessionState.UpdateConv(func(conv *internal.Conv) error {
+		assert.Equal(t, version, sessionState.ConvVersion())
+		return nil
+	})
+	assert.NotEqual(t, version, sessionState.ConvVersion())
+	version = sessionState.ConvVersion()
+	conv := internal.MakeConv()
+	unlock := sessionState.ReplaceConv(conv)
+	assert.Same(t, conv, sessionState.GetConv())
+	assert.False(t, conv.ConvLock.TryLock())
+	unlock()
+	assert.NotEqual(t, version, sessionState.ConvVersion())
+}
@@ -2,6 +2,8 @@ package session
 
 import (
 	"database/sql"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	cc "github.com/GoogleCloudPlatform/spanner-migration-tool/accessors/clients/cassandra"
@@ -68,6 +70,8 @@ type SessionState struct {
 	SessionMetadata      SessionMetadata
 	Error                error
 	Counter
+	convMu      sync.RWMutex  // Guards the Conv pointer, see SetConv.
+	convVersion atomic.Uint64 // Incremented when the conversion is updated or replaced, see ConvVersion.
 }
 
 // Counter used to generate id for table, column, Foreignkey and indexes.
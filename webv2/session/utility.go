@@ -27,11 +27,9 @@ import (
 
 // UpdateSessionFile updates the content of session file with
 // latest sessionState.Conv while also dumping schemas and report.
-func UpdateSessionFile() error {
-	sessionState := GetSessionState()
-
+func UpdateSessionFile(sessionState *SessionState) error {
 	ioHelper := &utils.IOStreams{In: os.Stdin, Out: os.Stdout}
-	_, err := conversion.WriteConvGeneratedFiles(sessionState.Conv, sessionState.DbName, sessionState.Driver, ioHelper.BytesRead, ioHelper.Out)
+	_, err := conversion.WriteConvGeneratedFiles(sessionState.GetConv(), sessionState.DbName, sessionState.Driver, ioHelper.BytesRead, ioHelper.Out)
 	if err != nil {
 		return fmt.Errorf("Error encountered while updating session file %w", err)
 	}
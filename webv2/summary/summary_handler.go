@@ -3,10 +3,13 @@ package summary
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
 )
 
 // getSummary returns table wise summary of conversion.
 func GetSummary(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(getSummary())
+	json.NewEncoder(w).Encode(getSummary(sessionState))
 }
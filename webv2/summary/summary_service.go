@@ -6,10 +6,8 @@ import (
 )
 
 // getSummary returns table wise summary of conversion.
-func getSummary() map[string]ConversionSummary {
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
+func getSummary(sessionState *session.SessionState) map[string]ConversionSummary {
+	defer sessionState.LockConv()()
 	tableReports := reports.AnalyzeTables(sessionState.Conv, nil)
 	summary := make(map[string]ConversionSummary)
 	for _, t := range tableReports {
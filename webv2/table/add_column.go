@@ -29,11 +29,13 @@ import (
 )
 
 type columnDetails struct {
-	Name       string         `json:"Name"`
-	Datatype   string         `json:"Datatype"`
-	Length     int            `json:"Length"`
-	IsNullable bool           `json:"IsNullable"`
-	AutoGen    ddl.AutoGenCol `json:"AutoGen"`
+	Name            string              `json:"Name"`
+	Datatype        string              `json:"Datatype"`
+	Length          int                 `json:"Length"`
+	IsNullable      bool                `json:"IsNullable"`
+	AutoGen         ddl.AutoGenCol      `json:"AutoGen"`
+	DefaultValue    ddl.DefaultValue    `json:"DefaultValue"`
+	GeneratedColumn ddl.GeneratedColumn `json:"GeneratedColumn"`
 }
 
 // addColumn add given column into spannerTable.
@@ -76,9 +78,8 @@ func AddNewColumn(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
+	sessionState := session.RequestSessionState(r)
+	defer sessionState.LockConv()()
 	for _, c := range sessionState.Conv.SpSchema[tableId].ColDefs {
 		if strings.EqualFold(c.Name, details.Name) {
 			http.Error(w, fmt.Sprintf("Multiple columns with similar name cannot exist for column : %v", details.Name), http.StatusBadRequest)
@@ -106,7 +107,14 @@ func AddNewColumn(w http.ResponseWriter, r *http.Request) {
 		colDef.Opts["cassandra_type"] = GetCassandraType(details.Datatype)
 	}
 	ct.ColDefs[columnId] = colDef
+	if err := validateGeneratedCol(details.GeneratedColumn, details.DefaultValue, details.AutoGen, tableId, columnId, sessionState.Conv); err != nil {
+		delete(ct.ColDefs, columnId)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	sessionState.Conv.SpSchema[tableId] = ct
+	UpdateDefaultValue(details.DefaultValue, tableId, columnId, sessionState.Conv)
+	UpdateGeneratedCol(details.GeneratedColumn, tableId, columnId, sessionState.Conv)
 	convm := session.ConvWithMetadata{
 		SessionMetadata: sessionState.SessionMetadata,
 		Conv:            sessionState.Conv,
@@ -118,6 +118,31 @@ func TestAddNewColumn(t *testing.T) {
 			expectedStatusCode:   http.StatusBadRequest,
 			expectedBodyContains: "Multiple columns with similar name cannot exist",
 		},
+		{
+			name:    "Add generated column",
+			payload: `{"Name": "total", "Datatype": "INT64", "IsNullable": true, "GeneratedColumn": {"IsPresent": true, "Type": "STORED", "Value": {"Statement": "price * quantity"}}}`,
+			initialConv: &internal.Conv{
+				SpSchema: map[string]ddl.CreateTable{tableId: {Id: tableId, Name: "my_table", ColIds: []string{"c1"}, ColDefs: map[string]ddl.ColumnDef{"c1": {Id: "c1", Name: "price", T: ddl.Type{Name: ddl.Int64}}}}},
+			},
+			initialCounterState: "1",
+			expectedNewColId:    "c2",
+			expectedStatusCode:  http.StatusOK,
+			checkConv: func(t *testing.T, conv *internal.Conv, newColId string) {
+				gc := conv.SpSchema[tableId].ColDefs[newColId].GeneratedColumn
+				assert.True(t, gc.IsPresent)
+				assert.Equal(t, ddl.GeneratedColStored, gc.Type)
+				assert.Equal(t, "price * quantity", gc.Value.Statement)
+				assert.NotEmpty(t, gc.Value.ExpressionId)
+			},
+		},
+		{
+			name:                 "Error on generated column with a default value",
+			payload:              `{"Name": "total", "Datatype": "INT64", "DefaultValue": {"IsPresent": true, "Value": {"Statement": "0"}}, "GeneratedColumn": {"IsPresent": true, "Type": "STORED", "Value": {"Statement": "price * 2"}}}`,
+			initialConv:          &internal.Conv{SpSchema: map[string]ddl.CreateTable{tableId: {Id: tableId, Name: "my_table", ColIds: []string{"c1"}, ColDefs: map[string]ddl.ColumnDef{"c1": {Id: "c1", Name: "price"}}}}},
+			initialCounterState:  "1",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedBodyContains: "can't have a default value",
+		},
 		{
 			name:                 "Error on used identifier",
 			payload:              `{"Name": "another_table"}`,
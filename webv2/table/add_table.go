@@ -0,0 +1,208 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
+)
+
+type primaryKeyDetails struct {
+	Name string `json:"Name"`
+	Desc bool   `json:"Desc"`
+}
+
+// tableDetails is the request body of AddNewTable. The primary key is given
+// by column names, in key order. ParentTableId is optional, and interleaves
+// the new table in an existing table.
+type tableDetails struct {
+	Name          string              `json:"Name"`
+	Columns       []columnDetails     `json:"Columns"`
+	PrimaryKeys   []primaryKeyDetails `json:"PrimaryKeys"`
+	ParentTableId string              `json:"ParentTableId"`
+	OnDelete      string              `json:"OnDelete"`
+}
+
+// AddNewTable adds a Spanner table which has no source table, e.g. an outbox
+// or metadata table. The table is part of the generated DDL and the report,
+// and is skipped by data migrations.
+func AddNewTable(w http.ResponseWriter, r *http.Request) {
+	logger.Log.Info(fmt.Sprint("request started", "method", r.Method, "path", r.URL.Path))
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		logger.Log.Info(fmt.Sprint("request's body Read Error"))
+		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
+		return
+	}
+	details := tableDetails{}
+	err = json.Unmarshal(reqBody, &details)
+	if err != nil {
+		logger.Log.Info(fmt.Sprint("request's Body parse error"))
+		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
+		return
+	}
+
+	sessionState := session.RequestSessionState(r)
+	if sessionState.Conv == nil {
+		http.Error(w, "Schema is not converted. Please convert the database to Spanner first.", http.StatusNotFound)
+		return
+	}
+	defer sessionState.LockConv()()
+	ct, err := buildNewTable(sessionState.Conv, details)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	addNewTable(sessionState.Conv, ct)
+
+	convm := session.ConvWithMetadata{
+		SessionMetadata: sessionState.SessionMetadata,
+		Conv:            sessionState.Conv,
+	}
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(convm)
+}
+
+// buildNewTable validates details and returns the Spanner table they define.
+func buildNewTable(conv *internal.Conv, details tableDetails) (ddl.CreateTable, error) {
+	if _, changed := internal.FixName(details.Name); changed {
+		return ddl.CreateTable{}, fmt.Errorf("table name is not valid: %v", details.Name)
+	}
+	usedNames := internal.ComputeUsedNames(conv)
+	if _, found := usedNames[strings.ToLower(details.Name)]; found {
+		return ddl.CreateTable{}, fmt.Errorf("specified name: '%v' is an existing identifier, please use a different table name", details.Name)
+	}
+	if len(details.Columns) == 0 {
+		return ddl.CreateTable{}, fmt.Errorf("table %v must have at least one column", details.Name)
+	}
+	ct := ddl.CreateTable{
+		Name:    details.Name,
+		Id:      newTableId(conv),
+		ColDefs: make(map[string]ddl.ColumnDef),
+	}
+	colIdByName := make(map[string]string)
+	for _, c := range details.Columns {
+		if _, changed := internal.FixName(c.Name); changed {
+			return ddl.CreateTable{}, fmt.Errorf("column name is not valid: %v", c.Name)
+		}
+		if _, found := colIdByName[strings.ToLower(c.Name)]; found {
+			return ddl.CreateTable{}, fmt.Errorf("multiple columns with similar name cannot exist for column : %v", c.Name)
+		}
+		if !isSupportedSpannerType(c.Datatype) {
+			return ddl.CreateTable{}, fmt.Errorf("unsupported type %v for column %v", c.Datatype, c.Name)
+		}
+		ty := ddl.Type{Name: c.Datatype, Len: int64(c.Length)}
+		if (ty.Name == ddl.String || ty.Name == ddl.Bytes) && ty.Len <= 0 {
+			ty.Len = ddl.MaxLength
+		}
+		colId := internal.GenerateColumnId()
+		colIdByName[strings.ToLower(c.Name)] = colId
+		ct.ColIds = append(ct.ColIds, colId)
+		ct.ColDefs[colId] = ddl.ColumnDef{
+			Name:    c.Name,
+			Id:      colId,
+			T:       ty,
+			NotNull: !c.IsNullable,
+			AutoGen: c.AutoGen,
+		}
+	}
+	if len(details.PrimaryKeys) == 0 {
+		return ddl.CreateTable{}, fmt.Errorf("table %v must have a primary key", details.Name)
+	}
+	for i, pk := range details.PrimaryKeys {
+		colId, found := colIdByName[strings.ToLower(pk.Name)]
+		if !found {
+			return ddl.CreateTable{}, fmt.Errorf("primary key column %v is not a column of table %v", pk.Name, details.Name)
+		}
+		ct.PrimaryKeys = append(ct.PrimaryKeys, ddl.IndexKey{ColId: colId, Desc: pk.Desc, Order: i + 1})
+	}
+	if details.ParentTableId != "" {
+		if err := checkInterleavable(conv, ct, details.ParentTableId); err != nil {
+			return ddl.CreateTable{}, err
+		}
+		onDelete := strings.ToUpper(details.OnDelete)
+		if onDelete == "" {
+			onDelete = constants.FK_NO_ACTION
+		}
+		if onDelete != constants.FK_NO_ACTION && onDelete != constants.FK_CASCADE {
+			return ddl.CreateTable{}, fmt.Errorf("unsupported ON DELETE action for interleaving: %v", details.OnDelete)
+		}
+		ct.ParentTable = ddl.InterleavedParent{Id: details.ParentTableId, OnDelete: onDelete}
+	}
+	return ct, nil
+}
+
+// checkInterleavable checks that the primary key of parentTableId is a
+// prefix of the primary key of ct, as required for ct to be interleaved in
+// it.
+func checkInterleavable(conv *internal.Conv, ct ddl.CreateTable, parentTableId string) error {
+	parent, ok := conv.SpSchema[parentTableId]
+	if !ok {
+		return fmt.Errorf("parent table %v doesn't exist", parentTableId)
+	}
+	if len(parent.PrimaryKeys) > len(ct.PrimaryKeys) {
+		return fmt.Errorf("table %v can't be interleaved in %v: the primary key of %v must start with the primary key of %v", ct.Name, parent.Name, ct.Name, parent.Name)
+	}
+	for i, parentPk := range parent.PrimaryKeys {
+		parentCol := parent.ColDefs[parentPk.ColId]
+		col := ct.ColDefs[ct.PrimaryKeys[i].ColId]
+		if parentCol.Name != col.Name || parentCol.T.Name != col.T.Name || parentCol.T.Len != col.T.Len {
+			return fmt.Errorf("table %v can't be interleaved in %v: primary key column %v doesn't match column %v of the parent", ct.Name, parent.Name, col.Name, parentCol.Name)
+		}
+	}
+	return nil
+}
+
+// addNewTable adds ct to the Spanner schema. The table has no source table
+// and no schema issues.
+func addNewTable(conv *internal.Conv, ct ddl.CreateTable) {
+	conv.SpSchema[ct.Id] = ct
+	conv.SchemaIssues[ct.Id] = internal.TableIssues{
+		ColumnLevelIssues: make(map[string][]internal.SchemaIssue),
+	}
+	conv.UsedNames[strings.ToLower(ct.Name)] = true
+}
+
+// newTableId returns a table id which is used by neither a source table nor
+// a Spanner table. Ids are generated from a counter, which may be behind the
+// ids of a session that was restored from a file.
+func newTableId(conv *internal.Conv) string {
+	for {
+		id := internal.GenerateTableId()
+		_, inSpanner := conv.SpSchema[id]
+		_, inSource := conv.SrcSchema[id]
+		if !inSpanner && !inSource {
+			return id
+		}
+	}
+}
+
+func isSupportedSpannerType(ty string) bool {
+	for _, spType := range []string{ddl.Bool, ddl.Bytes, ddl.Date, ddl.Float32, ddl.Float64, ddl.Int64, ddl.String, ddl.Timestamp, ddl.Numeric, ddl.JSON} {
+		if ty == spType {
+			return true
+		}
+	}
+	return false
+}
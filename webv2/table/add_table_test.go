@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddNewTable(t *testing.T) {
+	initialConv := func() *internal.Conv {
+		conv := internal.MakeConv()
+		conv.SrcSchema = map[string]schema.Table{"t1": {Id: "t1", Name: "orders"}}
+		conv.SpSchema = map[string]ddl.CreateTable{
+			"t1": {
+				Id:          "t1",
+				Name:        "orders",
+				ColIds:      []string{"c1"},
+				ColDefs:     map[string]ddl.ColumnDef{"c1": {Id: "c1", Name: "order_id", T: ddl.Type{Name: ddl.Int64}, NotNull: true}},
+				PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+			},
+		}
+		conv.UsedNames = map[string]bool{"orders": true}
+		return conv
+	}
+	testCases := []struct {
+		name                 string
+		payload              string
+		expectedStatusCode   int
+		expectedBodyContains string
+		checkConv            func(t *testing.T, conv *internal.Conv)
+	}{
+		{
+			name: "Add outbox table",
+			payload: `{"Name": "outbox", "Columns": [
+				{"Name": "event_id", "Datatype": "STRING", "Length": 36},
+				{"Name": "payload", "Datatype": "JSON", "IsNullable": true}],
+				"PrimaryKeys": [{"Name": "event_id"}]}`,
+			expectedStatusCode: http.StatusOK,
+			checkConv: func(t *testing.T, conv *internal.Conv) {
+				var outbox ddl.CreateTable
+				for id, ct := range conv.SpSchema {
+					if ct.Name == "outbox" {
+						outbox = ct
+						assert.True(t, internal.IsAddedTable(conv, id))
+					}
+				}
+				assert.Equal(t, 2, len(outbox.ColIds))
+				assert.Equal(t, ddl.Type{Name: ddl.String, Len: 36}, outbox.ColDefs[outbox.ColIds[0]].T)
+				assert.True(t, outbox.ColDefs[outbox.ColIds[0]].NotNull)
+				assert.Equal(t, []ddl.IndexKey{{ColId: outbox.ColIds[0], Order: 1}}, outbox.PrimaryKeys)
+			},
+		},
+		{
+			name: "Add interleaved table",
+			payload: `{"Name": "order_events", "Columns": [
+				{"Name": "order_id", "Datatype": "INT64"},
+				{"Name": "event_id", "Datatype": "INT64"}],
+				"PrimaryKeys": [{"Name": "order_id"}, {"Name": "event_id", "Desc": true}],
+				"ParentTableId": "t1", "OnDelete": "cascade"}`,
+			expectedStatusCode: http.StatusOK,
+			checkConv: func(t *testing.T, conv *internal.Conv) {
+				for _, ct := range conv.SpSchema {
+					if ct.Name == "order_events" {
+						assert.Equal(t, ddl.InterleavedParent{Id: "t1", OnDelete: constants.FK_CASCADE}, ct.ParentTable)
+						assert.True(t, ct.PrimaryKeys[1].Desc)
+					}
+				}
+			},
+		},
+		{
+			name:                 "Error on parent key mismatch",
+			payload:              `{"Name": "order_events", "Columns": [{"Name": "event_id", "Datatype": "INT64"}], "PrimaryKeys": [{"Name": "event_id"}], "ParentTableId": "t1"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedBodyContains: "can't be interleaved",
+		},
+		{
+			name:                 "Error on used identifier",
+			payload:              `{"Name": "Orders", "Columns": [{"Name": "id", "Datatype": "INT64"}], "PrimaryKeys": [{"Name": "id"}]}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedBodyContains: "is an existing identifier",
+		},
+		{
+			name:                 "Error on missing primary key",
+			payload:              `{"Name": "outbox", "Columns": [{"Name": "id", "Datatype": "INT64"}]}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedBodyContains: "must have a primary key",
+		},
+		{
+			name:                 "Error on unsupported type",
+			payload:              `{"Name": "outbox", "Columns": [{"Name": "id", "Datatype": "INT32"}], "PrimaryKeys": [{"Name": "id"}]}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedBodyContains: "unsupported type",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sessionState := session.GetSessionState()
+			sessionState.Conv = initialConv()
+
+			req, err := http.NewRequest("POST", "/AddTable", strings.NewReader(tc.payload))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			handler := http.HandlerFunc(AddNewTable)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatusCode, rr.Code)
+			if tc.expectedBodyContains != "" {
+				assert.Contains(t, rr.Body.String(), tc.expectedBodyContains)
+			}
+			if tc.checkConv != nil {
+				var res session.ConvWithMetadata
+				err := json.Unmarshal(rr.Body.Bytes(), &res)
+				if err != nil {
+					t.Fatalf("Failed to unmarshal response body: %v", err)
+				}
+				tc.checkConv(t, res.Conv)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
+)
+
+// Status of a schema object in a DDL diff.
+const (
+	DDLAdded     = "ADDED"
+	DDLRemoved   = "REMOVED"
+	DDLChanged   = "CHANGED"
+	DDLUnchanged = "UNCHANGED"
+)
+
+// DDLLine is a line of a DDL diff. Op is "+" for an added line, "-" for a
+// removed line and " " for an unchanged line.
+type DDLLine struct {
+	Op   string
+	Text string
+}
+
+// DDLObjectDiff is the difference between the DDL of a schema object before
+// and after the staged edits.
+type DDLObjectDiff struct {
+	Kind   string // TABLE, INDEX, FOREIGN KEY, SEQUENCE, VIEW, DATABASE or ACCESS CONTROL.
+	Id     string
+	Name   string // Name after the edits, or before them for removed objects.
+	Status string
+	Lines  []DDLLine
+}
+
+// ReviewTableSchemaDiffResponse is the full database DDL before and after
+// the staged edits, and the diff of every schema object.
+type ReviewTableSchemaDiffResponse struct {
+	Before  string
+	After   string
+	Objects []DDLObjectDiff
+}
+
+// ddlObject is the DDL of a schema object.
+type ddlObject struct {
+	kind string
+	id   string
+	name string
+	stmt string
+}
+
+// ReviewTableSchemaDiff applies the same staged edits as ReviewTableSchema
+// to a copy of the session, and returns the database DDL before and after
+// them as a per-object diff. The session is not modified.
+func ReviewTableSchemaDiff(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
+	defer sessionState.LockConv()()
+	conv, _, ok := stageTableUpdates(w, r)
+	if !ok {
+		return
+	}
+	resp := diffDDL(ddlObjects(sessionState.Conv, sessionState.Driver), ddlObjects(conv, sessionState.Driver))
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ddlObjects returns the DDL of the schema objects of conv, in the order in
+// which they are created.
+func ddlObjects(conv *internal.Conv, driver string) []ddlObject {
+	c := ddl.Config{Comments: true, ProtectIds: false, SpDialect: conv.SpDialect, Source: driver}
+	var objs []ddlObject
+	if options := ddl.GetDDL(c, ddl.Schema{}, nil, conv.DatabaseOptions); len(options) > 0 {
+		objs = append(objs, ddlObject{kind: "DATABASE", id: "database", name: conv.DatabaseOptions.DbName, stmt: strings.Join(options, ";\n")})
+	}
+
+	var sequences []ddl.Sequence
+	for _, seq := range conv.SpSequences {
+		sequences = append(sequences, seq)
+	}
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i].Name < sequences[j].Name })
+	for _, seq := range sequences {
+		stmt := seq.PrintSequence(c)
+		if c.SpDialect == constants.DIALECT_POSTGRESQL {
+			stmt = seq.PGPrintSequence(c)
+		}
+		objs = append(objs, ddlObject{kind: "SEQUENCE", id: seq.Id, name: seq.Name, stmt: stmt})
+	}
+
+	tableIds := ddl.GetSortedTableIdsBySpName(conv.SpSchema)
+	for _, tableId := range tableIds {
+		ct := conv.SpSchema[tableId]
+		objs = append(objs, ddlObject{kind: "TABLE", id: tableId, name: ct.Name, stmt: ct.PrintCreateTable(conv.SpSchema, c)})
+		for _, index := range ct.Indexes {
+			objs = append(objs, ddlObject{kind: "INDEX", id: index.Id, name: index.Name, stmt: index.PrintCreateIndex(ct, c)})
+		}
+	}
+	for _, tableId := range tableIds {
+		for _, fk := range conv.SpSchema[tableId].ForeignKeys {
+			objs = append(objs, ddlObject{kind: "FOREIGN KEY", id: fk.Id, name: fk.Name, stmt: fk.PrintForeignKeyAlterTable(conv.SpSchema, c, tableId)})
+		}
+	}
+
+	var views []ddl.CreateView
+	for _, v := range conv.SpViews {
+		views = append(views, v)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+	for _, v := range views {
+		objs = append(objs, ddlObject{kind: "VIEW", id: v.Id, name: v.Name, stmt: v.PrintCreateView(c)})
+	}
+
+	if stmts := ddl.GetAccessControlDDL(c, conv.SpSchema, conv.SpRoles, conv.SpGrants); len(stmts) > 0 {
+		objs = append(objs, ddlObject{kind: "ACCESS CONTROL", id: "access_control", stmt: strings.Join(stmts, ";\n")})
+	}
+	return objs
+}
+
+// diffDDL compares the DDL of the schema objects before and after the edits.
+// Objects are matched by kind and id so that renamed objects are reported as
+// changed. Objects are listed in the order of after, followed by the removed
+// objects.
+func diffDDL(before, after []ddlObject) ReviewTableSchemaDiffResponse {
+	resp := ReviewTableSchemaDiffResponse{Before: joinDDL(before), After: joinDDL(after), Objects: []DDLObjectDiff{}}
+	beforeByKey := make(map[string]ddlObject)
+	for _, o := range before {
+		beforeByKey[o.kind+"/"+o.id] = o
+	}
+	seen := make(map[string]bool)
+	for _, o := range after {
+		key := o.kind + "/" + o.id
+		seen[key] = true
+		d := DDLObjectDiff{Kind: o.kind, Id: o.id, Name: o.name}
+		old, ok := beforeByKey[key]
+		switch {
+		case !ok:
+			d.Status = DDLAdded
+			d.Lines = diffLines("", o.stmt)
+		case old.stmt != o.stmt:
+			d.Status = DDLChanged
+			d.Lines = diffLines(old.stmt, o.stmt)
+		default:
+			d.Status = DDLUnchanged
+			d.Lines = diffLines(o.stmt, o.stmt)
+		}
+		resp.Objects = append(resp.Objects, d)
+	}
+	for _, o := range before {
+		if !seen[o.kind+"/"+o.id] {
+			resp.Objects = append(resp.Objects, DDLObjectDiff{Kind: o.kind, Id: o.id, Name: o.name, Status: DDLRemoved, Lines: diffLines(o.stmt, "")})
+		}
+	}
+	return resp
+}
+
+func joinDDL(objs []ddlObject) string {
+	var stmts []string
+	for _, o := range objs {
+		stmts = append(stmts, o.stmt)
+	}
+	return strings.Join(stmts, ";\n\n")
+}
+
+// diffLines returns the line diff of two statements, based on their longest
+// common subsequence of lines.
+func diffLines(before, after string) []DDLLine {
+	a, b := splitLines(before), splitLines(after)
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	lines := []DDLLine{}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, DDLLine{Op: " ", Text: a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, DDLLine{Op: "-", Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DDLLine{Op: "+", Text: b[j]})
+			j++
+		}
+	}
+	return lines
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
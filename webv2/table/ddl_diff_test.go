@@ -0,0 +1,56 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLines(t *testing.T) {
+	lines := diffLines("CREATE TABLE t (\n\ta INT64,\n\tb STRING(10),\n) PRIMARY KEY (a)", "CREATE TABLE t (\n\ta INT64,\n\tb STRING(20),\n\tc BOOL,\n) PRIMARY KEY (a)")
+	assert.Equal(t, []DDLLine{
+		{Op: " ", Text: "CREATE TABLE t ("},
+		{Op: " ", Text: "\ta INT64,"},
+		{Op: "-", Text: "\tb STRING(10),"},
+		{Op: "+", Text: "\tb STRING(20),"},
+		{Op: "+", Text: "\tc BOOL,"},
+		{Op: " ", Text: ") PRIMARY KEY (a)"},
+	}, lines)
+	assert.Equal(t, []DDLLine{{Op: "+", Text: "CREATE INDEX i ON t (b)"}}, diffLines("", "CREATE INDEX i ON t (b)"))
+}
+
+func TestDiffDDL(t *testing.T) {
+	before := []ddlObject{
+		{kind: "TABLE", id: "t1", name: "orders", stmt: "CREATE TABLE orders"},
+		{kind: "INDEX", id: "i1", name: "orders_idx", stmt: "CREATE INDEX orders_idx"},
+		{kind: "TABLE", id: "t2", name: "items", stmt: "CREATE TABLE items"},
+	}
+	after := []ddlObject{
+		{kind: "TABLE", id: "t1", name: "purchases", stmt: "CREATE TABLE purchases"},
+		{kind: "TABLE", id: "t2", name: "items", stmt: "CREATE TABLE items"},
+		{kind: "SEQUENCE", id: "s1", name: "seq", stmt: "CREATE SEQUENCE seq"},
+	}
+	resp := diffDDL(before, after)
+	assert.Equal(t, "CREATE TABLE orders;\n\nCREATE INDEX orders_idx;\n\nCREATE TABLE items", resp.Before)
+	assert.Equal(t, "CREATE TABLE purchases;\n\nCREATE TABLE items;\n\nCREATE SEQUENCE seq", resp.After)
+	assert.Equal(t, []DDLObjectDiff{
+		{Kind: "TABLE", Id: "t1", Name: "purchases", Status: DDLChanged, Lines: []DDLLine{{Op: "-", Text: "CREATE TABLE orders"}, {Op: "+", Text: "CREATE TABLE purchases"}}},
+		{Kind: "TABLE", Id: "t2", Name: "items", Status: DDLUnchanged, Lines: []DDLLine{{Op: " ", Text: "CREATE TABLE items"}}},
+		{Kind: "SEQUENCE", Id: "s1", Name: "seq", Status: DDLAdded, Lines: []DDLLine{{Op: "+", Text: "CREATE SEQUENCE seq"}}},
+		{Kind: "INDEX", Id: "i1", Name: "orders_idx", Status: DDLRemoved, Lines: []DDLLine{{Op: "-", Text: "CREATE INDEX orders_idx"}}},
+	}, resp.Objects)
+}
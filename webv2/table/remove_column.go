@@ -15,6 +15,8 @@
 package table
 
 import (
+	"strings"
+
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
@@ -43,8 +45,15 @@ func RemoveColumn(tableId string, colId string, conv *internal.Conv) {
 		conv.SpSchema[id] = sp
 	}
 
+	// flag check constraints referencing the column, which can't be applied without it.
+	conv.FlagCheckConstraints(tableId, colId, internal.ColumnNotFoundError)
+
 	//remove column from the table.
 	removeColumnFromTableSchema(conv, tableId, colId)
+	delete(conv.ColumnFills[tableId], colId)
+	delete(conv.ColumnTimezones[tableId], colId)
+	delete(conv.ColumnMasks[tableId], colId)
+	conv.RemoveColumnTransforms(tableId, colId)
 
 }
 
@@ -64,6 +73,10 @@ func removeColumnFromTableSchema(conv *internal.Conv, tableId string, colId stri
 
 	sp = removeColumnFromSpannerForeignkeyReferColumns(sp, colId)
 
+	sp = removeColumnFromSpannerRowDeletionPolicy(sp, colId)
+
+	sp = removeColumnFromSpannerVectorIndexes(conv, sp, colId)
+
 	sp = removeColumnFromSpannerColNames(sp, colId)
 
 	removeSpannerSchemaIssue(tableId, colId, conv)
@@ -100,6 +113,36 @@ func removeColumnFromSpannerPK(sp ddl.CreateTable, colId string) ddl.CreateTable
 	return sp
 }
 
+// removeColumnFromSpannerRowDeletionPolicy remove row deletion policy defined on given column.
+func removeColumnFromSpannerRowDeletionPolicy(sp ddl.CreateTable, colId string) ddl.CreateTable {
+	if sp.RowDeletionPolicy.ColId == colId {
+		sp.RowDeletionPolicy = ddl.RowDeletionPolicy{}
+	}
+	return sp
+}
+
+// removeColumnFromSpannerVectorIndexes remove vector indexes defined on given
+// column and remove given column from the stored columns of the others.
+func removeColumnFromSpannerVectorIndexes(conv *internal.Conv, sp ddl.CreateTable, colId string) ddl.CreateTable {
+	var vectorIndexes []ddl.VectorIndex
+	for _, vi := range sp.VectorIndexes {
+		if vi.ColId == colId {
+			delete(conv.UsedNames, strings.ToLower(vi.Name))
+			continue
+		}
+		var storedColumnIds []string
+		for _, id := range vi.StoredColumnIds {
+			if id != colId {
+				storedColumnIds = append(storedColumnIds, id)
+			}
+		}
+		vi.StoredColumnIds = storedColumnIds
+		vectorIndexes = append(vectorIndexes, vi)
+	}
+	sp.VectorIndexes = vectorIndexes
+	return sp
+}
+
 // removeColumnFromSpannerColDefs remove given column from Spanner ColDefs List.
 func removeColumnFromSpannerColDefs(sp ddl.CreateTable, colId string) ddl.CreateTable {
 	delete(sp.ColDefs, colId)
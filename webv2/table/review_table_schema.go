@@ -19,7 +19,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"regexp"
 	"strconv"
 	"strings"
 
@@ -38,10 +37,38 @@ type ReviewTableSchemaResponse struct {
 
 // ReviewTableSchema review Spanner Table Schema.
 func ReviewTableSchema(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
+	defer sessionState.LockConv()()
+	conv, tableId, ok := stageTableUpdates(w, r)
+	if !ok {
+		return
+	}
+
+	ddl := GetSpannerTableDDL(sessionState, conv.SpSchema[tableId], conv.SpDialect, sessionState.Driver)
+
+	resp := ReviewTableSchemaResponse{
+		DDL: ddl,
+	}
+
+	sessionMetaData := session.RequestSessionState(r).SessionMetadata
+	if sessionMetaData.DatabaseName == "" || sessionMetaData.DatabaseType == "" || sessionMetaData.SessionName == "" {
+		sessionMetaData.DatabaseName = sessionState.DbName
+		sessionMetaData.DatabaseType = sessionState.Driver
+		sessionMetaData.SessionName = "NewSession"
+	}
+	session.RequestSessionState(r).SessionMetadata = sessionMetaData
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// stageTableUpdates applies the column updates of the request to a copy of
+// the session conv, and returns the copy and the updated table. Errors are
+// written to w. Callers must hold the conv lock.
+func stageTableUpdates(w http.ResponseWriter, r *http.Request) (*internal.Conv, string, bool) {
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
-		return
+		return nil, "", false
 	}
 	var t updateTable
 
@@ -50,35 +77,32 @@ func ReviewTableSchema(w http.ResponseWriter, r *http.Request) {
 	err = json.Unmarshal(reqBody, &t)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
-		return
+		return nil, "", false
 	}
 
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
-
+	sessionState := session.RequestSessionState(r)
 	var conv *internal.Conv
 
 	convByte, err := json.Marshal(sessionState.Conv)
 
 	if err != nil {
 		http.Error(w, fmt.Sprintf("conversion object parse error : %v", err), http.StatusInternalServerError)
-		return
+		return nil, "", false
 	}
 	if err := json.Unmarshal(convByte, &conv); err != nil {
 		http.Error(w, fmt.Sprintf("conversion object parse error : %v", err), http.StatusInternalServerError)
-		return
+		return nil, "", false
 	}
 
 	conv.UsedNames = internal.ComputeUsedNames(conv)
 
 	for colId, v := range t.UpdateCols {
 
-		interleavingImpact := IsInterleavingImpacted(v, tableId, colId, conv)
+		interleavingImpact := IsInterleavingImpacted(sessionState, v, tableId, colId, conv)
 
 		if interleavingImpact != "" {
 			http.Error(w, interleavingImpact, http.StatusBadRequest)
-			return
+			return nil, "", false
 		}
 
 		if v.Add {
@@ -94,39 +118,26 @@ func ReviewTableSchema(w http.ResponseWriter, r *http.Request) {
 			for _, c := range conv.SpSchema[tableId].ColDefs {
 				if strings.EqualFold(c.Name, v.Rename) {
 					http.Error(w, fmt.Sprintf("Multiple columns with similar name cannot exist for column : %v", v.Rename), http.StatusBadRequest)
-					return
+					return nil, "", false
 				}
 			}
-			oldName := conv.SpSchema[tableId].ColDefs[colId].Name
-			// Using a regular expression to match the exact column name
-			re := regexp.MustCompile(`\b` + regexp.QuoteMeta(oldName) + `\b`)
-
-			for i := range conv.SpSchema[tableId].CheckConstraints {
-				originalString := conv.SpSchema[tableId].CheckConstraints[i].Expr
-				updatedValue := re.ReplaceAllString(originalString, v.Rename)
-				conv.SpSchema[tableId].CheckConstraints[i].Expr = updatedValue
-			}
-
-			sp := conv.SpSchema[tableId]
-			column, ok := sp.ColDefs[colId]
-			if ok {
-				column.Name = v.Rename
-				sp.ColDefs[colId] = column
-				conv.SpSchema[tableId] = sp
+			if err := conv.RenameColumn(tableId, colId, v.Rename); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return nil, "", false
 			}
 		}
 
 		_, found := conv.SrcSchema[tableId].ColDefs[colId]
 		if v.ToType != "" && found {
 
-			typeChange, err := utilities.IsTypeChanged(v.ToType, tableId, colId, conv)
+			typeChange, err := utilities.IsTypeChanged(sessionState, v.ToType, tableId, colId, conv)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusBadRequest)
-				return
+				return nil, "", false
 			}
 
 			if typeChange {
-				sp, ty, err := utilities.GetType(conv, v.ToType, tableId, colId)
+				sp, ty, err := utilities.GetType(sessionState, conv, v.ToType, tableId, colId)
 
 				colDef := sp.ColDefs[colId]
 				colDef.T = ty
@@ -145,8 +156,9 @@ func ReviewTableSchema(w http.ResponseWriter, r *http.Request) {
 				conv.SpSchema[tableId] = sp
 				if err != nil {
 					http.Error(w, err.Error(), http.StatusBadRequest)
-					return
+					return nil, "", false
 				}
+				updateRowDeletionPolicyForColumnType(conv, tableId, colId)
 			}
 		}
 
@@ -176,21 +188,27 @@ func ReviewTableSchema(w http.ResponseWriter, r *http.Request) {
 			UpdateDefaultValue(v.DefaultValue, tableId, colId, conv)
 			UpdateGeneratedCol(v.GeneratedColumn, tableId, colId, conv)
 		}
-	}
 
-	ddl := GetSpannerTableDDL(conv.SpSchema[tableId], conv.SpDialect, sessionState.Driver)
+		if !v.Removed && v.Fill != nil {
+			if err := conv.SetColumnFill(tableId, colId, *v.Fill); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return nil, "", false
+			}
+		}
 
-	resp := ReviewTableSchemaResponse{
-		DDL: ddl,
-	}
+		if !v.Removed && v.Timezone != nil {
+			if err := conv.SetColumnTimezone(tableId, colId, *v.Timezone); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return nil, "", false
+			}
+		}
 
-	sessionMetaData := session.GetSessionState().SessionMetadata
-	if sessionMetaData.DatabaseName == "" || sessionMetaData.DatabaseType == "" || sessionMetaData.SessionName == "" {
-		sessionMetaData.DatabaseName = sessionState.DbName
-		sessionMetaData.DatabaseType = sessionState.Driver
-		sessionMetaData.SessionName = "NewSession"
+		if !v.Removed && v.Mask != nil {
+			if err := conv.SetColumnMask(tableId, colId, *v.Mask); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return nil, "", false
+			}
+		}
 	}
-	session.GetSessionState().SessionMetadata = sessionMetaData
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(resp)
+	return conv, tableId, true
 }
@@ -21,43 +21,48 @@ import (
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/spanner/ddl"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
 	utilities "github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/utilities"
 )
 
 // UpdateColumnType updates type of given column to newType.
-func UpdateColumnType(newType, tableId, colId string, conv *internal.Conv, w http.ResponseWriter) {
-
+func UpdateColumnType(sessionState *session.SessionState, newType, tableId, colId string, conv *internal.Conv, w http.ResponseWriter) {
 	// update column type for current table.
-	err := UpdateColumnTypeChangeTableSchema(conv, tableId, colId, newType, w)
+	oldType := conv.SpSchema[tableId].ColDefs[colId].T
+	err := UpdateColumnTypeChangeTableSchema(sessionState, conv, tableId, colId, newType, w)
 	if err != nil {
 		return
 	}
+	if conv.SpSchema[tableId].ColDefs[colId].T != oldType {
+		// check constraints on the column may not hold for the new type.
+		conv.FlagCheckConstraints(tableId, colId, internal.CheckConstraintColumnRetyped)
+	}
 
 	// update column type for refer tables.
-	err = updateColumnTypeForReferredTable(newType, tableId, colId, conv, w)
+	err = updateColumnTypeForReferredTable(sessionState, newType, tableId, colId, conv, w)
 	if err != nil {
 		return
 	}
 
 	// update column type for tables referring to the current table.
-	err = updateColumnTypeForReferringTable(newType, tableId, colId, conv, w)
+	err = updateColumnTypeForReferringTable(sessionState, newType, tableId, colId, conv, w)
 	if err != nil {
 		return
 	}
 }
 
-func updateColumnTypeForReferredTable(newType, tableId, colId string, conv *internal.Conv, w http.ResponseWriter) error {
+func updateColumnTypeForReferredTable(sessionState *session.SessionState, newType, tableId, colId string, conv *internal.Conv, w http.ResponseWriter) error {
 	sp := conv.SpSchema[tableId]
 	for _, fk := range sp.ForeignKeys {
 		fkReferColPosition := getFkColumnPosition(fk.ColIds, colId)
 		if fkReferColPosition == -1 {
 			continue
 		}
-		err := UpdateColumnTypeChangeTableSchema(conv, fk.ReferTableId, fk.ReferColumnIds[fkReferColPosition], newType, w)
+		err := UpdateColumnTypeChangeTableSchema(sessionState, conv, fk.ReferTableId, fk.ReferColumnIds[fkReferColPosition], newType, w)
 		if err != nil {
 			return err
 		}
-		err = updateColumnTypeForReferredTable(newType, fk.ReferTableId, fk.ReferColumnIds[fkReferColPosition], conv, w)
+		err = updateColumnTypeForReferredTable(sessionState, newType, fk.ReferTableId, fk.ReferColumnIds[fkReferColPosition], conv, w)
 		if err != nil {
 			return err
 		}
@@ -65,7 +70,7 @@ func updateColumnTypeForReferredTable(newType, tableId, colId string, conv *inte
 	return nil
 }
 
-func updateColumnTypeForReferringTable(newType, tableId, colId string, conv *internal.Conv, w http.ResponseWriter) error {
+func updateColumnTypeForReferringTable(sessionState *session.SessionState, newType, tableId, colId string, conv *internal.Conv, w http.ResponseWriter) error {
 	for _, sp := range conv.SpSchema {
 		for j := 0; j < len(sp.ForeignKeys); j++ {
 			if sp.ForeignKeys[j].ReferTableId == tableId {
@@ -73,11 +78,11 @@ func updateColumnTypeForReferringTable(newType, tableId, colId string, conv *int
 				if fkColPosition == -1 {
 					continue
 				}
-				err := UpdateColumnTypeChangeTableSchema(conv, sp.Id, sp.ForeignKeys[j].ColIds[fkColPosition], newType, w)
+				err := UpdateColumnTypeChangeTableSchema(sessionState, conv, sp.Id, sp.ForeignKeys[j].ColIds[fkColPosition], newType, w)
 				if err != nil {
 					return err
 				}
-				err = updateColumnTypeForReferringTable(newType, sp.Id, sp.ForeignKeys[j].ColIds[fkColPosition], conv, w)
+				err = updateColumnTypeForReferringTable(sessionState, newType, sp.Id, sp.ForeignKeys[j].ColIds[fkColPosition], conv, w)
 				if err != nil {
 					return err
 				}
@@ -107,11 +112,21 @@ func UpdateColumnSizeChangeTableSchema(conv *internal.Conv, tableId string, colI
 }
 
 // UpdateColumnTypeTableSchema updates column type to newtype for a column of a table.
-func UpdateColumnTypeChangeTableSchema(conv *internal.Conv, tableId string, colId string, newType string, w http.ResponseWriter) error {
-	err := utilities.UpdateDataType(conv, newType, tableId, colId)
+func UpdateColumnTypeChangeTableSchema(sessionState *session.SessionState, conv *internal.Conv, tableId string, colId string, newType string, w http.ResponseWriter) error {
+	err := utilities.UpdateDataType(sessionState, conv, newType, tableId, colId)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return err
 	}
+	updateRowDeletionPolicyForColumnType(conv, tableId, colId)
 	return nil
 }
+
+// updateRowDeletionPolicyForColumnType removes the row deletion policy defined
+// on given column once the column is no longer a TIMESTAMP.
+func updateRowDeletionPolicyForColumnType(conv *internal.Conv, tableId string, colId string) {
+	sp := conv.SpSchema[tableId]
+	if t := sp.ColDefs[colId].T; t.Name != ddl.Timestamp || t.IsArray {
+		conv.SpSchema[tableId] = removeColumnFromSpannerRowDeletionPolicy(sp, colId)
+	}
+}
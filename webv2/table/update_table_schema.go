@@ -19,7 +19,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"regexp"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
@@ -35,16 +34,25 @@ import (
 // (3) Rename: New name or empty string.
 // (4) NotNull: "ADDED", "REMOVED" or "".
 // (5) ToType: New type or empty string.
+// (6) Fill: Strategy filling the column during data migration, e.g. for added
+// columns, or nil to leave it unchanged.
+// (7) Timezone: Time zone of the column's source values without one, empty
+// string to use the timezone policy, or nil to leave it unchanged.
+// (8) Mask: Strategy masking the column's values during data migration, with
+// an empty strategy to remove it, or nil to leave it unchanged.
 type updateCol struct {
-	Add             bool                `json:"Add"`
-	Removed         bool                `json:"Removed"`
-	Rename          string              `json:"Rename"`
-	NotNull         string              `json:"NotNull"`
-	ToType          string              `json:"ToType"`
-	MaxColLength    string              `json:"MaxColLength"`
-	AutoGen         ddl.AutoGenCol      `json:"AutoGen"`
-	DefaultValue    ddl.DefaultValue    `json:"DefaultValue"`
-	GeneratedColumn ddl.GeneratedColumn `json:"GeneratedColumn"`
+	Add             bool                 `json:"Add"`
+	Removed         bool                 `json:"Removed"`
+	Rename          string               `json:"Rename"`
+	NotNull         string               `json:"NotNull"`
+	ToType          string               `json:"ToType"`
+	MaxColLength    string               `json:"MaxColLength"`
+	AutoGen         ddl.AutoGenCol       `json:"AutoGen"`
+	DefaultValue    ddl.DefaultValue     `json:"DefaultValue"`
+	GeneratedColumn ddl.GeneratedColumn  `json:"GeneratedColumn"`
+	Fill            *internal.ColumnFill `json:"Fill"`
+	Timezone        *string              `json:"Timezone"`
+	Mask            *internal.ColumnMask `json:"Mask"`
 }
 
 type updateTable struct {
@@ -59,6 +67,7 @@ type updateTable struct {
 // (4) Add or Remove NotNull constraint.
 // (5) Update Spanner type.
 // (6) Update Check constraints Name.
+// (7) Add, update or remove the default value or generated column expression.
 func UpdateTableSchema(w http.ResponseWriter, r *http.Request) {
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -75,16 +84,15 @@ func UpdateTableSchema(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
+	sessionState := session.RequestSessionState(r)
+	defer sessionState.LockConv()()
 
 	var conv *internal.Conv
 	conv = nil
 	conv = sessionState.Conv
 
 	for colId, v := range t.UpdateCols {
-		interleavingImpact := IsInterleavingImpacted(v, tableId, colId, conv)
+		interleavingImpact := IsInterleavingImpacted(sessionState, v, tableId, colId, conv)
 		if interleavingImpact != "" {
 			http.Error(w, interleavingImpact, http.StatusBadRequest)
 			return
@@ -99,32 +107,23 @@ func UpdateTableSchema(w http.ResponseWriter, r *http.Request) {
 		}
 
 		if v.Rename != "" && v.Rename != conv.SpSchema[tableId].ColDefs[colId].Name {
-
-			oldName := conv.SrcSchema[tableId].ColDefs[colId].Name
-
-			// Use a regular expression to match the exact column name
-			re := regexp.MustCompile(`\b` + regexp.QuoteMeta(oldName) + `\b`)
-
-			for i := range conv.SpSchema[tableId].CheckConstraints {
-				originalString := conv.SpSchema[tableId].CheckConstraints[i].Expr
-				updatedValue := re.ReplaceAllString(originalString, v.Rename)
-				conv.SpSchema[tableId].CheckConstraints[i].Expr = updatedValue
+			if err := conv.RenameColumn(tableId, colId, v.Rename); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
 			}
-
-			renameColumn(v.Rename, tableId, colId, conv)
 		}
 
 		_, found := conv.SrcSchema[tableId].ColDefs[colId]
 		if v.ToType != "" && found {
 
-			typeChange, err := utilities.IsTypeChanged(v.ToType, tableId, colId, conv)
+			typeChange, err := utilities.IsTypeChanged(sessionState, v.ToType, tableId, colId, conv)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
 
 			if typeChange {
-				UpdateColumnType(v.ToType, tableId, colId, conv, w)
+				UpdateColumnType(sessionState, v.ToType, tableId, colId, conv, w)
 			}
 		}
 
@@ -135,19 +134,41 @@ func UpdateTableSchema(w http.ResponseWriter, r *http.Request) {
 			UpdateColumnSize(v.MaxColLength, tableId, colId, conv)
 		}
 		if !v.Removed {
+			if err := validateGeneratedCol(v.GeneratedColumn, v.DefaultValue, v.AutoGen, tableId, colId, conv); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 			sequences := UpdateAutoGenCol(v.AutoGen, tableId, colId, conv)
 			conv.SpSequences = sequences
 			UpdateDefaultValue(v.DefaultValue, tableId, colId, conv)
 			UpdateGeneratedCol(v.GeneratedColumn, tableId, colId, conv)
+			if v.Fill != nil {
+				if err := conv.SetColumnFill(tableId, colId, *v.Fill); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+			if v.Timezone != nil {
+				if err := conv.SetColumnTimezone(tableId, colId, *v.Timezone); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+			if v.Mask != nil {
+				if err := conv.SetColumnMask(tableId, colId, *v.Mask); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
 		}
 	}
 
 	common.ComputeNonKeyColumnSize(conv, tableId)
 
 	delete(conv.SpSchema[tableId].ColDefs, "")
-	sessionState.Conv = conv
+	sessionState.SetConv(conv)
 
-	session.UpdateSessionFile()
+	session.UpdateSessionFile(sessionState)
 
 	convm := session.ConvWithMetadata{
 		SessionMetadata: sessionState.SessionMetadata,
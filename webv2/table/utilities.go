@@ -67,8 +67,7 @@ func IsColumnPresentInColNames(colIds []string, colId string) bool {
 }
 
 // GetSpannerTableDDL return Spanner Table DDL as string.
-func GetSpannerTableDDL(spannerTable ddl.CreateTable, spDialect string, driver string) string {
-	sessionState := session.GetSessionState()
+func GetSpannerTableDDL(sessionState *session.SessionState, spannerTable ddl.CreateTable, spDialect string, driver string) string {
 	c := ddl.Config{Comments: true, ProtectIds: false, SpDialect: spDialect, Source: driver}
 
 	ddl := spannerTable.PrintCreateTable(sessionState.Conv.SpSchema, c)
@@ -168,6 +167,33 @@ func getSequenceId(sequenceName string, spSeq map[string]ddl.Sequence) string {
 	return ""
 }
 
+// validateGeneratedCol checks that the column colId of table tableId can be a
+// generated column defined by gc, given its default value dv and
+// auto-generation autoGen.
+func validateGeneratedCol(gc ddl.GeneratedColumn, dv ddl.DefaultValue, autoGen ddl.AutoGenCol, tableId, colId string, conv *internal.Conv) error {
+	if !gc.IsPresent {
+		return nil
+	}
+	colName := conv.SpSchema[tableId].ColDefs[colId].Name
+	if strings.TrimSpace(gc.Value.Statement) == "" {
+		return fmt.Errorf("generated column %s has an empty expression", colName)
+	}
+	if gc.Type != ddl.GeneratedColStored && gc.Type != ddl.GeneratedColVirtual {
+		return fmt.Errorf("generated column %s has an invalid type %q, it must be %s or %s", colName, gc.Type, ddl.GeneratedColStored, ddl.GeneratedColVirtual)
+	}
+	if dv.IsPresent || autoGen.GenerationType != "" {
+		return fmt.Errorf("generated column %s can't have a default value or be auto-generated", colName)
+	}
+	if gc.Type == ddl.GeneratedColVirtual {
+		for _, pk := range conv.SpSchema[tableId].PrimaryKeys {
+			if pk.ColId == colId {
+				return fmt.Errorf("generated column %s is part of the primary key and must be %s", colName, ddl.GeneratedColStored)
+			}
+		}
+	}
+	return nil
+}
+
 // Add, deletes and updates generated column associated with a column during edit column functionality
 func UpdateGeneratedCol(gc ddl.GeneratedColumn, tableId, colId string, conv *internal.Conv) {
 	col := conv.SpSchema[tableId].ColDefs[colId]
@@ -239,7 +265,7 @@ func UpdateDefaultValue(dv ddl.DefaultValue, tableId, colId string, conv *intern
 	conv.SpSchema[tableId].ColDefs[colId] = col
 }
 
-func IsInterleavingImpacted(v updateCol, tableId string, colId string, conv *internal.Conv) string {
+func IsInterleavingImpacted(sessionState *session.SessionState, v updateCol, tableId string, colId string, conv *internal.Conv) string {
 	isPkColumn := false
 	pkOrder := -1
 	for _, pk := range conv.SpSchema[tableId].PrimaryKeys {
@@ -253,7 +279,7 @@ func IsInterleavingImpacted(v updateCol, tableId string, colId string, conv *int
 	if isPkColumn {
 		isModification := false
 		isRename := v.Rename != "" && v.Rename != conv.SpSchema[tableId].ColDefs[colId].Name
-		isTypeChange, _ := utilities.IsTypeChanged(v.ToType, tableId, colId, conv)
+		isTypeChange, _ := utilities.IsTypeChanged(sessionState, v.ToType, tableId, colId, conv)
 		isNullChange := v.NotNull != "" && ((v.NotNull == "ADDED" && !conv.SpSchema[tableId].ColDefs[colId].NotNull) || (v.NotNull == "REMOVED" && conv.SpSchema[tableId].ColDefs[colId].NotNull))
 
 		var isSizeChange bool
@@ -273,7 +299,7 @@ func IsInterleavingImpacted(v updateCol, tableId string, colId string, conv *int
 		}
 
 		if isModification {
-			isParent, _ := utilities.IsParent(tableId)
+			isParent, _ := utilities.IsParent(sessionState, tableId)
 			isChild := conv.SpSchema[tableId].ParentTable.Id != ""
 
 			// Rule 1: If it's a parent table, any change to a PK column is disallowed.
@@ -209,7 +209,7 @@ func TestIsInterleavingImpacted(t *testing.T) {
 			sessionState := session.GetSessionState()
 			sessionState.Conv = currentConv
 			sessionState.Driver = constants.MYSQL
-			errStr := IsInterleavingImpacted(tc.update, tc.tableId, tc.colId, currentConv)
+			errStr := IsInterleavingImpacted(session.GetSessionState(), tc.update, tc.tableId, tc.colId, currentConv)
 			if tc.expectImpact {
 				assert.Equal(t, tc.expectedError, errStr)
 			} else {
@@ -282,3 +282,40 @@ func TestUpdateGeneratedCol(t *testing.T) {
 	assert.Equal(t, "custom_id", conv.SpSchema["table1"].ColDefs["col2"].GeneratedColumn.Value.ExpressionId)
 	assert.Equal(t, ddl.GeneratedColVirtual, conv.SpSchema["table1"].ColDefs["col2"].GeneratedColumn.Type)
 }
+
+func TestValidateGeneratedCol(t *testing.T) {
+	conv := internal.MakeConv()
+	conv.SpSchema = ddl.Schema{
+		"t1": {
+			ColDefs: map[string]ddl.ColumnDef{
+				"c1": {Name: "id", T: ddl.Type{Name: ddl.Int64}},
+				"c2": {Name: "total", T: ddl.Type{Name: ddl.Int64}},
+			},
+			PrimaryKeys: []ddl.IndexKey{{ColId: "c1", Order: 1}},
+		},
+	}
+	stored := ddl.GeneratedColumn{IsPresent: true, Value: ddl.Expression{Statement: "price * 2"}, Type: ddl.GeneratedColStored}
+	virtual := ddl.GeneratedColumn{IsPresent: true, Value: ddl.Expression{Statement: "price * 2"}, Type: ddl.GeneratedColVirtual}
+	tests := []struct {
+		name    string
+		gc      ddl.GeneratedColumn
+		dv      ddl.DefaultValue
+		autoGen ddl.AutoGenCol
+		colId   string
+		wantErr bool
+	}{
+		{name: "no generated column", gc: ddl.GeneratedColumn{}, dv: ddl.DefaultValue{IsPresent: true}, colId: "c2"},
+		{name: "stored", gc: stored, colId: "c2"},
+		{name: "virtual", gc: virtual, colId: "c2"},
+		{name: "stored primary key", gc: stored, colId: "c1"},
+		{name: "virtual primary key", gc: virtual, colId: "c1", wantErr: true},
+		{name: "empty expression", gc: ddl.GeneratedColumn{IsPresent: true, Type: ddl.GeneratedColStored}, colId: "c2", wantErr: true},
+		{name: "invalid type", gc: ddl.GeneratedColumn{IsPresent: true, Value: ddl.Expression{Statement: "1"}}, colId: "c2", wantErr: true},
+		{name: "default value", gc: stored, dv: ddl.DefaultValue{IsPresent: true}, colId: "c2", wantErr: true},
+		{name: "auto-generated", gc: stored, autoGen: ddl.AutoGenCol{Name: "UUID", GenerationType: "Pre-defined"}, colId: "c2", wantErr: true},
+	}
+	for _, tc := range tests {
+		err := validateGeneratedCol(tc.gc, tc.dv, tc.autoGen, "t1", tc.colId, conv)
+		assert.Equal(t, tc.wantErr, err != nil, tc.name)
+	}
+}
@@ -169,6 +169,21 @@ type GeneratedResources struct {
 	ShardToShardResourcesMap map[string][]ResourceDetails `json:"ShardToShardResourcesMap"`
 }
 
+// VerifyExpressionRequest is an expression edited in the UI, to verify
+// against the session schema before it is saved. ColId is required for
+// default values and generated columns.
+type VerifyExpressionRequest struct {
+	TableId    string `json:"TableId"`
+	ColId      string `json:"ColId"`
+	Type       string `json:"Type"`
+	Expression string `json:"Expression"`
+}
+
+type VerifyExpressionResponse struct {
+	Valid bool   `json:"Valid"`
+	Error string `json:"Error"`
+}
+
 type DropDetail struct {
 	Name string `json:"Name"`
 }
@@ -180,4 +195,44 @@ type TableInterleaveStatus struct {
 	OnDelete string
 	Comment  string
 	InterleaveType string
-}
\ No newline at end of file
+	// Warning describes how the ON DELETE action of the interleaving differs
+	// from the one of the source foreign key it replaces, if it does.
+	Warning string
+}
+
+// InterleaveTree is the structure of the interleaved tables of a schema.
+type InterleaveTree struct {
+	Roots    []*internal.InterleaveNode // Trees of interleaved tables, one per top-level table.
+	MaxDepth int                        // Maximum number of levels supported by Spanner.
+	Cycles   [][]string                 // Names of the tables of each cycle of parent tables.
+	TooDeep  []string                   // Names of the tables nested deeper than MaxDepth.
+}
+
+// TableListItem summarizes a Spanner table of the session.
+type TableListItem struct {
+	Id      string `json:"Id"`
+	Name    string `json:"Name"`
+	SrcName string `json:"SrcName"`
+	Columns int    `json:"Columns"`
+	Issues  int    `json:"Issues"`
+}
+
+// TableList is a page of the tables of the session. Total is the number of
+// tables matching the filter, across all pages.
+type TableList struct {
+	Tables []TableListItem `json:"Tables"`
+	Total  int             `json:"Total"`
+}
+
+// SearchResult is a schema object or issue matching a search query. TableId
+// is the table the object belongs to, and Id the id of the object itself,
+// e.g. of the column, index or constraint. Issues are identified by the
+// table and, for column issues, the column they were raised on.
+type SearchResult struct {
+	Type    string `json:"Type"`
+	Id      string `json:"Id"`
+	TableId string `json:"TableId"`
+	ColId   string `json:"ColId"`
+	Name    string `json:"Name"`
+	Match   string `json:"Match"`
+}
@@ -19,8 +19,10 @@ import (
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/internal"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/schema"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/cassandra"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/common"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mongodb"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/mysql"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/oracle"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/sources/postgres"
@@ -29,9 +31,7 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
 )
 
-func GetType(conv *internal.Conv, newType, tableId, colId string) (ddl.CreateTable, ddl.Type, error) {
-	sessionState := session.GetSessionState()
-
+func GetType(sessionState *session.SessionState, conv *internal.Conv, newType, tableId, colId string) (ddl.CreateTable, ddl.Type, error) {
 	sp := conv.SpSchema[tableId]
 	srcCol := conv.SrcSchema[tableId].ColDefs[colId]
 	isPk := common.IsPrimaryKey(colId, conv.SrcSchema[tableId])
@@ -54,18 +54,25 @@ func GetType(conv *internal.Conv, newType, tableId, colId string) (ddl.CreateTab
 	case constants.CASSANDRA:
 		toddl = cassandra.InfoSchemaImpl{}.GetToDdl()
 		ty, issues = toddl.ToSpannerType(conv, newType, srcCol.Type, isPk)
+	case constants.MONGODB:
+		toddl = mongodb.InfoSchemaImpl{}.GetToDdl()
+		ty, issues = toddl.ToSpannerType(conv, newType, srcCol.Type, isPk)
 	default:
 		return sp, ty, fmt.Errorf("driver : '%s' is not supported", sessionState.Driver)
 	}
-	if len(srcCol.Type.ArrayBounds) > 0 && conv.SpDialect == constants.DIALECT_POSTGRESQL {
-		ty = ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
-	} else if len(srcCol.Type.ArrayBounds) > 1 {
+	// Multi-dimensional arrays are mapped by ToSpannerType.
+	if len(srcCol.Type.ArrayBounds) == 1 && conv.SpDialect == constants.DIALECT_POSTGRESQL {
 		ty = ddl.Type{Name: ddl.String, Len: ddl.MaxLength}
-		issues = append(issues, internal.MultiDimensionalArray)
 	}
 	if srcCol.Ignored.Default {
 		issues = append(issues, internal.DefaultValue)
 	}
+	if srcCol.UserType.Kind == schema.UserTypeDomain {
+		issues = append(issues, internal.DomainType)
+	}
+	if issue, ok := internal.CollationIssue(srcCol.Collation, ty); ok {
+		issues = append(issues, issue)
+	}
 	if srcCol.Ignored.AutoIncrement {
 		issues = append(issues, internal.AutoIncrement)
 	}
@@ -69,9 +69,9 @@ func TestGetType(t *testing.T) {
 			dialect: constants.DIALECT_GOOGLESQL,
 			srcCol:  schema.Column{Name: "col1", Type: schema.Type{Name: "text", ArrayBounds: []int64{-1, -1}}},
 			newType: "",
-			wantType: ddl.Type{Name: ddl.String, Len: ddl.MaxLength, IsArray: false},
+			wantType: ddl.Type{Name: ddl.JSON, IsArray: false},
 			wantErr:  false,
-			wantIssues: []internal.SchemaIssue{internal.MultiDimensionalArray, internal.MultiDimensionalArray},
+			wantIssues: []internal.SchemaIssue{internal.MultiDimensionalArray},
 		},
 		{
 			name:    "Cassandra array type",
@@ -154,7 +154,7 @@ func TestGetType(t *testing.T) {
 				Source:    tc.source,
 			}
 
-			_, gotType, gotErr := GetType(conv, tc.newType, tableId, colId)
+			_, gotType, gotErr := GetType(session.GetSessionState(), conv, tc.newType, tableId, colId)
 
 			if tc.wantErr {
 				assert.Error(t, gotErr)
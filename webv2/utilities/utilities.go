@@ -31,9 +31,7 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/session"
 )
 
-func InitObjectId() {
-
-	sessionState := session.GetSessionState()
+func InitObjectId(sessionState *session.SessionState) {
 	sessionState.Counter.ObjectId = "0"
 }
 
@@ -174,9 +172,8 @@ func RemoveFkReferColumns(slice []string, s int) []string {
 	return append(slice[:s], slice[s+1:]...)
 }
 
-func IsTypeChanged(newType, tableId, colId string, conv *internal.Conv) (bool, error) {
-
-	sp, ty, err := GetType(conv, newType, tableId, colId)
+func IsTypeChanged(sessionState *session.SessionState, newType, tableId, colId string, conv *internal.Conv) (bool, error) {
+	sp, ty, err := GetType(sessionState, conv, newType, tableId, colId)
 	if err != nil {
 		return false, err
 	}
@@ -184,9 +181,7 @@ func IsTypeChanged(newType, tableId, colId string, conv *internal.Conv) (bool, e
 	return !reflect.DeepEqual(colDef.T, ty), nil
 }
 
-func IsPartOfPK(col, table string) bool {
-	sessionState := session.GetSessionState()
-
+func IsPartOfPK(sessionState *session.SessionState, col, table string) bool {
 	for _, pk := range sessionState.Conv.SpSchema[table].PrimaryKeys {
 		if pk.ColId == col {
 			return true
@@ -195,9 +190,7 @@ func IsPartOfPK(col, table string) bool {
 	return false
 }
 
-func IsPartOfSecondaryIndex(col, table string) (bool, string) {
-	sessionState := session.GetSessionState()
-
+func IsPartOfSecondaryIndex(sessionState *session.SessionState, col, table string) (bool, string) {
 	for _, index := range sessionState.Conv.SpSchema[table].Indexes {
 		for _, key := range index.Keys {
 			if key.ColId == col {
@@ -208,9 +201,7 @@ func IsPartOfSecondaryIndex(col, table string) (bool, string) {
 	return false, ""
 }
 
-func IsPartOfFK(col, table string) bool {
-	sessionState := session.GetSessionState()
-
+func IsPartOfFK(sessionState *session.SessionState, col, table string) bool {
 	for _, fk := range sessionState.Conv.SpSchema[table].ForeignKeys {
 		for _, column := range fk.ColIds {
 			if column == col {
@@ -221,9 +212,7 @@ func IsPartOfFK(col, table string) bool {
 	return false
 }
 
-func IsReferencedByFK(col, table string) (bool, string) {
-	sessionState := session.GetSessionState()
-
+func IsReferencedByFK(sessionState *session.SessionState, col, table string) (bool, string) {
 	for _, spSchema := range sessionState.Conv.SpSchema {
 		if table != spSchema.Name {
 			for _, fk := range spSchema.ForeignKeys {
@@ -248,8 +237,7 @@ func RemovePk(slice []ddl.IndexKey, s int) []ddl.IndexKey {
 	return append(slice[:s], slice[s+1:]...)
 }
 
-func RemoveFk(slice []ddl.Foreignkey, fkId string, srcSchema schema.Table, tableId string) ([]ddl.Foreignkey, error) {
-	sessionState := session.GetSessionState()
+func RemoveFk(sessionState *session.SessionState, slice []ddl.Foreignkey, fkId string, srcSchema schema.Table, tableId string) ([]ddl.Foreignkey, error) {
 	tableIssues := sessionState.Conv.SchemaIssues[tableId].TableLevelIssues
 
 	pos := -1
@@ -308,8 +296,7 @@ func CheckSpannerNamesValidity(input []string) (bool, []string) {
 	return status, invalidNewNames
 }
 
-func CanRename(names []string, table string) (bool, error) {
-	sessionState := session.GetSessionState()
+func CanRename(sessionState *session.SessionState, names []string, table string) (bool, error) {
 	for _, name := range names {
 		if _, ok := sessionState.Conv.UsedNames[strings.ToLower(name)]; ok {
 			return false, fmt.Errorf("new name : '%s' is used by another entity", name)
@@ -337,9 +324,7 @@ func GetRefColIndexFromFk(fk ddl.Foreignkey, colId string) int {
 	return -1
 }
 
-func GetFilePrefix(now time.Time) (string, error) {
-	sessionState := session.GetSessionState()
-
+func GetFilePrefix(sessionState *session.SessionState, now time.Time) (string, error) {
 	dbName := sessionState.DbName
 	var err error
 	if dbName == "" {
@@ -352,8 +337,8 @@ func GetFilePrefix(now time.Time) (string, error) {
 	return dbName, nil
 }
 
-func UpdateDataType(conv *internal.Conv, newType, tableId, colId string) error {
-	sp, ty, err := GetType(conv, newType, tableId, colId)
+func UpdateDataType(sessionState *session.SessionState, conv *internal.Conv, newType, tableId, colId string) error {
+	sp, ty, err := GetType(sessionState, conv, newType, tableId, colId)
 	if err != nil {
 		return err
 	}
@@ -371,12 +356,14 @@ func UpdateDataType(conv *internal.Conv, newType, tableId, colId string) error {
 		}
 	}
 	sp.ColDefs[colId] = colDef
+	// vector indexes on the column may not hold for the new type.
+	conv.RemoveInvalidVectorIndexes(tableId)
 	return nil
 }
 
 // Update the column length with the default mapping length in case its same as the length in the rule added
-func updateColLen(conv *internal.Conv, dataType, tableId, colId string, spColLen int64) error {
-	sp, ty, err := GetType(conv, dataType, tableId, colId)
+func updateColLen(sessionState *session.SessionState, conv *internal.Conv, dataType, tableId, colId string, spColLen int64) error {
+	sp, ty, err := GetType(sessionState, conv, dataType, tableId, colId)
 	if err != nil {
 		return err
 	}
@@ -388,9 +375,8 @@ func updateColLen(conv *internal.Conv, dataType, tableId, colId string, spColLen
 	return nil
 }
 
-func UpdateMaxColumnLen(conv *internal.Conv, dataType, tableId, colId string, spColLen int64) error {
-
-	err := updateColLen(conv, dataType, tableId, colId, spColLen)
+func UpdateMaxColumnLen(sessionState *session.SessionState, conv *internal.Conv, dataType, tableId, colId string, spColLen int64) error {
+	err := updateColLen(sessionState, conv, dataType, tableId, colId, spColLen)
 	if err != nil {
 		return err
 	}
@@ -406,8 +392,7 @@ func GetColIdFromSpannerName(conv *internal.Conv, tableId, colName string) (stri
 	return "", fmt.Errorf("column id not found for spaner column %v", colName)
 }
 
-func IsParent(tableId string) (bool, []string) {
-	sessionState := session.GetSessionState()
+func IsParent(sessionState *session.SessionState, tableId string) (bool, []string) {
 	childTableIds := []string{}
 	for _, spSchema := range sessionState.Conv.SpSchema {
 		if spSchema.ParentTable.Id == tableId {
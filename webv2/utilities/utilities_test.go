@@ -93,7 +93,7 @@ func TestUpdateDataType(t *testing.T) {
 				Source:    tc.source,
 			}
 
-			err := UpdateDataType(conv, tc.newType, tableId, colId)
+			err := UpdateDataType(session.GetSessionState(), conv, tc.newType, tableId, colId)
 
 			if tc.wantErr {
 				assert.Error(t, err)
@@ -154,7 +154,7 @@ func TestIsParent(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			session.GetSessionState().Conv = &internal.Conv{SpSchema: tc.spSchema}
-			isParent, childIds := IsParent(tc.tableId)
+			isParent, childIds := IsParent(session.GetSessionState(), tc.tableId)
 			assert.Equal(t, tc.expectedIsParent, isParent)
 			assert.ElementsMatch(t, tc.expectedChildIds, childIds)
 		})
@@ -47,6 +47,7 @@ import (
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/profiles"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/proto/migration"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/streaming"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/auth"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/config"
 	helpers "github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/helpers"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/types"
@@ -107,7 +108,7 @@ func databaseConnection(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		sessionState := session.GetSessionState()
+		sessionState := session.RequestSessionState(r)
 		sessionState.SourceDB = nil
 		sessionState.DbName = config.Database
 		sessionState.Driver = config.Driver
@@ -124,6 +125,25 @@ func databaseConnection(w http.ResponseWriter, r *http.Request) {
 		}
 		w.WriteHeader(http.StatusOK)
 		return
+	case constants.MONGODB:
+		// MongoDB collections are read from mongoexport files, the database
+		// is the directory that holds them.
+		if err := validateMongoDBExportDir(config); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		sessionState := session.RequestSessionState(r)
+		sessionState.SourceDB = nil
+		sessionState.DbName = config.Database
+		sessionState.Driver = config.Driver
+		sessionState.SessionFile = ""
+		sessionState.Dialect = config.Dialect
+		sessionState.SourceDBConnDetails = session.SourceDBConnDetails{
+			ConnectionType: helpers.DIRECT_CONNECT_MODE,
+		}
+		w.WriteHeader(http.StatusOK)
+		return
 	default:
 		http.Error(w, fmt.Sprintf("Driver : '%s' is not supported", config.Driver), http.StatusBadRequest)
 		return
@@ -140,7 +160,7 @@ func databaseConnection(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 	sessionState.SourceDB = sourceDB
 	sessionState.DbName = config.Database
 	// schema and user is same in oracle.
@@ -162,7 +182,7 @@ func databaseConnection(w http.ResponseWriter, r *http.Request) {
 }
 
 func setSourceDBDetailsForDump(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
@@ -189,7 +209,7 @@ func setSourceDBDetailsForDump(w http.ResponseWriter, r *http.Request) {
 
 // getSourceProfileConfig returns the configured source profile by the user
 func getSourceProfileConfig(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 	sourceProfileConfig := sessionState.SourceProfileConfig
 	if sourceProfileConfig.ConfigType == "dataflow" {
 		for _, dataShard := range sourceProfileConfig.ShardConfigurationDataflow.DataShards {
@@ -206,7 +226,7 @@ func getSourceProfileConfig(w http.ResponseWriter, r *http.Request) {
 }
 
 func setDatastreamDetailsForShardedMigrations(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
@@ -223,7 +243,7 @@ func setDatastreamDetailsForShardedMigrations(w http.ResponseWriter, r *http.Req
 }
 
 func setGcsDetailsForShardedMigrations(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
@@ -240,7 +260,7 @@ func setGcsDetailsForShardedMigrations(w http.ResponseWriter, r *http.Request) {
 }
 
 func setDataflowDetailsForShardedMigrations(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
@@ -261,7 +281,7 @@ func setDataflowDetailsForShardedMigrations(w http.ResponseWriter, r *http.Reque
 
 func setShardsSourceDBDetailsForDataflow(w http.ResponseWriter, r *http.Request) {
 	//Take the received object and store it into session state.
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
@@ -293,7 +313,7 @@ func setShardsSourceDBDetailsForDataflow(w http.ResponseWriter, r *http.Request)
 }
 
 func setShardsSourceDBDetailsForBulk(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
@@ -348,7 +368,7 @@ func setShardsSourceDBDetailsForBulk(w http.ResponseWriter, r *http.Request) {
 }
 
 func setSourceDBDetailsForDirectConnect(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Body Read Error : %v", err), http.StatusInternalServerError)
@@ -392,6 +412,19 @@ func setSourceDBDetailsForDirectConnect(w http.ResponseWriter, r *http.Request)
 		}
 		w.WriteHeader(http.StatusOK)
 		return
+	case constants.MONGODB:
+		if err := validateMongoDBExportDir(config); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		sessionState.DbName = config.Database
+		sessionState.SessionFile = ""
+		sessionState.SourceDBConnDetails = session.SourceDBConnDetails{
+			ConnectionType: helpers.DIRECT_CONNECT_MODE,
+		}
+		w.WriteHeader(http.StatusOK)
+		return
 	default:
 		http.Error(w, fmt.Sprintf("Driver : '%s' is not supported", config.Driver), http.StatusBadRequest)
 		return
@@ -448,11 +481,20 @@ func validateCassandraConnection(config types.DriverConfig) (cc.KeyspaceMetadata
 	return keyspaceMetadata, nil
 }
 
+// validateMongoDBExportDir checks that the database of a MongoDB connection
+// is a readable directory, where the mongoexport files of the collections are.
+func validateMongoDBExportDir(config types.DriverConfig) error {
+	if _, err := os.ReadDir(config.Database); err != nil {
+		return fmt.Errorf("MongoDB export directory error, check that %q is a directory with the mongoexport files of the collections: %v", config.Database, err)
+	}
+	return nil
+}
+
 // loadSession load seesion file to Spanner migration tool.
 func loadSession(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 
-	utilities.InitObjectId()
+	utilities.InitObjectId(sessionState)
 
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -513,15 +555,12 @@ func loadSession(w http.ResponseWriter, r *http.Request) {
 		sessionMetadata.DatabaseName = strings.TrimRight(filepath.Base(s.FilePath), filepath.Ext(s.FilePath))
 	}
 
-	sessionState.Conv.ConvLock.Lock()
-	defer sessionState.Conv.ConvLock.Unlock()
+	defer sessionState.ReplaceConv(conv)()
 
-	sessionState.Conv = conv
+	primarykey.DetectHotspot(sessionState)
+	index.IndexSuggestion(conv)
 
-	primarykey.DetectHotspot()
-	index.IndexSuggestion()
-
-	sessionState.Conv.UsedNames = internal.ComputeUsedNames(sessionState.Conv)
+	conv.UsedNames = internal.ComputeUsedNames(conv)
 
 	sessionState.SessionMetadata = sessionMetadata
 	sessionState.Driver = s.Driver
@@ -541,7 +580,7 @@ func loadSession(w http.ResponseWriter, r *http.Request) {
 }
 
 func fetchLastLoadedSessionDetails(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
 	convm := session.ConvWithMetadata{
 		SessionMetadata: sessionState.SessionMetadata,
 		Conv:            sessionState.Conv,
@@ -552,18 +591,17 @@ func fetchLastLoadedSessionDetails(w http.ResponseWriter, r *http.Request) {
 
 // getSchemaFile generates schema file and returns file path.
 func getSchemaFile(w http.ResponseWriter, r *http.Request) {
+	sessionState := session.RequestSessionState(r)
 	ioHelper := &utils.IOStreams{In: os.Stdin, Out: os.Stdout}
 	var err error
 	now := time.Now()
-	filePrefix, err := utilities.GetFilePrefix(now)
+	filePrefix, err := utilities.GetFilePrefix(sessionState, now)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Can not get file prefix : %v", err), http.StatusInternalServerError)
 	}
 	schemaFileName := "frontend/" + filePrefix + "schema.txt"
 
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.RLock()
-	defer sessionState.Conv.ConvLock.RUnlock()
+	defer sessionState.RLockConv()()
 	conversion.WriteSchemaFile(sessionState.Conv, now, schemaFileName, ioHelper.Out, sessionState.Driver)
 	schemaAbsPath, err := filepath.Abs(schemaFileName)
 	if err != nil {
@@ -597,9 +635,8 @@ func getBackendHealth(w http.ResponseWriter, r *http.Request) {
 // secondary indexes or foreign key constraints. If above checks passed then new indexes are added to the schema else appropriate
 // error thrown.
 func getSourceDestinationSummary(w http.ResponseWriter, r *http.Request) {
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.RLock()
-	defer sessionState.Conv.ConvLock.RUnlock()
+	sessionState := session.RequestSessionState(r)
+	defer sessionState.RLockConv()()
 	// GetSourceDestinationSummary is called when the user enters prepare migration page
 	// Getting and populating SpannerProjectId if it doesn't exist.
 	if sessionState.SpannerProjectId == "" {
@@ -663,9 +700,8 @@ func getSourceDestinationSummary(w http.ResponseWriter, r *http.Request) {
 func updateProgress(w http.ResponseWriter, r *http.Request) {
 
 	var detail types.ProgressDetails
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.RLock()
-	defer sessionState.Conv.ConvLock.RUnlock()
+	sessionState := session.RequestSessionState(r)
+	defer sessionState.RLockConv()()
 	if sessionState.Error != nil {
 		detail.ErrorMessage = sessionState.Error.Error()
 	} else {
@@ -692,11 +728,27 @@ func migrate(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("Request Body parse error : %v", err), http.StatusBadRequest)
 		return
 	}
-	sessionState := session.GetSessionState()
+	sessionState := session.RequestSessionState(r)
+	var hasColumnMasks bool
+	err = sessionState.ReadConv(func(conv *internal.Conv) error {
+		hasColumnMasks = conv.HasColumnMasks()
+		return nil
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if details.MigrationType == helpers.LOW_DOWNTIME_MIGRATION && details.MigrationMode != helpers.SCHEMA_ONLY && hasColumnMasks {
+		http.Error(w, "Column masks only apply to bulk migrations, remove them to run a minimal downtime migration", http.StatusBadRequest)
+		return
+	}
 	sessionState.Error = nil
 	ctx := context.Background()
-	sessionState.Conv.Audit.Progress = internal.Progress{}
-	sessionState.Conv.UI = true
+	sessionState.UpdateConv(func(conv *internal.Conv) error {
+		conv.Audit.Progress = internal.Progress{}
+		conv.UI = true
+		return nil
+	})
 	sourceProfile, targetProfile, ioHelper, dbName, err := getSourceAndTargetProfiles(ctx, sessionState, details)
 	// TODO: Fix UX flow of migration project id
 	migrationProjectId := sessionState.GCPProjectID
@@ -714,30 +766,40 @@ func migrate(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("Can't write session file to GCS: %v", err), http.StatusBadRequest)
 		return
 	}
-	sessionState.Conv.ResetStats()
-	sessionState.Conv.Audit.Progress = internal.Progress{}
-	// Set env variable SKIP_METRICS_POPULATION to true in case of dev testing
-	sessionState.Conv.Audit.SkipMetricsPopulation = os.Getenv("SKIP_METRICS_POPULATION") == "true"
+	var conv *internal.Conv
+	sessionState.UpdateConv(func(c *internal.Conv) error {
+		c.ResetStats()
+		c.Audit.Progress = internal.Progress{}
+		// Set env variable SKIP_METRICS_POPULATION to true in case of dev testing
+		c.Audit.SkipMetricsPopulation = os.Getenv("SKIP_METRICS_POPULATION") == "true"
+		switch details.MigrationMode {
+		case helpers.SCHEMA_ONLY:
+			c.Audit.MigrationType = migration.MigrationData_SCHEMA_ONLY.Enum()
+		case helpers.DATA_ONLY:
+			c.Audit.MigrationType = migration.MigrationData_DATA_ONLY.Enum()
+		default:
+			c.Audit.MigrationType = migration.MigrationData_SCHEMA_AND_DATA.Enum()
+		}
+		conv = c
+		return nil
+	})
 	if details.MigrationMode == helpers.SCHEMA_ONLY {
 		log.Println("Starting schema only migration")
-		sessionState.Conv.Audit.MigrationType = migration.MigrationData_SCHEMA_ONLY.Enum()
-		go cmd.MigrateDatabase(ctx, migrationProjectId, targetProfile, sourceProfile, dbName, &ioHelper, &cmd.SchemaCmd{}, sessionState.Conv, &sessionState.Error)
+		go cmd.MigrateDatabase(ctx, migrationProjectId, targetProfile, sourceProfile, dbName, &ioHelper, &cmd.SchemaCmd{}, conv, &sessionState.Error)
 	} else if details.MigrationMode == helpers.DATA_ONLY {
 		dataCmd := &cmd.DataCmd{
 			SkipForeignKeys: details.SkipForeignKeys,
 			WriteLimit:      cmd.DefaultWritersLimit,
 		}
 		log.Println("Starting data only migration")
-		sessionState.Conv.Audit.MigrationType = migration.MigrationData_DATA_ONLY.Enum()
-		go cmd.MigrateDatabase(ctx, migrationProjectId, targetProfile, sourceProfile, dbName, &ioHelper, dataCmd, sessionState.Conv, &sessionState.Error)
+		go cmd.MigrateDatabase(ctx, migrationProjectId, targetProfile, sourceProfile, dbName, &ioHelper, dataCmd, conv, &sessionState.Error)
 	} else {
 		schemaAndDataCmd := &cmd.SchemaAndDataCmd{
 			SkipForeignKeys: details.SkipForeignKeys,
 			WriteLimit:      cmd.DefaultWritersLimit,
 		}
 		log.Println("Starting schema and data migration")
-		sessionState.Conv.Audit.MigrationType = migration.MigrationData_SCHEMA_AND_DATA.Enum()
-		go cmd.MigrateDatabase(ctx, migrationProjectId, targetProfile, sourceProfile, dbName, &ioHelper, schemaAndDataCmd, sessionState.Conv, &sessionState.Error)
+		go cmd.MigrateDatabase(ctx, migrationProjectId, targetProfile, sourceProfile, dbName, &ioHelper, schemaAndDataCmd, conv, &sessionState.Error)
 	}
 	w.WriteHeader(http.StatusOK)
 	log.Println("migration completed", "method", r.Method, "path", r.URL.Path, "remoteaddr", r.RemoteAddr)
@@ -745,9 +807,8 @@ func migrate(w http.ResponseWriter, r *http.Request) {
 
 func getGeneratedResources(w http.ResponseWriter, r *http.Request) {
 	var generatedResources types.GeneratedResources
-	sessionState := session.GetSessionState()
-	sessionState.Conv.ConvLock.RLock()
-	defer sessionState.Conv.ConvLock.RUnlock()
+	sessionState := session.RequestSessionState(r)
+	defer sessionState.RLockConv()()
 	generatedResources.MigrationJobId = sessionState.Conv.Audit.MigrationRequestId
 	generatedResources.DatabaseName = sessionState.SpannerDatabaseName
 	generatedResources.DatabaseUrl = fmt.Sprintf("https://console.cloud.google.com/spanner/instances/%v/databases/%v/details/tables?project=%v", sessionState.SpannerInstanceID, sessionState.SpannerDatabaseName, sessionState.SpannerProjectId)
@@ -837,6 +898,8 @@ func getSourceAndTargetProfiles(ctx context.Context, sessionState *session.Sessi
 			sourceDBConnectionDetails.Password,
 			sessionState.DbName,
 			sourceDBConnectionDetails.DataCenter)
+	} else if sessionState.Driver == constants.MONGODB {
+		sourceProfileString = fmt.Sprintf("\"dir=%v\"", sessionState.DbName)
 	} else {
 		sourceProfileString = fmt.Sprintf("\"host=%v\",\"port=%v\",\"user=%v\",\"password=%v\",\"dbName=%v\"",
 			sourceDBConnectionDetails.Host, sourceDBConnectionDetails.Port, sourceDBConnectionDetails.User,
@@ -1098,13 +1161,11 @@ func uploadFile(w http.ResponseWriter, r *http.Request) {
 
 // rollback is used to get previous state of conversion in case
 // some unexpected error occurs during update operations.
-func rollback(err error) error {
-	sessionState := session.GetSessionState()
-
+func rollback(sessionState *session.SessionState, err error) error {
 	if sessionState.SessionFile == "" {
 		return fmt.Errorf("encountered error %w. rollback failed because we don't have a session file", err)
 	}
-	sessionState.Conv = internal.MakeConv()
+	sessionState.SetConv(internal.MakeConv())
 	sessionState.Conv.SpDialect = constants.DIALECT_GOOGLESQL
 	err2 := conversion.ReadSessionFile(sessionState.Conv, sessionState.SessionFile)
 	if err2 != nil {
@@ -1115,20 +1176,29 @@ func rollback(err error) error {
 
 func init() {
 	sessionState := session.GetSessionState()
-	utilities.InitObjectId()
-	sessionState.Conv = internal.MakeConv()
+	utilities.InitObjectId(sessionState)
+	sessionState.SetConv(internal.MakeConv())
 	config := config.TryInitializeSpannerConfig()
 	session.SetSessionStorageConnectionState(config.GCPProjectID, config.SpannerProjectID, config.SpannerInstanceID)
 }
 
-// App connects to the web app v2.
-func App(logLevel string, open bool, port int) error {
+// App connects to the web app v2. If multiSession is set, each browser
+// session works on its own session state instead of the global one. Requests
+// are authenticated and authorized as per authConfig.
+func App(logLevel string, open bool, port int, multiSession bool, authConfig auth.Config) error {
 	err := logger.InitializeLogger(logLevel)
 	if err != nil {
 		return fmt.Errorf("error initialising webapp, did you specify a valid log-level? [DEBUG, INFO]")
 	}
+	if err := authConfig.Validate(); err != nil {
+		return fmt.Errorf("error initialising webapp: %v", err)
+	}
 	addr := fmt.Sprintf(":%s", strconv.Itoa(port))
-	router := getRoutes()
+	var router http.Handler = getRoutes()
+	if multiSession {
+		router = session.WithSessionState(router)
+	}
+	router = withProbes(auth.Middleware(authConfig, router))
 	logger.Log.Info(fmt.Sprint("Starting Spanner migration tool UI at:", fmt.Sprintf("http://localhost%s", addr)))
 	logger.Log.Info(fmt.Sprint("Reverse Replication feature in preview: Please refer to https://github.com/GoogleCloudPlatform/spanner-migration-tool/blob/master/reverse_replication/README.md for detailed instructions."))
 	if open {
@@ -22,11 +22,13 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/utils"
 	"github.com/google/subcommands"
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/logger"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/auth"
 )
 
 var FrontendDir embed.FS
@@ -38,6 +40,11 @@ type WebCmd struct {
 	port             int
 	validate         bool
 	dataflowTemplate string
+	multiSession     bool
+	authMode         string
+	authAudience     string
+	editors          string
+	viewers          string
 }
 
 // Name returns the name of operation.
@@ -60,6 +67,11 @@ func (cmd *WebCmd) SetFlags(f *flag.FlagSet) {
 	f.IntVar(&cmd.port, "port", 8080, "The port in which Spanner migration tool will run, defaults to 8080")
 	f.BoolVar(&cmd.validate, "validate", false, "Flag for validating if all the required input parameters are present")
 	f.StringVar(&cmd.dataflowTemplate, "dataflow-template", constants.DEFAULT_TEMPLATE_PATH, "GCS path of the Dataflow template")
+	f.BoolVar(&cmd.multiSession, "multi-session", false, "Isolate session state per browser session so that multiple users can share one server, defaults to false")
+	f.StringVar(&cmd.authMode, "auth", auth.ModeNone, "Authentication for the web APIs (none, iap, oauth), defaults to none")
+	f.StringVar(&cmd.authAudience, "auth-audience", "", "Expected audience of the IAP or OAuth token, required when auth is enabled")
+	f.StringVar(&cmd.editors, "editors", "", "Comma separated emails of users allowed to modify the session, '*' allows all authenticated users")
+	f.StringVar(&cmd.viewers, "viewers", "", "Comma separated emails of users with read-only access, '*' allows all authenticated users")
 }
 
 func (cmd *WebCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
@@ -75,6 +87,23 @@ func (cmd *WebCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...interface{
 			logger.Log.Info(fmt.Sprintf("FATAL error, unable to start webapp: %s", err))
 		}
 	}()
-	err = App(cmd.logLevel, cmd.open, cmd.port)
+	authConfig := auth.Config{
+		Mode:     cmd.authMode,
+		Audience: cmd.authAudience,
+		Editors:  splitList(cmd.editors),
+		Viewers:  splitList(cmd.viewers),
+	}
+	err = App(cmd.logLevel, cmd.open, cmd.port, cmd.multiSession, authConfig)
 	return subcommands.ExitSuccess
 }
+
+// splitList splits a comma separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/spanner-migration-tool/common/constants"
+	"github.com/GoogleCloudPlatform/spanner-migration-tool/webv2/auth"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -30,6 +31,7 @@ func TestWebCmdSetFlags(t *testing.T) {
 		port:             8080,
 		validate:         false,
 		dataflowTemplate: constants.DEFAULT_TEMPLATE_PATH,
+		authMode:         auth.ModeNone,
 	}
 
 	webCmd := WebCmd{}